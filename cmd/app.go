@@ -11,9 +11,12 @@ import (
 
 	"github.com/ydcloud-dy/leaf-api/config"
 	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/encrypt"
 	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	mdutils "github.com/ydcloud-dy/leaf-api/pkg/markdown"
 	"github.com/ydcloud-dy/leaf-api/pkg/oss"
 	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+	"github.com/ydcloud-dy/leaf-api/pkg/search"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -28,6 +31,14 @@ func Run(configPath string) error {
 	logger.Init()
 	logger.Info("Starting Blog Admin API...")
 
+	// 初始化字段加密
+	if err := encrypt.Init(); err != nil {
+		return fmt.Errorf("failed to initialize encryption: %w", err)
+	}
+	if !encrypt.Enabled() {
+		logger.Warn("Field encryption is disabled, set encryption.key to encrypt PII at rest")
+	}
+
 	// 初始化数据库
 	if err := config.InitDatabase(); err != nil {
 		return fmt.Errorf("failed to initialize database: %w", err)
@@ -51,18 +62,41 @@ func Run(configPath string) error {
 		logger.Warn("Failed to initialize OSS: ", err)
 	}
 
+	// 图片下载并发数和重试次数
+	mdutils.SetImageFetchOptions(config.AppConfig.Markdown.ImageConcurrency, config.AppConfig.Markdown.ImageRetries)
+
+	// 初始化全文搜索
+	if err := search.Init(); err != nil {
+		logger.Warn("Failed to initialize search engine, falling back to database search: ", err)
+	} else if search.Enabled() {
+		logger.Info("Search engine initialized: ", search.Default().Name())
+	}
+
 	// 创建默认管理员
 	initDefaultAdmin()
 
 	// 创建默认分类
 	initDefaultCategories()
 
+	// 创建默认路由权限规则
+	initDefaultRoutePermissions()
+
+	// 创建默认角色
+	initDefaultRoles()
+
 	// 初始化应用（依赖注入）
 	app, err := InitApp(config.DB)
 	if err != nil {
 		return fmt.Errorf("failed to initialize app: %w", err)
 	}
 
+	// 启动后台任务
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	if err := startJobs(jobCtx); err != nil {
+		return fmt.Errorf("failed to start background jobs: %w", err)
+	}
+
 	// 创建 HTTP 服务器
 	addr := fmt.Sprintf(":%d", config.AppConfig.Server.Port)
 	srv := &http.Server{
@@ -87,6 +121,7 @@ func Run(configPath string) error {
 	<-quit
 
 	logger.Info("Shutting down server...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -100,6 +135,11 @@ func Run(configPath string) error {
 		sqlDB.Close()
 	}
 
+	// 关闭搜索索引
+	if err := search.Close(); err != nil {
+		logger.Error("Failed to close search index: ", err)
+	}
+
 	// 关闭 Redis 连接
 	if err := redis.Close(); err != nil {
 		logger.Error("Failed to close Redis: ", err)
@@ -174,3 +214,95 @@ func initDefaultCategories() {
 
 	logger.Info("Default categories created")
 }
+
+// initDefaultRoutePermissions 创建默认的根路由规则
+// 未匹配任何规则的管理接口会被拒绝访问，根路由规则保证管理员可以访问所有管理接口
+func initDefaultRoutePermissions() {
+	var count int64
+	config.DB.Model(&po.RoutePermission{}).Where("method = ? AND path = ?", "*", "/").Count(&count)
+	if count > 0 {
+		return
+	}
+
+	rule := po.RoutePermission{
+		Method:      "*",
+		Path:        "/",
+		Roles:       "admin",
+		Description: "所有管理接口默认仅允许管理员访问",
+	}
+	if err := config.DB.Create(&rule).Error; err != nil {
+		logger.Error("Failed to create default route permission: ", err)
+		return
+	}
+
+	logger.Info("Default route permission created: * / -> admin")
+}
+
+// defaultRoles 默认角色及权限，reader 即前台用户 user
+var defaultRoles = []struct {
+	role        po.Role
+	permissions []string
+}{
+	{po.Role{Name: "super_admin", DisplayName: "超级管理员", Description: "拥有全部权限，可以管理角色和权限", Builtin: true}, []string{"*"}},
+	{po.Role{Name: "admin", DisplayName: "管理员", Description: "拥有全部管理权限", Builtin: true}, []string{"*"}},
+	{po.Role{Name: "editor", DisplayName: "编辑", Description: "撰写和发布文章，管理评论、分类和标签"}, []string{
+		"article:*", "comment:moderate", "taxonomy:manage", "file:upload", "file:manage", "stats:read",
+	}},
+	{po.Role{Name: "author", DisplayName: "作者", Description: "撰写和编辑自己的文章，发布需由编辑审核"}, []string{
+		"article:read", "article:create", "article:update", "file:upload",
+	}},
+	{po.Role{Name: "user", DisplayName: "读者", Description: "前台用户，不能登录管理后台", Builtin: true}, nil},
+}
+
+// defaultRoleRoutes 编辑和作者可以访问的管理接口，具体操作再由权限点控制
+var defaultRoleRoutes = []po.RoutePermission{
+	{Method: "*", Path: "/articles", Roles: "admin,editor,author", Description: "文章管理"},
+	{Method: "*", Path: "/files", Roles: "admin,editor,author", Description: "文件上传"},
+	{Method: "*", Path: "/attachments", Roles: "admin,editor,author", Description: "附件管理"},
+	{Method: "GET", Path: "/media", Roles: "admin,editor,author", Description: "媒体库"},
+	{Method: "GET", Path: "/categories", Roles: "admin,editor,author", Description: "选择文章分类"},
+	{Method: "GET", Path: "/tags", Roles: "admin,editor,author", Description: "选择文章标签"},
+	{Method: "*", Path: "/comments", Roles: "admin,editor", Description: "评论管理"},
+	{Method: "*", Path: "/categories", Roles: "admin,editor", Description: "分类管理"},
+	{Method: "*", Path: "/tags", Roles: "admin,editor", Description: "标签管理"},
+	{Method: "*", Path: "/chapters", Roles: "admin,editor", Description: "章节管理"},
+	{Method: "*", Path: "/series", Roles: "admin,editor", Description: "系列管理"},
+	{Method: "*", Path: "/stats", Roles: "admin,editor", Description: "统计"},
+}
+
+// initDefaultRoles 首次启动时创建默认角色，以及编辑和作者可以访问的路由规则
+// 角色表不为空时不再创建，避免覆盖管理员的修改
+func initDefaultRoles() {
+	var count int64
+	config.DB.Model(&po.Role{}).Count(&count)
+	if count > 0 {
+		return
+	}
+
+	for _, item := range defaultRoles {
+		role := item.role
+		if err := config.DB.Create(&role).Error; err != nil {
+			logger.Error("Failed to create default role: ", err)
+			continue
+		}
+		for _, permission := range item.permissions {
+			grant := po.RolePermission{Role: role.Name, Permission: permission}
+			if err := config.DB.Create(&grant).Error; err != nil {
+				logger.Error("Failed to create default role permission: ", err)
+			}
+		}
+	}
+
+	for _, rule := range defaultRoleRoutes {
+		var exists int64
+		config.DB.Model(&po.RoutePermission{}).Where("method = ? AND path = ?", rule.Method, rule.Path).Count(&exists)
+		if exists > 0 {
+			continue
+		}
+		if err := config.DB.Create(&rule).Error; err != nil {
+			logger.Error("Failed to create default route permission: ", err)
+		}
+	}
+
+	logger.Info("Default roles created")
+}
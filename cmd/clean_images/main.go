@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/pkg/oss"
+)
+
+// 未引用图片清理工具
+// 对比文章、评论、头像、系列封面和站点设置中引用的图片与 OSS（未配置时为本地 uploads 目录）中的图片，
+// 列出或删除不再被引用的图片。建议先加 -dry-run 查看待删除列表，确认后再去掉该参数执行。
+//
+//	go run ./cmd/clean_images -config config.yaml -dry-run
+//	go run ./cmd/clean_images -config config.yaml -min-age 72
+
+func main() {
+	configPath := flag.String("config", "config.yaml", "config file path")
+	dryRun := flag.Bool("dry-run", false, "only list unreferenced images")
+	minAge := flag.Int("min-age", 24, "only consider images uploaded more than this many hours ago")
+	flag.Parse()
+
+	// 加载配置
+	if err := config.LoadConfig(*configPath); err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+
+	// 初始化数据库
+	if err := config.InitDatabase(); err != nil {
+		log.Fatalf("初始化数据库失败: %v", err)
+	}
+
+	// 未配置 OSS 时扫描本地 uploads 目录
+	if err := oss.Init(); err != nil {
+		fmt.Println("未使用 OSS:", err)
+	}
+
+	d, err := data.NewData(config.DB)
+	if err != nil {
+		log.Fatalf("初始化数据层失败: %v", err)
+	}
+
+	report, err := biz.NewImageCleanupUseCase(d).CleanOrphans(*dryRun, time.Duration(*minAge)*time.Hour)
+	if err != nil {
+		log.Fatalf("清理失败: %v", err)
+	}
+
+	for _, orphan := range report.Orphans {
+		fmt.Printf("%s  %8d  %s\n", orphan.LastModified.Format("2006-01-02 15:04:05"), orphan.Size, orphan.Key)
+	}
+	fmt.Printf("\n存储: %s，图片 %d 张，仍被引用 %d 张，上传不足 %d 小时跳过 %d 张\n",
+		report.Location, report.Scanned, report.Referenced, *minAge, report.Recent)
+	fmt.Printf("未引用图片 %d 张，共 %.2f MB\n", len(report.Orphans), float64(report.OrphanSize)/1024/1024)
+
+	if *dryRun {
+		fmt.Println("\ndry-run 模式，未删除任何文件")
+		return
+	}
+	for _, failed := range report.Failed {
+		fmt.Println("删除失败:", failed)
+	}
+	fmt.Printf("\n已删除 %d 张图片\n", report.Deleted)
+}
@@ -21,6 +21,7 @@ func main() {
 	}
 
 	// 创建图片处理器
+	mdutils.SetImageFetchOptions(config.AppConfig.Markdown.ImageConcurrency, config.AppConfig.Markdown.ImageRetries)
 	processor := mdutils.NewImageProcessor("uploads", "")
 
 	// 查询所有文章
@@ -47,11 +48,14 @@ func main() {
 		}
 
 		// 处理 Markdown 中的图片
+		// 部分图片失败时仍保存已处理的图片，失败的图片保留原地址，下次运行时重试
 		processedMarkdown, err := processor.ProcessMarkdownImages(article.ContentMarkdown)
 		if err != nil {
-			fmt.Printf("  ✗ 处理失败: %v\n", err)
-			failCount++
-			continue
+			fmt.Printf("  ⚠ %v\n", err)
+			if processedMarkdown == article.ContentMarkdown {
+				failCount++
+				continue
+			}
 		}
 
 		// 检查是否有变化
@@ -0,0 +1,429 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/mail"
+	"github.com/ydcloud-dy/leaf-api/pkg/realtime"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+// startJobs 启动后台定时任务，ctx 取消后任务退出
+func startJobs(ctx context.Context) error {
+	d, err := data.NewData(config.DB)
+	if err != nil {
+		return err
+	}
+
+	articleUseCase := biz.NewArticleUseCase(d)
+	go backfillArticleSlugs(articleUseCase)
+	go backfillReadingStats(articleUseCase)
+	go backfillCommentThreads(biz.NewCommentUseCase(d))
+	go runTrashCleanup(ctx, articleUseCase)
+	go runCounterFlush(ctx, articleUseCase)
+	go runExportWorker(ctx, articleUseCase)
+	go runSitemapRefresh(ctx, biz.NewSitemapUseCase(d))
+	go runWebhookRetry(ctx, biz.NewWebhookUseCase(d))
+	go runYuqueSync(ctx, biz.NewYuqueUseCase(d))
+	go runBackup(ctx, biz.NewBackupUseCase(d))
+	go runUploadCleanup(ctx, biz.NewUploadUseCase(d))
+	go runStorageStats(ctx, biz.NewStorageStatsUseCase())
+	go runLoginLogCleanup(ctx, biz.NewLoginLogUseCase(d))
+	go runPushCleanup(ctx, biz.NewPushUseCase(d))
+	go runFriendLinkCheck(ctx, biz.NewFriendLinkUseCase(d))
+	go runAnnouncementNotify(ctx, biz.NewAnnouncementUseCase(d))
+	go mail.RunQueue(ctx)
+	go realtime.Run(ctx)
+	go data.WatchSettings(ctx)
+	return nil
+}
+
+// backfillArticleSlugs 启动时为历史文章补全 slug
+func backfillArticleSlugs(articleUseCase biz.ArticleUseCase) {
+	filled, err := articleUseCase.BackfillSlugs()
+	if err != nil {
+		logger.Error("Failed to backfill article slugs: ", err)
+		return
+	}
+	if filled > 0 {
+		logger.Info(fmt.Sprintf("Generated slugs for %d articles", filled))
+	}
+}
+
+// backfillReadingStats 启动时为历史文章补全字数和阅读时间
+func backfillReadingStats(articleUseCase biz.ArticleUseCase) {
+	filled, err := articleUseCase.BackfillReadingStats()
+	if err != nil {
+		logger.Error("Failed to backfill article reading stats: ", err)
+		return
+	}
+	if filled > 0 {
+		logger.Info(fmt.Sprintf("Computed reading stats for %d articles", filled))
+	}
+}
+
+// backfillCommentThreads 启动时为历史回复补全所属顶级评论和回复数
+func backfillCommentThreads(commentUseCase biz.CommentUseCase) {
+	filled, err := commentUseCase.BackfillThreads()
+	if err != nil {
+		logger.Error("Failed to backfill comment threads: ", err)
+		return
+	}
+	if filled > 0 {
+		logger.Info(fmt.Sprintf("Resolved root comments for %d replies", filled))
+	}
+}
+
+// runTrashCleanup 定期彻底删除超过保留天数的回收站文章
+func runTrashCleanup(ctx context.Context, articleUseCase biz.ArticleUseCase) {
+	cfg := config.AppConfig.Trash
+	if cfg.RetentionDays <= 0 {
+		logger.Info("Trash auto purge is disabled")
+		return
+	}
+
+	interval := time.Duration(cfg.CleanupInterval) * time.Minute
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := articleUseCase.PurgeExpiredTrash(cfg.RetentionDays)
+		if err != nil {
+			logger.Error("Failed to purge expired trash: ", err)
+		} else if purged > 0 {
+			logger.Info(fmt.Sprintf("Purged %d articles from trash", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runCounterFlush 定期将 Redis 中缓冲的文章计数写入数据库，退出前再刷新一次
+func runCounterFlush(ctx context.Context, articleUseCase biz.ArticleUseCase) {
+	interval := time.Duration(config.AppConfig.Counters.FlushInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// 未开启缓冲时仍会刷新，确保关闭缓冲前残留在 Redis 中的计数写入数据库
+		if _, err := articleUseCase.FlushCounters(); err != nil {
+			logger.Error("Failed to flush article counters: ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			if _, err := articleUseCase.FlushCounters(); err != nil {
+				logger.Error("Failed to flush article counters: ", err)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runSitemapRefresh 定期重新生成站点地图，内容变更时缓存会被清除并在下次访问时生成
+func runSitemapRefresh(ctx context.Context, sitemapUseCase biz.SitemapUseCase) {
+	if config.AppConfig.Sitemap.SiteURL == "" {
+		logger.Info("Sitemap is disabled")
+		return
+	}
+
+	interval := time.Duration(config.AppConfig.Sitemap.Interval) * time.Minute
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := sitemapUseCase.Regenerate(); err != nil {
+			logger.Error("Failed to regenerate sitemap: ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runWebhookRetry 定期重试投递失败或投递中断的 Webhook
+func runWebhookRetry(ctx context.Context, webhookUseCase biz.WebhookUseCase) {
+	interval := time.Duration(config.AppConfig.Webhook.RetryInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		retried, err := webhookUseCase.RetryDue()
+		if err != nil {
+			logger.Error("Failed to retry webhook deliveries: ", err)
+		} else if retried > 0 {
+			logger.Info(fmt.Sprintf("Retried %d webhook deliveries", retried))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runYuqueSync 定期增量同步已启用的语雀知识库
+func runYuqueSync(ctx context.Context, yuqueUseCase biz.YuqueUseCase) {
+	interval := time.Duration(config.AppConfig.Yuque.SyncInterval) * time.Minute
+	if interval <= 0 {
+		logger.Info("Yuque auto sync is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		synced, err := yuqueUseCase.SyncAll()
+		if err != nil {
+			logger.Error("Failed to sync yuque knowledge bases: ", err)
+		}
+		if synced > 0 {
+			logger.Info(fmt.Sprintf("Synced %d yuque knowledge bases", synced))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runBackup 定期备份数据并按保留策略清理旧备份
+func runBackup(ctx context.Context, backupUseCase biz.BackupUseCase) {
+	interval := time.Duration(config.AppConfig.Backup.Interval) * time.Hour
+	if interval <= 0 {
+		logger.Info("Automatic backup is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		item, err := backupUseCase.Create()
+		if err != nil {
+			logger.Error("Failed to create backup: ", err)
+			continue
+		}
+		logger.Info(fmt.Sprintf("Created backup %s (%d bytes, %s)", item.Name, item.Size, item.Location))
+
+		removed, err := backupUseCase.Prune()
+		if err != nil {
+			logger.Error("Failed to prune backups: ", err)
+		}
+		if removed > 0 {
+			logger.Info(fmt.Sprintf("Pruned %d old backups", removed))
+		}
+	}
+}
+
+// runUploadCleanup 每小时清理过期未完成的分片上传
+func runUploadCleanup(ctx context.Context, uploadUseCase biz.UploadUseCase) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		cleaned, err := uploadUseCase.CleanupExpired()
+		if err != nil {
+			logger.Error("Failed to clean up expired uploads: ", err)
+		} else if cleaned > 0 {
+			logger.Info(fmt.Sprintf("Cleaned up %d expired uploads", cleaned))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runStorageStats 每 6 小时重新统计存储用量，扫描较慢，接口只读取缓存结果
+func runStorageStats(ctx context.Context, storageStatsUseCase biz.StorageStatsUseCase) {
+	ticker := time.NewTicker(6 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if _, err := storageStatsUseCase.Refresh(); err != nil {
+			logger.Error("Failed to refresh storage stats: ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runLoginLogCleanup 每天删除超过保留天数的登录日志
+func runLoginLogCleanup(ctx context.Context, loginLogUseCase biz.LoginLogUseCase) {
+	retentionDays := config.AppConfig.LoginLog.RetentionDays
+	if retentionDays <= 0 {
+		logger.Info("Login log cleanup is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		purged, err := loginLogUseCase.PurgeExpired(retentionDays)
+		if err != nil {
+			logger.Error("Failed to purge expired login logs: ", err)
+		} else if purged > 0 {
+			logger.Info(fmt.Sprintf("Purged %d login logs", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runPushCleanup 每天清理推送失败次数过多或长期未更新的浏览器推送订阅
+func runPushCleanup(ctx context.Context, pushUseCase biz.PushUseCase) {
+	if config.AppConfig.WebPush.PrivateKey == "" {
+		logger.Info("Web push is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		purged, err := pushUseCase.PurgeExpired()
+		if err != nil {
+			logger.Error("Failed to purge expired push subscriptions: ", err)
+		} else if purged > 0 {
+			logger.Info(fmt.Sprintf("Purged %d push subscriptions", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runFriendLinkCheck 定期检测审核通过的友链是否可以访问
+func runFriendLinkCheck(ctx context.Context, friendLinkUseCase biz.FriendLinkUseCase) {
+	interval := time.Duration(config.AppConfig.FriendLink.CheckInterval) * time.Hour
+	if interval <= 0 {
+		logger.Info("Friend link check is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		checked, dead, err := friendLinkUseCase.CheckAll(ctx)
+		if err != nil {
+			logger.Error("Failed to check friend links: ", err)
+			continue
+		}
+		if checked > 0 {
+			logger.Info(fmt.Sprintf("Checked %d friend links, %d marked as dead", checked, dead))
+		}
+	}
+}
+
+// runExportWorker 执行异步导出任务，并每小时清理过期的导出文件
+func runExportWorker(ctx context.Context, articleUseCase biz.ArticleUseCase) {
+	if redis.Client == nil {
+		logger.Info("Async export worker is disabled (redis not configured)")
+		return
+	}
+
+	lastCleanup := time.Time{}
+	for ctx.Err() == nil {
+		if time.Since(lastCleanup) >= time.Hour {
+			lastCleanup = time.Now()
+			if removed, err := articleUseCase.CleanupExportFiles(); err != nil {
+				logger.Error("Failed to clean up export files: ", err)
+			} else if removed > 0 {
+				logger.Info(fmt.Sprintf("Removed %d expired export files", removed))
+			}
+		}
+
+		if err := articleUseCase.ProcessExportJob(ctx); err != nil {
+			logger.Error("Failed to process export job: ", err)
+			// 队列不可用时稍后重试
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(10 * time.Second):
+			}
+		}
+	}
+}
+
+// runAnnouncementNotify 每分钟推送到达开始时间的定时公告
+func runAnnouncementNotify(ctx context.Context, announcementUseCase biz.AnnouncementUseCase) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		notified, err := announcementUseCase.NotifyPending()
+		if err != nil {
+			logger.Error("Failed to notify scheduled announcements: ", err)
+		} else if notified > 0 {
+			logger.Info(fmt.Sprintf("Notified %d announcements", notified))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/pkg/encrypt"
+)
+
+// 敏感字段重新加密工具
+// 轮换 encryption.key 后执行：将旧密钥加密的数据和历史明文数据统一用当前密钥重新加密，并重建盲索引。
+// 全部完成后即可从 encryption.previous_keys 中移除旧密钥。
+//
+//	go run ./cmd/reencrypt -config config.yaml
+
+// encryptedColumn 需要加密的列，hash 为空表示该列没有盲索引
+type encryptedColumn struct {
+	table  string
+	column string
+	hash   string
+}
+
+var columns = []encryptedColumn{
+	{table: "users", column: "email", hash: "email_hash"},
+	{table: "page_visits", column: "ip", hash: "ip_hash"},
+	{table: "webhooks", column: "secret"},
+	{table: "yuque_syncs", column: "token"},
+}
+
+type row struct {
+	ID    uint
+	Value string
+	Hash  string
+}
+
+func main() {
+	configPath := flag.String("config", "config.yaml", "config file path")
+	batchSize := flag.Int("batch", 500, "rows per batch")
+	dryRun := flag.Bool("dry-run", false, "only report rows that need re-encryption")
+	flag.Parse()
+
+	// 加载配置
+	if err := config.LoadConfig(*configPath); err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+
+	// 初始化加密密钥
+	if err := encrypt.Init(); err != nil {
+		log.Fatalf("初始化加密密钥失败: %v", err)
+	}
+	if !encrypt.Enabled() {
+		fmt.Println("警告：未配置 encryption.key，已加密的数据将被还原为明文")
+	}
+
+	// 初始化数据库
+	if err := config.InitDatabase(); err != nil {
+		log.Fatalf("初始化数据库失败: %v", err)
+	}
+
+	for _, col := range columns {
+		updated, failed, err := reencrypt(col, *batchSize, *dryRun)
+		if err != nil {
+			log.Fatalf("处理 %s.%s 失败: %v", col.table, col.column, err)
+		}
+		fmt.Printf("%s.%s: 更新 %d 行，失败 %d 行\n", col.table, col.column, updated, failed)
+	}
+
+	if *dryRun {
+		fmt.Println("\ndry-run 模式，未写入数据库")
+	} else {
+		fmt.Println("\n处理完成！")
+	}
+}
+
+// reencrypt 按主键分批处理一列数据
+func reencrypt(col encryptedColumn, batchSize int, dryRun bool) (int, int, error) {
+	current := encrypt.CurrentKeyID()
+	updated, failed := 0, 0
+	var lastID uint
+
+	hashColumn := "''"
+	if col.hash != "" {
+		hashColumn = col.hash
+	}
+
+	for {
+		var rows []row
+		err := config.DB.Table(col.table).
+			Select(fmt.Sprintf("id, %s AS value, %s AS hash", col.column, hashColumn)).
+			Where("id > ?", lastID).
+			Order("id ASC").
+			Limit(batchSize).
+			Scan(&rows).Error
+		if err != nil {
+			return updated, failed, err
+		}
+		if len(rows) == 0 {
+			return updated, failed, nil
+		}
+		lastID = rows[len(rows)-1].ID
+
+		for _, r := range rows {
+			if r.Value == "" {
+				continue
+			}
+
+			plain, err := encrypt.Decrypt(r.Value)
+			if err != nil {
+				fmt.Printf("  ✗ %s id=%d 解密失败: %v\n", col.table, r.ID, err)
+				failed++
+				continue
+			}
+			hash := ""
+			if col.hash != "" {
+				hash = encrypt.BlindIndex(plain)
+			}
+
+			// 已使用当前密钥加密且索引正确的跳过
+			if encrypt.KeyIDOf(r.Value) == current && r.Hash == hash {
+				continue
+			}
+
+			sealed, err := encrypt.Encrypt(plain)
+			if err != nil {
+				fmt.Printf("  ✗ %s id=%d 加密失败: %v\n", col.table, r.ID, err)
+				failed++
+				continue
+			}
+
+			if !dryRun {
+				values := map[string]interface{}{col.column: sealed}
+				if col.hash != "" {
+					values[col.hash] = hash
+				}
+				err = config.DB.Table(col.table).Where("id = ?", r.ID).UpdateColumns(values).Error
+				if err != nil {
+					fmt.Printf("  ✗ %s id=%d 更新失败: %v\n", col.table, r.ID, err)
+					failed++
+					continue
+				}
+			}
+			updated++
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/encrypt"
+	"github.com/ydcloud-dy/leaf-api/pkg/oss"
+)
+
+// 备份恢复工具
+// 从本地备份文件（-file，备份压缩包或 /backups/export 导出的 JSON 文档）
+// 或已保存的备份（-name，OSS 或 backup.dir 中）恢复数据。
+// 建议先加 -dry-run 查看将要发生的变更，确认后再去掉该参数执行。
+//
+//	go run ./cmd/restore -config config.yaml -name leaf-backup-20260101-030000-9f86d081884c7d65.zip -dry-run
+//	go run ./cmd/restore -config config.yaml -file ./leaf-backup-20260101-030000-9f86d081884c7d65.zip
+//	go run ./cmd/restore -config config.yaml -file ./leaf-export-20260101-030000.json
+
+func main() {
+	configPath := flag.String("config", "config.yaml", "config file path")
+	filePath := flag.String("file", "", "local backup archive (.zip) or full export (.json) to restore")
+	name := flag.String("name", "", "name of a stored backup to restore")
+	dryRun := flag.Bool("dry-run", false, "only report what would change")
+	verbose := flag.Bool("v", false, "print every changed record")
+	flag.Parse()
+
+	if (*filePath == "") == (*name == "") {
+		log.Fatal("请指定 -file 或 -name 之一")
+	}
+
+	// 加载配置
+	if err := config.LoadConfig(*configPath); err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+
+	// 初始化字段加密，用于解密备份中的密码哈希和写入加密字段
+	if err := encrypt.Init(); err != nil {
+		log.Fatalf("初始化字段加密失败: %v", err)
+	}
+
+	// 初始化数据库
+	if err := config.InitDatabase(); err != nil {
+		log.Fatalf("初始化数据库失败: %v", err)
+	}
+
+	d, err := data.NewData(config.DB)
+	if err != nil {
+		log.Fatalf("初始化数据层失败: %v", err)
+	}
+	backupUseCase := biz.NewBackupUseCase(d)
+
+	var report *dto.RestoreReport
+	if *name != "" {
+		// 未配置 OSS 时从本地备份目录读取
+		if err := oss.Init(); err != nil {
+			fmt.Println("未使用 OSS:", err)
+		}
+		report, err = backupUseCase.RestoreByName(*name, *dryRun)
+	} else {
+		report, err = restoreFile(backupUseCase, *filePath, *dryRun)
+	}
+	if err != nil {
+		log.Fatalf("恢复失败: %v", err)
+	}
+
+	fmt.Printf("备份时间: %s，格式版本: %d\n\n", report.BackupCreatedAt.Format("2006-01-02 15:04:05"), report.Version)
+	for _, entity := range report.Entities {
+		fmt.Printf("%s: 新增 %d，更新 %d，未变化 %d，跳过 %d\n",
+			entity.Entity, entity.Created, entity.Updated, entity.Unchanged, entity.Skipped)
+		for _, change := range entity.Changes {
+			if !*verbose && change.Action != "skipped" {
+				continue
+			}
+			if change.Reason != "" {
+				fmt.Printf("  %s #%d %s（%s）\n", change.Action, change.BackupID, change.Name, change.Reason)
+			} else {
+				fmt.Printf("  %s #%d -> #%d %s\n", change.Action, change.BackupID, change.ID, change.Name)
+			}
+		}
+	}
+
+	if *dryRun {
+		fmt.Println("\ndry-run 模式，未写入数据库")
+	} else {
+		fmt.Println("\n恢复完成！如已启用搜索引擎，请重建搜索索引")
+	}
+}
+
+// restoreFile 从本地文件恢复，.json 文件按全量导出文档导入
+func restoreFile(backupUseCase biz.BackupUseCase, path string, dryRun bool) (*dto.RestoreReport, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		return backupUseCase.ImportJSON(file, dryRun)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return backupUseCase.Restore(file, info.Size(), dryRun)
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ydcloud-dy/leaf-api/pkg/webpush"
+)
+
+// VAPID 密钥生成工具
+// 生成浏览器推送使用的密钥对，私钥填入 web_push.private_key（或环境变量 WEB_PUSH_PRIVATE_KEY）。
+// 更换密钥后已有的浏览器订阅全部失效，需要用户重新订阅。
+//
+//	go run ./cmd/vapidkeys
+func main() {
+	publicKey, privateKey, err := webpush.GenerateKeys()
+	if err != nil {
+		log.Fatalf("生成密钥失败: %v", err)
+	}
+	fmt.Println("public key: ", publicKey)
+	fmt.Println("private key:", privateKey)
+}
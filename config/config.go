@@ -13,12 +13,41 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	OSS      OSSConfig      `mapstructure:"oss"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	Log      LogConfig      `mapstructure:"log"`
+	Server        ServerConfig        `mapstructure:"server"`
+	Database      DatabaseConfig      `mapstructure:"database"`
+	JWT           JWTConfig           `mapstructure:"jwt"`
+	OSS           OSSConfig           `mapstructure:"oss"`
+	Redis         RedisConfig         `mapstructure:"redis"`
+	Log           LogConfig           `mapstructure:"log"`
+	Secrets       SecretsConfig       `mapstructure:"secrets"`
+	Encryption    EncryptionConfig    `mapstructure:"encryption"`
+	Signing       SigningConfig       `mapstructure:"signing"`
+	Trash         TrashConfig         `mapstructure:"trash"`
+	Search        SearchConfig        `mapstructure:"search"`
+	Views         ViewsConfig         `mapstructure:"views"`
+	Counters      CountersConfig      `mapstructure:"counters"`
+	Sitemap       SitemapConfig       `mapstructure:"sitemap"`
+	Webhook       WebhookConfig       `mapstructure:"webhook"`
+	Markdown      MarkdownConfig      `mapstructure:"markdown"`
+	Image         ImageConfig         `mapstructure:"image"`
+	Upload        UploadConfig        `mapstructure:"upload"`
+	Yuque         YuqueConfig         `mapstructure:"yuque"`
+	Export        ExportConfig        `mapstructure:"export"`
+	Backup        BackupConfig        `mapstructure:"backup"`
+	Comment       CommentConfig       `mapstructure:"comment"`
+	Reaction      ReactionConfig      `mapstructure:"reaction"`
+	FriendLink    FriendLinkConfig    `mapstructure:"friend_link"`
+	Mail          MailConfig          `mapstructure:"mail"`
+	WebPush       WebPushConfig       `mapstructure:"web_push"`
+	Realtime      RealtimeConfig      `mapstructure:"realtime"`
+	Register      RegisterConfig      `mapstructure:"register"`
+	PasswordReset PasswordResetConfig `mapstructure:"password_reset"`
+	OAuth         OAuthConfig         `mapstructure:"oauth"`
+	TwoFactor     TwoFactorConfig     `mapstructure:"two_factor"`
+	LoginLog      LoginLogConfig      `mapstructure:"login_log"`
+	Captcha       CaptchaConfig       `mapstructure:"captcha"`
+	I18n          I18nConfig          `mapstructure:"i18n"`
+	RateLimit     RateLimitConfig     `mapstructure:"rate_limit"`
 }
 
 type ServerConfig struct {
@@ -46,16 +75,257 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
+	Secret        string   `mapstructure:"secret"`
+	Expire        int      `mapstructure:"expire"`         // token lifetime in hours when refresh tokens are unavailable (no Redis)
+	AccessExpire  int      `mapstructure:"access_expire"`  // access token lifetime in minutes when refresh tokens are issued, default 15
+	RefreshExpire int      `mapstructure:"refresh_expire"` // refresh token lifetime in hours, extended on every rotation, default 720
+	KeyID         string   `mapstructure:"key_id"`         // current signing key id, written to the token "kid" header
+	PreviousKeys  []JWTKey `mapstructure:"previous_keys"`  // retired keys, only used to verify tokens issued before rotation
+}
+
+type JWTKey struct {
+	ID     string `mapstructure:"id"`
 	Secret string `mapstructure:"secret"`
-	Expire int    `mapstructure:"expire"`
+}
+
+type EncryptionConfig struct {
+	Key          string          `mapstructure:"key"`           // base64 encoded 32-byte AES key, empty disables field encryption
+	KeyID        string          `mapstructure:"key_id"`        // id of the current key, stored in every ciphertext
+	PreviousKeys []EncryptionKey `mapstructure:"previous_keys"` // retired keys, only used to decrypt
+	IndexKey     string          `mapstructure:"index_key"`     // blind index HMAC key, derived from key when empty
+}
+
+type EncryptionKey struct {
+	ID  string `mapstructure:"id"`
+	Key string `mapstructure:"key"`
+}
+
+type SigningConfig struct {
+	Enabled bool   `mapstructure:"enabled"`  // require signed requests on public write endpoints
+	Secret  string `mapstructure:"secret"`   // HMAC secret shared with the blog frontend
+	MaxSkew int    `mapstructure:"max_skew"` // allowed clock skew in seconds, default 300
+}
+
+type TrashConfig struct {
+	RetentionDays   int `mapstructure:"retention_days"`   // days before deleted articles are purged, 0 disables auto purge
+	CleanupInterval int `mapstructure:"cleanup_interval"` // cleanup job interval in minutes, default 60
+}
+
+type SearchConfig struct {
+	Driver   string `mapstructure:"driver"`   // elasticsearch, meilisearch, bleve, empty disables full-text search
+	Address  string `mapstructure:"address"`  // e.g. http://127.0.0.1:9200 or http://127.0.0.1:7700
+	Index    string `mapstructure:"index"`    // index name, default articles
+	Username string `mapstructure:"username"` // elasticsearch basic auth
+	Password string `mapstructure:"password"` // elasticsearch basic auth
+	APIKey   string `mapstructure:"api_key"`  // elasticsearch api key or meilisearch master/admin key
+	Analyzer string `mapstructure:"analyzer"` // elasticsearch text analyzer, e.g. ik_max_word, default standard
+	Timeout  int    `mapstructure:"timeout"`  // request timeout in seconds, default 5
+	Path     string `mapstructure:"path"`     // bleve index directory, default data/search.bleve
+}
+
+type ViewsConfig struct {
+	DedupWindow int `mapstructure:"dedup_window"` // minutes a viewer is counted once per article, 0 counts every request
+}
+
+type CountersConfig struct {
+	FlushInterval int `mapstructure:"flush_interval"` // seconds between flushes of buffered view/like/favorite counts, 0 writes through
+}
+
+type SitemapConfig struct {
+	SiteURL      string `mapstructure:"site_url"`      // blog frontend origin used for <loc>, e.g. https://blog.example.com; empty disables the sitemap
+	Interval     int    `mapstructure:"interval"`      // regeneration interval in minutes, default 60
+	ArticlePath  string `mapstructure:"article_path"`  // placeholders {slug} {id}, default /article/{slug}
+	CategoryPath string `mapstructure:"category_path"` // placeholders {name} {id}, default /category/{name}
+	TagPath      string `mapstructure:"tag_path"`      // placeholders {name} {id}, default /tag/{name}
+	ChapterPath  string `mapstructure:"chapter_path"`  // placeholders {tag} {id}, default /notes/{tag}?chapter={id}
+	PagePath     string `mapstructure:"page_path"`     // placeholders {slug} {id}, default /page/{slug}
+}
+
+type WebhookConfig struct {
+	Timeout       int `mapstructure:"timeout"`        // delivery request timeout in seconds, default 10
+	MaxAttempts   int `mapstructure:"max_attempts"`   // attempts before a delivery is marked failed, default 6
+	RetryInterval int `mapstructure:"retry_interval"` // seconds between scans for due retries, default 60
+}
+
+type MarkdownConfig struct {
+	DiagramRenderer  string `mapstructure:"diagram_renderer"`  // Kroki-compatible endpoint for mermaid/plantuml, e.g. https://kroki.io; empty leaves diagrams to the frontend
+	ImageConcurrency int    `mapstructure:"image_concurrency"` // images downloaded in parallel when processing or exporting an article, default 4
+	ImageRetries     int    `mapstructure:"image_retries"`     // retries per image after a network or 5xx error, default 2
+}
+
+type ImageConfig struct {
+	StripEXIF   bool   `mapstructure:"strip_exif"`   // remove Exif/XMP/IPTC (GPS, camera model) from JPEGs before storing
+	Optimize    bool   `mapstructure:"optimize"`     // recompress uploaded and imported JPEG/PNG images
+	MinSize     int    `mapstructure:"min_size"`     // images smaller than this (KB) keep their original bytes, default 200
+	JPEGQuality int    `mapstructure:"jpeg_quality"` // JPEG re-encode quality 1-100, default 82
+	WebP        bool   `mapstructure:"webp"`         // also store a .webp sibling next to the original
+	WebPQuality int    `mapstructure:"webp_quality"` // WebP quality 1-100, default 80
+	WebPCommand string `mapstructure:"webp_command"` // cwebp executable, default cwebp from PATH
+
+	Variants       bool `mapstructure:"variants"`        // also store resized copies for srcset under <name>_thumbnail / <name>_medium keys
+	ThumbnailWidth int  `mapstructure:"thumbnail_width"` // thumbnail width in pixels, default 320
+	MediumWidth    int  `mapstructure:"medium_width"`    // medium width in pixels, default 960
+}
+
+type UploadConfig struct {
+	ChunkSize  int    `mapstructure:"chunk_size"`  // chunk size in MB for resumable uploads, default 5 (OSS requires at least 100KB per part)
+	MaxSize    int    `mapstructure:"max_size"`    // largest file accepted by resumable uploads in MB, default 2048
+	TempDir    string `mapstructure:"temp_dir"`    // directory for chunks when OSS is not configured, default ./tmp/uploads
+	SessionTTL int    `mapstructure:"session_ttl"` // hours an unfinished upload is kept before its chunks are discarded, default 24
+
+	AttachmentMaxSize int      `mapstructure:"attachment_max_size"` // largest attachment accepted in MB, default 100
+	AttachmentExts    []string `mapstructure:"attachment_exts"`     // allowed attachment extensions without the dot; empty uses the built-in document/archive list
+
+	VideoMaxSize int      `mapstructure:"video_max_size"` // largest video accepted by /files/videos in MB, default 100
+	VideoExts    []string `mapstructure:"video_exts"`     // allowed video extensions without the dot, default mp4, webm, mov, m4v
+}
+
+type CommentConfig struct {
+	HoldFirstTime bool `mapstructure:"hold_first_time"` // hold comments from users without an approved comment in the moderation queue
+	EditWindow    int  `mapstructure:"edit_window"`     // minutes after posting a commenter may edit or delete their own comment, 0 means no limit
+}
+
+type ReactionConfig struct {
+	Emojis []string `mapstructure:"emojis"` // emojis readers may react with, in display order; empty uses 👍 ❤️ 🎉 😄 😕 👀 🚀
+}
+
+type FriendLinkConfig struct {
+	ApplyLimit    int `mapstructure:"apply_limit"`    // applications accepted from one IP per day, default 3
+	CheckInterval int `mapstructure:"check_interval"` // hours between dead-link checks of approved links, 0 disables
+	Timeout       int `mapstructure:"timeout"`        // seconds to wait for a linked site, default 10
+	Concurrency   int `mapstructure:"concurrency"`    // links checked in parallel, default 5
+	MaxFailures   int `mapstructure:"max_failures"`   // consecutive failed checks before a link is hidden from the public list, default 3
+}
+
+type MailConfig struct {
+	Host     string `mapstructure:"host"`      // SMTP server, empty disables outgoing mail
+	Port     int    `mapstructure:"port"`      // default 587 (STARTTLS); 465 uses implicit TLS
+	Username string `mapstructure:"username"`  // SMTP auth user, empty skips authentication
+	Password string `mapstructure:"password"`  // SMTP auth password
+	From     string `mapstructure:"from"`      // sender address, default username
+	FromName string `mapstructure:"from_name"` // sender display name, also shown as the site name in templates
+
+	TemplateDir string `mapstructure:"template_dir"` // directory with {name}.html files overriding the built-in templates, re-read on every send
+	QueueSize   int    `mapstructure:"queue_size"`   // pending messages kept in memory, default 200
+	Workers     int    `mapstructure:"workers"`      // concurrent senders, default 2
+	MaxRetries  int    `mapstructure:"max_retries"`  // retries after a failed send, default 3
+	RetryDelay  int    `mapstructure:"retry_delay"`  // seconds before the first retry, doubled on each retry, default 30
+}
+
+type WebPushConfig struct {
+	PrivateKey      string `mapstructure:"private_key"`       // VAPID private key (base64url P-256 scalar) from go run ./cmd/vapidkeys; empty disables web push
+	Subject         string `mapstructure:"subject"`           // contact sent to push services, mailto: or https: URL
+	TTL             int    `mapstructure:"ttl"`               // seconds push services keep an undelivered message, default 86400
+	NotifyOnPublish bool   `mapstructure:"notify_on_publish"` // push newly published public articles to every subscriber
+	Concurrency     int    `mapstructure:"concurrency"`       // subscriptions pushed in parallel, default 10
+	MaxFailures     int    `mapstructure:"max_failures"`      // consecutive failed pushes before a subscription is removed, default 5
+	ExpireDays      int    `mapstructure:"expire_days"`       // days without a re-subscribe or successful push before a subscription is removed, default 180
+}
+
+type RealtimeConfig struct {
+	MaxConnections int `mapstructure:"max_connections"` // concurrent SSE streams per instance, default 1000
+	Heartbeat      int `mapstructure:"heartbeat"`       // seconds between keep-alive comments so proxies don't close idle streams, default 25
+	OnlineInterval int `mapstructure:"online_interval"` // seconds between online count checks, an event is sent only when it changes, default 15
+
+	PresenceInterval int `mapstructure:"presence_interval"` // seconds between presence pings a WebSocket client sends and reader counts it receives, default 30
+	PresenceTimeout  int `mapstructure:"presence_timeout"`  // seconds without a ping or heartbeat before a visitor counts as gone, default 90
+}
+
+type RegisterConfig struct {
+	VerifyEmail bool   `mapstructure:"verify_email"` // require email verification before login; the require_email_verification setting overrides it
+	VerifyURL   string `mapstructure:"verify_url"`   // frontend page receiving the token, {token} placeholder, default {sitemap.site_url}/verify-email?token={token}
+	TokenExpire int    `mapstructure:"token_expire"` // verification link lifetime in hours, default 24
+}
+
+type PasswordResetConfig struct {
+	ResetURL    string `mapstructure:"reset_url"`    // frontend page receiving the token, {token} placeholder, default {sitemap.site_url}/reset-password?token={token}
+	TokenExpire int    `mapstructure:"token_expire"` // reset link lifetime in minutes, default 30
+	EmailLimit  int    `mapstructure:"email_limit"`  // reset emails per address per hour, default 3
+	IPLimit     int    `mapstructure:"ip_limit"`     // reset requests per client IP per hour, default 10
+}
+
+type OAuthConfig struct {
+	CallbackBase string                         `mapstructure:"callback_base"` // public API origin used to build callback urls, e.g. https://api.example.com; empty uses the request host
+	FrontendURL  string                         `mapstructure:"frontend_url"`  // page receiving tokens in the url fragment after login, default {sitemap.site_url}/oauth/callback
+	Providers    map[string]OAuthProviderConfig `mapstructure:"providers"`     // keyed by provider name: github, google, wechat, wechat_mp, qq
+}
+
+type OAuthProviderConfig struct {
+	ClientID     string   `mapstructure:"client_id"`     // empty disables the provider; AppID for wechat, wechat_mp and qq
+	ClientSecret string   `mapstructure:"client_secret"` // AppSecret for wechat and wechat_mp, AppKey for qq
+	Scopes       []string `mapstructure:"scopes"`        // empty uses the provider defaults
+}
+
+type TwoFactorConfig struct {
+	Issuer       string   `mapstructure:"issuer"`        // name shown in authenticator apps, default Leaf Blog
+	EnforceRoles []string `mapstructure:"enforce_roles"` // roles that must use 2FA to sign in to the admin console, e.g. [super_admin, admin]
+}
+
+type LoginLogConfig struct {
+	GeoURL           string `mapstructure:"geo_url"`            // IP geolocation API with an {ip} placeholder returning ip-api.com style JSON (country, regionName, city); empty disables lookups
+	GeoTimeout       int    `mapstructure:"geo_timeout"`        // lookup timeout in seconds, default 3
+	NewLocationAlert bool   `mapstructure:"new_location_alert"` // email users when they sign in from a location not seen before
+	RetentionDays    int    `mapstructure:"retention_days"`     // records older than this are deleted, 0 keeps all
+}
+
+type CaptchaConfig struct {
+	Provider  string   `mapstructure:"provider"`   // image, slider, turnstile or hcaptcha; empty disables captcha. Requires redis
+	Scenes    []string `mapstructure:"scenes"`     // where a captcha is required: login, register, comment, password_reset, friend_link; empty means all
+	Expire    int      `mapstructure:"expire"`     // challenge lifetime in seconds, default 300
+	Tolerance int      `mapstructure:"tolerance"`  // allowed slider offset in pixels, default 5
+	SiteKey   string   `mapstructure:"site_key"`   // Turnstile / hCaptcha site key returned to the frontend widget
+	Secret    string   `mapstructure:"secret"`     // Turnstile / hCaptcha secret key
+	VerifyURL string   `mapstructure:"verify_url"` // overrides the provider's siteverify endpoint
+}
+
+type I18nConfig struct {
+	DefaultLocale string `mapstructure:"default_locale"` // zh-CN or en, used when Accept-Language matches no supported locale, default zh-CN
+}
+
+type RateLimitConfig struct {
+	Enabled bool                     `mapstructure:"enabled"` // token-bucket limiting of login, comment and search requests. Requires redis; requests pass when redis is unavailable
+	Rules   map[string]RateLimitRule `mapstructure:"rules"`   // keyed by route group: login, comment, search; a missing group uses its built-in default
+}
+
+type RateLimitRule struct {
+	Rate   int    `mapstructure:"rate"`   // requests refilled per period, 0 disables limiting for the group
+	Period int    `mapstructure:"period"` // refill period in seconds, default 60
+	Burst  int    `mapstructure:"burst"`  // bucket capacity, the most requests accepted at once, default rate
+	By     string `mapstructure:"by"`     // ip or user; user limits signed-in users by account and anonymous requests by ip, default ip
+}
+
+type YuqueConfig struct {
+	BaseURL      string `mapstructure:"base_url"`      // Yuque open API root, default https://www.yuque.com/api/v2
+	Timeout      int    `mapstructure:"timeout"`       // request timeout in seconds, default 30
+	SyncInterval int    `mapstructure:"sync_interval"` // minutes between automatic syncs of enabled knowledge bases, 0 disables
+}
+
+type ExportConfig struct {
+	PDFCommand string `mapstructure:"pdf_command"` // wkhtmltopdf executable, default wkhtmltopdf from PATH
+	PDFTimeout int    `mapstructure:"pdf_timeout"` // PDF rendering timeout in seconds, default 120
+	Dir        string `mapstructure:"dir"`         // directory for archives built by async export jobs, default ./exports
+	JobTTL     int    `mapstructure:"job_ttl"`     // hours an export job and its archive are kept, default 24
+}
+
+type BackupConfig struct {
+	Interval      int    `mapstructure:"interval"`       // hours between automatic backups, 0 disables
+	Dir           string `mapstructure:"dir"`            // local directory used when OSS is not configured, default ./backups
+	Prefix        string `mapstructure:"prefix"`         // OSS key prefix for backup archives, default backups/
+	RetentionDays int    `mapstructure:"retention_days"` // backups older than this are deleted, 0 keeps all
+	KeepLast      int    `mapstructure:"keep_last"`      // newest backups always kept regardless of age, default 7
 }
 
 type OSSConfig struct {
+	Driver          string `mapstructure:"driver"` // aliyun (default), s3, minio, cos, qiniu or local
 	Endpoint        string `mapstructure:"endpoint"`
 	AccessKeyID     string `mapstructure:"access_key_id"`
 	AccessKeySecret string `mapstructure:"access_key_secret"`
 	BucketName      string `mapstructure:"bucket_name"`
 	BaseURL         string `mapstructure:"base_url"`
+	Private         bool   `mapstructure:"private"`      // private bucket: stored URLs are rewritten to short-lived signed URLs in API responses
+	SignExpires     int    `mapstructure:"sign_expires"` // signed URL lifetime in seconds, default 3600
+	Region          string `mapstructure:"region"`       // S3-compatible drivers; cos/qiniu derive the endpoint from it when endpoint is empty
+	PathStyle       bool   `mapstructure:"path_style"`   // S3-compatible drivers: host/bucket/key instead of bucket.host/key, always on for minio
 }
 
 type RedisConfig struct {
@@ -107,6 +377,11 @@ func LoadConfig(configPath string) error {
 		return fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	// Resolve ${env:...}, ${file:...}, ${docker:...} and ${vault:...} references
+	if err := resolveSecrets(AppConfig); err != nil {
+		return fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+
 	// Set defaults for log config
 	if AppConfig.Log.Level == "" {
 		AppConfig.Log.Level = "info"
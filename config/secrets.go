@@ -0,0 +1,227 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Secret references let config.yaml point at a secret instead of holding it:
+//
+//	${env:DB_PASSWORD}            environment variable
+//	${env:REDIS_PASSWORD:-}       environment variable with a fallback
+//	${file:/run/secrets/db_pass}  file contents (trailing newline trimmed)
+//	${docker:db_pass}             Docker secret, shorthand for /run/secrets/db_pass
+//	${vault:leaf/db#password}     HashiCorp Vault KV v2, path#field
+var secretRefPattern = regexp.MustCompile(`^\$\{([a-z]+):(.+)\}$`)
+
+type SecretsConfig struct {
+	Vault VaultConfig `mapstructure:"vault"`
+}
+
+type VaultConfig struct {
+	Address string `mapstructure:"address"` // falls back to VAULT_ADDR
+	Token   string `mapstructure:"token"`   // falls back to VAULT_TOKEN
+	Mount   string `mapstructure:"mount"`   // KV v2 mount path, default "secret"
+}
+
+// SecretProvider resolves the reference part of ${scheme:ref}
+type SecretProvider interface {
+	Resolve(ref string) (string, error)
+}
+
+var secretProviders = map[string]SecretProvider{}
+
+// RegisterSecretProvider registers a provider for the given scheme
+func RegisterSecretProvider(scheme string, p SecretProvider) {
+	secretProviders[scheme] = p
+}
+
+type envProvider struct{}
+
+func (envProvider) Resolve(ref string) (string, error) {
+	name, def, hasDefault := strings.Cut(ref, ":-")
+	if v, ok := os.LookupEnv(name); ok {
+		return v, nil
+	}
+	if hasDefault {
+		return def, nil
+	}
+	return "", fmt.Errorf("environment variable %s is not set", name)
+}
+
+type fileProvider struct {
+	dir string
+}
+
+func (p fileProvider) Resolve(ref string) (string, error) {
+	path := ref
+	if p.dir != "" {
+		path = filepath.Join(p.dir, ref)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+type vaultProvider struct {
+	cfg    VaultConfig
+	client *http.Client
+	cache  map[string]map[string]interface{}
+}
+
+func (p *vaultProvider) Resolve(ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || field == "" {
+		return "", fmt.Errorf("vault reference must be path#field, got %q", ref)
+	}
+
+	data, ok := p.cache[path]
+	if !ok {
+		var err error
+		if data, err = p.read(path); err != nil {
+			return "", err
+		}
+		p.cache[path] = data
+	}
+
+	v, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("field %s not found in vault path %s", field, path)
+	}
+	return fmt.Sprint(v), nil
+}
+
+func (p *vaultProvider) read(path string) (map[string]interface{}, error) {
+	addr := p.cfg.Address
+	if addr == "" {
+		addr = os.Getenv("VAULT_ADDR")
+	}
+	token := p.cfg.Token
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	mount := p.cfg.Mount
+	if mount == "" {
+		mount = "secret"
+	}
+	if addr == "" || token == "" {
+		return nil, fmt.Errorf("vault address or token is not configured")
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/data/%s", strings.TrimRight(addr, "/"), strings.Trim(mount, "/"), strings.TrimLeft(path, "/"))
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault path %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to read vault path %s: status %d", path, resp.StatusCode)
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+	return body.Data.Data, nil
+}
+
+// resolveSecrets replaces every ${scheme:ref} string field in cfg with the resolved secret
+func resolveSecrets(cfg *Config) error {
+	if _, ok := secretProviders["env"]; !ok {
+		RegisterSecretProvider("env", envProvider{})
+	}
+	if _, ok := secretProviders["file"]; !ok {
+		RegisterSecretProvider("file", fileProvider{})
+	}
+	if _, ok := secretProviders["docker"]; !ok {
+		RegisterSecretProvider("docker", fileProvider{dir: "/run/secrets"})
+	}
+	if _, ok := secretProviders["vault"]; !ok {
+		RegisterSecretProvider("vault", &vaultProvider{
+			cfg:    cfg.Secrets.Vault,
+			client: &http.Client{Timeout: 10 * time.Second},
+			cache:  make(map[string]map[string]interface{}),
+		})
+	}
+
+	return resolveValue(reflect.ValueOf(cfg).Elem(), "")
+}
+
+func resolveValue(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			name := t.Field(i).Tag.Get("mapstructure")
+			if name == "" {
+				name = t.Field(i).Name
+			}
+			if err := resolveValue(v.Field(i), strings.TrimPrefix(path+"."+name, ".")); err != nil {
+				return err
+			}
+		}
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return resolveValue(v.Elem(), path)
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := resolveValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		// map values are not addressable, resolve a copy and write it back
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			if err := resolveValue(elem, fmt.Sprintf("%s.%v", path, iter.Key())); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	case reflect.String:
+		m := secretRefPattern.FindStringSubmatch(v.String())
+		if m == nil {
+			return nil
+		}
+		p, ok := secretProviders[m[1]]
+		if !ok {
+			return fmt.Errorf("%s: unknown secret provider %q", path, m[1])
+		}
+		secret, err := p.Resolve(m[2])
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if !v.CanSet() {
+			return fmt.Errorf("%s: secret reference cannot be resolved in place", path)
+		}
+		v.SetString(secret)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type secretsTestConfig struct {
+	Password  string                         `mapstructure:"password"`
+	Plain     string                         `mapstructure:"plain"`
+	List      []string                       `mapstructure:"list"`
+	Providers map[string]OAuthProviderConfig `mapstructure:"providers"`
+	Nested    *VaultConfig                   `mapstructure:"nested"`
+	Nil       *VaultConfig                   `mapstructure:"nil"`
+}
+
+func TestResolveValue(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "db_pass"), []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("LEAF_TEST_SECRET", "from-env")
+
+	secretProviders = map[string]SecretProvider{
+		"env":    envProvider{},
+		"file":   fileProvider{},
+		"docker": fileProvider{dir: dir},
+	}
+
+	tests := []struct {
+		name    string
+		in      secretsTestConfig
+		want    secretsTestConfig
+		wantErr bool
+	}{
+		{
+			name: "env and plain values",
+			in:   secretsTestConfig{Password: "${env:LEAF_TEST_SECRET}", Plain: "keep-me"},
+			want: secretsTestConfig{Password: "from-env", Plain: "keep-me"},
+		},
+		{
+			name: "env fallback",
+			in:   secretsTestConfig{Password: "${env:LEAF_TEST_UNSET:-fallback}"},
+			want: secretsTestConfig{Password: "fallback"},
+		},
+		{
+			name:    "missing env without fallback",
+			in:      secretsTestConfig{Password: "${env:LEAF_TEST_UNSET}"},
+			wantErr: true,
+		},
+		{
+			name: "file and docker secrets",
+			in:   secretsTestConfig{Password: "${file:" + filepath.Join(dir, "db_pass") + "}", Plain: "${docker:db_pass}"},
+			want: secretsTestConfig{Password: "from-file", Plain: "from-file"},
+		},
+		{
+			name: "slice elements",
+			in:   secretsTestConfig{List: []string{"a", "${env:LEAF_TEST_SECRET}"}},
+			want: secretsTestConfig{List: []string{"a", "from-env"}},
+		},
+		{
+			name: "map values",
+			in: secretsTestConfig{Providers: map[string]OAuthProviderConfig{
+				"github": {ClientID: "id", ClientSecret: "${env:LEAF_TEST_SECRET}"},
+			}},
+			want: secretsTestConfig{Providers: map[string]OAuthProviderConfig{
+				"github": {ClientID: "id", ClientSecret: "from-env"},
+			}},
+		},
+		{
+			name: "pointer to struct",
+			in:   secretsTestConfig{Nested: &VaultConfig{Token: "${env:LEAF_TEST_SECRET}"}},
+			want: secretsTestConfig{Nested: &VaultConfig{Token: "from-env"}},
+		},
+		{
+			name:    "unknown provider",
+			in:      secretsTestConfig{Password: "${unknown:x}"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.in
+			err := resolveValue(reflect.ValueOf(&got).Elem(), "")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/realtime"
+)
+
+var (
+	// ErrAnnouncementNotFound 公告不存在
+	ErrAnnouncementNotFound = errors.New("公告不存在")
+	// ErrAnnouncementInvalid 公告参数不合法
+	ErrAnnouncementInvalid = errors.New("公告参数不合法")
+)
+
+// announcementSeverityRank 前台按级别从高到低排列公告
+var announcementSeverityRank = map[string]int{
+	po.AnnouncementCritical: 0,
+	po.AnnouncementWarning:  1,
+	po.AnnouncementSuccess:  2,
+	po.AnnouncementInfo:     3,
+}
+
+// AnnouncementUseCase 站点公告业务用例接口
+type AnnouncementUseCase interface {
+	// Active 前台查询正在展示的公告，按级别从高到低、创建时间从新到旧排列
+	Active() ([]*dto.AnnouncementResponse, error)
+	// List 后台分页查询公告
+	List(req *dto.AnnouncementListRequest) (*dto.PageResponse, error)
+	// Create 创建公告，需要推送且已开始展示时立即推送到通知中心
+	Create(req *dto.AnnouncementRequest) (*dto.AnnouncementResponse, error)
+	// Update 更新公告
+	Update(id uint, req *dto.AnnouncementRequest) (*dto.AnnouncementResponse, error)
+	// Delete 删除公告
+	Delete(id uint) error
+	// NotifyPending 推送已到开始时间、需要推送且尚未推送的公告，返回推送数量
+	NotifyPending() (int, error)
+}
+
+// announcementUseCase 站点公告业务用例实现
+type announcementUseCase struct {
+	data *data.Data
+}
+
+// NewAnnouncementUseCase 创建站点公告业务用例
+func NewAnnouncementUseCase(d *data.Data) AnnouncementUseCase {
+	return &announcementUseCase{data: d}
+}
+
+// Active 前台查询正在展示的公告
+func (uc *announcementUseCase) Active() ([]*dto.AnnouncementResponse, error) {
+	announcements, err := uc.data.AnnouncementRepo.ListActive(time.Now())
+	if err != nil {
+		return nil, errors.New("查询公告失败")
+	}
+	sort.SliceStable(announcements, func(i, j int) bool {
+		return announcementSeverityRank[announcements[i].Severity] < announcementSeverityRank[announcements[j].Severity]
+	})
+
+	items := make([]*dto.AnnouncementResponse, 0, len(announcements))
+	for _, announcement := range announcements {
+		items = append(items, convertToAnnouncementResponse(announcement))
+	}
+	return items, nil
+}
+
+// List 后台分页查询公告
+func (uc *announcementUseCase) List(req *dto.AnnouncementListRequest) (*dto.PageResponse, error) {
+	now := time.Now()
+	announcements, total, err := uc.data.AnnouncementRepo.List(req.Page, req.Limit, req.Status, req.Keyword, now)
+	if err != nil {
+		return nil, errors.New("查询公告列表失败")
+	}
+
+	items := make([]*dto.AnnouncementResponse, 0, len(announcements))
+	for _, announcement := range announcements {
+		items = append(items, convertToAnnouncementAdmin(announcement, now))
+	}
+
+	return &dto.PageResponse{
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Data:  items,
+	}, nil
+}
+
+// Create 创建公告，未指定时默认启用且可关闭
+func (uc *announcementUseCase) Create(req *dto.AnnouncementRequest) (*dto.AnnouncementResponse, error) {
+	announcement := &po.Announcement{Enabled: true, Dismissible: true}
+	if err := applyAnnouncement(announcement, req); err != nil {
+		return nil, err
+	}
+	if err := uc.data.AnnouncementRepo.Create(announcement); err != nil {
+		return nil, errors.New("创建公告失败")
+	}
+	uc.notifyIfDue(announcement)
+	return convertToAnnouncementAdmin(announcement, time.Now()), nil
+}
+
+// Update 更新公告
+func (uc *announcementUseCase) Update(id uint, req *dto.AnnouncementRequest) (*dto.AnnouncementResponse, error) {
+	announcement, err := uc.data.AnnouncementRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrAnnouncementNotFound
+	}
+	if err := applyAnnouncement(announcement, req); err != nil {
+		return nil, err
+	}
+	if err := uc.data.AnnouncementRepo.Update(announcement); err != nil {
+		return nil, errors.New("更新公告失败")
+	}
+	uc.notifyIfDue(announcement)
+	return convertToAnnouncementAdmin(announcement, time.Now()), nil
+}
+
+// Delete 删除公告
+func (uc *announcementUseCase) Delete(id uint) error {
+	if _, err := uc.data.AnnouncementRepo.FindByID(id); err != nil {
+		return ErrAnnouncementNotFound
+	}
+	if err := uc.data.AnnouncementRepo.Delete(id); err != nil {
+		return errors.New("删除公告失败")
+	}
+	return nil
+}
+
+// NotifyPending 推送已到开始时间的公告，由定时任务调用
+func (uc *announcementUseCase) NotifyPending() (int, error) {
+	announcements, err := uc.data.AnnouncementRepo.ListPendingNotify(time.Now())
+	if err != nil {
+		return 0, err
+	}
+	notified := 0
+	for _, announcement := range announcements {
+		if uc.notify(announcement) {
+			notified++
+		}
+	}
+	return notified, nil
+}
+
+// notifyIfDue 公告保存后，需要推送、尚未推送且正在展示时立即推送，定时开始的公告由定时任务推送
+func (uc *announcementUseCase) notifyIfDue(announcement *po.Announcement) {
+	if announcement.Notify && announcement.NotifiedAt == nil &&
+		announcementStatus(announcement, time.Now()) == po.AnnouncementStatusActive {
+		uc.notify(announcement)
+	}
+}
+
+// notify 推送公告到所有连接的通知中心，先标记已推送，多个实例只有一个推送
+func (uc *announcementUseCase) notify(announcement *po.Announcement) bool {
+	now := time.Now()
+	marked, err := uc.data.AnnouncementRepo.MarkNotified(announcement.ID, now)
+	if err != nil {
+		logger.Warn("Failed to mark announcement notified: ", err)
+		return false
+	}
+	if !marked {
+		return false
+	}
+	announcement.NotifiedAt = &now
+
+	err = realtime.Publish(realtime.ChannelSite, realtime.EventNotification, &dto.RealtimeNotification{
+		Type:           dto.NotificationAnnouncement,
+		Excerpt:        announcement.Title,
+		AnnouncementID: announcement.ID,
+		Severity:       announcement.Severity,
+		CreatedAt:      now,
+	})
+	if err != nil {
+		logger.Warn("Failed to publish announcement notification: ", err)
+	}
+	return true
+}
+
+// applyAnnouncement 校验请求并写入公告
+func applyAnnouncement(announcement *po.Announcement, req *dto.AnnouncementRequest) error {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return fmt.Errorf("%w: 标题不能为空", ErrAnnouncementInvalid)
+	}
+	link := strings.TrimSpace(req.Link)
+	if link != "" && !validLinkURL(link) {
+		return fmt.Errorf("%w: 链接地址必须是 / 开头的站内路径或 http(s) 地址", ErrAnnouncementInvalid)
+	}
+	if req.StartAt != nil && req.EndAt != nil && !req.EndAt.After(*req.StartAt) {
+		return fmt.Errorf("%w: 结束时间必须晚于开始时间", ErrAnnouncementInvalid)
+	}
+
+	severity := req.Severity
+	if severity == "" {
+		severity = po.AnnouncementInfo
+	}
+
+	announcement.Title = title
+	announcement.Content = strings.TrimSpace(req.Content)
+	announcement.Severity = severity
+	announcement.Link = link
+	announcement.StartAt = req.StartAt
+	announcement.EndAt = req.EndAt
+	announcement.Notify = req.Notify
+	if req.Dismissible != nil {
+		announcement.Dismissible = *req.Dismissible
+	}
+	if req.Enabled != nil {
+		announcement.Enabled = *req.Enabled
+	}
+	return nil
+}
+
+// announcementStatus 公告在 now 时刻的展示状态
+func announcementStatus(announcement *po.Announcement, now time.Time) string {
+	switch {
+	case !announcement.Enabled:
+		return po.AnnouncementStatusDisabled
+	case announcement.EndAt != nil && !now.Before(*announcement.EndAt):
+		return po.AnnouncementStatusExpired
+	case announcement.StartAt != nil && now.Before(*announcement.StartAt):
+		return po.AnnouncementStatusScheduled
+	default:
+		return po.AnnouncementStatusActive
+	}
+}
+
+// convertToAnnouncementResponse 转换为前台公告
+func convertToAnnouncementResponse(announcement *po.Announcement) *dto.AnnouncementResponse {
+	return &dto.AnnouncementResponse{
+		ID:          announcement.ID,
+		Title:       announcement.Title,
+		Content:     announcement.Content,
+		Severity:    announcement.Severity,
+		Link:        announcement.Link,
+		Dismissible: announcement.Dismissible,
+		StartAt:     announcement.StartAt,
+		EndAt:       announcement.EndAt,
+		UpdatedAt:   announcement.UpdatedAt,
+	}
+}
+
+// convertToAnnouncementAdmin 转换为后台公告，附带启用状态、展示状态和推送情况
+func convertToAnnouncementAdmin(announcement *po.Announcement, now time.Time) *dto.AnnouncementResponse {
+	resp := convertToAnnouncementResponse(announcement)
+	resp.Enabled = &announcement.Enabled
+	resp.Status = announcementStatus(announcement, now)
+	resp.Notify = &announcement.Notify
+	resp.NotifiedAt = announcement.NotifiedAt
+	resp.CreatedAt = &announcement.CreatedAt
+	return resp
+}
@@ -0,0 +1,159 @@
+package biz
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/realtime"
+)
+
+// stubAnnouncementRepo 保存在内存中的公告仓储
+type stubAnnouncementRepo struct {
+	data.AnnouncementRepo
+	announcements []*po.Announcement
+}
+
+func (r *stubAnnouncementRepo) Create(announcement *po.Announcement) error {
+	announcement.ID = uint(len(r.announcements) + 1)
+	r.announcements = append(r.announcements, announcement)
+	return nil
+}
+
+func (r *stubAnnouncementRepo) ListActive(now time.Time) ([]*po.Announcement, error) {
+	var active []*po.Announcement
+	for i := len(r.announcements) - 1; i >= 0; i-- {
+		if announcementStatus(r.announcements[i], now) == po.AnnouncementStatusActive {
+			active = append(active, r.announcements[i])
+		}
+	}
+	return active, nil
+}
+
+func (r *stubAnnouncementRepo) ListPendingNotify(now time.Time) ([]*po.Announcement, error) {
+	var pending []*po.Announcement
+	for _, announcement := range r.announcements {
+		if announcement.Notify && announcement.NotifiedAt == nil && announcementStatus(announcement, now) == po.AnnouncementStatusActive {
+			pending = append(pending, announcement)
+		}
+	}
+	return pending, nil
+}
+
+func (r *stubAnnouncementRepo) MarkNotified(id uint, at time.Time) (bool, error) {
+	announcement := r.announcements[id-1]
+	if announcement.NotifiedAt != nil {
+		return false, nil
+	}
+	announcement.NotifiedAt = &at
+	return true, nil
+}
+
+func TestAnnouncementActiveOrdersBySeverity(t *testing.T) {
+	repo := &stubAnnouncementRepo{}
+	uc := NewAnnouncementUseCase(&data.Data{AnnouncementRepo: repo})
+
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	disabled, fixed := false, false
+	requests := []*dto.AnnouncementRequest{
+		{Title: "维护通知", Severity: po.AnnouncementCritical, Dismissible: &fixed},
+		{Title: "新功能上线"},
+		{Title: "已结束", EndAt: &past},
+		{Title: "未开始", StartAt: &future},
+		{Title: "已停用", Enabled: &disabled},
+		{Title: "活动提醒", Severity: po.AnnouncementWarning},
+	}
+	for _, req := range requests {
+		if _, err := uc.Create(req); err != nil {
+			t.Fatalf("Create(%s): %v", req.Title, err)
+		}
+	}
+
+	active, err := uc.Active()
+	if err != nil {
+		t.Fatalf("Active: %v", err)
+	}
+	var titles []string
+	for _, item := range active {
+		titles = append(titles, item.Title)
+		if item.Status != "" || item.Enabled != nil {
+			t.Errorf("public announcement exposes admin fields: %+v", item)
+		}
+	}
+	want := []string{"维护通知", "活动提醒", "新功能上线"}
+	if len(titles) != len(want) {
+		t.Fatalf("active = %v, want %v", titles, want)
+	}
+	for i := range want {
+		if titles[i] != want[i] {
+			t.Fatalf("active = %v, want %v", titles, want)
+		}
+	}
+	if active[0].Dismissible || !active[2].Dismissible || active[2].Severity != po.AnnouncementInfo {
+		t.Errorf("dismissible and severity defaults not applied: %+v, %+v", active[0], active[2])
+	}
+
+	invalid := []*dto.AnnouncementRequest{
+		{Title: "  "},
+		{Title: "链接", Link: "javascript:alert(1)"},
+		{Title: "时间", StartAt: &future, EndAt: &past},
+	}
+	for _, req := range invalid {
+		if _, err := uc.Create(req); !errors.Is(err, ErrAnnouncementInvalid) {
+			t.Errorf("Create(%+v) = %v, want ErrAnnouncementInvalid", req, err)
+		}
+	}
+}
+
+func TestAnnouncementNotifiesOnce(t *testing.T) {
+	repo := &stubAnnouncementRepo{}
+	uc := NewAnnouncementUseCase(&data.Data{AnnouncementRepo: repo})
+
+	sub, err := realtime.Subscribe(0, realtime.ChannelSite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	created, err := uc.Create(&dto.AnnouncementRequest{Title: "维护通知", Severity: po.AnnouncementWarning, Notify: true})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.NotifiedAt == nil {
+		t.Fatal("an active announcement was not notified on create")
+	}
+	select {
+	case event := <-sub.Events():
+		var notification dto.RealtimeNotification
+		if err := json.Unmarshal(event.Data, &notification); err != nil {
+			t.Fatal(err)
+		}
+		if event.Type != realtime.EventNotification || notification.Type != dto.NotificationAnnouncement ||
+			notification.AnnouncementID != created.ID || notification.Severity != po.AnnouncementWarning {
+			t.Fatalf("event = %s %+v", event.Type, notification)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("notification not received")
+	}
+
+	// 定时公告到达开始时间前不推送，到达后由定时任务推送一次
+	start := time.Now().Add(time.Hour)
+	if _, err := uc.Create(&dto.AnnouncementRequest{Title: "活动预告", StartAt: &start, Notify: true}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if notified, err := uc.NotifyPending(); err != nil || notified != 0 {
+		t.Fatalf("NotifyPending before start = %d, %v", notified, err)
+	}
+	started := time.Now().Add(-time.Minute)
+	repo.announcements[1].StartAt = &started
+	if notified, err := uc.NotifyPending(); err != nil || notified != 1 {
+		t.Fatalf("NotifyPending = %d, %v, want 1", notified, err)
+	}
+	if notified, err := uc.NotifyPending(); err != nil || notified != 0 {
+		t.Fatalf("NotifyPending again = %d, %v, want 0", notified, err)
+	}
+}
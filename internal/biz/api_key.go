@@ -0,0 +1,182 @@
+package biz
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+)
+
+// API Key 格式为 leaf_ 加 40 位十六进制随机串
+const (
+	apiKeyPrefix        = "leaf_"
+	apiKeyDisplayLength = 13 // 列表中显示的前缀长度（leaf_ 加 8 位）
+	apiKeyTouchInterval = time.Minute
+)
+
+var (
+	// ErrAPIKeyNotFound API Key 不存在或已吊销
+	ErrAPIKeyNotFound = errors.New("API Key 不存在或已吊销")
+	// ErrAPIKeyInvalid API Key 无效、已过期、已吊销或所属账号不可用
+	ErrAPIKeyInvalid = errors.New("无效的 API Key")
+	// ErrAPIKeyScopeInvalid 权限范围格式错误
+	ErrAPIKeyScopeInvalid = errors.New("权限范围格式错误，应为 资源:read、资源:write 或 *，如 articles:write")
+)
+
+// apiKeyScopePattern 权限范围：资源为管理接口路径的第一段，read 允许 GET，write 允许全部方法
+var apiKeyScopePattern = regexp.MustCompile(`^([a-z0-9-]+|\*):(read|write)$`)
+
+// APIKeyUseCase API Key 业务用例接口
+type APIKeyUseCase interface {
+	// List 查询用户的 API Key
+	List(userID uint) ([]*dto.APIKeyInfo, error)
+	// Create 创建 API Key，令牌内容只在响应中返回一次
+	Create(userID uint, req *dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error)
+	// Revoke 吊销 API Key
+	Revoke(userID, id uint) error
+	// Authenticate 校验 API Key，返回调用方以及权限范围是否包含该接口
+	Authenticate(key, method, path string) (*dto.APIKeyIdentity, bool, error)
+}
+
+// apiKeyUseCase API Key 业务用例实现
+type apiKeyUseCase struct {
+	data *data.Data
+}
+
+// NewAPIKeyUseCase 创建 API Key 业务用例
+func NewAPIKeyUseCase(d *data.Data) APIKeyUseCase {
+	return &apiKeyUseCase{data: d}
+}
+
+// List 查询用户的 API Key
+func (uc *apiKeyUseCase) List(userID uint) ([]*dto.APIKeyInfo, error) {
+	keys, err := uc.data.APIKeyRepo.ListByUser(userID)
+	if err != nil {
+		return nil, errors.New("查询 API Key 失败")
+	}
+	list := make([]*dto.APIKeyInfo, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, toAPIKeyInfo(key))
+	}
+	return list, nil
+}
+
+// Create 创建 API Key
+func (uc *apiKeyUseCase) Create(userID uint, req *dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error) {
+	scopes := make([]string, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if scope != "*" && !apiKeyScopePattern.MatchString(scope) {
+			return nil, ErrAPIKeyScopeInvalid
+		}
+		scopes = append(scopes, scope)
+	}
+
+	secret, err := randomHex(20)
+	if err != nil {
+		return nil, errors.New("生成 API Key 失败")
+	}
+	raw := apiKeyPrefix + secret
+	key := &po.APIKey{
+		UserID:  userID,
+		Name:    strings.TrimSpace(req.Name),
+		Prefix:  raw[:apiKeyDisplayLength],
+		KeyHash: tokenDigest(raw),
+		Scopes:  strings.Join(scopes, ","),
+	}
+	if req.ExpiresIn > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresIn)
+		key.ExpiresAt = &expiresAt
+	}
+	if err := uc.data.APIKeyRepo.Create(key); err != nil {
+		logger.Error("Failed to create API key: ", err)
+		return nil, errors.New("生成 API Key 失败")
+	}
+
+	return &dto.CreateAPIKeyResponse{APIKeyInfo: *toAPIKeyInfo(key), Key: raw}, nil
+}
+
+// Revoke 吊销 API Key，吊销后立即失效
+func (uc *apiKeyUseCase) Revoke(userID, id uint) error {
+	ok, err := uc.data.APIKeyRepo.Revoke(userID, id, time.Now())
+	if err != nil {
+		return errors.New("吊销 API Key 失败")
+	}
+	if !ok {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// Authenticate 校验 API Key
+// 调用方使用所属用户当前的角色，用户被禁用后其 API Key 同时失效
+func (uc *apiKeyUseCase) Authenticate(raw, method, path string) (*dto.APIKeyIdentity, bool, error) {
+	if !strings.HasPrefix(raw, apiKeyPrefix) {
+		return nil, false, ErrAPIKeyInvalid
+	}
+	key, err := uc.data.APIKeyRepo.FindByHash(tokenDigest(raw))
+	if err != nil {
+		return nil, false, ErrAPIKeyInvalid
+	}
+	now := time.Now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && now.After(*key.ExpiresAt)) {
+		return nil, false, ErrAPIKeyInvalid
+	}
+	user, err := uc.data.UserRepo.FindByID(key.UserID)
+	if err != nil || user.Status != po.UserStatusActive {
+		return nil, false, ErrAPIKeyInvalid
+	}
+
+	// 最后使用时间只用于展示，每分钟最多更新一次
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+		if err := uc.data.APIKeyRepo.Touch(key.ID, now); err != nil {
+			logger.Warn("Failed to update API key last used time: ", err)
+		}
+	}
+
+	identity := &dto.APIKeyIdentity{
+		KeyID:    key.ID,
+		UserID:   user.ID,
+		Username: user.Username,
+		Role:     user.Role,
+	}
+	return identity, apiKeyAllows(strings.Split(key.Scopes, ","), method, path), nil
+}
+
+// apiKeyAllows 权限范围是否包含该接口，资源为路径的第一段，如 /articles/:id 的资源为 articles
+func apiKeyAllows(scopes []string, method, path string) bool {
+	resource, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	write := method != "GET" && method != "HEAD"
+	for _, scope := range scopes {
+		if scope == "*" {
+			return true
+		}
+		name, access, ok := strings.Cut(scope, ":")
+		if !ok || (name != "*" && name != resource) {
+			continue
+		}
+		if access == "write" || !write {
+			return true
+		}
+	}
+	return false
+}
+
+// toAPIKeyInfo 转换为 API Key 信息
+func toAPIKeyInfo(key *po.APIKey) *dto.APIKeyInfo {
+	return &dto.APIKeyInfo{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     strings.Split(key.Scopes, ","),
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
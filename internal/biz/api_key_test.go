@@ -0,0 +1,152 @@
+package biz
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// stubAPIKeyRepo 内存中的 API Key 仓储
+type stubAPIKeyRepo struct {
+	data.APIKeyRepo
+	keys []*po.APIKey
+}
+
+func (r *stubAPIKeyRepo) Create(key *po.APIKey) error {
+	key.ID = uint(len(r.keys) + 1)
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *stubAPIKeyRepo) FindByHash(hash string) (*po.APIKey, error) {
+	for _, key := range r.keys {
+		if key.KeyHash == hash {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *stubAPIKeyRepo) Revoke(userID, id uint, at time.Time) (bool, error) {
+	for _, key := range r.keys {
+		if key.ID == id && key.UserID == userID && key.RevokedAt == nil {
+			key.RevokedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *stubAPIKeyRepo) Touch(id uint, at time.Time) error {
+	r.keys[id-1].LastUsedAt = &at
+	return nil
+}
+
+func TestAPIKeyAllows(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		method string
+		path   string
+		want   bool
+	}{
+		{name: "wildcard", scopes: []string{"*"}, method: "DELETE", path: "/users/:id", want: true},
+		{name: "write allows post", scopes: []string{"articles:write"}, method: "POST", path: "/articles", want: true},
+		{name: "write allows get", scopes: []string{"articles:write"}, method: "GET", path: "/articles/:id", want: true},
+		{name: "read allows get", scopes: []string{"articles:read"}, method: "GET", path: "/articles", want: true},
+		{name: "read denies put", scopes: []string{"articles:read"}, method: "PUT", path: "/articles/:id"},
+		{name: "other resource", scopes: []string{"articles:write"}, method: "POST", path: "/files/upload"},
+		{name: "prefix is not a resource match", scopes: []string{"article:write"}, method: "POST", path: "/articles"},
+		{name: "all resources read", scopes: []string{"*:read"}, method: "GET", path: "/comments", want: true},
+		{name: "all resources read denies write", scopes: []string{"*:read"}, method: "DELETE", path: "/comments/:id"},
+		{name: "any of several scopes", scopes: []string{"files:write", "articles:write"}, method: "POST", path: "/articles", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := apiKeyAllows(tt.scopes, tt.method, tt.path); got != tt.want {
+				t.Errorf("apiKeyAllows(%v, %s %s) = %v, want %v", tt.scopes, tt.method, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuthenticate(t *testing.T) {
+	users := &stubUserRepo{users: map[uint]*po.User{
+		1: {ID: 1, Username: "ci", Role: "admin", Status: po.UserStatusActive},
+		2: {ID: 2, Username: "banned", Role: "admin", Status: po.UserStatusBanned},
+	}}
+	keys := &stubAPIKeyRepo{}
+	uc := &apiKeyUseCase{data: &data.Data{UserRepo: users, APIKeyRepo: keys}}
+
+	create := func(userID uint, expiresIn int) string {
+		resp, err := uc.Create(userID, &dto.CreateAPIKeyRequest{Name: "ci", Scopes: []string{" Articles:Write "}, ExpiresIn: expiresIn})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		return resp.Key
+	}
+	valid := create(1, 0)
+	banned := create(2, 0)
+	expired := create(1, 1)
+	keys.keys[2].ExpiresAt = &time.Time{}
+	revoked := create(1, 0)
+	if err := uc.Revoke(1, 4); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		key         string
+		path        string
+		wantErr     error
+		wantAllowed bool
+	}{
+		{name: "valid", key: valid, path: "/articles/:id", wantAllowed: true},
+		{name: "outside scope", key: valid, path: "/users/:id"},
+		{name: "unknown", key: "leaf_0000", wantErr: ErrAPIKeyInvalid},
+		{name: "not an api key", key: "Bearer abc", wantErr: ErrAPIKeyInvalid},
+		{name: "owner banned", key: banned, wantErr: ErrAPIKeyInvalid},
+		{name: "expired", key: expired, wantErr: ErrAPIKeyInvalid},
+		{name: "revoked", key: revoked, wantErr: ErrAPIKeyInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, allowed, err := uc.Authenticate(tt.key, "POST", tt.path)
+			if err != tt.wantErr {
+				t.Fatalf("Authenticate err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if allowed != tt.wantAllowed {
+				t.Errorf("allowed = %v, want %v", allowed, tt.wantAllowed)
+			}
+			if identity.UserID != 1 || identity.Role != "admin" {
+				t.Errorf("identity = %+v, want user 1 with role admin", identity)
+			}
+		})
+	}
+
+	if keys.keys[0].LastUsedAt == nil {
+		t.Error("last used time not recorded")
+	}
+	if err := uc.Revoke(2, 1); err != ErrAPIKeyNotFound {
+		t.Errorf("revoking another user's key: err = %v, want %v", err, ErrAPIKeyNotFound)
+	}
+}
+
+func TestAPIKeyCreateRejectsInvalidScopes(t *testing.T) {
+	uc := &apiKeyUseCase{data: &data.Data{APIKeyRepo: &stubAPIKeyRepo{}}}
+	for _, scope := range []string{"articles", "articles:admin", "/articles:write", ""} {
+		if _, err := uc.Create(1, &dto.CreateAPIKeyRequest{Name: "ci", Scopes: []string{scope}}); err != ErrAPIKeyScopeInvalid {
+			t.Errorf("scope %q: err = %v, want %v", scope, err, ErrAPIKeyScopeInvalid)
+		}
+	}
+}
@@ -1,16 +1,20 @@
 package biz
 
 import (
+	"context"
 	"errors"
+	"io"
 	"strconv"
 
 	"github.com/gomarkdown/markdown"
 	"github.com/gomarkdown/markdown/html"
-	"github.com/gomarkdown/markdown/parser"
+	"github.com/ydcloud-dy/leaf-api/config"
 	"github.com/ydcloud-dy/leaf-api/internal/data"
 	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
 	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
 	mdutils "github.com/ydcloud-dy/leaf-api/pkg/markdown"
+	"github.com/ydcloud-dy/leaf-api/pkg/sanitize"
 )
 
 // ArticleUseCase 文章业务用例接口
@@ -25,12 +29,18 @@ type ArticleUseCase interface {
 	GetByID(id uint) (*dto.ArticleResponse, error)
 	// List 查询文章列表
 	List(req *dto.ArticleListRequest) (*dto.PageResponse, error)
+	// ListByCursor 游标分页查询文章列表
+	ListByCursor(req *dto.ArticleListRequest) (*dto.CursorPageResponse, error)
 	// UpdateStatus 更新文章状态
 	UpdateStatus(id uint, status int) error
+	// Pin 置顶文章
+	Pin(id uint, pinnedSort int) error
+	// Unpin 取消置顶
+	Unpin(id uint) error
 	// Search 搜索文章
 	Search(keyword string, page, limit int, sort string) (*dto.PageResponse, error)
-	// Archive 获取归档文章（按月份分组）
-	Archive(page, limit int) (*dto.PageResponse, error)
+	// Archive 获取归档文章（按年、月分组）
+	Archive() ([]dto.ArchiveYear, error)
 	// GetDefaultCategoryID 获取默认分类ID
 	GetDefaultCategoryID() (uint, error)
 	// BatchUpdateCover 批量更新封面
@@ -39,15 +49,96 @@ type ArticleUseCase interface {
 	BatchUpdateFields(req *dto.BatchUpdateFieldsRequest) error
 	// BatchDelete 批量删除
 	BatchDelete(articleIDs []uint) error
+	// BatchUpdateStatus 批量更新状态，返回每篇文章的处理结果
+	BatchUpdateStatus(req *dto.BatchUpdateStatusRequest) (*dto.BatchStatusResponse, error)
 	// GetAdjacentArticles 获取上一篇和下一篇文章
 	GetAdjacentArticles(id uint) (map[string]*dto.ArticleListItem, error)
-	// Export 导出文章为 ZIP 文件
-	Export(articleIDs []uint) ([]byte, error)
+	// Export 导出文章为 ZIP 文件并流式写入 w，profile 为 hugo / hexo 时按对应静态站点生成器的格式导出
+	Export(w io.Writer, articleIDs []uint, profile string) error
+	// ExportPDF 导出文章或笔记标签的章节树为 PDF，返回文件内容和文件名
+	ExportPDF(req *dto.ExportBookRequest) ([]byte, string, error)
+	// ExportEPUB 导出文章或笔记标签的章节树为 EPUB 电子书，返回文件内容和文件名
+	ExportEPUB(req *dto.ExportBookRequest) ([]byte, string, error)
+	// ListVersions 查询文章历史版本
+	ListVersions(articleID uint) ([]*dto.ArticleVersionItem, error)
+	// GetVersion 查询文章历史版本详情
+	GetVersion(articleID, versionID uint) (*dto.ArticleVersionResponse, error)
+	// DiffVersion 对比历史版本
+	DiffVersion(articleID, versionID, againstID uint) (*dto.ArticleVersionDiff, error)
+	// RestoreVersion 恢复到指定历史版本
+	RestoreVersion(articleID, versionID uint) (*dto.ArticleResponse, error)
+	// ListTrash 查询回收站文章
+	ListTrash(req *dto.TrashListRequest) (*dto.PageResponse, error)
+	// RestoreTrash 从回收站恢复文章
+	RestoreTrash(articleIDs []uint) error
+	// PurgeTrash 彻底删除回收站中的文章
+	PurgeTrash(articleIDs []uint) error
+	// PurgeExpiredTrash 彻底删除超过保留天数的回收站文章
+	PurgeExpiredTrash(retentionDays int) (int64, error)
+	// BackfillSlugs 为历史文章补全 slug
+	BackfillSlugs() (int, error)
+	// BackfillReadingStats 为历史文章补全字数和阅读时间
+	BackfillReadingStats() (int, error)
+	// FlushCounters 将 Redis 中缓冲的浏览、点赞、收藏数写入数据库
+	FlushCounters() (int, error)
+	// Clone 复制文章为新的草稿
+	Clone(id, authorID uint) (*dto.ArticleResponse, error)
+	// Autosave 自动保存草稿
+	Autosave(articleID, userID uint, req *dto.AutosaveRequest) (*dto.AutosaveResponse, error)
+	// GetDraft 获取未保存的草稿
+	GetDraft(articleID, userID uint) (*dto.ArticleDraftResponse, error)
+	// DiscardDraft 丢弃草稿
+	DiscardDraft(articleID, userID uint) error
+	// SetAuthors 设置文章的共同作者及署名顺序
+	SetAuthors(articleID uint, req *dto.SetArticleAuthorsRequest) (*dto.ArticleResponse, error)
+	// AddAuthor 添加共同作者
+	AddAuthor(articleID uint, req *dto.ArticleAuthorItem) (*dto.ArticleResponse, error)
+	// RemoveAuthor 移除共同作者
+	RemoveAuthor(articleID, userID uint) error
+	// ListTranslations 查询文章所在翻译组的全部语言版本
+	ListTranslations(articleID uint) (*dto.ArticleTranslationsResponse, error)
+	// LinkTranslation 将另一篇文章关联为当前文章的译文
+	LinkTranslation(articleID uint, req *dto.LinkTranslationRequest) (*dto.ArticleTranslationsResponse, error)
+	// UnlinkTranslation 将文章移出翻译组
+	UnlinkTranslation(articleID uint) error
+	// AcquireEditLock 获取或续期文章编辑锁，锁被他人持有时返回持有者信息
+	AcquireEditLock(articleID, adminID uint) (*dto.ArticleLockResponse, error)
+	// ReleaseEditLock 释放文章编辑锁
+	ReleaseEditLock(articleID, adminID uint) error
+	// ListAuditLogs 查询文章审计日志
+	ListAuditLogs(req *dto.ArticleAuditListRequest) (*dto.PageResponse, error)
+	// EnqueueExport 创建异步导出任务（需要 Redis），由后台任务生成 ZIP
+	EnqueueExport(req *dto.ExportArticleRequest, adminID uint) (*dto.ExportJobResponse, error)
+	// GetExportJob 查询导出任务状态和进度
+	GetExportJob(id string) (*dto.ExportJobResponse, error)
+	// ExportJobFile 获取已完成导出任务的文件路径
+	ExportJobFile(id string) (string, error)
+	// ProcessExportJob 从队列取出一个导出任务并执行，队列为空时等待片刻后返回
+	ProcessExportJob(ctx context.Context) error
+	// CleanupExportFiles 删除超过保留时间的导出文件，返回删除数量
+	CleanupExportFiles() (int, error)
+	// ImportMarkdown 导入 Markdown 文件，解析 Front Matter 中的元数据
+	ImportMarkdown(filename, content string, defaultCategoryID, authorID uint) (*dto.ArticleResponse, error)
+	// ImportArchive 导入 ZIP 导入包，返回导入报告
+	ImportArchive(archiveName string, data []byte, defaultCategoryID, authorID uint) (*dto.ImportReport, error)
+	// ImportNotion 导入 Notion 导出的 ZIP，页面层级展开为章节，返回导入报告
+	ImportNotion(archiveName string, data []byte, defaultCategoryID, authorID uint) (*dto.ImportReport, error)
+	// WithOperator 按操作人限定写操作范围（非超级管理员只能修改自己的文章）
+	WithOperator(userID uint, role string) ArticleUseCase
+	// WithContext 绑定请求 context，Webhook、推送等异步任务和日志沿用其中的请求ID
+	WithContext(ctx context.Context) ArticleUseCase
 }
 
 // articleUseCase 文章业务用例实现
 type articleUseCase struct {
 	data *data.Data
+	// scoped 为 true 时写操作仅作用于 ownerID 的文章
+	scoped  bool
+	ownerID uint
+	// operatorID 当前操作人，写入审计日志
+	operatorID uint
+	// ctx 当前请求的 context，未绑定时为 nil
+	ctx context.Context
 }
 
 // NewArticleUseCase 创建文章业务用例
@@ -55,6 +146,51 @@ func NewArticleUseCase(d *data.Data) ArticleUseCase {
 	return &articleUseCase{data: d}
 }
 
+// WithOperator 按操作人限定写操作范围
+func (uc *articleUseCase) WithOperator(userID uint, role string) ArticleUseCase {
+	if role == "super_admin" {
+		return &articleUseCase{data: uc.data, operatorID: userID, ctx: uc.ctx}
+	}
+	return &articleUseCase{data: uc.data, scoped: true, ownerID: userID, operatorID: userID, ctx: uc.ctx}
+}
+
+// WithContext 绑定请求 context
+func (uc *articleUseCase) WithContext(ctx context.Context) ArticleUseCase {
+	scoped := *uc
+	scoped.ctx = ctx
+	return &scoped
+}
+
+// context 当前请求的 context，未绑定时返回 context.Background()
+func (uc *articleUseCase) context() context.Context {
+	if uc.ctx == nil {
+		return context.Background()
+	}
+	return uc.ctx
+}
+
+// articleRepo 获取文章仓储（已按操作人限定范围）
+func (uc *articleUseCase) articleRepo() data.ArticleRepo {
+	if uc.scoped {
+		return uc.data.ArticleRepo.WithOwner(uc.ownerID)
+	}
+	return uc.data.ArticleRepo
+}
+
+// ErrArticleConflict 文章已被他人修改，提交的修订号已过期
+var ErrArticleConflict = errors.New("文章已被他人修改，请刷新后重试")
+
+// ownershipError 将仓储的归属错误转换为业务错误
+func ownershipError(err error, msg string) error {
+	if errors.Is(err, data.ErrArticleNotOwned) {
+		return errors.New("无权操作该文章")
+	}
+	if errors.Is(err, data.ErrArticleStale) {
+		return ErrArticleConflict
+	}
+	return errors.New(msg)
+}
+
 // Create 创建文章
 func (uc *articleUseCase) Create(req *dto.CreateArticleRequest, authorID uint) (*dto.ArticleResponse, error) {
 	// 验证分类是否存在
@@ -66,22 +202,29 @@ func (uc *articleUseCase) Create(req *dto.CreateArticleRequest, authorID uint) (
 	processor := mdutils.NewImageProcessor("uploads", "")
 	processedMarkdown, err := processor.ProcessMarkdownImages(req.ContentMarkdown)
 	if err != nil {
-		// 图片处理失败不阻断文章创建，使用原始内容
-		processedMarkdown = req.ContentMarkdown
+		// 图片处理失败不阻断文章创建，失败的图片保留原地址
+		logger.FromContext(uc.context()).Warn("Failed to process article images: ", err)
 	}
 
 	// 清理 Markdown 内容中的多余符号
 	processedMarkdown = mdutils.CleanMarkdownContent(processedMarkdown)
 
-	// 如果没有提供 HTML，则自动从 Markdown 转换
-	contentHTML := req.ContentHTML
+	// 如果没有提供 HTML，则自动从 Markdown 转换；提交的 HTML 存储前清理
+	contentHTML := sanitize.Article(req.ContentHTML)
 	if contentHTML == "" {
 		contentHTML = markdownToHTML(processedMarkdown)
 	}
 
+	// 生成 URL 别名
+	slugValue, err := uc.resolveSlug(req.Slug, req.Title, 0)
+	if err != nil {
+		return nil, err
+	}
+
 	// 创建文章
 	article := &po.Article{
 		Title:           req.Title,
+		Slug:            &slugValue,
 		ContentMarkdown: processedMarkdown, // 使用处理后的 Markdown
 		ContentHTML:     contentHTML,
 		Summary:         req.Summary,
@@ -92,6 +235,19 @@ func (uc *articleUseCase) Create(req *dto.CreateArticleRequest, authorID uint) (
 		Status:          req.Status,
 	}
 
+	// 设置可见性
+	if err := applyVisibility(article, req.Visibility, req.Password); err != nil {
+		return nil, err
+	}
+
+	// 统计字数和阅读时间
+	applyReadingStats(article)
+
+	// 设置 SEO 信息
+	if req.SEO != nil {
+		applySEO(article, req.SEO)
+	}
+
 	// 如果指定了创建时间，则设置
 	if req.CreatedAt != nil {
 		article.CreatedAt = *req.CreatedAt
@@ -108,6 +264,19 @@ func (uc *articleUseCase) Create(req *dto.CreateArticleRequest, authorID uint) (
 		}
 	}
 
+	// 记录审计日志（创建时记录初始字段）
+	changes := append(diffArticle(&po.Article{}, article), diffTags(nil, req.TagIDs)...)
+	uc.recordAudit(newAuditLog(authorID, po.AuditActionCreate, article, changes))
+
+	// 同步搜索索引，刷新站点地图
+	syncSearchIndex(uc.data, article.ID)
+	InvalidateSitemap()
+
+	// 直接发布时投递 Webhook 事件
+	if article.Status == 1 {
+		notifyArticles(uc.context(), uc.data, po.WebhookEventArticlePublished, article.ID)
+	}
+
 	// 重新查询文章（包含关联数据）
 	return uc.GetByID(article.ID)
 }
@@ -119,6 +288,7 @@ func (uc *articleUseCase) Update(id uint, req *dto.UpdateArticleRequest) (*dto.A
 	if err != nil {
 		return nil, errors.New("文章不存在")
 	}
+	before := *article
 
 	// 更新字段
 	if req.Title != "" {
@@ -129,21 +299,30 @@ func (uc *articleUseCase) Update(id uint, req *dto.UpdateArticleRequest) (*dto.A
 		processor := mdutils.NewImageProcessor("uploads", "")
 		processedMarkdown, err := processor.ProcessMarkdownImages(req.ContentMarkdown)
 		if err != nil {
-			// 图片处理失败不阻断文章更新，使用原始内容
-			processedMarkdown = req.ContentMarkdown
+			// 图片处理失败不阻断文章更新，失败的图片保留原地址
+			logger.FromContext(uc.context()).Warn("Failed to process article images: ", err)
 		}
 
 		// 清理 Markdown 内容中的多余符号
 		processedMarkdown = mdutils.CleanMarkdownContent(processedMarkdown)
 
 		article.ContentMarkdown = processedMarkdown
+		applyReadingStats(article)
 		// 如果提供了 Markdown，自动转换为 HTML（除非明确提供了 HTML）
 		if req.ContentHTML != "" {
-			article.ContentHTML = req.ContentHTML
+			article.ContentHTML = sanitize.Article(req.ContentHTML)
 		} else {
 			article.ContentHTML = markdownToHTML(processedMarkdown)
 		}
 	}
+	// 指定了 slug 则更新；历史文章没有 slug 时自动生成（标题变更不影响已有 slug，保持链接稳定）
+	if req.Slug != "" || article.Slug == nil || *article.Slug == "" {
+		s, err := uc.resolveSlug(req.Slug, article.Title, article.ID)
+		if err != nil {
+			return nil, err
+		}
+		article.Slug = &s
+	}
 	if req.Summary != "" {
 		article.Summary = req.Summary
 	}
@@ -162,23 +341,61 @@ func (uc *articleUseCase) Update(id uint, req *dto.UpdateArticleRequest) (*dto.A
 	if req.Status >= 0 {
 		article.Status = req.Status
 	}
+	// 未指定可见性时只允许修改加密文章的密码
+	if req.Visibility != "" || req.Password != "" {
+		visibility := req.Visibility
+		if visibility == "" {
+			visibility = article.Visibility
+		}
+		if err := applyVisibility(article, visibility, req.Password); err != nil {
+			return nil, err
+		}
+	}
+
+	// 提交了 SEO 信息时整体替换
+	if req.SEO != nil {
+		applySEO(article, req.SEO)
+	}
 
 	// 如果指定了创建时间，则更新
 	if req.CreatedAt != nil {
 		article.CreatedAt = *req.CreatedAt
 	}
+	// 提交了修订号时按提交的修订号做并发校验，防止覆盖他人的修改
+	if req.Revision != nil {
+		article.Revision = *req.Revision
+	}
 
-	if err := uc.data.ArticleRepo.Update(article); err != nil {
-		return nil, errors.New("更新文章失败")
+	if err := uc.articleRepo().Update(article); err != nil {
+		return nil, ownershipError(err, "更新文章失败")
 	}
 
 	// 更新标签关联
+	changes := diffArticle(&before, article)
 	if len(req.TagIDs) > 0 {
-		if err := uc.data.ArticleRepo.AssociateTags(article.ID, req.TagIDs); err != nil {
-			return nil, errors.New("更新标签失败")
+		if current, err := uc.data.ArticleRepo.FindByIDWithRelations(id); err == nil {
+			changes = append(changes, diffTags(current.Tags, req.TagIDs)...)
+		}
+		if err := uc.articleRepo().AssociateTags(article.ID, req.TagIDs); err != nil {
+			return nil, ownershipError(err, "更新标签失败")
 		}
 	}
 
+	// 记录审计日志
+	if len(changes) > 0 {
+		uc.recordAudit(newAuditLog(uc.operatorID, po.AuditActionUpdate, article, changes))
+	}
+
+	// 正式保存后清除自动保存的草稿
+	_ = uc.data.ArticleDraftRepo.DeleteByArticle(id)
+
+	// 同步搜索索引，刷新站点地图
+	syncSearchIndex(uc.data, id)
+	InvalidateSitemap()
+
+	// 投递 Webhook 事件
+	notifyArticles(uc.context(), uc.data, articleStatusEvent(before.Status, article.Status), id)
+
 	// 重新查询文章
 	return uc.GetByID(id)
 }
@@ -186,14 +403,25 @@ func (uc *articleUseCase) Update(id uint, req *dto.UpdateArticleRequest) (*dto.A
 // Delete 删除文章
 func (uc *articleUseCase) Delete(id uint) error {
 	// 检查文章是否存在
-	if _, err := uc.data.ArticleRepo.FindByID(id); err != nil {
+	article, err := uc.data.ArticleRepo.FindByID(id)
+	if err != nil {
 		return errors.New("文章不存在")
 	}
 
-	if err := uc.data.ArticleRepo.Delete(id); err != nil {
-		return errors.New("删除文章失败")
+	if err := uc.articleRepo().Delete(id); err != nil {
+		return ownershipError(err, "删除文章失败")
 	}
 
+	// 记录审计日志
+	uc.recordAudit(newAuditLog(uc.operatorID, po.AuditActionDelete, article, nil))
+
+	// 同步搜索索引，刷新站点地图
+	syncSearchIndex(uc.data, id)
+	InvalidateSitemap()
+
+	// 投递 Webhook 事件
+	notifyDeletedArticles(uc.context(), uc.data, article)
+
 	return nil
 }
 
@@ -209,8 +437,43 @@ func (uc *articleUseCase) GetByID(id uint) (*dto.ArticleResponse, error) {
 
 // List 查询文章列表
 func (uc *articleUseCase) List(req *dto.ArticleListRequest) (*dto.PageResponse, error) {
-	// 解析查询参数
-	var categoryID, tagID, chapterID uint
+	fields, err := parseListFields(req.Fields)
+	if err != nil {
+		return nil, err
+	}
+	categoryID, tagID, chapterID := uc.listFilters(req)
+
+	// 只读取列表需要的列，不读取正文
+	repo := uc.data.ArticleRepo.WithFields(fields.columns, fields.preloads)
+	// 博客前台只返回已发布且非私密的文章，翻译组按请求语言只返回一个版本
+	if req.Public {
+		req.Status = "1"
+		repo = repo.PublicOnly().WithLanguage(normalizeLanguage(req.Lang))
+	}
+
+	// 查询文章列表
+	articles, total, err := repo.List(
+		req.Page, req.Limit,
+		categoryID, tagID, chapterID, req.AuthorID,
+		req.Status, req.Keyword, req.Sort,
+	)
+	if err != nil {
+		return nil, errors.New("查询文章列表失败")
+	}
+
+	items := uc.convertToListItems(articles, req.Public)
+	uc.fillListLanguages(items)
+
+	return &dto.PageResponse{
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Data:  fields.pick(items),
+	}, nil
+}
+
+// listFilters 将分类名、标签名和章节 ID 参数解析为过滤 ID，无法解析的参数忽略
+func (uc *articleUseCase) listFilters(req *dto.ArticleListRequest) (categoryID, tagID, chapterID uint) {
 	if req.Category != "" {
 		category, err := uc.data.CategoryRepo.FindByName(req.Category)
 		if err == nil {
@@ -229,64 +492,111 @@ func (uc *articleUseCase) List(req *dto.ArticleListRequest) (*dto.PageResponse,
 			chapterID = uint(id)
 		}
 	}
+	return categoryID, tagID, chapterID
+}
 
-	// 查询文章列表
-	articles, total, err := uc.data.ArticleRepo.List(
-		req.Page, req.Limit,
-		categoryID, tagID, chapterID,
-		req.Status, req.Keyword, req.Sort,
-	)
-	if err != nil {
-		return nil, errors.New("查询文章列表失败")
-	}
-
-	// 转换为 DTO
+// convertToListItems 转换为列表项，public 为 true 时加密文章不展示摘要
+func (uc *articleUseCase) convertToListItems(articles []*po.Article, public bool) []dto.ArticleListItem {
 	items := make([]dto.ArticleListItem, 0, len(articles))
 	for _, article := range articles {
-		items = append(items, uc.convertToArticleListItem(article))
+		item := uc.convertToArticleListItem(article)
+		if public && articleLocked(article) {
+			item.Summary = ""
+		}
+		items = append(items, item)
 	}
-
-	return &dto.PageResponse{
-		Total: total,
-		Page:  req.Page,
-		Limit: req.Limit,
-		Data:  items,
-	}, nil
+	return items
 }
 
 // UpdateStatus 更新文章状态
 func (uc *articleUseCase) UpdateStatus(id uint, status int) error {
 	// 检查文章是否存在
-	if _, err := uc.data.ArticleRepo.FindByID(id); err != nil {
+	article, err := uc.data.ArticleRepo.FindByID(id)
+	if err != nil {
 		return errors.New("文章不存在")
 	}
 
-	if err := uc.data.ArticleRepo.UpdateStatus(id, status); err != nil {
-		return errors.New("更新状态失败")
+	if err := uc.articleRepo().UpdateStatus(id, status); err != nil {
+		return ownershipError(err, "更新状态失败")
+	}
+
+	// 记录审计日志
+	if article.Status != status {
+		changes := []dto.AuditChange{{Field: "status", Old: article.Status, New: status}}
+		uc.recordAudit(newAuditLog(uc.operatorID, po.AuditActionStatus, article, changes))
+	}
+
+	// 同步搜索索引，刷新站点地图
+	syncSearchIndex(uc.data, id)
+	InvalidateSitemap()
+
+	// 状态变化时投递 Webhook 事件
+	if article.Status != status {
+		notifyArticles(uc.context(), uc.data, articleStatusEvent(article.Status, status), id)
 	}
 
 	return nil
 }
 
+// Pin 置顶文章
+func (uc *articleUseCase) Pin(id uint, pinnedSort int) error {
+	article, err := uc.data.ArticleRepo.FindByID(id)
+	if err != nil {
+		return errors.New("文章不存在")
+	}
+
+	if err := uc.articleRepo().UpdatePinned(id, true, pinnedSort); err != nil {
+		return ownershipError(err, "置顶文章失败")
+	}
+
+	uc.auditPinned(article, true, pinnedSort)
+
+	return nil
+}
+
+// Unpin 取消置顶
+func (uc *articleUseCase) Unpin(id uint) error {
+	article, err := uc.data.ArticleRepo.FindByID(id)
+	if err != nil {
+		return errors.New("文章不存在")
+	}
+
+	if err := uc.articleRepo().UpdatePinned(id, false, 0); err != nil {
+		return ownershipError(err, "取消置顶失败")
+	}
+
+	uc.auditPinned(article, false, 0)
+
+	return nil
+}
+
 // convertToArticleResponse 转换为文章响应
 func (uc *articleUseCase) convertToArticleResponse(article *po.Article) *dto.ArticleResponse {
 	resp := &dto.ArticleResponse{
 		ID:              article.ID,
 		Title:           article.Title,
+		Slug:            articleSlug(article),
 		ContentMarkdown: article.ContentMarkdown,
-		ContentHTML:     article.ContentHTML,
+		ContentHTML:     sanitize.Article(article.ContentHTML),
 		Summary:         article.Summary,
 		Cover:           article.Cover,
 		AuthorID:        article.AuthorID,
 		CategoryID:      article.CategoryID,
 		ChapterID:       article.ChapterID,
 		Status:          article.Status,
+		IsTop:           article.IsTop,
+		PinnedSort:      article.PinnedSort,
+		Visibility:      article.Visibility,
+		WordCount:       article.WordCount,
+		ReadingTime:     article.ReadingTime,
+		Revision:        article.Revision,
 		ViewCount:       article.ViewCount,
 		LikeCount:       article.LikeCount,
 		FavoriteCount:   article.FavoriteCount,
 		CommentCount:    article.CommentCount,
 		CreatedAt:       article.CreatedAt,
 		UpdatedAt:       article.UpdatedAt,
+		SEO:             seoMeta(article),
 	}
 
 	// 作者信息
@@ -298,6 +608,7 @@ func (uc *articleUseCase) convertToArticleResponse(article *po.Article) *dto.Art
 			Avatar:   article.Author.Avatar,
 		}
 	}
+	resp.Authors = convertToCoAuthors(article.Authors)
 
 	// 分类信息
 	if article.Category.ID > 0 {
@@ -329,9 +640,15 @@ func (uc *articleUseCase) convertToArticleListItem(article *po.Article) dto.Arti
 	item := dto.ArticleListItem{
 		ID:            article.ID,
 		Title:         article.Title,
+		Slug:          articleSlug(article),
 		Summary:       article.Summary,
 		Cover:         article.Cover,
 		Status:        article.Status,
+		IsTop:         article.IsTop,
+		PinnedSort:    article.PinnedSort,
+		Visibility:    article.Visibility,
+		WordCount:     article.WordCount,
+		ReadingTime:   article.ReadingTime,
 		ViewCount:     article.ViewCount,
 		LikeCount:     article.LikeCount,
 		FavoriteCount: article.FavoriteCount,
@@ -348,6 +665,7 @@ func (uc *articleUseCase) convertToArticleListItem(article *po.Article) dto.Arti
 			Avatar:   article.Author.Avatar,
 		}
 	}
+	item.Authors = convertToCoAuthors(article.Authors)
 
 	// 分类信息
 	if article.Category.ID > 0 {
@@ -376,18 +694,23 @@ func (uc *articleUseCase) convertToArticleListItem(article *po.Article) dto.Arti
 
 // markdownToHTML 将 Markdown 转换为 HTML
 func markdownToHTML(md string) string {
-	// 创建 Markdown 解析器
-	extensions := parser.CommonExtensions | parser.AutoHeadingIDs | parser.NoEmptyLineBeforeBlock
-	p := parser.NewWithExtensions(extensions)
-	doc := p.Parse([]byte(md))
+	// 创建 Markdown 解析器（与目录提取使用同一组扩展）
+	doc := mdutils.NewParser().Parse([]byte(md))
 
-	// 创建 HTML 渲染器
+	// 创建 HTML 渲染器（mermaid / plantuml 代码块按图表输出，公式按 KaTeX 分隔符输出，视频链接按播放器输出）
 	htmlFlags := html.CommonFlags | html.HrefTargetBlank
-	opts := html.RendererOptions{Flags: htmlFlags}
+	opts := html.RendererOptions{
+		Flags: htmlFlags,
+		RenderNodeHook: mdutils.ChainHooks(
+			mdutils.DiagramHook(config.AppConfig.Markdown.DiagramRenderer),
+			mdutils.MathHook,
+			mdutils.VideoHook,
+		),
+	}
 	renderer := html.NewRenderer(opts)
 
-	// 渲染为 HTML
-	return string(markdown.Render(doc, renderer))
+	// 渲染为 HTML，Markdown 中的原始 HTML 会原样输出，需要清理
+	return sanitize.Article(string(markdown.Render(doc, renderer)))
 }
 
 // Search 搜索文章
@@ -401,18 +724,7 @@ func (uc *articleUseCase) Search(keyword string, page, limit int, sort string) (
 		Keyword: keyword,
 		Status:  "1", // 只搜索已发布的文章
 		Sort:    sort,
-	}
-	return uc.List(req)
-}
-
-// Archive 获取归档文章（返回所有已发布的文章，前端按月份分组）
-func (uc *articleUseCase) Archive(page, limit int) (*dto.PageResponse, error) {
-	req := &dto.ArticleListRequest{
-		PageRequest: dto.PageRequest{
-			Page:  page,
-			Limit: limit,
-		},
-		Status: "1", // 只返回已发布的文章
+		Public:  true,
 	}
 	return uc.List(req)
 }
@@ -435,10 +747,13 @@ func (uc *articleUseCase) BatchUpdateCover(articleIDs []uint, cover string) erro
 		return errors.New("文章ID列表不能为空")
 	}
 
-	if err := uc.data.ArticleRepo.BatchUpdateCover(articleIDs, cover); err != nil {
-		return errors.New("批量更新封面失败: " + err.Error())
+	before, _ := uc.data.ArticleRepo.FindByIDs(articleIDs)
+	if err := uc.articleRepo().BatchUpdateCover(articleIDs, cover); err != nil {
+		return ownershipError(err, "批量更新封面失败: "+err.Error())
 	}
 
+	uc.auditBatchUpdate(before, func(article *po.Article) { article.Cover = cover }, nil)
+
 	return nil
 }
 
@@ -472,19 +787,43 @@ func (uc *articleUseCase) BatchUpdateFields(req *dto.BatchUpdateFieldsRequest) e
 	}
 
 	// 更新基础字段
+	before, _ := uc.data.ArticleRepo.FindByIDs(req.ArticleIDs)
 	if len(updates) > 0 {
-		if err := uc.data.ArticleRepo.BatchUpdateFields(req.ArticleIDs, updates); err != nil {
-			return errors.New("批量更新字段失败: " + err.Error())
+		if err := uc.articleRepo().BatchUpdateFields(req.ArticleIDs, updates); err != nil {
+			return ownershipError(err, "批量更新字段失败: "+err.Error())
 		}
 	}
 
 	// 更新标签关联
 	if len(req.TagIDs) > 0 {
-		if err := uc.data.ArticleRepo.BatchAssociateTags(req.ArticleIDs, req.TagIDs); err != nil {
-			return errors.New("批量更新标签失败: " + err.Error())
+		if err := uc.articleRepo().BatchAssociateTags(req.ArticleIDs, req.TagIDs); err != nil {
+			return ownershipError(err, "批量更新标签失败: "+err.Error())
 		}
 	}
 
+	// 记录审计日志
+	uc.auditBatchUpdate(before, func(article *po.Article) {
+		if req.Cover != nil {
+			article.Cover = *req.Cover
+		}
+		if req.CategoryID != nil {
+			article.CategoryID = *req.CategoryID
+		}
+		if req.ChapterID != nil {
+			article.ChapterID = req.ChapterID
+		}
+		if req.CreatedAt != nil {
+			article.CreatedAt = *req.CreatedAt
+		}
+	}, req.TagIDs)
+
+	// 同步搜索索引，刷新站点地图
+	syncSearchIndex(uc.data, req.ArticleIDs...)
+	InvalidateSitemap()
+
+	// 投递 Webhook 事件
+	notifyArticles(uc.context(), uc.data, po.WebhookEventArticleUpdated, req.ArticleIDs...)
+
 	return nil
 }
 
@@ -494,10 +833,25 @@ func (uc *articleUseCase) BatchDelete(articleIDs []uint) error {
 		return errors.New("文章ID列表不能为空")
 	}
 
-	if err := uc.data.ArticleRepo.BatchDelete(articleIDs); err != nil {
-		return errors.New("批量删除失败: " + err.Error())
+	before, _ := uc.data.ArticleRepo.FindByIDs(articleIDs)
+	if err := uc.articleRepo().BatchDelete(articleIDs); err != nil {
+		return ownershipError(err, "批量删除失败: "+err.Error())
 	}
 
+	// 记录审计日志
+	logs := make([]*po.ArticleAuditLog, 0, len(before))
+	for _, article := range before {
+		logs = append(logs, newAuditLog(uc.operatorID, po.AuditActionDelete, article, nil))
+	}
+	uc.recordAudit(logs...)
+
+	// 同步搜索索引，刷新站点地图
+	syncSearchIndex(uc.data, articleIDs...)
+	InvalidateSitemap()
+
+	// 投递 Webhook 事件
+	notifyDeletedArticles(uc.context(), uc.data, before...)
+
 	return nil
 }
 
@@ -523,30 +877,81 @@ func (uc *articleUseCase) GetAdjacentArticles(id uint) (map[string]*dto.ArticleL
 	return result, nil
 }
 
-// Export 导出文章为 ZIP 文件
-func (uc *articleUseCase) Export(articleIDs []uint) ([]byte, error) {
-	var articles []*po.Article
-	var err error
+// exportBatchSize 导出全部文章时每批查询的数量
+const exportBatchSize = 100
 
-	// 获取文章列表
-	if len(articleIDs) == 0 {
-		// 获取所有已发布的文章
-		articles, _, err = uc.data.ArticleRepo.List(1, 10000, 0, 0, 0, "1", "", "created_at DESC")
-	} else {
+// Export 导出文章为 ZIP 文件，逐篇写入 w
+// 查询失败或没有文章时在写入任何数据之前返回错误；导出全部时分批查询，不一次性加载所有文章
+func (uc *articleUseCase) Export(w io.Writer, articleIDs []uint, profile string) error {
+	return uc.exportArticles(w, articleIDs, profile, func(done, total int) {})
+}
+
+// exportArticles 导出文章为 ZIP 文件，每写完一篇调用 progress 报告进度
+func (uc *articleUseCase) exportArticles(w io.Writer, articleIDs []uint, profile string, progress func(done, total int)) error {
+	// 调用导出工具创建ZIP
+	// Hugo / Hexo 格式的固定链接与站点地图中的前台地址一致
+	exporter := mdutils.NewArticleExporter()
+	if profile != "" && profile != "default" {
+		exporter.WithProfile(mdutils.ExportProfile(profile), config.AppConfig.Sitemap.ArticlePath)
+	}
+
+	if len(articleIDs) > 0 {
 		// 获取指定ID的文章
-		articles, err = uc.data.ArticleRepo.FindByIDs(articleIDs)
+		articles, err := uc.data.ArticleRepo.FindByIDs(articleIDs)
+		if err != nil {
+			return errors.New("获取文章列表失败: " + err.Error())
+		}
+		if len(articles) == 0 {
+			return errors.New("没有找到要导出的文章")
+		}
+
+		stream := exporter.NewZipStream(w)
+		for i, article := range articles {
+			if err := stream.Add(article); err != nil {
+				return err
+			}
+			progress(i+1, len(articles))
+		}
+		return closeExportStream(stream)
 	}
 
+	// 获取所有已发布的文章
+	articles, total, err := uc.data.ArticleRepo.List(1, exportBatchSize, 0, 0, 0, 0, "1", "", "created_at DESC")
 	if err != nil {
-		return nil, errors.New("获取文章列表失败: " + err.Error())
+		return errors.New("获取文章列表失败: " + err.Error())
+	}
+	if total == 0 {
+		return errors.New("没有找到要导出的文章")
 	}
 
-	if len(articles) == 0 {
-		return nil, errors.New("没有找到要导出的文章")
+	stream := exporter.NewZipStream(w)
+	done := 0
+	for page := 1; ; page++ {
+		if page > 1 {
+			articles, _, err = uc.data.ArticleRepo.List(page, exportBatchSize, 0, 0, 0, 0, "1", "", "created_at DESC")
+			if err != nil {
+				return errors.New("获取文章列表失败: " + err.Error())
+			}
+		}
+		for _, article := range articles {
+			if err := stream.Add(article); err != nil {
+				return err
+			}
+			done++
+			progress(done, int(total))
+		}
+		if len(articles) < exportBatchSize {
+			break
+		}
 	}
+	return closeExportStream(stream)
+}
 
-	// 调用导出工具创建ZIP
-	exporter := mdutils.NewArticleExporter()
-	return exporter.ExportToZip(articles)
+// closeExportStream 结束导出，获取失败的图片已在文章中保留原始链接，只记录日志
+func closeExportStream(stream *mdutils.ZipStream) error {
+	if failed := stream.ImageErrors(); len(failed) > 0 {
+		logger.Warn("Article export kept original links for images that could not be fetched: ", failed)
+	}
+	return stream.Close()
 }
 
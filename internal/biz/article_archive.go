@@ -0,0 +1,85 @@
+package biz
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+// 归档数据缓存，文章发布或删除后最多延迟 articleArchiveCacheExpire 生效
+const (
+	articleArchiveCacheKey    = "article:archive"
+	articleArchiveCacheExpire = 5 * time.Minute
+)
+
+// Archive 获取归档文章，按年、月分组并附带文章数（优先读取 Redis 缓存）
+func (uc *articleUseCase) Archive() ([]dto.ArchiveYear, error) {
+	if redis.Client != nil {
+		if cached, err := redis.Get(articleArchiveCacheKey); err == nil {
+			var years []dto.ArchiveYear
+			if err := json.Unmarshal([]byte(cached), &years); err == nil {
+				return years, nil
+			}
+		}
+	}
+
+	years, err := uc.buildArchive()
+	if err != nil {
+		return nil, err
+	}
+
+	if redis.Client != nil {
+		if b, err := json.Marshal(years); err == nil {
+			redis.SetWithExpire(articleArchiveCacheKey, string(b), articleArchiveCacheExpire)
+		}
+	}
+	return years, nil
+}
+
+// buildArchive 按数据库的年月统计结果组装归档，文章按创建时间归入对应月份
+func (uc *articleUseCase) buildArchive() ([]dto.ArchiveYear, error) {
+	repo := uc.data.ArticleRepo.PublicOnly()
+	counts, err := repo.ArchiveCounts()
+	if err != nil {
+		return nil, errors.New("查询归档统计失败")
+	}
+	articles, err := repo.ListArchive()
+	if err != nil {
+		return nil, errors.New("查询归档文章失败")
+	}
+
+	type yearMonth struct{ year, month int }
+	grouped := make(map[yearMonth][]dto.ArchiveArticle, len(counts))
+	for _, article := range articles {
+		key := yearMonth{article.CreatedAt.Year(), int(article.CreatedAt.Month())}
+		grouped[key] = append(grouped[key], dto.ArchiveArticle{
+			ID:        article.ID,
+			Title:     article.Title,
+			Slug:      articleSlug(article),
+			CreatedAt: article.CreatedAt,
+		})
+	}
+
+	years := make([]dto.ArchiveYear, 0)
+	for _, count := range counts {
+		if len(years) == 0 || years[len(years)-1].Year != count.Year {
+			years = append(years, dto.ArchiveYear{Year: count.Year, Months: []dto.ArchiveMonth{}})
+		}
+		year := &years[len(years)-1]
+		year.Count += count.Count
+
+		monthArticles := grouped[yearMonth{count.Year, count.Month}]
+		if monthArticles == nil {
+			monthArticles = []dto.ArchiveArticle{}
+		}
+		year.Months = append(year.Months, dto.ArchiveMonth{
+			Month:    count.Month,
+			Count:    count.Count,
+			Articles: monthArticles,
+		})
+	}
+	return years, nil
+}
@@ -0,0 +1,191 @@
+package biz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+)
+
+// auditField 参与审计比对的文章字段
+type auditField struct {
+	name  string
+	value func(article *po.Article) interface{}
+	// masked 为 true 时只记录字段是否变更，不记录新旧值（正文太长、密码不能落库）
+	masked bool
+}
+
+// auditFields 审计的字段列表，content_html 由 Markdown 生成，不单独记录
+var auditFields = []auditField{
+	{name: "title", value: func(a *po.Article) interface{} { return a.Title }},
+	{name: "slug", value: func(a *po.Article) interface{} { return articleSlug(a) }},
+	{name: "summary", value: func(a *po.Article) interface{} { return a.Summary }},
+	{name: "cover", value: func(a *po.Article) interface{} { return a.Cover }},
+	{name: "category_id", value: func(a *po.Article) interface{} { return a.CategoryID }},
+	{name: "chapter_id", value: func(a *po.Article) interface{} {
+		if a.ChapterID == nil {
+			return nil
+		}
+		return *a.ChapterID
+	}},
+	{name: "status", value: func(a *po.Article) interface{} { return a.Status }},
+	{name: "visibility", value: func(a *po.Article) interface{} { return a.Visibility }},
+	{name: "is_top", value: func(a *po.Article) interface{} { return a.IsTop }},
+	{name: "pinned_sort", value: func(a *po.Article) interface{} { return a.PinnedSort }},
+	{name: "created_at", value: func(a *po.Article) interface{} { return a.CreatedAt.Format(time.RFC3339) }},
+	{name: "meta_description", value: func(a *po.Article) interface{} { return a.MetaDescription }},
+	{name: "meta_keywords", value: func(a *po.Article) interface{} { return a.MetaKeywords }},
+	{name: "canonical_url", value: func(a *po.Article) interface{} { return a.CanonicalURL }},
+	{name: "noindex", value: func(a *po.Article) interface{} { return a.NoIndex }},
+	{name: "content_markdown", value: func(a *po.Article) interface{} { return a.ContentMarkdown }, masked: true},
+	{name: "access_password", value: func(a *po.Article) interface{} { return a.AccessPassword }, masked: true},
+}
+
+// diffArticle 比较文章修改前后的字段，返回字段级变更
+func diffArticle(before, after *po.Article) []dto.AuditChange {
+	var changes []dto.AuditChange
+	for _, field := range auditFields {
+		oldValue, newValue := field.value(before), field.value(after)
+		if oldValue == newValue {
+			continue
+		}
+		change := dto.AuditChange{Field: field.name}
+		if !field.masked {
+			change.Old, change.New = oldValue, newValue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// diffTags 比较标签变更，标签 ID 按升序记录
+func diffTags(before []po.Tag, after []uint) []dto.AuditChange {
+	oldIDs := make([]uint, 0, len(before))
+	for _, tag := range before {
+		oldIDs = append(oldIDs, tag.ID)
+	}
+	newIDs := uniqueIDs(after)
+	sort.Slice(oldIDs, func(i, j int) bool { return oldIDs[i] < oldIDs[j] })
+	sort.Slice(newIDs, func(i, j int) bool { return newIDs[i] < newIDs[j] })
+
+	if fmt.Sprint(oldIDs) == fmt.Sprint(newIDs) {
+		return nil
+	}
+	return []dto.AuditChange{{Field: "tag_ids", Old: oldIDs, New: newIDs}}
+}
+
+// uniqueIDs 去重
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// newAuditLog 构造审计日志
+func newAuditLog(operatorID uint, action string, article *po.Article, changes []dto.AuditChange) *po.ArticleAuditLog {
+	if changes == nil {
+		changes = []dto.AuditChange{}
+	}
+	payload, _ := json.Marshal(changes)
+	return &po.ArticleAuditLog{
+		ArticleID:    article.ID,
+		ArticleTitle: article.Title,
+		OperatorID:   operatorID,
+		Action:       action,
+		Changes:      string(payload),
+	}
+}
+
+// recordAudit 写入审计日志，失败只记录日志，不影响业务操作
+func (uc *articleUseCase) recordAudit(logs ...*po.ArticleAuditLog) {
+	if err := uc.data.ArticleAuditRepo.Create(logs...); err != nil {
+		logger.Warn("Failed to write article audit log: ", err)
+	}
+}
+
+// auditPinned 记录置顶状态变更
+func (uc *articleUseCase) auditPinned(article *po.Article, isTop bool, pinnedSort int) {
+	after := *article
+	after.IsTop, after.PinnedSort = isTop, pinnedSort
+	if changes := diffArticle(article, &after); len(changes) > 0 {
+		uc.recordAudit(newAuditLog(uc.operatorID, po.AuditActionUpdate, article, changes))
+	}
+}
+
+// auditBatchUpdate 记录批量更新，apply 将更新应用到文章副本上用于比对，tagIDs 为空表示未修改标签
+func (uc *articleUseCase) auditBatchUpdate(before []*po.Article, apply func(article *po.Article), tagIDs []uint) {
+	logs := make([]*po.ArticleAuditLog, 0, len(before))
+	for _, article := range before {
+		after := *article
+		apply(&after)
+		changes := diffArticle(article, &after)
+		if len(tagIDs) > 0 {
+			changes = append(changes, diffTags(article.Tags, tagIDs)...)
+		}
+		if len(changes) > 0 {
+			logs = append(logs, newAuditLog(uc.operatorID, po.AuditActionUpdate, article, changes))
+		}
+	}
+	uc.recordAudit(logs...)
+}
+
+// ListAuditLogs 查询文章审计日志，非超级管理员只能查看自己的操作记录
+func (uc *articleUseCase) ListAuditLogs(req *dto.ArticleAuditListRequest) (*dto.PageResponse, error) {
+	operatorID := req.OperatorID
+	if uc.scoped {
+		operatorID = uc.ownerID
+	}
+
+	logs, total, err := uc.data.ArticleAuditRepo.List(req.Page, req.Limit, req.ArticleID, operatorID, req.Action)
+	if err != nil {
+		return nil, errors.New("查询审计日志失败")
+	}
+
+	items := make([]*dto.ArticleAuditLogResponse, 0, len(logs))
+	for _, log := range logs {
+		items = append(items, convertToAuditLogResponse(log))
+	}
+
+	return &dto.PageResponse{
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Data:  items,
+	}, nil
+}
+
+// convertToAuditLogResponse 转换为审计日志响应
+func convertToAuditLogResponse(log *po.ArticleAuditLog) *dto.ArticleAuditLogResponse {
+	resp := &dto.ArticleAuditLogResponse{
+		ID:           log.ID,
+		ArticleID:    log.ArticleID,
+		ArticleTitle: log.ArticleTitle,
+		OperatorID:   log.OperatorID,
+		Action:       log.Action,
+		Changes:      []dto.AuditChange{},
+		CreatedAt:    log.CreatedAt,
+	}
+	if log.Changes != "" {
+		_ = json.Unmarshal([]byte(log.Changes), &resp.Changes)
+	}
+	if log.Operator != nil {
+		resp.Operator = &dto.AuthorInfo{
+			ID:       log.Operator.ID,
+			Username: log.Operator.Username,
+			Nickname: log.Operator.Nickname,
+			Avatar:   log.Operator.Avatar,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,102 @@
+package biz
+
+import (
+	"errors"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// SetAuthors 按给定顺序替换文章的共同作者，主作者不能同时作为共同作者
+func (uc *articleUseCase) SetAuthors(articleID uint, req *dto.SetArticleAuthorsRequest) (*dto.ArticleResponse, error) {
+	article, err := uc.editableArticle(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool, len(req.Authors))
+	authors := make([]po.ArticleAuthor, 0, len(req.Authors))
+	for _, item := range req.Authors {
+		if seen[item.UserID] {
+			continue
+		}
+		seen[item.UserID] = true
+		if err := uc.checkCoAuthor(article, item.UserID); err != nil {
+			return nil, err
+		}
+		authors = append(authors, po.ArticleAuthor{UserID: item.UserID, Role: coAuthorRole(item.Role)})
+	}
+
+	if err := uc.data.ArticleAuthorRepo.Set(articleID, authors); err != nil {
+		return nil, errors.New("设置共同作者失败")
+	}
+	return uc.GetByID(articleID)
+}
+
+// AddAuthor 添加共同作者，已存在时更新角色
+func (uc *articleUseCase) AddAuthor(articleID uint, req *dto.ArticleAuthorItem) (*dto.ArticleResponse, error) {
+	article, err := uc.editableArticle(articleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkCoAuthor(article, req.UserID); err != nil {
+		return nil, err
+	}
+
+	author := &po.ArticleAuthor{ArticleID: articleID, UserID: req.UserID, Role: coAuthorRole(req.Role)}
+	if err := uc.data.ArticleAuthorRepo.Add(author); err != nil {
+		return nil, errors.New("添加共同作者失败")
+	}
+	return uc.GetByID(articleID)
+}
+
+// RemoveAuthor 移除共同作者
+func (uc *articleUseCase) RemoveAuthor(articleID, userID uint) error {
+	if _, err := uc.editableArticle(articleID); err != nil {
+		return err
+	}
+	if err := uc.data.ArticleAuthorRepo.Remove(articleID, userID); err != nil {
+		return errors.New("移除共同作者失败")
+	}
+	return nil
+}
+
+// checkCoAuthor 校验共同作者存在且不是文章主作者
+func (uc *articleUseCase) checkCoAuthor(article *po.Article, userID uint) error {
+	if userID == article.AuthorID {
+		return errors.New("主作者无需添加为共同作者")
+	}
+	if _, err := uc.data.UserRepo.FindByID(userID); err != nil {
+		return errors.New("用户不存在")
+	}
+	return nil
+}
+
+// coAuthorRole 共同作者角色，未指定时为 author
+func coAuthorRole(role string) string {
+	if role == "" {
+		return po.ArticleAuthorRoleAuthor
+	}
+	return role
+}
+
+// convertToCoAuthors 转换共同作者列表，跳过已删除的用户
+func convertToCoAuthors(authors []po.ArticleAuthor) []dto.CoAuthorInfo {
+	if len(authors) == 0 {
+		return nil
+	}
+	result := make([]dto.CoAuthorInfo, 0, len(authors))
+	for _, author := range authors {
+		if author.User == nil {
+			continue
+		}
+		result = append(result, dto.CoAuthorInfo{
+			ID:       author.User.ID,
+			Username: author.User.Username,
+			Nickname: author.User.Nickname,
+			Avatar:   author.User.Avatar,
+			Role:     author.Role,
+		})
+	}
+	return result
+}
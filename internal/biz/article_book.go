@@ -0,0 +1,215 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	mdutils "github.com/ydcloud-dy/leaf-api/pkg/markdown"
+	"github.com/ydcloud-dy/leaf-api/pkg/sanitize"
+)
+
+// defaultPDFTimeout 生成 PDF 的默认超时
+const defaultPDFTimeout = 120 * time.Second
+
+// ExportPDF 导出 PDF，返回文件内容和建议的文件名（不含扩展名）
+func (uc *articleUseCase) ExportPDF(req *dto.ExportBookRequest) ([]byte, string, error) {
+	book, err := uc.buildBook(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	timeout := time.Duration(config.AppConfig.Export.PDFTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = defaultPDFTimeout
+	}
+	pdf, err := mdutils.NewPDFExporter(config.AppConfig.Export.PDFCommand, timeout).Export(book)
+	if err != nil {
+		return nil, "", err
+	}
+	return pdf, book.Title, nil
+}
+
+// ExportEPUB 导出 EPUB 3 电子书，返回文件内容和建议的文件名（不含扩展名）
+func (uc *articleUseCase) ExportEPUB(req *dto.ExportBookRequest) ([]byte, string, error) {
+	book, err := uc.buildBook(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	epub, err := mdutils.NewEPUBExporter().Export(book)
+	if err != nil {
+		return nil, "", err
+	}
+	return epub, book.Title, nil
+}
+
+// buildBook 组织导出内容
+// 指定 tag_id 时按章节树导出该笔记标签下已发布的文章，章节作为目录层级；否则按 article_ids 顺序导出
+func (uc *articleUseCase) buildBook(req *dto.ExportBookRequest) (*mdutils.Book, error) {
+	if req.TagID > 0 {
+		return uc.tagBook(req.TagID)
+	}
+	return uc.articlesBook(req.ArticleIDs)
+}
+
+// articlesBook 按给定顺序组织文章，单篇文章以文章标题和封面作为封面
+func (uc *articleUseCase) articlesBook(articleIDs []uint) (*mdutils.Book, error) {
+	if len(articleIDs) == 0 {
+		return nil, errors.New("请指定要导出的文章或笔记标签")
+	}
+	articles, err := uc.data.ArticleRepo.FindByIDs(articleIDs)
+	if err != nil {
+		return nil, errors.New("获取文章列表失败: " + err.Error())
+	}
+	articles = uc.exportable(articles)
+	if len(articles) == 0 {
+		return nil, errors.New("没有找到要导出的文章")
+	}
+
+	byID := make(map[uint]*po.Article, len(articles))
+	for _, article := range articles {
+		byID[article.ID] = article
+	}
+
+	book := &mdutils.Book{Title: "文章合集", Author: bookAuthors(articles)}
+	for _, id := range articleIDs {
+		article, ok := byID[id]
+		if !ok {
+			continue
+		}
+		delete(byID, id) // 重复的 ID 只导出一次
+		book.Entries = append(book.Entries, bookArticleEntry(article, 1))
+	}
+
+	if len(articles) == 1 {
+		article := articles[0]
+		book.Title = article.Title
+		book.Cover = article.Cover
+		book.Subtitle = fmt.Sprintf("%s · %s", book.Author, article.CreatedAt.Format("2006-01-02"))
+	} else {
+		book.Subtitle = fmt.Sprintf("共 %d 篇 · 导出于 %s", len(book.Entries), time.Now().Format("2006-01-02"))
+	}
+	return book, nil
+}
+
+// tagBook 按笔记标签的章节树组织文章：章节按层级作为目录项，章节内文章按创建时间排列
+func (uc *articleUseCase) tagBook(tagID uint) (*mdutils.Book, error) {
+	tag, err := uc.data.TagRepo.FindByID(tagID)
+	if err != nil {
+		return nil, errors.New("标签不存在")
+	}
+
+	var chapters []po.Chapter
+	if err := uc.data.GetDB().Where("tag_id = ?", tagID).Order("sort ASC, id ASC").Find(&chapters).Error; err != nil {
+		return nil, errors.New("获取章节失败: " + err.Error())
+	}
+	if len(chapters) == 0 {
+		return nil, errors.New("该标签下没有章节")
+	}
+
+	chapterIDs := make([]uint, 0, len(chapters))
+	known := make(map[uint]bool, len(chapters))
+	for _, chapter := range chapters {
+		chapterIDs = append(chapterIDs, chapter.ID)
+		known[chapter.ID] = true
+	}
+
+	var articles []*po.Article
+	if err := uc.data.GetDB().Preload("Author").Preload("Authors").
+		Where("chapter_id IN ? AND status = ?", chapterIDs, 1).
+		Order("created_at ASC, id ASC").
+		Find(&articles).Error; err != nil {
+		return nil, errors.New("获取文章列表失败: " + err.Error())
+	}
+	articles = uc.exportable(articles)
+	if len(articles) == 0 {
+		return nil, errors.New("该标签下没有已发布的文章")
+	}
+
+	children := make(map[uint][]po.Chapter) // 上级章节ID -> 子章节，0 表示顶级
+	for _, chapter := range chapters {
+		parentID := uint(0)
+		if chapter.ParentID != nil && known[*chapter.ParentID] {
+			parentID = *chapter.ParentID
+		}
+		children[parentID] = append(children[parentID], chapter)
+	}
+	byChapter := make(map[uint][]*po.Article)
+	for _, article := range articles {
+		byChapter[*article.ChapterID] = append(byChapter[*article.ChapterID], article)
+	}
+
+	book := &mdutils.Book{
+		Title:    tag.Name,
+		Author:   bookAuthors(articles),
+		Subtitle: fmt.Sprintf("共 %d 篇 · 导出于 %s", len(articles), time.Now().Format("2006-01-02")),
+	}
+	var walk func(parentID uint, level int)
+	walk = func(parentID uint, level int) {
+		for _, chapter := range children[parentID] {
+			book.Entries = append(book.Entries, mdutils.BookEntry{Title: chapter.Name, Level: level})
+			for _, article := range byChapter[chapter.ID] {
+				book.Entries = append(book.Entries, bookArticleEntry(article, level+1))
+			}
+			walk(chapter.ID, level+1)
+		}
+	}
+	walk(0, 1)
+	return book, nil
+}
+
+// exportable 过滤当前操作人可以导出的文章
+// 超级管理员可以导出全部文章，其他管理员只能导出自己（含共同作者）的文章和已公开发布的文章
+func (uc *articleUseCase) exportable(articles []*po.Article) []*po.Article {
+	if !uc.scoped {
+		return articles
+	}
+	result := make([]*po.Article, 0, len(articles))
+	for _, article := range articles {
+		if article.AuthorID == uc.ownerID ||
+			(article.Status == 1 && article.Visibility == po.VisibilityPublic) {
+			result = append(result, article)
+			continue
+		}
+		for _, author := range article.Authors {
+			if author.UserID == uc.ownerID {
+				result = append(result, article)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// bookArticleEntry 文章正文使用保存时渲染的 HTML，旧数据为空时重新渲染
+// 启用清理之前保存的文章可能包含未清理的 HTML，渲染 PDF/EPUB 前再清理一次
+func bookArticleEntry(article *po.Article, level int) mdutils.BookEntry {
+	content := article.ContentHTML
+	if content == "" {
+		content = markdownToHTML(article.ContentMarkdown)
+	}
+	return mdutils.BookEntry{Title: article.Title, Level: level, HTML: sanitize.Article(content)}
+}
+
+// bookAuthors 按首次出现的顺序列出文章作者
+func bookAuthors(articles []*po.Article) string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, article := range articles {
+		name := article.Author.Nickname
+		if name == "" {
+			name = article.Author.Username
+		}
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return strings.Join(names, "、")
+}
@@ -0,0 +1,90 @@
+package biz
+
+import (
+	"errors"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	mdutils "github.com/ydcloud-dy/leaf-api/pkg/markdown"
+)
+
+// cloneTitleSuffix 克隆文章的标题后缀
+const cloneTitleSuffix = " copy"
+
+// Clone 复制文章为新的草稿
+// 正文和封面中的图片重新上传一份，分类、章节、标签和可见性与原文一致
+func (uc *articleUseCase) Clone(id, authorID uint) (*dto.ArticleResponse, error) {
+	if _, err := uc.editableArticle(id); err != nil {
+		return nil, err
+	}
+	source, err := uc.data.ArticleRepo.FindByIDWithRelations(id)
+	if err != nil {
+		return nil, errors.New("文章不存在")
+	}
+
+	// 重新托管图片，失败的图片保留原地址
+	processor := mdutils.NewImageProcessor("uploads", "").CopyHosted()
+	content, err := processor.ProcessMarkdownImages(source.ContentMarkdown)
+	if err != nil {
+		logger.Warn("Failed to rehost cloned article images: ", err)
+	}
+	cover := source.Cover
+	if cover != "" {
+		if rehosted, err := processor.RehostImage(cover); err == nil {
+			cover = rehosted
+		}
+	}
+
+	title := cloneTitle(source.Title)
+	slugValue, err := uc.resolveSlug("", title, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	article := &po.Article{
+		Title:           title,
+		Slug:            &slugValue,
+		ContentMarkdown: content,
+		ContentHTML:     markdownToHTML(content),
+		Summary:         source.Summary,
+		Cover:           cover,
+		AuthorID:        authorID,
+		CategoryID:      source.CategoryID,
+		ChapterID:       source.ChapterID,
+		Status:          0, // 克隆的文章始终为草稿
+		Visibility:      source.Visibility,
+		AccessPassword:  source.AccessPassword,
+	}
+	applyReadingStats(article)
+
+	if err := uc.data.ArticleRepo.Create(article); err != nil {
+		return nil, errors.New("复制文章失败: " + err.Error())
+	}
+
+	tagIDs := make([]uint, 0, len(source.Tags))
+	for _, tag := range source.Tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	if len(tagIDs) > 0 {
+		if err := uc.data.ArticleRepo.AssociateTags(article.ID, tagIDs); err != nil {
+			return nil, errors.New("关联标签失败: " + err.Error())
+		}
+	}
+
+	changes := append(diffArticle(&po.Article{}, article), diffTags(nil, tagIDs)...)
+	uc.recordAudit(newAuditLog(authorID, po.AuditActionCreate, article, changes))
+
+	return uc.GetByID(article.ID)
+}
+
+// cloneTitle 生成克隆文章标题，超长时截断原标题以保留后缀
+func cloneTitle(title string) string {
+	const maxTitleLen = 200
+	runes := []rune(title)
+	limit := maxTitleLen - len([]rune(cloneTitleSuffix))
+	if len(runes) > limit {
+		runes = runes[:limit]
+	}
+	return string(runes) + cloneTitleSuffix
+}
@@ -0,0 +1,113 @@
+package biz
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+// 文章计数缓冲：增量先累加到 Redis 哈希 article:counters:{文章ID}，
+// 有增量的文章 ID 记录在集合 article:counters:dirty 中，由后台任务定期合并写入数据库
+const (
+	articleCounterPrefix   = "article:counters:"
+	articleCounterDirtyKey = "article:counters:dirty"
+)
+
+// 文章计数字段（与 articles 表列名一致）
+const (
+	counterViews     = "view_count"
+	counterLikes     = "like_count"
+	counterFavorites = "favorite_count"
+)
+
+// counterBuffered 是否启用计数缓冲（需要 Redis 且配置了刷新间隔）
+func counterBuffered() bool {
+	return redis.Client != nil && config.AppConfig.Counters.FlushInterval > 0
+}
+
+// incrArticleCounter 累加文章计数，启用缓冲时写入 Redis，否则直接更新数据库
+// Redis 写入失败时退化为直接更新数据库，保证计数不丢失
+func incrArticleCounter(d *data.Data, articleID uint, field string, delta int64) error {
+	if counterBuffered() {
+		err := bufferArticleCounters(articleID, map[string]int64{field: delta})
+		if err == nil {
+			return nil
+		}
+		logger.Warn("Failed to buffer article counter, writing through: ", err)
+	}
+	return d.ArticleRepo.AddCounters(articleID, map[string]int64{field: delta})
+}
+
+// bufferArticleCounters 将增量写入 Redis 并标记文章待刷新
+func bufferArticleCounters(articleID uint, deltas map[string]int64) error {
+	ctx := redis.GetContext()
+	pipe := redis.GetClient().TxPipeline()
+	key := fmt.Sprintf("%s%d", articleCounterPrefix, articleID)
+	for field, delta := range deltas {
+		pipe.HIncrBy(ctx, key, field, delta)
+	}
+	pipe.SAdd(ctx, articleCounterDirtyKey, articleID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// FlushCounters 将 Redis 中累积的计数合并写入数据库，返回写入的文章数
+// 每篇文章的哈希通过 MULTI 原子地读取并删除，刷新期间新产生的增量会重新进入待刷新集合
+func (uc *articleUseCase) FlushCounters() (int, error) {
+	if redis.Client == nil {
+		return 0, nil
+	}
+
+	ctx := redis.GetContext()
+	client := redis.GetClient()
+	members, err := client.SMembers(ctx, articleCounterDirtyKey).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	flushed := 0
+	for _, member := range members {
+		id, err := strconv.ParseUint(member, 10, 64)
+		if err != nil {
+			client.SRem(ctx, articleCounterDirtyKey, member)
+			continue
+		}
+		if err := client.SRem(ctx, articleCounterDirtyKey, member).Err(); err != nil {
+			return flushed, err
+		}
+
+		key := articleCounterPrefix + member
+		pipe := client.TxPipeline()
+		values := pipe.HGetAll(ctx, key)
+		pipe.Del(ctx, key)
+		if _, err := pipe.Exec(ctx); err != nil {
+			client.SAdd(ctx, articleCounterDirtyKey, member)
+			return flushed, err
+		}
+
+		deltas := make(map[string]int64)
+		for field, value := range values.Val() {
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil && n != 0 {
+				deltas[field] = n
+			}
+		}
+		if len(deltas) == 0 {
+			continue
+		}
+
+		if err := uc.data.ArticleRepo.AddCounters(uint(id), deltas); err != nil {
+			// 写库失败时把增量放回 Redis，下次重试
+			if rerr := bufferArticleCounters(uint(id), deltas); rerr != nil {
+				logger.Error(fmt.Sprintf("Lost counter deltas for article %d: %v", id, deltas))
+			}
+			return flushed, err
+		}
+		flushed++
+	}
+
+	return flushed, nil
+}
@@ -0,0 +1,103 @@
+package biz
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// ErrInvalidCursor 游标格式错误或已被篡改
+var ErrInvalidCursor = errors.New("无效的分页游标")
+
+// ListByCursor 游标分页查询文章列表
+// 游标模式固定按创建时间和 ID 倒序，不统计总数，忽略 page 和 sort 参数
+// 与普通分页的默认排序一致，未搜索关键词时置顶文章排在最前：第一页先返回全部置顶文章（不计入 limit），其余文章按游标分页
+func (uc *articleUseCase) ListByCursor(req *dto.ArticleListRequest) (*dto.CursorPageResponse, error) {
+	cursor, err := decodeArticleCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := parseListFields(req.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryID, tagID, chapterID := uc.listFilters(req)
+
+	// 只读取列表需要的列，不读取正文
+	repo := uc.data.ArticleRepo.WithFields(fields.columns, fields.preloads)
+	// 博客前台只返回已发布且非私密的文章，翻译组按请求语言只返回一个版本
+	if req.Public {
+		req.Status = "1"
+		repo = repo.PublicOnly().WithLanguage(normalizeLanguage(req.Lang))
+	}
+
+	pinFirst := req.Keyword == ""
+	var pinned []*po.Article
+	if pinFirst && cursor == nil {
+		pinned, err = repo.ListPinned(categoryID, tagID, chapterID, req.AuthorID, req.Status)
+		if err != nil {
+			return nil, errors.New("查询文章列表失败")
+		}
+	}
+
+	// 多取一条用于判断是否还有下一页
+	articles, err := repo.ListAfter(
+		req.Limit+1,
+		categoryID, tagID, chapterID, req.AuthorID,
+		req.Status, req.Keyword, pinFirst, cursor,
+	)
+	if err != nil {
+		return nil, errors.New("查询文章列表失败")
+	}
+
+	resp := &dto.CursorPageResponse{Limit: req.Limit}
+	if len(articles) > req.Limit {
+		articles = articles[:req.Limit]
+		resp.NextCursor = encodeArticleCursor(articles[len(articles)-1])
+	}
+	items := uc.convertToListItems(append(pinned, articles...), req.Public)
+	uc.fillListLanguages(items)
+	resp.Data = fields.pick(items)
+
+	return resp, nil
+}
+
+// encodeArticleCursor 将文章的创建时间（纳秒）和 ID 编码为不透明的游标
+func encodeArticleCursor(article *po.Article) string {
+	raw := fmt.Sprintf("%d_%d", article.CreatedAt.UnixNano(), article.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeArticleCursor 解析游标，空游标表示从第一条开始
+func decodeArticleCursor(cursor string) (*data.ArticleCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), "_", 2)
+	if len(parts) != 2 {
+		return nil, ErrInvalidCursor
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &data.ArticleCursor{CreatedAt: time.Unix(0, nanos), ID: uint(id)}, nil
+}
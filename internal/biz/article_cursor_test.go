@@ -0,0 +1,107 @@
+package biz
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// stubCursorArticleRepo 内存中的文章仓储，articles 按创建时间倒序排列
+type stubCursorArticleRepo struct {
+	data.ArticleRepo
+	articles []*po.Article
+}
+
+func (r *stubCursorArticleRepo) WithFields(columns, preloads []string) data.ArticleRepo {
+	return r
+}
+
+func (r *stubCursorArticleRepo) ListAfter(limit int, categoryID, tagID, chapterID, authorID uint, status, keyword string, skipPinned bool, cursor *data.ArticleCursor) ([]*po.Article, error) {
+	var result []*po.Article
+	for _, article := range r.articles {
+		if skipPinned && article.IsTop {
+			continue
+		}
+		if cursor != nil && !article.CreatedAt.Before(cursor.CreatedAt) &&
+			!(article.CreatedAt.Equal(cursor.CreatedAt) && article.ID < cursor.ID) {
+			continue
+		}
+		if len(result) == limit {
+			break
+		}
+		result = append(result, article)
+	}
+	return result, nil
+}
+
+func (r *stubCursorArticleRepo) ListPinned(categoryID, tagID, chapterID, authorID uint, status string) ([]*po.Article, error) {
+	var result []*po.Article
+	for _, article := range r.articles {
+		if article.IsTop {
+			result = append(result, article)
+		}
+	}
+	return result, nil
+}
+
+func cursorPageIDs(t *testing.T, resp *dto.CursorPageResponse) []uint {
+	t.Helper()
+	items, ok := resp.Data.([]dto.ArticleListItem)
+	if !ok {
+		t.Fatalf("data = %T", resp.Data)
+	}
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+func TestListByCursorPinnedFirst(t *testing.T) {
+	now := time.Now()
+	// 文章 3 置顶，创建时间介于其他文章之间
+	var articles []*po.Article
+	for id := uint(5); id >= 1; id-- {
+		articles = append(articles, &po.Article{ID: id, IsTop: id == 3, CreatedAt: now.Add(time.Duration(id) * time.Minute)})
+	}
+	uc := &articleUseCase{data: &data.Data{
+		ArticleRepo:            &stubCursorArticleRepo{articles: articles},
+		ArticleTranslationRepo: &stubTranslationRepo{},
+	}}
+
+	first, err := uc.ListByCursor(&dto.ArticleListRequest{PageRequest: dto.PageRequest{Limit: 2}})
+	if err != nil {
+		t.Fatalf("ListByCursor: %v", err)
+	}
+	// 置顶文章不计入 limit
+	if ids := cursorPageIDs(t, first); len(ids) != 3 || ids[0] != 3 || ids[1] != 5 || ids[2] != 4 {
+		t.Fatalf("first page = %v, want [3 5 4]", ids)
+	}
+	if first.NextCursor == "" {
+		t.Fatal("first page has no next cursor")
+	}
+
+	second, err := uc.ListByCursor(&dto.ArticleListRequest{PageRequest: dto.PageRequest{Limit: 2}, Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatalf("ListByCursor: %v", err)
+	}
+	// 后续页不再重复返回置顶文章
+	if ids := cursorPageIDs(t, second); len(ids) != 2 || ids[0] != 2 || ids[1] != 1 {
+		t.Fatalf("second page = %v, want [2 1]", ids)
+	}
+	if second.NextCursor != "" {
+		t.Fatalf("next cursor = %q, want empty", second.NextCursor)
+	}
+
+	// 搜索时不置顶，按创建时间排序
+	search, err := uc.ListByCursor(&dto.ArticleListRequest{PageRequest: dto.PageRequest{Limit: 5}, Keyword: "go"})
+	if err != nil {
+		t.Fatalf("ListByCursor: %v", err)
+	}
+	if ids := cursorPageIDs(t, search); len(ids) != 5 || ids[0] != 5 || ids[2] != 3 {
+		t.Fatalf("search page = %v, want [5 4 3 2 1]", ids)
+	}
+}
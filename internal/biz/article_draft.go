@@ -0,0 +1,75 @@
+package biz
+
+import (
+	"errors"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// Autosave 自动保存编辑器中的草稿（不影响文章正式内容）
+func (uc *articleUseCase) Autosave(articleID, userID uint, req *dto.AutosaveRequest) (*dto.AutosaveResponse, error) {
+	if _, err := uc.editableArticle(articleID); err != nil {
+		return nil, err
+	}
+
+	draft := &po.ArticleDraft{
+		ArticleID:       articleID,
+		UserID:          userID,
+		Title:           req.Title,
+		Summary:         req.Summary,
+		ContentMarkdown: req.ContentMarkdown,
+	}
+	if err := uc.data.ArticleDraftRepo.Save(draft); err != nil {
+		return nil, errors.New("保存草稿失败")
+	}
+
+	return &dto.AutosaveResponse{SavedAt: draft.UpdatedAt}, nil
+}
+
+// GetDraft 获取未保存的草稿，用于编辑器崩溃或关闭后恢复
+func (uc *articleUseCase) GetDraft(articleID, userID uint) (*dto.ArticleDraftResponse, error) {
+	article, err := uc.editableArticle(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	draft, err := uc.data.ArticleDraftRepo.Find(articleID, userID)
+	if err != nil {
+		return nil, errors.New("没有未保存的草稿")
+	}
+
+	return &dto.ArticleDraftResponse{
+		ArticleID:        draft.ArticleID,
+		Title:            draft.Title,
+		Summary:          draft.Summary,
+		ContentMarkdown:  draft.ContentMarkdown,
+		SavedAt:          draft.UpdatedAt,
+		ArticleUpdatedAt: article.UpdatedAt,
+		Newer:            draft.UpdatedAt.After(article.UpdatedAt),
+	}, nil
+}
+
+// DiscardDraft 丢弃草稿
+func (uc *articleUseCase) DiscardDraft(articleID, userID uint) error {
+	if _, err := uc.editableArticle(articleID); err != nil {
+		return err
+	}
+
+	if err := uc.data.ArticleDraftRepo.Delete(articleID, userID); err != nil {
+		return errors.New("删除草稿失败")
+	}
+	return nil
+}
+
+// editableArticle 查询当前操作人可编辑的文章
+func (uc *articleUseCase) editableArticle(articleID uint) (*po.Article, error) {
+	article, err := uc.data.ArticleRepo.FindByID(articleID)
+	if err != nil {
+		return nil, errors.New("文章不存在")
+	}
+	if uc.scoped && article.AuthorID != uc.ownerID {
+		return nil, errors.New("无权操作该文章")
+	}
+	return article, nil
+}
@@ -0,0 +1,274 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+// 异步导出：任务以 JSON 存在 export:job:{任务ID}，任务ID 推入 export:queue 列表，
+// 后台任务逐个取出并把 ZIP 写入导出目录，每写完一篇文章更新一次进度
+const (
+	exportJobPrefix     = "export:job:"
+	exportQueueKey      = "export:queue"
+	exportPollTimeout   = 5 * time.Second
+	defaultExportDir    = "./exports"
+	defaultExportJobTTL = 24 * time.Hour
+)
+
+// 导出任务状态
+const (
+	ExportJobPending = "pending"
+	ExportJobRunning = "running"
+	ExportJobDone    = "done"
+	ExportJobFailed  = "failed"
+)
+
+var (
+	// ErrExportQueueUnavailable 未启用 Redis，无法使用异步导出
+	ErrExportQueueUnavailable = errors.New("未启用 Redis，无法创建异步导出任务")
+	// ErrExportJobNotFound 导出任务不存在或已过期
+	ErrExportJobNotFound = errors.New("导出任务不存在或已过期")
+	// ErrExportJobNotReady 导出任务尚未完成
+	ErrExportJobNotReady = errors.New("导出任务尚未完成")
+)
+
+// exportJob 存储在 Redis 中的导出任务
+type exportJob struct {
+	ID         string     `json:"id"`
+	Status     string     `json:"status"`
+	AdminID    uint       `json:"admin_id"`
+	ArticleIDs []uint     `json:"article_ids"`
+	Profile    string     `json:"profile"`
+	Total      int        `json:"total"`
+	Done       int        `json:"done"`
+	Error      string     `json:"error"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// EnqueueExport 创建异步导出任务
+func (uc *articleUseCase) EnqueueExport(req *dto.ExportArticleRequest, adminID uint) (*dto.ExportJobResponse, error) {
+	if redis.Client == nil {
+		return nil, ErrExportQueueUnavailable
+	}
+
+	job := &exportJob{
+		ID:         uuid.NewString(),
+		Status:     ExportJobPending,
+		AdminID:    adminID,
+		ArticleIDs: req.ArticleIDs,
+		Profile:    req.Profile,
+		CreatedAt:  time.Now(),
+	}
+	if err := saveExportJob(job); err != nil {
+		return nil, errors.New("创建导出任务失败")
+	}
+	if err := redis.LPush(exportQueueKey, job.ID); err != nil {
+		return nil, errors.New("创建导出任务失败")
+	}
+	return convertToExportJobResponse(job), nil
+}
+
+// GetExportJob 查询导出任务，非超级管理员只能查看自己创建的任务
+func (uc *articleUseCase) GetExportJob(id string) (*dto.ExportJobResponse, error) {
+	job, err := uc.findExportJob(id)
+	if err != nil {
+		return nil, err
+	}
+	return convertToExportJobResponse(job), nil
+}
+
+// ExportJobFile 获取已完成导出任务的文件路径
+func (uc *articleUseCase) ExportJobFile(id string) (string, error) {
+	job, err := uc.findExportJob(id)
+	if err != nil {
+		return "", err
+	}
+	if job.Status != ExportJobDone {
+		return "", ErrExportJobNotReady
+	}
+
+	path := exportJobPath(job.ID)
+	if _, err := os.Stat(path); err != nil {
+		return "", ErrExportJobNotFound
+	}
+	return path, nil
+}
+
+// ProcessExportJob 从队列取出一个导出任务并执行
+// 导出失败记录在任务中，只有读写队列或任务出错时返回错误
+func (uc *articleUseCase) ProcessExportJob(ctx context.Context) error {
+	id, err := redis.BRPop(ctx, exportPollTimeout, exportQueueKey)
+	if redis.IsNil(err) || ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	job, err := loadExportJob(id)
+	if errors.Is(err, ErrExportJobNotFound) {
+		// 任务在排队期间过期
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	job.Status = ExportJobRunning
+	if err := saveExportJob(job); err != nil {
+		return err
+	}
+
+	exportErr := uc.writeExportFile(job)
+	now := time.Now()
+	job.FinishedAt = &now
+	if exportErr != nil {
+		job.Status = ExportJobFailed
+		job.Error = exportErr.Error()
+	} else {
+		job.Status = ExportJobDone
+	}
+	return saveExportJob(job)
+}
+
+// CleanupExportFiles 删除超过保留时间的导出文件
+func (uc *articleUseCase) CleanupExportFiles() (int, error) {
+	entries, err := os.ReadDir(exportDir())
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-exportJobTTL())
+	removed := 0
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(exportDir(), entry.Name())); err == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// writeExportFile 生成 ZIP 到临时文件，完成后再改名，避免下载到不完整的文件
+func (uc *articleUseCase) writeExportFile(job *exportJob) error {
+	if err := os.MkdirAll(exportDir(), 0755); err != nil {
+		return fmt.Errorf("创建导出目录失败: %w", err)
+	}
+
+	path := exportJobPath(job.ID)
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("创建导出文件失败: %w", err)
+	}
+
+	err = uc.exportArticles(file, job.ArticleIDs, job.Profile, func(done, total int) {
+		job.Done, job.Total = done, total
+		// 进度写入失败不影响导出
+		_ = saveExportJob(job)
+	})
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// findExportJob 读取任务并校验归属
+func (uc *articleUseCase) findExportJob(id string) (*exportJob, error) {
+	if redis.Client == nil {
+		return nil, ErrExportQueueUnavailable
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrExportJobNotFound
+	}
+
+	job, err := loadExportJob(id)
+	if err != nil {
+		return nil, err
+	}
+	if uc.scoped && job.AdminID != uc.ownerID {
+		return nil, ErrExportJobNotFound
+	}
+	return job, nil
+}
+
+// loadExportJob 从 Redis 读取任务
+func loadExportJob(id string) (*exportJob, error) {
+	value, err := redis.Get(exportJobPrefix + id)
+	if redis.IsNil(err) {
+		return nil, ErrExportJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var job exportJob
+	if err := json.Unmarshal([]byte(value), &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// saveExportJob 写入任务，每次写入都重置保留时间
+func saveExportJob(job *exportJob) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return redis.SetWithExpire(exportJobPrefix+job.ID, string(value), exportJobTTL())
+}
+
+// exportDir 导出文件目录
+func exportDir() string {
+	if dir := config.AppConfig.Export.Dir; dir != "" {
+		return dir
+	}
+	return defaultExportDir
+}
+
+// exportJobPath 任务的 ZIP 文件路径
+func exportJobPath(id string) string {
+	return filepath.Join(exportDir(), id+".zip")
+}
+
+// exportJobTTL 任务及文件的保留时间
+func exportJobTTL() time.Duration {
+	if hours := config.AppConfig.Export.JobTTL; hours > 0 {
+		return time.Duration(hours) * time.Hour
+	}
+	return defaultExportJobTTL
+}
+
+// convertToExportJobResponse 转换为响应，下载地址由接口层补充
+func convertToExportJobResponse(job *exportJob) *dto.ExportJobResponse {
+	return &dto.ExportJobResponse{
+		ID:         job.ID,
+		Status:     job.Status,
+		Total:      job.Total,
+		Done:       job.Done,
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		FinishedAt: job.FinishedAt,
+	}
+}
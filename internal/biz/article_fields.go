@@ -0,0 +1,119 @@
+package biz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+)
+
+// ErrInvalidFields 列表字段选择参数包含不支持的字段
+var ErrInvalidFields = errors.New("不支持的字段")
+
+// listFieldColumns 列表可选字段（ArticleListItem 的 JSON 字段名）对应需要读取的列
+var listFieldColumns = map[string][]string{
+	"id":             {"id"},
+	"title":          {"title"},
+	"slug":           {"slug"},
+	"summary":        {"summary", "visibility"}, // 加密文章在前台隐藏摘要，需要可见性
+	"cover":          {"cover"},
+	"status":         {"status"},
+	"is_top":         {"is_top"},
+	"pinned_sort":    {"pinned_sort"},
+	"visibility":     {"visibility"},
+	"word_count":     {"word_count"},
+	"reading_time":   {"reading_time"},
+	"view_count":     {"view_count"},
+	"like_count":     {"like_count"},
+	"favorite_count": {"favorite_count"},
+	"comment_count":  {"comment_count"},
+	"created_at":     {"created_at"},
+	"language":       {},
+	"author":         {"author_id"},
+	"authors":        {},
+	"category":       {"category_id"},
+	"tags":           {},
+}
+
+// listFieldPreloads 列表可选字段对应需要预加载的关联
+var listFieldPreloads = map[string]string{
+	"author":   "Author",
+	"authors":  "Authors",
+	"category": "Category",
+	"tags":     "Tags",
+}
+
+// listFields 列表字段选择
+type listFields struct {
+	// names 为空表示返回全部字段
+	names    []string
+	columns  []string
+	preloads []string
+}
+
+// parseListFields 解析逗号分隔的字段列表，为空时返回全部列表字段（仍不读取正文）
+// id 和 created_at 始终读取，用于关联预加载和游标分页
+func parseListFields(fields string) (*listFields, error) {
+	result := &listFields{}
+	seen := map[string]bool{}
+	addColumns := func(columns ...string) {
+		for _, column := range columns {
+			if !seen[column] {
+				seen[column] = true
+				result.columns = append(result.columns, column)
+			}
+		}
+	}
+	addColumns("id", "created_at")
+
+	var names []string
+	for _, name := range strings.Split(fields, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		for name := range listFieldColumns {
+			names = append(names, name)
+		}
+	} else {
+		result.names = names
+	}
+
+	for _, name := range names {
+		columns, ok := listFieldColumns[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFields, name)
+		}
+		addColumns(columns...)
+		if preload, ok := listFieldPreloads[name]; ok {
+			result.preloads = append(result.preloads, preload)
+		}
+	}
+	return result, nil
+}
+
+// pick 只保留请求的字段，未指定字段时原样返回
+func (f *listFields) pick(items []dto.ArticleListItem) interface{} {
+	if len(f.names) == 0 {
+		return items
+	}
+
+	result := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		payload, _ := json.Marshal(item)
+		var full map[string]interface{}
+		_ = json.Unmarshal(payload, &full)
+
+		picked := make(map[string]interface{}, len(f.names))
+		for _, name := range f.names {
+			if value, ok := full[name]; ok {
+				picked[name] = value
+			}
+		}
+		result = append(result, picked)
+	}
+	return result
+}
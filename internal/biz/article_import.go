@@ -0,0 +1,216 @@
+package biz
+
+import (
+	"errors"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	mdutils "github.com/ydcloud-dy/leaf-api/pkg/markdown"
+)
+
+// importSummaryLength 未提供摘要时从正文截取的长度
+const importSummaryLength = 200
+
+// ImportMarkdown 导入单个 Markdown 文件
+// 解析 YAML Front Matter 中的标题、分类、标签、时间和状态，不存在的分类和标签自动创建；
+// 没有 Front Matter 时以文件名作为标题，使用默认分类并保存为草稿
+func (uc *articleUseCase) ImportMarkdown(filename, content string, defaultCategoryID, authorID uint) (*dto.ArticleResponse, error) {
+	return uc.importDocument(filename, content, nil, defaultCategoryID, authorID)
+}
+
+// ImportArchive 导入 ZIP 导入包（ArticleExporter 导出的格式），返回每个 Markdown 文件的导入结果
+// 正文和封面中的相对图片路径从包内 images/ 目录读取并重新上传
+func (uc *articleUseCase) ImportArchive(archiveName string, data []byte, defaultCategoryID, authorID uint) (*dto.ImportReport, error) {
+	archive, err := mdutils.ReadArchive(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(archive.Documents) == 0 {
+		return nil, errors.New("ZIP中没有Markdown文件")
+	}
+
+	report := &dto.ImportReport{Items: []dto.ImportReportItem{}}
+	for _, doc := range archive.Documents {
+		title := strings.TrimSuffix(path.Base(doc.Name), path.Ext(doc.Name))
+		processor := mdutils.NewImageProcessor("uploads", "").WithAssets(archive.Assets, path.Dir(doc.Name))
+		article, err := uc.importDocument(title, doc.Content, processor, defaultCategoryID, authorID)
+		AddImportResult(report, archiveName+"/"+doc.Name, article, err)
+	}
+	return report, nil
+}
+
+// AddImportResult 记录单个文件的导入结果
+func AddImportResult(report *dto.ImportReport, file string, article *dto.ArticleResponse, err error) {
+	report.Total++
+	item := dto.ImportReportItem{File: file}
+	if err != nil {
+		report.Failed++
+		item.Error = err.Error()
+		report.FailedFiles = append(report.FailedFiles, file+": "+item.Error)
+	} else {
+		report.Success++
+		item.ArticleID = article.ID
+		item.Title = article.Title
+	}
+	report.Items = append(report.Items, item)
+}
+
+// importDocument 解析 Front Matter 并创建文章
+// processor 不为空时先用它重新托管正文和封面中的图片（用于解析导入包内的相对路径）
+func (uc *articleUseCase) importDocument(filename, content string, processor *mdutils.ImageProcessor, defaultCategoryID, authorID uint) (*dto.ArticleResponse, error) {
+	fm, body, err := mdutils.ParseFrontMatter(content)
+	if err != nil {
+		return nil, err
+	}
+	if fm == nil {
+		fm = &mdutils.FrontMatter{}
+	}
+	return uc.importArticle(filename, fm, body, processor, nil, defaultCategoryID, authorID)
+}
+
+// importArticle 按 Front Matter 创建文章，分类和标签不存在时自动创建
+// filename 为没有标题时使用的默认标题，chapterID 不为空时归入该章节
+func (uc *articleUseCase) importArticle(filename string, fm *mdutils.FrontMatter, body string, processor *mdutils.ImageProcessor, chapterID *uint, defaultCategoryID, authorID uint) (*dto.ArticleResponse, error) {
+	if processor != nil {
+		processed, err := processor.ProcessMarkdownImages(body)
+		if err != nil {
+			// 失败的图片保留原地址
+			logger.Warn("Failed to process imported article images: ", err)
+		}
+		body = processed
+		// 只处理包内相对路径的封面，外部链接保持原样
+		if fm.Cover != "" && !strings.Contains(fm.Cover, "://") && !strings.HasPrefix(fm.Cover, "/") {
+			if rehosted, err := processor.RehostImage(fm.Cover); err == nil {
+				fm.Cover = rehosted
+			}
+		}
+	}
+
+	req := &dto.CreateArticleRequest{
+		Title:           fm.Title,
+		Slug:            fm.Slug,
+		ContentMarkdown: body,
+		Summary:         fm.Summary,
+		Cover:           fm.Cover,
+		CategoryID:      defaultCategoryID,
+		ChapterID:       chapterID,
+		TagIDs:          []uint{},
+		CreatedAt:       fm.CreatedAt,
+	}
+	if req.Title == "" {
+		req.Title = filename
+	}
+	if req.Summary == "" {
+		req.Summary = generateSummary(body, importSummaryLength)
+	}
+	if fm.Status != nil {
+		req.Status = *fm.Status
+	}
+
+	if fm.Category != "" {
+		category, err := uc.findOrCreateCategory(fm.Category)
+		if err != nil {
+			return nil, err
+		}
+		req.CategoryID = category.ID
+	}
+
+	seen := make(map[uint]bool, len(fm.Tags))
+	for _, name := range fm.Tags {
+		tag, err := uc.findOrCreateTag(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag.ID] {
+			seen[tag.ID] = true
+			req.TagIDs = append(req.TagIDs, tag.ID)
+		}
+	}
+
+	article, err := uc.Create(req, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 保留原始更新时间
+	if fm.UpdatedAt != nil {
+		if err := uc.data.ArticleRepo.UpdateTimestamp(article.ID, *fm.UpdatedAt); err == nil {
+			article.UpdatedAt = *fm.UpdatedAt
+		}
+	}
+
+	return article, nil
+}
+
+// findOrCreateCategory 按名称查找分类，不存在时创建
+func (uc *articleUseCase) findOrCreateCategory(name string) (*po.Category, error) {
+	name = truncateRunes(strings.TrimSpace(name), 50)
+	if category, err := uc.data.CategoryRepo.FindByName(name); err == nil {
+		return category, nil
+	}
+
+	category := &po.Category{Name: name}
+	if err := uc.data.CategoryRepo.Create(category); err != nil {
+		// 并发导入时可能已被创建
+		if existing, findErr := uc.data.CategoryRepo.FindByName(name); findErr == nil {
+			return existing, nil
+		}
+		return nil, errors.New("创建分类失败: " + name)
+	}
+
+	// 刷新站点地图
+	InvalidateSitemap()
+
+	return category, nil
+}
+
+// findOrCreateTag 按名称查找标签，不存在时创建
+func (uc *articleUseCase) findOrCreateTag(name string) (*po.Tag, error) {
+	name = truncateRunes(strings.TrimSpace(name), 50)
+	if tag, err := uc.data.TagRepo.FindByName(name); err == nil {
+		return tag, nil
+	}
+
+	tag := &po.Tag{Name: name}
+	if err := uc.data.TagRepo.Create(tag); err != nil {
+		if existing, findErr := uc.data.TagRepo.FindByName(name); findErr == nil {
+			return existing, nil
+		}
+		return nil, errors.New("创建标签失败: " + name)
+	}
+
+	// 刷新站点地图
+	InvalidateSitemap()
+
+	return tag, nil
+}
+
+// generateSummary 从内容中生成摘要
+func generateSummary(content string, maxLen int) string {
+	// 移除 Markdown 标记
+	content = strings.ReplaceAll(content, "#", "")
+	content = strings.ReplaceAll(content, "*", "")
+	content = strings.ReplaceAll(content, "_", "")
+	content = strings.ReplaceAll(content, "`", "")
+	content = strings.ReplaceAll(content, "\n", " ")
+	content = strings.TrimSpace(content)
+
+	// 截取指定长度
+	runes := []rune(content)
+	if len(runes) > maxLen {
+		return string(runes[:maxLen]) + "..."
+	}
+	return content
+}
+
+// truncateRunes 按字符数截断字符串
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
@@ -0,0 +1,119 @@
+package biz
+
+import (
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	mdutils "github.com/ydcloud-dy/leaf-api/pkg/markdown"
+)
+
+// notionImport 一次 Notion 导入的上下文
+type notionImport struct {
+	uc                *articleUseCase
+	archive           *mdutils.Archive
+	archiveName       string
+	tag               *po.Tag
+	rootChapterID     *uint // 没有上级页面的单页文章归入的章节，按需创建
+	report            *dto.ImportReport
+	defaultCategoryID uint
+	authorID          uint
+}
+
+// ImportNotion 导入 Notion 导出的 ZIP（Markdown & CSV 格式）
+// 以根页面标题（多个根页面时为 ZIP 文件名）作为笔记标签，含子页面的页面和数据库展开为章节，
+// 每个页面创建一篇文章并归入所在章节；标注和折叠块转换为普通 Markdown，图片重新上传
+func (uc *articleUseCase) ImportNotion(archiveName string, data []byte, defaultCategoryID, authorID uint) (*dto.ImportReport, error) {
+	archive, err := mdutils.ReadArchive(data)
+	if err != nil {
+		return nil, err
+	}
+	roots := mdutils.NotionPages(archive)
+	if len(roots) == 0 {
+		return nil, errors.New("ZIP中没有Notion页面")
+	}
+
+	name := strings.TrimSuffix(archiveName, path.Ext(archiveName))
+	if len(roots) == 1 {
+		name = roots[0].Title
+	}
+	tag, err := uc.findOrCreateTag(name)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &notionImport{
+		uc:                uc,
+		archive:           archive,
+		archiveName:       archiveName,
+		tag:               tag,
+		report:            &dto.ImportReport{Items: []dto.ImportReportItem{}},
+		defaultCategoryID: defaultCategoryID,
+		authorID:          authorID,
+	}
+	for i, page := range roots {
+		imp.importPage(page, nil, i)
+	}
+
+	// 刷新站点地图（新增章节）
+	InvalidateSitemap()
+
+	return imp.report, nil
+}
+
+// importPage 导入页面及其子页面
+// 含子页面的页面创建为 parentID 下的章节，页面本身的文章归入该章节；否则归入上级章节
+func (imp *notionImport) importPage(page *mdutils.NotionPage, parentID *uint, sort int) {
+	file := imp.archiveName + "/" + page.Path
+	chapterID := parentID
+
+	if page.HasChildren() {
+		chapter := &po.Chapter{
+			TagID:    imp.tag.ID,
+			ParentID: parentID,
+			Name:     truncateRunes(page.Title, 200),
+			Sort:     sort,
+		}
+		if err := imp.uc.data.GetDB().Create(chapter).Error; err != nil {
+			AddImportResult(imp.report, file, nil, errors.New("创建章节失败: "+page.Title))
+			return
+		}
+		chapterID = &chapter.ID
+	}
+
+	if !page.Database {
+		if chapterID == nil {
+			id, err := imp.rootChapter()
+			if err != nil {
+				AddImportResult(imp.report, file, nil, err)
+				return
+			}
+			chapterID = id
+		}
+
+		fm := page.FrontMatter()
+		fm.Tags = append([]string{imp.tag.Name}, fm.Tags...)
+		processor := mdutils.NewImageProcessor("uploads", "").WithAssets(imp.archive.Assets, page.Dir())
+		article, err := imp.uc.importArticle(page.Title, fm, page.Content, processor, chapterID, imp.defaultCategoryID, imp.authorID)
+		AddImportResult(imp.report, file, article, err)
+	}
+
+	for i, child := range page.Children {
+		imp.importPage(child, chapterID, i)
+	}
+}
+
+// rootChapter 获取或创建根章节（以笔记标签命名）
+func (imp *notionImport) rootChapter() (*uint, error) {
+	if imp.rootChapterID != nil {
+		return imp.rootChapterID, nil
+	}
+	chapter := &po.Chapter{TagID: imp.tag.ID, Name: imp.tag.Name}
+	if err := imp.uc.data.GetDB().Create(chapter).Error; err != nil {
+		return nil, errors.New("创建章节失败: " + imp.tag.Name)
+	}
+	imp.rootChapterID = &chapter.ID
+	return imp.rootChapterID, nil
+}
@@ -0,0 +1,110 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+// 文章编辑锁：key 为 article:lock:{文章ID}，值为持有者的管理员 ID
+// 编辑器打开时加锁并定时续期，超过 articleLockTTL 未续期自动释放
+const (
+	articleLockPrefix = "article:lock:"
+	articleLockTTL    = 60 * time.Second
+)
+
+// AcquireEditLock 获取或续期文章编辑锁
+// 锁被其他管理员持有时不返回错误，而是返回 acquired=false 及持有者信息，由前端提示“正在被他人编辑”
+func (uc *articleUseCase) AcquireEditLock(articleID, adminID uint) (*dto.ArticleLockResponse, error) {
+	article, err := uc.editableArticle(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.ArticleLockResponse{Revision: article.Revision}
+	if redis.Client == nil {
+		// 未启用 Redis 时不加锁，仅依赖修订号校验防止覆盖
+		resp.Acquired = true
+		return resp, nil
+	}
+	resp.Enabled = true
+
+	key := fmt.Sprintf("%s%d", articleLockPrefix, articleID)
+	owner := strconv.FormatUint(uint64(adminID), 10)
+
+	// 锁可能在检查持有者时恰好过期，重试一次
+	for attempt := 0; attempt < 2; attempt++ {
+		ok, err := redis.SetNX(key, owner, articleLockTTL)
+		if err != nil {
+			return nil, errors.New("获取编辑锁失败")
+		}
+		if !ok {
+			// 已持有锁时续期（编辑器心跳）
+			if ok, err = redis.ExpireIfEqual(key, owner, articleLockTTL); err != nil {
+				return nil, errors.New("获取编辑锁失败")
+			}
+		}
+		if ok {
+			expiresAt := time.Now().Add(articleLockTTL)
+			resp.Acquired = true
+			resp.ExpiresAt = &expiresAt
+			resp.Holder = uc.lockHolder(adminID)
+			return resp, nil
+		}
+
+		value, err := redis.Get(key)
+		if redis.IsNil(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.New("获取编辑锁失败")
+		}
+		holderID, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, errors.New("获取编辑锁失败")
+		}
+
+		resp.Holder = uc.lockHolder(uint(holderID))
+		resp.Message = fmt.Sprintf("文章正在被 %s 编辑", resp.Holder.Name)
+		if ttl, err := redis.TTL(key); err == nil && ttl > 0 {
+			expiresAt := time.Now().Add(ttl)
+			resp.ExpiresAt = &expiresAt
+		}
+		return resp, nil
+	}
+
+	return nil, errors.New("获取编辑锁失败，请重试")
+}
+
+// ReleaseEditLock 释放文章编辑锁，锁不属于当前管理员时忽略
+func (uc *articleUseCase) ReleaseEditLock(articleID, adminID uint) error {
+	if redis.Client == nil {
+		return nil
+	}
+
+	key := fmt.Sprintf("%s%d", articleLockPrefix, articleID)
+	owner := strconv.FormatUint(uint64(adminID), 10)
+	if _, err := redis.DelIfEqual(key, owner); err != nil {
+		logger.Warn("Failed to release article edit lock: ", err)
+		return errors.New("释放编辑锁失败")
+	}
+	return nil
+}
+
+// lockHolder 查询锁持有者的显示名称，优先使用昵称
+func (uc *articleUseCase) lockHolder(adminID uint) *dto.LockHolder {
+	holder := &dto.LockHolder{ID: adminID, Name: fmt.Sprintf("管理员 #%d", adminID)}
+	if user, err := uc.data.UserRepo.FindByID(adminID); err == nil {
+		if user.Nickname != "" {
+			holder.Name = user.Nickname
+		} else {
+			holder.Name = user.Username
+		}
+	}
+	return holder
+}
@@ -0,0 +1,38 @@
+package biz
+
+import (
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	mdutils "github.com/ydcloud-dy/leaf-api/pkg/markdown"
+)
+
+// readingStatsBackfillBatch 补全字数统计时每批处理的文章数
+const readingStatsBackfillBatch = 200
+
+// applyReadingStats 根据 Markdown 正文计算字数和预计阅读时间
+func applyReadingStats(article *po.Article) {
+	article.WordCount, article.ReadingTime = mdutils.ReadingStats(article.ContentMarkdown)
+}
+
+// BackfillReadingStats 为历史文章补全字数和阅读时间，返回处理数量
+func (uc *articleUseCase) BackfillReadingStats() (int, error) {
+	filled := 0
+	var lastID uint
+	for {
+		articles, err := uc.data.ArticleRepo.ListWithoutReadingStats(lastID, readingStatsBackfillBatch)
+		if err != nil {
+			return filled, err
+		}
+		if len(articles) == 0 {
+			return filled, nil
+		}
+
+		for _, article := range articles {
+			lastID = article.ID
+			applyReadingStats(article)
+			if err := uc.data.ArticleRepo.UpdateReadingStats(article.ID, article.WordCount, article.ReadingTime); err != nil {
+				return filled, err
+			}
+			filled++
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package biz
+
+import (
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// applySEO 设置文章 SEO 信息，关键词统一为英文逗号分隔并去掉空项
+func applySEO(article *po.Article, seo *dto.SEOMeta) {
+	article.MetaDescription = strings.TrimSpace(seo.MetaDescription)
+	article.MetaKeywords = normalizeKeywords(seo.MetaKeywords)
+	article.CanonicalURL = strings.TrimSpace(seo.CanonicalURL)
+	article.NoIndex = seo.NoIndex
+}
+
+// seoMeta 获取文章 SEO 信息
+func seoMeta(article *po.Article) dto.SEOMeta {
+	return dto.SEOMeta{
+		MetaDescription: article.MetaDescription,
+		MetaKeywords:    article.MetaKeywords,
+		CanonicalURL:    article.CanonicalURL,
+		NoIndex:         article.NoIndex,
+	}
+}
+
+// normalizeKeywords 规范化关键词：中文逗号、顿号视为分隔符，去重并去掉空项
+func normalizeKeywords(keywords string) string {
+	fields := strings.FieldsFunc(keywords, func(r rune) bool {
+		return r == ',' || r == '，' || r == '、'
+	})
+	seen := make(map[string]bool, len(fields))
+	result := make([]string, 0, len(fields))
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+		seen[field] = true
+		result = append(result, field)
+	}
+	return strings.Join(result, ",")
+}
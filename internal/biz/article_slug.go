@@ -0,0 +1,87 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/slug"
+)
+
+// slugBackfillBatch 补全 slug 时每批处理的文章数
+const slugBackfillBatch = 200
+
+// resolveSlug 确定文章 slug
+// 指定了 slug 时规范化后校验唯一性；否则根据标题生成，冲突时追加数字后缀
+func (uc *articleUseCase) resolveSlug(input, title string, excludeID uint) (string, error) {
+	if input != "" {
+		s := slug.Make(input)
+		if s == "" {
+			return "", errors.New("slug 只能包含字母、数字、中文和连字符")
+		}
+		exists, err := uc.data.ArticleRepo.SlugExists(s, excludeID)
+		if err != nil {
+			return "", errors.New("校验 slug 失败")
+		}
+		if exists {
+			return "", errors.New("slug 已被其他文章使用")
+		}
+		return s, nil
+	}
+
+	base := slug.Make(title)
+	if base == "" {
+		base = "article"
+	}
+	return uc.uniqueSlug(base, excludeID)
+}
+
+// uniqueSlug 在 base 后追加 -2、-3... 直到不冲突
+func (uc *articleUseCase) uniqueSlug(base string, excludeID uint) (string, error) {
+	candidate := base
+	for i := 2; i <= 100; i++ {
+		exists, err := uc.data.ArticleRepo.SlugExists(candidate, excludeID)
+		if err != nil {
+			return "", errors.New("校验 slug 失败")
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+	return fmt.Sprintf("%s-%d", base, time.Now().UnixNano()), nil
+}
+
+// BackfillSlugs 为历史文章补全 slug，返回处理数量
+func (uc *articleUseCase) BackfillSlugs() (int, error) {
+	filled := 0
+	for {
+		articles, err := uc.data.ArticleRepo.ListWithoutSlug(slugBackfillBatch)
+		if err != nil {
+			return filled, err
+		}
+		if len(articles) == 0 {
+			return filled, nil
+		}
+
+		for _, article := range articles {
+			s, err := uc.resolveSlug("", article.Title, article.ID)
+			if err != nil {
+				return filled, err
+			}
+			if err := uc.data.ArticleRepo.UpdateSlug(article.ID, s); err != nil {
+				return filled, err
+			}
+			filled++
+		}
+	}
+}
+
+// articleSlug 获取文章 slug，未生成时返回空字符串
+func articleSlug(article *po.Article) string {
+	if article.Slug == nil {
+		return ""
+	}
+	return *article.Slug
+}
@@ -0,0 +1,60 @@
+package biz
+
+import (
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// BatchUpdateStatus 批量发布、取消发布或下线文章，返回每篇文章的处理结果
+// 所有更新在同一事务中完成，不存在或无权限的文章不会影响其他文章
+func (uc *articleUseCase) BatchUpdateStatus(req *dto.BatchUpdateStatusRequest) (*dto.BatchStatusResponse, error) {
+	status := *req.Status
+	articleIDs := uniqueIDs(req.ArticleIDs)
+
+	articles, err := uc.articleRepo().BatchUpdateStatus(articleIDs, status)
+	if err != nil {
+		return nil, ownershipError(err, "批量更新状态失败")
+	}
+
+	found := make(map[uint]*po.Article, len(articles))
+	for _, article := range articles {
+		found[article.ID] = article
+	}
+
+	resp := &dto.BatchStatusResponse{Results: make([]dto.BatchStatusResult, 0, len(articleIDs))}
+	logs := make([]*po.ArticleAuditLog, 0, len(articles))
+	changedIDs := make([]uint, 0, len(articles))
+	for _, id := range articleIDs {
+		article, ok := found[id]
+		if !ok {
+			resp.Results = append(resp.Results, dto.BatchStatusResult{
+				ArticleID: id,
+				Message:   "文章不存在或无权操作",
+			})
+			continue
+		}
+
+		result := dto.BatchStatusResult{ArticleID: id, Success: true, Changed: article.Status != status}
+		if result.Changed {
+			changes := []dto.AuditChange{{Field: "status", Old: article.Status, New: status}}
+			logs = append(logs, newAuditLog(uc.operatorID, po.AuditActionStatus, article, changes))
+			changedIDs = append(changedIDs, id)
+		} else {
+			result.Message = "状态未变化"
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	resp.Updated = len(changedIDs)
+
+	// 记录审计日志
+	uc.recordAudit(logs...)
+
+	// 同步搜索索引，刷新站点地图
+	syncSearchIndex(uc.data, changedIDs...)
+	InvalidateSitemap()
+
+	// 投递 Webhook 事件，changedIDs 中的文章原状态都不等于 status
+	notifyArticles(uc.context(), uc.data, articleStatusEvent(-1, status), changedIDs...)
+
+	return resp, nil
+}
@@ -0,0 +1,34 @@
+package biz
+
+import (
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	mdutils "github.com/ydcloud-dy/leaf-api/pkg/markdown"
+)
+
+// buildTOC 从 Markdown 提取标题并按级别组装为嵌套目录
+// 跳级的标题（如 h2 后直接出现 h4）挂在最近的上级标题下
+func buildTOC(content string) []*dto.TOCItem {
+	toc := make([]*dto.TOCItem, 0)
+	var stack []*dto.TOCItem
+
+	for _, heading := range mdutils.ExtractHeadings(content) {
+		item := &dto.TOCItem{
+			Level:  heading.Level,
+			Text:   heading.Text,
+			Anchor: heading.ID,
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].Level >= item.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			toc = append(toc, item)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, item)
+		}
+		stack = append(stack, item)
+	}
+
+	return toc
+}
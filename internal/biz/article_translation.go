@@ -0,0 +1,230 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/i18n"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrTranslationInvalid 翻译参数不合法
+	ErrTranslationInvalid = errors.New("翻译参数不合法")
+	// ErrTranslationConflict 翻译组中已存在该语言的版本，或译文已属于其他翻译组
+	ErrTranslationConflict = errors.New("翻译关联冲突")
+)
+
+// languagePattern 语言代码格式，如 zh、zh-CN、zh-Hant-TW、en_US
+var languagePattern = regexp.MustCompile(`^[A-Za-z]{2,3}([-_][A-Za-z0-9]{2,8})*$`)
+
+// normalizeLanguage 规范化语言代码：主语言小写、地区大写、文字首字母大写，如 zh_cn 规范为 zh-CN，不合法时返回空字符串
+func normalizeLanguage(tag string) string {
+	tag = strings.TrimSpace(tag)
+	if len(tag) > 20 || !languagePattern.MatchString(tag) {
+		return ""
+	}
+	parts := strings.Split(strings.ReplaceAll(tag, "_", "-"), "-")
+	for i, part := range parts {
+		switch {
+		case i == 0:
+			parts[i] = strings.ToLower(part)
+		case len(part) == 2:
+			parts[i] = strings.ToUpper(part)
+		case len(part) == 4:
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		default:
+			parts[i] = strings.ToLower(part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// ListTranslations 查询文章所在翻译组的全部语言版本，未加入翻译组时返回空列表
+func (uc *articleUseCase) ListTranslations(articleID uint) (*dto.ArticleTranslationsResponse, error) {
+	if _, err := uc.data.ArticleRepo.FindByID(articleID); err != nil {
+		return nil, errors.New("文章不存在")
+	}
+
+	translation, err := uc.data.ArticleTranslationRepo.FindByArticle(articleID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &dto.ArticleTranslationsResponse{Items: []dto.ArticleTranslationItem{}}, nil
+	}
+	if err != nil {
+		return nil, errors.New("查询翻译失败")
+	}
+
+	translations, err := uc.data.ArticleTranslationRepo.ListByGroup(translation.GroupID)
+	if err != nil {
+		return nil, errors.New("查询翻译失败")
+	}
+	return &dto.ArticleTranslationsResponse{
+		GroupID: translation.GroupID,
+		Items:   convertToTranslationItems(translations, false),
+	}, nil
+}
+
+// LinkTranslation 将另一篇文章关联为当前文章的译文
+// 当前文章尚未加入翻译组时以它为源文章创建翻译组，源文章语言取 source_language，未指定时为站点默认语言
+// 译文已在同一翻译组中时只更新语言
+func (uc *articleUseCase) LinkTranslation(articleID uint, req *dto.LinkTranslationRequest) (*dto.ArticleTranslationsResponse, error) {
+	if req.ArticleID == articleID {
+		return nil, fmt.Errorf("%w: 不能将文章关联为自身的译文", ErrTranslationInvalid)
+	}
+	language := normalizeLanguage(req.Language)
+	if language == "" {
+		return nil, fmt.Errorf("%w: 语言代码格式不正确", ErrTranslationInvalid)
+	}
+	sourceLanguage := ""
+	if req.SourceLanguage != "" {
+		if sourceLanguage = normalizeLanguage(req.SourceLanguage); sourceLanguage == "" {
+			return nil, fmt.Errorf("%w: 语言代码格式不正确", ErrTranslationInvalid)
+		}
+	}
+
+	if _, err := uc.editableArticle(articleID); err != nil {
+		return nil, err
+	}
+	if _, err := uc.editableArticle(req.ArticleID); err != nil {
+		return nil, err
+	}
+
+	// 当前文章所在的翻译组，没有时以当前文章为源文章
+	groupID := articleID
+	current, err := uc.data.ArticleTranslationRepo.FindByArticle(articleID)
+	if err == nil {
+		groupID = current.GroupID
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("关联译文失败")
+	}
+
+	existing, err := uc.data.ArticleTranslationRepo.FindByArticle(req.ArticleID)
+	if err == nil && existing.GroupID != groupID {
+		return nil, fmt.Errorf("%w: 译文已属于其他翻译组", ErrTranslationConflict)
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("关联译文失败")
+	}
+
+	members, err := uc.data.ArticleTranslationRepo.ListByGroup(groupID)
+	if err != nil {
+		return nil, errors.New("关联译文失败")
+	}
+
+	// 确定源文章语言：请求指定 > 已有记录 > 站点默认语言
+	languages := make(map[uint]string, len(members)+2)
+	for _, member := range members {
+		languages[member.ArticleID] = member.Language
+	}
+	switch {
+	case req.ArticleID == groupID:
+		// 关联的是源文章本身，只更新源文章语言
+	case sourceLanguage != "":
+		languages[groupID] = sourceLanguage
+	case languages[groupID] == "":
+		languages[groupID] = normalizeLanguage(i18n.DefaultLocale())
+	}
+	languages[req.ArticleID] = language
+
+	// 同一翻译组内每种语言只能有一篇文章
+	seen := make(map[string]uint, len(languages))
+	for id, lang := range languages {
+		if other, ok := seen[lang]; ok && other != id {
+			return nil, fmt.Errorf("%w: 翻译组中已存在 %s 版本", ErrTranslationConflict, lang)
+		}
+		seen[lang] = id
+	}
+
+	if err := uc.data.ArticleTranslationRepo.Save(
+		&po.ArticleTranslation{GroupID: groupID, ArticleID: groupID, Language: languages[groupID]},
+		&po.ArticleTranslation{GroupID: groupID, ArticleID: req.ArticleID, Language: language},
+	); err != nil {
+		return nil, errors.New("关联译文失败")
+	}
+	return uc.ListTranslations(articleID)
+}
+
+// UnlinkTranslation 将文章移出翻译组，移出源文章会解散整个翻译组
+func (uc *articleUseCase) UnlinkTranslation(articleID uint) error {
+	if _, err := uc.editableArticle(articleID); err != nil {
+		return err
+	}
+	if err := uc.data.ArticleTranslationRepo.Remove(articleID); err != nil {
+		return errors.New("取消译文关联失败")
+	}
+	return nil
+}
+
+// fillListLanguages 为列表项补充文章语言，未加入翻译组的文章语言为空
+func (uc *articleUseCase) fillListLanguages(items []dto.ArticleListItem) {
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	translations, err := uc.data.ArticleTranslationRepo.FindByArticles(ids)
+	if err != nil {
+		return
+	}
+	languages := make(map[uint]string, len(translations))
+	for _, translation := range translations {
+		languages[translation.ArticleID] = translation.Language
+	}
+	for i := range items {
+		items[i].Language = languages[items[i].ID]
+	}
+}
+
+// resolveTranslation 按请求语言选择翻译组中的文章版本
+// 翻译组中有该语言的已发布版本时返回该版本，否则返回原文章；同时返回前台可见的全部语言版本和原文章的语言
+func resolveTranslation(d *data.Data, article *po.Article, lang string) (*po.Article, string, []dto.ArticleTranslationItem) {
+	translation, err := d.ArticleTranslationRepo.FindByArticle(article.ID)
+	if err != nil {
+		return article, "", nil
+	}
+	members, err := d.ArticleTranslationRepo.ListByGroup(translation.GroupID)
+	if err != nil {
+		return article, translation.Language, nil
+	}
+
+	items := convertToTranslationItems(members, true)
+	lang = normalizeLanguage(lang)
+	if lang == "" || lang == translation.Language {
+		return article, translation.Language, items
+	}
+	for _, item := range items {
+		if item.Language != lang {
+			continue
+		}
+		if variant, err := d.ArticleRepo.FindByIDWithRelations(item.ArticleID); err == nil {
+			return variant, item.Language, items
+		}
+	}
+	return article, translation.Language, items
+}
+
+// convertToTranslationItems 转换翻译组成员，public 为 true 时只保留前台可见的已发布版本
+func convertToTranslationItems(translations []*po.ArticleTranslation, public bool) []dto.ArticleTranslationItem {
+	items := make([]dto.ArticleTranslationItem, 0, len(translations))
+	for _, translation := range translations {
+		article := translation.Article
+		if article == nil {
+			continue
+		}
+		if public && (article.Status != 1 || !articleVisible(article)) {
+			continue
+		}
+		items = append(items, dto.ArticleTranslationItem{
+			ArticleID: translation.ArticleID,
+			Language:  translation.Language,
+			Title:     article.Title,
+			Slug:      articleSlug(article),
+			Status:    article.Status,
+			Source:    translation.ArticleID == translation.GroupID,
+		})
+	}
+	return items
+}
@@ -0,0 +1,267 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// stubTranslationArticleRepo 内存中的文章仓储
+type stubTranslationArticleRepo struct {
+	data.ArticleRepo
+	articles map[uint]*po.Article
+}
+
+func (r *stubTranslationArticleRepo) FindByID(id uint) (*po.Article, error) {
+	article, ok := r.articles[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return article, nil
+}
+
+func (r *stubTranslationArticleRepo) FindByIDWithRelations(id uint) (*po.Article, error) {
+	return r.FindByID(id)
+}
+
+// stubTranslationRepo 内存中的翻译关联仓储，键为文章ID
+type stubTranslationRepo struct {
+	data.ArticleTranslationRepo
+	articles     map[uint]*po.Article
+	translations map[uint]*po.ArticleTranslation
+}
+
+func (r *stubTranslationRepo) FindByArticle(articleID uint) (*po.ArticleTranslation, error) {
+	translation, ok := r.translations[articleID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *translation
+	return &copied, nil
+}
+
+func (r *stubTranslationRepo) FindByArticles(articleIDs []uint) ([]*po.ArticleTranslation, error) {
+	var result []*po.ArticleTranslation
+	for _, id := range articleIDs {
+		if translation, ok := r.translations[id]; ok {
+			result = append(result, translation)
+		}
+	}
+	return result, nil
+}
+
+func (r *stubTranslationRepo) ListByGroup(groupID uint) ([]*po.ArticleTranslation, error) {
+	var result []*po.ArticleTranslation
+	for _, translation := range r.translations {
+		if translation.GroupID == groupID {
+			copied := *translation
+			copied.Article = r.articles[translation.ArticleID]
+			result = append(result, &copied)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if source := result[i].ArticleID == groupID; source != (result[j].ArticleID == groupID) {
+			return source
+		}
+		return result[i].Language < result[j].Language
+	})
+	return result, nil
+}
+
+// Save 整体写入后检查 (group_id, language) 唯一索引，违反时回滚
+func (r *stubTranslationRepo) Save(translations ...*po.ArticleTranslation) error {
+	saved := make(map[uint]*po.ArticleTranslation, len(r.translations)+len(translations))
+	for id, translation := range r.translations {
+		saved[id] = translation
+	}
+	for _, translation := range translations {
+		copied := *translation
+		saved[translation.ArticleID] = &copied
+	}
+	seen := make(map[string]bool, len(saved))
+	for _, translation := range saved {
+		key := fmt.Sprintf("%d/%s", translation.GroupID, translation.Language)
+		if seen[key] {
+			return fmt.Errorf("duplicate entry %s for key idx_translation_language", key)
+		}
+		seen[key] = true
+	}
+	r.translations = saved
+	return nil
+}
+
+func (r *stubTranslationRepo) Remove(articleID uint) error {
+	translation, ok := r.translations[articleID]
+	if !ok {
+		return nil
+	}
+	groupID := translation.GroupID
+	delete(r.translations, articleID)
+	remaining := 0
+	for _, t := range r.translations {
+		if t.GroupID == groupID {
+			remaining++
+		}
+	}
+	for id, t := range r.translations {
+		if t.GroupID == groupID && (articleID == groupID || remaining <= 1) {
+			delete(r.translations, id)
+		}
+	}
+	return nil
+}
+
+func newTranslationTestData() (*data.Data, *stubTranslationRepo) {
+	slug := func(s string) *string { return &s }
+	articles := map[uint]*po.Article{
+		1: {ID: 1, Title: "你好", Slug: slug("ni-hao"), Status: 1, Visibility: po.VisibilityPublic},
+		2: {ID: 2, Title: "Hello", Slug: slug("hello"), Status: 1, Visibility: po.VisibilityPublic},
+		3: {ID: 3, Title: "こんにちは", Slug: slug("konnichiwa"), Status: 0, Visibility: po.VisibilityPublic},
+		4: {ID: 4, Title: "Other", Slug: slug("other"), Status: 1, Visibility: po.VisibilityPublic},
+		5: {ID: 5, Title: "Other en", Slug: slug("other-en"), Status: 1, Visibility: po.VisibilityPublic},
+	}
+	translations := &stubTranslationRepo{articles: articles, translations: map[uint]*po.ArticleTranslation{}}
+	return &data.Data{
+		ArticleRepo:            &stubTranslationArticleRepo{articles: articles},
+		ArticleTranslationRepo: translations,
+	}, translations
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	tests := map[string]string{
+		"en":         "en",
+		"EN-us":      "en-US",
+		"zh_cn":      "zh-CN",
+		"zh-hant-tw": "zh-Hant-TW",
+		"":           "",
+		"e":          "",
+		"en--US":     "",
+		"en;q=0.9":   "",
+	}
+	for in, want := range tests {
+		if got := normalizeLanguage(in); got != want {
+			t.Errorf("normalizeLanguage(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLinkTranslation(t *testing.T) {
+	d, repo := newTranslationTestData()
+	uc := NewArticleUseCase(d)
+
+	// 首次关联以当前文章为源文章建立翻译组，源文章语言默认为站点默认语言
+	resp, err := uc.LinkTranslation(1, &dto.LinkTranslationRequest{ArticleID: 2, Language: "EN"})
+	if err != nil {
+		t.Fatalf("LinkTranslation: %v", err)
+	}
+	if resp.GroupID != 1 || len(resp.Items) != 2 || !resp.Items[0].Source || resp.Items[0].Language != "zh-CN" || resp.Items[1].Language != "en" {
+		t.Fatalf("translations = %+v", resp)
+	}
+
+	// 从译文一侧继续关联，加入同一翻译组
+	if _, err := uc.LinkTranslation(2, &dto.LinkTranslationRequest{ArticleID: 3, Language: "ja"}); err != nil {
+		t.Fatalf("LinkTranslation from variant: %v", err)
+	}
+	if repo.translations[3].GroupID != 1 {
+		t.Errorf("variant group = %d", repo.translations[3].GroupID)
+	}
+
+	tests := []struct {
+		name      string
+		articleID uint
+		req       dto.LinkTranslationRequest
+		wantErr   error
+	}{
+		{name: "self", articleID: 1, req: dto.LinkTranslationRequest{ArticleID: 1, Language: "en"}, wantErr: ErrTranslationInvalid},
+		{name: "bad language", articleID: 1, req: dto.LinkTranslationRequest{ArticleID: 4, Language: "english!"}, wantErr: ErrTranslationInvalid},
+		{name: "language taken", articleID: 1, req: dto.LinkTranslationRequest{ArticleID: 4, Language: "en"}, wantErr: ErrTranslationConflict},
+		{name: "source language taken", articleID: 1, req: dto.LinkTranslationRequest{ArticleID: 4, Language: "fr", SourceLanguage: "ja"}, wantErr: ErrTranslationConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.LinkTranslation(tt.articleID, &tt.req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// 已属于其他翻译组的文章不能直接关联
+	if _, err := uc.LinkTranslation(4, &dto.LinkTranslationRequest{ArticleID: 5, Language: "en"}); err != nil {
+		t.Fatalf("LinkTranslation second group: %v", err)
+	}
+	if _, err := uc.LinkTranslation(1, &dto.LinkTranslationRequest{ArticleID: 5, Language: "fr"}); !errors.Is(err, ErrTranslationConflict) {
+		t.Errorf("cross group err = %v", err)
+	}
+
+	// 移出源文章解散整个翻译组
+	if err := uc.UnlinkTranslation(4); err != nil {
+		t.Fatalf("UnlinkTranslation: %v", err)
+	}
+	if _, ok := repo.translations[5]; ok {
+		t.Error("group not dissolved after source unlinked")
+	}
+}
+
+func TestLinkTranslationSwapLanguages(t *testing.T) {
+	d, repo := newTranslationTestData()
+	uc := NewArticleUseCase(d)
+	if _, err := uc.LinkTranslation(1, &dto.LinkTranslationRequest{ArticleID: 2, Language: "en", SourceLanguage: "zh-CN"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 源文章与译文互换语言：源文章改为 en，译文改为 zh-CN
+	resp, err := uc.LinkTranslation(1, &dto.LinkTranslationRequest{ArticleID: 2, Language: "zh-CN", SourceLanguage: "en"})
+	if err != nil {
+		t.Fatalf("swap languages: %v", err)
+	}
+	if repo.translations[1].Language != "en" || repo.translations[2].Language != "zh-CN" {
+		t.Errorf("languages = %s/%s, want en/zh-CN", repo.translations[1].Language, repo.translations[2].Language)
+	}
+	if len(resp.Items) != 2 || !resp.Items[0].Source || resp.Items[0].Language != "en" {
+		t.Errorf("translations = %+v", resp.Items)
+	}
+}
+
+func TestResolveTranslation(t *testing.T) {
+	d, _ := newTranslationTestData()
+	uc := NewArticleUseCase(d)
+	if _, err := uc.LinkTranslation(1, &dto.LinkTranslationRequest{ArticleID: 2, Language: "en", SourceLanguage: "zh-CN"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.LinkTranslation(1, &dto.LinkTranslationRequest{ArticleID: 3, Language: "ja"}); err != nil {
+		t.Fatal(err)
+	}
+	source, _ := d.ArticleRepo.FindByID(1)
+
+	tests := []struct {
+		lang         string
+		wantID       uint
+		wantLanguage string
+	}{
+		{lang: "", wantID: 1, wantLanguage: "zh-CN"},
+		{lang: "en-us", wantID: 1, wantLanguage: "zh-CN"},
+		{lang: "EN", wantID: 2, wantLanguage: "en"},
+		{lang: "ja", wantID: 1, wantLanguage: "zh-CN"}, // 日文版本未发布，回退到原文章
+	}
+	for _, tt := range tests {
+		article, language, items := resolveTranslation(d, source, tt.lang)
+		if article.ID != tt.wantID || language != tt.wantLanguage {
+			t.Errorf("lang %q: got article %d (%s), want %d (%s)", tt.lang, article.ID, language, tt.wantID, tt.wantLanguage)
+		}
+		if len(items) != 2 {
+			t.Errorf("lang %q: visible translations = %+v", tt.lang, items)
+		}
+	}
+
+	// 未加入翻译组的文章原样返回
+	other, _ := d.ArticleRepo.FindByID(4)
+	if article, language, items := resolveTranslation(d, other, "en"); article.ID != 4 || language != "" || items != nil {
+		t.Errorf("unlinked = %d %q %v", article.ID, language, items)
+	}
+}
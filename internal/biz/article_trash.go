@@ -0,0 +1,89 @@
+package biz
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// ListTrash 查询回收站文章
+func (uc *articleUseCase) ListTrash(req *dto.TrashListRequest) (*dto.PageResponse, error) {
+	articles, total, err := uc.articleRepo().ListTrashed(req.Page, req.Limit, req.Keyword)
+	if err != nil {
+		return nil, errors.New("查询回收站失败")
+	}
+
+	retentionDays := config.AppConfig.Trash.RetentionDays
+	items := make([]dto.TrashItem, 0, len(articles))
+	for _, article := range articles {
+		item := dto.TrashItem{
+			ArticleListItem: uc.convertToArticleListItem(article),
+			DeletedAt:       article.DeletedAt.Time,
+		}
+		if retentionDays > 0 {
+			purgeAt := article.DeletedAt.Time.AddDate(0, 0, retentionDays)
+			item.PurgeAt = &purgeAt
+		}
+		items = append(items, item)
+	}
+
+	return &dto.PageResponse{
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Data:  items,
+	}, nil
+}
+
+// RestoreTrash 从回收站恢复文章
+func (uc *articleUseCase) RestoreTrash(articleIDs []uint) error {
+	if len(articleIDs) == 0 {
+		return errors.New("文章ID列表不能为空")
+	}
+
+	if err := uc.articleRepo().Restore(articleIDs); err != nil {
+		return trashError(err, "恢复文章失败")
+	}
+
+	// 同步搜索索引，刷新站点地图
+	syncSearchIndex(uc.data, articleIDs...)
+	InvalidateSitemap()
+
+	// 恢复的文章重新可见，按更新事件投递 Webhook
+	notifyArticles(uc.context(), uc.data, po.WebhookEventArticleUpdated, articleIDs...)
+
+	return nil
+}
+
+// PurgeTrash 彻底删除回收站中的文章
+func (uc *articleUseCase) PurgeTrash(articleIDs []uint) error {
+	if len(articleIDs) == 0 {
+		return errors.New("文章ID列表不能为空")
+	}
+
+	if err := uc.articleRepo().Purge(articleIDs); err != nil {
+		return trashError(err, "彻底删除文章失败")
+	}
+
+	return nil
+}
+
+// PurgeExpiredTrash 彻底删除超过保留期限的回收站文章
+func (uc *articleUseCase) PurgeExpiredTrash(retentionDays int) (int64, error) {
+	if retentionDays <= 0 {
+		return 0, nil
+	}
+	return uc.data.ArticleRepo.PurgeDeletedBefore(time.Now().AddDate(0, 0, -retentionDays))
+}
+
+// trashError 将回收站操作的仓储错误转换为业务错误
+func trashError(err error, msg string) error {
+	if errors.Is(err, data.ErrArticleNotInTrash) {
+		return errors.New("文章不在回收站中")
+	}
+	return ownershipError(err, msg)
+}
@@ -0,0 +1,147 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/diff"
+	"github.com/ydcloud-dy/leaf-api/pkg/sanitize"
+)
+
+// ListVersions 查询文章历史版本
+func (uc *articleUseCase) ListVersions(articleID uint) ([]*dto.ArticleVersionItem, error) {
+	if _, err := uc.data.ArticleRepo.FindByID(articleID); err != nil {
+		return nil, errors.New("文章不存在")
+	}
+
+	versions, err := uc.data.ArticleVersionRepo.ListByArticle(articleID)
+	if err != nil {
+		return nil, errors.New("查询历史版本失败")
+	}
+
+	items := make([]*dto.ArticleVersionItem, 0, len(versions))
+	for _, v := range versions {
+		item := convertToArticleVersionItem(v)
+		items = append(items, &item)
+	}
+	return items, nil
+}
+
+// GetVersion 查询文章历史版本详情
+func (uc *articleUseCase) GetVersion(articleID, versionID uint) (*dto.ArticleVersionResponse, error) {
+	version, err := uc.findVersion(articleID, versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ArticleVersionResponse{
+		ArticleVersionItem: convertToArticleVersionItem(version),
+		ContentMarkdown:    version.ContentMarkdown,
+		ContentHTML:        sanitize.Article(version.ContentHTML),
+	}, nil
+}
+
+// DiffVersion 对比历史版本与另一个版本（againstID 为 0 时与当前内容对比）
+func (uc *articleUseCase) DiffVersion(articleID, versionID, againstID uint) (*dto.ArticleVersionDiff, error) {
+	from, err := uc.findVersion(articleID, versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var toLabel, toTitle, toContent string
+	if againstID == 0 {
+		article, err := uc.data.ArticleRepo.FindByID(articleID)
+		if err != nil {
+			return nil, errors.New("文章不存在")
+		}
+		toLabel, toTitle, toContent = "current", article.Title, article.ContentMarkdown
+	} else {
+		to, err := uc.findVersion(articleID, againstID)
+		if err != nil {
+			return nil, err
+		}
+		toLabel, toTitle, toContent = fmt.Sprintf("v%d", to.Version), to.Title, to.ContentMarkdown
+	}
+
+	content := diff.Lines(from.ContentMarkdown, toContent)
+	added, removed := diff.Stats(content)
+
+	return &dto.ArticleVersionDiff{
+		From:    fmt.Sprintf("v%d", from.Version),
+		To:      toLabel,
+		Title:   convertDiffLines(diff.Lines(from.Title, toTitle)),
+		Content: convertDiffLines(content),
+		Added:   added,
+		Removed: removed,
+	}, nil
+}
+
+// RestoreVersion 将文章恢复到指定历史版本（恢复前的内容会保存为新版本）
+func (uc *articleUseCase) RestoreVersion(articleID, versionID uint) (*dto.ArticleResponse, error) {
+	version, err := uc.findVersion(articleID, versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	article, err := uc.data.ArticleRepo.FindByID(articleID)
+	if err != nil {
+		return nil, errors.New("文章不存在")
+	}
+
+	before := *article
+	article.Title = version.Title
+	article.ContentMarkdown = version.ContentMarkdown
+	applyReadingStats(article)
+	article.ContentHTML = sanitize.Article(version.ContentHTML)
+	article.Summary = version.Summary
+
+	if err := uc.articleRepo().Update(article); err != nil {
+		return nil, ownershipError(err, "恢复历史版本失败")
+	}
+
+	// 记录审计日志
+	if changes := diffArticle(&before, article); len(changes) > 0 {
+		uc.recordAudit(newAuditLog(uc.operatorID, po.AuditActionUpdate, article, changes))
+	}
+
+	// 同步搜索索引，刷新站点地图
+	syncSearchIndex(uc.data, articleID)
+	InvalidateSitemap()
+
+	// 投递 Webhook 事件
+	notifyArticles(uc.context(), uc.data, po.WebhookEventArticleUpdated, articleID)
+
+	return uc.GetByID(articleID)
+}
+
+// findVersion 查询属于指定文章的版本
+func (uc *articleUseCase) findVersion(articleID, versionID uint) (*po.ArticleVersion, error) {
+	version, err := uc.data.ArticleVersionRepo.FindByID(versionID)
+	if err != nil || version.ArticleID != articleID {
+		return nil, errors.New("历史版本不存在")
+	}
+	return version, nil
+}
+
+// convertToArticleVersionItem 转换为版本列表项
+func convertToArticleVersionItem(v *po.ArticleVersion) dto.ArticleVersionItem {
+	return dto.ArticleVersionItem{
+		ID:        v.ID,
+		ArticleID: v.ArticleID,
+		Version:   v.Version,
+		Title:     v.Title,
+		Summary:   v.Summary,
+		CreatedAt: v.CreatedAt,
+	}
+}
+
+// convertDiffLines 转换差异行
+func convertDiffLines(lines []diff.Line) []dto.DiffLine {
+	result := make([]dto.DiffLine, 0, len(lines))
+	for _, l := range lines {
+		result = append(result, dto.DiffLine{Op: string(l.Op), Text: l.Text})
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package biz
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+// articleViewPrefix 文章浏览去重 key 前缀：article:view:{文章ID}:{访客}
+const articleViewPrefix = "article:view:"
+
+// shouldCountView 判断本次浏览是否计入浏览量
+// 同一访客在去重窗口内重复访问只计一次；未开启去重、Redis 不可用或无法识别访客时每次都计数
+func shouldCountView(articleID uint, viewer string) bool {
+	window := time.Duration(config.AppConfig.Views.DedupWindow) * time.Minute
+	if window <= 0 || redis.Client == nil || viewer == "" {
+		return true
+	}
+
+	ok, err := redis.SetNX(fmt.Sprintf("%s%d:%s", articleViewPrefix, articleID, viewer), 1, window)
+	if err != nil {
+		return true
+	}
+	return ok
+}
+
+// viewerKey 访客标识：登录用户使用用户ID，游客使用 IP+UA 的摘要
+func viewerKey(userID uint, client *dto.ClientInfo) string {
+	if userID > 0 {
+		return fmt.Sprintf("u%d", userID)
+	}
+	if client == nil || client.IP == "" {
+		return ""
+	}
+	sum := sha1.Sum([]byte(client.IP + "|" + client.UserAgent))
+	return "g" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,52 @@
+package biz
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// articleAccessTTL 加密文章解锁后访问令牌的有效期
+const articleAccessTTL = 2 * time.Hour
+
+// applyVisibility 设置文章可见性，加密文章保存密码哈希
+// password 为空时保留原密码，首次设置为加密文章时必须提供密码
+func applyVisibility(article *po.Article, visibility, password string) error {
+	if visibility == "" {
+		visibility = po.VisibilityPublic
+	}
+
+	if visibility != po.VisibilityPassword {
+		article.Visibility = visibility
+		article.AccessPassword = ""
+		return nil
+	}
+
+	if password == "" {
+		if article.AccessPassword == "" {
+			return errors.New("加密文章必须设置访问密码")
+		}
+		article.Visibility = visibility
+		return nil
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New("密码加密失败")
+	}
+	article.Visibility = visibility
+	article.AccessPassword = string(hashed)
+	return nil
+}
+
+// articleLocked 文章是否需要密码才能查看正文
+func articleLocked(article *po.Article) bool {
+	return article.Visibility == po.VisibilityPassword
+}
+
+// articleVisible 文章是否对博客前台可见（私密文章仅后台可见）
+func articleVisible(article *po.Article) bool {
+	return article.Visibility != po.VisibilityPrivate
+}
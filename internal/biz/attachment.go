@@ -0,0 +1,269 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/jwt"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/oss"
+)
+
+const (
+	defaultAttachmentMaxSize = 100 // MB
+	// attachmentFolder 附件的存储目录
+	attachmentFolder = "attachments"
+)
+
+// defaultAttachmentExts 未配置 upload.attachment_exts 时允许的附件类型
+var defaultAttachmentExts = []string{
+	"pdf", "zip", "rar", "7z", "tar", "gz",
+	"doc", "docx", "xls", "xlsx", "ppt", "pptx",
+	"txt", "md", "csv", "epub",
+}
+
+var (
+	// ErrAttachmentNotFound 附件不存在或不可下载
+	ErrAttachmentNotFound = errors.New("附件不存在")
+	// ErrAttachmentInvalid 附件参数不合法
+	ErrAttachmentInvalid = errors.New("附件参数不合法")
+	// ErrAttachmentLocked 附件所属文章需要密码解锁
+	ErrAttachmentLocked = errors.New("文章需要密码解锁后才能下载附件")
+)
+
+// AttachmentUseCase 附件业务用例
+type AttachmentUseCase interface {
+	// Upload 上传附件，articleID 不为 0 时关联到文章
+	Upload(file *multipart.FileHeader, req *dto.UploadAttachmentRequest, uploaderID uint) (*dto.AttachmentResponse, error)
+	// List 分页查询附件
+	List(req *dto.AttachmentListRequest) (*dto.PageResponse, error)
+	// SetArticle 修改附件关联的文章，articleID 为 nil 时取消关联
+	SetArticle(id uint, articleID *uint) (*dto.AttachmentResponse, error)
+	// Delete 删除附件及存储中的文件
+	Delete(id uint) error
+	// ListByArticle 博客前台查询文章附件，加密文章需要访问令牌
+	ListByArticle(articleID uint, accessToken string) ([]*dto.AttachmentResponse, error)
+	// Download 博客前台下载附件，记录下载次数并返回附件信息
+	Download(id uint, accessToken string) (*po.Attachment, error)
+}
+
+type attachmentUseCase struct {
+	data *data.Data
+}
+
+// NewAttachmentUseCase 创建附件业务用例
+func NewAttachmentUseCase(d *data.Data) AttachmentUseCase {
+	return &attachmentUseCase{data: d}
+}
+
+// Upload 上传附件
+func (uc *attachmentUseCase) Upload(file *multipart.FileHeader, req *dto.UploadAttachmentRequest, uploaderID uint) (*dto.AttachmentResponse, error) {
+	maxSize := settingInt(uc.data, SettingAttachmentMaxSize)
+	if file.Size > int64(maxSize)<<20 {
+		return nil, fmt.Errorf("%w: 附件不能超过 %d MB", ErrAttachmentInvalid, maxSize)
+	}
+	if !attachmentExtAllowed(file.Filename) {
+		return nil, fmt.Errorf("%w: 不支持的文件类型", ErrAttachmentInvalid)
+	}
+
+	attachment := &po.Attachment{
+		Name:        filepath.Base(file.Filename),
+		Size:        file.Size,
+		MimeType:    file.Header.Get("Content-Type"),
+		Description: req.Description,
+		UploaderID:  uploaderID,
+	}
+	if req.ArticleID > 0 {
+		if _, err := uc.data.ArticleRepo.FindByID(req.ArticleID); err != nil {
+			return nil, fmt.Errorf("%w: 文章不存在", ErrAttachmentInvalid)
+		}
+		articleID := req.ArticleID
+		attachment.ArticleID = &articleID
+	}
+
+	url, err := oss.UploadFile(file, attachmentFolder)
+	if err != nil {
+		return nil, fmt.Errorf("上传附件失败: %w", err)
+	}
+	attachment.URL = url
+
+	if err := uc.data.AttachmentRepo.Create(attachment); err != nil {
+		if err := oss.DeleteFile(oss.GetObjectKeyFromURL(url)); err != nil {
+			logger.Warn("Failed to delete attachment file: ", err)
+		}
+		return nil, errors.New("保存附件失败")
+	}
+	return convertToAttachmentResponse(attachment, true), nil
+}
+
+// List 分页查询附件
+func (uc *attachmentUseCase) List(req *dto.AttachmentListRequest) (*dto.PageResponse, error) {
+	list, total, err := uc.data.AttachmentRepo.List(req.Page, req.Limit, req.Keyword, req.ArticleID, req.Unattached)
+	if err != nil {
+		return nil, errors.New("查询附件列表失败")
+	}
+
+	articleIDs := make([]uint, 0, len(list))
+	for _, attachment := range list {
+		if attachment.ArticleID != nil {
+			articleIDs = append(articleIDs, *attachment.ArticleID)
+		}
+	}
+	titles := make(map[uint]string, len(articleIDs))
+	if len(articleIDs) > 0 {
+		articles, err := uc.data.ArticleRepo.FindByIDs(articleIDs)
+		if err != nil {
+			return nil, errors.New("查询附件文章失败")
+		}
+		for _, article := range articles {
+			titles[article.ID] = article.Title
+		}
+	}
+
+	items := make([]*dto.AttachmentResponse, 0, len(list))
+	for _, attachment := range list {
+		resp := convertToAttachmentResponse(attachment, true)
+		if attachment.ArticleID != nil {
+			resp.ArticleTitle = titles[*attachment.ArticleID]
+		}
+		items = append(items, resp)
+	}
+
+	return &dto.PageResponse{
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Data:  items,
+	}, nil
+}
+
+// SetArticle 修改附件关联的文章
+func (uc *attachmentUseCase) SetArticle(id uint, articleID *uint) (*dto.AttachmentResponse, error) {
+	attachment, err := uc.data.AttachmentRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrAttachmentNotFound
+	}
+	if articleID != nil && *articleID == 0 {
+		articleID = nil
+	}
+
+	resp := convertToAttachmentResponse(attachment, true)
+	if articleID != nil {
+		article, err := uc.data.ArticleRepo.FindByID(*articleID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 文章不存在", ErrAttachmentInvalid)
+		}
+		resp.ArticleTitle = article.Title
+	}
+
+	if err := uc.data.AttachmentRepo.SetArticle(id, articleID); err != nil {
+		return nil, errors.New("关联文章失败")
+	}
+	resp.ArticleID = articleID
+	return resp, nil
+}
+
+// Delete 删除附件，存储中的文件删除失败时保留记录以便重试
+func (uc *attachmentUseCase) Delete(id uint) error {
+	attachment, err := uc.data.AttachmentRepo.FindByID(id)
+	if err != nil {
+		return ErrAttachmentNotFound
+	}
+	if err := oss.DeleteFile(oss.GetObjectKeyFromURL(attachment.URL)); err != nil {
+		return fmt.Errorf("删除附件文件失败: %w", err)
+	}
+	if err := uc.data.AttachmentRepo.Delete(id); err != nil {
+		return errors.New("删除附件失败")
+	}
+	return nil
+}
+
+// ListByArticle 博客前台查询文章附件，只返回已发布且前台可见文章的附件
+func (uc *attachmentUseCase) ListByArticle(articleID uint, accessToken string) ([]*dto.AttachmentResponse, error) {
+	if err := uc.checkArticle(articleID, accessToken); err != nil {
+		return nil, err
+	}
+
+	list, err := uc.data.AttachmentRepo.ListByArticle(articleID)
+	if err != nil {
+		return nil, errors.New("查询附件失败")
+	}
+	items := make([]*dto.AttachmentResponse, 0, len(list))
+	for _, attachment := range list {
+		items = append(items, convertToAttachmentResponse(attachment, false))
+	}
+	return items, nil
+}
+
+// Download 博客前台下载附件，未关联文章或文章不可见的附件视为不存在
+func (uc *attachmentUseCase) Download(id uint, accessToken string) (*po.Attachment, error) {
+	attachment, err := uc.data.AttachmentRepo.FindByID(id)
+	if err != nil || attachment.ArticleID == nil {
+		return nil, ErrAttachmentNotFound
+	}
+	if err := uc.checkArticle(*attachment.ArticleID, accessToken); err != nil {
+		return nil, err
+	}
+
+	if err := uc.data.AttachmentRepo.IncrDownloads(id); err != nil {
+		logger.Warn("Failed to count attachment download: ", err)
+	}
+	attachment.DownloadCount++
+	return attachment, nil
+}
+
+// checkArticle 文章是否允许前台访问附件，规则与文章详情一致：已发布、非私密，加密文章需携带有效访问令牌
+func (uc *attachmentUseCase) checkArticle(articleID uint, accessToken string) error {
+	article, err := uc.data.ArticleRepo.FindByID(articleID)
+	if err != nil || article.Status != 1 || !articleVisible(article) {
+		return ErrAttachmentNotFound
+	}
+	if articleLocked(article) && !jwt.VerifyArticleAccessToken(accessToken, articleID) {
+		return ErrAttachmentLocked
+	}
+	return nil
+}
+
+// convertToAttachmentResponse 转换为附件响应，withURL 为 false 时不返回文件地址
+func convertToAttachmentResponse(attachment *po.Attachment, withURL bool) *dto.AttachmentResponse {
+	resp := &dto.AttachmentResponse{
+		ID:            attachment.ID,
+		Name:          attachment.Name,
+		DownloadURL:   fmt.Sprintf("/blog/attachments/%d/download", attachment.ID),
+		Size:          attachment.Size,
+		MimeType:      attachment.MimeType,
+		Description:   attachment.Description,
+		ArticleID:     attachment.ArticleID,
+		DownloadCount: attachment.DownloadCount,
+		CreatedAt:     attachment.CreatedAt,
+	}
+	if withURL {
+		resp.URL = attachment.URL
+	}
+	return resp
+}
+
+// attachmentExtAllowed 文件扩展名是否在允许的附件类型中
+func attachmentExtAllowed(filename string) bool {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+	if ext == "" {
+		return false
+	}
+	exts := config.AppConfig.Upload.AttachmentExts
+	if len(exts) == 0 {
+		exts = defaultAttachmentExts
+	}
+	for _, allowed := range exts {
+		if strings.EqualFold(strings.TrimPrefix(allowed, "."), ext) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,23 +1,65 @@
 package biz
 
 import (
+	"context"
 	"errors"
+	"fmt"
 
 	"github.com/ydcloud-dy/leaf-api/internal/data"
 	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
 	"github.com/ydcloud-dy/leaf-api/internal/model/po"
 	"github.com/ydcloud-dy/leaf-api/pkg/jwt"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	// ErrSigningKeyForbidden 无权限管理签名密钥
+	ErrSigningKeyForbidden = errors.New("无权限管理签名密钥")
+	// ErrSigningKeyInvalid 签名密钥不符合要求或当前不能操作
+	ErrSigningKeyInvalid = errors.New("签名密钥无效")
+	// ErrSigningKeyNotFound 签名密钥不存在
+	ErrSigningKeyNotFound = errors.New("签名密钥不存在")
+	// ErrSigningKeyStoreUnavailable 未启用 Redis，轮换后的密钥无法保存
+	ErrSigningKeyStoreUnavailable = errors.New("未启用 Redis，无法轮换签名密钥，请修改配置文件中的 jwt.secret 和 jwt.key_id 后重启")
+)
+
 // AuthUseCase 认证业务用例接口
 type AuthUseCase interface {
 	// Login 管理员登录
-	Login(req *dto.LoginRequest) (*dto.LoginResponse, error)
+	Login(ctx context.Context, req *dto.LoginRequest, client *dto.ClientInfo) (*dto.LoginResponse, error)
 	// GetProfile 获取管理员信息
 	GetProfile(adminID uint) (*dto.AdminInfo, error)
 	// UpdateProfile 更新管理员信息
 	UpdateProfile(adminID uint, req *dto.UpdateProfileRequest) (*po.User, error)
+	// RotateSigningKey 轮换 JWT 签名密钥
+	RotateSigningKey(role string, req *dto.RotateKeyRequest) (*dto.RotateKeyResponse, error)
+	// RevokeSigningKey 吊销 JWT 旧签名密钥
+	RevokeSigningKey(role, keyID string) ([]*dto.SigningKeyInfo, error)
+	// Refresh 使用刷新令牌换取新的访问令牌和刷新令牌
+	Refresh(refreshToken string, client *dto.ClientInfo) (*dto.TokenResponse, error)
+	// Logout 退出登录，吊销刷新令牌和访问令牌所属的会话
+	Logout(refreshToken, accessToken string)
+	// ListSessions 查询用户的登录会话
+	ListSessions(userID uint, currentSession string) ([]*dto.SessionInfo, error)
+	// RevokeSession 吊销用户的一个会话
+	RevokeSession(userID uint, sessionID string) error
+	// LogoutAll 退出所有设备
+	LogoutAll(userID uint) error
+	// VerifyTwoFactor 登录时提交两步验证码，验证通过后签发 Token
+	VerifyTwoFactor(ctx context.Context, req *dto.TwoFactorVerifyRequest, client *dto.ClientInfo) (*dto.LoginResponse, error)
+	// SetupTwoFactorChallenge 登录时为角色要求两步验证但尚未绑定的账号生成密钥
+	SetupTwoFactorChallenge(challengeToken string) (*dto.TwoFactorSetupResponse, error)
+	// GetTwoFactorStatus 查询两步验证状态
+	GetTwoFactorStatus(userID uint, role string) (*dto.TwoFactorStatusResponse, error)
+	// SetupTwoFactor 生成新的 TOTP 密钥，使用验证码确认后才启用
+	SetupTwoFactor(userID uint) (*dto.TwoFactorSetupResponse, error)
+	// EnableTwoFactor 使用验证码确认并启用两步验证，返回恢复码
+	EnableTwoFactor(userID uint, code string) (*dto.RecoveryCodesResponse, error)
+	// DisableTwoFactor 关闭两步验证
+	DisableTwoFactor(userID uint, role, code string) error
+	// RegenerateRecoveryCodes 重新生成恢复码，旧恢复码全部失效
+	RegenerateRecoveryCodes(userID uint, code string) (*dto.RecoveryCodesResponse, error)
 }
 
 // authUseCase 认证业务用例实现
@@ -30,38 +72,59 @@ func NewAuthUseCase(d *data.Data) AuthUseCase {
 	return &authUseCase{data: d}
 }
 
-// Login 管理员登录
-func (uc *authUseCase) Login(req *dto.LoginRequest) (*dto.LoginResponse, error) {
+// Login 管理员登录，记录登录日志
+func (uc *authUseCase) Login(ctx context.Context, req *dto.LoginRequest, client *dto.ClientInfo) (*dto.LoginResponse, error) {
+	user, resp, err := uc.login(req, client)
+	// 需要两步验证时登录尚未完成，验证结果在 VerifyTwoFactor 中记录
+	if err != nil || !resp.TwoFactorRequired {
+		recordLogin(ctx, uc.data, user, req.Username, po.LoginChannelAdmin, client, err)
+	}
+	return resp, err
+}
+
+// login 校验用户名密码并签发 Token，用户名不存在时返回的用户为 nil
+func (uc *authUseCase) login(req *dto.LoginRequest, client *dto.ClientInfo) (*po.User, *dto.LoginResponse, error) {
 	// 查询用户（统一使用users表）
 	user, err := uc.data.UserRepo.FindByUsername(req.Username)
 	if err != nil {
-		return nil, errors.New("用户名或密码错误")
+		return nil, nil, errors.New("用户名或密码错误")
 	}
 
 	// 验证密码
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return nil, errors.New("用户名或密码错误")
+		return user, nil, errors.New("用户名或密码错误")
 	}
 
 	// 检查状态
 	if user.Status != 1 {
-		return nil, errors.New("账号已被禁用")
+		return user, nil, errors.New("账号已被禁用")
+	}
+
+	// 检查是否可以登录管理后台
+	if !ConsoleRole(user.Role) {
+		return user, nil, errors.New("无权限访问管理后台")
 	}
 
-	// 检查是否是管理员角色
-	if user.Role != "admin" && user.Role != "super_admin" {
-		return nil, errors.New("无权限访问管理后台")
+	// 需要两步验证时先返回两步验证令牌，验证通过后再签发 Token
+	if challenge, err := uc.twoFactorChallenge(user); err != nil || challenge != nil {
+		return user, challenge, err
 	}
 
 	// 生成 Token
-	token, err := jwt.GenerateToken(user.ID, user.Username, user.Role)
+	tokens, err := issueTokens(user, user.Role, client)
 	if err != nil {
-		return nil, errors.New("生成 Token 失败")
+		return user, nil, err
 	}
 
-	// 返回登录结果
+	return user, adminLoginResponse(user, tokens), nil
+}
+
+// adminLoginResponse 管理后台登录结果
+func adminLoginResponse(user *po.User, tokens *dto.TokenResponse) *dto.LoginResponse {
 	return &dto.LoginResponse{
-		Token: token,
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
 		Admin: &dto.AdminInfo{
 			ID:        user.ID,
 			Username:  user.Username,
@@ -75,7 +138,7 @@ func (uc *authUseCase) Login(req *dto.LoginRequest) (*dto.LoginResponse, error)
 			Status:    user.Status,
 			CreatedAt: user.CreatedAt,
 		},
-	}, nil
+	}
 }
 
 // GetProfile 获取管理员信息
@@ -144,3 +207,70 @@ func (uc *authUseCase) UpdateProfile(adminID uint, req *dto.UpdateProfileRequest
 
 	return user, nil
 }
+
+// RotateSigningKey 轮换 JWT 签名密钥
+// 新密钥保存在 Redis 中，所有实例共享且重启后仍然有效，响应中不返回密钥内容；
+// 默认保留旧密钥用于校验已签发的 token，revoke_previous 为 true 时旧密钥立即失效
+func (uc *authUseCase) RotateSigningKey(role string, req *dto.RotateKeyRequest) (*dto.RotateKeyResponse, error) {
+	if role != "admin" && role != "super_admin" {
+		return nil, ErrSigningKeyForbidden
+	}
+
+	kid, err := jwt.RotateKey(req.Secret, req.RevokePrevious)
+	if err != nil {
+		return nil, signingKeyError("轮换签名密钥失败", err)
+	}
+
+	return &dto.RotateKeyResponse{
+		KeyID: kid,
+		Keys:  signingKeys(),
+	}, nil
+}
+
+// RevokeSigningKey 吊销 JWT 旧签名密钥，使用该密钥签发的 token 立即失效
+func (uc *authUseCase) RevokeSigningKey(role, keyID string) ([]*dto.SigningKeyInfo, error) {
+	if role != "admin" && role != "super_admin" {
+		return nil, ErrSigningKeyForbidden
+	}
+
+	if err := jwt.RevokeKey(keyID); err != nil {
+		return nil, signingKeyError("吊销签名密钥失败", err)
+	}
+	return signingKeys(), nil
+}
+
+// signingKeys 当前可用的签名密钥
+func signingKeys() []*dto.SigningKeyInfo {
+	keys := make([]*dto.SigningKeyInfo, 0)
+	for _, k := range jwt.ListKeys() {
+		keys = append(keys, &dto.SigningKeyInfo{
+			ID:      k.ID,
+			Current: k.Current,
+		})
+	}
+	return keys
+}
+
+// signingKeyError 将密钥环错误转换为业务错误
+func signingKeyError(action string, err error) error {
+	switch {
+	case errors.Is(err, jwt.ErrKeyStoreUnavailable):
+		return ErrSigningKeyStoreUnavailable
+	case errors.Is(err, jwt.ErrKeyNotFound):
+		return ErrSigningKeyNotFound
+	case errors.Is(err, jwt.ErrSecretTooShort):
+		return fmt.Errorf("%w: 密钥长度至少 32 个字符", ErrSigningKeyInvalid)
+	case errors.Is(err, jwt.ErrRotateTooFrequently):
+		return fmt.Errorf("%w: 轮换过于频繁，请稍后再试", ErrSigningKeyInvalid)
+	case errors.Is(err, jwt.ErrRevokeCurrentKey):
+		return fmt.Errorf("%w: 当前签名密钥不能吊销", ErrSigningKeyInvalid)
+	default:
+		logger.Error(action, ": ", err)
+		return errors.New(action)
+	}
+}
+
+// Refresh 使用刷新令牌换取新的访问令牌和刷新令牌，管理后台和博客前台的令牌通用
+func (uc *authUseCase) Refresh(refreshToken string, client *dto.ClientInfo) (*dto.TokenResponse, error) {
+	return refreshTokens(uc.data, refreshToken, client)
+}
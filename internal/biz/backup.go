@@ -0,0 +1,646 @@
+package biz
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/encrypt"
+	"github.com/ydcloud-dy/leaf-api/pkg/oss"
+	"gorm.io/gorm"
+)
+
+const (
+	// backupFormatVersion 备份格式版本，结构变化时递增，恢复时据此兼容旧备份
+	// 1: 文章、标签、分类、章节、评论、设置
+	// 2: 增加用户、共同作者、系列、点赞、收藏和评论点赞，可完整迁移到其他实例
+	backupFormatVersion = 2
+	defaultBackupDir    = "./backups"
+	defaultBackupPrefix = "backups/"
+	defaultBackupKeep   = 7
+	// backupBatchSize 导出文章和评论时每批查询的数量
+	backupBatchSize = 200
+)
+
+var (
+	// backupNamePattern 备份文件名，如 leaf-backup-20260101-030000-9f86d081884c7d65.zip
+	// 随机后缀使文件名无法按时间推测；兼容没有后缀的旧备份
+	backupNamePattern = regexp.MustCompile(`^leaf-backup-\d{8}-\d{6}(-[0-9a-f]{16})?\.zip$`)
+	// backupImagePattern Markdown 中的图片地址
+	backupImagePattern = regexp.MustCompile(`!\[[^\]]*\]\(([^)\s]+)`)
+)
+
+// ErrBackupNotFound 备份不存在
+var ErrBackupNotFound = errors.New("备份不存在")
+
+// backupManifest 备份中的 manifest.json
+type backupManifest struct {
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"created_at"`
+	Counts    map[string]int `json:"counts"` // 各数据文件的记录数
+	Images    []backupImage  `json:"images"` // 文章引用的图片清单，图片文件本身不在备份中
+}
+
+// backupImage 图片清单项
+type backupImage struct {
+	URL        string `json:"url"`
+	ArticleIDs []uint `json:"article_ids"`
+}
+
+// backupArticle 备份中的文章：补充 JSON 中不输出的访问密码和标签关系，不含关联对象
+type backupArticle struct {
+	po.Article
+	AccessPassword string `json:"access_password,omitempty"`
+	TagIDs         []uint `json:"tag_ids"`
+	// 以下字段屏蔽 po.Article 中未加载的关联对象
+	Author   *struct{} `json:"author,omitempty"`
+	Category *struct{} `json:"category,omitempty"`
+}
+
+// backupUser 备份中的用户：启用字段加密时补充加密后的密码哈希，未启用时不包含密码
+type backupUser struct {
+	po.User
+	Password string `json:"password,omitempty"`
+}
+
+// backupLink 点赞、收藏和评论点赞记录
+type backupLink struct {
+	ID        uint      `json:"id"`
+	ArticleID uint      `json:"article_id,omitempty"`
+	CommentID uint      `json:"comment_id,omitempty"`
+	UserID    uint      `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// backupComment 备份中的评论，不含关联对象
+type backupComment struct {
+	po.Comment
+	User *struct{} `json:"user,omitempty"`
+}
+
+// BackupUseCase 备份业务用例接口
+type BackupUseCase interface {
+	// Create 立即备份全部内容，上传到 OSS（未配置时保存在本地）
+	Create() (*dto.BackupItem, error)
+	// List 按时间倒序列出备份
+	List() ([]*dto.BackupItem, error)
+	// Open 读取备份文件，调用方负责关闭
+	Open(name string) (io.ReadCloser, error)
+	// Prune 按保留策略删除旧备份，返回删除数量
+	Prune() (int, error)
+	// Restore 从备份文件恢复数据，dryRun 时只返回将要发生的变更
+	Restore(r io.ReaderAt, size int64, dryRun bool) (*dto.RestoreReport, error)
+	// RestoreByName 从已保存的备份恢复数据
+	RestoreByName(name string, dryRun bool) (*dto.RestoreReport, error)
+	// ExportJSON 导出全部内容为单个 JSON 文档
+	ExportJSON(w io.Writer) error
+	// ImportJSON 导入 ExportJSON 生成的文档，与恢复备份的处理方式相同
+	ImportJSON(r io.Reader, dryRun bool) (*dto.RestoreReport, error)
+}
+
+// backupUseCase 备份业务用例实现
+type backupUseCase struct {
+	data *data.Data
+}
+
+// NewBackupUseCase 创建备份业务用例
+func NewBackupUseCase(d *data.Data) BackupUseCase {
+	return &backupUseCase{data: d}
+}
+
+// Create 生成备份：先写入备份目录下的临时文件，再上传到 OSS 或改名为正式文件
+func (uc *backupUseCase) Create() (*dto.BackupItem, error) {
+	if err := os.MkdirAll(backupDir(), 0755); err != nil {
+		return nil, fmt.Errorf("创建备份目录失败: %w", err)
+	}
+	tmp, err := os.CreateTemp(backupDir(), "leaf-backup-*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("创建备份文件失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	now := time.Now()
+	if err := uc.writeBackup(&zipBackupSink{w: zip.NewWriter(tmp)}, now); err != nil {
+		return nil, err
+	}
+	info, err := tmp.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	suffix, err := randomHex(8)
+	if err != nil {
+		return nil, fmt.Errorf("生成备份文件名失败: %w", err)
+	}
+	name := fmt.Sprintf("leaf-backup-%s-%s.zip", now.Format("20060102-150405"), suffix)
+	item := &dto.BackupItem{Name: name, Size: info.Size(), Location: backupLocation(), CreatedAt: now}
+	if oss.Enabled() {
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if err := oss.PutObject(backupPrefix()+name, tmp); err != nil {
+			return nil, fmt.Errorf("上传备份失败: %w", err)
+		}
+		return item, nil
+	}
+
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(backupDir(), name)); err != nil {
+		return nil, fmt.Errorf("保存备份失败: %w", err)
+	}
+	return item, nil
+}
+
+// List 按时间倒序列出备份
+func (uc *backupUseCase) List() ([]*dto.BackupItem, error) {
+	var items []*dto.BackupItem
+	if oss.Enabled() {
+		objects, err := oss.ListObjects(backupPrefix())
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range objects {
+			name := strings.TrimPrefix(object.Key, backupPrefix())
+			if !backupNamePattern.MatchString(name) {
+				continue
+			}
+			items = append(items, &dto.BackupItem{Name: name, Size: object.Size, Location: "oss", CreatedAt: object.LastModified})
+		}
+	} else {
+		entries, err := os.ReadDir(backupDir())
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil || !backupNamePattern.MatchString(entry.Name()) {
+				continue
+			}
+			items = append(items, &dto.BackupItem{Name: entry.Name(), Size: info.Size(), Location: "local", CreatedAt: info.ModTime()})
+		}
+	}
+
+	// 文件名中的时间与创建时间一致，按文件名倒序即按时间倒序
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name > items[j].Name
+	})
+	return items, nil
+}
+
+// Open 读取备份文件
+func (uc *backupUseCase) Open(name string) (io.ReadCloser, error) {
+	if !backupNamePattern.MatchString(name) {
+		return nil, ErrBackupNotFound
+	}
+	if oss.Enabled() {
+		body, err := oss.GetObject(backupPrefix() + name)
+		if err != nil {
+			return nil, ErrBackupNotFound
+		}
+		return body, nil
+	}
+
+	file, err := os.Open(filepath.Join(backupDir(), name))
+	if os.IsNotExist(err) {
+		return nil, ErrBackupNotFound
+	}
+	return file, err
+}
+
+// Prune 删除超过保留天数的备份，最近 keep_last 份始终保留
+func (uc *backupUseCase) Prune() (int, error) {
+	days := config.AppConfig.Backup.RetentionDays
+	if days <= 0 {
+		return 0, nil
+	}
+	keep := config.AppConfig.Backup.KeepLast
+	if keep <= 0 {
+		keep = defaultBackupKeep
+	}
+
+	items, err := uc.List()
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -days)
+	removed := 0
+	for i, item := range items {
+		if i < keep || item.CreatedAt.After(cutoff) {
+			continue
+		}
+		if oss.Enabled() {
+			err = oss.DeleteFile(backupPrefix() + item.Name)
+		} else {
+			err = os.Remove(filepath.Join(backupDir(), item.Name))
+		}
+		if err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// ExportJSON 导出全部内容为单个 JSON 文档，结构与备份一致，可通过 ImportJSON 导入其他实例
+func (uc *backupUseCase) ExportJSON(w io.Writer) error {
+	return uc.writeBackup(&jsonBackupSink{w: w}, time.Now())
+}
+
+// writeBackup 写入备份内容：每类数据一个 JSON 数组，manifest 记录版本、数量和图片清单
+// 被引用的数据先写入，恢复时可按顺序建立ID映射
+func (uc *backupUseCase) writeBackup(sink backupSink, createdAt time.Time) error {
+	manifest := &backupManifest{
+		Version:   backupFormatVersion,
+		CreatedAt: createdAt,
+		Counts:    make(map[string]int),
+	}
+	db := uc.data.GetDB()
+
+	if err := uc.writeUsers(sink, manifest); err != nil {
+		return err
+	}
+
+	var tags []*po.Tag
+	if err := db.Order("id ASC").Find(&tags).Error; err != nil {
+		return fmt.Errorf("读取标签失败: %w", err)
+	}
+	var categories []*po.Category
+	if err := db.Order("id ASC").Find(&categories).Error; err != nil {
+		return fmt.Errorf("读取分类失败: %w", err)
+	}
+	var chapters []*po.Chapter
+	if err := db.Order("id ASC").Find(&chapters).Error; err != nil {
+		return fmt.Errorf("读取章节失败: %w", err)
+	}
+	var settings []*po.Setting
+	if err := db.Order("id ASC").Find(&settings).Error; err != nil {
+		return fmt.Errorf("读取设置失败: %w", err)
+	}
+	for _, entry := range []struct {
+		name  string
+		rows  interface{}
+		count int
+	}{
+		{"tags", tags, len(tags)},
+		{"categories", categories, len(categories)},
+		{"chapters", chapters, len(chapters)},
+		{"settings", settings, len(settings)},
+	} {
+		if err := writeBackupEntry(sink, entry.name, entry.rows); err != nil {
+			return err
+		}
+		manifest.Counts[entry.name] = entry.count
+	}
+
+	images, err := uc.writeArticles(sink, manifest)
+	if err != nil {
+		return err
+	}
+	manifest.Images = images
+
+	var authors []*po.ArticleAuthor
+	if err := db.Order("id ASC").Find(&authors).Error; err != nil {
+		return fmt.Errorf("读取共同作者失败: %w", err)
+	}
+	var series []*po.Series
+	if err := db.Order("id ASC").Find(&series).Error; err != nil {
+		return fmt.Errorf("读取系列失败: %w", err)
+	}
+	var seriesArticles []*po.SeriesArticle
+	if err := db.Order("series_id ASC, sort ASC").Find(&seriesArticles).Error; err != nil {
+		return fmt.Errorf("读取系列文章失败: %w", err)
+	}
+	for _, entry := range []struct {
+		name  string
+		rows  interface{}
+		count int
+	}{
+		{"article_authors", authors, len(authors)},
+		{"series", series, len(series)},
+		{"series_articles", seriesArticles, len(seriesArticles)},
+	} {
+		if err := writeBackupEntry(sink, entry.name, entry.rows); err != nil {
+			return err
+		}
+		manifest.Counts[entry.name] = entry.count
+	}
+
+	if err := uc.writeComments(sink, manifest); err != nil {
+		return err
+	}
+	for _, table := range []string{"likes", "favorites", "comment_likes"} {
+		if err := uc.writeLinks(sink, manifest, table); err != nil {
+			return err
+		}
+	}
+
+	if err := writeBackupEntry(sink, "manifest", manifest); err != nil {
+		return err
+	}
+	if err := sink.Close(); err != nil {
+		return fmt.Errorf("写入备份失败: %w", err)
+	}
+	return nil
+}
+
+// writeUsers 写入 users，包含密码哈希，导入后用户可用原密码登录
+func (uc *backupUseCase) writeUsers(sink backupSink, manifest *backupManifest) error {
+	array, err := newBackupArray(sink, "users")
+	if err != nil {
+		return err
+	}
+	var batch []*po.User
+	result := uc.data.GetDB().Order("id ASC").FindInBatches(&batch, backupBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, user := range batch {
+			item := backupUser{User: *user}
+			// 密码哈希只在启用字段加密时写入，且使用加密密钥加密，备份泄露时无法离线破解
+			if encrypt.Enabled() {
+				sealed, err := encrypt.Encrypt(user.Password)
+				if err != nil {
+					return fmt.Errorf("加密用户密码失败: %w", err)
+				}
+				item.Password = sealed
+			}
+			if err := array.Add(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if result.Error != nil {
+		return fmt.Errorf("读取用户失败: %w", result.Error)
+	}
+	if err := array.Close(); err != nil {
+		return err
+	}
+	manifest.Counts["users"] = array.count
+	return nil
+}
+
+// writeArticles 分批写入 articles，返回文章引用的图片清单
+func (uc *backupUseCase) writeArticles(sink backupSink, manifest *backupManifest) ([]backupImage, error) {
+	db := uc.data.GetDB()
+
+	var relations []struct {
+		ArticleID uint
+		TagID     uint
+	}
+	if err := db.Table("article_tags").Select("article_id, tag_id").Order("article_id ASC, tag_id ASC").Scan(&relations).Error; err != nil {
+		return nil, fmt.Errorf("读取文章标签失败: %w", err)
+	}
+	tagIDs := make(map[uint][]uint)
+	for _, rel := range relations {
+		tagIDs[rel.ArticleID] = append(tagIDs[rel.ArticleID], rel.TagID)
+	}
+
+	imageArticles := make(map[string][]uint)
+	var imageOrder []string
+	addImage := func(url string, articleID uint) {
+		if url == "" {
+			return
+		}
+		ids, ok := imageArticles[url]
+		if !ok {
+			imageOrder = append(imageOrder, url)
+		}
+		if len(ids) == 0 || ids[len(ids)-1] != articleID {
+			imageArticles[url] = append(ids, articleID)
+		}
+	}
+
+	array, err := newBackupArray(sink, "articles")
+	if err != nil {
+		return nil, err
+	}
+	var batch []*po.Article
+	result := db.Order("id ASC").FindInBatches(&batch, backupBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, article := range batch {
+			item := backupArticle{
+				Article:        *article,
+				AccessPassword: article.AccessPassword,
+				TagIDs:         tagIDs[article.ID],
+			}
+			if err := array.Add(item); err != nil {
+				return err
+			}
+			addImage(article.Cover, article.ID)
+			for _, match := range backupImagePattern.FindAllStringSubmatch(article.ContentMarkdown, -1) {
+				addImage(match[1], article.ID)
+			}
+		}
+		return nil
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("读取文章失败: %w", result.Error)
+	}
+	if err := array.Close(); err != nil {
+		return nil, err
+	}
+	manifest.Counts["articles"] = array.count
+
+	images := make([]backupImage, 0, len(imageOrder))
+	for _, url := range imageOrder {
+		images = append(images, backupImage{URL: url, ArticleIDs: imageArticles[url]})
+	}
+	return images, nil
+}
+
+// writeComments 分批写入 comments
+func (uc *backupUseCase) writeComments(sink backupSink, manifest *backupManifest) error {
+	array, err := newBackupArray(sink, "comments")
+	if err != nil {
+		return err
+	}
+	var batch []*po.Comment
+	result := uc.data.GetDB().Order("id ASC").FindInBatches(&batch, backupBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, comment := range batch {
+			if err := array.Add(backupComment{Comment: *comment}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if result.Error != nil {
+		return fmt.Errorf("读取评论失败: %w", result.Error)
+	}
+	if err := array.Close(); err != nil {
+		return err
+	}
+	manifest.Counts["comments"] = array.count
+	return nil
+}
+
+// writeLinks 逐行写入点赞、收藏等只有ID和时间的关联表
+func (uc *backupUseCase) writeLinks(sink backupSink, manifest *backupManifest, table string) error {
+	db := uc.data.GetDB()
+	rows, err := db.Table(table).Order("id ASC").Rows()
+	if err != nil {
+		return fmt.Errorf("读取 %s 失败: %w", table, err)
+	}
+	defer rows.Close()
+
+	array, err := newBackupArray(sink, table)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var link backupLink
+		if err := db.ScanRows(rows, &link); err != nil {
+			return fmt.Errorf("读取 %s 失败: %w", table, err)
+		}
+		if err := array.Add(link); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("读取 %s 失败: %w", table, err)
+	}
+	if err := array.Close(); err != nil {
+		return err
+	}
+	manifest.Counts[table] = array.count
+	return nil
+}
+
+// writeBackupEntry 写入一类数据
+func writeBackupEntry(sink backupSink, name string, v interface{}) error {
+	writer, err := sink.Create(name)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(writer).Encode(v)
+}
+
+// backupArray 逐条写入的 JSON 数组，数据量大的表不必整体加载到内存
+type backupArray struct {
+	w     io.Writer
+	count int
+}
+
+// newBackupArray 开始写入一类数据的 JSON 数组
+func newBackupArray(sink backupSink, name string) (*backupArray, error) {
+	writer, err := sink.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(writer, "["); err != nil {
+		return nil, err
+	}
+	return &backupArray{w: writer}, nil
+}
+
+// Add 追加一条记录
+func (a *backupArray) Add(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if a.count > 0 {
+		if _, err := io.WriteString(a.w, ",\n"); err != nil {
+			return err
+		}
+	}
+	a.count++
+	_, err = a.w.Write(data)
+	return err
+}
+
+// Close 结束数组
+func (a *backupArray) Close() error {
+	_, err := io.WriteString(a.w, "]\n")
+	return err
+}
+
+// backupSink 备份写入目标，每类数据以名称区分
+type backupSink interface {
+	// Create 开始写入一类数据，返回的 Writer 在下一次调用 Create 前有效
+	Create(name string) (io.Writer, error)
+	// Close 结束写入
+	Close() error
+}
+
+// zipBackupSink 备份压缩包，每类数据一个 JSON 文件
+type zipBackupSink struct {
+	w *zip.Writer
+}
+
+// Create 在压缩包中创建 {name}.json
+func (s *zipBackupSink) Create(name string) (io.Writer, error) {
+	return s.w.Create(name + ".json")
+}
+
+// Close 关闭压缩包
+func (s *zipBackupSink) Close() error {
+	return s.w.Close()
+}
+
+// jsonBackupSink 单个 JSON 文档，每类数据是顶层对象的一个字段
+type jsonBackupSink struct {
+	w     io.Writer
+	count int
+}
+
+// Create 写入字段名，字段值由调用方写入
+func (s *jsonBackupSink) Create(name string) (io.Writer, error) {
+	prefix := ",\n"
+	if s.count == 0 {
+		prefix = "{\n"
+	}
+	s.count++
+	if _, err := io.WriteString(s.w, prefix+strconv.Quote(name)+": "); err != nil {
+		return nil, err
+	}
+	return s.w, nil
+}
+
+// Close 结束顶层对象
+func (s *jsonBackupSink) Close() error {
+	suffix := "}\n"
+	if s.count == 0 {
+		suffix = "{}\n"
+	}
+	_, err := io.WriteString(s.w, suffix)
+	return err
+}
+
+// backupDir 本地备份目录，也用于存放生成中的临时文件
+func backupDir() string {
+	if dir := config.AppConfig.Backup.Dir; dir != "" {
+		return dir
+	}
+	return defaultBackupDir
+}
+
+// backupPrefix OSS 中的备份路径前缀
+func backupPrefix() string {
+	prefix := config.AppConfig.Backup.Prefix
+	if prefix == "" {
+		return defaultBackupPrefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/"
+}
+
+// backupLocation 备份存放位置
+func backupLocation() string {
+	if oss.Enabled() {
+		return "oss"
+	}
+	return "local"
+}
@@ -0,0 +1,1047 @@
+package biz
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/encrypt"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 恢复时的记录变更
+const (
+	restoreCreated = "created"
+	restoreUpdated = "updated"
+	restoreSkipped = "skipped"
+)
+
+var (
+	// ErrBackupInvalid 备份文件无效
+	ErrBackupInvalid = errors.New("备份文件无效")
+	// ErrBackupUnsupported 备份格式版本高于当前程序支持的版本
+	ErrBackupUnsupported = errors.New("备份格式版本过高，请升级程序后再恢复")
+	// errRestoreDryRun dry-run 结束时回滚事务
+	errRestoreDryRun = errors.New("restore dry run")
+)
+
+// Restore 从备份压缩包恢复数据
+func (uc *backupUseCase) Restore(r io.ReaderAt, size int64, dryRun bool) (*dto.RestoreReport, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, ErrBackupInvalid
+	}
+	source := make(zipBackupSource, len(archive.File))
+	for _, file := range archive.File {
+		source[strings.TrimSuffix(file.Name, ".json")] = file
+	}
+	return uc.restore(source, dryRun)
+}
+
+// ImportJSON 导入 ExportJSON 生成的文档
+func (uc *backupUseCase) ImportJSON(r io.Reader, dryRun bool) (*dto.RestoreReport, error) {
+	var source jsonBackupSource
+	if err := json.NewDecoder(r).Decode(&source); err != nil {
+		return nil, ErrBackupInvalid
+	}
+	return uc.restore(source, dryRun)
+}
+
+// restore 恢复数据
+// 所有写入在一个事务中完成：记录按名称、别名等业务键与现有数据对应，对应不上的新建并重新分配ID，
+// 关联关系按新旧ID映射改写。dry-run 同样执行全部写入后回滚，返回的变更与实际恢复一致
+func (uc *backupUseCase) restore(source backupSource, dryRun bool) (*dto.RestoreReport, error) {
+	var manifest backupManifest
+	if err := readBackupEntry(source, "manifest", &manifest); err != nil {
+		return nil, ErrBackupInvalid
+	}
+	if manifest.Version < 1 {
+		return nil, ErrBackupInvalid
+	}
+	if manifest.Version > backupFormatVersion {
+		return nil, ErrBackupUnsupported
+	}
+
+	report := &dto.RestoreReport{
+		DryRun:          dryRun,
+		Version:         manifest.Version,
+		BackupCreatedAt: manifest.CreatedAt,
+	}
+	err := uc.data.GetDB().Transaction(func(tx *gorm.DB) error {
+		restorer := newBackupRestorer(tx, source, report)
+		if err := restorer.run(); err != nil {
+			return err
+		}
+		if dryRun {
+			return errRestoreDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRestoreDryRun) {
+		return nil, err
+	}
+	// 设置表在事务中直接写入，需要清空设置缓存
+	if !dryRun {
+		data.InvalidateSettings()
+	}
+	return report, nil
+}
+
+// RestoreByName 从已保存的备份恢复，OSS 中的备份先下载到临时文件
+func (uc *backupUseCase) RestoreByName(name string, dryRun bool) (*dto.RestoreReport, error) {
+	body, err := uc.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	file, ok := body.(*os.File)
+	if !ok {
+		tmp, err := os.CreateTemp("", "leaf-restore-*.zip")
+		if err != nil {
+			return nil, fmt.Errorf("创建临时文件失败: %w", err)
+		}
+		defer os.Remove(tmp.Name())
+		defer tmp.Close()
+		if _, err := io.Copy(tmp, body); err != nil {
+			return nil, fmt.Errorf("下载备份失败: %w", err)
+		}
+		file = tmp
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return uc.Restore(file, info.Size(), dryRun)
+}
+
+// backupRestorer 一次恢复的状态，各 map 记录备份ID到当前ID的映射
+type backupRestorer struct {
+	tx       *gorm.DB
+	source   backupSource
+	report   *dto.RestoreReport
+	entities map[string]*dto.RestoreEntityReport
+
+	tags       map[uint]uint
+	categories map[uint]uint
+	chapters   map[uint]uint
+	articles   map[uint]uint
+	comments   map[uint]uint
+	series     map[uint]uint
+	users      map[uint]uint // 备份包含用户时按用户名映射，否则缓存当前数据库中是否存在该ID
+
+	hasUsers      bool // 备份是否包含用户（格式版本 2 起）
+	defaultAuthor uint // 原作者不存在时文章归属的管理员
+}
+
+// newBackupRestorer 创建恢复状态
+func newBackupRestorer(tx *gorm.DB, source backupSource, report *dto.RestoreReport) *backupRestorer {
+	return &backupRestorer{
+		tx:         tx,
+		source:     source,
+		report:     report,
+		entities:   make(map[string]*dto.RestoreEntityReport),
+		tags:       make(map[uint]uint),
+		categories: make(map[uint]uint),
+		chapters:   make(map[uint]uint),
+		articles:   make(map[uint]uint),
+		comments:   make(map[uint]uint),
+		series:     make(map[uint]uint),
+		users:      make(map[uint]uint),
+	}
+}
+
+// run 按依赖顺序恢复，被引用的数据先恢复
+func (rs *backupRestorer) run() error {
+	for _, step := range []func() error{
+		rs.restoreUsers,
+		rs.restoreTags,
+		rs.restoreCategories,
+		rs.restoreChapters,
+		rs.restoreSettings,
+		rs.restoreArticles,
+		rs.restoreArticleAuthors,
+		rs.restoreSeries,
+		rs.restoreSeriesArticles,
+		rs.restoreComments,
+		func() error { return rs.restoreLinks("likes") },
+		func() error { return rs.restoreLinks("favorites") },
+		func() error { return rs.restoreLinks("comment_likes") },
+	} {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreUsers 按用户名对应用户
+// 已存在的用户只建立ID映射，不覆盖其资料、密码和角色
+// 新建的用户使用备份中加密的密码哈希，备份不含密码或无法解密时设置随机密码，需通过找回密码重新设置
+func (rs *backupRestorer) restoreUsers() error {
+	if !rs.has("users") {
+		return nil
+	}
+	rs.hasUsers = true
+	entity := rs.entity("users")
+
+	return decodeBackupArray(rs.source, "users", func(dec *json.Decoder) error {
+		var item backupUser
+		if err := dec.Decode(&item); err != nil {
+			return err
+		}
+		user := item.User
+		backupID := user.ID
+
+		var existing po.User
+		err := rs.tx.Unscoped().Where("username = ?", user.Username).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			password, reason, err := restoredPassword(item.Password)
+			if err != nil {
+				return fmt.Errorf("恢复用户 %s 失败: %w", user.Username, err)
+			}
+			user.ID = 0
+			user.Password = password
+			user.DeletedAt = gorm.DeletedAt{}
+			// 写入全部字段，避免状态等零值被数据库默认值替换
+			if err := rs.tx.Select("*").Omit("ID").Create(&user).Error; err != nil {
+				return fmt.Errorf("恢复用户 %s 失败: %w", user.Username, err)
+			}
+			rs.users[backupID] = user.ID
+			rs.record(entity, restoreCreated, backupID, user.ID, user.Username, reason)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		rs.users[backupID] = existing.ID
+		entity.Unchanged++
+		return nil
+	})
+}
+
+// restoredPassword 解密备份中的密码哈希，备份不含密码或无法解密时返回随机密码的哈希和原因
+func restoredPassword(sealed string) (string, string, error) {
+	if sealed != "" {
+		// 早期备份中的密码哈希未加密，解密时原样返回
+		if hash, err := encrypt.Decrypt(sealed); err == nil && hash != "" {
+			return hash, "", nil
+		}
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return "", "", err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return "", "", err
+	}
+	return string(hash), "备份中没有可用的密码，需通过找回密码重新设置", nil
+}
+
+// restoreTags 按名称对应标签
+func (rs *backupRestorer) restoreTags() error {
+	var tags []*po.Tag
+	if err := readBackupEntry(rs.source, "tags", &tags); err != nil {
+		return err
+	}
+	entity := rs.entity("tags")
+
+	for _, tag := range tags {
+		var existing po.Tag
+		err := rs.tx.Unscoped().Where("name = ?", tag.Name).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			created := &po.Tag{Name: tag.Name, Color: tag.Color, CreatedAt: tag.CreatedAt, UpdatedAt: tag.UpdatedAt}
+			if err := rs.tx.Create(created).Error; err != nil {
+				return fmt.Errorf("恢复标签 %s 失败: %w", tag.Name, err)
+			}
+			rs.tags[tag.ID] = created.ID
+			rs.record(entity, restoreCreated, tag.ID, created.ID, tag.Name, "")
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		rs.tags[tag.ID] = existing.ID
+		if existing.Color == tag.Color && !existing.DeletedAt.Valid {
+			entity.Unchanged++
+			continue
+		}
+		err = rs.tx.Unscoped().Model(&po.Tag{}).Where("id = ?", existing.ID).Updates(map[string]interface{}{
+			"color":      tag.Color,
+			"deleted_at": nil,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("恢复标签 %s 失败: %w", tag.Name, err)
+		}
+		rs.record(entity, restoreUpdated, tag.ID, existing.ID, tag.Name, "")
+	}
+	return nil
+}
+
+// restoreCategories 按名称对应分类
+func (rs *backupRestorer) restoreCategories() error {
+	var categories []*po.Category
+	if err := readBackupEntry(rs.source, "categories", &categories); err != nil {
+		return err
+	}
+	entity := rs.entity("categories")
+
+	for _, category := range categories {
+		var existing po.Category
+		err := rs.tx.Unscoped().Where("name = ?", category.Name).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			created := &po.Category{
+				Name:        category.Name,
+				Description: category.Description,
+				Sort:        category.Sort,
+				CreatedAt:   category.CreatedAt,
+				UpdatedAt:   category.UpdatedAt,
+			}
+			if err := rs.tx.Create(created).Error; err != nil {
+				return fmt.Errorf("恢复分类 %s 失败: %w", category.Name, err)
+			}
+			rs.categories[category.ID] = created.ID
+			rs.record(entity, restoreCreated, category.ID, created.ID, category.Name, "")
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		rs.categories[category.ID] = existing.ID
+		if existing.Description == category.Description && existing.Sort == category.Sort && !existing.DeletedAt.Valid {
+			entity.Unchanged++
+			continue
+		}
+		err = rs.tx.Unscoped().Model(&po.Category{}).Where("id = ?", existing.ID).Updates(map[string]interface{}{
+			"description": category.Description,
+			"sort":        category.Sort,
+			"deleted_at":  nil,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("恢复分类 %s 失败: %w", category.Name, err)
+		}
+		rs.record(entity, restoreUpdated, category.ID, existing.ID, category.Name, "")
+	}
+	return nil
+}
+
+// restoreChapters 按标签、父章节和名称对应章节，父章节先于子章节恢复
+func (rs *backupRestorer) restoreChapters() error {
+	var chapters []*po.Chapter
+	if err := readBackupEntry(rs.source, "chapters", &chapters); err != nil {
+		return err
+	}
+	entity := rs.entity("chapters")
+
+	pending := chapters
+	for len(pending) > 0 {
+		var next []*po.Chapter
+		for _, chapter := range pending {
+			if chapter.ParentID != nil {
+				if _, ok := rs.chapters[*chapter.ParentID]; !ok {
+					next = append(next, chapter)
+					continue
+				}
+			}
+			if err := rs.restoreChapter(entity, chapter); err != nil {
+				return err
+			}
+		}
+		if len(next) == len(pending) {
+			// 剩余章节的父章节不在备份中或未能恢复
+			for _, chapter := range next {
+				rs.record(entity, restoreSkipped, chapter.ID, 0, chapter.Name, "父章节不存在")
+			}
+			break
+		}
+		pending = next
+	}
+	return nil
+}
+
+// restoreChapter 恢复单个章节，调用前父章节已完成映射
+func (rs *backupRestorer) restoreChapter(entity *dto.RestoreEntityReport, chapter *po.Chapter) error {
+	tagID, ok := rs.tags[chapter.TagID]
+	if !ok {
+		rs.record(entity, restoreSkipped, chapter.ID, 0, chapter.Name, "标签不存在")
+		// 记为已处理，避免子章节一直等待
+		rs.chapters[chapter.ID] = 0
+		return nil
+	}
+	var parentID *uint
+	if chapter.ParentID != nil {
+		id := rs.chapters[*chapter.ParentID]
+		if id == 0 {
+			rs.record(entity, restoreSkipped, chapter.ID, 0, chapter.Name, "父章节未恢复")
+			rs.chapters[chapter.ID] = 0
+			return nil
+		}
+		parentID = &id
+	}
+
+	query := rs.tx.Where("tag_id = ? AND name = ?", tagID, chapter.Name)
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+	var existing po.Chapter
+	err := query.First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		created := &po.Chapter{
+			TagID:     tagID,
+			ParentID:  parentID,
+			Name:      chapter.Name,
+			Sort:      chapter.Sort,
+			CreatedAt: chapter.CreatedAt,
+			UpdatedAt: chapter.UpdatedAt,
+		}
+		if err := rs.tx.Create(created).Error; err != nil {
+			return fmt.Errorf("恢复章节 %s 失败: %w", chapter.Name, err)
+		}
+		rs.chapters[chapter.ID] = created.ID
+		rs.record(entity, restoreCreated, chapter.ID, created.ID, chapter.Name, "")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	rs.chapters[chapter.ID] = existing.ID
+	if existing.Sort == chapter.Sort {
+		entity.Unchanged++
+		return nil
+	}
+	if err := rs.tx.Model(&po.Chapter{}).Where("id = ?", existing.ID).Update("sort", chapter.Sort).Error; err != nil {
+		return fmt.Errorf("恢复章节 %s 失败: %w", chapter.Name, err)
+	}
+	rs.record(entity, restoreUpdated, chapter.ID, existing.ID, chapter.Name, "")
+	return nil
+}
+
+// restoreSettings 按键对应设置
+func (rs *backupRestorer) restoreSettings() error {
+	var settings []*po.Setting
+	if err := readBackupEntry(rs.source, "settings", &settings); err != nil {
+		return err
+	}
+	entity := rs.entity("settings")
+
+	for _, setting := range settings {
+		var existing po.Setting
+		err := rs.tx.Where("`key` = ?", setting.Key).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			created := &po.Setting{Key: setting.Key, Value: setting.Value, UpdatedAt: setting.UpdatedAt}
+			if err := rs.tx.Create(created).Error; err != nil {
+				return fmt.Errorf("恢复设置 %s 失败: %w", setting.Key, err)
+			}
+			rs.record(entity, restoreCreated, setting.ID, created.ID, setting.Key, "")
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if existing.Value == setting.Value {
+			entity.Unchanged++
+			continue
+		}
+		if err := rs.tx.Model(&po.Setting{}).Where("id = ?", existing.ID).Update("value", setting.Value).Error; err != nil {
+			return fmt.Errorf("恢复设置 %s 失败: %w", setting.Key, err)
+		}
+		rs.record(entity, restoreUpdated, setting.ID, existing.ID, setting.Key, "")
+	}
+	return nil
+}
+
+// restoreArticles 按别名（没有别名时按标题和创建时间）对应文章
+// 已存在的文章只在备份中的版本更新时覆盖，已删除的文章会被还原
+func (rs *backupRestorer) restoreArticles() error {
+	entity := rs.entity("articles")
+
+	return decodeBackupArray(rs.source, "articles", func(dec *json.Decoder) error {
+		var item backupArticle
+		if err := dec.Decode(&item); err != nil {
+			return err
+		}
+		article := item.Article
+		backupID := article.ID
+
+		authorID, err := rs.resolveAuthor(article.AuthorID)
+		if err != nil {
+			return err
+		}
+		if authorID == 0 {
+			rs.record(entity, restoreSkipped, backupID, 0, article.Title, "作者不存在且没有可用的管理员")
+			return nil
+		}
+		article.AuthorID = authorID
+		article.CategoryID = rs.categories[article.CategoryID]
+		if article.ChapterID != nil {
+			if id := rs.chapters[*article.ChapterID]; id != 0 {
+				article.ChapterID = &id
+			} else {
+				article.ChapterID = nil
+			}
+		}
+		article.AccessPassword = item.AccessPassword
+
+		query := rs.tx.Unscoped()
+		if article.Slug != nil && *article.Slug != "" {
+			query = query.Where("slug = ?", *article.Slug)
+		} else {
+			query = query.Where("title = ? AND created_at = ?", article.Title, article.CreatedAt)
+		}
+		var existing po.Article
+		err = query.First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			article.ID = 0
+			article.DeletedAt = gorm.DeletedAt{}
+			if err := rs.tx.Omit(clause.Associations).Create(&article).Error; err != nil {
+				return fmt.Errorf("恢复文章 %s 失败: %w", article.Title, err)
+			}
+			rs.articles[backupID] = article.ID
+			if err := rs.replaceArticleTags(article.ID, item.TagIDs); err != nil {
+				return err
+			}
+			rs.record(entity, restoreCreated, backupID, article.ID, article.Title, "")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		rs.articles[backupID] = existing.ID
+		if !item.UpdatedAt.After(existing.UpdatedAt) && !existing.DeletedAt.Valid {
+			entity.Unchanged++
+			return nil
+		}
+		err = rs.tx.Unscoped().Model(&po.Article{}).Where("id = ?", existing.ID).UpdateColumns(map[string]interface{}{
+			"title":            article.Title,
+			"slug":             article.Slug,
+			"content_markdown": article.ContentMarkdown,
+			"content_html":     article.ContentHTML,
+			"summary":          article.Summary,
+			"cover":            article.Cover,
+			"author_id":        article.AuthorID,
+			"category_id":      article.CategoryID,
+			"chapter_id":       article.ChapterID,
+			"status":           article.Status,
+			"is_top":           article.IsTop,
+			"pinned_sort":      article.PinnedSort,
+			"visibility":       article.Visibility,
+			"access_password":  article.AccessPassword,
+			"word_count":       article.WordCount,
+			"reading_time":     article.ReadingTime,
+			"revision":         article.Revision,
+			"meta_description": article.MetaDescription,
+			"meta_keywords":    article.MetaKeywords,
+			"canonical_url":    article.CanonicalURL,
+			"no_index":         article.NoIndex,
+			"view_count":       article.ViewCount,
+			"like_count":       article.LikeCount,
+			"favorite_count":   article.FavoriteCount,
+			"comment_count":    article.CommentCount,
+			"updated_at":       article.UpdatedAt,
+			"deleted_at":       nil,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("恢复文章 %s 失败: %w", article.Title, err)
+		}
+		if err := rs.replaceArticleTags(existing.ID, item.TagIDs); err != nil {
+			return err
+		}
+		rs.record(entity, restoreUpdated, backupID, existing.ID, article.Title, "")
+		return nil
+	})
+}
+
+// replaceArticleTags 按映射后的标签ID重建文章标签关系
+func (rs *backupRestorer) replaceArticleTags(articleID uint, backupTagIDs []uint) error {
+	if err := rs.tx.Exec("DELETE FROM article_tags WHERE article_id = ?", articleID).Error; err != nil {
+		return err
+	}
+	for _, backupTagID := range backupTagIDs {
+		tagID, ok := rs.tags[backupTagID]
+		if !ok {
+			continue
+		}
+		if err := rs.tx.Exec("INSERT INTO article_tags (article_id, tag_id) VALUES (?, ?)", articleID, tagID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreArticleAuthors 恢复共同作者及署名顺序
+func (rs *backupRestorer) restoreArticleAuthors() error {
+	if !rs.has("article_authors") {
+		return nil
+	}
+	var authors []*po.ArticleAuthor
+	if err := readBackupEntry(rs.source, "article_authors", &authors); err != nil {
+		return err
+	}
+	entity := rs.entity("article_authors")
+
+	for _, author := range authors {
+		name := fmt.Sprintf("文章 #%d 作者 #%d", author.ArticleID, author.UserID)
+		articleID, ok := rs.articles[author.ArticleID]
+		userID, err := rs.mapUser(author.UserID)
+		if err != nil {
+			return err
+		}
+		if !ok || userID == 0 {
+			rs.record(entity, restoreSkipped, author.ID, 0, name, "文章或用户未恢复")
+			continue
+		}
+
+		var existing po.ArticleAuthor
+		err = rs.tx.Where("article_id = ? AND user_id = ?", articleID, userID).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			created := &po.ArticleAuthor{
+				ArticleID: articleID,
+				UserID:    userID,
+				Role:      author.Role,
+				Sort:      author.Sort,
+				CreatedAt: author.CreatedAt,
+			}
+			if err := rs.tx.Create(created).Error; err != nil {
+				return fmt.Errorf("恢复共同作者失败: %w", err)
+			}
+			rs.record(entity, restoreCreated, author.ID, created.ID, name, "")
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if existing.Role == author.Role && existing.Sort == author.Sort {
+			entity.Unchanged++
+			continue
+		}
+		err = rs.tx.Model(&po.ArticleAuthor{}).Where("id = ?", existing.ID).Updates(map[string]interface{}{
+			"role": author.Role,
+			"sort": author.Sort,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("恢复共同作者失败: %w", err)
+		}
+		rs.record(entity, restoreUpdated, author.ID, existing.ID, name, "")
+	}
+	return nil
+}
+
+// restoreSeries 按标题对应系列
+func (rs *backupRestorer) restoreSeries() error {
+	if !rs.has("series") {
+		return nil
+	}
+	var series []*po.Series
+	if err := readBackupEntry(rs.source, "series", &series); err != nil {
+		return err
+	}
+	entity := rs.entity("series")
+
+	for _, item := range series {
+		var existing po.Series
+		err := rs.tx.Where("title = ?", item.Title).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			created := &po.Series{
+				Title:       item.Title,
+				Description: item.Description,
+				Cover:       item.Cover,
+				Sort:        item.Sort,
+				CreatedAt:   item.CreatedAt,
+				UpdatedAt:   item.UpdatedAt,
+			}
+			if err := rs.tx.Create(created).Error; err != nil {
+				return fmt.Errorf("恢复系列 %s 失败: %w", item.Title, err)
+			}
+			rs.series[item.ID] = created.ID
+			rs.record(entity, restoreCreated, item.ID, created.ID, item.Title, "")
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		rs.series[item.ID] = existing.ID
+		if existing.Description == item.Description && existing.Cover == item.Cover && existing.Sort == item.Sort {
+			entity.Unchanged++
+			continue
+		}
+		err = rs.tx.Model(&po.Series{}).Where("id = ?", existing.ID).Updates(map[string]interface{}{
+			"description": item.Description,
+			"cover":       item.Cover,
+			"sort":        item.Sort,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("恢复系列 %s 失败: %w", item.Title, err)
+		}
+		rs.record(entity, restoreUpdated, item.ID, existing.ID, item.Title, "")
+	}
+	return nil
+}
+
+// restoreSeriesArticles 恢复系列中的文章及顺序
+func (rs *backupRestorer) restoreSeriesArticles() error {
+	if !rs.has("series_articles") {
+		return nil
+	}
+	var items []*po.SeriesArticle
+	if err := readBackupEntry(rs.source, "series_articles", &items); err != nil {
+		return err
+	}
+	entity := rs.entity("series_articles")
+
+	for _, item := range items {
+		name := fmt.Sprintf("系列 #%d 文章 #%d", item.SeriesID, item.ArticleID)
+		seriesID, seriesOK := rs.series[item.SeriesID]
+		articleID, articleOK := rs.articles[item.ArticleID]
+		if !seriesOK || !articleOK {
+			rs.record(entity, restoreSkipped, item.ID, 0, name, "系列或文章未恢复")
+			continue
+		}
+
+		var existing po.SeriesArticle
+		err := rs.tx.Where("series_id = ? AND article_id = ?", seriesID, articleID).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			created := &po.SeriesArticle{SeriesID: seriesID, ArticleID: articleID, Sort: item.Sort}
+			if err := rs.tx.Create(created).Error; err != nil {
+				return fmt.Errorf("恢复系列文章失败: %w", err)
+			}
+			rs.record(entity, restoreCreated, item.ID, created.ID, name, "")
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if existing.Sort == item.Sort {
+			entity.Unchanged++
+			continue
+		}
+		if err := rs.tx.Model(&po.SeriesArticle{}).Where("id = ?", existing.ID).Update("sort", item.Sort).Error; err != nil {
+			return fmt.Errorf("恢复系列文章失败: %w", err)
+		}
+		rs.record(entity, restoreUpdated, item.ID, existing.ID, name, "")
+	}
+	return nil
+}
+
+// restoreComments 按文章、用户和创建时间对应评论，父评论先于回复恢复
+// 用户不在备份中，用户已不存在的评论跳过
+func (rs *backupRestorer) restoreComments() error {
+	entity := rs.entity("comments")
+
+	return decodeBackupArray(rs.source, "comments", func(dec *json.Decoder) error {
+		var item backupComment
+		if err := dec.Decode(&item); err != nil {
+			return err
+		}
+		comment := item.Comment
+		backupID := comment.ID
+		name := backupCommentName(comment.Content)
+
+		if comment.ArticleID != nil {
+			id, ok := rs.articles[*comment.ArticleID]
+			if !ok {
+				rs.record(entity, restoreSkipped, backupID, 0, name, "文章未恢复")
+				return nil
+			}
+			comment.ArticleID = &id
+		}
+		userID, err := rs.mapUser(comment.UserID)
+		if err != nil {
+			return err
+		}
+		if userID == 0 {
+			rs.record(entity, restoreSkipped, backupID, 0, name, "用户不存在")
+			return nil
+		}
+		comment.UserID = userID
+		if comment.ParentID != nil {
+			id, ok := rs.comments[*comment.ParentID]
+			if !ok {
+				rs.record(entity, restoreSkipped, backupID, 0, name, "父评论未恢复")
+				return nil
+			}
+			comment.ParentID = &id
+		}
+		if comment.ReplyToUserID != nil {
+			replyToUserID, err := rs.mapUser(*comment.ReplyToUserID)
+			if err != nil {
+				return err
+			}
+			if replyToUserID == 0 {
+				comment.ReplyToUserID = nil
+			} else {
+				comment.ReplyToUserID = &replyToUserID
+			}
+		}
+
+		query := rs.tx.Unscoped().Where("user_id = ? AND created_at = ?", comment.UserID, comment.CreatedAt)
+		if comment.ArticleID == nil {
+			query = query.Where("article_id IS NULL")
+		} else {
+			query = query.Where("article_id = ?", *comment.ArticleID)
+		}
+		var existing po.Comment
+		err = query.First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			comment.ID = 0
+			comment.DeletedAt = gorm.DeletedAt{}
+			if err := rs.tx.Omit(clause.Associations).Create(&comment).Error; err != nil {
+				return fmt.Errorf("恢复评论 %d 失败: %w", backupID, err)
+			}
+			rs.comments[backupID] = comment.ID
+			rs.record(entity, restoreCreated, backupID, comment.ID, name, "")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		rs.comments[backupID] = existing.ID
+		if !comment.UpdatedAt.After(existing.UpdatedAt) && !existing.DeletedAt.Valid {
+			entity.Unchanged++
+			return nil
+		}
+		err = rs.tx.Unscoped().Model(&po.Comment{}).Where("id = ?", existing.ID).UpdateColumns(map[string]interface{}{
+			"parent_id":        comment.ParentID,
+			"reply_to_user_id": comment.ReplyToUserID,
+			"content":          comment.Content,
+			"like_count":       comment.LikeCount,
+			"status":           comment.Status,
+			"updated_at":       comment.UpdatedAt,
+			"deleted_at":       nil,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("恢复评论 %d 失败: %w", backupID, err)
+		}
+		rs.record(entity, restoreUpdated, backupID, existing.ID, name, "")
+		return nil
+	})
+}
+
+// restoreLinks 恢复点赞、收藏或评论点赞，已存在的记录不重复添加
+// 文章和评论上的计数随文章、评论一同恢复，这里不再累加
+func (rs *backupRestorer) restoreLinks(table string) error {
+	if !rs.has(table) {
+		return nil
+	}
+	entity := rs.entity(table)
+
+	return decodeBackupArray(rs.source, table, func(dec *json.Decoder) error {
+		var link backupLink
+		if err := dec.Decode(&link); err != nil {
+			return err
+		}
+
+		column, backupTargetID := "article_id", link.ArticleID
+		targetID, ok := rs.articles[link.ArticleID]
+		if table == "comment_likes" {
+			column, backupTargetID = "comment_id", link.CommentID
+			targetID, ok = rs.comments[link.CommentID]
+		}
+		userID, err := rs.mapUser(link.UserID)
+		if err != nil {
+			return err
+		}
+		name := fmt.Sprintf("用户 #%d %s #%d", link.UserID, column, backupTargetID)
+		if !ok || userID == 0 {
+			rs.record(entity, restoreSkipped, link.ID, 0, name, "文章、评论或用户未恢复")
+			return nil
+		}
+
+		var count int64
+		if err := rs.tx.Table(table).Where(column+" = ? AND user_id = ?", targetID, userID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			entity.Unchanged++
+			return nil
+		}
+		err = rs.tx.Table(table).Create(map[string]interface{}{
+			column:       targetID,
+			"user_id":    userID,
+			"created_at": link.CreatedAt,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("恢复 %s 失败: %w", table, err)
+		}
+		rs.record(entity, restoreCreated, link.ID, 0, name, "")
+		return nil
+	})
+}
+
+// resolveAuthor 原作者存在时映射到对应用户，否则归属第一个管理员，都没有时返回 0
+func (rs *backupRestorer) resolveAuthor(authorID uint) (uint, error) {
+	userID, err := rs.mapUser(authorID)
+	if err != nil || userID != 0 {
+		return userID, err
+	}
+
+	if rs.defaultAuthor == 0 {
+		var admin po.User
+		err := rs.tx.Where("role IN ?", []string{"super_admin", "admin"}).Order("id ASC").First(&admin).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		rs.defaultAuthor = admin.ID
+	}
+	return rs.defaultAuthor, nil
+}
+
+// mapUser 将备份中的用户ID映射为当前ID，用户不存在时返回 0
+// 旧版备份不含用户，按原ID查询当前数据库，结果在本次恢复中缓存
+func (rs *backupRestorer) mapUser(id uint) (uint, error) {
+	if id == 0 {
+		return 0, nil
+	}
+	if userID, ok := rs.users[id]; ok || rs.hasUsers {
+		return userID, nil
+	}
+	var count int64
+	if err := rs.tx.Model(&po.User{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		rs.users[id] = 0
+		return 0, nil
+	}
+	rs.users[id] = id
+	return id, nil
+}
+
+// has 备份中是否包含某类数据
+func (rs *backupRestorer) has(name string) bool {
+	reader, err := rs.source.Open(name)
+	if err != nil || reader == nil {
+		return false
+	}
+	reader.Close()
+	return true
+}
+
+// entity 获取某类数据的恢复结果，首次获取时按顺序加入报告
+func (rs *backupRestorer) entity(name string) *dto.RestoreEntityReport {
+	if entity, ok := rs.entities[name]; ok {
+		return entity
+	}
+	entity := &dto.RestoreEntityReport{Entity: name, Changes: []*dto.RestoreChange{}}
+	rs.entities[name] = entity
+	rs.report.Entities = append(rs.report.Entities, entity)
+	return entity
+}
+
+// record 记录一条变更
+func (rs *backupRestorer) record(entity *dto.RestoreEntityReport, action string, backupID, id uint, name, reason string) {
+	switch action {
+	case restoreCreated:
+		entity.Created++
+	case restoreUpdated:
+		entity.Updated++
+	case restoreSkipped:
+		entity.Skipped++
+	}
+	entity.Changes = append(entity.Changes, &dto.RestoreChange{
+		Action:   action,
+		BackupID: backupID,
+		ID:       id,
+		Name:     name,
+		Reason:   reason,
+	})
+}
+
+// backupSource 备份读取来源，每类数据以名称区分
+type backupSource interface {
+	// Open 读取一类数据，不存在时返回 nil
+	Open(name string) (io.ReadCloser, error)
+}
+
+// zipBackupSource 备份压缩包，名称到 {name}.json 文件
+type zipBackupSource map[string]*zip.File
+
+// Open 打开压缩包中的文件
+func (s zipBackupSource) Open(name string) (io.ReadCloser, error) {
+	file, ok := s[name]
+	if !ok {
+		return nil, nil
+	}
+	return file.Open()
+}
+
+// jsonBackupSource 单个 JSON 文档的顶层字段
+type jsonBackupSource map[string]json.RawMessage
+
+// Open 读取字段值
+func (s jsonBackupSource) Open(name string) (io.ReadCloser, error) {
+	raw, ok := s[name]
+	if !ok {
+		return nil, nil
+	}
+	return io.NopCloser(bytes.NewReader(raw)), nil
+}
+
+// readBackupEntry 读取一类数据，不存在时保持 v 不变
+func readBackupEntry(source backupSource, name string, v interface{}) error {
+	reader, err := source.Open(name)
+	if err != nil || reader == nil {
+		return err
+	}
+	defer reader.Close()
+	if err := json.NewDecoder(reader).Decode(v); err != nil {
+		return fmt.Errorf("解析 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// decodeBackupArray 逐条读取一类数据的 JSON 数组，每条记录调用一次 fn
+func decodeBackupArray(source backupSource, name string, fn func(dec *json.Decoder) error) error {
+	reader, err := source.Open(name)
+	if err != nil || reader == nil {
+		return err
+	}
+	defer reader.Close()
+
+	dec := json.NewDecoder(reader)
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("解析 %s 失败: %w", name, err)
+	}
+	for dec.More() {
+		if err := fn(dec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backupCommentName 截取评论内容作为报告中的名称
+func backupCommentName(content string) string {
+	runes := []rune(content)
+	if len(runes) > 30 {
+		return string(runes[:30]) + "..."
+	}
+	return content
+}
@@ -0,0 +1,211 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+var (
+	// ErrBannerNotFound 横幅不存在
+	ErrBannerNotFound = errors.New("横幅不存在")
+	// ErrBannerInvalid 横幅参数不合法
+	ErrBannerInvalid = errors.New("横幅参数不合法")
+)
+
+// BannerUseCase 首页横幅业务用例接口
+type BannerUseCase interface {
+	// Active 前台查询正在展示的横幅
+	Active() ([]*dto.BannerResponse, error)
+	// List 后台分页查询横幅
+	List(req *dto.BannerListRequest) (*dto.PageResponse, error)
+	// Create 创建横幅，图片需先通过文件上传接口上传
+	Create(req *dto.BannerRequest) (*dto.BannerResponse, error)
+	// Update 更新横幅
+	Update(id uint, req *dto.BannerRequest) (*dto.BannerResponse, error)
+	// Delete 删除横幅，图片由未引用图片清理任务回收
+	Delete(id uint) error
+}
+
+// bannerUseCase 首页横幅业务用例实现
+type bannerUseCase struct {
+	data *data.Data
+}
+
+// NewBannerUseCase 创建首页横幅业务用例
+func NewBannerUseCase(d *data.Data) BannerUseCase {
+	return &bannerUseCase{data: d}
+}
+
+// Active 前台查询正在展示的横幅
+func (uc *bannerUseCase) Active() ([]*dto.BannerResponse, error) {
+	banners, err := uc.data.BannerRepo.ListActive(time.Now())
+	if err != nil {
+		return nil, errors.New("查询横幅失败")
+	}
+	return uc.convert(banners)
+}
+
+// List 后台分页查询横幅
+func (uc *bannerUseCase) List(req *dto.BannerListRequest) (*dto.PageResponse, error) {
+	banners, total, err := uc.data.BannerRepo.List(req.Page, req.Limit, req.Status, req.Keyword, time.Now())
+	if err != nil {
+		return nil, errors.New("查询横幅列表失败")
+	}
+	items, err := uc.convert(banners)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PageResponse{
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Data:  items,
+	}, nil
+}
+
+// Create 创建横幅，未指定时默认启用
+func (uc *bannerUseCase) Create(req *dto.BannerRequest) (*dto.BannerResponse, error) {
+	banner := &po.Banner{Enabled: true}
+	if err := uc.apply(banner, req); err != nil {
+		return nil, err
+	}
+	if err := uc.data.BannerRepo.Create(banner); err != nil {
+		return nil, errors.New("创建横幅失败")
+	}
+	return uc.convertOne(banner)
+}
+
+// Update 更新横幅
+func (uc *bannerUseCase) Update(id uint, req *dto.BannerRequest) (*dto.BannerResponse, error) {
+	banner, err := uc.data.BannerRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrBannerNotFound
+	}
+	if err := uc.apply(banner, req); err != nil {
+		return nil, err
+	}
+	if err := uc.data.BannerRepo.Update(banner); err != nil {
+		return nil, errors.New("更新横幅失败")
+	}
+	return uc.convertOne(banner)
+}
+
+// Delete 删除横幅
+func (uc *bannerUseCase) Delete(id uint) error {
+	if _, err := uc.data.BannerRepo.FindByID(id); err != nil {
+		return ErrBannerNotFound
+	}
+	if err := uc.data.BannerRepo.Delete(id); err != nil {
+		return errors.New("删除横幅失败")
+	}
+	return nil
+}
+
+// apply 校验请求并写入横幅
+func (uc *bannerUseCase) apply(banner *po.Banner, req *dto.BannerRequest) error {
+	image := strings.TrimSpace(req.Image)
+	files, err := uc.data.FileRepo.FindByURLs([]string{image})
+	if err != nil {
+		return errors.New("查询图片失败")
+	}
+	if len(files) == 0 || !strings.HasPrefix(files[0].MimeType, "image/") {
+		return fmt.Errorf("%w: 图片必须先通过文件上传接口上传", ErrBannerInvalid)
+	}
+
+	link := strings.TrimSpace(req.Link)
+	if link != "" && !validLinkURL(link) {
+		return fmt.Errorf("%w: 跳转地址必须是 / 开头的站内路径或 http(s) 地址", ErrBannerInvalid)
+	}
+	if req.StartAt != nil && req.EndAt != nil && !req.EndAt.After(*req.StartAt) {
+		return fmt.Errorf("%w: 结束时间必须晚于开始时间", ErrBannerInvalid)
+	}
+
+	banner.Title = strings.TrimSpace(req.Title)
+	banner.Subtitle = strings.TrimSpace(req.Subtitle)
+	banner.Image = image
+	banner.Link = link
+	banner.NewTab = req.NewTab
+	banner.StartAt = req.StartAt
+	banner.EndAt = req.EndAt
+	if req.Enabled != nil {
+		banner.Enabled = *req.Enabled
+	}
+	if req.Sort != nil {
+		banner.Sort = *req.Sort
+	}
+	return nil
+}
+
+// convertOne 转换单个横幅
+func (uc *bannerUseCase) convertOne(banner *po.Banner) (*dto.BannerResponse, error) {
+	items, err := uc.convert([]*po.Banner{banner})
+	if err != nil {
+		return nil, err
+	}
+	return items[0], nil
+}
+
+// convert 转换为横幅响应，图片附带缩略图和 WebP 地址
+func (uc *bannerUseCase) convert(banners []*po.Banner) ([]*dto.BannerResponse, error) {
+	urls := make([]string, 0, len(banners))
+	for _, banner := range banners {
+		urls = append(urls, banner.Image)
+	}
+	files, err := uc.data.FileRepo.FindByURLs(urls)
+	if err != nil {
+		return nil, errors.New("查询图片失败")
+	}
+	media := make(map[string]*dto.MediaItem, len(files))
+	for _, file := range files {
+		media[file.URL] = convertToMediaItem(file)
+	}
+
+	now := time.Now()
+	items := make([]*dto.BannerResponse, 0, len(banners))
+	for _, banner := range banners {
+		item := &dto.BannerResponse{
+			ID:           banner.ID,
+			Title:        banner.Title,
+			Subtitle:     banner.Subtitle,
+			Image:        banner.Image,
+			ThumbnailURL: banner.Image,
+			Link:         banner.Link,
+			NewTab:       banner.NewTab,
+			Enabled:      banner.Enabled,
+			Sort:         banner.Sort,
+			StartAt:      banner.StartAt,
+			EndAt:        banner.EndAt,
+			Status:       bannerStatus(banner, now),
+			CreatedAt:    banner.CreatedAt,
+			UpdatedAt:    banner.UpdatedAt,
+		}
+		// 上传记录被删除时只返回原图地址
+		if file, ok := media[banner.Image]; ok {
+			item.ThumbnailURL = file.ThumbnailURL
+			item.WebPURL = file.WebPURL
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// bannerStatus 横幅在 now 时刻的展示状态
+func bannerStatus(banner *po.Banner, now time.Time) string {
+	switch {
+	case !banner.Enabled:
+		return po.BannerStatusDisabled
+	case banner.EndAt != nil && !now.Before(*banner.EndAt):
+		return po.BannerStatusExpired
+	case banner.StartAt != nil && now.Before(*banner.StartAt):
+		return po.BannerStatusScheduled
+	default:
+		return po.BannerStatusActive
+	}
+}
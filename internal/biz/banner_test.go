@@ -0,0 +1,84 @@
+package biz
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// stubBannerRepo 保存在内存中的横幅仓储
+type stubBannerRepo struct {
+	data.BannerRepo
+	banners []*po.Banner
+}
+
+func (r *stubBannerRepo) Create(banner *po.Banner) error {
+	banner.ID = uint(len(r.banners) + 1)
+	r.banners = append(r.banners, banner)
+	return nil
+}
+
+func TestBannerCreate(t *testing.T) {
+	files := &stubFileRepo{files: []*po.File{
+		{URL: "/uploads/banners/a.jpg", MimeType: "image/jpeg", WebPURL: "/uploads/banners/a.webp",
+			Variants: `[{"name":"thumbnail","width":320,"url":"/uploads/banners/a_thumbnail.jpg"}]`},
+		{URL: "/uploads/docs/b.pdf", MimeType: "application/pdf"},
+	}}
+	repo := &stubBannerRepo{}
+	uc := NewBannerUseCase(&data.Data{BannerRepo: repo, FileRepo: files})
+
+	now := time.Now()
+	later := now.Add(time.Hour)
+	banner, err := uc.Create(&dto.BannerRequest{Title: " 新版上线 ", Image: "/uploads/banners/a.jpg", Link: "/page/about", StartAt: &later})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if banner.Title != "新版上线" || !banner.Enabled || banner.Status != po.BannerStatusScheduled {
+		t.Errorf("banner = %+v", banner)
+	}
+	if banner.ThumbnailURL != "/uploads/banners/a_thumbnail.jpg" || banner.WebPURL != "/uploads/banners/a.webp" {
+		t.Errorf("image urls = %q, %q", banner.ThumbnailURL, banner.WebPURL)
+	}
+
+	disabled := false
+	invalid := []*dto.BannerRequest{
+		{Image: "/uploads/banners/missing.jpg"},
+		{Image: "/uploads/docs/b.pdf"},
+		{Image: "/uploads/banners/a.jpg", Link: "javascript:alert(1)"},
+		{Image: "/uploads/banners/a.jpg", StartAt: &later, EndAt: &now, Enabled: &disabled},
+	}
+	for _, req := range invalid {
+		if _, err := uc.Create(req); !errors.Is(err, ErrBannerInvalid) {
+			t.Errorf("Create(%+v) = %v, want ErrBannerInvalid", req, err)
+		}
+	}
+	if len(repo.banners) != 1 {
+		t.Fatalf("stored %d banners, invalid requests must not be saved", len(repo.banners))
+	}
+}
+
+func TestBannerStatus(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	cases := []struct {
+		banner po.Banner
+		want   string
+	}{
+		{po.Banner{Enabled: true}, po.BannerStatusActive},
+		{po.Banner{Enabled: true, StartAt: &past, EndAt: &future}, po.BannerStatusActive},
+		{po.Banner{Enabled: true, StartAt: &future}, po.BannerStatusScheduled},
+		{po.Banner{Enabled: true, EndAt: &past}, po.BannerStatusExpired},
+		// 结束时间点本身已不再展示
+		{po.Banner{Enabled: true, EndAt: &now}, po.BannerStatusExpired},
+		{po.Banner{Enabled: false, StartAt: &past}, po.BannerStatusDisabled},
+	}
+	for _, tc := range cases {
+		if got := bannerStatus(&tc.banner, now); got != tc.want {
+			t.Errorf("bannerStatus(%+v) = %s, want %s", tc.banner, got, tc.want)
+		}
+	}
+}
@@ -6,24 +6,94 @@ import (
 
 // Biz 业务逻辑层结构
 type Biz struct {
-	AuthUseCase     AuthUseCase
-	ArticleUseCase  ArticleUseCase
-	UserUseCase     UserUseCase
-	CategoryUseCase CategoryUseCase
-	TagUseCase      TagUseCase
-	CommentUseCase  CommentUseCase
-	BlogUseCase     BlogUseCase
+	AuthUseCase            AuthUseCase
+	ArticleUseCase         ArticleUseCase
+	UserUseCase            UserUseCase
+	CategoryUseCase        CategoryUseCase
+	TagUseCase             TagUseCase
+	CommentUseCase         CommentUseCase
+	BlogUseCase            BlogUseCase
+	PermissionUseCase      PermissionUseCase
+	SearchUseCase          SearchUseCase
+	SeriesUseCase          SeriesUseCase
+	SitemapUseCase         SitemapUseCase
+	WebhookUseCase         WebhookUseCase
+	YuqueUseCase           YuqueUseCase
+	BackupUseCase          BackupUseCase
+	ImageCleanupUseCase    ImageCleanupUseCase
+	UploadUseCase          UploadUseCase
+	StorageStatsUseCase    StorageStatsUseCase
+	AttachmentUseCase      AttachmentUseCase
+	MediaUseCase           MediaUseCase
+	ReactionUseCase        ReactionUseCase
+	CommentBlockUseCase    CommentBlockUseCase
+	FeedUseCase            FeedUseCase
+	OAuthUseCase           OAuthUseCase
+	APIKeyUseCase          APIKeyUseCase
+	RBACUseCase            RBACUseCase
+	LoginLogUseCase        LoginLogUseCase
+	CaptchaUseCase         CaptchaUseCase
+	UserProfileUseCase     UserProfileUseCase
+	FollowUseCase          FollowUseCase
+	FavoriteUseCase        FavoriteUseCase
+	ReadingProgressUseCase ReadingProgressUseCase
+	MailUseCase            MailUseCase
+	PushUseCase            PushUseCase
+	RealtimeUseCase        RealtimeUseCase
+	SettingUseCase         SettingUseCase
+	FriendLinkUseCase      FriendLinkUseCase
+	GuestbookUseCase       GuestbookUseCase
+	MomentUseCase          MomentUseCase
+	PageUseCase            PageUseCase
+	MenuUseCase            MenuUseCase
+	BannerUseCase          BannerUseCase
+	AnnouncementUseCase    AnnouncementUseCase
 }
 
 // NewBiz 创建业务逻辑层实例
 func NewBiz(d *data.Data) *Biz {
 	return &Biz{
-		AuthUseCase:     NewAuthUseCase(d),
-		ArticleUseCase:  NewArticleUseCase(d),
-		UserUseCase:     NewUserUseCase(d),
-		CategoryUseCase: NewCategoryUseCase(d),
-		TagUseCase:      NewTagUseCase(d),
-		CommentUseCase:  NewCommentUseCase(d),
-		BlogUseCase:     NewBlogUseCase(d),
+		AuthUseCase:            NewAuthUseCase(d),
+		ArticleUseCase:         NewArticleUseCase(d),
+		UserUseCase:            NewUserUseCase(d),
+		CategoryUseCase:        NewCategoryUseCase(d),
+		TagUseCase:             NewTagUseCase(d),
+		CommentUseCase:         NewCommentUseCase(d),
+		BlogUseCase:            NewBlogUseCase(d),
+		PermissionUseCase:      NewPermissionUseCase(d),
+		SearchUseCase:          NewSearchUseCase(d),
+		SeriesUseCase:          NewSeriesUseCase(d),
+		SitemapUseCase:         NewSitemapUseCase(d),
+		WebhookUseCase:         NewWebhookUseCase(d),
+		YuqueUseCase:           NewYuqueUseCase(d),
+		BackupUseCase:          NewBackupUseCase(d),
+		ImageCleanupUseCase:    NewImageCleanupUseCase(d),
+		UploadUseCase:          NewUploadUseCase(d),
+		StorageStatsUseCase:    NewStorageStatsUseCase(),
+		AttachmentUseCase:      NewAttachmentUseCase(d),
+		MediaUseCase:           NewMediaUseCase(d),
+		ReactionUseCase:        NewReactionUseCase(d),
+		CommentBlockUseCase:    NewCommentBlockUseCase(d),
+		FeedUseCase:            NewFeedUseCase(d),
+		OAuthUseCase:           NewOAuthUseCase(d),
+		APIKeyUseCase:          NewAPIKeyUseCase(d),
+		RBACUseCase:            NewRBACUseCase(d),
+		LoginLogUseCase:        NewLoginLogUseCase(d),
+		CaptchaUseCase:         NewCaptchaUseCase(),
+		UserProfileUseCase:     NewUserProfileUseCase(d),
+		FollowUseCase:          NewFollowUseCase(d),
+		FavoriteUseCase:        NewFavoriteUseCase(d),
+		ReadingProgressUseCase: NewReadingProgressUseCase(d),
+		MailUseCase:            NewMailUseCase(),
+		PushUseCase:            NewPushUseCase(d),
+		RealtimeUseCase:        NewRealtimeUseCase(d),
+		SettingUseCase:         NewSettingUseCase(d),
+		FriendLinkUseCase:      NewFriendLinkUseCase(d),
+		GuestbookUseCase:       NewGuestbookUseCase(d),
+		MomentUseCase:          NewMomentUseCase(d),
+		PageUseCase:            NewPageUseCase(d),
+		MenuUseCase:            NewMenuUseCase(d),
+		BannerUseCase:          NewBannerUseCase(d),
+		AnnouncementUseCase:    NewAnnouncementUseCase(d),
 	}
 }
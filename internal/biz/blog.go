@@ -1,6 +1,7 @@
 package biz
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -8,6 +9,8 @@ import (
 	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
 	"github.com/ydcloud-dy/leaf-api/internal/model/po"
 	"github.com/ydcloud-dy/leaf-api/pkg/jwt"
+	mdutils "github.com/ydcloud-dy/leaf-api/pkg/markdown"
+	"github.com/ydcloud-dy/leaf-api/pkg/sanitize"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -15,14 +18,26 @@ import (
 // BlogUseCase 博客用户业务用例接口
 type BlogUseCase interface {
 	// Register 用户注册
-	Register(req *dto.RegisterRequest) (*dto.LoginResponse, error)
+	Register(ctx context.Context, req *dto.RegisterRequest, client *dto.ClientInfo) (*dto.LoginResponse, error)
 	// Login 用户登录
-	Login(req *dto.LoginRequest) (*dto.LoginResponse, error)
+	Login(ctx context.Context, req *dto.LoginRequest, client *dto.ClientInfo) (*dto.LoginResponse, error)
+	// VerifyEmail 验证邮箱并激活账号
+	VerifyEmail(token string) error
+	// ResendVerification 重新发送验证邮件
+	ResendVerification(ctx context.Context, email string) error
+	// ForgotPassword 发送重置密码邮件，ip 用于限流
+	ForgotPassword(ctx context.Context, email, ip string) error
+	// ResetPassword 使用重置令牌设置新密码
+	ResetPassword(token, password string) error
 	// GetUserInfo 获取用户信息
 	GetUserInfo(userID uint) (*dto.UserInfo, error)
 
-	// GetArticleDetail 获取文章详情（包含用户点赞收藏状态）
-	GetArticleDetail(articleID, userID uint) (*dto.ArticleDetailResponse, error)
+	// GetArticleDetail 获取文章详情（包含用户点赞收藏状态），accessToken 用于查看加密文章，lang 不为空时返回翻译组中该语言的版本，client 用于浏览量去重
+	GetArticleDetail(articleID, userID uint, accessToken, lang string, client *dto.ClientInfo) (*dto.ArticleDetailResponse, error)
+	// GetArticleDetailBySlug 根据 slug 获取文章详情
+	GetArticleDetailBySlug(slug string, userID uint, accessToken, lang string, client *dto.ClientInfo) (*dto.ArticleDetailResponse, error)
+	// UnlockArticle 校验加密文章密码并签发访问令牌
+	UnlockArticle(articleID uint, password string) (*dto.UnlockArticleResponse, error)
 	// GetAdjacentArticles 获取文章的上一篇和下一篇
 	GetAdjacentArticles(articleID uint) (*dto.AdjacentArticlesResponse, error)
 
@@ -45,17 +60,23 @@ type BlogUseCase interface {
 	GetUserFavorites(userID uint, page, limit int) (*dto.FavoriteListResponse, error)
 
 	// CreateComment 创建评论
-	CreateComment(req *dto.CreateCommentRequest) (*dto.CommentResponse, error)
+	CreateComment(ctx context.Context, req *dto.CreateCommentRequest) (*dto.CommentResponse, error)
 	// GetArticleComments 获取文章评论列表
 	GetArticleComments(articleID, userID uint, page, limit int) (*dto.CommentListResponse, error)
+	// GetCommentReplies 获取顶级评论下的回复列表
+	GetCommentReplies(commentID, userID uint, page, limit int) (*dto.CommentListResponse, error)
 	// LikeComment 点赞评论
 	LikeComment(userID, commentID uint) error
 	// UnlikeComment 取消点赞评论
 	UnlikeComment(userID, commentID uint) error
+	// UpdateComment 作者在编辑时间窗口内编辑评论
+	UpdateComment(commentID, userID uint, req *dto.UpdateCommentRequest) (*dto.CommentResponse, error)
 	// DeleteComment 删除评论
 	DeleteComment(commentID, userID uint) error
 	// GetUserStats 获取用户统计信息
 	GetUserStats(userID uint) (*dto.UserStatsResponse, error)
+	// ExportUserData 导出当前用户的全部数据
+	ExportUserData(userID uint) (*dto.UserDataExport, error)
 	// UpdateProfile 更新用户资料
 	UpdateProfile(userID uint, req *dto.UpdateProfileRequest) (*po.User, error)
 	// ChangePassword 修改密码
@@ -75,7 +96,11 @@ func NewBlogUseCase(d *data.Data) BlogUseCase {
 }
 
 // Register 用户注册
-func (uc *blogUseCase) Register(req *dto.RegisterRequest) (*dto.LoginResponse, error) {
+func (uc *blogUseCase) Register(ctx context.Context, req *dto.RegisterRequest, client *dto.ClientInfo) (*dto.LoginResponse, error) {
+	if !uc.registrationAllowed() {
+		return nil, ErrRegistrationClosed
+	}
+
 	// 检查用户名是否已存在
 	if _, err := uc.data.UserRepo.FindByUsername(req.Username); err == nil {
 		return nil, errors.New("用户名已存在")
@@ -99,21 +124,45 @@ func (uc *blogUseCase) Register(req *dto.RegisterRequest) (*dto.LoginResponse, e
 		Password: string(hashedPassword),
 		Nickname: req.Nickname,
 		Avatar:   req.Avatar,
-		Status:   1, // 默认启用
+		Status:   po.UserStatusActive,
+	}
+	verify := uc.emailVerificationRequired()
+	if verify {
+		user.Status = po.UserStatusUnverified
 	}
 
 	if err := uc.data.UserRepo.Create(user); err != nil {
 		return nil, errors.New("创建用户失败")
 	}
 
+	// 需要验证邮箱时不返回 Token，发送失败时用户可以通过重新发送接口再次获取
+	if verify {
+		_ = sendVerificationEmail(ctx, user)
+		return &dto.LoginResponse{
+			User: &dto.UserInfo{
+				ID:        user.ID,
+				Username:  user.Username,
+				Email:     user.Email,
+				Nickname:  user.Nickname,
+				Avatar:    user.Avatar,
+				Role:      user.Role,
+				Status:    user.Status,
+				CreatedAt: user.CreatedAt,
+			},
+			VerificationRequired: true,
+		}, nil
+	}
+
 	// 生成 Token
-	token, err := jwt.GenerateToken(user.ID, user.Username, "user")
+	tokens, err := issueTokens(user, "user", client)
 	if err != nil {
-		return nil, errors.New("生成 Token 失败")
+		return nil, err
 	}
 
 	return &dto.LoginResponse{
-		Token: token,
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
 		User: &dto.UserInfo{
 			ID:        user.ID,
 			Username:  user.Username,
@@ -130,32 +179,44 @@ func (uc *blogUseCase) Register(req *dto.RegisterRequest) (*dto.LoginResponse, e
 	}, nil
 }
 
-// Login 用户登录
-func (uc *blogUseCase) Login(req *dto.LoginRequest) (*dto.LoginResponse, error) {
+// Login 用户登录，记录登录日志
+func (uc *blogUseCase) Login(ctx context.Context, req *dto.LoginRequest, client *dto.ClientInfo) (*dto.LoginResponse, error) {
+	user, resp, err := uc.login(req, client)
+	recordLogin(ctx, uc.data, user, req.Username, po.LoginChannelBlog, client, err)
+	return resp, err
+}
+
+// login 校验用户名密码并签发 Token，用户名不存在时返回的用户为 nil
+func (uc *blogUseCase) login(req *dto.LoginRequest, client *dto.ClientInfo) (*po.User, *dto.LoginResponse, error) {
 	// 查询用户
 	user, err := uc.data.UserRepo.FindByUsername(req.Username)
 	if err != nil {
-		return nil, errors.New("用户名或密码错误")
+		return nil, nil, errors.New("用户名或密码错误")
 	}
 
 	// 验证密码
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return nil, errors.New("用户名或密码错误")
+		return user, nil, errors.New("用户名或密码错误")
 	}
 
 	// 检查状态
-	if user.Status != 1 {
-		return nil, errors.New("账号已被禁用")
+	if user.Status == po.UserStatusUnverified {
+		return user, nil, ErrEmailUnverified
+	}
+	if user.Status != po.UserStatusActive {
+		return user, nil, errors.New("账号已被禁用")
 	}
 
 	// 生成 Token
-	token, err := jwt.GenerateToken(user.ID, user.Username, "user")
+	tokens, err := issueTokens(user, "user", client)
 	if err != nil {
-		return nil, errors.New("生成 Token 失败")
+		return user, nil, err
 	}
 
-	return &dto.LoginResponse{
-		Token: token,
+	return user, &dto.LoginResponse{
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
 		User: &dto.UserInfo{
 			ID:        user.ID,
 			Username:  user.Username,
@@ -195,40 +256,74 @@ func (uc *blogUseCase) GetUserInfo(userID uint) (*dto.UserInfo, error) {
 }
 
 // GetArticleDetail 获取文章详情（包含用户点赞收藏状态）
-func (uc *blogUseCase) GetArticleDetail(articleID, userID uint) (*dto.ArticleDetailResponse, error) {
+func (uc *blogUseCase) GetArticleDetail(articleID, userID uint, accessToken, lang string, client *dto.ClientInfo) (*dto.ArticleDetailResponse, error) {
 	// 获取文章基本信息
 	article, err := uc.data.ArticleRepo.FindByIDWithRelations(articleID)
 	if err != nil {
 		return nil, errors.New("文章不存在")
 	}
 
-	// 博客前台只能查看已发布的文章（status = 1）
-	if article.Status != 1 {
+	return uc.articleDetail(article, userID, accessToken, lang, client)
+}
+
+// GetArticleDetailBySlug 根据 slug 获取文章详情
+func (uc *blogUseCase) GetArticleDetailBySlug(slug string, userID uint, accessToken, lang string, client *dto.ClientInfo) (*dto.ArticleDetailResponse, error) {
+	article, err := uc.data.ArticleRepo.FindBySlug(slug)
+	if err != nil {
+		return nil, errors.New("文章不存在")
+	}
+
+	return uc.articleDetail(article, userID, accessToken, lang, client)
+}
+
+// articleDetail 组装文章详情并记录浏览量
+// 加密文章未携带有效访问令牌时只返回标题等基础信息
+func (uc *blogUseCase) articleDetail(article *po.Article, userID uint, accessToken, lang string, client *dto.ClientInfo) (*dto.ArticleDetailResponse, error) {
+	// 按请求语言切换到翻译组中的对应版本，没有该语言的已发布版本时返回原文章
+	article, language, translations := resolveTranslation(uc.data, article, lang)
+	articleID := article.ID
+
+	// 博客前台只能查看已发布的公开或加密文章（status = 1）
+	if article.Status != 1 || !articleVisible(article) {
 		return nil, errors.New("文章不存在或未发布")
 	}
 
-	// 增加浏览量（异步更新，不影响返回）
-	go func() {
-		_ = uc.data.ArticleRepo.IncrementViewCount(articleID)
-	}()
+	locked := articleLocked(article) && !jwt.VerifyArticleAccessToken(accessToken, articleID)
+
+	// 增加浏览量（异步更新，不影响返回），未解锁的加密文章不计入，同一访客在去重窗口内只计一次
+	if !locked {
+		viewer := viewerKey(userID, client)
+		go func() {
+			if shouldCountView(articleID, viewer) {
+				_ = incrArticleCounter(uc.data, articleID, counterViews, 1)
+			}
+		}()
+	}
 
 	// 转换为响应结构
 	articleResp := &dto.ArticleResponse{
 		ID:              article.ID,
 		Title:           article.Title,
+		Slug:            articleSlug(article),
 		ContentMarkdown: article.ContentMarkdown,
-		ContentHTML:     article.ContentHTML,
+		ContentHTML:     sanitize.Article(article.ContentHTML),
 		Summary:         article.Summary,
 		Cover:           article.Cover,
 		AuthorID:        article.AuthorID,
 		CategoryID:      article.CategoryID,
 		Status:          article.Status,
+		IsTop:           article.IsTop,
+		PinnedSort:      article.PinnedSort,
+		Visibility:      article.Visibility,
+		WordCount:       article.WordCount,
+		ReadingTime:     article.ReadingTime,
 		ViewCount:       article.ViewCount,
 		LikeCount:       article.LikeCount,
 		FavoriteCount:   article.FavoriteCount,
 		CommentCount:    article.CommentCount,
 		CreatedAt:       article.CreatedAt,
 		UpdatedAt:       article.UpdatedAt,
+		SEO:             seoMeta(article),
 	}
 
 	// 作者信息
@@ -240,6 +335,7 @@ func (uc *blogUseCase) GetArticleDetail(articleID, userID uint) (*dto.ArticleDet
 			Avatar:   article.Author.Avatar,
 		}
 	}
+	articleResp.Authors = convertToCoAuthors(article.Authors)
 
 	// 分类信息
 	if article.Category.ID > 0 {
@@ -263,6 +359,16 @@ func (uc *blogUseCase) GetArticleDetail(articleID, userID uint) (*dto.ArticleDet
 		articleResp.Tags = tags
 	}
 
+	// 文章目录，未解锁的加密文章不返回
+	toc := make([]*dto.TOCItem, 0)
+	if locked {
+		articleResp.ContentMarkdown = ""
+		articleResp.ContentHTML = ""
+		articleResp.Summary = ""
+	} else {
+		toc = buildTOC(article.ContentMarkdown)
+	}
+
 	// 检查用户点赞和收藏状态
 	var isLiked, isFavorited bool
 	if userID > 0 {
@@ -271,9 +377,40 @@ func (uc *blogUseCase) GetArticleDetail(articleID, userID uint) (*dto.ArticleDet
 	}
 
 	return &dto.ArticleDetailResponse{
-		ArticleResponse: *articleResp,
-		IsLiked:         isLiked,
-		IsFavorited:     isFavorited,
+		ArticleResponse:     *articleResp,
+		IsLiked:             isLiked,
+		IsFavorited:         isFavorited,
+		Locked:              locked,
+		TOC:                 toc,
+		Reactions:           reactionSummaries(uc.data, po.ReactionTargetArticle, []uint{articleID}, reactorKey(userID, client))[articleID],
+		Language:            language,
+		Translations:        translations,
+		TranslationFallback: lang != "" && normalizeLanguage(lang) != language,
+	}, nil
+}
+
+// UnlockArticle 校验加密文章密码并签发访问令牌
+func (uc *blogUseCase) UnlockArticle(articleID uint, password string) (*dto.UnlockArticleResponse, error) {
+	article, err := uc.data.ArticleRepo.FindByID(articleID)
+	if err != nil || article.Status != 1 || !articleVisible(article) {
+		return nil, errors.New("文章不存在或未发布")
+	}
+	if !articleLocked(article) {
+		return nil, errors.New("该文章无需密码")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(article.AccessPassword), []byte(password)); err != nil {
+		return nil, errors.New("密码错误")
+	}
+
+	token, expiresAt, err := jwt.GenerateArticleAccessToken(articleID, articleAccessTTL)
+	if err != nil {
+		return nil, errors.New("生成访问令牌失败")
+	}
+
+	return &dto.UnlockArticleResponse{
+		AccessToken: token,
+		ExpiresAt:   expiresAt,
 	}, nil
 }
 
@@ -299,7 +436,7 @@ func (uc *blogUseCase) LikeArticle(userID, articleID uint) error {
 	}
 
 	// 更新文章点赞数
-	return uc.data.ArticleRepo.IncrementLikeCount(articleID)
+	return incrArticleCounter(uc.data, articleID, counterLikes, 1)
 }
 
 // UnlikeArticle 取消点赞
@@ -308,7 +445,7 @@ func (uc *blogUseCase) UnlikeArticle(userID, articleID uint) error {
 		return err
 	}
 	// 更新文章点赞数
-	return uc.data.ArticleRepo.DecrementLikeCount(articleID)
+	return incrArticleCounter(uc.data, articleID, counterLikes, -1)
 }
 
 // IsLiked 检查是否已点赞
@@ -328,6 +465,7 @@ func (uc *blogUseCase) GetUserLikes(userID uint, page, limit int) (*dto.LikeList
 		articleResp := &dto.ArticleResponse{
 			ID:            like.Article.ID,
 			Title:         like.Article.Title,
+			Slug:          articleSlug(&like.Article),
 			Summary:       like.Article.Summary,
 			Cover:         like.Article.Cover,
 			AuthorID:      like.Article.AuthorID,
@@ -389,38 +527,14 @@ func (uc *blogUseCase) GetUserLikes(userID uint, page, limit int) (*dto.LikeList
 	}, nil
 }
 
-// FavoriteArticle 收藏文章
+// FavoriteArticle 收藏文章，放入默认收藏夹
 func (uc *blogUseCase) FavoriteArticle(userID, articleID uint) error {
-	// 检查是否已收藏
-	exists, err := uc.data.FavoriteRepo.Exists(articleID, userID)
-	if err != nil {
-		return err
-	}
-	if exists {
-		return errors.New("已经收藏过了")
-	}
-
-	// 创建收藏记录
-	favorite := &po.Favorite{
-		UserID:    userID,
-		ArticleID: articleID,
-		CreatedAt: time.Now(),
-	}
-	if err := uc.data.FavoriteRepo.Create(favorite); err != nil {
-		return err
-	}
-
-	// 更新文章收藏数
-	return uc.data.ArticleRepo.IncrementFavoriteCount(articleID)
+	return addFavorite(uc.data, userID, articleID, 0)
 }
 
 // UnfavoriteArticle 取消收藏
 func (uc *blogUseCase) UnfavoriteArticle(userID, articleID uint) error {
-	if err := uc.data.FavoriteRepo.Delete(articleID, userID); err != nil {
-		return err
-	}
-	// 更新文章收藏数
-	return uc.data.ArticleRepo.DecrementFavoriteCount(articleID)
+	return removeFavorite(uc.data, userID, articleID)
 }
 
 // IsFavorited 检查是否已收藏
@@ -435,66 +549,8 @@ func (uc *blogUseCase) GetUserFavorites(userID uint, page, limit int) (*dto.Favo
 		return nil, err
 	}
 
-	favoriteList := make([]dto.FavoriteInfo, 0, len(favorites))
-	for _, favorite := range favorites {
-		articleResp := &dto.ArticleResponse{
-			ID:            favorite.Article.ID,
-			Title:         favorite.Article.Title,
-			Summary:       favorite.Article.Summary,
-			Cover:         favorite.Article.Cover,
-			AuthorID:      favorite.Article.AuthorID,
-			CategoryID:    favorite.Article.CategoryID,
-			Status:        favorite.Article.Status,
-			ViewCount:     favorite.Article.ViewCount,
-			LikeCount:     favorite.Article.LikeCount,
-			FavoriteCount: favorite.Article.FavoriteCount,
-			CommentCount:  favorite.Article.CommentCount,
-			CreatedAt:     favorite.Article.CreatedAt,
-		}
-
-		// 添加作者信息
-		if favorite.Article.Author.ID > 0 {
-			articleResp.Author = &dto.AuthorInfo{
-				ID:       favorite.Article.Author.ID,
-				Username: favorite.Article.Author.Username,
-				Nickname: favorite.Article.Author.Nickname,
-				Avatar:   favorite.Article.Author.Avatar,
-			}
-		}
-
-		// 添加分类信息
-		if favorite.Article.Category.ID > 0 {
-			articleResp.Category = &dto.CategoryInfo{
-				ID:          favorite.Article.Category.ID,
-				Name:        favorite.Article.Category.Name,
-				Description: favorite.Article.Category.Description,
-			}
-		}
-
-		// 添加标签信息
-		if len(favorite.Article.Tags) > 0 {
-			tags := make([]dto.TagInfo, 0, len(favorite.Article.Tags))
-			for _, tag := range favorite.Article.Tags {
-				tags = append(tags, dto.TagInfo{
-					ID:    tag.ID,
-					Name:  tag.Name,
-					Color: tag.Color,
-				})
-			}
-			articleResp.Tags = tags
-		}
-
-		favoriteList = append(favoriteList, dto.FavoriteInfo{
-			ID:        favorite.ID,
-			ArticleID: favorite.ArticleID,
-			UserID:    favorite.UserID,
-			CreatedAt: favorite.CreatedAt,
-			Article:   articleResp,
-		})
-	}
-
 	return &dto.FavoriteListResponse{
-		List:  favoriteList,
+		List:  favoriteInfos(favorites),
 		Total: total,
 		Page:  page,
 		Limit: limit,
@@ -502,24 +558,72 @@ func (uc *blogUseCase) GetUserFavorites(userID uint, page, limit int) (*dto.Favo
 }
 
 // CreateComment 创建评论
-func (uc *blogUseCase) CreateComment(req *dto.CreateCommentRequest) (*dto.CommentResponse, error) {
+func (uc *blogUseCase) CreateComment(ctx context.Context, req *dto.CreateCommentRequest) (*dto.CommentResponse, error) {
+	if req.ArticleID == nil && !settingBool(uc.data, SettingGuestbookEnabled) {
+		return nil, ErrGuestbookClosed
+	}
+	if req.ArticleID != nil && !settingBool(uc.data, SettingCommentEnabled) {
+		return nil, ErrCommentClosed
+	}
+
 	comment := &po.Comment{
 		ArticleID:     req.ArticleID,
 		UserID:        req.UserID,
 		ParentID:      req.ParentID,
 		ReplyToUserID: req.ReplyToUserID,
 		Content:       req.Content,
-		Status:        1, // 默认审核通过
+		ContentHTML:   commentToHTML(req.Content),
+		Status:        uc.initialCommentStatus(req.UserID),
 		CreatedAt:     time.Now(),
 	}
 
+	// 回复评论：回复挂在父评论所属的顶级评论下，被回复用户默认为父评论作者
+	if req.ParentID != nil {
+		parent, err := uc.data.CommentRepo.FindByID(*req.ParentID)
+		if err != nil || parent.Status != po.CommentStatusApproved {
+			return nil, errors.New("回复的评论不存在")
+		}
+		if !sameCommentTarget(parent.ArticleID, req.ArticleID) {
+			return nil, errors.New("回复的评论不属于该文章")
+		}
+		rootID := parent.ID
+		if parent.RootID != nil {
+			rootID = *parent.RootID
+		}
+		comment.RootID = &rootID
+		if comment.ReplyToUserID == nil {
+			comment.ReplyToUserID = &parent.UserID
+		}
+	}
+
+	// 屏蔽规则：命中拒绝规则的评论不保存，命中垃圾规则的评论直接进入垃圾评论
+	if rule := uc.matchBlockRule(req); rule != nil {
+		_ = uc.data.CommentBlockRepo.RecordHit(rule.ID)
+		if rule.Action == po.CommentBlockReject {
+			return nil, ErrCommentBlocked
+		}
+		comment.Status = po.CommentStatusSpam
+	}
+
 	if err := uc.data.CommentRepo.Create(comment); err != nil {
 		return nil, err
 	}
 
-	// 更新文章评论数（仅当是文章评论时）
-	if req.ArticleID != nil {
-		_ = uc.data.ArticleRepo.IncrementCommentCount(*req.ArticleID)
+	// 投递 Webhook 事件，垃圾评论不通知
+	if comment.Status != po.CommentStatusSpam {
+		notifyComment(ctx, uc.data, comment)
+	}
+
+	// 待审核的评论审核通过后再计入回复数和文章评论数、通知被回复的用户和正在查看页面的读者
+	if comment.Status == po.CommentStatusApproved {
+		notifyCommentReply(ctx, uc.data, comment)
+		publishComment(uc.data, comment)
+		if comment.RootID != nil {
+			_ = uc.data.CommentRepo.AddReplyCount(*comment.RootID, 1)
+		}
+		if req.ArticleID != nil {
+			_ = uc.data.ArticleRepo.IncrementCommentCount(*req.ArticleID)
+		}
 	}
 
 	// 查询创建的评论（带用户信息）
@@ -528,122 +632,159 @@ func (uc *blogUseCase) CreateComment(req *dto.CreateCommentRequest) (*dto.Commen
 		return nil, err
 	}
 
-	response := &dto.CommentResponse{
-		ID:        createdComment.ID,
-		ArticleID: createdComment.ArticleID,
-		UserID:    createdComment.UserID,
-		ParentID:  createdComment.ParentID,
-		Content:   createdComment.Content,
-		LikeCount: createdComment.LikeCount,
-		Status:    createdComment.Status,
-		CreatedAt: createdComment.CreatedAt,
-		User: &dto.UserInfo{
-			ID:       createdComment.User.ID,
-			Username: createdComment.User.Username,
-			Nickname: createdComment.User.Nickname,
-			Avatar:   createdComment.User.Avatar,
-		},
+	return convertToCommentResponse(createdComment, false), nil
+}
+
+// initialCommentStatus 新评论的状态：开启全部审核时，管理员以外的评论进入待审核队列；
+// 开启首评审核时，还没有审核通过评论的用户（管理员除外）进入待审核队列
+func (uc *blogUseCase) initialCommentStatus(userID uint) int {
+	moderation := settingBool(uc.data, SettingCommentModeration)
+	if !moderation && !settingBool(uc.data, SettingCommentHoldFirstTime) {
+		return po.CommentStatusApproved
+	}
+	if user, err := uc.data.UserRepo.FindByID(userID); err == nil && user.Role == "admin" {
+		return po.CommentStatusApproved
+	}
+	if moderation {
+		return po.CommentStatusPending
+	}
+	if approved, err := uc.data.CommentRepo.CountApprovedByUser(userID); err == nil && approved > 0 {
+		return po.CommentStatusApproved
 	}
+	return po.CommentStatusPending
+}
 
-	// 如果有回复目标用户，添加用户信息
-	if createdComment.ReplyToUser != nil {
-		response.ReplyToUser = &dto.UserInfo{
-			ID:       createdComment.ReplyToUser.ID,
-			Username: createdComment.ReplyToUser.Username,
-			Nickname: createdComment.ReplyToUser.Nickname,
-			Avatar:   createdComment.ReplyToUser.Avatar,
+// matchBlockRule 匹配评论屏蔽规则，管理员的评论不受屏蔽规则限制
+func (uc *blogUseCase) matchBlockRule(req *dto.CreateCommentRequest) *po.CommentBlockRule {
+	email := ""
+	if user, err := uc.data.UserRepo.FindByID(req.UserID); err == nil {
+		if user.Role == "admin" {
+			return nil
 		}
+		email = user.Email
 	}
-
-	return response, nil
+	return matchCommentBlock(uc.data, req.IP, email, req.Content)
 }
 
-// GetArticleComments 获取文章评论列表
+// GetArticleComments 分页获取文章顶级评论，articleID 为 0 时获取留言板消息
+// 回复不随列表返回，通过 GetCommentReplies 按需加载
 func (uc *blogUseCase) GetArticleComments(articleID, userID uint, page, limit int) (*dto.CommentListResponse, error) {
-	// 获取所有评论（不分页，为了构建完整的树形结构）
-	comments, _, err := uc.data.CommentRepo.List(1, 1000, articleID, "1") // 只返回审核通过的
+	comments, total, err := uc.data.CommentRepo.ListTopLevel(articleID, page, limit)
 	if err != nil {
 		return nil, err
 	}
 
-	// 将评论转换为map，以便快速查找
-	commentMap := make(map[uint]*dto.CommentResponse)
-	for _, comment := range comments {
-		commentResp := &dto.CommentResponse{
-			ID:        comment.ID,
-			ArticleID: comment.ArticleID,
-			UserID:    comment.UserID,
-			ParentID:  comment.ParentID,
-			Content:   comment.Content,
-			LikeCount: comment.LikeCount,
-			Status:    comment.Status,
-			CreatedAt: comment.CreatedAt,
-			User: &dto.UserInfo{
-				ID:       comment.User.ID,
-				Username: comment.User.Username,
-				Nickname: comment.User.Nickname,
-				Avatar:   comment.User.Avatar,
-			},
-			Replies: make([]dto.CommentResponse, 0),
-		}
+	return &dto.CommentListResponse{
+		List:  uc.convertComments(comments, userID),
+		Total: total, // 只统计顶级评论数
+		Page:  page,
+		Limit: limit,
+	}, nil
+}
 
-		// 添加回复目标用户信息
-		if comment.ReplyToUser != nil {
-			commentResp.ReplyToUser = &dto.UserInfo{
-				ID:       comment.ReplyToUser.ID,
-				Username: comment.ReplyToUser.Username,
-				Nickname: comment.ReplyToUser.Nickname,
-				Avatar:   comment.ReplyToUser.Avatar,
-			}
-		}
+// GetCommentReplies 分页获取顶级评论下的回复，按时间正序
+func (uc *blogUseCase) GetCommentReplies(commentID, userID uint, page, limit int) (*dto.CommentListResponse, error) {
+	root, err := uc.data.CommentRepo.FindByID(commentID)
+	if err != nil || root.Status != po.CommentStatusApproved {
+		return nil, errors.New("评论不存在")
+	}
+	if root.RootID != nil {
+		return nil, errors.New("只能查询顶级评论的回复")
+	}
 
-		// 检查当前用户是否已点赞该评论
-		if userID > 0 {
-			isLiked, _ := uc.data.CommentLikeRepo.Exists(comment.ID, userID)
-			commentResp.IsLiked = isLiked
-		}
+	comments, total, err := uc.data.CommentRepo.ListReplies(root.ID, page, limit)
+	if err != nil {
+		return nil, err
+	}
 
-		commentMap[comment.ID] = commentResp
+	return &dto.CommentListResponse{
+		List:  uc.convertComments(comments, userID),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}, nil
+}
+
+// convertComments 转换评论列表并批量统计表情回应，userID 大于 0 时填充点赞和回应状态
+func (uc *blogUseCase) convertComments(comments []*po.Comment, userID uint) []dto.CommentResponse {
+	ids := make([]uint, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
 	}
+	reactions := reactionSummaries(uc.data, po.ReactionTargetComment, ids, reactorKey(userID, nil))
 
-	// 构建树形结构
-	topLevelComments := make([]dto.CommentResponse, 0)
+	result := make([]dto.CommentResponse, 0, len(comments))
 	for _, comment := range comments {
-		commentResp := commentMap[comment.ID]
-		if comment.ParentID == nil {
-			// 顶级评论
-			topLevelComments = append(topLevelComments, *commentResp)
-		} else {
-			// 回复评论，添加到父评论的replies中
-			if parent, ok := commentMap[*comment.ParentID]; ok {
-				parent.Replies = append(parent.Replies, *commentResp)
-			}
+		// 检查当前用户是否已点赞该评论
+		isLiked := false
+		if userID > 0 {
+			isLiked, _ = uc.data.CommentLikeRepo.Exists(comment.ID, userID)
 		}
+		resp := convertToCommentResponse(comment, isLiked)
+		resp.Reactions = reactions[comment.ID]
+		result = append(result, *resp)
 	}
+	return result
+}
 
-	// 应用分页到顶级评论
-	start := (page - 1) * limit
-	end := start + limit
-	if start > len(topLevelComments) {
-		start = len(topLevelComments)
+// convertToCommentResponse 转换评论响应
+func convertToCommentResponse(comment *po.Comment, isLiked bool) *dto.CommentResponse {
+	resp := &dto.CommentResponse{
+		ID:          comment.ID,
+		ArticleID:   comment.ArticleID,
+		UserID:      comment.UserID,
+		ParentID:    comment.ParentID,
+		RootID:      comment.RootID,
+		Content:     comment.Content,
+		ContentHTML: commentHTML(comment),
+		LikeCount:   comment.LikeCount,
+		ReplyCount:  comment.ReplyCount,
+		IsLiked:     isLiked,
+		Status:      comment.Status,
+		Edited:      comment.EditedAt != nil,
+		EditedAt:    comment.EditedAt,
+		Reactions:   []dto.ReactionCount{},
+		CreatedAt:   comment.CreatedAt,
+		User: &dto.UserInfo{
+			ID:       comment.User.ID,
+			Username: comment.User.Username,
+			Nickname: comment.User.Nickname,
+			Avatar:   comment.User.Avatar,
+		},
 	}
-	if end > len(topLevelComments) {
-		end = len(topLevelComments)
+
+	// 添加回复目标用户信息
+	if comment.ReplyToUser != nil {
+		resp.ReplyToUser = &dto.UserInfo{
+			ID:       comment.ReplyToUser.ID,
+			Username: comment.ReplyToUser.Username,
+			Nickname: comment.ReplyToUser.Nickname,
+			Avatar:   comment.ReplyToUser.Avatar,
+		}
 	}
-	pagedComments := topLevelComments[start:end]
 
-	// 重新构建完整的树（包括子评论）
-	result := make([]dto.CommentResponse, 0, len(pagedComments))
-	for _, topComment := range pagedComments {
-		result = append(result, *commentMap[topComment.ID])
+	return resp
+}
+
+// commentToHTML 将评论 Markdown 渲染为 HTML，只保留粗体、代码、链接和引用等安全子集
+func commentToHTML(content string) string {
+	return sanitize.CommentHTML(mdutils.RenderComment(content))
+}
+
+// commentHTML 评论渲染后的 HTML，历史评论没有保存渲染结果时即时渲染
+func commentHTML(comment *po.Comment) string {
+	if comment.ContentHTML != "" {
+		return comment.ContentHTML
 	}
+	return commentToHTML(comment.Content)
+}
 
-	return &dto.CommentListResponse{
-		List:  result,
-		Total: int64(len(topLevelComments)), // 只统计顶级评论数
-		Page:  page,
-		Limit: limit,
-	}, nil
+// sameCommentTarget 两条评论是否属于同一篇文章（都为空表示同属留言板）
+func sameCommentTarget(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
 }
 
 // LikeComment 点赞评论
@@ -698,8 +839,9 @@ func (uc *blogUseCase) DeleteComment(commentID, userID uint) error {
 		return errors.New("评论不存在")
 	}
 
-	// 权限检查：评论作者本人、管理员或父评论作者可以删除
-	canDelete := comment.UserID == userID
+	// 权限检查：编辑时间窗口内的评论作者本人、管理员或父评论作者可以删除
+	isAuthor := comment.UserID == userID
+	canDelete := isAuthor && commentEditable(uc.data, comment)
 
 	// 检查是否为管理员
 	if !canDelete {
@@ -718,17 +860,75 @@ func (uc *blogUseCase) DeleteComment(commentID, userID uint) error {
 	}
 
 	if !canDelete {
+		if isAuthor {
+			return errors.New("评论已超过可删除的时间")
+		}
 		return errors.New("无权删除该评论")
 	}
 
-	// 删除评论（如果是父评论，子评论会被级联删除）
-	if err := uc.data.CommentRepo.Delete(commentID); err != nil {
+	return deleteComment(uc.data, comment)
+}
+
+// UpdateComment 编辑评论，只有作者本人可以在编辑时间窗口内编辑，编辑前的内容保存到编辑记录
+func (uc *blogUseCase) UpdateComment(commentID, userID uint, req *dto.UpdateCommentRequest) (*dto.CommentResponse, error) {
+	comment, err := uc.data.CommentRepo.FindByID(commentID)
+	if err != nil || comment.Status == po.CommentStatusSpam || comment.Status == po.CommentStatusTrash {
+		return nil, errors.New("评论不存在")
+	}
+	if comment.UserID != userID {
+		return nil, errors.New("无权编辑该评论")
+	}
+	if !commentEditable(uc.data, comment) {
+		return nil, errors.New("评论已超过可编辑的时间")
+	}
+
+	if req.Content != comment.Content {
+		if err := uc.data.CommentRepo.Edit(comment, req.Content, commentToHTML(req.Content), userID); err != nil {
+			return nil, err
+		}
+		if comment, err = uc.data.CommentRepo.FindByID(commentID); err != nil {
+			return nil, err
+		}
+	}
+
+	isLiked, _ := uc.data.CommentLikeRepo.Exists(comment.ID, userID)
+	resp := convertToCommentResponse(comment, isLiked)
+	resp.Reactions = reactionSummaries(uc.data, po.ReactionTargetComment, []uint{comment.ID}, reactorKey(userID, nil))[comment.ID]
+	return resp, nil
+}
+
+// commentEditable 评论是否仍在作者可以编辑、删除的时间窗口内
+func commentEditable(d *data.Data, comment *po.Comment) bool {
+	window := settingInt(d, SettingCommentEditWindow)
+	return window <= 0 || time.Since(comment.CreatedAt) <= time.Duration(window)*time.Minute
+}
+
+// deleteComment 删除评论并维护计数：删除回复时减少顶级评论回复数，删除顶级评论时一并删除其下全部回复
+func deleteComment(d *data.Data, comment *po.Comment) error {
+	if err := d.CommentRepo.Delete(comment.ID); err != nil {
 		return err
 	}
 
+	// 只有审核通过的评论计入回复数和文章评论数
+	removed := int64(0)
+	if comment.Status == po.CommentStatusApproved {
+		removed = 1
+	}
+	if comment.RootID != nil {
+		if removed > 0 {
+			_ = d.CommentRepo.AddReplyCount(*comment.RootID, -1)
+		}
+	} else {
+		replies, err := d.CommentRepo.DeleteReplies(comment.ID)
+		if err != nil {
+			return err
+		}
+		removed += replies
+	}
+
 	// 更新文章评论数（仅当是文章评论时）
-	if comment.ArticleID != nil {
-		_ = uc.data.ArticleRepo.DecrementCommentCount(*comment.ArticleID)
+	if comment.ArticleID != nil && removed > 0 {
+		_ = d.ArticleRepo.AddCounters(*comment.ArticleID, map[string]int64{"comment_count": -removed})
 	}
 
 	return nil
@@ -890,7 +1090,7 @@ func (uc *blogUseCase) GetAdjacentArticles(articleID uint) (*dto.AdjacentArticle
 	// 查询上一篇文章（ID小于当前文章ID，按ID降序，取第一条）
 	var prevArticle po.Article
 	err = uc.data.GetDB().Model(&po.Article{}).
-		Where("id < ? AND status = ?", articleID, 1).
+		Where("id < ? AND status = ? AND visibility <> ?", articleID, 1, po.VisibilityPrivate).
 		Order("id DESC").
 		Limit(1).
 		Select("id, title").
@@ -906,7 +1106,7 @@ func (uc *blogUseCase) GetAdjacentArticles(articleID uint) (*dto.AdjacentArticle
 	// 查询下一篇文章（ID大于当前文章ID，按ID升序，取第一条）
 	var nextArticle po.Article
 	err = uc.data.GetDB().Model(&po.Article{}).
-		Where("id > ? AND status = ?", articleID, 1).
+		Where("id > ? AND status = ? AND visibility <> ?", articleID, 1, po.VisibilityPrivate).
 		Order("id ASC").
 		Limit(1).
 		Select("id, title").
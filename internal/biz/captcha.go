@@ -0,0 +1,197 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/captcha"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+// 人机验证场景
+const (
+	CaptchaSceneLogin         = "login"
+	CaptchaSceneRegister      = "register"
+	CaptchaSceneComment       = "comment"
+	CaptchaScenePasswordReset = "password_reset"
+	CaptchaSceneFriendLink    = "friend_link"
+)
+
+// captchaScenes 全部场景
+var captchaScenes = []string{CaptchaSceneLogin, CaptchaSceneRegister, CaptchaSceneComment, CaptchaScenePasswordReset, CaptchaSceneFriendLink}
+
+// 验证码默认值
+const (
+	defaultCaptchaExpire = 300
+	captchaTicketTTL     = 2 * time.Minute
+)
+
+// 题目答案和验证凭证都只能使用一次：captcha:challenge:{id} -> 场景|答案，captcha:ticket:{ticket} -> 场景
+const (
+	captchaChallengeKey = "captcha:challenge:%s"
+	captchaTicketKey    = "captcha:ticket:%s"
+)
+
+var (
+	// ErrCaptchaSceneInvalid 未知的验证场景
+	ErrCaptchaSceneInvalid = errors.New("未知的验证场景")
+	// ErrCaptchaRequired 未完成人机验证
+	ErrCaptchaRequired = errors.New("请先完成人机验证")
+	// ErrCaptchaInvalid 验证码错误、已使用或已过期
+	ErrCaptchaInvalid = errors.New("验证码错误或已过期")
+	// ErrCaptchaUnavailable 第三方验证平台不可用
+	ErrCaptchaUnavailable = errors.New("人机验证服务暂时不可用，请稍后再试")
+)
+
+// CaptchaUseCase 人机验证业务用例接口
+type CaptchaUseCase interface {
+	// Generate 获取场景的验证码，第三方平台只返回组件所需的站点密钥
+	Generate(scene string) (*dto.CaptchaResponse, error)
+	// Verify 校验答案，通过后返回一次性验证凭证
+	Verify(ctx context.Context, req *dto.CaptchaVerifyRequest, ip string) (*dto.CaptchaTicket, error)
+	// Check 提交表单时核销验证凭证，场景不需要验证时直接通过
+	Check(scene, ticket string) error
+}
+
+// captchaUseCase 人机验证业务用例实现
+type captchaUseCase struct{}
+
+// NewCaptchaUseCase 创建人机验证业务用例
+func NewCaptchaUseCase() CaptchaUseCase {
+	return &captchaUseCase{}
+}
+
+// Generate 获取场景的验证码，本站出题时答案保存在 Redis 中
+func (uc *captchaUseCase) Generate(scene string) (*dto.CaptchaResponse, error) {
+	provider, err := captchaProvider(scene)
+	if err != nil {
+		return nil, err
+	}
+	if provider == nil {
+		return &dto.CaptchaResponse{Enabled: false}, nil
+	}
+
+	resp := &dto.CaptchaResponse{Enabled: true, Provider: provider.Name()}
+	if provider.Remote() {
+		resp.SiteKey = config.AppConfig.Captcha.SiteKey
+		return resp, nil
+	}
+
+	challenge, err := provider.Generate()
+	if err != nil {
+		logger.Error("Failed to generate captcha: ", err)
+		return nil, errors.New("生成验证码失败")
+	}
+	id, err := randomHex(16)
+	if err != nil {
+		return nil, errors.New("生成验证码失败")
+	}
+	expire := captchaExpire()
+	if err := redis.SetWithExpire(fmt.Sprintf(captchaChallengeKey, id), scene+"|"+challenge.Answer, expire); err != nil {
+		return nil, errors.New("生成验证码失败")
+	}
+
+	resp.ID = id
+	resp.Image = challenge.Image
+	resp.Piece = challenge.Piece
+	resp.PieceY = challenge.PieceY
+	resp.Width = challenge.Width
+	resp.Height = challenge.Height
+	resp.ExpiresIn = int(expire.Seconds())
+	return resp, nil
+}
+
+// Verify 校验答案，本站出题时每个题目只能校验一次，答错需要重新获取
+func (uc *captchaUseCase) Verify(ctx context.Context, req *dto.CaptchaVerifyRequest, ip string) (*dto.CaptchaTicket, error) {
+	provider, err := captchaProvider(req.Scene)
+	if err != nil {
+		return nil, err
+	}
+	if provider == nil {
+		return nil, ErrCaptchaInvalid
+	}
+
+	var expected string
+	if !provider.Remote() {
+		if req.ID == "" {
+			return nil, ErrCaptchaInvalid
+		}
+		value, err := redis.GetDel(fmt.Sprintf(captchaChallengeKey, req.ID))
+		if err != nil {
+			return nil, ErrCaptchaInvalid
+		}
+		scene, answer, ok := strings.Cut(value, "|")
+		if !ok || scene != req.Scene {
+			return nil, ErrCaptchaInvalid
+		}
+		expected = answer
+	}
+
+	passed, err := provider.Verify(ctx, req.Answer, expected, ip)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to verify captcha with ", provider.Name(), ": ", err)
+		return nil, ErrCaptchaUnavailable
+	}
+	if !passed {
+		return nil, ErrCaptchaInvalid
+	}
+
+	ticket, err := randomHex(16)
+	if err != nil {
+		return nil, errors.New("生成验证凭证失败")
+	}
+	if err := redis.SetWithExpire(fmt.Sprintf(captchaTicketKey, ticket), req.Scene, captchaTicketTTL); err != nil {
+		return nil, errors.New("生成验证凭证失败")
+	}
+	return &dto.CaptchaTicket{Ticket: ticket, ExpiresIn: int(captchaTicketTTL.Seconds())}, nil
+}
+
+// Check 核销验证凭证，凭证只能用于获取时的场景
+func (uc *captchaUseCase) Check(scene, ticket string) error {
+	provider, err := captchaProvider(scene)
+	if err != nil || provider == nil {
+		return err
+	}
+	if ticket == "" {
+		return ErrCaptchaRequired
+	}
+
+	value, err := redis.GetDel(fmt.Sprintf(captchaTicketKey, ticket))
+	if err != nil || value != scene {
+		return ErrCaptchaInvalid
+	}
+	return nil
+}
+
+// captchaProvider 返回场景使用的验证方式，场景不需要验证时返回 nil
+// 题目和验证凭证保存在 Redis 中，未启用 Redis 时不进行人机验证
+func captchaProvider(scene string) (captcha.Provider, error) {
+	if !slices.Contains(captchaScenes, scene) {
+		return nil, ErrCaptchaSceneInvalid
+	}
+	cfg := config.AppConfig.Captcha
+	if redis.Client == nil || (len(cfg.Scenes) > 0 && !slices.Contains(cfg.Scenes, scene)) {
+		return nil, nil
+	}
+	provider, err := captcha.Get()
+	if err != nil {
+		return nil, nil
+	}
+	return provider, nil
+}
+
+// captchaExpire 题目有效期
+func captchaExpire() time.Duration {
+	expire := config.AppConfig.Captcha.Expire
+	if expire <= 0 {
+		expire = defaultCaptchaExpire
+	}
+	return time.Duration(expire) * time.Second
+}
@@ -0,0 +1,138 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+// setCaptchaConfig 替换人机验证配置
+func setCaptchaConfig(t *testing.T, cfg config.CaptchaConfig) {
+	t.Helper()
+	previous := config.AppConfig.Captcha
+	config.AppConfig.Captcha = cfg
+	t.Cleanup(func() { config.AppConfig.Captcha = previous })
+}
+
+// captchaAnswer 读取服务端保存的答案
+func captchaAnswer(t *testing.T, id string) string {
+	t.Helper()
+	value, err := redis.Get(fmt.Sprintf(captchaChallengeKey, id))
+	if err != nil {
+		t.Fatalf("challenge %s not stored: %v", id, err)
+	}
+	_, answer, _ := strings.Cut(value, "|")
+	return answer
+}
+
+func TestCaptchaTicketFlow(t *testing.T) {
+	setupTokenRedis(t)
+	setCaptchaConfig(t, config.CaptchaConfig{Provider: "image", Scenes: []string{CaptchaSceneLogin, CaptchaSceneRegister}})
+	uc := NewCaptchaUseCase()
+	ctx := context.Background()
+
+	// 未启用的场景直接通过
+	if resp, err := uc.Generate(CaptchaSceneComment); err != nil || resp.Enabled {
+		t.Fatalf("Generate(comment) = %+v, %v", resp, err)
+	}
+	if err := uc.Check(CaptchaSceneComment, ""); err != nil {
+		t.Fatalf("Check(comment) = %v", err)
+	}
+	if _, err := uc.Generate("unknown"); !errors.Is(err, ErrCaptchaSceneInvalid) {
+		t.Fatalf("Generate(unknown) = %v", err)
+	}
+	if err := uc.Check(CaptchaSceneLogin, ""); !errors.Is(err, ErrCaptchaRequired) {
+		t.Fatalf("Check without ticket = %v", err)
+	}
+
+	// 答错后题目作废
+	wrong, err := uc.Generate(CaptchaSceneLogin)
+	if err != nil || !wrong.Enabled || wrong.ID == "" || !strings.HasPrefix(wrong.Image, "data:image/png;base64,") {
+		t.Fatalf("Generate = %+v, %v", wrong, err)
+	}
+	answer := captchaAnswer(t, wrong.ID)
+	if _, err := uc.Verify(ctx, &dto.CaptchaVerifyRequest{Scene: CaptchaSceneLogin, ID: wrong.ID, Answer: "x"}, ""); !errors.Is(err, ErrCaptchaInvalid) {
+		t.Fatalf("Verify wrong answer = %v", err)
+	}
+	if _, err := uc.Verify(ctx, &dto.CaptchaVerifyRequest{Scene: CaptchaSceneLogin, ID: wrong.ID, Answer: answer}, ""); !errors.Is(err, ErrCaptchaInvalid) {
+		t.Fatalf("Verify after wrong answer = %v", err)
+	}
+
+	// 题目不能用于其他场景
+	other, _ := uc.Generate(CaptchaSceneRegister)
+	if _, err := uc.Verify(ctx, &dto.CaptchaVerifyRequest{Scene: CaptchaSceneLogin, ID: other.ID, Answer: captchaAnswer(t, other.ID)}, ""); !errors.Is(err, ErrCaptchaInvalid) {
+		t.Fatalf("Verify other scene = %v", err)
+	}
+
+	challenge, _ := uc.Generate(CaptchaSceneLogin)
+	ticket, err := uc.Verify(ctx, &dto.CaptchaVerifyRequest{Scene: CaptchaSceneLogin, ID: challenge.ID, Answer: " " + captchaAnswer(t, challenge.ID)}, "")
+	if err != nil {
+		t.Fatalf("Verify = %v", err)
+	}
+	if err := uc.Check(CaptchaSceneRegister, ticket.Ticket); !errors.Is(err, ErrCaptchaInvalid) {
+		t.Fatalf("Check other scene = %v", err)
+	}
+
+	// 凭证只能核销一次
+	challenge, _ = uc.Generate(CaptchaSceneLogin)
+	ticket, _ = uc.Verify(ctx, &dto.CaptchaVerifyRequest{Scene: CaptchaSceneLogin, ID: challenge.ID, Answer: captchaAnswer(t, challenge.ID)}, "")
+	if err := uc.Check(CaptchaSceneLogin, ticket.Ticket); err != nil {
+		t.Fatalf("Check = %v", err)
+	}
+	if err := uc.Check(CaptchaSceneLogin, ticket.Ticket); !errors.Is(err, ErrCaptchaInvalid) {
+		t.Fatalf("Check reused ticket = %v", err)
+	}
+}
+
+func TestCaptchaRemoteProvider(t *testing.T) {
+	setupTokenRedis(t)
+	var form map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = map[string]string{"secret": r.PostForm.Get("secret"), "response": r.PostForm.Get("response"), "remoteip": r.PostForm.Get("remoteip")}
+		if r.PostForm.Get("response") == "unavailable" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprintf(w, `{"success":%v}`, r.PostForm.Get("response") == "good")
+	}))
+	defer server.Close()
+	setCaptchaConfig(t, config.CaptchaConfig{Provider: "turnstile", SiteKey: "site", Secret: "secret", VerifyURL: server.URL})
+	uc := NewCaptchaUseCase()
+
+	resp, err := uc.Generate(CaptchaSceneComment)
+	if err != nil || !resp.Enabled || resp.Provider != "turnstile" || resp.SiteKey != "site" || resp.ID != "" {
+		t.Fatalf("Generate = %+v, %v", resp, err)
+	}
+
+	tests := []struct {
+		answer string
+		err    error
+	}{
+		{answer: "bad", err: ErrCaptchaInvalid},
+		{answer: "unavailable", err: ErrCaptchaUnavailable},
+		{answer: "good"},
+	}
+	for _, tt := range tests {
+		ticket, err := uc.Verify(context.Background(), &dto.CaptchaVerifyRequest{Scene: CaptchaSceneComment, Answer: tt.answer}, "203.0.113.9")
+		if !errors.Is(err, tt.err) {
+			t.Fatalf("Verify(%s) = %v, want %v", tt.answer, err, tt.err)
+		}
+		if tt.err == nil {
+			if form["secret"] != "secret" || form["remoteip"] != "203.0.113.9" {
+				t.Errorf("siteverify form = %v", form)
+			}
+			if err := uc.Check(CaptchaSceneComment, ticket.Ticket); err != nil {
+				t.Errorf("Check = %v", err)
+			}
+		}
+	}
+}
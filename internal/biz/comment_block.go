@@ -0,0 +1,193 @@
+package biz
+
+import (
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+)
+
+var (
+	// ErrCommentBlockNotFound 屏蔽规则不存在
+	ErrCommentBlockNotFound = errors.New("屏蔽规则不存在")
+	// ErrCommentBlockInvalid 屏蔽规则的值不合法
+	ErrCommentBlockInvalid = errors.New("屏蔽规则的值不合法：IP 规则需为 IP 或 CIDR 网段，邮箱规则需为邮箱地址或域名")
+	// ErrCommentBlocked 评论命中拒绝规则
+	ErrCommentBlocked = errors.New("评论包含不允许的内容，发表失败")
+)
+
+// CommentBlockUseCase 评论屏蔽规则业务用例接口
+type CommentBlockUseCase interface {
+	// List 查询屏蔽规则列表
+	List(req *dto.CommentBlockListRequest) (*dto.PageResponse, error)
+	// Create 创建屏蔽规则
+	Create(req *dto.CommentBlockRequest) (*po.CommentBlockRule, error)
+	// Update 更新屏蔽规则
+	Update(id uint, req *dto.CommentBlockRequest) (*po.CommentBlockRule, error)
+	// Delete 删除屏蔽规则
+	Delete(id uint) error
+}
+
+// commentBlockUseCase 评论屏蔽规则业务用例实现
+type commentBlockUseCase struct {
+	data *data.Data
+}
+
+// NewCommentBlockUseCase 创建评论屏蔽规则业务用例
+func NewCommentBlockUseCase(d *data.Data) CommentBlockUseCase {
+	return &commentBlockUseCase{data: d}
+}
+
+// List 查询屏蔽规则列表
+func (uc *commentBlockUseCase) List(req *dto.CommentBlockListRequest) (*dto.PageResponse, error) {
+	rules, total, err := uc.data.CommentBlockRepo.List(req.Page, req.Limit, req.Type, req.Keyword)
+	if err != nil {
+		return nil, errors.New("查询屏蔽规则失败")
+	}
+
+	return &dto.PageResponse{
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Data:  rules,
+	}, nil
+}
+
+// Create 创建屏蔽规则
+func (uc *commentBlockUseCase) Create(req *dto.CommentBlockRequest) (*po.CommentBlockRule, error) {
+	value, err := normalizeBlockValue(req.Type, req.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := &po.CommentBlockRule{
+		Type:    req.Type,
+		Value:   value,
+		Action:  req.Action,
+		Note:    req.Note,
+		Enabled: req.Enabled == nil || *req.Enabled,
+	}
+	if err := uc.data.CommentBlockRepo.Create(rule); err != nil {
+		return nil, errors.New("创建屏蔽规则失败")
+	}
+	return rule, nil
+}
+
+// Update 更新屏蔽规则，enabled 为空时不修改
+func (uc *commentBlockUseCase) Update(id uint, req *dto.CommentBlockRequest) (*po.CommentBlockRule, error) {
+	rule, err := uc.data.CommentBlockRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrCommentBlockNotFound
+	}
+
+	value, err := normalizeBlockValue(req.Type, req.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.Type = req.Type
+	rule.Value = value
+	rule.Action = req.Action
+	rule.Note = req.Note
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	if err := uc.data.CommentBlockRepo.Update(rule); err != nil {
+		return nil, errors.New("更新屏蔽规则失败")
+	}
+	return rule, nil
+}
+
+// Delete 删除屏蔽规则
+func (uc *commentBlockUseCase) Delete(id uint) error {
+	if _, err := uc.data.CommentBlockRepo.FindByID(id); err != nil {
+		return ErrCommentBlockNotFound
+	}
+	if err := uc.data.CommentBlockRepo.Delete(id); err != nil {
+		return errors.New("删除屏蔽规则失败")
+	}
+	return nil
+}
+
+// normalizeBlockValue 校验并规范化规则的值：IP 统一为标准写法，邮箱和域名转为小写，关键词去掉首尾空白
+func normalizeBlockValue(ruleType, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch ruleType {
+	case po.CommentBlockIP:
+		if ip := net.ParseIP(value); ip != nil {
+			return ip.String(), nil
+		}
+		if _, network, err := net.ParseCIDR(value); err == nil {
+			return network.String(), nil
+		}
+		return "", ErrCommentBlockInvalid
+	case po.CommentBlockEmail:
+		value = strings.ToLower(strings.TrimPrefix(value, "@"))
+		if value == "" || strings.HasPrefix(value, ".") || !strings.Contains(value, ".") {
+			return "", ErrCommentBlockInvalid
+		}
+		return value, nil
+	}
+	if value == "" {
+		return "", ErrCommentBlockInvalid
+	}
+	return value, nil
+}
+
+// matchCommentBlock 返回评论命中的屏蔽规则，同时命中多条时拒绝规则优先，未命中时返回 nil
+// 规则查询失败时放行评论，只记录日志
+func matchCommentBlock(d *data.Data, ip, email, content string) *po.CommentBlockRule {
+	rules, err := d.CommentBlockRepo.ListEnabled()
+	if err != nil {
+		logger.Warn("Failed to load comment block rules: ", err)
+		return nil
+	}
+
+	var matched *po.CommentBlockRule
+	for _, rule := range rules {
+		if !blockRuleMatches(rule, ip, email, content) {
+			continue
+		}
+		if rule.Action == po.CommentBlockReject {
+			return rule
+		}
+		if matched == nil {
+			matched = rule
+		}
+	}
+	return matched
+}
+
+// blockRuleMatches 评论是否命中规则：IP 精确匹配或落在网段内，邮箱完全相同或属于该域名（含子域名），内容包含关键词（不区分大小写）
+func blockRuleMatches(rule *po.CommentBlockRule, ip, email, content string) bool {
+	switch rule.Type {
+	case po.CommentBlockIP:
+		addr := net.ParseIP(ip)
+		if addr == nil {
+			return false
+		}
+		if strings.Contains(rule.Value, "/") {
+			_, network, err := net.ParseCIDR(rule.Value)
+			return err == nil && network.Contains(addr)
+		}
+		blocked := net.ParseIP(rule.Value)
+		return blocked != nil && blocked.Equal(addr)
+	case po.CommentBlockEmail:
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email == "" {
+			return false
+		}
+		if strings.Contains(rule.Value, "@") {
+			return email == rule.Value
+		}
+		_, domain, ok := strings.Cut(email, "@")
+		return ok && (domain == rule.Value || strings.HasSuffix(domain, "."+rule.Value))
+	case po.CommentBlockKeyword:
+		return strings.Contains(strings.ToLower(content), strings.ToLower(rule.Value))
+	}
+	return false
+}
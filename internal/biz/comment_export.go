@@ -0,0 +1,148 @@
+package biz
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// 评论导出格式
+const (
+	CommentExportCSV  = "csv"
+	CommentExportJSON = "json"
+)
+
+// commentExportHeader CSV 表头，与 dto.CommentExportItem 的字段一一对应
+var commentExportHeader = []string{
+	"id", "article_id", "article_title", "user_id", "username", "nickname", "parent_id", "root_id",
+	"status", "content", "like_count", "reply_count", "created_at", "edited_at",
+}
+
+// Export 按筛选条件导出评论并流式写入 w，CSV 带 UTF-8 BOM 以便 Excel 正确识别中文
+func (uc *commentUseCase) Export(w io.Writer, req *dto.CommentExportRequest) error {
+	if req.Format == CommentExportJSON {
+		return uc.exportJSON(w, req)
+	}
+	return uc.exportCSV(w, req)
+}
+
+func (uc *commentUseCase) exportCSV(w io.Writer, req *dto.CommentExportRequest) error {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(commentExportHeader); err != nil {
+		return err
+	}
+
+	err := uc.data.CommentRepo.ExportBatches(req.ArticleID, 0, req.Status, func(comments []*po.Comment) error {
+		for _, comment := range comments {
+			item := convertToCommentExportItem(comment)
+			record := []string{
+				strconv.FormatUint(uint64(item.ID), 10),
+				formatOptionalID(item.ArticleID),
+				csvSafe(item.ArticleTitle),
+				strconv.FormatUint(uint64(item.UserID), 10),
+				csvSafe(item.Username),
+				csvSafe(item.Nickname),
+				formatOptionalID(item.ParentID),
+				formatOptionalID(item.RootID),
+				strconv.Itoa(item.Status),
+				csvSafe(item.Content),
+				strconv.Itoa(item.LikeCount),
+				strconv.Itoa(item.ReplyCount),
+				item.CreatedAt.Format(time.RFC3339),
+				"",
+			}
+			if item.EditedAt != nil {
+				record[13] = item.EditedAt.Format(time.RFC3339)
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		return fmt.Errorf("导出评论失败: %w", err)
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportJSON 逐条编码写入 JSON 数组，避免一次性加载全部评论
+func (uc *commentUseCase) exportJSON(w io.Writer, req *dto.CommentExportRequest) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	first := true
+	err := uc.data.CommentRepo.ExportBatches(req.ArticleID, 0, req.Status, func(comments []*po.Comment) error {
+		for _, comment := range comments {
+			item, err := json.Marshal(convertToCommentExportItem(comment))
+			if err != nil {
+				return err
+			}
+			if !first {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			first = false
+			if _, err := w.Write(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("导出评论失败: %w", err)
+	}
+	_, err = io.WriteString(w, "]")
+	return err
+}
+
+// convertToCommentExportItem 转换为导出格式，内容为用户提交的原始 Markdown
+func convertToCommentExportItem(comment *po.Comment) *dto.CommentExportItem {
+	item := &dto.CommentExportItem{
+		ID:         comment.ID,
+		ArticleID:  comment.ArticleID,
+		UserID:     comment.UserID,
+		Username:   comment.User.Username,
+		Nickname:   comment.User.Nickname,
+		ParentID:   comment.ParentID,
+		RootID:     comment.RootID,
+		Status:     comment.Status,
+		Content:    comment.Content,
+		LikeCount:  comment.LikeCount,
+		ReplyCount: comment.ReplyCount,
+		CreatedAt:  comment.CreatedAt,
+		EditedAt:   comment.EditedAt,
+	}
+	if comment.Article != nil {
+		item.ArticleTitle = comment.Article.Title
+	}
+	return item
+}
+
+func formatOptionalID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+// csvSafe 以 = + - @ 开头的单元格会被电子表格当作公式执行，前面加单引号作为纯文本
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,121 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/jwt"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/mail"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+const (
+	defaultVerifyTokenExpire = 24
+	// verifyResendInterval 同一用户两次发送验证邮件的最小间隔
+	verifyResendInterval = time.Minute
+	verifyResendKey      = "email_verify:resend:%d"
+)
+
+var (
+	// ErrRegistrationClosed 系统设置关闭了注册
+	ErrRegistrationClosed = errors.New("暂未开放注册")
+	// ErrEmailUnverified 邮箱未验证，不能登录
+	ErrEmailUnverified = errors.New("邮箱未验证，请先点击验证邮件中的链接完成验证")
+	// ErrVerifyTokenInvalid 验证链接无效或已过期
+	ErrVerifyTokenInvalid = errors.New("验证链接无效或已过期")
+	// ErrVerifyTooFrequent 发送验证邮件过于频繁
+	ErrVerifyTooFrequent = errors.New("发送过于频繁，请稍后再试")
+)
+
+// registrationAllowed 是否开放注册，由系统设置 allow_registration 控制，默认开放
+func (uc *blogUseCase) registrationAllowed() bool {
+	return settingBool(uc.data, SettingAllowRegistration)
+}
+
+// emailVerificationRequired 注册后是否需要验证邮箱，未配置邮件服务时无法发送验证邮件，不要求验证
+func (uc *blogUseCase) emailVerificationRequired() bool {
+	if !settingBool(uc.data, SettingRequireEmailVerification) {
+		return false
+	}
+	if !mail.Enabled() {
+		logger.Warn("Email verification is enabled but mail is not configured, skipping verification")
+		return false
+	}
+	return true
+}
+
+// VerifyEmail 校验验证令牌并激活账号，邮箱在令牌签发后变更时令牌失效
+func (uc *blogUseCase) VerifyEmail(token string) error {
+	claims, err := jwt.ParseEmailVerifyToken(token)
+	if err != nil {
+		return ErrVerifyTokenInvalid
+	}
+	user, err := uc.data.UserRepo.FindByID(claims.UserID)
+	if err != nil || !strings.EqualFold(user.Email, claims.Email) {
+		return ErrVerifyTokenInvalid
+	}
+
+	switch user.Status {
+	case po.UserStatusActive:
+		return nil
+	case po.UserStatusUnverified:
+		user.Status = po.UserStatusActive
+		if err := uc.data.UserRepo.Update(user); err != nil {
+			return errors.New("验证邮箱失败")
+		}
+		return nil
+	}
+	return errors.New("账号已被禁用")
+}
+
+// ResendVerification 重新发送验证邮件
+// 邮箱不存在或已验证时同样返回成功，避免通过该接口探测已注册的邮箱
+func (uc *blogUseCase) ResendVerification(ctx context.Context, email string) error {
+	user, err := uc.data.UserRepo.FindByEmail(email)
+	if err != nil || user.Status != po.UserStatusUnverified {
+		return nil
+	}
+	if redis.Client != nil {
+		ok, err := redis.SetNX(fmt.Sprintf(verifyResendKey, user.ID), 1, verifyResendInterval)
+		if err == nil && !ok {
+			return ErrVerifyTooFrequent
+		}
+	}
+	return sendVerificationEmail(ctx, user)
+}
+
+// sendVerificationEmail 发送包含验证链接的邮件
+func sendVerificationEmail(ctx context.Context, user *po.User) error {
+	expire := config.AppConfig.Register.TokenExpire
+	if expire <= 0 {
+		expire = defaultVerifyTokenExpire
+	}
+	token, err := jwt.GenerateEmailVerifyToken(user.ID, user.Email, time.Duration(expire)*time.Hour)
+	if err != nil {
+		return errors.New("生成验证链接失败")
+	}
+
+	link := verifyURL(token)
+	data := mail.Data{"Name": mailName(user), "ExpireHours": expire, "Link": link}
+	if err := mail.SendTemplate(ctx, user.Email, mail.TemplateVerifyEmail, data); err != nil {
+		logger.FromContext(ctx).Error("Failed to send verification email: ", err)
+		return errors.New("发送验证邮件失败")
+	}
+	return nil
+}
+
+// verifyURL 前台验证页面地址
+func verifyURL(token string) string {
+	pattern := config.AppConfig.Register.VerifyURL
+	if pattern == "" {
+		pattern = strings.TrimRight(config.AppConfig.Sitemap.SiteURL, "/") + "/verify-email?token={token}"
+	}
+	return strings.ReplaceAll(pattern, "{token}", url.QueryEscape(token))
+}
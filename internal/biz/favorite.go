@@ -0,0 +1,335 @@
+package biz
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// maxFavoriteFolders 每个用户最多可创建的收藏夹数
+const maxFavoriteFolders = 50
+
+// defaultFavoriteFolder 默认收藏夹名称
+const defaultFavoriteFolder = "默认收藏夹"
+
+var (
+	// ErrFavoriteArticleNotFound 收藏的文章不存在或未发布
+	ErrFavoriteArticleNotFound = errors.New("文章不存在或未发布")
+	// ErrFavoriteExists 已收藏过该文章
+	ErrFavoriteExists = errors.New("已经收藏过了")
+	// ErrFavoriteFolderNotFound 收藏夹不存在或不属于当前用户
+	ErrFavoriteFolderNotFound = errors.New("收藏夹不存在")
+	// ErrFavoriteFolderNameEmpty 收藏夹名称为空
+	ErrFavoriteFolderNameEmpty = errors.New("收藏夹名称不能为空")
+	// ErrFavoriteFolderExists 收藏夹重名
+	ErrFavoriteFolderExists = errors.New("收藏夹名称已存在")
+	// ErrFavoriteFolderLimit 收藏夹数量达到上限
+	ErrFavoriteFolderLimit = errors.New("收藏夹数量已达上限")
+)
+
+// FavoriteUseCase 收藏夹业务用例接口
+type FavoriteUseCase interface {
+	// ListFolders 查询当前用户的收藏夹，第一个为默认收藏夹
+	ListFolders(userID uint) ([]*dto.FavoriteFolderInfo, error)
+	// CreateFolder 创建收藏夹
+	CreateFolder(userID uint, req *dto.FavoriteFolderRequest) (*dto.FavoriteFolderInfo, error)
+	// UpdateFolder 修改收藏夹名称和描述
+	UpdateFolder(userID, id uint, req *dto.FavoriteFolderRequest) (*dto.FavoriteFolderInfo, error)
+	// DeleteFolder 删除收藏夹，其中的收藏移回默认收藏夹
+	DeleteFolder(userID, id uint) error
+	// List 分页查询当前用户的收藏
+	List(userID uint, req *dto.FavoriteListRequest) (*dto.PageResponse, error)
+	// Add 收藏文章到指定收藏夹
+	Add(userID uint, req *dto.AddFavoriteRequest) error
+	// Remove 取消收藏，未收藏时直接返回
+	Remove(userID, articleID uint) error
+	// Move 将收藏移动到指定收藏夹，返回移动的收藏数
+	Move(userID uint, req *dto.MoveFavoritesRequest) (int64, error)
+}
+
+// favoriteUseCase 收藏夹业务用例实现
+type favoriteUseCase struct {
+	data *data.Data
+}
+
+// NewFavoriteUseCase 创建收藏夹业务用例
+func NewFavoriteUseCase(d *data.Data) FavoriteUseCase {
+	return &favoriteUseCase{data: d}
+}
+
+// ListFolders 查询当前用户的收藏夹及各自的收藏数
+func (uc *favoriteUseCase) ListFolders(userID uint) ([]*dto.FavoriteFolderInfo, error) {
+	folders, err := uc.data.FavoriteFolderRepo.ListByUser(userID)
+	if err != nil {
+		return nil, errors.New("查询收藏夹失败")
+	}
+	counts, err := uc.data.FavoriteRepo.CountByFolder(userID)
+	if err != nil {
+		return nil, errors.New("查询收藏夹失败")
+	}
+
+	items := make([]*dto.FavoriteFolderInfo, 0, len(folders)+1)
+	items = append(items, &dto.FavoriteFolderInfo{Name: defaultFavoriteFolder, Count: counts[0]})
+	for _, folder := range folders {
+		info := favoriteFolderInfo(folder)
+		info.Count = counts[folder.ID]
+		items = append(items, info)
+	}
+	return items, nil
+}
+
+// CreateFolder 创建收藏夹
+func (uc *favoriteUseCase) CreateFolder(userID uint, req *dto.FavoriteFolderRequest) (*dto.FavoriteFolderInfo, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := uc.checkFolderName(userID, name, 0); err != nil {
+		return nil, err
+	}
+	count, err := uc.data.FavoriteFolderRepo.CountByUser(userID)
+	if err != nil {
+		return nil, errors.New("创建收藏夹失败")
+	}
+	if count >= maxFavoriteFolders {
+		return nil, ErrFavoriteFolderLimit
+	}
+
+	folder := &po.FavoriteFolder{
+		UserID:      userID,
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+	}
+	if err := uc.data.FavoriteFolderRepo.Create(folder); err != nil {
+		return nil, errors.New("创建收藏夹失败")
+	}
+	return favoriteFolderInfo(folder), nil
+}
+
+// UpdateFolder 修改收藏夹名称和描述
+func (uc *favoriteUseCase) UpdateFolder(userID, id uint, req *dto.FavoriteFolderRequest) (*dto.FavoriteFolderInfo, error) {
+	folder, err := uc.data.FavoriteFolderRepo.FindByID(userID, id)
+	if err != nil {
+		return nil, ErrFavoriteFolderNotFound
+	}
+	name := strings.TrimSpace(req.Name)
+	if err := uc.checkFolderName(userID, name, id); err != nil {
+		return nil, err
+	}
+
+	folder.Name = name
+	folder.Description = strings.TrimSpace(req.Description)
+	if err := uc.data.FavoriteFolderRepo.Update(folder); err != nil {
+		return nil, errors.New("修改收藏夹失败")
+	}
+	return favoriteFolderInfo(folder), nil
+}
+
+// DeleteFolder 删除收藏夹，收藏本身保留在默认收藏夹中，文章收藏数不变
+func (uc *favoriteUseCase) DeleteFolder(userID, id uint) error {
+	folder, err := uc.data.FavoriteFolderRepo.FindByID(userID, id)
+	if err != nil {
+		return ErrFavoriteFolderNotFound
+	}
+	if err := uc.data.FavoriteFolderRepo.Delete(folder); err != nil {
+		return errors.New("删除收藏夹失败")
+	}
+	return nil
+}
+
+// List 分页查询当前用户的收藏，按收藏时间倒序
+func (uc *favoriteUseCase) List(userID uint, req *dto.FavoriteListRequest) (*dto.PageResponse, error) {
+	if req.FolderID != nil && *req.FolderID != 0 {
+		if _, err := uc.data.FavoriteFolderRepo.FindByID(userID, *req.FolderID); err != nil {
+			return nil, ErrFavoriteFolderNotFound
+		}
+	}
+
+	favorites, total, err := uc.data.FavoriteRepo.ListByFolder(userID, req.FolderID, req.Page, req.Limit)
+	if err != nil {
+		return nil, errors.New("查询收藏列表失败")
+	}
+	return &dto.PageResponse{Total: total, Page: req.Page, Limit: req.Limit, Data: favoriteInfos(favorites)}, nil
+}
+
+// Add 收藏文章到指定收藏夹
+func (uc *favoriteUseCase) Add(userID uint, req *dto.AddFavoriteRequest) error {
+	if err := uc.checkFolder(userID, req.FolderID); err != nil {
+		return err
+	}
+	return addFavorite(uc.data, userID, req.ArticleID, req.FolderID)
+}
+
+// Remove 取消收藏
+func (uc *favoriteUseCase) Remove(userID, articleID uint) error {
+	if err := removeFavorite(uc.data, userID, articleID); err != nil {
+		return errors.New("取消收藏失败")
+	}
+	return nil
+}
+
+// Move 将收藏移动到指定收藏夹，未收藏的文章会被忽略
+func (uc *favoriteUseCase) Move(userID uint, req *dto.MoveFavoritesRequest) (int64, error) {
+	if err := uc.checkFolder(userID, req.FolderID); err != nil {
+		return 0, err
+	}
+	moved, err := uc.data.FavoriteRepo.Move(userID, req.ArticleIDs, req.FolderID)
+	if err != nil {
+		return 0, errors.New("移动收藏失败")
+	}
+	return moved, nil
+}
+
+// checkFolder 校验收藏夹属于当前用户，0 表示默认收藏夹
+func (uc *favoriteUseCase) checkFolder(userID, folderID uint) error {
+	if folderID == 0 {
+		return nil
+	}
+	if _, err := uc.data.FavoriteFolderRepo.FindByID(userID, folderID); err != nil {
+		return ErrFavoriteFolderNotFound
+	}
+	return nil
+}
+
+// checkFolderName 校验收藏夹名称不为空且不与其他收藏夹（包括默认收藏夹）重名
+func (uc *favoriteUseCase) checkFolderName(userID uint, name string, excludeID uint) error {
+	if name == "" {
+		return ErrFavoriteFolderNameEmpty
+	}
+	if name == defaultFavoriteFolder {
+		return ErrFavoriteFolderExists
+	}
+	exists, err := uc.data.FavoriteFolderRepo.NameExists(userID, name, excludeID)
+	if err != nil {
+		return errors.New("查询收藏夹失败")
+	}
+	if exists {
+		return ErrFavoriteFolderExists
+	}
+	return nil
+}
+
+// addFavorite 创建收藏记录并增加文章收藏数，只能收藏已发布的公开文章，每个用户对同一文章只有一条收藏记录
+func addFavorite(d *data.Data, userID, articleID, folderID uint) error {
+	article, err := d.ArticleRepo.FindByID(articleID)
+	if err != nil || article.Status != 1 || !articleVisible(article) {
+		return ErrFavoriteArticleNotFound
+	}
+
+	exists, err := d.FavoriteRepo.Exists(articleID, userID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrFavoriteExists
+	}
+
+	favorite := &po.Favorite{
+		UserID:    userID,
+		ArticleID: articleID,
+		CreatedAt: time.Now(),
+	}
+	if folderID != 0 {
+		favorite.FolderID = &folderID
+	}
+	// 并发收藏同一文章时只有实际新增记录的请求累加收藏数
+	created, err := d.FavoriteRepo.Create(favorite)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return ErrFavoriteExists
+	}
+
+	return incrArticleCounter(d, articleID, counterFavorites, 1)
+}
+
+// removeFavorite 删除收藏记录，只有确实删除了记录时才减少文章收藏数，重复取消不会使计数变少
+func removeFavorite(d *data.Data, userID, articleID uint) error {
+	deleted, err := d.FavoriteRepo.Delete(articleID, userID)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return nil
+	}
+	return incrArticleCounter(d, articleID, counterFavorites, -deleted)
+}
+
+// favoriteFolderInfo 转换为收藏夹信息
+func favoriteFolderInfo(folder *po.FavoriteFolder) *dto.FavoriteFolderInfo {
+	return &dto.FavoriteFolderInfo{
+		ID:          folder.ID,
+		Name:        folder.Name,
+		Description: folder.Description,
+		CreatedAt:   folder.CreatedAt,
+	}
+}
+
+// favoriteInfos 转换为收藏列表，包含文章的作者、分类和标签
+func favoriteInfos(favorites []*po.Favorite) []dto.FavoriteInfo {
+	favoriteList := make([]dto.FavoriteInfo, 0, len(favorites))
+	for _, favorite := range favorites {
+		articleResp := &dto.ArticleResponse{
+			ID:            favorite.Article.ID,
+			Title:         favorite.Article.Title,
+			Slug:          articleSlug(&favorite.Article),
+			Summary:       favorite.Article.Summary,
+			Cover:         favorite.Article.Cover,
+			AuthorID:      favorite.Article.AuthorID,
+			CategoryID:    favorite.Article.CategoryID,
+			Status:        favorite.Article.Status,
+			ViewCount:     favorite.Article.ViewCount,
+			LikeCount:     favorite.Article.LikeCount,
+			FavoriteCount: favorite.Article.FavoriteCount,
+			CommentCount:  favorite.Article.CommentCount,
+			CreatedAt:     favorite.Article.CreatedAt,
+		}
+
+		// 添加作者信息
+		if favorite.Article.Author.ID > 0 {
+			articleResp.Author = &dto.AuthorInfo{
+				ID:       favorite.Article.Author.ID,
+				Username: favorite.Article.Author.Username,
+				Nickname: favorite.Article.Author.Nickname,
+				Avatar:   favorite.Article.Author.Avatar,
+			}
+		}
+
+		// 添加分类信息
+		if favorite.Article.Category.ID > 0 {
+			articleResp.Category = &dto.CategoryInfo{
+				ID:          favorite.Article.Category.ID,
+				Name:        favorite.Article.Category.Name,
+				Description: favorite.Article.Category.Description,
+			}
+		}
+
+		// 添加标签信息
+		if len(favorite.Article.Tags) > 0 {
+			tags := make([]dto.TagInfo, 0, len(favorite.Article.Tags))
+			for _, tag := range favorite.Article.Tags {
+				tags = append(tags, dto.TagInfo{
+					ID:    tag.ID,
+					Name:  tag.Name,
+					Color: tag.Color,
+				})
+			}
+			articleResp.Tags = tags
+		}
+
+		info := dto.FavoriteInfo{
+			ID:        favorite.ID,
+			ArticleID: favorite.ArticleID,
+			UserID:    favorite.UserID,
+			CreatedAt: favorite.CreatedAt,
+			Article:   articleResp,
+		}
+		if favorite.FolderID != nil {
+			info.FolderID = *favorite.FolderID
+		}
+		favoriteList = append(favoriteList, info)
+	}
+	return favoriteList
+}
@@ -0,0 +1,226 @@
+package biz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// stubCounterArticleRepo 内存中的文章仓储，记录收藏数
+type stubCounterArticleRepo struct {
+	data.ArticleRepo
+	articles map[uint]*po.Article
+}
+
+func (r *stubCounterArticleRepo) FindByID(id uint) (*po.Article, error) {
+	article, ok := r.articles[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return article, nil
+}
+
+func (r *stubCounterArticleRepo) AddCounters(id uint, deltas map[string]int64) error {
+	r.articles[id].FavoriteCount += int(deltas[counterFavorites])
+	return nil
+}
+
+// stubFavoriteRepo 内存中的收藏仓储，键为 {用户ID, 文章ID}
+type stubFavoriteRepo struct {
+	data.FavoriteRepo
+	favorites map[[2]uint]*po.Favorite
+}
+
+func (r *stubFavoriteRepo) Create(favorite *po.Favorite) (bool, error) {
+	key := [2]uint{favorite.UserID, favorite.ArticleID}
+	if _, ok := r.favorites[key]; ok {
+		return false, nil
+	}
+	r.favorites[key] = favorite
+	return true, nil
+}
+
+func (r *stubFavoriteRepo) Delete(articleID, userID uint) (int64, error) {
+	key := [2]uint{userID, articleID}
+	if _, ok := r.favorites[key]; !ok {
+		return 0, nil
+	}
+	delete(r.favorites, key)
+	return 1, nil
+}
+
+func (r *stubFavoriteRepo) Exists(articleID, userID uint) (bool, error) {
+	_, ok := r.favorites[[2]uint{userID, articleID}]
+	return ok, nil
+}
+
+func (r *stubFavoriteRepo) Move(userID uint, articleIDs []uint, folderID uint) (int64, error) {
+	var moved int64
+	for _, articleID := range articleIDs {
+		if favorite, ok := r.favorites[[2]uint{userID, articleID}]; ok {
+			favorite.FolderID = nil
+			if folderID != 0 {
+				id := folderID
+				favorite.FolderID = &id
+			}
+			moved++
+		}
+	}
+	return moved, nil
+}
+
+// stubFavoriteFolderRepo 内存中的收藏夹仓储
+type stubFavoriteFolderRepo struct {
+	data.FavoriteFolderRepo
+	folders []*po.FavoriteFolder
+}
+
+func (r *stubFavoriteFolderRepo) FindByID(userID, id uint) (*po.FavoriteFolder, error) {
+	for _, folder := range r.folders {
+		if folder.ID == id && folder.UserID == userID {
+			return folder, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubFavoriteFolderRepo) NameExists(userID uint, name string, excludeID uint) (bool, error) {
+	for _, folder := range r.folders {
+		if folder.UserID == userID && folder.Name == name && folder.ID != excludeID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *stubFavoriteFolderRepo) CountByUser(userID uint) (int64, error) {
+	var count int64
+	for _, folder := range r.folders {
+		if folder.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *stubFavoriteFolderRepo) Create(folder *po.FavoriteFolder) error {
+	folder.ID = uint(len(r.folders) + 1)
+	r.folders = append(r.folders, folder)
+	return nil
+}
+
+func newFavoriteTestData() (*data.Data, *stubCounterArticleRepo, *stubFavoriteRepo) {
+	articles := &stubCounterArticleRepo{articles: map[uint]*po.Article{
+		1: {ID: 1, Status: 1},
+		2: {ID: 2, Status: 0},
+	}}
+	favorites := &stubFavoriteRepo{favorites: map[[2]uint]*po.Favorite{}}
+	folders := &stubFavoriteFolderRepo{folders: []*po.FavoriteFolder{{ID: 7, UserID: 1, Name: "Go"}}}
+	return &data.Data{ArticleRepo: articles, FavoriteRepo: favorites, FavoriteFolderRepo: folders}, articles, favorites
+}
+
+func TestFavoriteCountStaysConsistent(t *testing.T) {
+	d, articles, favorites := newFavoriteTestData()
+	uc := NewFavoriteUseCase(d)
+
+	if err := uc.Add(1, &dto.AddFavoriteRequest{ArticleID: 1, FolderID: 7}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := uc.Add(1, &dto.AddFavoriteRequest{ArticleID: 1}); !errors.Is(err, ErrFavoriteExists) {
+		t.Fatalf("duplicate Add = %v, want ErrFavoriteExists", err)
+	}
+	if got := favorites.favorites[[2]uint{1, 1}].FolderID; got == nil || *got != 7 {
+		t.Fatalf("favorite folder = %v, want 7", got)
+	}
+
+	// 重复取消收藏不应使计数变为负数
+	for i := 0; i < 2; i++ {
+		if err := uc.Remove(1, 1); err != nil {
+			t.Fatalf("Remove: %v", err)
+		}
+	}
+	if got := articles.articles[1].FavoriteCount; got != 0 {
+		t.Fatalf("favorite_count = %d, want 0", got)
+	}
+}
+
+// racingFavoriteRepo 模拟并发收藏：存在性检查时另一个请求尚未写入，创建时已被唯一索引拦截
+type racingFavoriteRepo struct {
+	*stubFavoriteRepo
+}
+
+func (r racingFavoriteRepo) Exists(articleID, userID uint) (bool, error) {
+	return false, nil
+}
+
+func TestFavoriteConcurrentAddCountsOnce(t *testing.T) {
+	d, articles, favorites := newFavoriteTestData()
+	d.FavoriteRepo = racingFavoriteRepo{favorites}
+	uc := NewFavoriteUseCase(d)
+
+	if err := uc.Add(1, &dto.AddFavoriteRequest{ArticleID: 1}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := uc.Add(1, &dto.AddFavoriteRequest{ArticleID: 1}); !errors.Is(err, ErrFavoriteExists) {
+		t.Fatalf("concurrent Add = %v, want ErrFavoriteExists", err)
+	}
+	if got := articles.articles[1].FavoriteCount; got != 1 {
+		t.Fatalf("favorite_count = %d, want 1", got)
+	}
+}
+
+func TestFavoriteRejectsUnpublishedAndForeignFolder(t *testing.T) {
+	d, _, _ := newFavoriteTestData()
+	uc := NewFavoriteUseCase(d)
+
+	if err := uc.Add(1, &dto.AddFavoriteRequest{ArticleID: 2}); !errors.Is(err, ErrFavoriteArticleNotFound) {
+		t.Fatalf("Add draft = %v, want ErrFavoriteArticleNotFound", err)
+	}
+	if err := uc.Add(2, &dto.AddFavoriteRequest{ArticleID: 1, FolderID: 7}); !errors.Is(err, ErrFavoriteFolderNotFound) {
+		t.Fatalf("Add to other user's folder = %v, want ErrFavoriteFolderNotFound", err)
+	}
+	if _, err := uc.Move(2, &dto.MoveFavoritesRequest{ArticleIDs: []uint{1}, FolderID: 7}); !errors.Is(err, ErrFavoriteFolderNotFound) {
+		t.Fatalf("Move to other user's folder = %v, want ErrFavoriteFolderNotFound", err)
+	}
+}
+
+func TestFavoriteMoveBackToDefault(t *testing.T) {
+	d, _, favorites := newFavoriteTestData()
+	uc := NewFavoriteUseCase(d)
+
+	if err := uc.Add(1, &dto.AddFavoriteRequest{ArticleID: 1, FolderID: 7}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	moved, err := uc.Move(1, &dto.MoveFavoritesRequest{ArticleIDs: []uint{1, 3}})
+	if err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	if moved != 1 {
+		t.Fatalf("moved = %d, want 1", moved)
+	}
+	if got := favorites.favorites[[2]uint{1, 1}].FolderID; got != nil {
+		t.Fatalf("favorite folder = %d, want default", *got)
+	}
+}
+
+func TestFavoriteFolderNames(t *testing.T) {
+	d, _, _ := newFavoriteTestData()
+	uc := NewFavoriteUseCase(d)
+
+	for _, name := range []string{"Go", " Go ", defaultFavoriteFolder} {
+		if _, err := uc.CreateFolder(1, &dto.FavoriteFolderRequest{Name: name}); !errors.Is(err, ErrFavoriteFolderExists) {
+			t.Fatalf("CreateFolder(%q) = %v, want ErrFavoriteFolderExists", name, err)
+		}
+	}
+	if _, err := uc.CreateFolder(1, &dto.FavoriteFolderRequest{Name: "   "}); !errors.Is(err, ErrFavoriteFolderNameEmpty) {
+		t.Fatalf("CreateFolder(blank) = %v, want ErrFavoriteFolderNameEmpty", err)
+	}
+	// 其他用户可以使用同名收藏夹
+	if _, err := uc.CreateFolder(2, &dto.FavoriteFolderRequest{Name: "Go"}); err != nil {
+		t.Fatalf("CreateFolder for another user: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/feed"
+)
+
+// commentFeedSize 评论订阅中的条目数
+const commentFeedSize = 50
+
+// ErrFeedDisabled 未配置前台地址，无法生成订阅中的链接
+var ErrFeedDisabled = errors.New("订阅未启用")
+
+// ErrFeedNotFound 订阅的内容不存在或不公开
+var ErrFeedNotFound = errors.New("文章不存在或未发布")
+
+// FeedUseCase RSS 订阅业务用例接口
+type FeedUseCase interface {
+	// ArticleComments 文章评论的 RSS 订阅，selfURL 为订阅地址本身
+	ArticleComments(articleID uint, selfURL string) ([]byte, error)
+}
+
+// feedUseCase RSS 订阅业务用例实现
+type feedUseCase struct {
+	data *data.Data
+}
+
+// NewFeedUseCase 创建 RSS 订阅业务用例
+func NewFeedUseCase(d *data.Data) FeedUseCase {
+	return &feedUseCase{data: d}
+}
+
+// ArticleComments 生成文章最新评论的订阅，只支持已发布的公开文章（加密文章的评论同样不公开）
+// 条目链接为前台文章地址加 #comment-{id} 锚点，前台地址与站点地图使用同一配置
+func (uc *feedUseCase) ArticleComments(articleID uint, selfURL string) ([]byte, error) {
+	if config.AppConfig.Sitemap.SiteURL == "" {
+		return nil, ErrFeedDisabled
+	}
+
+	article, err := uc.data.ArticleRepo.FindByID(articleID)
+	if err != nil || article.Status != 1 || !articleVisible(article) || articleLocked(article) {
+		return nil, ErrFeedNotFound
+	}
+
+	comments, err := uc.data.CommentRepo.ListLatestByArticle(articleID, commentFeedSize)
+	if err != nil {
+		return nil, errors.New("查询评论失败")
+	}
+
+	articleURL := frontendArticleURL(article)
+	channel := feed.Channel{
+		Title:       fmt.Sprintf("《%s》的评论", article.Title),
+		Link:        articleURL,
+		SelfURL:     selfURL,
+		Description: fmt.Sprintf("文章《%s》的最新评论", article.Title),
+		Language:    "zh-cn",
+		Items:       make([]feed.Item, 0, len(comments)),
+	}
+	for _, comment := range comments {
+		author := commentAuthorName(&comment.User)
+		title := fmt.Sprintf("%s 评论了《%s》", author, article.Title)
+		if comment.ReplyToUser != nil {
+			title = fmt.Sprintf("%s 回复了 %s", author, commentAuthorName(comment.ReplyToUser))
+		}
+		channel.Items = append(channel.Items, feed.Item{
+			Title:       title,
+			Link:        articleURL + "#comment-" + strconv.FormatUint(uint64(comment.ID), 10),
+			GUID:        "comment-" + strconv.FormatUint(uint64(comment.ID), 10),
+			Author:      author,
+			Description: commentHTML(comment),
+			PubDate:     comment.CreatedAt,
+		})
+	}
+
+	content, err := feed.Build(channel)
+	if err != nil {
+		return nil, errors.New("生成订阅失败")
+	}
+	return content, nil
+}
+
+// frontendArticleURL 文章的前台地址，格式与站点地图一致
+func frontendArticleURL(article *po.Article) string {
+	slug := articleSlug(article)
+	if slug == "" {
+		slug = strconv.FormatUint(uint64(article.ID), 10)
+	}
+	cfg := config.AppConfig.Sitemap
+	return strings.TrimRight(cfg.SiteURL, "/") + expandPath(pathOrDefault(cfg.ArticlePath, defaultArticlePath), "{slug}", slug, article.ID)
+}
+
+// commentAuthorName 评论作者的显示名称，优先使用昵称
+func commentAuthorName(user *po.User) string {
+	if user.Nickname != "" {
+		return user.Nickname
+	}
+	return user.Username
+}
@@ -0,0 +1,173 @@
+package biz
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// ErrFollowSelf 不能关注自己
+var ErrFollowSelf = errors.New("不能关注自己")
+
+// FollowUseCase 关注业务用例接口
+type FollowUseCase interface {
+	// Follow 关注用户，已关注时直接返回
+	Follow(followerID uint, username string) (*dto.FollowStatusResponse, error)
+	// Unfollow 取消关注
+	Unfollow(followerID uint, username string) (*dto.FollowStatusResponse, error)
+	// ListFollowers 查询用户的粉丝
+	ListFollowers(username string, req *dto.PageRequest) (*dto.PageResponse, error)
+	// ListFollowing 查询用户关注的人
+	ListFollowing(username string, req *dto.PageRequest) (*dto.PageResponse, error)
+	// Feed 查询关注的作者最近发布的文章
+	Feed(userID uint, req *dto.PageRequest) (*dto.PageResponse, error)
+}
+
+// followUseCase 关注业务用例实现
+type followUseCase struct {
+	data     *data.Data
+	articles *articleUseCase
+}
+
+// NewFollowUseCase 创建关注业务用例
+func NewFollowUseCase(d *data.Data) FollowUseCase {
+	return &followUseCase{data: d, articles: &articleUseCase{data: d}}
+}
+
+// Follow 关注用户，首次关注时通知对方
+func (uc *followUseCase) Follow(followerID uint, username string) (*dto.FollowStatusResponse, error) {
+	followee, err := uc.activeUser(username)
+	if err != nil {
+		return nil, err
+	}
+	if followee.ID == followerID {
+		return nil, ErrFollowSelf
+	}
+
+	following, err := uc.data.FollowRepo.Exists(followerID, followee.ID)
+	if err != nil {
+		return nil, errors.New("关注失败")
+	}
+	follow := &po.Follow{FollowerID: followerID, FolloweeID: followee.ID, CreatedAt: time.Now()}
+	if err := uc.data.FollowRepo.Create(follow); err != nil {
+		return nil, errors.New("关注失败")
+	}
+	// 重复关注不再通知
+	if !following {
+		publishFollow(uc.data, followerID, followee.ID)
+	}
+	return uc.status(followerID, followee.ID)
+}
+
+// Unfollow 取消关注，已禁用的用户也可以取消
+func (uc *followUseCase) Unfollow(followerID uint, username string) (*dto.FollowStatusResponse, error) {
+	followee, err := uc.data.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, ErrProfileNotFound
+	}
+
+	if err := uc.data.FollowRepo.Delete(followerID, followee.ID); err != nil {
+		return nil, errors.New("取消关注失败")
+	}
+	return uc.status(followerID, followee.ID)
+}
+
+// ListFollowers 查询用户的粉丝
+func (uc *followUseCase) ListFollowers(username string, req *dto.PageRequest) (*dto.PageResponse, error) {
+	user, err := uc.activeUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	follows, total, err := uc.data.FollowRepo.ListFollowers(user.ID, req.Page, req.Limit)
+	if err != nil {
+		return nil, errors.New("查询粉丝列表失败")
+	}
+
+	items := make([]*dto.FollowUserInfo, 0, len(follows))
+	for _, follow := range follows {
+		if follow.Follower != nil {
+			items = append(items, followUserInfo(follow.Follower, follow.CreatedAt))
+		}
+	}
+	return &dto.PageResponse{Total: total, Page: req.Page, Limit: req.Limit, Data: items}, nil
+}
+
+// ListFollowing 查询用户关注的人
+func (uc *followUseCase) ListFollowing(username string, req *dto.PageRequest) (*dto.PageResponse, error) {
+	user, err := uc.activeUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	follows, total, err := uc.data.FollowRepo.ListFollowing(user.ID, req.Page, req.Limit)
+	if err != nil {
+		return nil, errors.New("查询关注列表失败")
+	}
+
+	items := make([]*dto.FollowUserInfo, 0, len(follows))
+	for _, follow := range follows {
+		if follow.Followee != nil {
+			items = append(items, followUserInfo(follow.Followee, follow.CreatedAt))
+		}
+	}
+	return &dto.PageResponse{Total: total, Page: req.Page, Limit: req.Limit, Data: items}, nil
+}
+
+// Feed 查询关注的作者（主作者或共同作者）最近发布的文章，不包含私密文章
+func (uc *followUseCase) Feed(userID uint, req *dto.PageRequest) (*dto.PageResponse, error) {
+	fields, err := parseListFields("")
+	if err != nil {
+		return nil, err
+	}
+
+	articles, total, err := uc.data.ArticleRepo.WithFields(fields.columns, fields.preloads).PublicOnly().
+		ListFollowed(req.Page, req.Limit, userID)
+	if err != nil {
+		return nil, errors.New("查询关注动态失败")
+	}
+
+	return &dto.PageResponse{
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Data:  uc.articles.convertToListItems(articles, true),
+	}, nil
+}
+
+// activeUser 查询可公开展示的用户
+func (uc *followUseCase) activeUser(username string) (*po.User, error) {
+	user, err := uc.data.UserRepo.FindByUsername(username)
+	if err != nil || user.Status != po.UserStatusActive {
+		return nil, ErrProfileNotFound
+	}
+	return user, nil
+}
+
+// status 查询关注状态和对方的粉丝数
+func (uc *followUseCase) status(followerID, followeeID uint) (*dto.FollowStatusResponse, error) {
+	following, err := uc.data.FollowRepo.Exists(followerID, followeeID)
+	if err != nil {
+		return nil, errors.New("查询关注状态失败")
+	}
+	count, err := uc.data.FollowRepo.CountFollowers(followeeID)
+	if err != nil {
+		return nil, errors.New("查询关注状态失败")
+	}
+	return &dto.FollowStatusResponse{Following: following, FollowerCount: count}, nil
+}
+
+// followUserInfo 转换为关注列表中的用户
+func followUserInfo(user *po.User, followedAt time.Time) *dto.FollowUserInfo {
+	return &dto.FollowUserInfo{
+		ID:         user.ID,
+		Username:   user.Username,
+		Nickname:   user.Nickname,
+		Avatar:     user.Avatar,
+		Bio:        user.Bio,
+		FollowedAt: followedAt,
+	}
+}
@@ -0,0 +1,84 @@
+package biz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// stubFollowRepo 内存中的关注仓储
+type stubFollowRepo struct {
+	data.FollowRepo
+	follows map[[2]uint]bool
+}
+
+func (r *stubFollowRepo) Create(follow *po.Follow) error {
+	r.follows[[2]uint{follow.FollowerID, follow.FolloweeID}] = true
+	return nil
+}
+
+func (r *stubFollowRepo) Delete(followerID, followeeID uint) error {
+	delete(r.follows, [2]uint{followerID, followeeID})
+	return nil
+}
+
+func (r *stubFollowRepo) Exists(followerID, followeeID uint) (bool, error) {
+	return r.follows[[2]uint{followerID, followeeID}], nil
+}
+
+func (r *stubFollowRepo) CountFollowers(userID uint) (int64, error) {
+	var count int64
+	for pair := range r.follows {
+		if pair[1] == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func TestFollow(t *testing.T) {
+	users := &stubUserRepo{users: map[uint]*po.User{
+		1: {ID: 1, Username: "reader", Status: po.UserStatusActive},
+		2: {ID: 2, Username: "author", Status: po.UserStatusActive},
+		3: {ID: 3, Username: "banned", Status: po.UserStatusBanned},
+		4: {ID: 4, Username: "fan", Status: po.UserStatusActive},
+	}}
+	follows := &stubFollowRepo{follows: map[[2]uint]bool{}}
+	uc := NewFollowUseCase(&data.Data{UserRepo: users, FollowRepo: follows})
+
+	tests := []struct {
+		name       string
+		followerID uint
+		username   string
+		unfollow   bool
+		err        error
+		following  bool
+		followers  int64
+	}{
+		{name: "follow", followerID: 1, username: "author", following: true, followers: 1},
+		{name: "follow again", followerID: 1, username: "author", following: true, followers: 1},
+		{name: "second follower", followerID: 4, username: "author", following: true, followers: 2},
+		{name: "self", followerID: 2, username: "author", err: ErrFollowSelf},
+		{name: "banned user", followerID: 1, username: "banned", err: ErrProfileNotFound},
+		{name: "missing user", followerID: 1, username: "nobody", err: ErrProfileNotFound},
+		{name: "unfollow", followerID: 1, username: "author", unfollow: true, following: false, followers: 1},
+		{name: "unfollow again", followerID: 1, username: "author", unfollow: true, following: false, followers: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			follow := uc.Follow
+			if tt.unfollow {
+				follow = uc.Unfollow
+			}
+			status, err := follow(tt.followerID, tt.username)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if tt.err == nil && (status.Following != tt.following || status.FollowerCount != tt.followers) {
+				t.Errorf("status = %+v, want following %v followers %d", status, tt.following, tt.followers)
+			}
+		})
+	}
+}
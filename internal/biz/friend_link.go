@@ -0,0 +1,402 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+// 友情链接默认值
+const (
+	defaultFriendLinkApplyLimit  = 3
+	defaultFriendLinkTimeout     = 10
+	defaultFriendLinkConcurrency = 5
+	defaultFriendLinkMaxFailures = 3
+	friendLinkUserAgent          = "Mozilla/5.0 (compatible; LeafBlog-LinkChecker/1.0)"
+	// friendLinkCheckLock 检测任务锁，多实例部署时同一轮只由一个实例检测
+	friendLinkCheckLock = "friend_link:check"
+)
+
+var (
+	// ErrFriendLinkNotFound 友链不存在
+	ErrFriendLinkNotFound = errors.New("友链不存在")
+	// ErrFriendLinkInvalid 网站名称为空，或网站地址、Logo 地址不是 http(s) 地址
+	ErrFriendLinkInvalid = errors.New("友链信息不合法")
+	// ErrFriendLinkExists 该网站已提交过友链
+	ErrFriendLinkExists = errors.New("该网站已提交过友链申请")
+	// ErrFriendLinkTooFrequent 同一 IP 申请过于频繁
+	ErrFriendLinkTooFrequent = errors.New("申请过于频繁，请明天再试")
+	// ErrFriendLinkApplyClosed 系统设置关闭了友链申请
+	ErrFriendLinkApplyClosed = errors.New("暂不接受友链申请")
+)
+
+// FriendLinkUseCase 友情链接业务用例接口
+type FriendLinkUseCase interface {
+	// Apply 访客申请友链，申请进入待审核状态
+	Apply(req *dto.FriendLinkApplyRequest, userID *uint, ip string) error
+	// ListVisible 前台友链列表：审核通过且未判定为死链，按排序值排列
+	ListVisible() ([]*dto.FriendLinkResponse, error)
+	// List 后台查询友链列表
+	List(req *dto.FriendLinkListRequest) (*dto.PageResponse, error)
+	// Create 管理员添加友链
+	Create(req *dto.FriendLinkRequest) (*po.FriendLink, error)
+	// Update 更新友链
+	Update(id uint, req *dto.FriendLinkRequest) (*po.FriendLink, error)
+	// Delete 删除友链
+	Delete(id uint) error
+	// Review 审核友链申请
+	Review(id uint, req *dto.FriendLinkReviewRequest) (*po.FriendLink, error)
+	// Sort 批量调整排序值
+	Sort(req *dto.FriendLinkSortRequest) error
+	// Check 立即检测一个友链是否可以访问
+	Check(ctx context.Context, id uint) (*dto.FriendLinkCheckResponse, error)
+	// CheckAll 检测全部审核通过的友链，返回检测数和连续失败次数达到上限的友链数
+	CheckAll(ctx context.Context) (int, int, error)
+}
+
+// friendLinkUseCase 友情链接业务用例实现
+type friendLinkUseCase struct {
+	data *data.Data
+}
+
+// NewFriendLinkUseCase 创建友情链接业务用例
+func NewFriendLinkUseCase(d *data.Data) FriendLinkUseCase {
+	return &friendLinkUseCase{data: d}
+}
+
+// Apply 访客申请友链，申请进入待审核状态
+func (uc *friendLinkUseCase) Apply(req *dto.FriendLinkApplyRequest, userID *uint, ip string) error {
+	if !settingBool(uc.data, SettingFriendLinkApply) {
+		return ErrFriendLinkApplyClosed
+	}
+
+	link, err := newFriendLink(req.Name, req.URL, req.Logo, req.Description, req.Email)
+	if err != nil {
+		return err
+	}
+	if _, err := uc.data.FriendLinkRepo.FindByURL(link.URL); err == nil {
+		return ErrFriendLinkExists
+	}
+
+	limit := config.AppConfig.FriendLink.ApplyLimit
+	if limit <= 0 {
+		limit = defaultFriendLinkApplyLimit
+	}
+	count, err := uc.data.FriendLinkRepo.CountByIPSince(ip, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		return err
+	}
+	if count >= int64(limit) {
+		return ErrFriendLinkTooFrequent
+	}
+
+	link.Status = po.FriendLinkPending
+	link.UserID = userID
+	link.IP = ip
+	if err := uc.data.FriendLinkRepo.Create(link); err != nil {
+		return errors.New("提交友链申请失败")
+	}
+	return nil
+}
+
+// ListVisible 前台友链列表：审核通过且未判定为死链，按排序值排列
+func (uc *friendLinkUseCase) ListVisible() ([]*dto.FriendLinkResponse, error) {
+	links, err := uc.data.FriendLinkRepo.ListVisible(friendLinkMaxFailures())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*dto.FriendLinkResponse, 0, len(links))
+	for _, link := range links {
+		resp = append(resp, &dto.FriendLinkResponse{
+			ID:          link.ID,
+			Name:        link.Name,
+			URL:         link.URL,
+			Logo:        link.Logo,
+			Description: link.Description,
+		})
+	}
+	return resp, nil
+}
+
+// List 后台查询友链列表
+func (uc *friendLinkUseCase) List(req *dto.FriendLinkListRequest) (*dto.PageResponse, error) {
+	minFailures := 0
+	if req.Dead {
+		minFailures = friendLinkMaxFailures()
+	}
+	links, total, err := uc.data.FriendLinkRepo.List(req.Page, req.Limit, req.Status, req.Keyword, minFailures)
+	if err != nil {
+		return nil, errors.New("查询友链失败")
+	}
+
+	return &dto.PageResponse{
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Data:  links,
+	}, nil
+}
+
+// Create 管理员添加友链，未指定状态时直接审核通过
+func (uc *friendLinkUseCase) Create(req *dto.FriendLinkRequest) (*po.FriendLink, error) {
+	link, err := newFriendLink(req.Name, req.URL, req.Logo, req.Description, req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.data.FriendLinkRepo.FindByURL(link.URL); err == nil {
+		return nil, ErrFriendLinkExists
+	}
+
+	link.Status = po.FriendLinkApproved
+	if req.Status != "" {
+		link.Status = req.Status
+	}
+	if req.Sort != nil {
+		link.Sort = *req.Sort
+	}
+	if err := uc.data.FriendLinkRepo.Create(link); err != nil {
+		return nil, errors.New("创建友链失败")
+	}
+	return link, nil
+}
+
+// Update 更新友链，地址变化时重新开始检测；状态和排序值为空时不修改
+func (uc *friendLinkUseCase) Update(id uint, req *dto.FriendLinkRequest) (*po.FriendLink, error) {
+	link, err := uc.data.FriendLinkRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrFriendLinkNotFound
+	}
+
+	updated, err := newFriendLink(req.Name, req.URL, req.Logo, req.Description, req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if updated.URL != link.URL {
+		if existing, err := uc.data.FriendLinkRepo.FindByURL(updated.URL); err == nil && existing.ID != link.ID {
+			return nil, ErrFriendLinkExists
+		}
+		link.URL = updated.URL
+		link.CheckStatus, link.CheckError, link.FailCount, link.CheckedAt = 0, "", 0, nil
+	}
+
+	link.Name = updated.Name
+	link.Logo = updated.Logo
+	link.Description = updated.Description
+	link.Email = updated.Email
+	if req.Status != "" {
+		link.Status = req.Status
+	}
+	if req.Sort != nil {
+		link.Sort = *req.Sort
+	}
+	if err := uc.data.FriendLinkRepo.Update(link); err != nil {
+		return nil, errors.New("更新友链失败")
+	}
+	return link, nil
+}
+
+// Delete 删除友链
+func (uc *friendLinkUseCase) Delete(id uint) error {
+	if _, err := uc.data.FriendLinkRepo.FindByID(id); err != nil {
+		return ErrFriendLinkNotFound
+	}
+	if err := uc.data.FriendLinkRepo.Delete(id); err != nil {
+		return errors.New("删除友链失败")
+	}
+	return nil
+}
+
+// Review 审核友链申请，通过时清空原因
+func (uc *friendLinkUseCase) Review(id uint, req *dto.FriendLinkReviewRequest) (*po.FriendLink, error) {
+	link, err := uc.data.FriendLinkRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrFriendLinkNotFound
+	}
+
+	link.Status = req.Status
+	link.Reason = strings.TrimSpace(req.Reason)
+	if link.Status == po.FriendLinkApproved {
+		link.Reason = ""
+	}
+	if err := uc.data.FriendLinkRepo.Update(link); err != nil {
+		return nil, errors.New("审核友链失败")
+	}
+	return link, nil
+}
+
+// Sort 批量调整排序值
+func (uc *friendLinkUseCase) Sort(req *dto.FriendLinkSortRequest) error {
+	sorts := make(map[uint]int, len(req.Items))
+	for _, item := range req.Items {
+		sorts[item.ID] = item.Sort
+	}
+	if err := uc.data.FriendLinkRepo.UpdateSort(sorts); err != nil {
+		return errors.New("调整友链排序失败")
+	}
+	return nil
+}
+
+// Check 立即检测一个友链是否可以访问，结果计入连续失败次数
+func (uc *friendLinkUseCase) Check(ctx context.Context, id uint) (*dto.FriendLinkCheckResponse, error) {
+	link, err := uc.data.FriendLinkRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrFriendLinkNotFound
+	}
+
+	status, checkErr := checkFriendLink(ctx, link.URL)
+	resp := &dto.FriendLinkCheckResponse{Alive: checkErr == nil, Status: status}
+	if checkErr != nil {
+		resp.Error = checkErr.Error()
+		resp.FailCount = link.FailCount + 1
+	}
+	if err := uc.data.FriendLinkRepo.SaveCheck(link.ID, status, resp.Error, checkErr != nil); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CheckAll 检测全部审核通过的友链，返回检测数和连续失败次数达到上限的友链数
+// 启用 Redis 时同一轮检测只由抢到锁的实例执行
+func (uc *friendLinkUseCase) CheckAll(ctx context.Context) (int, int, error) {
+	if redis.Client != nil {
+		ttl := time.Duration(config.AppConfig.FriendLink.CheckInterval) * time.Hour / 2
+		ok, err := redis.SetNX(friendLinkCheckLock, 1, max(ttl, time.Minute))
+		if err == nil && !ok {
+			return 0, 0, nil
+		}
+	}
+
+	links, err := uc.data.FriendLinkRepo.ListApproved()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	concurrency := config.AppConfig.FriendLink.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultFriendLinkConcurrency
+	}
+	maxFailures := friendLinkMaxFailures()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		dead int
+		sem  = make(chan struct{}, concurrency)
+	)
+	for _, link := range links {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(link *po.FriendLink) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			status, checkErr := checkFriendLink(ctx, link.URL)
+			message := ""
+			if checkErr != nil {
+				message = checkErr.Error()
+			}
+			if err := uc.data.FriendLinkRepo.SaveCheck(link.ID, status, message, checkErr != nil); err != nil {
+				return
+			}
+			if checkErr != nil && link.FailCount+1 >= maxFailures {
+				mu.Lock()
+				dead++
+				mu.Unlock()
+			}
+		}(link)
+	}
+	wg.Wait()
+	return len(links), dead, nil
+}
+
+// newFriendLink 校验并规范化友链信息
+func newFriendLink(name, rawURL, logo, description, email string) (*po.FriendLink, error) {
+	link := &po.FriendLink{
+		Name:        strings.TrimSpace(name),
+		Logo:        strings.TrimSpace(logo),
+		Description: strings.TrimSpace(description),
+		Email:       strings.ToLower(strings.TrimSpace(email)),
+	}
+	if link.Name == "" {
+		return nil, fmt.Errorf("%w: 网站名称不能为空", ErrFriendLinkInvalid)
+	}
+
+	normalized, err := normalizeFriendLinkURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 网站地址必须是 http 或 https 地址", err)
+	}
+	link.URL = normalized
+	if link.Logo != "" {
+		if _, err := normalizeFriendLinkURL(link.Logo); err != nil {
+			return nil, fmt.Errorf("%w: Logo 地址必须是 http 或 https 地址", err)
+		}
+	}
+	return link, nil
+}
+
+// normalizeFriendLinkURL 校验地址为 http(s) 地址，协议和域名转为小写，去掉片段和末尾的根路径斜杠
+func normalizeFriendLinkURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil {
+		return "", ErrFriendLinkInvalid
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	if u.Path == "/" && u.RawQuery == "" {
+		u.Path = ""
+	}
+	return u.String(), nil
+}
+
+// checkFriendLink 请求友链地址，跟随重定向后状态码小于 400 视为可以访问
+func checkFriendLink(ctx context.Context, link string) (int, error) {
+	timeout := config.AppConfig.FriendLink.Timeout
+	if timeout <= 0 {
+		timeout = defaultFriendLinkTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", friendLinkUserAgent)
+	req.Header.Set("Accept", "text/html,*/*")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, errors.New(truncateRunes(err.Error(), 200))
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return resp.StatusCode, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// friendLinkMaxFailures 连续检测失败多少次后视为死链
+func friendLinkMaxFailures() int {
+	if n := config.AppConfig.FriendLink.MaxFailures; n > 0 {
+		return n
+	}
+	return defaultFriendLinkMaxFailures
+}
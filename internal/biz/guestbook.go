@@ -0,0 +1,119 @@
+package biz
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// ErrGuestbookMessageNotFound 留言不存在或不是留言板消息
+var ErrGuestbookMessageNotFound = errors.New("留言不存在")
+
+// GuestbookUseCase 留言板业务用例接口
+// 留言保存在评论表中（article_id 为空），与文章评论共用屏蔽规则、审核队列、回复和表情回应
+type GuestbookUseCase interface {
+	// List 前台分页查询审核通过的顶级留言，回复通过评论回复接口按需加载
+	List(userID uint, page, limit int) (*dto.CommentListResponse, error)
+	// Create 发表留言或回复留言
+	Create(ctx context.Context, req *dto.CreateGuestbookMessageRequest, userID uint, ip string) (*dto.CommentResponse, error)
+	// Delete 前台删除留言，权限与删除评论相同
+	Delete(id, userID uint) error
+	// AdminList 后台查询留言列表，包含待审核和垃圾留言
+	AdminList(req *dto.GuestbookListRequest) (*dto.PageResponse, error)
+	// UpdateStatus 后台审核留言
+	UpdateStatus(ctx context.Context, id uint, status int) error
+	// AdminDelete 后台删除留言，删除顶级留言时一并删除回复
+	AdminDelete(id uint) error
+}
+
+// guestbookUseCase 留言板业务用例实现
+type guestbookUseCase struct {
+	data *data.Data
+	blog *blogUseCase
+}
+
+// NewGuestbookUseCase 创建留言板业务用例
+func NewGuestbookUseCase(d *data.Data) GuestbookUseCase {
+	return &guestbookUseCase{data: d, blog: &blogUseCase{data: d}}
+}
+
+// List 前台分页查询审核通过的顶级留言
+func (uc *guestbookUseCase) List(userID uint, page, limit int) (*dto.CommentListResponse, error) {
+	return uc.blog.GetArticleComments(0, userID, page, limit)
+}
+
+// Create 发表留言或回复留言，留言板关闭时返回 ErrGuestbookClosed
+func (uc *guestbookUseCase) Create(ctx context.Context, req *dto.CreateGuestbookMessageRequest, userID uint, ip string) (*dto.CommentResponse, error) {
+	return uc.blog.CreateComment(ctx, &dto.CreateCommentRequest{
+		UserID:        userID,
+		ParentID:      req.ParentID,
+		ReplyToUserID: req.ReplyToUserID,
+		Content:       req.Content,
+		IP:            ip,
+	})
+}
+
+// Delete 前台删除留言，权限与删除评论相同
+func (uc *guestbookUseCase) Delete(id, userID uint) error {
+	if _, err := uc.find(id); err != nil {
+		return err
+	}
+	return uc.blog.DeleteComment(id, userID)
+}
+
+// AdminList 后台查询留言列表
+func (uc *guestbookUseCase) AdminList(req *dto.GuestbookListRequest) (*dto.PageResponse, error) {
+	messages, total, err := uc.data.CommentRepo.ListGuestbook(req.Page, req.Limit, req.Status, req.Keyword)
+	if err != nil {
+		return nil, errors.New("查询留言列表失败")
+	}
+
+	// 历史留言没有保存渲染结果，返回前补全
+	for _, message := range messages {
+		message.ContentHTML = commentHTML(message)
+	}
+
+	return &dto.PageResponse{
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Data:  messages,
+	}, nil
+}
+
+// UpdateStatus 后台审核留言，审核通过时通知被回复的用户和正在查看留言板的读者
+func (uc *guestbookUseCase) UpdateStatus(ctx context.Context, id uint, status int) error {
+	message, err := uc.find(id)
+	if err != nil {
+		return err
+	}
+	if err := uc.data.CommentRepo.UpdateStatus(id, status); err != nil {
+		return errors.New("更新状态失败")
+	}
+	applyCommentStatusChange(ctx, uc.data, message, status)
+	return nil
+}
+
+// AdminDelete 后台删除留言
+func (uc *guestbookUseCase) AdminDelete(id uint) error {
+	message, err := uc.find(id)
+	if err != nil {
+		return err
+	}
+	if err := deleteComment(uc.data, message); err != nil {
+		return errors.New("删除留言失败")
+	}
+	return nil
+}
+
+// find 查询留言，文章评论视为不存在
+func (uc *guestbookUseCase) find(id uint) (*po.Comment, error) {
+	message, err := uc.data.CommentRepo.FindByID(id)
+	if err != nil || message.ArticleID != nil {
+		return nil, ErrGuestbookMessageNotFound
+	}
+	return message, nil
+}
@@ -0,0 +1,31 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+func TestGuestbookRejectsArticleComments(t *testing.T) {
+	articleID := uint(3)
+	uc := NewGuestbookUseCase(&data.Data{
+		CommentRepo: &stubCommentRepo{comments: map[uint]*po.Comment{
+			1: {ID: 1, UserID: 1, ArticleID: &articleID},
+		}},
+	})
+
+	for _, id := range []uint{1, 2} {
+		if err := uc.Delete(id, 1); !errors.Is(err, ErrGuestbookMessageNotFound) {
+			t.Errorf("Delete(%d) = %v, want ErrGuestbookMessageNotFound", id, err)
+		}
+		if err := uc.UpdateStatus(context.Background(), id, po.CommentStatusSpam); !errors.Is(err, ErrGuestbookMessageNotFound) {
+			t.Errorf("UpdateStatus(%d) = %v, want ErrGuestbookMessageNotFound", id, err)
+		}
+		if err := uc.AdminDelete(id); !errors.Is(err, ErrGuestbookMessageNotFound) {
+			t.Errorf("AdminDelete(%d) = %v, want ErrGuestbookMessageNotFound", id, err)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package biz
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/oss"
+)
+
+// defaultOrphanMinAge 默认只清理上传超过 24 小时的图片
+const defaultOrphanMinAge = 24 * time.Hour
+
+var (
+	// imageNamePattern 文本中引用的图片文件名（不含目录），同名的不同目录对象都视为被引用
+	imageNamePattern = regexp.MustCompile(`(?i)([A-Za-z0-9_\-]+)\.(?:jpe?g|png|gif|webp|svg|bmp|ico)\b`)
+	// imageVariantSuffix 缩放版本的文件名后缀，与原图视为同一张图片
+	imageVariantSuffix = regexp.MustCompile(`_(?:thumbnail|medium)$`)
+)
+
+// ImageCleanupUseCase 未引用图片清理业务用例
+type ImageCleanupUseCase interface {
+	// CleanOrphans 找出存储中不再被任何内容引用的图片，dryRun 为 false 时删除
+	// 只处理上传时间超过 minAge 的图片，minAge 为 0 时使用默认值 24 小时
+	CleanOrphans(dryRun bool, minAge time.Duration) (*dto.OrphanImageReport, error)
+}
+
+type imageCleanupUseCase struct {
+	data *data.Data
+}
+
+// NewImageCleanupUseCase 创建未引用图片清理业务用例
+func NewImageCleanupUseCase(d *data.Data) ImageCleanupUseCase {
+	return &imageCleanupUseCase{data: d}
+}
+
+// CleanOrphans 清理未引用图片
+// 引用来源包括文章（含回收站）正文、封面、历史版本和草稿，评论，说说图片，独立页面，系列封面，用户和管理员头像，以及站点设置；
+// 文件列表中的上传记录不算引用，删除图片时一并删除对应记录
+func (uc *imageCleanupUseCase) CleanOrphans(dryRun bool, minAge time.Duration) (*dto.OrphanImageReport, error) {
+	if minAge <= 0 {
+		minAge = defaultOrphanMinAge
+	}
+
+	// 先读取引用再列出对象，期间新上传的图片会因上传时间过近被跳过
+	referenced, err := uc.referencedImages()
+	if err != nil {
+		return nil, err
+	}
+	objects, err := oss.ListStoredObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.OrphanImageReport{
+		DryRun:   dryRun,
+		Location: "local",
+		Orphans:  []*dto.OrphanImage{},
+		Failed:   []string{},
+	}
+	if oss.Enabled() {
+		report.Location = "oss"
+	}
+
+	cutoff := time.Now().Add(-minAge)
+	for _, object := range objects {
+		stem, ok := imageStem(object.Key)
+		if !ok {
+			continue
+		}
+		report.Scanned++
+		if referenced[stem] {
+			report.Referenced++
+			continue
+		}
+		if object.LastModified.After(cutoff) {
+			report.Recent++
+			continue
+		}
+		report.Orphans = append(report.Orphans, &dto.OrphanImage{
+			Key:          object.Key,
+			URL:          oss.ObjectURL(object.Key),
+			Size:         object.Size,
+			LastModified: object.LastModified,
+		})
+		report.OrphanSize += object.Size
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	db := uc.data.GetDB()
+	for _, orphan := range report.Orphans {
+		if err := oss.RemoveObject(orphan.Key); err != nil {
+			report.Failed = append(report.Failed, fmt.Sprintf("%s: %v", orphan.Key, err))
+			continue
+		}
+		report.Deleted++
+		if err := db.Unscoped().Where("url = ?", orphan.URL).Delete(&po.File{}).Error; err != nil {
+			report.Failed = append(report.Failed, fmt.Sprintf("%s: 删除文件记录失败: %v", orphan.Key, err))
+		}
+	}
+	return report, nil
+}
+
+// referencedImages 收集所有内容中引用的图片文件名
+func (uc *imageCleanupUseCase) referencedImages() (map[string]bool, error) {
+	db := uc.data.GetDB().Unscoped()
+	sources := []struct {
+		model   interface{}
+		columns []string
+	}{
+		{&po.Article{}, []string{"content_markdown", "content_html", "cover"}},
+		{&po.ArticleVersion{}, []string{"content_markdown", "content_html"}},
+		{&po.ArticleDraft{}, []string{"content_markdown"}},
+		{&po.Banner{}, []string{"image"}},
+		{&po.Comment{}, []string{"content"}},
+		{&po.Moment{}, []string{"images"}},
+		{&po.Page{}, []string{"content_markdown", "content_html"}},
+		{&po.Series{}, []string{"cover"}},
+		{&po.User{}, []string{"avatar"}},
+		{&po.Admin{}, []string{"avatar"}},
+		{&po.Setting{}, []string{"value"}},
+	}
+
+	referenced := make(map[string]bool)
+	for _, source := range sources {
+		rows, err := db.Model(source.model).Select(source.columns).Rows()
+		if err != nil {
+			return nil, fmt.Errorf("读取引用失败: %w", err)
+		}
+		values := make([]*string, len(source.columns))
+		dest := make([]interface{}, len(values))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		for rows.Next() {
+			if err := rows.Scan(dest...); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("读取引用失败: %w", err)
+			}
+			for _, value := range values {
+				if value == nil {
+					continue
+				}
+				for _, match := range imageNamePattern.FindAllStringSubmatch(*value, -1) {
+					referenced[imageVariantSuffix.ReplaceAllString(match[1], "")] = true
+				}
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("读取引用失败: %w", err)
+		}
+	}
+	return referenced, nil
+}
+
+// imageStem 图片对象的文件名（不含扩展名和缩放后缀），WebP 和缩放版本与原图相同；非图片返回 false
+func imageStem(key string) (string, bool) {
+	name := path.Base(key)
+	match := imageNamePattern.FindStringSubmatch(name)
+	if match == nil || match[0] != name {
+		return "", false
+	}
+	return imageVariantSuffix.ReplaceAllString(match[1], ""), true
+}
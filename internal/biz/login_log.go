@@ -0,0 +1,156 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/geoip"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/mail"
+)
+
+// LoginLogUseCase 登录日志业务用例接口
+type LoginLogUseCase interface {
+	// ListMine 查询当前用户最近的登录记录
+	ListMine(userID uint, req *dto.PageRequest) (*dto.PageResponse, error)
+	// List 查询全部登录日志（管理后台）
+	List(req *dto.LoginLogListRequest) (*dto.PageResponse, error)
+	// PurgeExpired 删除超过保留天数的登录日志
+	PurgeExpired(retentionDays int) (int64, error)
+}
+
+// loginLogUseCase 登录日志业务用例实现
+type loginLogUseCase struct {
+	data *data.Data
+}
+
+// NewLoginLogUseCase 创建登录日志业务用例
+func NewLoginLogUseCase(d *data.Data) LoginLogUseCase {
+	return &loginLogUseCase{data: d}
+}
+
+// ListMine 查询当前用户最近的登录记录，包括失败的尝试
+func (uc *loginLogUseCase) ListMine(userID uint, req *dto.PageRequest) (*dto.PageResponse, error) {
+	return uc.list(req.Page, req.Limit, userID, "", nil)
+}
+
+// List 查询全部登录日志
+func (uc *loginLogUseCase) List(req *dto.LoginLogListRequest) (*dto.PageResponse, error) {
+	return uc.list(req.Page, req.Limit, req.UserID, req.Username, req.Success)
+}
+
+// PurgeExpired 删除超过保留天数的登录日志
+func (uc *loginLogUseCase) PurgeExpired(retentionDays int) (int64, error) {
+	return uc.data.LoginLogRepo.DeleteBefore(time.Now().AddDate(0, 0, -retentionDays))
+}
+
+// list 分页查询登录日志
+func (uc *loginLogUseCase) list(page, limit int, userID uint, username string, success *bool) (*dto.PageResponse, error) {
+	logs, total, err := uc.data.LoginLogRepo.List(page, limit, userID, username, success)
+	if err != nil {
+		return nil, errors.New("查询登录日志失败")
+	}
+
+	items := make([]*dto.LoginLogInfo, 0, len(logs))
+	for _, log := range logs {
+		items = append(items, &dto.LoginLogInfo{
+			ID:          log.ID,
+			UserID:      log.UserID,
+			Username:    log.Username,
+			Channel:     log.Channel,
+			Success:     log.Success,
+			Reason:      log.Reason,
+			IP:          log.IP,
+			Device:      log.Device,
+			UserAgent:   log.UserAgent,
+			Location:    log.Location,
+			NewLocation: log.NewLocation,
+			CreatedAt:   log.CreatedAt,
+		})
+	}
+
+	return &dto.PageResponse{
+		Total: total,
+		Page:  page,
+		Limit: limit,
+		Data:  items,
+	}, nil
+}
+
+// recordLogin 异步记录登录尝试，loginErr 为 nil 表示登录成功；user 为 nil 表示用户名不存在
+// 查询所在地可能较慢，不阻塞登录；记录失败只写日志，不影响登录
+func recordLogin(ctx context.Context, d *data.Data, user *po.User, username, channel string, client *dto.ClientInfo, loginErr error) {
+	go saveLoginLog(context.WithoutCancel(ctx), d, user, username, channel, client, loginErr)
+}
+
+// saveLoginLog 写入登录日志，登录成功且所在地与以往不同时发出提醒
+func saveLoginLog(ctx context.Context, d *data.Data, user *po.User, username, channel string, client *dto.ClientInfo, loginErr error) {
+	log := &po.LoginLog{
+		Username: truncateRunes(username, 50),
+		Channel:  channel,
+		Success:  loginErr == nil,
+	}
+	if user != nil {
+		log.UserID = user.ID
+		log.Username = user.Username
+	}
+	if loginErr != nil {
+		log.Reason = truncateRunes(loginErr.Error(), 100)
+	}
+	if client != nil {
+		log.IP = client.IP
+		log.UserAgent = truncateRunes(client.UserAgent, 500)
+		log.Device = deviceName(client.UserAgent)
+		log.Location = geoip.Lookup(client.IP)
+	}
+
+	// 首次登录和无法识别所在地时不提醒
+	if log.Success && log.UserID > 0 && log.Location != "" && log.Location != geoip.Private {
+		known, err := d.LoginLogRepo.HasLocation(log.UserID, log.Location)
+		if err == nil && !known {
+			seen, err := d.LoginLogRepo.HasSucceeded(log.UserID)
+			log.NewLocation = err == nil && seen
+		}
+	}
+
+	if err := d.LoginLogRepo.Create(log); err != nil {
+		logger.FromContext(ctx).Warn("Failed to write login log: ", err)
+		return
+	}
+	if log.NewLocation {
+		alertNewLocation(ctx, d, user, log)
+	}
+}
+
+// alertNewLocation 新的所在地登录提醒：投递 Webhook 事件，并在开启提醒时向用户发送邮件
+func alertNewLocation(ctx context.Context, d *data.Data, user *po.User, log *po.LoginLog) {
+	hooks := webhookSubscribers(ctx, d, po.WebhookEventLoginNewLocation)
+	deliverEvent(ctx, d, hooks, po.WebhookEventLoginNewLocation, &dto.WebhookLogin{
+		UserID:    log.UserID,
+		Username:  log.Username,
+		Channel:   log.Channel,
+		IP:        log.IP,
+		Device:    log.Device,
+		Location:  log.Location,
+		CreatedAt: log.CreatedAt,
+	})
+
+	if !config.AppConfig.LoginLog.NewLocationAlert || !mail.Enabled() || user.Email == "" {
+		return
+	}
+	data := mail.Data{
+		"Name":     mailName(user),
+		"Time":     log.CreatedAt.Format("2006-01-02 15:04:05"),
+		"Location": log.Location,
+		"IP":       log.IP,
+		"Device":   log.Device,
+	}
+	if err := mail.SendTemplate(ctx, user.Email, mail.TemplateLoginAlert, data); err != nil {
+		logger.FromContext(ctx).Error("Failed to send new location alert: ", err)
+	}
+}
@@ -0,0 +1,130 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// stubLoginLogRepo 内存中的登录日志仓储，登录日志异步写入，需要加锁
+type stubLoginLogRepo struct {
+	data.LoginLogRepo
+	mu   sync.Mutex
+	logs []*po.LoginLog
+}
+
+func (r *stubLoginLogRepo) Create(log *po.LoginLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	log.ID = uint(len(r.logs) + 1)
+	log.CreatedAt = time.Now()
+	copied := *log
+	r.logs = append(r.logs, &copied)
+	return nil
+}
+
+func (r *stubLoginLogRepo) HasSucceeded(userID uint) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, log := range r.logs {
+		if log.UserID == userID && log.Success {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *stubLoginLogRepo) HasLocation(userID uint, location string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, log := range r.logs {
+		if log.UserID == userID && log.Success && log.Location == location {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// stubAlertWebhookRepo 记录新地点提醒查询 Webhook 的次数
+type stubAlertWebhookRepo struct {
+	data.WebhookRepo
+	lookups int
+}
+
+func (r *stubAlertWebhookRepo) ListEnabled() ([]*po.Webhook, error) {
+	r.lookups++
+	return nil, nil
+}
+
+func TestSaveLoginLogNewLocation(t *testing.T) {
+	// 按 IP 返回固定所在地的查询接口
+	geo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		city := map[string]string{"/1.1.1.1": "Sydney", "/8.8.8.8": "Mountain View"}[r.URL.Path]
+		w.Write([]byte(`{"status":"success","country":"X","regionName":"Y","city":"` + city + `"}`))
+	}))
+	defer geo.Close()
+	previous := config.AppConfig.LoginLog
+	config.AppConfig.LoginLog = config.LoginLogConfig{GeoURL: geo.URL + "/{ip}"}
+	t.Cleanup(func() { config.AppConfig.LoginLog = previous })
+
+	user := &po.User{ID: 1, Username: "alice"}
+	tests := []struct {
+		name     string
+		user     *po.User
+		ip       string
+		loginErr error
+		location string
+		isNew    bool
+	}{
+		{name: "first login is not alerted", user: user, ip: "1.1.1.1", location: "X Y Sydney"},
+		{name: "known location", user: user, ip: "1.1.1.1", location: "X Y Sydney"},
+		{name: "failed login from new location", user: user, ip: "8.8.8.8", loginErr: errors.New("用户名或密码错误"), location: "X Y Mountain View"},
+		{name: "unknown username", ip: "8.8.8.8", loginErr: errors.New("用户名或密码错误"), location: "X Y Mountain View"},
+		{name: "private address", user: user, ip: "192.168.1.2", location: "内网"},
+		{name: "new location", user: user, ip: "8.8.8.8", location: "X Y Mountain View", isNew: true},
+		{name: "new location seen before", user: user, ip: "8.8.8.8", location: "X Y Mountain View"},
+	}
+
+	logs := &stubLoginLogRepo{}
+	hooks := &stubAlertWebhookRepo{}
+	d := &data.Data{LoginLogRepo: logs, WebhookRepo: hooks}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := hooks.lookups
+			saveLoginLog(context.Background(), d, tt.user, "alice", po.LoginChannelBlog, &dto.ClientInfo{IP: tt.ip, UserAgent: "Mozilla/5.0 (Windows NT 10.0) Chrome/120.0"}, tt.loginErr)
+
+			if len(logs.logs) != i+1 {
+				t.Fatalf("logs = %d, want %d", len(logs.logs), i+1)
+			}
+			log := logs.logs[i]
+			if log.Location != tt.location || log.NewLocation != tt.isNew || log.Success != (tt.loginErr == nil) {
+				t.Errorf("log = %+v, want location %q new %v", log, tt.location, tt.isNew)
+			}
+			if tt.loginErr != nil && log.Reason != tt.loginErr.Error() {
+				t.Errorf("reason = %q", log.Reason)
+			}
+			if alerted := hooks.lookups > alerts; alerted != tt.isNew {
+				t.Errorf("alerted = %v, want %v", alerted, tt.isNew)
+			}
+		})
+	}
+}
+
+func TestSaveLoginLogTruncatesUsername(t *testing.T) {
+	logs := &stubLoginLogRepo{}
+	saveLoginLog(context.Background(), &data.Data{LoginLogRepo: logs}, nil, strings.Repeat("名", 80), po.LoginChannelAdmin, nil, errors.New("用户名或密码错误"))
+
+	if got := []rune(logs.logs[0].Username); len(got) > 50 || logs.logs[0].UserID != 0 {
+		t.Errorf("log = %+v", logs.logs[0])
+	}
+}
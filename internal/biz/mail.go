@@ -0,0 +1,170 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/mail"
+)
+
+var (
+	// ErrMailDisabled 未配置 SMTP 服务器
+	ErrMailDisabled = errors.New("未配置邮件服务器")
+	// ErrMailTemplateInvalid 未知的邮件模板
+	ErrMailTemplateInvalid = errors.New("未知的邮件模板")
+)
+
+// MailUseCase 邮件业务用例接口
+type MailUseCase interface {
+	// SendTest 使用示例数据渲染模板并立即发送，用于检查 SMTP 配置和模板效果
+	SendTest(req *dto.MailTestRequest) error
+}
+
+// mailUseCase 邮件业务用例实现
+type mailUseCase struct{}
+
+// NewMailUseCase 创建邮件业务用例
+func NewMailUseCase() MailUseCase {
+	return &mailUseCase{}
+}
+
+// SendTest 发送测试邮件，不经过发送队列，SMTP 错误直接返回给管理员
+func (uc *mailUseCase) SendTest(req *dto.MailTestRequest) error {
+	if !mail.Enabled() {
+		return ErrMailDisabled
+	}
+	name := req.Template
+	if name == "" {
+		name = mail.TemplateTest
+	}
+	if !slices.Contains(mail.Templates, name) {
+		return ErrMailTemplateInvalid
+	}
+
+	subject, body, err := mail.Render(name, sampleMailData(name))
+	if err != nil {
+		logger.Error("Failed to render mail template: ", err)
+		return fmt.Errorf("渲染邮件模板失败: %w", err)
+	}
+	if err := mail.Send(req.To, subject, body); err != nil {
+		return fmt.Errorf("发送失败: %w", err)
+	}
+	return nil
+}
+
+// sampleMailData 模板的示例数据
+func sampleMailData(name string) mail.Data {
+	link := config.AppConfig.Sitemap.SiteURL
+	if link == "" {
+		link = "https://example.com"
+	}
+	switch name {
+	case mail.TemplateVerifyEmail:
+		return mail.Data{"Name": "测试用户", "ExpireHours": defaultVerifyTokenExpire, "Link": link}
+	case mail.TemplatePasswordReset:
+		return mail.Data{"Name": "测试用户", "ExpireMinutes": defaultResetTokenExpire, "Link": link}
+	case mail.TemplateCommentReply:
+		return mail.Data{
+			"Name":         "测试用户",
+			"ReplierName":  "访客",
+			"ArticleTitle": "示例文章",
+			"Original":     "这是你发表的评论。",
+			"Reply":        "这是对你评论的回复。",
+			"Link":         link,
+		}
+	case mail.TemplateNewsletter:
+		return mail.Data{
+			"Title": "本周文章精选",
+			"Name":  "测试用户",
+			"Intro": "以下是本周发布的文章：",
+			"Articles": []mail.Data{
+				{"Title": "示例文章", "Summary": "文章摘要", "Link": link},
+			},
+		}
+	case mail.TemplateLoginAlert:
+		return mail.Data{
+			"Name":     "测试用户",
+			"Time":     time.Now().Format("2006-01-02 15:04:05"),
+			"Location": "中国 上海",
+			"IP":       "203.0.113.1",
+			"Device":   "Chrome on macOS",
+		}
+	default:
+		return mail.Data{"Time": time.Now().Format("2006-01-02 15:04:05")}
+	}
+}
+
+// notifyCommentReply 回复审核通过后邮件通知被回复的用户，回复自己、对方未激活或没有邮箱时不通知
+func notifyCommentReply(ctx context.Context, d *data.Data, comment *po.Comment) {
+	if comment.ParentID == nil || !mail.Enabled() {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		parent, recipientID, ok := replyRecipient(d, comment)
+		if !ok {
+			return
+		}
+		recipient, err := d.UserRepo.FindByID(recipientID)
+		if err != nil || recipient.Email == "" || recipient.Status != po.UserStatusActive {
+			return
+		}
+		replier, err := d.UserRepo.FindByID(comment.UserID)
+		if err != nil {
+			return
+		}
+
+		data := mail.Data{
+			"Name":        mailName(recipient),
+			"ReplierName": mailName(replier),
+			"Original":    truncateRunes(parent.Content, 200),
+			"Reply":       truncateRunes(comment.Content, 500),
+		}
+		if comment.ArticleID != nil {
+			if article, err := d.ArticleRepo.FindByID(*comment.ArticleID); err == nil {
+				data["ArticleTitle"] = article.Title
+				if config.AppConfig.Sitemap.SiteURL != "" {
+					data["Link"] = fmt.Sprintf("%s#comment-%d", frontendArticleURL(article), comment.ID)
+				}
+			}
+		}
+		if err := mail.SendTemplate(ctx, recipient.Email, mail.TemplateCommentReply, data); err != nil {
+			logger.FromContext(ctx).Warn("Failed to queue comment reply mail: ", err)
+		}
+	}()
+}
+
+// replyRecipient 查询回复的上级评论和被回复的用户，不是回复或回复自己时 ok 为 false
+func replyRecipient(d *data.Data, comment *po.Comment) (*po.Comment, uint, bool) {
+	if comment.ParentID == nil {
+		return nil, 0, false
+	}
+	parent, err := d.CommentRepo.FindByID(*comment.ParentID)
+	if err != nil {
+		return nil, 0, false
+	}
+	recipientID := parent.UserID
+	if comment.ReplyToUserID != nil {
+		recipientID = *comment.ReplyToUserID
+	}
+	if recipientID == comment.UserID {
+		return nil, 0, false
+	}
+	return parent, recipientID, true
+}
+
+// mailName 邮件中对用户的称呼
+func mailName(user *po.User) string {
+	if user.Nickname != "" {
+		return user.Nickname
+	}
+	return user.Username
+}
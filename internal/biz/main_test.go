@@ -0,0 +1,20 @@
+package biz
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+)
+
+func TestMain(m *testing.M) {
+	config.AppConfig = &config.Config{
+		JWT: config.JWTConfig{Secret: "biz-test-secret-0123456789abcdef0123456789", KeyID: "test"},
+	}
+	logger.Log = logrus.New()
+	logger.Log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
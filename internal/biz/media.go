@@ -0,0 +1,91 @@
+package biz
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/oss"
+)
+
+// MediaUseCase 媒体库业务用例
+type MediaUseCase interface {
+	// List 分页查询已上传的图片及其在文章中的引用次数，供编辑器选择已有图片
+	List(req *dto.MediaListRequest) (*dto.PageResponse, error)
+}
+
+type mediaUseCase struct {
+	data *data.Data
+}
+
+// NewMediaUseCase 创建媒体库业务用例
+func NewMediaUseCase(d *data.Data) MediaUseCase {
+	return &mediaUseCase{data: d}
+}
+
+// List 分页查询图片
+// 引用次数按文件名匹配文章正文和封面，与未引用图片清理的规则一致，WebP 和缩放版本的引用也计入原图
+func (uc *mediaUseCase) List(req *dto.MediaListRequest) (*dto.PageResponse, error) {
+	files, total, err := uc.data.FileRepo.ListImages(req.Page, req.Limit, req.Keyword, req.Folder)
+	if err != nil {
+		return nil, errors.New("查询图片列表失败")
+	}
+
+	items := make([]*dto.MediaItem, 0, len(files))
+	for _, file := range files {
+		item := convertToMediaItem(file)
+		count, err := uc.data.ArticleRepo.CountReferencing(mediaReference(file.URL))
+		if err != nil {
+			return nil, errors.New("统计图片引用失败")
+		}
+		item.UsageCount = count
+		items = append(items, item)
+	}
+
+	return &dto.PageResponse{
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Data:  items,
+	}, nil
+}
+
+// convertToMediaItem 转换为媒体库条目，缩略图优先使用 thumbnail 缩放版本
+func convertToMediaItem(file *po.File) *dto.MediaItem {
+	item := &dto.MediaItem{
+		ID:           file.ID,
+		Name:         file.Name,
+		URL:          file.URL,
+		ThumbnailURL: file.URL,
+		WebPURL:      file.WebPURL,
+		Size:         file.Size,
+		MimeType:     file.MimeType,
+		Folder:       file.Type,
+		CreatedAt:    file.CreatedAt,
+	}
+	if file.Variants != "" {
+		var variants []oss.ImageVariant
+		if err := json.Unmarshal([]byte(file.Variants), &variants); err == nil {
+			for _, variant := range variants {
+				if variant.Name == "thumbnail" {
+					item.ThumbnailURL = variant.URL
+					break
+				}
+			}
+		}
+	}
+	return item
+}
+
+// mediaReference 文章中引用图片时包含的文本：文件名（不含扩展名和尺寸后缀），无法识别时使用完整地址
+func mediaReference(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		if stem, ok := imageStem(u.Path); ok {
+			return stem
+		}
+	}
+	return rawURL
+}
@@ -0,0 +1,405 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// maxMenuDepth 菜单最多层级
+const maxMenuDepth = 3
+
+var (
+	// ErrMenuNotFound 菜单项不存在
+	ErrMenuNotFound = errors.New("菜单项不存在")
+	// ErrMenuInvalid 菜单参数不合法
+	ErrMenuInvalid = errors.New("菜单参数不合法")
+)
+
+// MenuUseCase 导航菜单业务用例接口
+type MenuUseCase interface {
+	// Tree 后台查询某个位置的完整菜单树，包含隐藏和失效的菜单项
+	Tree(location string) ([]*dto.MenuItemResponse, error)
+	// PublicTree 前台查询菜单树，隐藏的菜单项连同子菜单不返回，链接目标已删除或未发布的菜单项不返回
+	PublicTree(location string) ([]*dto.MenuItemResponse, error)
+	// Create 创建菜单项
+	Create(req *dto.MenuItemRequest) (*dto.MenuItemResponse, error)
+	// Update 更新菜单项，位置不可修改
+	Update(id uint, req *dto.MenuItemRequest) (*dto.MenuItemResponse, error)
+	// Delete 删除菜单项及其子菜单
+	Delete(id uint) error
+	// Sort 批量调整菜单项的上级和排序值
+	Sort(req *dto.MenuSortRequest) error
+}
+
+// menuUseCase 导航菜单业务用例实现
+type menuUseCase struct {
+	data *data.Data
+}
+
+// NewMenuUseCase 创建导航菜单业务用例
+func NewMenuUseCase(d *data.Data) MenuUseCase {
+	return &menuUseCase{data: d}
+}
+
+// Tree 后台查询完整菜单树
+func (uc *menuUseCase) Tree(location string) ([]*dto.MenuItemResponse, error) {
+	return uc.tree(location, false)
+}
+
+// PublicTree 前台查询菜单树
+func (uc *menuUseCase) PublicTree(location string) ([]*dto.MenuItemResponse, error) {
+	return uc.tree(location, true)
+}
+
+// Create 创建菜单项，未指定位置时放在顶部导航
+func (uc *menuUseCase) Create(req *dto.MenuItemRequest) (*dto.MenuItemResponse, error) {
+	item := &po.MenuItem{Location: menuLocation(req.Location), Visible: true}
+	if err := uc.apply(item, req); err != nil {
+		return nil, err
+	}
+	if err := uc.data.MenuRepo.Create(item); err != nil {
+		return nil, errors.New("创建菜单项失败")
+	}
+	return uc.convertOne(item)
+}
+
+// Update 更新菜单项
+func (uc *menuUseCase) Update(id uint, req *dto.MenuItemRequest) (*dto.MenuItemResponse, error) {
+	item, err := uc.data.MenuRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrMenuNotFound
+	}
+	if err := uc.apply(item, req); err != nil {
+		return nil, err
+	}
+	if err := uc.data.MenuRepo.Update(item); err != nil {
+		return nil, errors.New("更新菜单项失败")
+	}
+	return uc.convertOne(item)
+}
+
+// Delete 删除菜单项及其子菜单
+func (uc *menuUseCase) Delete(id uint) error {
+	item, err := uc.data.MenuRepo.FindByID(id)
+	if err != nil {
+		return ErrMenuNotFound
+	}
+	items, err := uc.data.MenuRepo.ListByLocation(item.Location)
+	if err != nil {
+		return errors.New("查询菜单失败")
+	}
+
+	ids := []uint{id}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range items {
+			if child.ParentID != nil && *child.ParentID == ids[i] {
+				ids = append(ids, child.ID)
+			}
+		}
+	}
+	if err := uc.data.MenuRepo.Delete(ids); err != nil {
+		return errors.New("删除菜单项失败")
+	}
+	return nil
+}
+
+// Sort 批量调整菜单项的上级和排序值，调整后的菜单树需满足层级限制
+func (uc *menuUseCase) Sort(req *dto.MenuSortRequest) error {
+	byID, err := uc.itemsByID(req.Location)
+	if err != nil {
+		return err
+	}
+
+	changed := make([]*po.MenuItem, 0, len(req.Items))
+	for _, sortItem := range req.Items {
+		item, ok := byID[sortItem.ID]
+		if !ok {
+			return fmt.Errorf("%w: 菜单项 %d 不在该位置", ErrMenuInvalid, sortItem.ID)
+		}
+		item.ParentID = sortItem.ParentID
+		item.Sort = sortItem.Sort
+		changed = append(changed, item)
+	}
+	if err := validateMenuTree(byID); err != nil {
+		return err
+	}
+
+	if err := uc.data.MenuRepo.UpdateTree(changed); err != nil {
+		return errors.New("保存菜单排序失败")
+	}
+	return nil
+}
+
+// apply 校验请求并写入菜单项
+func (uc *menuUseCase) apply(item *po.MenuItem, req *dto.MenuItemRequest) error {
+	title := strings.TrimSpace(req.Title)
+	if req.Type == po.MenuTypeLink {
+		link := strings.TrimSpace(req.URL)
+		if !validLinkURL(link) {
+			return fmt.Errorf("%w: 链接地址必须是 / 开头的站内路径或 http(s) 地址", ErrMenuInvalid)
+		}
+		if title == "" {
+			return fmt.Errorf("%w: 标题不能为空", ErrMenuInvalid)
+		}
+		item.URL, item.TargetID = link, nil
+	} else {
+		if req.TargetID == nil {
+			return fmt.Errorf("%w: 请选择链接的分类、标签或页面", ErrMenuInvalid)
+		}
+		name, err := uc.targetName(req.Type, *req.TargetID)
+		if err != nil {
+			return fmt.Errorf("%w: 链接的分类、标签或页面不存在", ErrMenuInvalid)
+		}
+		if title == "" {
+			title = name
+		}
+		targetID := *req.TargetID
+		item.URL, item.TargetID = "", &targetID
+	}
+
+	// 在当前菜单树上应用新的上级后校验层级
+	byID, err := uc.itemsByID(item.Location)
+	if err != nil {
+		return err
+	}
+	item.ParentID = req.ParentID
+	byID[item.ID] = item
+	if err := validateMenuTree(byID); err != nil {
+		return err
+	}
+
+	item.Title = title
+	item.Type = req.Type
+	item.Icon = strings.TrimSpace(req.Icon)
+	item.NewTab = req.NewTab
+	if req.Visible != nil {
+		item.Visible = *req.Visible
+	}
+	if req.Sort != nil {
+		item.Sort = *req.Sort
+	}
+	return nil
+}
+
+// targetName 查询菜单项链接的分类、标签或页面名称
+func (uc *menuUseCase) targetName(menuType string, id uint) (string, error) {
+	switch menuType {
+	case po.MenuTypeCategory:
+		category, err := uc.data.CategoryRepo.FindByID(id)
+		if err != nil {
+			return "", err
+		}
+		return category.Name, nil
+	case po.MenuTypeTag:
+		tag, err := uc.data.TagRepo.FindByID(id)
+		if err != nil {
+			return "", err
+		}
+		return tag.Name, nil
+	case po.MenuTypePage:
+		page, err := uc.data.PageRepo.FindByID(id)
+		if err != nil {
+			return "", err
+		}
+		return page.Title, nil
+	}
+	return "", ErrMenuInvalid
+}
+
+// itemsByID 查询某个位置的全部菜单项，按 ID 索引
+func (uc *menuUseCase) itemsByID(location string) (map[uint]*po.MenuItem, error) {
+	items, err := uc.data.MenuRepo.ListByLocation(location)
+	if err != nil {
+		return nil, errors.New("查询菜单失败")
+	}
+	byID := make(map[uint]*po.MenuItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	return byID, nil
+}
+
+// tree 组装菜单树，public 为 true 时去掉隐藏和失效的菜单项
+func (uc *menuUseCase) tree(location string, public bool) ([]*dto.MenuItemResponse, error) {
+	items, err := uc.data.MenuRepo.ListByLocation(menuLocation(location))
+	if err != nil {
+		return nil, errors.New("查询菜单失败")
+	}
+	targets, err := uc.loadTargets(items)
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []*po.MenuItem
+	children := make(map[uint][]*po.MenuItem)
+	for _, item := range items {
+		if item.ParentID == nil {
+			roots = append(roots, item)
+		} else {
+			children[*item.ParentID] = append(children[*item.ParentID], item)
+		}
+	}
+
+	var build func(level []*po.MenuItem, depth int) []*dto.MenuItemResponse
+	build = func(level []*po.MenuItem, depth int) []*dto.MenuItemResponse {
+		result := make([]*dto.MenuItemResponse, 0, len(level))
+		for _, item := range level {
+			resp := targets.convert(item)
+			if public && (!item.Visible || resp.Broken) {
+				continue
+			}
+			if depth < maxMenuDepth {
+				resp.Children = build(children[item.ID], depth+1)
+			}
+			result = append(result, resp)
+		}
+		return result
+	}
+	return build(roots, 1), nil
+}
+
+// convertOne 转换单个菜单项，不包含子菜单
+func (uc *menuUseCase) convertOne(item *po.MenuItem) (*dto.MenuItemResponse, error) {
+	targets, err := uc.loadTargets([]*po.MenuItem{item})
+	if err != nil {
+		return nil, err
+	}
+	return targets.convert(item), nil
+}
+
+// menuTargets 菜单项可以链接的分类、标签和已发布页面
+type menuTargets struct {
+	categories map[uint]*po.Category
+	tags       map[uint]*po.Tag
+	pages      map[uint]*po.Page
+}
+
+// loadTargets 查询菜单项用到的链接目标，只加载用到的类型
+func (uc *menuUseCase) loadTargets(items []*po.MenuItem) (*menuTargets, error) {
+	used := make(map[string]bool)
+	for _, item := range items {
+		used[item.Type] = true
+	}
+
+	targets := &menuTargets{
+		categories: make(map[uint]*po.Category),
+		tags:       make(map[uint]*po.Tag),
+		pages:      make(map[uint]*po.Page),
+	}
+	if used[po.MenuTypeCategory] {
+		categories, err := uc.data.CategoryRepo.List()
+		if err != nil {
+			return nil, errors.New("查询分类失败")
+		}
+		for _, category := range categories {
+			targets.categories[category.ID] = category
+		}
+	}
+	if used[po.MenuTypeTag] {
+		tags, err := uc.data.TagRepo.List()
+		if err != nil {
+			return nil, errors.New("查询标签失败")
+		}
+		for _, tag := range tags {
+			targets.tags[tag.ID] = tag
+		}
+	}
+	if used[po.MenuTypePage] {
+		pages, err := uc.data.PageRepo.ListPublished()
+		if err != nil {
+			return nil, errors.New("查询页面失败")
+		}
+		for _, page := range pages {
+			targets.pages[page.ID] = page
+		}
+	}
+	return targets, nil
+}
+
+// convert 转换为菜单项响应，按站点地图的前台地址格式解析链接
+func (t *menuTargets) convert(item *po.MenuItem) *dto.MenuItemResponse {
+	resp := &dto.MenuItemResponse{
+		ID:       item.ID,
+		ParentID: item.ParentID,
+		Title:    item.Title,
+		Type:     item.Type,
+		TargetID: item.TargetID,
+		URL:      item.URL,
+		Icon:     item.Icon,
+		NewTab:   item.NewTab,
+		Visible:  item.Visible,
+		Sort:     item.Sort,
+		Children: []*dto.MenuItemResponse{},
+	}
+	if item.Type == po.MenuTypeLink {
+		return resp
+	}
+
+	cfg := config.AppConfig.Sitemap
+	resp.Broken = true
+	if item.TargetID == nil {
+		return resp
+	}
+	switch item.Type {
+	case po.MenuTypeCategory:
+		if category, ok := t.categories[*item.TargetID]; ok {
+			resp.URL = expandPath(pathOrDefault(cfg.CategoryPath, defaultCategoryPath), "{name}", category.Name, category.ID)
+			resp.Broken = false
+		}
+	case po.MenuTypeTag:
+		if tag, ok := t.tags[*item.TargetID]; ok {
+			resp.URL = expandPath(pathOrDefault(cfg.TagPath, defaultTagPath), "{name}", tag.Name, tag.ID)
+			resp.Broken = false
+		}
+	case po.MenuTypePage:
+		if page, ok := t.pages[*item.TargetID]; ok {
+			resp.URL = expandPath(pathOrDefault(cfg.PagePath, defaultPagePath), "{slug}", page.Slug, page.ID)
+			resp.Broken = false
+		}
+	}
+	return resp
+}
+
+// validateMenuTree 校验菜单树：上级必须存在于同一位置、不能形成环、层级不超过 maxMenuDepth
+func validateMenuTree(items map[uint]*po.MenuItem) error {
+	for _, item := range items {
+		depth := 1
+		for current := item; current.ParentID != nil; depth++ {
+			parent, ok := items[*current.ParentID]
+			if !ok {
+				return fmt.Errorf("%w: 上级菜单不存在", ErrMenuInvalid)
+			}
+			if parent.ID == item.ID {
+				return fmt.Errorf("%w: 不能移动到自己或子菜单下", ErrMenuInvalid)
+			}
+			if depth >= maxMenuDepth {
+				return fmt.Errorf("%w: 菜单最多 %d 级", ErrMenuInvalid, maxMenuDepth)
+			}
+			current = parent
+		}
+	}
+	return nil
+}
+
+// validLinkURL 站内路径（/ 开头，不含协议相对地址）或 http(s) 地址
+func validLinkURL(link string) bool {
+	if strings.HasPrefix(link, "/") {
+		return !strings.HasPrefix(link, "//")
+	}
+	lower := strings.ToLower(link)
+	return (strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")) && len(link) > len("https://")
+}
+
+// menuLocation 菜单位置，默认顶部导航
+func menuLocation(location string) string {
+	if location == "" {
+		return po.MenuLocationHeader
+	}
+	return location
+}
@@ -0,0 +1,122 @@
+package biz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// stubMenuRepo 保存在内存中的菜单仓储
+type stubMenuRepo struct {
+	data.MenuRepo
+	items  map[uint]*po.MenuItem
+	nextID uint
+}
+
+func (r *stubMenuRepo) Create(item *po.MenuItem) error {
+	r.nextID++
+	item.ID = r.nextID
+	r.items[item.ID] = item
+	return nil
+}
+
+func (r *stubMenuRepo) ListByLocation(location string) ([]*po.MenuItem, error) {
+	var items []*po.MenuItem
+	for id := uint(1); id <= r.nextID; id++ {
+		if item, ok := r.items[id]; ok && item.Location == location {
+			copied := *item
+			items = append(items, &copied)
+		}
+	}
+	return items, nil
+}
+
+func (r *stubMenuRepo) UpdateTree(items []*po.MenuItem) error {
+	for _, item := range items {
+		r.items[item.ID].ParentID, r.items[item.ID].Sort = item.ParentID, item.Sort
+	}
+	return nil
+}
+
+// stubCategoryRepo 保存在内存中的分类仓储
+type stubCategoryRepo struct {
+	data.CategoryRepo
+	categories []*po.Category
+}
+
+func (r *stubCategoryRepo) FindByID(id uint) (*po.Category, error) {
+	for _, category := range r.categories {
+		if category.ID == id {
+			return category, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubCategoryRepo) List() ([]*po.Category, error) {
+	return r.categories, nil
+}
+
+func TestMenuTree(t *testing.T) {
+	repo := &stubMenuRepo{items: map[uint]*po.MenuItem{}}
+	categories := &stubCategoryRepo{categories: []*po.Category{{ID: 5, Name: "Go 语言"}}}
+	uc := NewMenuUseCase(&data.Data{MenuRepo: repo, CategoryRepo: categories})
+
+	create := func(req *dto.MenuItemRequest) uint {
+		t.Helper()
+		item, err := uc.Create(req)
+		if err != nil {
+			t.Fatalf("Create(%+v): %v", req, err)
+		}
+		return item.ID
+	}
+	hidden := false
+	categoryID := uint(5)
+	home := create(&dto.MenuItemRequest{Title: "首页", Type: po.MenuTypeLink, URL: "/"})
+	category := create(&dto.MenuItemRequest{ParentID: &home, Type: po.MenuTypeCategory, TargetID: &categoryID})
+	level3 := create(&dto.MenuItemRequest{ParentID: &category, Title: "GitHub", Type: po.MenuTypeLink, URL: "https://github.com", NewTab: true})
+	create(&dto.MenuItemRequest{Title: "草稿", Type: po.MenuTypeLink, URL: "/draft", Visible: &hidden})
+
+	if _, err := uc.Create(&dto.MenuItemRequest{ParentID: &level3, Title: "Too deep", Type: po.MenuTypeLink, URL: "/deep"}); !errors.Is(err, ErrMenuInvalid) {
+		t.Errorf("fourth level Create = %v, want ErrMenuInvalid", err)
+	}
+	for _, link := range []string{"javascript:alert(1)", "//evil.example.com", "https://"} {
+		if _, err := uc.Create(&dto.MenuItemRequest{Title: "x", Type: po.MenuTypeLink, URL: link}); !errors.Is(err, ErrMenuInvalid) {
+			t.Errorf("Create with URL %q = %v, want ErrMenuInvalid", link, err)
+		}
+	}
+	missing := uint(9)
+	if _, err := uc.Create(&dto.MenuItemRequest{Type: po.MenuTypeCategory, TargetID: &missing}); !errors.Is(err, ErrMenuInvalid) {
+		t.Errorf("Create with a missing category = %v, want ErrMenuInvalid", err)
+	}
+
+	if err := uc.Sort(&dto.MenuSortRequest{Location: po.MenuLocationHeader, Items: []dto.MenuSortItem{{ID: home, ParentID: &level3}}}); !errors.Is(err, ErrMenuInvalid) {
+		t.Errorf("Sort into a cycle = %v, want ErrMenuInvalid", err)
+	}
+
+	tree, err := uc.PublicTree("")
+	if err != nil {
+		t.Fatalf("PublicTree: %v", err)
+	}
+	if len(tree) != 1 || len(tree[0].Children) != 1 || len(tree[0].Children[0].Children) != 1 {
+		t.Fatalf("PublicTree = %+v, want home > category > GitHub", tree)
+	}
+	if item := tree[0].Children[0]; item.Title != "Go 语言" || item.URL != "/category/Go%20%E8%AF%AD%E8%A8%80" {
+		t.Errorf("category item = %+v, want the category name and path", item)
+	}
+
+	// 分类删除后菜单项在前台隐藏，后台标记为失效
+	categories.categories = nil
+	tree, _ = uc.PublicTree(po.MenuLocationHeader)
+	if len(tree) != 1 || len(tree[0].Children) != 0 {
+		t.Errorf("PublicTree after deleting the category = %+v, want only home", tree)
+	}
+	admin, _ := uc.Tree(po.MenuLocationHeader)
+	if len(admin) != 2 || !admin[0].Children[0].Broken {
+		t.Errorf("Tree = %+v, want the hidden item and a broken category", admin)
+	}
+}
@@ -0,0 +1,270 @@
+package biz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// maxMomentImages 每条说说最多附带的图片数
+const maxMomentImages = 9
+
+var (
+	// ErrMomentNotFound 说说不存在
+	ErrMomentNotFound = errors.New("说说不存在")
+	// ErrMomentInvalid 说说内容不合法
+	ErrMomentInvalid = errors.New("说说内容不合法")
+)
+
+// MomentUseCase 说说业务用例接口
+type MomentUseCase interface {
+	// Timeline 前台分页查询说说，按发布时间倒序，userID 不为 0 时标记是否已点赞
+	Timeline(userID uint, page, limit int) (*dto.PageResponse, error)
+	// List 后台分页查询说说
+	List(req *dto.MomentListRequest) (*dto.PageResponse, error)
+	// Get 查询单条说说
+	Get(id, userID uint) (*dto.MomentResponse, error)
+	// Create 发布说说，图片需先通过文件上传接口上传
+	Create(req *dto.MomentRequest, authorID uint) (*dto.MomentResponse, error)
+	// Update 编辑说说
+	Update(id uint, req *dto.MomentRequest) (*dto.MomentResponse, error)
+	// Delete 删除说说，图片由未引用图片清理任务回收
+	Delete(id uint) error
+	// Like 点赞说说，重复点赞不报错
+	Like(id, userID uint) (*dto.MomentLikeResponse, error)
+	// Unlike 取消点赞，未点赞时不报错
+	Unlike(id, userID uint) (*dto.MomentLikeResponse, error)
+}
+
+// momentUseCase 说说业务用例实现
+type momentUseCase struct {
+	data *data.Data
+}
+
+// NewMomentUseCase 创建说说业务用例
+func NewMomentUseCase(d *data.Data) MomentUseCase {
+	return &momentUseCase{data: d}
+}
+
+// Timeline 前台分页查询说说
+func (uc *momentUseCase) Timeline(userID uint, page, limit int) (*dto.PageResponse, error) {
+	return uc.list(userID, page, limit, "")
+}
+
+// List 后台分页查询说说
+func (uc *momentUseCase) List(req *dto.MomentListRequest) (*dto.PageResponse, error) {
+	return uc.list(0, req.Page, req.Limit, req.Keyword)
+}
+
+// Get 查询单条说说
+func (uc *momentUseCase) Get(id, userID uint) (*dto.MomentResponse, error) {
+	moment, err := uc.find(id)
+	if err != nil {
+		return nil, err
+	}
+	items, err := uc.convert([]*po.Moment{moment}, userID)
+	if err != nil {
+		return nil, err
+	}
+	return items[0], nil
+}
+
+// Create 发布说说
+func (uc *momentUseCase) Create(req *dto.MomentRequest, authorID uint) (*dto.MomentResponse, error) {
+	moment := &po.Moment{AuthorID: authorID}
+	if err := uc.apply(moment, req); err != nil {
+		return nil, err
+	}
+	if err := uc.data.MomentRepo.Create(moment); err != nil {
+		return nil, errors.New("发布说说失败")
+	}
+	return uc.Get(moment.ID, 0)
+}
+
+// Update 编辑说说，点赞数和发布时间不变
+func (uc *momentUseCase) Update(id uint, req *dto.MomentRequest) (*dto.MomentResponse, error) {
+	moment, err := uc.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.apply(moment, req); err != nil {
+		return nil, err
+	}
+	if err := uc.data.MomentRepo.Update(moment); err != nil {
+		return nil, errors.New("更新说说失败")
+	}
+	return uc.Get(moment.ID, 0)
+}
+
+// Delete 删除说说
+func (uc *momentUseCase) Delete(id uint) error {
+	if _, err := uc.find(id); err != nil {
+		return err
+	}
+	if err := uc.data.MomentRepo.Delete(id); err != nil {
+		return errors.New("删除说说失败")
+	}
+	return nil
+}
+
+// Like 点赞说说
+func (uc *momentUseCase) Like(id, userID uint) (*dto.MomentLikeResponse, error) {
+	if _, err := uc.find(id); err != nil {
+		return nil, err
+	}
+	if _, err := uc.data.MomentRepo.Like(id, userID); err != nil {
+		return nil, errors.New("点赞失败")
+	}
+	return uc.likeState(id, true)
+}
+
+// Unlike 取消点赞
+func (uc *momentUseCase) Unlike(id, userID uint) (*dto.MomentLikeResponse, error) {
+	if _, err := uc.find(id); err != nil {
+		return nil, err
+	}
+	if _, err := uc.data.MomentRepo.Unlike(id, userID); err != nil {
+		return nil, errors.New("取消点赞失败")
+	}
+	return uc.likeState(id, false)
+}
+
+// likeState 返回点赞操作后的最新点赞数
+func (uc *momentUseCase) likeState(id uint, liked bool) (*dto.MomentLikeResponse, error) {
+	moment, err := uc.find(id)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.MomentLikeResponse{Liked: liked, LikeCount: moment.LikeCount}, nil
+}
+
+// list 分页查询说说并转换为响应
+func (uc *momentUseCase) list(userID uint, page, limit int, keyword string) (*dto.PageResponse, error) {
+	moments, total, err := uc.data.MomentRepo.List(page, limit, keyword)
+	if err != nil {
+		return nil, errors.New("查询说说列表失败")
+	}
+	items, err := uc.convert(moments, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PageResponse{
+		Total: total,
+		Page:  page,
+		Limit: limit,
+		Data:  items,
+	}, nil
+}
+
+// find 查询说说
+func (uc *momentUseCase) find(id uint) (*po.Moment, error) {
+	moment, err := uc.data.MomentRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrMomentNotFound
+	}
+	return moment, nil
+}
+
+// apply 校验请求并写入说说，图片去重后必须都有上传记录
+func (uc *momentUseCase) apply(moment *po.Moment, req *dto.MomentRequest) error {
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return fmt.Errorf("%w: 内容不能为空", ErrMomentInvalid)
+	}
+
+	images := make([]string, 0, len(req.Images))
+	seen := make(map[string]bool, len(req.Images))
+	for _, image := range req.Images {
+		image = strings.TrimSpace(image)
+		if image == "" || seen[image] {
+			continue
+		}
+		seen[image] = true
+		images = append(images, image)
+	}
+	if len(images) > maxMomentImages {
+		return fmt.Errorf("%w: 最多上传 %d 张图片", ErrMomentInvalid, maxMomentImages)
+	}
+
+	files, err := uc.data.FileRepo.FindByURLs(images)
+	if err != nil {
+		return errors.New("查询图片失败")
+	}
+	uploaded := make(map[string]bool, len(files))
+	for _, file := range files {
+		if strings.HasPrefix(file.MimeType, "image/") {
+			uploaded[file.URL] = true
+		}
+	}
+	for _, image := range images {
+		if !uploaded[image] {
+			return fmt.Errorf("%w: 图片 %s 不是通过文件上传接口上传的图片", ErrMomentInvalid, image)
+		}
+	}
+
+	encoded, _ := json.Marshal(images)
+	moment.Content = content
+	moment.Images = string(encoded)
+	moment.Location = strings.TrimSpace(req.Location)
+	return nil
+}
+
+// convert 转换为说说响应，图片附带缩略图和 WebP 地址，并标记用户是否已点赞
+func (uc *momentUseCase) convert(moments []*po.Moment, userID uint) ([]*dto.MomentResponse, error) {
+	ids := make([]uint, 0, len(moments))
+	imagesByMoment := make(map[uint][]string, len(moments))
+	var urls []string
+	for _, moment := range moments {
+		ids = append(ids, moment.ID)
+		var images []string
+		if moment.Images != "" {
+			_ = json.Unmarshal([]byte(moment.Images), &images)
+		}
+		imagesByMoment[moment.ID] = images
+		urls = append(urls, images...)
+	}
+
+	files, err := uc.data.FileRepo.FindByURLs(urls)
+	if err != nil {
+		return nil, errors.New("查询图片失败")
+	}
+	media := make(map[string]*dto.MediaItem, len(files))
+	for _, file := range files {
+		media[file.URL] = convertToMediaItem(file)
+	}
+
+	liked, err := uc.data.MomentRepo.LikedIDs(userID, ids)
+	if err != nil {
+		return nil, errors.New("查询点赞状态失败")
+	}
+
+	items := make([]*dto.MomentResponse, 0, len(moments))
+	for _, moment := range moments {
+		images := make([]*dto.MomentImage, 0, len(imagesByMoment[moment.ID]))
+		for _, url := range imagesByMoment[moment.ID] {
+			image := &dto.MomentImage{URL: url, ThumbnailURL: url}
+			// 上传记录被删除时只返回原图地址
+			if item, ok := media[url]; ok {
+				image.ThumbnailURL = item.ThumbnailURL
+				image.WebPURL = item.WebPURL
+			}
+			images = append(images, image)
+		}
+		items = append(items, &dto.MomentResponse{
+			ID:        moment.ID,
+			Content:   moment.Content,
+			Images:    images,
+			Location:  moment.Location,
+			LikeCount: moment.LikeCount,
+			IsLiked:   liked[moment.ID],
+			CreatedAt: moment.CreatedAt,
+			UpdatedAt: moment.UpdatedAt,
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,139 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// stubMomentRepo 保存在内存中的说说仓储
+type stubMomentRepo struct {
+	data.MomentRepo
+	moments map[uint]*po.Moment
+	likes   map[uint]map[uint]bool
+}
+
+func (r *stubMomentRepo) Create(moment *po.Moment) error {
+	moment.ID = uint(len(r.moments) + 1)
+	r.moments[moment.ID] = moment
+	return nil
+}
+
+func (r *stubMomentRepo) FindByID(id uint) (*po.Moment, error) {
+	if moment, ok := r.moments[id]; ok {
+		return moment, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubMomentRepo) Like(momentID, userID uint) (bool, error) {
+	if r.likes[momentID][userID] {
+		return false, nil
+	}
+	if r.likes[momentID] == nil {
+		r.likes[momentID] = map[uint]bool{}
+	}
+	r.likes[momentID][userID] = true
+	r.moments[momentID].LikeCount++
+	return true, nil
+}
+
+func (r *stubMomentRepo) LikedIDs(userID uint, momentIDs []uint) (map[uint]bool, error) {
+	liked := map[uint]bool{}
+	for _, id := range momentIDs {
+		liked[id] = r.likes[id][userID]
+	}
+	return liked, nil
+}
+
+// stubFileRepo 只支持按地址查询的文件仓储
+type stubFileRepo struct {
+	data.FileRepo
+	files []*po.File
+}
+
+func (r *stubFileRepo) FindByURLs(urls []string) ([]*po.File, error) {
+	var files []*po.File
+	for _, file := range r.files {
+		for _, url := range urls {
+			if file.URL == url {
+				files = append(files, file)
+			}
+		}
+	}
+	return files, nil
+}
+
+func TestMomentCreate(t *testing.T) {
+	files := &stubFileRepo{files: []*po.File{
+		{URL: "/uploads/moments/a.jpg", MimeType: "image/jpeg", WebPURL: "/uploads/moments/a.webp",
+			Variants: `[{"name":"thumbnail","width":320,"url":"/uploads/moments/a_thumbnail.jpg"}]`},
+		{URL: "/uploads/docs/b.pdf", MimeType: "application/pdf"},
+	}}
+	for i := 0; i < maxMomentImages+1; i++ {
+		files.files = append(files.files, &po.File{URL: fmt.Sprintf("/uploads/moments/%d.png", i), MimeType: "image/png"})
+	}
+	repo := &stubMomentRepo{moments: map[uint]*po.Moment{}, likes: map[uint]map[uint]bool{}}
+	uc := NewMomentUseCase(&data.Data{MomentRepo: repo, FileRepo: files})
+
+	resp, err := uc.Create(&dto.MomentRequest{
+		Content:  " 今天天气不错 ",
+		Images:   []string{"/uploads/moments/a.jpg", "/uploads/moments/a.jpg"},
+		Location: "杭州",
+	}, 1)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if resp.Content != "今天天气不错" || len(resp.Images) != 1 {
+		t.Fatalf("Create = %+v, want trimmed content and one deduplicated image", resp)
+	}
+	if image := resp.Images[0]; image.ThumbnailURL != "/uploads/moments/a_thumbnail.jpg" || image.WebPURL != "/uploads/moments/a.webp" {
+		t.Errorf("image = %+v, want the uploaded variants", image)
+	}
+
+	invalid := [][]string{
+		{"https://example.com/c.jpg"},
+		{"/uploads/docs/b.pdf"},
+	}
+	var tooMany []string
+	for i := 0; i < maxMomentImages+1; i++ {
+		tooMany = append(tooMany, fmt.Sprintf("/uploads/moments/%d.png", i))
+	}
+	invalid = append(invalid, tooMany)
+	for _, images := range invalid {
+		if _, err := uc.Create(&dto.MomentRequest{Content: "x", Images: images}, 1); !errors.Is(err, ErrMomentInvalid) {
+			t.Errorf("Create with images %v = %v, want ErrMomentInvalid", images, err)
+		}
+	}
+}
+
+func TestMomentLike(t *testing.T) {
+	repo := &stubMomentRepo{moments: map[uint]*po.Moment{1: {ID: 1, Content: "hi"}}, likes: map[uint]map[uint]bool{}}
+	uc := NewMomentUseCase(&data.Data{MomentRepo: repo, FileRepo: &stubFileRepo{}})
+
+	for i := 0; i < 2; i++ {
+		resp, err := uc.Like(1, 7)
+		if err != nil {
+			t.Fatalf("Like: %v", err)
+		}
+		if !resp.Liked || resp.LikeCount != 1 {
+			t.Fatalf("Like #%d = %+v, want liked once", i+1, resp)
+		}
+	}
+	if _, err := uc.Like(2, 7); !errors.Is(err, ErrMomentNotFound) {
+		t.Errorf("Like on a missing moment = %v, want ErrMomentNotFound", err)
+	}
+
+	moment, err := uc.Get(1, 7)
+	if err != nil || !moment.IsLiked {
+		t.Errorf("Get = %+v, %v, want IsLiked", moment, err)
+	}
+	if moment, _ := uc.Get(1, 8); moment.IsLiked {
+		t.Error("another user sees the moment as liked")
+	}
+}
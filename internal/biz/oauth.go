@@ -0,0 +1,259 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/jwt"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/oauth"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// oauthStateExpire 授权页面停留的最长时间
+const oauthStateExpire = 10 * time.Minute
+
+// usernameInvalidChars 生成用户名时去掉的字符
+var usernameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+var (
+	// ErrOAuthStateInvalid state 无效或已过期
+	ErrOAuthStateInvalid = errors.New("登录请求已过期，请重新登录")
+	// ErrOAuthFailed 换取第三方账号信息失败
+	ErrOAuthFailed = errors.New("第三方登录失败，请稍后再试")
+)
+
+// OAuthUseCase 第三方登录业务用例接口
+type OAuthUseCase interface {
+	// Providers 已启用的登录方式
+	Providers() []string
+	// AuthURL 生成授权页面地址，state 需由调用方保存到浏览器并在回调时一并提交
+	AuthURL(provider, redirectURI string) (authURL, state string, err error)
+	// Login 使用授权码登录，首次登录时关联同邮箱的已有账号或创建新账号
+	Login(ctx context.Context, provider, code, state, redirectURI string, client *dto.ClientInfo) (*dto.LoginResponse, error)
+}
+
+// oauthUseCase 第三方登录业务用例实现
+type oauthUseCase struct {
+	data *data.Data
+}
+
+// NewOAuthUseCase 创建第三方登录业务用例
+func NewOAuthUseCase(d *data.Data) OAuthUseCase {
+	return &oauthUseCase{data: d}
+}
+
+// Providers 已启用的登录方式
+func (uc *oauthUseCase) Providers() []string {
+	return oauth.Enabled()
+}
+
+// AuthURL 生成授权页面地址
+func (uc *oauthUseCase) AuthURL(provider, redirectURI string) (string, string, error) {
+	p, err := oauth.Get(provider)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := randomHex(16)
+	if err != nil {
+		return "", "", errors.New("生成登录请求失败")
+	}
+	state, err := jwt.GenerateOAuthState(p.Name(), nonce, oauthStateExpire)
+	if err != nil {
+		return "", "", errors.New("生成登录请求失败")
+	}
+	return p.AuthURL(state, redirectURI), state, nil
+}
+
+// Login 使用授权码登录
+func (uc *oauthUseCase) Login(ctx context.Context, provider, code, state, redirectURI string, client *dto.ClientInfo) (*dto.LoginResponse, error) {
+	p, err := oauth.Get(provider)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := jwt.ParseOAuthState(state)
+	if err != nil || claims.Provider != p.Name() {
+		return nil, ErrOAuthStateInvalid
+	}
+
+	identity, err := p.Exchange(ctx, code, redirectURI)
+	if err != nil {
+		logger.FromContext(ctx).Error("OAuth exchange failed for ", provider, ": ", err)
+		return nil, ErrOAuthFailed
+	}
+	if identity.Subject == "" {
+		return nil, ErrOAuthFailed
+	}
+
+	user, err := uc.resolveUser(identity)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status == po.UserStatusBanned {
+		err := errors.New("账号已被禁用")
+		recordLogin(ctx, uc.data, user, user.Username, po.LoginChannelOAuth, client, err)
+		return nil, err
+	}
+
+	tokens, err := issueTokens(user, "user", client)
+	if err != nil {
+		return nil, err
+	}
+	recordLogin(ctx, uc.data, user, user.Username, po.LoginChannelOAuth, client, nil)
+	return &dto.LoginResponse{
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		User: &dto.UserInfo{
+			ID:        user.ID,
+			Username:  user.Username,
+			Email:     user.Email,
+			Nickname:  user.Nickname,
+			Avatar:    user.Avatar,
+			Bio:       user.Bio,
+			Skills:    user.Skills,
+			Contacts:  user.Contacts,
+			Role:      user.Role,
+			Status:    user.Status,
+			CreatedAt: user.CreatedAt,
+		},
+	}, nil
+}
+
+// resolveUser 查找第三方账号绑定的用户
+// 未绑定时依次按同一开放平台的 unionid、第三方平台已验证的邮箱关联已有账号，都没有时创建新账号
+func (uc *oauthUseCase) resolveUser(identity *oauth.Identity) (*po.User, error) {
+	if bound, err := uc.data.UserIdentityRepo.FindBySubject(identity.Provider, identity.Subject); err == nil {
+		user, err := uc.data.UserRepo.FindByID(bound.UserID)
+		if err != nil {
+			return nil, errors.New("绑定的账号不存在")
+		}
+		// 应用后来才绑定到开放平台时，之前的绑定没有 unionid
+		if bound.UnionID == "" && identity.UnionID != "" {
+			if err := uc.data.UserIdentityRepo.UpdateUnionID(bound.ID, identity.UnionID); err != nil {
+				logger.Warn("Failed to update oauth union id: ", err)
+			}
+		}
+		return user, nil
+	}
+
+	var user *po.User
+	if identity.UnionID != "" {
+		if providers := oauth.UnionProviders(identity.Provider); len(providers) > 0 {
+			if bound, err := uc.data.UserIdentityRepo.FindByUnionID(providers, identity.UnionID); err == nil {
+				if existing, err := uc.data.UserRepo.FindByID(bound.UserID); err == nil {
+					user = existing
+				}
+			}
+		}
+	}
+	if user == nil && identity.Email != "" && identity.EmailVerified {
+		if existing, err := uc.data.UserRepo.FindByEmail(identity.Email); err == nil {
+			user = existing
+			// 第三方平台已验证过该邮箱，等同于完成了邮箱验证
+			if user.Status == po.UserStatusUnverified {
+				user.Status = po.UserStatusActive
+				if err := uc.data.UserRepo.Update(user); err != nil {
+					return nil, errors.New("登录失败")
+				}
+			}
+		}
+	}
+	if user == nil {
+		created, err := uc.provisionUser(identity)
+		if err != nil {
+			return nil, err
+		}
+		user = created
+	}
+
+	if err := uc.data.UserIdentityRepo.Create(&po.UserIdentity{
+		UserID:   user.ID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		UnionID:  identity.UnionID,
+		Login:    identity.Login,
+	}); err != nil {
+		// 并发回调时另一请求已完成绑定
+		if bound, findErr := uc.data.UserIdentityRepo.FindBySubject(identity.Provider, identity.Subject); findErr == nil && bound.UserID == user.ID {
+			return user, nil
+		}
+		logger.Error("Failed to bind oauth identity: ", err)
+		return nil, errors.New("绑定第三方账号失败")
+	}
+	return user, nil
+}
+
+// provisionUser 首次登录时使用第三方资料创建账号，密码随机生成，用户可通过找回密码设置
+func (uc *oauthUseCase) provisionUser(identity *oauth.Identity) (*po.User, error) {
+	if !settingBool(uc.data, SettingAllowRegistration) {
+		return nil, ErrRegistrationClosed
+	}
+
+	username, err := uc.uniqueUsername(identity)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, errors.New("创建用户失败")
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errors.New("密码加密失败")
+	}
+
+	// 未验证的邮箱不保存，避免占用他人邮箱
+	email := ""
+	if identity.EmailVerified {
+		email = identity.Email
+	}
+	nickname := identity.Nickname
+	if nickname == "" {
+		nickname = identity.Login
+	}
+	user := &po.User{
+		Username: username,
+		Email:    email,
+		Password: string(hashedPassword),
+		Nickname: truncateRunes(strings.TrimSpace(nickname), 50),
+		Avatar:   truncateRunes(identity.Avatar, 500),
+		Bio:      truncateRunes(strings.TrimSpace(identity.Bio), 500),
+		Status:   po.UserStatusActive,
+	}
+	if err := uc.data.UserRepo.Create(user); err != nil {
+		return nil, errors.New("创建用户失败")
+	}
+	return user, nil
+}
+
+// uniqueUsername 由第三方用户名生成本站用户名，重名时追加随机后缀
+func (uc *oauthUseCase) uniqueUsername(identity *oauth.Identity) (string, error) {
+	base := usernameInvalidChars.ReplaceAllString(identity.Login, "")
+	if len(base) < 3 {
+		base = fmt.Sprintf("%s_%s", identity.Provider, usernameInvalidChars.ReplaceAllString(identity.Subject, ""))
+	}
+	if len(base) > 40 {
+		base = base[:40]
+	}
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		if _, err := uc.data.UserRepo.FindByUsername(candidate); err != nil {
+			return candidate, nil
+		}
+		suffix, err := randomHex(3)
+		if err != nil {
+			break
+		}
+		candidate = base + "_" + suffix
+	}
+	return "", errors.New("生成用户名失败")
+}
@@ -0,0 +1,126 @@
+package biz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/oauth"
+)
+
+// stubUserIdentityRepo 内存中的第三方账号绑定仓储
+type stubUserIdentityRepo struct {
+	data.UserIdentityRepo
+	identities []*po.UserIdentity
+}
+
+func (r *stubUserIdentityRepo) Create(identity *po.UserIdentity) error {
+	identity.ID = uint(len(r.identities) + 1)
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func (r *stubUserIdentityRepo) FindBySubject(provider, subject string) (*po.UserIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *stubUserIdentityRepo) FindByUnionID(providers []string, unionID string) (*po.UserIdentity, error) {
+	for _, identity := range r.identities {
+		for _, provider := range providers {
+			if identity.Provider == provider && identity.UnionID == unionID {
+				return identity, nil
+			}
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *stubUserIdentityRepo) UpdateUnionID(id uint, unionID string) error {
+	for _, identity := range r.identities {
+		if identity.ID == id {
+			identity.UnionID = unionID
+		}
+	}
+	return nil
+}
+
+// stubSettingRepo 没有任何配置项的设置仓储
+type stubSettingRepo struct {
+	data.SettingRepo
+}
+
+func (stubSettingRepo) FindByKey(key string) (*po.Setting, error) {
+	return nil, errors.New("record not found")
+}
+
+func TestOAuthResolveUserByUnionID(t *testing.T) {
+	tests := []struct {
+		name     string
+		bound    []*po.UserIdentity
+		identity oauth.Identity
+		wantUser uint
+	}{
+		{
+			name:     "same subject",
+			bound:    []*po.UserIdentity{{ID: 1, UserID: 10, Provider: "wechat", Subject: "o-web", UnionID: "u1"}},
+			identity: oauth.Identity{Provider: "wechat", Subject: "o-web", UnionID: "u1"},
+			wantUser: 10,
+		},
+		{
+			name:     "other wechat app with same unionid",
+			bound:    []*po.UserIdentity{{ID: 1, UserID: 10, Provider: "wechat", Subject: "o-web", UnionID: "u1"}},
+			identity: oauth.Identity{Provider: "wechat_mp", Subject: "o-mp", UnionID: "u1"},
+			wantUser: 10,
+		},
+		{
+			name:     "unionid is not shared across platforms",
+			bound:    []*po.UserIdentity{{ID: 1, UserID: 10, Provider: "wechat", Subject: "o-web", UnionID: "u1"}},
+			identity: oauth.Identity{Provider: "qq", Subject: "q1", UnionID: "u1"},
+		},
+		{
+			name:     "no unionid",
+			bound:    []*po.UserIdentity{{ID: 1, UserID: 10, Provider: "wechat", Subject: "o-web"}},
+			identity: oauth.Identity{Provider: "wechat_mp", Subject: "o-mp"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identities := &stubUserIdentityRepo{identities: tt.bound}
+			users := &stubUserRepo{users: map[uint]*po.User{10: {ID: 10, Username: "alice", Status: po.UserStatusActive}}}
+			uc := &oauthUseCase{data: &data.Data{UserRepo: users, UserIdentityRepo: identities, SettingRepo: stubSettingRepo{}}}
+
+			user, err := uc.resolveUser(&tt.identity)
+			if err != nil {
+				t.Fatalf("resolveUser: %v", err)
+			}
+			// wantUser 为 0 表示不应关联已有账号，而是创建新账号
+			if (tt.wantUser == 0 && user.ID == 10) || (tt.wantUser != 0 && user.ID != tt.wantUser) {
+				t.Fatalf("user = %d, want %d", user.ID, tt.wantUser)
+			}
+			bound, err := identities.FindBySubject(tt.identity.Provider, tt.identity.Subject)
+			if err != nil || bound.UserID != user.ID || bound.UnionID != tt.identity.UnionID {
+				t.Errorf("identity not bound to user %d: %+v, %v", user.ID, bound, err)
+			}
+		})
+	}
+}
+
+func TestOAuthResolveUserBackfillsUnionID(t *testing.T) {
+	identities := &stubUserIdentityRepo{identities: []*po.UserIdentity{{ID: 1, UserID: 10, Provider: "wechat", Subject: "o-web"}}}
+	users := &stubUserRepo{users: map[uint]*po.User{10: {ID: 10, Username: "alice", Status: po.UserStatusActive}}}
+	uc := &oauthUseCase{data: &data.Data{UserRepo: users, UserIdentityRepo: identities}}
+
+	if _, err := uc.resolveUser(&oauth.Identity{Provider: "wechat", Subject: "o-web", UnionID: "u1"}); err != nil {
+		t.Fatalf("resolveUser: %v", err)
+	}
+	if got := identities.identities[0].UnionID; got != "u1" {
+		t.Errorf("union id = %q, want u1", got)
+	}
+}
@@ -0,0 +1,247 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	mdutils "github.com/ydcloud-dy/leaf-api/pkg/markdown"
+	"github.com/ydcloud-dy/leaf-api/pkg/slug"
+)
+
+var (
+	// ErrPageNotFound 页面不存在或未发布
+	ErrPageNotFound = errors.New("页面不存在")
+	// ErrPageInvalid 页面参数不合法
+	ErrPageInvalid = errors.New("页面参数不合法")
+	// ErrPageSlugExists slug 已被其他页面使用
+	ErrPageSlugExists = errors.New("slug 已被其他页面使用")
+)
+
+// PageUseCase 独立页面业务用例接口
+type PageUseCase interface {
+	// List 后台分页查询页面
+	List(req *dto.StaticPageListRequest) (*dto.PageResponse, error)
+	// Get 后台查询页面详情，包含 Markdown 原文
+	Get(id uint) (*dto.StaticPageResponse, error)
+	// Create 创建页面，正文与文章使用相同的图片处理、渲染和清理流程
+	Create(req *dto.StaticPageRequest, authorID uint) (*dto.StaticPageResponse, error)
+	// Update 更新页面
+	Update(id uint, req *dto.StaticPageRequest) (*dto.StaticPageResponse, error)
+	// Delete 删除页面
+	Delete(id uint) error
+	// GetPublished 前台根据 slug 查询已发布的页面
+	GetPublished(slug string) (*dto.StaticPageResponse, error)
+	// ListPublished 前台查询全部已发布页面，用于导航和页脚
+	ListPublished() ([]*dto.StaticPageSummary, error)
+}
+
+// pageUseCase 独立页面业务用例实现
+type pageUseCase struct {
+	data *data.Data
+}
+
+// NewPageUseCase 创建独立页面业务用例
+func NewPageUseCase(d *data.Data) PageUseCase {
+	return &pageUseCase{data: d}
+}
+
+// List 后台分页查询页面
+func (uc *pageUseCase) List(req *dto.StaticPageListRequest) (*dto.PageResponse, error) {
+	pages, total, err := uc.data.PageRepo.List(req.Page, req.Limit, req.Status, req.Keyword)
+	if err != nil {
+		return nil, errors.New("查询页面列表失败")
+	}
+
+	items := make([]*dto.StaticPageSummary, 0, len(pages))
+	for _, page := range pages {
+		items = append(items, convertToPageSummary(page))
+	}
+
+	return &dto.PageResponse{
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Data:  items,
+	}, nil
+}
+
+// Get 后台查询页面详情
+func (uc *pageUseCase) Get(id uint) (*dto.StaticPageResponse, error) {
+	page, err := uc.data.PageRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrPageNotFound
+	}
+	resp := convertToPageResponse(page)
+	resp.ContentMarkdown = page.ContentMarkdown
+	return resp, nil
+}
+
+// Create 创建页面，未指定状态时保存为草稿
+func (uc *pageUseCase) Create(req *dto.StaticPageRequest, authorID uint) (*dto.StaticPageResponse, error) {
+	page := &po.Page{AuthorID: authorID, Status: po.PageDraft}
+	if err := uc.apply(page, req); err != nil {
+		return nil, err
+	}
+	if err := uc.data.PageRepo.Create(page); err != nil {
+		return nil, errors.New("创建页面失败")
+	}
+	InvalidateSitemap()
+	return uc.Get(page.ID)
+}
+
+// Update 更新页面，slug 为空时保留原值
+func (uc *pageUseCase) Update(id uint, req *dto.StaticPageRequest) (*dto.StaticPageResponse, error) {
+	page, err := uc.data.PageRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrPageNotFound
+	}
+	if err := uc.apply(page, req); err != nil {
+		return nil, err
+	}
+	if err := uc.data.PageRepo.Update(page); err != nil {
+		return nil, errors.New("更新页面失败")
+	}
+	InvalidateSitemap()
+	return uc.Get(page.ID)
+}
+
+// Delete 删除页面
+func (uc *pageUseCase) Delete(id uint) error {
+	if _, err := uc.data.PageRepo.FindByID(id); err != nil {
+		return ErrPageNotFound
+	}
+	if err := uc.data.PageRepo.Delete(id); err != nil {
+		return errors.New("删除页面失败")
+	}
+	InvalidateSitemap()
+	return nil
+}
+
+// GetPublished 前台根据 slug 查询已发布的页面，草稿视为不存在
+func (uc *pageUseCase) GetPublished(slug string) (*dto.StaticPageResponse, error) {
+	page, err := uc.data.PageRepo.FindBySlug(slug)
+	if err != nil || page.Status != po.PagePublished {
+		return nil, ErrPageNotFound
+	}
+	return convertToPageResponse(page), nil
+}
+
+// ListPublished 前台查询全部已发布页面
+func (uc *pageUseCase) ListPublished() ([]*dto.StaticPageSummary, error) {
+	pages, err := uc.data.PageRepo.ListPublished()
+	if err != nil {
+		return nil, errors.New("查询页面列表失败")
+	}
+	items := make([]*dto.StaticPageSummary, 0, len(pages))
+	for _, page := range pages {
+		items = append(items, convertToPageSummary(page))
+	}
+	return items, nil
+}
+
+// apply 校验请求并写入页面，首次发布时记录发布时间
+func (uc *pageUseCase) apply(page *po.Page, req *dto.StaticPageRequest) error {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return fmt.Errorf("%w: 标题不能为空", ErrPageInvalid)
+	}
+
+	if req.Slug != "" || page.Slug == "" {
+		s, err := uc.resolveSlug(req.Slug, title, page.ID)
+		if err != nil {
+			return err
+		}
+		page.Slug = s
+	}
+
+	// 与文章相同：下载外部图片并替换为本地链接，清理多余符号后渲染为清理过的 HTML
+	processor := mdutils.NewImageProcessor("uploads", "")
+	processedMarkdown, err := processor.ProcessMarkdownImages(req.ContentMarkdown)
+	if err != nil {
+		// 图片处理失败不阻断保存，失败的图片保留原地址
+		logger.Warn("Failed to process page images: ", err)
+	}
+	processedMarkdown = mdutils.CleanMarkdownContent(processedMarkdown)
+
+	page.Title = title
+	page.ContentMarkdown = processedMarkdown
+	page.ContentHTML = markdownToHTML(processedMarkdown)
+	page.MetaDescription = strings.TrimSpace(req.MetaDescription)
+	if req.Status != nil {
+		page.Status = *req.Status
+	}
+	if req.Sort != nil {
+		page.Sort = *req.Sort
+	}
+	if page.Status == po.PagePublished && page.PublishedAt == nil {
+		now := time.Now()
+		page.PublishedAt = &now
+	}
+	return nil
+}
+
+// resolveSlug 确定页面 slug
+// 指定了 slug 时规范化后校验唯一性；否则根据标题生成，冲突时追加数字后缀
+func (uc *pageUseCase) resolveSlug(input, title string, excludeID uint) (string, error) {
+	if input != "" {
+		s := slug.Make(input)
+		if s == "" {
+			return "", fmt.Errorf("%w: slug 只能包含字母、数字、中文和连字符", ErrPageInvalid)
+		}
+		exists, err := uc.data.PageRepo.SlugExists(s, excludeID)
+		if err != nil {
+			return "", errors.New("校验 slug 失败")
+		}
+		if exists {
+			return "", ErrPageSlugExists
+		}
+		return s, nil
+	}
+
+	base := slug.Make(title)
+	if base == "" {
+		base = "page"
+	}
+	candidate := base
+	for i := 2; i <= 100; i++ {
+		exists, err := uc.data.PageRepo.SlugExists(candidate, excludeID)
+		if err != nil {
+			return "", errors.New("校验 slug 失败")
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+	return fmt.Sprintf("%s-%d", base, time.Now().UnixNano()), nil
+}
+
+// convertToPageSummary 转换为页面列表项
+func convertToPageSummary(page *po.Page) *dto.StaticPageSummary {
+	return &dto.StaticPageSummary{
+		ID:              page.ID,
+		Title:           page.Title,
+		Slug:            page.Slug,
+		MetaDescription: page.MetaDescription,
+		Status:          page.Status,
+		Sort:            page.Sort,
+		PublishedAt:     page.PublishedAt,
+		UpdatedAt:       page.UpdatedAt,
+	}
+}
+
+// convertToPageResponse 转换为页面详情，目录从 Markdown 标题生成
+func convertToPageResponse(page *po.Page) *dto.StaticPageResponse {
+	return &dto.StaticPageResponse{
+		StaticPageSummary: *convertToPageSummary(page),
+		ContentHTML:       page.ContentHTML,
+		TOC:               buildTOC(page.ContentMarkdown),
+		CreatedAt:         page.CreatedAt,
+	}
+}
@@ -0,0 +1,101 @@
+package biz
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// stubPageRepo 保存在内存中的页面仓储
+type stubPageRepo struct {
+	data.PageRepo
+	pages map[uint]*po.Page
+}
+
+func (r *stubPageRepo) Create(page *po.Page) error {
+	page.ID = uint(len(r.pages) + 1)
+	r.pages[page.ID] = page
+	return nil
+}
+
+func (r *stubPageRepo) Update(page *po.Page) error {
+	r.pages[page.ID] = page
+	return nil
+}
+
+func (r *stubPageRepo) FindByID(id uint) (*po.Page, error) {
+	if page, ok := r.pages[id]; ok {
+		copied := *page
+		return &copied, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubPageRepo) FindBySlug(slug string) (*po.Page, error) {
+	for _, page := range r.pages {
+		if page.Slug == slug {
+			return page, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubPageRepo) SlugExists(slug string, excludeID uint) (bool, error) {
+	for _, page := range r.pages {
+		if page.Slug == slug && page.ID != excludeID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestPageCreateAndPublish(t *testing.T) {
+	repo := &stubPageRepo{pages: map[uint]*po.Page{}}
+	uc := NewPageUseCase(&data.Data{PageRepo: repo})
+
+	page, err := uc.Create(&dto.StaticPageRequest{
+		Title:           "About",
+		ContentMarkdown: "## Hello\n\n<script>alert(1)</script>\n\nhi",
+	}, 1)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if page.Slug != "about" || page.Status != po.PageDraft || page.PublishedAt != nil {
+		t.Fatalf("Create = %+v, want a draft with slug about", page.StaticPageSummary)
+	}
+	if strings.Contains(page.ContentHTML, "<script") || !strings.Contains(page.ContentHTML, "Hello") {
+		t.Errorf("ContentHTML = %q, want rendered and sanitized HTML", page.ContentHTML)
+	}
+	if len(page.TOC) != 1 || page.TOC[0].Text != "Hello" {
+		t.Errorf("TOC = %+v, want the Hello heading", page.TOC)
+	}
+
+	if _, err := uc.GetPublished("about"); !errors.Is(err, ErrPageNotFound) {
+		t.Fatalf("GetPublished on a draft = %v, want ErrPageNotFound", err)
+	}
+
+	second, err := uc.Create(&dto.StaticPageRequest{Title: "About", ContentMarkdown: "x"}, 1)
+	if err != nil || second.Slug != "about-2" {
+		t.Fatalf("second Create = %+v, %v, want slug about-2", second, err)
+	}
+	if _, err := uc.Create(&dto.StaticPageRequest{Title: "Now", Slug: "about", ContentMarkdown: "x"}, 1); !errors.Is(err, ErrPageSlugExists) {
+		t.Fatalf("Create with a taken slug = %v, want ErrPageSlugExists", err)
+	}
+
+	published := po.PagePublished
+	if _, err := uc.Update(page.ID, &dto.StaticPageRequest{Title: "About me", ContentMarkdown: "hi", Status: &published}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, err := uc.GetPublished("about")
+	if err != nil {
+		t.Fatalf("GetPublished: %v", err)
+	}
+	if got.Title != "About me" || got.PublishedAt == nil || got.ContentMarkdown != "" {
+		t.Errorf("GetPublished = %+v, want the published page without markdown", got)
+	}
+}
@@ -0,0 +1,177 @@
+package biz
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/mail"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 找回密码默认值
+const (
+	defaultResetTokenExpire = 30
+	defaultResetEmailLimit  = 3
+	defaultResetIPLimit     = 10
+	resetLimitWindow        = time.Hour
+)
+
+// 重置令牌只保存摘要：password_reset:token:{sha256} -> 用户ID，password_reset:user:{id} -> 当前有效令牌的摘要
+const (
+	resetTokenKey    = "password_reset:token:%s"
+	resetUserKey     = "password_reset:user:%d"
+	resetEmailLimKey = "password_reset:limit:email:%s"
+	resetIPLimKey    = "password_reset:limit:ip:%s"
+)
+
+var (
+	// ErrPasswordResetUnavailable 未启用 Redis 或邮件服务
+	ErrPasswordResetUnavailable = errors.New("找回密码功能未启用")
+	// ErrResetTokenInvalid 重置链接无效、已使用或已过期
+	ErrResetTokenInvalid = errors.New("重置链接无效或已过期")
+	// ErrResetTooFrequent 请求过于频繁
+	ErrResetTooFrequent = errors.New("请求过于频繁，请稍后再试")
+)
+
+// ForgotPassword 发送重置密码邮件
+// 邮箱未注册时同样返回成功，避免通过该接口探测已注册的邮箱；同一邮箱和同一 IP 每小时的请求数有上限
+func (uc *blogUseCase) ForgotPassword(ctx context.Context, email, ip string) error {
+	if redis.Client == nil || !mail.Enabled() {
+		return ErrPasswordResetUnavailable
+	}
+
+	cfg := config.AppConfig.PasswordReset
+	emailKey := tokenDigest(strings.ToLower(strings.TrimSpace(email)))
+	if resetLimitExceeded(fmt.Sprintf(resetIPLimKey, ip), cfg.IPLimit, defaultResetIPLimit) ||
+		resetLimitExceeded(fmt.Sprintf(resetEmailLimKey, emailKey), cfg.EmailLimit, defaultResetEmailLimit) {
+		return ErrResetTooFrequent
+	}
+
+	user, err := uc.data.UserRepo.FindByEmail(email)
+	if err != nil || user.Status == po.UserStatusBanned {
+		return nil
+	}
+
+	token, err := newResetToken(user.ID)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to create password reset token: ", err)
+		return errors.New("发送重置邮件失败")
+	}
+	return sendResetEmail(ctx, user, token)
+}
+
+// ResetPassword 使用重置令牌设置新密码，令牌只能使用一次
+// 未验证邮箱的账号重置成功即视为完成了邮箱验证；重置后用户已登录的会话全部失效
+func (uc *blogUseCase) ResetPassword(token, password string) error {
+	if redis.Client == nil {
+		return ErrPasswordResetUnavailable
+	}
+
+	digest := tokenDigest(token)
+	value, err := redis.GetDel(fmt.Sprintf(resetTokenKey, digest))
+	if err != nil {
+		return ErrResetTokenInvalid
+	}
+	userID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return ErrResetTokenInvalid
+	}
+	_, _ = redis.DelIfEqual(fmt.Sprintf(resetUserKey, userID), digest)
+
+	user, err := uc.data.UserRepo.FindByID(uint(userID))
+	if err != nil || user.Status == po.UserStatusBanned {
+		return ErrResetTokenInvalid
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New("密码加密失败")
+	}
+	user.Password = string(hashedPassword)
+	if user.Status == po.UserStatusUnverified {
+		user.Status = po.UserStatusActive
+	}
+	if err := uc.data.UserRepo.Update(user); err != nil {
+		return errors.New("重置密码失败")
+	}
+	revokeUserSessions(user.ID)
+	return nil
+}
+
+// newResetToken 生成重置令牌，同一用户之前签发的令牌随之失效
+func newResetToken(userID uint) (string, error) {
+	token, err := randomHex(32)
+	if err != nil {
+		return "", err
+	}
+	digest := tokenDigest(token)
+
+	expire := config.AppConfig.PasswordReset.TokenExpire
+	if expire <= 0 {
+		expire = defaultResetTokenExpire
+	}
+	ttl := time.Duration(expire) * time.Minute
+
+	userKey := fmt.Sprintf(resetUserKey, userID)
+	if previous, err := redis.Get(userKey); err == nil {
+		_ = redis.Del(fmt.Sprintf(resetTokenKey, previous))
+	}
+	if err := redis.SetWithExpire(fmt.Sprintf(resetTokenKey, digest), userID, ttl); err != nil {
+		return "", err
+	}
+	if err := redis.SetWithExpire(userKey, digest, ttl); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// sendResetEmail 发送包含重置链接的邮件
+func sendResetEmail(ctx context.Context, user *po.User, token string) error {
+	expire := config.AppConfig.PasswordReset.TokenExpire
+	if expire <= 0 {
+		expire = defaultResetTokenExpire
+	}
+
+	pattern := config.AppConfig.PasswordReset.ResetURL
+	if pattern == "" {
+		pattern = strings.TrimRight(config.AppConfig.Sitemap.SiteURL, "/") + "/reset-password?token={token}"
+	}
+	link := strings.ReplaceAll(pattern, "{token}", url.QueryEscape(token))
+
+	data := mail.Data{"Name": mailName(user), "ExpireMinutes": expire, "Link": link}
+	if err := mail.SendTemplate(ctx, user.Email, mail.TemplatePasswordReset, data); err != nil {
+		logger.FromContext(ctx).Error("Failed to send password reset email: ", err)
+		return errors.New("发送重置邮件失败")
+	}
+	return nil
+}
+
+// resetLimitExceeded 固定窗口计数，超过 limit（未配置时为 fallback）返回 true；Redis 出错时不限制
+func resetLimitExceeded(key string, limit, fallback int) bool {
+	if limit <= 0 {
+		limit = fallback
+	}
+	n, err := redis.IncrWithExpire(key, resetLimitWindow)
+	if err != nil {
+		logger.Warn("Failed to check password reset rate limit: ", err)
+		return false
+	}
+	return n > int64(limit)
+}
+
+// tokenDigest 令牌和邮箱只以 SHA-256 摘要形式出现在 Redis key 中
+func tokenDigest(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
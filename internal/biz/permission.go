@@ -0,0 +1,189 @@
+package biz
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+const (
+	// 路由权限规则缓存 Key
+	routePermissionCacheKey = "permission:routes"
+	// 路由权限规则缓存时间
+	routePermissionCacheExpire = 10 * time.Minute
+)
+
+// PermissionUseCase 路由权限业务用例接口
+type PermissionUseCase interface {
+	// List 查询所有规则
+	List() ([]*po.RoutePermission, error)
+	// Create 创建规则
+	Create(req *dto.RoutePermissionRequest) (*po.RoutePermission, error)
+	// Update 更新规则
+	Update(id uint, req *dto.RoutePermissionRequest) (*po.RoutePermission, error)
+	// Delete 删除规则
+	Delete(id uint) error
+	// Allowed 判断角色是否可以访问该路由（未配置规则的路由默认拒绝）
+	Allowed(method, path, role string) bool
+}
+
+// permissionUseCase 路由权限业务用例实现
+type permissionUseCase struct {
+	data *data.Data
+}
+
+// NewPermissionUseCase 创建路由权限业务用例
+func NewPermissionUseCase(d *data.Data) PermissionUseCase {
+	return &permissionUseCase{data: d}
+}
+
+// List 查询所有规则
+func (uc *permissionUseCase) List() ([]*po.RoutePermission, error) {
+	rules, err := uc.data.RoutePermissionRepo.List()
+	if err != nil {
+		return nil, errors.New("查询权限规则失败")
+	}
+	return rules, nil
+}
+
+// Create 创建规则
+func (uc *permissionUseCase) Create(req *dto.RoutePermissionRequest) (*po.RoutePermission, error) {
+	rule := &po.RoutePermission{}
+	fillRoutePermission(rule, req)
+
+	if err := uc.data.RoutePermissionRepo.Create(rule); err != nil {
+		return nil, errors.New("创建权限规则失败，该路由规则可能已存在")
+	}
+
+	uc.invalidate()
+	return rule, nil
+}
+
+// Update 更新规则
+func (uc *permissionUseCase) Update(id uint, req *dto.RoutePermissionRequest) (*po.RoutePermission, error) {
+	rule, err := uc.data.RoutePermissionRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("权限规则不存在")
+	}
+	fillRoutePermission(rule, req)
+
+	if err := uc.data.RoutePermissionRepo.Update(rule); err != nil {
+		return nil, errors.New("更新权限规则失败")
+	}
+
+	uc.invalidate()
+	return rule, nil
+}
+
+// Delete 删除规则
+func (uc *permissionUseCase) Delete(id uint) error {
+	if _, err := uc.data.RoutePermissionRepo.FindByID(id); err != nil {
+		return errors.New("权限规则不存在")
+	}
+
+	if err := uc.data.RoutePermissionRepo.Delete(id); err != nil {
+		return errors.New("删除权限规则失败")
+	}
+
+	uc.invalidate()
+	return nil
+}
+
+// Allowed 判断角色是否可以访问该路由
+// 按最长路由前缀匹配，前缀相同时指定方法的规则优先于 *
+func (uc *permissionUseCase) Allowed(method, path, role string) bool {
+	// 超级管理员不受规则限制，避免误配置后无法恢复
+	if role == "super_admin" {
+		return true
+	}
+
+	rules, err := uc.rules()
+	if err != nil {
+		// 规则加载失败时无法判断权限，拒绝访问
+		logger.Error("Failed to load route permissions: ", err)
+		return false
+	}
+
+	var matched *po.RoutePermission
+	for _, rule := range rules {
+		if rule.Method != "*" && rule.Method != method {
+			continue
+		}
+		if path != rule.Path && !strings.HasPrefix(path, strings.TrimSuffix(rule.Path, "/")+"/") {
+			continue
+		}
+		if matched == nil || len(rule.Path) > len(matched.Path) ||
+			(len(rule.Path) == len(matched.Path) && matched.Method == "*") {
+			matched = rule
+		}
+	}
+
+	// 没有匹配的规则时拒绝访问，启动时会创建默认的根路由规则（/ → admin）
+	if matched == nil {
+		return false
+	}
+
+	for _, r := range strings.Split(matched.Roles, ",") {
+		r = strings.TrimSpace(r)
+		if r == "*" || r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// rules 获取规则列表（优先读取 Redis 缓存）
+func (uc *permissionUseCase) rules() ([]*po.RoutePermission, error) {
+	if redis.Client != nil {
+		if cached, err := redis.Get(routePermissionCacheKey); err == nil {
+			var rules []*po.RoutePermission
+			if err := json.Unmarshal([]byte(cached), &rules); err == nil {
+				return rules, nil
+			}
+		}
+	}
+
+	rules, err := uc.data.RoutePermissionRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	if redis.Client != nil {
+		if b, err := json.Marshal(rules); err == nil {
+			redis.SetWithExpire(routePermissionCacheKey, string(b), routePermissionCacheExpire)
+		}
+	}
+	return rules, nil
+}
+
+// invalidate 清除规则缓存
+func (uc *permissionUseCase) invalidate() {
+	if redis.Client != nil {
+		redis.Del(routePermissionCacheKey)
+	}
+}
+
+// fillRoutePermission 将请求填充到规则
+func fillRoutePermission(rule *po.RoutePermission, req *dto.RoutePermissionRequest) {
+	rule.Method = strings.ToUpper(req.Method)
+	if rule.Method == "" {
+		rule.Method = "*"
+	}
+	rule.Path = req.Path
+	rule.Description = req.Description
+
+	roles := make([]string, 0, len(req.Roles))
+	for _, r := range req.Roles {
+		if r = strings.TrimSpace(r); r != "" {
+			roles = append(roles, r)
+		}
+	}
+	rule.Roles = strings.Join(roles, ",")
+}
@@ -0,0 +1,61 @@
+package biz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// stubRoutePermissionRepo 只实现 List 的路由权限仓储
+type stubRoutePermissionRepo struct {
+	data.RoutePermissionRepo
+	rules []*po.RoutePermission
+	err   error
+}
+
+func (r *stubRoutePermissionRepo) List() ([]*po.RoutePermission, error) {
+	return r.rules, r.err
+}
+
+func TestPermissionAllowed(t *testing.T) {
+	rules := []*po.RoutePermission{
+		{Method: "*", Path: "/", Roles: "admin"},
+		{Method: "*", Path: "/analytics", Roles: "admin,editor"},
+		{Method: "DELETE", Path: "/analytics", Roles: "admin"},
+		{Method: "*", Path: "/stats/", Roles: "*"},
+	}
+
+	tests := []struct {
+		name   string
+		rules  []*po.RoutePermission
+		err    error
+		method string
+		path   string
+		role   string
+		want   bool
+	}{
+		{name: "root rule allows admin", rules: rules, method: "GET", path: "/articles", role: "admin", want: true},
+		{name: "root rule denies user", rules: rules, method: "GET", path: "/articles", role: "user", want: false},
+		{name: "longer prefix wins", rules: rules, method: "GET", path: "/analytics/pages/top", role: "editor", want: true},
+		{name: "method rule beats wildcard", rules: rules, method: "DELETE", path: "/analytics", role: "editor", want: false},
+		{name: "prefix must end at segment", rules: rules, method: "GET", path: "/analyticsx", role: "editor", want: false},
+		{name: "wildcard role", rules: rules, method: "GET", path: "/stats/overview", role: "user", want: true},
+		{name: "super admin bypasses rules", rules: nil, method: "GET", path: "/articles", role: "super_admin", want: true},
+		{name: "no matching rule denies", rules: rules[1:], method: "GET", path: "/articles", role: "admin", want: false},
+		{name: "no rules denies", rules: nil, method: "GET", path: "/articles", role: "admin", want: false},
+		{name: "load error denies", err: errors.New("db down"), method: "GET", path: "/articles", role: "admin", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &permissionUseCase{data: &data.Data{
+				RoutePermissionRepo: &stubRoutePermissionRepo{rules: tt.rules, err: tt.err},
+			}}
+			if got := uc.Allowed(tt.method, tt.path, tt.role); got != tt.want {
+				t.Errorf("Allowed(%s, %s, %s) = %v, want %v", tt.method, tt.path, tt.role, got, tt.want)
+			}
+		})
+	}
+}
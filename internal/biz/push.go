@@ -0,0 +1,326 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+	"github.com/ydcloud-dy/leaf-api/pkg/webpush"
+)
+
+// 浏览器推送默认值
+const (
+	defaultPushTTL         = 86400
+	defaultPushConcurrency = 10
+	defaultPushMaxFailures = 5
+	defaultPushExpireDays  = 180
+	pushBatchSize          = 200
+	pushSendTimeout        = 20 * time.Second
+	// pushedArticleKey 已推送过的文章，文章撤回后重新发布不再重复推送
+	pushedArticleKey = "push:article:%d"
+	pushedArticleTTL = 30 * 24 * time.Hour
+)
+
+var (
+	// ErrPushDisabled 未配置 VAPID 私钥
+	ErrPushDisabled = errors.New("未启用浏览器推送")
+	// ErrPushSubscriptionInvalid 推送地址不是 https 地址或密钥格式错误
+	ErrPushSubscriptionInvalid = errors.New("推送订阅无效")
+	// ErrPushArticleNotFound 文章不存在或未公开发布
+	ErrPushArticleNotFound = errors.New("文章不存在或未发布")
+	// ErrPushMessageEmpty 未指定文章也未填写标题
+	ErrPushMessageEmpty = errors.New("请指定文章或填写推送标题")
+)
+
+// pushSender 向单个订阅发送消息，由 webpush.Client 实现，测试时替换
+type pushSender interface {
+	PublicKey() string
+	Send(ctx context.Context, sub *webpush.Subscription, payload []byte, ttl int) error
+}
+
+var (
+	pushClientMu  sync.Mutex
+	pushClient    *webpush.Client
+	pushClientKey string
+)
+
+// newPushSender 按配置创建推送客户端，私钥不变时复用
+var newPushSender = func() (pushSender, error) {
+	cfg := config.AppConfig.WebPush
+	if cfg.PrivateKey == "" {
+		return nil, ErrPushDisabled
+	}
+
+	pushClientMu.Lock()
+	defer pushClientMu.Unlock()
+	key := cfg.PrivateKey + "\x00" + cfg.Subject
+	if pushClient == nil || pushClientKey != key {
+		client, err := webpush.New(cfg.PrivateKey, cfg.Subject)
+		if err != nil {
+			return nil, err
+		}
+		pushClient, pushClientKey = client, key
+	}
+	return pushClient, nil
+}
+
+// PushUseCase 浏览器推送业务用例接口
+type PushUseCase interface {
+	// VAPIDKey 获取浏览器订阅所需的公钥
+	VAPIDKey() *dto.PushVAPIDKeyResponse
+	// Subscribe 保存推送订阅，userID 为空表示匿名订阅
+	Subscribe(userID *uint, userAgent string, req *dto.PushSubscribeRequest) error
+	// Unsubscribe 取消推送订阅，订阅不存在时不报错
+	Unsubscribe(req *dto.PushUnsubscribeRequest) error
+	// Send 向全部订阅者推送一条消息，在后台发送
+	Send(ctx context.Context, req *dto.PushSendRequest) (*dto.PushSendResponse, error)
+	// PurgeExpired 清理失败次数过多或长期未更新的订阅
+	PurgeExpired() (int64, error)
+}
+
+// pushUseCase 浏览器推送业务用例实现
+type pushUseCase struct {
+	data *data.Data
+}
+
+// NewPushUseCase 创建浏览器推送业务用例
+func NewPushUseCase(d *data.Data) PushUseCase {
+	return &pushUseCase{data: d}
+}
+
+// VAPIDKey 获取公钥，私钥配置错误时视为未启用
+func (uc *pushUseCase) VAPIDKey() *dto.PushVAPIDKeyResponse {
+	sender, err := newPushSender()
+	if err != nil {
+		if !errors.Is(err, ErrPushDisabled) {
+			logger.Error("Invalid web push private key: ", err)
+		}
+		return &dto.PushVAPIDKeyResponse{}
+	}
+	return &dto.PushVAPIDKeyResponse{Enabled: true, PublicKey: sender.PublicKey()}
+}
+
+// Subscribe 保存推送订阅，同一浏览器重新订阅时更新密钥和所属用户
+func (uc *pushUseCase) Subscribe(userID *uint, userAgent string, req *dto.PushSubscribeRequest) error {
+	if config.AppConfig.WebPush.PrivateKey == "" {
+		return ErrPushDisabled
+	}
+	sub := &webpush.Subscription{Endpoint: req.Endpoint, P256dh: req.Keys.P256dh, Auth: req.Keys.Auth}
+	if err := sub.Validate(); err != nil {
+		return ErrPushSubscriptionInvalid
+	}
+
+	return uc.data.PushSubscriptionRepo.Save(&po.PushSubscription{
+		UserID:    userID,
+		Endpoint:  req.Endpoint,
+		P256dh:    req.Keys.P256dh,
+		Auth:      req.Keys.Auth,
+		UserAgent: truncateRunes(userAgent, 255),
+	})
+}
+
+// Unsubscribe 取消推送订阅
+func (uc *pushUseCase) Unsubscribe(req *dto.PushUnsubscribeRequest) error {
+	_, err := uc.data.PushSubscriptionRepo.DeleteByEndpoint(req.Endpoint)
+	return err
+}
+
+// Send 手动推送，指定文章时未填写的标题、正文和链接取自文章
+func (uc *pushUseCase) Send(ctx context.Context, req *dto.PushSendRequest) (*dto.PushSendResponse, error) {
+	if _, err := newPushSender(); err != nil {
+		return nil, err
+	}
+
+	msg := &dto.PushMessage{}
+	if req.ArticleID != 0 {
+		article, err := uc.data.ArticleRepo.FindByID(req.ArticleID)
+		if err != nil || article.Status != 1 || !articleVisible(article) {
+			return nil, ErrPushArticleNotFound
+		}
+		msg = articlePushMessage(article)
+	}
+	if req.Title != "" {
+		msg.Title = req.Title
+	}
+	if req.Body != "" {
+		msg.Body = req.Body
+	}
+	if req.URL != "" {
+		msg.URL = req.URL
+	}
+	if msg.Title == "" {
+		return nil, ErrPushMessageEmpty
+	}
+
+	count, err := uc.data.PushSubscriptionRepo.Count()
+	if err != nil {
+		return nil, err
+	}
+	go broadcastPush(context.WithoutCancel(ctx), uc.data, msg)
+	return &dto.PushSendResponse{Subscribers: count}, nil
+}
+
+// PurgeExpired 清理失效订阅
+func (uc *pushUseCase) PurgeExpired() (int64, error) {
+	cfg := config.AppConfig.WebPush
+	maxFailures := cfg.MaxFailures
+	if maxFailures <= 0 {
+		maxFailures = defaultPushMaxFailures
+	}
+	expireDays := cfg.ExpireDays
+	if expireDays <= 0 {
+		expireDays = defaultPushExpireDays
+	}
+	return uc.data.PushSubscriptionRepo.PurgeExpired(maxFailures, time.Now().AddDate(0, 0, -expireDays))
+}
+
+// pushArticles 文章发布后推送给全部订阅者，只推送公开可见的文章，每篇文章只推送一次
+func pushArticles(ctx context.Context, d *data.Data, articleIDs ...uint) {
+	cfg := config.AppConfig.WebPush
+	if !cfg.NotifyOnPublish || cfg.PrivateKey == "" || len(articleIDs) == 0 {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		articles, err := d.ArticleRepo.FindByIDs(articleIDs)
+		if err != nil {
+			logger.FromContext(ctx).Warn("Failed to load articles for web push: ", err)
+			return
+		}
+		for _, article := range articles {
+			if article.Status != 1 || !articleVisible(article) {
+				continue
+			}
+			if redis.Client != nil {
+				first, err := redis.SetNX(fmt.Sprintf(pushedArticleKey, article.ID), 1, pushedArticleTTL)
+				if err == nil && !first {
+					continue
+				}
+			}
+			broadcastPush(ctx, d, articlePushMessage(article))
+		}
+	}()
+}
+
+// articlePushMessage 文章的推送消息，加密文章不显示摘要
+func articlePushMessage(article *po.Article) *dto.PushMessage {
+	msg := &dto.PushMessage{
+		Title: article.Title,
+		URL:   frontendArticleURL(article),
+		Image: article.Cover,
+		Tag:   fmt.Sprintf("article-%d", article.ID),
+	}
+	if !articleLocked(article) {
+		msg.Body = truncateRunes(article.Summary, 120)
+	}
+	return msg
+}
+
+// broadcastPush 分批向全部订阅者推送消息
+// 推送服务返回 404/410 或订阅密钥无效时删除订阅，其他失败累计次数，达到上限后删除；ctx 携带触发推送的请求ID
+func broadcastPush(ctx context.Context, d *data.Data, msg *dto.PushMessage) {
+	log := logger.FromContext(ctx)
+	sender, err := newPushSender()
+	if err != nil {
+		log.Error("Web push is unavailable: ", err)
+		return
+	}
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		log.Error("Failed to encode push message: ", err)
+		return
+	}
+
+	cfg := config.AppConfig.WebPush
+	ttl := cfg.TTL
+	if ttl <= 0 {
+		ttl = defaultPushTTL
+	}
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultPushConcurrency
+	}
+	maxFailures := cfg.MaxFailures
+	if maxFailures <= 0 {
+		maxFailures = defaultPushMaxFailures
+	}
+
+	var (
+		mu                    sync.Mutex
+		sent, removed, failed int
+	)
+	deliver := func(sub *po.PushSubscription) {
+		ctx, cancel := context.WithTimeout(ctx, pushSendTimeout)
+		defer cancel()
+		err := sender.Send(ctx, &webpush.Subscription{Endpoint: sub.Endpoint, P256dh: sub.P256dh, Auth: sub.Auth}, payload, ttl)
+
+		remove := false
+		switch {
+		case err == nil:
+			if err := d.PushSubscriptionRepo.MarkSuccess(sub.ID); err != nil {
+				log.Warn("Failed to update push subscription: ", err)
+			}
+		case errors.Is(err, webpush.ErrGone), errors.Is(err, webpush.ErrInvalidSubscription):
+			remove = true
+		default:
+			log.Warn("Failed to push to subscription ", sub.ID, ": ", err)
+			count, markErr := d.PushSubscriptionRepo.MarkFailure(sub.ID)
+			remove = markErr == nil && count >= maxFailures
+		}
+		if remove {
+			if err := d.PushSubscriptionRepo.Delete(sub.ID); err != nil {
+				log.Warn("Failed to delete push subscription: ", err)
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case err == nil:
+			sent++
+		case remove:
+			removed++
+		default:
+			failed++
+		}
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var afterID uint
+	for {
+		subs, err := d.PushSubscriptionRepo.ListAfter(afterID, pushBatchSize)
+		if err != nil {
+			log.Error("Failed to list push subscriptions: ", err)
+			break
+		}
+		for _, sub := range subs {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(sub *po.PushSubscription) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				deliver(sub)
+			}(sub)
+		}
+		if len(subs) < pushBatchSize {
+			break
+		}
+		afterID = subs[len(subs)-1].ID
+	}
+	wg.Wait()
+
+	log.Info(fmt.Sprintf("Web push %q: %d sent, %d subscriptions removed, %d failed", msg.Title, sent, removed, failed))
+}
@@ -0,0 +1,179 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/webpush"
+)
+
+// stubPushSubscriptionRepo 内存中的推送订阅仓储
+type stubPushSubscriptionRepo struct {
+	data.PushSubscriptionRepo
+	mu   sync.Mutex
+	subs map[uint]*po.PushSubscription
+}
+
+func (r *stubPushSubscriptionRepo) ListAfter(afterID uint, limit int) ([]*po.PushSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []*po.PushSubscription
+	for id, sub := range r.subs {
+		if id > afterID {
+			copied := *sub
+			list = append(list, &copied)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (r *stubPushSubscriptionRepo) Count() (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return int64(len(r.subs)), nil
+}
+
+func (r *stubPushSubscriptionRepo) MarkSuccess(id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subs[id].FailCount = 0
+	return nil
+}
+
+func (r *stubPushSubscriptionRepo) MarkFailure(id uint) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subs[id].FailCount++
+	return r.subs[id].FailCount, nil
+}
+
+func (r *stubPushSubscriptionRepo) Delete(id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.subs, id)
+	return nil
+}
+
+// stubPushSender 按推送地址返回预设结果
+type stubPushSender struct {
+	mu      sync.Mutex
+	results map[string]error
+	sent    map[string][]byte
+}
+
+func (s *stubPushSender) PublicKey() string { return "public-key" }
+
+func (s *stubPushSender) Send(ctx context.Context, sub *webpush.Subscription, payload []byte, ttl int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent[sub.Endpoint] = payload
+	return s.results[sub.Endpoint]
+}
+
+func setupPushSender(t *testing.T, sender *stubPushSender) {
+	t.Helper()
+	previous, previousConfig := newPushSender, config.AppConfig.WebPush
+	newPushSender = func() (pushSender, error) { return sender, nil }
+	config.AppConfig.WebPush = config.WebPushConfig{PrivateKey: "test", MaxFailures: 2}
+	t.Cleanup(func() {
+		newPushSender = previous
+		config.AppConfig.WebPush = previousConfig
+	})
+}
+
+func TestBroadcastPushRemovesExpiredSubscriptions(t *testing.T) {
+	sender := &stubPushSender{
+		results: map[string]error{
+			"https://push.example.com/gone":  webpush.ErrGone,
+			"https://push.example.com/flaky": errors.New("503"),
+		},
+		sent: map[string][]byte{},
+	}
+	setupPushSender(t, sender)
+	repo := &stubPushSubscriptionRepo{subs: map[uint]*po.PushSubscription{
+		1: {ID: 1, Endpoint: "https://push.example.com/ok"},
+		2: {ID: 2, Endpoint: "https://push.example.com/gone"},
+		3: {ID: 3, Endpoint: "https://push.example.com/flaky"},
+	}}
+	d := &data.Data{PushSubscriptionRepo: repo}
+
+	broadcastPush(context.Background(), d, &dto.PushMessage{Title: "hello"})
+	if len(sender.sent) != 3 {
+		t.Fatalf("sent to %d subscriptions, want 3", len(sender.sent))
+	}
+	if _, ok := repo.subs[2]; ok {
+		t.Fatal("gone subscription was not removed")
+	}
+	if repo.subs[3].FailCount != 1 {
+		t.Fatalf("fail count = %d, want 1", repo.subs[3].FailCount)
+	}
+
+	// 连续失败达到 max_failures 后删除
+	broadcastPush(context.Background(), d, &dto.PushMessage{Title: "hello again"})
+	if _, ok := repo.subs[3]; ok {
+		t.Fatal("failing subscription was not removed after max_failures")
+	}
+	if _, ok := repo.subs[1]; !ok {
+		t.Fatal("working subscription was removed")
+	}
+}
+
+func TestPushSendUsesArticleDefaults(t *testing.T) {
+	setupPushSender(t, &stubPushSender{sent: map[string][]byte{}})
+	articles := map[uint]*po.Article{
+		1: {ID: 1, Title: "published", Summary: "summary", Status: 1, Visibility: po.VisibilityPublic},
+		2: {ID: 2, Title: "private", Status: 1, Visibility: po.VisibilityPrivate},
+		3: {ID: 3, Title: "draft", Status: 0},
+	}
+	uc := NewPushUseCase(&data.Data{
+		ArticleRepo:          &stubCounterArticleRepo{articles: articles},
+		PushSubscriptionRepo: &stubPushSubscriptionRepo{subs: map[uint]*po.PushSubscription{}},
+	})
+
+	for _, id := range []uint{2, 3, 99} {
+		if _, err := uc.Send(context.Background(), &dto.PushSendRequest{ArticleID: id}); !errors.Is(err, ErrPushArticleNotFound) {
+			t.Fatalf("Send(article %d) = %v, want ErrPushArticleNotFound", id, err)
+		}
+	}
+	if _, err := uc.Send(context.Background(), &dto.PushSendRequest{Body: "no title"}); !errors.Is(err, ErrPushMessageEmpty) {
+		t.Fatalf("Send without title = %v, want ErrPushMessageEmpty", err)
+	}
+
+	msg := articlePushMessage(articles[1])
+	if msg.Title != "published" || msg.Body != "summary" || msg.Tag != "article-1" {
+		t.Fatalf("message = %+v", msg)
+	}
+	articles[1].Visibility = po.VisibilityPassword
+	if msg := articlePushMessage(articles[1]); msg.Body != "" {
+		t.Fatalf("locked article message body = %q, want empty", msg.Body)
+	}
+}
+
+func TestPushSubscribeValidatesSubscription(t *testing.T) {
+	setupPushSender(t, &stubPushSender{sent: map[string][]byte{}})
+	uc := NewPushUseCase(&data.Data{})
+
+	req := &dto.PushSubscribeRequest{
+		Endpoint: "http://push.example.com/abc",
+		Keys:     dto.PushSubscriptionKeys{P256dh: "invalid", Auth: "invalid"},
+	}
+	if err := uc.Subscribe(nil, "", req); !errors.Is(err, ErrPushSubscriptionInvalid) {
+		t.Fatalf("Subscribe = %v, want ErrPushSubscriptionInvalid", err)
+	}
+
+	config.AppConfig.WebPush.PrivateKey = ""
+	if err := uc.Subscribe(nil, "", req); !errors.Is(err, ErrPushDisabled) {
+		t.Fatalf("Subscribe = %v, want ErrPushDisabled", err)
+	}
+}
@@ -0,0 +1,352 @@
+package biz
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+const (
+	// 角色权限缓存 Key
+	rolePermissionCacheKey = "permission:roles"
+	// 角色权限缓存时间
+	rolePermissionCacheExpire = 10 * time.Minute
+)
+
+var (
+	// ErrRoleNotFound 角色不存在
+	ErrRoleNotFound = errors.New("角色不存在")
+	// ErrRoleExists 角色已存在
+	ErrRoleExists = errors.New("角色已存在")
+	// ErrRoleNameInvalid 角色名称格式错误
+	ErrRoleNameInvalid = errors.New("角色名称只能包含小写字母、数字和下划线，且以字母开头")
+	// ErrRoleBuiltin 内置角色不能删除
+	ErrRoleBuiltin = errors.New("内置角色不能删除")
+	// ErrRoleInUse 角色仍有用户使用
+	ErrRoleInUse = errors.New("该角色仍有用户使用，请先为这些用户分配其他角色")
+	// ErrPermissionInvalid 权限点不存在
+	ErrPermissionInvalid = errors.New("权限点不存在")
+	// ErrLastAdmin 不能取消最后一个管理员
+	ErrLastAdmin = errors.New("不能取消最后一个管理员的角色")
+)
+
+// roleNamePattern 角色名称
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,19}$`)
+
+// permissionCatalog 系统支持的权限点，授权时还可以使用 * 和 资源:* 通配
+var permissionCatalog = []dto.PermissionInfo{
+	{Name: "article:read", Description: "查看文章"},
+	{Name: "article:create", Description: "创建和导入文章"},
+	{Name: "article:update", Description: "编辑文章"},
+	{Name: "article:publish", Description: "发布、下线和置顶文章"},
+	{Name: "article:delete", Description: "删除文章"},
+	{Name: "comment:moderate", Description: "审核和管理评论"},
+	{Name: "taxonomy:manage", Description: "管理分类、标签、章节和系列"},
+	{Name: "file:upload", Description: "上传文件和使用媒体库"},
+	{Name: "file:manage", Description: "管理文件存储"},
+	{Name: "user:manage", Description: "管理用户"},
+	{Name: "setting:manage", Description: "修改系统设置"},
+	{Name: "site:manage", Description: "管理友链、说说、独立页面、导航菜单、首页横幅、公告等站点内容"},
+	{Name: "stats:read", Description: "查看统计数据"},
+}
+
+// RBACUseCase 角色权限业务用例接口
+type RBACUseCase interface {
+	// ListPermissions 查询系统支持的权限点
+	ListPermissions() []dto.PermissionInfo
+	// ListRoles 查询所有角色及其权限
+	ListRoles() ([]*dto.RoleInfo, error)
+	// CreateRole 创建角色
+	CreateRole(req *dto.RoleRequest) (*dto.RoleInfo, error)
+	// UpdateRole 更新角色信息和权限
+	UpdateRole(name string, req *dto.RoleRequest) (*dto.RoleInfo, error)
+	// DeleteRole 删除角色
+	DeleteRole(name string) error
+	// AssignRole 为用户分配角色
+	AssignRole(userID uint, role string) error
+	// HasPermission 判断角色是否拥有权限
+	HasPermission(role, permission string) bool
+}
+
+// rbacUseCase 角色权限业务用例实现
+type rbacUseCase struct {
+	data *data.Data
+}
+
+// NewRBACUseCase 创建角色权限业务用例
+func NewRBACUseCase(d *data.Data) RBACUseCase {
+	return &rbacUseCase{data: d}
+}
+
+// ConsoleRole 角色是否可以登录管理后台，前台用户 user 以外的角色都可以登录，能执行的操作由权限决定
+func ConsoleRole(role string) bool {
+	return role != "" && role != "user"
+}
+
+// ListPermissions 查询系统支持的权限点
+func (uc *rbacUseCase) ListPermissions() []dto.PermissionInfo {
+	return permissionCatalog
+}
+
+// ListRoles 查询所有角色及其权限
+func (uc *rbacUseCase) ListRoles() ([]*dto.RoleInfo, error) {
+	roles, err := uc.data.RoleRepo.List()
+	if err != nil {
+		return nil, errors.New("查询角色失败")
+	}
+	grants, err := uc.grants()
+	if err != nil {
+		return nil, errors.New("查询角色权限失败")
+	}
+
+	list := make([]*dto.RoleInfo, 0, len(roles))
+	for _, role := range roles {
+		list = append(list, toRoleInfo(role, grants[role.Name]))
+	}
+	return list, nil
+}
+
+// CreateRole 创建角色
+func (uc *rbacUseCase) CreateRole(req *dto.RoleRequest) (*dto.RoleInfo, error) {
+	if !roleNamePattern.MatchString(req.Name) {
+		return nil, ErrRoleNameInvalid
+	}
+	permissions, err := normalizePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.data.RoleRepo.FindByName(req.Name); err == nil {
+		return nil, ErrRoleExists
+	}
+
+	role := &po.Role{
+		Name:        req.Name,
+		DisplayName: req.DisplayName,
+		Description: req.Description,
+	}
+	if err := uc.data.RoleRepo.Create(role, permissions); err != nil {
+		logger.Error("Failed to create role: ", err)
+		return nil, errors.New("创建角色失败")
+	}
+
+	uc.invalidate()
+	return toRoleInfo(role, permissions), nil
+}
+
+// UpdateRole 更新角色信息和权限，修改后立即生效
+func (uc *rbacUseCase) UpdateRole(name string, req *dto.RoleRequest) (*dto.RoleInfo, error) {
+	role, err := uc.data.RoleRepo.FindByName(name)
+	if err != nil {
+		return nil, ErrRoleNotFound
+	}
+	// 未传入 permissions 时保留原有权限
+	var permissions []string
+	if req.Permissions != nil {
+		if permissions, err = normalizePermissions(req.Permissions); err != nil {
+			return nil, err
+		}
+	}
+
+	role.DisplayName = req.DisplayName
+	role.Description = req.Description
+	if err := uc.data.RoleRepo.Update(role, permissions); err != nil {
+		logger.Error("Failed to update role: ", err)
+		return nil, errors.New("更新角色失败")
+	}
+
+	uc.invalidate()
+	if permissions == nil {
+		grants, err := uc.grants()
+		if err != nil {
+			return nil, errors.New("查询角色权限失败")
+		}
+		permissions = grants[role.Name]
+	}
+	return toRoleInfo(role, permissions), nil
+}
+
+// DeleteRole 删除角色，内置角色和仍有用户使用的角色不能删除
+func (uc *rbacUseCase) DeleteRole(name string) error {
+	role, err := uc.data.RoleRepo.FindByName(name)
+	if err != nil {
+		return ErrRoleNotFound
+	}
+	if role.Builtin {
+		return ErrRoleBuiltin
+	}
+	count, err := uc.data.RoleRepo.CountUsers(name)
+	if err != nil {
+		return errors.New("删除角色失败")
+	}
+	if count > 0 {
+		return ErrRoleInUse
+	}
+
+	if err := uc.data.RoleRepo.Delete(role); err != nil {
+		return errors.New("删除角色失败")
+	}
+
+	uc.invalidate()
+	return nil
+}
+
+// AssignRole 为用户分配角色
+// 令牌中的角色在签发时确定，分配后吊销用户的全部会话，重新登录后按新角色授权
+func (uc *rbacUseCase) AssignRole(userID uint, name string) error {
+	if _, err := uc.data.RoleRepo.FindByName(name); err != nil {
+		return ErrRoleNotFound
+	}
+	user, err := uc.data.UserRepo.FindByID(userID)
+	if err != nil {
+		return errors.New("用户不存在")
+	}
+	if user.Role == name {
+		return nil
+	}
+
+	// 取消管理员角色时，检查是否是最后一个管理员
+	if isAdminRole(user.Role) && !isAdminRole(name) {
+		var adminCount int64
+		for _, role := range []string{"admin", "super_admin"} {
+			count, err := uc.data.RoleRepo.CountUsers(role)
+			if err != nil {
+				return errors.New("分配角色失败")
+			}
+			adminCount += count
+		}
+		if adminCount <= 1 {
+			return ErrLastAdmin
+		}
+	}
+
+	user.Role = name
+	if err := uc.data.UserRepo.Update(user); err != nil {
+		return errors.New("分配角色失败")
+	}
+	revokeUserSessions(user.ID)
+	return nil
+}
+
+// HasPermission 判断角色是否拥有权限
+func (uc *rbacUseCase) HasPermission(role, permission string) bool {
+	// 超级管理员拥有全部权限，避免误配置后无法恢复
+	if role == "super_admin" {
+		return true
+	}
+
+	grants, err := uc.grants()
+	if err != nil {
+		// 权限加载失败时无法判断，拒绝访问
+		logger.Error("Failed to load role permissions: ", err)
+		return false
+	}
+	for _, grant := range grants[role] {
+		if permissionMatches(grant, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// grants 获取各角色的权限（优先读取 Redis 缓存）
+func (uc *rbacUseCase) grants() (map[string][]string, error) {
+	if redis.Client != nil {
+		if cached, err := redis.Get(rolePermissionCacheKey); err == nil {
+			var grants map[string][]string
+			if err := json.Unmarshal([]byte(cached), &grants); err == nil {
+				return grants, nil
+			}
+		}
+	}
+
+	rows, err := uc.data.RoleRepo.ListPermissions()
+	if err != nil {
+		return nil, err
+	}
+	grants := make(map[string][]string)
+	for _, row := range rows {
+		grants[row.Role] = append(grants[row.Role], row.Permission)
+	}
+
+	if redis.Client != nil {
+		if b, err := json.Marshal(grants); err == nil {
+			redis.SetWithExpire(rolePermissionCacheKey, string(b), rolePermissionCacheExpire)
+		}
+	}
+	return grants, nil
+}
+
+// invalidate 清除角色权限缓存
+func (uc *rbacUseCase) invalidate() {
+	if redis.Client != nil {
+		redis.Del(rolePermissionCacheKey)
+	}
+}
+
+// permissionMatches 授权是否包含权限点，* 匹配全部，article:* 匹配 article 的全部权限点
+func permissionMatches(grant, permission string) bool {
+	if grant == "*" || grant == permission {
+		return true
+	}
+	resource, ok := strings.CutSuffix(grant, ":*")
+	return ok && strings.HasPrefix(permission, resource+":")
+}
+
+// normalizePermissions 校验并去重权限点
+func normalizePermissions(permissions []string) ([]string, error) {
+	list := make([]string, 0, len(permissions))
+	seen := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		permission = strings.ToLower(strings.TrimSpace(permission))
+		if seen[permission] {
+			continue
+		}
+		if !knownPermission(permission) {
+			return nil, ErrPermissionInvalid
+		}
+		seen[permission] = true
+		list = append(list, permission)
+	}
+	return list, nil
+}
+
+// knownPermission 权限点是否存在，通配符需至少匹配一个权限点
+func knownPermission(permission string) bool {
+	if permission == "*" {
+		return true
+	}
+	for _, p := range permissionCatalog {
+		if permissionMatches(permission, p.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAdminRole 是否为管理员角色
+func isAdminRole(role string) bool {
+	return role == "admin" || role == "super_admin"
+}
+
+// toRoleInfo 转换为角色信息
+func toRoleInfo(role *po.Role, permissions []string) *dto.RoleInfo {
+	if permissions == nil {
+		permissions = []string{}
+	}
+	return &dto.RoleInfo{
+		ID:          role.ID,
+		Name:        role.Name,
+		DisplayName: role.DisplayName,
+		Description: role.Description,
+		Builtin:     role.Builtin,
+		Permissions: permissions,
+	}
+}
@@ -0,0 +1,232 @@
+package biz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// stubRoleRepo 内存中的角色仓储，用户数从用户仓储中统计
+type stubRoleRepo struct {
+	data.RoleRepo
+	roles  map[string]*po.Role
+	grants []*po.RolePermission
+	users  *stubUserRepo
+}
+
+func (r *stubRoleRepo) FindByName(name string) (*po.Role, error) {
+	if role, ok := r.roles[name]; ok {
+		copied := *role
+		return &copied, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *stubRoleRepo) Update(role *po.Role, permissions []string) error {
+	r.roles[role.Name] = role
+	if permissions == nil {
+		return nil
+	}
+	kept := r.grants[:0]
+	for _, grant := range r.grants {
+		if grant.Role != role.Name {
+			kept = append(kept, grant)
+		}
+	}
+	r.grants = kept
+	for _, permission := range permissions {
+		r.grants = append(r.grants, &po.RolePermission{Role: role.Name, Permission: permission})
+	}
+	return nil
+}
+
+func (r *stubRoleRepo) ListPermissions() ([]*po.RolePermission, error) {
+	return r.grants, nil
+}
+
+func (r *stubRoleRepo) CountUsers(name string) (int64, error) {
+	var count int64
+	for _, user := range r.users.users {
+		if user.Role == name {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// newRBACUseCase 创建包含默认角色的角色权限用例
+func newRBACUseCase(users map[uint]*po.User) (*rbacUseCase, *stubRoleRepo) {
+	userRepo := &stubUserRepo{users: users}
+	roles := &stubRoleRepo{
+		roles: map[string]*po.Role{
+			"super_admin": {Name: "super_admin", Builtin: true},
+			"admin":       {Name: "admin", Builtin: true},
+			"editor":      {Name: "editor"},
+			"author":      {Name: "author"},
+			"user":        {Name: "user", Builtin: true},
+		},
+		grants: []*po.RolePermission{
+			{Role: "admin", Permission: "*"},
+			{Role: "editor", Permission: "article:*"},
+			{Role: "editor", Permission: "comment:moderate"},
+			{Role: "author", Permission: "article:create"},
+			{Role: "author", Permission: "article:update"},
+		},
+		users: userRepo,
+	}
+	return &rbacUseCase{data: &data.Data{UserRepo: userRepo, RoleRepo: roles}}, roles
+}
+
+func TestHasPermission(t *testing.T) {
+	uc, _ := newRBACUseCase(map[uint]*po.User{})
+
+	tests := []struct {
+		role       string
+		permission string
+		want       bool
+	}{
+		{role: "super_admin", permission: "user:manage", want: true},
+		{role: "admin", permission: "setting:manage", want: true},
+		{role: "editor", permission: "article:publish", want: true},
+		{role: "editor", permission: "comment:moderate", want: true},
+		{role: "editor", permission: "user:manage"},
+		{role: "author", permission: "article:update", want: true},
+		{role: "author", permission: "article:publish"},
+		{role: "user", permission: "article:read"},
+		{role: "", permission: "article:read"},
+		{role: "unknown", permission: "article:read"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role+" "+tt.permission, func(t *testing.T) {
+			if got := uc.HasPermission(tt.role, tt.permission); got != tt.want {
+				t.Errorf("HasPermission(%q, %q) = %v, want %v", tt.role, tt.permission, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermissionMatches(t *testing.T) {
+	tests := []struct {
+		grant      string
+		permission string
+		want       bool
+	}{
+		{grant: "*", permission: "article:read", want: true},
+		{grant: "article:read", permission: "article:read", want: true},
+		{grant: "article:*", permission: "article:delete", want: true},
+		{grant: "article:*", permission: "articles:read"},
+		{grant: "article:read", permission: "article:delete"},
+		{grant: "comment:*", permission: "article:read"},
+	}
+
+	for _, tt := range tests {
+		if got := permissionMatches(tt.grant, tt.permission); got != tt.want {
+			t.Errorf("permissionMatches(%q, %q) = %v, want %v", tt.grant, tt.permission, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateRoleValidatesPermissions(t *testing.T) {
+	setupTokenRedis(t)
+	uc, _ := newRBACUseCase(map[uint]*po.User{})
+
+	if _, err := uc.UpdateRole("author", &dto.RoleRequest{DisplayName: "作者", Permissions: []string{"article:fly"}}); !errors.Is(err, ErrPermissionInvalid) {
+		t.Fatalf("unknown permission: err = %v, want ErrPermissionInvalid", err)
+	}
+	if _, err := uc.UpdateRole("author", &dto.RoleRequest{DisplayName: "作者", Permissions: []string{"foo:*"}}); !errors.Is(err, ErrPermissionInvalid) {
+		t.Fatalf("unknown wildcard: err = %v, want ErrPermissionInvalid", err)
+	}
+
+	// 更新后缓存失效，新权限立即生效
+	if uc.HasPermission("author", "article:publish") {
+		t.Fatal("author should not publish before update")
+	}
+	info, err := uc.UpdateRole("author", &dto.RoleRequest{DisplayName: "作者", Permissions: []string{"article:*", "Article:* "}})
+	if err != nil {
+		t.Fatalf("UpdateRole: %v", err)
+	}
+	if len(info.Permissions) != 1 || info.Permissions[0] != "article:*" {
+		t.Errorf("permissions = %v, want [article:*]", info.Permissions)
+	}
+	if !uc.HasPermission("author", "article:publish") {
+		t.Error("author should publish after update")
+	}
+
+	// 不传权限时保留原有权限
+	info, err = uc.UpdateRole("author", &dto.RoleRequest{DisplayName: "撰稿人"})
+	if err != nil {
+		t.Fatalf("UpdateRole without permissions: %v", err)
+	}
+	if len(info.Permissions) != 1 || info.DisplayName != "撰稿人" {
+		t.Errorf("role = %+v, want permissions kept", info)
+	}
+}
+
+func TestAssignRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		users    map[uint]*po.User
+		userID   uint
+		role     string
+		wantErr  error
+		wantRole string
+	}{
+		{
+			name:     "promote user to author",
+			users:    map[uint]*po.User{1: {ID: 1, Role: "admin"}, 2: {ID: 2, Role: "user"}},
+			userID:   2,
+			role:     "author",
+			wantRole: "author",
+		},
+		{
+			name:     "demote one of two admins",
+			users:    map[uint]*po.User{1: {ID: 1, Role: "admin"}, 2: {ID: 2, Role: "super_admin"}},
+			userID:   1,
+			role:     "editor",
+			wantRole: "editor",
+		},
+		{
+			name:     "last admin cannot be demoted",
+			users:    map[uint]*po.User{1: {ID: 1, Role: "admin"}, 2: {ID: 2, Role: "editor"}},
+			userID:   1,
+			role:     "editor",
+			wantErr:  ErrLastAdmin,
+			wantRole: "admin",
+		},
+		{
+			name:     "unknown role",
+			users:    map[uint]*po.User{1: {ID: 1, Role: "admin"}, 2: {ID: 2, Role: "user"}},
+			userID:   2,
+			role:     "owner",
+			wantErr:  ErrRoleNotFound,
+			wantRole: "user",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTokenRedis(t)
+			uc, _ := newRBACUseCase(tt.users)
+
+			err := uc.AssignRole(tt.userID, tt.role)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AssignRole err = %v, want %v", err, tt.wantErr)
+			}
+			if got := tt.users[tt.userID].Role; got != tt.wantRole {
+				t.Errorf("role = %q, want %q", got, tt.wantRole)
+			}
+		})
+	}
+}
+
+func TestConsoleRole(t *testing.T) {
+	for role, want := range map[string]bool{"super_admin": true, "admin": true, "editor": true, "author": true, "user": false, "": false} {
+		if got := ConsoleRole(role); got != want {
+			t.Errorf("ConsoleRole(%q) = %v, want %v", role, got, want)
+		}
+	}
+}
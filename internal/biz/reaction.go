@@ -0,0 +1,169 @@
+package biz
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+)
+
+// defaultReactionEmojis 未配置 reaction.emojis 时可用的表情
+var defaultReactionEmojis = []string{"👍", "❤️", "🎉", "😄", "😕", "👀", "🚀"}
+
+var (
+	// ErrReactionTargetNotFound 回应的文章或评论不存在
+	ErrReactionTargetNotFound = errors.New("回应的内容不存在")
+	// ErrReactionInvalid 不支持的表情
+	ErrReactionInvalid = errors.New("不支持的表情")
+)
+
+// ReactionUseCase 表情回应业务用例接口
+type ReactionUseCase interface {
+	// Toggle 切换对文章或评论的表情回应，登录用户按用户去重，游客按 IP 去重
+	Toggle(targetType string, targetID, userID uint, client *dto.ClientInfo, req *dto.ReactionRequest) (*dto.ReactionToggleResponse, error)
+}
+
+// reactionUseCase 表情回应业务用例实现
+type reactionUseCase struct {
+	data *data.Data
+}
+
+// NewReactionUseCase 创建表情回应业务用例
+func NewReactionUseCase(d *data.Data) ReactionUseCase {
+	return &reactionUseCase{data: d}
+}
+
+// Toggle 切换表情回应，返回对象最新的回应统计
+func (uc *reactionUseCase) Toggle(targetType string, targetID, userID uint, client *dto.ClientInfo, req *dto.ReactionRequest) (*dto.ReactionToggleResponse, error) {
+	if reactionIndex(req.Emoji) < 0 {
+		return nil, ErrReactionInvalid
+	}
+	if !uc.targetExists(targetType, targetID) {
+		return nil, ErrReactionTargetNotFound
+	}
+	reactor := reactorKey(userID, client)
+	if reactor == "" {
+		return nil, ErrReactionInvalid
+	}
+
+	reaction := &po.Reaction{
+		TargetType: targetType,
+		TargetID:   targetID,
+		Emoji:      req.Emoji,
+		Reactor:    reactor,
+	}
+	if userID > 0 {
+		reaction.UserID = &userID
+	}
+	reacted, err := uc.data.ReactionRepo.Toggle(reaction)
+	if err != nil {
+		return nil, fmt.Errorf("切换表情回应失败: %w", err)
+	}
+
+	return &dto.ReactionToggleResponse{
+		Emoji:     req.Emoji,
+		Reacted:   reacted,
+		Reactions: reactionSummaries(uc.data, targetType, []uint{targetID}, reactor)[targetID],
+	}, nil
+}
+
+// targetExists 回应对象是否存在：文章需已发布且非私密，评论需审核通过
+func (uc *reactionUseCase) targetExists(targetType string, targetID uint) bool {
+	switch targetType {
+	case po.ReactionTargetArticle:
+		article, err := uc.data.ArticleRepo.FindByID(targetID)
+		return err == nil && article.Status == 1 && articleVisible(article)
+	case po.ReactionTargetComment:
+		comment, err := uc.data.CommentRepo.FindByID(targetID)
+		return err == nil && comment.Status == po.CommentStatusApproved
+	}
+	return false
+}
+
+// reactionSummaries 批量统计对象的表情回应，按配置的表情顺序排列，reactor 不为空时标记其回应过的表情
+// 查询失败时只记录日志，返回空统计，不影响文章和评论的正常返回
+func reactionSummaries(d *data.Data, targetType string, targetIDs []uint, reactor string) map[uint][]dto.ReactionCount {
+	summaries := make(map[uint][]dto.ReactionCount, len(targetIDs))
+	for _, id := range targetIDs {
+		summaries[id] = []dto.ReactionCount{}
+	}
+	if len(targetIDs) == 0 {
+		return summaries
+	}
+
+	counts, err := d.ReactionRepo.Counts(targetType, targetIDs)
+	if err != nil {
+		logger.Warn("Failed to count reactions: ", err)
+		return summaries
+	}
+	reacted, err := d.ReactionRepo.Reacted(targetType, targetIDs, reactor)
+	if err != nil {
+		logger.Warn("Failed to load reactions: ", err)
+	}
+
+	for _, count := range counts {
+		summary := dto.ReactionCount{Emoji: count.Emoji, Count: count.Count}
+		for _, emoji := range reacted[count.TargetID] {
+			if emoji == count.Emoji {
+				summary.Reacted = true
+				break
+			}
+		}
+		summaries[count.TargetID] = append(summaries[count.TargetID], summary)
+	}
+
+	for _, summary := range summaries {
+		sortReactions(summary)
+	}
+	return summaries
+}
+
+// sortReactions 按表情在配置中的顺序排序，已从配置中移除的表情排在最后
+func sortReactions(reactions []dto.ReactionCount) {
+	order := func(emoji string) int {
+		if i := reactionIndex(emoji); i >= 0 {
+			return i
+		}
+		return len(reactionEmojis())
+	}
+	sort.SliceStable(reactions, func(i, j int) bool {
+		return order(reactions[i].Emoji) < order(reactions[j].Emoji)
+	})
+}
+
+// reactionEmojis 可用的表情
+func reactionEmojis() []string {
+	if emojis := config.AppConfig.Reaction.Emojis; len(emojis) > 0 {
+		return emojis
+	}
+	return defaultReactionEmojis
+}
+
+// reactionIndex 表情在可用表情中的位置，不可用时返回 -1
+func reactionIndex(emoji string) int {
+	for i, allowed := range reactionEmojis() {
+		if allowed == emoji {
+			return i
+		}
+	}
+	return -1
+}
+
+// reactorKey 回应者标识：登录用户使用用户ID，游客使用 IP 的摘要
+func reactorKey(userID uint, client *dto.ClientInfo) string {
+	if userID > 0 {
+		return fmt.Sprintf("u%d", userID)
+	}
+	if client == nil || client.IP == "" {
+		return ""
+	}
+	sum := sha1.Sum([]byte(client.IP))
+	return "g" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,239 @@
+package biz
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+)
+
+var (
+	// ErrBookNotFound 笔记标签不存在
+	ErrBookNotFound = errors.New("笔记不存在")
+	// ErrProgressArticleNotFound 文章不存在、未发布或不属于任何笔记章节
+	ErrProgressArticleNotFound = errors.New("文章不存在或不属于任何笔记")
+)
+
+// ReadingProgressUseCase 笔记阅读进度业务用例接口
+type ReadingProgressUseCase interface {
+	// Update 上报文章阅读进度
+	Update(userID, articleID uint, req *dto.UpdateReadingProgressRequest) (*dto.ArticleProgress, error)
+	// GetBook 查询某本书的阅读进度，包括每篇读过的文章和继续阅读的位置
+	GetBook(userID uint, tagName string) (*dto.BookProgressResponse, error)
+	// ListBooks 查询读过的书及完成进度，按最近阅读时间倒序
+	ListBooks(userID uint) ([]*dto.BookProgressResponse, error)
+	// ResetBook 清除某本书的阅读进度
+	ResetBook(userID uint, tagName string) error
+}
+
+// readingProgressUseCase 笔记阅读进度业务用例实现
+type readingProgressUseCase struct {
+	data *data.Data
+}
+
+// NewReadingProgressUseCase 创建笔记阅读进度业务用例
+func NewReadingProgressUseCase(d *data.Data) ReadingProgressUseCase {
+	return &readingProgressUseCase{data: d}
+}
+
+// Update 上报文章阅读进度，只记录笔记章节下已发布的公开文章
+// 已读完的文章再次打开时不会变回未读，除非显式传 completed=false
+func (uc *readingProgressUseCase) Update(userID, articleID uint, req *dto.UpdateReadingProgressRequest) (*dto.ArticleProgress, error) {
+	article, err := uc.data.ArticleRepo.FindByID(articleID)
+	if err != nil || article.Status != 1 || !articleVisible(article) || article.ChapterID == nil {
+		return nil, ErrProgressArticleNotFound
+	}
+	chapter, err := uc.data.ReadingProgressRepo.FindChapter(*article.ChapterID)
+	if err != nil {
+		return nil, ErrProgressArticleNotFound
+	}
+
+	now := time.Now()
+	progress, err := uc.data.ReadingProgressRepo.Find(userID, articleID)
+	if err != nil {
+		progress = &po.ReadingProgress{UserID: userID, ArticleID: articleID}
+	}
+	progress.TagID = chapter.TagID
+	progress.ReadAt = now
+	if req.Percent != nil {
+		progress.Percent = *req.Percent
+	}
+
+	completed := progress.Completed || progress.Percent >= 100
+	if req.Completed != nil {
+		completed = *req.Completed
+	}
+	switch {
+	case completed && !progress.Completed:
+		progress.CompletedAt = &now
+	case !completed:
+		progress.CompletedAt = nil
+	}
+	progress.Completed = completed
+
+	if err := uc.data.ReadingProgressRepo.Save(progress); err != nil {
+		return nil, errors.New("保存阅读进度失败")
+	}
+	return articleProgress(progress), nil
+}
+
+// GetBook 查询某本书的阅读进度
+func (uc *readingProgressUseCase) GetBook(userID uint, tagName string) (*dto.BookProgressResponse, error) {
+	tag, err := uc.data.TagRepo.FindByName(tagName)
+	if err != nil {
+		return nil, ErrBookNotFound
+	}
+	return uc.bookProgress(userID, tag, true)
+}
+
+// ListBooks 查询读过的书及完成进度，不包含每篇文章的进度
+func (uc *readingProgressUseCase) ListBooks(userID uint) ([]*dto.BookProgressResponse, error) {
+	tagIDs, err := uc.data.ReadingProgressRepo.ListTagIDs(userID)
+	if err != nil {
+		return nil, errors.New("查询阅读进度失败")
+	}
+
+	books := make([]*dto.BookProgressResponse, 0, len(tagIDs))
+	for _, tagID := range tagIDs {
+		tag, err := uc.data.TagRepo.FindByID(tagID)
+		if err != nil {
+			continue // 标签已删除
+		}
+		book, err := uc.bookProgress(userID, tag, false)
+		if err != nil {
+			return nil, err
+		}
+		books = append(books, book)
+	}
+	return books, nil
+}
+
+// ResetBook 清除某本书的阅读进度
+func (uc *readingProgressUseCase) ResetBook(userID uint, tagName string) error {
+	tag, err := uc.data.TagRepo.FindByName(tagName)
+	if err != nil {
+		return ErrBookNotFound
+	}
+	if err := uc.data.ReadingProgressRepo.DeleteByTag(userID, tag.ID); err != nil {
+		return errors.New("清除阅读进度失败")
+	}
+	return nil
+}
+
+// bookProgress 统计书的完成进度：只计算当前仍在书中的已发布文章，文章移出章节或下线后不再计入
+// 继续阅读的位置优先取最近读过但未读完的文章，否则取目录顺序中第一篇未读完的文章
+func (uc *readingProgressUseCase) bookProgress(userID uint, tag *po.Tag, withArticles bool) (*dto.BookProgressResponse, error) {
+	articles, err := uc.bookArticles(tag.ID)
+	if err != nil {
+		logger.Error("Failed to load book articles: ", err)
+		return nil, errors.New("查询阅读进度失败")
+	}
+	records, err := uc.data.ReadingProgressRepo.ListByTag(userID, tag.ID)
+	if err != nil {
+		return nil, errors.New("查询阅读进度失败")
+	}
+
+	inBook := make(map[uint]*po.Article, len(articles))
+	for _, article := range articles {
+		inBook[article.ID] = article
+	}
+
+	book := &dto.BookProgressResponse{TagID: tag.ID, TagName: tag.Name, Total: len(articles)}
+	completed := make(map[uint]bool, len(records))
+	for _, record := range records {
+		if _, ok := inBook[record.ArticleID]; !ok {
+			continue
+		}
+		if book.LastReadAt == nil {
+			readAt := record.ReadAt
+			book.LastReadAt = &readAt
+		}
+		if record.Completed {
+			completed[record.ArticleID] = true
+		} else if book.ResumeArticleID == 0 {
+			book.ResumeArticleID = record.ArticleID
+		}
+		if withArticles {
+			book.Articles = append(book.Articles, articleProgress(record))
+		}
+	}
+	book.CompletedCount = len(completed)
+	if book.Total > 0 {
+		book.Percent = book.CompletedCount * 100 / book.Total
+	}
+
+	if book.ResumeArticleID == 0 {
+		for _, article := range articles {
+			if !completed[article.ID] {
+				book.ResumeArticleID = article.ID
+				break
+			}
+		}
+	}
+	if article, ok := inBook[book.ResumeArticleID]; ok {
+		book.ResumeArticleTitle = article.Title
+	}
+	if withArticles && book.Articles == nil {
+		book.Articles = []*dto.ArticleProgress{}
+	}
+	return book, nil
+}
+
+// bookArticles 按目录顺序列出书中的文章：章节按层级深度优先，章节内按创建时间，与导出电子书的顺序一致
+func (uc *readingProgressUseCase) bookArticles(tagID uint) ([]*po.Article, error) {
+	chapters, err := uc.data.ReadingProgressRepo.BookChapters(tagID)
+	if err != nil {
+		return nil, err
+	}
+	articles, err := uc.data.ReadingProgressRepo.BookArticles(tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[uint]bool, len(chapters))
+	for _, chapter := range chapters {
+		known[chapter.ID] = true
+	}
+	children := make(map[uint][]*po.Chapter) // 上级章节ID -> 子章节，0 表示顶级
+	for _, chapter := range chapters {
+		parentID := uint(0)
+		if chapter.ParentID != nil && known[*chapter.ParentID] {
+			parentID = *chapter.ParentID
+		}
+		children[parentID] = append(children[parentID], chapter)
+	}
+	byChapter := make(map[uint][]*po.Article)
+	for _, article := range articles {
+		byChapter[*article.ChapterID] = append(byChapter[*article.ChapterID], article)
+	}
+
+	ordered := make([]*po.Article, 0, len(articles))
+	visited := make(map[uint]bool, len(chapters))
+	var walk func(parentID uint)
+	walk = func(parentID uint) {
+		for _, chapter := range children[parentID] {
+			if visited[chapter.ID] {
+				continue // 章节的上下级关系成环时避免死循环
+			}
+			visited[chapter.ID] = true
+			ordered = append(ordered, byChapter[chapter.ID]...)
+			walk(chapter.ID)
+		}
+	}
+	walk(0)
+	return ordered, nil
+}
+
+// articleProgress 转换为文章阅读进度
+func articleProgress(progress *po.ReadingProgress) *dto.ArticleProgress {
+	return &dto.ArticleProgress{
+		ArticleID:   progress.ArticleID,
+		Percent:     progress.Percent,
+		Completed:   progress.Completed,
+		CompletedAt: progress.CompletedAt,
+		ReadAt:      progress.ReadAt,
+	}
+}
@@ -0,0 +1,185 @@
+package biz
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// stubReadingProgressRepo 内存中的阅读进度仓储，书中只有一个标签
+type stubReadingProgressRepo struct {
+	data.ReadingProgressRepo
+	chapters []*po.Chapter
+	articles []*po.Article
+	progress map[[2]uint]*po.ReadingProgress
+}
+
+func (r *stubReadingProgressRepo) Save(progress *po.ReadingProgress) error {
+	saved := *progress
+	r.progress[[2]uint{progress.UserID, progress.ArticleID}] = &saved
+	return nil
+}
+
+func (r *stubReadingProgressRepo) Find(userID, articleID uint) (*po.ReadingProgress, error) {
+	progress, ok := r.progress[[2]uint{userID, articleID}]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *progress
+	return &found, nil
+}
+
+func (r *stubReadingProgressRepo) ListByTag(userID, tagID uint) ([]*po.ReadingProgress, error) {
+	var list []*po.ReadingProgress
+	for key, progress := range r.progress {
+		if key[0] == userID && progress.TagID == tagID {
+			list = append(list, progress)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ReadAt.After(list[j].ReadAt) })
+	return list, nil
+}
+
+func (r *stubReadingProgressRepo) FindChapter(id uint) (*po.Chapter, error) {
+	for _, chapter := range r.chapters {
+		if chapter.ID == id {
+			return chapter, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubReadingProgressRepo) BookChapters(tagID uint) ([]*po.Chapter, error) {
+	return r.chapters, nil
+}
+
+func (r *stubReadingProgressRepo) BookArticles(tagID uint) ([]*po.Article, error) {
+	return r.articles, nil
+}
+
+// stubTagRepo 内存中的标签仓储
+type stubTagRepo struct {
+	data.TagRepo
+	tags []*po.Tag
+}
+
+func (r *stubTagRepo) FindByName(name string) (*po.Tag, error) {
+	for _, tag := range r.tags {
+		if tag.Name == name {
+			return tag, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func uintPtr(v uint) *uint { return &v }
+
+func intPtr(v int) *int { return &v }
+
+func boolPtr(v bool) *bool { return &v }
+
+// newReadingProgressTestData 书的目录：章节 A（子章节 A1）、章节 B；B 中的文章创建最早，但在目录中排在最后
+func newReadingProgressTestData() (ReadingProgressUseCase, *stubReadingProgressRepo) {
+	chapters := []*po.Chapter{
+		{ID: 1, TagID: 5, Name: "A", Sort: 1},
+		{ID: 2, TagID: 5, Name: "B", Sort: 2},
+		{ID: 3, TagID: 5, Name: "A1", ParentID: uintPtr(1)},
+	}
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	articles := []*po.Article{
+		{ID: 11, Title: "b-1", ChapterID: uintPtr(2), Status: 1, CreatedAt: base},
+		{ID: 12, Title: "a-1", ChapterID: uintPtr(1), Status: 1, CreatedAt: base.Add(time.Hour)},
+		{ID: 13, Title: "a1-1", ChapterID: uintPtr(3), Status: 1, CreatedAt: base.Add(2 * time.Hour)},
+	}
+	byID := map[uint]*po.Article{14: {ID: 14, Status: 1}}
+	for _, article := range articles {
+		byID[article.ID] = article
+	}
+
+	repo := &stubReadingProgressRepo{chapters: chapters, articles: articles, progress: map[[2]uint]*po.ReadingProgress{}}
+	d := &data.Data{
+		ArticleRepo:         &stubCounterArticleRepo{articles: byID},
+		TagRepo:             &stubTagRepo{tags: []*po.Tag{{ID: 5, Name: "go"}}},
+		ReadingProgressRepo: repo,
+	}
+	return NewReadingProgressUseCase(d), repo
+}
+
+func TestReadingProgressBookPercentAndResume(t *testing.T) {
+	uc, _ := newReadingProgressTestData()
+
+	if _, err := uc.Update(1, 12, &dto.UpdateReadingProgressRequest{Percent: intPtr(100)}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	book, err := uc.GetBook(1, "go")
+	if err != nil {
+		t.Fatalf("GetBook: %v", err)
+	}
+	if book.Total != 3 || book.CompletedCount != 1 || book.Percent != 33 {
+		t.Fatalf("book = %d/%d (%d%%), want 1/3 (33%%)", book.CompletedCount, book.Total, book.Percent)
+	}
+	// 没有读到一半的文章时，从目录顺序中下一篇未读完的文章继续
+	if book.ResumeArticleID != 13 || book.ResumeArticleTitle != "a1-1" {
+		t.Fatalf("resume = %d %q, want 13 a1-1", book.ResumeArticleID, book.ResumeArticleTitle)
+	}
+
+	time.Sleep(time.Millisecond)
+	if _, err := uc.Update(1, 11, &dto.UpdateReadingProgressRequest{Percent: intPtr(40)}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	book, err = uc.GetBook(1, "go")
+	if err != nil {
+		t.Fatalf("GetBook: %v", err)
+	}
+	if book.ResumeArticleID != 11 {
+		t.Fatalf("resume = %d, want the article read most recently (11)", book.ResumeArticleID)
+	}
+	if len(book.Articles) != 2 {
+		t.Fatalf("articles = %d, want 2", len(book.Articles))
+	}
+}
+
+func TestReadingProgressCompletedIsSticky(t *testing.T) {
+	uc, repo := newReadingProgressTestData()
+
+	if _, err := uc.Update(1, 12, &dto.UpdateReadingProgressRequest{Completed: boolPtr(true)}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	completedAt := repo.progress[[2]uint{1, 12}].CompletedAt
+
+	// 重新打开已读完的文章不会变回未读
+	progress, err := uc.Update(1, 12, &dto.UpdateReadingProgressRequest{Percent: intPtr(10)})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if !progress.Completed || progress.CompletedAt == nil || !progress.CompletedAt.Equal(*completedAt) {
+		t.Fatalf("progress = %+v, want still completed at %v", progress, completedAt)
+	}
+
+	progress, err = uc.Update(1, 12, &dto.UpdateReadingProgressRequest{Completed: boolPtr(false)})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if progress.Completed || progress.CompletedAt != nil {
+		t.Fatalf("progress = %+v, want not completed", progress)
+	}
+}
+
+func TestReadingProgressRejectsArticleOutsideBook(t *testing.T) {
+	uc, _ := newReadingProgressTestData()
+
+	for _, id := range []uint{14, 99} {
+		if _, err := uc.Update(1, id, &dto.UpdateReadingProgressRequest{Percent: intPtr(50)}); !errors.Is(err, ErrProgressArticleNotFound) {
+			t.Fatalf("Update(%d) = %v, want ErrProgressArticleNotFound", id, err)
+		}
+	}
+	if _, err := uc.GetBook(1, "rust"); !errors.Is(err, ErrBookNotFound) {
+		t.Fatalf("GetBook(rust) = %v, want ErrBookNotFound", err)
+	}
+}
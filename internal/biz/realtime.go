@@ -0,0 +1,172 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/realtime"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+const (
+	// realtimeTicketKey 实时事件连接票据，值为用户 ID
+	realtimeTicketKey = "realtime:ticket:%s"
+	realtimeTicketTTL = time.Minute
+)
+
+var (
+	// ErrRealtimeTicketUnavailable 未配置 Redis，无法签发票据
+	ErrRealtimeTicketUnavailable = errors.New("服务器未启用连接票据，请使用 Authorization 请求头")
+	// ErrRealtimeTicketInvalid 票据无效、已使用或已过期
+	ErrRealtimeTicketInvalid = errors.New("连接票据无效或已过期")
+	// ErrRealtimeArticleNotFound 订阅的文章不存在或未发布
+	ErrRealtimeArticleNotFound = errors.New("文章不存在或未发布")
+)
+
+// RealtimeUseCase 实时事件业务用例接口
+type RealtimeUseCase interface {
+	// IssueTicket 签发一次性连接票据，供无法设置请求头的 EventSource 和 WebSocket 使用
+	IssueTicket(userID uint) (*dto.RealtimeTicketResponse, error)
+	// Channels 确定连接订阅的频道，userID 为 0 时尝试使用票据识别用户
+	Channels(userID uint, req *dto.RealtimeStreamRequest) ([]string, error)
+	// RedeemTicket 使用连接票据，返回签发时的用户 ID，票据只能使用一次
+	RedeemTicket(ticket string) (uint, error)
+}
+
+// realtimeUseCase 实时事件业务用例实现
+type realtimeUseCase struct {
+	data *data.Data
+}
+
+// NewRealtimeUseCase 创建实时事件业务用例
+func NewRealtimeUseCase(d *data.Data) RealtimeUseCase {
+	return &realtimeUseCase{data: d}
+}
+
+// IssueTicket 签发连接票据，一分钟内有效，使用一次后失效
+func (uc *realtimeUseCase) IssueTicket(userID uint) (*dto.RealtimeTicketResponse, error) {
+	if redis.Client == nil {
+		return nil, ErrRealtimeTicketUnavailable
+	}
+	ticket, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	if err := redis.SetWithExpire(fmt.Sprintf(realtimeTicketKey, ticket), userID, realtimeTicketTTL); err != nil {
+		return nil, err
+	}
+	return &dto.RealtimeTicketResponse{Ticket: ticket, ExpiresAt: time.Now().Add(realtimeTicketTTL)}, nil
+}
+
+// Channels 所有连接订阅全站频道，登录用户订阅自己的频道，查看文章或留言板时订阅对应频道
+func (uc *realtimeUseCase) Channels(userID uint, req *dto.RealtimeStreamRequest) ([]string, error) {
+	if userID == 0 && req.Ticket != "" {
+		id, err := uc.RedeemTicket(req.Ticket)
+		if err != nil {
+			return nil, err
+		}
+		userID = id
+	}
+
+	channels := []string{realtime.ChannelSite}
+	if userID != 0 {
+		channels = append(channels, realtime.UserChannel(userID))
+	}
+	if req.ArticleID != 0 {
+		article, err := uc.data.ArticleRepo.FindByID(req.ArticleID)
+		if err != nil || article.Status != 1 || !articleVisible(article) {
+			return nil, ErrRealtimeArticleNotFound
+		}
+		channels = append(channels, realtime.ArticleChannel(article.ID))
+	} else if req.Guestbook {
+		channels = append(channels, realtime.ChannelGuestbook)
+	}
+	return channels, nil
+}
+
+// RedeemTicket 使用票据，返回签发时的用户 ID
+func (uc *realtimeUseCase) RedeemTicket(ticket string) (uint, error) {
+	if redis.Client == nil {
+		return 0, ErrRealtimeTicketInvalid
+	}
+	value, err := redis.GetDel(fmt.Sprintf(realtimeTicketKey, ticket))
+	if err != nil {
+		return 0, ErrRealtimeTicketInvalid
+	}
+	var userID uint
+	if _, err := fmt.Sscan(value, &userID); err != nil || userID == 0 {
+		return 0, ErrRealtimeTicketInvalid
+	}
+	return userID, nil
+}
+
+// publishComment 审核通过的评论推送给正在查看该页面的读者，回复同时通知被回复的用户
+func publishComment(d *data.Data, comment *po.Comment) {
+	go func() {
+		author, err := d.UserRepo.FindByID(comment.UserID)
+		if err != nil {
+			return
+		}
+		excerpt := truncateRunes(comment.Content, 100)
+
+		channel := realtime.ChannelGuestbook
+		if comment.ArticleID != nil {
+			channel = realtime.ArticleChannel(*comment.ArticleID)
+		}
+		event := &dto.RealtimeComment{
+			ID:         comment.ID,
+			ArticleID:  comment.ArticleID,
+			ParentID:   comment.ParentID,
+			RootID:     comment.RootID,
+			AuthorName: commentAuthorName(author),
+			Avatar:     author.Avatar,
+			Excerpt:    excerpt,
+			CreatedAt:  comment.CreatedAt,
+		}
+		if err := realtime.Publish(channel, realtime.EventComment, event); err != nil {
+			logger.Warn("Failed to publish comment event: ", err)
+		}
+
+		if _, recipientID, ok := replyRecipient(d, comment); ok {
+			publishNotification(recipientID, &dto.RealtimeNotification{
+				Type:      dto.NotificationCommentReply,
+				ActorID:   author.ID,
+				ActorName: commentAuthorName(author),
+				Avatar:    author.Avatar,
+				ArticleID: comment.ArticleID,
+				CommentID: comment.ID,
+				Excerpt:   excerpt,
+				CreatedAt: comment.CreatedAt,
+			})
+		}
+	}()
+}
+
+// publishFollow 通知被关注的用户
+func publishFollow(d *data.Data, followerID, followeeID uint) {
+	go func() {
+		follower, err := d.UserRepo.FindByID(followerID)
+		if err != nil {
+			return
+		}
+		publishNotification(followeeID, &dto.RealtimeNotification{
+			Type:      dto.NotificationFollow,
+			ActorID:   follower.ID,
+			ActorName: commentAuthorName(follower),
+			Avatar:    follower.Avatar,
+			CreatedAt: time.Now(),
+		})
+	}()
+}
+
+// publishNotification 推送通知给用户的所有连接
+func publishNotification(userID uint, notification *dto.RealtimeNotification) {
+	if err := realtime.Publish(realtime.UserChannel(userID), realtime.EventNotification, notification); err != nil {
+		logger.Warn("Failed to publish notification event: ", err)
+	}
+}
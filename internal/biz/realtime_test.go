@@ -0,0 +1,111 @@
+package biz
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/realtime"
+)
+
+// stubCommentRepo 内存中的评论仓储
+type stubCommentRepo struct {
+	data.CommentRepo
+	comments map[uint]*po.Comment
+}
+
+func (r *stubCommentRepo) FindByID(id uint) (*po.Comment, error) {
+	if comment, ok := r.comments[id]; ok {
+		return comment, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func TestRealtimeTicketIdentifiesUserOnce(t *testing.T) {
+	setupTokenRedis(t)
+	uc := NewRealtimeUseCase(&data.Data{})
+
+	ticket, err := uc.IssueTicket(7)
+	if err != nil {
+		t.Fatalf("IssueTicket: %v", err)
+	}
+	channels, err := uc.Channels(0, &dto.RealtimeStreamRequest{Ticket: ticket.Ticket, Guestbook: true})
+	if err != nil {
+		t.Fatalf("Channels: %v", err)
+	}
+	want := []string{realtime.ChannelSite, realtime.UserChannel(7), realtime.ChannelGuestbook}
+	if len(channels) != len(want) {
+		t.Fatalf("channels = %v, want %v", channels, want)
+	}
+	for i := range want {
+		if channels[i] != want[i] {
+			t.Fatalf("channels = %v, want %v", channels, want)
+		}
+	}
+
+	if _, err := uc.Channels(0, &dto.RealtimeStreamRequest{Ticket: ticket.Ticket}); !errors.Is(err, ErrRealtimeTicketInvalid) {
+		t.Fatalf("reused ticket = %v, want ErrRealtimeTicketInvalid", err)
+	}
+}
+
+func TestRealtimeChannelsRejectHiddenArticle(t *testing.T) {
+	uc := NewRealtimeUseCase(&data.Data{ArticleRepo: &stubCounterArticleRepo{articles: map[uint]*po.Article{
+		1: {ID: 1, Status: 1, Visibility: po.VisibilityPublic},
+		2: {ID: 2, Status: 1, Visibility: po.VisibilityPrivate},
+	}}})
+
+	channels, err := uc.Channels(0, &dto.RealtimeStreamRequest{ArticleID: 1})
+	if err != nil || len(channels) != 2 || channels[1] != realtime.ArticleChannel(1) {
+		t.Fatalf("Channels = %v, %v", channels, err)
+	}
+	if _, err := uc.Channels(0, &dto.RealtimeStreamRequest{ArticleID: 2}); !errors.Is(err, ErrRealtimeArticleNotFound) {
+		t.Fatalf("Channels(private) = %v, want ErrRealtimeArticleNotFound", err)
+	}
+}
+
+func TestPublishCommentNotifiesReplyRecipient(t *testing.T) {
+	articleID, parentID := uint(3), uint(10)
+	d := &data.Data{
+		UserRepo: &stubUserRepo{users: map[uint]*po.User{
+			1: {ID: 1, Username: "alice"},
+			2: {ID: 2, Username: "bob", Nickname: "Bob"},
+		}},
+		CommentRepo: &stubCommentRepo{comments: map[uint]*po.Comment{
+			parentID: {ID: parentID, UserID: 1, ArticleID: &articleID},
+		}},
+	}
+
+	viewer, err := realtime.Subscribe(0, realtime.ArticleChannel(articleID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer viewer.Close()
+	recipient, err := realtime.Subscribe(0, realtime.UserChannel(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer recipient.Close()
+
+	publishComment(d, &po.Comment{ID: 11, UserID: 2, ArticleID: &articleID, ParentID: &parentID, Content: "hi"})
+
+	for _, sub := range []*realtime.Subscriber{viewer, recipient} {
+		select {
+		case event := <-sub.Events():
+			if event.Type == realtime.EventNotification {
+				var notification dto.RealtimeNotification
+				if err := json.Unmarshal(event.Data, &notification); err != nil {
+					t.Fatal(err)
+				}
+				if notification.Type != dto.NotificationCommentReply || notification.ActorName != "Bob" || notification.CommentID != 11 {
+					t.Fatalf("notification = %+v", notification)
+				}
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("event not received")
+		}
+	}
+}
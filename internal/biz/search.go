@@ -0,0 +1,223 @@
+package biz
+
+import (
+	"errors"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/search"
+)
+
+// reindexBatchSize 重建索引时每批写入的文章数
+const reindexBatchSize = 200
+
+// SearchUseCase 全文搜索业务用例接口
+type SearchUseCase interface {
+	// Search 搜索已发布的文章（未启用搜索引擎时退化为数据库 LIKE 查询）
+	Search(req *dto.SearchRequest) (*dto.PageResponse, error)
+	// Reindex 重建全部已发布文章的索引
+	Reindex() (*dto.ReindexResponse, error)
+}
+
+// searchUseCase 全文搜索业务用例实现
+type searchUseCase struct {
+	data     *data.Data
+	articles *articleUseCase
+}
+
+// NewSearchUseCase 创建全文搜索业务用例
+func NewSearchUseCase(d *data.Data) SearchUseCase {
+	return &searchUseCase{
+		data:     d,
+		articles: &articleUseCase{data: d},
+	}
+}
+
+// Search 搜索文章
+func (uc *searchUseCase) Search(req *dto.SearchRequest) (*dto.PageResponse, error) {
+	engine := search.Default()
+	if engine == nil {
+		return uc.searchDatabase(req)
+	}
+
+	result, err := engine.Search(&search.Query{
+		Keyword: req.Keyword,
+		Page:    req.Page,
+		Limit:   req.Limit,
+	})
+	if err != nil {
+		logger.Warn("Search engine unavailable, falling back to database: ", err)
+		return uc.searchDatabase(req)
+	}
+
+	ids := make([]uint, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.ID)
+	}
+
+	articles := make(map[uint]*po.Article, len(ids))
+	if len(ids) > 0 {
+		list, err := uc.data.ArticleRepo.PublicOnly().FindByIDs(ids)
+		if err != nil {
+			return nil, errors.New("查询文章失败")
+		}
+		for _, article := range list {
+			articles[article.ID] = article
+		}
+	}
+
+	// 按相关度顺序返回，跳过已删除、已下线或已设为私密但索引尚未同步的文章
+	items := make([]dto.SearchHit, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		article, ok := articles[hit.ID]
+		if !ok || !searchable(article) {
+			continue
+		}
+		items = append(items, dto.SearchHit{
+			ArticleListItem: uc.articles.convertToArticleListItem(article),
+			Score:           hit.Score,
+			Highlight: dto.SearchHighlight{
+				Title:   hit.Title,
+				Summary: hit.Summary,
+				Content: hit.Content,
+			},
+		})
+	}
+
+	return &dto.PageResponse{
+		Total: result.Total,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Data:  items,
+	}, nil
+}
+
+// searchDatabase 使用数据库 LIKE 查询标题和摘要
+func (uc *searchUseCase) searchDatabase(req *dto.SearchRequest) (*dto.PageResponse, error) {
+	articles, total, err := uc.data.ArticleRepo.PublicOnly().List(req.Page, req.Limit, 0, 0, 0, 0, "1", req.Keyword, "latest")
+	if err != nil {
+		return nil, errors.New("搜索文章失败")
+	}
+
+	items := make([]dto.SearchHit, 0, len(articles))
+	for _, article := range articles {
+		item := uc.articles.convertToArticleListItem(article)
+		if articleLocked(article) {
+			item.Summary = ""
+		}
+		items = append(items, dto.SearchHit{
+			ArticleListItem: item,
+		})
+	}
+
+	return &dto.PageResponse{
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Data:  items,
+	}, nil
+}
+
+// Reindex 重建索引
+func (uc *searchUseCase) Reindex() (*dto.ReindexResponse, error) {
+	engine := search.Default()
+	if engine == nil {
+		return nil, errors.New("未启用全文搜索引擎")
+	}
+
+	indexed := 0
+	for page := 1; ; page++ {
+		articles, _, err := uc.data.ArticleRepo.PublicOnly().List(page, reindexBatchSize, 0, 0, 0, 0, "1", "", "latest")
+		if err != nil {
+			return nil, errors.New("查询文章失败")
+		}
+		if len(articles) == 0 {
+			break
+		}
+
+		docs := make([]*search.Document, 0, len(articles))
+		for _, article := range articles {
+			if searchable(article) {
+				docs = append(docs, buildSearchDocument(article))
+			}
+		}
+		if err := engine.Index(docs...); err != nil {
+			return nil, errors.New("写入索引失败: " + err.Error())
+		}
+		indexed += len(docs)
+
+		if len(articles) < reindexBatchSize {
+			break
+		}
+	}
+
+	return &dto.ReindexResponse{
+		Engine:  engine.Name(),
+		Indexed: indexed,
+	}, nil
+}
+
+// syncSearchIndex 异步同步文章索引：已发布的公开文章写入索引，其余从索引中移除
+func syncSearchIndex(d *data.Data, articleIDs ...uint) {
+	engine := search.Default()
+	if engine == nil || len(articleIDs) == 0 {
+		return
+	}
+
+	go func() {
+		articles, err := d.ArticleRepo.FindByIDs(articleIDs)
+		if err != nil {
+			logger.Warn("Failed to load articles for search index: ", err)
+			return
+		}
+
+		published := make(map[uint]bool, len(articles))
+		docs := make([]*search.Document, 0, len(articles))
+		for _, article := range articles {
+			if searchable(article) {
+				published[article.ID] = true
+				docs = append(docs, buildSearchDocument(article))
+			}
+		}
+
+		removed := make([]uint, 0, len(articleIDs))
+		for _, id := range articleIDs {
+			if !published[id] {
+				removed = append(removed, id)
+			}
+		}
+
+		if err := engine.Index(docs...); err != nil {
+			logger.Warn("Failed to index articles: ", err)
+		}
+		if err := engine.Delete(removed...); err != nil {
+			logger.Warn("Failed to remove articles from search index: ", err)
+		}
+	}()
+}
+
+// searchable 文章是否可以进入搜索索引：已发布且公开
+// 私密文章和加密文章的正文都不能通过搜索高亮泄露
+func searchable(article *po.Article) bool {
+	return article.Status == 1 && article.Visibility == po.VisibilityPublic
+}
+
+// buildSearchDocument 构建索引文档
+func buildSearchDocument(article *po.Article) *search.Document {
+	doc := &search.Document{
+		ID:        article.ID,
+		Title:     article.Title,
+		Slug:      articleSlug(article),
+		Summary:   article.Summary,
+		Content:   article.ContentMarkdown,
+		Category:  article.Category.Name,
+		Author:    article.Author.Nickname,
+		CreatedAt: article.CreatedAt.Unix(),
+	}
+	for _, tag := range article.Tags {
+		doc.Tags = append(doc.Tags, tag.Name)
+	}
+	return doc
+}
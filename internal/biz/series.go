@@ -0,0 +1,273 @@
+package biz
+
+import (
+	"errors"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// SeriesUseCase 文章系列业务用例接口
+type SeriesUseCase interface {
+	// Create 创建系列
+	Create(req *dto.CreateSeriesRequest) (*dto.SeriesResponse, error)
+	// Update 更新系列
+	Update(id uint, req *dto.UpdateSeriesRequest) (*dto.SeriesResponse, error)
+	// Delete 删除系列（不删除文章）
+	Delete(id uint) error
+	// Get 获取系列详情及文章目录，public 为 true 时只包含前台可见的文章
+	Get(id uint, public bool) (*dto.SeriesResponse, error)
+	// List 查询系列列表
+	List(req *dto.SeriesListRequest, public bool) (*dto.PageResponse, error)
+	// SetArticles 设置系列中的文章及顺序
+	SetArticles(id uint, articleIDs []uint) (*dto.SeriesResponse, error)
+	// Navigation 获取文章所在系列的导航（上一篇、下一篇、阅读进度）
+	Navigation(articleID uint) ([]dto.SeriesNavigation, error)
+}
+
+// seriesUseCase 文章系列业务用例实现
+type seriesUseCase struct {
+	data *data.Data
+}
+
+// NewSeriesUseCase 创建文章系列业务用例
+func NewSeriesUseCase(d *data.Data) SeriesUseCase {
+	return &seriesUseCase{data: d}
+}
+
+// Create 创建系列
+func (uc *seriesUseCase) Create(req *dto.CreateSeriesRequest) (*dto.SeriesResponse, error) {
+	articleIDs, err := uc.checkArticles(req.ArticleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	series := &po.Series{
+		Title:       req.Title,
+		Description: req.Description,
+		Cover:       req.Cover,
+		Sort:        req.Sort,
+	}
+	if err := uc.data.SeriesRepo.Create(series); err != nil {
+		return nil, errors.New("创建系列失败")
+	}
+
+	if len(articleIDs) > 0 {
+		if err := uc.data.SeriesRepo.SetArticles(series.ID, articleIDs); err != nil {
+			return nil, errors.New("设置系列文章失败")
+		}
+	}
+
+	return uc.Get(series.ID, false)
+}
+
+// Update 更新系列
+func (uc *seriesUseCase) Update(id uint, req *dto.UpdateSeriesRequest) (*dto.SeriesResponse, error) {
+	series, err := uc.data.SeriesRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("系列不存在")
+	}
+
+	var articleIDs []uint
+	if req.ArticleIDs != nil {
+		if articleIDs, err = uc.checkArticles(*req.ArticleIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	series.Title = req.Title
+	series.Description = req.Description
+	series.Cover = req.Cover
+	series.Sort = req.Sort
+	if err := uc.data.SeriesRepo.Update(series); err != nil {
+		return nil, errors.New("更新系列失败")
+	}
+
+	if req.ArticleIDs != nil {
+		if err := uc.data.SeriesRepo.SetArticles(id, articleIDs); err != nil {
+			return nil, errors.New("设置系列文章失败")
+		}
+	}
+
+	return uc.Get(id, false)
+}
+
+// Delete 删除系列
+func (uc *seriesUseCase) Delete(id uint) error {
+	if _, err := uc.data.SeriesRepo.FindByID(id); err != nil {
+		return errors.New("系列不存在")
+	}
+	if err := uc.data.SeriesRepo.Delete(id); err != nil {
+		return errors.New("删除系列失败")
+	}
+	return nil
+}
+
+// Get 获取系列详情
+func (uc *seriesUseCase) Get(id uint, public bool) (*dto.SeriesResponse, error) {
+	series, err := uc.data.SeriesRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("系列不存在")
+	}
+
+	items, err := uc.data.SeriesRepo.ListArticles(id, public)
+	if err != nil {
+		return nil, errors.New("查询系列文章失败")
+	}
+
+	resp := convertToSeriesResponse(series)
+	resp.Articles = convertToSeriesArticleItems(items)
+	resp.ArticleCount = int64(len(resp.Articles))
+	return resp, nil
+}
+
+// List 查询系列列表
+func (uc *seriesUseCase) List(req *dto.SeriesListRequest, public bool) (*dto.PageResponse, error) {
+	list, total, err := uc.data.SeriesRepo.List(req.Page, req.Limit, req.Keyword)
+	if err != nil {
+		return nil, errors.New("查询系列列表失败")
+	}
+
+	ids := make([]uint, 0, len(list))
+	for _, series := range list {
+		ids = append(ids, series.ID)
+	}
+	counts, err := uc.data.SeriesRepo.CountArticles(ids, public)
+	if err != nil {
+		return nil, errors.New("统计系列文章失败")
+	}
+
+	items := make([]*dto.SeriesResponse, 0, len(list))
+	for _, series := range list {
+		resp := convertToSeriesResponse(series)
+		resp.ArticleCount = counts[series.ID]
+		items = append(items, resp)
+	}
+
+	return &dto.PageResponse{
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Data:  items,
+	}, nil
+}
+
+// SetArticles 设置系列中的文章及顺序
+func (uc *seriesUseCase) SetArticles(id uint, articleIDs []uint) (*dto.SeriesResponse, error) {
+	if _, err := uc.data.SeriesRepo.FindByID(id); err != nil {
+		return nil, errors.New("系列不存在")
+	}
+
+	articleIDs, err := uc.checkArticles(articleIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.data.SeriesRepo.SetArticles(id, articleIDs); err != nil {
+		return nil, errors.New("设置系列文章失败")
+	}
+
+	return uc.Get(id, false)
+}
+
+// Navigation 获取文章所在系列的导航
+// 只统计前台可见的文章，私密、未发布或已删除的文章不计入进度
+func (uc *seriesUseCase) Navigation(articleID uint) ([]dto.SeriesNavigation, error) {
+	article, err := uc.data.ArticleRepo.FindByID(articleID)
+	if err != nil || article.Status != 1 || !articleVisible(article) {
+		return nil, errors.New("文章不存在或未发布")
+	}
+
+	list, err := uc.data.SeriesRepo.FindByArticle(articleID)
+	if err != nil {
+		return nil, errors.New("查询文章系列失败")
+	}
+
+	navs := make([]dto.SeriesNavigation, 0, len(list))
+	for _, series := range list {
+		items, err := uc.data.SeriesRepo.ListArticles(series.ID, true)
+		if err != nil {
+			return nil, errors.New("查询系列文章失败")
+		}
+
+		articles := convertToSeriesArticleItems(items)
+		nav := dto.SeriesNavigation{
+			SeriesID:    series.ID,
+			SeriesTitle: series.Title,
+			Total:       len(articles),
+			Articles:    articles,
+		}
+		for i := range articles {
+			if articles[i].ID != articleID {
+				continue
+			}
+			nav.Position = articles[i].Position
+			if i > 0 {
+				nav.Prev = &articles[i-1]
+			}
+			if i < len(articles)-1 {
+				nav.Next = &articles[i+1]
+			}
+			break
+		}
+		navs = append(navs, nav)
+	}
+
+	return navs, nil
+}
+
+// checkArticles 去重并校验文章是否存在，保持原有顺序
+func (uc *seriesUseCase) checkArticles(articleIDs []uint) ([]uint, error) {
+	seen := make(map[uint]bool, len(articleIDs))
+	unique := make([]uint, 0, len(articleIDs))
+	for _, id := range articleIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return unique, nil
+	}
+
+	articles, err := uc.data.ArticleRepo.FindByIDs(unique)
+	if err != nil {
+		return nil, errors.New("查询文章失败")
+	}
+	if len(articles) != len(unique) {
+		return nil, errors.New("部分文章不存在")
+	}
+	return unique, nil
+}
+
+// convertToSeriesResponse 转换为系列响应
+func convertToSeriesResponse(series *po.Series) *dto.SeriesResponse {
+	return &dto.SeriesResponse{
+		ID:          series.ID,
+		Title:       series.Title,
+		Description: series.Description,
+		Cover:       series.Cover,
+		Sort:        series.Sort,
+		CreatedAt:   series.CreatedAt,
+		UpdatedAt:   series.UpdatedAt,
+	}
+}
+
+// convertToSeriesArticleItems 转换为系列文章列表，序号按过滤后的顺序重新编号
+func convertToSeriesArticleItems(items []*po.SeriesArticle) []dto.SeriesArticleItem {
+	result := make([]dto.SeriesArticleItem, 0, len(items))
+	for _, item := range items {
+		if item.Article == nil {
+			continue
+		}
+		result = append(result, dto.SeriesArticleItem{
+			ID:        item.Article.ID,
+			Title:     item.Article.Title,
+			Slug:      articleSlug(item.Article),
+			Status:    item.Article.Status,
+			Position:  len(result) + 1,
+			CreatedAt: item.Article.CreatedAt,
+		})
+	}
+	return result
+}
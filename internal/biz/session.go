@@ -0,0 +1,142 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/jwt"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+var (
+	// ErrSessionNotFound 会话不存在或已失效
+	ErrSessionNotFound = errors.New("会话不存在或已失效")
+	// ErrSessionStoreUnavailable 未启用 Redis，无法管理会话
+	ErrSessionStoreUnavailable = errors.New("未启用 Redis，无法管理登录会话")
+)
+
+// ListSessions 查询用户的登录会话，按最近使用时间倒序
+// 已过期的家族在查询时从用户的家族集合中清理
+func (uc *authUseCase) ListSessions(userID uint, currentSession string) ([]*dto.SessionInfo, error) {
+	if redis.Client == nil {
+		return nil, ErrSessionStoreUnavailable
+	}
+	userKey := fmt.Sprintf(refreshUserKey, userID)
+	families, err := redis.SMembers(userKey)
+	if err != nil {
+		logger.Error("Failed to list sessions of user ", userID, ": ", err)
+		return nil, errors.New("查询登录会话失败")
+	}
+
+	sessions := make([]*dto.SessionInfo, 0, len(families))
+	for _, family := range families {
+		info, err := loadRefreshFamily(family)
+		if err != nil || info.UserID != userID {
+			if redis.IsNil(err) {
+				_ = redis.SRem(userKey, family)
+			}
+			continue
+		}
+		sessions = append(sessions, &dto.SessionInfo{
+			ID:         family,
+			Device:     deviceName(info.UserAgent),
+			IP:         info.IP,
+			UserAgent:  info.UserAgent,
+			Console:    info.Role != "user",
+			Current:    family == currentSession,
+			CreatedAt:  info.CreatedAt,
+			LastUsedAt: info.LastUsedAt,
+		})
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+	return sessions, nil
+}
+
+// RevokeSession 吊销用户的一个会话，该会话的刷新令牌和访问令牌立即失效
+func (uc *authUseCase) RevokeSession(userID uint, sessionID string) error {
+	if redis.Client == nil {
+		return ErrSessionStoreUnavailable
+	}
+	info, err := loadRefreshFamily(sessionID)
+	if err != nil || info.UserID != userID {
+		return ErrSessionNotFound
+	}
+	revokeRefreshFamily(sessionID, userID)
+	return nil
+}
+
+// LogoutAll 退出所有设备，包括当前会话
+func (uc *authUseCase) LogoutAll(userID uint) error {
+	if redis.Client == nil {
+		return ErrSessionStoreUnavailable
+	}
+	revokeUserSessions(userID)
+	return nil
+}
+
+// Logout 退出登录：吊销刷新令牌所在的家族；携带访问令牌时将其加入黑名单并吊销所属会话
+func (uc *authUseCase) Logout(refreshToken, accessToken string) {
+	revokeRefreshToken(refreshToken)
+	if accessToken == "" {
+		return
+	}
+	claims, err := jwt.ParseToken(accessToken)
+	if err != nil {
+		return
+	}
+	if err := jwt.DenyToken(claims); err != nil {
+		logger.Error("Failed to deny access token: ", err)
+	}
+	if claims.SessionID != "" && redis.Client != nil {
+		if info, err := loadRefreshFamily(claims.SessionID); err == nil && info.UserID == claims.AdminID {
+			revokeRefreshFamily(claims.SessionID, claims.AdminID)
+		}
+	}
+}
+
+// deviceBrowsers 按顺序匹配 User-Agent 中的浏览器标识（Edge、Opera 的标识中同时包含 Chrome）
+var deviceBrowsers = []struct{ token, name string }{
+	{"MicroMessenger", "微信"},
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Firefox/", "Firefox"},
+	{"Chrome/", "Chrome"},
+	{"Safari/", "Safari"},
+}
+
+// deviceSystems 按顺序匹配 User-Agent 中的系统标识（Android 的标识中同时包含 Linux）
+var deviceSystems = []struct{ token, name string }{
+	{"Windows", "Windows"},
+	{"iPhone", "iOS"},
+	{"iPad", "iPadOS"},
+	{"Android", "Android"},
+	{"Mac OS X", "macOS"},
+	{"Linux", "Linux"},
+}
+
+// deviceName 根据 User-Agent 识别浏览器和系统，无法识别时返回未知设备
+func deviceName(userAgent string) string {
+	var parts []string
+	for _, b := range deviceBrowsers {
+		if strings.Contains(userAgent, b.token) {
+			parts = append(parts, b.name)
+			break
+		}
+	}
+	for _, s := range deviceSystems {
+		if strings.Contains(userAgent, s.token) {
+			parts = append(parts, s.name)
+			break
+		}
+	}
+	if len(parts) == 0 {
+		return "未知设备"
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,151 @@
+package biz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/jwt"
+)
+
+const (
+	chromeWindowsUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36"
+	safariIPhoneUA  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"
+)
+
+// sessionOf 解析访问令牌所属的会话
+func sessionOf(t *testing.T, token string) *jwt.Claims {
+	t.Helper()
+	claims, err := jwt.ParseToken(token)
+	if err != nil {
+		t.Fatalf("ParseToken: %v", err)
+	}
+	return claims
+}
+
+func TestRevokeSession(t *testing.T) {
+	setupTokenRedis(t)
+	alice := &po.User{ID: 1, Username: "alice", Role: "user", Status: po.UserStatusActive}
+	d := &data.Data{UserRepo: &stubUserRepo{users: map[uint]*po.User{1: alice}}}
+	uc := &authUseCase{data: d}
+
+	desktop, err := issueTokens(alice, "user", &dto.ClientInfo{IP: "10.0.0.1", UserAgent: chromeWindowsUA})
+	if err != nil {
+		t.Fatalf("issueTokens: %v", err)
+	}
+	phone, err := issueTokens(alice, "user", &dto.ClientInfo{IP: "10.0.0.2", UserAgent: safariIPhoneUA})
+	if err != nil {
+		t.Fatalf("issueTokens: %v", err)
+	}
+	desktopSession := sessionOf(t, desktop.Token).SessionID
+
+	sessions, err := uc.ListSessions(alice.ID, desktopSession)
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("sessions = %d, want 2", len(sessions))
+	}
+	devices := map[string]*dto.SessionInfo{}
+	for _, s := range sessions {
+		devices[s.Device] = s
+	}
+	if s := devices["Chrome · Windows"]; s == nil || !s.Current || s.IP != "10.0.0.1" {
+		t.Errorf("desktop session = %+v, want current Chrome · Windows from 10.0.0.1", s)
+	}
+	if s := devices["Safari · iOS"]; s == nil || s.Current {
+		t.Errorf("phone session = %+v, want non-current Safari · iOS", s)
+	}
+
+	if err := uc.RevokeSession(2, desktopSession); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("revoke other user's session: err = %v, want ErrSessionNotFound", err)
+	}
+	if err := uc.RevokeSession(alice.ID, desktopSession); err != nil {
+		t.Fatalf("RevokeSession: %v", err)
+	}
+
+	if !jwt.Revoked(sessionOf(t, desktop.Token)) {
+		t.Error("access token of revoked session is still valid")
+	}
+	if _, err := refreshTokens(d, desktop.RefreshToken, nil); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Errorf("refresh revoked session: err = %v, want ErrRefreshTokenInvalid", err)
+	}
+	if jwt.Revoked(sessionOf(t, phone.Token)) {
+		t.Error("access token of other session was revoked")
+	}
+	rotated, err := refreshTokens(d, phone.RefreshToken, &dto.ClientInfo{IP: "10.0.0.3"})
+	if err != nil {
+		t.Fatalf("refresh other session: %v", err)
+	}
+	if sessionOf(t, rotated.Token).SessionID != sessionOf(t, phone.Token).SessionID {
+		t.Error("rotated access token belongs to a different session")
+	}
+
+	sessions, err = uc.ListSessions(alice.ID, "")
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].IP != "10.0.0.3" {
+		t.Errorf("sessions after revoke = %+v, want the phone session last used from 10.0.0.3", sessions)
+	}
+}
+
+func TestLogoutDeniesAccessToken(t *testing.T) {
+	setupTokenRedis(t)
+	alice := &po.User{ID: 1, Username: "alice", Role: "user", Status: po.UserStatusActive}
+	d := &data.Data{UserRepo: &stubUserRepo{users: map[uint]*po.User{1: alice}}}
+	uc := &authUseCase{data: d}
+
+	current, err := issueTokens(alice, "user", nil)
+	if err != nil {
+		t.Fatalf("issueTokens: %v", err)
+	}
+	other, err := issueTokens(alice, "user", nil)
+	if err != nil {
+		t.Fatalf("issueTokens: %v", err)
+	}
+
+	uc.Logout("", current.Token)
+
+	if !jwt.Revoked(sessionOf(t, current.Token)) {
+		t.Error("access token is still valid after logout")
+	}
+	if _, err := refreshTokens(d, current.RefreshToken, nil); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Errorf("refresh after logout: err = %v, want ErrRefreshTokenInvalid", err)
+	}
+	if jwt.Revoked(sessionOf(t, other.Token)) {
+		t.Error("logout revoked another session")
+	}
+
+	if err := uc.LogoutAll(alice.ID); err != nil {
+		t.Fatalf("LogoutAll: %v", err)
+	}
+	if !jwt.Revoked(sessionOf(t, other.Token)) {
+		t.Error("access token is still valid after logging out all devices")
+	}
+	if sessions, err := uc.ListSessions(alice.ID, ""); err != nil || len(sessions) != 0 {
+		t.Errorf("sessions after logging out all devices = %v, %v", sessions, err)
+	}
+}
+
+func TestDeviceName(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{userAgent: chromeWindowsUA, want: "Chrome · Windows"},
+		{userAgent: safariIPhoneUA, want: "Safari · iOS"},
+		{userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36 Edg/120.0", want: "Edge · macOS"},
+		{userAgent: "Mozilla/5.0 (Linux; Android 14) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Mobile Safari/537.36 MicroMessenger/8.0", want: "微信 · Android"},
+		{userAgent: "curl/8.4.0", want: "未知设备"},
+		{userAgent: "", want: "未知设备"},
+	}
+
+	for _, tt := range tests {
+		if got := deviceName(tt.userAgent); got != tt.want {
+			t.Errorf("deviceName(%q) = %q, want %q", tt.userAgent, got, tt.want)
+		}
+	}
+}
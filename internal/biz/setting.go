@@ -0,0 +1,484 @@
+package biz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// 系统设置项，未设置时使用注册表中的默认值（部分取自配置文件）
+const (
+	SettingSiteTitle       = "site_title"
+	SettingSiteDescription = "site_description"
+	SettingSiteKeywords    = "site_keywords"
+	SettingSiteLogo        = "site_logo"
+	SettingICPNumber       = "icp_number"
+	SettingPoliceNumber    = "police_number"
+	SettingFooter          = "footer"
+	SettingSiteStartDate   = "site_start_date"
+
+	SettingCommentEnabled       = "comment_enabled"
+	SettingGuestbookEnabled     = "guestbook_enabled"
+	SettingCommentModeration    = "comment_moderation"
+	SettingCommentHoldFirstTime = "comment_hold_first_time"
+	SettingCommentEditWindow    = "comment_edit_window"
+
+	SettingAttachmentMaxSize = "attachment_max_size"
+	SettingVideoMaxSize      = "video_max_size"
+
+	SettingAllowRegistration        = "allow_registration"
+	SettingRequireEmailVerification = "require_email_verification"
+	SettingFriendLinkApply          = "friend_link_apply"
+
+	SettingThemePrimaryColor = "theme_primary_color"
+	SettingThemeDarkMode     = "theme_dark_mode"
+	SettingThemeHeroImages   = "theme_hero_images"
+	SettingThemeFontFamily   = "theme_font_family"
+	SettingThemeHeadingFont  = "theme_heading_font"
+	SettingThemeFontSize     = "theme_font_size"
+)
+
+// 设置值的类型
+const (
+	settingTypeString = "string"
+	settingTypeText   = "text"
+	settingTypeInt    = "int"
+	settingTypeBool   = "bool"
+	settingTypeDate   = "date"
+	settingTypeColor  = "color"  // #rrggbb
+	settingTypeSelect = "select" // Options 中的一项
+	settingTypeList   = "list"   // 字符串数组，以 JSON 保存，Max 为最大项数
+)
+
+// 上传大小设置的范围（MB）
+const (
+	defaultVideoMaxSize = 100
+	maxUploadSizeLimit  = 10240
+)
+
+var (
+	// ErrSettingInvalid 设置值与类型不符或超出范围
+	ErrSettingInvalid = errors.New("设置值不合法")
+	// ErrCommentClosed 系统设置关闭了文章评论
+	ErrCommentClosed = errors.New("评论功能已关闭")
+	// ErrGuestbookClosed 系统设置关闭了留言板
+	ErrGuestbookClosed = errors.New("留言板已关闭")
+
+	// settingKeyPattern 自定义设置的键，只允许小写字母、数字、下划线和点
+	settingKeyPattern = regexp.MustCompile(`^[a-z0-9_.]{1,100}$`)
+	// settingColorPattern 颜色设置值
+	settingColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+	// themeFonts 可选的字体，由前端映射为具体的字体栈
+	themeFonts = []string{"system", "sans-serif", "serif", "monospace", "rounded"}
+)
+
+// settingDef 设置项定义：字符串类型的 Max 为最大字符数，整数类型的 Min、Max 为取值范围，列表类型的 Max 为最大项数
+type settingDef struct {
+	Key         string
+	Type        string
+	Group       string
+	Label       string
+	Description string
+	Public      bool
+	Min         int
+	Max         int
+	Options     []string // 选择类型的可选值
+	Default     func() any
+}
+
+// settingDefs 内置设置项，按后台显示顺序排列；未注册的键作为自定义字符串设置保存
+var settingDefs = []*settingDef{
+	{Key: SettingSiteTitle, Type: settingTypeString, Group: "site", Label: "站点标题", Public: true, Max: 100, Default: func() any { return "" }},
+	{Key: SettingSiteDescription, Type: settingTypeText, Group: "site", Label: "站点描述", Public: true, Max: 500, Default: func() any { return "" }},
+	{Key: SettingSiteKeywords, Type: settingTypeString, Group: "site", Label: "站点关键词", Description: "多个关键词用英文逗号分隔", Public: true, Max: 200, Default: func() any { return "" }},
+	{Key: SettingSiteLogo, Type: settingTypeString, Group: "site", Label: "站点 Logo", Public: true, Max: 500, Default: func() any { return "" }},
+	{Key: SettingICPNumber, Type: settingTypeString, Group: "site", Label: "ICP 备案号", Public: true, Max: 50, Default: func() any { return "" }},
+	{Key: SettingPoliceNumber, Type: settingTypeString, Group: "site", Label: "公安备案号", Public: true, Max: 50, Default: func() any { return "" }},
+	{Key: SettingFooter, Type: settingTypeText, Group: "site", Label: "页脚内容", Description: "显示在页面底部，支持 HTML", Public: true, Max: 2000, Default: func() any { return "" }},
+	{Key: SettingSiteStartDate, Type: settingTypeDate, Group: "site", Label: "建站日期", Description: "用于计算网站运行天数，格式 2006-01-02", Public: true, Default: func() any { return "" }},
+
+	{Key: SettingCommentEnabled, Type: settingTypeBool, Group: "comment", Label: "开放文章评论", Public: true, Default: func() any { return true }},
+	{Key: SettingGuestbookEnabled, Type: settingTypeBool, Group: "comment", Label: "开放留言板", Public: true, Default: func() any { return true }},
+	{Key: SettingCommentModeration, Type: settingTypeBool, Group: "comment", Label: "评论全部审核", Description: "开启后管理员以外的评论都进入待审核队列", Default: func() any { return false }},
+	{Key: SettingCommentHoldFirstTime, Type: settingTypeBool, Group: "comment", Label: "首次评论审核", Description: "还没有审核通过评论的用户的评论进入待审核队列", Default: func() any { return config.AppConfig.Comment.HoldFirstTime }},
+	{Key: SettingCommentEditWindow, Type: settingTypeInt, Group: "comment", Label: "评论编辑时限（分钟）", Description: "发表后作者可以编辑、删除评论的时间，0 表示不限", Public: true, Min: 0, Max: 10080, Default: func() any { return max(config.AppConfig.Comment.EditWindow, 0) }},
+
+	{Key: SettingAttachmentMaxSize, Type: settingTypeInt, Group: "upload", Label: "附件大小上限（MB）", Public: true, Min: 1, Max: maxUploadSizeLimit, Default: func() any {
+		return positiveOr(config.AppConfig.Upload.AttachmentMaxSize, defaultAttachmentMaxSize)
+	}},
+	{Key: SettingVideoMaxSize, Type: settingTypeInt, Group: "upload", Label: "视频大小上限（MB）", Public: true, Min: 1, Max: maxUploadSizeLimit, Default: func() any {
+		return positiveOr(config.AppConfig.Upload.VideoMaxSize, defaultVideoMaxSize)
+	}},
+
+	{Key: SettingAllowRegistration, Type: settingTypeBool, Group: "feature", Label: "开放注册", Public: true, Default: func() any { return true }},
+	{Key: SettingRequireEmailVerification, Type: settingTypeBool, Group: "feature", Label: "注册需验证邮箱", Public: true, Default: func() any { return config.AppConfig.Register.VerifyEmail }},
+	{Key: SettingFriendLinkApply, Type: settingTypeBool, Group: "feature", Label: "开放友链申请", Public: true, Default: func() any { return true }},
+
+	{Key: SettingThemePrimaryColor, Type: settingTypeColor, Group: "appearance", Label: "主题色", Public: true, Default: func() any { return "#3b82f6" }},
+	{Key: SettingThemeDarkMode, Type: settingTypeSelect, Group: "appearance", Label: "默认配色模式", Description: "访客未手动切换时使用，system 表示跟随系统", Public: true, Options: []string{"system", "light", "dark"}, Default: func() any { return "system" }},
+	{Key: SettingThemeHeroImages, Type: settingTypeList, Group: "appearance", Label: "首页横幅图片", Description: "多张时轮播，图片先通过文件上传接口上传", Public: true, Max: 10, Default: func() any { return []string{} }},
+	{Key: SettingThemeFontFamily, Type: settingTypeSelect, Group: "appearance", Label: "正文字体", Public: true, Options: themeFonts, Default: func() any { return "system" }},
+	{Key: SettingThemeHeadingFont, Type: settingTypeSelect, Group: "appearance", Label: "标题字体", Public: true, Options: themeFonts, Default: func() any { return "system" }},
+	{Key: SettingThemeFontSize, Type: settingTypeInt, Group: "appearance", Label: "正文字号（px）", Public: true, Min: 12, Max: 24, Default: func() any { return 16 }},
+}
+
+// settingDefsByKey 按键索引的内置设置项
+var settingDefsByKey = func() map[string]*settingDef {
+	defs := make(map[string]*settingDef, len(settingDefs))
+	for _, def := range settingDefs {
+		defs[def.Key] = def
+	}
+	return defs
+}()
+
+// SettingUseCase 系统设置业务用例
+type SettingUseCase interface {
+	// List 后台查询全部设置，内置设置项按定义顺序在前，自定义设置在后
+	List() ([]*dto.SettingItem, error)
+	// Public 博客前台可见的设置：公开的内置设置项和全部自定义设置
+	Public() (map[string]any, error)
+	// Update 批量更新设置，值为 null 时删除设置恢复默认值
+	Update(values map[string]any) error
+	// Int 整数设置的当前值
+	Int(key string) int
+	// Theme 外观设置，前端启动时读取
+	Theme() *dto.ThemeResponse
+}
+
+type settingUseCase struct {
+	data *data.Data
+}
+
+// NewSettingUseCase 创建系统设置业务用例
+func NewSettingUseCase(d *data.Data) SettingUseCase {
+	return &settingUseCase{data: d}
+}
+
+// List 后台查询全部设置，内置设置项按定义顺序在前，自定义设置在后
+func (uc *settingUseCase) List() ([]*dto.SettingItem, error) {
+	settings, err := uc.data.SettingRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	stored := make(map[string]*po.Setting, len(settings))
+	for _, setting := range settings {
+		stored[setting.Key] = setting
+	}
+
+	items := make([]*dto.SettingItem, 0, len(settingDefs)+len(settings))
+	for _, def := range settingDefs {
+		item := &dto.SettingItem{
+			Key:         def.Key,
+			Type:        def.Type,
+			Group:       def.Group,
+			Label:       def.Label,
+			Description: def.Description,
+			Public:      def.Public,
+			Default:     def.Default(),
+		}
+		item.Value = item.Default
+		if setting, ok := stored[def.Key]; ok {
+			item.Value = def.parse(setting.Value)
+			item.Customized = true
+			item.UpdatedAt = &setting.UpdatedAt
+		}
+		switch def.Type {
+		case settingTypeInt:
+			lower, upper := def.Min, def.Max
+			item.Min, item.Max = &lower, &upper
+		case settingTypeList:
+			upper := def.Max
+			item.Max = &upper
+		}
+		item.Options = def.Options
+		items = append(items, item)
+	}
+
+	for _, setting := range settings {
+		if _, ok := settingDefsByKey[setting.Key]; ok {
+			continue
+		}
+		items = append(items, &dto.SettingItem{
+			Key:        setting.Key,
+			Type:       settingTypeString,
+			Group:      "custom",
+			Label:      setting.Key,
+			Public:     true,
+			Value:      setting.Value,
+			Default:    "",
+			Customized: true,
+			UpdatedAt:  &setting.UpdatedAt,
+		})
+	}
+	return items, nil
+}
+
+// Public 博客前台可见的设置：公开的内置设置项和全部自定义设置
+func (uc *settingUseCase) Public() (map[string]any, error) {
+	settings, err := uc.data.SettingRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]any, len(settingDefs)+len(settings))
+	for _, def := range settingDefs {
+		if def.Public {
+			values[def.Key] = def.Default()
+		}
+	}
+	for _, setting := range settings {
+		def, ok := settingDefsByKey[setting.Key]
+		switch {
+		case !ok:
+			values[setting.Key] = setting.Value
+		case def.Public:
+			values[setting.Key] = def.parse(setting.Value)
+		}
+	}
+	return values, nil
+}
+
+// Update 批量更新设置，值为 null 时删除设置恢复默认值
+// 先校验全部值，任意一项不合法时不做修改
+func (uc *settingUseCase) Update(values map[string]any) error {
+	var updates []*po.Setting
+	var deletes []string
+	for key, raw := range values {
+		if raw == nil {
+			deletes = append(deletes, key)
+			continue
+		}
+		value, err := normalizeSetting(key, raw)
+		if err != nil {
+			return err
+		}
+		updates = append(updates, &po.Setting{Key: key, Value: value})
+	}
+
+	if len(updates) > 0 {
+		if err := uc.data.SettingRepo.BatchUpdate(updates); err != nil {
+			return err
+		}
+	}
+	for _, key := range deletes {
+		if err := uc.data.SettingRepo.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Int 整数设置的当前值
+func (uc *settingUseCase) Int(key string) int {
+	return settingInt(uc.data, key)
+}
+
+// Theme 外观设置，未设置的项使用默认值
+func (uc *settingUseCase) Theme() *dto.ThemeResponse {
+	heroImages, _ := settingValue(uc.data, SettingThemeHeroImages).([]string)
+	return &dto.ThemeResponse{
+		PrimaryColor: settingString(uc.data, SettingThemePrimaryColor),
+		DarkMode:     settingString(uc.data, SettingThemeDarkMode),
+		HeroImages:   heroImages,
+		FontFamily:   settingString(uc.data, SettingThemeFontFamily),
+		HeadingFont:  settingString(uc.data, SettingThemeHeadingFont),
+		FontSize:     settingInt(uc.data, SettingThemeFontSize),
+	}
+}
+
+// normalizeSetting 校验设置值并转换为保存的字符串，布尔和整数同时接受 JSON 原生类型和字符串
+func normalizeSetting(key string, raw any) (string, error) {
+	def, ok := settingDefsByKey[key]
+	if !ok {
+		if !settingKeyPattern.MatchString(key) {
+			return "", fmt.Errorf("%w: 设置键 %q 只能包含小写字母、数字、下划线和点", ErrSettingInvalid, key)
+		}
+		value, ok := raw.(string)
+		if !ok {
+			return "", fmt.Errorf("%w: 自定义设置 %s 的值必须是字符串", ErrSettingInvalid, key)
+		}
+		return value, nil
+	}
+
+	switch def.Type {
+	case settingTypeBool:
+		switch v := raw.(type) {
+		case bool:
+			return strconv.FormatBool(v), nil
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+				return strconv.FormatBool(b), nil
+			}
+		}
+		return "", fmt.Errorf("%w: %s 必须是布尔值", ErrSettingInvalid, key)
+
+	case settingTypeInt:
+		var n int
+		switch v := raw.(type) {
+		case float64:
+			if v != math.Trunc(v) || math.Abs(v) > math.MaxInt32 {
+				return "", fmt.Errorf("%w: %s 必须是整数", ErrSettingInvalid, key)
+			}
+			n = int(v)
+		case string:
+			parsed, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return "", fmt.Errorf("%w: %s 必须是整数", ErrSettingInvalid, key)
+			}
+			n = parsed
+		default:
+			return "", fmt.Errorf("%w: %s 必须是整数", ErrSettingInvalid, key)
+		}
+		if n < def.Min || n > def.Max {
+			return "", fmt.Errorf("%w: %s 必须在 %d 到 %d 之间", ErrSettingInvalid, key, def.Min, def.Max)
+		}
+		return strconv.Itoa(n), nil
+
+	case settingTypeDate:
+		value, ok := raw.(string)
+		if !ok {
+			return "", fmt.Errorf("%w: %s 必须是日期", ErrSettingInvalid, key)
+		}
+		value = strings.TrimSpace(value)
+		if value != "" {
+			if _, err := time.Parse("2006-01-02", value); err != nil {
+				return "", fmt.Errorf("%w: %s 的格式应为 2006-01-02", ErrSettingInvalid, key)
+			}
+		}
+		return value, nil
+
+	case settingTypeColor:
+		value, ok := raw.(string)
+		value = strings.TrimSpace(value)
+		if !ok || !settingColorPattern.MatchString(value) {
+			return "", fmt.Errorf("%w: %s 必须是 #rrggbb 格式的颜色", ErrSettingInvalid, key)
+		}
+		return strings.ToLower(value), nil
+
+	case settingTypeSelect:
+		value, ok := raw.(string)
+		if !ok || !slices.Contains(def.Options, value) {
+			return "", fmt.Errorf("%w: %s 必须是 %s 之一", ErrSettingInvalid, key, strings.Join(def.Options, "、"))
+		}
+		return value, nil
+
+	case settingTypeList:
+		rawItems, ok := raw.([]any)
+		if !ok {
+			return "", fmt.Errorf("%w: %s 必须是字符串数组", ErrSettingInvalid, key)
+		}
+		items := make([]string, 0, len(rawItems))
+		for _, rawItem := range rawItems {
+			item, ok := rawItem.(string)
+			item = strings.TrimSpace(item)
+			if !ok || !validLinkURL(item) || len(item) > 500 {
+				return "", fmt.Errorf("%w: %s 的每一项必须是 / 开头的站内路径或 http(s) 地址", ErrSettingInvalid, key)
+			}
+			items = append(items, item)
+		}
+		if len(items) > def.Max {
+			return "", fmt.Errorf("%w: %s 最多 %d 项", ErrSettingInvalid, key, def.Max)
+		}
+		encoded, _ := json.Marshal(items)
+		return string(encoded), nil
+
+	default:
+		value, ok := raw.(string)
+		if !ok {
+			return "", fmt.Errorf("%w: %s 必须是字符串", ErrSettingInvalid, key)
+		}
+		if def.Type == settingTypeString {
+			value = strings.TrimSpace(value)
+		}
+		if def.Max > 0 && utf8.RuneCountInString(value) > def.Max {
+			return "", fmt.Errorf("%w: %s 不能超过 %d 个字符", ErrSettingInvalid, key, def.Max)
+		}
+		return value, nil
+	}
+}
+
+// parse 解析保存的设置值，无法解析或超出范围时返回默认值
+func (def *settingDef) parse(value string) any {
+	switch def.Type {
+	case settingTypeBool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+		return def.Default()
+	case settingTypeInt:
+		if n, err := strconv.Atoi(value); err == nil && n >= def.Min && n <= def.Max {
+			return n
+		}
+		return def.Default()
+	case settingTypeColor:
+		if settingColorPattern.MatchString(value) {
+			return value
+		}
+		return def.Default()
+	case settingTypeSelect:
+		if slices.Contains(def.Options, value) {
+			return value
+		}
+		return def.Default()
+	case settingTypeList:
+		var items []string
+		if err := json.Unmarshal([]byte(value), &items); err == nil && items != nil {
+			return items
+		}
+		return def.Default()
+	default:
+		return value
+	}
+}
+
+// settingValue 读取内置设置项的当前值，未设置或读取失败时返回默认值，未注册的键返回 nil
+func settingValue(d *data.Data, key string) any {
+	def, ok := settingDefsByKey[key]
+	if !ok {
+		return nil
+	}
+	setting, err := d.SettingRepo.FindByKey(key)
+	if err != nil {
+		return def.Default()
+	}
+	return def.parse(setting.Value)
+}
+
+// settingBool 读取布尔型系统设置
+func settingBool(d *data.Data, key string) bool {
+	value, _ := settingValue(d, key).(bool)
+	return value
+}
+
+// settingString 读取字符串型系统设置
+func settingString(d *data.Data, key string) string {
+	value, _ := settingValue(d, key).(string)
+	return value
+}
+
+// settingInt 读取整数型系统设置
+func settingInt(d *data.Data, key string) int {
+	value, _ := settingValue(d, key).(int)
+	return value
+}
+
+// positiveOr 配置值未设置（不大于 0）时使用默认值
+func positiveOr(value, fallback int) int {
+	if value <= 0 {
+		return fallback
+	}
+	return value
+}
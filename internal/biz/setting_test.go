@@ -0,0 +1,198 @@
+package biz
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// memorySettingRepo 保存在内存中的设置仓储
+type memorySettingRepo struct {
+	data.SettingRepo
+	values map[string]string
+}
+
+func (r *memorySettingRepo) FindByKey(key string) (*po.Setting, error) {
+	value, ok := r.values[key]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &po.Setting{Key: key, Value: value}, nil
+}
+
+func (r *memorySettingRepo) List() ([]*po.Setting, error) {
+	settings := make([]*po.Setting, 0, len(r.values))
+	for key, value := range r.values {
+		settings = append(settings, &po.Setting{Key: key, Value: value})
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings, nil
+}
+
+func (r *memorySettingRepo) BatchUpdate(settings []*po.Setting) error {
+	for _, setting := range settings {
+		r.values[setting.Key] = setting.Value
+	}
+	return nil
+}
+
+func (r *memorySettingRepo) Delete(key string) error {
+	delete(r.values, key)
+	return nil
+}
+
+func TestSettingUpdateValidatesTypes(t *testing.T) {
+	repo := &memorySettingRepo{values: map[string]string{SettingICPNumber: "京ICP备00000000号"}}
+	uc := NewSettingUseCase(&data.Data{SettingRepo: repo})
+
+	err := uc.Update(map[string]any{
+		SettingSiteTitle:         "  Leaf  ",
+		SettingCommentEnabled:    false,
+		SettingAttachmentMaxSize: float64(20),
+		SettingVideoMaxSize:      "50",
+		SettingICPNumber:         nil,
+		"theme_color":            "#333",
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	want := map[string]string{
+		SettingSiteTitle:         "Leaf",
+		SettingCommentEnabled:    "false",
+		SettingAttachmentMaxSize: "20",
+		SettingVideoMaxSize:      "50",
+		"theme_color":            "#333",
+	}
+	if len(repo.values) != len(want) {
+		t.Fatalf("stored = %v, want %v", repo.values, want)
+	}
+	for key, value := range want {
+		if repo.values[key] != value {
+			t.Errorf("%s = %q, want %q", key, repo.values[key], value)
+		}
+	}
+
+	invalid := []map[string]any{
+		{SettingCommentEnabled: "maybe"},
+		{SettingAttachmentMaxSize: float64(0)},
+		{SettingAttachmentMaxSize: 1.5},
+		{SettingCommentEditWindow: true},
+		{SettingSiteStartDate: "2024/01/01"},
+		{"Bad Key": "x"},
+		{"custom": float64(1)},
+		// 任意一项不合法时整批不保存
+		{SettingSiteTitle: "changed", SettingVideoMaxSize: float64(maxUploadSizeLimit + 1)},
+	}
+	for _, values := range invalid {
+		if err := uc.Update(values); !errors.Is(err, ErrSettingInvalid) {
+			t.Errorf("Update(%v) = %v, want ErrSettingInvalid", values, err)
+		}
+	}
+	if repo.values[SettingSiteTitle] != "Leaf" {
+		t.Fatalf("site_title = %q, an invalid batch must not be saved", repo.values[SettingSiteTitle])
+	}
+}
+
+func TestSettingValuesFallBackToDefaults(t *testing.T) {
+	repo := &memorySettingRepo{values: map[string]string{
+		SettingCommentModeration: "true",
+		SettingAttachmentMaxSize: "not a number",
+		SettingFooter:            "<p>footer</p>",
+		"theme_color":            "#333",
+	}}
+	d := &data.Data{SettingRepo: repo}
+	uc := NewSettingUseCase(d)
+
+	if !settingBool(d, SettingCommentModeration) || !settingBool(d, SettingCommentEnabled) {
+		t.Fatal("stored and default bool settings were not read")
+	}
+	if got := uc.Int(SettingAttachmentMaxSize); got != defaultAttachmentMaxSize {
+		t.Fatalf("attachment_max_size = %d, want the default for an unparsable value", got)
+	}
+
+	public, err := uc.Public()
+	if err != nil {
+		t.Fatalf("Public: %v", err)
+	}
+	if _, ok := public[SettingCommentModeration]; ok {
+		t.Error("Public exposed a non-public setting")
+	}
+	if public[SettingFooter] != "<p>footer</p>" || public[SettingCommentEnabled] != true || public["theme_color"] != "#333" {
+		t.Errorf("Public = %v", public)
+	}
+
+	items, err := uc.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(items) != len(settingDefs)+1 || items[len(items)-1].Key != "theme_color" {
+		t.Fatalf("List returned %d items, want the built-in settings followed by custom ones", len(items))
+	}
+	for _, item := range items {
+		if item.Key == SettingCommentModeration && (item.Value != true || !item.Customized || item.Default != false) {
+			t.Errorf("comment_moderation item = %+v", item)
+		}
+	}
+}
+
+func TestSettingAppearance(t *testing.T) {
+	repo := &memorySettingRepo{values: map[string]string{}}
+	uc := NewSettingUseCase(&data.Data{SettingRepo: repo})
+
+	theme := uc.Theme()
+	if theme.PrimaryColor != "#3b82f6" || theme.DarkMode != "system" || theme.FontSize != 16 || theme.HeroImages == nil {
+		t.Fatalf("default theme = %+v", theme)
+	}
+
+	err := uc.Update(map[string]any{
+		SettingThemePrimaryColor: " #FF6600 ",
+		SettingThemeDarkMode:     "dark",
+		SettingThemeHeroImages:   []any{"/uploads/hero.jpg", " https://cdn.example.com/a.png "},
+		SettingThemeFontFamily:   "serif",
+		SettingThemeFontSize:     float64(18),
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	theme = uc.Theme()
+	if theme.PrimaryColor != "#ff6600" || theme.DarkMode != "dark" || theme.FontFamily != "serif" || theme.HeadingFont != "system" || theme.FontSize != 18 {
+		t.Errorf("theme = %+v", theme)
+	}
+	if len(theme.HeroImages) != 2 || theme.HeroImages[1] != "https://cdn.example.com/a.png" {
+		t.Errorf("hero_images = %v", theme.HeroImages)
+	}
+
+	tooMany := make([]any, 11)
+	for i := range tooMany {
+		tooMany[i] = "/uploads/hero.jpg"
+	}
+	invalid := []map[string]any{
+		{SettingThemePrimaryColor: "red"},
+		{SettingThemePrimaryColor: "#fff"},
+		{SettingThemeDarkMode: "auto"},
+		{SettingThemeFontFamily: "Comic Sans"},
+		{SettingThemeFontSize: float64(40)},
+		{SettingThemeHeroImages: "/uploads/hero.jpg"},
+		{SettingThemeHeroImages: []any{"javascript:alert(1)"}},
+		{SettingThemeHeroImages: []any{"//evil.example.com/a.png"}},
+		{SettingThemeHeroImages: tooMany},
+	}
+	for _, values := range invalid {
+		if err := uc.Update(values); !errors.Is(err, ErrSettingInvalid) {
+			t.Errorf("Update(%v) = %v, want ErrSettingInvalid", values, err)
+		}
+	}
+
+	// 保存的值被手工改坏时回退到默认值
+	repo.values[SettingThemePrimaryColor] = "blue"
+	repo.values[SettingThemeDarkMode] = "auto"
+	repo.values[SettingThemeHeroImages] = "not json"
+	theme = uc.Theme()
+	if theme.PrimaryColor != "#3b82f6" || theme.DarkMode != "system" || len(theme.HeroImages) != 0 || theme.HeroImages == nil {
+		t.Errorf("theme with corrupt values = %+v", theme)
+	}
+}
@@ -0,0 +1,226 @@
+package biz
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+	"github.com/ydcloud-dy/leaf-api/pkg/sitemap"
+)
+
+// 站点地图缓存：所有文件以 JSON（文件名 -> XML）保存在一个 key 中
+// 内容变更时删除缓存，下次访问或定时任务重新生成
+const (
+	sitemapCacheKey    = "sitemap:files"
+	sitemapCacheExpire = 24 * time.Hour
+)
+
+// 站点地图默认的前台地址格式
+const (
+	defaultArticlePath  = "/article/{slug}"
+	defaultCategoryPath = "/category/{name}"
+	defaultTagPath      = "/tag/{name}"
+	defaultChapterPath  = "/notes/{tag}?chapter={id}"
+	defaultPagePath     = "/page/{slug}"
+)
+
+// ErrSitemapDisabled 未配置前台地址，不生成站点地图
+var ErrSitemapDisabled = errors.New("站点地图未启用")
+
+// ErrSitemapNotFound 站点地图文件不存在
+var ErrSitemapNotFound = errors.New("站点地图文件不存在")
+
+// SitemapUseCase 站点地图业务用例接口
+type SitemapUseCase interface {
+	// File 获取站点地图文件内容，name 为 sitemap.xml 或分片文件名
+	File(name string) ([]byte, error)
+	// Regenerate 重新生成站点地图并写入缓存
+	Regenerate() error
+}
+
+// sitemapUseCase 站点地图业务用例实现
+type sitemapUseCase struct {
+	data *data.Data
+}
+
+// NewSitemapUseCase 创建站点地图业务用例
+func NewSitemapUseCase(d *data.Data) SitemapUseCase {
+	return &sitemapUseCase{data: d}
+}
+
+// InvalidateSitemap 内容变更后删除站点地图缓存，下次访问时重新生成
+func InvalidateSitemap() {
+	if redis.Client == nil {
+		return
+	}
+	if err := redis.Del(sitemapCacheKey); err != nil {
+		logger.Warn("Failed to invalidate sitemap cache: ", err)
+	}
+}
+
+// File 获取站点地图文件，优先读取 Redis 缓存，未启用 Redis 时每次重新生成
+func (uc *sitemapUseCase) File(name string) ([]byte, error) {
+	if config.AppConfig.Sitemap.SiteURL == "" {
+		return nil, ErrSitemapDisabled
+	}
+
+	files, err := uc.cachedFiles()
+	if err != nil {
+		return nil, err
+	}
+	content, ok := files[name]
+	if !ok {
+		return nil, ErrSitemapNotFound
+	}
+	return []byte(content), nil
+}
+
+// Regenerate 重新生成站点地图并写入缓存
+func (uc *sitemapUseCase) Regenerate() error {
+	if config.AppConfig.Sitemap.SiteURL == "" {
+		return nil
+	}
+	_, err := uc.generate()
+	return err
+}
+
+// cachedFiles 读取缓存的站点地图，缓存不存在时重新生成
+func (uc *sitemapUseCase) cachedFiles() (map[string]string, error) {
+	if redis.Client != nil {
+		if cached, err := redis.Get(sitemapCacheKey); err == nil {
+			var files map[string]string
+			if err := json.Unmarshal([]byte(cached), &files); err == nil {
+				return files, nil
+			}
+		}
+	}
+	return uc.generate()
+}
+
+// generate 生成站点地图并写入缓存
+func (uc *sitemapUseCase) generate() (map[string]string, error) {
+	urls, err := uc.collectURLs()
+	if err != nil {
+		return nil, err
+	}
+
+	siteURL := strings.TrimRight(config.AppConfig.Sitemap.SiteURL, "/")
+	built, err := sitemap.Build(urls, siteURL+"/sitemaps")
+	if err != nil {
+		return nil, errors.New("生成站点地图失败")
+	}
+
+	files := make(map[string]string, len(built))
+	for name, content := range built {
+		files[name] = string(content)
+	}
+
+	if redis.Client != nil {
+		if b, err := json.Marshal(files); err == nil {
+			redis.SetWithExpire(sitemapCacheKey, string(b), sitemapCacheExpire)
+		}
+	}
+	return files, nil
+}
+
+// collectURLs 收集已发布文章、分类、标签、章节和独立页面的前台地址
+// 分类的 lastmod 取分类本身与其下文章更新时间的较大值
+func (uc *sitemapUseCase) collectURLs() ([]sitemap.URL, error) {
+	cfg := config.AppConfig.Sitemap
+	siteURL := strings.TrimRight(cfg.SiteURL, "/")
+
+	articles, err := uc.data.ArticleRepo.PublicOnly().ListSitemap()
+	if err != nil {
+		return nil, errors.New("查询文章失败")
+	}
+	categories, err := uc.data.CategoryRepo.List()
+	if err != nil {
+		return nil, errors.New("查询分类失败")
+	}
+	tags, err := uc.data.TagRepo.List()
+	if err != nil {
+		return nil, errors.New("查询标签失败")
+	}
+	var chapters []po.Chapter
+	if err := uc.data.GetDB().Order("tag_id ASC, sort ASC, id ASC").Find(&chapters).Error; err != nil {
+		return nil, errors.New("查询章节失败")
+	}
+	pages, err := uc.data.PageRepo.ListPublished()
+	if err != nil {
+		return nil, errors.New("查询页面失败")
+	}
+
+	urls := make([]sitemap.URL, 0, len(articles)+len(categories)+len(tags)+len(chapters)+len(pages))
+	categoryUpdated := make(map[uint]time.Time, len(categories))
+	for _, article := range articles {
+		urls = append(urls, sitemap.URL{
+			Loc:     frontendArticleURL(article),
+			LastMod: article.UpdatedAt,
+		})
+		if article.UpdatedAt.After(categoryUpdated[article.CategoryID]) {
+			categoryUpdated[article.CategoryID] = article.UpdatedAt
+		}
+	}
+
+	for _, category := range categories {
+		lastMod := category.UpdatedAt
+		if categoryUpdated[category.ID].After(lastMod) {
+			lastMod = categoryUpdated[category.ID]
+		}
+		urls = append(urls, sitemap.URL{
+			Loc:     siteURL + expandPath(pathOrDefault(cfg.CategoryPath, defaultCategoryPath), "{name}", category.Name, category.ID),
+			LastMod: lastMod,
+		})
+	}
+
+	tagNames := make(map[uint]string, len(tags))
+	for _, tag := range tags {
+		tagNames[tag.ID] = tag.Name
+		urls = append(urls, sitemap.URL{
+			Loc:     siteURL + expandPath(pathOrDefault(cfg.TagPath, defaultTagPath), "{name}", tag.Name, tag.ID),
+			LastMod: tag.UpdatedAt,
+		})
+	}
+
+	for _, chapter := range chapters {
+		tagName, ok := tagNames[chapter.TagID]
+		if !ok {
+			continue
+		}
+		urls = append(urls, sitemap.URL{
+			Loc:     siteURL + expandPath(pathOrDefault(cfg.ChapterPath, defaultChapterPath), "{tag}", tagName, chapter.ID),
+			LastMod: chapter.UpdatedAt,
+		})
+	}
+
+	for _, page := range pages {
+		urls = append(urls, sitemap.URL{
+			Loc:     siteURL + expandPath(pathOrDefault(cfg.PagePath, defaultPagePath), "{slug}", page.Slug, page.ID),
+			LastMod: page.UpdatedAt,
+		})
+	}
+
+	return urls, nil
+}
+
+// pathOrDefault 未配置地址格式时使用默认值
+func pathOrDefault(path, fallback string) string {
+	if path == "" {
+		return fallback
+	}
+	return path
+}
+
+// expandPath 替换地址格式中的名称占位符（URL 编码）和 {id}
+func expandPath(path, placeholder, value string, id uint) string {
+	path = strings.ReplaceAll(path, placeholder, url.PathEscape(value))
+	return strings.ReplaceAll(path, "{id}", strconv.FormatUint(uint64(id), 10))
+}
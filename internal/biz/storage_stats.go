@@ -0,0 +1,139 @@
+package biz
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/oss"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+// 存储用量统计缓存，后台任务定期刷新；未启用 Redis 时缓存在进程内
+const (
+	storageStatsCacheKey    = "storage:stats"
+	storageStatsCacheExpire = 24 * time.Hour
+	storageStatsMonths      = 12
+)
+
+// rootFolder 不在任何目录下的对象归入的目录名
+const rootFolder = "/"
+
+// 未启用 Redis 时的进程内缓存，后台任务和接口共用
+var (
+	storageStatsMu     sync.Mutex
+	storageStatsCached *dto.StorageStats
+)
+
+// StorageStatsUseCase 存储用量统计业务用例
+type StorageStatsUseCase interface {
+	// Stats 获取缓存的统计结果，缓存不存在时立即扫描
+	Stats() (*dto.StorageStats, error)
+	// Refresh 扫描存储中的全部对象，重新统计并写入缓存
+	Refresh() (*dto.StorageStats, error)
+}
+
+type storageStatsUseCase struct{}
+
+// NewStorageStatsUseCase 创建存储用量统计业务用例
+func NewStorageStatsUseCase() StorageStatsUseCase {
+	return &storageStatsUseCase{}
+}
+
+// Stats 优先读取 Redis 缓存，存储驱动变化后的缓存视为失效
+func (uc *storageStatsUseCase) Stats() (*dto.StorageStats, error) {
+	if redis.Client != nil {
+		if cached, err := redis.Get(storageStatsCacheKey); err == nil {
+			var stats dto.StorageStats
+			if err := json.Unmarshal([]byte(cached), &stats); err == nil && stats.Driver == oss.Driver() {
+				return &stats, nil
+			}
+		}
+	} else {
+		storageStatsMu.Lock()
+		cached := storageStatsCached
+		storageStatsMu.Unlock()
+		if cached != nil && cached.Driver == oss.Driver() && time.Since(cached.ScannedAt) < storageStatsCacheExpire {
+			return cached, nil
+		}
+	}
+	return uc.Refresh()
+}
+
+// Refresh 扫描存储并写入缓存
+func (uc *storageStatsUseCase) Refresh() (*dto.StorageStats, error) {
+	objects, err := oss.ListStoredObjects()
+	if err != nil {
+		logger.Error("Failed to list stored objects: ", err)
+		return nil, errors.New("扫描存储失败")
+	}
+	stats := buildStorageStats(oss.Driver(), objects, time.Now())
+
+	if redis.Client != nil {
+		if b, err := json.Marshal(stats); err == nil {
+			if err := redis.SetWithExpire(storageStatsCacheKey, string(b), storageStatsCacheExpire); err != nil {
+				logger.Warn("Failed to cache storage stats: ", err)
+			}
+		}
+	} else {
+		storageStatsMu.Lock()
+		storageStatsCached = stats
+		storageStatsMu.Unlock()
+	}
+	return stats, nil
+}
+
+// buildStorageStats 按顶层目录和月份汇总对象，月度增长只保留最近 storageStatsMonths 个月（含没有新增的月份）
+func buildStorageStats(driver string, objects []oss.ObjectInfo, now time.Time) *dto.StorageStats {
+	stats := &dto.StorageStats{Driver: driver, ScannedAt: now}
+
+	folders := make(map[string]*dto.StorageFolderUsage)
+	monthly := make([]dto.StorageMonthlyGrowth, storageStatsMonths)
+	monthIndex := make(map[string]int, storageStatsMonths)
+	firstMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, 1-storageStatsMonths, 0)
+	for i := range monthly {
+		month := firstMonth.AddDate(0, i, 0).Format("2006-01")
+		monthly[i].Month = month
+		monthIndex[month] = i
+	}
+
+	for _, object := range objects {
+		stats.TotalSize += object.Size
+		stats.ObjectCount++
+
+		name := rootFolder
+		if folder, _, found := strings.Cut(object.Key, "/"); found {
+			name = folder
+		}
+		usage, ok := folders[name]
+		if !ok {
+			usage = &dto.StorageFolderUsage{Folder: name}
+			folders[name] = usage
+		}
+		usage.Size += object.Size
+		usage.ObjectCount++
+
+		if i, ok := monthIndex[object.LastModified.In(now.Location()).Format("2006-01")]; ok {
+			monthly[i].Size += object.Size
+			monthly[i].ObjectCount++
+		}
+	}
+
+	stats.Folders = make([]dto.StorageFolderUsage, 0, len(folders))
+	for _, usage := range folders {
+		stats.Folders = append(stats.Folders, *usage)
+	}
+	sort.Slice(stats.Folders, func(i, j int) bool {
+		if stats.Folders[i].Size != stats.Folders[j].Size {
+			return stats.Folders[i].Size > stats.Folders[j].Size
+		}
+		return stats.Folders[i].Folder < stats.Folders[j].Folder
+	})
+	stats.Monthly = monthly
+	return stats
+}
@@ -0,0 +1,256 @@
+package biz
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/jwt"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+// 刷新令牌默认有效期
+const (
+	defaultAccessExpire  = 15  // 分钟
+	defaultRefreshExpire = 720 // 小时
+)
+
+// 刷新令牌格式为 {家族ID}.{随机串}，一次登录签发的令牌及其轮换出的后续令牌属于同一家族
+// refresh:token:{摘要} -> 家族ID，使用一次后删除；refresh:family:{家族ID} -> 家族信息，删除即吊销整个家族
+// refresh:user:{用户ID} -> 用户的全部家族ID，用于吊销用户的所有会话
+const (
+	refreshTokenKey  = "refresh:token:%s"
+	refreshFamilyKey = "refresh:family:%s"
+	refreshUserKey   = "refresh:user:%d"
+)
+
+// ErrRefreshTokenInvalid 刷新令牌无效、已使用或已过期
+var ErrRefreshTokenInvalid = errors.New("刷新令牌无效或已过期，请重新登录")
+
+// refreshFamily 刷新令牌家族，记录签发访问令牌所需的信息，家族即一个登录设备上的会话
+type refreshFamily struct {
+	UserID     uint      `json:"user_id"`
+	Role       string    `json:"role"` // 登录时的角色，前台登录固定为 user，后台登录为当时的管理员角色
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+// issueTokens 签发登录令牌：启用 Redis 时为短期访问令牌加刷新令牌，否则为 jwt.expire 小时的访问令牌
+func issueTokens(user *po.User, role string, client *dto.ClientInfo) (*dto.TokenResponse, error) {
+	if redis.Client == nil {
+		token, err := jwt.GenerateToken(user.ID, user.Username, role)
+		if err != nil {
+			return nil, errors.New("生成 Token 失败")
+		}
+		return &dto.TokenResponse{Token: token, ExpiresIn: int64(config.AppConfig.JWT.Expire) * 3600}, nil
+	}
+
+	family, err := randomHex(16)
+	if err != nil {
+		return nil, errors.New("生成 Token 失败")
+	}
+	now := time.Now()
+	info := &refreshFamily{UserID: user.ID, Role: role, CreatedAt: now, LastUsedAt: now}
+	if client != nil {
+		info.IP = client.IP
+		info.UserAgent = client.UserAgent
+	}
+	value, _ := json.Marshal(info)
+	if err := redis.SetWithExpire(fmt.Sprintf(refreshFamilyKey, family), string(value), refreshTTL()); err != nil {
+		return nil, errors.New("生成 Token 失败")
+	}
+	if err := redis.SAdd(fmt.Sprintf(refreshUserKey, user.ID), family); err != nil {
+		return nil, errors.New("生成 Token 失败")
+	}
+	return rotateTokens(user, role, family, info)
+}
+
+// rotateTokens 在家族中签发新的访问令牌和刷新令牌，保存家族信息并延长家族有效期
+// 家族只在仍然存在时更新，避免并发吊销后被重新写入
+func rotateTokens(user *po.User, role, family string, info *refreshFamily) (*dto.TokenResponse, error) {
+	value, _ := json.Marshal(info)
+	ok, err := redis.SetXX(fmt.Sprintf(refreshFamilyKey, family), string(value), refreshTTL())
+	if err != nil {
+		return nil, errors.New("生成 Token 失败")
+	}
+	if !ok {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	accessTTL := time.Duration(config.AppConfig.JWT.AccessExpire) * time.Minute
+	if accessTTL <= 0 {
+		accessTTL = defaultAccessExpire * time.Minute
+	}
+	token, err := jwt.GenerateSessionToken(user.ID, user.Username, role, family, accessTTL)
+	if err != nil {
+		return nil, errors.New("生成 Token 失败")
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, errors.New("生成 Token 失败")
+	}
+	refreshToken := family + "." + secret
+	if err := redis.SetWithExpire(fmt.Sprintf(refreshTokenKey, tokenDigest(refreshToken)), family, refreshTTL()); err != nil {
+		return nil, errors.New("生成 Token 失败")
+	}
+	_ = redis.Expire(fmt.Sprintf(refreshUserKey, user.ID), refreshTTL())
+
+	return &dto.TokenResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(accessTTL / time.Second),
+	}, nil
+}
+
+// refreshTokens 使用刷新令牌换取新的令牌，旧刷新令牌随即失效
+// 已使用过的刷新令牌再次出现说明令牌可能已泄露，吊销整个家族，持有者需要重新登录
+func refreshTokens(d *data.Data, refreshToken string, client *dto.ClientInfo) (*dto.TokenResponse, error) {
+	if redis.Client == nil {
+		return nil, ErrRefreshTokenInvalid
+	}
+	family, _, ok := strings.Cut(refreshToken, ".")
+	if !ok || family == "" {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	owner, err := redis.GetDel(fmt.Sprintf(refreshTokenKey, tokenDigest(refreshToken)))
+	if err != nil || owner != family {
+		if info, err := loadRefreshFamily(family); err == nil {
+			logger.Warn("Refresh token reuse detected, revoking token family ", family)
+			revokeRefreshFamily(family, info.UserID)
+		}
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	info, err := loadRefreshFamily(family)
+	if err != nil {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	// 按当前账号状态和角色续期：账号被禁用或后台账号不再是管理员时吊销家族
+	user, err := d.UserRepo.FindByID(info.UserID)
+	if err != nil || user.Status != po.UserStatusActive {
+		revokeRefreshFamily(family, info.UserID)
+		return nil, ErrRefreshTokenInvalid
+	}
+	role, ok := sessionRole(info.Role, user.Role)
+	if !ok {
+		revokeRefreshFamily(family, info.UserID)
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	// 记录会话最近一次使用的时间和地址，用于会话列表展示
+	info.LastUsedAt = time.Now()
+	if client != nil && client.IP != "" {
+		info.IP = client.IP
+	}
+	return rotateTokens(user, role, family, info)
+}
+
+// sessionRole 续期时访问令牌使用的角色
+// 前台登录的会话始终为 user；后台登录的会话使用用户当前的角色，降级为普通用户后不能再续期
+func sessionRole(loginRole, currentRole string) (string, bool) {
+	if loginRole == "user" {
+		return "user", true
+	}
+	if !ConsoleRole(currentRole) {
+		return "", false
+	}
+	return currentRole, true
+}
+
+// loadRefreshFamily 读取家族信息
+func loadRefreshFamily(family string) (*refreshFamily, error) {
+	value, err := redis.Get(fmt.Sprintf(refreshFamilyKey, family))
+	if err != nil {
+		return nil, err
+	}
+	var info refreshFamily
+	if err := json.Unmarshal([]byte(value), &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// revokeRefreshFamily 吊销家族，家族中尚未使用的刷新令牌在换取时因家族不存在而失效，
+// 家族签发的访问令牌同时失效
+func revokeRefreshFamily(family string, userID uint) {
+	_ = redis.Del(fmt.Sprintf(refreshFamilyKey, family))
+	_ = redis.SRem(fmt.Sprintf(refreshUserKey, userID), family)
+	if err := jwt.RevokeSession(family); err != nil {
+		logger.Error("Failed to revoke access tokens of session ", family, ": ", err)
+	}
+}
+
+// revokeUserRefreshTokens 吊销用户的全部刷新令牌家族（修改密码、重置密码、禁用账号等场景）
+func revokeUserRefreshTokens(userID uint) {
+	if redis.Client == nil {
+		return
+	}
+	userKey := fmt.Sprintf(refreshUserKey, userID)
+	families, err := redis.SMembers(userKey)
+	if err != nil {
+		logger.Error("Failed to list refresh token families of user ", userID, ": ", err)
+		return
+	}
+	for _, family := range families {
+		_ = redis.Del(fmt.Sprintf(refreshFamilyKey, family))
+	}
+	_ = redis.Del(userKey)
+}
+
+// revokeUserSessions 吊销用户的全部会话：刷新令牌家族和已签发的访问令牌
+func revokeUserSessions(userID uint) {
+	revokeUserRefreshTokens(userID)
+	if err := jwt.RevokeUserTokens(userID); err != nil {
+		logger.Error("Failed to revoke access tokens of user ", userID, ": ", err)
+	}
+}
+
+// revokeRefreshToken 吊销刷新令牌所在的家族
+func revokeRefreshToken(refreshToken string) {
+	if redis.Client == nil || refreshToken == "" {
+		return
+	}
+	family, _, ok := strings.Cut(refreshToken, ".")
+	if !ok {
+		return
+	}
+	// 只有持有家族中令牌的调用方才能吊销
+	if owner, err := redis.Get(fmt.Sprintf(refreshTokenKey, tokenDigest(refreshToken))); err != nil || owner != family {
+		return
+	}
+	_ = redis.Del(fmt.Sprintf(refreshTokenKey, tokenDigest(refreshToken)))
+	if info, err := loadRefreshFamily(family); err == nil {
+		revokeRefreshFamily(family, info.UserID)
+	}
+}
+
+func refreshTTL() time.Duration {
+	hours := config.AppConfig.JWT.RefreshExpire
+	if hours <= 0 {
+		hours = defaultRefreshExpire
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// randomHex 生成 n 字节的随机数并以十六进制表示
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,219 @@
+package biz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/jwt"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+// stubUserRepo 内存中的用户仓储
+type stubUserRepo struct {
+	data.UserRepo
+	users map[uint]*po.User
+}
+
+func (r *stubUserRepo) FindByID(id uint) (*po.User, error) {
+	if user, ok := r.users[id]; ok {
+		copied := *user
+		return &copied, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *stubUserRepo) FindByUsername(username string) (*po.User, error) {
+	for _, user := range r.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *stubUserRepo) Create(user *po.User) error {
+	user.ID = uint(len(r.users) + 100)
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *stubUserRepo) Update(user *po.User) error {
+	copied := *user
+	r.users[user.ID] = &copied
+	return nil
+}
+
+// setupTokenRedis 使用内存 Redis 替换全局客户端
+func setupTokenRedis(t *testing.T) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	redis.Client = goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		redis.Client.Close()
+		redis.Client = nil
+	})
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	setupTokenRedis(t)
+	user := &po.User{ID: 1, Username: "alice", Role: "user", Status: po.UserStatusActive}
+	d := &data.Data{UserRepo: &stubUserRepo{users: map[uint]*po.User{1: user}}}
+
+	issued, err := issueTokens(user, "user", nil)
+	if err != nil {
+		t.Fatalf("issueTokens: %v", err)
+	}
+	other, err := issueTokens(user, "user", nil)
+	if err != nil {
+		t.Fatalf("issueTokens: %v", err)
+	}
+
+	rotated, err := refreshTokens(d, issued.RefreshToken, nil)
+	if err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "reuse is rejected", token: issued.RefreshToken, wantErr: true},
+		{name: "rotated token is revoked with its family", token: rotated.RefreshToken, wantErr: true},
+		{name: "other sessions are untouched", token: other.RefreshToken},
+		{name: "malformed token", token: "not-a-token", wantErr: true},
+		{name: "unknown family", token: "0123.4567", wantErr: true},
+	}
+
+	// 按顺序执行，后面的用例依赖前面用例产生的吊销
+	for _, tt := range tests {
+		resp, err := refreshTokens(d, tt.token, nil)
+		if tt.wantErr {
+			if !errors.Is(err, ErrRefreshTokenInvalid) {
+				t.Fatalf("%s: got err %v, want ErrRefreshTokenInvalid", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if resp.Token == "" || resp.RefreshToken == "" || resp.RefreshToken == tt.token {
+			t.Fatalf("%s: tokens were not rotated: %+v", tt.name, resp)
+		}
+	}
+}
+
+func TestRefreshTokenUsesCurrentAccount(t *testing.T) {
+	tests := []struct {
+		name      string
+		loginRole string
+		user      po.User
+		wantRole  string
+		wantErr   bool
+	}{
+		{name: "front session stays user", loginRole: "user", user: po.User{Role: "super_admin", Status: po.UserStatusActive}, wantRole: "user"},
+		{name: "admin session picks up promotion", loginRole: "admin", user: po.User{Role: "super_admin", Status: po.UserStatusActive}, wantRole: "super_admin"},
+		{name: "admin session ends after demotion", loginRole: "admin", user: po.User{Role: "user", Status: po.UserStatusActive}, wantErr: true},
+		{name: "banned account", loginRole: "user", user: po.User{Role: "user", Status: po.UserStatusBanned}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTokenRedis(t)
+			login := &po.User{ID: 7, Username: "bob", Role: tt.loginRole, Status: po.UserStatusActive}
+			current := tt.user
+			current.ID, current.Username = login.ID, login.Username
+			d := &data.Data{UserRepo: &stubUserRepo{users: map[uint]*po.User{7: &current}}}
+
+			issued, err := issueTokens(login, tt.loginRole, nil)
+			if err != nil {
+				t.Fatalf("issueTokens: %v", err)
+			}
+			resp, err := refreshTokens(d, issued.RefreshToken, nil)
+			if tt.wantErr {
+				if !errors.Is(err, ErrRefreshTokenInvalid) {
+					t.Fatalf("got err %v, want ErrRefreshTokenInvalid", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			claims, err := jwt.ParseToken(resp.Token)
+			if err != nil {
+				t.Fatalf("ParseToken: %v", err)
+			}
+			if claims.Role != tt.wantRole {
+				t.Errorf("role = %q, want %q", claims.Role, tt.wantRole)
+			}
+		})
+	}
+}
+
+func TestRevokeUserRefreshTokens(t *testing.T) {
+	setupTokenRedis(t)
+	user := &po.User{ID: 3, Username: "carol", Role: "user", Status: po.UserStatusActive}
+	d := &data.Data{UserRepo: &stubUserRepo{users: map[uint]*po.User{3: user}}}
+
+	var tokens []string
+	for i := 0; i < 3; i++ {
+		issued, err := issueTokens(user, "user", nil)
+		if err != nil {
+			t.Fatalf("issueTokens: %v", err)
+		}
+		tokens = append(tokens, issued.RefreshToken)
+	}
+
+	revokeUserRefreshTokens(user.ID)
+
+	for i, token := range tokens {
+		if _, err := refreshTokens(d, token, nil); !errors.Is(err, ErrRefreshTokenInvalid) {
+			t.Errorf("session %d: got err %v, want ErrRefreshTokenInvalid", i, err)
+		}
+	}
+}
+
+func TestRevokeUserSessionsRevokesAccessTokens(t *testing.T) {
+	setupTokenRedis(t)
+	alice := &po.User{ID: 4, Username: "alice", Role: "user", Status: po.UserStatusActive}
+	bob := &po.User{ID: 5, Username: "bob", Role: "user", Status: po.UserStatusActive}
+
+	aliceTokens, err := issueTokens(alice, "user", nil)
+	if err != nil {
+		t.Fatalf("issueTokens: %v", err)
+	}
+	bobTokens, err := issueTokens(bob, "user", nil)
+	if err != nil {
+		t.Fatalf("issueTokens: %v", err)
+	}
+
+	revokeUserSessions(alice.ID)
+
+	tests := []struct {
+		name        string
+		token       string
+		wantRevoked bool
+	}{
+		{name: "revoked user", token: aliceTokens.Token, wantRevoked: true},
+		{name: "other user", token: bobTokens.Token},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := jwt.ParseToken(tt.token)
+			if err != nil {
+				t.Fatalf("ParseToken: %v", err)
+			}
+			if got := jwt.Revoked(claims); got != tt.wantRevoked {
+				t.Errorf("Revoked = %v, want %v", got, tt.wantRevoked)
+			}
+		})
+	}
+	d := &data.Data{UserRepo: &stubUserRepo{users: map[uint]*po.User{4: alice}}}
+	if _, err := refreshTokens(d, aliceTokens.RefreshToken, nil); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Errorf("refresh after revoke: got err %v, want ErrRefreshTokenInvalid", err)
+	}
+}
@@ -0,0 +1,411 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/png"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/jwt"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+	"gorm.io/gorm"
+)
+
+// 两步验证默认值
+const (
+	defaultTwoFactorIssuer = "Leaf Blog"
+	twoFactorChallengeTTL  = 5 * time.Minute
+	twoFactorMaxAttempts   = 5  // 每个两步验证令牌最多提交的验证码次数
+	totpPeriod             = 30 // 秒
+	recoveryCodeCount      = 10
+)
+
+// twoFactorAttemptKey 两步验证令牌的验证码提交次数，2fa:attempts:{令牌ID}
+const twoFactorAttemptKey = "2fa:attempts:%s"
+
+var (
+	// ErrTwoFactorChallengeInvalid 两步验证令牌无效、已过期或尝试次数过多
+	ErrTwoFactorChallengeInvalid = errors.New("验证已过期，请重新登录")
+	// ErrTwoFactorCodeInvalid 验证码或恢复码错误
+	ErrTwoFactorCodeInvalid = errors.New("验证码错误")
+	// ErrTwoFactorNotEnabled 尚未绑定验证器或尚未启用两步验证
+	ErrTwoFactorNotEnabled = errors.New("尚未启用两步验证")
+	// ErrTwoFactorAlreadyEnabled 已启用两步验证
+	ErrTwoFactorAlreadyEnabled = errors.New("已启用两步验证")
+	// ErrTwoFactorRequired 当前角色必须启用两步验证
+	ErrTwoFactorRequired = errors.New("当前角色必须启用两步验证，不能关闭")
+)
+
+// twoFactorChallenge 密码校验通过后检查是否需要两步验证，需要时返回携带两步验证令牌的登录结果
+// 已启用两步验证或角色要求两步验证（尚未绑定时先绑定）的账号不直接签发 Token
+func (uc *authUseCase) twoFactorChallenge(user *po.User) (*dto.LoginResponse, error) {
+	tf, err := uc.loadTwoFactor(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	enabled := tf != nil && tf.Enabled
+	if !enabled && !twoFactorEnforced(user.Role) {
+		return nil, nil
+	}
+
+	nonce, err := randomHex(16)
+	if err != nil {
+		return nil, errors.New("生成两步验证令牌失败")
+	}
+	token, err := jwt.GenerateTwoFactorToken(user.ID, nonce, twoFactorChallengeTTL)
+	if err != nil {
+		return nil, errors.New("生成两步验证令牌失败")
+	}
+	return &dto.LoginResponse{
+		TwoFactorRequired: true,
+		TwoFactorSetup:    !enabled,
+		ChallengeToken:    token,
+	}, nil
+}
+
+// VerifyTwoFactor 登录时提交两步验证码，验证通过后签发 Token，记录登录日志
+// 尚未绑定验证器的账号提交的是绑定时的验证码，验证通过即启用两步验证并返回恢复码
+func (uc *authUseCase) VerifyTwoFactor(ctx context.Context, req *dto.TwoFactorVerifyRequest, client *dto.ClientInfo) (*dto.LoginResponse, error) {
+	user, resp, err := uc.verifyTwoFactor(req, client)
+	// 两步验证令牌无效时无法确定用户，不记录
+	if user != nil {
+		recordLogin(ctx, uc.data, user, user.Username, po.LoginChannelAdmin, client, err)
+	}
+	return resp, err
+}
+
+// verifyTwoFactor 校验两步验证码并签发 Token，两步验证令牌无效时返回的用户为 nil
+func (uc *authUseCase) verifyTwoFactor(req *dto.TwoFactorVerifyRequest, client *dto.ClientInfo) (*po.User, *dto.LoginResponse, error) {
+	user, claims, err := uc.challengeUser(req.ChallengeToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 启用 Redis 时限制同一令牌的尝试次数，超过后需要重新输入密码
+	if redis.Client != nil {
+		n, err := redis.IncrWithExpire(fmt.Sprintf(twoFactorAttemptKey, claims.ID), twoFactorChallengeTTL)
+		if err != nil || n > twoFactorMaxAttempts {
+			return user, nil, ErrTwoFactorChallengeInvalid
+		}
+	}
+
+	tf, err := uc.loadTwoFactor(user.ID)
+	if err != nil {
+		return user, nil, err
+	}
+	if tf == nil {
+		return user, nil, ErrTwoFactorNotEnabled
+	}
+
+	var codes []string
+	if tf.Enabled {
+		err = uc.checkTwoFactorCode(tf, req.Code)
+	} else {
+		codes, err = uc.confirmTwoFactor(tf, req.Code)
+	}
+	if err != nil {
+		return user, nil, err
+	}
+
+	tokens, err := issueTokens(user, user.Role, client)
+	if err != nil {
+		return user, nil, err
+	}
+	resp := adminLoginResponse(user, tokens)
+	resp.RecoveryCodes = codes
+	return user, resp, nil
+}
+
+// SetupTwoFactorChallenge 登录时为角色要求两步验证但尚未绑定的账号生成密钥
+func (uc *authUseCase) SetupTwoFactorChallenge(challengeToken string) (*dto.TwoFactorSetupResponse, error) {
+	user, _, err := uc.challengeUser(challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	return uc.setupTwoFactor(user)
+}
+
+// GetTwoFactorStatus 查询两步验证状态
+func (uc *authUseCase) GetTwoFactorStatus(userID uint, role string) (*dto.TwoFactorStatusResponse, error) {
+	tf, err := uc.loadTwoFactor(userID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.TwoFactorStatusResponse{Required: twoFactorEnforced(role)}
+	if tf != nil && tf.Enabled {
+		resp.Enabled = true
+		resp.RecoveryCodesLeft = len(recoveryCodeDigests(tf))
+	}
+	return resp, nil
+}
+
+// SetupTwoFactor 生成新的 TOTP 密钥，使用验证码确认后才启用
+func (uc *authUseCase) SetupTwoFactor(userID uint) (*dto.TwoFactorSetupResponse, error) {
+	user, err := uc.data.UserRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	return uc.setupTwoFactor(user)
+}
+
+// EnableTwoFactor 使用验证码确认并启用两步验证，返回恢复码
+func (uc *authUseCase) EnableTwoFactor(userID uint, code string) (*dto.RecoveryCodesResponse, error) {
+	tf, err := uc.loadTwoFactor(userID)
+	if err != nil {
+		return nil, err
+	}
+	if tf == nil {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	if tf.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	codes, err := uc.confirmTwoFactor(tf, code)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTwoFactor 关闭两步验证，需要提交验证码或恢复码，角色要求两步验证时不能关闭
+func (uc *authUseCase) DisableTwoFactor(userID uint, role, code string) error {
+	if twoFactorEnforced(role) {
+		return ErrTwoFactorRequired
+	}
+	tf, err := uc.enabledTwoFactor(userID)
+	if err != nil {
+		return err
+	}
+	if err := uc.checkTwoFactorCode(tf, code); err != nil {
+		return err
+	}
+	if err := uc.data.TwoFactorRepo.DeleteByUserID(userID); err != nil {
+		logger.Error("Failed to disable two-factor authentication of user ", userID, ": ", err)
+		return errors.New("关闭两步验证失败")
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，旧恢复码全部失效
+func (uc *authUseCase) RegenerateRecoveryCodes(userID uint, code string) (*dto.RecoveryCodesResponse, error) {
+	tf, err := uc.enabledTwoFactor(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkTwoFactorCode(tf, code); err != nil {
+		return nil, err
+	}
+
+	codes, digests, err := newRecoveryCodes()
+	if err != nil {
+		return nil, errors.New("生成恢复码失败")
+	}
+	// 校验时可能消耗了恢复码，重新读取后再保存
+	if tf, err = uc.enabledTwoFactor(userID); err != nil {
+		return nil, err
+	}
+	tf.RecoveryCodes = digests
+	if err := uc.data.TwoFactorRepo.Save(tf); err != nil {
+		return nil, errors.New("生成恢复码失败")
+	}
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// challengeUser 解析两步验证令牌并重新检查账号状态
+func (uc *authUseCase) challengeUser(challengeToken string) (*po.User, *jwt.TwoFactorClaims, error) {
+	claims, err := jwt.ParseTwoFactorToken(challengeToken)
+	if err != nil {
+		return nil, nil, ErrTwoFactorChallengeInvalid
+	}
+	user, err := uc.data.UserRepo.FindByID(claims.UserID)
+	if err != nil || user.Status != po.UserStatusActive || !ConsoleRole(user.Role) {
+		return nil, nil, ErrTwoFactorChallengeInvalid
+	}
+	return user, claims, nil
+}
+
+// setupTwoFactor 生成新的密钥并保存为未启用状态，已启用时需要先关闭
+func (uc *authUseCase) setupTwoFactor(user *po.User) (*dto.TwoFactorSetupResponse, error) {
+	tf, err := uc.loadTwoFactor(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if tf != nil && tf.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	issuer := config.AppConfig.TwoFactor.Issuer
+	if issuer == "" {
+		issuer = defaultTwoFactorIssuer
+	}
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: issuer, AccountName: user.Username})
+	if err != nil {
+		return nil, errors.New("生成两步验证密钥失败")
+	}
+
+	if tf == nil {
+		tf = &po.UserTwoFactor{UserID: user.ID}
+	}
+	tf.Secret = key.Secret()
+	tf.RecoveryCodes = ""
+	tf.LastUsedStep = 0
+	if err := uc.data.TwoFactorRepo.Save(tf); err != nil {
+		logger.Error("Failed to save two-factor secret of user ", user.ID, ": ", err)
+		return nil, errors.New("生成两步验证密钥失败")
+	}
+
+	resp := &dto.TwoFactorSetupResponse{Secret: key.Secret(), OTPAuthURL: key.URL()}
+	img, err := key.Image(200, 200)
+	if err == nil {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err == nil {
+			resp.QRCode = "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+		}
+	}
+	return resp, nil
+}
+
+// confirmTwoFactor 使用验证器生成的验证码确认绑定，启用两步验证并生成恢复码
+func (uc *authUseCase) confirmTwoFactor(tf *po.UserTwoFactor, code string) ([]string, error) {
+	step, ok := matchTOTP(tf.Secret, normalizeTwoFactorCode(code), time.Now())
+	if !ok {
+		return nil, ErrTwoFactorCodeInvalid
+	}
+	codes, digests, err := newRecoveryCodes()
+	if err != nil {
+		return nil, errors.New("生成恢复码失败")
+	}
+
+	now := time.Now()
+	tf.Enabled = true
+	tf.EnabledAt = &now
+	tf.LastUsedStep = step
+	tf.RecoveryCodes = digests
+	if err := uc.data.TwoFactorRepo.Save(tf); err != nil {
+		logger.Error("Failed to enable two-factor authentication of user ", tf.UserID, ": ", err)
+		return nil, errors.New("启用两步验证失败")
+	}
+	return codes, nil
+}
+
+// checkTwoFactorCode 校验验证码或恢复码，验证码和恢复码都只能使用一次
+func (uc *authUseCase) checkTwoFactorCode(tf *po.UserTwoFactor, code string) error {
+	code = normalizeTwoFactorCode(code)
+	if step, ok := matchTOTP(tf.Secret, code, time.Now()); ok {
+		used, err := uc.data.TwoFactorRepo.UseStep(tf.ID, step)
+		if err != nil {
+			logger.Error("Failed to record two-factor code of user ", tf.UserID, ": ", err)
+			return errors.New("两步验证失败")
+		}
+		if !used {
+			return ErrTwoFactorCodeInvalid
+		}
+		return nil
+	}
+
+	digests := recoveryCodeDigests(tf)
+	i := slices.Index(digests, tokenDigest(code))
+	if i < 0 {
+		return ErrTwoFactorCodeInvalid
+	}
+	remaining, _ := json.Marshal(slices.Delete(slices.Clone(digests), i, i+1))
+	replaced, err := uc.data.TwoFactorRepo.ReplaceRecoveryCodes(tf.ID, tf.RecoveryCodes, string(remaining))
+	if err != nil {
+		logger.Error("Failed to consume recovery code of user ", tf.UserID, ": ", err)
+		return errors.New("两步验证失败")
+	}
+	if !replaced {
+		return ErrTwoFactorCodeInvalid
+	}
+	logger.Warn("Recovery code used by user ", tf.UserID, ", ", len(digests)-1, " left")
+	return nil
+}
+
+// loadTwoFactor 查询两步验证设置，未绑定时返回 nil
+// 查询失败时返回错误而不是视为未启用，避免数据库异常时跳过两步验证
+func (uc *authUseCase) loadTwoFactor(userID uint) (*po.UserTwoFactor, error) {
+	tf, err := uc.data.TwoFactorRepo.FindByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error("Failed to load two-factor settings of user ", userID, ": ", err)
+		return nil, errors.New("查询两步验证设置失败")
+	}
+	return tf, nil
+}
+
+// enabledTwoFactor 查询已启用的两步验证设置
+func (uc *authUseCase) enabledTwoFactor(userID uint) (*po.UserTwoFactor, error) {
+	tf, err := uc.loadTwoFactor(userID)
+	if err != nil {
+		return nil, err
+	}
+	if tf == nil || !tf.Enabled {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	return tf, nil
+}
+
+// twoFactorEnforced 角色是否必须启用两步验证
+func twoFactorEnforced(role string) bool {
+	return slices.Contains(config.AppConfig.TwoFactor.EnforceRoles, role)
+}
+
+// matchTOTP 校验 TOTP 验证码，允许前后各一个时间步的时钟偏差，返回验证码所在的时间步
+func matchTOTP(secret, code string, now time.Time) (int64, bool) {
+	if len(code) != 6 {
+		return 0, false
+	}
+	for _, skew := range []int64{0, -1, 1} {
+		t := now.Add(time.Duration(skew*totpPeriod) * time.Second)
+		want, err := totp.GenerateCode(secret, t)
+		if err == nil && subtle.ConstantTimeCompare([]byte(want), []byte(code)) == 1 {
+			return t.Unix() / totpPeriod, true
+		}
+	}
+	return 0, false
+}
+
+// normalizeTwoFactorCode 去掉用户输入的空格和恢复码中的连字符
+func normalizeTwoFactorCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer(" ", "", "-", "").Replace(code)
+}
+
+// newRecoveryCodes 生成恢复码，返回展示给用户的恢复码和保存的摘要（JSON 数组）
+func newRecoveryCodes() ([]string, string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	digests := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := randomHex(5)
+		if err != nil {
+			return nil, "", err
+		}
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		digests = append(digests, tokenDigest(raw))
+	}
+	value, _ := json.Marshal(digests)
+	return codes, string(value), nil
+}
+
+// recoveryCodeDigests 未使用的恢复码摘要
+func recoveryCodeDigests(tf *po.UserTwoFactor) []string {
+	var digests []string
+	if tf.RecoveryCodes != "" {
+		_ = json.Unmarshal([]byte(tf.RecoveryCodes), &digests)
+	}
+	return digests
+}
@@ -0,0 +1,248 @@
+package biz
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/jwt"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// stubTwoFactorRepo 内存中的两步验证仓储
+type stubTwoFactorRepo struct {
+	data.TwoFactorRepo
+	settings map[uint]*po.UserTwoFactor
+}
+
+func (r *stubTwoFactorRepo) FindByUserID(userID uint) (*po.UserTwoFactor, error) {
+	if tf, ok := r.settings[userID]; ok {
+		copied := *tf
+		return &copied, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubTwoFactorRepo) Save(tf *po.UserTwoFactor) error {
+	if tf.ID == 0 {
+		tf.ID = uint(len(r.settings) + 1)
+	}
+	copied := *tf
+	r.settings[tf.UserID] = &copied
+	return nil
+}
+
+func (r *stubTwoFactorRepo) DeleteByUserID(userID uint) error {
+	delete(r.settings, userID)
+	return nil
+}
+
+func (r *stubTwoFactorRepo) UseStep(id uint, step int64) (bool, error) {
+	for _, tf := range r.settings {
+		if tf.ID == id && tf.LastUsedStep < step {
+			tf.LastUsedStep = step
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *stubTwoFactorRepo) ReplaceRecoveryCodes(id uint, old, codes string) (bool, error) {
+	for _, tf := range r.settings {
+		if tf.ID == id && tf.RecoveryCodes == old {
+			tf.RecoveryCodes = codes
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// newTwoFactorUseCase 创建只有一个管理员的认证用例，密码为 secret123
+func newTwoFactorUseCase(t *testing.T, enforceRoles ...string) (*authUseCase, *stubTwoFactorRepo) {
+	t.Helper()
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	admin := &po.User{ID: 1, Username: "admin", Password: string(hash), Role: "admin", Status: po.UserStatusActive}
+	settings := &stubTwoFactorRepo{settings: map[uint]*po.UserTwoFactor{}}
+
+	previous := config.AppConfig.TwoFactor
+	config.AppConfig.TwoFactor = config.TwoFactorConfig{EnforceRoles: enforceRoles}
+	t.Cleanup(func() { config.AppConfig.TwoFactor = previous })
+
+	uc := &authUseCase{data: &data.Data{
+		UserRepo:      &stubUserRepo{users: map[uint]*po.User{1: admin}},
+		TwoFactorRepo: settings,
+		LoginLogRepo:  &stubLoginLogRepo{},
+	}}
+	return uc, settings
+}
+
+// enrollTwoFactor 绑定并启用两步验证，返回密钥和恢复码
+func enrollTwoFactor(t *testing.T, uc *authUseCase) (string, []string) {
+	t.Helper()
+	setup, err := uc.SetupTwoFactor(1)
+	if err != nil {
+		t.Fatalf("SetupTwoFactor: %v", err)
+	}
+	// 使用上一个时间步的验证码确认，当前时间步的验证码留给登录
+	code, _ := totp.GenerateCode(setup.Secret, time.Now().Add(-totpPeriod*time.Second))
+	codes, err := uc.EnableTwoFactor(1, code)
+	if err != nil {
+		t.Fatalf("EnableTwoFactor: %v", err)
+	}
+	return setup.Secret, codes.RecoveryCodes
+}
+
+func TestLoginRequiresTwoFactor(t *testing.T) {
+	tests := []struct {
+		name         string
+		enforceRoles []string
+		enroll       bool
+		wantRequired bool
+		wantSetup    bool
+	}{
+		{name: "not enabled"},
+		{name: "enabled", enroll: true, wantRequired: true},
+		{name: "enforced for role but not enrolled", enforceRoles: []string{"admin"}, wantRequired: true, wantSetup: true},
+		{name: "enforced for other role", enforceRoles: []string{"super_admin"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, _ := newTwoFactorUseCase(t, tt.enforceRoles...)
+			if tt.enroll {
+				enrollTwoFactor(t, uc)
+			}
+
+			resp, err := uc.Login(context.Background(), &dto.LoginRequest{Username: "admin", Password: "secret123"}, nil)
+			if err != nil {
+				t.Fatalf("Login: %v", err)
+			}
+			if resp.TwoFactorRequired != tt.wantRequired || resp.TwoFactorSetup != tt.wantSetup {
+				t.Fatalf("required = %v, setup = %v, want %v, %v", resp.TwoFactorRequired, resp.TwoFactorSetup, tt.wantRequired, tt.wantSetup)
+			}
+			if tt.wantRequired && (resp.Token != "" || resp.ChallengeToken == "") {
+				t.Errorf("token issued before two-factor verification: %+v", resp)
+			}
+			if !tt.wantRequired && resp.Token == "" {
+				t.Error("token not issued")
+			}
+		})
+	}
+}
+
+func TestVerifyTwoFactor(t *testing.T) {
+	setupTokenRedis(t)
+	uc, _ := newTwoFactorUseCase(t)
+	secret, recovery := enrollTwoFactor(t, uc)
+	current, _ := totp.GenerateCode(secret, time.Now())
+
+	tests := []struct {
+		name    string
+		code    string
+		wantErr error
+	}{
+		{name: "wrong code", code: "000000", wantErr: ErrTwoFactorCodeInvalid},
+		{name: "totp code", code: current},
+		{name: "totp code cannot be reused", code: current, wantErr: ErrTwoFactorCodeInvalid},
+		{name: "recovery code", code: recovery[0]},
+		{name: "recovery code without dash and in upper case", code: " " + strings.ToUpper(recovery[1][:5]+recovery[1][6:]) + " "},
+		{name: "recovery code cannot be reused", code: recovery[0], wantErr: ErrTwoFactorCodeInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			login, err := uc.Login(context.Background(), &dto.LoginRequest{Username: "admin", Password: "secret123"}, nil)
+			if err != nil {
+				t.Fatalf("Login: %v", err)
+			}
+			resp, err := uc.VerifyTwoFactor(context.Background(), &dto.TwoFactorVerifyRequest{ChallengeToken: login.ChallengeToken, Code: tt.code}, nil)
+			if err != tt.wantErr {
+				t.Fatalf("VerifyTwoFactor err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (resp.Token == "" || resp.Admin == nil) {
+				t.Errorf("token not issued: %+v", resp)
+			}
+		})
+	}
+
+	status, err := uc.GetTwoFactorStatus(1, "admin")
+	if err != nil || !status.Enabled || status.RecoveryCodesLeft != recoveryCodeCount-2 {
+		t.Errorf("status = %+v, %v, want %d recovery codes left", status, err, recoveryCodeCount-2)
+	}
+}
+
+func TestVerifyTwoFactorLimitsAttempts(t *testing.T) {
+	setupTokenRedis(t)
+	uc, _ := newTwoFactorUseCase(t)
+	secret, _ := enrollTwoFactor(t, uc)
+
+	login, err := uc.Login(context.Background(), &dto.LoginRequest{Username: "admin", Password: "secret123"}, nil)
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	for i := 0; i < twoFactorMaxAttempts; i++ {
+		if _, err := uc.VerifyTwoFactor(context.Background(), &dto.TwoFactorVerifyRequest{ChallengeToken: login.ChallengeToken, Code: "000000"}, nil); err != ErrTwoFactorCodeInvalid {
+			t.Fatalf("attempt %d: err = %v, want %v", i+1, err, ErrTwoFactorCodeInvalid)
+		}
+	}
+	code, _ := totp.GenerateCode(secret, time.Now())
+	if _, err := uc.VerifyTwoFactor(context.Background(), &dto.TwoFactorVerifyRequest{ChallengeToken: login.ChallengeToken, Code: code}, nil); err != ErrTwoFactorChallengeInvalid {
+		t.Errorf("err = %v, want %v after too many attempts", err, ErrTwoFactorChallengeInvalid)
+	}
+}
+
+func TestEnforcedTwoFactorSetupDuringLogin(t *testing.T) {
+	uc, settings := newTwoFactorUseCase(t, "admin")
+
+	login, err := uc.Login(context.Background(), &dto.LoginRequest{Username: "admin", Password: "secret123"}, nil)
+	if err != nil || !login.TwoFactorSetup {
+		t.Fatalf("Login = %+v, %v, want setup required", login, err)
+	}
+	if _, err := uc.VerifyTwoFactor(context.Background(), &dto.TwoFactorVerifyRequest{ChallengeToken: login.ChallengeToken, Code: "123456"}, nil); err != ErrTwoFactorNotEnabled {
+		t.Fatalf("verify before setup: err = %v, want %v", err, ErrTwoFactorNotEnabled)
+	}
+
+	setup, err := uc.SetupTwoFactorChallenge(login.ChallengeToken)
+	if err != nil {
+		t.Fatalf("SetupTwoFactorChallenge: %v", err)
+	}
+	if setup.QRCode == "" || setup.OTPAuthURL == "" {
+		t.Errorf("setup response misses the QR code: %+v", setup)
+	}
+	code, _ := totp.GenerateCode(setup.Secret, time.Now())
+	resp, err := uc.VerifyTwoFactor(context.Background(), &dto.TwoFactorVerifyRequest{ChallengeToken: login.ChallengeToken, Code: code}, nil)
+	if err != nil {
+		t.Fatalf("VerifyTwoFactor: %v", err)
+	}
+	if resp.Token == "" || len(resp.RecoveryCodes) != recoveryCodeCount {
+		t.Errorf("want token and %d recovery codes, got %+v", recoveryCodeCount, resp)
+	}
+	if !settings.settings[1].Enabled {
+		t.Error("two-factor authentication not enabled after setup")
+	}
+	if err := uc.DisableTwoFactor(1, "admin", code); err != ErrTwoFactorRequired {
+		t.Errorf("DisableTwoFactor err = %v, want %v", err, ErrTwoFactorRequired)
+	}
+}
+
+func TestChallengeTokenIsNotALoginToken(t *testing.T) {
+	uc, _ := newTwoFactorUseCase(t)
+	enrollTwoFactor(t, uc)
+	login, err := uc.Login(context.Background(), &dto.LoginRequest{Username: "admin", Password: "secret123"}, nil)
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if _, err := uc.VerifyTwoFactor(context.Background(), &dto.TwoFactorVerifyRequest{ChallengeToken: "invalid", Code: "123456"}, nil); err != ErrTwoFactorChallengeInvalid {
+		t.Errorf("invalid challenge: err = %v, want %v", err, ErrTwoFactorChallengeInvalid)
+	}
+	if _, err := jwt.ParseToken(login.ChallengeToken); err == nil {
+		t.Error("challenge token accepted as login token")
+	}
+}
@@ -0,0 +1,296 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/oss"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultUploadChunkSize = 5    // MB
+	defaultUploadMaxSize   = 2048 // MB
+	defaultUploadTTL       = 24   // 小时
+	// maxUploadParts OSS 分片上传最多 10000 片
+	maxUploadParts = 10000
+	// uploadCleanupBatch 每次清理的过期会话数
+	uploadCleanupBatch = 100
+)
+
+// uploadFolderPattern 存储目录只允许字母、数字、下划线和连字符，可多级
+var uploadFolderPattern = regexp.MustCompile(`^[A-Za-z0-9_\-]+(/[A-Za-z0-9_\-]+)*$`)
+
+var (
+	// ErrUploadNotFound 上传会话不存在或已过期
+	ErrUploadNotFound = errors.New("上传会话不存在或已过期")
+	// ErrUploadInvalid 上传参数不合法
+	ErrUploadInvalid = errors.New("上传参数不合法")
+	// ErrUploadIncomplete 分片未全部上传
+	ErrUploadIncomplete = errors.New("分片未全部上传")
+)
+
+// UploadUseCase 分片上传业务用例
+type UploadUseCase interface {
+	// Init 创建分片上传会话，返回分片大小和分片数
+	Init(req *dto.InitUploadRequest, uploaderID uint) (*dto.UploadSessionResponse, error)
+	// UploadPart 上传一个分片，size 必须与该分片的预期大小一致
+	UploadPart(id string, uploaderID uint, partNumber int, r io.Reader, size int64) error
+	// Status 查询会话和已上传的分片，用于断点续传
+	Status(id string, uploaderID uint) (*dto.UploadSessionResponse, error)
+	// Complete 合并分片并保存文件记录
+	Complete(id string, uploaderID uint) (*po.File, error)
+	// Abort 取消上传并删除已上传的分片
+	Abort(id string, uploaderID uint) error
+	// CleanupExpired 清理过期未完成的上传，返回清理数量
+	CleanupExpired() (int, error)
+}
+
+type uploadUseCase struct {
+	data *data.Data
+}
+
+// NewUploadUseCase 创建分片上传业务用例
+func NewUploadUseCase(d *data.Data) UploadUseCase {
+	return &uploadUseCase{data: d}
+}
+
+// Init 创建分片上传会话
+func (uc *uploadUseCase) Init(req *dto.InitUploadRequest, uploaderID uint) (*dto.UploadSessionResponse, error) {
+	cfg := config.AppConfig.Upload
+	maxSize := cfg.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultUploadMaxSize
+	}
+	if req.Size > int64(maxSize)<<20 {
+		return nil, fmt.Errorf("%w: 文件不能超过 %d MB", ErrUploadInvalid, maxSize)
+	}
+
+	folder := req.Folder
+	if folder == "" {
+		folder = "uploads"
+	}
+	if !uploadFolderPattern.MatchString(folder) {
+		return nil, fmt.Errorf("%w: 存储目录只能包含字母、数字、下划线和连字符", ErrUploadInvalid)
+	}
+
+	chunkSize := int64(cfg.ChunkSize)
+	if chunkSize <= 0 {
+		chunkSize = defaultUploadChunkSize
+	}
+	chunkSize <<= 20
+	totalParts := int((req.Size + chunkSize - 1) / chunkSize)
+	if totalParts > maxUploadParts {
+		return nil, fmt.Errorf("%w: 分片数超过 %d，请调大 upload.chunk_size", ErrUploadInvalid, maxUploadParts)
+	}
+
+	ttl := cfg.SessionTTL
+	if ttl <= 0 {
+		ttl = defaultUploadTTL
+	}
+
+	upload, err := oss.InitMultipart(oss.ObjectKey(folder, req.Filename))
+	if err != nil {
+		return nil, err
+	}
+	session := &po.UploadSession{
+		ID:         uuid.NewString(),
+		Filename:   req.Filename,
+		Folder:     folder,
+		MimeType:   req.MimeType,
+		Size:       req.Size,
+		ChunkSize:  chunkSize,
+		TotalParts: totalParts,
+		Storage:    upload.Storage,
+		ObjectKey:  upload.Key,
+		StorageID:  upload.UploadID,
+		UploaderID: uploaderID,
+		ExpiresAt:  time.Now().Add(time.Duration(ttl) * time.Hour),
+	}
+	if err := uc.data.UploadSessionRepo.Create(session); err != nil {
+		if abortErr := upload.Abort(); abortErr != nil {
+			logger.Warn("Failed to abort multipart upload: ", abortErr)
+		}
+		return nil, err
+	}
+
+	return toUploadSessionResponse(session, []int{}), nil
+}
+
+// UploadPart 上传一个分片
+func (uc *uploadUseCase) UploadPart(id string, uploaderID uint, partNumber int, r io.Reader, size int64) error {
+	session, err := uc.findSession(id, uploaderID)
+	if err != nil {
+		return err
+	}
+	if partNumber < 1 || partNumber > session.TotalParts {
+		return fmt.Errorf("%w: 分片号应在 1-%d 之间", ErrUploadInvalid, session.TotalParts)
+	}
+	if expected := partSize(session, partNumber); size != expected {
+		return fmt.Errorf("%w: 分片 %d 应为 %d 字节，实际 %d 字节", ErrUploadInvalid, partNumber, expected, size)
+	}
+
+	return multipartOf(session).UploadPart(partNumber, io.LimitReader(r, size), size)
+}
+
+// Status 查询会话和已上传的分片
+func (uc *uploadUseCase) Status(id string, uploaderID uint) (*dto.UploadSessionResponse, error) {
+	session, err := uc.findSession(id, uploaderID)
+	if err != nil {
+		return nil, err
+	}
+	uploaded, err := uploadedParts(session)
+	if err != nil {
+		return nil, err
+	}
+	numbers := make([]int, 0, len(uploaded))
+	for number := range uploaded {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+	return toUploadSessionResponse(session, numbers), nil
+}
+
+// Complete 合并分片并保存文件记录
+func (uc *uploadUseCase) Complete(id string, uploaderID uint) (*po.File, error) {
+	session, err := uc.findSession(id, uploaderID)
+	if err != nil {
+		return nil, err
+	}
+	uploaded, err := uploadedParts(session)
+	if err != nil {
+		return nil, err
+	}
+	var missing []int
+	for number := 1; number <= session.TotalParts; number++ {
+		if !uploaded[number] {
+			missing = append(missing, number)
+		}
+	}
+	if len(missing) > 0 {
+		if len(missing) > 20 {
+			return nil, fmt.Errorf("%w: 缺少 %d 个分片，包括 %v", ErrUploadIncomplete, len(missing), missing[:20])
+		}
+		return nil, fmt.Errorf("%w: 缺少分片 %v", ErrUploadIncomplete, missing)
+	}
+
+	url, err := multipartOf(session).Complete(session.TotalParts)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &po.File{
+		Name:     session.Filename,
+		URL:      url,
+		Size:     session.Size,
+		Type:     session.Folder,
+		MimeType: session.MimeType,
+	}
+	if err := uc.data.FileRepo.Create(file); err != nil {
+		return nil, err
+	}
+	if err := uc.data.UploadSessionRepo.Delete(session.ID); err != nil {
+		logger.Warn("Failed to delete upload session: ", err)
+	}
+	return file, nil
+}
+
+// Abort 取消上传
+func (uc *uploadUseCase) Abort(id string, uploaderID uint) error {
+	session, err := uc.findSession(id, uploaderID)
+	if err != nil {
+		return err
+	}
+	if err := multipartOf(session).Abort(); err != nil {
+		return err
+	}
+	return uc.data.UploadSessionRepo.Delete(session.ID)
+}
+
+// CleanupExpired 清理过期未完成的上传
+// 分片删除失败只记录日志，会话照常删除，避免反复重试无法删除的上传
+func (uc *uploadUseCase) CleanupExpired() (int, error) {
+	cleaned := 0
+	for {
+		sessions, err := uc.data.UploadSessionRepo.ListExpired(time.Now(), uploadCleanupBatch)
+		if err != nil {
+			return cleaned, err
+		}
+		for _, session := range sessions {
+			if err := multipartOf(session).Abort(); err != nil {
+				logger.Warn("Failed to abort expired upload "+session.ID+": ", err)
+			}
+			if err := uc.data.UploadSessionRepo.Delete(session.ID); err != nil {
+				return cleaned, err
+			}
+			cleaned++
+		}
+		if len(sessions) < uploadCleanupBatch {
+			return cleaned, nil
+		}
+	}
+}
+
+// findSession 查询上传者未过期的会话
+func (uc *uploadUseCase) findSession(id string, uploaderID uint) (*po.UploadSession, error) {
+	session, err := uc.data.UploadSessionRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, err
+	}
+	if session.UploaderID != uploaderID || time.Now().After(session.ExpiresAt) {
+		return nil, ErrUploadNotFound
+	}
+	return session, nil
+}
+
+// uploadedParts 已上传且大小正确的分片号
+func uploadedParts(session *po.UploadSession) (map[int]bool, error) {
+	parts, err := multipartOf(session).ListParts()
+	if err != nil {
+		return nil, err
+	}
+	uploaded := make(map[int]bool, len(parts))
+	for _, part := range parts {
+		if part.Number >= 1 && part.Number <= session.TotalParts && part.Size == partSize(session, part.Number) {
+			uploaded[part.Number] = true
+		}
+	}
+	return uploaded, nil
+}
+
+// partSize 分片的预期大小，最后一片为剩余部分
+func partSize(session *po.UploadSession, partNumber int) int64 {
+	if partNumber < session.TotalParts {
+		return session.ChunkSize
+	}
+	return session.Size - int64(session.TotalParts-1)*session.ChunkSize
+}
+
+func multipartOf(session *po.UploadSession) *oss.MultipartUpload {
+	return &oss.MultipartUpload{Storage: session.Storage, Key: session.ObjectKey, UploadID: session.StorageID}
+}
+
+func toUploadSessionResponse(session *po.UploadSession, uploaded []int) *dto.UploadSessionResponse {
+	return &dto.UploadSessionResponse{
+		UploadID:      session.ID,
+		Filename:      session.Filename,
+		Size:          session.Size,
+		ChunkSize:     session.ChunkSize,
+		TotalParts:    session.TotalParts,
+		UploadedParts: uploaded,
+		ExpiresAt:     session.ExpiresAt,
+	}
+}
@@ -1,7 +1,9 @@
 package biz
 
 import (
+	"context"
 	"errors"
+	"io"
 
 	"github.com/ydcloud-dy/leaf-api/internal/data"
 	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
@@ -21,6 +23,8 @@ type UserUseCase interface {
 	GetByID(id uint) (*dto.UserResponse, error)
 	// List 查询管理员列表
 	List(req *dto.UserListRequest) (*dto.PageResponse, error)
+	// ExportData 导出用户的全部数据，用于数据可携带请求
+	ExportData(id uint) (*dto.UserDataExport, error)
 }
 
 // userUseCase 用户业务用例实现
@@ -111,13 +115,19 @@ func (uc *userUseCase) Update(id uint, req *dto.UpdateUserRequest) (*dto.UserRes
 	// 更新技术栈和联系方式（允许空字符串清空）
 	user.Skills = req.Skills
 	user.Contacts = req.Contacts
+	// 修改密码或禁用账号后，已登录的会话全部失效
+	revoke := req.Password != ""
 	if req.Status != nil {
+		revoke = revoke || (*req.Status != po.UserStatusActive && *req.Status != user.Status)
 		user.Status = *req.Status
 	}
 
 	if err := uc.data.UserRepo.Update(user); err != nil {
 		return nil, errors.New("更新用户失败")
 	}
+	if revoke {
+		revokeUserSessions(user.ID)
+	}
 
 	return uc.convertToUserResponse(user), nil
 }
@@ -232,6 +242,9 @@ func (uc *categoryUseCase) Create(name, description string, sort int) error {
 		return errors.New("创建分类失败")
 	}
 
+	// 刷新站点地图
+	InvalidateSitemap()
+
 	return nil
 }
 
@@ -255,6 +268,9 @@ func (uc *categoryUseCase) Delete(id uint) error {
 		return errors.New("删除分类失败")
 	}
 
+	// 刷新站点地图
+	InvalidateSitemap()
+
 	return nil
 }
 
@@ -309,6 +325,9 @@ func (uc *tagUseCase) Create(name, color string) error {
 		return errors.New("创建标签失败")
 	}
 
+	// 刷新站点地图
+	InvalidateSitemap()
+
 	return nil
 }
 
@@ -323,6 +342,9 @@ func (uc *tagUseCase) Delete(id uint) error {
 		return errors.New("删除标签失败")
 	}
 
+	// 刷新站点地图
+	InvalidateSitemap()
+
 	return nil
 }
 
@@ -346,9 +368,17 @@ type CommentUseCase interface {
 	// Delete 删除评论
 	Delete(id uint) error
 	// UpdateStatus 更新评论状态
-	UpdateStatus(id uint, status int) error
+	UpdateStatus(ctx context.Context, id uint, status int) error
+	// BatchUpdateStatus 批量审核评论，返回每条评论的处理结果
+	BatchUpdateStatus(ctx context.Context, req *dto.BatchCommentStatusRequest) (*dto.BatchCommentStatusResponse, error)
 	// List 查询评论列表
 	List(page, limit int, articleID uint, status string) ([]*po.Comment, int64, error)
+	// BackfillThreads 为历史回复补全所属顶级评论和回复数，返回补全的回复数
+	BackfillThreads() (int64, error)
+	// ListEdits 查询评论的编辑记录
+	ListEdits(id uint) ([]*po.CommentEdit, error)
+	// Export 按筛选条件导出评论为 CSV 或 JSON 并流式写入 w
+	Export(w io.Writer, req *dto.CommentExportRequest) error
 }
 
 // commentUseCase 评论业务用例实现
@@ -364,11 +394,12 @@ func NewCommentUseCase(d *data.Data) CommentUseCase {
 // Delete 删除评论
 func (uc *commentUseCase) Delete(id uint) error {
 	// 检查评论是否存在
-	if _, err := uc.data.CommentRepo.FindByID(id); err != nil {
+	comment, err := uc.data.CommentRepo.FindByID(id)
+	if err != nil {
 		return errors.New("评论不存在")
 	}
 
-	if err := uc.data.CommentRepo.Delete(id); err != nil {
+	if err := deleteComment(uc.data, comment); err != nil {
 		return errors.New("删除评论失败")
 	}
 
@@ -376,9 +407,10 @@ func (uc *commentUseCase) Delete(id uint) error {
 }
 
 // UpdateStatus 更新评论状态
-func (uc *commentUseCase) UpdateStatus(id uint, status int) error {
+func (uc *commentUseCase) UpdateStatus(ctx context.Context, id uint, status int) error {
 	// 检查评论是否存在
-	if _, err := uc.data.CommentRepo.FindByID(id); err != nil {
+	comment, err := uc.data.CommentRepo.FindByID(id)
+	if err != nil {
 		return errors.New("评论不存在")
 	}
 
@@ -386,9 +418,75 @@ func (uc *commentUseCase) UpdateStatus(id uint, status int) error {
 		return errors.New("更新状态失败")
 	}
 
+	applyCommentStatusChange(ctx, uc.data, comment, status)
+
 	return nil
 }
 
+// BatchUpdateStatus 批量通过、标记垃圾或移入回收站，所有更新在同一事务中完成
+func (uc *commentUseCase) BatchUpdateStatus(ctx context.Context, req *dto.BatchCommentStatusRequest) (*dto.BatchCommentStatusResponse, error) {
+	status := *req.Status
+	commentIDs := uniqueIDs(req.CommentIDs)
+
+	comments, err := uc.data.CommentRepo.BatchUpdateStatus(commentIDs, status)
+	if err != nil {
+		return nil, errors.New("批量更新状态失败")
+	}
+
+	found := make(map[uint]*po.Comment, len(comments))
+	for _, comment := range comments {
+		found[comment.ID] = comment
+	}
+
+	resp := &dto.BatchCommentStatusResponse{Results: make([]dto.BatchCommentStatusResult, 0, len(commentIDs))}
+	for _, id := range commentIDs {
+		comment, ok := found[id]
+		if !ok {
+			resp.Results = append(resp.Results, dto.BatchCommentStatusResult{
+				CommentID: id,
+				Message:   "评论不存在",
+			})
+			continue
+		}
+
+		result := dto.BatchCommentStatusResult{CommentID: id, Success: true, Changed: comment.Status != status}
+		if result.Changed {
+			applyCommentStatusChange(ctx, uc.data, comment, status)
+			resp.Updated++
+		} else {
+			result.Message = "状态未变化"
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	return resp, nil
+}
+
+// applyCommentStatusChange 评论进入或离开审核通过状态时，同步顶级评论回复数和文章评论数
+// 待审核的评论首次审核通过时通知被回复的用户和正在查看页面的读者；comment 为修改前的评论
+func applyCommentStatusChange(ctx context.Context, d *data.Data, comment *po.Comment, status int) {
+	wasApproved := comment.Status == po.CommentStatusApproved
+	isApproved := status == po.CommentStatusApproved
+	if wasApproved == isApproved {
+		return
+	}
+	if comment.Status == po.CommentStatusPending && isApproved {
+		notifyCommentReply(ctx, d, comment)
+		publishComment(d, comment)
+	}
+
+	delta := 1
+	if !isApproved {
+		delta = -1
+	}
+	if comment.RootID != nil {
+		_ = d.CommentRepo.AddReplyCount(*comment.RootID, delta)
+	}
+	if comment.ArticleID != nil {
+		_ = d.ArticleRepo.AddCounters(*comment.ArticleID, map[string]int64{"comment_count": int64(delta)})
+	}
+}
+
 // List 查询评论列表
 func (uc *commentUseCase) List(page, limit int, articleID uint, status string) ([]*po.Comment, int64, error) {
 	comments, total, err := uc.data.CommentRepo.List(page, limit, articleID, status)
@@ -396,5 +494,29 @@ func (uc *commentUseCase) List(page, limit int, articleID uint, status string) (
 		return nil, 0, errors.New("查询评论列表失败")
 	}
 
+	// 历史评论没有保存渲染结果，返回前补全
+	for _, comment := range comments {
+		comment.ContentHTML = commentHTML(comment)
+	}
+
 	return comments, total, nil
 }
+
+// BackfillThreads 为历史回复补全所属顶级评论和回复数
+func (uc *commentUseCase) BackfillThreads() (int64, error) {
+	return uc.data.CommentRepo.BackfillRoots()
+}
+
+// ListEdits 查询评论的编辑记录，按时间倒序
+func (uc *commentUseCase) ListEdits(id uint) ([]*po.CommentEdit, error) {
+	if _, err := uc.data.CommentRepo.FindByID(id); err != nil {
+		return nil, errors.New("评论不存在")
+	}
+
+	edits, err := uc.data.CommentRepo.ListEdits(id)
+	if err != nil {
+		return nil, errors.New("查询编辑记录失败")
+	}
+
+	return edits, nil
+}
@@ -0,0 +1,119 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// ExportData 导出用户的全部数据
+func (uc *userUseCase) ExportData(id uint) (*dto.UserDataExport, error) {
+	return exportUserData(uc.data, id)
+}
+
+// ExportUserData 导出当前用户的全部数据
+func (uc *blogUseCase) ExportUserData(userID uint) (*dto.UserDataExport, error) {
+	return exportUserData(uc.data, userID)
+}
+
+// exportUserData 汇总系统中保存的用户数据：资料、评论及编辑记录、点赞、收藏、表情回应、浏览和访问记录
+// 不包含密码哈希等凭据
+func exportUserData(d *data.Data, userID uint) (*dto.UserDataExport, error) {
+	user, err := d.UserRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("用户不存在")
+	}
+
+	export := &dto.UserDataExport{
+		ExportedAt: time.Now(),
+		Profile: dto.UserDataProfile{
+			UserResponse: dto.UserResponse{
+				ID:        user.ID,
+				Username:  user.Username,
+				Email:     user.Email,
+				Nickname:  user.Nickname,
+				Avatar:    user.Avatar,
+				Bio:       user.Bio,
+				Skills:    user.Skills,
+				Contacts:  user.Contacts,
+				Status:    user.Status,
+				CreatedAt: user.CreatedAt,
+				UpdatedAt: user.UpdatedAt,
+			},
+			Role: user.Role,
+		},
+		Comments:     []*dto.CommentExportItem{},
+		CommentEdits: []*dto.UserDataCommentEdit{},
+		CommentLikes: []*dto.UserDataCommentLike{},
+		Likes:        []*dto.UserDataArticle{},
+		Favorites:    []*dto.UserDataArticle{},
+		Reactions:    []*dto.UserDataReaction{},
+		Views:        []*dto.UserDataView{},
+		Visits:       []*dto.UserDataVisit{},
+	}
+
+	err = d.CommentRepo.ExportBatches(0, userID, "", func(comments []*po.Comment) error {
+		for _, comment := range comments {
+			export.Comments = append(export.Comments, convertToCommentExportItem(comment))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取评论失败: %w", err)
+	}
+
+	db := d.GetDB()
+	if err := db.Model(&po.CommentEdit{}).Select("comment_id, content, created_at").
+		Where("editor_id = ?", userID).Order("id ASC").Scan(&export.CommentEdits).Error; err != nil {
+		return nil, fmt.Errorf("读取评论编辑记录失败: %w", err)
+	}
+	if err := db.Model(&po.CommentLike{}).Select("comment_id, created_at").
+		Where("user_id = ?", userID).Order("id ASC").Scan(&export.CommentLikes).Error; err != nil {
+		return nil, fmt.Errorf("读取评论点赞失败: %w", err)
+	}
+	for _, source := range []struct {
+		table string
+		name  string
+		rows  *[]*dto.UserDataArticle
+	}{
+		{"likes", "点赞", &export.Likes},
+		{"favorites", "收藏", &export.Favorites},
+	} {
+		if err := db.Table(source.table).
+			Select(source.table+".article_id, articles.title AS article_title, "+source.table+".created_at").
+			Joins("LEFT JOIN articles ON articles.id = "+source.table+".article_id").
+			Where(source.table+".user_id = ?", userID).Order(source.table + ".id ASC").
+			Scan(source.rows).Error; err != nil {
+			return nil, fmt.Errorf("读取%s失败: %w", source.name, err)
+		}
+	}
+	if err := db.Model(&po.Reaction{}).Select("target_type, target_id, emoji, created_at").
+		Where("user_id = ?", userID).Order("id ASC").Scan(&export.Reactions).Error; err != nil {
+		return nil, fmt.Errorf("读取表情回应失败: %w", err)
+	}
+	if err := db.Model(&po.View{}).Select("article_id, ip, created_at").
+		Where("user_id = ?", userID).Order("id ASC").Scan(&export.Views).Error; err != nil {
+		return nil, fmt.Errorf("读取浏览记录失败: %w", err)
+	}
+
+	// 访问记录的 IP 可能加密存储，通过模型查询以触发解密
+	var visits []*po.PageVisit
+	if err := db.Where("user_id = ?", userID).Order("id ASC").Find(&visits).Error; err != nil {
+		return nil, fmt.Errorf("读取访问记录失败: %w", err)
+	}
+	for _, visit := range visits {
+		export.Visits = append(export.Visits, &dto.UserDataVisit{
+			Path:      visit.Path,
+			Duration:  visit.Duration,
+			IP:        visit.IP,
+			UserAgent: visit.UserAgent,
+			Referrer:  visit.Referrer,
+			CreatedAt: visit.CreatedAt,
+		})
+	}
+	return export, nil
+}
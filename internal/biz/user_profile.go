@@ -0,0 +1,145 @@
+package biz
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+)
+
+var (
+	// ErrProfileNotFound 用户不存在或主页不公开（已禁用、邮箱未验证）
+	ErrProfileNotFound = errors.New("用户不存在")
+	// ErrProfileLinkInvalid 网站或社交链接不是 http(s) 地址
+	ErrProfileLinkInvalid = errors.New("链接必须是 http 或 https 地址")
+)
+
+// UserProfileUseCase 用户主页业务用例接口
+type UserProfileUseCase interface {
+	// GetPublic 查询用户的公开主页，包括资料、统计和已发布的文章
+	GetPublic(username string, req *dto.PageRequest) (*dto.PublicProfileResponse, error)
+	// UpdateMine 更新当前用户的主页资料
+	UpdateMine(userID uint, req *dto.UpdatePublicProfileRequest) (*dto.PublicProfileResponse, error)
+}
+
+// userProfileUseCase 用户主页业务用例实现
+type userProfileUseCase struct {
+	data     *data.Data
+	articles ArticleUseCase
+}
+
+// NewUserProfileUseCase 创建用户主页业务用例
+func NewUserProfileUseCase(d *data.Data) UserProfileUseCase {
+	return &userProfileUseCase{data: d, articles: NewArticleUseCase(d)}
+}
+
+// GetPublic 查询用户的公开主页，文章列表与博客前台一致，不包含私密文章
+func (uc *userProfileUseCase) GetPublic(username string, req *dto.PageRequest) (*dto.PublicProfileResponse, error) {
+	user, err := uc.data.UserRepo.FindByUsername(username)
+	if err != nil || user.Status != po.UserStatusActive {
+		return nil, ErrProfileNotFound
+	}
+
+	profile := publicProfile(user)
+	if stats, err := uc.data.ArticleRepo.PublicOnly().AuthorStats(user.ID); err == nil {
+		profile.Stats.ArticleCount = stats.ArticleCount
+		profile.Stats.ViewCount = stats.ViewCount
+		profile.Stats.LikeCount = stats.LikeCount
+	} else {
+		logger.Warn("Failed to count author stats: ", err)
+	}
+	if count, err := uc.data.CommentRepo.CountByUser(user.ID); err == nil {
+		profile.Stats.CommentCount = count
+	}
+	if count, err := uc.data.FollowRepo.CountFollowers(user.ID); err == nil {
+		profile.Stats.FollowerCount = count
+	}
+	if count, err := uc.data.FollowRepo.CountFollowing(user.ID); err == nil {
+		profile.Stats.FollowingCount = count
+	}
+
+	articles, err := uc.articles.List(&dto.ArticleListRequest{
+		PageRequest: *req,
+		AuthorID:    user.ID,
+		Sort:        "latest",
+		Public:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	profile.Articles = articles
+	return profile, nil
+}
+
+// UpdateMine 更新当前用户的主页资料，未传的字段保持不变
+func (uc *userProfileUseCase) UpdateMine(userID uint, req *dto.UpdatePublicProfileRequest) (*dto.PublicProfileResponse, error) {
+	user, err := uc.data.UserRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrProfileNotFound
+	}
+
+	if req.Nickname != nil {
+		user.Nickname = strings.TrimSpace(*req.Nickname)
+	}
+	if req.Bio != nil {
+		user.Bio = strings.TrimSpace(*req.Bio)
+	}
+	if req.Website != nil {
+		website := strings.TrimSpace(*req.Website)
+		if website != "" && !webURL(website) {
+			return nil, ErrProfileLinkInvalid
+		}
+		user.Website = website
+	}
+	if req.SocialLinks != nil {
+		links := make([]dto.SocialLink, 0, len(*req.SocialLinks))
+		for _, link := range *req.SocialLinks {
+			if !webURL(link.URL) {
+				return nil, ErrProfileLinkInvalid
+			}
+			links = append(links, dto.SocialLink{Platform: strings.TrimSpace(link.Platform), URL: link.URL})
+		}
+		encoded, err := json.Marshal(links)
+		if err != nil {
+			return nil, err
+		}
+		user.SocialLinks = string(encoded)
+	}
+
+	if err := uc.data.UserRepo.Update(user); err != nil {
+		return nil, errors.New("更新资料失败")
+	}
+	return publicProfile(user), nil
+}
+
+// publicProfile 转换为公开资料，不包含邮箱、角色等信息
+func publicProfile(user *po.User) *dto.PublicProfileResponse {
+	links := []dto.SocialLink{}
+	if user.SocialLinks != "" {
+		if err := json.Unmarshal([]byte(user.SocialLinks), &links); err != nil {
+			links = []dto.SocialLink{}
+		}
+	}
+	return &dto.PublicProfileResponse{
+		ID:          user.ID,
+		Username:    user.Username,
+		Nickname:    user.Nickname,
+		Avatar:      user.Avatar,
+		Bio:         user.Bio,
+		Website:     user.Website,
+		SocialLinks: links,
+		Skills:      user.Skills,
+		JoinedAt:    user.CreatedAt,
+	}
+}
+
+// webURL 是否为 http(s) 地址，避免在主页渲染 javascript: 等链接
+func webURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,76 @@
+package biz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestUpdatePublicProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     dto.UpdatePublicProfileRequest
+		err     error
+		website string
+		links   int
+		bio     string
+	}{
+		{name: "keeps unset fields", req: dto.UpdatePublicProfileRequest{Nickname: strPtr(" Alice ")}, website: "https://old.example.com", links: 1, bio: "old bio"},
+		{name: "clears website", req: dto.UpdatePublicProfileRequest{Website: strPtr("")}, links: 1, bio: "old bio"},
+		{name: "replaces links", req: dto.UpdatePublicProfileRequest{SocialLinks: &[]dto.SocialLink{
+			{Platform: "github", URL: "https://github.com/alice"},
+			{Platform: "weibo", URL: "http://weibo.com/alice"},
+		}}, website: "https://old.example.com", links: 2, bio: "old bio"},
+		{name: "rejects javascript website", req: dto.UpdatePublicProfileRequest{Website: strPtr("javascript:alert(1)")}, err: ErrProfileLinkInvalid},
+		{name: "rejects link without host", req: dto.UpdatePublicProfileRequest{SocialLinks: &[]dto.SocialLink{{Platform: "x", URL: "https:///alice"}}}, err: ErrProfileLinkInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &stubUserRepo{users: map[uint]*po.User{1: {
+				ID: 1, Username: "alice", Bio: "old bio", Status: po.UserStatusActive,
+				Website: "https://old.example.com", SocialLinks: `[{"platform":"github","url":"https://github.com/old"}]`,
+			}}}
+			uc := NewUserProfileUseCase(&data.Data{UserRepo: users})
+
+			profile, err := uc.UpdateMine(1, &tt.req)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("UpdateMine() error = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				if users.users[1].Website != "https://old.example.com" {
+					t.Errorf("rejected update was saved: %+v", users.users[1])
+				}
+				return
+			}
+			if profile.Website != tt.website || len(profile.SocialLinks) != tt.links || profile.Bio != tt.bio {
+				t.Errorf("profile = %+v", profile)
+			}
+			saved := publicProfile(users.users[1])
+			if saved.Website != tt.website || len(saved.SocialLinks) != tt.links {
+				t.Errorf("saved = %+v", saved)
+			}
+			if tt.req.Nickname != nil && saved.Nickname != "Alice" {
+				t.Errorf("nickname = %q", saved.Nickname)
+			}
+		})
+	}
+}
+
+func TestPublicProfileHidesInactiveUsers(t *testing.T) {
+	users := &stubUserRepo{users: map[uint]*po.User{
+		1: {ID: 1, Username: "banned", Status: po.UserStatusBanned},
+		2: {ID: 2, Username: "pending", Status: po.UserStatusUnverified},
+	}}
+	uc := NewUserProfileUseCase(&data.Data{UserRepo: users})
+
+	for _, username := range []string{"banned", "pending", "missing"} {
+		if _, err := uc.GetPublic(username, &dto.PageRequest{Page: 1, Limit: 10}); !errors.Is(err, ErrProfileNotFound) {
+			t.Errorf("GetPublic(%s) = %v, want ErrProfileNotFound", username, err)
+		}
+	}
+}
@@ -0,0 +1,581 @@
+package biz
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/webhook"
+)
+
+const (
+	defaultWebhookTimeout     = 10 * time.Second
+	defaultWebhookMaxAttempts = 6
+	// webhookRetryBatchSize 每次扫描最多重试的投递数
+	webhookRetryBatchSize = 100
+	// webhookErrorMaxLen 投递错误信息最多保留的字符数
+	webhookErrorMaxLen = 500
+)
+
+// webhookBackoff 第 N 次失败后等待的时间，超出部分使用最后一项
+var webhookBackoff = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	6 * time.Hour,
+}
+
+// ErrWebhookNotFound Webhook 不存在
+var ErrWebhookNotFound = errors.New("Webhook 不存在")
+
+// ErrWebhookDeliveryNotFound 投递记录不存在
+var ErrWebhookDeliveryNotFound = errors.New("投递记录不存在")
+
+// ErrInvalidWebhookEvent 订阅了不支持的事件
+var ErrInvalidWebhookEvent = errors.New("不支持的事件类型，可选值：" + strings.Join(po.WebhookEvents, ", "))
+
+// WebhookUseCase Webhook 业务用例接口
+type WebhookUseCase interface {
+	// List 查询 Webhook 列表
+	List(req *dto.PageRequest) (*dto.PageResponse, error)
+	// Get 获取 Webhook 详情
+	Get(id uint) (*dto.WebhookResponse, error)
+	// Create 创建 Webhook，未指定密钥时自动生成并在响应中返回
+	Create(req *dto.CreateWebhookRequest) (*dto.WebhookResponse, error)
+	// Update 更新 Webhook
+	Update(id uint, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error)
+	// Delete 删除 Webhook 及其投递记录
+	Delete(id uint) error
+	// Ping 同步发送一次测试投递
+	Ping(ctx context.Context, id uint) (*dto.WebhookDeliveryResponse, error)
+	// ListDeliveries 查询投递记录
+	ListDeliveries(webhookID uint, req *dto.WebhookDeliveryListRequest) (*dto.PageResponse, error)
+	// Redeliver 使用原请求体重新投递一次
+	Redeliver(ctx context.Context, webhookID, deliveryID uint) (*dto.WebhookDeliveryResponse, error)
+	// RetryDue 重试到达重试时间的投递，返回处理的数量
+	RetryDue() (int, error)
+}
+
+// webhookUseCase Webhook 业务用例实现
+type webhookUseCase struct {
+	data *data.Data
+}
+
+// NewWebhookUseCase 创建 Webhook 业务用例
+func NewWebhookUseCase(d *data.Data) WebhookUseCase {
+	return &webhookUseCase{data: d}
+}
+
+// List 查询 Webhook 列表
+func (uc *webhookUseCase) List(req *dto.PageRequest) (*dto.PageResponse, error) {
+	list, total, err := uc.data.WebhookRepo.List(req.Page, req.Limit)
+	if err != nil {
+		return nil, errors.New("查询 Webhook 列表失败")
+	}
+
+	items := make([]*dto.WebhookResponse, 0, len(list))
+	for _, hook := range list {
+		items = append(items, convertToWebhookResponse(hook))
+	}
+
+	return &dto.PageResponse{
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Data:  items,
+	}, nil
+}
+
+// Get 获取 Webhook 详情
+func (uc *webhookUseCase) Get(id uint) (*dto.WebhookResponse, error) {
+	hook, err := uc.data.WebhookRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrWebhookNotFound
+	}
+	return convertToWebhookResponse(hook), nil
+}
+
+// Create 创建 Webhook
+func (uc *webhookUseCase) Create(req *dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
+	events, err := normalizeWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, errors.New("生成签名密钥失败")
+		}
+	}
+
+	hook := &po.Webhook{
+		Name:    req.Name,
+		URL:     req.URL,
+		Secret:  secret,
+		Events:  events,
+		Enabled: req.Enabled == nil || *req.Enabled,
+	}
+	if err := uc.data.WebhookRepo.Create(hook); err != nil {
+		return nil, errors.New("创建 Webhook 失败")
+	}
+
+	resp := convertToWebhookResponse(hook)
+	resp.Secret = secret
+	return resp, nil
+}
+
+// Update 更新 Webhook
+func (uc *webhookUseCase) Update(id uint, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error) {
+	hook, err := uc.data.WebhookRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	events, err := normalizeWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	hook.Name = req.Name
+	hook.URL = req.URL
+	hook.Events = events
+	if req.Secret != "" {
+		hook.Secret = req.Secret
+	}
+	if req.Enabled != nil {
+		hook.Enabled = *req.Enabled
+	}
+	if err := uc.data.WebhookRepo.Update(hook); err != nil {
+		return nil, errors.New("更新 Webhook 失败")
+	}
+
+	resp := convertToWebhookResponse(hook)
+	resp.Secret = req.Secret
+	return resp, nil
+}
+
+// Delete 删除 Webhook
+func (uc *webhookUseCase) Delete(id uint) error {
+	if _, err := uc.data.WebhookRepo.FindByID(id); err != nil {
+		return ErrWebhookNotFound
+	}
+	if err := uc.data.WebhookRepo.Delete(id); err != nil {
+		return errors.New("删除 Webhook 失败")
+	}
+	return nil
+}
+
+// Ping 发送 ping 事件，不论 Webhook 是否启用或订阅
+func (uc *webhookUseCase) Ping(ctx context.Context, id uint) (*dto.WebhookDeliveryResponse, error) {
+	hook, err := uc.data.WebhookRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	payload, err := marshalWebhookPayload(po.WebhookEventPing, map[string]interface{}{
+		"webhook_id": hook.ID,
+		"events":     splitWebhookEvents(hook.Events),
+	})
+	if err != nil {
+		return nil, errors.New("生成投递内容失败")
+	}
+
+	delivery, err := createDelivery(ctx, uc.data, hook, po.WebhookEventPing, payload)
+	if err != nil {
+		return nil, errors.New("创建投递记录失败")
+	}
+	attemptDelivery(ctx, uc.data, hook, delivery, false)
+	return convertToWebhookDeliveryResponse(delivery), nil
+}
+
+// ListDeliveries 查询投递记录
+func (uc *webhookUseCase) ListDeliveries(webhookID uint, req *dto.WebhookDeliveryListRequest) (*dto.PageResponse, error) {
+	if _, err := uc.data.WebhookRepo.FindByID(webhookID); err != nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	list, total, err := uc.data.WebhookRepo.ListDeliveries(req.Page, req.Limit, webhookID, req.Status)
+	if err != nil {
+		return nil, errors.New("查询投递记录失败")
+	}
+
+	items := make([]*dto.WebhookDeliveryResponse, 0, len(list))
+	for _, delivery := range list {
+		items = append(items, convertToWebhookDeliveryResponse(delivery))
+	}
+
+	return &dto.PageResponse{
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Data:  items,
+	}, nil
+}
+
+// Redeliver 手动重新投递，生成新的投递记录且失败后不再自动重试
+func (uc *webhookUseCase) Redeliver(ctx context.Context, webhookID, deliveryID uint) (*dto.WebhookDeliveryResponse, error) {
+	original, err := uc.data.WebhookRepo.FindDelivery(deliveryID)
+	if err != nil || original.WebhookID != webhookID || original.Webhook == nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+
+	delivery, err := createDelivery(ctx, uc.data, original.Webhook, original.Event, []byte(original.Payload))
+	if err != nil {
+		return nil, errors.New("创建投递记录失败")
+	}
+	attemptDelivery(ctx, uc.data, original.Webhook, delivery, false)
+	return convertToWebhookDeliveryResponse(delivery), nil
+}
+
+// RetryDue 重试到达重试时间的投递
+func (uc *webhookUseCase) RetryDue() (int, error) {
+	now := time.Now()
+	list, err := uc.data.WebhookRepo.ListDueDeliveries(now, webhookRetryBatchSize)
+	if err != nil {
+		return 0, errors.New("查询待重试投递失败")
+	}
+
+	retried := 0
+	for _, delivery := range list {
+		// 重试沿用首次投递时记录的请求ID
+		ctx := logger.WithRequestID(context.Background(), delivery.RequestID)
+		// 先推迟重试时间再投递，其他实例不会重复处理
+		claimed, err := uc.data.WebhookRepo.ClaimDelivery(delivery, time.Now().Add(webhookLease()))
+		if err != nil || !claimed {
+			continue
+		}
+
+		if delivery.Webhook == nil || !delivery.Webhook.Enabled {
+			delivery.Status = po.WebhookDeliveryFailed
+			delivery.Error = "Webhook 已停用"
+			delivery.NextRetryAt = nil
+			if err := uc.data.WebhookRepo.SaveDelivery(delivery); err != nil {
+				logger.FromContext(ctx).Warn("Failed to save webhook delivery: ", err)
+			}
+			continue
+		}
+
+		attemptDelivery(ctx, uc.data, delivery.Webhook, delivery, true)
+		retried++
+	}
+	return retried, nil
+}
+
+// notifyArticles 异步投递文章事件，投递前重新查询文章以携带最新内容；发布事件同时发送浏览器推送
+// ctx 只用于传递请求ID，请求结束后投递不会被取消
+func notifyArticles(ctx context.Context, d *data.Data, event string, articleIDs ...uint) {
+	if len(articleIDs) == 0 {
+		return
+	}
+	if event == po.WebhookEventArticlePublished {
+		pushArticles(ctx, d, articleIDs...)
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		hooks := webhookSubscribers(ctx, d, event)
+		if len(hooks) == 0 {
+			return
+		}
+
+		articles, err := d.ArticleRepo.FindByIDs(articleIDs)
+		if err != nil {
+			logger.FromContext(ctx).Warn("Failed to load articles for webhook: ", err)
+			return
+		}
+		for _, article := range articles {
+			deliverEvent(ctx, d, hooks, event, convertToWebhookArticle(article))
+		}
+	}()
+}
+
+// notifyDeletedArticles 异步投递文章删除事件，使用删除前查询到的文章
+func notifyDeletedArticles(ctx context.Context, d *data.Data, articles ...*po.Article) {
+	if len(articles) == 0 {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		hooks := webhookSubscribers(ctx, d, po.WebhookEventArticleDeleted)
+		for _, article := range articles {
+			deliverEvent(ctx, d, hooks, po.WebhookEventArticleDeleted, convertToWebhookArticle(article))
+		}
+	}()
+}
+
+// notifyComment 异步投递评论创建事件
+func notifyComment(ctx context.Context, d *data.Data, comment *po.Comment) {
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		hooks := webhookSubscribers(ctx, d, po.WebhookEventCommentCreated)
+		deliverEvent(ctx, d, hooks, po.WebhookEventCommentCreated, &dto.WebhookComment{
+			ID:        comment.ID,
+			ArticleID: comment.ArticleID,
+			UserID:    comment.UserID,
+			ParentID:  comment.ParentID,
+			Content:   comment.Content,
+			Status:    comment.Status,
+			CreatedAt: comment.CreatedAt,
+		})
+	}()
+}
+
+// webhookSubscribers 查询订阅了指定事件的启用中的 Webhook
+func webhookSubscribers(ctx context.Context, d *data.Data, event string) []*po.Webhook {
+	hooks, err := d.WebhookRepo.ListEnabled()
+	if err != nil {
+		logger.FromContext(ctx).Warn("Failed to load webhooks: ", err)
+		return nil
+	}
+
+	subscribed := make([]*po.Webhook, 0, len(hooks))
+	for _, hook := range hooks {
+		for _, e := range splitWebhookEvents(hook.Events) {
+			if e == event {
+				subscribed = append(subscribed, hook)
+				break
+			}
+		}
+	}
+	return subscribed
+}
+
+// deliverEvent 为每个 Webhook 创建投递记录并立即投递一次，失败的由重试任务接管
+func deliverEvent(ctx context.Context, d *data.Data, hooks []*po.Webhook, event string, eventData interface{}) {
+	if len(hooks) == 0 {
+		return
+	}
+
+	payload, err := marshalWebhookPayload(event, eventData)
+	if err != nil {
+		logger.FromContext(ctx).Warn("Failed to marshal webhook payload: ", err)
+		return
+	}
+
+	for _, hook := range hooks {
+		delivery, err := createDelivery(ctx, d, hook, event, payload)
+		if err != nil {
+			logger.FromContext(ctx).Warn("Failed to create webhook delivery: ", err)
+			continue
+		}
+		attemptDelivery(ctx, d, hook, delivery, true)
+	}
+}
+
+// createDelivery 创建待投递记录，记录 ctx 中的请求ID；重试时间先设为租约到期时间，进程在投递中退出时由重试任务接管
+func createDelivery(ctx context.Context, d *data.Data, hook *po.Webhook, event string, payload []byte) (*po.WebhookDelivery, error) {
+	lease := time.Now().Add(webhookLease())
+	delivery := &po.WebhookDelivery{
+		WebhookID:   hook.ID,
+		Event:       event,
+		Payload:     string(payload),
+		Status:      po.WebhookDeliveryPending,
+		NextRetryAt: &lease,
+		RequestID:   logger.RequestIDFromContext(ctx),
+	}
+	if err := d.WebhookRepo.CreateDelivery(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// attemptDelivery 投递一次并保存结果，retry 为 false 时失败后不再重试
+func attemptDelivery(ctx context.Context, d *data.Data, hook *po.Webhook, delivery *po.WebhookDelivery, retry bool) {
+	result, err := webhook.Send(&http.Client{Timeout: webhookTimeout()}, &webhook.Request{
+		URL:        hook.URL,
+		Secret:     hook.Secret,
+		Event:      delivery.Event,
+		DeliveryID: delivery.ID,
+		Body:       []byte(delivery.Payload),
+		RequestID:  delivery.RequestID,
+	})
+
+	delivery.Attempts++
+	if result != nil {
+		delivery.ResponseStatus = result.StatusCode
+		delivery.ResponseBody = result.Body
+		delivery.Duration = result.Duration.Milliseconds()
+	}
+
+	now := time.Now()
+	switch {
+	case err == nil:
+		delivery.Status = po.WebhookDeliverySuccess
+		delivery.Error = ""
+		delivery.NextRetryAt = nil
+		delivery.DeliveredAt = &now
+	case retry && delivery.Attempts < webhookMaxAttempts():
+		delivery.Error = truncateWebhookError(err.Error())
+		next := now.Add(webhookRetryDelay(delivery.Attempts))
+		delivery.NextRetryAt = &next
+	default:
+		delivery.Status = po.WebhookDeliveryFailed
+		delivery.Error = truncateWebhookError(err.Error())
+		delivery.NextRetryAt = nil
+	}
+
+	if err := d.WebhookRepo.SaveDelivery(delivery); err != nil {
+		logger.FromContext(ctx).Warn("Failed to save webhook delivery: ", err)
+	}
+}
+
+// marshalWebhookPayload 生成投递的请求体
+func marshalWebhookPayload(event string, eventData interface{}) ([]byte, error) {
+	return json.Marshal(&dto.WebhookPayload{
+		Event:     event,
+		CreatedAt: time.Now(),
+		Data:      eventData,
+	})
+}
+
+// normalizeWebhookEvents 校验并去重订阅的事件，返回逗号分隔的字符串
+func normalizeWebhookEvents(events []string) (string, error) {
+	seen := make(map[string]bool, len(events))
+	result := make([]string, 0, len(events))
+	for _, event := range events {
+		event = strings.TrimSpace(event)
+		if !isWebhookEvent(event) {
+			return "", ErrInvalidWebhookEvent
+		}
+		if !seen[event] {
+			seen[event] = true
+			result = append(result, event)
+		}
+	}
+	return strings.Join(result, ","), nil
+}
+
+// isWebhookEvent 是否为可订阅的事件
+func isWebhookEvent(event string) bool {
+	for _, e := range po.WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// splitWebhookEvents 拆分逗号分隔的事件
+func splitWebhookEvents(events string) []string {
+	if events == "" {
+		return []string{}
+	}
+	return strings.Split(events, ",")
+}
+
+// generateWebhookSecret 生成 32 字节随机签名密钥
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// webhookTimeout 投递请求超时
+func webhookTimeout() time.Duration {
+	if t := config.AppConfig.Webhook.Timeout; t > 0 {
+		return time.Duration(t) * time.Second
+	}
+	return defaultWebhookTimeout
+}
+
+// webhookLease 投递租约时长，超过后视为投递中断
+func webhookLease() time.Duration {
+	return webhookTimeout() + time.Minute
+}
+
+// webhookMaxAttempts 最多投递次数
+func webhookMaxAttempts() int {
+	if n := config.AppConfig.Webhook.MaxAttempts; n > 0 {
+		return n
+	}
+	return defaultWebhookMaxAttempts
+}
+
+// webhookRetryDelay 第 attempts 次失败后的等待时间
+func webhookRetryDelay(attempts int) time.Duration {
+	if attempts > len(webhookBackoff) {
+		attempts = len(webhookBackoff)
+	}
+	return webhookBackoff[attempts-1]
+}
+
+// truncateWebhookError 截断错误信息
+func truncateWebhookError(msg string) string {
+	runes := []rune(msg)
+	if len(runes) <= webhookErrorMaxLen {
+		return msg
+	}
+	return string(runes[:webhookErrorMaxLen])
+}
+
+// convertToWebhookResponse 转换 Webhook 响应（不包含密钥）
+func convertToWebhookResponse(hook *po.Webhook) *dto.WebhookResponse {
+	return &dto.WebhookResponse{
+		ID:        hook.ID,
+		Name:      hook.Name,
+		URL:       hook.URL,
+		Events:    splitWebhookEvents(hook.Events),
+		Enabled:   hook.Enabled,
+		CreatedAt: hook.CreatedAt,
+		UpdatedAt: hook.UpdatedAt,
+	}
+}
+
+// convertToWebhookDeliveryResponse 转换投递记录响应
+func convertToWebhookDeliveryResponse(delivery *po.WebhookDelivery) *dto.WebhookDeliveryResponse {
+	return &dto.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		Event:          delivery.Event,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		ResponseBody:   delivery.ResponseBody,
+		Error:          delivery.Error,
+		Duration:       delivery.Duration,
+		RequestID:      delivery.RequestID,
+		NextRetryAt:    delivery.NextRetryAt,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
+
+// convertToWebhookArticle 转换文章事件数据（不包含正文）
+func convertToWebhookArticle(article *po.Article) *dto.WebhookArticle {
+	return &dto.WebhookArticle{
+		ID:         article.ID,
+		Title:      article.Title,
+		Slug:       articleSlug(article),
+		Summary:    article.Summary,
+		Status:     article.Status,
+		Visibility: article.Visibility,
+		CategoryID: article.CategoryID,
+		AuthorID:   article.AuthorID,
+		CreatedAt:  article.CreatedAt,
+		UpdatedAt:  article.UpdatedAt,
+	}
+}
+
+// articleStatusEvent 根据状态变化选择文章事件：由非发布状态变为发布时为 published，其余为 updated
+func articleStatusEvent(before, after int) string {
+	if before != 1 && after == 1 {
+		return po.WebhookEventArticlePublished
+	}
+	return po.WebhookEventArticleUpdated
+}
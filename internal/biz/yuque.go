@@ -0,0 +1,526 @@
+package biz
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	mdutils "github.com/ydcloud-dy/leaf-api/pkg/markdown"
+	"github.com/ydcloud-dy/leaf-api/pkg/yuque"
+)
+
+const (
+	defaultYuqueTimeout = 30 * time.Second
+	// yuqueSyncLease 同步租约时长，实例中途退出时租约到期后可重新同步
+	yuqueSyncLease = 30 * time.Minute
+	// yuqueRootUUID 知识库根章节在节点映射中的 uuid，存放没有上级分组的文档
+	yuqueRootUUID = "root"
+	// yuqueErrorMaxLen 同步错误信息最多保留的字符数
+	yuqueErrorMaxLen = 500
+)
+
+// ErrYuqueSyncNotFound 同步配置不存在
+var ErrYuqueSyncNotFound = errors.New("语雀同步配置不存在")
+
+// ErrYuqueSyncExists 知识库已配置同步
+var ErrYuqueSyncExists = errors.New("该知识库已配置同步")
+
+// ErrYuqueSyncRunning 知识库正在同步
+var ErrYuqueSyncRunning = errors.New("该知识库正在同步，请稍后再试")
+
+// YuqueUseCase 语雀同步业务用例接口
+type YuqueUseCase interface {
+	// List 查询同步配置列表
+	List(req *dto.PageRequest) (*dto.PageResponse, error)
+	// Get 获取同步配置详情
+	Get(id uint) (*dto.YuqueSyncResponse, error)
+	// Create 校验 Token 并创建同步配置，以知识库名称创建笔记标签
+	Create(req *dto.CreateYuqueSyncRequest, authorID uint) (*dto.YuqueSyncResponse, error)
+	// Update 更新同步配置
+	Update(id uint, req *dto.UpdateYuqueSyncRequest) (*dto.YuqueSyncResponse, error)
+	// Delete 删除同步配置，已同步的文章和章节保留
+	Delete(id uint) error
+	// Sync 同步知识库：目录映射为章节，新文档创建文章，正文有更新的文档更新文章
+	Sync(id uint) (*dto.YuqueSyncReport, error)
+	// SyncAll 同步全部启用的知识库，返回成功同步的数量
+	SyncAll() (int, error)
+}
+
+// yuqueUseCase 语雀同步业务用例实现
+type yuqueUseCase struct {
+	data     *data.Data
+	articles *articleUseCase
+}
+
+// NewYuqueUseCase 创建语雀同步业务用例
+func NewYuqueUseCase(d *data.Data) YuqueUseCase {
+	return &yuqueUseCase{data: d, articles: &articleUseCase{data: d}}
+}
+
+// List 查询同步配置列表
+func (uc *yuqueUseCase) List(req *dto.PageRequest) (*dto.PageResponse, error) {
+	list, total, err := uc.data.YuqueRepo.List(req.Page, req.Limit)
+	if err != nil {
+		return nil, errors.New("查询语雀同步列表失败")
+	}
+
+	tagIDs := make([]uint, 0, len(list))
+	for _, s := range list {
+		tagIDs = append(tagIDs, s.TagID)
+	}
+	tagNames := make(map[uint]string, len(tagIDs))
+	if tags, err := uc.data.TagRepo.FindByIDs(tagIDs); err == nil {
+		for _, tag := range tags {
+			tagNames[tag.ID] = tag.Name
+		}
+	}
+
+	items := make([]*dto.YuqueSyncResponse, 0, len(list))
+	for _, s := range list {
+		items = append(items, convertToYuqueSyncResponse(s, tagNames[s.TagID]))
+	}
+
+	return &dto.PageResponse{
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Data:  items,
+	}, nil
+}
+
+// Get 获取同步配置详情
+func (uc *yuqueUseCase) Get(id uint) (*dto.YuqueSyncResponse, error) {
+	s, err := uc.data.YuqueRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrYuqueSyncNotFound
+	}
+	return uc.toResponse(s), nil
+}
+
+// Create 创建同步配置
+func (uc *yuqueUseCase) Create(req *dto.CreateYuqueSyncRequest, authorID uint) (*dto.YuqueSyncResponse, error) {
+	namespace := strings.Trim(strings.TrimSpace(req.Namespace), "/")
+	if _, err := uc.data.YuqueRepo.FindByNamespace(namespace); err == nil {
+		return nil, ErrYuqueSyncExists
+	}
+
+	// 校验 Token 和知识库
+	repo, err := newYuqueClient(req.Token).Repo(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryID := req.CategoryID
+	if categoryID == 0 {
+		if categoryID, err = uc.articles.GetDefaultCategoryID(); err != nil {
+			return nil, err
+		}
+	} else if _, err := uc.data.CategoryRepo.FindByID(categoryID); err != nil {
+		return nil, errors.New("分类不存在")
+	}
+
+	tag, err := uc.articles.findOrCreateTag(repo.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &po.YuqueSync{
+		Namespace:   namespace,
+		Name:        repo.Name,
+		Token:       req.Token,
+		TagID:       tag.ID,
+		CategoryID:  categoryID,
+		AuthorID:    authorID,
+		AutoPublish: req.AutoPublish,
+		Enabled:     req.Enabled == nil || *req.Enabled,
+	}
+	if err := uc.data.YuqueRepo.Create(s); err != nil {
+		return nil, errors.New("创建语雀同步失败")
+	}
+	return convertToYuqueSyncResponse(s, tag.Name), nil
+}
+
+// Update 更新同步配置
+func (uc *yuqueUseCase) Update(id uint, req *dto.UpdateYuqueSyncRequest) (*dto.YuqueSyncResponse, error) {
+	s, err := uc.data.YuqueRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrYuqueSyncNotFound
+	}
+
+	if req.Token != "" {
+		if _, err := newYuqueClient(req.Token).Repo(s.Namespace); err != nil {
+			return nil, err
+		}
+		s.Token = req.Token
+	}
+	if req.CategoryID > 0 {
+		if _, err := uc.data.CategoryRepo.FindByID(req.CategoryID); err != nil {
+			return nil, errors.New("分类不存在")
+		}
+		s.CategoryID = req.CategoryID
+	}
+	if req.AutoPublish != nil {
+		s.AutoPublish = *req.AutoPublish
+	}
+	if req.Enabled != nil {
+		s.Enabled = *req.Enabled
+	}
+
+	if err := uc.data.YuqueRepo.Update(s); err != nil {
+		return nil, errors.New("更新语雀同步失败")
+	}
+	return uc.toResponse(s), nil
+}
+
+// Delete 删除同步配置
+func (uc *yuqueUseCase) Delete(id uint) error {
+	if _, err := uc.data.YuqueRepo.FindByID(id); err != nil {
+		return ErrYuqueSyncNotFound
+	}
+	if err := uc.data.YuqueRepo.Delete(id); err != nil {
+		return errors.New("删除语雀同步失败")
+	}
+	return nil
+}
+
+// Sync 同步知识库
+func (uc *yuqueUseCase) Sync(id uint) (*dto.YuqueSyncReport, error) {
+	s, err := uc.data.YuqueRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrYuqueSyncNotFound
+	}
+
+	now := time.Now()
+	claimed, err := uc.data.YuqueRepo.Claim(s.ID, now, now.Add(yuqueSyncLease))
+	if err != nil {
+		return nil, errors.New("获取同步租约失败")
+	}
+	if !claimed {
+		return nil, ErrYuqueSyncRunning
+	}
+
+	report, err := uc.sync(s)
+
+	// 记录同步结果并释放租约
+	if err != nil {
+		s.LastError = truncateRunes(err.Error(), yuqueErrorMaxLen)
+	} else {
+		synced := time.Now()
+		s.LastSyncedAt = &synced
+		s.LastError = ""
+	}
+	_ = uc.data.YuqueRepo.Release(s)
+
+	if err != nil {
+		return nil, err
+	}
+	if report.Chapters > 0 || report.Created > 0 {
+		InvalidateSitemap()
+	}
+	return report, nil
+}
+
+// SyncAll 同步全部启用的知识库
+func (uc *yuqueUseCase) SyncAll() (int, error) {
+	list, err := uc.data.YuqueRepo.ListEnabled()
+	if err != nil {
+		return 0, err
+	}
+
+	synced := 0
+	var errs []string
+	for _, s := range list {
+		if _, err := uc.Sync(s.ID); err != nil {
+			if !errors.Is(err, ErrYuqueSyncRunning) {
+				errs = append(errs, s.Namespace+": "+err.Error())
+			}
+			continue
+		}
+		synced++
+	}
+	if len(errs) > 0 {
+		return synced, errors.New(strings.Join(errs, "; "))
+	}
+	return synced, nil
+}
+
+// yuqueSyncRun 一次同步的上下文
+type yuqueSyncRun struct {
+	uc       *yuqueUseCase
+	sync     *po.YuqueSync
+	client   *yuque.Client
+	tag      *po.Tag
+	items    map[string]*po.YuqueItem // uuid -> 节点映射
+	chapters map[string]*uint         // uuid -> 本次同步确定的章节
+	sorts    map[string]int           // 父节点 uuid -> 下一个子节点序号
+	report   *dto.YuqueSyncReport
+}
+
+// sync 拉取目录和文档列表，逐个节点同步
+func (uc *yuqueUseCase) sync(s *po.YuqueSync) (*dto.YuqueSyncReport, error) {
+	client := newYuqueClient(s.Token)
+	toc, err := client.TOC(s.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := client.Docs(s.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	contentUpdated := make(map[uint]time.Time, len(docs))
+	for _, doc := range docs {
+		contentUpdated[doc.ID] = doc.ContentUpdatedAt
+	}
+
+	// 笔记标签被删除时按知识库名称重新创建
+	tag, err := uc.data.TagRepo.FindByID(s.TagID)
+	if err != nil {
+		if tag, err = uc.articles.findOrCreateTag(s.Name); err != nil {
+			return nil, err
+		}
+		s.TagID = tag.ID
+		_ = uc.data.GetDB().Model(&po.YuqueSync{}).Where("id = ?", s.ID).UpdateColumn("tag_id", tag.ID).Error
+	}
+
+	existing, err := uc.data.YuqueRepo.ListItems(s.ID)
+	if err != nil {
+		return nil, errors.New("查询同步记录失败")
+	}
+	run := &yuqueSyncRun{
+		uc:       uc,
+		sync:     s,
+		client:   client,
+		tag:      tag,
+		items:    make(map[string]*po.YuqueItem, len(existing)),
+		chapters: make(map[string]*uint),
+		sorts:    make(map[string]int),
+		report:   &dto.YuqueSyncReport{},
+	}
+	for _, item := range existing {
+		run.items[item.UUID] = item
+	}
+
+	hasChildren := make(map[string]bool)
+	for _, node := range toc {
+		if node.ParentUUID != "" {
+			hasChildren[node.ParentUUID] = true
+		}
+	}
+
+	// 目录按先序返回，父节点总是先于子节点处理
+	for _, node := range toc {
+		parentChapter := run.chapters[node.ParentUUID]
+		sort := run.sorts[node.ParentUUID]
+		run.sorts[node.ParentUUID]++
+
+		chapterID := parentChapter
+		if node.Type == yuque.NodeTitle || hasChildren[node.UUID] {
+			id, err := run.ensureChapter(node.UUID, node.DocID, node.Title, parentChapter, sort)
+			if err != nil {
+				run.fail(node.Title, err)
+				continue
+			}
+			run.chapters[node.UUID] = id
+			chapterID = id
+		}
+
+		if node.Type != yuque.NodeDoc {
+			continue
+		}
+		if chapterID == nil {
+			id, err := run.ensureChapter(yuqueRootUUID, 0, s.Name, nil, 0)
+			if err != nil {
+				run.fail(node.Title, err)
+				continue
+			}
+			run.chapters[yuqueRootUUID] = id
+			chapterID = id
+		}
+		if err := run.syncDoc(node, contentUpdated[node.DocID], chapterID); err != nil {
+			run.fail(node.Title, err)
+		}
+	}
+
+	return run.report, nil
+}
+
+// ensureChapter 创建或更新节点对应的章节，章节在本地被删除时重新创建
+func (run *yuqueSyncRun) ensureChapter(uuid string, docID uint, name string, parentID *uint, sort int) (*uint, error) {
+	if id, ok := run.chapters[uuid]; ok {
+		return id, nil
+	}
+	db := run.uc.data.GetDB()
+	name = truncateRunes(name, 200)
+
+	item := run.item(uuid, docID)
+	if item.ChapterID != nil {
+		result := db.Model(&po.Chapter{}).Where("id = ?", *item.ChapterID).Updates(map[string]interface{}{
+			"name":      name,
+			"parent_id": parentID,
+			"sort":      sort,
+		})
+		if result.Error != nil {
+			return nil, errors.New("更新章节失败")
+		}
+		var count int64
+		db.Model(&po.Chapter{}).Where("id = ?", *item.ChapterID).Count(&count)
+		if count > 0 {
+			return item.ChapterID, nil
+		}
+	}
+
+	chapter := &po.Chapter{
+		TagID:    run.tag.ID,
+		ParentID: parentID,
+		Name:     name,
+		Sort:     sort,
+	}
+	if err := db.Create(chapter).Error; err != nil {
+		return nil, errors.New("创建章节失败")
+	}
+	item.ChapterID = &chapter.ID
+	if err := run.uc.data.YuqueRepo.SaveItem(item); err != nil {
+		return nil, errors.New("保存同步记录失败")
+	}
+	run.report.Chapters++
+	return item.ChapterID, nil
+}
+
+// syncDoc 同步单个文档
+// 已同步且正文未更新的文档只调整所属章节；本地已删除的文章不再重新创建
+func (run *yuqueSyncRun) syncDoc(node yuque.TOCNode, contentUpdatedAt time.Time, chapterID *uint) error {
+	item := run.item(node.UUID, node.DocID)
+
+	if item.ArticleID != nil {
+		article, err := run.uc.data.ArticleRepo.FindByID(*item.ArticleID)
+		if err != nil {
+			run.report.Skipped++
+			return nil
+		}
+		if item.ContentUpdatedAt != nil && !contentUpdatedAt.After(*item.ContentUpdatedAt) {
+			if !sameChapter(article.ChapterID, chapterID) {
+				if err := run.uc.data.GetDB().Model(&po.Article{}).Where("id = ?", article.ID).
+					UpdateColumn("chapter_id", chapterID).Error; err != nil {
+					return errors.New("更新文章章节失败")
+				}
+			}
+			run.report.Skipped++
+			return nil
+		}
+
+		doc, err := run.client.Doc(run.sync.Namespace, node.URL)
+		if err != nil {
+			return err
+		}
+		_, err = run.uc.articles.Update(article.ID, &dto.UpdateArticleRequest{
+			Title:           doc.Title,
+			ContentMarkdown: yuqueBody(doc),
+			ChapterID:       chapterID,
+			Status:          article.Status,
+		})
+		if err != nil {
+			return err
+		}
+		run.report.Updated++
+	} else {
+		doc, err := run.client.Doc(run.sync.Namespace, node.URL)
+		if err != nil {
+			return err
+		}
+		fm := &mdutils.FrontMatter{
+			Title:     doc.Title,
+			Tags:      []string{run.tag.Name},
+			CreatedAt: &doc.CreatedAt,
+		}
+		if run.sync.AutoPublish {
+			published := 1
+			fm.Status = &published
+		}
+		article, err := run.uc.articles.importArticle(node.Title, fm, yuqueBody(doc), nil, chapterID, run.sync.CategoryID, run.sync.AuthorID)
+		if err != nil {
+			return err
+		}
+		item.ArticleID = &article.ID
+		run.report.Created++
+	}
+
+	item.ContentUpdatedAt = &contentUpdatedAt
+	if err := run.uc.data.YuqueRepo.SaveItem(item); err != nil {
+		return errors.New("保存同步记录失败")
+	}
+	return nil
+}
+
+// item 获取节点映射，不存在时新建（未保存）
+func (run *yuqueSyncRun) item(uuid string, docID uint) *po.YuqueItem {
+	item, ok := run.items[uuid]
+	if !ok {
+		item = &po.YuqueItem{SyncID: run.sync.ID, UUID: uuid}
+		run.items[uuid] = item
+	}
+	item.DocID = docID
+	return item
+}
+
+// fail 记录同步失败的节点
+func (run *yuqueSyncRun) fail(title string, err error) {
+	run.report.Failed++
+	run.report.Errors = append(run.report.Errors, fmt.Sprintf("%s: %v", title, err))
+}
+
+// yuqueBody 文档正文，语雀的空文档返回空字符串时使用标题占位
+func yuqueBody(doc *yuque.Doc) string {
+	if strings.TrimSpace(doc.Body) == "" {
+		return "# " + doc.Title
+	}
+	return doc.Body
+}
+
+// sameChapter 比较两个可为空的章节 ID
+func sameChapter(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// newYuqueClient 按配置创建语雀客户端
+func newYuqueClient(token string) *yuque.Client {
+	timeout := time.Duration(config.AppConfig.Yuque.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = defaultYuqueTimeout
+	}
+	return yuque.NewClient(config.AppConfig.Yuque.BaseURL, token, timeout)
+}
+
+// toResponse 转换为响应，附带笔记标签名称
+func (uc *yuqueUseCase) toResponse(s *po.YuqueSync) *dto.YuqueSyncResponse {
+	tagName := ""
+	if tag, err := uc.data.TagRepo.FindByID(s.TagID); err == nil {
+		tagName = tag.Name
+	}
+	return convertToYuqueSyncResponse(s, tagName)
+}
+
+// convertToYuqueSyncResponse 转换为同步配置响应
+func convertToYuqueSyncResponse(s *po.YuqueSync, tagName string) *dto.YuqueSyncResponse {
+	return &dto.YuqueSyncResponse{
+		ID:           s.ID,
+		Namespace:    s.Namespace,
+		Name:         s.Name,
+		TagID:        s.TagID,
+		TagName:      tagName,
+		CategoryID:   s.CategoryID,
+		AutoPublish:  s.AutoPublish,
+		Enabled:      s.Enabled,
+		LastSyncedAt: s.LastSyncedAt,
+		LastError:    s.LastError,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
+	}
+}
@@ -0,0 +1,122 @@
+package data
+
+import (
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// AnnouncementRepo 站点公告仓储接口
+type AnnouncementRepo interface {
+	// Create 创建公告
+	Create(announcement *po.Announcement) error
+	// Update 更新公告
+	Update(announcement *po.Announcement) error
+	// Delete 删除公告
+	Delete(id uint) error
+	// FindByID 根据 ID 查询公告
+	FindByID(id uint) (*po.Announcement, error)
+	// List 查询公告列表，status 为空时查询全部，否则按 now 时刻的展示状态过滤
+	List(page, limit int, status, keyword string, now time.Time) ([]*po.Announcement, int64, error)
+	// ListActive 查询 now 时刻正在展示的公告，按创建时间倒序
+	ListActive(now time.Time) ([]*po.Announcement, error)
+	// ListPendingNotify 查询 now 时刻正在展示、需要推送且尚未推送的公告
+	ListPendingNotify(now time.Time) ([]*po.Announcement, error)
+	// MarkNotified 标记公告已推送，已被其他实例标记时返回 false
+	MarkNotified(id uint, at time.Time) (bool, error)
+}
+
+// announcementRepo 站点公告仓储实现
+type announcementRepo struct {
+	db *gorm.DB
+}
+
+// NewAnnouncementRepo 创建站点公告仓储
+func NewAnnouncementRepo(db *gorm.DB) AnnouncementRepo {
+	return &announcementRepo{db: db}
+}
+
+// Create 创建公告
+func (r *announcementRepo) Create(announcement *po.Announcement) error {
+	return r.db.Create(announcement).Error
+}
+
+// Update 更新公告
+func (r *announcementRepo) Update(announcement *po.Announcement) error {
+	return r.db.Save(announcement).Error
+}
+
+// Delete 删除公告
+func (r *announcementRepo) Delete(id uint) error {
+	return r.db.Delete(&po.Announcement{}, id).Error
+}
+
+// FindByID 根据 ID 查询公告
+func (r *announcementRepo) FindByID(id uint) (*po.Announcement, error) {
+	var announcement po.Announcement
+	if err := r.db.First(&announcement, id).Error; err != nil {
+		return nil, err
+	}
+	return &announcement, nil
+}
+
+// List 查询公告列表
+func (r *announcementRepo) List(page, limit int, status, keyword string, now time.Time) ([]*po.Announcement, int64, error) {
+	var announcements []*po.Announcement
+	var total int64
+
+	query := r.db.Model(&po.Announcement{})
+	switch status {
+	case po.AnnouncementStatusActive:
+		query = activeAnnouncements(query, now)
+	case po.AnnouncementStatusScheduled:
+		query = query.Where("enabled = ? AND start_at > ?", true, now)
+	case po.AnnouncementStatusExpired:
+		query = query.Where("enabled = ? AND end_at <= ?", true, now)
+	case po.AnnouncementStatusDisabled:
+		query = query.Where("enabled = ?", false)
+	}
+	if keyword != "" {
+		query = query.Where("title LIKE ? OR content LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&announcements).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return announcements, total, nil
+}
+
+// ListActive 查询正在展示的公告
+func (r *announcementRepo) ListActive(now time.Time) ([]*po.Announcement, error) {
+	var announcements []*po.Announcement
+	err := activeAnnouncements(r.db, now).Order("created_at DESC, id DESC").Find(&announcements).Error
+	return announcements, err
+}
+
+// ListPendingNotify 查询需要推送且尚未推送的公告
+func (r *announcementRepo) ListPendingNotify(now time.Time) ([]*po.Announcement, error) {
+	var announcements []*po.Announcement
+	err := activeAnnouncements(r.db, now).Where("notify = ? AND notified_at IS NULL", true).
+		Order("id ASC").Find(&announcements).Error
+	return announcements, err
+}
+
+// MarkNotified 标记公告已推送，多个实例同时推送时只有一个标记成功
+func (r *announcementRepo) MarkNotified(id uint, at time.Time) (bool, error) {
+	result := r.db.Model(&po.Announcement{}).Where("id = ? AND notified_at IS NULL", id).UpdateColumn("notified_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+// activeAnnouncements 正在展示的条件：已启用且 now 处于展示时间段内
+func activeAnnouncements(query *gorm.DB, now time.Time) *gorm.DB {
+	return query.Where("enabled = ?", true).
+		Where("start_at IS NULL OR start_at <= ?", now).
+		Where("end_at IS NULL OR end_at > ?", now)
+}
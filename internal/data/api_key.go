@@ -0,0 +1,70 @@
+package data
+
+import (
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepo API Key 仓储接口
+type APIKeyRepo interface {
+	// Create 创建 API Key
+	Create(key *po.APIKey) error
+	// FindByHash 根据令牌摘要查询
+	FindByHash(hash string) (*po.APIKey, error)
+	// ListByUser 查询用户的全部 API Key，最新创建的在前
+	ListByUser(userID uint) ([]*po.APIKey, error)
+	// Revoke 吊销用户的 API Key，返回是否存在未吊销的记录
+	Revoke(userID, id uint, at time.Time) (bool, error)
+	// Touch 更新最后使用时间
+	Touch(id uint, at time.Time) error
+}
+
+// apiKeyRepo API Key 仓储实现
+type apiKeyRepo struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepo 创建 API Key 仓储
+func NewAPIKeyRepo(db *gorm.DB) APIKeyRepo {
+	return &apiKeyRepo{db: db}
+}
+
+// Create 创建 API Key
+func (r *apiKeyRepo) Create(key *po.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+// FindByHash 根据令牌摘要查询
+func (r *apiKeyRepo) FindByHash(hash string) (*po.APIKey, error) {
+	var key po.APIKey
+	err := r.db.Where("key_hash = ?", hash).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListByUser 查询用户的全部 API Key
+func (r *apiKeyRepo) ListByUser(userID uint) ([]*po.APIKey, error) {
+	var keys []*po.APIKey
+	err := r.db.Where("user_id = ?", userID).Order("id DESC").Find(&keys).Error
+	return keys, err
+}
+
+// Revoke 吊销用户的 API Key
+func (r *apiKeyRepo) Revoke(userID, id uint, at time.Time) (bool, error) {
+	result := r.db.Model(&po.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Touch 更新最后使用时间
+func (r *apiKeyRepo) Touch(id uint, at time.Time) error {
+	return r.db.Model(&po.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error
+}
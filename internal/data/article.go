@@ -1,10 +1,12 @@
 package data
 
 import (
+	"errors"
 	"time"
 
 	"github.com/ydcloud-dy/leaf-api/internal/model/po"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ArticleRepo 文章仓储接口
@@ -19,14 +21,49 @@ type ArticleRepo interface {
 	FindByID(id uint) (*po.Article, error)
 	// FindByIDWithRelations 根据 ID 查询文章（包含关联数据）
 	FindByIDWithRelations(id uint) (*po.Article, error)
+	// FindBySlug 根据 slug 查询文章（包含关联数据）
+	FindBySlug(slug string) (*po.Article, error)
+	// SlugExists 检查 slug 是否已被其他文章使用（包含回收站中的文章）
+	SlugExists(slug string, excludeID uint) (bool, error)
+	// ListWithoutSlug 查询尚未生成 slug 的文章
+	ListWithoutSlug(limit int) ([]*po.Article, error)
+	// UpdateSlug 更新文章 slug
+	UpdateSlug(id uint, slug string) error
+	// ListWithoutReadingStats 查询 ID 大于 afterID 且尚未统计字数的文章
+	ListWithoutReadingStats(afterID uint, limit int) ([]*po.Article, error)
+	// UpdateReadingStats 更新文章字数和阅读时间
+	UpdateReadingStats(id uint, wordCount, readingTime int) error
+	// UpdateTimestamp 设置文章更新时间
+	UpdateTimestamp(id uint, updatedAt time.Time) error
 	// FindByIDs 根据多个 ID 查询文章
 	FindByIDs(ids []uint) ([]*po.Article, error)
-	// List 查询文章列表
-	List(page, limit int, categoryID, tagID, chapterID uint, status, keyword, sort string) ([]*po.Article, int64, error)
+	// List 查询文章列表，authorID 大于 0 时返回该用户为主作者或共同作者的文章
+	List(page, limit int, categoryID, tagID, chapterID, authorID uint, status, keyword, sort string) ([]*po.Article, int64, error)
+	// ListAfter 游标分页查询文章列表，按创建时间和 ID 倒序，cursor 为空时从第一条开始，不统计总数
+	// skipPinned 为 true 时不返回置顶文章，置顶文章通过 ListPinned 单独查询
+	ListAfter(limit int, categoryID, tagID, chapterID, authorID uint, status, keyword string, skipPinned bool, cursor *ArticleCursor) ([]*po.Article, error)
+	// ListPinned 查询符合过滤条件的全部置顶文章，按置顶排序和创建时间倒序
+	ListPinned(categoryID, tagID, chapterID, authorID uint, status string) ([]*po.Article, error)
+	// ListFollowed 分页查询 followerID 关注的用户作为主作者或共同作者的已发布文章，按发布时间倒序
+	ListFollowed(page, limit int, followerID uint) ([]*po.Article, int64, error)
+	// ArchiveCounts 按年月统计已发布文章数，按时间倒序
+	ArchiveCounts() ([]ArchiveCount, error)
+	// AuthorStats 统计用户为主作者或共同作者的已发布文章数、浏览量和获赞数
+	AuthorStats(userID uint) (*AuthorStats, error)
+	// ListArchive 查询已发布文章的归档信息（仅包含 ID、标题、slug 和创建时间）
+	ListArchive() ([]*po.Article, error)
+	// ListSitemap 查询已发布文章的站点地图信息（仅包含 ID、slug、分类和更新时间）
+	ListSitemap() ([]*po.Article, error)
+	// CountReferencing 统计正文或封面中包含 text 的文章数（不含回收站中的文章）
+	CountReferencing(text string) (int64, error)
 	// UpdateStatus 更新文章状态
 	UpdateStatus(id uint, status int) error
+	// UpdatePinned 设置文章置顶状态
+	UpdatePinned(id uint, isTop bool, pinnedSort int) error
 	// IncrementViewCount 增加浏览量
 	IncrementViewCount(id uint) error
+	// AddCounters 批量累加计数字段（view_count、like_count、favorite_count），结果不小于 0
+	AddCounters(id uint, deltas map[string]int64) error
 	// IncrementLikeCount 增加点赞数
 	IncrementLikeCount(id uint) error
 	// DecrementLikeCount 减少点赞数
@@ -49,13 +86,68 @@ type ArticleRepo interface {
 	BatchAssociateTags(articleIDs []uint, tagIDs []uint) error
 	// BatchDelete 批量删除
 	BatchDelete(articleIDs []uint) error
+	// BatchUpdateStatus 在同一事务中批量更新状态，返回存在且有权限操作的文章（状态为修改前的值）
+	BatchUpdateStatus(articleIDs []uint, status int) ([]*po.Article, error)
 	// GetAdjacentArticles 获取上一篇和下一篇文章（基于章节排序）
 	GetAdjacentArticles(id uint) (*po.Article, *po.Article, error)
+	// ListTrashed 查询回收站中的文章
+	ListTrashed(page, limit int, keyword string) ([]*po.Article, int64, error)
+	// Restore 从回收站恢复文章
+	Restore(articleIDs []uint) error
+	// Purge 彻底删除回收站中的文章及其关联数据
+	Purge(articleIDs []uint) error
+	// PurgeDeletedBefore 彻底删除指定时间之前移入回收站的文章，返回删除数量
+	PurgeDeletedBefore(before time.Time) (int64, error)
+	// WithOwner 返回限定作者的仓储，写操作只作用于该作者的文章
+	WithOwner(userID uint) ArticleRepo
+	// PublicOnly 返回排除私密文章的仓储，供博客前台查询使用
+	PublicOnly() ArticleRepo
+	// WithFields 返回列表查询只读取指定列、只预加载指定关联的仓储，用于减少大字段读取
+	WithFields(columns, preloads []string) ArticleRepo
+	// WithLanguage 返回按语言合并翻译组的仓储，列表中每个翻译组只出现一篇文章
+	WithLanguage(language string) ArticleRepo
+}
+
+// ErrArticleNotOwned 文章不存在或不属于当前作者
+var ErrArticleNotOwned = errors.New("article not found or not owned by current user")
+
+// ErrArticleStale 文章已被其他人修改，提交的修订号已过期
+var ErrArticleStale = errors.New("article revision is stale")
+
+// ArticleCursor 游标分页位置，指向上一页最后一篇文章
+type ArticleCursor struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
+// ArchiveCount 归档月份的文章数
+type ArchiveCount struct {
+	Year  int
+	Month int
+	Count int64
+}
+
+// AuthorStats 作者的文章统计
+type AuthorStats struct {
+	ArticleCount int64
+	ViewCount    int64
+	LikeCount    int64
 }
 
 // articleRepo 文章仓储实现
 type articleRepo struct {
 	db *gorm.DB
+	// scoped 为 true 时写操作仅作用于 ownerID 的文章
+	scoped  bool
+	ownerID uint
+	// public 为 true 时列表查询排除私密文章
+	public bool
+	// columns 不为空时列表查询只读取这些列，preloads 为需要预加载的关联
+	columns  []string
+	preloads []string
+	// translated 为 true 时列表查询按 language 合并翻译组
+	translated bool
+	language   string
 }
 
 // NewArticleRepo 创建文章仓储
@@ -63,31 +155,212 @@ func NewArticleRepo(db *gorm.DB) ArticleRepo {
 	return &articleRepo{db: db}
 }
 
+// WithOwner 返回限定作者的仓储
+func (r *articleRepo) WithOwner(userID uint) ArticleRepo {
+	return &articleRepo{db: r.db, scoped: true, ownerID: userID}
+}
+
+// PublicOnly 返回排除私密文章的仓储
+func (r *articleRepo) PublicOnly() ArticleRepo {
+	return &articleRepo{db: r.db, scoped: r.scoped, ownerID: r.ownerID, public: true, columns: r.columns, preloads: r.preloads, translated: r.translated, language: r.language}
+}
+
+// WithFields 返回只读取指定列的仓储，preloads 可选 Author、Category、Tags、Authors
+func (r *articleRepo) WithFields(columns, preloads []string) ArticleRepo {
+	return &articleRepo{db: r.db, scoped: r.scoped, ownerID: r.ownerID, public: r.public, columns: columns, preloads: preloads, translated: r.translated, language: r.language}
+}
+
+// WithLanguage 返回按语言合并翻译组的仓储
+// 翻译组中有该语言的已发布版本时返回该版本，否则返回源文章；language 为空时只返回源文章，未加入翻译组的文章始终返回
+func (r *articleRepo) WithLanguage(language string) ArticleRepo {
+	return &articleRepo{db: r.db, scoped: r.scoped, ownerID: r.ownerID, public: r.public, columns: r.columns, preloads: r.preloads, translated: true, language: language}
+}
+
+// preloaded 列表查询是否预加载指定关联，未指定字段时全部预加载
+func (r *articleRepo) preloaded(name string) bool {
+	if len(r.columns) == 0 {
+		return true
+	}
+	for _, preload := range r.preloads {
+		if preload == name {
+			return true
+		}
+	}
+	return false
+}
+
+// selectColumns 为列表查询追加列选择，统计总数之后再调用以免影响 COUNT
+func (r *articleRepo) selectColumns(db *gorm.DB) *gorm.DB {
+	if len(r.columns) == 0 {
+		return db
+	}
+	columns := make([]string, 0, len(r.columns))
+	for _, column := range r.columns {
+		columns = append(columns, "articles."+column)
+	}
+	return db.Select(columns)
+}
+
+// visible 为前台查询追加可见性条件
+func (r *articleRepo) visible(db *gorm.DB) *gorm.DB {
+	if r.public {
+		return db.Where("visibility <> ?", po.VisibilityPrivate)
+	}
+	return db
+}
+
+// translatedOnly 为列表查询追加翻译组合并条件
+func (r *articleRepo) translatedOnly(db *gorm.DB) *gorm.DB {
+	if !r.translated {
+		return db
+	}
+	if r.language == "" {
+		return db.Where("articles.id NOT IN (SELECT article_id FROM article_translations WHERE article_id <> group_id)")
+	}
+
+	// 该语言的版本需要已发布且在前台可见，才能替代源文章
+	available := r.db.Table("article_translations AS t").
+		Select("1").
+		Joins("JOIN articles AS v ON v.id = t.article_id").
+		Where("t.group_id = articles.id AND t.language = ?", r.language).
+		Where("v.status = 1 AND v.visibility <> ? AND v.deleted_at IS NULL", po.VisibilityPrivate)
+	return db.Where(
+		r.db.Where("articles.id NOT IN (SELECT article_id FROM article_translations)").
+			Or("articles.id IN (SELECT article_id FROM article_translations WHERE language = ?)", r.language).
+			Or("articles.id IN (SELECT group_id FROM article_translations) AND NOT EXISTS (?)", available),
+	)
+}
+
+// owned 为写操作追加作者条件
+func (r *articleRepo) owned(db *gorm.DB) *gorm.DB {
+	if r.scoped {
+		return db.Where("author_id = ?", r.ownerID)
+	}
+	return db
+}
+
+// checkOwned 校验批量操作的文章是否全部属于当前作者
+func (r *articleRepo) checkOwned(articleIDs []uint) error {
+	if !r.scoped {
+		return nil
+	}
+
+	unique := make(map[uint]struct{}, len(articleIDs))
+	for _, id := range articleIDs {
+		unique[id] = struct{}{}
+	}
+
+	var count int64
+	if err := r.db.Model(&po.Article{}).
+		Where("id IN ? AND author_id = ?", articleIDs, r.ownerID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(unique) {
+		return ErrArticleNotOwned
+	}
+	return nil
+}
+
 // Create 创建文章
 func (r *articleRepo) Create(article *po.Article) error {
 	return r.db.Create(article).Error
 }
 
 // Update 更新文章
+// 仅当数据库中的修订号与 article.Revision 一致时才会写入，成功后 article.Revision 加 1
 func (r *articleRepo) Update(article *po.Article) error {
-	// 使用 Updates 并设置 UpdatedAt，允许更新 CreatedAt
-	return r.db.Model(article).Updates(map[string]interface{}{
-		"title":            article.Title,
-		"content_markdown": article.ContentMarkdown,
-		"content_html":     article.ContentHTML,
-		"summary":          article.Summary,
-		"cover":            article.Cover,
-		"category_id":      article.CategoryID,
-		"chapter_id":       article.ChapterID,
-		"status":           article.Status,
-		"created_at":       article.CreatedAt, // 明确允许更新创建时间
-		"updated_at":       time.Now(),
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// 保存更新前的内容为历史版本
+		if err := snapshotArticle(tx, article); err != nil {
+			return err
+		}
+
+		// 使用 Updates 并设置 UpdatedAt，允许更新 CreatedAt
+		result := r.owned(tx.Model(article)).Where("revision = ?", article.Revision).Updates(map[string]interface{}{
+			"title":            article.Title,
+			"slug":             article.Slug,
+			"content_markdown": article.ContentMarkdown,
+			"content_html":     article.ContentHTML,
+			"summary":          article.Summary,
+			"cover":            article.Cover,
+			"category_id":      article.CategoryID,
+			"chapter_id":       article.ChapterID,
+			"status":           article.Status,
+			"visibility":       article.Visibility,
+			"access_password":  article.AccessPassword,
+			"word_count":       article.WordCount,
+			"reading_time":     article.ReadingTime,
+			"meta_description": article.MetaDescription,
+			"meta_keywords":    article.MetaKeywords,
+			"canonical_url":    article.CanonicalURL,
+			"no_index":         article.NoIndex,
+			"revision":         gorm.Expr("revision + 1"),
+			"created_at":       article.CreatedAt, // 明确允许更新创建时间
+			"updated_at":       time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// 区分无权限与修订号过期
+			var count int64
+			if err := r.owned(tx.Model(&po.Article{})).Where("id = ?", article.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return ErrArticleNotOwned
+			}
+			return ErrArticleStale
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	article.Revision++
+	return nil
+}
+
+// snapshotArticle 内容有变化时将数据库中的当前内容保存为新版本
+func snapshotArticle(tx *gorm.DB, article *po.Article) error {
+	var current po.Article
+	if err := tx.Select("id", "title", "content_markdown", "content_html", "summary").
+		First(&current, article.ID).Error; err != nil {
+		return err
+	}
+	if current.Title == article.Title &&
+		current.ContentMarkdown == article.ContentMarkdown &&
+		current.ContentHTML == article.ContentHTML &&
+		current.Summary == article.Summary {
+		return nil
+	}
+
+	var latest int
+	if err := tx.Model(&po.ArticleVersion{}).
+		Where("article_id = ?", article.ID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&po.ArticleVersion{
+		ArticleID:       current.ID,
+		Version:         latest + 1,
+		Title:           current.Title,
+		ContentMarkdown: current.ContentMarkdown,
+		ContentHTML:     current.ContentHTML,
+		Summary:         current.Summary,
 	}).Error
 }
 
 // Delete 删除文章
 func (r *articleRepo) Delete(id uint) error {
-	return r.db.Select("Tags").Delete(&po.Article{ID: id}).Error
+	if err := r.checkOwned([]uint{id}); err != nil {
+		return err
+	}
+	// 软删除，保留标签关联以便从回收站恢复
+	return r.db.Delete(&po.Article{ID: id}).Error
 }
 
 // FindByID 根据 ID 查询文章
@@ -103,17 +376,80 @@ func (r *articleRepo) FindByID(id uint) (*po.Article, error) {
 // FindByIDWithRelations 根据 ID 查询文章（包含关联数据）
 func (r *articleRepo) FindByIDWithRelations(id uint) (*po.Article, error) {
 	var article po.Article
-	err := r.db.Preload("Author").Preload("Category").Preload("Tags").First(&article, id).Error
+	err := preloadAuthors(r.db.Preload("Author").Preload("Category").Preload("Tags")).First(&article, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+// FindBySlug 根据 slug 查询文章（包含关联数据）
+func (r *articleRepo) FindBySlug(slug string) (*po.Article, error) {
+	var article po.Article
+	err := preloadAuthors(r.db.Preload("Author").Preload("Category").Preload("Tags")).Where("slug = ?", slug).First(&article).Error
 	if err != nil {
 		return nil, err
 	}
 	return &article, nil
 }
 
+// SlugExists 检查 slug 是否已被其他文章使用
+func (r *articleRepo) SlugExists(slug string, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Unscoped().Model(&po.Article{}).Where("slug = ?", slug)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListWithoutSlug 查询尚未生成 slug 的文章
+func (r *articleRepo) ListWithoutSlug(limit int) ([]*po.Article, error) {
+	var articles []*po.Article
+	err := r.db.Unscoped().Select("id", "title").
+		Where("slug IS NULL OR slug = ''").
+		Order("id ASC").
+		Limit(limit).
+		Find(&articles).Error
+	return articles, err
+}
+
+// UpdateSlug 更新文章 slug（不修改更新时间）
+func (r *articleRepo) UpdateSlug(id uint, slug string) error {
+	return r.db.Unscoped().Model(&po.Article{}).Where("id = ?", id).UpdateColumn("slug", slug).Error
+}
+
+// ListWithoutReadingStats 查询尚未统计字数的文章（按 ID 递增，用于分批补全）
+func (r *articleRepo) ListWithoutReadingStats(afterID uint, limit int) ([]*po.Article, error) {
+	var articles []*po.Article
+	err := r.db.Unscoped().Select("id", "content_markdown").
+		Where("id > ? AND word_count = 0 AND content_markdown <> ''", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&articles).Error
+	return articles, err
+}
+
+// UpdateReadingStats 更新文章字数和阅读时间（不修改更新时间）
+func (r *articleRepo) UpdateReadingStats(id uint, wordCount, readingTime int) error {
+	return r.db.Unscoped().Model(&po.Article{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"word_count":   wordCount,
+		"reading_time": readingTime,
+	}).Error
+}
+
+// UpdateTimestamp 设置文章更新时间（用于导入时保留原始时间）
+func (r *articleRepo) UpdateTimestamp(id uint, updatedAt time.Time) error {
+	return r.db.Unscoped().Model(&po.Article{}).Where("id = ?", id).UpdateColumn("updated_at", updatedAt).Error
+}
+
 // FindByIDs 根据多个 ID 查询文章
 func (r *articleRepo) FindByIDs(ids []uint) ([]*po.Article, error) {
 	var articles []*po.Article
-	err := r.db.Preload("Author").Preload("Category").Preload("Tags").
+	err := preloadAuthors(r.visible(r.db).Preload("Author").Preload("Category").Preload("Tags")).
 		Where("id IN ?", ids).
 		Order("created_at DESC").
 		Find(&articles).Error
@@ -124,12 +460,79 @@ func (r *articleRepo) FindByIDs(ids []uint) ([]*po.Article, error) {
 }
 
 // List 查询文章列表
-func (r *articleRepo) List(page, limit int, categoryID, tagID, chapterID uint, status, keyword, sort string) ([]*po.Article, int64, error) {
+func (r *articleRepo) List(page, limit int, categoryID, tagID, chapterID, authorID uint, status, keyword, sort string) ([]*po.Article, int64, error) {
 	var articles []*po.Article
 	var total int64
 
 	offset := (page - 1) * limit
-	query := r.db.Model(&po.Article{}).Preload("Author").Preload("Category").Preload("Tags")
+	query := r.listQuery(categoryID, tagID, chapterID, authorID, status, keyword)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 根据排序参数动态排序
+	orderBy := "created_at DESC" // 默认按创建时间降序
+	switch sort {
+	case "views":
+		orderBy = "view_count DESC"
+	case "likes":
+		orderBy = "like_count DESC"
+	case "latest", "date":
+		orderBy = "created_at DESC"
+	}
+
+	// 默认排序下置顶文章排在最前（搜索和按时间归档时不置顶）
+	if (sort == "" || sort == "latest") && keyword == "" {
+		orderBy = "is_top DESC, pinned_sort DESC, " + orderBy
+	}
+
+	if err := r.selectColumns(query).Offset(offset).Limit(limit).Order(orderBy).Find(&articles).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return articles, total, nil
+}
+
+// ListAfter 游标分页查询文章列表，避免大偏移量 OFFSET 扫描
+func (r *articleRepo) ListAfter(limit int, categoryID, tagID, chapterID, authorID uint, status, keyword string, skipPinned bool, cursor *ArticleCursor) ([]*po.Article, error) {
+	var articles []*po.Article
+
+	query := r.listQuery(categoryID, tagID, chapterID, authorID, status, keyword)
+	if skipPinned {
+		query = query.Where("articles.is_top = ?", false)
+	}
+	if cursor != nil {
+		query = query.Where("articles.created_at < ? OR (articles.created_at = ? AND articles.id < ?)",
+			cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	}
+
+	err := r.selectColumns(query).Order("articles.created_at DESC, articles.id DESC").Limit(limit).Find(&articles).Error
+	return articles, err
+}
+
+// ListPinned 查询置顶文章，置顶文章数量很少，不分页
+func (r *articleRepo) ListPinned(categoryID, tagID, chapterID, authorID uint, status string) ([]*po.Article, error) {
+	var articles []*po.Article
+
+	query := r.listQuery(categoryID, tagID, chapterID, authorID, status, "").Where("articles.is_top = ?", true)
+	err := r.selectColumns(query).
+		Order("articles.pinned_sort DESC, articles.created_at DESC, articles.id DESC").
+		Find(&articles).Error
+	return articles, err
+}
+
+// listQuery 构造文章列表的过滤条件（预加载作者、分类和标签）
+func (r *articleRepo) listQuery(categoryID, tagID, chapterID, authorID uint, status, keyword string) *gorm.DB {
+	query := r.translatedOnly(r.visible(r.db.Model(&po.Article{})))
+	for _, name := range []string{"Author", "Category", "Tags"} {
+		if r.preloaded(name) {
+			query = query.Preload(name)
+		}
+	}
+	if r.preloaded("Authors") {
+		query = preloadAuthors(query)
+	}
 
 	// 分类过滤
 	if categoryID > 0 {
@@ -147,6 +550,11 @@ func (r *articleRepo) List(page, limit int, categoryID, tagID, chapterID uint, s
 		query = query.Where("chapter_id = ?", chapterID)
 	}
 
+	// 作者过滤（主作者或共同作者）
+	if authorID > 0 {
+		query = authoredBy(query, authorID)
+	}
+
 	// 状态过滤
 	if status != "" {
 		query = query.Where("status = ?", status)
@@ -157,39 +565,95 @@ func (r *articleRepo) List(page, limit int, categoryID, tagID, chapterID uint, s
 		query = query.Where("title LIKE ? OR summary LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
 	}
 
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
+	return query
+}
 
-	// 根据排序参数动态排序
-	orderBy := "created_at DESC" // 默认按创建时间降序
-	switch sort {
-	case "views":
-		orderBy = "view_count DESC"
-	case "likes":
-		orderBy = "like_count DESC"
-	case "latest":
-		orderBy = "created_at DESC"
-	}
+// ArchiveCounts 按年月统计已发布文章数
+func (r *articleRepo) ArchiveCounts() ([]ArchiveCount, error) {
+	var counts []ArchiveCount
+	err := r.visible(r.db.Model(&po.Article{})).
+		Select("YEAR(created_at) AS year, MONTH(created_at) AS month, COUNT(*) AS count").
+		Where("status = ?", 1).
+		Group("year, month").
+		Order("year DESC, month DESC").
+		Scan(&counts).Error
+	return counts, err
+}
 
-	if err := query.Offset(offset).Limit(limit).Order(orderBy).Find(&articles).Error; err != nil {
-		return nil, 0, err
-	}
+// AuthorStats 统计用户为主作者或共同作者的已发布文章数、浏览量和获赞数
+func (r *articleRepo) AuthorStats(userID uint) (*AuthorStats, error) {
+	var stats AuthorStats
+	err := authoredBy(r.visible(r.db.Model(&po.Article{})), userID).
+		Select("COUNT(*) AS article_count, COALESCE(SUM(view_count), 0) AS view_count, COALESCE(SUM(like_count), 0) AS like_count").
+		Where("status = ?", 1).
+		Scan(&stats).Error
+	return &stats, err
+}
 
-	return articles, total, nil
+// ListArchive 查询已发布文章的归档信息，按创建时间倒序
+func (r *articleRepo) ListArchive() ([]*po.Article, error) {
+	var articles []*po.Article
+	err := r.visible(r.db).Select("id", "title", "slug", "created_at").
+		Where("status = ?", 1).
+		Order("created_at DESC").
+		Find(&articles).Error
+	return articles, err
+}
+
+// ListSitemap 查询已发布文章的站点地图信息，按更新时间倒序
+func (r *articleRepo) ListSitemap() ([]*po.Article, error) {
+	var articles []*po.Article
+	err := r.visible(r.db).Select("id", "slug", "category_id", "updated_at").
+		Where("status = ?", 1).
+		Order("updated_at DESC").
+		Find(&articles).Error
+	return articles, err
+}
+
+// CountReferencing 统计正文或封面中包含 text 的文章数
+func (r *articleRepo) CountReferencing(text string) (int64, error) {
+	var count int64
+	pattern := "%" + text + "%"
+	err := r.db.Model(&po.Article{}).
+		Where("content_markdown LIKE ? OR cover LIKE ?", pattern, pattern).
+		Count(&count).Error
+	return count, err
 }
 
 // UpdateStatus 更新文章状态
 func (r *articleRepo) UpdateStatus(id uint, status int) error {
+	if err := r.checkOwned([]uint{id}); err != nil {
+		return err
+	}
 	return r.db.Model(&po.Article{}).Where("id = ?", id).Update("status", status).Error
 }
 
+// UpdatePinned 设置文章置顶状态
+func (r *articleRepo) UpdatePinned(id uint, isTop bool, pinnedSort int) error {
+	if err := r.checkOwned([]uint{id}); err != nil {
+		return err
+	}
+	return r.db.Model(&po.Article{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"is_top":      isTop,
+		"pinned_sort": pinnedSort,
+	}).Error
+}
+
 // IncrementViewCount 增加浏览量
 func (r *articleRepo) IncrementViewCount(id uint) error {
 	return r.db.Model(&po.Article{}).Where("id = ?", id).
 		UpdateColumn("view_count", gorm.Expr("view_count + ?", 1)).Error
 }
 
+// AddCounters 批量累加计数字段，字段名由调用方保证为计数列
+func (r *articleRepo) AddCounters(id uint, deltas map[string]int64) error {
+	updates := make(map[string]interface{}, len(deltas))
+	for field, delta := range deltas {
+		updates[field] = gorm.Expr("GREATEST("+field+" + ?, 0)", delta)
+	}
+	return r.db.Model(&po.Article{}).Where("id = ?", id).UpdateColumns(updates).Error
+}
+
 // IncrementLikeCount 增加点赞数
 func (r *articleRepo) IncrementLikeCount(id uint) error {
 	return r.db.Model(&po.Article{}).Where("id = ?", id).
@@ -228,6 +692,10 @@ func (r *articleRepo) DecrementCommentCount(id uint) error {
 
 // AssociateTags 关联标签
 func (r *articleRepo) AssociateTags(articleID uint, tagIDs []uint) error {
+	if err := r.checkOwned([]uint{articleID}); err != nil {
+		return err
+	}
+
 	var article po.Article
 	if err := r.db.First(&article, articleID).Error; err != nil {
 		return err
@@ -243,6 +711,9 @@ func (r *articleRepo) AssociateTags(articleID uint, tagIDs []uint) error {
 
 // BatchUpdateCover 批量更新封面
 func (r *articleRepo) BatchUpdateCover(articleIDs []uint, cover string) error {
+	if err := r.checkOwned(articleIDs); err != nil {
+		return err
+	}
 	return r.db.Model(&po.Article{}).
 		Where("id IN ?", articleIDs).
 		Update("cover", cover).Error
@@ -250,6 +721,9 @@ func (r *articleRepo) BatchUpdateCover(articleIDs []uint, cover string) error {
 
 // BatchUpdateFields 批量更新字段
 func (r *articleRepo) BatchUpdateFields(articleIDs []uint, updates map[string]interface{}) error {
+	if err := r.checkOwned(articleIDs); err != nil {
+		return err
+	}
 	return r.db.Model(&po.Article{}).
 		Where("id IN ?", articleIDs).
 		Updates(updates).Error
@@ -257,6 +731,10 @@ func (r *articleRepo) BatchUpdateFields(articleIDs []uint, updates map[string]in
 
 // BatchAssociateTags 批量关联标签
 func (r *articleRepo) BatchAssociateTags(articleIDs []uint, tagIDs []uint) error {
+	if err := r.checkOwned(articleIDs); err != nil {
+		return err
+	}
+
 	var tags []po.Tag
 	if err := r.db.Find(&tags, tagIDs).Error; err != nil {
 		return err
@@ -276,7 +754,37 @@ func (r *articleRepo) BatchAssociateTags(articleIDs []uint, tagIDs []uint) error
 
 // BatchDelete 批量删除
 func (r *articleRepo) BatchDelete(articleIDs []uint) error {
-	return r.db.Select("Tags").Delete(&po.Article{}, articleIDs).Error
+	if err := r.checkOwned(articleIDs); err != nil {
+		return err
+	}
+	return r.db.Delete(&po.Article{}, articleIDs).Error
+}
+
+// BatchUpdateStatus 批量更新状态，不存在或不属于当前作者的文章跳过，只更新状态有变化的文章
+func (r *articleRepo) BatchUpdateStatus(articleIDs []uint, status int) ([]*po.Article, error) {
+	var articles []*po.Article
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := r.owned(tx.Clauses(clause.Locking{Strength: "UPDATE"})).
+			Where("id IN ?", articleIDs).
+			Find(&articles).Error; err != nil {
+			return err
+		}
+
+		changed := make([]uint, 0, len(articles))
+		for _, article := range articles {
+			if article.Status != status {
+				changed = append(changed, article.ID)
+			}
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+		return tx.Model(&po.Article{}).Where("id IN ?", changed).Update("status", status).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return articles, nil
 }
 
 // GetAdjacentArticles 获取上一篇和下一篇文章（基于章节排序）
@@ -306,14 +814,14 @@ func (r *articleRepo) GetAdjacentArticles(id uint) (*po.Article, *po.Article, er
 		return nil, nil, err
 	}
 
-	// 获取所有章节下的文章（只获取已发布的）
+	// 获取所有章节下的文章（只获取已发布且非私密的）
 	chapterIDs := make([]uint, 0, len(allChapters))
 	for _, chapter := range allChapters {
 		chapterIDs = append(chapterIDs, chapter.ID)
 	}
 
 	var allArticles []po.Article
-	if err := r.db.Where("chapter_id IN ? AND status = ?", chapterIDs, 1).
+	if err := r.db.Where("chapter_id IN ? AND status = ? AND visibility <> ?", chapterIDs, 1, po.VisibilityPrivate).
 		Preload("Author").Preload("Category").Preload("Tags").Preload("Chapter").
 		Find(&allArticles).Error; err != nil {
 		return nil, nil, err
@@ -355,7 +863,7 @@ func (r *articleRepo) getAdjacentArticlesByID(id uint) (*po.Article, *po.Article
 	var prevArticle, nextArticle po.Article
 
 	// 获取上一篇（ID小于当前文章ID，按ID降序，取第一条）
-	err := r.db.Where("id < ? AND status = ?", id, 1).
+	err := r.db.Where("id < ? AND status = ? AND visibility <> ?", id, 1, po.VisibilityPrivate).
 		Order("id DESC").
 		Limit(1).
 		Preload("Author").Preload("Category").Preload("Tags").Preload("Chapter").
@@ -366,7 +874,7 @@ func (r *articleRepo) getAdjacentArticlesByID(id uint) (*po.Article, *po.Article
 	}
 
 	// 获取下一篇（ID大于当前文章ID，按ID升序，取第一条）
-	err = r.db.Where("id > ? AND status = ?", id, 1).
+	err = r.db.Where("id > ? AND status = ? AND visibility <> ?", id, 1, po.VisibilityPrivate).
 		Order("id ASC").
 		Limit(1).
 		Preload("Author").Preload("Category").Preload("Tags").Preload("Chapter").
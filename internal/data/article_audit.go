@@ -0,0 +1,61 @@
+package data
+
+import (
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// ArticleAuditRepo 文章审计日志仓储接口
+type ArticleAuditRepo interface {
+	// Create 记录审计日志
+	Create(logs ...*po.ArticleAuditLog) error
+	// List 查询审计日志，articleID、operatorID 为 0 或 action 为空时不过滤
+	List(page, limit int, articleID, operatorID uint, action string) ([]*po.ArticleAuditLog, int64, error)
+}
+
+// articleAuditRepo 文章审计日志仓储实现
+type articleAuditRepo struct {
+	db *gorm.DB
+}
+
+// NewArticleAuditRepo 创建文章审计日志仓储
+func NewArticleAuditRepo(db *gorm.DB) ArticleAuditRepo {
+	return &articleAuditRepo{db: db}
+}
+
+// Create 记录审计日志
+func (r *articleAuditRepo) Create(logs ...*po.ArticleAuditLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	return r.db.Create(logs).Error
+}
+
+// List 按时间倒序查询审计日志
+func (r *articleAuditRepo) List(page, limit int, articleID, operatorID uint, action string) ([]*po.ArticleAuditLog, int64, error) {
+	var logs []*po.ArticleAuditLog
+	var total int64
+
+	query := r.db.Model(&po.ArticleAuditLog{})
+	if articleID > 0 {
+		query = query.Where("article_id = ?", articleID)
+	}
+	if operatorID > 0 {
+		query = query.Where("operator_id = ?", operatorID)
+	}
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Preload("Operator").
+		Order("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&logs).Error
+	return logs, total, err
+}
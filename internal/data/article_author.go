@@ -0,0 +1,99 @@
+package data
+
+import (
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// ArticleAuthorRepo 文章共同作者仓储接口
+type ArticleAuthorRepo interface {
+	// ListByArticle 按署名顺序查询文章的共同作者（包含用户信息）
+	ListByArticle(articleID uint) ([]*po.ArticleAuthor, error)
+	// Set 按给定顺序替换文章的共同作者
+	Set(articleID uint, authors []po.ArticleAuthor) error
+	// Add 添加共同作者，已存在时更新角色
+	Add(author *po.ArticleAuthor) error
+	// Remove 移除共同作者
+	Remove(articleID, userID uint) error
+}
+
+// articleAuthorRepo 文章共同作者仓储实现
+type articleAuthorRepo struct {
+	db *gorm.DB
+}
+
+// NewArticleAuthorRepo 创建文章共同作者仓储
+func NewArticleAuthorRepo(db *gorm.DB) ArticleAuthorRepo {
+	return &articleAuthorRepo{db: db}
+}
+
+// preloadAuthors 预加载文章的共同作者及其用户信息，按署名顺序排列
+func preloadAuthors(db *gorm.DB) *gorm.DB {
+	return db.Preload("Authors", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("sort ASC, id ASC")
+	}).Preload("Authors.User")
+}
+
+// authoredBy 过滤主作者或共同作者为指定用户的文章
+func authoredBy(db *gorm.DB, userID uint) *gorm.DB {
+	return db.Where("articles.author_id = ? OR articles.id IN (SELECT article_id FROM article_authors WHERE user_id = ?)", userID, userID)
+}
+
+// ListByArticle 按署名顺序查询文章的共同作者
+func (r *articleAuthorRepo) ListByArticle(articleID uint) ([]*po.ArticleAuthor, error) {
+	var authors []*po.ArticleAuthor
+	err := r.db.Preload("User").
+		Where("article_id = ?", articleID).
+		Order("sort ASC, id ASC").
+		Find(&authors).Error
+	return authors, err
+}
+
+// Set 按给定顺序替换文章的共同作者
+func (r *articleAuthorRepo) Set(articleID uint, authors []po.ArticleAuthor) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("article_id = ?", articleID).Delete(&po.ArticleAuthor{}).Error; err != nil {
+			return err
+		}
+		if len(authors) == 0 {
+			return nil
+		}
+
+		items := make([]po.ArticleAuthor, 0, len(authors))
+		for i, author := range authors {
+			items = append(items, po.ArticleAuthor{
+				ArticleID: articleID,
+				UserID:    author.UserID,
+				Role:      author.Role,
+				Sort:      i,
+			})
+		}
+		return tx.Create(&items).Error
+	})
+}
+
+// Add 添加共同作者，已存在时只更新角色，新作者排在最后
+func (r *articleAuthorRepo) Add(author *po.ArticleAuthor) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing po.ArticleAuthor
+		err := tx.Where("article_id = ? AND user_id = ?", author.ArticleID, author.UserID).First(&existing).Error
+		if err == nil {
+			return tx.Model(&existing).Update("role", author.Role).Error
+		}
+		if err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&po.ArticleAuthor{}).Where("article_id = ?", author.ArticleID).Count(&count).Error; err != nil {
+			return err
+		}
+		author.Sort = int(count)
+		return tx.Create(author).Error
+	})
+}
+
+// Remove 移除共同作者
+func (r *articleAuthorRepo) Remove(articleID, userID uint) error {
+	return r.db.Where("article_id = ? AND user_id = ?", articleID, userID).Delete(&po.ArticleAuthor{}).Error
+}
@@ -0,0 +1,60 @@
+package data
+
+import (
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ArticleDraftRepo 文章自动保存草稿仓储接口
+type ArticleDraftRepo interface {
+	// Save 保存草稿（存在则覆盖）
+	Save(draft *po.ArticleDraft) error
+	// Find 查询用户在某篇文章上的草稿
+	Find(articleID, userID uint) (*po.ArticleDraft, error)
+	// Delete 删除用户在某篇文章上的草稿
+	Delete(articleID, userID uint) error
+	// DeleteByArticle 删除文章的全部草稿
+	DeleteByArticle(articleID uint) error
+}
+
+// articleDraftRepo 文章自动保存草稿仓储实现
+type articleDraftRepo struct {
+	db *gorm.DB
+}
+
+// NewArticleDraftRepo 创建文章自动保存草稿仓储
+func NewArticleDraftRepo(db *gorm.DB) ArticleDraftRepo {
+	return &articleDraftRepo{db: db}
+}
+
+// Save 保存草稿，按 (article_id, user_id) 覆盖写入
+func (r *articleDraftRepo) Save(draft *po.ArticleDraft) error {
+	draft.UpdatedAt = time.Now()
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "article_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "summary", "content_markdown", "updated_at"}),
+	}).Create(draft).Error
+}
+
+// Find 查询草稿
+func (r *articleDraftRepo) Find(articleID, userID uint) (*po.ArticleDraft, error) {
+	var draft po.ArticleDraft
+	err := r.db.Where("article_id = ? AND user_id = ?", articleID, userID).First(&draft).Error
+	if err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+// Delete 删除草稿
+func (r *articleDraftRepo) Delete(articleID, userID uint) error {
+	return r.db.Where("article_id = ? AND user_id = ?", articleID, userID).Delete(&po.ArticleDraft{}).Error
+}
+
+// DeleteByArticle 删除文章的全部草稿
+func (r *articleDraftRepo) DeleteByArticle(articleID uint) error {
+	return r.db.Where("article_id = ?", articleID).Delete(&po.ArticleDraft{}).Error
+}
@@ -0,0 +1,127 @@
+package data
+
+import (
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// ArticleTranslationRepo 文章翻译关联仓储接口
+type ArticleTranslationRepo interface {
+	// FindByArticle 查询文章所属的翻译组记录
+	FindByArticle(articleID uint) (*po.ArticleTranslation, error)
+	// FindByArticles 批量查询文章的翻译组记录，未加入翻译组的文章不返回
+	FindByArticles(articleIDs []uint) ([]*po.ArticleTranslation, error)
+	// ListByGroup 查询翻译组内的全部语言版本（包含文章基础信息），源文章在前
+	ListByGroup(groupID uint) ([]*po.ArticleTranslation, error)
+	// Save 在同一事务中保存翻译记录，文章已有记录时更新翻译组和语言
+	Save(translations ...*po.ArticleTranslation) error
+	// Remove 将文章移出翻译组，源文章移出时解散整个翻译组，组内只剩源文章时一并删除
+	Remove(articleID uint) error
+}
+
+// articleTranslationRepo 文章翻译关联仓储实现
+type articleTranslationRepo struct {
+	db *gorm.DB
+}
+
+// NewArticleTranslationRepo 创建文章翻译关联仓储
+func NewArticleTranslationRepo(db *gorm.DB) ArticleTranslationRepo {
+	return &articleTranslationRepo{db: db}
+}
+
+// FindByArticle 查询文章所属的翻译组记录
+func (r *articleTranslationRepo) FindByArticle(articleID uint) (*po.ArticleTranslation, error) {
+	var translation po.ArticleTranslation
+	if err := r.db.Where("article_id = ?", articleID).First(&translation).Error; err != nil {
+		return nil, err
+	}
+	return &translation, nil
+}
+
+// FindByArticles 批量查询文章的翻译组记录
+func (r *articleTranslationRepo) FindByArticles(articleIDs []uint) ([]*po.ArticleTranslation, error) {
+	var translations []*po.ArticleTranslation
+	if len(articleIDs) == 0 {
+		return translations, nil
+	}
+	err := r.db.Where("article_id IN ?", articleIDs).Find(&translations).Error
+	return translations, err
+}
+
+// ListByGroup 查询翻译组内的全部语言版本，源文章在前，其余按语言排序
+func (r *articleTranslationRepo) ListByGroup(groupID uint) ([]*po.ArticleTranslation, error) {
+	var translations []*po.ArticleTranslation
+	err := r.db.Preload("Article", func(tx *gorm.DB) *gorm.DB {
+		return tx.Select("id", "title", "slug", "status", "visibility")
+	}).
+		Where("group_id = ?", groupID).
+		Order("article_id = group_id DESC, language ASC").
+		Find(&translations).Error
+	return translations, err
+}
+
+// Save 在同一事务中保存翻译记录，文章已有记录时更新翻译组和语言
+// 先删除这些文章的原有记录再重新写入（保留创建时间），组内成员互换语言时不会在中途违反 (group_id, language) 唯一索引
+func (r *articleTranslationRepo) Save(translations ...*po.ArticleTranslation) error {
+	if len(translations) == 0 {
+		return nil
+	}
+	articleIDs := make([]uint, 0, len(translations))
+	for _, translation := range translations {
+		articleIDs = append(articleIDs, translation.ArticleID)
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing []*po.ArticleTranslation
+		if err := tx.Where("article_id IN ?", articleIDs).Find(&existing).Error; err != nil {
+			return err
+		}
+		createdAt := make(map[uint]time.Time, len(existing))
+		for _, translation := range existing {
+			createdAt[translation.ArticleID] = translation.CreatedAt
+		}
+
+		if err := tx.Where("article_id IN ?", articleIDs).Delete(&po.ArticleTranslation{}).Error; err != nil {
+			return err
+		}
+		for _, translation := range translations {
+			translation.ID = 0
+			if t, ok := createdAt[translation.ArticleID]; ok {
+				translation.CreatedAt = t
+			}
+		}
+		return tx.Create(&translations).Error
+	})
+}
+
+// Remove 将文章移出翻译组
+func (r *articleTranslationRepo) Remove(articleID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var translation po.ArticleTranslation
+		if err := tx.Where("article_id = ?", articleID).First(&translation).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil
+			}
+			return err
+		}
+
+		// 源文章移出时解散整个翻译组
+		if translation.GroupID == articleID {
+			return tx.Where("group_id = ?", articleID).Delete(&po.ArticleTranslation{}).Error
+		}
+
+		if err := tx.Delete(&translation).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&po.ArticleTranslation{}).Where("group_id = ?", translation.GroupID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count <= 1 {
+			return tx.Where("group_id = ?", translation.GroupID).Delete(&po.ArticleTranslation{}).Error
+		}
+		return nil
+	})
+}
@@ -0,0 +1,148 @@
+package data
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// ErrArticleNotInTrash 文章不在回收站中
+var ErrArticleNotInTrash = errors.New("article not found in trash")
+
+// purgeBatchSize 自动清理时每批彻底删除的文章数
+const purgeBatchSize = 100
+
+// trashed 回收站查询（包含已软删除的记录）
+func (r *articleRepo) trashed() *gorm.DB {
+	return r.owned(r.db.Unscoped().Model(&po.Article{}).Where("deleted_at IS NOT NULL"))
+}
+
+// checkTrashed 校验文章是否全部在回收站中（且属于当前作者）
+func (r *articleRepo) checkTrashed(tx *gorm.DB, articleIDs []uint) error {
+	unique := make(map[uint]struct{}, len(articleIDs))
+	for _, id := range articleIDs {
+		unique[id] = struct{}{}
+	}
+
+	var count int64
+	query := r.owned(tx.Unscoped().Model(&po.Article{}).Where("deleted_at IS NOT NULL"))
+	if err := query.Where("id IN ?", articleIDs).Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(unique) {
+		if r.scoped {
+			return ErrArticleNotOwned
+		}
+		return ErrArticleNotInTrash
+	}
+	return nil
+}
+
+// ListTrashed 查询回收站中的文章
+func (r *articleRepo) ListTrashed(page, limit int, keyword string) ([]*po.Article, int64, error) {
+	var articles []*po.Article
+	var total int64
+
+	offset := (page - 1) * limit
+	query := r.trashed().Preload("Author").Preload("Category").Preload("Tags")
+
+	// 关键词搜索
+	if keyword != "" {
+		query = query.Where("title LIKE ? OR summary LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Offset(offset).Limit(limit).Order("deleted_at DESC").Find(&articles).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return articles, total, nil
+}
+
+// Restore 从回收站恢复文章
+func (r *articleRepo) Restore(articleIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := r.checkTrashed(tx, articleIDs); err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&po.Article{}).
+			Where("id IN ?", articleIDs).
+			Update("deleted_at", nil).Error
+	})
+}
+
+// Purge 彻底删除回收站中的文章及其关联数据
+func (r *articleRepo) Purge(articleIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := r.checkTrashed(tx, articleIDs); err != nil {
+			return err
+		}
+		return purgeArticles(tx, articleIDs)
+	})
+}
+
+// PurgeDeletedBefore 彻底删除指定时间之前移入回收站的文章
+func (r *articleRepo) PurgeDeletedBefore(before time.Time) (int64, error) {
+	var purged int64
+	for {
+		var ids []uint
+		err := r.db.Unscoped().Model(&po.Article{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Limit(purgeBatchSize).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return purged, err
+		}
+		if len(ids) == 0 {
+			return purged, nil
+		}
+
+		if err := r.db.Transaction(func(tx *gorm.DB) error {
+			return purgeArticles(tx, ids)
+		}); err != nil {
+			return purged, err
+		}
+		purged += int64(len(ids))
+	}
+}
+
+// purgeArticles 物理删除文章及其标签关联、系列关联、翻译关联、历史版本、草稿、评论、点赞、收藏、表情回应和浏览记录
+func purgeArticles(tx *gorm.DB, articleIDs []uint) error {
+	// 评论外键为 ON DELETE SET NULL，需先删除评论，避免文章评论变成留言板消息
+	commentIDs := tx.Unscoped().Model(&po.Comment{}).Select("id").Where("article_id IN ?", articleIDs)
+	if err := tx.Where("comment_id IN (?)", commentIDs).Delete(&po.CommentLike{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("target_type = ? AND target_id IN (?)", po.ReactionTargetComment, commentIDs).
+		Delete(&po.Reaction{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("target_type = ? AND target_id IN ?", po.ReactionTargetArticle, articleIDs).
+		Delete(&po.Reaction{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("article_id IN ?", articleIDs).Delete(&po.Comment{}).Error; err != nil {
+		return err
+	}
+
+	// 源文章被删除时解散整个翻译组，其余译文恢复为独立文章
+	if err := tx.Where("group_id IN ?", articleIDs).Delete(&po.ArticleTranslation{}).Error; err != nil {
+		return err
+	}
+	for _, model := range []interface{}{&po.Like{}, &po.Favorite{}, &po.View{}, &po.ArticleVersion{}, &po.ArticleDraft{}, &po.SeriesArticle{}, &po.ArticleAuthor{}, &po.ReadingProgress{}, &po.ArticleTranslation{}} {
+		if err := tx.Where("article_id IN ?", articleIDs).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Exec("DELETE FROM article_tags WHERE article_id IN ?", articleIDs).Error; err != nil {
+		return err
+	}
+
+	return tx.Unscoped().Delete(&po.Article{}, articleIDs).Error
+}
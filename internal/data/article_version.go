@@ -0,0 +1,54 @@
+package data
+
+import (
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// ArticleVersionRepo 文章历史版本仓储接口
+type ArticleVersionRepo interface {
+	// ListByArticle 查询文章的历史版本（不含正文，按版本号倒序）
+	ListByArticle(articleID uint) ([]*po.ArticleVersion, error)
+	// FindByID 根据 ID 查询版本
+	FindByID(id uint) (*po.ArticleVersion, error)
+	// DeleteByArticle 删除文章的全部历史版本
+	DeleteByArticle(articleID uint) error
+}
+
+// articleVersionRepo 文章历史版本仓储实现
+type articleVersionRepo struct {
+	db *gorm.DB
+}
+
+// NewArticleVersionRepo 创建文章历史版本仓储
+func NewArticleVersionRepo(db *gorm.DB) ArticleVersionRepo {
+	return &articleVersionRepo{db: db}
+}
+
+// ListByArticle 查询文章的历史版本
+func (r *articleVersionRepo) ListByArticle(articleID uint) ([]*po.ArticleVersion, error) {
+	var versions []*po.ArticleVersion
+	err := r.db.Select("id", "article_id", "version", "title", "summary", "created_at").
+		Where("article_id = ?", articleID).
+		Order("version DESC").
+		Find(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// FindByID 根据 ID 查询版本
+func (r *articleVersionRepo) FindByID(id uint) (*po.ArticleVersion, error) {
+	var version po.ArticleVersion
+	err := r.db.First(&version, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// DeleteByArticle 删除文章的全部历史版本
+func (r *articleVersionRepo) DeleteByArticle(articleID uint) error {
+	return r.db.Where("article_id = ?", articleID).Delete(&po.ArticleVersion{}).Error
+}
@@ -0,0 +1,98 @@
+package data
+
+import (
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// AttachmentRepo 附件仓储接口
+type AttachmentRepo interface {
+	// Create 创建附件
+	Create(attachment *po.Attachment) error
+	// FindByID 根据 ID 查询附件
+	FindByID(id uint) (*po.Attachment, error)
+	// List 查询附件列表，articleID 不为 0 时只返回该文章的附件，unattached 为 true 时只返回未关联文章的附件
+	List(page, limit int, keyword string, articleID uint, unattached bool) ([]*po.Attachment, int64, error)
+	// ListByArticle 查询文章的全部附件
+	ListByArticle(articleID uint) ([]*po.Attachment, error)
+	// SetArticle 修改附件关联的文章，articleID 为 nil 时取消关联
+	SetArticle(id uint, articleID *uint) error
+	// IncrDownloads 下载次数加一
+	IncrDownloads(id uint) error
+	// Delete 删除附件
+	Delete(id uint) error
+}
+
+// attachmentRepo 附件仓储实现
+type attachmentRepo struct {
+	db *gorm.DB
+}
+
+// NewAttachmentRepo 创建附件仓储
+func NewAttachmentRepo(db *gorm.DB) AttachmentRepo {
+	return &attachmentRepo{db: db}
+}
+
+// Create 创建附件
+func (r *attachmentRepo) Create(attachment *po.Attachment) error {
+	return r.db.Create(attachment).Error
+}
+
+// FindByID 根据 ID 查询附件
+func (r *attachmentRepo) FindByID(id uint) (*po.Attachment, error) {
+	var attachment po.Attachment
+	if err := r.db.First(&attachment, id).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// List 查询附件列表
+func (r *attachmentRepo) List(page, limit int, keyword string, articleID uint, unattached bool) ([]*po.Attachment, int64, error) {
+	var list []*po.Attachment
+	var total int64
+
+	query := r.db.Model(&po.Attachment{})
+	if keyword != "" {
+		query = query.Where("name LIKE ?", "%"+keyword+"%")
+	}
+	if articleID > 0 {
+		query = query.Where("article_id = ?", articleID)
+	} else if unattached {
+		query = query.Where("article_id IS NULL")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Offset(offset).Limit(limit).Order("id DESC").Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return list, total, nil
+}
+
+// ListByArticle 查询文章的全部附件，按上传顺序排列
+func (r *attachmentRepo) ListByArticle(articleID uint) ([]*po.Attachment, error) {
+	var list []*po.Attachment
+	err := r.db.Where("article_id = ?", articleID).Order("id ASC").Find(&list).Error
+	return list, err
+}
+
+// SetArticle 修改附件关联的文章
+func (r *attachmentRepo) SetArticle(id uint, articleID *uint) error {
+	return r.db.Model(&po.Attachment{}).Where("id = ?", id).Update("article_id", articleID).Error
+}
+
+// IncrDownloads 下载次数加一
+func (r *attachmentRepo) IncrDownloads(id uint) error {
+	return r.db.Model(&po.Attachment{}).Where("id = ?", id).
+		UpdateColumn("download_count", gorm.Expr("download_count + ?", 1)).Error
+}
+
+// Delete 删除附件
+func (r *attachmentRepo) Delete(id uint) error {
+	return r.db.Delete(&po.Attachment{}, id).Error
+}
@@ -0,0 +1,104 @@
+package data
+
+import (
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// BannerRepo 首页横幅仓储接口
+type BannerRepo interface {
+	// Create 创建横幅
+	Create(banner *po.Banner) error
+	// Update 更新横幅
+	Update(banner *po.Banner) error
+	// Delete 删除横幅
+	Delete(id uint) error
+	// FindByID 根据 ID 查询横幅
+	FindByID(id uint) (*po.Banner, error)
+	// List 查询横幅列表，status 为空时查询全部，否则按 now 时刻的展示状态过滤
+	List(page, limit int, status, keyword string, now time.Time) ([]*po.Banner, int64, error)
+	// ListActive 查询 now 时刻正在展示的横幅，按排序值排列
+	ListActive(now time.Time) ([]*po.Banner, error)
+}
+
+// bannerRepo 首页横幅仓储实现
+type bannerRepo struct {
+	db *gorm.DB
+}
+
+// NewBannerRepo 创建首页横幅仓储
+func NewBannerRepo(db *gorm.DB) BannerRepo {
+	return &bannerRepo{db: db}
+}
+
+// Create 创建横幅
+func (r *bannerRepo) Create(banner *po.Banner) error {
+	return r.db.Create(banner).Error
+}
+
+// Update 更新横幅
+func (r *bannerRepo) Update(banner *po.Banner) error {
+	return r.db.Save(banner).Error
+}
+
+// Delete 删除横幅
+func (r *bannerRepo) Delete(id uint) error {
+	return r.db.Delete(&po.Banner{}, id).Error
+}
+
+// FindByID 根据 ID 查询横幅
+func (r *bannerRepo) FindByID(id uint) (*po.Banner, error) {
+	var banner po.Banner
+	if err := r.db.First(&banner, id).Error; err != nil {
+		return nil, err
+	}
+	return &banner, nil
+}
+
+// List 查询横幅列表
+func (r *bannerRepo) List(page, limit int, status, keyword string, now time.Time) ([]*po.Banner, int64, error) {
+	var banners []*po.Banner
+	var total int64
+
+	query := r.db.Model(&po.Banner{})
+	switch status {
+	case po.BannerStatusActive:
+		query = activeBanners(query, now)
+	case po.BannerStatusScheduled:
+		query = query.Where("enabled = ? AND start_at > ?", true, now)
+	case po.BannerStatusExpired:
+		query = query.Where("enabled = ? AND end_at <= ?", true, now)
+	case po.BannerStatusDisabled:
+		query = query.Where("enabled = ?", false)
+	}
+	if keyword != "" {
+		query = query.Where("title LIKE ? OR subtitle LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("sort ASC, id DESC").Offset(offset).Limit(limit).Find(&banners).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return banners, total, nil
+}
+
+// ListActive 查询正在展示的横幅
+func (r *bannerRepo) ListActive(now time.Time) ([]*po.Banner, error) {
+	var banners []*po.Banner
+	err := activeBanners(r.db, now).Order("sort ASC, id DESC").Find(&banners).Error
+	return banners, err
+}
+
+// activeBanners 正在展示的条件：已启用且 now 处于展示时间段内
+func activeBanners(query *gorm.DB, now time.Time) *gorm.DB {
+	return query.Where("enabled = ?", true).
+		Where("start_at IS NULL OR start_at <= ?", now).
+		Where("end_at IS NULL OR end_at > ?", now)
+}
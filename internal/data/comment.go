@@ -1,10 +1,16 @@
 package data
 
 import (
+	"time"
+
 	"github.com/ydcloud-dy/leaf-api/internal/model/po"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// commentExportBatchSize 导出评论时每批查询的数量
+const commentExportBatchSize = 500
+
 // CommentRepo 评论仓储接口
 type CommentRepo interface {
 	// Create 创建评论
@@ -17,12 +23,36 @@ type CommentRepo interface {
 	FindByID(id uint) (*po.Comment, error)
 	// List 查询评论列表
 	List(page, limit int, articleID uint, status string) ([]*po.Comment, int64, error)
+	// ListGuestbook 查询留言板消息列表（含回复），status 为空时查询全部状态
+	ListGuestbook(page, limit int, status, keyword string) ([]*po.Comment, int64, error)
 	// UpdateStatus 更新评论状态
 	UpdateStatus(id uint, status int) error
 	// CountByArticle 统计文章评论数
 	CountByArticle(articleID uint) (int64, error)
 	// CountByUser 统计用户评论数
 	CountByUser(userID uint) (int64, error)
+	// ListTopLevel 分页查询审核通过的顶级评论，articleID 为 0 时查询留言板
+	ListTopLevel(articleID uint, page, limit int) ([]*po.Comment, int64, error)
+	// ListReplies 分页查询顶级评论下审核通过的回复，按时间正序
+	ListReplies(rootID uint, page, limit int) ([]*po.Comment, int64, error)
+	// ListLatestByArticle 查询文章最新的审核通过评论（含回复），按时间倒序
+	ListLatestByArticle(articleID uint, limit int) ([]*po.Comment, error)
+	// AddReplyCount 累加顶级评论的回复数，结果不小于 0
+	AddReplyCount(rootID uint, delta int) error
+	// DeleteReplies 删除顶级评论下的全部回复，返回删除的审核通过回复数
+	DeleteReplies(rootID uint) (int64, error)
+	// BackfillRoots 为历史回复补全 root_id 并重新统计回复数，返回补全的回复数
+	BackfillRoots() (int64, error)
+	// CountApprovedByUser 统计用户审核通过的评论数
+	CountApprovedByUser(userID uint) (int64, error)
+	// BatchUpdateStatus 在同一事务中批量更新状态，返回存在的评论（状态为修改前的值）
+	BatchUpdateStatus(ids []uint, status int) ([]*po.Comment, error)
+	// Edit 更新评论内容并记录编辑前的内容，comment 为编辑前的评论
+	Edit(comment *po.Comment, content, contentHTML string, editorID uint) error
+	// ListEdits 查询评论的编辑记录，按时间倒序
+	ListEdits(commentID uint) ([]*po.CommentEdit, error)
+	// ExportBatches 按 ID 顺序分批查询评论（带用户和文章），userID 为 0 时不按用户过滤，每批调用一次 fn
+	ExportBatches(articleID, userID uint, status string, fn func([]*po.Comment) error) error
 }
 
 // commentRepo 评论仓储实现
@@ -89,6 +119,31 @@ func (r *commentRepo) List(page, limit int, articleID uint, status string) ([]*p
 	return comments, total, nil
 }
 
+// ListGuestbook 查询留言板消息列表
+func (r *commentRepo) ListGuestbook(page, limit int, status, keyword string) ([]*po.Comment, int64, error) {
+	var comments []*po.Comment
+	var total int64
+
+	query := r.db.Model(&po.Comment{}).Preload("User").Preload("ReplyToUser").Where("article_id IS NULL")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if keyword != "" {
+		query = query.Where("content LIKE ?", "%"+keyword+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC, id DESC").Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
 // UpdateStatus 更新评论状态
 func (r *commentRepo) UpdateStatus(id uint, status int) error {
 	return r.db.Model(&po.Comment{}).Where("id = ?", id).Update("status", status).Error
@@ -97,7 +152,7 @@ func (r *commentRepo) UpdateStatus(id uint, status int) error {
 // CountByArticle 统计文章评论数
 func (r *commentRepo) CountByArticle(articleID uint) (int64, error) {
 	var count int64
-	err := r.db.Model(&po.Comment{}).Where("article_id = ? AND status = ?", articleID, 1).Count(&count).Error
+	err := r.db.Model(&po.Comment{}).Where("article_id = ? AND status = ?", articleID, po.CommentStatusApproved).Count(&count).Error
 	return count, err
 }
 
@@ -107,3 +162,186 @@ func (r *commentRepo) CountByUser(userID uint) (int64, error) {
 	err := r.db.Model(&po.Comment{}).Where("user_id = ?", userID).Count(&count).Error
 	return count, err
 }
+
+// ListTopLevel 分页查询审核通过的顶级评论
+func (r *commentRepo) ListTopLevel(articleID uint, page, limit int) ([]*po.Comment, int64, error) {
+	var comments []*po.Comment
+	var total int64
+
+	query := r.db.Model(&po.Comment{}).Where("parent_id IS NULL AND status = ?", po.CommentStatusApproved)
+	if articleID > 0 {
+		query = query.Where("article_id = ?", articleID)
+	} else {
+		query = query.Where("article_id IS NULL")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("User").Preload("ReplyToUser").
+		Offset(offset).Limit(limit).Order("created_at DESC").Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+// ListReplies 分页查询顶级评论下审核通过的回复
+func (r *commentRepo) ListReplies(rootID uint, page, limit int) ([]*po.Comment, int64, error) {
+	var comments []*po.Comment
+	var total int64
+
+	query := r.db.Model(&po.Comment{}).Where("root_id = ? AND status = ?", rootID, po.CommentStatusApproved)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("User").Preload("ReplyToUser").
+		Offset(offset).Limit(limit).Order("created_at ASC, id ASC").Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+// ListLatestByArticle 查询文章最新的评论
+func (r *commentRepo) ListLatestByArticle(articleID uint, limit int) ([]*po.Comment, error) {
+	var comments []*po.Comment
+	err := r.db.Preload("User").Preload("ReplyToUser").
+		Where("article_id = ? AND status = ?", articleID, po.CommentStatusApproved).
+		Order("created_at DESC, id DESC").Limit(limit).Find(&comments).Error
+	return comments, err
+}
+
+// AddReplyCount 累加顶级评论的回复数
+func (r *commentRepo) AddReplyCount(rootID uint, delta int) error {
+	return r.db.Model(&po.Comment{}).Where("id = ?", rootID).
+		UpdateColumn("reply_count", gorm.Expr("GREATEST(reply_count + ?, 0)", delta)).Error
+}
+
+// DeleteReplies 删除顶级评论下的全部回复
+func (r *commentRepo) DeleteReplies(rootID uint) (int64, error) {
+	var approved int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&po.Comment{}).Where("root_id = ? AND status = ?", rootID, po.CommentStatusApproved).
+			Count(&approved).Error; err != nil {
+			return err
+		}
+		return tx.Where("root_id = ?", rootID).Delete(&po.Comment{}).Error
+	})
+	return approved, err
+}
+
+// BackfillRoots 为历史回复补全 root_id 并重新统计回复数
+// 每轮为父评论已确定顶级评论的回复赋值，直到没有可更新的行；父评论已软删除的回复同样补全
+func (r *commentRepo) BackfillRoots() (int64, error) {
+	var filled int64
+	for {
+		result := r.db.Exec(`UPDATE comments c JOIN comments p ON c.parent_id = p.id
+			SET c.root_id = COALESCE(p.root_id, p.id)
+			WHERE c.root_id IS NULL AND (p.parent_id IS NULL OR p.root_id IS NOT NULL)`)
+		if result.Error != nil {
+			return filled, result.Error
+		}
+		if result.RowsAffected == 0 {
+			break
+		}
+		filled += result.RowsAffected
+	}
+	if filled == 0 {
+		return 0, nil
+	}
+
+	err := r.db.Exec(`UPDATE comments c JOIN (
+			SELECT root_id, COUNT(*) AS cnt FROM comments
+			WHERE root_id IS NOT NULL AND status = 1 AND deleted_at IS NULL
+			GROUP BY root_id
+		) t ON c.id = t.root_id
+		SET c.reply_count = t.cnt`).Error
+	return filled, err
+}
+
+// CountApprovedByUser 统计用户审核通过的评论数
+func (r *commentRepo) CountApprovedByUser(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&po.Comment{}).Where("user_id = ? AND status = ?", userID, po.CommentStatusApproved).Count(&count).Error
+	return count, err
+}
+
+// BatchUpdateStatus 批量更新状态，不存在的评论跳过，只更新状态有变化的评论
+func (r *commentRepo) BatchUpdateStatus(ids []uint, status int) ([]*po.Comment, error) {
+	var comments []*po.Comment
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", ids).
+			Find(&comments).Error; err != nil {
+			return err
+		}
+
+		changed := make([]uint, 0, len(comments))
+		for _, comment := range comments {
+			if comment.Status != status {
+				changed = append(changed, comment.ID)
+			}
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+		return tx.Model(&po.Comment{}).Where("id IN ?", changed).Update("status", status).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// Edit 更新评论内容并记录编辑前的内容
+func (r *commentRepo) Edit(comment *po.Comment, content, contentHTML string, editorID uint) error {
+	now := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		edit := &po.CommentEdit{
+			CommentID: comment.ID,
+			EditorID:  editorID,
+			Content:   comment.Content,
+			CreatedAt: now,
+		}
+		if err := tx.Create(edit).Error; err != nil {
+			return err
+		}
+		return tx.Model(&po.Comment{}).Where("id = ?", comment.ID).UpdateColumns(map[string]interface{}{
+			"content":      content,
+			"content_html": contentHTML,
+			"edited_at":    now,
+		}).Error
+	})
+}
+
+// ListEdits 查询评论的编辑记录
+func (r *commentRepo) ListEdits(commentID uint) ([]*po.CommentEdit, error) {
+	var edits []*po.CommentEdit
+	err := r.db.Preload("Editor").Where("comment_id = ?", commentID).
+		Order("created_at DESC, id DESC").Find(&edits).Error
+	return edits, err
+}
+
+// ExportBatches 分批查询评论，fn 返回错误时停止
+func (r *commentRepo) ExportBatches(articleID, userID uint, status string, fn func([]*po.Comment) error) error {
+	query := r.db.Model(&po.Comment{}).Preload("User").Preload("Article")
+	if articleID > 0 {
+		query = query.Where("article_id = ?", articleID)
+	}
+	if userID > 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var batch []*po.Comment
+	return query.Order("id ASC").FindInBatches(&batch, commentExportBatchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
@@ -0,0 +1,100 @@
+package data
+
+import (
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// CommentBlockRepo 评论屏蔽规则仓储接口
+type CommentBlockRepo interface {
+	// Create 创建规则
+	Create(rule *po.CommentBlockRule) error
+	// Update 更新规则
+	Update(rule *po.CommentBlockRule) error
+	// Delete 删除规则
+	Delete(id uint) error
+	// FindByID 根据 ID 查询规则
+	FindByID(id uint) (*po.CommentBlockRule, error)
+	// List 查询规则列表，ruleType 为空时查询全部类型
+	List(page, limit int, ruleType, keyword string) ([]*po.CommentBlockRule, int64, error)
+	// ListEnabled 查询全部启用的规则
+	ListEnabled() ([]*po.CommentBlockRule, error)
+	// RecordHit 命中次数加一并记录命中时间
+	RecordHit(id uint) error
+}
+
+// commentBlockRepo 评论屏蔽规则仓储实现
+type commentBlockRepo struct {
+	db *gorm.DB
+}
+
+// NewCommentBlockRepo 创建评论屏蔽规则仓储
+func NewCommentBlockRepo(db *gorm.DB) CommentBlockRepo {
+	return &commentBlockRepo{db: db}
+}
+
+// Create 创建规则
+func (r *commentBlockRepo) Create(rule *po.CommentBlockRule) error {
+	return r.db.Create(rule).Error
+}
+
+// Update 更新规则
+func (r *commentBlockRepo) Update(rule *po.CommentBlockRule) error {
+	return r.db.Save(rule).Error
+}
+
+// Delete 删除规则
+func (r *commentBlockRepo) Delete(id uint) error {
+	return r.db.Delete(&po.CommentBlockRule{}, id).Error
+}
+
+// FindByID 根据 ID 查询规则
+func (r *commentBlockRepo) FindByID(id uint) (*po.CommentBlockRule, error) {
+	var rule po.CommentBlockRule
+	if err := r.db.First(&rule, id).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// List 查询规则列表
+func (r *commentBlockRepo) List(page, limit int, ruleType, keyword string) ([]*po.CommentBlockRule, int64, error) {
+	var rules []*po.CommentBlockRule
+	var total int64
+
+	query := r.db.Model(&po.CommentBlockRule{})
+	if ruleType != "" {
+		query = query.Where("type = ?", ruleType)
+	}
+	if keyword != "" {
+		query = query.Where("value LIKE ? OR note LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&rules).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return rules, total, nil
+}
+
+// ListEnabled 查询全部启用的规则
+func (r *commentBlockRepo) ListEnabled() ([]*po.CommentBlockRule, error) {
+	var rules []*po.CommentBlockRule
+	err := r.db.Where("enabled = ?", true).Order("id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// RecordHit 命中次数加一并记录命中时间
+func (r *commentBlockRepo) RecordHit(id uint) error {
+	return r.db.Model(&po.CommentBlockRule{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"hit_count":   gorm.Expr("hit_count + ?", 1),
+		"last_hit_at": time.Now(),
+	}).Error
+}
@@ -6,37 +6,93 @@ import (
 
 // Data 数据层结构，包含所有 Repository
 type Data struct {
-	db              *gorm.DB
-	AdminRepo       AdminRepo
-	UserRepo        UserRepo
-	ArticleRepo     ArticleRepo
-	CategoryRepo    CategoryRepo
-	TagRepo         TagRepo
-	CommentRepo     CommentRepo
-	LikeRepo        LikeRepo
-	FavoriteRepo    FavoriteRepo
-	CommentLikeRepo CommentLikeRepo
-	ViewRepo        ViewRepo
-	FileRepo        FileRepo
-	SettingRepo     SettingRepo
+	db                     *gorm.DB
+	AdminRepo              AdminRepo
+	UserRepo               UserRepo
+	ArticleRepo            ArticleRepo
+	CategoryRepo           CategoryRepo
+	TagRepo                TagRepo
+	CommentRepo            CommentRepo
+	LikeRepo               LikeRepo
+	FavoriteRepo           FavoriteRepo
+	CommentLikeRepo        CommentLikeRepo
+	ViewRepo               ViewRepo
+	FileRepo               FileRepo
+	SettingRepo            SettingRepo
+	RoutePermissionRepo    RoutePermissionRepo
+	ArticleVersionRepo     ArticleVersionRepo
+	ArticleDraftRepo       ArticleDraftRepo
+	SeriesRepo             SeriesRepo
+	ArticleAuthorRepo      ArticleAuthorRepo
+	ArticleTranslationRepo ArticleTranslationRepo
+	ArticleAuditRepo       ArticleAuditRepo
+	WebhookRepo            WebhookRepo
+	YuqueRepo              YuqueRepo
+	UploadSessionRepo      UploadSessionRepo
+	AttachmentRepo         AttachmentRepo
+	ReactionRepo           ReactionRepo
+	CommentBlockRepo       CommentBlockRepo
+	UserIdentityRepo       UserIdentityRepo
+	TwoFactorRepo          TwoFactorRepo
+	APIKeyRepo             APIKeyRepo
+	RoleRepo               RoleRepo
+	LoginLogRepo           LoginLogRepo
+	FollowRepo             FollowRepo
+	FavoriteFolderRepo     FavoriteFolderRepo
+	ReadingProgressRepo    ReadingProgressRepo
+	PushSubscriptionRepo   PushSubscriptionRepo
+	FriendLinkRepo         FriendLinkRepo
+	MomentRepo             MomentRepo
+	PageRepo               PageRepo
+	MenuRepo               MenuRepo
+	BannerRepo             BannerRepo
+	AnnouncementRepo       AnnouncementRepo
 }
 
 // NewData 创建数据层实例
 func NewData(db *gorm.DB) (*Data, error) {
 	return &Data{
-		db:              db,
-		AdminRepo:       NewAdminRepo(db),
-		UserRepo:        NewUserRepo(db),
-		ArticleRepo:     NewArticleRepo(db),
-		CategoryRepo:    NewCategoryRepo(db),
-		TagRepo:         NewTagRepo(db),
-		CommentRepo:     NewCommentRepo(db),
-		LikeRepo:        NewLikeRepo(db),
-		FavoriteRepo:    NewFavoriteRepo(db),
-		CommentLikeRepo: NewCommentLikeRepo(db),
-		ViewRepo:        NewViewRepo(db),
-		FileRepo:        NewFileRepo(db),
-		SettingRepo:     NewSettingRepo(db),
+		db:                     db,
+		AdminRepo:              NewAdminRepo(db),
+		UserRepo:               NewUserRepo(db),
+		ArticleRepo:            NewArticleRepo(db),
+		CategoryRepo:           NewCategoryRepo(db),
+		TagRepo:                NewTagRepo(db),
+		CommentRepo:            NewCommentRepo(db),
+		LikeRepo:               NewLikeRepo(db),
+		FavoriteRepo:           NewFavoriteRepo(db),
+		CommentLikeRepo:        NewCommentLikeRepo(db),
+		ViewRepo:               NewViewRepo(db),
+		FileRepo:               NewFileRepo(db),
+		SettingRepo:            NewSettingRepo(db),
+		RoutePermissionRepo:    NewRoutePermissionRepo(db),
+		ArticleVersionRepo:     NewArticleVersionRepo(db),
+		ArticleDraftRepo:       NewArticleDraftRepo(db),
+		SeriesRepo:             NewSeriesRepo(db),
+		ArticleAuthorRepo:      NewArticleAuthorRepo(db),
+		ArticleTranslationRepo: NewArticleTranslationRepo(db),
+		ArticleAuditRepo:       NewArticleAuditRepo(db),
+		WebhookRepo:            NewWebhookRepo(db),
+		YuqueRepo:              NewYuqueRepo(db),
+		UploadSessionRepo:      NewUploadSessionRepo(db),
+		AttachmentRepo:         NewAttachmentRepo(db),
+		ReactionRepo:           NewReactionRepo(db),
+		CommentBlockRepo:       NewCommentBlockRepo(db),
+		UserIdentityRepo:       NewUserIdentityRepo(db),
+		TwoFactorRepo:          NewTwoFactorRepo(db),
+		APIKeyRepo:             NewAPIKeyRepo(db),
+		RoleRepo:               NewRoleRepo(db),
+		LoginLogRepo:           NewLoginLogRepo(db),
+		FollowRepo:             NewFollowRepo(db),
+		FavoriteFolderRepo:     NewFavoriteFolderRepo(db),
+		ReadingProgressRepo:    NewReadingProgressRepo(db),
+		PushSubscriptionRepo:   NewPushSubscriptionRepo(db),
+		FriendLinkRepo:         NewFriendLinkRepo(db),
+		MomentRepo:             NewMomentRepo(db),
+		PageRepo:               NewPageRepo(db),
+		MenuRepo:               NewMenuRepo(db),
+		BannerRepo:             NewBannerRepo(db),
+		AnnouncementRepo:       NewAnnouncementRepo(db),
 	}, nil
 }
 
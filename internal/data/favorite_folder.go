@@ -0,0 +1,89 @@
+package data
+
+import (
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// FavoriteFolderRepo 收藏夹仓储接口
+type FavoriteFolderRepo interface {
+	// Create 创建收藏夹
+	Create(folder *po.FavoriteFolder) error
+	// Update 更新收藏夹
+	Update(folder *po.FavoriteFolder) error
+	// Delete 删除收藏夹，其中的收藏移回默认收藏夹
+	Delete(folder *po.FavoriteFolder) error
+	// FindByID 查询用户的收藏夹
+	FindByID(userID, id uint) (*po.FavoriteFolder, error)
+	// ListByUser 查询用户的全部收藏夹，按创建顺序
+	ListByUser(userID uint) ([]*po.FavoriteFolder, error)
+	// NameExists 检查用户是否已有同名收藏夹
+	NameExists(userID uint, name string, excludeID uint) (bool, error)
+	// CountByUser 统计用户的收藏夹数
+	CountByUser(userID uint) (int64, error)
+}
+
+// favoriteFolderRepo 收藏夹仓储实现
+type favoriteFolderRepo struct {
+	db *gorm.DB
+}
+
+// NewFavoriteFolderRepo 创建收藏夹仓储
+func NewFavoriteFolderRepo(db *gorm.DB) FavoriteFolderRepo {
+	return &favoriteFolderRepo{db: db}
+}
+
+// Create 创建收藏夹
+func (r *favoriteFolderRepo) Create(folder *po.FavoriteFolder) error {
+	return r.db.Create(folder).Error
+}
+
+// Update 更新收藏夹
+func (r *favoriteFolderRepo) Update(folder *po.FavoriteFolder) error {
+	return r.db.Save(folder).Error
+}
+
+// Delete 删除收藏夹，在同一事务中将其中的收藏移回默认收藏夹
+func (r *favoriteFolderRepo) Delete(folder *po.FavoriteFolder) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&po.Favorite{}).
+			Where("user_id = ? AND folder_id = ?", folder.UserID, folder.ID).
+			Update("folder_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&po.FavoriteFolder{}, folder.ID).Error
+	})
+}
+
+// FindByID 查询用户的收藏夹
+func (r *favoriteFolderRepo) FindByID(userID, id uint) (*po.FavoriteFolder, error) {
+	var folder po.FavoriteFolder
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&folder).Error
+	if err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// ListByUser 查询用户的全部收藏夹
+func (r *favoriteFolderRepo) ListByUser(userID uint) ([]*po.FavoriteFolder, error) {
+	var folders []*po.FavoriteFolder
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&folders).Error
+	return folders, err
+}
+
+// NameExists 检查用户是否已有同名收藏夹
+func (r *favoriteFolderRepo) NameExists(userID uint, name string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&po.FavoriteFolder{}).
+		Where("user_id = ? AND name = ? AND id <> ?", userID, name, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CountByUser 统计用户的收藏夹数
+func (r *favoriteFolderRepo) CountByUser(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&po.FavoriteFolder{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,126 @@
+package data
+
+import (
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// FollowRepo 关注仓储接口
+type FollowRepo interface {
+	// Create 创建关注，已关注时不重复创建
+	Create(follow *po.Follow) error
+	// Delete 取消关注
+	Delete(followerID, followeeID uint) error
+	// Exists 检查是否已关注
+	Exists(followerID, followeeID uint) (bool, error)
+	// ListFollowers 分页查询用户的粉丝，按关注时间倒序
+	ListFollowers(userID uint, page, limit int) ([]*po.Follow, int64, error)
+	// ListFollowing 分页查询用户关注的人，按关注时间倒序
+	ListFollowing(userID uint, page, limit int) ([]*po.Follow, int64, error)
+	// CountFollowers 统计粉丝数
+	CountFollowers(userID uint) (int64, error)
+	// CountFollowing 统计关注数
+	CountFollowing(userID uint) (int64, error)
+}
+
+// followRepo 关注仓储实现
+type followRepo struct {
+	db *gorm.DB
+}
+
+// NewFollowRepo 创建关注仓储
+func NewFollowRepo(db *gorm.DB) FollowRepo {
+	return &followRepo{db: db}
+}
+
+// Create 创建关注，并发关注时由唯一索引去重
+func (r *followRepo) Create(follow *po.Follow) error {
+	if err := r.db.Create(follow).Error; err != nil {
+		if exists, existsErr := r.Exists(follow.FollowerID, follow.FolloweeID); existsErr == nil && exists {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete 取消关注
+func (r *followRepo) Delete(followerID, followeeID uint) error {
+	return r.db.Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&po.Follow{}).Error
+}
+
+// Exists 检查是否已关注
+func (r *followRepo) Exists(followerID, followeeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&po.Follow{}).Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Count(&count).Error
+	return count > 0, err
+}
+
+// ListFollowers 分页查询用户的粉丝
+func (r *followRepo) ListFollowers(userID uint, page, limit int) ([]*po.Follow, int64, error) {
+	return r.list("followee_id = ?", "Follower", userID, page, limit)
+}
+
+// ListFollowing 分页查询用户关注的人
+func (r *followRepo) ListFollowing(userID uint, page, limit int) ([]*po.Follow, int64, error) {
+	return r.list("follower_id = ?", "Followee", userID, page, limit)
+}
+
+// list 按条件分页查询关注关系，并预加载另一方用户
+func (r *followRepo) list(condition, preload string, userID uint, page, limit int) ([]*po.Follow, int64, error) {
+	var follows []*po.Follow
+	var total int64
+
+	offset := (page - 1) * limit
+	query := r.db.Model(&po.Follow{}).Where(condition, userID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload(preload).Offset(offset).Limit(limit).Order("created_at DESC, id DESC").Find(&follows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return follows, total, nil
+}
+
+// CountFollowers 统计粉丝数
+func (r *followRepo) CountFollowers(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&po.Follow{}).Where("followee_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// CountFollowing 统计关注数
+func (r *followRepo) CountFollowing(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&po.Follow{}).Where("follower_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// followedBy 限定为 followerID 关注的用户作为主作者或共同作者的文章
+func followedBy(db *gorm.DB, followerID uint) *gorm.DB {
+	followees := "SELECT followee_id FROM follows WHERE follower_id = ?"
+	return db.Where("articles.author_id IN ("+followees+") OR articles.id IN (SELECT article_id FROM article_authors WHERE user_id IN ("+followees+"))",
+		followerID, followerID)
+}
+
+// ListFollowed 分页查询关注的作者已发布的文章，按发布时间倒序
+func (r *articleRepo) ListFollowed(page, limit int, followerID uint) ([]*po.Article, int64, error) {
+	var articles []*po.Article
+	var total int64
+
+	offset := (page - 1) * limit
+	query := followedBy(r.listQuery(0, 0, 0, 0, "1", ""), followerID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.selectColumns(query).Offset(offset).Limit(limit).Order("articles.created_at DESC, articles.id DESC").Find(&articles).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return articles, total, nil
+}
@@ -0,0 +1,153 @@
+package data
+
+import (
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// FriendLinkRepo 友情链接仓储接口
+type FriendLinkRepo interface {
+	// Create 创建友链
+	Create(link *po.FriendLink) error
+	// Update 更新友链
+	Update(link *po.FriendLink) error
+	// Delete 删除友链
+	Delete(id uint) error
+	// FindByID 根据 ID 查询友链
+	FindByID(id uint) (*po.FriendLink, error)
+	// FindByURL 根据地址查询友链
+	FindByURL(url string) (*po.FriendLink, error)
+	// List 查询友链列表，status 为空时查询全部状态，minFailures 大于 0 时只查询连续检测失败次数不少于该值的友链
+	List(page, limit int, status, keyword string, minFailures int) ([]*po.FriendLink, int64, error)
+	// ListVisible 查询前台显示的友链：审核通过且连续检测失败次数小于 maxFailures，按排序值排列
+	ListVisible(maxFailures int) ([]*po.FriendLink, error)
+	// ListApproved 查询全部审核通过的友链
+	ListApproved() ([]*po.FriendLink, error)
+	// UpdateSort 批量更新排序值
+	UpdateSort(sorts map[uint]int) error
+	// SaveCheck 保存检测结果，失败时连续失败次数加一，成功时清零
+	SaveCheck(id uint, status int, checkErr string, failed bool) error
+	// CountByIPSince 统计 IP 在某时间之后提交的申请数
+	CountByIPSince(ip string, since time.Time) (int64, error)
+}
+
+// friendLinkRepo 友情链接仓储实现
+type friendLinkRepo struct {
+	db *gorm.DB
+}
+
+// NewFriendLinkRepo 创建友情链接仓储
+func NewFriendLinkRepo(db *gorm.DB) FriendLinkRepo {
+	return &friendLinkRepo{db: db}
+}
+
+// Create 创建友链
+func (r *friendLinkRepo) Create(link *po.FriendLink) error {
+	return r.db.Create(link).Error
+}
+
+// Update 更新友链
+func (r *friendLinkRepo) Update(link *po.FriendLink) error {
+	return r.db.Save(link).Error
+}
+
+// Delete 删除友链
+func (r *friendLinkRepo) Delete(id uint) error {
+	return r.db.Delete(&po.FriendLink{}, id).Error
+}
+
+// FindByID 根据 ID 查询友链
+func (r *friendLinkRepo) FindByID(id uint) (*po.FriendLink, error) {
+	var link po.FriendLink
+	if err := r.db.First(&link, id).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// FindByURL 根据地址查询友链
+func (r *friendLinkRepo) FindByURL(url string) (*po.FriendLink, error) {
+	var link po.FriendLink
+	if err := r.db.Where("url = ?", url).First(&link).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// List 查询友链列表
+func (r *friendLinkRepo) List(page, limit int, status, keyword string, minFailures int) ([]*po.FriendLink, int64, error) {
+	var links []*po.FriendLink
+	var total int64
+
+	query := r.db.Model(&po.FriendLink{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if keyword != "" {
+		query = query.Where("name LIKE ? OR url LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+	if minFailures > 0 {
+		query = query.Where("fail_count >= ?", minFailures)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("sort ASC, id DESC").Offset(offset).Limit(limit).Find(&links).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return links, total, nil
+}
+
+// ListVisible 查询前台显示的友链
+func (r *friendLinkRepo) ListVisible(maxFailures int) ([]*po.FriendLink, error) {
+	var links []*po.FriendLink
+	err := r.db.Where("status = ? AND fail_count < ?", po.FriendLinkApproved, maxFailures).
+		Order("sort ASC, id ASC").Find(&links).Error
+	return links, err
+}
+
+// ListApproved 查询全部审核通过的友链
+func (r *friendLinkRepo) ListApproved() ([]*po.FriendLink, error) {
+	var links []*po.FriendLink
+	err := r.db.Where("status = ?", po.FriendLinkApproved).Order("id ASC").Find(&links).Error
+	return links, err
+}
+
+// UpdateSort 批量更新排序值
+func (r *friendLinkRepo) UpdateSort(sorts map[uint]int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for id, sort := range sorts {
+			if err := tx.Model(&po.FriendLink{}).Where("id = ?", id).UpdateColumn("sort", sort).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SaveCheck 保存检测结果
+func (r *friendLinkRepo) SaveCheck(id uint, status int, checkErr string, failed bool) error {
+	failCount := gorm.Expr("0")
+	if failed {
+		failCount = gorm.Expr("fail_count + ?", 1)
+	}
+	return r.db.Model(&po.FriendLink{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"check_status": status,
+		"check_error":  checkErr,
+		"fail_count":   failCount,
+		"checked_at":   time.Now(),
+	}).Error
+}
+
+// CountByIPSince 统计 IP 在某时间之后提交的申请数
+func (r *friendLinkRepo) CountByIPSince(ip string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&po.FriendLink{}).Where("ip = ? AND created_at >= ?", ip, since).Count(&count).Error
+	return count, err
+}
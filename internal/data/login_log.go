@@ -0,0 +1,84 @@
+package data
+
+import (
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// LoginLogRepo 登录日志仓储接口
+type LoginLogRepo interface {
+	// Create 记录登录日志
+	Create(log *po.LoginLog) error
+	// List 查询登录日志，userID 为 0、username 为空或 success 为 nil 时不过滤
+	List(page, limit int, userID uint, username string, success *bool) ([]*po.LoginLog, int64, error)
+	// HasSucceeded 用户是否有登录成功的记录
+	HasSucceeded(userID uint) (bool, error)
+	// HasLocation 用户是否在该所在地登录成功过
+	HasLocation(userID uint, location string) (bool, error)
+	// DeleteBefore 删除早于指定时间的日志
+	DeleteBefore(t time.Time) (int64, error)
+}
+
+// loginLogRepo 登录日志仓储实现
+type loginLogRepo struct {
+	db *gorm.DB
+}
+
+// NewLoginLogRepo 创建登录日志仓储
+func NewLoginLogRepo(db *gorm.DB) LoginLogRepo {
+	return &loginLogRepo{db: db}
+}
+
+// Create 记录登录日志
+func (r *loginLogRepo) Create(log *po.LoginLog) error {
+	return r.db.Create(log).Error
+}
+
+// List 按时间倒序查询登录日志
+func (r *loginLogRepo) List(page, limit int, userID uint, username string, success *bool) ([]*po.LoginLog, int64, error) {
+	var logs []*po.LoginLog
+	var total int64
+
+	query := r.db.Model(&po.LoginLog{})
+	if userID > 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if username != "" {
+		query = query.Where("username = ?", username)
+	}
+	if success != nil {
+		query = query.Where("success = ?", *success)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&logs).Error
+	return logs, total, err
+}
+
+// HasSucceeded 用户是否有登录成功的记录
+func (r *loginLogRepo) HasSucceeded(userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&po.LoginLog{}).Where("user_id = ? AND success = ?", userID, true).Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+// HasLocation 用户是否在该所在地登录成功过
+func (r *loginLogRepo) HasLocation(userID uint, location string) (bool, error) {
+	var count int64
+	err := r.db.Model(&po.LoginLog{}).
+		Where("user_id = ? AND success = ? AND location = ?", userID, true, location).
+		Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteBefore 删除早于指定时间的日志
+func (r *loginLogRepo) DeleteBefore(t time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", t).Delete(&po.LoginLog{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,82 @@
+package data
+
+import (
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// MenuRepo 导航菜单仓储接口
+type MenuRepo interface {
+	// Create 创建菜单项
+	Create(item *po.MenuItem) error
+	// Update 更新菜单项
+	Update(item *po.MenuItem) error
+	// Delete 批量删除菜单项
+	Delete(ids []uint) error
+	// FindByID 根据 ID 查询菜单项
+	FindByID(id uint) (*po.MenuItem, error)
+	// ListByLocation 查询某个位置的全部菜单项，按排序值排列
+	ListByLocation(location string) ([]*po.MenuItem, error)
+	// UpdateTree 批量更新菜单项的上级和排序值
+	UpdateTree(items []*po.MenuItem) error
+}
+
+// menuRepo 导航菜单仓储实现
+type menuRepo struct {
+	db *gorm.DB
+}
+
+// NewMenuRepo 创建导航菜单仓储
+func NewMenuRepo(db *gorm.DB) MenuRepo {
+	return &menuRepo{db: db}
+}
+
+// Create 创建菜单项
+func (r *menuRepo) Create(item *po.MenuItem) error {
+	return r.db.Create(item).Error
+}
+
+// Update 更新菜单项
+func (r *menuRepo) Update(item *po.MenuItem) error {
+	return r.db.Save(item).Error
+}
+
+// Delete 批量删除菜单项
+func (r *menuRepo) Delete(ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Where("id IN ?", ids).Delete(&po.MenuItem{}).Error
+}
+
+// FindByID 根据 ID 查询菜单项
+func (r *menuRepo) FindByID(id uint) (*po.MenuItem, error) {
+	var item po.MenuItem
+	if err := r.db.First(&item, id).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ListByLocation 查询某个位置的全部菜单项
+func (r *menuRepo) ListByLocation(location string) ([]*po.MenuItem, error) {
+	var items []*po.MenuItem
+	err := r.db.Where("location = ?", location).Order("sort ASC, id ASC").Find(&items).Error
+	return items, err
+}
+
+// UpdateTree 批量更新菜单项的上级和排序值
+func (r *menuRepo) UpdateTree(items []*po.MenuItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, item := range items {
+			err := tx.Model(&po.MenuItem{}).Where("id = ?", item.ID).UpdateColumns(map[string]interface{}{
+				"parent_id": item.ParentID,
+				"sort":      item.Sort,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,132 @@
+package data
+
+import (
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MomentRepo 说说仓储接口
+type MomentRepo interface {
+	// Create 创建说说
+	Create(moment *po.Moment) error
+	// Update 更新说说
+	Update(moment *po.Moment) error
+	// Delete 删除说说及其点赞记录
+	Delete(id uint) error
+	// FindByID 根据 ID 查询说说
+	FindByID(id uint) (*po.Moment, error)
+	// List 分页查询说说，按发布时间倒序，keyword 匹配内容和位置
+	List(page, limit int, keyword string) ([]*po.Moment, int64, error)
+	// Like 点赞说说并累加点赞数，已点赞时返回 false
+	Like(momentID, userID uint) (bool, error)
+	// Unlike 取消点赞并扣减点赞数，未点赞时返回 false
+	Unlike(momentID, userID uint) (bool, error)
+	// LikedIDs 返回 momentIDs 中用户已点赞的说说
+	LikedIDs(userID uint, momentIDs []uint) (map[uint]bool, error)
+}
+
+// momentRepo 说说仓储实现
+type momentRepo struct {
+	db *gorm.DB
+}
+
+// NewMomentRepo 创建说说仓储
+func NewMomentRepo(db *gorm.DB) MomentRepo {
+	return &momentRepo{db: db}
+}
+
+// Create 创建说说
+func (r *momentRepo) Create(moment *po.Moment) error {
+	return r.db.Create(moment).Error
+}
+
+// Update 更新说说
+func (r *momentRepo) Update(moment *po.Moment) error {
+	return r.db.Save(moment).Error
+}
+
+// Delete 删除说说及其点赞记录
+func (r *momentRepo) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("moment_id = ?", id).Delete(&po.MomentLike{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&po.Moment{}, id).Error
+	})
+}
+
+// FindByID 根据 ID 查询说说
+func (r *momentRepo) FindByID(id uint) (*po.Moment, error) {
+	var moment po.Moment
+	if err := r.db.First(&moment, id).Error; err != nil {
+		return nil, err
+	}
+	return &moment, nil
+}
+
+// List 分页查询说说
+func (r *momentRepo) List(page, limit int, keyword string) ([]*po.Moment, int64, error) {
+	var moments []*po.Moment
+	var total int64
+
+	query := r.db.Model(&po.Moment{})
+	if keyword != "" {
+		query = query.Where("content LIKE ? OR location LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&moments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return moments, total, nil
+}
+
+// Like 点赞说说，并发点赞时由唯一索引去重，只有新增记录时累加点赞数
+func (r *momentRepo) Like(momentID, userID uint) (bool, error) {
+	liked := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&po.MomentLike{MomentID: momentID, UserID: userID})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		liked = true
+		return tx.Model(&po.Moment{}).Where("id = ?", momentID).
+			UpdateColumn("like_count", gorm.Expr("like_count + ?", 1)).Error
+	})
+	return liked, err
+}
+
+// Unlike 取消点赞，只有删除了记录时扣减点赞数
+func (r *momentRepo) Unlike(momentID, userID uint) (bool, error) {
+	unliked := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("moment_id = ? AND user_id = ?", momentID, userID).Delete(&po.MomentLike{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		unliked = true
+		return tx.Model(&po.Moment{}).Where("id = ? AND like_count > 0", momentID).
+			UpdateColumn("like_count", gorm.Expr("like_count - ?", 1)).Error
+	})
+	return unliked, err
+}
+
+// LikedIDs 返回 momentIDs 中用户已点赞的说说
+func (r *momentRepo) LikedIDs(userID uint, momentIDs []uint) (map[uint]bool, error) {
+	liked := make(map[uint]bool)
+	if userID == 0 || len(momentIDs) == 0 {
+		return liked, nil
+	}
+	var ids []uint
+	err := r.db.Model(&po.MomentLike{}).Where("user_id = ? AND moment_id IN ?", userID, momentIDs).Pluck("moment_id", &ids).Error
+	for _, id := range ids {
+		liked[id] = true
+	}
+	return liked, err
+}
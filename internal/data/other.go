@@ -3,6 +3,7 @@ package data
 import (
 	"github.com/ydcloud-dy/leaf-api/internal/model/po"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // LikeRepo 点赞仓储接口
@@ -104,16 +105,24 @@ func (r *likeRepo) CountByUser(userID uint) (int64, error) {
 
 // FavoriteRepo 收藏仓储接口
 type FavoriteRepo interface {
-	// Create 创建收藏
-	Create(favorite *po.Favorite) error
-	// Delete 删除收藏
-	Delete(articleID, userID uint) error
+	// Create 创建收藏，返回是否新增了记录，已收藏时不重复创建
+	Create(favorite *po.Favorite) (bool, error)
+	// Delete 删除收藏，返回删除的记录数
+	Delete(articleID, userID uint) (int64, error)
 	// Exists 检查是否已收藏
 	Exists(articleID, userID uint) (bool, error)
 	// List 查询收藏列表
 	List(articleID uint, page, limit int) ([]*po.Favorite, int64, error)
 	// ListByUser 根据用户ID查询收藏列表
 	ListByUser(userID uint, page, limit int) ([]*po.Favorite, int64, error)
+	// ListByFolder 分页查询用户的收藏，folderID 为 nil 时查询全部，为 0 时查询默认收藏夹
+	ListByFolder(userID uint, folderID *uint, page, limit int) ([]*po.Favorite, int64, error)
+	// Move 将用户收藏的文章移动到收藏夹，folderID 为 0 时移回默认收藏夹，返回移动的记录数
+	Move(userID uint, articleIDs []uint, folderID uint) (int64, error)
+	// ClearFolder 将收藏夹中的收藏移回默认收藏夹
+	ClearFolder(userID, folderID uint) error
+	// CountByFolder 按收藏夹统计用户的收藏数，默认收藏夹的键为 0
+	CountByFolder(userID uint) (map[uint]int64, error)
 	// CountByArticle 统计文章收藏数
 	CountByArticle(articleID uint) (int64, error)
 	// CountByUser 统计用户收藏数
@@ -130,14 +139,16 @@ func NewFavoriteRepo(db *gorm.DB) FavoriteRepo {
 	return &favoriteRepo{db: db}
 }
 
-// Create 创建收藏
-func (r *favoriteRepo) Create(favorite *po.Favorite) error {
-	return r.db.Create(favorite).Error
+// Create 创建收藏，并发收藏时由唯一索引去重
+func (r *favoriteRepo) Create(favorite *po.Favorite) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(favorite)
+	return result.RowsAffected > 0, result.Error
 }
 
-// Delete 删除收藏
-func (r *favoriteRepo) Delete(articleID, userID uint) error {
-	return r.db.Where("article_id = ? AND user_id = ?", articleID, userID).Delete(&po.Favorite{}).Error
+// Delete 删除收藏，返回删除的记录数
+func (r *favoriteRepo) Delete(articleID, userID uint) (int64, error) {
+	result := r.db.Where("article_id = ? AND user_id = ?", articleID, userID).Delete(&po.Favorite{})
+	return result.RowsAffected, result.Error
 }
 
 // Exists 检查是否已收藏
@@ -185,6 +196,78 @@ func (r *favoriteRepo) ListByUser(userID uint, page, limit int) ([]*po.Favorite,
 	return favorites, total, nil
 }
 
+// ListByFolder 分页查询用户的收藏，按收藏时间倒序
+func (r *favoriteRepo) ListByFolder(userID uint, folderID *uint, page, limit int) ([]*po.Favorite, int64, error) {
+	var favorites []*po.Favorite
+	var total int64
+
+	offset := (page - 1) * limit
+	query := r.db.Model(&po.Favorite{}).Where("user_id = ?", userID)
+	if folderID != nil {
+		query = query.Where("folder_id <=> ?", folderColumn(*folderID))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Article").Preload("Article.Author").Preload("Article.Category").Preload("Article.Tags").
+		Offset(offset).Limit(limit).Order("created_at DESC, id DESC").Find(&favorites).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return favorites, total, nil
+}
+
+// Move 将用户收藏的文章移动到收藏夹
+func (r *favoriteRepo) Move(userID uint, articleIDs []uint, folderID uint) (int64, error) {
+	result := r.db.Model(&po.Favorite{}).
+		Where("user_id = ? AND article_id IN ?", userID, articleIDs).
+		Update("folder_id", folderColumn(folderID))
+	return result.RowsAffected, result.Error
+}
+
+// ClearFolder 将收藏夹中的收藏移回默认收藏夹
+func (r *favoriteRepo) ClearFolder(userID, folderID uint) error {
+	return r.db.Model(&po.Favorite{}).
+		Where("user_id = ? AND folder_id = ?", userID, folderID).
+		Update("folder_id", nil).Error
+}
+
+// CountByFolder 按收藏夹统计用户的收藏数
+func (r *favoriteRepo) CountByFolder(userID uint) (map[uint]int64, error) {
+	var rows []struct {
+		FolderID *uint
+		Count    int64
+	}
+	err := r.db.Model(&po.Favorite{}).
+		Select("folder_id, COUNT(*) AS count").
+		Where("user_id = ?", userID).
+		Group("folder_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		var id uint
+		if row.FolderID != nil {
+			id = *row.FolderID
+		}
+		counts[id] = row.Count
+	}
+	return counts, nil
+}
+
+// folderColumn 收藏夹 ID 为 0 表示默认收藏夹，数据库中存储为 NULL
+func folderColumn(folderID uint) *uint {
+	if folderID == 0 {
+		return nil
+	}
+	return &folderID
+}
+
 // CountByArticle 统计文章收藏数
 func (r *favoriteRepo) CountByArticle(articleID uint) (int64, error) {
 	var count int64
@@ -294,6 +377,10 @@ type FileRepo interface {
 	FindByID(id uint) (*po.File, error)
 	// List 查询文件列表
 	List(page, limit int) ([]*po.File, int64, error)
+	// ListImages 查询图片文件，keyword 匹配文件名，folder 不为空时只返回该目录的图片
+	ListImages(page, limit int, keyword, folder string) ([]*po.File, int64, error)
+	// FindByURLs 根据地址批量查询文件，地址没有上传记录时不返回
+	FindByURLs(urls []string) ([]*po.File, error)
 }
 
 // fileRepo 文件仓储实现
@@ -345,6 +432,41 @@ func (r *fileRepo) List(page, limit int) ([]*po.File, int64, error) {
 	return files, total, nil
 }
 
+// ListImages 查询图片文件，按上传时间倒序
+func (r *fileRepo) ListImages(page, limit int, keyword, folder string) ([]*po.File, int64, error) {
+	var files []*po.File
+	var total int64
+
+	query := r.db.Model(&po.File{}).Where("mime_type LIKE ?", "image/%")
+	if keyword != "" {
+		query = query.Where("name LIKE ?", "%"+keyword+"%")
+	}
+	if folder != "" {
+		query = query.Where("type = ?", folder)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC, id DESC").Find(&files).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return files, total, nil
+}
+
+// FindByURLs 根据地址批量查询文件
+func (r *fileRepo) FindByURLs(urls []string) ([]*po.File, error) {
+	var files []*po.File
+	if len(urls) == 0 {
+		return files, nil
+	}
+	err := r.db.Where("url IN ?", urls).Find(&files).Error
+	return files, err
+}
+
 // SettingRepo 设置仓储接口
 type SettingRepo interface {
 	// Create 创建设置
@@ -373,48 +495,77 @@ func NewSettingRepo(db *gorm.DB) SettingRepo {
 
 // Create 创建设置
 func (r *settingRepo) Create(setting *po.Setting) error {
-	return r.db.Create(setting).Error
+	if err := r.db.Create(setting).Error; err != nil {
+		return err
+	}
+	InvalidateSettings()
+	return nil
 }
 
 // Update 更新设置
 func (r *settingRepo) Update(setting *po.Setting) error {
-	return r.db.Save(setting).Error
+	if err := r.db.Save(setting).Error; err != nil {
+		return err
+	}
+	InvalidateSettings()
+	return nil
 }
 
 // Delete 删除设置
 func (r *settingRepo) Delete(key string) error {
-	return r.db.Where("key = ?", key).Delete(&po.Setting{}).Error
+	if err := r.db.Where("`key` = ?", key).Delete(&po.Setting{}).Error; err != nil {
+		return err
+	}
+	InvalidateSettings()
+	return nil
 }
 
-// FindByKey 根据 Key 查询设置
+// FindByKey 根据 Key 查询设置，从缓存读取
 func (r *settingRepo) FindByKey(key string) (*po.Setting, error) {
-	var setting po.Setting
-	err := r.db.Where("key = ?", key).First(&setting).Error
+	byKey, _, err := cachedSettings(r.load)
 	if err != nil {
 		return nil, err
 	}
-	return &setting, nil
+	setting, ok := byKey[key]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *setting
+	return &copied, nil
 }
 
-// List 查询所有设置
+// List 查询所有设置，从缓存读取，按 Key 排序
 func (r *settingRepo) List() ([]*po.Setting, error) {
-	var settings []*po.Setting
-	err := r.db.Find(&settings).Error
+	_, ordered, err := cachedSettings(r.load)
 	if err != nil {
 		return nil, err
 	}
+	settings := make([]*po.Setting, len(ordered))
+	for i, setting := range ordered {
+		copied := *setting
+		settings[i] = &copied
+	}
+	return settings, nil
+}
+
+// load 从数据库读取全部设置
+func (r *settingRepo) load() ([]*po.Setting, error) {
+	var settings []*po.Setting
+	if err := r.db.Order("`key`").Find(&settings).Error; err != nil {
+		return nil, err
+	}
 	return settings, nil
 }
 
 // BatchUpdate 批量更新设置
 func (r *settingRepo) BatchUpdate(settings []*po.Setting) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
 		for _, setting := range settings {
 			// 如果ID为0，说明是新记录，需要先检查是否存在
 			if setting.ID == 0 {
 				// 尝试查找已存在的记录
 				var existing po.Setting
-				if err := tx.Where("key = ?", setting.Key).First(&existing).Error; err == nil {
+				if err := tx.Where("`key` = ?", setting.Key).First(&existing).Error; err == nil {
 					// 已存在，更新ID后再保存
 					setting.ID = existing.ID
 				}
@@ -425,4 +576,9 @@ func (r *settingRepo) BatchUpdate(settings []*po.Setting) error {
 		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+	InvalidateSettings()
+	return nil
 }
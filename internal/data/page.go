@@ -0,0 +1,115 @@
+package data
+
+import (
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// PageRepo 独立页面仓储接口
+type PageRepo interface {
+	// Create 创建页面
+	Create(page *po.Page) error
+	// Update 更新页面
+	Update(page *po.Page) error
+	// Delete 删除页面
+	Delete(id uint) error
+	// FindByID 根据 ID 查询页面
+	FindByID(id uint) (*po.Page, error)
+	// FindBySlug 根据 slug 查询页面
+	FindBySlug(slug string) (*po.Page, error)
+	// SlugExists 检查 slug 是否已被其他页面使用
+	SlugExists(slug string, excludeID uint) (bool, error)
+	// List 分页查询页面，不加载正文，status 为空时查询全部状态，keyword 匹配标题和 slug
+	List(page, limit int, status, keyword string) ([]*po.Page, int64, error)
+	// ListPublished 查询全部已发布页面，不加载正文，按排序值排列
+	ListPublished() ([]*po.Page, error)
+}
+
+// pageRepo 独立页面仓储实现
+type pageRepo struct {
+	db *gorm.DB
+}
+
+// NewPageRepo 创建独立页面仓储
+func NewPageRepo(db *gorm.DB) PageRepo {
+	return &pageRepo{db: db}
+}
+
+// pageListColumns 列表查询的字段，不包含正文
+var pageListColumns = []string{"id", "title", "slug", "meta_description", "status", "sort", "author_id", "published_at", "created_at", "updated_at"}
+
+// Create 创建页面
+func (r *pageRepo) Create(page *po.Page) error {
+	return r.db.Create(page).Error
+}
+
+// Update 更新页面
+func (r *pageRepo) Update(page *po.Page) error {
+	return r.db.Save(page).Error
+}
+
+// Delete 删除页面
+func (r *pageRepo) Delete(id uint) error {
+	return r.db.Delete(&po.Page{}, id).Error
+}
+
+// FindByID 根据 ID 查询页面
+func (r *pageRepo) FindByID(id uint) (*po.Page, error) {
+	var page po.Page
+	if err := r.db.First(&page, id).Error; err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// FindBySlug 根据 slug 查询页面
+func (r *pageRepo) FindBySlug(slug string) (*po.Page, error) {
+	var page po.Page
+	if err := r.db.Where("slug = ?", slug).First(&page).Error; err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// SlugExists 检查 slug 是否已被其他页面使用
+func (r *pageRepo) SlugExists(slug string, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Model(&po.Page{}).Where("slug = ?", slug)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// List 分页查询页面
+func (r *pageRepo) List(page, limit int, status, keyword string) ([]*po.Page, int64, error) {
+	var pages []*po.Page
+	var total int64
+
+	query := r.db.Model(&po.Page{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if keyword != "" {
+		query = query.Where("title LIKE ? OR slug LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Select(pageListColumns).Order("sort ASC, id ASC").Offset(offset).Limit(limit).Find(&pages).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return pages, total, nil
+}
+
+// ListPublished 查询全部已发布页面
+func (r *pageRepo) ListPublished() ([]*po.Page, error) {
+	var pages []*po.Page
+	err := r.db.Select(pageListColumns).Where("status = ?", po.PagePublished).Order("sort ASC, id ASC").Find(&pages).Error
+	return pages, err
+}
@@ -0,0 +1,65 @@
+package data
+
+import (
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// RoutePermissionRepo 路由权限仓储接口
+type RoutePermissionRepo interface {
+	// Create 创建规则
+	Create(rule *po.RoutePermission) error
+	// Update 更新规则
+	Update(rule *po.RoutePermission) error
+	// Delete 删除规则
+	Delete(id uint) error
+	// FindByID 根据 ID 查询规则
+	FindByID(id uint) (*po.RoutePermission, error)
+	// List 查询所有规则
+	List() ([]*po.RoutePermission, error)
+}
+
+// routePermissionRepo 路由权限仓储实现
+type routePermissionRepo struct {
+	db *gorm.DB
+}
+
+// NewRoutePermissionRepo 创建路由权限仓储
+func NewRoutePermissionRepo(db *gorm.DB) RoutePermissionRepo {
+	return &routePermissionRepo{db: db}
+}
+
+// Create 创建规则
+func (r *routePermissionRepo) Create(rule *po.RoutePermission) error {
+	return r.db.Create(rule).Error
+}
+
+// Update 更新规则
+func (r *routePermissionRepo) Update(rule *po.RoutePermission) error {
+	return r.db.Save(rule).Error
+}
+
+// Delete 删除规则
+func (r *routePermissionRepo) Delete(id uint) error {
+	return r.db.Delete(&po.RoutePermission{}, id).Error
+}
+
+// FindByID 根据 ID 查询规则
+func (r *routePermissionRepo) FindByID(id uint) (*po.RoutePermission, error) {
+	var rule po.RoutePermission
+	err := r.db.First(&rule, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// List 查询所有规则
+func (r *routePermissionRepo) List() ([]*po.RoutePermission, error) {
+	var rules []*po.RoutePermission
+	err := r.db.Order("path ASC, method ASC").Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
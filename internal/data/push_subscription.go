@@ -0,0 +1,101 @@
+package data
+
+import (
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PushSubscriptionRepo 浏览器推送订阅仓储接口
+type PushSubscriptionRepo interface {
+	// Save 保存订阅，按 endpoint 覆盖写入并清零失败次数
+	Save(sub *po.PushSubscription) error
+	// DeleteByEndpoint 删除订阅，返回删除的行数
+	DeleteByEndpoint(endpoint string) (int64, error)
+	// Delete 删除订阅
+	Delete(id uint) error
+	// ListAfter 按 ID 顺序分批查询订阅，afterID 为上一批的最大 ID
+	ListAfter(afterID uint, limit int) ([]*po.PushSubscription, error)
+	// Count 统计订阅数
+	Count() (int64, error)
+	// MarkSuccess 记录推送成功，清零失败次数
+	MarkSuccess(id uint) error
+	// MarkFailure 失败次数加一，返回累计失败次数
+	MarkFailure(id uint) (int, error)
+	// PurgeExpired 删除失败次数达到上限或 before 之前未更新且未推送成功的订阅
+	PurgeExpired(maxFailures int, before time.Time) (int64, error)
+}
+
+// pushSubscriptionRepo 浏览器推送订阅仓储实现
+type pushSubscriptionRepo struct {
+	db *gorm.DB
+}
+
+// NewPushSubscriptionRepo 创建浏览器推送订阅仓储
+func NewPushSubscriptionRepo(db *gorm.DB) PushSubscriptionRepo {
+	return &pushSubscriptionRepo{db: db}
+}
+
+// Save 保存订阅
+func (r *pushSubscriptionRepo) Save(sub *po.PushSubscription) error {
+	sub.FailCount = 0
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "endpoint"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "p256dh", "auth", "user_agent", "fail_count", "updated_at"}),
+	}).Create(sub).Error
+}
+
+// DeleteByEndpoint 删除订阅
+func (r *pushSubscriptionRepo) DeleteByEndpoint(endpoint string) (int64, error) {
+	result := r.db.Where("endpoint = ?", endpoint).Delete(&po.PushSubscription{})
+	return result.RowsAffected, result.Error
+}
+
+// Delete 删除订阅
+func (r *pushSubscriptionRepo) Delete(id uint) error {
+	return r.db.Delete(&po.PushSubscription{}, id).Error
+}
+
+// ListAfter 分批查询订阅
+func (r *pushSubscriptionRepo) ListAfter(afterID uint, limit int) ([]*po.PushSubscription, error) {
+	var subs []*po.PushSubscription
+	err := r.db.Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&subs).Error
+	return subs, err
+}
+
+// Count 统计订阅数
+func (r *pushSubscriptionRepo) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&po.PushSubscription{}).Count(&count).Error
+	return count, err
+}
+
+// MarkSuccess 记录推送成功，不修改 updated_at，以便区分重新订阅时间
+func (r *pushSubscriptionRepo) MarkSuccess(id uint) error {
+	return r.db.Model(&po.PushSubscription{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"fail_count": 0, "last_success_at": time.Now()}).Error
+}
+
+// MarkFailure 失败次数加一
+func (r *pushSubscriptionRepo) MarkFailure(id uint) (int, error) {
+	err := r.db.Model(&po.PushSubscription{}).Where("id = ?", id).
+		UpdateColumn("fail_count", gorm.Expr("fail_count + 1")).Error
+	if err != nil {
+		return 0, err
+	}
+	var sub po.PushSubscription
+	if err := r.db.Select("fail_count").First(&sub, id).Error; err != nil {
+		return 0, err
+	}
+	return sub.FailCount, nil
+}
+
+// PurgeExpired 删除失效的订阅
+func (r *pushSubscriptionRepo) PurgeExpired(maxFailures int, before time.Time) (int64, error) {
+	result := r.db.Where("fail_count >= ?", maxFailures).
+		Or("updated_at < ? AND (last_success_at IS NULL OR last_success_at < ?)", before, before).
+		Delete(&po.PushSubscription{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,113 @@
+package data
+
+import (
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// RoleRepo 角色仓储接口
+type RoleRepo interface {
+	// List 查询所有角色
+	List() ([]*po.Role, error)
+	// FindByName 根据名称查询角色
+	FindByName(name string) (*po.Role, error)
+	// Create 创建角色及其权限
+	Create(role *po.Role, permissions []string) error
+	// Update 更新角色信息，permissions 不为 nil 时替换角色的全部权限
+	Update(role *po.Role, permissions []string) error
+	// Delete 删除角色及其权限
+	Delete(role *po.Role) error
+	// ListPermissions 查询所有角色的权限
+	ListPermissions() ([]*po.RolePermission, error)
+	// CountUsers 统计使用该角色的用户数
+	CountUsers(name string) (int64, error)
+}
+
+// roleRepo 角色仓储实现
+type roleRepo struct {
+	db *gorm.DB
+}
+
+// NewRoleRepo 创建角色仓储
+func NewRoleRepo(db *gorm.DB) RoleRepo {
+	return &roleRepo{db: db}
+}
+
+// List 查询所有角色
+func (r *roleRepo) List() ([]*po.Role, error) {
+	var roles []*po.Role
+	err := r.db.Order("id ASC").Find(&roles).Error
+	return roles, err
+}
+
+// FindByName 根据名称查询角色
+func (r *roleRepo) FindByName(name string) (*po.Role, error) {
+	var role po.Role
+	err := r.db.Where("name = ?", name).First(&role).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// Create 创建角色及其权限
+func (r *roleRepo) Create(role *po.Role, permissions []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(role).Error; err != nil {
+			return err
+		}
+		return insertRolePermissions(tx, role.Name, permissions)
+	})
+}
+
+// Update 更新角色信息，permissions 不为 nil 时替换角色的全部权限
+func (r *roleRepo) Update(role *po.Role, permissions []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(role).Error; err != nil {
+			return err
+		}
+		if permissions == nil {
+			return nil
+		}
+		if err := tx.Where("role = ?", role.Name).Delete(&po.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return insertRolePermissions(tx, role.Name, permissions)
+	})
+}
+
+// Delete 删除角色及其权限
+func (r *roleRepo) Delete(role *po.Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role = ?", role.Name).Delete(&po.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(role).Error
+	})
+}
+
+// ListPermissions 查询所有角色的权限
+func (r *roleRepo) ListPermissions() ([]*po.RolePermission, error) {
+	var permissions []*po.RolePermission
+	err := r.db.Order("role ASC, permission ASC").Find(&permissions).Error
+	return permissions, err
+}
+
+// CountUsers 统计使用该角色的用户数
+func (r *roleRepo) CountUsers(name string) (int64, error) {
+	var count int64
+	err := r.db.Model(&po.User{}).Where("role = ?", name).Count(&count).Error
+	return count, err
+}
+
+// insertRolePermissions 写入角色的权限
+func insertRolePermissions(tx *gorm.DB, role string, permissions []string) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+	rows := make([]*po.RolePermission, 0, len(permissions))
+	for _, permission := range permissions {
+		rows = append(rows, &po.RolePermission{Role: role, Permission: permission})
+	}
+	return tx.Create(&rows).Error
+}
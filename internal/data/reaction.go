@@ -0,0 +1,90 @@
+package data
+
+import (
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// ReactionCount 对象某个表情的回应数
+type ReactionCount struct {
+	TargetID uint
+	Emoji    string
+	Count    int64
+}
+
+// ReactionRepo 表情回应仓储接口
+type ReactionRepo interface {
+	// Toggle 切换回应：已回应时取消，否则添加，返回切换后是否为已回应
+	Toggle(reaction *po.Reaction) (bool, error)
+	// Counts 统计对象的各表情回应数
+	Counts(targetType string, targetIDs []uint) ([]ReactionCount, error)
+	// Reacted 查询回应者对对象回应过的表情，按对象ID分组
+	Reacted(targetType string, targetIDs []uint, reactor string) (map[uint][]string, error)
+}
+
+// reactionRepo 表情回应仓储实现
+type reactionRepo struct {
+	db *gorm.DB
+}
+
+// NewReactionRepo 创建表情回应仓储
+func NewReactionRepo(db *gorm.DB) ReactionRepo {
+	return &reactionRepo{db: db}
+}
+
+// Toggle 切换回应，并发添加同一回应时由唯一索引去重
+func (r *reactionRepo) Toggle(reaction *po.Reaction) (bool, error) {
+	result := r.db.Where("target_type = ? AND target_id = ? AND emoji = ? AND reactor = ?",
+		reaction.TargetType, reaction.TargetID, reaction.Emoji, reaction.Reactor).
+		Delete(&po.Reaction{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return false, nil
+	}
+
+	if err := r.db.Create(reaction).Error; err != nil {
+		var count int64
+		if countErr := r.db.Model(&po.Reaction{}).
+			Where("target_type = ? AND target_id = ? AND emoji = ? AND reactor = ?",
+				reaction.TargetType, reaction.TargetID, reaction.Emoji, reaction.Reactor).
+			Count(&count).Error; countErr == nil && count > 0 {
+			return true, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Counts 统计对象的各表情回应数
+func (r *reactionRepo) Counts(targetType string, targetIDs []uint) ([]ReactionCount, error) {
+	var counts []ReactionCount
+	if len(targetIDs) == 0 {
+		return counts, nil
+	}
+	err := r.db.Model(&po.Reaction{}).
+		Select("target_id, emoji, COUNT(*) AS count").
+		Where("target_type = ? AND target_id IN ?", targetType, targetIDs).
+		Group("target_id, emoji").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// Reacted 查询回应者对对象回应过的表情
+func (r *reactionRepo) Reacted(targetType string, targetIDs []uint, reactor string) (map[uint][]string, error) {
+	reacted := make(map[uint][]string)
+	if len(targetIDs) == 0 || reactor == "" {
+		return reacted, nil
+	}
+	var reactions []po.Reaction
+	if err := r.db.Select("target_id, emoji").
+		Where("target_type = ? AND target_id IN ? AND reactor = ?", targetType, targetIDs, reactor).
+		Find(&reactions).Error; err != nil {
+		return nil, err
+	}
+	for _, reaction := range reactions {
+		reacted[reaction.TargetID] = append(reacted[reaction.TargetID], reaction.Emoji)
+	}
+	return reacted, nil
+}
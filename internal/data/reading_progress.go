@@ -0,0 +1,123 @@
+package data
+
+import (
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReadingProgressRepo 阅读进度仓储接口
+type ReadingProgressRepo interface {
+	// Save 保存阅读进度，按 (user_id, article_id) 覆盖写入
+	Save(progress *po.ReadingProgress) error
+	// Find 查询用户某篇文章的阅读进度
+	Find(userID, articleID uint) (*po.ReadingProgress, error)
+	// ListByTag 查询用户在某本书下的全部阅读进度
+	ListByTag(userID, tagID uint) ([]*po.ReadingProgress, error)
+	// ListTagIDs 查询用户读过的书，按最近阅读时间倒序
+	ListTagIDs(userID uint) ([]uint, error)
+	// DeleteByTag 清除用户在某本书下的阅读进度
+	DeleteByTag(userID, tagID uint) error
+	// FindChapter 查询章节
+	FindChapter(id uint) (*po.Chapter, error)
+	// BookChapters 查询书的全部章节，按排序
+	BookChapters(tagID uint) ([]*po.Chapter, error)
+	// BookArticles 查询书中已发布且非私密的文章（只包含目录需要的字段），按创建时间排序
+	BookArticles(tagID uint) ([]*po.Article, error)
+}
+
+// readingProgressRepo 阅读进度仓储实现
+type readingProgressRepo struct {
+	db *gorm.DB
+}
+
+// NewReadingProgressRepo 创建阅读进度仓储
+func NewReadingProgressRepo(db *gorm.DB) ReadingProgressRepo {
+	return &readingProgressRepo{db: db}
+}
+
+// Save 保存阅读进度
+func (r *readingProgressRepo) Save(progress *po.ReadingProgress) error {
+	if progress.ReadAt.IsZero() {
+		progress.ReadAt = time.Now()
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "article_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"tag_id", "percent", "completed", "completed_at", "read_at"}),
+	}).Create(progress).Error
+}
+
+// Find 查询用户某篇文章的阅读进度
+func (r *readingProgressRepo) Find(userID, articleID uint) (*po.ReadingProgress, error) {
+	var progress po.ReadingProgress
+	err := r.db.Where("user_id = ? AND article_id = ?", userID, articleID).First(&progress).Error
+	if err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+// ListByTag 查询用户在某本书下的全部阅读进度
+func (r *readingProgressRepo) ListByTag(userID, tagID uint) ([]*po.ReadingProgress, error) {
+	var progress []*po.ReadingProgress
+	err := r.db.Where("user_id = ? AND tag_id = ?", userID, tagID).Order("read_at DESC").Find(&progress).Error
+	return progress, err
+}
+
+// ListTagIDs 查询用户读过的书
+func (r *readingProgressRepo) ListTagIDs(userID uint) ([]uint, error) {
+	var rows []struct {
+		TagID  uint
+		ReadAt time.Time
+	}
+	err := r.db.Model(&po.ReadingProgress{}).
+		Select("tag_id, MAX(read_at) AS read_at").
+		Where("user_id = ?", userID).
+		Group("tag_id").
+		Order("read_at DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.TagID)
+	}
+	return ids, nil
+}
+
+// DeleteByTag 清除用户在某本书下的阅读进度
+func (r *readingProgressRepo) DeleteByTag(userID, tagID uint) error {
+	return r.db.Where("user_id = ? AND tag_id = ?", userID, tagID).Delete(&po.ReadingProgress{}).Error
+}
+
+// FindChapter 查询章节
+func (r *readingProgressRepo) FindChapter(id uint) (*po.Chapter, error) {
+	var chapter po.Chapter
+	if err := r.db.First(&chapter, id).Error; err != nil {
+		return nil, err
+	}
+	return &chapter, nil
+}
+
+// BookChapters 查询书的全部章节
+func (r *readingProgressRepo) BookChapters(tagID uint) ([]*po.Chapter, error) {
+	var chapters []*po.Chapter
+	err := r.db.Where("tag_id = ?", tagID).Order("sort ASC, id ASC").Find(&chapters).Error
+	return chapters, err
+}
+
+// BookArticles 查询书中已发布且非私密的文章
+func (r *readingProgressRepo) BookArticles(tagID uint) ([]*po.Article, error) {
+	var articles []*po.Article
+	err := r.db.Model(&po.Article{}).
+		Select("articles.id, articles.title, articles.chapter_id, articles.created_at").
+		Joins("JOIN chapters ON chapters.id = articles.chapter_id").
+		Where("chapters.tag_id = ? AND articles.status = ? AND articles.visibility <> ?", tagID, 1, po.VisibilityPrivate).
+		Order("articles.created_at ASC, articles.id ASC").
+		Find(&articles).Error
+	return articles, err
+}
@@ -0,0 +1,182 @@
+package data
+
+import (
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// SeriesRepo 文章系列仓储接口
+type SeriesRepo interface {
+	// Create 创建系列
+	Create(series *po.Series) error
+	// Update 更新系列
+	Update(series *po.Series) error
+	// Delete 删除系列及其文章关联
+	Delete(id uint) error
+	// FindByID 根据 ID 查询系列
+	FindByID(id uint) (*po.Series, error)
+	// List 查询系列列表
+	List(page, limit int, keyword string) ([]*po.Series, int64, error)
+	// CountArticles 统计各系列的文章数
+	CountArticles(seriesIDs []uint, publicOnly bool) (map[uint]int64, error)
+	// ListArticles 按系列内顺序查询文章，publicOnly 为 true 时只返回已发布且非私密的文章
+	ListArticles(seriesID uint, publicOnly bool) ([]*po.SeriesArticle, error)
+	// SetArticles 按给定顺序替换系列中的文章
+	SetArticles(seriesID uint, articleIDs []uint) error
+	// FindByArticle 查询文章所属的系列
+	FindByArticle(articleID uint) ([]*po.Series, error)
+}
+
+// seriesRepo 文章系列仓储实现
+type seriesRepo struct {
+	db *gorm.DB
+}
+
+// NewSeriesRepo 创建文章系列仓储
+func NewSeriesRepo(db *gorm.DB) SeriesRepo {
+	return &seriesRepo{db: db}
+}
+
+// Create 创建系列
+func (r *seriesRepo) Create(series *po.Series) error {
+	return r.db.Create(series).Error
+}
+
+// Update 更新系列
+func (r *seriesRepo) Update(series *po.Series) error {
+	return r.db.Model(series).Updates(map[string]interface{}{
+		"title":       series.Title,
+		"description": series.Description,
+		"cover":       series.Cover,
+		"sort":        series.Sort,
+	}).Error
+}
+
+// Delete 删除系列及其文章关联
+func (r *seriesRepo) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", id).Delete(&po.SeriesArticle{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&po.Series{}, id).Error
+	})
+}
+
+// FindByID 根据 ID 查询系列
+func (r *seriesRepo) FindByID(id uint) (*po.Series, error) {
+	var series po.Series
+	err := r.db.First(&series, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+// List 查询系列列表
+func (r *seriesRepo) List(page, limit int, keyword string) ([]*po.Series, int64, error) {
+	var list []*po.Series
+	var total int64
+
+	query := r.db.Model(&po.Series{})
+	if keyword != "" {
+		query = query.Where("title LIKE ?", "%"+keyword+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Offset(offset).Limit(limit).Order("sort ASC, id DESC").Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return list, total, nil
+}
+
+// CountArticles 统计各系列的文章数
+func (r *seriesRepo) CountArticles(seriesIDs []uint, publicOnly bool) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(seriesIDs))
+	if len(seriesIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		SeriesID uint
+		Count    int64
+	}
+	query := r.db.Model(&po.SeriesArticle{}).
+		Select("series_articles.series_id, COUNT(*) AS count").
+		Joins("JOIN articles ON articles.id = series_articles.article_id AND articles.deleted_at IS NULL").
+		Where("series_articles.series_id IN ?", seriesIDs)
+	if publicOnly {
+		query = publicSeriesArticles(query)
+	}
+	if err := query.Group("series_articles.series_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.SeriesID] = row.Count
+	}
+	return counts, nil
+}
+
+// ListArticles 按系列内顺序查询文章
+func (r *seriesRepo) ListArticles(seriesID uint, publicOnly bool) ([]*po.SeriesArticle, error) {
+	var items []*po.SeriesArticle
+	query := r.db.Model(&po.SeriesArticle{}).
+		Joins("JOIN articles ON articles.id = series_articles.article_id AND articles.deleted_at IS NULL").
+		Where("series_articles.series_id = ?", seriesID)
+	if publicOnly {
+		query = publicSeriesArticles(query)
+	}
+	err := query.Preload("Article").
+		Order("series_articles.sort ASC, series_articles.id ASC").
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// SetArticles 按给定顺序替换系列中的文章
+func (r *seriesRepo) SetArticles(seriesID uint, articleIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", seriesID).Delete(&po.SeriesArticle{}).Error; err != nil {
+			return err
+		}
+		if len(articleIDs) == 0 {
+			return nil
+		}
+
+		items := make([]po.SeriesArticle, 0, len(articleIDs))
+		for i, articleID := range articleIDs {
+			items = append(items, po.SeriesArticle{
+				SeriesID:  seriesID,
+				ArticleID: articleID,
+				Sort:      i,
+			})
+		}
+		return tx.Create(&items).Error
+	})
+}
+
+// FindByArticle 查询文章所属的系列
+func (r *seriesRepo) FindByArticle(articleID uint) ([]*po.Series, error) {
+	var list []*po.Series
+	err := r.db.Model(&po.Series{}).
+		Joins("JOIN series_articles ON series_articles.series_id = series.id").
+		Where("series_articles.article_id = ?", articleID).
+		Order("series.sort ASC, series.id ASC").
+		Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// publicSeriesArticles 只保留博客前台可见的文章
+func publicSeriesArticles(query *gorm.DB) *gorm.DB {
+	return query.Where("articles.status = ? AND articles.visibility <> ?", 1, po.VisibilityPrivate)
+}
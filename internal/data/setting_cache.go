@@ -0,0 +1,90 @@
+package data
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+// settingsChannel 设置变更通知的 Redis 频道，多实例部署时用于让其他实例清空缓存
+const settingsChannel = "settings:changed"
+
+// settingCache 系统设置的进程内缓存，首次读取时整表加载，写入后清空
+// 所有 settingRepo 实例共享，缓存中的对象只读，返回给调用方前复制
+var settingCache struct {
+	mu      sync.RWMutex
+	loaded  bool
+	byKey   map[string]*po.Setting
+	ordered []*po.Setting
+}
+
+// cachedSettings 返回缓存的设置，未加载时调用 load 从数据库读取
+func cachedSettings(load func() ([]*po.Setting, error)) (map[string]*po.Setting, []*po.Setting, error) {
+	settingCache.mu.RLock()
+	if settingCache.loaded {
+		defer settingCache.mu.RUnlock()
+		return settingCache.byKey, settingCache.ordered, nil
+	}
+	settingCache.mu.RUnlock()
+
+	settingCache.mu.Lock()
+	defer settingCache.mu.Unlock()
+	if settingCache.loaded {
+		return settingCache.byKey, settingCache.ordered, nil
+	}
+	settings, err := load()
+	if err != nil {
+		return nil, nil, err
+	}
+	byKey := make(map[string]*po.Setting, len(settings))
+	for _, setting := range settings {
+		byKey[setting.Key] = setting
+	}
+	settingCache.byKey, settingCache.ordered, settingCache.loaded = byKey, settings, true
+	return byKey, settings, nil
+}
+
+// clearSettingCache 清空本实例的设置缓存，下次读取时重新加载
+func clearSettingCache() {
+	settingCache.mu.Lock()
+	settingCache.loaded = false
+	settingCache.byKey, settingCache.ordered = nil, nil
+	settingCache.mu.Unlock()
+}
+
+// InvalidateSettings 清空设置缓存并通知其他实例，绕过 SettingRepo 直接修改设置表后需要调用
+func InvalidateSettings() {
+	clearSettingCache()
+	if redis.Client == nil {
+		return
+	}
+	if err := redis.Client.Publish(redis.GetContext(), settingsChannel, "1").Err(); err != nil {
+		logger.Warn("Failed to publish settings change: ", err)
+	}
+}
+
+// WatchSettings 接收其他实例发布的设置变更并清空本实例的缓存，ctx 取消后退出
+func WatchSettings(ctx context.Context) {
+	if redis.Client == nil {
+		return
+	}
+
+	pubsub := redis.Client.Subscribe(ctx, settingsChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-messages:
+			if !ok {
+				return
+			}
+			clearSettingCache()
+		}
+	}
+}
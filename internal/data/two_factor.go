@@ -0,0 +1,72 @@
+package data
+
+import (
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// TwoFactorRepo 两步验证仓储接口
+type TwoFactorRepo interface {
+	// FindByUserID 查询用户的两步验证设置
+	FindByUserID(userID uint) (*po.UserTwoFactor, error)
+	// Save 创建或更新两步验证设置
+	Save(tf *po.UserTwoFactor) error
+	// DeleteByUserID 删除用户的两步验证设置
+	DeleteByUserID(userID uint) error
+	// UseStep 记录验证通过的时间步，时间步不大于上次记录时返回 false（验证码已使用过）
+	UseStep(id uint, step int64) (bool, error)
+	// ReplaceRecoveryCodes 恢复码未被并发修改时替换为新值，用于消耗恢复码
+	ReplaceRecoveryCodes(id uint, old, codes string) (bool, error)
+}
+
+// twoFactorRepo 两步验证仓储实现
+type twoFactorRepo struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorRepo 创建两步验证仓储
+func NewTwoFactorRepo(db *gorm.DB) TwoFactorRepo {
+	return &twoFactorRepo{db: db}
+}
+
+// FindByUserID 查询用户的两步验证设置
+func (r *twoFactorRepo) FindByUserID(userID uint) (*po.UserTwoFactor, error) {
+	var tf po.UserTwoFactor
+	err := r.db.Where("user_id = ?", userID).First(&tf).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tf, nil
+}
+
+// Save 创建或更新两步验证设置
+func (r *twoFactorRepo) Save(tf *po.UserTwoFactor) error {
+	return r.db.Save(tf).Error
+}
+
+// DeleteByUserID 删除用户的两步验证设置
+func (r *twoFactorRepo) DeleteByUserID(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&po.UserTwoFactor{}).Error
+}
+
+// UseStep 记录验证通过的时间步，条件更新保证并发请求中同一验证码只有一个成功
+func (r *twoFactorRepo) UseStep(id uint, step int64) (bool, error) {
+	result := r.db.Model(&po.UserTwoFactor{}).
+		Where("id = ? AND last_used_step < ?", id, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReplaceRecoveryCodes 恢复码未被并发修改时替换为新值
+func (r *twoFactorRepo) ReplaceRecoveryCodes(id uint, old, codes string) (bool, error) {
+	result := r.db.Model(&po.UserTwoFactor{}).
+		Where("id = ? AND recovery_codes = ?", id, old).
+		Update("recovery_codes", codes)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,56 @@
+package data
+
+import (
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// UploadSessionRepo 分片上传会话仓储接口
+type UploadSessionRepo interface {
+	// Create 创建会话
+	Create(session *po.UploadSession) error
+	// FindByID 根据 ID 查询会话
+	FindByID(id string) (*po.UploadSession, error)
+	// Delete 删除会话
+	Delete(id string) error
+	// ListExpired 查询已过期的会话
+	ListExpired(now time.Time, limit int) ([]*po.UploadSession, error)
+}
+
+// uploadSessionRepo 分片上传会话仓储实现
+type uploadSessionRepo struct {
+	db *gorm.DB
+}
+
+// NewUploadSessionRepo 创建分片上传会话仓储
+func NewUploadSessionRepo(db *gorm.DB) UploadSessionRepo {
+	return &uploadSessionRepo{db: db}
+}
+
+// Create 创建会话
+func (r *uploadSessionRepo) Create(session *po.UploadSession) error {
+	return r.db.Create(session).Error
+}
+
+// FindByID 根据 ID 查询会话
+func (r *uploadSessionRepo) FindByID(id string) (*po.UploadSession, error) {
+	var session po.UploadSession
+	if err := r.db.Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Delete 删除会话
+func (r *uploadSessionRepo) Delete(id string) error {
+	return r.db.Where("id = ?", id).Delete(&po.UploadSession{}).Error
+}
+
+// ListExpired 查询已过期的会话
+func (r *uploadSessionRepo) ListExpired(now time.Time, limit int) ([]*po.UploadSession, error) {
+	var sessions []*po.UploadSession
+	err := r.db.Where("expires_at < ?", now).Order("expires_at ASC").Limit(limit).Find(&sessions).Error
+	return sessions, err
+}
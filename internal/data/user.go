@@ -2,6 +2,7 @@ package data
 
 import (
 	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/encrypt"
 	"gorm.io/gorm"
 )
 
@@ -71,7 +72,8 @@ func (r *userRepo) FindByUsername(username string) (*po.User, error) {
 // FindByEmail 根据邮箱查询用户
 func (r *userRepo) FindByEmail(email string) (*po.User, error) {
 	var user po.User
-	err := r.db.Where("email = ?", email).First(&user).Error
+	// 邮箱可能已加密，按盲索引查询；email = ? 兼容尚未加密的历史数据
+	err := r.db.Where("email_hash = ? OR email = ?", encrypt.BlindIndex(email), email).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
@@ -88,8 +90,9 @@ func (r *userRepo) List(page, limit int, keyword, status string) ([]*po.User, in
 
 	// 关键词搜索
 	if keyword != "" {
-		query = query.Where("username LIKE ? OR email LIKE ? OR nickname LIKE ?",
-			"%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%")
+		// 邮箱加密后无法模糊匹配，仅支持完整邮箱查询
+		query = query.Where("username LIKE ? OR email LIKE ? OR nickname LIKE ? OR email_hash = ?",
+			"%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%", encrypt.BlindIndex(keyword))
 	}
 
 	// 状态过滤
@@ -0,0 +1,58 @@
+package data
+
+import (
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// UserIdentityRepo 第三方账号绑定仓储接口
+type UserIdentityRepo interface {
+	// Create 绑定第三方账号
+	Create(identity *po.UserIdentity) error
+	// FindBySubject 根据平台和第三方用户ID查询绑定
+	FindBySubject(provider, subject string) (*po.UserIdentity, error)
+	// FindByUnionID 在共享 unionid 的平台中查询绑定
+	FindByUnionID(providers []string, unionID string) (*po.UserIdentity, error)
+	// UpdateUnionID 补全绑定的 unionid
+	UpdateUnionID(id uint, unionID string) error
+}
+
+// userIdentityRepo 第三方账号绑定仓储实现
+type userIdentityRepo struct {
+	db *gorm.DB
+}
+
+// NewUserIdentityRepo 创建第三方账号绑定仓储
+func NewUserIdentityRepo(db *gorm.DB) UserIdentityRepo {
+	return &userIdentityRepo{db: db}
+}
+
+// Create 绑定第三方账号
+func (r *userIdentityRepo) Create(identity *po.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+// FindBySubject 根据平台和第三方用户ID查询绑定
+func (r *userIdentityRepo) FindBySubject(provider, subject string) (*po.UserIdentity, error) {
+	var identity po.UserIdentity
+	err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// FindByUnionID 在共享 unionid 的平台中查询绑定
+func (r *userIdentityRepo) FindByUnionID(providers []string, unionID string) (*po.UserIdentity, error) {
+	var identity po.UserIdentity
+	err := r.db.Where("provider IN ? AND union_id = ?", providers, unionID).Order("id ASC").First(&identity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// UpdateUnionID 补全绑定的 unionid
+func (r *userIdentityRepo) UpdateUnionID(id uint, unionID string) error {
+	return r.db.Model(&po.UserIdentity{}).Where("id = ?", id).Update("union_id", unionID).Error
+}
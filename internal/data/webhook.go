@@ -0,0 +1,171 @@
+package data
+
+import (
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// WebhookRepo Webhook 仓储接口
+type WebhookRepo interface {
+	// Create 创建 Webhook
+	Create(webhook *po.Webhook) error
+	// Update 更新 Webhook
+	Update(webhook *po.Webhook) error
+	// Delete 删除 Webhook 及其投递记录
+	Delete(id uint) error
+	// FindByID 根据 ID 查询 Webhook
+	FindByID(id uint) (*po.Webhook, error)
+	// List 查询 Webhook 列表
+	List(page, limit int) ([]*po.Webhook, int64, error)
+	// ListEnabled 查询全部启用的 Webhook
+	ListEnabled() ([]*po.Webhook, error)
+	// CreateDelivery 创建投递记录
+	CreateDelivery(delivery *po.WebhookDelivery) error
+	// SaveDelivery 保存投递结果
+	SaveDelivery(delivery *po.WebhookDelivery) error
+	// FindDelivery 查询投递记录
+	FindDelivery(id uint) (*po.WebhookDelivery, error)
+	// ListDeliveries 查询 Webhook 的投递记录，status 为空时不过滤
+	ListDeliveries(page, limit int, webhookID uint, status string) ([]*po.WebhookDelivery, int64, error)
+	// ListDueDeliveries 查询到达重试时间的投递记录（包含 Webhook）
+	ListDueDeliveries(now time.Time, limit int) ([]*po.WebhookDelivery, error)
+	// ClaimDelivery 将待重试的投递记录推迟到 until，返回是否抢占成功，避免多个实例重复投递
+	ClaimDelivery(delivery *po.WebhookDelivery, until time.Time) (bool, error)
+}
+
+// webhookRepo Webhook 仓储实现
+type webhookRepo struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepo 创建 Webhook 仓储
+func NewWebhookRepo(db *gorm.DB) WebhookRepo {
+	return &webhookRepo{db: db}
+}
+
+// Create 创建 Webhook
+func (r *webhookRepo) Create(webhook *po.Webhook) error {
+	return r.db.Create(webhook).Error
+}
+
+// Update 更新 Webhook
+func (r *webhookRepo) Update(webhook *po.Webhook) error {
+	// 逐字段赋值后使用 Save，确保触发签名密钥的加密钩子
+	return r.db.Save(webhook).Error
+}
+
+// Delete 删除 Webhook 及其投递记录
+func (r *webhookRepo) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&po.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&po.Webhook{}, id).Error
+	})
+}
+
+// FindByID 根据 ID 查询 Webhook
+func (r *webhookRepo) FindByID(id uint) (*po.Webhook, error) {
+	var webhook po.Webhook
+	if err := r.db.First(&webhook, id).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// List 查询 Webhook 列表
+func (r *webhookRepo) List(page, limit int) ([]*po.Webhook, int64, error) {
+	var list []*po.Webhook
+	var total int64
+
+	query := r.db.Model(&po.Webhook{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&list).Error
+	return list, total, err
+}
+
+// ListEnabled 查询全部启用的 Webhook
+func (r *webhookRepo) ListEnabled() ([]*po.Webhook, error) {
+	var list []*po.Webhook
+	err := r.db.Where("enabled = ?", true).Order("id ASC").Find(&list).Error
+	return list, err
+}
+
+// CreateDelivery 创建投递记录
+func (r *webhookRepo) CreateDelivery(delivery *po.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+// SaveDelivery 保存投递结果
+func (r *webhookRepo) SaveDelivery(delivery *po.WebhookDelivery) error {
+	return r.db.Model(delivery).Updates(map[string]interface{}{
+		"status":          delivery.Status,
+		"attempts":        delivery.Attempts,
+		"response_status": delivery.ResponseStatus,
+		"response_body":   delivery.ResponseBody,
+		"error":           delivery.Error,
+		"duration":        delivery.Duration,
+		"next_retry_at":   delivery.NextRetryAt,
+		"delivered_at":    delivery.DeliveredAt,
+	}).Error
+}
+
+// FindDelivery 查询投递记录
+func (r *webhookRepo) FindDelivery(id uint) (*po.WebhookDelivery, error) {
+	var delivery po.WebhookDelivery
+	if err := r.db.Preload("Webhook").First(&delivery, id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// ListDeliveries 按时间倒序查询投递记录
+func (r *webhookRepo) ListDeliveries(page, limit int, webhookID uint, status string) ([]*po.WebhookDelivery, int64, error) {
+	var list []*po.WebhookDelivery
+	var total int64
+
+	query := r.db.Model(&po.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&list).Error
+	return list, total, err
+}
+
+// ListDueDeliveries 查询到达重试时间的投递记录
+func (r *webhookRepo) ListDueDeliveries(now time.Time, limit int) ([]*po.WebhookDelivery, error) {
+	var list []*po.WebhookDelivery
+	err := r.db.Preload("Webhook").
+		Where("status = ? AND next_retry_at <= ?", po.WebhookDeliveryPending, now).
+		Order("next_retry_at ASC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+// ClaimDelivery 以 next_retry_at 作为乐观锁抢占投递记录
+func (r *webhookRepo) ClaimDelivery(delivery *po.WebhookDelivery, until time.Time) (bool, error) {
+	result := r.db.Model(&po.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_retry_at = ?", delivery.ID, po.WebhookDeliveryPending, delivery.NextRetryAt).
+		Update("next_retry_at", until)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	delivery.NextRetryAt = &until
+	return true, nil
+}
@@ -0,0 +1,137 @@
+package data
+
+import (
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"gorm.io/gorm"
+)
+
+// YuqueRepo 语雀同步仓储接口
+type YuqueRepo interface {
+	// Create 创建同步配置
+	Create(sync *po.YuqueSync) error
+	// Update 更新同步配置
+	Update(sync *po.YuqueSync) error
+	// Delete 删除同步配置及其节点映射（已同步的文章和章节保留）
+	Delete(id uint) error
+	// FindByID 根据 ID 查询同步配置
+	FindByID(id uint) (*po.YuqueSync, error)
+	// FindByNamespace 根据知识库路径查询同步配置
+	FindByNamespace(namespace string) (*po.YuqueSync, error)
+	// List 查询同步配置列表
+	List(page, limit int) ([]*po.YuqueSync, int64, error)
+	// ListEnabled 查询全部启用的同步配置
+	ListEnabled() ([]*po.YuqueSync, error)
+	// Claim 获取同步租约，租约未过期时返回 false，避免多个实例同时同步同一知识库
+	Claim(id uint, now, until time.Time) (bool, error)
+	// Release 释放同步租约并记录同步结果
+	Release(sync *po.YuqueSync) error
+	// ListItems 查询同步配置的全部节点映射
+	ListItems(syncID uint) ([]*po.YuqueItem, error)
+	// SaveItem 保存节点映射
+	SaveItem(item *po.YuqueItem) error
+}
+
+// yuqueRepo 语雀同步仓储实现
+type yuqueRepo struct {
+	db *gorm.DB
+}
+
+// NewYuqueRepo 创建语雀同步仓储
+func NewYuqueRepo(db *gorm.DB) YuqueRepo {
+	return &yuqueRepo{db: db}
+}
+
+// Create 创建同步配置
+func (r *yuqueRepo) Create(sync *po.YuqueSync) error {
+	return r.db.Create(sync).Error
+}
+
+// Update 更新同步配置
+func (r *yuqueRepo) Update(sync *po.YuqueSync) error {
+	// 使用 Save 触发 Token 的加密钩子
+	return r.db.Save(sync).Error
+}
+
+// Delete 删除同步配置及其节点映射
+func (r *yuqueRepo) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("sync_id = ?", id).Delete(&po.YuqueItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&po.YuqueSync{}, id).Error
+	})
+}
+
+// FindByID 根据 ID 查询同步配置
+func (r *yuqueRepo) FindByID(id uint) (*po.YuqueSync, error) {
+	var sync po.YuqueSync
+	if err := r.db.First(&sync, id).Error; err != nil {
+		return nil, err
+	}
+	return &sync, nil
+}
+
+// FindByNamespace 根据知识库路径查询同步配置
+func (r *yuqueRepo) FindByNamespace(namespace string) (*po.YuqueSync, error) {
+	var sync po.YuqueSync
+	if err := r.db.Where("namespace = ?", namespace).First(&sync).Error; err != nil {
+		return nil, err
+	}
+	return &sync, nil
+}
+
+// List 查询同步配置列表
+func (r *yuqueRepo) List(page, limit int) ([]*po.YuqueSync, int64, error) {
+	var list []*po.YuqueSync
+	var total int64
+
+	query := r.db.Model(&po.YuqueSync{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&list).Error
+	return list, total, err
+}
+
+// ListEnabled 查询全部启用的同步配置
+func (r *yuqueRepo) ListEnabled() ([]*po.YuqueSync, error) {
+	var list []*po.YuqueSync
+	err := r.db.Where("enabled = ?", true).Order("id ASC").Find(&list).Error
+	return list, err
+}
+
+// Claim 获取同步租约
+func (r *yuqueRepo) Claim(id uint, now, until time.Time) (bool, error) {
+	result := r.db.Model(&po.YuqueSync{}).
+		Where("id = ? AND (locked_until IS NULL OR locked_until < ?)", id, now).
+		UpdateColumn("locked_until", until)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Release 释放同步租约并记录同步结果（不触发加密钩子，不修改 Token）
+func (r *yuqueRepo) Release(sync *po.YuqueSync) error {
+	return r.db.Model(&po.YuqueSync{}).Where("id = ?", sync.ID).UpdateColumns(map[string]interface{}{
+		"locked_until":   nil,
+		"last_synced_at": sync.LastSyncedAt,
+		"last_error":     sync.LastError,
+	}).Error
+}
+
+// ListItems 查询同步配置的全部节点映射
+func (r *yuqueRepo) ListItems(syncID uint) ([]*po.YuqueItem, error) {
+	var items []*po.YuqueItem
+	err := r.db.Where("sync_id = ?", syncID).Find(&items).Error
+	return items, err
+}
+
+// SaveItem 保存节点映射
+func (r *yuqueRepo) SaveItem(item *po.YuqueItem) error {
+	return r.db.Save(item).Error
+}
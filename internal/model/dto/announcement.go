@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+// AnnouncementRequest 创建或更新公告请求
+type AnnouncementRequest struct {
+	Title       string     `json:"title" binding:"required,max=100"`
+	Content     string     `json:"content" binding:"max=1000"`
+	Severity    string     `json:"severity" binding:"omitempty,oneof=info success warning critical"` // 为空时使用 info
+	Link        string     `json:"link" binding:"max=500"`                                           // / 开头的站内路径或 http(s) 地址
+	Dismissible *bool      `json:"dismissible"`                                                      // 为空时不修改，创建时默认可关闭
+	Enabled     *bool      `json:"enabled"`                                                          // 为空时不修改，创建时默认启用
+	StartAt     *time.Time `json:"start_at"`
+	EndAt       *time.Time `json:"end_at"`
+	Notify      bool       `json:"notify"` // 开始展示时推送到通知中心，已推送过的公告不会重复推送
+}
+
+// AnnouncementListRequest 后台公告列表请求
+type AnnouncementListRequest struct {
+	PageRequest
+	Status  string `form:"status" binding:"omitempty,oneof=active scheduled expired disabled"`
+	Keyword string `form:"keyword"` // 匹配标题和内容
+}
+
+// AnnouncementResponse 公告
+type AnnouncementResponse struct {
+	ID          uint       `json:"id"`
+	Title       string     `json:"title"`
+	Content     string     `json:"content"`
+	Severity    string     `json:"severity"`
+	Link        string     `json:"link"`
+	Dismissible bool       `json:"dismissible"`
+	StartAt     *time.Time `json:"start_at"`
+	EndAt       *time.Time `json:"end_at"`
+	UpdatedAt   time.Time  `json:"updated_at"` // 公告修改后前台可据此重新显示已关闭的公告
+
+	// 以下字段只在后台返回
+	Enabled    *bool      `json:"enabled,omitempty"`
+	Status     string     `json:"status,omitempty"` // 展示状态：active、scheduled、expired、disabled
+	Notify     *bool      `json:"notify,omitempty"`
+	NotifiedAt *time.Time `json:"notified_at,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+}
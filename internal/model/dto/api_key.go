@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+// CreateAPIKeyRequest 创建 API Key 请求
+type CreateAPIKeyRequest struct {
+	Name      string   `json:"name" binding:"required,max=100"`
+	Scopes    []string `json:"scopes" binding:"required,min=1,max=50,dive,required,max=50"` // 如 articles:write、files:read，* 表示全部
+	ExpiresIn int      `json:"expires_in" binding:"min=0,max=3650"`                         // 有效天数，0 表示永不过期
+}
+
+// APIKeyInfo API Key 信息，不包含令牌内容
+type APIKeyInfo struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAPIKeyResponse 创建 API Key 响应
+type CreateAPIKeyResponse struct {
+	APIKeyInfo
+	Key string `json:"key"` // 令牌内容，只在创建时返回一次
+}
+
+// APIKeyIdentity API Key 认证通过后的调用方
+type APIKeyIdentity struct {
+	KeyID    uint
+	UserID   uint
+	Username string
+	Role     string
+}
@@ -5,6 +5,7 @@ import "time"
 // CreateArticleRequest 创建文章请求
 type CreateArticleRequest struct {
 	Title           string     `json:"title" binding:"required,max=200"`
+	Slug            string     `json:"slug" binding:"max=100"` // URL 别名，可选，不传则根据标题自动生成
 	ContentMarkdown string     `json:"content_markdown" binding:"required"`
 	ContentHTML     string     `json:"content_html"` // 可选，如果不传则自动从 Markdown 转换
 	Summary         string     `json:"summary" binding:"max=500"`
@@ -12,13 +13,17 @@ type CreateArticleRequest struct {
 	CategoryID      uint       `json:"category_id" binding:"required"`
 	ChapterID       *uint      `json:"chapter_id"` // 章节ID，可为空
 	TagIDs          []uint     `json:"tag_ids"`
-	Status          int        `json:"status" binding:"oneof=0 1 2"` // 0: draft, 1: published, 2: offline
-	CreatedAt       *time.Time `json:"created_at"`                   // 创建时间，可选，如果不传则使用当前时间
+	Status          int        `json:"status" binding:"oneof=0 1 2"`                                 // 0: draft, 1: published, 2: offline
+	Visibility      string     `json:"visibility" binding:"omitempty,oneof=public private password"` // 默认 public
+	Password        string     `json:"password" binding:"max=64"`                                    // visibility=password 时的访问密码
+	CreatedAt       *time.Time `json:"created_at"`                                                   // 创建时间，可选，如果不传则使用当前时间
+	SEO             *SEOMeta   `json:"seo"`                                                          // SEO 信息，可选
 }
 
 // UpdateArticleRequest 更新文章请求
 type UpdateArticleRequest struct {
 	Title           string     `json:"title" binding:"omitempty,max=200"`
+	Slug            string     `json:"slug" binding:"max=100"` // URL 别名，可选
 	ContentMarkdown string     `json:"content_markdown"`
 	ContentHTML     string     `json:"content_html"` // 可选
 	Summary         string     `json:"summary" binding:"max=500"`
@@ -27,7 +32,19 @@ type UpdateArticleRequest struct {
 	ChapterID       *uint      `json:"chapter_id"` // 章节ID，可为空
 	TagIDs          []uint     `json:"tag_ids"`
 	Status          int        `json:"status" binding:"omitempty,oneof=0 1 2"`
-	CreatedAt       *time.Time `json:"created_at"` // 创建时间，可选，允许手动修改创建时间
+	Visibility      string     `json:"visibility" binding:"omitempty,oneof=public private password"`
+	Password        string     `json:"password" binding:"max=64"` // 为空时保留原密码
+	CreatedAt       *time.Time `json:"created_at"`                // 创建时间，可选，允许手动修改创建时间
+	Revision        *int       `json:"revision"`                  // 编辑时加载的修订号，与当前不一致时拒绝保存；为空时不校验
+	SEO             *SEOMeta   `json:"seo"`                       // SEO 信息，为空时不修改，传入时整体替换
+}
+
+// SEOMeta 文章 SEO 信息，用于前台渲染 meta 标签
+type SEOMeta struct {
+	MetaDescription string `json:"meta_description" binding:"max=300"` // 为空时前台可回退到摘要
+	MetaKeywords    string `json:"meta_keywords" binding:"max=255"`    // 逗号分隔
+	CanonicalURL    string `json:"canonical_url" binding:"omitempty,url,max=500"`
+	NoIndex         bool   `json:"noindex"` // 为 true 时输出 noindex，禁止搜索引擎收录
 }
 
 // UpdateArticleStatusRequest 更新文章状态请求
@@ -51,6 +68,26 @@ type BatchUpdateFieldsRequest struct {
 	CreatedAt  *time.Time  `json:"created_at"`  // 创建时间，可选
 }
 
+// BatchUpdateStatusRequest 批量更新状态请求
+type BatchUpdateStatusRequest struct {
+	ArticleIDs []uint `json:"article_ids" binding:"required,min=1,max=200"`
+	Status     *int   `json:"status" binding:"required,oneof=0 1 2"` // 0: 取消发布（草稿）, 1: 发布, 2: 下线归档
+}
+
+// BatchStatusResult 单篇文章的状态更新结果
+type BatchStatusResult struct {
+	ArticleID uint   `json:"article_id"`
+	Success   bool   `json:"success"`
+	Changed   bool   `json:"changed"` // 状态是否发生变化，原状态相同时为 false
+	Message   string `json:"message,omitempty"`
+}
+
+// BatchStatusResponse 批量更新状态响应
+type BatchStatusResponse struct {
+	Updated int                 `json:"updated"` // 状态发生变化的文章数
+	Results []BatchStatusResult `json:"results"`
+}
+
 // BatchDeleteRequest 批量删除请求
 type BatchDeleteRequest struct {
 	ArticleIDs []uint `json:"article_ids" binding:"required,min=1"`
@@ -62,15 +99,21 @@ type ArticleListRequest struct {
 	Category  string `form:"category"`
 	Tag       string `form:"tag"`
 	ChapterID string `form:"chapter_id"`
+	AuthorID  uint   `form:"author_id"` // 主作者或共同作者
 	Status    string `form:"status"`
 	Keyword   string `form:"keyword"`
-	Sort      string `form:"sort"` // latest, views, likes
+	Sort      string `form:"sort"`   // latest, views, likes
+	Cursor    string `form:"cursor"` // 游标分页：上一页返回的 next_cursor，首页传空
+	Fields    string `form:"fields"` // 只返回指定字段，逗号分隔，如 id,title,summary
+	Lang      string `form:"lang"`   // 博客前台查询：按语言合并翻译组，有该语言版本时返回该版本，否则返回源文章
+	Public    bool   `form:"-"`      // 博客前台查询：只返回已发布且非私密的文章，隐藏加密文章摘要
 }
 
 // ArticleResponse 文章响应
 type ArticleResponse struct {
 	ID              uint             `json:"id"`
 	Title           string           `json:"title"`
+	Slug            string           `json:"slug"`
 	ContentMarkdown string           `json:"content_markdown"`
 	ContentHTML     string           `json:"content_html"`
 	Summary         string           `json:"summary"`
@@ -79,32 +122,64 @@ type ArticleResponse struct {
 	CategoryID      uint             `json:"category_id"`
 	ChapterID       *uint            `json:"chapter_id"`
 	Status          int              `json:"status"`
+	IsTop           bool             `json:"is_top"`
+	PinnedSort      int              `json:"pinned_sort"`
+	Visibility      string           `json:"visibility"`
+	WordCount       int              `json:"word_count"`
+	ReadingTime     int              `json:"reading_time"` // 预计阅读分钟数
+	Revision        int              `json:"revision"`     // 修订号，更新时原样提交用于冲突检测
 	ViewCount       int              `json:"view_count"`
 	LikeCount       int              `json:"like_count"`
 	FavoriteCount   int              `json:"favorite_count"`
 	CommentCount    int              `json:"comment_count"`
 	CreatedAt       time.Time        `json:"created_at"`
 	UpdatedAt       time.Time        `json:"updated_at"`
+	SEO             SEOMeta          `json:"seo"`
 	Author          *AuthorInfo      `json:"author,omitempty"`
+	Authors         []CoAuthorInfo   `json:"authors,omitempty"` // 共同作者
 	Category        *CategoryInfo    `json:"category,omitempty"`
 	Tags            []TagInfo        `json:"tags,omitempty"`
 }
 
+// ArticleLockResponse 文章编辑锁状态
+type ArticleLockResponse struct {
+	Acquired  bool        `json:"acquired"` // 当前管理员是否持有锁，为 false 时提示正在被他人编辑
+	Enabled   bool        `json:"enabled"`  // 编辑锁是否可用，未配置 Redis 时为 false
+	Holder    *LockHolder `json:"holder,omitempty"`
+	ExpiresAt *time.Time  `json:"expires_at,omitempty"`
+	Revision  int         `json:"revision"` // 文章当前修订号，保存时原样提交
+	Message   string      `json:"message,omitempty"`
+}
+
+// LockHolder 编辑锁持有者
+type LockHolder struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
 // ArticleListItem 文章列表项
 type ArticleListItem struct {
-	ID            uint          `json:"id"`
-	Title         string        `json:"title"`
-	Summary       string        `json:"summary"`
-	Cover         string        `json:"cover"`
-	Status        int           `json:"status"`
-	ViewCount     int           `json:"view_count"`
-	LikeCount     int           `json:"like_count"`
-	FavoriteCount int           `json:"favorite_count"`
-	CommentCount  int           `json:"comment_count"`
-	CreatedAt     time.Time     `json:"created_at"`
-	Author        *AuthorInfo   `json:"author,omitempty"`
-	Category      *CategoryInfo `json:"category,omitempty"`
-	Tags          []TagInfo     `json:"tags,omitempty"`
+	ID            uint           `json:"id"`
+	Title         string         `json:"title"`
+	Slug          string         `json:"slug"`
+	Summary       string         `json:"summary"`
+	Cover         string         `json:"cover"`
+	Status        int            `json:"status"`
+	IsTop         bool           `json:"is_top"`
+	PinnedSort    int            `json:"pinned_sort"`
+	Visibility    string         `json:"visibility"`
+	WordCount     int            `json:"word_count"`
+	ReadingTime   int            `json:"reading_time"` // 预计阅读分钟数
+	ViewCount     int            `json:"view_count"`
+	LikeCount     int            `json:"like_count"`
+	FavoriteCount int            `json:"favorite_count"`
+	CommentCount  int            `json:"comment_count"`
+	CreatedAt     time.Time      `json:"created_at"`
+	Language      string         `json:"language,omitempty"` // 文章语言，未加入翻译组时为空
+	Author        *AuthorInfo    `json:"author,omitempty"`
+	Authors       []CoAuthorInfo `json:"authors,omitempty"` // 共同作者
+	Category      *CategoryInfo  `json:"category,omitempty"`
+	Tags          []TagInfo      `json:"tags,omitempty"`
 }
 
 // CategoryInfo 分类信息
@@ -129,7 +204,237 @@ type AuthorInfo struct {
 	Avatar   string `json:"avatar"`
 }
 
+// CoAuthorInfo 共同作者信息
+type CoAuthorInfo struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Nickname string `json:"nickname"`
+	Avatar   string `json:"avatar"`
+	Role     string `json:"role"` // author, contributor
+}
+
+// ArticleAuthorItem 共同作者设置项
+type ArticleAuthorItem struct {
+	UserID uint   `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"omitempty,oneof=author contributor"` // 默认为 author
+}
+
+// ArticleAuthorRequest 文章共同作者请求
+type ArticleAuthorRequest struct {
+	ID     uint `uri:"id" binding:"required,min=1"`
+	UserID uint `uri:"user_id" binding:"required,min=1"`
+}
+
+// SetArticleAuthorsRequest 设置文章共同作者请求（按数组顺序署名，传入空数组表示清空）
+type SetArticleAuthorsRequest struct {
+	Authors []ArticleAuthorItem `json:"authors" binding:"dive"`
+}
+
+// LinkTranslationRequest 关联文章译文请求
+type LinkTranslationRequest struct {
+	ArticleID      uint   `json:"article_id" binding:"required,min=1"`        // 译文文章ID
+	Language       string `json:"language" binding:"required,max=20"`         // 译文语言代码，如 en、zh-CN
+	SourceLanguage string `json:"source_language" binding:"omitempty,max=20"` // 源文章语言代码，不传时保持原值，新建翻译组时默认为站点默认语言
+}
+
+// ArticleTranslationItem 文章的一个语言版本
+type ArticleTranslationItem struct {
+	ArticleID uint   `json:"article_id"`
+	Language  string `json:"language"`
+	Title     string `json:"title"`
+	Slug      string `json:"slug"`
+	Status    int    `json:"status"`
+	Source    bool   `json:"source"` // 是否为翻译组的源文章
+}
+
+// ArticleTranslationsResponse 文章所在翻译组的语言版本，未加入翻译组时 group_id 为 0
+type ArticleTranslationsResponse struct {
+	GroupID uint                     `json:"group_id"`
+	Items   []ArticleTranslationItem `json:"items"`
+}
+
 // ExportArticleRequest 导出文章请求
 type ExportArticleRequest struct {
-	ArticleIDs []uint `json:"article_ids"` // 文章ID列表，为空表示导出全部
+	ArticleIDs []uint  `json:"article_ids"`                                     // 文章ID列表，为空表示导出全部
+	Profile    string `json:"profile" binding:"omitempty,oneof=default hugo hexo"` // 导出格式：default（可重新导入）、hugo、hexo
+}
+
+// ExportJobResponse 异步导出任务
+type ExportJobResponse struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"` // pending 排队中, running 导出中, done 已完成, failed 失败
+	Total       int        `json:"total"`  // 文章总数，开始导出后才有值
+	Done        int        `json:"done"`   // 已写入的文章数
+	Error       string     `json:"error,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"` // 完成后的下载地址
+	CreatedAt   time.Time  `json:"created_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+// ExportBookRequest 导出 PDF / EPUB 请求，tag_id 与 article_ids 二选一
+type ExportBookRequest struct {
+	ArticleIDs []uint `json:"article_ids"` // 文章ID列表，按列表顺序排列
+	TagID      uint   `json:"tag_id"`      // 笔记标签ID，按章节树导出该标签下已发布的文章
+}
+
+// ImportReport 导入报告
+type ImportReport struct {
+	Total       int                `json:"total"`
+	Success     int                `json:"success"`
+	Failed      int                `json:"failed"`
+	FailedFiles []string           `json:"failed_files,omitempty"` // 失败原因摘要：文件名: 原因
+	Items       []ImportReportItem `json:"items"`
+}
+
+// ImportReportItem 单个文件的导入结果
+type ImportReportItem struct {
+	File      string `json:"file"`
+	ArticleID uint   `json:"article_id,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ArticleVersionRequest 文章版本请求
+type ArticleVersionRequest struct {
+	ID        uint `uri:"id" binding:"required,min=1"`
+	VersionID uint `uri:"version_id" binding:"required,min=1"`
+}
+
+// ArticleVersionItem 文章版本列表项
+type ArticleVersionItem struct {
+	ID        uint      `json:"id"`
+	ArticleID uint      `json:"article_id"`
+	Version   int       `json:"version"`
+	Title     string    `json:"title"`
+	Summary   string    `json:"summary"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ArticleVersionResponse 文章版本详情
+type ArticleVersionResponse struct {
+	ArticleVersionItem
+	ContentMarkdown string `json:"content_markdown"`
+	ContentHTML     string `json:"content_html"`
+}
+
+// ArticleVersionDiff 文章版本差异
+type ArticleVersionDiff struct {
+	From    string     `json:"from"` // 对比基准，如 v3
+	To      string     `json:"to"`   // 对比目标，如 v5 或 current
+	Title   []DiffLine `json:"title"`
+	Content []DiffLine `json:"content"`
+	Added   int        `json:"added"`
+	Removed int        `json:"removed"`
+}
+
+// DiffLine 差异行
+type DiffLine struct {
+	Op   string `json:"op"` // equal, insert, delete
+	Text string `json:"text"`
+}
+
+// TrashListRequest 回收站列表请求
+type TrashListRequest struct {
+	PageRequest
+	Keyword string `form:"keyword"`
+}
+
+// TrashItem 回收站文章列表项
+type TrashItem struct {
+	ArticleListItem
+	DeletedAt time.Time  `json:"deleted_at"`
+	PurgeAt   *time.Time `json:"purge_at,omitempty"` // 预计自动彻底删除时间，未开启自动清理时为空
+}
+
+// TrashActionRequest 回收站恢复/彻底删除请求
+type TrashActionRequest struct {
+	ArticleIDs []uint `json:"article_ids" binding:"required,min=1"`
+}
+
+// AutosaveRequest 自动保存草稿请求
+type AutosaveRequest struct {
+	Title           string `json:"title" binding:"max=200"`
+	Summary         string `json:"summary" binding:"max=500"`
+	ContentMarkdown string `json:"content_markdown"`
+}
+
+// AutosaveResponse 自动保存结果
+type AutosaveResponse struct {
+	SavedAt time.Time `json:"saved_at"`
+}
+
+// ArticleDraftResponse 自动保存的草稿
+type ArticleDraftResponse struct {
+	ArticleID        uint      `json:"article_id"`
+	Title            string    `json:"title"`
+	Summary          string    `json:"summary"`
+	ContentMarkdown  string    `json:"content_markdown"`
+	SavedAt          time.Time `json:"saved_at"`
+	ArticleUpdatedAt time.Time `json:"article_updated_at"`
+	Newer            bool      `json:"newer"` // 草稿是否比文章正式内容更新（为 true 时编辑器应提示恢复）
+}
+
+// PinArticleRequest 置顶文章请求
+type PinArticleRequest struct {
+	PinnedSort int `json:"pinned_sort"` // 置顶排序，越大越靠前
+}
+
+// UnlockArticleRequest 加密文章解锁请求
+type UnlockArticleRequest struct {
+	Password string `json:"password" binding:"required,max=64"`
+}
+
+// UnlockArticleResponse 加密文章解锁响应
+type UnlockArticleResponse struct {
+	AccessToken string    `json:"access_token"` // 通过 X-Article-Token 请求头或 access_token 查询参数传递
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// ArticleAuditListRequest 文章审计日志查询请求
+type ArticleAuditListRequest struct {
+	PageRequest
+	ArticleID  uint   `form:"article_id"`
+	OperatorID uint   `form:"operator_id"`
+	Action     string `form:"action" binding:"omitempty,oneof=create update status delete"`
+}
+
+// AuditChange 字段级变更，正文和密码只记录是否变更，不记录内容
+type AuditChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old,omitempty"`
+	New   interface{} `json:"new,omitempty"`
+}
+
+// ArticleAuditLogResponse 文章审计日志
+type ArticleAuditLogResponse struct {
+	ID           uint          `json:"id"`
+	ArticleID    uint          `json:"article_id"`
+	ArticleTitle string        `json:"article_title"`
+	OperatorID   uint          `json:"operator_id"`
+	Operator     *AuthorInfo   `json:"operator,omitempty"`
+	Action       string        `json:"action"`
+	Changes      []AuditChange `json:"changes"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+// ArchiveYear 归档年份
+type ArchiveYear struct {
+	Year   int            `json:"year"`
+	Count  int64          `json:"count"`
+	Months []ArchiveMonth `json:"months"`
+}
+
+// ArchiveMonth 归档月份
+type ArchiveMonth struct {
+	Month    int              `json:"month"`
+	Count    int64            `json:"count"`
+	Articles []ArchiveArticle `json:"articles"`
+}
+
+// ArchiveArticle 归档中的文章
+type ArchiveArticle struct {
+	ID        uint      `json:"id"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
 }
@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// UploadAttachmentRequest 上传附件请求（multipart/form-data，文件字段为 file）
+type UploadAttachmentRequest struct {
+	ArticleID   uint   `form:"article_id"` // 关联的文章，可选
+	Description string `form:"description" binding:"max=500"`
+}
+
+// AttachmentListRequest 附件列表请求
+type AttachmentListRequest struct {
+	PageRequest
+	Keyword    string `form:"keyword"`
+	ArticleID  uint   `form:"article_id"` // 只返回该文章的附件
+	Unattached bool   `form:"unattached"` // 只返回未关联文章的附件
+}
+
+// SetAttachmentArticleRequest 修改附件关联文章请求
+type SetAttachmentArticleRequest struct {
+	ArticleID *uint `json:"article_id"` // 为空时取消关联
+}
+
+// AttachmentResponse 附件响应
+type AttachmentResponse struct {
+	ID            uint      `json:"id"`
+	Name          string    `json:"name"`
+	URL           string    `json:"url,omitempty"` // 文件地址，博客前台不返回，通过 download_url 下载并计数
+	DownloadURL   string    `json:"download_url"`
+	Size          int64     `json:"size"`
+	MimeType      string    `json:"mime_type"`
+	Description   string    `json:"description"`
+	ArticleID     *uint     `json:"article_id"`
+	ArticleTitle  string    `json:"article_title,omitempty"`
+	DownloadCount int64     `json:"download_count"`
+	CreatedAt     time.Time `json:"created_at"`
+}
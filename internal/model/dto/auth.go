@@ -17,11 +17,56 @@ type RegisterRequest struct {
 	Avatar   string `json:"avatar" binding:"max=500"`
 }
 
+// VerifyEmailRequest 验证邮箱请求
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ResendVerificationRequest 重新发送验证邮件请求
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ForgotPasswordRequest 找回密码请求
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest 重置密码请求
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6,max=50"`
+}
+
 // LoginResponse 登录响应
 type LoginResponse struct {
-	Token string     `json:"token"`
-	Admin *AdminInfo `json:"admin,omitempty"`
-	User  *UserInfo  `json:"user,omitempty"`
+	Token                string     `json:"token"`
+	RefreshToken         string     `json:"refresh_token,omitempty"` // 启用 Redis 时返回，用于 /auth/refresh 换取新的令牌
+	ExpiresIn            int64      `json:"expires_in,omitempty"`    // token 有效期（秒）
+	Admin                *AdminInfo `json:"admin,omitempty"`
+	User                 *UserInfo  `json:"user,omitempty"`
+	VerificationRequired bool       `json:"verification_required,omitempty"` // 注册后需要先验证邮箱，此时不返回 token
+	TwoFactorRequired    bool       `json:"two_factor_required,omitempty"`   // 需要两步验证，此时不返回 token，使用 challenge_token 提交验证码
+	TwoFactorSetup       bool       `json:"two_factor_setup,omitempty"`      // 当前角色必须启用两步验证但尚未绑定验证器，需要先绑定
+	ChallengeToken       string     `json:"challenge_token,omitempty"`       // 两步验证令牌，5 分钟内有效
+	RecoveryCodes        []string   `json:"recovery_codes,omitempty"`        // 登录时完成绑定后生成的恢复码，只显示一次
+}
+
+// RefreshTokenRequest 刷新令牌请求
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest 登出请求，携带刷新令牌时吊销该令牌
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// TokenResponse 令牌响应
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int64  `json:"expires_in"` // token 有效期（秒）
 }
 
 // AdminInfo 管理员信息
@@ -53,3 +98,21 @@ type UserInfo struct {
 	Status    int       `json:"status"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// RotateKeyRequest 轮换签名密钥请求
+type RotateKeyRequest struct {
+	Secret         string `json:"secret" binding:"omitempty,min=32"` // 为空时自动生成
+	RevokePrevious bool   `json:"revoke_previous"`                   // 同时吊销全部旧密钥，已签发的 token 立即失效
+}
+
+// SigningKeyInfo 签名密钥信息
+type SigningKeyInfo struct {
+	ID      string `json:"id"`
+	Current bool   `json:"current"`
+}
+
+// RotateKeyResponse 轮换签名密钥响应
+type RotateKeyResponse struct {
+	KeyID string            `json:"key_id"`
+	Keys  []*SigningKeyInfo `json:"keys"`
+}
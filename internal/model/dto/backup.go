@@ -0,0 +1,49 @@
+package dto
+
+import "time"
+
+// BackupItem 备份文件
+type BackupItem struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	Location  string    `json:"location"` // oss 或 local
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ImportBackupRequest 导入 JSON 文档
+type ImportBackupRequest struct {
+	DryRun bool `form:"dry_run" json:"dry_run"` // 只返回将要发生的变更，不写入数据库
+}
+
+// RestoreBackupRequest 从已有备份恢复
+type RestoreBackupRequest struct {
+	Name   string `form:"name" json:"name"`       // 备份文件名，为空时需上传备份文件
+	DryRun bool   `form:"dry_run" json:"dry_run"` // 只返回将要发生的变更，不写入数据库
+}
+
+// RestoreReport 恢复结果
+type RestoreReport struct {
+	DryRun          bool                   `json:"dry_run"`
+	Version         int                    `json:"version"`           // 备份格式版本
+	BackupCreatedAt time.Time              `json:"backup_created_at"` // 备份时间
+	Entities        []*RestoreEntityReport `json:"entities"`
+}
+
+// RestoreEntityReport 每类数据的恢复结果
+type RestoreEntityReport struct {
+	Entity    string           `json:"entity"` // users, tags, categories, chapters, settings, articles, comments 等
+	Created   int              `json:"created"`
+	Updated   int              `json:"updated"`
+	Unchanged int              `json:"unchanged"`
+	Skipped   int              `json:"skipped"`
+	Changes   []*RestoreChange `json:"changes"` // 新增、更新和跳过的记录，不含未变化的记录
+}
+
+// RestoreChange 单条记录的变更
+type RestoreChange struct {
+	Action   string `json:"action"`    // created, updated, skipped
+	BackupID uint   `json:"backup_id"` // 备份中的ID
+	ID       uint   `json:"id"`        // 当前数据库中的ID，dry-run 时新增记录的ID仅供参考
+	Name     string `json:"name"`
+	Reason   string `json:"reason,omitempty"` // 跳过原因
+}
@@ -0,0 +1,42 @@
+package dto
+
+import "time"
+
+// BannerRequest 创建或更新横幅请求
+type BannerRequest struct {
+	Title    string     `json:"title" binding:"max=100"`
+	Subtitle string     `json:"subtitle" binding:"max=200"`
+	Image    string     `json:"image" binding:"required,max=500"` // 通过文件上传接口上传后的图片地址
+	Link     string     `json:"link" binding:"max=500"`           // / 开头的站内路径或 http(s) 地址，为空时不可点击
+	NewTab   bool       `json:"new_tab"`
+	Enabled  *bool      `json:"enabled"` // 为空时不修改，创建时默认启用
+	Sort     *int       `json:"sort"`    // 为空时不修改，创建时默认 0
+	StartAt  *time.Time `json:"start_at"`
+	EndAt    *time.Time `json:"end_at"`
+}
+
+// BannerListRequest 后台横幅列表请求
+type BannerListRequest struct {
+	PageRequest
+	Status  string `form:"status" binding:"omitempty,oneof=active scheduled expired disabled"`
+	Keyword string `form:"keyword"` // 匹配标题和副标题
+}
+
+// BannerResponse 横幅
+type BannerResponse struct {
+	ID           uint       `json:"id"`
+	Title        string     `json:"title"`
+	Subtitle     string     `json:"subtitle"`
+	Image        string     `json:"image"`
+	ThumbnailURL string     `json:"thumbnail_url"` // 缩略图，未生成缩放版本时为原图
+	WebPURL      string     `json:"webp_url"`
+	Link         string     `json:"link"`
+	NewTab       bool       `json:"new_tab"`
+	Enabled      bool       `json:"enabled"`
+	Sort         int        `json:"sort"`
+	StartAt      *time.Time `json:"start_at"`
+	EndAt        *time.Time `json:"end_at"`
+	Status       string     `json:"status"` // 展示状态：active、scheduled、expired、disabled
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
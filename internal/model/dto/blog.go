@@ -9,6 +9,7 @@ type CreateCommentRequest struct {
 	ParentID      *uint  `json:"parent_id"`
 	ReplyToUserID *uint  `json:"reply_to_user_id"` // 被回复的用户ID
 	Content       string `json:"content" binding:"required,min=1,max=1000"`
+	IP            string `json:"-"` // 评论者 IP，由服务层填充，用于屏蔽规则匹配
 }
 
 // CreateGuestbookMessageRequest 创建留言板消息请求
@@ -25,14 +26,19 @@ type CommentResponse struct {
 	ArticleID    *uint              `json:"article_id"` // 可为空
 	UserID       uint               `json:"user_id"`
 	ParentID     *uint              `json:"parent_id"`
-	Content      string             `json:"content"`
+	RootID       *uint              `json:"root_id"`     // 所属顶级评论ID，顶级评论为空
+	Content      string             `json:"content"`      // 原始 Markdown 文本，未经转义，只能作为纯文本使用（如编辑框）
+	ContentHTML  string             `json:"content_html"` // 支持粗体、代码、链接和引用的 Markdown 渲染结果，展示评论时使用
 	LikeCount    int                `json:"like_count"`
+	ReplyCount   int                `json:"reply_count"` // 顶级评论的回复数，回复通过 /blog/comments/{id}/replies 按需加载
 	IsLiked      bool               `json:"is_liked"`
 	Status       int                `json:"status"`
+	Edited       bool               `json:"edited"`    // 作者是否编辑过
+	EditedAt     *time.Time         `json:"edited_at"` // 最后一次编辑的时间
+	Reactions    []ReactionCount    `json:"reactions"` // 表情回应统计
 	CreatedAt    time.Time          `json:"created_at"`
 	User         *UserInfo          `json:"user,omitempty"`
 	ReplyToUser  *UserInfo          `json:"reply_to_user,omitempty"`
-	Replies      []CommentResponse  `json:"replies,omitempty"`
 }
 
 // CommentListResponse 评论列表响应
@@ -46,8 +52,22 @@ type CommentListResponse struct {
 // ArticleDetailResponse 文章详情响应（包含用户状态）
 type ArticleDetailResponse struct {
 	ArticleResponse
-	IsLiked     bool `json:"is_liked"`
-	IsFavorited bool `json:"is_favorited"`
+	IsLiked             bool                     `json:"is_liked"`
+	IsFavorited         bool                     `json:"is_favorited"`
+	Locked              bool                     `json:"locked"`                         // 加密文章未解锁时为 true，不返回正文和摘要
+	TOC                 []*TOCItem               `json:"toc"`                            // 文章目录，锚点与 content_html 中标题的 id 一致
+	Reactions           []ReactionCount          `json:"reactions"`                      // 表情回应统计
+	Language            string                   `json:"language,omitempty"`             // 文章语言，未加入翻译组时为空
+	Translations        []ArticleTranslationItem `json:"translations,omitempty"`         // 前台可见的全部语言版本（包含当前文章）
+	TranslationFallback bool                     `json:"translation_fallback,omitempty"` // 请求的语言没有可用版本，返回的是原文章
+}
+
+// TOCItem 文章目录项
+type TOCItem struct {
+	Level    int        `json:"level"`  // 标题级别 1-6
+	Text     string     `json:"text"`   // 标题文本
+	Anchor   string     `json:"anchor"` // 标题锚点
+	Children []*TOCItem `json:"children,omitempty"`
 }
 
 // LikeInfo 点赞信息
@@ -72,6 +92,7 @@ type FavoriteInfo struct {
 	ID        uint             `json:"id"`
 	ArticleID uint             `json:"article_id"`
 	UserID    uint             `json:"user_id"`
+	FolderID  uint             `json:"folder_id"` // 所属收藏夹，0 为默认收藏夹
 	CreatedAt time.Time        `json:"created_at"`
 	Article   *ArticleResponse `json:"article,omitempty"`
 }
@@ -0,0 +1,33 @@
+package dto
+
+// CaptchaRequest 获取验证码请求
+type CaptchaRequest struct {
+	Scene string `form:"scene" binding:"required"` // login, register, comment, password_reset, friend_link
+}
+
+// CaptchaResponse 验证码
+type CaptchaResponse struct {
+	Enabled   bool   `json:"enabled"`            // 该场景是否需要人机验证
+	Provider  string `json:"provider,omitempty"` // image, slider, turnstile, hcaptcha
+	SiteKey   string `json:"site_key,omitempty"` // Turnstile / hCaptcha 组件的站点密钥
+	ID        string `json:"id,omitempty"`       // 验证码ID，校验时提交
+	Image     string `json:"image,omitempty"`    // 验证码图片或滑块背景图（data URI）
+	Piece     string `json:"piece,omitempty"`    // 滑块图片（data URI）
+	PieceY    int    `json:"piece_y,omitempty"`  // 滑块的纵坐标
+	Width     int    `json:"width,omitempty"`
+	Height    int    `json:"height,omitempty"`
+	ExpiresIn int    `json:"expires_in,omitempty"` // 有效期（秒）
+}
+
+// CaptchaVerifyRequest 校验验证码请求
+type CaptchaVerifyRequest struct {
+	Scene  string `json:"scene" binding:"required"`
+	ID     string `json:"id" binding:"max=64"`                // 本站出题时必填
+	Answer string `json:"answer" binding:"required,max=4096"` // 图片验证码的数字、滑块的横坐标或第三方组件返回的令牌
+}
+
+// CaptchaTicket 验证通过后的凭证，提交表单时放在 X-Captcha-Ticket 请求头中，只能使用一次
+type CaptchaTicket struct {
+	Ticket    string `json:"ticket"`
+	ExpiresIn int    `json:"expires_in"` // 有效期（秒）
+}
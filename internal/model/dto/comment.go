@@ -0,0 +1,81 @@
+package dto
+
+import "time"
+
+// UpdateCommentRequest 编辑评论请求
+type UpdateCommentRequest struct {
+	Content string `json:"content" binding:"required,min=1,max=1000"`
+}
+
+// UpdateCommentStatusRequest 更新评论状态请求
+type UpdateCommentStatusRequest struct {
+	Status *int `json:"status" binding:"required,oneof=0 1 2 3"` // 0: 待审核, 1: 通过, 2: 垃圾评论, 3: 回收站
+}
+
+// BatchCommentStatusRequest 批量审核评论请求
+type BatchCommentStatusRequest struct {
+	CommentIDs []uint `json:"comment_ids" binding:"required,min=1,max=200"`
+	Status     *int   `json:"status" binding:"required,oneof=0 1 2 3"` // 0: 待审核, 1: 通过, 2: 垃圾评论, 3: 回收站
+}
+
+// BatchCommentStatusResult 单条评论的审核结果
+type BatchCommentStatusResult struct {
+	CommentID uint   `json:"comment_id"`
+	Success   bool   `json:"success"`
+	Changed   bool   `json:"changed"` // 状态是否发生变化，原状态相同时为 false
+	Message   string `json:"message,omitempty"`
+}
+
+// BatchCommentStatusResponse 批量审核评论响应
+type BatchCommentStatusResponse struct {
+	Updated int                        `json:"updated"` // 状态发生变化的评论数
+	Results []BatchCommentStatusResult `json:"results"`
+}
+
+// GuestbookListRequest 后台留言列表请求
+type GuestbookListRequest struct {
+	PageRequest
+	Status  string `form:"status" binding:"omitempty,oneof=0 1 2 3"`
+	Keyword string `form:"keyword"`
+}
+
+// CommentBlockListRequest 评论屏蔽规则列表请求
+type CommentBlockListRequest struct {
+	PageRequest
+	Type    string `form:"type" binding:"omitempty,oneof=ip email keyword"`
+	Keyword string `form:"keyword"`
+}
+
+// CommentBlockRequest 创建或更新评论屏蔽规则请求
+type CommentBlockRequest struct {
+	Type    string `json:"type" binding:"required,oneof=ip email keyword"`
+	Value   string `json:"value" binding:"required,max=200"` // IP 或 CIDR 网段、邮箱地址或域名（如 example.com）、关键词
+	Action  string `json:"action" binding:"required,oneof=reject spam"`
+	Note    string `json:"note" binding:"max=200"`
+	Enabled *bool  `json:"enabled"` // 默认启用
+}
+
+// CommentExportRequest 导出评论请求
+type CommentExportRequest struct {
+	Format    string `form:"format" binding:"omitempty,oneof=csv json"` // 默认 csv
+	ArticleID uint   `form:"article_id"`
+	Status    string `form:"status" binding:"omitempty,oneof=0 1 2 3"`
+}
+
+// CommentExportItem 导出的评论
+type CommentExportItem struct {
+	ID           uint       `json:"id"`
+	ArticleID    *uint      `json:"article_id"` // 为空表示留言板消息
+	ArticleTitle string     `json:"article_title"`
+	UserID       uint       `json:"user_id"`
+	Username     string     `json:"username"`
+	Nickname     string     `json:"nickname"`
+	ParentID     *uint      `json:"parent_id"`
+	RootID       *uint      `json:"root_id"`
+	Status       int        `json:"status"`
+	Content      string     `json:"content"`
+	LikeCount    int        `json:"like_count"`
+	ReplyCount   int        `json:"reply_count"`
+	CreatedAt    time.Time  `json:"created_at"`
+	EditedAt     *time.Time `json:"edited_at"`
+}
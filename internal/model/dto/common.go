@@ -14,7 +14,20 @@ type PageResponse struct {
 	Data  interface{} `json:"data"`
 }
 
+// CursorPageResponse 游标分页响应，NextCursor 为空表示没有更多数据
+type CursorPageResponse struct {
+	NextCursor string      `json:"next_cursor"`
+	Limit      int         `json:"limit"`
+	Data       interface{} `json:"data"`
+}
+
 // IDRequest ID 请求
 type IDRequest struct {
 	ID uint `uri:"id" binding:"required,min=1"`
 }
+
+// ClientInfo 请求方信息
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+// FavoriteFolderRequest 创建或修改收藏夹请求
+type FavoriteFolderRequest struct {
+	Name        string `json:"name" binding:"required,max=50"`
+	Description string `json:"description" binding:"max=200"`
+}
+
+// FavoriteFolderInfo 收藏夹信息，ID 为 0 的是默认收藏夹
+type FavoriteFolderInfo struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Count       int64     `json:"count"` // 收藏数
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AddFavoriteRequest 收藏文章请求，FolderID 为 0 时放入默认收藏夹
+type AddFavoriteRequest struct {
+	ArticleID uint `json:"article_id" binding:"required,min=1"`
+	FolderID  uint `json:"folder_id"`
+}
+
+// MoveFavoritesRequest 移动收藏请求，FolderID 为 0 时移回默认收藏夹
+type MoveFavoritesRequest struct {
+	ArticleIDs []uint `json:"article_ids" binding:"required,min=1,max=100"`
+	FolderID   uint   `json:"folder_id"`
+}
+
+// FavoriteListRequest 我的收藏列表请求，不传 folder_id 时查询全部收藏，传 0 时查询默认收藏夹
+type FavoriteListRequest struct {
+	PageRequest
+	FolderID *uint `form:"folder_id"`
+}
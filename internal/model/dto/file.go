@@ -0,0 +1,73 @@
+package dto
+
+import "time"
+
+// CleanOrphanImagesRequest 清理未引用图片请求
+type CleanOrphanImagesRequest struct {
+	DryRun bool `json:"dry_run" form:"dry_run"`
+	MinAge int  `json:"min_age" form:"min_age"` // 只处理上传超过该小时数的图片，默认 24，避免误删编辑中尚未保存的图片
+}
+
+// OrphanImage 未被引用的图片
+type OrphanImage struct {
+	Key          string    `json:"key"`
+	URL          string    `json:"url"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// OrphanImageReport 未引用图片清理结果
+type OrphanImageReport struct {
+	DryRun     bool           `json:"dry_run"`
+	Location   string         `json:"location"`    // oss 或 local
+	Scanned    int            `json:"scanned"`     // 存储中的图片数
+	Referenced int            `json:"referenced"`  // 仍被引用的图片数
+	Recent     int            `json:"recent"`      // 上传时间不足 min_age 而跳过的图片数
+	Orphans    []*OrphanImage `json:"orphans"`     // 未被引用的图片
+	OrphanSize int64          `json:"orphan_size"` // 未引用图片总大小
+	Deleted    int            `json:"deleted"`
+	Failed     []string       `json:"failed"` // 删除失败的图片及原因
+}
+
+// InitUploadRequest 创建分片上传请求
+type InitUploadRequest struct {
+	Filename string `json:"filename" binding:"required,max=200"`
+	Size     int64  `json:"size" binding:"required,min=1"`
+	Folder   string `json:"folder"`    // 存储目录，默认 uploads
+	MimeType string `json:"mime_type"` // 文件类型，保存到文件记录
+}
+
+// UploadSessionResponse 分片上传会话
+type UploadSessionResponse struct {
+	UploadID      string    `json:"upload_id"`
+	Filename      string    `json:"filename"`
+	Size          int64     `json:"size"`
+	ChunkSize     int64     `json:"chunk_size"`     // 除最后一片外每片的大小
+	TotalParts    int       `json:"total_parts"`    // 分片数，分片号从 1 开始
+	UploadedParts []int     `json:"uploaded_parts"` // 已上传的分片号，续传时跳过
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// StorageStats 存储用量统计，由后台定时扫描存储生成并缓存
+type StorageStats struct {
+	Driver      string                 `json:"driver"` // 存储驱动，如 aliyun、s3、local
+	TotalSize   int64                  `json:"total_size"`
+	ObjectCount int                    `json:"object_count"`
+	Folders     []StorageFolderUsage   `json:"folders"` // 按顶层目录（articles、avatars、attachments 等）统计，按大小降序
+	Monthly     []StorageMonthlyGrowth `json:"monthly"` // 最近 12 个月每月新增，按月份升序
+	ScannedAt   time.Time              `json:"scanned_at"`
+}
+
+// StorageFolderUsage 目录存储用量
+type StorageFolderUsage struct {
+	Folder      string `json:"folder"`
+	Size        int64  `json:"size"`
+	ObjectCount int    `json:"object_count"`
+}
+
+// StorageMonthlyGrowth 每月新增存储，按对象最后修改时间归入月份
+type StorageMonthlyGrowth struct {
+	Month       string `json:"month"` // 2006-01
+	Size        int64  `json:"size"`
+	ObjectCount int    `json:"object_count"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// FollowUserInfo 关注列表中的用户
+type FollowUserInfo struct {
+	ID         uint      `json:"id"`
+	Username   string    `json:"username"`
+	Nickname   string    `json:"nickname"`
+	Avatar     string    `json:"avatar"`
+	Bio        string    `json:"bio"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+// FollowStatusResponse 关注状态
+type FollowStatusResponse struct {
+	Following     bool  `json:"following"`      // 当前用户是否已关注
+	FollowerCount int64 `json:"follower_count"` // 对方的粉丝数
+}
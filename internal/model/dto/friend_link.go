@@ -0,0 +1,63 @@
+package dto
+
+// FriendLinkApplyRequest 访客申请友链请求
+type FriendLinkApplyRequest struct {
+	Name        string `json:"name" binding:"required,max=50"`
+	URL         string `json:"url" binding:"required,max=255"`
+	Logo        string `json:"logo" binding:"max=500"`
+	Description string `json:"description" binding:"max=200"`
+	Email       string `json:"email" binding:"omitempty,email,max=100"`
+}
+
+// FriendLinkListRequest 后台友链列表请求
+type FriendLinkListRequest struct {
+	PageRequest
+	Status  string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	Keyword string `form:"keyword"`
+	Dead    bool   `form:"dead"` // 只查询连续检测失败次数达到上限的友链
+}
+
+// FriendLinkRequest 管理员创建或更新友链请求
+type FriendLinkRequest struct {
+	Name        string `json:"name" binding:"required,max=50"`
+	URL         string `json:"url" binding:"required,max=255"`
+	Logo        string `json:"logo" binding:"max=500"`
+	Description string `json:"description" binding:"max=200"`
+	Email       string `json:"email" binding:"omitempty,email,max=100"`
+	Status      string `json:"status" binding:"omitempty,oneof=pending approved rejected"` // 创建时默认 approved，更新时为空不修改
+	Sort        *int   `json:"sort"`                                                       // 为空时不修改，创建时默认 0
+}
+
+// FriendLinkReviewRequest 审核友链申请请求
+type FriendLinkReviewRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected"`
+	Reason string `json:"reason" binding:"max=200"`
+}
+
+// FriendLinkSortItem 友链排序值
+type FriendLinkSortItem struct {
+	ID   uint `json:"id" binding:"required"`
+	Sort int  `json:"sort"`
+}
+
+// FriendLinkSortRequest 批量调整友链排序请求
+type FriendLinkSortRequest struct {
+	Items []FriendLinkSortItem `json:"items" binding:"required,min=1,max=500,dive"`
+}
+
+// FriendLinkResponse 前台显示的友链
+type FriendLinkResponse struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	Logo        string `json:"logo"`
+	Description string `json:"description"`
+}
+
+// FriendLinkCheckResponse 手动检测友链的结果
+type FriendLinkCheckResponse struct {
+	Alive     bool   `json:"alive"`
+	Status    int    `json:"status"` // HTTP 状态码，请求失败时为 0
+	Error     string `json:"error,omitempty"`
+	FailCount int    `json:"fail_count"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+// GraphQLRequest GraphQL 请求，GET 请求时 variables 为 JSON 字符串
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+// LoginLogListRequest 登录日志查询请求
+type LoginLogListRequest struct {
+	PageRequest
+	UserID   uint   `form:"user_id"`
+	Username string `form:"username"`
+	Success  *bool  `form:"success"`
+}
+
+// LoginLogInfo 登录日志
+type LoginLogInfo struct {
+	ID          uint      `json:"id"`
+	UserID      uint      `json:"user_id"`
+	Username    string    `json:"username"`
+	Channel     string    `json:"channel"` // admin, blog, oauth
+	Success     bool      `json:"success"`
+	Reason      string    `json:"reason,omitempty"`
+	IP          string    `json:"ip"`
+	Device      string    `json:"device"`
+	UserAgent   string    `json:"user_agent"`
+	Location    string    `json:"location"`
+	NewLocation bool      `json:"new_location"`
+	CreatedAt   time.Time `json:"created_at"`
+}
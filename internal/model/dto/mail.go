@@ -0,0 +1,7 @@
+package dto
+
+// MailTestRequest 发送测试邮件请求，Template 为空时发送 test 模板，其他模板使用示例数据
+type MailTestRequest struct {
+	To       string `json:"to" binding:"required,email"`
+	Template string `json:"template"`
+}
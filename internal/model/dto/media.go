@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+// MediaListRequest 媒体库列表请求
+type MediaListRequest struct {
+	PageRequest
+	Keyword string `form:"keyword"` // 匹配文件名
+	Folder  string `form:"folder"`  // 上传目录，如 articles、avatars
+}
+
+// MediaItem 媒体库中的图片
+type MediaItem struct {
+	ID           uint      `json:"id"`
+	Name         string    `json:"name"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"` // 缩略图，未生成缩放版本时为原图
+	WebPURL      string    `json:"webp_url"`
+	Size         int64     `json:"size"`
+	MimeType     string    `json:"mime_type"`
+	Folder       string    `json:"folder"`
+	UsageCount   int64     `json:"usage_count"` // 正文或封面引用该图片的文章数
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,49 @@
+package dto
+
+// MenuListRequest 菜单查询请求
+type MenuListRequest struct {
+	Location string `form:"location" binding:"omitempty,oneof=header footer"` // 默认 header
+}
+
+// MenuItemRequest 创建或更新菜单项请求
+type MenuItemRequest struct {
+	Location string `json:"location" binding:"omitempty,oneof=header footer"` // 仅创建时有效，默认 header
+	ParentID *uint  `json:"parent_id"`                                        // 为空表示顶级菜单
+	Title    string `json:"title" binding:"max=50"`                           // 为空时使用分类、标签或页面的名称
+	Type     string `json:"type" binding:"required,oneof=category tag page link"`
+	TargetID *uint  `json:"target_id"`             // type 为 category、tag、page 时必填
+	URL      string `json:"url" binding:"max=500"` // type 为 link 时必填，站内路径（/ 开头）或 http(s) 地址
+	Icon     string `json:"icon" binding:"max=100"`
+	NewTab   bool   `json:"new_tab"`
+	Visible  *bool  `json:"visible"` // 为空时创建为显示，更新时不修改
+	Sort     *int   `json:"sort"`    // 为空时创建为 0，更新时不修改
+}
+
+// MenuSortItem 菜单项的新位置
+type MenuSortItem struct {
+	ID       uint  `json:"id" binding:"required"`
+	ParentID *uint `json:"parent_id"`
+	Sort     int   `json:"sort"`
+}
+
+// MenuSortRequest 拖拽调整菜单层级和顺序请求
+type MenuSortRequest struct {
+	Location string         `json:"location" binding:"required,oneof=header footer"`
+	Items    []MenuSortItem `json:"items" binding:"required,min=1,max=500,dive"`
+}
+
+// MenuItemResponse 菜单项，URL 为解析后的前台地址
+type MenuItemResponse struct {
+	ID       uint                `json:"id"`
+	ParentID *uint               `json:"parent_id"`
+	Title    string              `json:"title"`
+	Type     string              `json:"type"`
+	TargetID *uint               `json:"target_id"`
+	URL      string              `json:"url"`
+	Icon     string              `json:"icon"`
+	NewTab   bool                `json:"new_tab"`
+	Visible  bool                `json:"visible"`
+	Sort     int                 `json:"sort"`
+	Broken   bool                `json:"broken,omitempty"` // 链接的分类、标签或页面已删除或未发布，前台不显示，仅后台返回
+	Children []*MenuItemResponse `json:"children"`
+}
@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+// MomentRequest 发布或编辑说说请求
+type MomentRequest struct {
+	Content  string   `json:"content" binding:"required,max=2000"`
+	Images   []string `json:"images" binding:"max=9,dive,required,max=500"` // 通过文件上传接口上传后的图片地址，最多 9 张
+	Location string   `json:"location" binding:"max=100"`
+}
+
+// MomentListRequest 说说列表请求
+type MomentListRequest struct {
+	PageRequest
+	Keyword string `form:"keyword"` // 匹配内容和位置，仅后台列表使用
+}
+
+// MomentImage 说说中的图片
+type MomentImage struct {
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"` // 缩略图，未生成缩放版本时为原图
+	WebPURL      string `json:"webp_url"`
+}
+
+// MomentResponse 说说
+type MomentResponse struct {
+	ID        uint           `json:"id"`
+	Content   string         `json:"content"`
+	Images    []*MomentImage `json:"images"`
+	Location  string         `json:"location"`
+	LikeCount int            `json:"like_count"`
+	IsLiked   bool           `json:"is_liked"` // 当前用户是否已点赞，未登录时为 false
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// MomentLikeResponse 点赞或取消点赞后的状态
+type MomentLikeResponse struct {
+	Liked     bool `json:"liked"`
+	LikeCount int  `json:"like_count"`
+}
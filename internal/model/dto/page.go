@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+// StaticPageRequest 创建或更新独立页面请求
+type StaticPageRequest struct {
+	Title           string `json:"title" binding:"required,max=200"`
+	Slug            string `json:"slug" binding:"max=100"` // 为空时根据标题生成，如 about、resume、now
+	ContentMarkdown string `json:"content_markdown" binding:"required"`
+	MetaDescription string `json:"meta_description" binding:"max=300"`
+	Status          *int   `json:"status" binding:"omitempty,oneof=0 1"` // 0: 草稿, 1: 已发布；创建时默认草稿，更新时为空不修改
+	Sort            *int   `json:"sort"`                                 // 为空时不修改，创建时默认 0
+}
+
+// StaticPageListRequest 后台页面列表请求
+type StaticPageListRequest struct {
+	PageRequest
+	Status  string `form:"status" binding:"omitempty,oneof=0 1"`
+	Keyword string `form:"keyword"` // 匹配标题和 slug
+}
+
+// StaticPageSummary 页面列表项，不包含正文
+type StaticPageSummary struct {
+	ID              uint       `json:"id"`
+	Title           string     `json:"title"`
+	Slug            string     `json:"slug"`
+	MetaDescription string     `json:"meta_description"`
+	Status          int        `json:"status"`
+	Sort            int        `json:"sort"`
+	PublishedAt     *time.Time `json:"published_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// StaticPageResponse 页面详情
+type StaticPageResponse struct {
+	StaticPageSummary
+	ContentMarkdown string     `json:"content_markdown,omitempty"` // 仅后台返回
+	ContentHTML     string     `json:"content_html"`
+	TOC             []*TOCItem `json:"toc"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+// RoutePermissionRequest 路由权限规则请求
+type RoutePermissionRequest struct {
+	Method      string   `json:"method" binding:"omitempty,oneof=* GET POST PUT PATCH DELETE"` // 为空表示全部方法
+	Path        string   `json:"path" binding:"required,startswith=/,max=200"`                 // 路由前缀，如 /analytics
+	Roles       []string `json:"roles" binding:"required,min=1"`                               // 允许的角色，["*"] 表示所有登录用户
+	Description string   `json:"description" binding:"max=200"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+// PresenceConnectRequest 建立在线状态连接请求
+type PresenceConnectRequest struct {
+	Path      string `form:"path" binding:"max=255"` // 当前页面路径
+	ArticleID uint   `form:"article_id"`             // 正在阅读的文章
+	Ticket    string `form:"ticket"`                 // 浏览器 WebSocket 无法设置请求头，登录用户先换取一次性票据
+}
+
+// 在线状态消息类型
+const (
+	PresencePing    = "ping"    // 客户端保活
+	PresencePage    = "page"    // 客户端切换页面
+	PresenceReaders = "readers" // 服务器推送文章阅读人数
+)
+
+// PresenceMessage 在线状态连接上收发的消息
+// 客户端每 presence_interval 秒发送 ping，切换页面时发送 page；服务器定期和切换页面后发送 readers
+type PresenceMessage struct {
+	Type      string `json:"type"`
+	Path      string `json:"path,omitempty"`
+	ArticleID uint   `json:"article_id,omitempty"`
+	Count     int    `json:"count,omitempty"`
+}
+
+// ArticleReadersResponse 正在阅读文章的人数
+type ArticleReadersResponse struct {
+	ArticleID uint `json:"article_id"`
+	Count     int  `json:"count"`
+}
@@ -0,0 +1,46 @@
+package dto
+
+// PushVAPIDKeyResponse 浏览器订阅所需的 VAPID 公钥，未配置时 Enabled 为 false
+type PushVAPIDKeyResponse struct {
+	Enabled   bool   `json:"enabled"`
+	PublicKey string `json:"public_key"`
+}
+
+// PushSubscriptionKeys 浏览器 PushSubscription.toJSON() 中的密钥
+type PushSubscriptionKeys struct {
+	P256dh string `json:"p256dh" binding:"required"`
+	Auth   string `json:"auth" binding:"required"`
+}
+
+// PushSubscribeRequest 保存推送订阅请求，与 PushSubscription.toJSON() 的结构一致
+type PushSubscribeRequest struct {
+	Endpoint string               `json:"endpoint" binding:"required,max=500"`
+	Keys     PushSubscriptionKeys `json:"keys" binding:"required"`
+}
+
+// PushUnsubscribeRequest 取消推送订阅请求
+type PushUnsubscribeRequest struct {
+	Endpoint string `json:"endpoint" binding:"required"`
+}
+
+// PushSendRequest 手动推送请求，指定文章时标题、正文和链接默认取自文章
+type PushSendRequest struct {
+	ArticleID uint   `json:"article_id"`
+	Title     string `json:"title" binding:"max=100"`
+	Body      string `json:"body" binding:"max=300"`
+	URL       string `json:"url" binding:"max=500"`
+}
+
+// PushSendResponse 手动推送结果，推送在后台进行
+type PushSendResponse struct {
+	Subscribers int64 `json:"subscribers"`
+}
+
+// PushMessage 推送给浏览器的消息，由前台 Service Worker 显示为通知
+type PushMessage struct {
+	Title string `json:"title"`
+	Body  string `json:"body,omitempty"`
+	URL   string `json:"url,omitempty"`
+	Image string `json:"image,omitempty"`
+	Tag   string `json:"tag,omitempty"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+// PermissionInfo 权限点
+type PermissionInfo struct {
+	Name        string `json:"name"` // 如 article:publish
+	Description string `json:"description"`
+}
+
+// RoleRequest 创建/更新角色请求
+type RoleRequest struct {
+	Name        string   `json:"name" binding:"omitempty,min=2,max=20"` // 仅创建时使用，创建后不能修改
+	DisplayName string   `json:"display_name" binding:"required,max=50"`
+	Description string   `json:"description" binding:"max=200"`
+	Permissions []string `json:"permissions"` // 权限点，支持 * 和 article:* 通配
+}
+
+// RoleInfo 角色信息
+type RoleInfo struct {
+	ID          uint     `json:"id"`
+	Name        string   `json:"name"`
+	DisplayName string   `json:"display_name"`
+	Description string   `json:"description"`
+	Builtin     bool     `json:"builtin"`
+	Permissions []string `json:"permissions"`
+}
+
+// AssignRoleRequest 分配角色请求
+type AssignRoleRequest struct {
+	Role string `json:"role" binding:"required,max=20"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+// ReactionRequest 表情回应请求
+type ReactionRequest struct {
+	Emoji string `json:"emoji" binding:"required,max=32"`
+}
+
+// ReactionCount 某个表情的回应数
+type ReactionCount struct {
+	Emoji   string `json:"emoji"`
+	Count   int64  `json:"count"`
+	Reacted bool   `json:"reacted"` // 当前用户（游客按 IP）是否回应过
+}
+
+// ReactionToggleResponse 切换表情回应响应
+type ReactionToggleResponse struct {
+	Emoji     string          `json:"emoji"`
+	Reacted   bool            `json:"reacted"`   // 切换后是否为已回应
+	Reactions []ReactionCount `json:"reactions"` // 对象最新的回应统计
+}
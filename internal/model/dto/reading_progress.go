@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+// UpdateReadingProgressRequest 上报文章阅读进度，未传的字段保持不变，读到 100% 时自动标记为已读完
+type UpdateReadingProgressRequest struct {
+	Percent   *int  `json:"percent" binding:"omitempty,min=0,max=100"`
+	Completed *bool `json:"completed"`
+}
+
+// ArticleProgress 文章阅读进度
+type ArticleProgress struct {
+	ArticleID   uint       `json:"article_id"`
+	Percent     int        `json:"percent"`
+	Completed   bool       `json:"completed"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ReadAt      time.Time  `json:"read_at"`
+}
+
+// BookProgressResponse 笔记标签（书）的阅读进度
+type BookProgressResponse struct {
+	TagID              uint               `json:"tag_id"`
+	TagName            string             `json:"tag_name"`
+	Total              int                `json:"total"`                // 已发布的文章数
+	CompletedCount     int                `json:"completed_count"`      // 已读完的文章数
+	Percent            int                `json:"percent"`              // 整本书的完成百分比
+	ResumeArticleID    uint               `json:"resume_article_id"`    // 继续阅读的文章，全部读完时为 0
+	ResumeArticleTitle string             `json:"resume_article_title"` // 继续阅读的文章标题
+	LastReadAt         *time.Time         `json:"last_read_at"`
+	Articles           []*ArticleProgress `json:"articles,omitempty"` // 读过的文章，用于在目录中显示已读标记
+}
@@ -0,0 +1,54 @@
+package dto
+
+import "time"
+
+// RealtimeStreamRequest 订阅实时事件请求，只能查看一个页面的评论
+type RealtimeStreamRequest struct {
+	ArticleID uint   `form:"article_id"` // 正在查看的文章，推送其新评论
+	Guestbook bool   `form:"guestbook"`  // 正在查看留言板，推送新留言
+	Ticket    string `form:"ticket"`     // EventSource 无法设置请求头，登录用户先换取一次性票据放在查询参数中
+}
+
+// RealtimeTicketResponse 实时事件连接票据
+type RealtimeTicketResponse struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// RealtimeComment comment 事件：页面有新的审核通过的评论
+type RealtimeComment struct {
+	ID         uint      `json:"id"`
+	ArticleID  *uint     `json:"article_id"`
+	ParentID   *uint     `json:"parent_id"`
+	RootID     *uint     `json:"root_id"`
+	AuthorName string    `json:"author_name"`
+	Avatar     string    `json:"avatar"`
+	Excerpt    string    `json:"excerpt"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// 通知类型
+const (
+	NotificationCommentReply = "comment_reply" // 评论被回复
+	NotificationFollow       = "follow"        // 被关注
+	NotificationAnnouncement = "announcement"  // 站点公告，推送给所有连接
+)
+
+// RealtimeNotification notification 事件：当前用户收到新通知或站点发布公告，前台据此更新未读角标
+type RealtimeNotification struct {
+	Type           string    `json:"type"`
+	ActorID        uint      `json:"actor_id"`
+	ActorName      string    `json:"actor_name"`
+	Avatar         string    `json:"avatar"`
+	ArticleID      *uint     `json:"article_id,omitempty"`
+	CommentID      uint      `json:"comment_id,omitempty"`
+	Excerpt        string    `json:"excerpt,omitempty"`
+	AnnouncementID uint      `json:"announcement_id,omitempty"`
+	Severity       string    `json:"severity,omitempty"` // 公告级别
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// RealtimeOnline online 事件：在线人数
+type RealtimeOnline struct {
+	Count int64 `json:"count"`
+}
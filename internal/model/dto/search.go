@@ -0,0 +1,27 @@
+package dto
+
+// SearchRequest 全文搜索请求
+type SearchRequest struct {
+	PageRequest
+	Keyword string `form:"keyword" binding:"required,max=100"`
+}
+
+// SearchHit 搜索结果项
+type SearchHit struct {
+	ArticleListItem
+	Score     float64         `json:"score"`
+	Highlight SearchHighlight `json:"highlight"`
+}
+
+// SearchHighlight 搜索高亮片段（关键词以 <em></em> 包裹，未命中的字段为空）
+type SearchHighlight struct {
+	Title   string `json:"title,omitempty"`
+	Summary string `json:"summary,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+// ReindexResponse 重建索引响应
+type ReindexResponse struct {
+	Engine  string `json:"engine"`
+	Indexed int    `json:"indexed"`
+}
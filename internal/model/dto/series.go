@@ -0,0 +1,66 @@
+package dto
+
+import "time"
+
+// CreateSeriesRequest 创建系列请求
+type CreateSeriesRequest struct {
+	Title       string `json:"title" binding:"required,max=200"`
+	Description string `json:"description" binding:"max=500"`
+	Cover       string `json:"cover" binding:"max=255"`
+	Sort        int    `json:"sort"`
+	ArticleIDs  []uint `json:"article_ids"` // 系列中的文章，按数组顺序排列
+}
+
+// UpdateSeriesRequest 更新系列请求
+type UpdateSeriesRequest struct {
+	Title       string  `json:"title" binding:"required,max=200"`
+	Description string  `json:"description" binding:"max=500"`
+	Cover       string  `json:"cover" binding:"max=255"`
+	Sort        int     `json:"sort"`
+	ArticleIDs  *[]uint `json:"article_ids"` // 为空时不修改系列中的文章
+}
+
+// SetSeriesArticlesRequest 设置系列文章及顺序请求
+type SetSeriesArticlesRequest struct {
+	ArticleIDs []uint `json:"article_ids"`
+}
+
+// SeriesListRequest 系列列表请求
+type SeriesListRequest struct {
+	PageRequest
+	Keyword string `form:"keyword"`
+}
+
+// SeriesResponse 系列响应
+type SeriesResponse struct {
+	ID           uint                `json:"id"`
+	Title        string              `json:"title"`
+	Description  string              `json:"description"`
+	Cover        string              `json:"cover"`
+	Sort         int                 `json:"sort"`
+	ArticleCount int64               `json:"article_count"`
+	Articles     []SeriesArticleItem `json:"articles,omitempty"` // 详情接口返回
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+// SeriesArticleItem 系列中的文章
+type SeriesArticleItem struct {
+	ID        uint      `json:"id"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug"`
+	Status    int       `json:"status"`
+	Position  int       `json:"position"` // 在系列中的序号，从 1 开始
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SeriesNavigation 文章详情页的系列导航
+type SeriesNavigation struct {
+	SeriesID    uint                `json:"series_id"`
+	SeriesTitle string              `json:"series_title"`
+	Position    int                 `json:"position"` // 当前文章在系列中的序号，从 1 开始
+	Total       int                 `json:"total"`    // 系列文章总数
+	Prev        *SeriesArticleItem  `json:"prev"`     // 系列内上一篇
+	Next        *SeriesArticleItem  `json:"next"`     // 系列内下一篇
+	Articles    []SeriesArticleItem `json:"articles"` // 系列目录
+}
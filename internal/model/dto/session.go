@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+// SessionInfo 登录会话（一个登录设备）
+type SessionInfo struct {
+	ID         string    `json:"id"`
+	Device     string    `json:"device"` // 根据 User-Agent 识别的浏览器和系统，如 Chrome · Windows
+	IP         string    `json:"ip"`     // 最近一次使用的 IP
+	UserAgent  string    `json:"user_agent"`
+	Console    bool      `json:"console"` // 是否为管理后台登录
+	Current    bool      `json:"current"` // 是否为发起请求的会话
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+// SessionIDRequest 会话 ID 请求
+type SessionIDRequest struct {
+	ID string `uri:"id" binding:"required,len=32,hexadecimal"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+// SettingItem 后台设置列表中的一项，Value 为按类型解析后的当前值，未设置时为默认值
+type SettingItem struct {
+	Key         string     `json:"key"`
+	Type        string     `json:"type"`
+	Group       string     `json:"group"`
+	Label       string     `json:"label"`
+	Description string     `json:"description,omitempty"`
+	Public      bool       `json:"public"`
+	Value       any        `json:"value"`
+	Default     any        `json:"default"`
+	Customized  bool       `json:"customized"`
+	Min         *int       `json:"min,omitempty"`
+	Max         *int       `json:"max,omitempty"`     // 整数的最大值或列表的最大项数
+	Options     []string   `json:"options,omitempty"` // 选择类型的可选值
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// ThemeResponse 外观设置，前端启动时读取
+type ThemeResponse struct {
+	PrimaryColor string   `json:"primary_color"` // #rrggbb
+	DarkMode     string   `json:"dark_mode"`     // 默认配色模式：system、light、dark
+	HeroImages   []string `json:"hero_images"`   // 首页横幅图片
+	FontFamily   string   `json:"font_family"`   // 正文字体：system、sans-serif、serif、monospace、rounded
+	HeadingFont  string   `json:"heading_font"`  // 标题字体，可选值同正文字体
+	FontSize     int      `json:"font_size"`     // 正文字号（px）
+}
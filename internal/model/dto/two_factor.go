@@ -0,0 +1,36 @@
+package dto
+
+// TwoFactorChallengeRequest 登录时绑定验证器请求
+type TwoFactorChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+// TwoFactorVerifyRequest 登录时提交两步验证码请求
+type TwoFactorVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required,max=20"` // 验证器生成的 6 位验证码或恢复码
+}
+
+// TwoFactorCodeRequest 使用验证码确认操作的请求
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required,max=20"` // 验证器生成的 6 位验证码，启用后也可以使用恢复码
+}
+
+// TwoFactorSetupResponse 绑定验证器响应
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret"`      // base32 密钥，无法扫码时手动输入
+	OTPAuthURL string `json:"otpauth_url"` // otpauth:// 地址
+	QRCode     string `json:"qr_code"`     // 二维码图片，data:image/png;base64 格式
+}
+
+// TwoFactorStatusResponse 两步验证状态
+type TwoFactorStatusResponse struct {
+	Enabled           bool `json:"enabled"`
+	Required          bool `json:"required"`            // 当前角色必须启用两步验证，不能关闭
+	RecoveryCodesLeft int  `json:"recovery_codes_left"` // 剩余可用的恢复码数量
+}
+
+// RecoveryCodesResponse 恢复码响应，恢复码只在生成时显示一次
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -47,3 +47,68 @@ type UserResponse struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// UserDataExport 用户数据导出，包含系统中保存的该用户的全部数据，用于数据可携带请求
+type UserDataExport struct {
+	ExportedAt   time.Time              `json:"exported_at"`
+	Profile      UserDataProfile        `json:"profile"`
+	Comments     []*CommentExportItem   `json:"comments"`
+	CommentEdits []*UserDataCommentEdit `json:"comment_edits"` // 编辑前的评论内容
+	CommentLikes []*UserDataCommentLike `json:"comment_likes"`
+	Likes        []*UserDataArticle     `json:"likes"`
+	Favorites    []*UserDataArticle     `json:"favorites"`
+	Reactions    []*UserDataReaction    `json:"reactions"`
+	Views        []*UserDataView        `json:"views"`
+	Visits       []*UserDataVisit       `json:"visits"`
+}
+
+// UserDataProfile 用户资料
+type UserDataProfile struct {
+	UserResponse
+	Role string `json:"role"`
+}
+
+// UserDataCommentEdit 评论编辑记录
+type UserDataCommentEdit struct {
+	CommentID uint      `json:"comment_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserDataCommentLike 评论点赞记录
+type UserDataCommentLike struct {
+	CommentID uint      `json:"comment_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserDataArticle 文章点赞或收藏记录
+type UserDataArticle struct {
+	ArticleID    uint      `json:"article_id"`
+	ArticleTitle string    `json:"article_title"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// UserDataReaction 表情回应记录
+type UserDataReaction struct {
+	TargetType string    `json:"target_type"`
+	TargetID   uint      `json:"target_id"`
+	Emoji      string    `json:"emoji"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// UserDataView 文章浏览记录
+type UserDataView struct {
+	ArticleID uint      `json:"article_id"`
+	IP        string    `json:"ip"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserDataVisit 页面访问记录
+type UserDataVisit struct {
+	Path      string    `json:"path"`
+	Duration  int       `json:"duration"` // 停留时长（秒）
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	Referrer  string    `json:"referrer"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+// SocialLink 社交链接
+type SocialLink struct {
+	Platform string `json:"platform" binding:"required,max=30"` // 平台名称，如 github、weibo、twitter
+	URL      string `json:"url" binding:"required,url,max=500"`
+}
+
+// UpdatePublicProfileRequest 更新主页资料请求，未传的字段保持不变，传空值清空
+type UpdatePublicProfileRequest struct {
+	Nickname    *string       `json:"nickname" binding:"omitempty,max=50"`
+	Bio         *string       `json:"bio" binding:"omitempty,max=500"`
+	Website     *string       `json:"website" binding:"omitempty,url,max=255"`
+	SocialLinks *[]SocialLink `json:"social_links" binding:"omitempty,max=10,dive"`
+}
+
+// PublicProfileStats 用户主页统计
+type PublicProfileStats struct {
+	ArticleCount int64 `json:"article_count"` // 已发布的文章数（含共同署名）
+	ViewCount    int64 `json:"view_count"`
+	LikeCount    int64 `json:"like_count"`
+	CommentCount int64 `json:"comment_count"`
+	// 关注
+	FollowerCount  int64 `json:"follower_count"`
+	FollowingCount int64 `json:"following_count"`
+}
+
+// PublicProfileResponse 用户公开主页
+type PublicProfileResponse struct {
+	ID          uint               `json:"id"`
+	Username    string             `json:"username"`
+	Nickname    string             `json:"nickname"`
+	Avatar      string             `json:"avatar"`
+	Bio         string             `json:"bio"`
+	Website     string             `json:"website"`
+	SocialLinks []SocialLink       `json:"social_links"`
+	Skills      string             `json:"skills"`
+	JoinedAt    time.Time          `json:"joined_at"`
+	Stats       PublicProfileStats `json:"stats"`
+	Articles    *PageResponse      `json:"articles,omitempty"` // 已发布的文章，按发布时间倒序
+}
@@ -0,0 +1,100 @@
+package dto
+
+import "time"
+
+// CreateWebhookRequest 创建 Webhook 请求
+type CreateWebhookRequest struct {
+	Name    string   `json:"name" binding:"required,max=100"`
+	URL     string   `json:"url" binding:"required,url,max=500"`
+	Secret  string   `json:"secret" binding:"omitempty,min=16,max=128"` // 为空时自动生成
+	Events  []string `json:"events" binding:"required,min=1"`
+	Enabled *bool    `json:"enabled"` // 默认启用
+}
+
+// UpdateWebhookRequest 更新 Webhook 请求
+type UpdateWebhookRequest struct {
+	Name    string   `json:"name" binding:"required,max=100"`
+	URL     string   `json:"url" binding:"required,url,max=500"`
+	Secret  string   `json:"secret" binding:"omitempty,min=16,max=128"` // 为空时不修改
+	Events  []string `json:"events" binding:"required,min=1"`
+	Enabled *bool    `json:"enabled"` // 为空时不修改
+}
+
+// WebhookResponse Webhook 响应
+type WebhookResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"` // 仅创建和更换密钥时返回
+	Events    []string  `json:"events"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookDeliveryListRequest 投递记录列表请求
+type WebhookDeliveryListRequest struct {
+	PageRequest
+	Status string `form:"status" binding:"omitempty,oneof=pending success failed"`
+}
+
+// WebhookDeliveryResponse 投递记录响应
+type WebhookDeliveryResponse struct {
+	ID             uint       `json:"id"`
+	WebhookID      uint       `json:"webhook_id"`
+	Event          string     `json:"event"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	ResponseStatus int        `json:"response_status"`
+	ResponseBody   string     `json:"response_body"`
+	Error          string     `json:"error"`
+	Duration       int64      `json:"duration"` // 毫秒
+	RequestID      string     `json:"request_id"`
+	NextRetryAt    *time.Time `json:"next_retry_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// WebhookPayload 投递的请求体
+type WebhookPayload struct {
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// WebhookArticle 文章事件数据
+type WebhookArticle struct {
+	ID         uint      `json:"id"`
+	Title      string    `json:"title"`
+	Slug       string    `json:"slug"`
+	Summary    string    `json:"summary"`
+	Status     int       `json:"status"`
+	Visibility string    `json:"visibility"`
+	CategoryID uint      `json:"category_id"`
+	AuthorID   uint      `json:"author_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// WebhookComment 评论事件数据
+type WebhookComment struct {
+	ID        uint      `json:"id"`
+	ArticleID *uint     `json:"article_id"` // 为空表示留言板消息
+	UserID    uint      `json:"user_id"`
+	ParentID  *uint     `json:"parent_id"`
+	Content   string    `json:"content"`
+	Status    int       `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookLogin 登录事件数据
+type WebhookLogin struct {
+	UserID    uint      `json:"user_id"`
+	Username  string    `json:"username"`
+	Channel   string    `json:"channel"`
+	IP        string    `json:"ip"`
+	Device    string    `json:"device"`
+	Location  string    `json:"location"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,46 @@
+package dto
+
+import "time"
+
+// CreateYuqueSyncRequest 创建语雀同步请求
+type CreateYuqueSyncRequest struct {
+	Namespace   string `json:"namespace" binding:"required,max=200"` // 知识库路径，如 group/book
+	Token       string `json:"token" binding:"required,max=200"`
+	CategoryID  uint   `json:"category_id"` // 新建文章的分类，为空时使用默认分类
+	AutoPublish bool   `json:"auto_publish"`
+	Enabled     *bool  `json:"enabled"` // 是否参与定时同步，默认启用
+}
+
+// UpdateYuqueSyncRequest 更新语雀同步请求
+type UpdateYuqueSyncRequest struct {
+	Token       string `json:"token" binding:"max=200"` // 为空时不修改
+	CategoryID  uint   `json:"category_id"`             // 为空时不修改
+	AutoPublish *bool  `json:"auto_publish"`
+	Enabled     *bool  `json:"enabled"`
+}
+
+// YuqueSyncResponse 语雀同步响应
+type YuqueSyncResponse struct {
+	ID           uint       `json:"id"`
+	Namespace    string     `json:"namespace"`
+	Name         string     `json:"name"`
+	TagID        uint       `json:"tag_id"`
+	TagName      string     `json:"tag_name"`
+	CategoryID   uint       `json:"category_id"`
+	AutoPublish  bool       `json:"auto_publish"`
+	Enabled      bool       `json:"enabled"`
+	LastSyncedAt *time.Time `json:"last_synced_at"`
+	LastError    string     `json:"last_error"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// YuqueSyncReport 同步结果
+type YuqueSyncReport struct {
+	Created  int      `json:"created"`  // 新建文章数
+	Updated  int      `json:"updated"`  // 正文有更新的文章数
+	Skipped  int      `json:"skipped"`  // 未变更或本地已删除的文档数
+	Failed   int      `json:"failed"`   // 同步失败的文档数
+	Chapters int      `json:"chapters"` // 新建章节数
+	Errors   []string `json:"errors,omitempty"`
+}
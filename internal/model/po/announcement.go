@@ -0,0 +1,36 @@
+package po
+
+import "time"
+
+// 公告级别，前台据此选择样式
+const (
+	AnnouncementInfo     = "info"
+	AnnouncementSuccess  = "success"
+	AnnouncementWarning  = "warning"
+	AnnouncementCritical = "critical"
+)
+
+// 公告展示状态，由启用开关和展示时间段共同决定
+const (
+	AnnouncementStatusActive    = "active"    // 正在展示
+	AnnouncementStatusScheduled = "scheduled" // 已启用，尚未到开始时间
+	AnnouncementStatusExpired   = "expired"   // 已过结束时间
+	AnnouncementStatusDisabled  = "disabled"  // 未启用
+)
+
+// Announcement 站点公告，在启用且处于展示时间段内时显示在前台顶部
+type Announcement struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	Title       string     `gorm:"size:100;not null" json:"title"`
+	Content     string     `gorm:"size:1000" json:"content"`
+	Severity    string     `gorm:"size:20;not null" json:"severity"`
+	Link        string     `gorm:"size:500" json:"link"`        // 查看详情的地址，为空时不显示
+	Dismissible bool       `gorm:"not null" json:"dismissible"` // 访客能否关闭，关闭记录保存在浏览器中
+	Enabled     bool       `gorm:"not null;index" json:"enabled"`
+	StartAt     *time.Time `gorm:"index" json:"start_at"`  // 开始展示时间，为空时立即展示
+	EndAt       *time.Time `gorm:"index" json:"end_at"`    // 结束展示时间，为空时一直展示
+	Notify      bool       `gorm:"not null" json:"notify"` // 开始展示时推送到通知中心
+	NotifiedAt  *time.Time `json:"notified_at"`            // 已推送的时间，每条公告只推送一次
+	CreatedAt   time.Time  `gorm:"index" json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
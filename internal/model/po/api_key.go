@@ -0,0 +1,19 @@
+package po
+
+import "time"
+
+// APIKey 个人访问令牌，脚本（如 CI 发布文章）通过 X-API-Key 请求头调用管理接口，不需要用户密码
+// 只保存令牌摘要，权限范围之外还受所属用户当前角色的路由权限限制
+type APIKey struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	UserID     uint       `gorm:"index;not null" json:"user_id"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	Prefix     string     `gorm:"size:20;not null" json:"prefix"`        // 令牌的前几位，用于在列表中识别
+	KeyHash    string     `gorm:"size:64;not null;uniqueIndex" json:"-"` // 令牌的 SHA-256 摘要
+	Scopes     string     `gorm:"size:500;not null" json:"scopes"`       // 权限范围，逗号分隔，如 articles:write,files:write，* 表示全部
+	ExpiresAt  *time.Time `json:"expires_at"`                            // 为空表示永不过期
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
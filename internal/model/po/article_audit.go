@@ -0,0 +1,24 @@
+package po
+
+import "time"
+
+// 文章审计操作类型
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionStatus = "status"
+	AuditActionDelete = "delete"
+)
+
+// ArticleAuditLog 文章操作审计日志，记录谁在何时修改了哪些字段
+// 文章彻底删除后日志保留，ArticleTitle 为操作时的标题快照
+type ArticleAuditLog struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	ArticleID    uint      `gorm:"index;not null" json:"article_id"`
+	ArticleTitle string    `gorm:"size:200" json:"article_title"`
+	OperatorID   uint      `gorm:"index" json:"operator_id"` // 0 表示系统任务
+	Action       string    `gorm:"size:20;index" json:"action"`
+	Changes      string    `gorm:"type:text" json:"changes"` // 字段级变更，JSON 数组
+	CreatedAt    time.Time `gorm:"index" json:"created_at"`
+	Operator     *User     `gorm:"foreignKey:OperatorID" json:"operator,omitempty"`
+}
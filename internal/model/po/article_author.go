@@ -0,0 +1,24 @@
+package po
+
+import "time"
+
+// 文章作者角色
+const (
+	ArticleAuthorRoleAuthor      = "author"      // 共同作者
+	ArticleAuthorRoleContributor = "contributor" // 贡献者
+)
+
+// ArticleAuthor 文章的共同作者，articles.author_id 为主作者，不在此表中重复记录
+type ArticleAuthor struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	ArticleID uint      `gorm:"uniqueIndex:idx_article_author;not null" json:"article_id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_article_author;index;not null" json:"user_id"`
+	Role      string    `gorm:"size:20;default:author" json:"role"` // author, contributor
+	Sort      int       `gorm:"default:0" json:"sort"`              // 署名顺序，从 0 开始
+	CreatedAt time.Time `json:"created_at"`
+	User      *User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (ArticleAuthor) TableName() string {
+	return "article_authors"
+}
@@ -0,0 +1,15 @@
+package po
+
+import "time"
+
+// ArticleDraft 文章自动保存草稿（编辑器定时写入，与正式内容分开存储，每个用户每篇文章仅保留一份）
+type ArticleDraft struct {
+	ID              uint      `gorm:"primarykey" json:"id"`
+	ArticleID       uint      `gorm:"uniqueIndex:idx_draft_article_user;not null" json:"article_id"`
+	UserID          uint      `gorm:"uniqueIndex:idx_draft_article_user;not null" json:"user_id"`
+	Title           string    `gorm:"size:200" json:"title"`
+	Summary         string    `gorm:"size:500" json:"summary"`
+	ContentMarkdown string    `gorm:"type:longtext" json:"content_markdown"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
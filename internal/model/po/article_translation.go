@@ -0,0 +1,18 @@
+package po
+
+import "time"
+
+// ArticleTranslation 文章的语言版本，同一翻译组内的文章互为译文
+// 翻译组以源文章 ID 标识，源文章自身也在组内（article_id = group_id），未加入翻译组的文章视为站点默认语言
+type ArticleTranslation struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	GroupID   uint      `gorm:"uniqueIndex:idx_translation_language;not null" json:"group_id"`
+	ArticleID uint      `gorm:"uniqueIndex;not null" json:"article_id"`
+	Language  string    `gorm:"size:20;uniqueIndex:idx_translation_language;not null" json:"language"` // 语言代码，如 zh-CN、en
+	CreatedAt time.Time `json:"created_at"`
+	Article   *Article  `gorm:"foreignKey:ArticleID" json:"article,omitempty"`
+}
+
+func (ArticleTranslation) TableName() string {
+	return "article_translations"
+}
@@ -0,0 +1,15 @@
+package po
+
+import "time"
+
+// ArticleVersion 文章历史版本（每次更新前保存旧内容）
+type ArticleVersion struct {
+	ID              uint      `gorm:"primarykey" json:"id"`
+	ArticleID       uint      `gorm:"index;not null" json:"article_id"`
+	Version         int       `gorm:"not null" json:"version"` // 版本号，同一文章内递增
+	Title           string    `gorm:"size:200" json:"title"`
+	ContentMarkdown string    `gorm:"type:longtext" json:"content_markdown"`
+	ContentHTML     string    `gorm:"type:longtext" json:"content_html"`
+	Summary         string    `gorm:"size:500" json:"summary"`
+	CreatedAt       time.Time `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package po
+
+import "time"
+
+// Attachment 附件：PDF、压缩包等任意文件，可关联到文章供读者下载
+type Attachment struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	Name          string    `gorm:"size:200;not null" json:"name"` // 原始文件名，下载时使用
+	URL           string    `gorm:"size:500;not null" json:"url"`
+	Size          int64     `json:"size"`
+	MimeType      string    `gorm:"size:100" json:"mime_type"`
+	Description   string    `gorm:"size:500" json:"description"`
+	ArticleID     *uint     `gorm:"index" json:"article_id"` // 关联的文章，为空表示未关联
+	UploaderID    uint      `gorm:"index" json:"uploader_id"`
+	DownloadCount int64     `gorm:"default:0" json:"download_count"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package po
+
+import "time"
+
+// 横幅展示状态，由启用开关和展示时间段共同决定
+const (
+	BannerStatusActive    = "active"    // 正在展示
+	BannerStatusScheduled = "scheduled" // 已启用，尚未到开始时间
+	BannerStatusExpired   = "expired"   // 已过结束时间
+	BannerStatusDisabled  = "disabled"  // 未启用
+)
+
+// Banner 首页横幅（轮播图），在启用且处于展示时间段内时显示
+type Banner struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	Title     string     `gorm:"size:100" json:"title"`
+	Subtitle  string     `gorm:"size:200" json:"subtitle"`
+	Image     string     `gorm:"size:500;not null" json:"image"` // 通过文件上传接口上传的图片地址
+	Link      string     `gorm:"size:500" json:"link"`           // 点击跳转地址，为空时不可点击
+	NewTab    bool       `gorm:"not null" json:"new_tab"`
+	Enabled   bool       `gorm:"not null;index" json:"enabled"`
+	Sort      int        `gorm:"default:0;index" json:"sort"` // 前台按 sort 从小到大排列
+	StartAt   *time.Time `gorm:"index" json:"start_at"`       // 开始展示时间，为空时立即展示
+	EndAt     *time.Time `gorm:"index" json:"end_at"`         // 结束展示时间，为空时一直展示
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,30 @@
+package po
+
+import "time"
+
+// 评论屏蔽规则类型
+const (
+	CommentBlockIP      = "ip"      // IP 或 CIDR 网段
+	CommentBlockEmail   = "email"   // 邮箱地址或域名
+	CommentBlockKeyword = "keyword" // 内容关键词，不区分大小写
+)
+
+// 评论屏蔽规则命中后的处理方式
+const (
+	CommentBlockReject = "reject" // 拒绝发表
+	CommentBlockSpam   = "spam"   // 保存为垃圾评论，不公开显示
+)
+
+// CommentBlockRule 评论屏蔽规则，由管理员维护，发表评论时匹配
+type CommentBlockRule struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	Type      string     `gorm:"size:20;not null;index" json:"type"` // ip, email, keyword
+	Value     string     `gorm:"size:200;not null" json:"value"`
+	Action    string     `gorm:"size:20;not null" json:"action"` // reject, spam
+	Note      string     `gorm:"size:200" json:"note"`
+	Enabled   bool       `json:"enabled"`
+	HitCount  int64      `gorm:"default:0" json:"hit_count"`
+	LastHitAt *time.Time `json:"last_hit_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,14 @@
+package po
+
+import "time"
+
+// CommentEdit 评论编辑记录，保存每次编辑前的内容，仅管理员可见
+type CommentEdit struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CommentID uint      `gorm:"index;not null" json:"comment_id"`
+	EditorID  uint      `gorm:"index" json:"editor_id"`
+	Content   string    `gorm:"type:text;not null" json:"content"` // 编辑前的原始文本
+	CreatedAt time.Time `json:"created_at"`                        // 编辑时间
+
+	Editor User `gorm:"foreignKey:EditorID" json:"editor,omitempty"`
+}
@@ -0,0 +1,130 @@
+package po
+
+import (
+	"github.com/ydcloud-dy/leaf-api/pkg/encrypt"
+	"gorm.io/gorm"
+)
+
+// 敏感字段加解密钩子：写入前加密并计算盲索引，读取后解密
+// 查询敏感字段时需使用对应的 *_hash 列，例如 email_hash = encrypt.BlindIndex(email)
+
+// BeforeSave 保存前加密邮箱
+func (u *User) BeforeSave(tx *gorm.DB) error {
+	plain, err := encrypt.Decrypt(u.Email)
+	if err != nil {
+		return err
+	}
+	u.EmailHash = emailHash(plain)
+	return sealValue(&u.Email)
+}
+
+// AfterSave 保存后还原明文，避免调用方拿到密文
+func (u *User) AfterSave(tx *gorm.DB) error {
+	return openField(&u.Email)
+}
+
+// AfterFind 查询后解密邮箱
+func (u *User) AfterFind(tx *gorm.DB) error {
+	return openField(&u.Email)
+}
+
+// BeforeSave 保存前加密 IP
+func (v *PageVisit) BeforeSave(tx *gorm.DB) error {
+	return sealField(&v.IP, &v.IPHash)
+}
+
+// AfterSave 保存后还原明文
+func (v *PageVisit) AfterSave(tx *gorm.DB) error {
+	return openField(&v.IP)
+}
+
+// AfterFind 查询后解密 IP
+func (v *PageVisit) AfterFind(tx *gorm.DB) error {
+	return openField(&v.IP)
+}
+
+// BeforeSave 保存前加密签名密钥
+func (w *Webhook) BeforeSave(tx *gorm.DB) error {
+	return sealValue(&w.Secret)
+}
+
+// AfterSave 保存后还原明文
+func (w *Webhook) AfterSave(tx *gorm.DB) error {
+	return openField(&w.Secret)
+}
+
+// AfterFind 查询后解密签名密钥
+func (w *Webhook) AfterFind(tx *gorm.DB) error {
+	return openField(&w.Secret)
+}
+
+// BeforeSave 保存前加密语雀 Token
+func (y *YuqueSync) BeforeSave(tx *gorm.DB) error {
+	return sealValue(&y.Token)
+}
+
+// AfterSave 保存后还原明文
+func (y *YuqueSync) AfterSave(tx *gorm.DB) error {
+	return openField(&y.Token)
+}
+
+// AfterFind 查询后解密语雀 Token
+func (y *YuqueSync) AfterFind(tx *gorm.DB) error {
+	return openField(&y.Token)
+}
+
+// BeforeSave 保存前加密 TOTP 密钥
+func (t *UserTwoFactor) BeforeSave(tx *gorm.DB) error {
+	return sealValue(&t.Secret)
+}
+
+// AfterSave 保存后还原明文
+func (t *UserTwoFactor) AfterSave(tx *gorm.DB) error {
+	return openField(&t.Secret)
+}
+
+// AfterFind 查询后解密 TOTP 密钥
+func (t *UserTwoFactor) AfterFind(tx *gorm.DB) error {
+	return openField(&t.Secret)
+}
+
+// sealField 加密字段并更新盲索引
+func sealField(value, hash *string) error {
+	plain, err := encrypt.Decrypt(*value)
+	if err != nil {
+		return err
+	}
+	*hash = encrypt.BlindIndex(plain)
+
+	sealed, err := encrypt.Encrypt(plain)
+	if err != nil {
+		return err
+	}
+	*value = sealed
+	return nil
+}
+
+// sealValue 加密不需要按值查询的字段
+func sealValue(value *string) error {
+	plain, err := encrypt.Decrypt(*value)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := encrypt.Encrypt(plain)
+	if err != nil {
+		return err
+	}
+	*value = sealed
+	return nil
+}
+
+// openField 解密字段
+func openField(value *string) error {
+	plain, err := encrypt.Decrypt(*value)
+	if err != nil {
+		return err
+	}
+	*value = plain
+	return nil
+}
@@ -0,0 +1,13 @@
+package po
+
+import "time"
+
+// FavoriteFolder 用户的收藏夹，未归入收藏夹的收藏在默认收藏夹中
+type FavoriteFolder struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	UserID      uint      `gorm:"uniqueIndex:idx_favorite_folder,priority:1;not null" json:"user_id"`
+	Name        string    `gorm:"uniqueIndex:idx_favorite_folder,priority:2;size:50;not null" json:"name"`
+	Description string    `gorm:"size:200" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,13 @@
+package po
+
+import "time"
+
+// Follow 用户关注关系，FollowerID 关注了 FolloweeID
+type Follow struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	FollowerID uint      `gorm:"uniqueIndex:idx_follow,priority:1;not null" json:"follower_id"`
+	FolloweeID uint      `gorm:"uniqueIndex:idx_follow,priority:2;index;not null" json:"followee_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	Follower   *User     `gorm:"foreignKey:FollowerID" json:"follower,omitempty"`
+	Followee   *User     `gorm:"foreignKey:FolloweeID" json:"followee,omitempty"`
+}
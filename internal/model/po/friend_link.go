@@ -0,0 +1,31 @@
+package po
+
+import "time"
+
+// 友情链接状态
+const (
+	FriendLinkPending  = "pending"  // 访客申请，等待审核
+	FriendLinkApproved = "approved" // 审核通过，在前台显示
+	FriendLinkRejected = "rejected" // 审核未通过
+)
+
+// FriendLink 友情链接，访客申请后由管理员审核，审核通过的链接定期检测是否可访问
+type FriendLink struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	Name        string     `gorm:"size:50;not null" json:"name"`
+	URL         string     `gorm:"size:255;uniqueIndex;not null" json:"url"`
+	Logo        string     `gorm:"size:500" json:"logo"`
+	Description string     `gorm:"size:200" json:"description"`
+	Email       string     `gorm:"size:100" json:"email"` // 申请人联系邮箱，不在前台显示
+	Status      string     `gorm:"size:20;not null;index" json:"status"`
+	Reason      string     `gorm:"size:200" json:"reason"`      // 审核未通过的原因
+	Sort        int        `gorm:"default:0;index" json:"sort"` // 前台按 sort 从小到大排列
+	UserID      *uint      `gorm:"index" json:"user_id"`        // 申请时已登录的用户
+	IP          string     `gorm:"size:45;index" json:"ip"`     // 申请人 IP，管理员添加的链接为空
+	CheckStatus int        `json:"check_status"`                // 最近一次检测的 HTTP 状态码，请求失败时为 0
+	CheckError  string     `gorm:"size:200" json:"check_error"`
+	FailCount   int        `gorm:"default:0" json:"fail_count"` // 连续检测失败次数，成功后清零
+	CheckedAt   *time.Time `json:"checked_at"`
+	CreatedAt   time.Time  `gorm:"index" json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
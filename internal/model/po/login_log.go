@@ -0,0 +1,26 @@
+package po
+
+import "time"
+
+// 登录渠道
+const (
+	LoginChannelAdmin = "admin" // 管理后台（含两步验证）
+	LoginChannelBlog  = "blog"  // 博客前台
+	LoginChannelOAuth = "oauth" // 第三方登录
+)
+
+// LoginLog 登录日志，记录每次登录尝试
+type LoginLog struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	UserID      uint      `gorm:"index" json:"user_id"` // 用户名不存在时为 0
+	Username    string    `gorm:"size:50;index" json:"username"`
+	Channel     string    `gorm:"size:20" json:"channel"`
+	Success     bool      `json:"success"`
+	Reason      string    `gorm:"size:100" json:"reason"` // 失败原因
+	IP          string    `gorm:"size:50" json:"ip"`
+	UserAgent   string    `gorm:"size:500" json:"user_agent"`
+	Device      string    `gorm:"size:100" json:"device"`
+	Location    string    `gorm:"size:100" json:"location"`
+	NewLocation bool      `json:"new_location"` // 首次在该所在地登录成功
+	CreatedAt   time.Time `gorm:"index" json:"created_at"`
+}
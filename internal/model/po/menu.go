@@ -0,0 +1,34 @@
+package po
+
+import "time"
+
+// 导航菜单位置
+const (
+	MenuLocationHeader = "header" // 顶部导航
+	MenuLocationFooter = "footer" // 页脚导航
+)
+
+// 菜单项链接类型
+const (
+	MenuTypeCategory = "category" // 分类页
+	MenuTypeTag      = "tag"      // 标签页
+	MenuTypePage     = "page"     // 独立页面
+	MenuTypeLink     = "link"     // 站内路径或外部地址
+)
+
+// MenuItem 导航菜单项，通过 ParentID 组成多级菜单，同级按排序值排列
+type MenuItem struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Location  string    `gorm:"size:20;not null;index" json:"location"`
+	ParentID  *uint     `gorm:"index" json:"parent_id"` // 为空表示顶级菜单
+	Title     string    `gorm:"size:50;not null" json:"title"`
+	Type      string    `gorm:"size:20;not null" json:"type"`
+	TargetID  *uint     `json:"target_id"`           // 分类、标签或页面 ID，type 为 link 时为空
+	URL       string    `gorm:"size:500" json:"url"` // type 为 link 时的地址
+	Icon      string    `gorm:"size:100" json:"icon"`
+	NewTab    bool      `gorm:"default:false" json:"new_tab"` // 是否在新标签页打开
+	Visible   bool      `gorm:"not null" json:"visible"`      // 隐藏后连同子菜单不在前台显示
+	Sort      int       `gorm:"default:0" json:"sort"`        // 排序值，越小越靠前
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
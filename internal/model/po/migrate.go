@@ -0,0 +1,171 @@
+package po
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/pkg/encrypt"
+	"gorm.io/gorm"
+)
+
+// emailHashBatchSize 回填邮箱盲索引时每批处理的行数
+const emailHashBatchSize = 500
+
+// migrateEmailHash 将邮箱唯一约束从 email 列迁移到 email_hash 列
+// 启用字段加密后 email 列存储的密文每次都不同，唯一性改由盲索引保证。
+// 迁移前回填历史数据的盲索引（空邮箱置为 NULL），删除旧的 email 唯一索引和 email_hash 普通索引，
+// 唯一索引随后由 AutoMigrate 创建；存在重复邮箱时中止迁移，需先人工合并账号
+func migrateEmailHash(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasTable(&User{}) {
+		return nil
+	}
+	if !m.HasColumn(&User{}, "EmailHash") {
+		if err := m.AddColumn(&User{}, "EmailHash"); err != nil {
+			return fmt.Errorf("add users.email_hash: %w", err)
+		}
+	}
+
+	indexes, err := m.GetIndexes(&User{})
+	if err != nil {
+		return fmt.Errorf("list users indexes: %w", err)
+	}
+	var dropIndexes []string
+	for _, idx := range indexes {
+		switch idx.Name() {
+		case "idx_users_email":
+			dropIndexes = append(dropIndexes, idx.Name())
+		case "idx_users_email_hash":
+			if unique, ok := idx.Unique(); ok && unique {
+				return nil
+			}
+			dropIndexes = append(dropIndexes, idx.Name())
+		}
+	}
+
+	if err := backfillEmailHash(db); err != nil {
+		return err
+	}
+	if err := checkDuplicateEmails(db); err != nil {
+		return err
+	}
+	for _, name := range dropIndexes {
+		if err := m.DropIndex(&User{}, name); err != nil {
+			return fmt.Errorf("drop index %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// emailRow 回填盲索引时读取的用户邮箱
+type emailRow struct {
+	ID    uint
+	Email string
+}
+
+// backfillEmailHash 按主键分批重新计算所有用户（含已删除）的邮箱盲索引
+func backfillEmailHash(db *gorm.DB) error {
+	var lastID uint
+	for {
+		var rows []emailRow
+		err := db.Table("users").
+			Select("id, email").
+			Where("id > ?", lastID).
+			Order("id ASC").
+			Limit(emailHashBatchSize).
+			Scan(&rows).Error
+		if err != nil {
+			return fmt.Errorf("load users: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		lastID = rows[len(rows)-1].ID
+
+		for _, r := range rows {
+			plain, err := encrypt.Decrypt(r.Email)
+			if err != nil {
+				return fmt.Errorf("decrypt email of user %d: %w", r.ID, err)
+			}
+			if err := db.Table("users").Where("id = ?", r.ID).
+				UpdateColumn("email_hash", emailHash(plain)).Error; err != nil {
+				return fmt.Errorf("update email_hash of user %d: %w", r.ID, err)
+			}
+		}
+	}
+}
+
+// checkDuplicateEmails 检查是否存在邮箱相同的账号
+func checkDuplicateEmails(db *gorm.DB) error {
+	var duplicates []struct {
+		EmailHash string
+		IDs       string
+	}
+	err := db.Table("users").
+		Select("email_hash, GROUP_CONCAT(id) AS ids").
+		Where("email_hash IS NOT NULL").
+		Group("email_hash").
+		Having("COUNT(*) > 1").
+		Scan(&duplicates).Error
+	if err != nil {
+		return fmt.Errorf("check duplicate emails: %w", err)
+	}
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	groups := make([]string, 0, len(duplicates))
+	for _, d := range duplicates {
+		groups = append(groups, "["+d.IDs+"]")
+	}
+	return fmt.Errorf("users with duplicate emails must be merged before adding the unique index on email_hash, user ids: %s",
+		strings.Join(groups, " "))
+}
+
+// emailHash 邮箱盲索引，空邮箱返回 nil，唯一索引不限制多个未填写邮箱的账号
+func emailHash(email string) *string {
+	hash := encrypt.BlindIndex(email)
+	if hash == "" {
+		return nil
+	}
+	return &hash
+}
+
+// dedupeFavorites 删除重复的收藏记录，为 (user_id, article_id) 唯一索引做准备
+// 每个用户对同一文章只保留最早的一条收藏，并按剩余记录重新计算受影响文章的收藏数；唯一索引已存在时跳过
+func dedupeFavorites(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasTable(&Favorite{}) || m.HasIndex(&Favorite{}, "idx_favorite_user_article") {
+		return nil
+	}
+
+	var articleIDs []uint
+	err := db.Table("favorites").
+		Select("DISTINCT article_id").
+		Group("user_id, article_id").
+		Having("COUNT(*) > 1").
+		Pluck("article_id", &articleIDs).Error
+	if err != nil {
+		return fmt.Errorf("check duplicate favorites: %w", err)
+	}
+	if len(articleIDs) == 0 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 子查询包一层派生表，MySQL 不允许在 DELETE 的子查询中直接引用目标表
+		keep := tx.Table("(?) AS keep_favorites",
+			tx.Table("favorites").Select("MIN(id) AS id").Group("user_id, article_id")).Select("id")
+		if err := tx.Where("id NOT IN (?)", keep).Delete(&Favorite{}).Error; err != nil {
+			return fmt.Errorf("delete duplicate favorites: %w", err)
+		}
+		err := tx.Model(&Article{}).Unscoped().
+			Where("id IN ?", articleIDs).
+			UpdateColumn("favorite_count",
+				tx.Table("favorites").Select("COUNT(*)").Where("favorites.article_id = articles.id")).Error
+		if err != nil {
+			return fmt.Errorf("recount favorites: %w", err)
+		}
+		return nil
+	})
+}
@@ -28,25 +28,36 @@ type Admin struct {
 type User struct {
 	ID        uint           `gorm:"primarykey" json:"id"`
 	Username  string         `gorm:"size:50;uniqueIndex;not null" json:"username"`
-	Email     string         `gorm:"size:100;uniqueIndex" json:"email"`
+	Email     string         `gorm:"size:255" json:"email"`             // 启用字段加密时存储密文
+	EmailHash *string        `gorm:"size:128;uniqueIndex" json:"-"`     // 邮箱盲索引，用于等值查询和唯一约束，空邮箱为 NULL
 	Password  string         `gorm:"size:255;not null" json:"-"`
 	Nickname  string         `gorm:"size:50" json:"nickname"`
 	Avatar    string         `gorm:"size:500" json:"avatar"`
 	Bio       string         `gorm:"size:500" json:"bio"`
 	Skills    string         `gorm:"type:text" json:"skills"`     // JSON数组格式的技术栈
 	Contacts  string         `gorm:"type:text" json:"contacts"`   // JSON对象格式的联系方式
+	Website     string       `gorm:"size:255" json:"website"`        // 个人网站
+	SocialLinks string       `gorm:"type:text" json:"social_links"`  // JSON数组格式的社交链接，见 dto.SocialLink
 	Role      string         `gorm:"size:20;default:'user'" json:"role"` // user, admin, super_admin
 	IsBlogger bool           `gorm:"default:false" json:"is_blogger"`    // 是否为博主（用于关于页面展示）
-	Status    int            `gorm:"default:1" json:"status"`            // 1: active, 0: banned
+	Status    int            `gorm:"default:1" json:"status"`            // 1: active, 0: banned, 2: email unverified
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
+// 前台用户状态
+const (
+	UserStatusBanned     = 0 // 已禁用
+	UserStatusActive     = 1 // 正常
+	UserStatusUnverified = 2 // 邮箱未验证，验证后才能登录
+)
+
 // Article 文章模型
 type Article struct {
 	ID              uint           `gorm:"primarykey" json:"id"`
 	Title           string         `gorm:"size:200;not null" json:"title"`
+	Slug            *string        `gorm:"size:191;uniqueIndex" json:"slug"` // URL 别名，旧数据为空时由后台任务补全
 	ContentMarkdown string         `gorm:"type:longtext" json:"content_markdown"`
 	ContentHTML     string         `gorm:"type:longtext" json:"content_html"`
 	Summary         string         `gorm:"size:500" json:"summary"`
@@ -55,20 +66,39 @@ type Article struct {
 	CategoryID      uint           `gorm:"index" json:"category_id"`
 	ChapterID       *uint          `gorm:"index" json:"chapter_id"` // 所属章节ID,可为空
 	Status          int            `gorm:"default:0" json:"status"` // 0: draft, 1: published, 2: offline
+	IsTop           bool           `gorm:"default:false;index" json:"is_top"` // 是否置顶
+	PinnedSort      int            `gorm:"default:0" json:"pinned_sort"` // 置顶排序，越大越靠前
+	Visibility      string         `gorm:"size:20;default:public;index" json:"visibility"` // public, private, password
+	AccessPassword  string         `gorm:"size:255" json:"-"` // 访问密码（bcrypt），仅 visibility=password 时有效
+	WordCount       int            `gorm:"default:0" json:"word_count"` // 字数（中文按字、英文按单词）
+	ReadingTime     int            `gorm:"default:0" json:"reading_time"` // 预计阅读分钟数
+	Revision        int            `gorm:"default:0" json:"revision"` // 修订号，每次更新加 1，用于拒绝过期的保存
+	MetaDescription string         `gorm:"size:300" json:"meta_description"` // SEO 描述
+	MetaKeywords    string         `gorm:"size:255" json:"meta_keywords"` // SEO 关键词，逗号分隔
+	CanonicalURL    string         `gorm:"size:500" json:"canonical_url"` // 规范链接
+	NoIndex         bool           `gorm:"default:false" json:"noindex"` // 禁止搜索引擎收录
 	ViewCount       int            `gorm:"default:0" json:"view_count"`
 	LikeCount       int            `gorm:"default:0" json:"like_count"`
 	FavoriteCount   int            `gorm:"default:0" json:"favorite_count"`
 	CommentCount    int            `gorm:"default:0" json:"comment_count"`
-	CreatedAt       time.Time      `json:"created_at"`
+	CreatedAt       time.Time      `gorm:"index" json:"created_at"` // 游标分页按 created_at+id 排序
 	UpdatedAt       time.Time      `json:"updated_at"`
 	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
 
-	Author   User      `gorm:"foreignKey:AuthorID;references:ID;constraint:OnDelete:SET NULL" json:"author,omitempty"`
-	Category Category  `gorm:"foreignKey:CategoryID;references:ID" json:"category,omitempty"`
-	Chapter  *Chapter  `gorm:"foreignKey:ChapterID;references:ID" json:"chapter,omitempty"`
-	Tags     []Tag     `gorm:"many2many:article_tags" json:"tags,omitempty"`
+	Author   User            `gorm:"foreignKey:AuthorID;references:ID;constraint:OnDelete:SET NULL" json:"author,omitempty"`
+	Category Category        `gorm:"foreignKey:CategoryID;references:ID" json:"category,omitempty"`
+	Chapter  *Chapter        `gorm:"foreignKey:ChapterID;references:ID" json:"chapter,omitempty"`
+	Tags     []Tag           `gorm:"many2many:article_tags" json:"tags,omitempty"`
+	Authors  []ArticleAuthor `gorm:"foreignKey:ArticleID" json:"authors,omitempty"` // 共同作者
 }
 
+// 文章可见性
+const (
+	VisibilityPublic   = "public"   // 公开
+	VisibilityPrivate  = "private"  // 私密，仅后台可见
+	VisibilityPassword = "password" // 需要密码才能查看正文
+)
+
 // Category 分类模型
 type Category struct {
 	ID          uint           `gorm:"primarykey" json:"id"`
@@ -96,10 +126,14 @@ type Comment struct {
 	ArticleID     *uint          `gorm:"index" json:"article_id"` // 可为空，NULL表示留言板消息
 	UserID        uint           `gorm:"index;not null" json:"user_id"`
 	ParentID      *uint          `gorm:"index" json:"parent_id"`
-	ReplyToUserID *uint          `gorm:"index" json:"reply_to_user_id"` // 被回复的用户ID
-	Content       string         `gorm:"type:text;not null" json:"content"`
+	RootID        *uint          `gorm:"index" json:"root_id"`              // 所属顶级评论ID，顶级评论为空
+	ReplyToUserID *uint          `gorm:"index" json:"reply_to_user_id"`     // 被回复的用户ID
+	Content       string         `gorm:"type:text;not null" json:"content"` // 原始文本（Markdown）
+	ContentHTML   string         `gorm:"type:text" json:"content_html"`     // Markdown 渲染并清理后的 HTML
 	LikeCount     int            `gorm:"default:0" json:"like_count"`
-	Status        int            `gorm:"default:0" json:"status"` // 0: pending, 1: approved, 2: rejected
+	ReplyCount    int            `gorm:"default:0" json:"reply_count"` // 楼中楼回复数（仅顶级评论维护，只统计审核通过的）
+	Status        int            `gorm:"default:0" json:"status"`      // 0: pending, 1: approved, 2: spam, 3: trash
+	EditedAt      *time.Time     `json:"edited_at"`                    // 作者最后一次编辑的时间，未编辑过为空
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
@@ -110,6 +144,14 @@ type Comment struct {
 	Replies     []Comment `gorm:"foreignKey:ParentID" json:"replies,omitempty"`
 }
 
+// 评论状态，只有审核通过的评论在前台显示并计入评论数和回复数
+const (
+	CommentStatusPending  = 0 // 待审核
+	CommentStatusApproved = 1 // 已通过
+	CommentStatusSpam     = 2 // 垃圾评论
+	CommentStatusTrash    = 3 // 回收站
+)
+
 // Like 点赞记录
 type Like struct {
 	ID        uint      `gorm:"primarykey" json:"id"`
@@ -124,8 +166,9 @@ type Like struct {
 // Favorite 收藏记录
 type Favorite struct {
 	ID        uint      `gorm:"primarykey" json:"id"`
-	ArticleID uint      `gorm:"index;not null" json:"article_id"`
-	UserID    uint      `gorm:"index;not null" json:"user_id"`
+	ArticleID uint      `gorm:"uniqueIndex:idx_favorite_user_article,priority:2;index;not null" json:"article_id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_favorite_user_article,priority:1;not null" json:"user_id"`
+	FolderID  *uint     `gorm:"index" json:"folder_id"` // 所属收藏夹，为空时在默认收藏夹
 	CreatedAt time.Time `json:"created_at"`
 
 	User    User    `gorm:"foreignKey:UserID" json:"user,omitempty"`
@@ -156,7 +199,8 @@ type View struct {
 type PageVisit struct {
 	ID        uint      `gorm:"primarykey" json:"id"`
 	UserID    *uint     `gorm:"index" json:"user_id"` // 可为空，游客访问
-	IP        string    `gorm:"size:50;index" json:"ip"`
+	IP        string    `gorm:"size:255" json:"ip"`      // 启用字段加密时存储密文
+	IPHash    string    `gorm:"size:64;index" json:"-"`  // IP 盲索引，用于去重统计
 	Path      string    `gorm:"size:500" json:"path"`         // 访问路径
 	Duration  int       `gorm:"not null" json:"duration"`      // 停留时长（秒）
 	UserAgent string    `gorm:"size:500" json:"user_agent"`    // 用户代理
@@ -169,6 +213,8 @@ type File struct {
 	ID        uint           `gorm:"primarykey" json:"id"`
 	Name      string         `gorm:"size:200;not null" json:"name"`
 	URL       string         `gorm:"size:500;not null" json:"url"`
+	WebPURL   string         `gorm:"size:500" json:"webp_url"` // WebP 版本地址，未生成时为空
+	Variants  string         `gorm:"type:text" json:"variants"` // 缩放版本，JSON数组格式 [{name, width, url}]
 	Size      int64          `json:"size"`
 	Type      string         `gorm:"size:50" json:"type"`
 	MimeType  string         `gorm:"size:100" json:"mime_type"`
@@ -186,6 +232,12 @@ type Setting struct {
 
 // AutoMigrate 自动迁移数据库表
 func AutoMigrate(db *gorm.DB) error {
+	if err := migrateEmailHash(db); err != nil {
+		return err
+	}
+	if err := dedupeFavorites(db); err != nil {
+		return err
+	}
 	return db.AutoMigrate(
 		&Admin{},
 		&User{},
@@ -201,5 +253,39 @@ func AutoMigrate(db *gorm.DB) error {
 		&PageVisit{},
 		&File{},
 		&Setting{},
+		&RoutePermission{},
+		&ArticleVersion{},
+		&ArticleDraft{},
+		&Series{},
+		&SeriesArticle{},
+		&ArticleAuthor{},
+		&ArticleAuditLog{},
+		&Webhook{},
+		&WebhookDelivery{},
+		&YuqueSync{},
+		&YuqueItem{},
+		&UploadSession{},
+		&Attachment{},
+		&CommentEdit{},
+		&Reaction{},
+		&CommentBlockRule{},
+		&UserIdentity{},
+		&UserTwoFactor{},
+		&APIKey{},
+		&Role{},
+		&RolePermission{},
+		&LoginLog{},
+		&Follow{},
+		&FavoriteFolder{},
+		&ReadingProgress{},
+		&PushSubscription{},
+		&FriendLink{},
+		&Moment{},
+		&MomentLike{},
+		&Page{},
+		&MenuItem{},
+		&Banner{},
+		&Announcement{},
+		&ArticleTranslation{},
 	)
 }
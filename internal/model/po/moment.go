@@ -0,0 +1,28 @@
+package po
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Moment 说说，博主发布的短内容，可附带图片和位置
+type Moment struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	AuthorID  uint           `gorm:"index;not null" json:"author_id"` // 发布者（管理员）ID
+	Content   string         `gorm:"type:text;not null" json:"content"`
+	Images    string         `gorm:"type:text" json:"images"` // 图片地址，JSON数组格式，最多 9 张
+	Location  string         `gorm:"size:100" json:"location"`
+	LikeCount int            `gorm:"default:0;not null" json:"like_count"`
+	CreatedAt time.Time      `gorm:"index" json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// MomentLike 说说点赞记录，同一用户对同一条说说只记录一次
+type MomentLike struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	MomentID  uint      `gorm:"uniqueIndex:idx_moment_like,priority:1;not null" json:"moment_id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_moment_like,priority:2;index;not null" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package po
+
+import "time"
+
+// 独立页面状态
+const (
+	PageDraft     = 0 // 草稿，前台不可访问
+	PagePublished = 1 // 已发布
+)
+
+// Page 独立页面，如关于、简历、近况，通过 slug 访问，不出现在文章列表中
+type Page struct {
+	ID              uint       `gorm:"primarykey" json:"id"`
+	Title           string     `gorm:"size:200;not null" json:"title"`
+	Slug            string     `gorm:"size:191;uniqueIndex;not null" json:"slug"`
+	ContentMarkdown string     `gorm:"type:longtext" json:"content_markdown"`
+	ContentHTML     string     `gorm:"type:longtext" json:"content_html"` // Markdown 渲染并清理后的 HTML
+	MetaDescription string     `gorm:"size:300" json:"meta_description"`  // SEO 描述
+	Status          int        `gorm:"default:0;index" json:"status"`     // 0: 草稿, 1: 已发布
+	Sort            int        `gorm:"default:0" json:"sort"`             // 排序值，越小越靠前
+	AuthorID        uint       `gorm:"index" json:"author_id"`
+	PublishedAt     *time.Time `json:"published_at"` // 首次发布时间
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
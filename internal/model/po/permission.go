@@ -0,0 +1,14 @@
+package po
+
+import "time"
+
+// RoutePermission 路由权限规则（路由前缀 → 允许访问的角色）
+type RoutePermission struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	Method      string    `gorm:"size:10;not null;uniqueIndex:idx_route_method" json:"method"` // HTTP 方法，* 表示全部
+	Path        string    `gorm:"size:200;not null;uniqueIndex:idx_route_method" json:"path"`  // 路由前缀，如 /analytics
+	Roles       string    `gorm:"size:200;not null" json:"roles"`                              // 允许的角色，逗号分隔，* 表示所有登录用户
+	Description string    `gorm:"size:200" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,17 @@
+package po
+
+import "time"
+
+// PushSubscription 浏览器推送订阅，Endpoint 由浏览器推送服务分配，同一浏览器重新订阅时覆盖
+type PushSubscription struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	UserID        *uint      `gorm:"index" json:"user_id"` // 订阅时已登录的用户，匿名订阅为空
+	Endpoint      string     `gorm:"size:500;uniqueIndex;not null" json:"endpoint"`
+	P256dh        string     `gorm:"size:200;not null" json:"-"`
+	Auth          string     `gorm:"size:100;not null" json:"-"`
+	UserAgent     string     `gorm:"size:255" json:"user_agent"`
+	FailCount     int        `gorm:"default:0" json:"fail_count"` // 连续推送失败次数，成功后清零
+	LastSuccessAt *time.Time `json:"last_success_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
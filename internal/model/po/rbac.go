@@ -0,0 +1,22 @@
+package po
+
+import "time"
+
+// Role 角色，用户的 role 字段保存角色名称
+// 除前台用户 user 外的角色都可以登录管理后台，能执行的操作由角色拥有的权限决定
+type Role struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	Name        string    `gorm:"size:20;not null;uniqueIndex" json:"name"` // 与 users.role 一致，如 editor
+	DisplayName string    `gorm:"size:50;not null" json:"display_name"`
+	Description string    `gorm:"size:200" json:"description"`
+	Builtin     bool      `gorm:"default:false" json:"builtin"` // 内置角色不能删除
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// RolePermission 角色拥有的权限，如 article:publish；* 表示全部权限，article:* 表示文章的全部权限
+type RolePermission struct {
+	ID         uint   `gorm:"primarykey" json:"id"`
+	Role       string `gorm:"size:20;not null;uniqueIndex:idx_role_permission,priority:1" json:"role"`
+	Permission string `gorm:"size:100;not null;uniqueIndex:idx_role_permission,priority:2" json:"permission"`
+}
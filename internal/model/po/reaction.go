@@ -0,0 +1,20 @@
+package po
+
+import "time"
+
+// 表情回应的对象类型
+const (
+	ReactionTargetArticle = "article"
+	ReactionTargetComment = "comment"
+)
+
+// Reaction 文章或评论的表情回应，同一回应者对同一对象的同一表情只记录一次
+type Reaction struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	TargetType string    `gorm:"size:20;not null;uniqueIndex:idx_reaction,priority:1" json:"target_type"`
+	TargetID   uint      `gorm:"not null;uniqueIndex:idx_reaction,priority:2" json:"target_id"`
+	Emoji      string    `gorm:"size:32;not null;uniqueIndex:idx_reaction,priority:3" json:"emoji"`
+	Reactor    string    `gorm:"size:64;not null;uniqueIndex:idx_reaction,priority:4" json:"-"` // 登录用户为 u+用户ID，游客为 g+IP 摘要
+	UserID     *uint     `gorm:"index" json:"user_id"`                                          // 游客为空
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package po
+
+import "time"
+
+// ReadingProgress 用户在笔记标签（书）下的文章阅读进度，每个用户每篇文章一条
+type ReadingProgress struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	UserID      uint       `gorm:"uniqueIndex:idx_reading_progress,priority:1;index:idx_reading_progress_tag,priority:1;not null" json:"user_id"`
+	ArticleID   uint       `gorm:"uniqueIndex:idx_reading_progress,priority:2;not null" json:"article_id"`
+	TagID       uint       `gorm:"index:idx_reading_progress_tag,priority:2;not null" json:"tag_id"` // 文章所属章节的标签
+	Percent     int        `gorm:"default:0" json:"percent"`                                         // 文章内的阅读位置，0-100
+	Completed   bool       `gorm:"default:false" json:"completed"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ReadAt      time.Time  `gorm:"index" json:"read_at"` // 最近阅读时间，用于确定继续阅读的位置
+}
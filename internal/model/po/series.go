@@ -0,0 +1,32 @@
+package po
+
+import "time"
+
+// Series 文章系列（专栏），与章节相互独立，一篇文章可以加入多个系列
+type Series struct {
+	ID          uint            `gorm:"primarykey" json:"id"`
+	Title       string          `gorm:"size:200;not null" json:"title"`
+	Description string          `gorm:"size:500" json:"description"`
+	Cover       string          `gorm:"size:255" json:"cover"`
+	Sort        int             `gorm:"default:0" json:"sort"` // 系列排序，数字越小越靠前
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	Items       []SeriesArticle `gorm:"foreignKey:SeriesID" json:"items,omitempty"`
+}
+
+// SeriesArticle 系列中的文章及其顺序
+type SeriesArticle struct {
+	ID        uint     `gorm:"primarykey" json:"id"`
+	SeriesID  uint     `gorm:"uniqueIndex:idx_series_article;not null" json:"series_id"`
+	ArticleID uint     `gorm:"uniqueIndex:idx_series_article;index;not null" json:"article_id"`
+	Sort      int      `gorm:"default:0" json:"sort"` // 系列内顺序，从 0 开始
+	Article   *Article `gorm:"foreignKey:ArticleID" json:"article,omitempty"`
+}
+
+func (Series) TableName() string {
+	return "series"
+}
+
+func (SeriesArticle) TableName() string {
+	return "series_articles"
+}
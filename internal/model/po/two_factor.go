@@ -0,0 +1,17 @@
+package po
+
+import "time"
+
+// UserTwoFactor 用户的两步验证设置，每个用户一条
+// 绑定时先保存未启用的密钥，用户使用验证器生成的验证码确认后才启用
+type UserTwoFactor struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	UserID        uint       `gorm:"uniqueIndex;not null" json:"user_id"`
+	Secret        string     `gorm:"size:255;not null" json:"-"` // TOTP 密钥（base32），启用字段加密时加密保存
+	Enabled       bool       `gorm:"default:false" json:"enabled"`
+	RecoveryCodes string     `gorm:"type:text" json:"-"` // 未使用的恢复码摘要，JSON 数组，使用后移除
+	LastUsedStep  int64      `gorm:"default:0" json:"-"` // 最近一次验证通过的时间步，同一验证码不能重复使用
+	EnabledAt     *time.Time `json:"enabled_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,20 @@
+package po
+
+import "time"
+
+// UploadSession 分片上传会话，客户端按分片上传，中断后可查询已上传的分片继续上传
+type UploadSession struct {
+	ID         string    `gorm:"primarykey;size:36" json:"upload_id"`
+	Filename   string    `gorm:"size:200;not null" json:"filename"`
+	Folder     string    `gorm:"size:50" json:"folder"`
+	MimeType   string    `gorm:"size:100" json:"mime_type"`
+	Size       int64     `json:"size"`
+	ChunkSize  int64     `json:"chunk_size"`
+	TotalParts int       `json:"total_parts"`
+	Storage    string    `gorm:"size:20" json:"storage"`   // oss 或 local
+	ObjectKey  string    `gorm:"size:500" json:"-"`        // 合并后的对象键
+	StorageID  string    `gorm:"size:200" json:"-"`        // OSS 的 UploadId，本地存储时为分片目录名
+	UploaderID uint      `gorm:"index" json:"uploader_id"` // 上传者，只有上传者可以继续上传
+	ExpiresAt  time.Time `gorm:"index" json:"expires_at"`  // 过期后分片被清理
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package po
+
+import "time"
+
+// UserIdentity 前台用户绑定的第三方账号，同一平台的同一账号只能绑定一个用户
+type UserIdentity struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"index;not null" json:"user_id"`
+	Provider  string    `gorm:"size:20;not null;uniqueIndex:idx_identity,priority:1" json:"provider"` // github, google, wechat, wechat_mp, qq
+	Subject   string    `gorm:"size:128;not null;uniqueIndex:idx_identity,priority:2" json:"subject"` // 第三方平台的用户ID
+	UnionID   string    `gorm:"size:128;index" json:"-"`                                              // 同一开放平台下多个应用共享的用户ID
+	Login     string    `gorm:"size:100" json:"login"`                                                // 第三方平台的用户名
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
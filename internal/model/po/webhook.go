@@ -0,0 +1,62 @@
+package po
+
+import "time"
+
+// Webhook 事件类型
+const (
+	WebhookEventArticlePublished = "article.published"
+	WebhookEventArticleUpdated   = "article.updated"
+	WebhookEventArticleDeleted   = "article.deleted"
+	WebhookEventCommentCreated   = "comment.created"
+	WebhookEventLoginNewLocation = "login.new_location" // 用户在新的所在地登录
+	WebhookEventPing             = "ping"               // 测试投递，不可订阅
+)
+
+// WebhookEvents 可订阅的事件
+var WebhookEvents = []string{
+	WebhookEventArticlePublished,
+	WebhookEventArticleUpdated,
+	WebhookEventArticleDeleted,
+	WebhookEventCommentCreated,
+	WebhookEventLoginNewLocation,
+}
+
+// Webhook 投递状态
+const (
+	WebhookDeliveryPending = "pending" // 等待投递或重试
+	WebhookDeliverySuccess = "success"
+	WebhookDeliveryFailed  = "failed" // 重试次数用尽
+)
+
+// Webhook 外部系统订阅的回调地址
+type Webhook struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	URL       string    `gorm:"size:500;not null" json:"url"`
+	Secret    string    `gorm:"size:255;not null" json:"-"`      // 签名密钥，启用字段加密时存储密文
+	Events    string    `gorm:"size:500;not null" json:"events"` // 订阅的事件，逗号分隔
+	Enabled   bool      `gorm:"default:true" json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery Webhook 投递记录，失败后按退避间隔重试
+// NextRetryAt 同时作为投递租约：投递前先推迟到租约到期时间，进程中断后由重试任务接管
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primarykey" json:"id"`
+	WebhookID      uint       `gorm:"index;not null" json:"webhook_id"`
+	Event          string     `gorm:"size:50;index" json:"event"`
+	Payload        string     `gorm:"type:mediumtext" json:"payload"`
+	Status         string     `gorm:"size:20;index:idx_webhook_delivery_due" json:"status"`
+	Attempts       int        `gorm:"default:0" json:"attempts"`
+	ResponseStatus int        `json:"response_status"`
+	ResponseBody   string     `gorm:"size:1000" json:"response_body"`
+	Error          string     `gorm:"size:500" json:"error"`
+	Duration       int64      `json:"duration"`                  // 最近一次请求耗时（毫秒）
+	RequestID      string     `gorm:"size:64" json:"request_id"` // 触发投递的请求ID，每次投递都通过 X-Request-ID 发送
+	NextRetryAt    *time.Time `gorm:"index:idx_webhook_delivery_due" json:"next_retry_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `gorm:"index" json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	Webhook        *Webhook   `gorm:"foreignKey:WebhookID" json:"-"`
+}
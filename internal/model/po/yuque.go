@@ -0,0 +1,35 @@
+package po
+
+import "time"
+
+// YuqueSync 语雀知识库同步配置
+// 知识库对应一个笔记标签，目录中的分组和含子文档的文档对应章节，文档对应文章
+type YuqueSync struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	Namespace    string     `gorm:"size:200;uniqueIndex;not null" json:"namespace"` // 知识库路径，如 group/book
+	Name         string     `gorm:"size:100" json:"name"`                           // 知识库名称
+	Token        string     `gorm:"size:500;not null" json:"-"`                     // 语雀 Token，启用字段加密时存储密文
+	TagID        uint       `json:"tag_id"`                                         // 笔记标签
+	CategoryID   uint       `json:"category_id"`                                    // 新建文章的分类
+	AuthorID     uint       `json:"author_id"`                                      // 新建文章的作者
+	AutoPublish  bool       `gorm:"default:false" json:"auto_publish"`              // 新文档直接发布，否则保存为草稿
+	Enabled      bool       `gorm:"default:true" json:"enabled"`                    // 是否参与定时同步
+	LastSyncedAt *time.Time `json:"last_synced_at"`
+	LastError    string     `gorm:"size:500" json:"last_error"`
+	LockedUntil  *time.Time `json:"-"` // 同步租约，避免多个实例同时同步
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// YuqueItem 语雀目录节点与本地章节、文章的映射
+type YuqueItem struct {
+	ID               uint       `gorm:"primarykey" json:"id"`
+	SyncID           uint       `gorm:"uniqueIndex:idx_yuque_item;not null" json:"sync_id"`
+	UUID             string     `gorm:"size:64;uniqueIndex:idx_yuque_item;not null" json:"uuid"` // 目录节点 uuid
+	DocID            uint       `json:"doc_id"`                                                  // 语雀文档 ID，分组为 0
+	ChapterID        *uint      `json:"chapter_id"`
+	ArticleID        *uint      `json:"article_id"`
+	ContentUpdatedAt *time.Time `json:"content_updated_at"` // 上次同步时文档的正文更新时间
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"github.com/spf13/viper"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -11,7 +13,9 @@ import (
 	"github.com/ydcloud-dy/leaf-api/internal/data"
 	"github.com/ydcloud-dy/leaf-api/internal/server/middleware"
 	"github.com/ydcloud-dy/leaf-api/internal/service"
+	"github.com/ydcloud-dy/leaf-api/pkg/i18n"
 	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
 
 	_ "github.com/ydcloud-dy/leaf-api/docs" // Swagger 文档
 )
@@ -36,10 +40,18 @@ func NewHTTPServer(b *biz.Biz, d *data.Data) *HTTPServer {
 
 	r := gin.New()
 
+	// 校验错误使用请求参数名，便于翻译后的消息与客户端提交的字段对应
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		i18n.RegisterFieldNames(v)
+	}
+
 	// 全局中间件
+	r.Use(middleware.RequestID())
 	r.Use(logger.GinLogger())
 	r.Use(logger.GinRecovery())
 	r.Use(middleware.CORS())
+	r.Use(middleware.Locale())
+	r.Use(middleware.StorageURLs())
 
 	// 静态文件服务（用于本地文件上传）
 	r.Static("/uploads", "./uploads")
@@ -61,15 +73,59 @@ func NewHTTPServer(b *biz.Biz, d *data.Data) *HTTPServer {
 	commentService := service.NewCommentService(b.CommentUseCase)
 	chapterService := service.NewChapterService(d)
 	statsService := service.NewStatsService(d)
-	settingsService := service.NewSettingsService(d)
-	fileService := service.NewFileService(d)
+	settingsService := service.NewSettingsService(b.SettingUseCase)
+	fileService := service.NewFileService(d, b.ImageCleanupUseCase, b.UploadUseCase, b.StorageStatsUseCase, b.SettingUseCase)
 	blogService := service.NewBlogService(b.BlogUseCase)
 	onlineService := service.NewOnlineService(d)
 	visitService := service.NewVisitService(d)
 	analyticsService := service.NewAnalyticsService(d)
-
-	// 注册路由
-	registerRoutes(r, authService, articleService, userService, categoryService, tagService, commentService, chapterService, statsService, settingsService, fileService, blogService, onlineService, visitService, analyticsService)
+	permissionService := service.NewPermissionService(b.PermissionUseCase)
+	searchService := service.NewSearchService(b.SearchUseCase)
+	seriesService := service.NewSeriesService(b.SeriesUseCase)
+	graphqlService := service.NewGraphQLService(b.ArticleUseCase, b.BlogUseCase, b.CategoryUseCase, b.TagUseCase, d)
+	sitemapService := service.NewSitemapService(b.SitemapUseCase)
+	webhookService := service.NewWebhookService(b.WebhookUseCase)
+	yuqueService := service.NewYuqueService(b.YuqueUseCase)
+	backupService := service.NewBackupService(b.BackupUseCase)
+	attachmentService := service.NewAttachmentService(b.AttachmentUseCase)
+	mediaService := service.NewMediaService(b.MediaUseCase)
+	reactionService := service.NewReactionService(b.ReactionUseCase)
+	commentBlockService := service.NewCommentBlockService(b.CommentBlockUseCase)
+	friendLinkService := service.NewFriendLinkService(b.FriendLinkUseCase)
+	guestbookService := service.NewGuestbookService(b.GuestbookUseCase)
+	momentService := service.NewMomentService(b.MomentUseCase)
+	pageService := service.NewPageService(b.PageUseCase)
+	menuService := service.NewMenuService(b.MenuUseCase)
+	bannerService := service.NewBannerService(b.BannerUseCase)
+	announcementService := service.NewAnnouncementService(b.AnnouncementUseCase)
+	feedService := service.NewFeedService(b.FeedUseCase)
+	oauthService := service.NewOAuthService(b.OAuthUseCase)
+	apiKeyService := service.NewAPIKeyService(b.APIKeyUseCase)
+	rbacService := service.NewRBACService(b.RBACUseCase)
+	loginLogService := service.NewLoginLogService(b.LoginLogUseCase)
+	captchaService := service.NewCaptchaService(b.CaptchaUseCase)
+	userProfileService := service.NewUserProfileService(b.UserProfileUseCase)
+	followService := service.NewFollowService(b.FollowUseCase)
+	favoriteService := service.NewFavoriteService(b.FavoriteUseCase)
+	readingProgressService := service.NewReadingProgressService(b.ReadingProgressUseCase)
+	mailService := service.NewMailService(b.MailUseCase)
+	pushService := service.NewPushService(b.PushUseCase)
+	realtimeService := service.NewRealtimeService(b.RealtimeUseCase, onlineService)
+	presenceService := service.NewPresenceService(b.RealtimeUseCase)
+
+	// 站点地图（不区分 API 版本）
+	r.GET("/sitemap.xml", sitemapService.Index)
+	r.GET("/sitemaps/:file", sitemapService.Shard)
+
+	// 注册路由：各版本共享同一套服务，响应结构的差异由 response 包按版本映射
+	// 根路径保留为 v1 的兼容入口，新客户端应使用 /api/v1 或 /api/v2
+	for _, group := range []gin.IRouter{
+		r,
+		r.Group("/api/v1", middleware.APIVersion(response.VersionV1)),
+		r.Group("/api/v2", middleware.APIVersion(response.VersionV2)),
+	} {
+		registerRoutes(group, authService, articleService, userService, categoryService, tagService, commentService, chapterService, statsService, settingsService, fileService, blogService, onlineService, visitService, analyticsService, permissionService, searchService, seriesService, graphqlService, webhookService, yuqueService, backupService, attachmentService, mediaService, reactionService, commentBlockService, feedService, oauthService, apiKeyService, rbacService, loginLogService, captchaService, userProfileService, followService, favoriteService, readingProgressService, mailService, pushService, realtimeService, presenceService, friendLinkService, guestbookService, momentService, pageService, menuService, bannerService, announcementService)
+	}
 
 	// 获取端口
 	port := viper.GetInt("server.port")
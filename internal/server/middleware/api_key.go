@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// APIKeyHeader 携带 API Key 的请求头
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator API Key 认证
+type APIKeyAuthenticator interface {
+	// AuthenticateAPIKey 校验 API Key，返回调用方以及权限范围是否包含该接口
+	AuthenticateAPIKey(key, method, path string) (*dto.APIKeyIdentity, bool, error)
+}
+
+// APIKeyAuth API Key 认证中间件（需在 JWTAuth 之前使用）
+// 携带 X-API-Key 请求头时使用 API Key 认证并跳过 JWTAuth，否则仍由 JWTAuth 校验 Authorization
+func APIKeyAuth(auth APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(APIKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		path := c.FullPath()
+		if path == "" {
+			path = c.Request.URL.Path
+		}
+		identity, allowed, err := auth.AuthenticateAPIKey(key, c.Request.Method, unversionedPath(c, path))
+		if err != nil {
+			response.Unauthorized(c, err.Error())
+			c.Abort()
+			return
+		}
+		if !allowed {
+			response.Forbidden(c, "API Key 的权限范围不包含该接口")
+			c.Abort()
+			return
+		}
+
+		c.Set("api_key_id", identity.KeyID)
+		c.Set("admin_id", identity.UserID)
+		c.Set("user_id", identity.UserID)
+		c.Set("username", identity.Username)
+		c.Set("role", identity.Role)
+		c.Next()
+	}
+}
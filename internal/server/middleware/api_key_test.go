@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+)
+
+// stubAPIKeyAuthenticator 只认识 good 和 readonly 两个 API Key
+type stubAPIKeyAuthenticator struct{}
+
+func (stubAPIKeyAuthenticator) AuthenticateAPIKey(key, method, path string) (*dto.APIKeyIdentity, bool, error) {
+	identity := &dto.APIKeyIdentity{KeyID: 7, UserID: 3, Username: "ci", Role: "admin"}
+	switch key {
+	case "good":
+		return identity, true, nil
+	case "readonly":
+		return identity, method == http.MethodGet, nil
+	}
+	return nil, false, errors.New("无效的 API Key")
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name     string
+		key      string
+		method   string
+		wantCode int
+		wantUser uint
+	}{
+		{name: "valid key skips jwt", key: "good", method: http.MethodPost, wantCode: http.StatusOK, wantUser: 3},
+		{name: "outside scope", key: "readonly", method: http.MethodPost, wantCode: http.StatusForbidden},
+		{name: "inside scope", key: "readonly", method: http.MethodGet, wantCode: http.StatusOK, wantUser: 3},
+		{name: "invalid key", key: "bad", method: http.MethodPost, wantCode: http.StatusUnauthorized},
+		{name: "no key falls back to jwt", method: http.MethodPost, wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			var userID uint
+			handler := func(c *gin.Context) {
+				userID = c.GetUint("admin_id")
+				c.Status(http.StatusOK)
+			}
+			r.Use(APIKeyAuth(stubAPIKeyAuthenticator{}), JWTAuth())
+			r.GET("/articles", handler)
+			r.POST("/articles", handler)
+
+			req := httptest.NewRequest(tt.method, "/articles", nil)
+			if tt.key != "" {
+				req.Header.Set(APIKeyHeader, tt.key)
+			}
+			code, body := serveSigned(r, req)
+			if code != tt.wantCode {
+				t.Fatalf("status = %d, want %d, body %s", code, tt.wantCode, body)
+			}
+			if userID != tt.wantUser {
+				t.Errorf("admin_id = %d, want %d", userID, tt.wantUser)
+			}
+		})
+	}
+}
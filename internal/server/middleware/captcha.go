@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// CaptchaTicketHeader 人机验证凭证请求头
+const CaptchaTicketHeader = "X-Captcha-Ticket"
+
+// CaptchaChecker 人机验证凭证检查
+type CaptchaChecker interface {
+	CheckCaptcha(scene, ticket string) error
+}
+
+// Captcha 人机验证中间件，如 Captcha(checker, "login")
+// 前端先通过 /captcha/verify 完成验证，再将返回的凭证放在 X-Captcha-Ticket 请求头中提交表单；场景未启用验证时直接放行
+func Captcha(checker CaptchaChecker, scene string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := checker.CheckCaptcha(scene, c.GetHeader(CaptchaTicketHeader)); err != nil {
+			response.BadRequest(c, err.Error())
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/pkg/i18n"
+)
+
+// Locale 协商请求语言：优先使用查询参数 lang，其次按 Accept-Language 请求头选择，都不支持时使用默认语言
+// 响应头 Content-Language 为协商结果，错误消息和校验错误按该语言返回
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := i18n.Match(c.Query("lang"))
+		if locale == "" {
+			locale = i18n.Negotiate(c.GetHeader("Accept-Language"))
+		}
+		c.Set(i18n.LocaleKey, locale)
+		c.Header("Content-Language", locale)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Next()
+	}
+}
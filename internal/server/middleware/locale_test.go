@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/ydcloud-dy/leaf-api/pkg/i18n"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+func TestLocale(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	i18n.RegisterFieldNames(binding.Validator.Engine().(*validator.Validate))
+	tests := []struct {
+		name         string
+		query        string
+		header       string
+		wantLocale   string
+		wantNotFound string
+		wantBind     string
+	}{
+		{name: "default", wantLocale: "zh-CN", wantNotFound: "页面不存在", wantBind: "name 不能为空"},
+		{name: "accept language", header: "en-US,en;q=0.9", wantLocale: "en", wantNotFound: "Page not found", wantBind: "name is required"},
+		{name: "query overrides header", query: "?lang=zh", header: "en", wantLocale: "zh-CN", wantNotFound: "页面不存在", wantBind: "name 不能为空"},
+		{name: "unsupported query falls back", query: "?lang=fr", header: "en", wantLocale: "en", wantNotFound: "Page not found", wantBind: "name is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(Locale())
+			r.GET("/page", func(c *gin.Context) { response.NotFound(c, "页面不存在") })
+			r.POST("/page", func(c *gin.Context) {
+				var req struct {
+					Name string `json:"name" binding:"required"`
+				}
+				if err := c.ShouldBindJSON(&req); err != nil {
+					response.BindError(c, err)
+				}
+			})
+
+			check := func(req *http.Request, wantMessage, wantCode string) {
+				if tt.header != "" {
+					req.Header.Set("Accept-Language", tt.header)
+				}
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+				if got := w.Header().Get("Content-Language"); got != tt.wantLocale {
+					t.Errorf("Content-Language = %q, want %q", got, tt.wantLocale)
+				}
+				var body response.Response
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+					t.Fatalf("decode body: %v", err)
+				}
+				if !strings.Contains(body.Message, wantMessage) || body.ErrorCode != wantCode {
+					t.Errorf("%s %s: message=%q code=%q, want %q %q", req.Method, req.URL, body.Message, body.ErrorCode, wantMessage, wantCode)
+				}
+			}
+			check(httptest.NewRequest(http.MethodGet, "/page"+tt.query, nil), tt.wantNotFound, "page_not_found")
+			check(httptest.NewRequest(http.MethodPost, "/page"+tt.query, strings.NewReader(`{}`)), tt.wantBind, "validation_failed")
+		})
+	}
+}
@@ -11,6 +11,12 @@ import (
 // JWTAuth JWT认证中间件
 func JWTAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// 已通过 APIKeyAuth 认证
+		if c.GetUint("api_key_id") != 0 {
+			c.Next()
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			response.Unauthorized(c, "请求头中缺少Authorization")
@@ -31,11 +37,17 @@ func JWTAuth() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		if jwt.Revoked(claims) {
+			response.Unauthorized(c, "Token已失效，请重新登录")
+			c.Abort()
+			return
+		}
 
 		c.Set("admin_id", claims.AdminID)
 		c.Set("user_id", claims.AdminID) // For blog users, also set user_id
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("session_id", claims.SessionID)
 		c.Next()
 	}
 }
@@ -58,8 +70,8 @@ func OptionalJWTAuth() gin.HandlerFunc {
 		}
 
 		claims, err := jwt.ParseToken(parts[1])
-		if err != nil {
-			// token无效，继续处理但不设置user_id
+		if err != nil || jwt.Revoked(claims) {
+			// token无效或已吊销，继续处理但不设置user_id
 			c.Next()
 			return
 		}
@@ -69,6 +81,7 @@ func OptionalJWTAuth() gin.HandlerFunc {
 		c.Set("user_id", claims.AdminID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("session_id", claims.SessionID)
 		c.Next()
 	}
 }
@@ -78,8 +91,8 @@ func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization")
-		c.Header("Access-Control-Expose-Headers", "Content-Length")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, X-Timestamp, X-Nonce, X-Signature, X-Article-Token, X-API-Key, X-Captcha-Ticket, X-Request-ID, Accept-Language")
+		c.Header("Access-Control-Expose-Headers", "Content-Length, X-Request-ID, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, Retry-After")
 		c.Header("Access-Control-Allow-Credentials", "true")
 
 		if c.Request.Method == "OPTIONS" {
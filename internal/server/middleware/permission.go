@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// PermissionChecker 路由权限检查
+type PermissionChecker interface {
+	Allowed(method, path, role string) bool
+}
+
+// RoutePermission 路由权限中间件（需在 JWTAuth 之后使用）
+// 规则存储在数据库中，可通过管理接口动态调整，无需重新部署
+func RoutePermission(checker PermissionChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.FullPath()
+		if path == "" {
+			path = c.Request.URL.Path
+		}
+		path = unversionedPath(c, path)
+
+		if !checker.Allowed(c.Request.Method, path, c.GetString("role")) {
+			response.Forbidden(c, "无权访问该接口")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RoleChecker 角色权限检查
+type RoleChecker interface {
+	HasPermission(role, permission string) bool
+}
+
+// RequirePermission 操作权限中间件（需在 JWTAuth 之后使用），如 RequirePermission(checker, "article:publish")
+// 路由权限决定角色能否进入接口分组，操作权限进一步限制角色能执行的具体操作
+func RequirePermission(checker RoleChecker, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !checker.HasPermission(c.GetString("role"), permission) {
+			response.Forbidden(c, "无权执行该操作")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// setupRateLimit 设置限流配置并使用内存 Redis 保存令牌桶
+func setupRateLimit(t *testing.T, cfg config.RateLimitConfig) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	config.AppConfig = &config.Config{RateLimit: cfg}
+	mr := miniredis.RunT(t)
+	redis.Client = goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		redis.Client.Close()
+		redis.Client = nil
+		config.AppConfig = nil
+	})
+}
+
+// newRateLimitRouter 登录用户 ID 取自 X-User 请求头，模拟认证中间件
+func newRateLimitRouter(group string) *gin.Engine {
+	r := gin.New()
+	r.POST("/limited", func(c *gin.Context) {
+		if user := c.GetHeader("X-User"); user != "" {
+			c.Set("user_id", uint(len(user)))
+		}
+	}, RateLimit(group), func(c *gin.Context) {
+		response.Success(c, nil)
+	})
+	return r
+}
+
+func serveLimited(r *gin.Engine, ip, user string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/limited", nil)
+	req.RemoteAddr = ip + ":12345"
+	if user != "" {
+		req.Header.Set("X-User", user)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit(t *testing.T) {
+	setupRateLimit(t, config.RateLimitConfig{
+		Enabled: true,
+		Rules: map[string]config.RateLimitRule{
+			RateLimitLogin:   {Rate: 3, Period: 60, Burst: 2, By: "ip"},
+			RateLimitComment: {Rate: 3, Period: 60, Burst: 2, By: "user"},
+		},
+	})
+
+	t.Run("burst then 429", func(t *testing.T) {
+		r := newRateLimitRouter(RateLimitLogin)
+		for i, wantRemaining := range []string{"1", "0"} {
+			w := serveLimited(r, "10.0.0.1", "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("request %d: status = %d", i, w.Code)
+			}
+			if got := w.Header().Get("X-RateLimit-Remaining"); got != wantRemaining {
+				t.Errorf("request %d: remaining = %q, want %q", i, got, wantRemaining)
+			}
+			if got := w.Header().Get("X-RateLimit-Limit"); got != "2" {
+				t.Errorf("request %d: limit = %q", i, got)
+			}
+		}
+
+		w := serveLimited(r, "10.0.0.1", "")
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("status = %d, want 429", w.Code)
+		}
+		// 每 20 秒补充一个令牌
+		if got := w.Header().Get("Retry-After"); got != "20" {
+			t.Errorf("Retry-After = %q, want 20", got)
+		}
+		if got := w.Header().Get("X-RateLimit-Reset"); got != "40" {
+			t.Errorf("X-RateLimit-Reset = %q, want 40", got)
+		}
+		var body response.Response
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Code != http.StatusTooManyRequests || body.ErrorCode != "rate_limited" {
+			t.Errorf("body = %s", w.Body.String())
+		}
+
+		// 其他 IP 不受影响
+		if w := serveLimited(r, "10.0.0.2", ""); w.Code != http.StatusOK {
+			t.Errorf("other ip status = %d", w.Code)
+		}
+	})
+
+	t.Run("by user", func(t *testing.T) {
+		r := newRateLimitRouter(RateLimitComment)
+		for i := 0; i < 2; i++ {
+			serveLimited(r, "10.0.1.1", "alice")
+		}
+		// 同一用户换 IP 仍被限流，同一 IP 的其他用户不受影响
+		if w := serveLimited(r, "10.0.1.2", "alice"); w.Code != http.StatusTooManyRequests {
+			t.Errorf("same user status = %d, want 429", w.Code)
+		}
+		if w := serveLimited(r, "10.0.1.1", "bob"); w.Code != http.StatusOK {
+			t.Errorf("other user status = %d", w.Code)
+		}
+	})
+
+	t.Run("default rule", func(t *testing.T) {
+		// 配置中未设置的路由组使用默认规则
+		r := newRateLimitRouter(RateLimitSearch)
+		w := serveLimited(r, "10.0.2.1", "")
+		if got := w.Header().Get("X-RateLimit-Limit"); got != "10" {
+			t.Errorf("limit = %q, want 10", got)
+		}
+	})
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.RateLimitConfig
+	}{
+		{name: "disabled", cfg: config.RateLimitConfig{Rules: map[string]config.RateLimitRule{RateLimitLogin: {Rate: 1, Burst: 1}}}},
+		{name: "zero rate", cfg: config.RateLimitConfig{Enabled: true, Rules: map[string]config.RateLimitRule{RateLimitLogin: {Rate: 0}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupRateLimit(t, tt.cfg)
+			r := newRateLimitRouter(RateLimitLogin)
+			for i := 0; i < 3; i++ {
+				w := serveLimited(r, "10.0.0.1", "")
+				if w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Limit") != "" {
+					t.Fatalf("request %d: status = %d headers = %v", i, w.Code, w.Header())
+				}
+			}
+		})
+	}
+}
+
+func TestTakeTokenRefill(t *testing.T) {
+	setupRateLimit(t, config.RateLimitConfig{})
+	now := time.Unix(1700000000, 0)
+	take := func(at time.Time) *redis.TokenBucket {
+		t.Helper()
+		bucket, err := redis.TakeToken("rate_limit:test", 1, 2, at)
+		if err != nil {
+			t.Fatalf("TakeToken: %v", err)
+		}
+		return bucket
+	}
+
+	take(now)
+	take(now)
+	if bucket := take(now); bucket.Allowed || bucket.RetryAfter != time.Second {
+		t.Fatalf("exhausted bucket = %+v", bucket)
+	}
+	// 500 毫秒只补充半个令牌
+	if bucket := take(now.Add(500 * time.Millisecond)); bucket.Allowed || bucket.RetryAfter != 500*time.Millisecond {
+		t.Fatalf("half refilled bucket = %+v", bucket)
+	}
+	if bucket := take(now.Add(time.Second)); !bucket.Allowed || bucket.Remaining != 0 {
+		t.Fatalf("refilled bucket = %+v", bucket)
+	}
+	// 长时间未请求后最多补充到桶容量
+	if bucket := take(now.Add(time.Hour)); !bucket.Allowed || bucket.Remaining != 1 || bucket.ResetAfter != time.Second {
+		t.Fatalf("full bucket = %+v", bucket)
+	}
+}
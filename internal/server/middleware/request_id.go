@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// RequestID 请求ID中间件，需放在访问日志之前
+// 沿用上游网关或服务传入的 X-Request-ID（格式不合法时重新生成），写入响应头和错误响应，
+// 并随请求 context 传递，业务代码可通过 logger.FromContext(c.Request.Context()) 记录带请求ID的日志
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := response.RequestID(c)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
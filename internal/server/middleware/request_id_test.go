@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name   string
+		header string
+		want   string // 为空时应生成新的请求ID
+	}{
+		{name: "generated"},
+		{name: "propagated", header: "gateway-7f3a.1", want: "gateway-7f3a.1"},
+		{name: "invalid replaced", header: "bad id\r\nX-Injected: 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fromContext string
+			r := gin.New()
+			r.Use(RequestID())
+			r.GET("/fail", func(c *gin.Context) {
+				fromContext = logger.RequestIDFromContext(c.Request.Context())
+				response.BadRequest(c, "请求参数错误")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Request-ID", tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			id := w.Header().Get("X-Request-ID")
+			if tt.want != "" && id != tt.want {
+				t.Errorf("X-Request-ID = %q, want %q", id, tt.want)
+			}
+			if id == "" || id == tt.header && tt.want == "" {
+				t.Errorf("X-Request-ID = %q, want generated", id)
+			}
+			if fromContext != id {
+				t.Errorf("context request id = %q, want %q", fromContext, id)
+			}
+			var body response.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.RequestID != id {
+				t.Errorf("body = %s, want request_id %q", w.Body.String(), id)
+			}
+		})
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	previous := logger.Log
+	logger.Log = logrus.New()
+	logger.Log.SetOutput(&buf)
+	logger.Log.SetFormatter(&logrus.JSONFormatter{})
+	t.Cleanup(func() { logger.Log = previous })
+
+	r := gin.New()
+	r.Use(RequestID(), logger.GinLogger())
+	r.GET("/articles/:id", func(c *gin.Context) {
+		c.Set("user_id", uint(7))
+		response.NotFound(c, "文章不存在")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/42?lang=en", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("decode access log %q: %v", buf.String(), err)
+	}
+	want := map[string]interface{}{
+		"request_id": "req-1",
+		"route":      "/articles/:id",
+		"path":       "/articles/42",
+		"query":      "lang=en",
+		"method":     http.MethodGet,
+		"status":     float64(http.StatusOK),
+		"user_id":    float64(7),
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("%s = %v, want %v", key, entry[key], value)
+		}
+	}
+	if _, ok := entry["latency_ms"].(float64); !ok {
+		t.Errorf("latency_ms = %v", entry["latency_ms"])
+	}
+}
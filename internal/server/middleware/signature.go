@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+const (
+	// 签名 nonce Redis Key 前缀
+	signNoncePrefix = "sign:nonce:"
+	// 默认允许的时间偏差
+	defaultSignMaxSkew = 300 * time.Second
+)
+
+// SignedRequest 请求签名校验中间件（用于匿名可访问的写接口）
+//
+// 签名串：METHOD\nPATH\nTIMESTAMP\nNONCE\nhex(sha256(body))
+// 签名：hex(hmac_sha256(secret, 签名串))
+// 通过请求头 X-Timestamp / X-Nonce / X-Signature 传递，
+// sendBeacon 等无法设置请求头的场景可使用查询参数 _ts / _nonce / _sign。
+func SignedRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := config.AppConfig.Signing
+		if !cfg.Enabled || cfg.Secret == "" {
+			c.Next()
+			return
+		}
+
+		timestamp := signParam(c, "X-Timestamp", "_ts")
+		nonce := signParam(c, "X-Nonce", "_nonce")
+		signature := signParam(c, "X-Signature", "_sign")
+		if timestamp == "" || nonce == "" || signature == "" {
+			response.Forbidden(c, "缺少请求签名")
+			c.Abort()
+			return
+		}
+
+		// 校验时间戳
+		maxSkew := defaultSignMaxSkew
+		if cfg.MaxSkew > 0 {
+			maxSkew = time.Duration(cfg.MaxSkew) * time.Second
+		}
+		ts, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			response.Forbidden(c, "请求签名无效")
+			c.Abort()
+			return
+		}
+		skew := time.Since(time.Unix(ts, 0))
+		if skew > maxSkew || skew < -maxSkew {
+			response.Forbidden(c, "请求已过期")
+			c.Abort()
+			return
+		}
+
+		// 读取请求体后重新写回，供后续处理使用
+		var body []byte
+		if c.Request.Body != nil {
+			body, err = io.ReadAll(c.Request.Body)
+			if err != nil {
+				response.BadRequest(c, "读取请求体失败")
+				c.Abort()
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		// 请求体已被 StorageURLs 改写时按原始内容校验
+		if raw, ok := c.Get(rawBodyKey); ok {
+			body = raw.([]byte)
+		}
+
+		// 校验签名
+		bodyHash := sha256.Sum256(body)
+		payload := strings.Join([]string{
+			c.Request.Method,
+			c.Request.URL.Path,
+			timestamp,
+			nonce,
+			hex.EncodeToString(bodyHash[:]),
+		}, "\n")
+		mac := hmac.New(sha256.New, []byte(cfg.Secret))
+		mac.Write([]byte(payload))
+		expected := hex.EncodeToString(mac.Sum(nil))
+		if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+			response.Forbidden(c, "请求签名无效")
+			c.Abort()
+			return
+		}
+
+		// 防重放：nonce 在有效期内只能使用一次（Redis 不可用时跳过）
+		if redis.Client != nil {
+			ok, err := redis.SetNX(signNoncePrefix+nonce, 1, 2*maxSkew)
+			if err == nil && !ok {
+				response.Forbidden(c, "请求重复提交")
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// signParam 优先从请求头读取签名参数，其次从查询参数读取
+func signParam(c *gin.Context, header, query string) string {
+	if v := c.GetHeader(header); v != "" {
+		return v
+	}
+	return c.Query(query)
+}
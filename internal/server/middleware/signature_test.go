@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+const testSignSecret = "sign-test-secret"
+
+// sign 按客户端规则计算签名
+func sign(method, path, timestamp, nonce, body string) string {
+	bodyHash := sha256.Sum256([]byte(body))
+	payload := strings.Join([]string{method, path, timestamp, nonce, hex.EncodeToString(bodyHash[:])}, "\n")
+	mac := hmac.New(sha256.New, []byte(testSignSecret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// setupSigning 设置签名配置并使用内存 Redis 记录 nonce
+func setupSigning(t *testing.T, enabled bool) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	config.AppConfig = &config.Config{Signing: config.SigningConfig{Enabled: enabled, Secret: testSignSecret}}
+	mr := miniredis.RunT(t)
+	redis.Client = goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		redis.Client.Close()
+		redis.Client = nil
+	})
+}
+
+// serveSigned 执行请求，返回业务状态码（被中间件拦截时为响应体中的 code）
+func serveSigned(r *gin.Engine, req *http.Request) (int, string) {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var resp struct {
+		Code int `json:"code"`
+	}
+	if w.Body.Len() > 0 && json.Unmarshal(w.Body.Bytes(), &resp) == nil && resp.Code != 0 {
+		return resp.Code, w.Body.String()
+	}
+	return w.Code, w.Body.String()
+}
+
+func TestSignedRequest(t *testing.T) {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	body := `{"path":"/posts/1"}`
+
+	tests := []struct {
+		name     string
+		enabled  bool
+		header   map[string]string
+		query    string
+		body     string
+		wantCode int
+	}{
+		{name: "disabled passes through", body: body, wantCode: http.StatusOK},
+		{
+			name:     "valid header signature",
+			enabled:  true,
+			header:   map[string]string{"X-Timestamp": now, "X-Nonce": "n1", "X-Signature": sign("POST", "/track", now, "n1", body)},
+			body:     body,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "uppercase signature accepted",
+			enabled:  true,
+			header:   map[string]string{"X-Timestamp": now, "X-Nonce": "n2", "X-Signature": strings.ToUpper(sign("POST", "/track", now, "n2", body))},
+			body:     body,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "valid query signature",
+			enabled:  true,
+			query:    "?_ts=" + now + "&_nonce=n3&_sign=" + sign("POST", "/track", now, "n3", body),
+			body:     body,
+			wantCode: http.StatusOK,
+		},
+		{name: "missing signature", enabled: true, body: body, wantCode: http.StatusForbidden},
+		{
+			name:     "tampered body",
+			enabled:  true,
+			header:   map[string]string{"X-Timestamp": now, "X-Nonce": "n4", "X-Signature": sign("POST", "/track", now, "n4", body)},
+			body:     `{"path":"/posts/2"}`,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "wrong path",
+			enabled:  true,
+			header:   map[string]string{"X-Timestamp": now, "X-Nonce": "n5", "X-Signature": sign("POST", "/other", now, "n5", body)},
+			body:     body,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "expired timestamp",
+			enabled:  true,
+			header:   map[string]string{"X-Timestamp": stale, "X-Nonce": "n6", "X-Signature": sign("POST", "/track", stale, "n6", body)},
+			body:     body,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "malformed timestamp",
+			enabled:  true,
+			header:   map[string]string{"X-Timestamp": "abc", "X-Nonce": "n7", "X-Signature": sign("POST", "/track", "abc", "n7", body)},
+			body:     body,
+			wantCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupSigning(t, tt.enabled)
+			r := gin.New()
+			var received string
+			r.POST("/track", SignedRequest(), func(c *gin.Context) {
+				raw, _ := c.GetRawData()
+				received = string(raw)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/track"+tt.query, strings.NewReader(tt.body))
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			code, resp := serveSigned(r, req)
+			if code != tt.wantCode {
+				t.Fatalf("status = %d, want %d, body %s", code, tt.wantCode, resp)
+			}
+			if tt.wantCode == http.StatusOK && received != tt.body {
+				t.Errorf("handler received body %q, want %q", received, tt.body)
+			}
+		})
+	}
+}
+
+func TestSignedRequestRejectsReplay(t *testing.T) {
+	setupSigning(t, true)
+	r := gin.New()
+	r.POST("/track", SignedRequest(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := sign("POST", "/track", now, "replay", "")
+	for i, want := range []int{http.StatusOK, http.StatusForbidden} {
+		req := httptest.NewRequest(http.MethodPost, "/track", nil)
+		req.Header.Set("X-Timestamp", now)
+		req.Header.Set("X-Nonce", "replay")
+		req.Header.Set("X-Signature", signature)
+		if code, resp := serveSigned(r, req); code != want {
+			t.Fatalf("request %d: status = %d, want %d, body %s", i+1, code, want, resp)
+		}
+	}
+}
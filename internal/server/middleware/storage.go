@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/pkg/oss"
+)
+
+// rawBodyKey 改写前的请求体，请求签名按原始内容校验
+const rawBodyKey = "storage_raw_body"
+
+// StorageURLs 私有 bucket 模式下改写请求和响应中的 OSS 地址
+// JSON 响应中的 base_url 地址替换为临时签名地址；JSON 请求中的地址去掉签名参数，
+// 保证数据库中保存的始终是 base_url/对象键 形式。文件下载、导出等非 JSON 响应不做处理
+func StorageURLs() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !oss.Private() {
+			c.Next()
+			return
+		}
+
+		if c.Request.Body != nil && strings.HasPrefix(c.GetHeader("Content-Type"), "application/json") {
+			body, err := io.ReadAll(c.Request.Body)
+			c.Request.Body.Close()
+			if err == nil {
+				if unsigned := oss.UnsignContent(string(body)); unsigned != string(body) {
+					c.Set(rawBodyKey, body)
+					body = []byte(unsigned)
+				}
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			c.Request.ContentLength = int64(len(body))
+		}
+
+		writer := &signingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		writer.flush()
+	}
+}
+
+// signingWriter 缓存 JSON 响应，处理完成后统一替换地址；其他类型的响应直接输出
+type signingWriter struct {
+	gin.ResponseWriter
+	buf       bytes.Buffer
+	buffering bool
+	decided   bool
+}
+
+func (w *signingWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		w.buffering = strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+	}
+	if w.buffering {
+		return w.buf.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *signingWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// flush 输出替换后的 JSON 响应
+func (w *signingWriter) flush() {
+	if !w.buffering {
+		return
+	}
+	body := oss.SignContent(w.buf.String())
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.ResponseWriter.WriteString(body)
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// APIVersion 记录请求的 API 版本（/api/v1、/api/v2 路由组使用），响应层据此选择对应版本的数据结构
+func APIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(response.VersionKey, version)
+		c.Next()
+	}
+}
+
+// unversionedPath 去掉路由的版本前缀，使各版本共用同一套路由权限规则
+func unversionedPath(c *gin.Context, path string) string {
+	if version := c.GetString(response.VersionKey); version != "" {
+		return strings.TrimPrefix(path, "/api/"+version)
+	}
+	return path
+}
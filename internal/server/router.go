@@ -2,13 +2,14 @@ package server
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
 	"github.com/ydcloud-dy/leaf-api/internal/server/middleware"
 	"github.com/ydcloud-dy/leaf-api/internal/service"
 )
 
 // registerRoutes 注册路由
 func registerRoutes(
-	r *gin.Engine,
+	r gin.IRouter,
 	authService *service.AuthService,
 	articleService *service.ArticleService,
 	userService *service.UserService,
@@ -23,21 +24,92 @@ func registerRoutes(
 	onlineService *service.OnlineService,
 	visitService *service.VisitService,
 	analyticsService *service.AnalyticsService,
+	permissionService *service.PermissionService,
+	searchService *service.SearchService,
+	seriesService *service.SeriesService,
+	graphqlService *service.GraphQLService,
+	webhookService *service.WebhookService,
+	yuqueService *service.YuqueService,
+	backupService *service.BackupService,
+	attachmentService *service.AttachmentService,
+	mediaService *service.MediaService,
+	reactionService *service.ReactionService,
+	commentBlockService *service.CommentBlockService,
+	feedService *service.FeedService,
+	oauthService *service.OAuthService,
+	apiKeyService *service.APIKeyService,
+	rbacService *service.RBACService,
+	loginLogService *service.LoginLogService,
+	captchaService *service.CaptchaService,
+	userProfileService *service.UserProfileService,
+	followService *service.FollowService,
+	favoriteService *service.FavoriteService,
+	readingProgressService *service.ReadingProgressService,
+	mailService *service.MailService,
+	pushService *service.PushService,
+	realtimeService *service.RealtimeService,
+	presenceService *service.PresenceService,
+	friendLinkService *service.FriendLinkService,
+	guestbookService *service.GuestbookService,
+	momentService *service.MomentService,
+	pageService *service.PageService,
+	menuService *service.MenuService,
+	bannerService *service.BannerService,
+	announcementService *service.AnnouncementService,
 ) {
+	// 人机验证：登录、注册、找回密码和发表评论前先完成验证，凭证放在 X-Captcha-Ticket 请求头中
+	r.GET("/captcha", captchaService.Generate)
+	r.POST("/captcha/verify", captchaService.Verify)
+	captcha := func(scene string) gin.HandlerFunc {
+		return middleware.Captcha(captchaService, scene)
+	}
+
 	// 管理后台认证路由（不需要 JWT 验证）
 	auth := r.Group("/auth")
 	{
-		auth.POST("/login", authService.Login)
+		auth.POST("/login", middleware.RateLimit(middleware.RateLimitLogin), captcha(biz.CaptchaSceneLogin), authService.Login)
 		auth.POST("/logout", authService.Logout)
+		auth.POST("/refresh", authService.Refresh)
 		auth.GET("/profile", middleware.JWTAuth(), authService.GetProfile)
 		auth.PUT("/profile", middleware.JWTAuth(), authService.UpdateProfile)
+		auth.POST("/keys/rotate", middleware.JWTAuth(), authService.RotateSigningKey)
+		auth.DELETE("/keys/:id", middleware.JWTAuth(), authService.RevokeSigningKey)
+
+		// 登录会话（管理后台和博客前台通用）
+		auth.GET("/sessions", middleware.JWTAuth(), authService.ListSessions)
+		auth.DELETE("/sessions", middleware.JWTAuth(), authService.LogoutAll)
+		auth.DELETE("/sessions/:id", middleware.JWTAuth(), authService.RevokeSession)
+		auth.GET("/login-logs", middleware.JWTAuth(), loginLogService.ListMine)
+
+		// 两步验证：登录时凭两步验证令牌提交验证码，登录后在个人设置中管理
+		auth.POST("/2fa/challenge/setup", authService.SetupTwoFactorChallenge)
+		auth.POST("/2fa/challenge/verify", authService.VerifyTwoFactor)
+		auth.GET("/2fa", middleware.JWTAuth(), authService.GetTwoFactorStatus)
+		auth.POST("/2fa/setup", middleware.JWTAuth(), authService.SetupTwoFactor)
+		auth.POST("/2fa/enable", middleware.JWTAuth(), authService.EnableTwoFactor)
+		auth.POST("/2fa/disable", middleware.JWTAuth(), authService.DisableTwoFactor)
+		auth.POST("/2fa/recovery-codes", middleware.JWTAuth(), authService.RegenerateRecoveryCodes)
+
+		// 个人访问令牌（API Key），只能使用登录令牌管理
+		auth.GET("/api-keys", middleware.JWTAuth(), apiKeyService.List)
+		auth.POST("/api-keys", middleware.JWTAuth(), apiKeyService.Create)
+		auth.DELETE("/api-keys/:id", middleware.JWTAuth(), apiKeyService.Revoke)
+
+		// 第三方登录（博客前台用户）
+		auth.GET("/oauth", oauthService.Providers)
+		auth.GET("/oauth/:provider", oauthService.Authorize)
+		auth.GET("/oauth/:provider/callback", oauthService.Callback)
 	}
 
 	// 博客前台认证路由（不需要 JWT 验证）
 	blogAuth := r.Group("/blog/auth")
 	{
-		blogAuth.POST("/register", blogService.Register)
-		blogAuth.POST("/login", blogService.Login)
+		blogAuth.POST("/register", captcha(biz.CaptchaSceneRegister), blogService.Register)
+		blogAuth.POST("/login", middleware.RateLimit(middleware.RateLimitLogin), captcha(biz.CaptchaSceneLogin), blogService.Login)
+		blogAuth.POST("/verify-email", blogService.VerifyEmail)
+		blogAuth.POST("/verify-email/resend", blogService.ResendVerification)
+		blogAuth.POST("/password/forgot", captcha(biz.CaptchaScenePasswordReset), blogService.ForgotPassword)
+		blogAuth.POST("/password/reset", blogService.ResetPassword)
 		blogAuth.GET("/me", middleware.JWTAuth(), blogService.GetUserInfo)
 		blogAuth.PUT("/profile", middleware.JWTAuth(), blogService.UpdateProfile)
 		blogAuth.PUT("/password", middleware.JWTAuth(), blogService.ChangePassword)
@@ -47,10 +119,15 @@ func registerRoutes(
 	blog := r.Group("/blog")
 	{
 		// 文章相关
-		blog.GET("/articles", articleService.List)           // 文章列表
-		blog.GET("/articles/search", articleService.Search)  // 搜索文章
+		blog.GET("/articles", articleService.ListPublic)      // 文章列表
 		blog.GET("/articles/archive", articleService.Archive) // 归档文章
 		blog.GET("/articles/:id/adjacent", articleService.GetAdjacentArticles) // 获取上一篇和下一篇文章
+		blog.GET("/articles/:id/comments/feed", feedService.ArticleComments)   // 文章评论 RSS 订阅
+		blog.GET("/articles/:id/readers", presenceService.ArticleReaders)      // 正在阅读文章的人数
+
+		// 搜索（按 IP 限流）
+		blog.GET("/articles/search", middleware.RateLimit(middleware.RateLimitSearch), articleService.Search) // 搜索文章
+		blog.GET("/search", middleware.RateLimit(middleware.RateLimitSearch), searchService.Search)           // 全文搜索（高亮片段）
 
 		// 分类和标签
 		blog.GET("/categories", categoryService.List) // 分类列表
@@ -59,6 +136,15 @@ func registerRoutes(
 		// 章节
 		blog.GET("/chapters/:tag", chapterService.GetChaptersByTag) // 获取标签下的章节及文章
 
+		// 系列
+		blog.GET("/series", seriesService.ListPublic)              // 系列列表
+		blog.GET("/series/:id", seriesService.GetPublic)           // 系列详情及目录
+		blog.GET("/articles/:id/series", seriesService.Navigation) // 文章所在系列的导航
+
+		// 附件（加密文章需携带访问令牌）
+		blog.GET("/articles/:id/attachments", attachmentService.ListByArticle) // 文章附件列表
+		blog.GET("/attachments/:id/download", attachmentService.Download)      // 下载附件并计数
+
 		// 统计
 		blog.GET("/stats", statsService.GetStats) // 站点统计
 		blog.GET("/stats/hot-articles", statsService.GetHotArticles) // 热门文章
@@ -66,8 +152,34 @@ func registerRoutes(
 		// 博主信息（关于页面使用）
 		blog.GET("/blogger", blogService.GetBloggerInfo) // 获取博主信息
 
+		// 用户主页
+		blog.GET("/users/:username/profile", userProfileService.GetPublic)   // 用户公开主页
+		blog.GET("/users/:username/followers", followService.ListFollowers) // 粉丝列表
+		blog.GET("/users/:username/following", followService.ListFollowing) // 关注列表
+
 		// 站点设置（公开访问，用于前端显示备案信息等）
-		blog.GET("/settings", settingsService.Get) // 获取站点设置
+		blog.GET("/settings", settingsService.GetPublic) // 获取站点设置
+		blog.GET("/theme", settingsService.GetTheme)     // 获取外观设置
+
+		// 浏览器推送
+		blog.GET("/push/vapid-key", pushService.VAPIDKey)           // 获取推送公钥
+		blog.DELETE("/push/subscriptions", pushService.Unsubscribe) // 取消推送订阅
+
+		// 友情链接
+		blog.GET("/friend-links", friendLinkService.ListVisible) // 友链列表
+
+		// 独立页面
+		blog.GET("/pages", pageService.ListPublished)     // 已发布页面列表
+		blog.GET("/pages/:slug", pageService.GetPublished) // 页面详情
+
+		// 导航菜单
+		blog.GET("/menus", menuService.PublicTree) // 导航菜单树
+
+		// 首页横幅
+		blog.GET("/banners", bannerService.Active) // 正在展示的横幅
+
+		// 站点公告
+		blog.GET("/announcements", announcementService.Active) // 正在展示的公告
 	}
 
 	// 博客可选认证路由（支持登录和未登录状态）
@@ -75,97 +187,258 @@ func registerRoutes(
 	blogOptionalAuth.Use(middleware.OptionalJWTAuth())
 	{
 		// 在线追踪（登录用户按 UserID，未登录按 IP）
-		blogOptionalAuth.POST("/heartbeat", onlineService.RecordHeartbeat) // 心跳接口
-		blogOptionalAuth.POST("/visit", visitService.RecordVisitDuration)  // 记录访问时长
+		blogOptionalAuth.GET("/presence/ws", presenceService.Connect)                                  // 在线状态 WebSocket，断开即离线
+		blogOptionalAuth.POST("/heartbeat", middleware.SignedRequest(), onlineService.RecordHeartbeat) // 心跳接口（不支持 WebSocket 时回退）
+		blogOptionalAuth.POST("/heartbeat/leave", onlineService.LeaveHeartbeat)                        // 心跳回退方案下离开页面
+		blogOptionalAuth.POST("/visit", middleware.SignedRequest(), visitService.RecordVisitDuration)  // 记录访问时长
 
 		// 文章详情（登录用户可查看点赞收藏状态）
 		blogOptionalAuth.GET("/articles/:id", blogService.GetArticleDetail)
+		blogOptionalAuth.GET("/articles/slug/:slug", blogService.GetArticleDetailBySlug)
+		blogOptionalAuth.POST("/articles/:id/unlock", blogService.UnlockArticle)
 		// 文章评论（登录用户可查看点赞状态）
 		blogOptionalAuth.GET("/articles/:id/comments", blogService.GetArticleComments)
+		blogOptionalAuth.GET("/comments/:id/replies", blogService.GetCommentReplies)
 		// 留言板（登录用户可查看点赞状态）
-		blogOptionalAuth.GET("/guestbook", blogService.GetGuestbookMessages)
+		blogOptionalAuth.GET("/guestbook", guestbookService.GetMessages)
+		// 表情回应（登录用户按用户去重，游客按 IP 去重）
+		blogOptionalAuth.POST("/articles/:id/reactions", middleware.SignedRequest(), reactionService.ToggleArticle)
+		blogOptionalAuth.POST("/comments/:id/reactions", middleware.SignedRequest(), reactionService.ToggleComment)
+		// 浏览器推送订阅（登录用户关联到账号）
+		blogOptionalAuth.POST("/push/subscriptions", pushService.Subscribe)
+		// 实时事件流（SSE），登录用户额外接收自己的通知
+		blogOptionalAuth.GET("/events", realtimeService.Stream)
+		// 申请友链（登录用户关联到账号）
+		blogOptionalAuth.POST("/friend-links/apply", captcha(biz.CaptchaSceneFriendLink), friendLinkService.Apply)
+		// 说说（登录用户可查看点赞状态）
+		blogOptionalAuth.GET("/moments", momentService.Timeline)
+		blogOptionalAuth.GET("/moments/:id", momentService.Get)
 	}
 
+	// GraphQL 查询（只读，支持登录和未登录状态）
+	r.GET("/graphql", middleware.OptionalJWTAuth(), graphqlService.Query)
+	r.POST("/graphql", middleware.OptionalJWTAuth(), graphqlService.Query)
+
 	// 博客需要认证的路由
 	blogAuthed := r.Group("/blog")
 	blogAuthed.Use(middleware.JWTAuth())
 	{
 		// 点赞
-		blogAuthed.POST("/articles/:id/like", blogService.LikeArticle)
+		blogAuthed.POST("/articles/:id/like", middleware.SignedRequest(), blogService.LikeArticle)
 		blogAuthed.DELETE("/articles/:id/like", blogService.UnlikeArticle)
 
 		// 收藏
-		blogAuthed.POST("/articles/:id/favorite", blogService.FavoriteArticle)
+		blogAuthed.POST("/articles/:id/favorite", middleware.SignedRequest(), blogService.FavoriteArticle)
 		blogAuthed.DELETE("/articles/:id/favorite", blogService.UnfavoriteArticle)
+		blogAuthed.GET("/favorites", favoriteService.List) // 我的收藏，可按收藏夹筛选
+		blogAuthed.POST("/favorites", middleware.SignedRequest(), favoriteService.Add) // 收藏到指定收藏夹
+		blogAuthed.DELETE("/favorites/:article_id", favoriteService.Remove) // 取消收藏
+		blogAuthed.PUT("/favorites/move", favoriteService.Move) // 批量移动收藏
+		blogAuthed.GET("/favorites/folders", favoriteService.ListFolders) // 收藏夹列表
+		blogAuthed.POST("/favorites/folders", favoriteService.CreateFolder) // 创建收藏夹
+		blogAuthed.PUT("/favorites/folders/:id", favoriteService.UpdateFolder) // 修改收藏夹
+		blogAuthed.DELETE("/favorites/folders/:id", favoriteService.DeleteFolder) // 删除收藏夹
 
 		// 用户点赞和收藏列表
 		blogAuthed.GET("/user/likes", blogService.GetUserLikes)
 		blogAuthed.GET("/user/favorites", blogService.GetUserFavorites)
 		blogAuthed.GET("/user/stats", blogService.GetUserStats)
+		blogAuthed.GET("/user/export", blogService.ExportUserData)
+		blogAuthed.PUT("/users/me/profile", userProfileService.UpdateMine) // 更新主页资料
+
+		// 关注
+		blogAuthed.POST("/users/:username/follow", middleware.SignedRequest(), followService.Follow)
+		blogAuthed.DELETE("/users/:username/follow", followService.Unfollow)
+		blogAuthed.GET("/user/feed", followService.Feed) // 关注的作者发布的文章
+
+		// 笔记阅读进度
+		blogAuthed.GET("/progress/books", readingProgressService.ListBooks) // 读过的笔记及完成进度
+		blogAuthed.GET("/progress/books/:tag", readingProgressService.GetBook) // 笔记的已读文章和继续阅读位置
+		blogAuthed.DELETE("/progress/books/:tag", readingProgressService.ResetBook)
+		blogAuthed.PUT("/progress/articles/:id", readingProgressService.Update) // 上报文章阅读进度
+
+		// 实时事件连接票据（EventSource 无法设置请求头）
+		blogAuthed.POST("/events/ticket", realtimeService.IssueTicket)
 
 		// 评论
-		blogAuthed.POST("/comments", blogService.CreateComment)
-		blogAuthed.POST("/comments/:id/like", blogService.LikeComment)
+		blogAuthed.POST("/comments", middleware.RateLimit(middleware.RateLimitComment), middleware.SignedRequest(), captcha(biz.CaptchaSceneComment), blogService.CreateComment)
+		blogAuthed.POST("/comments/:id/like", middleware.SignedRequest(), blogService.LikeComment)
 		blogAuthed.DELETE("/comments/:id/like", blogService.UnlikeComment)
+		blogAuthed.PUT("/comments/:id", middleware.SignedRequest(), blogService.UpdateComment)
 		blogAuthed.DELETE("/comments/:id", blogService.DeleteComment)
 
 		// 留言板
-		blogAuthed.POST("/guestbook", blogService.CreateGuestbookMessage)
-		blogAuthed.DELETE("/guestbook/:id", blogService.DeleteGuestbookMessage)
+		blogAuthed.POST("/guestbook", middleware.RateLimit(middleware.RateLimitComment), middleware.SignedRequest(), captcha(biz.CaptchaSceneComment), guestbookService.CreateMessage)
+		blogAuthed.DELETE("/guestbook/:id", guestbookService.DeleteMessage)
+
+		// 说说点赞
+		blogAuthed.POST("/moments/:id/like", middleware.SignedRequest(), momentService.Like)
+		blogAuthed.DELETE("/moments/:id/like", momentService.Unlike)
 	}
 
-	// 管理后台 API 路由（需要 JWT 验证）
+	// 管理后台 API 路由（需要 JWT 或 API Key 验证）
 	api := r.Group("/")
-	api.Use(middleware.JWTAuth())
+	api.Use(middleware.APIKeyAuth(apiKeyService), middleware.JWTAuth(), middleware.RoutePermission(permissionService))
+	// 操作权限：路由权限控制角色能否进入接口分组，操作权限按角色拥有的权限点控制具体操作
+	requirePermission := func(permission string) gin.HandlerFunc {
+		return middleware.RequirePermission(rbacService, permission)
+	}
 	{
 		// 用户管理
-		users := api.Group("/users")
+		users := api.Group("/users", requirePermission("user:manage"))
 		{
 			users.GET("", userService.List)
 			users.GET("/:id", userService.GetByID)
+			users.GET("/:id/export", userService.ExportData)
 			users.POST("", userService.Create)
 			users.PUT("/:id", userService.Update)
 			users.DELETE("/:id", userService.Delete)
 		}
 
+		// 登录日志
+		api.GET("/login-logs", requirePermission("user:manage"), loginLogService.List)
+
 		// 文章管理
 		articles := api.Group("/articles")
 		{
-			articles.GET("", articleService.List)
-			articles.GET("/:id", articleService.GetByID)
-			articles.POST("", articleService.Create)
-			articles.POST("/import", articleService.ImportMarkdown)
-			articles.POST("/export", articleService.Export)
-			articles.POST("/batch-update-cover", articleService.BatchUpdateCover)
-			articles.POST("/batch-update-fields", articleService.BatchUpdateFields)
-			articles.POST("/batch-delete", articleService.BatchDelete)
-			articles.PUT("/:id", articleService.Update)
-			articles.PATCH("/:id/status", articleService.UpdateStatus)
-			articles.DELETE("/:id", articleService.Delete)
+			articles.GET("", requirePermission("article:read"), articleService.List)
+			articles.GET("/:id", requirePermission("article:read"), articleService.GetByID)
+			articles.POST("", requirePermission("article:create"), articleService.Create)
+			articles.POST("/import", requirePermission("article:create"), articleService.ImportMarkdown)
+			articles.POST("/import/notion", requirePermission("article:create"), articleService.ImportNotion)
+			articles.POST("/export", requirePermission("article:read"), articleService.Export)
+			articles.GET("/export/jobs/:id", requirePermission("article:read"), articleService.GetExportJob)
+			articles.GET("/export/jobs/:id/download", requirePermission("article:read"), articleService.DownloadExportJob)
+			articles.POST("/export/pdf", requirePermission("article:read"), articleService.ExportPDF)
+			articles.POST("/export/epub", requirePermission("article:read"), articleService.ExportEPUB)
+			articles.POST("/batch-update-cover", requirePermission("article:update"), articleService.BatchUpdateCover)
+			articles.POST("/batch-update-fields", requirePermission("article:update"), articleService.BatchUpdateFields)
+			articles.POST("/batch-delete", requirePermission("article:delete"), articleService.BatchDelete)
+			articles.POST("/batch/status", requirePermission("article:publish"), articleService.BatchUpdateStatus)
+			articles.GET("/trash", requirePermission("article:delete"), articleService.ListTrash)
+			articles.POST("/trash/restore", requirePermission("article:delete"), articleService.RestoreTrash)
+			articles.POST("/trash/purge", requirePermission("article:delete"), articleService.PurgeTrash)
+			articles.GET("/audit-logs", requirePermission("article:read"), articleService.ListAuditLogs)
+			articles.PUT("/:id", requirePermission("article:update"), articleService.Update)
+			articles.PATCH("/:id/status", requirePermission("article:publish"), articleService.UpdateStatus)
+			articles.PUT("/:id/pin", requirePermission("article:publish"), articleService.Pin)
+			articles.DELETE("/:id/pin", requirePermission("article:publish"), articleService.Unpin)
+			articles.POST("/:id/clone", requirePermission("article:create"), articleService.Clone)
+			articles.POST("/:id/autosave", requirePermission("article:update"), articleService.Autosave)
+			articles.GET("/:id/autosave", requirePermission("article:update"), articleService.GetDraft)
+			articles.DELETE("/:id/autosave", requirePermission("article:update"), articleService.DiscardDraft)
+			articles.POST("/:id/lock", requirePermission("article:update"), articleService.AcquireLock)
+			articles.PUT("/:id/lock", requirePermission("article:update"), articleService.AcquireLock)
+			articles.DELETE("/:id/lock", requirePermission("article:update"), articleService.ReleaseLock)
+			articles.PUT("/:id/authors", requirePermission("article:update"), articleService.SetAuthors)
+			articles.POST("/:id/authors", requirePermission("article:update"), articleService.AddAuthor)
+			articles.DELETE("/:id/authors/:user_id", requirePermission("article:update"), articleService.RemoveAuthor)
+			articles.GET("/:id/translations", requirePermission("article:read"), articleService.ListTranslations)
+			articles.POST("/:id/translations", requirePermission("article:update"), articleService.LinkTranslation)
+			articles.DELETE("/:id/translations", requirePermission("article:update"), articleService.UnlinkTranslation)
+			articles.GET("/:id/versions", requirePermission("article:read"), articleService.ListVersions)
+			articles.GET("/:id/versions/:version_id", requirePermission("article:read"), articleService.GetVersion)
+			articles.GET("/:id/versions/:version_id/diff", requirePermission("article:read"), articleService.DiffVersion)
+			articles.POST("/:id/versions/:version_id/restore", requirePermission("article:update"), articleService.RestoreVersion)
+			articles.DELETE("/:id", requirePermission("article:delete"), articleService.Delete)
 		}
 
 		// 评论管理
-		comments := api.Group("/comments")
+		comments := api.Group("/comments", requirePermission("comment:moderate"))
 		{
 			comments.GET("", commentService.List)
+			comments.GET("/export", commentService.Export)
 			comments.DELETE("/:id", commentService.Delete)
 			comments.PATCH("/:id/status", commentService.UpdateStatus)
+			comments.GET("/:id/edits", commentService.ListEdits)
+			comments.POST("/batch/status", commentService.BatchUpdateStatus)
+			comments.GET("/block-rules", commentBlockService.List)
+			comments.POST("/block-rules", commentBlockService.Create)
+			comments.PUT("/block-rules/:id", commentBlockService.Update)
+			comments.DELETE("/block-rules/:id", commentBlockService.Delete)
+		}
+
+		// 留言管理
+		guestbook := api.Group("/guestbook", requirePermission("comment:moderate"))
+		{
+			guestbook.GET("", guestbookService.List)
+			guestbook.PATCH("/:id/status", guestbookService.UpdateStatus)
+			guestbook.DELETE("/:id", guestbookService.Delete)
+		}
+
+		// 友情链接
+		friendLinks := api.Group("/friend-links", requirePermission("site:manage"))
+		{
+			friendLinks.GET("", friendLinkService.List)
+			friendLinks.POST("", friendLinkService.Create)
+			friendLinks.PUT("/sort", friendLinkService.Sort)
+			friendLinks.PUT("/:id", friendLinkService.Update)
+			friendLinks.DELETE("/:id", friendLinkService.Delete)
+			friendLinks.POST("/:id/review", friendLinkService.Review)
+			friendLinks.POST("/:id/check", friendLinkService.Check)
+		}
+
+		// 说说管理
+		moments := api.Group("/moments", requirePermission("site:manage"))
+		{
+			moments.GET("", momentService.List)
+			moments.POST("", momentService.Create)
+			moments.PUT("/:id", momentService.Update)
+			moments.DELETE("/:id", momentService.Delete)
+		}
+
+		// 独立页面管理
+		pages := api.Group("/pages", requirePermission("site:manage"))
+		{
+			pages.GET("", pageService.List)
+			pages.GET("/:id", pageService.Get)
+			pages.POST("", pageService.Create)
+			pages.PUT("/:id", pageService.Update)
+			pages.DELETE("/:id", pageService.Delete)
+		}
+
+		// 导航菜单管理
+		menus := api.Group("/menus", requirePermission("site:manage"))
+		{
+			menus.GET("", menuService.Tree)
+			menus.POST("", menuService.Create)
+			menus.PUT("/sort", menuService.Sort)
+			menus.PUT("/:id", menuService.Update)
+			menus.DELETE("/:id", menuService.Delete)
+		}
+
+		// 首页横幅管理
+		banners := api.Group("/banners", requirePermission("site:manage"))
+		{
+			banners.GET("", bannerService.List)
+			banners.POST("", bannerService.Create)
+			banners.PUT("/:id", bannerService.Update)
+			banners.DELETE("/:id", bannerService.Delete)
+		}
+
+		// 站点公告管理
+		announcements := api.Group("/announcements", requirePermission("site:manage"))
+		{
+			announcements.GET("", announcementService.List)
+			announcements.POST("", announcementService.Create)
+			announcements.PUT("/:id", announcementService.Update)
+			announcements.DELETE("/:id", announcementService.Delete)
 		}
 
 		// 标签管理
 		tags := api.Group("/tags")
 		{
 			tags.GET("", tagService.List)
-			tags.POST("", tagService.Create)
-			tags.DELETE("/:id", tagService.Delete)
+			tags.POST("", requirePermission("taxonomy:manage"), tagService.Create)
+			tags.DELETE("/:id", requirePermission("taxonomy:manage"), tagService.Delete)
 		}
 
 		// 分类管理
 		categories := api.Group("/categories")
 		{
 			categories.GET("", categoryService.List)
-			categories.POST("", categoryService.Create)
-			categories.DELETE("/:id", categoryService.Delete)
+			categories.POST("", requirePermission("taxonomy:manage"), categoryService.Create)
+			categories.DELETE("/:id", requirePermission("taxonomy:manage"), categoryService.Delete)
 		}
 
 		// 章节管理
@@ -173,20 +446,66 @@ func registerRoutes(
 		{
 			chapters.GET("", chapterService.GetChapters)
 			chapters.GET("/:id", chapterService.GetChapter)
-			chapters.POST("", chapterService.CreateChapter)
-			chapters.PUT("/:id", chapterService.UpdateChapter)
-			chapters.DELETE("/:id", chapterService.DeleteChapter)
+			chapters.POST("", requirePermission("taxonomy:manage"), chapterService.CreateChapter)
+			chapters.PUT("/:id", requirePermission("taxonomy:manage"), chapterService.UpdateChapter)
+			chapters.DELETE("/:id", requirePermission("taxonomy:manage"), chapterService.DeleteChapter)
+		}
+
+		// 系列管理
+		series := api.Group("/series")
+		{
+			series.GET("", seriesService.List)
+			series.GET("/:id", seriesService.Get)
+			series.POST("", requirePermission("taxonomy:manage"), seriesService.Create)
+			series.PUT("/:id", requirePermission("taxonomy:manage"), seriesService.Update)
+			series.PUT("/:id/articles", requirePermission("taxonomy:manage"), seriesService.SetArticles)
+			series.DELETE("/:id", requirePermission("taxonomy:manage"), seriesService.Delete)
+		}
+
+		// Webhook 管理
+		webhooks := api.Group("/webhooks", requirePermission("setting:manage"))
+		{
+			webhooks.GET("", webhookService.List)
+			webhooks.GET("/:id", webhookService.Get)
+			webhooks.POST("", webhookService.Create)
+			webhooks.PUT("/:id", webhookService.Update)
+			webhooks.DELETE("/:id", webhookService.Delete)
+			webhooks.POST("/:id/ping", webhookService.Ping)
+			webhooks.GET("/:id/deliveries", webhookService.ListDeliveries)
+			webhooks.POST("/:id/deliveries/:delivery_id/redeliver", webhookService.Redeliver)
+		}
+
+		// 语雀同步
+		yuqueSyncs := api.Group("/yuque/syncs", requirePermission("setting:manage"))
+		{
+			yuqueSyncs.GET("", yuqueService.List)
+			yuqueSyncs.GET("/:id", yuqueService.Get)
+			yuqueSyncs.POST("", yuqueService.Create)
+			yuqueSyncs.PUT("/:id", yuqueService.Update)
+			yuqueSyncs.DELETE("/:id", yuqueService.Delete)
+			yuqueSyncs.POST("/:id/sync", yuqueService.Sync)
+		}
+
+		// 数据备份
+		backups := api.Group("/backups")
+		{
+			backups.GET("", backupService.List)
+			backups.POST("", backupService.Create)
+			backups.GET("/:name/download", backupService.Download)
+			backups.POST("/restore", backupService.Restore)
+			backups.GET("/export", backupService.Export)
+			backups.POST("/import", backupService.Import)
 		}
 
 		// 统计
-		stats := api.Group("/stats")
+		stats := api.Group("/stats", requirePermission("stats:read"))
 		{
 			stats.GET("", statsService.GetStats)
 			stats.GET("/hot-articles", statsService.GetHotArticles)
 		}
 
 		// 数据分析
-		analytics := api.Group("/analytics")
+		analytics := api.Group("/analytics", requirePermission("stats:read"))
 		{
 			analytics.GET("/visits/7days", analyticsService.Get7DaysVisits)
 			analytics.GET("/online/users", analyticsService.GetOnlineUsers)
@@ -199,15 +518,65 @@ func registerRoutes(
 		settings := api.Group("/settings")
 		{
 			settings.GET("", settingsService.Get)
-			settings.PUT("", settingsService.Update)
+			settings.GET("/schema", settingsService.GetSchema)
+			settings.PUT("", requirePermission("setting:manage"), settingsService.Update)
 		}
 
+		// 路由权限
+		permissions := api.Group("/permissions")
+		{
+			permissions.GET("/routes", permissionService.List)
+			permissions.POST("/routes", permissionService.Create)
+			permissions.PUT("/routes/:id", permissionService.Update)
+			permissions.DELETE("/routes/:id", permissionService.Delete)
+		}
+
+		// 角色权限
+		rbac := api.Group("/rbac")
+		{
+			rbac.GET("/permissions", rbacService.ListPermissions)
+			rbac.GET("/roles", rbacService.ListRoles)
+			rbac.POST("/roles", rbacService.CreateRole)
+			rbac.PUT("/roles/:name", rbacService.UpdateRole)
+			rbac.DELETE("/roles/:name", rbacService.DeleteRole)
+			rbac.PUT("/users/:id/role", rbacService.AssignRole)
+		}
+
+		// 全文搜索
+		api.POST("/search/reindex", requirePermission("setting:manage"), searchService.Reindex)
+
+		// 邮件
+		api.POST("/mail/test", requirePermission("setting:manage"), mailService.SendTest) // 发送测试邮件
+
+		// 浏览器推送
+		api.POST("/push/send", requirePermission("article:publish"), pushService.Send) // 手动推送
+
 		// 文件上传
 		files := api.Group("/files")
 		{
-			files.POST("/upload", fileService.Upload)
-			files.GET("", fileService.List)
-			files.DELETE("/:id", fileService.Delete)
+			files.POST("/upload", requirePermission("file:upload"), fileService.Upload)
+			files.POST("/videos", requirePermission("file:upload"), fileService.UploadVideo)
+			files.GET("", requirePermission("file:manage"), fileService.List)
+			files.DELETE("/:id", requirePermission("file:manage"), fileService.Delete)
+			files.POST("/orphans/clean", requirePermission("file:manage"), fileService.CleanOrphanImages)
+			files.GET("/stats", requirePermission("file:manage"), fileService.StorageStats)
+			files.POST("/uploads", requirePermission("file:upload"), fileService.InitUpload)
+			files.GET("/uploads/:id", requirePermission("file:upload"), fileService.UploadStatus)
+			files.PUT("/uploads/:id/parts/:number", requirePermission("file:upload"), fileService.UploadPart)
+			files.POST("/uploads/:id/complete", requirePermission("file:upload"), fileService.CompleteUpload)
+			files.DELETE("/uploads/:id", requirePermission("file:upload"), fileService.AbortUpload)
+		}
+
+		// 媒体库
+		api.GET("/media", requirePermission("file:upload"), mediaService.List)
+
+		// 附件管理
+		attachments := api.Group("/attachments")
+		{
+			attachments.POST("", requirePermission("file:upload"), attachmentService.Upload)
+			attachments.GET("", requirePermission("file:upload"), attachmentService.List)
+			attachments.PUT("/:id/article", requirePermission("file:upload"), attachmentService.SetArticle)
+			attachments.DELETE("/:id", requirePermission("file:manage"), attachmentService.Delete)
 		}
 	}
 }
@@ -8,7 +8,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ydcloud-dy/leaf-api/internal/data"
 	"github.com/ydcloud-dy/leaf-api/internal/model/po"
-	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+	"github.com/ydcloud-dy/leaf-api/pkg/presence"
 	"github.com/ydcloud-dy/leaf-api/pkg/response"
 )
 
@@ -65,7 +65,7 @@ func (s *AnalyticsService) Get7DaysVisits(c *gin.Context) {
 		// 统计 UV（独立访客数）- 按 IP 去重
 		var uv int64
 		s.data.GetDB().Model(&po.PageVisit{}).
-			Select("COUNT(DISTINCT ip)").
+			Select("COUNT(DISTINCT COALESCE(NULLIF(ip_hash, ''), ip))").
 			Where("created_at >= ? AND created_at < ?", startTime, endTime).
 			Count(&uv)
 		uvData[6-i] = uv
@@ -93,20 +93,13 @@ func (s *AnalyticsService) Get7DaysVisits(c *gin.Context) {
 // @Failure 500 {object} response.Response "服务器错误"
 // @Router /analytics/online/users [get]
 func (s *AnalyticsService) GetOnlineUsers(c *gin.Context) {
-	// 获取所有在线用户的键
-	userKeys, err := redis.Keys(onlineUserPrefix + "*")
+	// 获取所有在线连接
+	conns, err := presence.List()
 	if err != nil {
 		response.ServerError(c, "获取在线用户失败")
 		return
 	}
 
-	// 获取所有在线游客的键
-	guestKeys, err := redis.Keys(onlineGuestPrefix + "*")
-	if err != nil {
-		response.ServerError(c, "获取在线游客失败")
-		return
-	}
-
 	// 在线用户详情列表
 	type OnlineUser struct {
 		UserID         uint      `json:"user_id"`
@@ -114,10 +107,11 @@ func (s *AnalyticsService) GetOnlineUsers(c *gin.Context) {
 		Nickname       string    `json:"nickname"`
 		Avatar         string    `json:"avatar"`
 		IP             string    `json:"ip"`
-		CurrentPage    string    `json:"current_page"`    // 当前访问的页面
+		CurrentPage    string    `json:"current_page"`    // 当前访问的页面（最近活跃的连接）
 		UserAgent      string    `json:"user_agent"`      // 浏览器信息
+		Connections    int       `json:"connections"`     // 打开的页面数
 		LastActiveAt   time.Time `json:"last_active_at"`
-		OnlineDuration int64     `json:"online_duration"` // 在线时长（秒）
+		OnlineDuration int64     `json:"online_duration"` // 在线时长（秒），从最早的连接开始计算
 	}
 
 	// 在线游客详情列表
@@ -125,85 +119,73 @@ func (s *AnalyticsService) GetOnlineUsers(c *gin.Context) {
 		IP           string    `json:"ip"`
 		CurrentPage  string    `json:"current_page"`  // 当前访问的页面
 		UserAgent    string    `json:"user_agent"`    // 浏览器信息
+		Connections  int       `json:"connections"`   // 打开的页面数
 		LastActiveAt time.Time `json:"last_active_at"`
 		Location     string    `json:"location,omitempty"` // IP地理位置（可选）
 	}
 
+	// 同一访客的多个连接合并为一条
+	type visitor struct {
+		latest      *presence.Conn
+		connectedAt time.Time
+		connections int
+	}
+	visitors := make(map[string]*visitor)
+	order := make([]string, 0)
+	for _, conn := range conns {
+		key := conn.Visitor()
+		v, ok := visitors[key]
+		if !ok {
+			v = &visitor{latest: conn, connectedAt: conn.ConnectedAt}
+			visitors[key] = v
+			order = append(order, key)
+		}
+		v.connections++
+		if conn.LastActiveAt.After(v.latest.LastActiveAt) {
+			v.latest = conn
+		}
+		if conn.ConnectedAt.Before(v.connectedAt) {
+			v.connectedAt = conn.ConnectedAt
+		}
+	}
+
 	users := make([]OnlineUser, 0)
 	guests := make([]OnlineGuest, 0)
 
-	client := redis.GetClient()
-	ctx := redis.GetContext()
-
-	// 处理在线用户
-	for _, key := range userKeys {
-		// 提取用户ID
-		userIDStr := strings.TrimPrefix(key, onlineUserPrefix)
-		var userID uint
-		fmt.Sscanf(userIDStr, "%d", &userID)
-
-		// 从 Redis Hash 获取详细信息
-		data, err := client.HGetAll(ctx, key).Result()
-		if err != nil || len(data) == 0 {
+	for _, key := range order {
+		v := visitors[key]
+		conn := v.latest
+
+		// 处理在线游客
+		if conn.UserID == 0 {
+			guests = append(guests, OnlineGuest{
+				IP:           conn.IP,
+				CurrentPage:  conn.Path,
+				UserAgent:    conn.UserAgent,
+				Connections:  v.connections,
+				LastActiveAt: conn.LastActiveAt,
+				Location:     s.getIPLocation(conn.IP), // 获取IP地理位置
+			})
 			continue
 		}
 
-		// 解析时间戳
-		var lastActiveTimestamp int64
-		if ts, ok := data["last_active_at"]; ok {
-			fmt.Sscanf(ts, "%d", &lastActiveTimestamp)
-		}
-		lastActiveAt := time.Unix(lastActiveTimestamp, 0)
-
 		// 从数据库获取用户详情
 		var user po.User
-		if err := s.data.GetDB().First(&user, userID).Error; err != nil {
+		if err := s.data.GetDB().First(&user, conn.UserID).Error; err != nil {
 			continue
 		}
 
-		// 计算在线时长
-		onlineDuration := time.Since(lastActiveAt).Seconds()
-		if onlineDuration < 0 {
-			onlineDuration = 0
-		}
-
 		users = append(users, OnlineUser{
 			UserID:         user.ID,
 			Username:       user.Username,
 			Nickname:       user.Nickname,
 			Avatar:         user.Avatar,
-			IP:             data["ip"],
-			CurrentPage:    data["path"],
-			UserAgent:      data["user_agent"],
-			LastActiveAt:   lastActiveAt,
-			OnlineDuration: int64(onlineDuration),
-		})
-	}
-
-	// 处理在线游客
-	for _, key := range guestKeys {
-		// 提取IP
-		ip := strings.TrimPrefix(key, onlineGuestPrefix)
-
-		// 从 Redis Hash 获取详细信息
-		data, err := client.HGetAll(ctx, key).Result()
-		if err != nil || len(data) == 0 {
-			continue
-		}
-
-		// 解析时间戳
-		var lastActiveTimestamp int64
-		if ts, ok := data["last_active_at"]; ok {
-			fmt.Sscanf(ts, "%d", &lastActiveTimestamp)
-		}
-		lastActiveAt := time.Unix(lastActiveTimestamp, 0)
-
-		guests = append(guests, OnlineGuest{
-			IP:           ip,
-			CurrentPage:  data["path"],
-			UserAgent:    data["user_agent"],
-			LastActiveAt: lastActiveAt,
-			Location:     s.getIPLocation(ip), // 获取IP地理位置
+			IP:             conn.IP,
+			CurrentPage:    conn.Path,
+			UserAgent:      conn.UserAgent,
+			Connections:    v.connections,
+			LastActiveAt:   conn.LastActiveAt,
+			OnlineDuration: int64(time.Since(v.connectedAt).Seconds()),
 		})
 	}
 
@@ -230,24 +212,17 @@ func (s *AnalyticsService) GetOnlineUsers(c *gin.Context) {
 // @Failure 500 {object} response.Response "服务器错误"
 // @Router /analytics/online/stats [get]
 func (s *AnalyticsService) GetOnlineStats(c *gin.Context) {
-	// 获取在线用户数
-	userKeys, err := redis.Keys(onlineUserPrefix + "*")
+	// 按访客去重统计在线用户和游客
+	users, guests, err := presence.Stats()
 	if err != nil {
 		response.ServerError(c, "获取在线用户失败")
 		return
 	}
 
-	// 获取在线游客数
-	guestKeys, err := redis.Keys(onlineGuestPrefix + "*")
-	if err != nil {
-		response.ServerError(c, "获取在线游客失败")
-		return
-	}
-
 	response.Success(c, gin.H{
-		"total":  len(userKeys) + len(guestKeys),
-		"users":  len(userKeys),
-		"guests": len(guestKeys),
+		"total":  users + guests,
+		"users":  users,
+		"guests": guests,
 	})
 }
 
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// AnnouncementService 站点公告服务
+type AnnouncementService struct {
+	announcementUseCase biz.AnnouncementUseCase
+}
+
+// NewAnnouncementService 创建站点公告服务
+func NewAnnouncementService(announcementUseCase biz.AnnouncementUseCase) *AnnouncementService {
+	return &AnnouncementService{
+		announcementUseCase: announcementUseCase,
+	}
+}
+
+// Active 前台公告列表
+// @Summary 获取站点公告
+// @Description 获取正在展示的公告：已启用且当前时间处于展示时间段内，按级别从高到低排列；可关闭的公告由前台按 ID 和更新时间记录关闭状态
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.AnnouncementResponse} "获取成功"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/announcements [get]
+func (s *AnnouncementService) Active(c *gin.Context) {
+	announcements, err := s.announcementUseCase.Active()
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, announcements)
+}
+
+// List 后台公告列表
+// @Summary 获取公告列表
+// @Description 分页获取站点公告，可按展示状态过滤
+// @Tags 公告管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Param status query string false "展示状态：active、scheduled、expired、disabled"
+// @Param keyword query string false "按标题或内容搜索"
+// @Success 200 {object} response.Response{data=[]dto.AnnouncementResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /announcements [get]
+func (s *AnnouncementService) List(c *gin.Context) {
+	req := dto.AnnouncementListRequest{PageRequest: dto.PageRequest{Page: 1, Limit: 10}}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.announcementUseCase.List(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// Create 创建公告
+// @Summary 创建公告
+// @Description 创建站点公告，未指定时默认启用且可关闭；开启推送时在开始展示后推送到通知中心
+// @Tags 公告管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.AnnouncementRequest true "公告信息"
+// @Success 200 {object} response.Response{data=dto.AnnouncementResponse} "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /announcements [post]
+func (s *AnnouncementService) Create(c *gin.Context) {
+	var req dto.AnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	announcement, err := s.announcementUseCase.Create(&req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, announcement)
+}
+
+// Update 更新公告
+// @Summary 更新公告
+// @Description 更新站点公告；启用状态和可关闭为空时不修改，展示时间为空表示不限制；已推送过的公告不会重复推送
+// @Tags 公告管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "公告ID"
+// @Param request body dto.AnnouncementRequest true "公告信息"
+// @Success 200 {object} response.Response{data=dto.AnnouncementResponse} "更新成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "公告不存在"
+// @Router /announcements/{id} [put]
+func (s *AnnouncementService) Update(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.AnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	announcement, err := s.announcementUseCase.Update(idReq.ID, &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, announcement)
+}
+
+// Delete 删除公告
+// @Summary 删除公告
+// @Description 删除站点公告
+// @Tags 公告管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "公告ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "公告不存在"
+// @Router /announcements/{id} [delete]
+func (s *AnnouncementService) Delete(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.announcementUseCase.Delete(idReq.ID); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// handleError 将业务错误映射为响应
+func (s *AnnouncementService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrAnnouncementNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrAnnouncementInvalid):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// APIKeyService API Key 服务
+type APIKeyService struct {
+	apiKeyUseCase biz.APIKeyUseCase
+}
+
+// NewAPIKeyService 创建 API Key 服务
+func NewAPIKeyService(apiKeyUseCase biz.APIKeyUseCase) *APIKeyService {
+	return &APIKeyService{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+// List API Key 列表
+// @Summary 获取 API Key 列表
+// @Description 获取当前管理员创建的 API Key，包含已吊销和已过期的，不返回令牌内容
+// @Tags 认证管理
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]dto.APIKeyInfo} "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Router /auth/api-keys [get]
+func (s *APIKeyService) List(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	keys, err := s.apiKeyUseCase.List(c.GetUint("admin_id"))
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, keys)
+}
+
+// Create 创建 API Key
+// @Summary 创建 API Key
+// @Description 创建个人访问令牌，脚本通过 X-API-Key 请求头调用管理接口，无需用户密码。令牌内容只在响应中返回一次。
+// @Description 权限范围为 资源:read 或 资源:write，资源为管理接口路径的第一段（如 articles、files），read 只允许 GET 请求，* 表示全部；
+// @Description 调用时同时受所属管理员当前角色的路由权限限制
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateAPIKeyRequest true "API Key 信息"
+// @Success 200 {object} response.Response{data=dto.CreateAPIKeyResponse} "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Router /auth/api-keys [post]
+func (s *APIKeyService) Create(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.apiKeyUseCase.Create(c.GetUint("admin_id"), &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Revoke 吊销 API Key
+// @Summary 吊销 API Key
+// @Description 吊销当前管理员的 API Key，吊销后立即失效
+// @Tags 认证管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API Key ID"
+// @Success 200 {object} response.Response "吊销成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "API Key 不存在或已吊销"
+// @Router /auth/api-keys/{id} [delete]
+func (s *APIKeyService) Revoke(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.apiKeyUseCase.Revoke(c.GetUint("admin_id"), req.ID); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// AuthenticateAPIKey 校验 API Key，供 APIKeyAuth 中间件使用
+func (s *APIKeyService) AuthenticateAPIKey(key, method, path string) (*dto.APIKeyIdentity, bool, error) {
+	return s.apiKeyUseCase.Authenticate(key, method, path)
+}
+
+// handleError 将 API Key 业务错误映射为响应
+func (s *APIKeyService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrAPIKeyNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrAPIKeyScopeInvalid):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
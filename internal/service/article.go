@@ -1,8 +1,10 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -10,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ydcloud-dy/leaf-api/internal/biz"
 	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
 	"github.com/ydcloud-dy/leaf-api/pkg/response"
 )
 
@@ -41,14 +44,14 @@ func NewArticleService(articleUseCase biz.ArticleUseCase) *ArticleService {
 func (s *ArticleService) Create(c *gin.Context) {
 	var req dto.CreateArticleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
 	// 获取作者 ID
 	adminID, _ := c.Get("admin_id")
 
-	resp, err := s.articleUseCase.Create(&req, adminID.(uint))
+	resp, err := s.articleUseCase.WithContext(c.Request.Context()).Create(&req, adminID.(uint))
 	if err != nil {
 		response.ServerError(c, err.Error())
 		return
@@ -59,7 +62,7 @@ func (s *ArticleService) Create(c *gin.Context) {
 
 // Update 更新文章
 // @Summary 更新文章
-// @Description 更新文章信息
+// @Description 更新文章信息，提交 revision 时与当前修订号不一致将返回 409
 // @Tags 文章管理
 // @Accept json
 // @Produce json
@@ -69,22 +72,27 @@ func (s *ArticleService) Create(c *gin.Context) {
 // @Success 200 {object} response.Response "更新成功"
 // @Failure 400 {object} response.Response "请求参数错误"
 // @Failure 401 {object} response.Response "未授权"
+// @Failure 409 {object} response.Response "文章已被他人修改"
 // @Failure 500 {object} response.Response "服务器错误"
 // @Router /articles/{id} [put]
 func (s *ArticleService) Update(c *gin.Context) {
 	var idReq dto.IDRequest
 	if err := c.ShouldBindUri(&idReq); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
 	var req dto.UpdateArticleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
-	resp, err := s.articleUseCase.Update(idReq.ID, &req)
+	resp, err := s.operator(c).Update(idReq.ID, &req)
+	if errors.Is(err, biz.ErrArticleConflict) {
+		response.Conflict(c, err.Error())
+		return
+	}
 	if err != nil {
 		response.ServerError(c, err.Error())
 		return
@@ -95,7 +103,7 @@ func (s *ArticleService) Update(c *gin.Context) {
 
 // Delete 删除文章
 // @Summary 删除文章
-// @Description 根据ID删除文章
+// @Description 根据ID删除文章（移入回收站，可恢复）
 // @Tags 文章管理
 // @Accept json
 // @Produce json
@@ -109,11 +117,11 @@ func (s *ArticleService) Update(c *gin.Context) {
 func (s *ArticleService) Delete(c *gin.Context) {
 	var req dto.IDRequest
 	if err := c.ShouldBindUri(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
-	if err := s.articleUseCase.Delete(req.ID); err != nil {
+	if err := s.operator(c).Delete(req.ID); err != nil {
 		response.ServerError(c, err.Error())
 		return
 	}
@@ -137,7 +145,7 @@ func (s *ArticleService) Delete(c *gin.Context) {
 func (s *ArticleService) GetByID(c *gin.Context) {
 	var req dto.IDRequest
 	if err := c.ShouldBindUri(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
@@ -156,17 +164,49 @@ func (s *ArticleService) GetByID(c *gin.Context) {
 // @Tags 文章管理
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(10)
 // @Param category query string false "分类"
 // @Param tag query string false "标签"
+// @Param author_id query int false "作者ID（主作者或共同作者）"
 // @Param status query string false "状态"
 // @Param keyword query string false "搜索关键词"
 // @Param sort query string false "排序方式" default(latest)
+// @Param cursor query string false "游标分页：首页传空值，之后传上一页返回的 next_cursor；游标模式按创建时间倒序，不返回总数；未搜索时首页先返回全部置顶文章（不计入 limit）"
+// @Param fields query string false "只返回指定字段，逗号分隔，如 id,title,summary,cover,created_at,author,category,tags"
 // @Success 200 {object} response.Response "获取成功"
 // @Failure 500 {object} response.Response "服务器错误"
-// @Router /blog/articles [get]
+// @Router /articles [get]
 func (s *ArticleService) List(c *gin.Context) {
+	s.list(c, false)
+}
+
+// ListPublic 博客前台文章列表
+// @Summary 获取博客文章列表
+// @Description 分页获取已发布的文章，不包含私密文章，加密文章不返回摘要
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Param category query string false "分类"
+// @Param tag query string false "标签"
+// @Param author_id query int false "作者ID（主作者或共同作者）"
+// @Param keyword query string false "搜索关键词"
+// @Param sort query string false "排序方式" default(latest)
+// @Param cursor query string false "游标分页：首页传空值，之后传上一页返回的 next_cursor；游标模式按创建时间倒序，不返回总数；未搜索时首页先返回全部置顶文章（不计入 limit）"
+// @Param fields query string false "只返回指定字段，逗号分隔，如 id,title,summary,cover,created_at,author,category,tags"
+// @Param lang query string false "语言代码：翻译组有该语言的已发布版本时返回该版本，否则返回源文章；不传时只返回源文章"
+// @Success 200 {object} response.Response "获取成功"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/articles [get]
+func (s *ArticleService) ListPublic(c *gin.Context) {
+	s.list(c, true)
+}
+
+// list 解析列表参数并查询，public 为 true 时只返回前台可见的文章
+func (s *ArticleService) list(c *gin.Context, public bool) {
 	var req dto.ArticleListRequest
 
 	// 解析分页参数
@@ -184,15 +224,32 @@ func (s *ArticleService) List(c *gin.Context) {
 	req.Category = c.Query("category")
 	req.Tag = c.Query("tag")
 	req.ChapterID = c.Query("chapter_id")
+	if authorID, err := strconv.ParseUint(c.Query("author_id"), 10, 32); err == nil {
+		req.AuthorID = uint(authorID)
+	}
 	req.Status = c.Query("status")
 	req.Keyword = c.Query("keyword")
 	req.Sort = c.DefaultQuery("sort", "latest") // 默认按最新排序
+	req.Fields = c.Query("fields")
+	req.Lang = c.Query("lang")
+	req.Public = public
 
 	// 调试日志
 	fmt.Printf("[文章列表] ChapterID: %s, Status: %s, Keyword: %s\n", req.ChapterID, req.Status, req.Keyword)
 
+	// 传了 cursor 参数（首页为空值）时使用游标分页
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		req.Cursor = cursor
+		s.listByCursor(c, &req)
+		return
+	}
+
 	resp, err := s.articleUseCase.List(&req)
 	if err != nil {
+		if errors.Is(err, biz.ErrInvalidFields) {
+			response.BadRequest(c, err.Error())
+			return
+		}
 		response.ServerError(c, err.Error())
 		return
 	}
@@ -200,6 +257,25 @@ func (s *ArticleService) List(c *gin.Context) {
 	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
 }
 
+// listByCursor 游标分页查询，返回 next_cursor 而不是总数
+func (s *ArticleService) listByCursor(c *gin.Context, req *dto.ArticleListRequest) {
+	if req.Limit < 1 || req.Limit > 100 {
+		req.Limit = 10
+	}
+
+	resp, err := s.articleUseCase.ListByCursor(req)
+	if err != nil {
+		if errors.Is(err, biz.ErrInvalidCursor) || errors.Is(err, biz.ErrInvalidFields) {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithCursor(c, resp.Data, resp.NextCursor, resp.Limit)
+}
+
 // UpdateStatus 更新文章状态
 // @Summary 更新文章状态
 // @Description 更新文章的发布状态（草稿/已发布）
@@ -217,17 +293,111 @@ func (s *ArticleService) List(c *gin.Context) {
 func (s *ArticleService) UpdateStatus(c *gin.Context) {
 	var idReq dto.IDRequest
 	if err := c.ShouldBindUri(&idReq); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
 	var req dto.UpdateArticleStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
-	if err := s.articleUseCase.UpdateStatus(idReq.ID, req.Status); err != nil {
+	if err := s.operator(c).UpdateStatus(idReq.ID, req.Status); err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Clone 复制文章
+// @Summary 复制文章
+// @Description 复制文章的正文、分类、章节和标签为新的草稿，标题追加 copy，正文和封面图片会重新上传
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Success 200 {object} response.Response{data=dto.ArticleResponse} "复制成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/{id}/clone [post]
+func (s *ArticleService) Clone(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.operator(c).Clone(req.ID, c.GetUint("admin_id"))
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Pin 置顶文章
+// @Summary 置顶文章
+// @Description 将文章置顶，博客首页列表中置顶文章按 pinned_sort 降序排在最前
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Param request body dto.PinArticleRequest false "置顶排序"
+// @Success 200 {object} response.Response "置顶成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/{id}/pin [put]
+func (s *ArticleService) Pin(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.PinArticleRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BindError(c, err)
+			return
+		}
+	}
+
+	if err := s.operator(c).Pin(idReq.ID, req.PinnedSort); err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Unpin 取消置顶
+// @Summary 取消置顶
+// @Description 取消文章置顶
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Success 200 {object} response.Response "取消成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/{id}/pin [delete]
+func (s *ArticleService) Unpin(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.operator(c).Unpin(idReq.ID); err != nil {
 		response.ServerError(c, err.Error())
 		return
 	}
@@ -247,6 +417,7 @@ func (s *ArticleService) UpdateStatus(c *gin.Context) {
 // @Param sort query string false "排序方式" default(latest)
 // @Success 200 {object} response.Response "搜索成功"
 // @Failure 500 {object} response.Response "服务器错误"
+// @Failure 429 {object} response.Response "请求过于频繁"
 // @Router /blog/articles/search [get]
 func (s *ArticleService) Search(c *gin.Context) {
 	keyword := c.Query("keyword")
@@ -270,37 +441,35 @@ func (s *ArticleService) Search(c *gin.Context) {
 
 // Archive 获取归档文章
 // @Summary 获取归档文章
-// @Description 获取文章归档列表，按时间归档
+// @Description 获取已发布文章的归档，按年、月倒序分组，包含每年、每月的文章数及文章列表（缓存 5 分钟）
 // @Tags 博客前台
 // @Accept json
 // @Produce json
-// @Param page query int false "页码" default(1)
-// @Param limit query int false "每页数量" default(100)
-// @Success 200 {object} response.Response "获取成功"
+// @Success 200 {object} response.Response{data=[]dto.ArchiveYear} "获取成功"
 // @Failure 500 {object} response.Response "服务器错误"
 // @Router /blog/articles/archive [get]
 func (s *ArticleService) Archive(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
-
-	resp, err := s.articleUseCase.Archive(page, limit)
+	resp, err := s.articleUseCase.Archive()
 	if err != nil {
 		response.ServerError(c, err.Error())
 		return
 	}
 
-	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+	response.Success(c, resp)
 }
 
+// maxImportArchiveSize 导入 ZIP 文件的最大大小
+const maxImportArchiveSize = 200 << 20
+
 // ImportMarkdown 批量导入 Markdown 文件
 // @Summary 批量导入Markdown文件
-// @Description 批量导入Markdown文件为文章
+// @Description 批量导入Markdown文件为文章，支持导出功能生成的ZIP包（Markdown文件加images/目录）
 // @Tags 文章管理
 // @Accept multipart/form-data
 // @Produce json
 // @Security BearerAuth
-// @Param files formData file true "Markdown文件（可多个）"
-// @Success 200 {object} response.Response "导入成功"
+// @Param files formData file true "Markdown文件或ZIP导入包（可多个）"
+// @Success 200 {object} response.Response{data=dto.ImportReport} "导入报告"
 // @Failure 400 {object} response.Response "请求参数错误"
 // @Failure 401 {object} response.Response "未授权"
 // @Router /articles/import [post]
@@ -332,22 +501,25 @@ func (s *ArticleService) ImportMarkdown(c *gin.Context) {
 		return
 	}
 
-	successCount := 0
-	failedFiles := []string{}
+	report := &dto.ImportReport{Items: []dto.ImportReportItem{}}
 
 	// 遍历所有文件
 	for _, file := range files {
 		// 检查文件扩展名
 		ext := strings.ToLower(filepath.Ext(file.Filename))
-		if ext != ".md" && ext != ".markdown" {
-			failedFiles = append(failedFiles, file.Filename+": 不支持的文件格式")
+		if ext != ".md" && ext != ".markdown" && ext != ".zip" {
+			biz.AddImportResult(report, file.Filename, nil, errors.New("不支持的文件格式"))
+			continue
+		}
+		if ext == ".zip" && file.Size > maxImportArchiveSize {
+			biz.AddImportResult(report, file.Filename, nil, errors.New("ZIP文件过大"))
 			continue
 		}
 
 		// 打开文件
 		f, err := file.Open()
 		if err != nil {
-			failedFiles = append(failedFiles, file.Filename+": 打开文件失败")
+			biz.AddImportResult(report, file.Filename, nil, errors.New("打开文件失败"))
 			continue
 		}
 
@@ -355,61 +527,35 @@ func (s *ArticleService) ImportMarkdown(c *gin.Context) {
 		content, err := io.ReadAll(f)
 		f.Close()
 		if err != nil {
-			failedFiles = append(failedFiles, file.Filename+": 读取文件失败")
+			biz.AddImportResult(report, file.Filename, nil, errors.New("读取文件失败"))
 			continue
 		}
 
-		// 提取文件名作为标题（去掉扩展名）
-		title := strings.TrimSuffix(file.Filename, ext)
-
-		// 创建文章
-		req := &dto.CreateArticleRequest{
-			Title:           title,
-			ContentMarkdown: string(content),
-			Summary:         generateSummary(string(content), 200),
-			Status:          0, // 默认为草稿
-			CategoryID:      defaultCategoryID,
-			TagIDs:          []uint{},
+		// ZIP 导入包：逐个导入其中的 Markdown 文件并合并结果
+		if ext == ".zip" {
+			archiveReport, err := s.articleUseCase.WithContext(c.Request.Context()).ImportArchive(file.Filename, content, defaultCategoryID, adminID.(uint))
+			if err != nil {
+				biz.AddImportResult(report, file.Filename, nil, err)
+				continue
+			}
+			report.Total += archiveReport.Total
+			report.Success += archiveReport.Success
+			report.Failed += archiveReport.Failed
+			report.FailedFiles = append(report.FailedFiles, archiveReport.FailedFiles...)
+			report.Items = append(report.Items, archiveReport.Items...)
+			continue
 		}
 
-		_, err = s.articleUseCase.Create(req, adminID.(uint))
+		// 解析 Front Matter 并创建文章，没有标题时使用文件名（去掉扩展名）
+		title := strings.TrimSuffix(file.Filename, ext)
+		article, err := s.articleUseCase.WithContext(c.Request.Context()).ImportMarkdown(title, string(content), defaultCategoryID, adminID.(uint))
 		if err != nil {
-			failedFiles = append(failedFiles, file.Filename+": 创建文章失败 - "+err.Error())
-			continue
+			err = errors.New("创建文章失败 - " + err.Error())
 		}
-
-		successCount++
-	}
-
-	result := map[string]interface{}{
-		"total":   len(files),
-		"success": successCount,
-		"failed":  len(failedFiles),
+		biz.AddImportResult(report, file.Filename, article, err)
 	}
 
-	if len(failedFiles) > 0 {
-		result["failed_files"] = failedFiles
-	}
-
-	response.Success(c, result)
-}
-
-// generateSummary 从内容中生成摘要
-func generateSummary(content string, maxLen int) string {
-	// 移除 Markdown 标记
-	content = strings.ReplaceAll(content, "#", "")
-	content = strings.ReplaceAll(content, "*", "")
-	content = strings.ReplaceAll(content, "_", "")
-	content = strings.ReplaceAll(content, "`", "")
-	content = strings.ReplaceAll(content, "\n", " ")
-	content = strings.TrimSpace(content)
-
-	// 截取指定长度
-	runes := []rune(content)
-	if len(runes) > maxLen {
-		return string(runes[:maxLen]) + "..."
-	}
-	return content
+	response.Success(c, report)
 }
 
 // BatchUpdateCover 批量更新封面
@@ -428,11 +574,11 @@ func generateSummary(content string, maxLen int) string {
 func (s *ArticleService) BatchUpdateCover(c *gin.Context) {
 	var req dto.BatchUpdateCoverRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
-	if err := s.articleUseCase.BatchUpdateCover(req.ArticleIDs, req.Cover); err != nil {
+	if err := s.operator(c).BatchUpdateCover(req.ArticleIDs, req.Cover); err != nil {
 		response.ServerError(c, err.Error())
 		return
 	}
@@ -458,11 +604,11 @@ func (s *ArticleService) BatchUpdateCover(c *gin.Context) {
 func (s *ArticleService) BatchUpdateFields(c *gin.Context) {
 	var req dto.BatchUpdateFieldsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
-	if err := s.articleUseCase.BatchUpdateFields(&req); err != nil {
+	if err := s.operator(c).BatchUpdateFields(&req); err != nil {
 		response.ServerError(c, err.Error())
 		return
 	}
@@ -474,7 +620,7 @@ func (s *ArticleService) BatchUpdateFields(c *gin.Context) {
 
 // BatchDelete 批量删除
 // @Summary 批量删除文章
-// @Description 批量删除多篇文章
+// @Description 批量删除多篇文章（移入回收站，可恢复）
 // @Tags 文章管理
 // @Accept json
 // @Produce json
@@ -488,11 +634,11 @@ func (s *ArticleService) BatchUpdateFields(c *gin.Context) {
 func (s *ArticleService) BatchDelete(c *gin.Context) {
 	var req dto.BatchDeleteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
-	if err := s.articleUseCase.BatchDelete(req.ArticleIDs); err != nil {
+	if err := s.operator(c).BatchDelete(req.ArticleIDs); err != nil {
 		response.ServerError(c, err.Error())
 		return
 	}
@@ -516,7 +662,7 @@ func (s *ArticleService) BatchDelete(c *gin.Context) {
 func (s *ArticleService) GetAdjacentArticles(c *gin.Context) {
 	var req dto.IDRequest
 	if err := c.ShouldBindUri(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
@@ -529,15 +675,75 @@ func (s *ArticleService) GetAdjacentArticles(c *gin.Context) {
 	response.Success(c, result)
 }
 
+// ImportNotion 导入 Notion 导出
+// @Summary 导入Notion导出
+// @Description 导入Notion导出的ZIP（Markdown & CSV格式），页面层级展开为笔记章节，图片重新上传
+// @Tags 文章管理
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Notion导出的ZIP文件"
+// @Success 200 {object} response.Response{data=dto.ImportReport} "导入报告"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /articles/import/notion [post]
+func (s *ArticleService) ImportNotion(c *gin.Context) {
+	adminID, exists := c.Get("admin_id")
+	if !exists {
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "没有上传文件")
+		return
+	}
+	if strings.ToLower(filepath.Ext(file.Filename)) != ".zip" {
+		response.BadRequest(c, "只支持ZIP文件")
+		return
+	}
+	if file.Size > maxImportArchiveSize {
+		response.BadRequest(c, "ZIP文件过大")
+		return
+	}
+
+	defaultCategoryID, err := s.articleUseCase.GetDefaultCategoryID()
+	if err != nil {
+		response.BadRequest(c, "获取默认分类失败: "+err.Error())
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		response.BadRequest(c, "打开文件失败")
+		return
+	}
+	content, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		response.BadRequest(c, "读取文件失败")
+		return
+	}
+
+	report, err := s.articleUseCase.WithContext(c.Request.Context()).ImportNotion(file.Filename, content, defaultCategoryID, adminID.(uint))
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, report)
+}
+
 // Export 批量导出文章为 ZIP
 // @Summary 批量导出文章
-// @Description 将指定或所有文章导出为 ZIP 文件，包含 Markdown 文件和图片
+// @Description 创建异步导出任务，返回任务ID，通过导出任务接口查询进度和下载地址；未启用 Redis 时直接返回 ZIP 文件（包含 Markdown 文件和图片）
 // @Tags 文章管理
 // @Accept json
-// @Produce application/zip
+// @Produce json,application/zip
 // @Security BearerAuth
-// @Param request body dto.ExportArticleRequest true "导出请求，article_ids 为空表示导出全部"
-// @Success 200 "ZIP 文件"
+// @Param request body dto.ExportArticleRequest true "导出请求，article_ids 为空表示导出全部，profile 可选 hugo / hexo"
+// @Success 200 {object} response.Response{data=dto.ExportJobResponse} "导出任务（未启用 Redis 时为 ZIP 文件）"
 // @Failure 400 {object} response.Response "请求参数错误"
 // @Failure 401 {object} response.Response "未授权"
 // @Failure 500 {object} response.Response "服务器错误"
@@ -545,19 +751,164 @@ func (s *ArticleService) GetAdjacentArticles(c *gin.Context) {
 func (s *ArticleService) Export(c *gin.Context) {
 	var req dto.ExportArticleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
-	zipData, err := s.articleUseCase.Export(req.ArticleIDs)
+	job, err := s.articleUseCase.EnqueueExport(&req, c.GetUint("admin_id"))
+	if errors.Is(err, biz.ErrExportQueueUnavailable) {
+		s.streamExport(c, &req)
+		return
+	}
 	if err != nil {
 		response.ServerError(c, err.Error())
 		return
 	}
 
-	// 设置响应头以便下载 ZIP 文件
+	response.Success(c, job)
+}
+
+// GetExportJob 查询导出任务
+// @Summary 查询导出任务
+// @Description 查询异步导出任务的状态和进度，完成后返回下载地址；非超级管理员只能查看自己创建的任务
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "任务ID"
+// @Success 200 {object} response.Response{data=dto.ExportJobResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "任务不存在或已过期"
+// @Router /articles/export/jobs/{id} [get]
+func (s *ArticleService) GetExportJob(c *gin.Context) {
+	job, err := s.operator(c).GetExportJob(c.Param("id"))
+	if err != nil {
+		s.handleExportJobError(c, err)
+		return
+	}
+
+	if job.Status == biz.ExportJobDone {
+		job.DownloadURL = strings.TrimSuffix(c.Request.URL.Path, "/") + "/download"
+	}
+	response.Success(c, job)
+}
+
+// DownloadExportJob 下载导出文件
+// @Summary 下载导出文件
+// @Description 下载已完成的异步导出任务生成的 ZIP 文件
+// @Tags 文章管理
+// @Produce application/zip
+// @Security BearerAuth
+// @Param id path string true "任务ID"
+// @Success 200 "ZIP 文件"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "任务不存在或已过期"
+// @Failure 409 {object} response.Response "任务尚未完成"
+// @Router /articles/export/jobs/{id}/download [get]
+func (s *ArticleService) DownloadExportJob(c *gin.Context) {
+	path, err := s.operator(c).ExportJobFile(c.Param("id"))
+	if err != nil {
+		s.handleExportJobError(c, err)
+		return
+	}
+
+	c.FileAttachment(path, "articles.zip")
+}
+
+// handleExportJobError 将导出任务错误映射为响应
+func (s *ArticleService) handleExportJobError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrExportJobNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrExportJobNotReady):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, biz.ErrExportQueueUnavailable):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
+
+// streamExport 同步导出：压缩包边生成边写入响应
+func (s *ArticleService) streamExport(c *gin.Context, req *dto.ExportArticleRequest) {
+	// 设置响应头以便下载 ZIP 文件，压缩包边生成边写入响应
 	c.Header("Content-Disposition", "attachment; filename=articles.zip")
 	c.Header("Content-Type", "application/zip")
-	c.Data(200, "application/zip", zipData)
+	if err := s.articleUseCase.Export(c.Writer, req.ArticleIDs, req.Profile); err != nil {
+		if !c.Writer.Written() {
+			// 尚未输出任何数据，仍可返回错误信息
+			c.Writer.Header().Del("Content-Disposition")
+			response.ServerError(c, err.Error())
+			return
+		}
+		// 已开始输出，只能中断下载
+		logger.FromContext(c.Request.Context()).Error("Failed to stream article export: ", err)
+	}
 }
 
+// ExportPDF 导出 PDF
+// @Summary 导出 PDF
+// @Description 导出单篇、多篇文章或笔记标签的章节树为 PDF，包含封面、目录和内嵌图片；依赖服务器安装 wkhtmltopdf
+// @Tags 文章管理
+// @Accept json
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param request body dto.ExportBookRequest true "导出请求，tag_id 与 article_ids 二选一"
+// @Success 200 "PDF 文件"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/export/pdf [post]
+func (s *ArticleService) ExportPDF(c *gin.Context) {
+	var req dto.ExportBookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	pdfData, name, err := s.operator(c).ExportPDF(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	// 文件名可能包含中文，按 RFC 5987 编码
+	c.Header("Content-Disposition", "attachment; filename=export.pdf; filename*=UTF-8''"+url.PathEscape(name+".pdf"))
+	c.Data(200, "application/pdf", pdfData)
+}
+
+// ExportEPUB 导出 EPUB 电子书
+// @Summary 导出 EPUB
+// @Description 将笔记标签的章节树（或指定文章）导出为 EPUB 3 电子书，章节层级生成导航目录，图片打包进电子书
+// @Tags 文章管理
+// @Accept json
+// @Produce application/epub+zip
+// @Security BearerAuth
+// @Param request body dto.ExportBookRequest true "导出请求，tag_id 与 article_ids 二选一"
+// @Success 200 "EPUB 文件"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/export/epub [post]
+func (s *ArticleService) ExportEPUB(c *gin.Context) {
+	var req dto.ExportBookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	epubData, name, err := s.operator(c).ExportEPUB(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	// 文件名可能包含中文，按 RFC 5987 编码
+	c.Header("Content-Disposition", "attachment; filename=export.epub; filename*=UTF-8''"+url.PathEscape(name+".epub"))
+	c.Data(200, "application/epub+zip", epubData)
+}
+
+// operator 获取按当前登录用户限定范围的文章用例（非超级管理员只能修改自己的文章）
+func (s *ArticleService) operator(c *gin.Context) biz.ArticleUseCase {
+	return s.articleUseCase.WithContext(c.Request.Context()).WithOperator(c.GetUint("admin_id"), c.GetString("role"))
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// ListAuditLogs 文章审计日志
+// @Summary 查询文章审计日志
+// @Description 按文章或操作人查询文章的创建、修改、状态变更和删除记录，changes 为字段级新旧值（正文和密码只记录是否变更）；非超级管理员只能查看自己的操作记录
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Param article_id query int false "文章ID"
+// @Param operator_id query int false "操作人ID"
+// @Param action query string false "操作类型：create, update, status, delete"
+// @Success 200 {object} response.Response{data=[]dto.ArticleAuditLogResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/audit-logs [get]
+func (s *ArticleService) ListAuditLogs(c *gin.Context) {
+	req := dto.ArticleAuditListRequest{
+		PageRequest: dto.PageRequest{Page: 1, Limit: 20},
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.operator(c).ListAuditLogs(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// SetAuthors 设置文章共同作者
+// @Summary 设置文章共同作者
+// @Description 按数组顺序替换文章的共同作者，role 可选 author（共同作者）或 contributor（贡献者），主作者不能重复添加
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Param request body dto.SetArticleAuthorsRequest true "共同作者列表"
+// @Success 200 {object} response.Response{data=dto.ArticleResponse} "设置成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/{id}/authors [put]
+func (s *ArticleService) SetAuthors(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.SetArticleAuthorsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.operator(c).SetAuthors(uri.ID, &req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// AddAuthor 添加文章共同作者
+// @Summary 添加文章共同作者
+// @Description 添加一位共同作者并排在署名最后，已是共同作者时只更新角色
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Param request body dto.ArticleAuthorItem true "共同作者"
+// @Success 200 {object} response.Response{data=dto.ArticleResponse} "添加成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/{id}/authors [post]
+func (s *ArticleService) AddAuthor(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.ArticleAuthorItem
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.operator(c).AddAuthor(uri.ID, &req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// RemoveAuthor 移除文章共同作者
+// @Summary 移除文章共同作者
+// @Description 从文章的共同作者中移除指定用户，不影响主作者
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Param user_id path int true "用户ID"
+// @Success 200 {object} response.Response "移除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/{id}/authors/{user_id} [delete]
+func (s *ArticleService) RemoveAuthor(c *gin.Context) {
+	var req dto.ArticleAuthorRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.operator(c).RemoveAuthor(req.ID, req.UserID); err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// Autosave 自动保存草稿
+// @Summary 自动保存草稿
+// @Description 编辑器定时保存未提交的内容到草稿区，不影响文章正式内容；文章正式保存后草稿自动清除
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Param request body dto.AutosaveRequest true "草稿内容"
+// @Success 200 {object} response.Response{data=dto.AutosaveResponse} "保存成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/{id}/autosave [post]
+func (s *ArticleService) Autosave(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.AutosaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.operator(c).Autosave(uri.ID, c.GetUint("admin_id"), &req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// GetDraft 获取未保存的草稿
+// @Summary 恢复未保存的草稿
+// @Description 获取当前用户在该文章上自动保存的草稿，newer 为 true 表示草稿比正式内容更新
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Success 200 {object} response.Response{data=dto.ArticleDraftResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "没有草稿"
+// @Router /articles/{id}/autosave [get]
+func (s *ArticleService) GetDraft(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.operator(c).GetDraft(uri.ID, c.GetUint("admin_id"))
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// DiscardDraft 丢弃草稿
+// @Summary 丢弃草稿
+// @Description 删除当前用户在该文章上自动保存的草稿
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/{id}/autosave [delete]
+func (s *ArticleService) DiscardDraft(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.operator(c).DiscardDraft(uri.ID, c.GetUint("admin_id")); err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// AcquireLock 获取文章编辑锁
+// @Summary 获取或续期文章编辑锁
+// @Description 打开编辑器时调用 POST 加锁，编辑期间定时调用 PUT 续期（锁 60 秒未续期自动释放）；锁被其他管理员持有时 acquired 为 false，holder 为正在编辑的管理员
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Success 200 {object} response.Response{data=dto.ArticleLockResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/{id}/lock [post]
+// @Router /articles/{id}/lock [put]
+func (s *ArticleService) AcquireLock(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.operator(c).AcquireEditLock(uri.ID, c.GetUint("admin_id"))
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// ReleaseLock 释放文章编辑锁
+// @Summary 释放文章编辑锁
+// @Description 关闭编辑器时释放当前管理员持有的编辑锁，锁不属于当前管理员时不做处理
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Success 200 {object} response.Response "释放成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/{id}/lock [delete]
+func (s *ArticleService) ReleaseLock(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.operator(c).ReleaseEditLock(uri.ID, c.GetUint("admin_id")); err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// BatchUpdateStatus 批量更新文章状态
+// @Summary 批量更新文章状态
+// @Description 在同一事务中批量发布（1）、取消发布（0）或下线归档（2）文章，返回每篇文章的处理结果
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.BatchUpdateStatusRequest true "文章ID列表和目标状态"
+// @Success 200 {object} response.Response{data=dto.BatchStatusResponse} "处理完成"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/batch/status [post]
+func (s *ArticleService) BatchUpdateStatus(c *gin.Context) {
+	var req dto.BatchUpdateStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.operator(c).BatchUpdateStatus(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
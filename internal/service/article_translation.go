@@ -0,0 +1,116 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// ListTranslations 查询文章的语言版本
+// @Summary 查询文章的语言版本
+// @Description 返回文章所在翻译组的全部语言版本（包含草稿），源文章在前，未加入翻译组时返回空列表
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Success 200 {object} response.Response{data=dto.ArticleTranslationsResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "文章不存在"
+// @Router /articles/{id}/translations [get]
+func (s *ArticleService) ListTranslations(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.articleUseCase.ListTranslations(uri.ID)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// LinkTranslation 关联文章译文
+// @Summary 关联文章译文
+// @Description 将另一篇文章关联为当前文章的指定语言版本。当前文章尚未加入翻译组时以它为源文章创建翻译组，源文章语言取 source_language，默认为站点默认语言；同一翻译组内每种语言只能有一篇文章
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Param request body dto.LinkTranslationRequest true "译文信息"
+// @Success 200 {object} response.Response{data=dto.ArticleTranslationsResponse} "关联成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 409 {object} response.Response "语言版本已存在或译文已属于其他翻译组"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/{id}/translations [post]
+func (s *ArticleService) LinkTranslation(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.LinkTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.operator(c).LinkTranslation(uri.ID, &req)
+	if err != nil {
+		s.handleTranslationError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// UnlinkTranslation 取消文章的译文关联
+// @Summary 取消文章的译文关联
+// @Description 将文章移出所在的翻译组；移出源文章会解散整个翻译组，组内只剩一篇文章时也会解散
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Success 200 {object} response.Response "取消成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/{id}/translations [delete]
+func (s *ArticleService) UnlinkTranslation(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.operator(c).UnlinkTranslation(uri.ID); err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// handleTranslationError 将译文关联错误映射为响应
+func (s *ArticleService) handleTranslationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrTranslationInvalid):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, biz.ErrTranslationConflict):
+		response.Conflict(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
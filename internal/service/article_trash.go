@@ -0,0 +1,100 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// ListTrash 回收站文章列表
+// @Summary 获取回收站文章列表
+// @Description 分页获取已删除的文章，超过保留天数后将被自动彻底删除
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Param keyword query string false "搜索关键词"
+// @Success 200 {object} response.Response{data=dto.PageResponse{data=[]dto.TrashItem}} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/trash [get]
+func (s *ArticleService) ListTrash(c *gin.Context) {
+	req := dto.TrashListRequest{
+		PageRequest: dto.PageRequest{Page: 1, Limit: 10},
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	result, err := s.operator(c).ListTrash(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// RestoreTrash 从回收站恢复文章
+// @Summary 恢复文章
+// @Description 将回收站中的文章恢复，保留原有标签、评论等数据
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.TrashActionRequest true "恢复文章ID列表"
+// @Success 200 {object} response.Response "恢复成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/trash/restore [post]
+func (s *ArticleService) RestoreTrash(c *gin.Context) {
+	var req dto.TrashActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.operator(c).RestoreTrash(req.ArticleIDs); err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"restored": len(req.ArticleIDs),
+	})
+}
+
+// PurgeTrash 彻底删除回收站中的文章
+// @Summary 彻底删除文章
+// @Description 物理删除回收站中的文章及其标签关联、历史版本、评论、点赞、收藏记录，操作不可恢复
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.TrashActionRequest true "彻底删除文章ID列表"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/trash/purge [post]
+func (s *ArticleService) PurgeTrash(c *gin.Context) {
+	var req dto.TrashActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.operator(c).PurgeTrash(req.ArticleIDs); err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"purged": len(req.ArticleIDs),
+	})
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// ListVersions 查询文章历史版本
+// @Summary 获取文章历史版本
+// @Description 获取文章的历史版本列表（按版本号倒序，不含正文）
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Success 200 {object} response.Response{data=[]dto.ArticleVersionItem} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/{id}/versions [get]
+func (s *ArticleService) ListVersions(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	versions, err := s.articleUseCase.ListVersions(req.ID)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, versions)
+}
+
+// GetVersion 查询文章历史版本详情
+// @Summary 获取文章历史版本详情
+// @Description 获取指定历史版本的完整内容
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Param version_id path int true "版本ID"
+// @Success 200 {object} response.Response{data=dto.ArticleVersionResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "版本不存在"
+// @Router /articles/{id}/versions/{version_id} [get]
+func (s *ArticleService) GetVersion(c *gin.Context) {
+	var req dto.ArticleVersionRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	version, err := s.articleUseCase.GetVersion(req.ID, req.VersionID)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.Success(c, version)
+}
+
+// DiffVersion 对比文章历史版本
+// @Summary 对比文章历史版本
+// @Description 按行对比历史版本与当前内容（或另一个历史版本）的 Markdown 差异
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Param version_id path int true "版本ID"
+// @Param against query int false "对比的版本ID，不传则与当前内容对比"
+// @Success 200 {object} response.Response{data=dto.ArticleVersionDiff} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/{id}/versions/{version_id}/diff [get]
+func (s *ArticleService) DiffVersion(c *gin.Context) {
+	var req dto.ArticleVersionRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var againstID uint
+	if against := c.Query("against"); against != "" {
+		id, err := strconv.ParseUint(against, 10, 32)
+		if err != nil {
+			response.BadRequest(c, "against 参数无效")
+			return
+		}
+		againstID = uint(id)
+	}
+
+	result, err := s.articleUseCase.DiffVersion(req.ID, req.VersionID, againstID)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// RestoreVersion 恢复文章历史版本
+// @Summary 恢复文章历史版本
+// @Description 将文章标题、摘要和正文恢复为指定历史版本，恢复前的内容会自动保存为新版本
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Param version_id path int true "版本ID"
+// @Success 200 {object} response.Response{data=dto.ArticleResponse} "恢复成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /articles/{id}/versions/{version_id}/restore [post]
+func (s *ArticleService) RestoreVersion(c *gin.Context) {
+	var req dto.ArticleVersionRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	article, err := s.operator(c).RestoreVersion(req.ID, req.VersionID)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, article)
+}
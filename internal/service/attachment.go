@@ -0,0 +1,228 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/oss"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// AttachmentService 附件服务
+type AttachmentService struct {
+	attachmentUseCase biz.AttachmentUseCase
+}
+
+// NewAttachmentService 创建附件服务
+func NewAttachmentService(attachmentUseCase biz.AttachmentUseCase) *AttachmentService {
+	return &AttachmentService{
+		attachmentUseCase: attachmentUseCase,
+	}
+}
+
+// Upload 上传附件
+// @Summary 上传附件
+// @Description 上传 PDF、压缩包、Office 文档等附件（类型和大小由 upload.attachment_exts、upload.attachment_max_size 配置），可同时关联到文章
+// @Tags 附件管理
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "附件"
+// @Param article_id formData int false "关联的文章ID"
+// @Param description formData string false "附件说明"
+// @Success 200 {object} response.Response{data=dto.AttachmentResponse} "上传成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /attachments [post]
+func (s *AttachmentService) Upload(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "文件上传失败")
+		return
+	}
+
+	var req dto.UploadAttachmentRequest
+	if err := c.ShouldBind(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.attachmentUseCase.Upload(file, &req, c.GetUint("user_id"))
+	if err != nil {
+		s.handleAttachmentError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// List 附件列表
+// @Summary 获取附件列表
+// @Description 分页获取附件，可按文件名、关联文章筛选
+// @Tags 附件管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Param keyword query string false "文件名关键词"
+// @Param article_id query int false "文章ID"
+// @Param unattached query bool false "只返回未关联文章的附件"
+// @Success 200 {object} response.Response{data=[]dto.AttachmentResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /attachments [get]
+func (s *AttachmentService) List(c *gin.Context) {
+	req := dto.AttachmentListRequest{
+		PageRequest: dto.PageRequest{Page: 1, Limit: 10},
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.attachmentUseCase.List(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// SetArticle 修改附件关联的文章
+// @Summary 关联附件到文章
+// @Description 将附件关联到文章，article_id 为空或 0 时取消关联
+// @Tags 附件管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "附件ID"
+// @Param request body dto.SetAttachmentArticleRequest true "关联的文章"
+// @Success 200 {object} response.Response{data=dto.AttachmentResponse} "修改成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "附件不存在"
+// @Router /attachments/{id}/article [put]
+func (s *AttachmentService) SetArticle(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.SetAttachmentArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.attachmentUseCase.SetArticle(uri.ID, req.ArticleID)
+	if err != nil {
+		s.handleAttachmentError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Delete 删除附件
+// @Summary 删除附件
+// @Description 删除附件记录及存储中的文件
+// @Tags 附件管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "附件ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 404 {object} response.Response "附件不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /attachments/{id} [delete]
+func (s *AttachmentService) Delete(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.attachmentUseCase.Delete(req.ID); err != nil {
+		s.handleAttachmentError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// ListByArticle 博客前台文章附件
+// @Summary 获取文章附件
+// @Description 获取已发布文章的附件列表，加密文章需通过 X-Article-Token 请求头携带访问令牌；下载请使用 download_url 以便统计下载次数
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param id path int true "文章ID"
+// @Success 200 {object} response.Response{data=[]dto.AttachmentResponse} "获取成功"
+// @Failure 403 {object} response.Response "文章需要密码解锁"
+// @Failure 404 {object} response.Response "文章不存在或未发布"
+// @Router /blog/articles/{id}/attachments [get]
+func (s *AttachmentService) ListByArticle(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	list, err := s.attachmentUseCase.ListByArticle(req.ID, articleAccessToken(c))
+	if err != nil {
+		s.handleAttachmentError(c, err)
+		return
+	}
+
+	response.Success(c, list)
+}
+
+// Download 博客前台下载附件
+// @Summary 下载附件
+// @Description 记录下载次数后返回文件：本地存储直接以原文件名下载，对象存储重定向到文件地址（私有 bucket 为临时签名地址）；加密文章需携带访问令牌（请求头 X-Article-Token 或查询参数 access_token）
+// @Tags 博客前台
+// @Param id path int true "附件ID"
+// @Success 200 {file} binary "附件内容"
+// @Success 302 {string} string "重定向到文件地址"
+// @Failure 403 {object} response.Response "文章需要密码解锁"
+// @Failure 404 {object} response.Response "附件不存在"
+// @Router /blog/attachments/{id}/download [get]
+func (s *AttachmentService) Download(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	attachment, err := s.attachmentUseCase.Download(req.ID, articleAccessToken(c))
+	if err != nil {
+		s.handleAttachmentError(c, err)
+		return
+	}
+
+	if strings.HasPrefix(attachment.URL, "/uploads/") {
+		c.FileAttachment(strings.TrimPrefix(attachment.URL, "/"), attachment.Name)
+		return
+	}
+	c.Redirect(http.StatusFound, oss.SignURL(attachment.URL))
+}
+
+// handleAttachmentError 将附件业务错误映射为响应状态
+func (s *AttachmentService) handleAttachmentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrAttachmentNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrAttachmentLocked):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, biz.ErrAttachmentInvalid):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
 	"github.com/ydcloud-dy/leaf-api/internal/biz"
 	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
@@ -29,15 +31,16 @@ func NewAuthService(authUseCase biz.AuthUseCase) *AuthService {
 // @Success 200 {object} response.Response "登录成功"
 // @Failure 400 {object} response.Response "请求参数错误"
 // @Failure 401 {object} response.Response "认证失败"
+// @Failure 429 {object} response.Response "请求过于频繁"
 // @Router /auth/login [post]
 func (s *AuthService) Login(c *gin.Context) {
 	var req dto.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
-	resp, err := s.authUseCase.Login(&req)
+	resp, err := s.authUseCase.Login(c.Request.Context(), &req, clientInfo(c))
 	if err != nil {
 		response.Unauthorized(c, err.Error())
 		return
@@ -47,17 +50,51 @@ func (s *AuthService) Login(c *gin.Context) {
 }
 
 // Logout 登出
-// @Summary 管理员登出
-// @Description 退出登录
+// @Summary 登出
+// @Description 退出登录，携带 refresh_token 时吊销该刷新令牌及其轮换出的全部令牌；
+// @Description 携带 Authorization 请求头时当前访问令牌立即失效，所属会话同时吊销
 // @Tags 认证管理
 // @Accept json
 // @Produce json
+// @Param request body dto.LogoutRequest false "刷新令牌"
 // @Success 200 {object} response.Response "登出成功"
 // @Router /auth/logout [post]
 func (s *AuthService) Logout(c *gin.Context) {
+	var req dto.LogoutRequest
+	_ = c.ShouldBindJSON(&req)
+
+	s.authUseCase.Logout(req.RefreshToken, bearerToken(c))
 	response.Success(c, nil)
 }
 
+// Refresh 刷新令牌
+// @Summary 刷新令牌
+// @Description 使用刷新令牌换取新的访问令牌和刷新令牌（管理后台和博客前台通用），旧刷新令牌随即失效；
+// @Description 已使用过的刷新令牌再次提交时视为泄露，吊销同一次登录签发的全部刷新令牌
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Param request body dto.RefreshTokenRequest true "刷新令牌"
+// @Success 200 {object} response.Response{data=dto.TokenResponse} "刷新成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "刷新令牌无效或已过期"
+// @Router /auth/refresh [post]
+func (s *AuthService) Refresh(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.authUseCase.Refresh(req.RefreshToken, clientInfo(c))
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
 // GetProfile 获取当前用户信息
 // @Summary 获取当前管理员信息
 // @Description 获取当前登录管理员的详细信息
@@ -98,7 +135,7 @@ func (s *AuthService) UpdateProfile(c *gin.Context) {
 
 	var req dto.UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
@@ -122,3 +159,302 @@ func (s *AuthService) UpdateProfile(c *gin.Context) {
 		"is_blogger": user.IsBlogger,
 	})
 }
+
+// RotateSigningKey 轮换 JWT 签名密钥
+// @Summary 轮换 JWT 签名密钥
+// @Description 生成新的签名密钥用于签发 token，密钥保存在 Redis 中，所有实例共享且重启后仍然有效，响应中不返回密钥内容；
+// @Description 旧密钥默认继续用于校验已签发的 token，revoke_previous 为 true 时立即吊销全部旧密钥（密钥泄露时使用）
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.RotateKeyRequest false "新密钥（为空时自动生成）"
+// @Success 200 {object} response.Response{data=dto.RotateKeyResponse} "轮换成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /auth/keys/rotate [post]
+func (s *AuthService) RotateSigningKey(c *gin.Context) {
+	var req dto.RotateKeyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BindError(c, err)
+			return
+		}
+	}
+
+	resp, err := s.authUseCase.RotateSigningKey(c.GetString("role"), &req)
+	if err != nil {
+		s.handleSigningKeyError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// RevokeSigningKey 吊销 JWT 旧签名密钥
+// @Summary 吊销 JWT 旧签名密钥
+// @Description 吊销指定的旧签名密钥，使用该密钥签发的 token 立即失效，当前签名密钥不能吊销
+// @Tags 认证管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "密钥ID"
+// @Success 200 {object} response.Response{data=[]dto.SigningKeyInfo} "吊销成功，返回剩余的签名密钥"
+// @Failure 400 {object} response.Response "当前签名密钥不能吊销"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "密钥不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /auth/keys/{id} [delete]
+func (s *AuthService) RevokeSigningKey(c *gin.Context) {
+	keys, err := s.authUseCase.RevokeSigningKey(c.GetString("role"), c.Param("id"))
+	if err != nil {
+		s.handleSigningKeyError(c, err)
+		return
+	}
+
+	response.Success(c, keys)
+}
+
+// handleSigningKeyError 将签名密钥业务错误映射为响应
+func (s *AuthService) handleSigningKeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrSigningKeyForbidden):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, biz.ErrSigningKeyNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrSigningKeyInvalid):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
+
+// VerifyTwoFactor 登录时提交两步验证码
+// @Summary 提交两步验证码
+// @Description 登录返回 two_factor_required 时，使用 challenge_token 提交验证器生成的验证码或恢复码，验证通过后返回 Token；
+// @Description two_factor_setup 为 true 时先调用 /auth/2fa/challenge/setup 绑定验证器，提交的验证码通过后启用两步验证并返回恢复码
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorVerifyRequest true "两步验证令牌和验证码"
+// @Success 200 {object} response.Response{data=dto.LoginResponse} "登录成功"
+// @Failure 400 {object} response.Response "验证码错误"
+// @Failure 401 {object} response.Response "两步验证令牌无效或已过期"
+// @Router /auth/2fa/challenge/verify [post]
+func (s *AuthService) VerifyTwoFactor(c *gin.Context) {
+	var req dto.TwoFactorVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.authUseCase.VerifyTwoFactor(c.Request.Context(), &req, clientInfo(c))
+	if err != nil {
+		s.handleTwoFactorError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// SetupTwoFactorChallenge 登录时绑定验证器
+// @Summary 登录时绑定验证器
+// @Description 当前角色必须启用两步验证但尚未绑定时，使用登录返回的 challenge_token 生成 TOTP 密钥和二维码
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorChallengeRequest true "两步验证令牌"
+// @Success 200 {object} response.Response{data=dto.TwoFactorSetupResponse} "生成成功"
+// @Failure 401 {object} response.Response "两步验证令牌无效或已过期"
+// @Failure 409 {object} response.Response "已启用两步验证"
+// @Router /auth/2fa/challenge/setup [post]
+func (s *AuthService) SetupTwoFactorChallenge(c *gin.Context) {
+	var req dto.TwoFactorChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.authUseCase.SetupTwoFactorChallenge(req.ChallengeToken)
+	if err != nil {
+		s.handleTwoFactorError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// GetTwoFactorStatus 查询两步验证状态
+// @Summary 查询两步验证状态
+// @Description 查询当前管理员是否启用了两步验证、角色是否要求两步验证以及剩余的恢复码数量
+// @Tags 认证管理
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=dto.TwoFactorStatusResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Router /auth/2fa [get]
+func (s *AuthService) GetTwoFactorStatus(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	resp, err := s.authUseCase.GetTwoFactorStatus(c.GetUint("admin_id"), c.GetString("role"))
+	if err != nil {
+		s.handleTwoFactorError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// SetupTwoFactor 绑定验证器
+// @Summary 绑定验证器
+// @Description 生成新的 TOTP 密钥和二维码，使用验证器扫码后调用 /auth/2fa/enable 提交验证码才会启用
+// @Tags 认证管理
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=dto.TwoFactorSetupResponse} "生成成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 409 {object} response.Response "已启用两步验证"
+// @Router /auth/2fa/setup [post]
+func (s *AuthService) SetupTwoFactor(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	resp, err := s.authUseCase.SetupTwoFactor(c.GetUint("admin_id"))
+	if err != nil {
+		s.handleTwoFactorError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// EnableTwoFactor 启用两步验证
+// @Summary 启用两步验证
+// @Description 提交验证器生成的验证码确认绑定，启用两步验证并返回恢复码（只显示一次）
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.TwoFactorCodeRequest true "验证码"
+// @Success 200 {object} response.Response{data=dto.RecoveryCodesResponse} "启用成功"
+// @Failure 400 {object} response.Response "验证码错误或尚未绑定验证器"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 409 {object} response.Response "已启用两步验证"
+// @Router /auth/2fa/enable [post]
+func (s *AuthService) EnableTwoFactor(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.authUseCase.EnableTwoFactor(c.GetUint("admin_id"), req.Code)
+	if err != nil {
+		s.handleTwoFactorError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// DisableTwoFactor 关闭两步验证
+// @Summary 关闭两步验证
+// @Description 提交验证码或恢复码关闭两步验证，当前角色要求两步验证时不能关闭
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.TwoFactorCodeRequest true "验证码或恢复码"
+// @Success 200 {object} response.Response "关闭成功"
+// @Failure 400 {object} response.Response "验证码错误或尚未启用两步验证"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限或当前角色必须启用两步验证"
+// @Router /auth/2fa/disable [post]
+func (s *AuthService) DisableTwoFactor(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.authUseCase.DisableTwoFactor(c.GetUint("admin_id"), c.GetString("role"), req.Code); err != nil {
+		s.handleTwoFactorError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+// @Summary 重新生成恢复码
+// @Description 提交验证码或恢复码后重新生成恢复码，旧恢复码全部失效
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.TwoFactorCodeRequest true "验证码或恢复码"
+// @Success 200 {object} response.Response{data=dto.RecoveryCodesResponse} "生成成功"
+// @Failure 400 {object} response.Response "验证码错误或尚未启用两步验证"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Router /auth/2fa/recovery-codes [post]
+func (s *AuthService) RegenerateRecoveryCodes(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.authUseCase.RegenerateRecoveryCodes(c.GetUint("admin_id"), req.Code)
+	if err != nil {
+		s.handleTwoFactorError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// requireAdmin 检查是否为管理后台登录，两步验证和 API Key 只用于管理后台，前台登录的令牌不能管理
+func requireAdmin(c *gin.Context) bool {
+	if !biz.ConsoleRole(c.GetString("role")) {
+		response.Forbidden(c, "无权限访问管理后台")
+		return false
+	}
+	return true
+}
+
+// handleTwoFactorError 将两步验证业务错误映射为响应
+func (s *AuthService) handleTwoFactorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrTwoFactorChallengeInvalid):
+		response.Unauthorized(c, err.Error())
+	case errors.Is(err, biz.ErrTwoFactorRequired):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, biz.ErrTwoFactorAlreadyEnabled):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, biz.ErrTwoFactorCodeInvalid), errors.Is(err, biz.ErrTwoFactorNotEnabled):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// BackupService 备份服务
+type BackupService struct {
+	backupUseCase biz.BackupUseCase
+}
+
+// NewBackupService 创建备份服务
+func NewBackupService(backupUseCase biz.BackupUseCase) *BackupService {
+	return &BackupService{
+		backupUseCase: backupUseCase,
+	}
+}
+
+// List 备份列表
+// @Summary 获取备份列表
+// @Description 按时间倒序列出 OSS（未配置时为本地目录）中的备份
+// @Tags 备份管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]dto.BackupItem} "获取成功"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /backups [get]
+func (s *BackupService) List(c *gin.Context) {
+	// 备份包含全部用户数据，不受路由权限规则影响，始终只允许超级管理员
+	if c.GetString("role") != "super_admin" {
+		response.Forbidden(c, "只有超级管理员可以查看备份")
+		return
+	}
+
+	items, err := s.backupUseCase.List()
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, items)
+}
+
+// Create 立即备份
+// @Summary 立即备份
+// @Description 备份用户、文章、标签、分类、章节、系列、评论、点赞收藏和站点设置，并按保留策略清理旧备份
+// @Tags 备份管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=dto.BackupItem} "备份成功"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /backups [post]
+func (s *BackupService) Create(c *gin.Context) {
+	item, err := s.backupUseCase.Create()
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+	if _, err := s.backupUseCase.Prune(); err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to prune backups: ", err)
+	}
+
+	response.Success(c, item)
+}
+
+// Download 下载备份
+// @Summary 下载备份
+// @Description 下载指定的备份文件
+// @Tags 备份管理
+// @Produce application/zip
+// @Security BearerAuth
+// @Param name path string true "备份文件名"
+// @Success 200 "ZIP 文件"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "备份不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /backups/{name}/download [get]
+func (s *BackupService) Download(c *gin.Context) {
+	if c.GetString("role") != "super_admin" {
+		response.Forbidden(c, "只有超级管理员可以下载备份")
+		return
+	}
+
+	name := c.Param("name")
+	body, err := s.backupUseCase.Open(name)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+	defer body.Close()
+
+	c.Header("Content-Disposition", "attachment; filename="+name)
+	c.Header("Content-Type", "application/zip")
+	if _, err := io.Copy(c.Writer, body); err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to send backup: ", err)
+	}
+}
+
+// Restore 从备份恢复
+// @Summary 从备份恢复
+// @Description 仅超级管理员可用。从已保存的备份（name）或上传的备份文件（file）恢复数据，记录按业务键与现有数据对应并重新映射ID；dry_run=true 时只返回将要发生的变更
+// @Tags 备份管理
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param name formData string false "备份文件名，为空时需上传备份文件"
+// @Param file formData file false "备份文件"
+// @Param dry_run formData bool false "只预览变更，不写入数据库"
+// @Success 200 {object} response.Response{data=dto.RestoreReport} "恢复结果"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "备份不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /backups/restore [post]
+func (s *BackupService) Restore(c *gin.Context) {
+	// 恢复会覆盖现有数据，不受路由权限规则影响，始终只允许超级管理员
+	if c.GetString("role") != "super_admin" {
+		response.Forbidden(c, "只有超级管理员可以恢复备份")
+		return
+	}
+
+	var req dto.RestoreBackupRequest
+	if err := c.ShouldBind(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var report *dto.RestoreReport
+	var err error
+	if req.Name != "" {
+		report, err = s.backupUseCase.RestoreByName(req.Name, req.DryRun)
+	} else {
+		file, formErr := c.FormFile("file")
+		if formErr != nil {
+			response.BadRequest(c, "请指定备份文件名或上传备份文件")
+			return
+		}
+		f, openErr := file.Open()
+		if openErr != nil {
+			response.BadRequest(c, "打开文件失败")
+			return
+		}
+		defer f.Close()
+		report, err = s.backupUseCase.Restore(f, file.Size, req.DryRun)
+	}
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, report)
+}
+
+// Export 导出全部内容
+// @Summary 导出全部内容为 JSON
+// @Description 导出用户、文章、章节、系列、评论、点赞收藏、计数和站点设置为单个 JSON 文档，用于迁移到其他实例；与 Markdown 导出不同，导入后章节顺序、计数和评论层级保持不变
+// @Tags 备份管理
+// @Produce application/json
+// @Security BearerAuth
+// @Success 200 "JSON 文件"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /backups/export [get]
+func (s *BackupService) Export(c *gin.Context) {
+	// 导出内容包含用户密码哈希，只允许超级管理员
+	if c.GetString("role") != "super_admin" {
+		response.Forbidden(c, "只有超级管理员可以导出全部内容")
+		return
+	}
+
+	filename := fmt.Sprintf("leaf-export-%s.json", time.Now().Format("20060102-150405"))
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	if err := s.backupUseCase.ExportJSON(c.Writer); err != nil {
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+			response.ServerError(c, err.Error())
+			return
+		}
+		// 已开始输出，只能中断下载
+		logger.FromContext(c.Request.Context()).Error("Failed to stream full export: ", err)
+	}
+}
+
+// Import 导入全部内容
+// @Summary 导入 JSON 全量导出
+// @Description 仅超级管理员可用。导入 /backups/export 生成的 JSON 文档，处理方式与恢复备份相同；dry_run=true 时只返回将要发生的变更
+// @Tags 备份管理
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "JSON 导出文件"
+// @Param dry_run formData bool false "只预览变更，不写入数据库"
+// @Success 200 {object} response.Response{data=dto.RestoreReport} "导入结果"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /backups/import [post]
+func (s *BackupService) Import(c *gin.Context) {
+	if c.GetString("role") != "super_admin" {
+		response.Forbidden(c, "只有超级管理员可以导入数据")
+		return
+	}
+
+	var req dto.ImportBackupRequest
+	if err := c.ShouldBind(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "没有上传文件")
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		response.BadRequest(c, "打开文件失败")
+		return
+	}
+	defer f.Close()
+
+	report, err := s.backupUseCase.ImportJSON(f, req.DryRun)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, report)
+}
+
+// handleError 将业务错误映射为响应
+func (s *BackupService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrBackupNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrBackupInvalid), errors.Is(err, biz.ErrBackupUnsupported):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
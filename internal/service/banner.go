@@ -0,0 +1,174 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// BannerService 首页横幅服务
+type BannerService struct {
+	bannerUseCase biz.BannerUseCase
+}
+
+// NewBannerService 创建首页横幅服务
+func NewBannerService(bannerUseCase biz.BannerUseCase) *BannerService {
+	return &BannerService{
+		bannerUseCase: bannerUseCase,
+	}
+}
+
+// Active 前台横幅列表
+// @Summary 获取首页横幅
+// @Description 获取正在展示的首页横幅：已启用且当前时间处于展示时间段内，按排序值排列
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.BannerResponse} "获取成功"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/banners [get]
+func (s *BannerService) Active(c *gin.Context) {
+	banners, err := s.bannerUseCase.Active()
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, banners)
+}
+
+// List 后台横幅列表
+// @Summary 获取横幅列表
+// @Description 分页获取首页横幅，可按展示状态过滤
+// @Tags 横幅管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Param status query string false "展示状态：active、scheduled、expired、disabled"
+// @Param keyword query string false "按标题或副标题搜索"
+// @Success 200 {object} response.Response{data=[]dto.BannerResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /banners [get]
+func (s *BannerService) List(c *gin.Context) {
+	req := dto.BannerListRequest{PageRequest: dto.PageRequest{Page: 1, Limit: 10}}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.bannerUseCase.List(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// Create 创建横幅
+// @Summary 创建横幅
+// @Description 创建首页横幅，图片需先通过文件上传接口上传；未指定时默认启用
+// @Tags 横幅管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.BannerRequest true "横幅信息"
+// @Success 200 {object} response.Response{data=dto.BannerResponse} "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /banners [post]
+func (s *BannerService) Create(c *gin.Context) {
+	var req dto.BannerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	banner, err := s.bannerUseCase.Create(&req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, banner)
+}
+
+// Update 更新横幅
+// @Summary 更新横幅
+// @Description 更新首页横幅；启用状态和排序值为空时不修改，展示时间为空表示不限制
+// @Tags 横幅管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "横幅ID"
+// @Param request body dto.BannerRequest true "横幅信息"
+// @Success 200 {object} response.Response{data=dto.BannerResponse} "更新成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "横幅不存在"
+// @Router /banners/{id} [put]
+func (s *BannerService) Update(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.BannerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	banner, err := s.bannerUseCase.Update(idReq.ID, &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, banner)
+}
+
+// Delete 删除横幅
+// @Summary 删除横幅
+// @Description 删除首页横幅，不再被引用的图片由未引用图片清理任务回收
+// @Tags 横幅管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "横幅ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "横幅不存在"
+// @Router /banners/{id} [delete]
+func (s *BannerService) Delete(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.bannerUseCase.Delete(idReq.ID); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// handleError 将业务错误映射为响应
+func (s *BannerService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrBannerNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrBannerInvalid):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
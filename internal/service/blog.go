@@ -1,6 +1,8 @@
 package service
 
 import (
+	"errors"
+	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -23,22 +25,27 @@ func NewBlogService(blogUseCase biz.BlogUseCase) *BlogService {
 
 // Register 用户注册
 // @Summary 用户注册
-// @Description 博客前台用户注册
+// @Description 博客前台用户注册。开启邮箱验证时向注册邮箱发送验证邮件，响应中 verification_required 为 true 且不返回 token，验证后才能登录
 // @Tags 博客前台
 // @Accept json
 // @Produce json
 // @Param request body dto.RegisterRequest true "注册信息"
-// @Success 200 {object} response.Response "注册成功"
+// @Success 200 {object} response.Response{data=dto.LoginResponse} "注册成功"
 // @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 403 {object} response.Response "暂未开放注册"
 // @Router /blog/auth/register [post]
 func (s *BlogService) Register(c *gin.Context) {
 	var req dto.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
-	resp, err := s.blogUseCase.Register(&req)
+	resp, err := s.blogUseCase.Register(c.Request.Context(), &req, clientInfo(c))
+	if errors.Is(err, biz.ErrRegistrationClosed) {
+		response.Forbidden(c, err.Error())
+		return
+	}
 	if err != nil {
 		response.BadRequest(c, err.Error())
 		return
@@ -56,15 +63,21 @@ func (s *BlogService) Register(c *gin.Context) {
 // @Param request body dto.LoginRequest true "登录信息"
 // @Success 200 {object} response.Response "登录成功"
 // @Failure 401 {object} response.Response "认证失败"
+// @Failure 403 {object} response.Response "邮箱未验证"
+// @Failure 429 {object} response.Response "请求过于频繁"
 // @Router /blog/auth/login [post]
 func (s *BlogService) Login(c *gin.Context) {
 	var req dto.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
-	resp, err := s.blogUseCase.Login(&req)
+	resp, err := s.blogUseCase.Login(c.Request.Context(), &req, clientInfo(c))
+	if errors.Is(err, biz.ErrEmailUnverified) {
+		response.Forbidden(c, err.Error())
+		return
+	}
 	if err != nil {
 		response.Unauthorized(c, err.Error())
 		return
@@ -73,6 +86,120 @@ func (s *BlogService) Login(c *gin.Context) {
 	response.Success(c, resp)
 }
 
+// VerifyEmail 验证邮箱
+// @Summary 验证邮箱
+// @Description 使用验证邮件中的令牌激活账号，已验证的账号重复验证同样返回成功
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param request body dto.VerifyEmailRequest true "验证令牌"
+// @Success 200 {object} response.Response "验证成功"
+// @Failure 400 {object} response.Response "验证链接无效或已过期"
+// @Router /blog/auth/verify-email [post]
+func (s *BlogService) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.blogUseCase.VerifyEmail(req.Token); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// ResendVerification 重新发送验证邮件
+// @Summary 重新发送验证邮件
+// @Description 向未验证的账号重新发送验证邮件，同一账号每分钟最多发送一次；邮箱未注册或已验证时同样返回成功
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param request body dto.ResendVerificationRequest true "注册邮箱"
+// @Success 200 {object} response.Response "发送成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 429 {object} response.Response "发送过于频繁"
+// @Failure 500 {object} response.Response "发送失败"
+// @Router /blog/auth/verify-email/resend [post]
+func (s *BlogService) ResendVerification(c *gin.Context) {
+	var req dto.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	err := s.blogUseCase.ResendVerification(c.Request.Context(), req.Email)
+	if errors.Is(err, biz.ErrVerifyTooFrequent) {
+		response.Error(c, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// ForgotPassword 找回密码
+// @Summary 找回密码
+// @Description 向注册邮箱发送重置密码链接（需要 Redis 和邮件服务），邮箱未注册时同样返回成功；同一邮箱和同一 IP 每小时的请求数有上限
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param request body dto.ForgotPasswordRequest true "注册邮箱"
+// @Success 200 {object} response.Response "发送成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 429 {object} response.Response "请求过于频繁"
+// @Failure 500 {object} response.Response "发送失败"
+// @Router /blog/auth/password/forgot [post]
+func (s *BlogService) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	err := s.blogUseCase.ForgotPassword(c.Request.Context(), req.Email, c.ClientIP())
+	switch {
+	case errors.Is(err, biz.ErrResetTooFrequent):
+		response.Error(c, http.StatusTooManyRequests, err.Error())
+	case errors.Is(err, biz.ErrPasswordResetUnavailable):
+		response.BadRequest(c, err.Error())
+	case err != nil:
+		response.ServerError(c, err.Error())
+	default:
+		response.Success(c, nil)
+	}
+}
+
+// ResetPassword 重置密码
+// @Summary 重置密码
+// @Description 使用重置邮件中的令牌设置新密码，令牌只能使用一次
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param request body dto.ResetPasswordRequest true "重置令牌和新密码"
+// @Success 200 {object} response.Response "重置成功"
+// @Failure 400 {object} response.Response "重置链接无效或已过期"
+// @Router /blog/auth/password/reset [post]
+func (s *BlogService) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.blogUseCase.ResetPassword(req.Token, req.Password); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
+
 // GetUserInfo 获取用户信息
 // @Summary 获取当前用户信息
 // @Description 获取当前登录用户的详细信息
@@ -102,6 +229,8 @@ func (s *BlogService) GetUserInfo(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "文章ID"
+// @Param X-Article-Token header string false "加密文章访问令牌"
+// @Param lang query string false "语言代码，翻译组中有该语言的已发布版本时返回该版本，否则返回原文章"
 // @Success 200 {object} response.Response "获取成功"
 // @Failure 400 {object} response.Response "请求参数错误"
 // @Failure 404 {object} response.Response "文章不存在"
@@ -119,7 +248,7 @@ func (s *BlogService) GetArticleDetail(c *gin.Context) {
 		userID = id.(uint)
 	}
 
-	resp, err := s.blogUseCase.GetArticleDetail(uint(articleID), userID)
+	resp, err := s.blogUseCase.GetArticleDetail(uint(articleID), userID, articleAccessToken(c), c.Query("lang"), clientInfo(c))
 	if err != nil {
 		response.NotFound(c, err.Error())
 		return
@@ -128,6 +257,90 @@ func (s *BlogService) GetArticleDetail(c *gin.Context) {
 	response.Success(c, resp)
 }
 
+// GetArticleDetailBySlug 根据 slug 获取文章详情
+// @Summary 根据 slug 获取文章详情
+// @Description 通过 URL 别名获取文章详细内容，用于 SEO 友好的文章链接
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param slug path string true "文章 slug"
+// @Param X-Article-Token header string false "加密文章访问令牌"
+// @Param lang query string false "语言代码，翻译组中有该语言的已发布版本时返回该版本，否则返回原文章"
+// @Success 200 {object} response.Response{data=dto.ArticleDetailResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "文章不存在"
+// @Router /blog/articles/slug/{slug} [get]
+func (s *BlogService) GetArticleDetailBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+	if slug == "" {
+		response.BadRequest(c, "无效的文章slug")
+		return
+	}
+
+	// 获取用户ID（如果已登录）
+	userID := uint(0)
+	if id, exists := c.Get("user_id"); exists {
+		userID = id.(uint)
+	}
+
+	resp, err := s.blogUseCase.GetArticleDetailBySlug(slug, userID, articleAccessToken(c), c.Query("lang"), clientInfo(c))
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// UnlockArticle 解锁加密文章
+// @Summary 解锁加密文章
+// @Description 校验文章访问密码，成功后返回短期访问令牌，查看详情时通过 X-Article-Token 请求头携带
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param request body dto.UnlockArticleRequest true "访问密码"
+// @Success 200 {object} response.Response{data=dto.UnlockArticleResponse} "解锁成功"
+// @Failure 400 {object} response.Response "请求参数错误或密码错误"
+// @Router /blog/articles/{id}/unlock [post]
+func (s *BlogService) UnlockArticle(c *gin.Context) {
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的文章ID")
+		return
+	}
+
+	var req dto.UnlockArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.blogUseCase.UnlockArticle(uint(articleID), req.Password)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// clientInfo 读取请求方 IP 和 User-Agent
+func clientInfo(c *gin.Context) *dto.ClientInfo {
+	return &dto.ClientInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+// articleAccessToken 读取加密文章访问令牌（请求头优先，其次查询参数）
+func articleAccessToken(c *gin.Context) string {
+	if token := c.GetHeader("X-Article-Token"); token != "" {
+		return token
+	}
+	return c.Query("access_token")
+}
+
 // GetAdjacentArticles 获取文章的上一篇和下一篇
 // @Summary 获取相邻文章
 // @Description 获取指定文章的上一篇和下一篇文章
@@ -332,19 +545,21 @@ func (s *BlogService) GetUserFavorites(c *gin.Context) {
 // @Success 200 {object} response.Response "创建成功"
 // @Failure 400 {object} response.Response "请求参数错误"
 // @Failure 401 {object} response.Response "未授权"
+// @Failure 429 {object} response.Response "请求过于频繁"
 // @Router /blog/comments [post]
 func (s *BlogService) CreateComment(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
 	var req dto.CreateCommentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
 	req.UserID = userID
+	req.IP = c.ClientIP()
 
-	resp, err := s.blogUseCase.CreateComment(&req)
+	resp, err := s.blogUseCase.CreateComment(c.Request.Context(), &req)
 	if err != nil {
 		response.BadRequest(c, err.Error())
 		return
@@ -355,7 +570,7 @@ func (s *BlogService) CreateComment(c *gin.Context) {
 
 // GetArticleComments 获取文章评论列表
 // @Summary 获取文章评论
-// @Description 获取指定文章的评论列表（包含用户点赞状态）
+// @Description 分页获取指定文章的顶级评论（包含用户点赞状态和回复数），回复通过评论回复接口按需加载
 // @Tags 博客前台
 // @Accept json
 // @Produce json
@@ -391,6 +606,49 @@ func (s *BlogService) GetArticleComments(c *gin.Context) {
 	response.Success(c, resp)
 }
 
+// GetCommentReplies 获取评论回复列表
+// @Summary 获取评论回复
+// @Description 分页获取顶级评论下的回复（楼中楼），按时间正序，包含用户点赞状态
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param id path int true "顶级评论ID"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Success 200 {object} response.Response "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Router /blog/comments/{id}/replies [get]
+func (s *BlogService) GetCommentReplies(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的评论ID")
+		return
+	}
+
+	// 获取用户ID（如果已登录）
+	userID := uint(0)
+	if id, exists := c.Get("user_id"); exists {
+		userID = id.(uint)
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	resp, err := s.blogUseCase.GetCommentReplies(uint(commentID), userID, page, limit)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
 // LikeComment 点赞评论
 // @Summary 点赞评论
 // @Description 用户点赞评论
@@ -447,19 +705,20 @@ func (s *BlogService) UnlikeComment(c *gin.Context) {
 	response.Success(c, nil)
 }
 
-// DeleteComment 删除评论
-// @Summary 删除评论
-// @Description 用户删除自己的评论
+// UpdateComment 编辑评论
+// @Summary 编辑评论
+// @Description 作者在发表后的编辑时间窗口内编辑自己的评论，编辑前的内容保存为编辑记录，响应中 edited 为 true
 // @Tags 博客前台
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "评论ID"
-// @Success 200 {object} response.Response "删除成功"
-// @Failure 400 {object} response.Response "请求参数错误"
+// @Param request body dto.UpdateCommentRequest true "评论内容"
+// @Success 200 {object} response.Response{data=dto.CommentResponse} "编辑成功"
+// @Failure 400 {object} response.Response "请求参数错误、无权编辑或已超过可编辑时间"
 // @Failure 401 {object} response.Response "未授权"
-// @Router /blog/comments/{id} [delete]
-func (s *BlogService) DeleteComment(c *gin.Context) {
+// @Router /blog/comments/{id} [put]
+func (s *BlogService) UpdateComment(c *gin.Context) {
 	userID := c.GetUint("user_id")
 	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -467,76 +726,13 @@ func (s *BlogService) DeleteComment(c *gin.Context) {
 		return
 	}
 
-	if err := s.blogUseCase.DeleteComment(uint(commentID), userID); err != nil {
-		response.BadRequest(c, err.Error())
-		return
-	}
-
-	response.Success(c, nil)
-}
-
-// GetGuestbookMessages 获取留言板消息列表
-// @Summary 获取留言板消息
-// @Description 获取留言板消息列表（包含用户点赞状态）
-// @Tags 博客前台
-// @Accept json
-// @Produce json
-// @Param page query int false "页码" default(1)
-// @Param limit query int false "每页数量" default(10)
-// @Success 200 {object} response.Response "获取成功"
-// @Failure 500 {object} response.Response "服务器错误"
-// @Router /blog/guestbook [get]
-func (s *BlogService) GetGuestbookMessages(c *gin.Context) {
-	// 获取用户ID（如果已登录）
-	userID := uint(0)
-	if id, exists := c.Get("user_id"); exists {
-		userID = id.(uint)
-	}
-
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-
-	// 使用 article_id = 0 表示留言板消息
-	resp, err := s.blogUseCase.GetArticleComments(0, userID, page, limit)
-	if err != nil {
-		response.ServerError(c, err.Error())
-		return
-	}
-
-	response.Success(c, resp)
-}
-
-// CreateGuestbookMessage 创建留言板消息
-// @Summary 创建留言板消息
-// @Description 用户在留言板发表留言
-// @Tags 博客前台
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param request body dto.CreateGuestbookMessageRequest true "留言信息"
-// @Success 200 {object} response.Response "创建成功"
-// @Failure 400 {object} response.Response "请求参数错误"
-// @Failure 401 {object} response.Response "未授权"
-// @Router /blog/guestbook [post]
-func (s *BlogService) CreateGuestbookMessage(c *gin.Context) {
-	userID := c.GetUint("user_id")
-
-	var guestbookReq dto.CreateGuestbookMessageRequest
-	if err := c.ShouldBindJSON(&guestbookReq); err != nil {
-		response.BadRequest(c, err.Error())
+	var req dto.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
 		return
 	}
 
-	// 转换为评论请求，ArticleID为nil表示留言板消息
-	req := dto.CreateCommentRequest{
-		ArticleID:     nil, // nil表示留言板消息
-		UserID:        userID,
-		ParentID:      guestbookReq.ParentID,
-		ReplyToUserID: guestbookReq.ReplyToUserID,
-		Content:       guestbookReq.Content,
-	}
-
-	resp, err := s.blogUseCase.CreateComment(&req)
+	resp, err := s.blogUseCase.UpdateComment(uint(commentID), userID, &req)
 	if err != nil {
 		response.BadRequest(c, err.Error())
 		return
@@ -545,27 +741,27 @@ func (s *BlogService) CreateGuestbookMessage(c *gin.Context) {
 	response.Success(c, resp)
 }
 
-// DeleteGuestbookMessage 删除留言板消息
-// @Summary 删除留言板消息
-// @Description 删除留言板消息（需要是管理员或消息作者）
+// DeleteComment 删除评论
+// @Summary 删除评论
+// @Description 用户删除自己的评论（需在编辑时间窗口内），管理员和父评论作者不受时间限制
 // @Tags 博客前台
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param id path int true "留言ID"
+// @Param id path int true "评论ID"
 // @Success 200 {object} response.Response "删除成功"
 // @Failure 400 {object} response.Response "请求参数错误"
 // @Failure 401 {object} response.Response "未授权"
-// @Router /blog/guestbook/{id} [delete]
-func (s *BlogService) DeleteGuestbookMessage(c *gin.Context) {
+// @Router /blog/comments/{id} [delete]
+func (s *BlogService) DeleteComment(c *gin.Context) {
 	userID := c.GetUint("user_id")
-	messageID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		response.BadRequest(c, "无效的留言ID")
+		response.BadRequest(c, "无效的评论ID")
 		return
 	}
 
-	if err := s.blogUseCase.DeleteComment(uint(messageID), userID); err != nil {
+	if err := s.blogUseCase.DeleteComment(uint(commentID), userID); err != nil {
 		response.BadRequest(c, err.Error())
 		return
 	}
@@ -596,6 +792,28 @@ func (s *BlogService) GetUserStats(c *gin.Context) {
 	response.Success(c, resp)
 }
 
+// ExportUserData 导出当前用户的数据
+// @Summary 导出我的数据
+// @Description 导出系统中保存的当前用户的全部数据（资料、评论及编辑记录、点赞、收藏、表情回应、浏览和访问记录）为 JSON 文件
+// @Tags 博客前台
+// @Produce application/json
+// @Security BearerAuth
+// @Success 200 {object} dto.UserDataExport "JSON 文件"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/user/export [get]
+func (s *BlogService) ExportUserData(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	export, err := s.blogUseCase.ExportUserData(userID)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	writeUserDataExport(c, export)
+}
+
 // UpdateProfile 更新用户资料
 // @Summary 更新用户资料
 // @Description 用户更新个人资料
@@ -613,7 +831,7 @@ func (s *BlogService) UpdateProfile(c *gin.Context) {
 
 	var req dto.UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
@@ -655,7 +873,7 @@ func (s *BlogService) ChangePassword(c *gin.Context) {
 
 	var req dto.ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
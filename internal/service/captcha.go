@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// CaptchaService 人机验证服务
+type CaptchaService struct {
+	captchaUseCase biz.CaptchaUseCase
+}
+
+// NewCaptchaService 创建人机验证服务
+func NewCaptchaService(captchaUseCase biz.CaptchaUseCase) *CaptchaService {
+	return &CaptchaService{
+		captchaUseCase: captchaUseCase,
+	}
+}
+
+// CheckCaptcha 核销验证凭证，供人机验证中间件使用
+func (s *CaptchaService) CheckCaptcha(scene, ticket string) error {
+	return s.captchaUseCase.Check(scene, ticket)
+}
+
+// Generate 获取验证码
+// @Summary 获取验证码
+// @Description 获取场景的验证码：image 返回验证码图片；slider 返回背景图、滑块图片和滑块纵坐标，答案为滑块的横坐标；
+// @Description turnstile、hcaptcha 只返回前端组件的站点密钥。enabled 为 false 时该场景无需验证
+// @Tags 人机验证
+// @Produce json
+// @Param scene query string true "场景：login, register, comment, password_reset, friend_link"
+// @Success 200 {object} response.Response{data=dto.CaptchaResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /captcha [get]
+func (s *CaptchaService) Generate(c *gin.Context) {
+	var req dto.CaptchaRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.captchaUseCase.Generate(req.Scene)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Verify 校验验证码
+// @Summary 校验验证码
+// @Description 校验答案，通过后返回一次性验证凭证，提交登录、注册等表单时放在 X-Captcha-Ticket 请求头中；
+// @Description 每个验证码只能校验一次，失败后需要重新获取
+// @Tags 人机验证
+// @Accept json
+// @Produce json
+// @Param request body dto.CaptchaVerifyRequest true "验证答案"
+// @Success 200 {object} response.Response{data=dto.CaptchaTicket} "验证通过"
+// @Failure 400 {object} response.Response "验证码错误或已过期"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /captcha/verify [post]
+func (s *CaptchaService) Verify(c *gin.Context) {
+	var req dto.CaptchaVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	ticket, err := s.captchaUseCase.Verify(c.Request.Context(), &req, c.ClientIP())
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, ticket)
+}
+
+// handleError 将业务错误转换为响应
+func (s *CaptchaService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrCaptchaSceneInvalid), errors.Is(err, biz.ErrCaptchaInvalid):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
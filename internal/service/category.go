@@ -59,7 +59,7 @@ func (s *CategoryService) Create(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
@@ -87,7 +87,7 @@ func (s *CategoryService) Create(c *gin.Context) {
 func (s *CategoryService) Delete(c *gin.Context) {
 	var req dto.IDRequest
 	if err := c.ShouldBindUri(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
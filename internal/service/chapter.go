@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
 	"github.com/ydcloud-dy/leaf-api/internal/data"
 	"github.com/ydcloud-dy/leaf-api/internal/model/po"
 	"github.com/ydcloud-dy/leaf-api/pkg/response"
@@ -98,7 +99,7 @@ func (s *ChapterService) CreateChapter(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.Error(c, 400, "参数错误: "+err.Error())
+		response.BindError(c, err)
 		return
 	}
 
@@ -114,6 +115,9 @@ func (s *ChapterService) CreateChapter(c *gin.Context) {
 		return
 	}
 
+	// 刷新站点地图
+	biz.InvalidateSitemap()
+
 	response.Success(c, chapter)
 }
 
@@ -153,7 +157,7 @@ func (s *ChapterService) UpdateChapter(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.Error(c, 400, "参数错误: "+err.Error())
+		response.BindError(c, err)
 		return
 	}
 
@@ -176,6 +180,9 @@ func (s *ChapterService) UpdateChapter(c *gin.Context) {
 		return
 	}
 
+	// 刷新站点地图
+	biz.InvalidateSitemap()
+
 	response.Success(c, chapter)
 }
 
@@ -219,6 +226,9 @@ func (s *ChapterService) DeleteChapter(c *gin.Context) {
 		return
 	}
 	
+	// 刷新站点地图
+	biz.InvalidateSitemap()
+
 	response.Success(c, nil)
 }
 
@@ -266,7 +276,7 @@ func (s *ChapterService) GetChaptersByTag(c *gin.Context) {
 
 	// 一次性查询所有章节的文章 (优化N+1查询问题)
 	var allArticles []po.Article
-	s.data.GetDB().Where("chapter_id IN ? AND status = 1", chapterIDs).
+	s.data.GetDB().Where("chapter_id IN ? AND status = 1 AND visibility <> ?", chapterIDs, po.VisibilityPrivate).
 		Select("id, title, chapter_id, view_count, created_at").
 		Find(&allArticles)
 
@@ -1,11 +1,14 @@
 package service
 
 import (
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ydcloud-dy/leaf-api/internal/biz"
 	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
 	"github.com/ydcloud-dy/leaf-api/pkg/response"
 )
 
@@ -31,7 +34,7 @@ func NewCommentService(commentUseCase biz.CommentUseCase) *CommentService {
 // @Param page query int false "页码" default(1)
 // @Param limit query int false "每页数量" default(10)
 // @Param article_id query int false "文章ID"
-// @Param status query string false "评论状态"
+// @Param status query string false "评论状态 0:待审核 1:已通过 2:垃圾评论 3:回收站，传 0 查询审核队列"
 // @Success 200 {object} response.Response "获取成功"
 // @Failure 401 {object} response.Response "未授权"
 // @Failure 500 {object} response.Response "服务器错误"
@@ -75,7 +78,7 @@ func (s *CommentService) List(c *gin.Context) {
 func (s *CommentService) Delete(c *gin.Context) {
 	var req dto.IDRequest
 	if err := c.ShouldBindUri(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
@@ -89,13 +92,13 @@ func (s *CommentService) Delete(c *gin.Context) {
 
 // UpdateStatus 更新评论状态
 // @Summary 更新评论状态
-// @Description 更新评论的审核状态（待审核/已通过/已拒绝）
+// @Description 更新评论的审核状态（待审核/已通过/垃圾评论/回收站），只有审核通过的评论在前台显示并计入评论数
 // @Tags 评论管理
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "评论ID"
-// @Param request body object{status=int} true "状态信息 0:待审核 1:已通过 2:已拒绝"
+// @Param request body dto.UpdateCommentStatusRequest true "状态信息 0:待审核 1:已通过 2:垃圾评论 3:回收站"
 // @Success 200 {object} response.Response "更新成功"
 // @Failure 400 {object} response.Response "请求参数错误"
 // @Failure 401 {object} response.Response "未授权"
@@ -104,22 +107,117 @@ func (s *CommentService) Delete(c *gin.Context) {
 func (s *CommentService) UpdateStatus(c *gin.Context) {
 	var idReq dto.IDRequest
 	if err := c.ShouldBindUri(&idReq); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
-	var req struct {
-		Status int `json:"status" binding:"required,oneof=0 1 2"` // 0: pending, 1: approved, 2: rejected
-	}
+	var req dto.UpdateCommentStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
-	if err := s.commentUseCase.UpdateStatus(idReq.ID, req.Status); err != nil {
+	if err := s.commentUseCase.UpdateStatus(c.Request.Context(), idReq.ID, *req.Status); err != nil {
 		response.ServerError(c, err.Error())
 		return
 	}
 
 	response.Success(c, nil)
 }
+
+// ListEdits 查询评论编辑记录
+// @Summary 获取评论编辑记录
+// @Description 获取评论每次编辑前的内容，按编辑时间倒序
+// @Tags 评论管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "评论ID"
+// @Success 200 {object} response.Response "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /comments/{id}/edits [get]
+func (s *CommentService) ListEdits(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	edits, err := s.commentUseCase.ListEdits(req.ID)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, edits)
+}
+
+// BatchUpdateStatus 批量审核评论
+// @Summary 批量审核评论
+// @Description 在同一事务中批量通过（1）、退回待审核（0）、标记垃圾（2）或移入回收站（3）评论，返回每条评论的处理结果
+// @Tags 评论管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.BatchCommentStatusRequest true "评论ID列表和目标状态"
+// @Success 200 {object} response.Response{data=dto.BatchCommentStatusResponse} "处理完成"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /comments/batch/status [post]
+func (s *CommentService) BatchUpdateStatus(c *gin.Context) {
+	var req dto.BatchCommentStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.commentUseCase.BatchUpdateStatus(c.Request.Context(), &req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Export 导出评论
+// @Summary 导出评论
+// @Description 按文章和状态筛选导出评论，format 为 csv（默认，带 UTF-8 BOM）或 json；内容为用户提交的原始文本
+// @Tags 评论管理
+// @Produce text/csv
+// @Produce application/json
+// @Security BearerAuth
+// @Param format query string false "导出格式：csv、json" default(csv)
+// @Param article_id query int false "文章ID"
+// @Param status query string false "评论状态 0:待审核 1:已通过 2:垃圾评论 3:回收站"
+// @Success 200 "CSV 或 JSON 文件"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /comments/export [get]
+func (s *CommentService) Export(c *gin.Context) {
+	req := dto.CommentExportRequest{Format: biz.CommentExportCSV}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("leaf-comments-%s.%s", time.Now().Format("20060102-150405"), req.Format)
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	if req.Format == biz.CommentExportJSON {
+		c.Header("Content-Type", "application/json; charset=utf-8")
+	} else {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+	}
+	if err := s.commentUseCase.Export(c.Writer, &req); err != nil {
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+			response.ServerError(c, err.Error())
+			return
+		}
+		// 已开始输出，只能中断下载
+		logger.FromContext(c.Request.Context()).Error("Failed to stream comment export: ", err)
+	}
+}
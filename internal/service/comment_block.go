@@ -0,0 +1,158 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// CommentBlockService 评论屏蔽规则服务
+type CommentBlockService struct {
+	commentBlockUseCase biz.CommentBlockUseCase
+}
+
+// NewCommentBlockService 创建评论屏蔽规则服务
+func NewCommentBlockService(commentBlockUseCase biz.CommentBlockUseCase) *CommentBlockService {
+	return &CommentBlockService{
+		commentBlockUseCase: commentBlockUseCase,
+	}
+}
+
+// List 屏蔽规则列表
+// @Summary 获取评论屏蔽规则列表
+// @Description 分页获取评论屏蔽规则，包含命中次数和最后命中时间
+// @Tags 评论管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Param type query string false "规则类型：ip、email、keyword"
+// @Param keyword query string false "按规则值或备注搜索"
+// @Success 200 {object} response.Response "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /comments/block-rules [get]
+func (s *CommentBlockService) List(c *gin.Context) {
+	req := dto.CommentBlockListRequest{PageRequest: dto.PageRequest{Page: 1, Limit: 10}}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.commentBlockUseCase.List(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// Create 创建屏蔽规则
+// @Summary 创建评论屏蔽规则
+// @Description 类型为 ip 时值为 IP 或 CIDR 网段；email 时为完整邮箱或域名（同时匹配子域名）；keyword 时为评论内容中的关键词（不区分大小写）。
+// @Description 动作为 reject 时直接拒绝评论，spam 时评论保存为垃圾评论。管理员发表的评论不受屏蔽规则限制
+// @Tags 评论管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CommentBlockRequest true "规则信息"
+// @Success 200 {object} response.Response "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /comments/block-rules [post]
+func (s *CommentBlockService) Create(c *gin.Context) {
+	var req dto.CommentBlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.commentBlockUseCase.Create(&req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Update 更新屏蔽规则
+// @Summary 更新评论屏蔽规则
+// @Description 更新规则内容，enabled 为空时保留原启用状态
+// @Tags 评论管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "规则ID"
+// @Param request body dto.CommentBlockRequest true "规则信息"
+// @Success 200 {object} response.Response "更新成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "规则不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /comments/block-rules/{id} [put]
+func (s *CommentBlockService) Update(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.CommentBlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.commentBlockUseCase.Update(uri.ID, &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Delete 删除屏蔽规则
+// @Summary 删除评论屏蔽规则
+// @Description 删除评论屏蔽规则，已被标记的评论不受影响
+// @Tags 评论管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "规则ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "规则不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /comments/block-rules/{id} [delete]
+func (s *CommentBlockService) Delete(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.commentBlockUseCase.Delete(req.ID); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// handleError 将业务错误映射为响应
+func (s *CommentBlockService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrCommentBlockNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrCommentBlockInvalid):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// FavoriteService 收藏夹服务
+type FavoriteService struct {
+	favoriteUseCase biz.FavoriteUseCase
+}
+
+// NewFavoriteService 创建收藏夹服务
+func NewFavoriteService(favoriteUseCase biz.FavoriteUseCase) *FavoriteService {
+	return &FavoriteService{
+		favoriteUseCase: favoriteUseCase,
+	}
+}
+
+// ListFolders 收藏夹列表
+// @Summary 获取收藏夹列表
+// @Description 获取当前用户的收藏夹及各自的收藏数，第一个是 ID 为 0 的默认收藏夹
+// @Tags 博客前台
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]dto.FavoriteFolderInfo} "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/favorites/folders [get]
+func (s *FavoriteService) ListFolders(c *gin.Context) {
+	folders, err := s.favoriteUseCase.ListFolders(c.GetUint("user_id"))
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, folders)
+}
+
+// CreateFolder 创建收藏夹
+// @Summary 创建收藏夹
+// @Description 创建收藏夹，名称不能与已有收藏夹重复
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.FavoriteFolderRequest true "收藏夹信息"
+// @Success 200 {object} response.Response{data=dto.FavoriteFolderInfo} "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误或收藏夹已达上限"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /blog/favorites/folders [post]
+func (s *FavoriteService) CreateFolder(c *gin.Context) {
+	var req dto.FavoriteFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	folder, err := s.favoriteUseCase.CreateFolder(c.GetUint("user_id"), &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, folder)
+}
+
+// UpdateFolder 修改收藏夹
+// @Summary 修改收藏夹
+// @Description 修改收藏夹的名称和描述
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "收藏夹ID"
+// @Param request body dto.FavoriteFolderRequest true "收藏夹信息"
+// @Success 200 {object} response.Response{data=dto.FavoriteFolderInfo} "修改成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "收藏夹不存在"
+// @Router /blog/favorites/folders/{id} [put]
+func (s *FavoriteService) UpdateFolder(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+	var req dto.FavoriteFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	folder, err := s.favoriteUseCase.UpdateFolder(c.GetUint("user_id"), idReq.ID, &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, folder)
+}
+
+// DeleteFolder 删除收藏夹
+// @Summary 删除收藏夹
+// @Description 删除收藏夹，其中的收藏移回默认收藏夹，不会取消收藏
+// @Tags 博客前台
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "收藏夹ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "收藏夹不存在"
+// @Router /blog/favorites/folders/{id} [delete]
+func (s *FavoriteService) DeleteFolder(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.favoriteUseCase.DeleteFolder(c.GetUint("user_id"), req.ID); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// List 我的收藏
+// @Summary 获取我的收藏
+// @Description 分页获取当前用户的收藏，按收藏时间倒序；不传 folder_id 时返回全部收藏，传 0 时只返回默认收藏夹
+// @Tags 博客前台
+// @Produce json
+// @Security BearerAuth
+// @Param folder_id query int false "收藏夹ID"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Success 200 {object} response.Response{data=[]dto.FavoriteInfo} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "收藏夹不存在"
+// @Router /blog/favorites [get]
+func (s *FavoriteService) List(c *gin.Context) {
+	req := dto.FavoriteListRequest{PageRequest: dto.PageRequest{Page: 1, Limit: 10}}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.favoriteUseCase.List(c.GetUint("user_id"), &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// Add 收藏文章
+// @Summary 收藏文章到收藏夹
+// @Description 收藏已发布的文章，folder_id 为 0 或不传时放入默认收藏夹
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.AddFavoriteRequest true "收藏信息"
+// @Success 200 {object} response.Response "收藏成功"
+// @Failure 400 {object} response.Response "请求参数错误或已收藏"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "文章或收藏夹不存在"
+// @Router /blog/favorites [post]
+func (s *FavoriteService) Add(c *gin.Context) {
+	var req dto.AddFavoriteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.favoriteUseCase.Add(c.GetUint("user_id"), &req); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Remove 取消收藏
+// @Summary 取消收藏
+// @Description 取消收藏文章，未收藏时不报错
+// @Tags 博客前台
+// @Produce json
+// @Security BearerAuth
+// @Param article_id path int true "文章ID"
+// @Success 200 {object} response.Response "取消成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /blog/favorites/{article_id} [delete]
+func (s *FavoriteService) Remove(c *gin.Context) {
+	articleID, err := strconv.ParseUint(c.Param("article_id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的文章ID")
+		return
+	}
+
+	if err := s.favoriteUseCase.Remove(c.GetUint("user_id"), uint(articleID)); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Move 移动收藏
+// @Summary 移动收藏到收藏夹
+// @Description 将多篇已收藏的文章移动到收藏夹，folder_id 为 0 时移回默认收藏夹；未收藏的文章会被忽略
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.MoveFavoritesRequest true "移动信息"
+// @Success 200 {object} response.Response "移动成功，返回移动的收藏数"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "收藏夹不存在"
+// @Router /blog/favorites/move [put]
+func (s *FavoriteService) Move(c *gin.Context) {
+	var req dto.MoveFavoritesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	moved, err := s.favoriteUseCase.Move(c.GetUint("user_id"), &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"moved": moved})
+}
+
+// handleError 将收藏夹业务错误转换为响应
+func (s *FavoriteService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrFavoriteArticleNotFound), errors.Is(err, biz.ErrFavoriteFolderNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrFavoriteExists), errors.Is(err, biz.ErrFavoriteFolderNameEmpty),
+		errors.Is(err, biz.ErrFavoriteFolderExists), errors.Is(err, biz.ErrFavoriteFolderLimit):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+)
+
+// FeedService RSS 订阅服务
+type FeedService struct {
+	feedUseCase biz.FeedUseCase
+}
+
+// NewFeedService 创建 RSS 订阅服务
+func NewFeedService(feedUseCase biz.FeedUseCase) *FeedService {
+	return &FeedService{feedUseCase: feedUseCase}
+}
+
+// ArticleComments 文章评论订阅
+// @Summary 文章评论 RSS
+// @Description 返回文章最新 50 条审核通过评论的 RSS 2.0 订阅，用于在阅读器中关注文章讨论；私密和加密文章不提供订阅，需配置 sitemap.site_url
+// @Tags 博客前台
+// @Produce xml
+// @Param id path int true "文章ID"
+// @Success 200 {string} string "RSS 订阅"
+// @Failure 404 {string} string "文章不存在或订阅未启用"
+// @Router /blog/articles/{id}/comments/feed [get]
+func (s *FeedService) ArticleComments(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	content, err := s.feedUseCase.ArticleComments(req.ID, requestURL(c))
+	if err != nil {
+		if errors.Is(err, biz.ErrFeedDisabled) || errors.Is(err, biz.ErrFeedNotFound) {
+			c.String(http.StatusNotFound, err.Error())
+			return
+		}
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=600")
+	c.Data(http.StatusOK, "application/rss+xml; charset=utf-8", content)
+}
+
+// requestURL 当前请求的完整地址，反向代理传入 X-Forwarded-Proto 时使用其协议
+func requestURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host + c.Request.URL.Path
+}
@@ -1,31 +1,61 @@
 package service
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
 	"github.com/ydcloud-dy/leaf-api/internal/data"
 	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
 	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/imaging"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
 	"github.com/ydcloud-dy/leaf-api/pkg/oss"
 	"github.com/ydcloud-dy/leaf-api/pkg/response"
 )
 
+const (
+	// videoFolder 视频的存储目录
+	videoFolder = "videos"
+)
+
+// defaultVideoExts 未配置 upload.video_exts 时允许的视频类型
+var defaultVideoExts = []string{"mp4", "webm", "mov", "m4v"}
+
 // FileService 文件服务
 type FileService struct {
-	data *data.Data
+	data                *data.Data
+	imageCleanupUseCase biz.ImageCleanupUseCase
+	uploadUseCase       biz.UploadUseCase
+	storageStatsUseCase biz.StorageStatsUseCase
+	settingUseCase      biz.SettingUseCase
 }
 
 // NewFileService 创建文件服务
-func NewFileService(d *data.Data) *FileService {
+func NewFileService(d *data.Data, imageCleanupUseCase biz.ImageCleanupUseCase, uploadUseCase biz.UploadUseCase, storageStatsUseCase biz.StorageStatsUseCase, settingUseCase biz.SettingUseCase) *FileService {
 	return &FileService{
-		data: d,
+		data:                d,
+		imageCleanupUseCase: imageCleanupUseCase,
+		uploadUseCase:       uploadUseCase,
+		storageStatsUseCase: storageStatsUseCase,
+		settingUseCase:      settingUseCase,
 	}
 }
 
 // Upload 上传文件
 // @Summary 上传文件
-// @Description 上传文件到OSS，支持图片、视频等多种文件类型
+// @Description 上传文件到OSS，支持图片、视频等多种文件类型；JPEG/PNG 图片按配置返回 WebP 地址（webp_url）和缩放版本（variants），可用于 srcset
 // @Tags 文件管理
 // @Accept multipart/form-data
 // @Produce json
@@ -48,35 +78,155 @@ func (s *FileService) Upload(c *gin.Context) {
 	// 获取文件夹参数
 	folder := c.DefaultPostForm("folder", "uploads")
 
-	// 上传到 OSS
-	url, err := oss.UploadFile(file, folder)
+	// 保存文件记录
+	fileRecord := &po.File{
+		Name:     file.Filename,
+		Size:     file.Size,
+		Type:     folder,
+		MimeType: file.Header.Get("Content-Type"),
+	}
+
+	// 上传到 OSS，JPEG/PNG 图片按配置压缩并生成 WebP 和缩放版本
+	var variants []oss.ImageVariant
+	if imaging.IsOptimizable(file.Filename) {
+		uploaded, err := oss.UploadImageFile(file, folder)
+		if err != nil {
+			response.ServerError(c, "上传文件失败: "+err.Error())
+			return
+		}
+		fileRecord.URL = uploaded.URL
+		fileRecord.WebPURL = uploaded.WebPURL
+		fileRecord.Size = uploaded.Size
+		variants = uploaded.Variants
+		if len(variants) > 0 {
+			encoded, _ := json.Marshal(variants)
+			fileRecord.Variants = string(encoded)
+		}
+	} else {
+		url, err := oss.UploadFile(file, folder)
+		if err != nil {
+			response.ServerError(c, "上传文件失败: "+err.Error())
+			return
+		}
+		fileRecord.URL = url
+	}
+
+	if err := s.data.FileRepo.Create(fileRecord); err != nil {
+		response.ServerError(c, "保存文件记录失败")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"url":      fileRecord.URL,
+		"webp_url": fileRecord.WebPURL,
+		"variants": variants,
+		"name":     file.Filename,
+		"size":     fileRecord.Size,
+		"id":       fileRecord.ID,
+	})
+}
+
+// UploadVideo 上传短视频
+// @Summary 上传短视频
+// @Description 上传短视频到 OSS（未配置时为本地存储），类型和大小由 upload.video_exts、upload.video_max_size 限制，并校验文件内容确为视频；返回的 markdown 可直接插入文章，渲染为 <video> 播放器
+// @Tags 文件管理
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "视频文件"
+// @Success 200 {object} response.Response "上传成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /files/videos [post]
+func (s *FileService) UploadVideo(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "文件上传失败")
+		return
+	}
+
+	mimeType, err := checkVideo(file, s.settingUseCase.Int(biz.SettingVideoMaxSize))
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	url, err := oss.UploadFile(file, videoFolder)
 	if err != nil {
 		response.ServerError(c, "上传文件失败: "+err.Error())
 		return
 	}
 
-	// 保存文件记录
 	fileRecord := &po.File{
 		Name:     file.Filename,
 		URL:      url,
 		Size:     file.Size,
-		Type:     folder,
-		MimeType: file.Header.Get("Content-Type"),
+		Type:     videoFolder,
+		MimeType: mimeType,
 	}
-
 	if err := s.data.FileRepo.Create(fileRecord); err != nil {
 		response.ServerError(c, "保存文件记录失败")
 		return
 	}
 
 	response.Success(c, gin.H{
-		"url":  url,
-		"name": file.Filename,
-		"size": file.Size,
-		"id":   fileRecord.ID,
+		"id":        fileRecord.ID,
+		"url":       fileRecord.URL,
+		"name":      file.Filename,
+		"size":      fileRecord.Size,
+		"mime_type": mimeType,
+		"markdown":  "![" + strings.TrimSuffix(file.Filename, filepath.Ext(file.Filename)) + "](" + fileRecord.URL + ")",
 	})
 }
 
+// checkVideo 校验视频的扩展名、大小和文件内容，返回视频的 MIME 类型
+// 内容嗅探无法识别 mov 等容器时为 application/octet-stream，此时按扩展名确定类型；识别为网页、图片等其他类型时拒绝
+// maxSize 为系统设置中的视频大小上限（MB）
+func checkVideo(file *multipart.FileHeader, maxSize int) (string, error) {
+	cfg := config.AppConfig.Upload
+	if file.Size > int64(maxSize)<<20 {
+		return "", fmt.Errorf("视频不能超过 %d MB", maxSize)
+	}
+
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(file.Filename), "."))
+	exts := cfg.VideoExts
+	if len(exts) == 0 {
+		exts = defaultVideoExts
+	}
+	allowed := false
+	for _, e := range exts {
+		if strings.EqualFold(strings.TrimPrefix(e, "."), ext) {
+			allowed = true
+			break
+		}
+	}
+	if ext == "" || !allowed {
+		return "", errors.New("不支持的视频格式")
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return "", errors.New("读取文件失败")
+	}
+	defer src.Close()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(src, head)
+
+	sniffed := http.DetectContentType(head[:n])
+	switch {
+	case strings.HasPrefix(sniffed, "video/"):
+		return sniffed, nil
+	case sniffed == "application/ogg", sniffed == "application/octet-stream":
+		if mimeType := mime.TypeByExtension("." + ext); mimeType != "" {
+			return mimeType, nil
+		}
+		return "video/" + ext, nil
+	default:
+		return "", errors.New("文件内容不是视频")
+	}
+}
+
 // List 查询文件列表
 // @Summary 获取文件列表
 // @Description 分页获取已上传的文件列表
@@ -124,7 +274,7 @@ func (s *FileService) List(c *gin.Context) {
 func (s *FileService) Delete(c *gin.Context) {
 	var req dto.IDRequest
 	if err := c.ShouldBindUri(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
@@ -141,6 +291,21 @@ func (s *FileService) Delete(c *gin.Context) {
 		response.ServerError(c, "删除文件失败: "+err.Error())
 		return
 	}
+	if file.WebPURL != "" {
+		if err := oss.DeleteFile(oss.GetObjectKeyFromURL(file.WebPURL)); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("Failed to delete WebP image: ", err)
+		}
+	}
+	if file.Variants != "" {
+		var variants []oss.ImageVariant
+		if err := json.Unmarshal([]byte(file.Variants), &variants); err == nil {
+			for _, variant := range variants {
+				if err := oss.DeleteFile(oss.GetObjectKeyFromURL(variant.URL)); err != nil {
+					logger.FromContext(c.Request.Context()).Warn("Failed to delete image variant: ", err)
+				}
+			}
+		}
+	}
 
 	// 删除数据库记录
 	if err := s.data.FileRepo.Delete(req.ID); err != nil {
@@ -150,3 +315,210 @@ func (s *FileService) Delete(c *gin.Context) {
 
 	response.Success(c, nil)
 }
+
+// CleanOrphanImages 清理未引用图片
+// @Summary 清理未引用图片
+// @Description 仅超级管理员可用。扫描文章（含回收站、历史版本和草稿）、评论、系列封面、头像和站点设置中引用的图片，与 OSS（未配置时为本地 uploads 目录）中的图片对比，删除不再被引用的图片及其文件记录；dry_run=true 时只返回待删除列表
+// @Tags 文件管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CleanOrphanImagesRequest false "清理参数"
+// @Success 200 {object} response.Response{data=dto.OrphanImageReport} "清理结果"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /files/orphans/clean [post]
+func (s *FileService) CleanOrphanImages(c *gin.Context) {
+	// 删除操作不可恢复，始终只允许超级管理员
+	if c.GetString("role") != "super_admin" {
+		response.Forbidden(c, "只有超级管理员可以清理图片")
+		return
+	}
+
+	var req dto.CleanOrphanImagesRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BindError(c, err)
+			return
+		}
+	}
+
+	report, err := s.imageCleanupUseCase.CleanOrphans(req.DryRun, time.Duration(req.MinAge)*time.Hour)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, report)
+}
+
+// StorageStats 存储用量统计
+// @Summary 存储用量统计
+// @Description 返回存储总用量、对象数、按顶层目录（articles、avatars、attachments 等）的用量和最近 12 个月每月新增；结果由后台任务定期扫描生成并缓存，refresh=true 时立即重新扫描
+// @Tags 文件管理
+// @Produce json
+// @Security BearerAuth
+// @Param refresh query bool false "立即重新扫描"
+// @Success 200 {object} response.Response{data=dto.StorageStats} "统计结果"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /files/stats [get]
+func (s *FileService) StorageStats(c *gin.Context) {
+	var (
+		stats *dto.StorageStats
+		err   error
+	)
+	if c.Query("refresh") == "true" {
+		stats, err = s.storageStatsUseCase.Refresh()
+	} else {
+		stats, err = s.storageStatsUseCase.Stats()
+	}
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, stats)
+}
+
+// InitUpload 创建分片上传
+// @Summary 创建分片上传
+// @Description 大文件按返回的 chunk_size 切分后逐片上传，全部上传后调用完成接口合并；已配置 OSS 时使用 OSS 分片上传，否则分片暂存在本地
+// @Tags 文件管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.InitUploadRequest true "文件信息"
+// @Success 200 {object} response.Response{data=dto.UploadSessionResponse} "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /files/uploads [post]
+func (s *FileService) InitUpload(c *gin.Context) {
+	var req dto.InitUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	session, err := s.uploadUseCase.Init(&req, c.GetUint("user_id"))
+	if err != nil {
+		s.handleUploadError(c, err)
+		return
+	}
+
+	response.Success(c, session)
+}
+
+// UploadStatus 查询分片上传进度
+// @Summary 查询分片上传进度
+// @Description 返回已上传的分片号，连接中断后据此只上传缺少的分片
+// @Tags 文件管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "上传ID"
+// @Success 200 {object} response.Response{data=dto.UploadSessionResponse} "获取成功"
+// @Failure 404 {object} response.Response "上传会话不存在或已过期"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /files/uploads/{id} [get]
+func (s *FileService) UploadStatus(c *gin.Context) {
+	session, err := s.uploadUseCase.Status(c.Param("id"), c.GetUint("user_id"))
+	if err != nil {
+		s.handleUploadError(c, err)
+		return
+	}
+
+	response.Success(c, session)
+}
+
+// UploadPart 上传分片
+// @Summary 上传分片
+// @Description 请求体为分片的原始字节，除最后一片外大小必须等于 chunk_size；重复上传同一分片会覆盖
+// @Tags 文件管理
+// @Accept application/octet-stream
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "上传ID"
+// @Param number path int true "分片号，从 1 开始"
+// @Success 200 {object} response.Response "上传成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "上传会话不存在或已过期"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /files/uploads/{id}/parts/{number} [put]
+func (s *FileService) UploadPart(c *gin.Context) {
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		response.BadRequest(c, "无效的分片号")
+		return
+	}
+	if c.Request.ContentLength <= 0 {
+		response.BadRequest(c, "请求需包含 Content-Length")
+		return
+	}
+
+	if err := s.uploadUseCase.UploadPart(c.Param("id"), c.GetUint("user_id"), number, c.Request.Body, c.Request.ContentLength); err != nil {
+		s.handleUploadError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"part_number": number})
+}
+
+// CompleteUpload 完成分片上传
+// @Summary 完成分片上传
+// @Description 合并全部分片并保存文件记录，返回结果与普通上传相同
+// @Tags 文件管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "上传ID"
+// @Success 200 {object} response.Response "上传成功"
+// @Failure 400 {object} response.Response "分片未全部上传"
+// @Failure 404 {object} response.Response "上传会话不存在或已过期"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /files/uploads/{id}/complete [post]
+func (s *FileService) CompleteUpload(c *gin.Context) {
+	file, err := s.uploadUseCase.Complete(c.Param("id"), c.GetUint("user_id"))
+	if err != nil {
+		s.handleUploadError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"url":  file.URL,
+		"name": file.Name,
+		"size": file.Size,
+		"id":   file.ID,
+	})
+}
+
+// AbortUpload 取消分片上传
+// @Summary 取消分片上传
+// @Description 取消上传并删除已上传的分片
+// @Tags 文件管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "上传ID"
+// @Success 200 {object} response.Response "取消成功"
+// @Failure 404 {object} response.Response "上传会话不存在或已过期"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /files/uploads/{id} [delete]
+func (s *FileService) AbortUpload(c *gin.Context) {
+	if err := s.uploadUseCase.Abort(c.Param("id"), c.GetUint("user_id")); err != nil {
+		s.handleUploadError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// handleUploadError 将分片上传的业务错误映射为响应
+func (s *FileService) handleUploadError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrUploadNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrUploadInvalid), errors.Is(err, biz.ErrUploadIncomplete):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// FollowService 关注服务
+type FollowService struct {
+	followUseCase biz.FollowUseCase
+}
+
+// NewFollowService 创建关注服务
+func NewFollowService(followUseCase biz.FollowUseCase) *FollowService {
+	return &FollowService{
+		followUseCase: followUseCase,
+	}
+}
+
+// Follow 关注用户
+// @Summary 关注用户
+// @Description 关注作者，之后可在关注动态中看到对方发布的文章；重复关注不报错
+// @Tags 博客前台
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "用户名"
+// @Success 200 {object} response.Response{data=dto.FollowStatusResponse} "关注成功"
+// @Failure 400 {object} response.Response "不能关注自己"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "用户不存在"
+// @Router /blog/users/{username}/follow [post]
+func (s *FollowService) Follow(c *gin.Context) {
+	status, err := s.followUseCase.Follow(c.GetUint("user_id"), c.Param("username"))
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, status)
+}
+
+// Unfollow 取消关注
+// @Summary 取消关注
+// @Description 取消关注用户，未关注时不报错
+// @Tags 博客前台
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "用户名"
+// @Success 200 {object} response.Response{data=dto.FollowStatusResponse} "取消成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "用户不存在"
+// @Router /blog/users/{username}/follow [delete]
+func (s *FollowService) Unfollow(c *gin.Context) {
+	status, err := s.followUseCase.Unfollow(c.GetUint("user_id"), c.Param("username"))
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, status)
+}
+
+// ListFollowers 粉丝列表
+// @Summary 获取粉丝列表
+// @Description 分页获取关注该用户的人，按关注时间倒序
+// @Tags 博客前台
+// @Produce json
+// @Param username path string true "用户名"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=[]dto.FollowUserInfo} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "用户不存在"
+// @Router /blog/users/{username}/followers [get]
+func (s *FollowService) ListFollowers(c *gin.Context) {
+	req := dto.PageRequest{Page: 1, Limit: 20}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.followUseCase.ListFollowers(c.Param("username"), &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// ListFollowing 关注列表
+// @Summary 获取关注列表
+// @Description 分页获取该用户关注的人，按关注时间倒序
+// @Tags 博客前台
+// @Produce json
+// @Param username path string true "用户名"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=[]dto.FollowUserInfo} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "用户不存在"
+// @Router /blog/users/{username}/following [get]
+func (s *FollowService) ListFollowing(c *gin.Context) {
+	req := dto.PageRequest{Page: 1, Limit: 20}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.followUseCase.ListFollowing(c.Param("username"), &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// Feed 关注动态
+// @Summary 获取关注动态
+// @Description 分页获取关注的作者（主作者或共同作者）最近发布的文章，按发布时间倒序，不包含私密文章
+// @Tags 博客前台
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Success 200 {object} response.Response{data=[]dto.ArticleListItem} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/user/feed [get]
+func (s *FollowService) Feed(c *gin.Context) {
+	req := dto.PageRequest{Page: 1, Limit: 10}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.followUseCase.Feed(c.GetUint("user_id"), &req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// handleError 将业务错误转换为响应
+func (s *FollowService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrProfileNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrFollowSelf):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// FriendLinkService 友情链接服务
+type FriendLinkService struct {
+	friendLinkUseCase biz.FriendLinkUseCase
+}
+
+// NewFriendLinkService 创建友情链接服务
+func NewFriendLinkService(friendLinkUseCase biz.FriendLinkUseCase) *FriendLinkService {
+	return &FriendLinkService{
+		friendLinkUseCase: friendLinkUseCase,
+	}
+}
+
+// ListVisible 前台友链列表
+// @Summary 获取友情链接
+// @Description 获取审核通过且可以访问的友情链接，按排序值排列；连续检测失败次数达到上限的链接不显示
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.FriendLinkResponse} "获取成功"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/friend-links [get]
+func (s *FriendLinkService) ListVisible(c *gin.Context) {
+	links, err := s.friendLinkUseCase.ListVisible()
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, links)
+}
+
+// Apply 申请友链
+// @Summary 申请友情链接
+// @Description 访客提交友链申请，管理员审核通过后显示；同一网站只能申请一次，每个 IP 每天的申请数有上限
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param request body dto.FriendLinkApplyRequest true "网站信息"
+// @Success 200 {object} response.Response "提交成功"
+// @Failure 400 {object} response.Response "请求参数错误或未开放申请"
+// @Failure 409 {object} response.Response "该网站已提交过申请"
+// @Failure 429 {object} response.Response "申请过于频繁"
+// @Router /blog/friend-links/apply [post]
+func (s *FriendLinkService) Apply(c *gin.Context) {
+	var req dto.FriendLinkApplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var userID *uint
+	if id := c.GetUint("user_id"); id > 0 {
+		userID = &id
+	}
+	if err := s.friendLinkUseCase.Apply(&req, userID, c.ClientIP()); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// List 后台友链列表
+// @Summary 获取友链列表
+// @Description 分页获取友情链接，包含待审核的申请和最近一次检测结果
+// @Tags 友情链接
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Param status query string false "状态：pending、approved、rejected"
+// @Param keyword query string false "按名称或地址搜索"
+// @Param dead query bool false "只显示连续检测失败次数达到上限的友链"
+// @Success 200 {object} response.Response "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /friend-links [get]
+func (s *FriendLinkService) List(c *gin.Context) {
+	req := dto.FriendLinkListRequest{PageRequest: dto.PageRequest{Page: 1, Limit: 10}}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.friendLinkUseCase.List(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// Create 添加友链
+// @Summary 添加友链
+// @Description 管理员添加友情链接，未指定状态时直接审核通过
+// @Tags 友情链接
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.FriendLinkRequest true "友链信息"
+// @Success 200 {object} response.Response "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 409 {object} response.Response "地址已存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /friend-links [post]
+func (s *FriendLinkService) Create(c *gin.Context) {
+	var req dto.FriendLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.friendLinkUseCase.Create(&req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Update 更新友链
+// @Summary 更新友链
+// @Description 更新友情链接，地址变化时清空检测记录；状态和排序值为空时不修改
+// @Tags 友情链接
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "友链ID"
+// @Param request body dto.FriendLinkRequest true "友链信息"
+// @Success 200 {object} response.Response "更新成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "友链不存在"
+// @Failure 409 {object} response.Response "地址已存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /friend-links/{id} [put]
+func (s *FriendLinkService) Update(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.FriendLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.friendLinkUseCase.Update(uri.ID, &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Delete 删除友链
+// @Summary 删除友链
+// @Description 删除友情链接或申请
+// @Tags 友情链接
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "友链ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "友链不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /friend-links/{id} [delete]
+func (s *FriendLinkService) Delete(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.friendLinkUseCase.Delete(req.ID); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Review 审核友链申请
+// @Summary 审核友链申请
+// @Description 通过或拒绝友链申请，拒绝时可填写原因
+// @Tags 友情链接
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "友链ID"
+// @Param request body dto.FriendLinkReviewRequest true "审核结果"
+// @Success 200 {object} response.Response "审核成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "友链不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /friend-links/{id}/review [post]
+func (s *FriendLinkService) Review(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.FriendLinkReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.friendLinkUseCase.Review(uri.ID, &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Sort 调整友链排序
+// @Summary 调整友链排序
+// @Description 批量设置友链的排序值，前台按排序值从小到大显示
+// @Tags 友情链接
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.FriendLinkSortRequest true "排序值"
+// @Success 200 {object} response.Response "调整成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /friend-links/sort [put]
+func (s *FriendLinkService) Sort(c *gin.Context) {
+	var req dto.FriendLinkSortRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.friendLinkUseCase.Sort(&req); err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Check 检测友链
+// @Summary 检测友链
+// @Description 立即请求友链地址检测是否可以访问，结果计入连续失败次数
+// @Tags 友情链接
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "友链ID"
+// @Success 200 {object} response.Response{data=dto.FriendLinkCheckResponse} "检测完成"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "友链不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /friend-links/{id}/check [post]
+func (s *FriendLinkService) Check(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.friendLinkUseCase.Check(c.Request.Context(), req.ID)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// handleError 将业务错误映射为响应
+func (s *FriendLinkService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrFriendLinkNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrFriendLinkExists):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, biz.ErrFriendLinkTooFrequent):
+		response.Error(c, http.StatusTooManyRequests, err.Error())
+	case errors.Is(err, biz.ErrFriendLinkInvalid), errors.Is(err, biz.ErrFriendLinkApplyClosed):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/data"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// graphqlMaxDepth 查询允许的最大嵌套层级，防止深层嵌套查询拖垮数据库
+const graphqlMaxDepth = 8
+
+// GraphQLService GraphQL 查询服务，只读地暴露博客前台可见的数据
+type GraphQLService struct {
+	articleUseCase  biz.ArticleUseCase
+	blogUseCase     biz.BlogUseCase
+	categoryUseCase biz.CategoryUseCase
+	tagUseCase      biz.TagUseCase
+	data            *data.Data
+	schema          graphql.Schema
+	schemaErr       error
+}
+
+// NewGraphQLService 创建 GraphQL 服务
+func NewGraphQLService(articleUseCase biz.ArticleUseCase, blogUseCase biz.BlogUseCase, categoryUseCase biz.CategoryUseCase, tagUseCase biz.TagUseCase, d *data.Data) *GraphQLService {
+	s := &GraphQLService{
+		articleUseCase:  articleUseCase,
+		blogUseCase:     blogUseCase,
+		categoryUseCase: categoryUseCase,
+		tagUseCase:      tagUseCase,
+		data:            d,
+	}
+	s.schema, s.schemaErr = s.buildSchema()
+	if s.schemaErr != nil {
+		logger.Error("Failed to build GraphQL schema: ", s.schemaErr)
+	}
+	return s
+}
+
+// graphqlViewer 当前请求的访问者信息，通过 context 传给解析函数
+type graphqlViewer struct {
+	userID      uint
+	accessToken string
+	client      *dto.ClientInfo
+}
+
+// graphqlViewerKey context 中访问者信息的 key
+type graphqlViewerKey struct{}
+
+// viewerFrom 从解析上下文中读取访问者信息
+func viewerFrom(ctx context.Context) *graphqlViewer {
+	if viewer, ok := ctx.Value(graphqlViewerKey{}).(*graphqlViewer); ok {
+		return viewer
+	}
+	return &graphqlViewer{}
+}
+
+// Query 执行 GraphQL 查询
+// @Summary GraphQL 查询
+// @Description 查询文章、标签、分类、章节和评论，支持嵌套选择字段。响应遵循 GraphQL 规范（data/errors），不使用统一响应结构
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param request body dto.GraphQLRequest false "GraphQL 请求（POST）"
+// @Param query query string false "GraphQL 查询语句（GET）"
+// @Param variables query string false "查询变量 JSON（GET）"
+// @Success 200 {object} object "查询结果"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Router /graphql [post]
+func (s *GraphQLService) Query(c *gin.Context) {
+	if s.schemaErr != nil {
+		response.ServerError(c, "GraphQL 服务不可用")
+		return
+	}
+
+	var req dto.GraphQLRequest
+	if c.Request.Method == http.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				response.BadRequest(c, "variables 格式错误")
+				return
+			}
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		response.BadRequest(c, "query 不能为空")
+		return
+	}
+	if queryDepth(req.Query) > graphqlMaxDepth {
+		response.BadRequest(c, "查询嵌套层级过深")
+		return
+	}
+
+	// 获取用户ID（如果已登录）
+	viewer := &graphqlViewer{accessToken: articleAccessToken(c), client: clientInfo(c)}
+	if id, exists := c.Get("user_id"); exists {
+		viewer.userID = id.(uint)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         s.schema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        context.WithValue(c.Request.Context(), graphqlViewerKey{}, viewer),
+	})
+
+	c.JSON(http.StatusOK, result)
+}
+
+// queryDepth 粗略计算查询的花括号嵌套层级（忽略字符串中的括号）
+func queryDepth(query string) int {
+	depth, maxDepth := 0, 0
+	inString := false
+	for i := 0; i < len(query); i++ {
+		switch query[i] {
+		case '"':
+			if i == 0 || query[i-1] != '\\' {
+				inString = !inString
+			}
+		case '{':
+			if !inString {
+				depth++
+				if depth > maxDepth {
+					maxDepth = depth
+				}
+			}
+		case '}':
+			if !inString {
+				depth--
+			}
+		}
+	}
+	return maxDepth
+}
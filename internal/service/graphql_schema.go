@@ -0,0 +1,428 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+)
+
+// GraphQL 列表分页参数
+const (
+	graphqlDefaultLimit = 10
+	graphqlMaxLimit     = 50
+)
+
+// pageArgs 分页参数定义
+func pageArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"page":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1},
+		"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: graphqlDefaultLimit},
+	}
+}
+
+// pageFrom 读取分页参数，limit 最大为 graphqlMaxLimit
+func pageFrom(args map[string]interface{}) (int, int) {
+	page, _ := args["page"].(int)
+	limit, _ := args["limit"].(int)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = graphqlDefaultLimit
+	}
+	if limit > graphqlMaxLimit {
+		limit = graphqlMaxLimit
+	}
+	return page, limit
+}
+
+// buildSchema 构建 GraphQL Schema，字段名与 REST 接口的 JSON 字段保持一致（snake_case）
+// 文章与分类、标签、章节互相引用，对象字段使用 thunk 延迟定义
+func (s *GraphQLService) buildSchema() (graphql.Schema, error) {
+	var articleType, categoryType, tagType, chapterType, commentType, commentPageType *graphql.Object
+
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"id":       &graphql.Field{Type: graphql.Int},
+			"username": &graphql.Field{Type: graphql.String},
+			"nickname": &graphql.Field{Type: graphql.String},
+			"avatar":   &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	coAuthorType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "CoAuthor",
+		Fields: graphql.Fields{
+			"id":       &graphql.Field{Type: graphql.Int},
+			"username": &graphql.Field{Type: graphql.String},
+			"nickname": &graphql.Field{Type: graphql.String},
+			"avatar":   &graphql.Field{Type: graphql.String},
+			"role":     &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	seoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "SEO",
+		Fields: graphql.Fields{
+			"meta_description": &graphql.Field{Type: graphql.String},
+			"meta_keywords":    &graphql.Field{Type: graphql.String},
+			"canonical_url":    &graphql.Field{Type: graphql.String},
+			"noindex":          &graphql.Field{Type: graphql.Boolean},
+		},
+	})
+
+	reactionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Reaction",
+		Fields: graphql.Fields{
+			"emoji":   &graphql.Field{Type: graphql.String},
+			"count":   &graphql.Field{Type: graphql.Int},
+			"reacted": &graphql.Field{Type: graphql.Boolean},
+		},
+	})
+
+	commentType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Comment",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":            &graphql.Field{Type: graphql.Int},
+				"article_id":    &graphql.Field{Type: graphql.Int},
+				"parent_id":     &graphql.Field{Type: graphql.Int},
+				"root_id":       &graphql.Field{Type: graphql.Int},
+				"content":       &graphql.Field{Type: graphql.String},
+				"content_html":  &graphql.Field{Type: graphql.String},
+				"like_count":    &graphql.Field{Type: graphql.Int},
+				"reply_count":   &graphql.Field{Type: graphql.Int},
+				"is_liked":      &graphql.Field{Type: graphql.Boolean},
+				"edited":        &graphql.Field{Type: graphql.Boolean},
+				"edited_at":     &graphql.Field{Type: graphql.DateTime},
+				"reactions":     &graphql.Field{Type: graphql.NewList(reactionType)},
+				"created_at":    &graphql.Field{Type: graphql.DateTime},
+				"user":          &graphql.Field{Type: userType},
+				"reply_to_user": &graphql.Field{Type: userType},
+				"replies": &graphql.Field{
+					Type:    commentPageType,
+					Args:    pageArgs(),
+					Resolve: s.resolveCommentReplies,
+				},
+			}
+		}),
+	})
+
+	commentPageType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "CommentPage",
+		Fields: graphql.Fields{
+			"list":  &graphql.Field{Type: graphql.NewList(commentType)},
+			"total": &graphql.Field{Type: graphql.Int},
+			"page":  &graphql.Field{Type: graphql.Int},
+			"limit": &graphql.Field{Type: graphql.Int},
+		},
+	})
+
+	articleType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Article",
+		Description: "文章，content_html 和 content_markdown 只在 article 查询中返回，加密文章未解锁时为空",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":               &graphql.Field{Type: graphql.Int},
+				"title":            &graphql.Field{Type: graphql.String},
+				"slug":             &graphql.Field{Type: graphql.String},
+				"summary":          &graphql.Field{Type: graphql.String},
+				"cover":            &graphql.Field{Type: graphql.String},
+				"content_html":     &graphql.Field{Type: graphql.String},
+				"content_markdown": &graphql.Field{Type: graphql.String},
+				"visibility":       &graphql.Field{Type: graphql.String},
+				"is_top":           &graphql.Field{Type: graphql.Boolean},
+				"word_count":       &graphql.Field{Type: graphql.Int},
+				"reading_time":     &graphql.Field{Type: graphql.Int},
+				"view_count":       &graphql.Field{Type: graphql.Int},
+				"like_count":       &graphql.Field{Type: graphql.Int},
+				"favorite_count":   &graphql.Field{Type: graphql.Int},
+				"comment_count":    &graphql.Field{Type: graphql.Int},
+				"created_at":       &graphql.Field{Type: graphql.DateTime},
+				"updated_at":       &graphql.Field{Type: graphql.DateTime},
+				"author":           &graphql.Field{Type: userType},
+				"authors":          &graphql.Field{Type: graphql.NewList(coAuthorType)},
+				"category":         &graphql.Field{Type: categoryType},
+				"tags":             &graphql.Field{Type: graphql.NewList(tagType)},
+				"seo":              &graphql.Field{Type: seoType},
+				"comments": &graphql.Field{
+					Type:    commentPageType,
+					Args:    pageArgs(),
+					Resolve: s.resolveArticleComments,
+				},
+			}
+		}),
+	})
+
+	articlePageType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ArticlePage",
+		Fields: graphql.Fields{
+			"list":  &graphql.Field{Type: graphql.NewList(articleType)},
+			"total": &graphql.Field{Type: graphql.Int},
+			"page":  &graphql.Field{Type: graphql.Int},
+			"limit": &graphql.Field{Type: graphql.Int},
+		},
+	})
+
+	// articlesField 分类、标签、章节下的文章列表字段，filter 根据父对象设置过滤条件
+	articlesField := func(filter func(source interface{}, req *dto.ArticleListRequest) error) *graphql.Field {
+		return &graphql.Field{
+			Type: articlePageType,
+			Args: pageArgs(),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				req := &dto.ArticleListRequest{}
+				if err := filter(p.Source, req); err != nil {
+					return nil, err
+				}
+				return s.listArticles(req, p.Args)
+			},
+		}
+	}
+
+	categoryType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Category",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":          &graphql.Field{Type: graphql.Int},
+				"name":        &graphql.Field{Type: graphql.String},
+				"description": &graphql.Field{Type: graphql.String},
+				"articles": articlesField(func(source interface{}, req *dto.ArticleListRequest) error {
+					name, err := categoryName(source)
+					req.Category = name
+					return err
+				}),
+			}
+		}),
+	})
+
+	tagType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Tag",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":    &graphql.Field{Type: graphql.Int},
+				"name":  &graphql.Field{Type: graphql.String},
+				"color": &graphql.Field{Type: graphql.String},
+				"articles": articlesField(func(source interface{}, req *dto.ArticleListRequest) error {
+					_, name, err := tagIdentity(source)
+					req.Tag = name
+					return err
+				}),
+				"chapters": &graphql.Field{
+					Type: graphql.NewList(chapterType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						id, _, err := tagIdentity(p.Source)
+						if err != nil {
+							return nil, err
+						}
+						return s.listChapters(id)
+					},
+				},
+			}
+		}),
+	})
+
+	chapterType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Chapter",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":        &graphql.Field{Type: graphql.Int},
+				"tag_id":    &graphql.Field{Type: graphql.Int},
+				"parent_id": &graphql.Field{Type: graphql.Int},
+				"name":      &graphql.Field{Type: graphql.String},
+				"sort":      &graphql.Field{Type: graphql.Int},
+				"articles": articlesField(func(source interface{}, req *dto.ArticleListRequest) error {
+					chapter, ok := source.(po.Chapter)
+					if !ok {
+						return errors.New("无效的章节")
+					}
+					req.ChapterID = fmt.Sprint(chapter.ID)
+					return nil
+				}),
+			}
+		}),
+	})
+
+	articlesArgs := pageArgs()
+	articlesArgs["category"] = &graphql.ArgumentConfig{Type: graphql.String, Description: "分类名称"}
+	articlesArgs["tag"] = &graphql.ArgumentConfig{Type: graphql.String, Description: "标签名称"}
+	articlesArgs["keyword"] = &graphql.ArgumentConfig{Type: graphql.String}
+	articlesArgs["author_id"] = &graphql.ArgumentConfig{Type: graphql.Int, Description: "主作者或共同作者"}
+	articlesArgs["sort"] = &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: "latest", Description: "latest, views, likes"}
+	articlesArgs["lang"] = &graphql.ArgumentConfig{Type: graphql.String, Description: "语言代码，翻译组只返回该语言的版本，没有时返回源文章"}
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"articles": &graphql.Field{
+				Type: articlePageType,
+				Args: articlesArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					req := &dto.ArticleListRequest{}
+					req.Category, _ = p.Args["category"].(string)
+					req.Tag, _ = p.Args["tag"].(string)
+					req.Keyword, _ = p.Args["keyword"].(string)
+					req.Sort, _ = p.Args["sort"].(string)
+					req.Lang, _ = p.Args["lang"].(string)
+					if authorID, ok := p.Args["author_id"].(int); ok && authorID > 0 {
+						req.AuthorID = uint(authorID)
+					}
+					return s.listArticles(req, p.Args)
+				},
+			},
+			"article": &graphql.Field{
+				Type:        articleType,
+				Description: "文章详情，按 id 或 slug 查询",
+				Args: graphql.FieldConfigArgument{
+					"id":   &graphql.ArgumentConfig{Type: graphql.Int},
+					"slug": &graphql.ArgumentConfig{Type: graphql.String},
+					"lang": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: s.resolveArticle,
+			},
+			"categories": &graphql.Field{
+				Type: graphql.NewList(categoryType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return s.categoryUseCase.List()
+				},
+			},
+			"tags": &graphql.Field{
+				Type: graphql.NewList(tagType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return s.tagUseCase.List()
+				},
+			},
+			"chapters": &graphql.Field{
+				Type: graphql.NewList(chapterType),
+				Args: graphql.FieldConfigArgument{
+					"tag_id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					tagID, _ := p.Args["tag_id"].(int)
+					return s.listChapters(uint(tagID))
+				},
+			},
+			"comments": &graphql.Field{
+				Type: commentPageType,
+				Args: graphql.FieldConfigArgument{
+					"article_id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					"page":       &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1},
+					"limit":      &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: graphqlDefaultLimit},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					articleID, _ := p.Args["article_id"].(int)
+					page, limit := pageFrom(p.Args)
+					return s.blogUseCase.GetArticleComments(uint(articleID), viewerFrom(p.Context).userID, page, limit)
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+// listArticles 查询前台可见的文章列表
+func (s *GraphQLService) listArticles(req *dto.ArticleListRequest, args map[string]interface{}) (interface{}, error) {
+	req.Page, req.Limit = pageFrom(args)
+	req.Public = true
+	resp, err := s.articleUseCase.List(req)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"list":  resp.Data,
+		"total": resp.Total,
+		"page":  resp.Page,
+		"limit": resp.Limit,
+	}, nil
+}
+
+// resolveArticle 查询文章详情，与 REST 详情接口一致会记录浏览量
+func (s *GraphQLService) resolveArticle(p graphql.ResolveParams) (interface{}, error) {
+	viewer := viewerFrom(p.Context)
+	lang, _ := p.Args["lang"].(string)
+
+	var detail *dto.ArticleDetailResponse
+	var err error
+	if id, ok := p.Args["id"].(int); ok && id > 0 {
+		detail, err = s.blogUseCase.GetArticleDetail(uint(id), viewer.userID, viewer.accessToken, lang, viewer.client)
+	} else if slug, ok := p.Args["slug"].(string); ok && slug != "" {
+		detail, err = s.blogUseCase.GetArticleDetailBySlug(slug, viewer.userID, viewer.accessToken, lang, viewer.client)
+	} else {
+		return nil, errors.New("需要提供 id 或 slug")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &detail.ArticleResponse, nil
+}
+
+// resolveArticleComments 查询文章的顶级评论（审核通过的）
+func (s *GraphQLService) resolveArticleComments(p graphql.ResolveParams) (interface{}, error) {
+	var articleID uint
+	switch article := p.Source.(type) {
+	case dto.ArticleListItem:
+		articleID = article.ID
+	case *dto.ArticleResponse:
+		articleID = article.ID
+	default:
+		return nil, errors.New("无效的文章")
+	}
+
+	page, limit := pageFrom(p.Args)
+	return s.blogUseCase.GetArticleComments(articleID, viewerFrom(p.Context).userID, page, limit)
+}
+
+// resolveCommentReplies 查询顶级评论下的回复，回复本身没有下级回复
+func (s *GraphQLService) resolveCommentReplies(p graphql.ResolveParams) (interface{}, error) {
+	var comment *dto.CommentResponse
+	switch source := p.Source.(type) {
+	case dto.CommentResponse:
+		comment = &source
+	case *dto.CommentResponse:
+		comment = source
+	default:
+		return nil, errors.New("无效的评论")
+	}
+	if comment.RootID != nil {
+		return nil, nil
+	}
+
+	page, limit := pageFrom(p.Args)
+	return s.blogUseCase.GetCommentReplies(comment.ID, viewerFrom(p.Context).userID, page, limit)
+}
+
+// listChapters 查询标签下的章节
+func (s *GraphQLService) listChapters(tagID uint) ([]po.Chapter, error) {
+	var chapters []po.Chapter
+	err := s.data.GetDB().Where("tag_id = ?", tagID).Order("sort ASC, id ASC").Find(&chapters).Error
+	if err != nil {
+		return nil, errors.New("查询章节失败")
+	}
+	return chapters, nil
+}
+
+// categoryName 读取分类名称，分类可能来自分类列表或文章的分类信息
+func categoryName(source interface{}) (string, error) {
+	switch category := source.(type) {
+	case po.Category:
+		return category.Name, nil
+	case *dto.CategoryInfo:
+		return category.Name, nil
+	}
+	return "", errors.New("无效的分类")
+}
+
+// tagIdentity 读取标签 ID 和名称，标签可能来自标签列表或文章的标签信息
+func tagIdentity(source interface{}) (uint, string, error) {
+	switch tag := source.(type) {
+	case po.Tag:
+		return tag.ID, tag.Name, nil
+	case dto.TagInfo:
+		return tag.ID, tag.Name, nil
+	}
+	return 0, "", errors.New("无效的标签")
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// GuestbookService 留言板服务
+type GuestbookService struct {
+	guestbookUseCase biz.GuestbookUseCase
+}
+
+// NewGuestbookService 创建留言板服务
+func NewGuestbookService(guestbookUseCase biz.GuestbookUseCase) *GuestbookService {
+	return &GuestbookService{
+		guestbookUseCase: guestbookUseCase,
+	}
+}
+
+// GetMessages 获取留言板消息列表
+// @Summary 获取留言板消息
+// @Description 分页获取留言板顶级留言（包含用户点赞状态和回复数），回复通过评论回复接口按需加载
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Success 200 {object} response.Response "获取成功"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/guestbook [get]
+func (s *GuestbookService) GetMessages(c *gin.Context) {
+	// 获取用户ID（如果已登录）
+	userID := uint(0)
+	if id, exists := c.Get("user_id"); exists {
+		userID = id.(uint)
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	resp, err := s.guestbookUseCase.List(userID, page, limit)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// CreateMessage 创建留言板消息
+// @Summary 创建留言板消息
+// @Description 用户在留言板发表留言或回复留言，与文章评论共用屏蔽规则和审核设置
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateGuestbookMessageRequest true "留言信息"
+// @Success 200 {object} response.Response "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误或留言板已关闭"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 429 {object} response.Response "请求过于频繁"
+// @Router /blog/guestbook [post]
+func (s *GuestbookService) CreateMessage(c *gin.Context) {
+	var req dto.CreateGuestbookMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.guestbookUseCase.Create(c.Request.Context(), &req, c.GetUint("user_id"), c.ClientIP())
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// DeleteMessage 删除留言板消息
+// @Summary 删除留言板消息
+// @Description 删除留言板消息（需要是管理员或消息作者），不能通过该接口删除文章评论
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "留言ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "留言不存在"
+// @Router /blog/guestbook/{id} [delete]
+func (s *GuestbookService) DeleteMessage(c *gin.Context) {
+	messageID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的留言ID")
+		return
+	}
+
+	if err := s.guestbookUseCase.Delete(uint(messageID), c.GetUint("user_id")); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// List 后台留言列表
+// @Summary 获取留言列表
+// @Description 分页获取留言板消息，包含待审核、垃圾和回收站中的留言，可按状态和内容筛选
+// @Tags 留言管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Param status query int false "状态 0:待审核 1:已通过 2:垃圾留言 3:回收站"
+// @Param keyword query string false "内容关键词"
+// @Success 200 {object} response.Response{data=dto.PageResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /guestbook [get]
+func (s *GuestbookService) List(c *gin.Context) {
+	req := dto.GuestbookListRequest{PageRequest: dto.PageRequest{Page: 1, Limit: 10}}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.guestbookUseCase.AdminList(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// UpdateStatus 审核留言
+// @Summary 更新留言状态
+// @Description 更新留言的审核状态，只有审核通过的留言在前台显示
+// @Tags 留言管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "留言ID"
+// @Param request body dto.UpdateCommentStatusRequest true "状态信息 0:待审核 1:已通过 2:垃圾留言 3:回收站"
+// @Success 200 {object} response.Response "更新成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "留言不存在"
+// @Router /guestbook/{id}/status [patch]
+func (s *GuestbookService) UpdateStatus(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.UpdateCommentStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.guestbookUseCase.UpdateStatus(c.Request.Context(), idReq.ID, *req.Status); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Delete 后台删除留言
+// @Summary 删除留言
+// @Description 删除留言，删除顶级留言时一并删除它的回复
+// @Tags 留言管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "留言ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "留言不存在"
+// @Router /guestbook/{id} [delete]
+func (s *GuestbookService) Delete(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.guestbookUseCase.AdminDelete(idReq.ID); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// handleError 将业务错误映射为响应
+func (s *GuestbookService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrGuestbookMessageNotFound):
+		response.NotFound(c, err.Error())
+	default:
+		response.BadRequest(c, err.Error())
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// LoginLogService 登录日志服务
+type LoginLogService struct {
+	loginLogUseCase biz.LoginLogUseCase
+}
+
+// NewLoginLogService 创建登录日志服务
+func NewLoginLogService(loginLogUseCase biz.LoginLogUseCase) *LoginLogService {
+	return &LoginLogService{
+		loginLogUseCase: loginLogUseCase,
+	}
+}
+
+// ListMine 查询当前用户的登录记录
+// @Summary 获取最近登录记录
+// @Description 获取当前用户最近的登录记录（含失败的尝试），包括 IP、设备和所在地，new_location 标记首次出现的所在地
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=[]dto.LoginLogInfo} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /auth/login-logs [get]
+func (s *LoginLogService) ListMine(c *gin.Context) {
+	req := dto.PageRequest{Page: 1, Limit: 20}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.loginLogUseCase.ListMine(c.GetUint("user_id"), &req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// List 查询登录日志
+// @Summary 获取登录日志
+// @Description 按用户或登录结果查询全部登录尝试，用户名不存在的尝试 user_id 为 0
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Param user_id query int false "用户ID"
+// @Param username query string false "用户名"
+// @Param success query bool false "是否登录成功"
+// @Success 200 {object} response.Response{data=[]dto.LoginLogInfo} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /login-logs [get]
+func (s *LoginLogService) List(c *gin.Context) {
+	req := dto.LoginLogListRequest{
+		PageRequest: dto.PageRequest{Page: 1, Limit: 20},
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.loginLogUseCase.List(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
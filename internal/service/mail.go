@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// MailService 邮件服务
+type MailService struct {
+	mailUseCase biz.MailUseCase
+}
+
+// NewMailService 创建邮件服务
+func NewMailService(mailUseCase biz.MailUseCase) *MailService {
+	return &MailService{
+		mailUseCase: mailUseCase,
+	}
+}
+
+// SendTest 发送测试邮件
+// @Summary 发送测试邮件
+// @Description 使用示例数据渲染邮件模板并立即发送，用于检查 SMTP 配置和模板效果；template 可选 test、verify_email、password_reset、comment_reply、newsletter、login_alert
+// @Tags 系统设置
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.MailTestRequest true "收件人和模板"
+// @Success 200 {object} response.Response "发送成功"
+// @Failure 400 {object} response.Response "请求参数错误或未配置邮件服务器"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "发送失败"
+// @Router /mail/test [post]
+func (s *MailService) SendTest(c *gin.Context) {
+	var req dto.MailTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.mailUseCase.SendTest(&req); err != nil {
+		switch {
+		case errors.Is(err, biz.ErrMailDisabled), errors.Is(err, biz.ErrMailTemplateInvalid):
+			response.BadRequest(c, err.Error())
+		default:
+			response.ServerError(c, err.Error())
+		}
+		return
+	}
+
+	response.Success(c, nil)
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// MediaService 媒体库服务
+type MediaService struct {
+	mediaUseCase biz.MediaUseCase
+}
+
+// NewMediaService 创建媒体库服务
+func NewMediaService(mediaUseCase biz.MediaUseCase) *MediaService {
+	return &MediaService{
+		mediaUseCase: mediaUseCase,
+	}
+}
+
+// List 媒体库图片列表
+// @Summary 获取媒体库图片
+// @Description 分页获取已上传的图片，包含缩略图、上传时间和被文章引用的次数，供编辑器复用已有图片
+// @Tags 文件管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(20)
+// @Param keyword query string false "文件名关键词"
+// @Param folder query string false "上传目录"
+// @Success 200 {object} response.Response{data=[]dto.MediaItem} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /media [get]
+func (s *MediaService) List(c *gin.Context) {
+	req := dto.MediaListRequest{
+		PageRequest: dto.PageRequest{Page: 1, Limit: 20},
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.mediaUseCase.List(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
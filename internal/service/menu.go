@@ -0,0 +1,206 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// MenuService 导航菜单服务
+type MenuService struct {
+	menuUseCase biz.MenuUseCase
+}
+
+// NewMenuService 创建导航菜单服务
+func NewMenuService(menuUseCase biz.MenuUseCase) *MenuService {
+	return &MenuService{
+		menuUseCase: menuUseCase,
+	}
+}
+
+// PublicTree 前台导航菜单
+// @Summary 获取导航菜单
+// @Description 获取导航菜单树，链接已解析为前台地址；隐藏的菜单项连同子菜单不返回，链接的分类、标签已删除或页面未发布的菜单项不返回
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param location query string false "菜单位置：header（默认）、footer"
+// @Success 200 {object} response.Response{data=[]dto.MenuItemResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/menus [get]
+func (s *MenuService) PublicTree(c *gin.Context) {
+	var req dto.MenuListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	menus, err := s.menuUseCase.PublicTree(req.Location)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, menus)
+}
+
+// Tree 后台菜单树
+// @Summary 获取菜单树
+// @Description 获取某个位置的完整菜单树，包含隐藏的菜单项；链接目标已删除或未发布的菜单项标记为 broken
+// @Tags 菜单管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param location query string false "菜单位置：header（默认）、footer"
+// @Success 200 {object} response.Response{data=[]dto.MenuItemResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /menus [get]
+func (s *MenuService) Tree(c *gin.Context) {
+	var req dto.MenuListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	menus, err := s.menuUseCase.Tree(req.Location)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, menus)
+}
+
+// Create 创建菜单项
+// @Summary 创建菜单项
+// @Description 创建菜单项，可链接到分类、标签、独立页面或自定义地址；菜单最多 3 级
+// @Tags 菜单管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.MenuItemRequest true "菜单项信息"
+// @Success 200 {object} response.Response{data=dto.MenuItemResponse} "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /menus [post]
+func (s *MenuService) Create(c *gin.Context) {
+	var req dto.MenuItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	item, err := s.menuUseCase.Create(&req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, item)
+}
+
+// Update 更新菜单项
+// @Summary 更新菜单项
+// @Description 更新菜单项，位置不可修改；显示状态和排序值为空时不修改
+// @Tags 菜单管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "菜单项ID"
+// @Param request body dto.MenuItemRequest true "菜单项信息"
+// @Success 200 {object} response.Response{data=dto.MenuItemResponse} "更新成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "菜单项不存在"
+// @Router /menus/{id} [put]
+func (s *MenuService) Update(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.MenuItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	item, err := s.menuUseCase.Update(idReq.ID, &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, item)
+}
+
+// Delete 删除菜单项
+// @Summary 删除菜单项
+// @Description 删除菜单项及其全部子菜单
+// @Tags 菜单管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "菜单项ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "菜单项不存在"
+// @Router /menus/{id} [delete]
+func (s *MenuService) Delete(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.menuUseCase.Delete(idReq.ID); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Sort 调整菜单层级和顺序
+// @Summary 调整菜单顺序
+// @Description 拖拽排序后批量保存菜单项的上级和排序值，调整后的菜单最多 3 级且不能形成环
+// @Tags 菜单管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.MenuSortRequest true "菜单项的新位置"
+// @Success 200 {object} response.Response "保存成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /menus/sort [put]
+func (s *MenuService) Sort(c *gin.Context) {
+	var req dto.MenuSortRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.menuUseCase.Sort(&req); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// handleError 将业务错误映射为响应
+func (s *MenuService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrMenuNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrMenuInvalid):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
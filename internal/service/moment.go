@@ -0,0 +1,267 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// MomentService 说说服务
+type MomentService struct {
+	momentUseCase biz.MomentUseCase
+}
+
+// NewMomentService 创建说说服务
+func NewMomentService(momentUseCase biz.MomentUseCase) *MomentService {
+	return &MomentService{
+		momentUseCase: momentUseCase,
+	}
+}
+
+// Timeline 前台说说列表
+// @Summary 获取说说列表
+// @Description 分页获取说说，按发布时间倒序；登录用户返回是否已点赞
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Success 200 {object} response.Response{data=[]dto.MomentResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/moments [get]
+func (s *MomentService) Timeline(c *gin.Context) {
+	req := dto.PageRequest{Page: 1, Limit: 10}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.momentUseCase.Timeline(c.GetUint("user_id"), req.Page, req.Limit)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// Get 前台说说详情
+// @Summary 获取说说详情
+// @Description 获取单条说说；登录用户返回是否已点赞
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param id path int true "说说ID"
+// @Success 200 {object} response.Response{data=dto.MomentResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "说说不存在"
+// @Router /blog/moments/{id} [get]
+func (s *MomentService) Get(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.momentUseCase.Get(idReq.ID, c.GetUint("user_id"))
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Like 点赞说说
+// @Summary 点赞说说
+// @Description 用户点赞说说，重复点赞不报错，返回最新点赞数
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "说说ID"
+// @Success 200 {object} response.Response{data=dto.MomentLikeResponse} "点赞成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "说说不存在"
+// @Router /blog/moments/{id}/like [post]
+func (s *MomentService) Like(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.momentUseCase.Like(idReq.ID, c.GetUint("user_id"))
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Unlike 取消点赞说说
+// @Summary 取消点赞说说
+// @Description 用户取消点赞说说，未点赞时不报错，返回最新点赞数
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "说说ID"
+// @Success 200 {object} response.Response{data=dto.MomentLikeResponse} "取消成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "说说不存在"
+// @Router /blog/moments/{id}/like [delete]
+func (s *MomentService) Unlike(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.momentUseCase.Unlike(idReq.ID, c.GetUint("user_id"))
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// List 后台说说列表
+// @Summary 获取说说列表
+// @Description 分页获取说说，可按内容或位置搜索
+// @Tags 说说管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Param keyword query string false "按内容或位置搜索"
+// @Success 200 {object} response.Response{data=[]dto.MomentResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /moments [get]
+func (s *MomentService) List(c *gin.Context) {
+	req := dto.MomentListRequest{PageRequest: dto.PageRequest{Page: 1, Limit: 10}}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.momentUseCase.List(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// Create 发布说说
+// @Summary 发布说说
+// @Description 发布说说，图片先通过 /files/upload 上传（建议 folder=moments），再把返回的地址放入 images，最多 9 张
+// @Tags 说说管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.MomentRequest true "说说内容"
+// @Success 200 {object} response.Response{data=dto.MomentResponse} "发布成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /moments [post]
+func (s *MomentService) Create(c *gin.Context) {
+	var req dto.MomentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.momentUseCase.Create(&req, c.GetUint("admin_id"))
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Update 编辑说说
+// @Summary 编辑说说
+// @Description 编辑说说的内容、图片和位置，点赞数和发布时间不变
+// @Tags 说说管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "说说ID"
+// @Param request body dto.MomentRequest true "说说内容"
+// @Success 200 {object} response.Response{data=dto.MomentResponse} "更新成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "说说不存在"
+// @Router /moments/{id} [put]
+func (s *MomentService) Update(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.MomentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.momentUseCase.Update(idReq.ID, &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Delete 删除说说
+// @Summary 删除说说
+// @Description 删除说说及其点赞记录，不再被引用的图片由未引用图片清理回收
+// @Tags 说说管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "说说ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "说说不存在"
+// @Router /moments/{id} [delete]
+func (s *MomentService) Delete(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.momentUseCase.Delete(idReq.ID); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// handleError 将业务错误映射为响应
+func (s *MomentService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrMomentNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrMomentInvalid):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
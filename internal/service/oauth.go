@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/pkg/oauth"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// oauthStateCookie 保存 state 的 cookie，回调时与 URL 中的 state 比对，防止登录 CSRF
+const oauthStateCookie = "oauth_state"
+
+// OAuthService 第三方登录服务
+type OAuthService struct {
+	oauthUseCase biz.OAuthUseCase
+}
+
+// NewOAuthService 创建第三方登录服务
+func NewOAuthService(oauthUseCase biz.OAuthUseCase) *OAuthService {
+	return &OAuthService{
+		oauthUseCase: oauthUseCase,
+	}
+}
+
+// Providers 已启用的第三方登录方式
+// @Summary 第三方登录方式
+// @Description 获取已启用的第三方登录方式，前台据此展示登录按钮
+// @Tags 认证管理
+// @Produce json
+// @Success 200 {object} response.Response{data=[]string} "获取成功"
+// @Router /auth/oauth [get]
+func (s *OAuthService) Providers(c *gin.Context) {
+	providers := s.oauthUseCase.Providers()
+	if providers == nil {
+		providers = []string{}
+	}
+	response.Success(c, providers)
+}
+
+// Authorize 跳转到第三方授权页面
+// @Summary 第三方登录
+// @Description 跳转到第三方平台的授权页面，授权完成后回调 /auth/oauth/{provider}/callback
+// @Tags 认证管理
+// @Param provider path string true "登录方式：github, google, wechat（扫码）, wechat_mp（微信内网页授权）, qq"
+// @Success 302 "跳转到授权页面"
+// @Failure 404 {object} response.Response "登录方式未启用"
+// @Router /auth/oauth/{provider} [get]
+func (s *OAuthService) Authorize(c *gin.Context) {
+	redirectURI := oauthCallbackURL(c, strings.TrimRight(c.Request.URL.Path, "/")+"/callback")
+
+	authURL, state, err := s.oauthUseCase.AuthURL(c.Param("provider"), redirectURI)
+	if err != nil {
+		if errors.Is(err, oauth.ErrUnknownProvider) {
+			response.NotFound(c, "登录方式未启用")
+			return
+		}
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, state, 600, "/", "", strings.HasPrefix(redirectURI, "https://"), true)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback 第三方授权回调
+// @Summary 第三方登录回调
+// @Description 使用授权码完成登录后跳转到前台页面，令牌放在 URL 片段中：#token=...&refresh_token=...&expires_in=...；
+// @Description 失败时为 #error=...。未绑定的第三方账号按已验证的邮箱关联已有账号，没有同邮箱账号时创建新账号
+// @Tags 认证管理
+// @Param provider path string true "登录方式：github, google, wechat（扫码）, wechat_mp（微信内网页授权）, qq"
+// @Param code query string true "授权码"
+// @Param state query string true "登录请求标识"
+// @Success 302 "跳转到前台页面"
+// @Router /auth/oauth/{provider}/callback [get]
+func (s *OAuthService) Callback(c *gin.Context) {
+	state := c.Query("state")
+	cookie, _ := c.Cookie(oauthStateCookie)
+	redirectURI := oauthCallbackURL(c, c.Request.URL.Path)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, "", -1, "/", "", strings.HasPrefix(redirectURI, "https://"), true)
+
+	if c.Query("error") != "" {
+		oauthRedirect(c, url.Values{"error": {"已取消授权"}})
+		return
+	}
+	if state == "" || cookie != state {
+		oauthRedirect(c, url.Values{"error": {biz.ErrOAuthStateInvalid.Error()}})
+		return
+	}
+
+	resp, err := s.oauthUseCase.Login(c.Request.Context(), c.Param("provider"), c.Query("code"), state, redirectURI, clientInfo(c))
+	if err != nil {
+		oauthRedirect(c, url.Values{"error": {err.Error()}})
+		return
+	}
+
+	fragment := url.Values{"token": {resp.Token}}
+	if resp.RefreshToken != "" {
+		fragment.Set("refresh_token", resp.RefreshToken)
+	}
+	if resp.ExpiresIn > 0 {
+		fragment.Set("expires_in", strconv.FormatInt(resp.ExpiresIn, 10))
+	}
+	oauthRedirect(c, fragment)
+}
+
+// oauthCallbackURL 回调地址，配置了 oauth.callback_base 时使用配置的公网地址，否则使用请求的地址
+func oauthCallbackURL(c *gin.Context, path string) string {
+	if base := config.AppConfig.OAuth.CallbackBase; base != "" {
+		return strings.TrimRight(base, "/") + path
+	}
+	current := requestURL(c)
+	return strings.TrimSuffix(current, c.Request.URL.Path) + path
+}
+
+// oauthRedirect 跳转到前台登录结果页面，参数放在 URL 片段中，不会出现在服务器日志和 Referer 中
+func oauthRedirect(c *gin.Context, fragment url.Values) {
+	target := config.AppConfig.OAuth.FrontendURL
+	if target == "" {
+		target = strings.TrimRight(config.AppConfig.Sitemap.SiteURL, "/") + "/oauth/callback"
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, target+"#"+fragment.Encode())
+}
@@ -2,25 +2,15 @@ package service
 
 import (
 	"encoding/json"
-	"fmt"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ydcloud-dy/leaf-api/internal/data"
 	"github.com/ydcloud-dy/leaf-api/internal/model/po"
-	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+	"github.com/ydcloud-dy/leaf-api/pkg/presence"
 	"github.com/ydcloud-dy/leaf-api/pkg/response"
 )
 
-const (
-	// 在线用户 Redis Key 前缀
-	onlineUserPrefix = "online:user:"
-	// 在线游客 Redis Key 前缀
-	onlineGuestPrefix = "online:guest:"
-	// 在线用户过期时间（60秒）
-	onlineUserExpire = 60 * time.Second
-)
-
 // OnlineService 在线用户追踪服务
 type OnlineService struct {
 	data *data.Data
@@ -35,74 +25,70 @@ func NewOnlineService(d *data.Data) *OnlineService {
 
 // RecordHeartbeat 记录用户心跳（保持在线状态）
 // @Summary 记录用户心跳
-// @Description 记录用户在线状态，登录用户按UserID追踪，未登录按IP追踪
+// @Description 不支持 WebSocket 时的在线状态回退方案，每 presence_interval 秒调用一次，超过 presence_timeout 秒未调用视为离线；登录用户按UserID追踪，未登录按IP追踪
 // @Tags 在线追踪
 // @Accept json
 // @Produce json
-// @Param request body object{path=string} false "当前页面路径"
+// @Param request body object{path=string,article_id=int} false "当前页面路径和正在阅读的文章ID"
 // @Success 200 {object} response.Response "记录成功"
 // @Failure 500 {object} response.Response "服务器错误"
 // @Router /blog/heartbeat [post]
 func (s *OnlineService) RecordHeartbeat(c *gin.Context) {
 	// 解析请求参数
 	var req struct {
-		Path string `json:"path"`
+		Path      string `json:"path"`
+		ArticleID uint   `json:"article_id"`
 	}
 	c.ShouldBindJSON(&req)
 
-	// 获取用户ID（如果已登录）
-	userIDValue, exists := c.Get("user_id")
-	ip := c.ClientIP()
-
-	var key string
-	var userID uint = 0
-	if exists && userIDValue != nil {
-		userID = userIDValue.(uint)
-		key = fmt.Sprintf("%s%d", onlineUserPrefix, userID)
-	} else {
-		// 未登录用户，使用 IP 作为标识
-		key = fmt.Sprintf("%s%s", onlineGuestPrefix, ip)
-	}
-
-	// 使用 Redis Hash 存储详细信息
-	client := redis.GetClient()
-	ctx := redis.GetContext()
+	conn := heartbeatConn(c)
+	conn.Path = req.Path
+	conn.ArticleID = req.ArticleID
+	conn.UserAgent = c.GetHeader("User-Agent")
 
-	// 存储在线信息
-	fields := map[string]interface{}{
-		"last_active_at": time.Now().Unix(),
-		"ip":             ip,
-		"path":           req.Path,
-		"user_agent":     c.GetHeader("User-Agent"),
+	if err := presence.Touch(conn, presenceTimeout()); err != nil {
+		response.Error(c, 500, "记录在线状态失败: "+err.Error())
+		return
 	}
 
-	err := client.HSet(ctx, key, fields).Err()
-	if err != nil {
+	response.Success(c, gin.H{"status": "ok"})
+}
+
+// LeaveHeartbeat 心跳回退方案下离开页面
+// @Summary 离开页面
+// @Description 使用心跳回退方案时，页面关闭前通过 navigator.sendBeacon 调用，立即离线
+// @Tags 在线追踪
+// @Produce json
+// @Success 200 {object} response.Response "记录成功"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/heartbeat/leave [post]
+func (s *OnlineService) LeaveHeartbeat(c *gin.Context) {
+	if err := presence.Leave(heartbeatConn(c)); err != nil {
 		response.Error(c, 500, "记录在线状态失败: "+err.Error())
 		return
 	}
 
-	// 设置过期时间
-	client.Expire(ctx, key, onlineUserExpire)
-
 	response.Success(c, gin.H{"status": "ok"})
 }
 
-// GetOnlineCount 获取在线人数
-func (s *OnlineService) GetOnlineCount() (int64, error) {
-	// 查询所有在线用户键
-	userKeys, err := redis.Keys(onlineUserPrefix + "*")
-	if err != nil {
-		return 0, err
+// heartbeatConn 心跳回退方案的连接，同一访客的多个页面共用一个连接
+func heartbeatConn(c *gin.Context) *presence.Conn {
+	conn := &presence.Conn{
+		UserID:    c.GetUint("user_id"),
+		IP:        c.ClientIP(),
+		Transport: presence.TransportHeartbeat,
 	}
+	conn.ID = "heartbeat:" + conn.Visitor()
+	return conn
+}
 
-	// 查询所有在线游客键
-	guestKeys, err := redis.Keys(onlineGuestPrefix + "*")
+// GetOnlineCount 获取在线人数，按访客去重
+func (s *OnlineService) GetOnlineCount() (int64, error) {
+	users, guests, err := presence.Stats()
 	if err != nil {
 		return 0, err
 	}
-
-	return int64(len(userKeys) + len(guestKeys)), nil
+	return int64(users + guests), nil
 }
 
 // VisitService 页面访问时长记录服务
@@ -144,7 +130,7 @@ func (s *VisitService) RecordVisitDuration(c *gin.Context) {
 			if len(bodyBytes) > 0 {
 				// 尝试解析为 JSON
 				if err := json.Unmarshal(bodyBytes, &req); err != nil {
-					response.Error(c, 400, "参数错误: "+err.Error())
+					response.BindError(c, err)
 					return
 				}
 			}
@@ -152,7 +138,7 @@ func (s *VisitService) RecordVisitDuration(c *gin.Context) {
 	} else {
 		// 标准 JSON 请求
 		if err := c.ShouldBindJSON(&req); err != nil {
-			response.Error(c, 400, "参数错误: "+err.Error())
+			response.BindError(c, err)
 			return
 		}
 	}
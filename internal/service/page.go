@@ -0,0 +1,226 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// PageService 独立页面服务
+type PageService struct {
+	pageUseCase biz.PageUseCase
+}
+
+// NewPageService 创建独立页面服务
+func NewPageService(pageUseCase biz.PageUseCase) *PageService {
+	return &PageService{
+		pageUseCase: pageUseCase,
+	}
+}
+
+// ListPublished 前台页面列表
+// @Summary 获取独立页面列表
+// @Description 获取全部已发布的独立页面（不含正文），按排序值排列，用于导航和页脚
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.StaticPageSummary} "获取成功"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/pages [get]
+func (s *PageService) ListPublished(c *gin.Context) {
+	pages, err := s.pageUseCase.ListPublished()
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, pages)
+}
+
+// GetPublished 前台页面详情
+// @Summary 获取独立页面
+// @Description 根据 slug 获取已发布的独立页面，包含渲染后的 HTML 和目录；草稿返回 404
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param slug path string true "页面 slug，如 about"
+// @Success 200 {object} response.Response{data=dto.StaticPageResponse} "获取成功"
+// @Failure 404 {object} response.Response "页面不存在"
+// @Router /blog/pages/{slug} [get]
+func (s *PageService) GetPublished(c *gin.Context) {
+	page, err := s.pageUseCase.GetPublished(c.Param("slug"))
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, page)
+}
+
+// List 后台页面列表
+// @Summary 获取页面列表
+// @Description 分页获取独立页面（不含正文），包含草稿
+// @Tags 页面管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Param status query int false "状态 0:草稿 1:已发布"
+// @Param keyword query string false "按标题或 slug 搜索"
+// @Success 200 {object} response.Response{data=[]dto.StaticPageSummary} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /pages [get]
+func (s *PageService) List(c *gin.Context) {
+	req := dto.StaticPageListRequest{PageRequest: dto.PageRequest{Page: 1, Limit: 10}}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.pageUseCase.List(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// Get 后台页面详情
+// @Summary 获取页面详情
+// @Description 获取独立页面详情，包含 Markdown 原文
+// @Tags 页面管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "页面ID"
+// @Success 200 {object} response.Response{data=dto.StaticPageResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "页面不存在"
+// @Router /pages/{id} [get]
+func (s *PageService) Get(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	page, err := s.pageUseCase.Get(idReq.ID)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, page)
+}
+
+// Create 创建页面
+// @Summary 创建页面
+// @Description 创建独立页面，Markdown 中的外部图片会下载到本地存储，渲染后的 HTML 与文章一样经过清理；未指定状态时保存为草稿
+// @Tags 页面管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.StaticPageRequest true "页面信息"
+// @Success 200 {object} response.Response{data=dto.StaticPageResponse} "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 409 {object} response.Response "slug 已被使用"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /pages [post]
+func (s *PageService) Create(c *gin.Context) {
+	var req dto.StaticPageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	page, err := s.pageUseCase.Create(&req, c.GetUint("admin_id"))
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, page)
+}
+
+// Update 更新页面
+// @Summary 更新页面
+// @Description 更新独立页面；slug 为空时保留原值，状态和排序值为空时不修改
+// @Tags 页面管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "页面ID"
+// @Param request body dto.StaticPageRequest true "页面信息"
+// @Success 200 {object} response.Response{data=dto.StaticPageResponse} "更新成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "页面不存在"
+// @Failure 409 {object} response.Response "slug 已被使用"
+// @Router /pages/{id} [put]
+func (s *PageService) Update(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.StaticPageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	page, err := s.pageUseCase.Update(idReq.ID, &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, page)
+}
+
+// Delete 删除页面
+// @Summary 删除页面
+// @Description 删除独立页面
+// @Tags 页面管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "页面ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "页面不存在"
+// @Router /pages/{id} [delete]
+func (s *PageService) Delete(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.pageUseCase.Delete(idReq.ID); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// handleError 将业务错误映射为响应
+func (s *PageService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrPageNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrPageSlugExists):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, biz.ErrPageInvalid):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
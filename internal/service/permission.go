@@ -0,0 +1,164 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// PermissionService 路由权限服务
+type PermissionService struct {
+	permissionUseCase biz.PermissionUseCase
+}
+
+// NewPermissionService 创建路由权限服务
+func NewPermissionService(permissionUseCase biz.PermissionUseCase) *PermissionService {
+	return &PermissionService{
+		permissionUseCase: permissionUseCase,
+	}
+}
+
+// Allowed 判断角色是否可以访问该路由（供权限中间件使用）
+func (s *PermissionService) Allowed(method, path, role string) bool {
+	return s.permissionUseCase.Allowed(method, path, role)
+}
+
+// List 查询路由权限规则
+// @Summary 获取路由权限规则
+// @Description 获取所有路由权限规则（路由前缀 → 允许访问的角色）
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /permissions/routes [get]
+func (s *PermissionService) List(c *gin.Context) {
+	// 权限规则决定所有管理接口的访问控制，不受规则本身影响，始终只允许超级管理员
+	if c.GetString("role") != "super_admin" {
+		response.Forbidden(c, "只有超级管理员可以管理权限规则")
+		return
+	}
+
+	rules, err := s.permissionUseCase.List()
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, rules)
+}
+
+// Create 创建路由权限规则
+// @Summary 创建路由权限规则
+// @Description 为路由前缀配置允许访问的角色，修改后立即生效
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.RoutePermissionRequest true "规则信息"
+// @Success 200 {object} response.Response "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /permissions/routes [post]
+func (s *PermissionService) Create(c *gin.Context) {
+	if c.GetString("role") != "super_admin" {
+		response.Forbidden(c, "只有超级管理员可以管理权限规则")
+		return
+	}
+
+	var req dto.RoutePermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	rule, err := s.permissionUseCase.Create(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, rule)
+}
+
+// Update 更新路由权限规则
+// @Summary 更新路由权限规则
+// @Description 根据ID更新路由权限规则，修改后立即生效
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "规则ID"
+// @Param request body dto.RoutePermissionRequest true "规则信息"
+// @Success 200 {object} response.Response "更新成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /permissions/routes/{id} [put]
+func (s *PermissionService) Update(c *gin.Context) {
+	if c.GetString("role") != "super_admin" {
+		response.Forbidden(c, "只有超级管理员可以管理权限规则")
+		return
+	}
+
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.RoutePermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	rule, err := s.permissionUseCase.Update(idReq.ID, &req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, rule)
+}
+
+// Delete 删除路由权限规则
+// @Summary 删除路由权限规则
+// @Description 根据ID删除路由权限规则，删除后该路由按上级路由前缀的规则控制，没有匹配规则时拒绝访问
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "规则ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /permissions/routes/{id} [delete]
+func (s *PermissionService) Delete(c *gin.Context) {
+	if c.GetString("role") != "super_admin" {
+		response.Forbidden(c, "只有超级管理员可以管理权限规则")
+		return
+	}
+
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.permissionUseCase.Delete(req.ID); err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
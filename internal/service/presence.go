@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/presence"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+	"golang.org/x/net/websocket"
+)
+
+// 在线状态默认值
+const (
+	defaultPresenceInterval = 30
+	defaultPresenceTimeout  = 90
+	presenceWriteTimeout    = 10 * time.Second
+)
+
+// presenceInterval 客户端 ping 和服务器推送阅读人数的间隔
+func presenceInterval() time.Duration {
+	interval := config.AppConfig.Realtime.PresenceInterval
+	if interval <= 0 {
+		interval = defaultPresenceInterval
+	}
+	return time.Duration(interval) * time.Second
+}
+
+// presenceTimeout 没有 ping 或心跳多久后视为离线
+func presenceTimeout() time.Duration {
+	timeout := config.AppConfig.Realtime.PresenceTimeout
+	if timeout <= 0 {
+		timeout = defaultPresenceTimeout
+	}
+	return time.Duration(timeout) * time.Second
+}
+
+// PresenceService 在线状态服务，WebSocket 连接期间视为在线，断开时立即离线
+type PresenceService struct {
+	realtimeUseCase biz.RealtimeUseCase
+}
+
+// NewPresenceService 创建在线状态服务
+func NewPresenceService(realtimeUseCase biz.RealtimeUseCase) *PresenceService {
+	return &PresenceService{
+		realtimeUseCase: realtimeUseCase,
+	}
+}
+
+// Connect 建立在线状态连接
+// @Summary 建立在线状态连接
+// @Description WebSocket 连接，连接期间计为在线，断开时立即离线。客户端每 presence_interval 秒发送 {"type":"ping"}，切换页面时发送 {"type":"page","path":"/x","article_id":1}；
+// @Description 服务器定期和切换页面后发送 {"type":"readers","article_id":1,"count":3}。超过 presence_timeout 秒未收到消息时服务器关闭连接。不支持 WebSocket 时使用 POST /blog/heartbeat 回退
+// @Tags 在线追踪
+// @Param path query string false "当前页面路径"
+// @Param article_id query int false "正在阅读的文章ID"
+// @Param ticket query string false "连接票据（POST /blog/events/ticket）"
+// @Success 101 {string} string "切换到 WebSocket 协议"
+// @Failure 401 {object} response.Response "连接票据无效"
+// @Failure 503 {object} response.Response "在线状态服务不可用"
+// @Router /blog/presence/ws [get]
+func (s *PresenceService) Connect(c *gin.Context) {
+	var req dto.PresenceConnectRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+	if redis.Client == nil {
+		response.Error(c, http.StatusServiceUnavailable, "在线状态服务不可用")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userID == 0 && req.Ticket != "" {
+		id, err := s.realtimeUseCase.RedeemTicket(req.Ticket)
+		if err != nil {
+			if errors.Is(err, biz.ErrRealtimeTicketInvalid) {
+				response.Unauthorized(c, err.Error())
+				return
+			}
+			response.ServerError(c, err.Error())
+			return
+		}
+		userID = id
+	}
+
+	conn := &presence.Conn{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		IP:        c.ClientIP(),
+		Path:      req.Path,
+		ArticleID: req.ArticleID,
+		UserAgent: c.Request.UserAgent(),
+		Transport: presence.TransportWebSocket,
+	}
+	// 前台与 API 通常不同源，与 CORS 设置一致不校验 Origin
+	server := websocket.Server{
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler:   func(ws *websocket.Conn) { s.serve(c.Request.Context(), ws, conn) },
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// serve 保持连接期间的在线状态，读取超时或连接断开后离线；日志带有建立连接时的请求ID
+func (s *PresenceService) serve(ctx context.Context, ws *websocket.Conn, conn *presence.Conn) {
+	defer ws.Close()
+	log := logger.FromContext(ctx)
+	timeout := presenceTimeout()
+
+	if err := presence.Touch(conn, timeout); err != nil {
+		log.Warn("Failed to record presence: ", err)
+		return
+	}
+	defer func() {
+		if err := presence.Leave(conn); err != nil {
+			log.Warn("Failed to remove presence: ", err)
+		}
+	}()
+
+	done := make(chan struct{})
+	defer close(done)
+	messages := make(chan dto.PresenceMessage)
+	go func() {
+		defer close(messages)
+		for {
+			var msg dto.PresenceMessage
+			_ = ws.SetReadDeadline(time.Now().Add(timeout))
+			if err := websocket.JSON.Receive(ws, &msg); err != nil {
+				return
+			}
+			select {
+			case messages <- msg:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	if !sendReaders(log, ws, conn) {
+		return
+	}
+	ticker := time.NewTicker(presenceInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			if msg.Type != dto.PresencePage {
+				continue
+			}
+			conn.Path = msg.Path
+			conn.ArticleID = msg.ArticleID
+		case <-ticker.C:
+		}
+
+		// 实例崩溃时连接无法主动离开，定期刷新过期时间，超时后自动离线
+		if err := presence.Touch(conn, timeout); err != nil {
+			log.Warn("Failed to record presence: ", err)
+		}
+		if !sendReaders(log, ws, conn) {
+			return
+		}
+	}
+}
+
+// sendReaders 发送当前文章的阅读人数，不在文章页面时不发送，写入失败返回 false
+func sendReaders(log *logrus.Entry, ws *websocket.Conn, conn *presence.Conn) bool {
+	if conn.ArticleID == 0 {
+		return true
+	}
+	count, err := presence.ArticleReaders(conn.ArticleID)
+	if err != nil {
+		log.Warn("Failed to count article readers: ", err)
+		return true
+	}
+	_ = ws.SetWriteDeadline(time.Now().Add(presenceWriteTimeout))
+	msg := &dto.PresenceMessage{Type: dto.PresenceReaders, ArticleID: conn.ArticleID, Count: count}
+	return websocket.JSON.Send(ws, msg) == nil
+}
+
+// ArticleReaders 正在阅读文章的人数
+// @Summary 正在阅读文章的人数
+// @Description 按访客去重统计当前打开文章页面的人数，包括 WebSocket 连接和心跳回退
+// @Tags 在线追踪
+// @Produce json
+// @Param id path int true "文章ID"
+// @Success 200 {object} response.Response{data=dto.ArticleReadersResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/articles/{id}/readers [get]
+func (s *PresenceService) ArticleReaders(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的文章ID")
+		return
+	}
+
+	count, err := presence.ArticleReaders(uint(id))
+	if err != nil {
+		response.ServerError(c, "获取阅读人数失败")
+		return
+	}
+
+	response.Success(c, &dto.ArticleReadersResponse{ArticleID: uint(id), Count: count})
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// PushService 浏览器推送服务
+type PushService struct {
+	pushUseCase biz.PushUseCase
+}
+
+// NewPushService 创建浏览器推送服务
+func NewPushService(pushUseCase biz.PushUseCase) *PushService {
+	return &PushService{
+		pushUseCase: pushUseCase,
+	}
+}
+
+// VAPIDKey 获取推送公钥
+// @Summary 获取推送公钥
+// @Description 返回浏览器调用 pushManager.subscribe 时使用的 applicationServerKey（base64url），enabled 为 false 时未启用浏览器推送
+// @Tags 浏览器推送
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.PushVAPIDKeyResponse} "获取成功"
+// @Router /blog/push/vapid-key [get]
+func (s *PushService) VAPIDKey(c *gin.Context) {
+	response.Success(c, s.pushUseCase.VAPIDKey())
+}
+
+// Subscribe 保存推送订阅
+// @Summary 保存推送订阅
+// @Description 提交浏览器 PushSubscription.toJSON() 的结果；同一推送地址重复提交时更新密钥，登录状态下关联当前用户。前台应在每次访问时重新提交，长期未更新的订阅会被清理
+// @Tags 浏览器推送
+// @Accept json
+// @Produce json
+// @Param request body dto.PushSubscribeRequest true "推送订阅"
+// @Success 200 {object} response.Response "订阅成功"
+// @Failure 400 {object} response.Response "订阅无效或未启用浏览器推送"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/push/subscriptions [post]
+func (s *PushService) Subscribe(c *gin.Context) {
+	var req dto.PushSubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var userID *uint
+	if id, exists := c.Get("user_id"); exists {
+		uid := id.(uint)
+		userID = &uid
+	}
+
+	if err := s.pushUseCase.Subscribe(userID, c.Request.UserAgent(), &req); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Unsubscribe 取消推送订阅
+// @Summary 取消推送订阅
+// @Description 按推送地址删除订阅，浏览器调用 subscription.unsubscribe() 后提交
+// @Tags 浏览器推送
+// @Accept json
+// @Produce json
+// @Param request body dto.PushUnsubscribeRequest true "推送地址"
+// @Success 200 {object} response.Response "取消成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/push/subscriptions [delete]
+func (s *PushService) Unsubscribe(c *gin.Context) {
+	var req dto.PushUnsubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.pushUseCase.Unsubscribe(&req); err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Send 手动推送
+// @Summary 手动推送
+// @Description 向全部订阅者推送一条通知，在后台发送；指定 article_id 时未填写的标题、正文和链接取自文章
+// @Tags 浏览器推送
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.PushSendRequest true "推送内容"
+// @Success 200 {object} response.Response{data=dto.PushSendResponse} "已开始推送"
+// @Failure 400 {object} response.Response "请求参数错误或未启用浏览器推送"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "文章不存在或未发布"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /push/send [post]
+func (s *PushService) Send(c *gin.Context) {
+	var req dto.PushSendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.pushUseCase.Send(c.Request.Context(), &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// handleError 将业务错误映射为响应
+func (s *PushService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrPushArticleNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrPushDisabled), errors.Is(err, biz.ErrPushSubscriptionInvalid),
+		errors.Is(err, biz.ErrPushMessageEmpty):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
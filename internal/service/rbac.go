@@ -0,0 +1,254 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// RBACService 角色权限服务
+type RBACService struct {
+	rbacUseCase biz.RBACUseCase
+}
+
+// NewRBACService 创建角色权限服务
+func NewRBACService(rbacUseCase biz.RBACUseCase) *RBACService {
+	return &RBACService{
+		rbacUseCase: rbacUseCase,
+	}
+}
+
+// HasPermission 判断角色是否拥有权限（供操作权限中间件使用）
+func (s *RBACService) HasPermission(role, permission string) bool {
+	return s.rbacUseCase.HasPermission(role, permission)
+}
+
+// roleNameURI 角色名称路径参数
+type roleNameURI struct {
+	Name string `uri:"name" binding:"required"`
+}
+
+// ListPermissions 查询权限点
+// @Summary 获取权限点
+// @Description 获取系统支持的全部权限点，授权时还可以使用 * 和 资源:* 通配
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Router /rbac/permissions [get]
+func (s *RBACService) ListPermissions(c *gin.Context) {
+	if !requireSuperAdmin(c) {
+		return
+	}
+
+	response.Success(c, s.rbacUseCase.ListPermissions())
+}
+
+// ListRoles 查询角色
+// @Summary 获取角色列表
+// @Description 获取所有角色及其权限
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /rbac/roles [get]
+func (s *RBACService) ListRoles(c *gin.Context) {
+	if !requireSuperAdmin(c) {
+		return
+	}
+
+	roles, err := s.rbacUseCase.ListRoles()
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, roles)
+}
+
+// CreateRole 创建角色
+// @Summary 创建角色
+// @Description 创建角色并设置权限，创建后可将角色分配给用户
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.RoleRequest true "角色信息"
+// @Success 200 {object} response.Response "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 409 {object} response.Response "角色已存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /rbac/roles [post]
+func (s *RBACService) CreateRole(c *gin.Context) {
+	if !requireSuperAdmin(c) {
+		return
+	}
+
+	var req dto.RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	role, err := s.rbacUseCase.CreateRole(&req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, role)
+}
+
+// UpdateRole 更新角色
+// @Summary 更新角色
+// @Description 更新角色信息和权限，修改后立即生效；不传 permissions 时保留原有权限
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "角色名称"
+// @Param request body dto.RoleRequest true "角色信息"
+// @Success 200 {object} response.Response "更新成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "角色不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /rbac/roles/{name} [put]
+func (s *RBACService) UpdateRole(c *gin.Context) {
+	if !requireSuperAdmin(c) {
+		return
+	}
+
+	var uri roleNameURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	role, err := s.rbacUseCase.UpdateRole(uri.Name, &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, role)
+}
+
+// DeleteRole 删除角色
+// @Summary 删除角色
+// @Description 删除角色，内置角色和仍有用户使用的角色不能删除
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "角色名称"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "角色不存在"
+// @Failure 409 {object} response.Response "角色仍在使用"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /rbac/roles/{name} [delete]
+func (s *RBACService) DeleteRole(c *gin.Context) {
+	if !requireSuperAdmin(c) {
+		return
+	}
+
+	var uri roleNameURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.rbacUseCase.DeleteRole(uri.Name); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// AssignRole 分配角色
+// @Summary 为用户分配角色
+// @Description 修改用户的角色，用户已登录的会话全部失效，重新登录后按新角色授权
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户ID"
+// @Param request body dto.AssignRoleRequest true "角色"
+// @Success 200 {object} response.Response "分配成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "角色不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /rbac/users/{id}/role [put]
+func (s *RBACService) AssignRole(c *gin.Context) {
+	if !requireSuperAdmin(c) {
+		return
+	}
+
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.rbacUseCase.AssignRole(idReq.ID, req.Role); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// requireSuperAdmin 角色和权限决定所有管理接口的访问控制，不受权限本身影响，始终只允许超级管理员
+func requireSuperAdmin(c *gin.Context) bool {
+	if c.GetString("role") != "super_admin" {
+		response.Forbidden(c, "只有超级管理员可以管理角色和权限")
+		return false
+	}
+	return true
+}
+
+// handleError 将角色权限业务错误映射为响应
+func (s *RBACService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrRoleNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrRoleExists), errors.Is(err, biz.ErrRoleInUse):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, biz.ErrRoleNameInvalid), errors.Is(err, biz.ErrPermissionInvalid),
+		errors.Is(err, biz.ErrRoleBuiltin), errors.Is(err, biz.ErrLastAdmin):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
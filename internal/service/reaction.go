@@ -0,0 +1,89 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// ReactionService 表情回应服务
+type ReactionService struct {
+	reactionUseCase biz.ReactionUseCase
+}
+
+// NewReactionService 创建表情回应服务
+func NewReactionService(reactionUseCase biz.ReactionUseCase) *ReactionService {
+	return &ReactionService{
+		reactionUseCase: reactionUseCase,
+	}
+}
+
+// ToggleArticle 切换文章表情回应
+// @Summary 切换文章表情回应
+// @Description 对文章添加或取消表情回应（👍 ❤️ 🎉 等），登录用户按用户去重，游客按 IP 去重，返回文章最新的回应统计
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param request body dto.ReactionRequest true "表情"
+// @Success 200 {object} response.Response{data=dto.ReactionToggleResponse} "切换成功"
+// @Failure 400 {object} response.Response "不支持的表情"
+// @Failure 404 {object} response.Response "文章不存在"
+// @Router /blog/articles/{id}/reactions [post]
+func (s *ReactionService) ToggleArticle(c *gin.Context) {
+	s.toggle(c, po.ReactionTargetArticle)
+}
+
+// ToggleComment 切换评论表情回应
+// @Summary 切换评论表情回应
+// @Description 对评论添加或取消表情回应（👍 ❤️ 🎉 等），登录用户按用户去重，游客按 IP 去重，返回评论最新的回应统计
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param id path int true "评论ID"
+// @Param request body dto.ReactionRequest true "表情"
+// @Success 200 {object} response.Response{data=dto.ReactionToggleResponse} "切换成功"
+// @Failure 400 {object} response.Response "不支持的表情"
+// @Failure 404 {object} response.Response "评论不存在"
+// @Router /blog/comments/{id}/reactions [post]
+func (s *ReactionService) ToggleComment(c *gin.Context) {
+	s.toggle(c, po.ReactionTargetComment)
+}
+
+func (s *ReactionService) toggle(c *gin.Context, targetType string) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.ReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.reactionUseCase.Toggle(targetType, uri.ID, c.GetUint("user_id"), clientInfo(c), &req)
+	if err != nil {
+		s.handleReactionError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// handleReactionError 将表情回应业务错误映射为响应状态
+func (s *ReactionService) handleReactionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrReactionTargetNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrReactionInvalid):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// ReadingProgressService 笔记阅读进度服务
+type ReadingProgressService struct {
+	readingProgressUseCase biz.ReadingProgressUseCase
+}
+
+// NewReadingProgressService 创建笔记阅读进度服务
+func NewReadingProgressService(readingProgressUseCase biz.ReadingProgressUseCase) *ReadingProgressService {
+	return &ReadingProgressService{
+		readingProgressUseCase: readingProgressUseCase,
+	}
+}
+
+// Update 上报文章阅读进度
+// @Summary 上报文章阅读进度
+// @Description 记录笔记文章的阅读位置，读到 100% 或传 completed=true 时标记为已读完；只支持属于笔记章节的已发布文章
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "文章ID"
+// @Param request body dto.UpdateReadingProgressRequest true "阅读进度"
+// @Success 200 {object} response.Response{data=dto.ArticleProgress} "保存成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "文章不存在或不属于任何笔记"
+// @Router /blog/progress/articles/{id} [put]
+func (s *ReadingProgressService) Update(c *gin.Context) {
+	var idReq dto.IDRequest
+	if err := c.ShouldBindUri(&idReq); err != nil {
+		response.BindError(c, err)
+		return
+	}
+	var req dto.UpdateReadingProgressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	progress, err := s.readingProgressUseCase.Update(c.GetUint("user_id"), idReq.ID, &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, progress)
+}
+
+// GetBook 笔记阅读进度
+// @Summary 获取笔记阅读进度
+// @Description 获取笔记标签下的完成百分比、读过的文章（用于目录中的已读标记）和继续阅读的文章
+// @Tags 博客前台
+// @Produce json
+// @Security BearerAuth
+// @Param tag path string true "标签名称"
+// @Success 200 {object} response.Response{data=dto.BookProgressResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "笔记不存在"
+// @Router /blog/progress/books/{tag} [get]
+func (s *ReadingProgressService) GetBook(c *gin.Context) {
+	book, err := s.readingProgressUseCase.GetBook(c.GetUint("user_id"), c.Param("tag"))
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, book)
+}
+
+// ListBooks 读过的笔记
+// @Summary 获取读过的笔记
+// @Description 获取当前用户读过的笔记及各自的完成进度，按最近阅读时间倒序
+// @Tags 博客前台
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]dto.BookProgressResponse} "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/progress/books [get]
+func (s *ReadingProgressService) ListBooks(c *gin.Context) {
+	books, err := s.readingProgressUseCase.ListBooks(c.GetUint("user_id"))
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, books)
+}
+
+// ResetBook 清除笔记阅读进度
+// @Summary 清除笔记阅读进度
+// @Description 清除当前用户在笔记标签下的全部阅读进度，用于重新阅读
+// @Tags 博客前台
+// @Produce json
+// @Security BearerAuth
+// @Param tag path string true "标签名称"
+// @Success 200 {object} response.Response "清除成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "笔记不存在"
+// @Router /blog/progress/books/{tag} [delete]
+func (s *ReadingProgressService) ResetBook(c *gin.Context) {
+	if err := s.readingProgressUseCase.ResetBook(c.GetUint("user_id"), c.Param("tag")); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// handleError 将阅读进度业务错误转换为响应
+func (s *ReadingProgressService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrBookNotFound), errors.Is(err, biz.ErrProgressArticleNotFound):
+		response.NotFound(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/realtime"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// 实时事件默认值
+const (
+	defaultRealtimeMaxConnections = 1000
+	defaultRealtimeHeartbeat      = 25
+	defaultRealtimeOnlineInterval = 15
+	// realtimeRetry 断线后浏览器重连的等待时间（毫秒）
+	realtimeRetry = 5000
+)
+
+// RealtimeService 实时事件服务（Server-Sent Events）
+type RealtimeService struct {
+	realtimeUseCase biz.RealtimeUseCase
+	onlineService   *OnlineService
+
+	onlineOnce  sync.Once
+	onlineCount atomic.Int64
+}
+
+// NewRealtimeService 创建实时事件服务
+func NewRealtimeService(realtimeUseCase biz.RealtimeUseCase, onlineService *OnlineService) *RealtimeService {
+	s := &RealtimeService{
+		realtimeUseCase: realtimeUseCase,
+		onlineService:   onlineService,
+	}
+	s.onlineCount.Store(-1)
+	return s
+}
+
+// IssueTicket 获取连接票据
+// @Summary 获取实时事件连接票据
+// @Description 浏览器原生 EventSource 和 WebSocket 无法设置 Authorization 请求头，登录用户先获取一次性票据，再以 /blog/events?ticket= 或 /blog/presence/ws?ticket= 建立连接；票据一分钟内有效
+// @Tags 实时事件
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=dto.RealtimeTicketResponse} "获取成功"
+// @Failure 400 {object} response.Response "服务器未启用连接票据"
+// @Failure 401 {object} response.Response "未授权"
+// @Router /blog/events/ticket [post]
+func (s *RealtimeService) IssueTicket(c *gin.Context) {
+	resp, err := s.realtimeUseCase.IssueTicket(c.GetUint("user_id"))
+	if err != nil {
+		if errors.Is(err, biz.ErrRealtimeTicketUnavailable) {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Stream 实时事件流
+// @Summary 实时事件流
+// @Description Server-Sent Events 长连接，事件类型：comment（当前文章或留言板的新评论）、notification（登录用户的新通知，用于更新未读角标）、online（在线人数，连接时和变化时发送）。
+// @Description 登录用户通过 Authorization 请求头或 ticket 参数识别，未登录只接收公开事件；每 heartbeat 秒发送一条注释保持连接
+// @Tags 实时事件
+// @Produce text/event-stream
+// @Param article_id query int false "正在查看的文章ID"
+// @Param guestbook query bool false "正在查看留言板"
+// @Param ticket query string false "连接票据"
+// @Success 200 {string} string "事件流"
+// @Failure 401 {object} response.Response "连接票据无效"
+// @Failure 404 {object} response.Response "文章不存在"
+// @Failure 503 {object} response.Response "连接数已达上限"
+// @Router /blog/events [get]
+func (s *RealtimeService) Stream(c *gin.Context) {
+	var req dto.RealtimeStreamRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	channels, err := s.realtimeUseCase.Channels(c.GetUint("user_id"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, biz.ErrRealtimeTicketInvalid):
+			response.Unauthorized(c, err.Error())
+		case errors.Is(err, biz.ErrRealtimeArticleNotFound):
+			response.NotFound(c, err.Error())
+		default:
+			response.ServerError(c, err.Error())
+		}
+		return
+	}
+
+	cfg := config.AppConfig.Realtime
+	maxConnections := cfg.MaxConnections
+	if maxConnections <= 0 {
+		maxConnections = defaultRealtimeMaxConnections
+	}
+	sub, err := realtime.Subscribe(maxConnections, channels...)
+	if err != nil {
+		response.Error(c, http.StatusServiceUnavailable, "实时连接数已达上限，请稍后重试")
+		return
+	}
+	defer sub.Close()
+	s.onlineOnce.Do(func() { go s.watchOnline() })
+
+	// 长连接不受服务器写超时限制
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", realtimeRetry)
+	if count := s.onlineCount.Load(); count >= 0 {
+		data, _ := json.Marshal(&dto.RealtimeOnline{Count: count})
+		writeEvent(c, realtime.Event{Type: realtime.EventOnline, Data: data})
+	}
+	c.Writer.Flush()
+
+	heartbeat := cfg.Heartbeat
+	if heartbeat <= 0 {
+		heartbeat = defaultRealtimeHeartbeat
+	}
+	ticker := time.NewTicker(time.Duration(heartbeat) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			writeEvent(c, event)
+		case <-ticker.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeEvent 按 SSE 格式写入一个事件，数据为单行 JSON
+func writeEvent(c *gin.Context, event realtime.Event) {
+	fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event.Type, event.Data)
+}
+
+// watchOnline 定期检查在线人数，变化时推送给本实例的连接
+// 在线人数保存在 Redis 中，各实例自行检查，不经 Redis 转发
+func (s *RealtimeService) watchOnline() {
+	interval := config.AppConfig.Realtime.OnlineInterval
+	if interval <= 0 {
+		interval = defaultRealtimeOnlineInterval
+	}
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		if redis.Client != nil && realtime.Count() > 0 {
+			count, err := s.onlineService.GetOnlineCount()
+			if err != nil {
+				logger.Warn("Failed to count online users: ", err)
+			} else if s.onlineCount.Swap(count) != count {
+				_ = realtime.Deliver(realtime.ChannelSite, realtime.EventOnline, &dto.RealtimeOnline{Count: count})
+			}
+		}
+		<-ticker.C
+	}
+}
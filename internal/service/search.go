@@ -0,0 +1,73 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// SearchService 全文搜索服务
+type SearchService struct {
+	searchUseCase biz.SearchUseCase
+}
+
+// NewSearchService 创建全文搜索服务
+func NewSearchService(searchUseCase biz.SearchUseCase) *SearchService {
+	return &SearchService{
+		searchUseCase: searchUseCase,
+	}
+}
+
+// Search 全文搜索
+// @Summary 全文搜索文章
+// @Description 在标题、摘要和正文中搜索已发布的文章，返回带 <em> 标签的高亮片段；未配置搜索引擎时退化为标题和摘要的模糊匹配
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param keyword query string true "搜索关键词"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Success 200 {object} response.Response{data=[]dto.SearchHit} "搜索成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Failure 429 {object} response.Response "请求过于频繁"
+// @Router /blog/search [get]
+func (s *SearchService) Search(c *gin.Context) {
+	req := dto.SearchRequest{
+		PageRequest: dto.PageRequest{Page: 1, Limit: 10},
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.searchUseCase.Search(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// Reindex 重建搜索索引
+// @Summary 重建搜索索引
+// @Description 将全部已发布文章重新写入搜索引擎，用于首次启用或切换搜索引擎后的全量同步
+// @Tags 搜索管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=dto.ReindexResponse} "重建成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /search/reindex [post]
+func (s *SearchService) Reindex(c *gin.Context) {
+	resp, err := s.searchUseCase.Reindex()
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// SeriesService 文章系列服务
+type SeriesService struct {
+	seriesUseCase biz.SeriesUseCase
+}
+
+// NewSeriesService 创建文章系列服务
+func NewSeriesService(seriesUseCase biz.SeriesUseCase) *SeriesService {
+	return &SeriesService{
+		seriesUseCase: seriesUseCase,
+	}
+}
+
+// List 系列列表
+// @Summary 获取系列列表
+// @Description 分页获取文章系列，文章数包含未发布的文章
+// @Tags 系列管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Param keyword query string false "搜索关键词"
+// @Success 200 {object} response.Response{data=[]dto.SeriesResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /series [get]
+func (s *SeriesService) List(c *gin.Context) {
+	s.list(c, false)
+}
+
+// ListPublic 博客前台系列列表
+// @Summary 获取博客系列列表
+// @Description 分页获取文章系列，文章数只统计已发布且非私密的文章
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Param keyword query string false "搜索关键词"
+// @Success 200 {object} response.Response{data=[]dto.SeriesResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/series [get]
+func (s *SeriesService) ListPublic(c *gin.Context) {
+	s.list(c, true)
+}
+
+// list 解析分页参数并查询系列列表
+func (s *SeriesService) list(c *gin.Context, public bool) {
+	req := dto.SeriesListRequest{
+		PageRequest: dto.PageRequest{Page: 1, Limit: 10},
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.seriesUseCase.List(&req, public)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// Get 系列详情
+// @Summary 获取系列详情
+// @Description 获取系列信息及按顺序排列的全部文章
+// @Tags 系列管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "系列ID"
+// @Success 200 {object} response.Response{data=dto.SeriesResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "系列不存在"
+// @Router /series/{id} [get]
+func (s *SeriesService) Get(c *gin.Context) {
+	s.get(c, false)
+}
+
+// GetPublic 博客前台系列详情
+// @Summary 获取博客系列详情
+// @Description 获取系列信息及已发布的文章目录
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param id path int true "系列ID"
+// @Success 200 {object} response.Response{data=dto.SeriesResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "系列不存在"
+// @Router /blog/series/{id} [get]
+func (s *SeriesService) GetPublic(c *gin.Context) {
+	s.get(c, true)
+}
+
+// get 查询系列详情
+func (s *SeriesService) get(c *gin.Context, public bool) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.seriesUseCase.Get(req.ID, public)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Create 创建系列
+// @Summary 创建系列
+// @Description 创建文章系列，可同时指定系列中的文章及顺序
+// @Tags 系列管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateSeriesRequest true "系列信息"
+// @Success 200 {object} response.Response{data=dto.SeriesResponse} "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /series [post]
+func (s *SeriesService) Create(c *gin.Context) {
+	var req dto.CreateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.seriesUseCase.Create(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Update 更新系列
+// @Summary 更新系列
+// @Description 更新系列信息，传入 article_ids 时同时替换系列中的文章及顺序
+// @Tags 系列管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "系列ID"
+// @Param request body dto.UpdateSeriesRequest true "系列信息"
+// @Success 200 {object} response.Response{data=dto.SeriesResponse} "更新成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /series/{id} [put]
+func (s *SeriesService) Update(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.UpdateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.seriesUseCase.Update(uri.ID, &req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Delete 删除系列
+// @Summary 删除系列
+// @Description 删除系列及其文章关联，文章本身不会被删除
+// @Tags 系列管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "系列ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /series/{id} [delete]
+func (s *SeriesService) Delete(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.seriesUseCase.Delete(req.ID); err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// SetArticles 设置系列文章及顺序
+// @Summary 设置系列文章
+// @Description 按数组顺序替换系列中的文章，传入空数组表示清空
+// @Tags 系列管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "系列ID"
+// @Param request body dto.SetSeriesArticlesRequest true "文章ID列表"
+// @Success 200 {object} response.Response{data=dto.SeriesResponse} "设置成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /series/{id}/articles [put]
+func (s *SeriesService) SetArticles(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.SetSeriesArticlesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.seriesUseCase.SetArticles(uri.ID, req.ArticleIDs)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Navigation 文章系列导航
+// @Summary 获取文章系列导航
+// @Description 返回文章所在各系列的目录、系列内上一篇和下一篇以及阅读进度
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Param id path int true "文章ID"
+// @Success 200 {object} response.Response{data=[]dto.SeriesNavigation} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "文章不存在"
+// @Router /blog/articles/{id}/series [get]
+func (s *SeriesService) Navigation(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	navs, err := s.seriesUseCase.Navigation(req.ID)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.Success(c, navs)
+}
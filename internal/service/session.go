@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// ListSessions 查询登录会话
+// @Summary 获取登录会话
+// @Description 获取当前用户在各设备上的登录会话（管理后台和博客前台通用），current 标记发起请求的会话
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]dto.SessionInfo} "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /auth/sessions [get]
+func (s *AuthService) ListSessions(c *gin.Context) {
+	sessions, err := s.authUseCase.ListSessions(c.GetUint("user_id"), c.GetString("session_id"))
+	if err != nil {
+		s.handleSessionError(c, err)
+		return
+	}
+
+	response.Success(c, sessions)
+}
+
+// RevokeSession 吊销登录会话
+// @Summary 吊销登录会话
+// @Description 让指定设备退出登录，该会话的刷新令牌和访问令牌立即失效
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "会话ID"
+// @Success 200 {object} response.Response "吊销成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 404 {object} response.Response "会话不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /auth/sessions/{id} [delete]
+func (s *AuthService) RevokeSession(c *gin.Context) {
+	var req dto.SessionIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.authUseCase.RevokeSession(c.GetUint("user_id"), req.ID); err != nil {
+		s.handleSessionError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// LogoutAll 退出所有设备
+// @Summary 退出所有设备
+// @Description 吊销当前用户的全部会话（包括当前会话），所有设备需要重新登录
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response "退出成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /auth/sessions [delete]
+func (s *AuthService) LogoutAll(c *gin.Context) {
+	if err := s.authUseCase.LogoutAll(c.GetUint("user_id")); err != nil {
+		s.handleSessionError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// bearerToken 读取 Authorization 请求头中的令牌
+func bearerToken(c *gin.Context) string {
+	scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !ok || scheme != "Bearer" {
+		return ""
+	}
+	return token
+}
+
+// handleSessionError 将会话业务错误映射为响应
+func (s *AuthService) handleSessionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrSessionNotFound):
+		response.NotFound(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
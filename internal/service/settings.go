@@ -1,27 +1,28 @@
 package service
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
-	"github.com/ydcloud-dy/leaf-api/internal/data"
-	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
 	"github.com/ydcloud-dy/leaf-api/pkg/response"
 )
 
 // SettingsService 设置服务
 type SettingsService struct {
-	data *data.Data
+	settingUseCase biz.SettingUseCase
 }
 
 // NewSettingsService 创建设置服务
-func NewSettingsService(d *data.Data) *SettingsService {
+func NewSettingsService(settingUseCase biz.SettingUseCase) *SettingsService {
 	return &SettingsService{
-		data: d,
+		settingUseCase: settingUseCase,
 	}
 }
 
 // Get 获取所有设置
 // @Summary 获取系统设置
-// @Description 获取所有系统配置项
+// @Description 获取所有系统配置项的当前值，未设置的内置设置项返回默认值；布尔和整数设置返回对应的 JSON 类型
 // @Tags 系统设置
 // @Accept json
 // @Produce json
@@ -31,60 +32,98 @@ func NewSettingsService(d *data.Data) *SettingsService {
 // @Failure 500 {object} response.Response "服务器错误"
 // @Router /settings [get]
 func (s *SettingsService) Get(c *gin.Context) {
-	settings, err := s.data.SettingRepo.List()
+	items, err := s.settingUseCase.List()
 	if err != nil {
 		response.ServerError(c, err.Error())
 		return
 	}
 
 	// 转换为 map 格式
-	settingsMap := make(map[string]string)
-	for _, setting := range settings {
-		settingsMap[setting.Key] = setting.Value
+	settingsMap := make(map[string]any, len(items))
+	for _, item := range items {
+		settingsMap[item.Key] = item.Value
 	}
 
 	response.Success(c, settingsMap)
 }
 
+// GetSchema 获取设置项定义
+// @Summary 获取设置项定义
+// @Description 获取全部设置项的类型、分组、取值范围、默认值和当前值，用于生成后台设置表单
+// @Tags 系统设置
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]dto.SettingItem} "获取成功"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /settings/schema [get]
+func (s *SettingsService) GetSchema(c *gin.Context) {
+	items, err := s.settingUseCase.List()
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, items)
+}
+
+// GetPublic 获取站点设置
+// @Summary 获取站点设置
+// @Description 博客前台获取公开的站点设置，如站点标题、备案号、页脚、评论和注册开关
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response "获取成功"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/settings [get]
+func (s *SettingsService) GetPublic(c *gin.Context) {
+	settings, err := s.settingUseCase.Public()
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.Success(c, settings)
+}
+
+// GetTheme 获取外观设置
+// @Summary 获取外观设置
+// @Description 博客前台启动时读取的外观设置：主题色、默认配色模式、首页横幅图片和字体，未设置的项返回默认值
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.ThemeResponse} "获取成功"
+// @Router /blog/theme [get]
+func (s *SettingsService) GetTheme(c *gin.Context) {
+	response.Success(c, s.settingUseCase.Theme())
+}
+
 // Update 更新设置
 // @Summary 更新系统设置
-// @Description 批量更新系统配置项
+// @Description 批量更新系统配置项，值按设置项类型校验，任意一项不合法时不做修改；值为 null 时恢复默认值
 // @Tags 系统设置
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param request body map[string]string true "配置项键值对"
+// @Param request body map[string]interface{} true "配置项键值对"
 // @Success 200 {object} response.Response "更新成功"
 // @Failure 400 {object} response.Response "请求参数错误"
 // @Failure 401 {object} response.Response "未授权"
 // @Failure 500 {object} response.Response "服务器错误"
 // @Router /settings [put]
 func (s *SettingsService) Update(c *gin.Context) {
-	var req map[string]string
+	var req map[string]any
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
-	// 批量更新设置
-	var settings []*po.Setting
-	for key, value := range req {
-		// 先查询是否存在
-		existing, err := s.data.SettingRepo.FindByKey(key)
-		if err != nil {
-			// 不存在则创建
-			settings = append(settings, &po.Setting{
-				Key:   key,
-				Value: value,
-			})
-		} else {
-			// 存在则更新
-			existing.Value = value
-			settings = append(settings, existing)
+	if err := s.settingUseCase.Update(req); err != nil {
+		if errors.Is(err, biz.ErrSettingInvalid) {
+			response.BadRequest(c, err.Error())
+			return
 		}
-	}
-
-	if err := s.data.SettingRepo.BatchUpdate(settings); err != nil {
 		response.ServerError(c, err.Error())
 		return
 	}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/pkg/sitemap"
+)
+
+// SitemapService 站点地图服务
+type SitemapService struct {
+	sitemapUseCase biz.SitemapUseCase
+}
+
+// NewSitemapService 创建站点地图服务
+func NewSitemapService(sitemapUseCase biz.SitemapUseCase) *SitemapService {
+	return &SitemapService{sitemapUseCase: sitemapUseCase}
+}
+
+// Index 站点地图入口
+// @Summary 站点地图
+// @Description 返回 sitemap.xml，包含已发布文章、分类、标签和章节；超过 50000 条时为索引文件，分片位于 /sitemaps/sitemap-N.xml
+// @Tags 博客前台
+// @Produce xml
+// @Success 200 {string} string "站点地图"
+// @Failure 404 {string} string "站点地图未启用"
+// @Router /sitemap.xml [get]
+func (s *SitemapService) Index(c *gin.Context) {
+	s.serve(c, sitemap.IndexFile)
+}
+
+// Shard 站点地图分片
+// @Summary 站点地图分片
+// @Description 站点地图超过 50000 条时的分片文件
+// @Tags 博客前台
+// @Produce xml
+// @Param file path string true "分片文件名，如 sitemap-1.xml"
+// @Success 200 {string} string "站点地图分片"
+// @Failure 404 {string} string "分片不存在"
+// @Router /sitemaps/{file} [get]
+func (s *SitemapService) Shard(c *gin.Context) {
+	s.serve(c, c.Param("file"))
+}
+
+// serve 输出站点地图文件
+func (s *SitemapService) serve(c *gin.Context, name string) {
+	content, err := s.sitemapUseCase.File(name)
+	if err != nil {
+		if errors.Is(err, biz.ErrSitemapDisabled) || errors.Is(err, biz.ErrSitemapNotFound) {
+			c.String(http.StatusNotFound, err.Error())
+			return
+		}
+		c.String(http.StatusInternalServerError, "生成站点地图失败")
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", content)
+}
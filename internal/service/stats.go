@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
 	"github.com/ydcloud-dy/leaf-api/internal/data"
 	"github.com/ydcloud-dy/leaf-api/internal/model/po"
 	"github.com/ydcloud-dy/leaf-api/pkg/response"
@@ -83,8 +84,7 @@ func (s *StatsService) GetStats(c *gin.Context) {
 	stats.AvgVisitDuration = avgDuration
 
 	// 计算网站运行天数（从 settings 表读取网站启动时间）
-	var setting po.Setting
-	err := s.data.GetDB().Where("`key` = ?", "site_start_date").First(&setting).Error
+	setting, err := s.data.SettingRepo.FindByKey(biz.SettingSiteStartDate)
 	if err == nil {
 		// 解析启动时间
 		if startTime, err := time.Parse("2006-01-02", setting.Value); err == nil {
@@ -108,7 +108,7 @@ func (s *StatsService) GetHotArticles(c *gin.Context) {
 	var articles []po.Article
 	s.data.GetDB().Model(&po.Article{}).
 		Preload("Category").
-		Where("status = ?", 1).
+		Where("status = ? AND visibility <> ?", 1, po.VisibilityPrivate).
 		Order("view_count DESC").
 		Limit(10).
 		Find(&articles)
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"fmt"
+	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -37,7 +39,7 @@ func NewUserService(userUseCase biz.UserUseCase) *UserService {
 func (s *UserService) Create(c *gin.Context) {
 	var req dto.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
@@ -67,13 +69,13 @@ func (s *UserService) Create(c *gin.Context) {
 func (s *UserService) Update(c *gin.Context) {
 	var idReq dto.IDRequest
 	if err := c.ShouldBindUri(&idReq); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
 	var req dto.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
@@ -102,7 +104,7 @@ func (s *UserService) Update(c *gin.Context) {
 func (s *UserService) Delete(c *gin.Context) {
 	var req dto.IDRequest
 	if err := c.ShouldBindUri(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
@@ -130,7 +132,7 @@ func (s *UserService) Delete(c *gin.Context) {
 func (s *UserService) GetByID(c *gin.Context) {
 	var req dto.IDRequest
 	if err := c.ShouldBindUri(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.BindError(c, err)
 		return
 	}
 
@@ -179,3 +181,44 @@ func (s *UserService) List(c *gin.Context) {
 
 	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
 }
+
+// ExportData 导出用户数据
+// @Summary 导出用户数据
+// @Description 导出系统中保存的该用户的全部数据（资料、评论及编辑记录、点赞、收藏、表情回应、浏览和访问记录）为 JSON 文件，用于处理数据可携带请求；只能导出自己的数据，超级管理员可以导出任意用户
+// @Tags 用户管理
+// @Produce application/json
+// @Security BearerAuth
+// @Param id path int true "用户ID"
+// @Success 200 {object} dto.UserDataExport "JSON 文件"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 403 {object} response.Response "无权限"
+// @Failure 404 {object} response.Response "用户不存在"
+// @Router /users/{id}/export [get]
+func (s *UserService) ExportData(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	// 导出内容包含浏览和访问记录等个人数据，只能导出自己的数据，超级管理员可以代用户导出
+	if req.ID != c.GetUint("admin_id") && c.GetString("role") != "super_admin" {
+		response.Forbidden(c, "只能导出自己的数据")
+		return
+	}
+
+	export, err := s.userUseCase.ExportData(req.ID)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	writeUserDataExport(c, export)
+}
+
+// writeUserDataExport 以附件形式返回用户数据导出
+func writeUserDataExport(c *gin.Context, export *dto.UserDataExport) {
+	filename := fmt.Sprintf("leaf-user-%d-%s.json", export.Profile.ID, export.ExportedAt.Format("20060102-150405"))
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.JSON(http.StatusOK, export)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// UserProfileService 用户主页服务
+type UserProfileService struct {
+	userProfileUseCase biz.UserProfileUseCase
+}
+
+// NewUserProfileService 创建用户主页服务
+func NewUserProfileService(userProfileUseCase biz.UserProfileUseCase) *UserProfileService {
+	return &UserProfileService{
+		userProfileUseCase: userProfileUseCase,
+	}
+}
+
+// GetPublic 用户公开主页
+// @Summary 获取用户主页
+// @Description 获取用户的公开资料、统计数据和已发布的文章（含共同署名，不含私密文章），已禁用或未验证邮箱的用户返回 404
+// @Tags 博客前台
+// @Produce json
+// @Param username path string true "用户名"
+// @Param page query int false "文章列表页码" default(1)
+// @Param limit query int false "文章列表每页数量" default(10)
+// @Success 200 {object} response.Response{data=dto.PublicProfileResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "用户不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/users/{username}/profile [get]
+func (s *UserProfileService) GetPublic(c *gin.Context) {
+	req := dto.PageRequest{Page: 1, Limit: 10}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	profile, err := s.userProfileUseCase.GetPublic(c.Param("username"), &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, profile)
+}
+
+// UpdateMine 更新主页资料
+// @Summary 更新主页资料
+// @Description 更新当前用户公开主页上的昵称、简介、个人网站和社交链接，未传的字段保持不变，链接必须是 http(s) 地址
+// @Tags 博客前台
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.UpdatePublicProfileRequest true "主页资料"
+// @Success 200 {object} response.Response{data=dto.PublicProfileResponse} "更新成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 401 {object} response.Response "未授权"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /blog/users/me/profile [put]
+func (s *UserProfileService) UpdateMine(c *gin.Context) {
+	var req dto.UpdatePublicProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	profile, err := s.userProfileUseCase.UpdateMine(c.GetUint("user_id"), &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, profile)
+}
+
+// handleError 将业务错误转换为响应
+func (s *UserProfileService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrProfileNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrProfileLinkInvalid):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+)
+
+// WebhookService Webhook 服务
+type WebhookService struct {
+	webhookUseCase biz.WebhookUseCase
+}
+
+// NewWebhookService 创建 Webhook 服务
+func NewWebhookService(webhookUseCase biz.WebhookUseCase) *WebhookService {
+	return &WebhookService{
+		webhookUseCase: webhookUseCase,
+	}
+}
+
+// List Webhook 列表
+// @Summary 获取 Webhook 列表
+// @Description 分页获取 Webhook，不返回签名密钥
+// @Tags Webhook管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Success 200 {object} response.Response{data=[]dto.WebhookResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /webhooks [get]
+func (s *WebhookService) List(c *gin.Context) {
+	req := dto.PageRequest{Page: 1, Limit: 10}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.webhookUseCase.List(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// Get Webhook 详情
+// @Summary 获取 Webhook 详情
+// @Description 获取 Webhook 配置，不返回签名密钥
+// @Tags Webhook管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} response.Response{data=dto.WebhookResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "Webhook 不存在"
+// @Router /webhooks/{id} [get]
+func (s *WebhookService) Get(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.webhookUseCase.Get(req.ID)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Create 创建 Webhook
+// @Summary 创建 Webhook
+// @Description 订阅内容事件：article.published、article.updated、article.deleted、comment.created。
+// @Description 投递为 POST JSON 请求，请求头 X-Leaf-Signature 为 sha256=hex(hmac_sha256(secret, X-Leaf-Timestamp + "." + body))。
+// @Description 未指定 secret 时自动生成，仅在本次响应中返回
+// @Tags Webhook管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateWebhookRequest true "Webhook 信息"
+// @Success 200 {object} response.Response{data=dto.WebhookResponse} "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /webhooks [post]
+func (s *WebhookService) Create(c *gin.Context) {
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.webhookUseCase.Create(&req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Update 更新 Webhook
+// @Summary 更新 Webhook
+// @Description 更新 Webhook 配置，secret 为空时保留原密钥
+// @Tags Webhook管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param request body dto.UpdateWebhookRequest true "Webhook 信息"
+// @Success 200 {object} response.Response{data=dto.WebhookResponse} "更新成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "Webhook 不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /webhooks/{id} [put]
+func (s *WebhookService) Update(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.webhookUseCase.Update(uri.ID, &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Delete 删除 Webhook
+// @Summary 删除 Webhook
+// @Description 删除 Webhook 及其投递记录
+// @Tags Webhook管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "Webhook 不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /webhooks/{id} [delete]
+func (s *WebhookService) Delete(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.webhookUseCase.Delete(req.ID); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Ping 测试 Webhook
+// @Summary 测试 Webhook
+// @Description 同步发送一次 ping 事件并返回投递结果，失败后不会自动重试
+// @Tags Webhook管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} response.Response{data=dto.WebhookDeliveryResponse} "投递结果"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "Webhook 不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /webhooks/{id}/ping [post]
+func (s *WebhookService) Ping(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.webhookUseCase.Ping(c.Request.Context(), req.ID)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// ListDeliveries 投递记录
+// @Summary 获取 Webhook 投递记录
+// @Description 按时间倒序分页获取投递记录，包含请求体、响应状态和重试信息
+// @Tags Webhook管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Param status query string false "投递状态：pending、success、failed"
+// @Success 200 {object} response.Response{data=[]dto.WebhookDeliveryResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "Webhook 不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /webhooks/{id}/deliveries [get]
+func (s *WebhookService) ListDeliveries(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	req := dto.WebhookDeliveryListRequest{
+		PageRequest: dto.PageRequest{Page: 1, Limit: 10},
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.webhookUseCase.ListDeliveries(uri.ID, &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// Redeliver 重新投递
+// @Summary 重新投递
+// @Description 使用原请求体同步重新投递一次，生成新的投递记录
+// @Tags Webhook管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param delivery_id path int true "投递记录ID"
+// @Success 200 {object} response.Response{data=dto.WebhookDeliveryResponse} "投递结果"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "投递记录不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (s *WebhookService) Redeliver(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	deliveryID, err := strconv.ParseUint(c.Param("delivery_id"), 10, 32)
+	if err != nil || deliveryID == 0 {
+		response.BadRequest(c, "无效的投递记录ID")
+		return
+	}
+
+	resp, err := s.webhookUseCase.Redeliver(c.Request.Context(), uri.ID, uint(deliveryID))
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// handleError 将业务错误映射为响应
+func (s *WebhookService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrWebhookNotFound), errors.Is(err, biz.ErrWebhookDeliveryNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrInvalidWebhookEvent):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
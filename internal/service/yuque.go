@@ -0,0 +1,217 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/leaf-api/internal/biz"
+	"github.com/ydcloud-dy/leaf-api/internal/model/dto"
+	"github.com/ydcloud-dy/leaf-api/pkg/response"
+	"github.com/ydcloud-dy/leaf-api/pkg/yuque"
+)
+
+// YuqueService 语雀同步服务
+type YuqueService struct {
+	yuqueUseCase biz.YuqueUseCase
+}
+
+// NewYuqueService 创建语雀同步服务
+func NewYuqueService(yuqueUseCase biz.YuqueUseCase) *YuqueService {
+	return &YuqueService{
+		yuqueUseCase: yuqueUseCase,
+	}
+}
+
+// List 语雀同步列表
+// @Summary 获取语雀同步列表
+// @Description 分页获取已配置同步的语雀知识库，不返回 Token
+// @Tags 语雀同步
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Success 200 {object} response.Response{data=[]dto.YuqueSyncResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /yuque/syncs [get]
+func (s *YuqueService) List(c *gin.Context) {
+	req := dto.PageRequest{Page: 1, Limit: 10}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.yuqueUseCase.List(&req)
+	if err != nil {
+		response.ServerError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPage(c, resp.Data, resp.Total, resp.Page, resp.Limit)
+}
+
+// Get 语雀同步详情
+// @Summary 获取语雀同步详情
+// @Description 获取同步配置及上次同步结果，不返回 Token
+// @Tags 语雀同步
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "同步配置ID"
+// @Success 200 {object} response.Response{data=dto.YuqueSyncResponse} "获取成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "同步配置不存在"
+// @Router /yuque/syncs/{id} [get]
+func (s *YuqueService) Get(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.yuqueUseCase.Get(req.ID)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Create 创建语雀同步
+// @Summary 创建语雀同步
+// @Description 校验 Token 后绑定知识库，以知识库名称创建笔记标签；创建后调用同步接口拉取文档
+// @Tags 语雀同步
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateYuqueSyncRequest true "同步配置"
+// @Success 200 {object} response.Response{data=dto.YuqueSyncResponse} "创建成功"
+// @Failure 400 {object} response.Response "请求参数错误或 Token 无效"
+// @Failure 409 {object} response.Response "知识库已配置同步"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /yuque/syncs [post]
+func (s *YuqueService) Create(c *gin.Context) {
+	var req dto.CreateYuqueSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.yuqueUseCase.Create(&req, c.GetUint("admin_id"))
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Update 更新语雀同步
+// @Summary 更新语雀同步
+// @Description 更新 Token、分类、自动发布和定时同步开关，token 为空时保留原 Token
+// @Tags 语雀同步
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "同步配置ID"
+// @Param request body dto.UpdateYuqueSyncRequest true "同步配置"
+// @Success 200 {object} response.Response{data=dto.YuqueSyncResponse} "更新成功"
+// @Failure 400 {object} response.Response "请求参数错误或 Token 无效"
+// @Failure 404 {object} response.Response "同步配置不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /yuque/syncs/{id} [put]
+func (s *YuqueService) Update(c *gin.Context) {
+	var uri dto.IDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	var req dto.UpdateYuqueSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	resp, err := s.yuqueUseCase.Update(uri.ID, &req)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Delete 删除语雀同步
+// @Summary 删除语雀同步
+// @Description 删除同步配置，已同步的文章和章节保留
+// @Tags 语雀同步
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "同步配置ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "请求参数错误"
+// @Failure 404 {object} response.Response "同步配置不存在"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /yuque/syncs/{id} [delete]
+func (s *YuqueService) Delete(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	if err := s.yuqueUseCase.Delete(req.ID); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Sync 立即同步
+// @Summary 立即同步语雀知识库
+// @Description 目录分组映射为章节，新文档创建文章，正文更新过的文档更新文章，未变更的文档跳过
+// @Tags 语雀同步
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "同步配置ID"
+// @Success 200 {object} response.Response{data=dto.YuqueSyncReport} "同步结果"
+// @Failure 400 {object} response.Response "Token 无效或知识库不存在"
+// @Failure 404 {object} response.Response "同步配置不存在"
+// @Failure 409 {object} response.Response "正在同步"
+// @Failure 500 {object} response.Response "服务器错误"
+// @Router /yuque/syncs/{id}/sync [post]
+func (s *YuqueService) Sync(c *gin.Context) {
+	var req dto.IDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	report, err := s.yuqueUseCase.Sync(req.ID)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	response.Success(c, report)
+}
+
+// handleError 将业务错误映射为响应
+func (s *YuqueService) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, biz.ErrYuqueSyncNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, biz.ErrYuqueSyncExists), errors.Is(err, biz.ErrYuqueSyncRunning):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, yuque.ErrUnauthorized), errors.Is(err, yuque.ErrNotFound):
+		response.BadRequest(c, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,68 @@
+package captcha
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/png"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+// ErrDisabled 未启用或未配置的验证方式
+var ErrDisabled = errors.New("captcha disabled")
+
+// Challenge 本站生成的验证码题目
+type Challenge struct {
+	Image  string // 图片验证码为验证码图片，滑块为带缺口的背景图（data URI）
+	Piece  string // 滑块图片（data URI），图片验证码为空
+	PieceY int    // 滑块在背景图中的纵坐标
+	Width  int    // 背景图宽度
+	Height int    // 背景图高度
+	Answer string // 答案，保存在服务端，不返回给前端
+}
+
+// Provider 验证方式
+type Provider interface {
+	// Name 验证方式名称，与配置中的 provider 相同
+	Name() string
+	// Remote 是否由第三方平台出题，前端使用平台组件获取令牌后提交校验
+	Remote() bool
+	// Generate 生成题目，第三方平台返回 nil
+	Generate() (*Challenge, error)
+	// Verify 校验答案：本站出题时 expected 为生成题目时的答案；第三方平台时 answer 为组件返回的令牌
+	Verify(ctx context.Context, answer, expected, remoteIP string) (bool, error)
+}
+
+// factories 已支持的验证方式
+var factories = map[string]func(cfg config.CaptchaConfig) Provider{
+	"image":     newImage,
+	"slider":    newSlider,
+	"turnstile": newTurnstile,
+	"hcaptcha":  newHCaptcha,
+}
+
+// Get 返回当前配置的验证方式，未配置、不支持或第三方平台缺少密钥时返回 ErrDisabled
+func Get() (Provider, error) {
+	cfg := config.AppConfig.Captcha
+	factory, ok := factories[cfg.Provider]
+	if !ok {
+		return nil, ErrDisabled
+	}
+	p := factory(cfg)
+	if p.Remote() && cfg.Secret == "" {
+		return nil, ErrDisabled
+	}
+	return p, nil
+}
+
+// dataURI 将图片编码为 PNG data URI
+func dataURI(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
@@ -0,0 +1,96 @@
+package captcha
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.CaptchaConfig
+		want string
+	}{
+		{name: "disabled", cfg: config.CaptchaConfig{}},
+		{name: "unknown", cfg: config.CaptchaConfig{Provider: "recaptcha"}},
+		{name: "remote without secret", cfg: config.CaptchaConfig{Provider: "hcaptcha"}},
+		{name: "remote", cfg: config.CaptchaConfig{Provider: "hcaptcha", Secret: "s"}, want: "hcaptcha"},
+		{name: "slider", cfg: config.CaptchaConfig{Provider: "slider"}, want: "slider"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.AppConfig = &config.Config{Captcha: tt.cfg}
+			p, err := Get()
+			if tt.want == "" {
+				if err != ErrDisabled {
+					t.Fatalf("Get() = %v, %v, want ErrDisabled", p, err)
+				}
+				return
+			}
+			if err != nil || p.Name() != tt.want {
+				t.Fatalf("Get() = %v, %v, want %s", p, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageCaptcha(t *testing.T) {
+	p := newImage(config.CaptchaConfig{})
+	c, err := p.Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(c.Answer) != imageDigits || c.Image == "" || c.Piece != "" {
+		t.Fatalf("challenge = %+v", c)
+	}
+
+	tests := []struct {
+		answer string
+		want   bool
+	}{
+		{answer: c.Answer, want: true},
+		{answer: " " + c.Answer + "\n", want: true},
+		{answer: c.Answer[:3], want: false},
+		{answer: "", want: false},
+	}
+	for _, tt := range tests {
+		if ok, _ := p.Verify(context.Background(), tt.answer, c.Answer, ""); ok != tt.want {
+			t.Errorf("Verify(%q) = %v, want %v", tt.answer, ok, tt.want)
+		}
+	}
+	if ok, _ := p.Verify(context.Background(), "", "", ""); ok {
+		t.Error("empty expected answer must not pass")
+	}
+}
+
+func TestSliderCaptcha(t *testing.T) {
+	p := newSlider(config.CaptchaConfig{Tolerance: 4})
+	c, err := p.Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	x, err := strconv.Atoi(c.Answer)
+	if err != nil || x < sliderPiece || x > sliderWidth-sliderPiece || c.PieceY+sliderPiece > sliderHeight || c.Piece == "" {
+		t.Fatalf("challenge = answer %s, piece y %d", c.Answer, c.PieceY)
+	}
+
+	tests := []struct {
+		answer string
+		want   bool
+	}{
+		{answer: c.Answer, want: true},
+		{answer: strconv.Itoa(x + 4), want: true},
+		{answer: strconv.FormatFloat(float64(x)-3.5, 'f', 1, 64), want: true},
+		{answer: strconv.Itoa(x - 5), want: false},
+		{answer: "NaN", want: false},
+		{answer: "left", want: false},
+	}
+	for _, tt := range tests {
+		if ok, _ := p.Verify(context.Background(), tt.answer, c.Answer, ""); ok != tt.want {
+			t.Errorf("Verify(%q) with answer %s = %v, want %v", tt.answer, c.Answer, ok, tt.want)
+		}
+	}
+}
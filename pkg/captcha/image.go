@@ -0,0 +1,135 @@
+package captcha
+
+import (
+	"context"
+	"crypto/rand"
+	"image"
+	"image/color"
+	"math/big"
+	mrand "math/rand/v2"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+const (
+	imageWidth  = 120
+	imageHeight = 40
+	imageDigits = 4
+	glyphScale  = 4
+)
+
+// glyphs 5x7 点阵数字字模
+var glyphs = [10][7]string{
+	{"01110", "10001", "10011", "10101", "11001", "10001", "01110"},
+	{"00100", "01100", "00100", "00100", "00100", "00100", "01110"},
+	{"01110", "10001", "00001", "00010", "00100", "01000", "11111"},
+	{"11111", "00010", "00100", "00010", "00001", "10001", "01110"},
+	{"00010", "00110", "01010", "10010", "11111", "00010", "00010"},
+	{"11111", "10000", "11110", "00001", "00001", "10001", "01110"},
+	{"00110", "01000", "10000", "11110", "10001", "10001", "01110"},
+	{"11111", "00001", "00010", "00100", "01000", "01000", "01000"},
+	{"01110", "10001", "10001", "01110", "10001", "10001", "01110"},
+	{"01110", "10001", "10001", "01111", "00001", "00010", "01100"},
+}
+
+// imageCaptcha 数字图片验证码
+type imageCaptcha struct{}
+
+func newImage(config.CaptchaConfig) Provider {
+	return imageCaptcha{}
+}
+
+func (imageCaptcha) Name() string { return "image" }
+
+func (imageCaptcha) Remote() bool { return false }
+
+// Generate 生成 4 位数字验证码，字符随机偏移、倾斜，并加入干扰线和噪点
+func (imageCaptcha) Generate() (*Challenge, error) {
+	digits := make([]byte, imageDigits)
+	for i := range digits {
+		n, err := secureInt(10)
+		if err != nil {
+			return nil, err
+		}
+		digits[i] = byte('0' + n)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, imageWidth, imageHeight))
+	fill(img, color.RGBA{R: 240, G: 240, B: 235, A: 255})
+	for i, d := range digits {
+		x := 8 + i*27 + mrand.IntN(5)
+		y := 4 + mrand.IntN(6)
+		drawGlyph(img, glyphs[d-'0'], x, y, mrand.IntN(3)-1, darkColor())
+	}
+	for i := 0; i < 3; i++ {
+		drawLine(img, 0, mrand.IntN(imageHeight), imageWidth-1, mrand.IntN(imageHeight), darkColor())
+	}
+	for i := 0; i < 80; i++ {
+		img.Set(mrand.IntN(imageWidth), mrand.IntN(imageHeight), darkColor())
+	}
+
+	uri, err := dataURI(img)
+	if err != nil {
+		return nil, err
+	}
+	return &Challenge{Image: uri, Width: imageWidth, Height: imageHeight, Answer: string(digits)}, nil
+}
+
+// Verify 比较输入的数字，忽略首尾空白
+func (imageCaptcha) Verify(_ context.Context, answer, expected, _ string) (bool, error) {
+	return expected != "" && strings.TrimSpace(answer) == expected, nil
+}
+
+// drawGlyph 按倍数放大绘制字模，shear 为每行的水平偏移，用于倾斜字符
+func drawGlyph(img *image.RGBA, glyph [7]string, x, y, shear int, c color.Color) {
+	for row, bits := range glyph {
+		offset := x + (row-3)*shear
+		for col, bit := range bits {
+			if bit != '1' {
+				continue
+			}
+			for dy := 0; dy < glyphScale; dy++ {
+				for dx := 0; dx < glyphScale; dx++ {
+					img.Set(offset+col*glyphScale+dx, y+row*glyphScale+dy, c)
+				}
+			}
+		}
+	}
+}
+
+// drawLine 绘制干扰线
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.Color) {
+	steps := max(abs(x1-x0), abs(y1-y0))
+	for i := 0; i <= steps; i++ {
+		img.Set(x0+(x1-x0)*i/steps, y0+(y1-y0)*i/steps, c)
+	}
+}
+
+// fill 填充纯色背景
+func fill(img *image.RGBA, c color.RGBA) {
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+}
+
+// darkColor 随机深色，与浅色背景区分
+func darkColor() color.RGBA {
+	return color.RGBA{R: uint8(mrand.IntN(120)), G: uint8(mrand.IntN(120)), B: uint8(mrand.IntN(120)), A: 255}
+}
+
+// secureInt 返回 [0, n) 内不可预测的随机数，用于生成答案
+func secureInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(v.Int64()), nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
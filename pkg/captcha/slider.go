@@ -0,0 +1,137 @@
+package captcha
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"math"
+	mrand "math/rand/v2"
+	"strconv"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+const (
+	sliderWidth      = 280
+	sliderHeight     = 160
+	sliderPiece      = 44
+	defaultTolerance = 5
+)
+
+// sliderCaptcha 滑块验证码：背景图上有一块缺口，用户将滑块拖到缺口处，答案为缺口的横坐标
+type sliderCaptcha struct {
+	tolerance int
+}
+
+func newSlider(cfg config.CaptchaConfig) Provider {
+	tolerance := cfg.Tolerance
+	if tolerance <= 0 {
+		tolerance = defaultTolerance
+	}
+	return sliderCaptcha{tolerance: tolerance}
+}
+
+func (sliderCaptcha) Name() string { return "slider" }
+
+func (sliderCaptcha) Remote() bool { return false }
+
+// Generate 生成随机背景和缺口，滑块从背景中截取，前端将滑块放在 x=0、y=PieceY 处供用户拖动
+func (sliderCaptcha) Generate() (*Challenge, error) {
+	// 缺口不与滑块的起始位置重叠
+	dx, err := secureInt(sliderWidth - 2*sliderPiece - 30)
+	if err != nil {
+		return nil, err
+	}
+	dy, err := secureInt(sliderHeight - sliderPiece - 20)
+	if err != nil {
+		return nil, err
+	}
+	x, y := sliderPiece+20+dx, 10+dy
+
+	bg := image.NewRGBA(image.Rect(0, 0, sliderWidth, sliderHeight))
+	paintBackground(bg)
+
+	piece := image.NewRGBA(image.Rect(0, 0, sliderPiece, sliderPiece))
+	for py := 0; py < sliderPiece; py++ {
+		for px := 0; px < sliderPiece; px++ {
+			c := bg.RGBAAt(x+px, y+py)
+			if px < 2 || py < 2 || px >= sliderPiece-2 || py >= sliderPiece-2 {
+				c = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+			}
+			piece.SetRGBA(px, py, c)
+			// 缺口处变暗
+			hole := bg.RGBAAt(x+px, y+py)
+			bg.SetRGBA(x+px, y+py, color.RGBA{R: hole.R / 3, G: hole.G / 3, B: hole.B / 3, A: 255})
+		}
+	}
+
+	bgURI, err := dataURI(bg)
+	if err != nil {
+		return nil, err
+	}
+	pieceURI, err := dataURI(piece)
+	if err != nil {
+		return nil, err
+	}
+	return &Challenge{
+		Image:  bgURI,
+		Piece:  pieceURI,
+		PieceY: y,
+		Width:  sliderWidth,
+		Height: sliderHeight,
+		Answer: strconv.Itoa(x),
+	}, nil
+}
+
+// Verify 滑块的横坐标与缺口相差不超过允许的误差
+func (s sliderCaptcha) Verify(_ context.Context, answer, expected, _ string) (bool, error) {
+	want, err := strconv.Atoi(expected)
+	if err != nil {
+		return false, nil
+	}
+	got, err := strconv.ParseFloat(strings.TrimSpace(answer), 64)
+	if err != nil || math.IsNaN(got) {
+		return false, nil
+	}
+	return math.Abs(got-float64(want)) <= float64(s.tolerance), nil
+}
+
+// paintBackground 绘制渐变背景和随机圆形，使缺口位置不能通过纯色背景识别
+func paintBackground(img *image.RGBA) {
+	from, to := lightColor(), lightColor()
+	for x := 0; x < sliderWidth; x++ {
+		c := color.RGBA{
+			R: blend(from.R, to.R, x, sliderWidth),
+			G: blend(from.G, to.G, x, sliderWidth),
+			B: blend(from.B, to.B, x, sliderWidth),
+			A: 255,
+		}
+		for y := 0; y < sliderHeight; y++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	for i := 0; i < 14; i++ {
+		cx, cy, r := mrand.IntN(sliderWidth), mrand.IntN(sliderHeight), 8+mrand.IntN(30)
+		c := darkColor()
+		for y := max(cy-r, 0); y < min(cy+r, sliderHeight); y++ {
+			for x := max(cx-r, 0); x < min(cx+r, sliderWidth); x++ {
+				if (x-cx)*(x-cx)+(y-cy)*(y-cy) <= r*r {
+					old := img.RGBAAt(x, y)
+					img.SetRGBA(x, y, color.RGBA{R: (old.R + c.R) / 2, G: (old.G + c.G) / 2, B: (old.B + c.B) / 2, A: 255})
+				}
+			}
+		}
+	}
+}
+
+// blend 按位置在两种颜色之间插值
+func blend(from, to uint8, pos, total int) uint8 {
+	return uint8(int(from) + (int(to)-int(from))*pos/total)
+}
+
+// lightColor 随机浅色
+func lightColor() color.RGBA {
+	return color.RGBA{R: uint8(150 + mrand.IntN(100)), G: uint8(150 + mrand.IntN(100)), B: uint8(150 + mrand.IntN(100)), A: 255}
+}
@@ -0,0 +1,121 @@
+package diff
+
+import "strings"
+
+// Op 差异操作类型
+type Op string
+
+const (
+	Equal  Op = "equal"
+	Insert Op = "insert"
+	Delete Op = "delete"
+)
+
+// Line 一行差异
+type Line struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// maxCells LCS 矩阵上限，超过时退化为整体替换，避免超长文章占用过多内存
+const maxCells = 4000000
+
+// Lines 按行比较两段文本（基于最长公共子序列）
+func Lines(a, b string) []Line {
+	al := splitLines(a)
+	bl := splitLines(b)
+
+	// 去掉公共前缀和后缀，缩小比较范围
+	prefix := 0
+	for prefix < len(al) && prefix < len(bl) && al[prefix] == bl[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(al)-prefix && suffix < len(bl)-prefix &&
+		al[len(al)-1-suffix] == bl[len(bl)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]Line, 0, len(al)+len(bl))
+	for _, l := range al[:prefix] {
+		result = append(result, Line{Op: Equal, Text: l})
+	}
+	result = append(result, lcs(al[prefix:len(al)-suffix], bl[prefix:len(bl)-suffix])...)
+	for _, l := range al[len(al)-suffix:] {
+		result = append(result, Line{Op: Equal, Text: l})
+	}
+	return result
+}
+
+// Stats 统计新增和删除的行数
+func Stats(lines []Line) (added, removed int) {
+	for _, l := range lines {
+		switch l.Op {
+		case Insert:
+			added++
+		case Delete:
+			removed++
+		}
+	}
+	return added, removed
+}
+
+func lcs(a, b []string) []Line {
+	result := make([]Line, 0, len(a)+len(b))
+	if len(a)*len(b) > maxCells {
+		for _, l := range a {
+			result = append(result, Line{Op: Delete, Text: l})
+		}
+		for _, l := range b {
+			result = append(result, Line{Op: Insert, Text: l})
+		}
+		return result
+	}
+
+	// dp[i][j] 为 a[i:] 与 b[j:] 的最长公共子序列长度
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				dp[i][j] = dp[i+1][j+1] + 1
+			} else if dp[i+1][j] >= dp[i][j+1] {
+				dp[i][j] = dp[i+1][j]
+			} else {
+				dp[i][j] = dp[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, Line{Op: Equal, Text: a[i]})
+			i++
+			j++
+		case dp[i+1][j] >= dp[i][j+1]:
+			result = append(result, Line{Op: Delete, Text: a[i]})
+			i++
+		default:
+			result = append(result, Line{Op: Insert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		result = append(result, Line{Op: Delete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		result = append(result, Line{Op: Insert, Text: b[j]})
+	}
+	return result
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,198 @@
+package encrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+// 密文格式：enc:<kid>:<base64(nonce|ciphertext)>
+const prefix = "enc:"
+
+var (
+	mu        sync.RWMutex
+	currentID string
+	keys      map[string]cipher.AEAD
+	indexKey  []byte
+)
+
+// Init 根据配置初始化字段加密密钥
+// 未配置 encryption.key 时不启用加密，Encrypt/Decrypt 原样返回
+func Init() error {
+	cfg := config.AppConfig.Encryption
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	currentID = ""
+	keys = make(map[string]cipher.AEAD)
+	indexKey = nil
+
+	if cfg.Key == "" {
+		return nil
+	}
+
+	kid := cfg.KeyID
+	if kid == "" {
+		kid = "default"
+	}
+	aead, raw, err := newAEAD(cfg.Key)
+	if err != nil {
+		return fmt.Errorf("encryption key %s: %w", kid, err)
+	}
+	keys[kid] = aead
+	currentID = kid
+
+	for _, k := range cfg.PreviousKeys {
+		if k.ID == "" || k.ID == kid {
+			continue
+		}
+		aead, _, err := newAEAD(k.Key)
+		if err != nil {
+			return fmt.Errorf("encryption key %s: %w", k.ID, err)
+		}
+		keys[k.ID] = aead
+	}
+
+	// 盲索引密钥独立于加密密钥，轮换加密密钥时不影响等值查询
+	if cfg.IndexKey != "" {
+		indexKey = []byte(cfg.IndexKey)
+	} else {
+		mac := hmac.New(sha256.New, raw)
+		mac.Write([]byte("blind-index"))
+		indexKey = mac.Sum(nil)
+	}
+
+	return nil
+}
+
+func newAEAD(encoded string) (cipher.AEAD, []byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, nil, errors.New("key must be base64 encoded")
+	}
+	if len(raw) != 32 {
+		return nil, nil, errors.New("key must be 32 bytes (AES-256)")
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, raw, nil
+}
+
+// Enabled 是否启用字段加密
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return currentID != ""
+}
+
+// CurrentKeyID 当前加密密钥ID
+func CurrentKeyID() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return currentID
+}
+
+// IsEncrypted 判断值是否为密文
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// KeyIDOf 返回密文使用的密钥ID，明文返回空字符串
+func KeyIDOf(value string) string {
+	if !IsEncrypted(value) {
+		return ""
+	}
+	parts := strings.SplitN(strings.TrimPrefix(value, prefix), ":", 2)
+	return parts[0]
+}
+
+// Encrypt 使用当前密钥加密，空值和已加密的值原样返回
+func Encrypt(plaintext string) (string, error) {
+	if plaintext == "" || IsEncrypted(plaintext) {
+		return plaintext, nil
+	}
+
+	mu.RLock()
+	kid, aead := currentID, keys[currentID]
+	mu.RUnlock()
+	if kid == "" {
+		return plaintext, nil
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(kid))
+	return prefix + kid + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密，兼容未加密的历史明文数据
+func Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(value, prefix), ":", 2)
+	if len(parts) != 2 {
+		return "", errors.New("malformed ciphertext")
+	}
+	kid := parts[0]
+
+	mu.RLock()
+	aead, ok := keys[kid]
+	mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unknown encryption key %s", kid)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed ciphertext")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(kid))
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// BlindIndex 计算用于等值查询的盲索引
+// 未启用加密时返回规范化后的原值
+func BlindIndex(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return ""
+	}
+
+	mu.RLock()
+	key := indexKey
+	mu.RUnlock()
+	if key == nil {
+		return value
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
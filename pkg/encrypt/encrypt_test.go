@@ -0,0 +1,204 @@
+package encrypt
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+// testKey 生成 32 字节的 base64 测试密钥
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+}
+
+// setupKeys 使用给定配置初始化密钥，测试结束后恢复为未启用加密
+func setupKeys(t *testing.T, cfg config.EncryptionConfig) {
+	t.Helper()
+	config.AppConfig = &config.Config{Encryption: cfg}
+	if err := Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	t.Cleanup(func() {
+		config.AppConfig = &config.Config{}
+		_ = Init()
+	})
+}
+
+func TestInit(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.EncryptionConfig
+		enabled bool
+		wantErr bool
+	}{
+		{name: "disabled without key"},
+		{name: "valid key", cfg: config.EncryptionConfig{Key: testKey('a'), KeyID: "k1"}, enabled: true},
+		{name: "not base64", cfg: config.EncryptionConfig{Key: "not base64!"}, wantErr: true},
+		{name: "wrong length", cfg: config.EncryptionConfig{Key: base64.StdEncoding.EncodeToString([]byte("short"))}, wantErr: true},
+		{
+			name:    "invalid previous key",
+			cfg:     config.EncryptionConfig{Key: testKey('a'), PreviousKeys: []config.EncryptionKey{{ID: "old", Key: "bad"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.AppConfig = &config.Config{Encryption: tt.cfg}
+			t.Cleanup(func() {
+				config.AppConfig = &config.Config{}
+				_ = Init()
+			})
+			err := Init()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Init err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && Enabled() != tt.enabled {
+				t.Errorf("Enabled = %v, want %v", Enabled(), tt.enabled)
+			}
+		})
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	setupKeys(t, config.EncryptionConfig{Key: testKey('a'), KeyID: "k1"})
+
+	tests := []struct {
+		name      string
+		plaintext string
+		sealed    bool
+	}{
+		{name: "empty stays empty", plaintext: ""},
+		{name: "ascii", plaintext: "alice@example.com", sealed: true},
+		{name: "unicode", plaintext: "张三@例子.中国", sealed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sealed, err := Encrypt(tt.plaintext)
+			if err != nil {
+				t.Fatalf("Encrypt: %v", err)
+			}
+			if IsEncrypted(sealed) != tt.sealed {
+				t.Fatalf("IsEncrypted(%q) = %v, want %v", sealed, !tt.sealed, tt.sealed)
+			}
+			if tt.sealed && KeyIDOf(sealed) != "k1" {
+				t.Errorf("KeyIDOf = %q, want k1", KeyIDOf(sealed))
+			}
+			again, err := Encrypt(sealed)
+			if err != nil || again != sealed {
+				t.Errorf("encrypting ciphertext again changed it: %q -> %q (%v)", sealed, again, err)
+			}
+			plain, err := Decrypt(sealed)
+			if err != nil {
+				t.Fatalf("Decrypt: %v", err)
+			}
+			if plain != tt.plaintext {
+				t.Errorf("Decrypt = %q, want %q", plain, tt.plaintext)
+			}
+		})
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	setupKeys(t, config.EncryptionConfig{Key: testKey('a'), KeyID: "k1"})
+	sealed, err := Encrypt("secret")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	payload := strings.TrimPrefix(sealed, prefix+"k1:")
+	raw, _ := base64.StdEncoding.DecodeString(payload)
+	raw[len(raw)-1] ^= 0xff
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "legacy plaintext", value: "plain@example.com", want: "plain@example.com"},
+		{name: "missing payload", value: "enc:k1", wantErr: true},
+		{name: "unknown key", value: "enc:k9:" + payload, wantErr: true},
+		{name: "key id is authenticated", value: "enc:k2:" + payload, wantErr: true},
+		{name: "not base64", value: "enc:k1:!!!", wantErr: true},
+		{name: "too short", value: "enc:k1:" + base64.StdEncoding.EncodeToString([]byte("x")), wantErr: true},
+		{name: "tampered", value: "enc:k1:" + base64.StdEncoding.EncodeToString(raw), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decrypt(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Decrypt(%q) = %q, want error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decrypt: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Decrypt = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	setupKeys(t, config.EncryptionConfig{Key: testKey('a'), KeyID: "k1", IndexKey: "index"})
+	old, err := Encrypt("alice@example.com")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	oldIndex := BlindIndex("alice@example.com")
+
+	setupKeys(t, config.EncryptionConfig{
+		Key:          testKey('b'),
+		KeyID:        "k2",
+		IndexKey:     "index",
+		PreviousKeys: []config.EncryptionKey{{ID: "k1", Key: testKey('a')}},
+	})
+	if plain, err := Decrypt(old); err != nil || plain != "alice@example.com" {
+		t.Fatalf("Decrypt with previous key = %q, %v", plain, err)
+	}
+	sealed, err := Encrypt("alice@example.com")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if KeyIDOf(sealed) != "k2" {
+		t.Errorf("new ciphertext uses key %q, want k2", KeyIDOf(sealed))
+	}
+	if BlindIndex("alice@example.com") != oldIndex {
+		t.Error("blind index changed although index_key is unchanged")
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	setupKeys(t, config.EncryptionConfig{Key: testKey('a')})
+
+	tests := []struct {
+		name  string
+		a, b  string
+		equal bool
+	}{
+		{name: "case and spaces normalized", a: "Alice@Example.com ", b: "alice@example.com", equal: true},
+		{name: "different values", a: "alice@example.com", b: "bob@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := BlindIndex(tt.a), BlindIndex(tt.b)
+			if (a == b) != tt.equal {
+				t.Errorf("BlindIndex(%q) == BlindIndex(%q) is %v, want %v", tt.a, tt.b, a == b, tt.equal)
+			}
+			if strings.Contains(a, "alice") {
+				t.Errorf("BlindIndex leaks the plaintext: %q", a)
+			}
+		})
+	}
+	if BlindIndex("  ") != "" {
+		t.Error("BlindIndex of blank value should be empty")
+	}
+}
@@ -0,0 +1,123 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const (
+	atomNamespace = "http://www.w3.org/2005/Atom"
+	dcNamespace   = "http://purl.org/dc/elements/1.1/"
+)
+
+// Channel RSS 2.0 频道
+type Channel struct {
+	Title       string
+	Link        string // 频道对应的网页地址
+	SelfURL     string // 订阅地址本身，输出为 atom:link rel="self"
+	Description string
+	Language    string
+	Items       []Item
+}
+
+// Item 频道条目
+type Item struct {
+	Title       string
+	Link        string
+	GUID        string // 为空时使用 Link
+	Author      string
+	Description string // HTML 内容，输出时作为文本转义
+	PubDate     time.Time
+}
+
+// rss 根节点
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	AtomLink      *atomLink `xml:"atom:link,omitempty"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	Author      string  `xml:"dc:creator,omitempty"`
+	Description string  `xml:"description"`
+	PubDate     string  `xml:"pubDate,omitempty"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// Build 生成 RSS 2.0 文档，lastBuildDate 取最新条目的发布时间
+func Build(channel Channel) ([]byte, error) {
+	doc := rss{
+		Version: "2.0",
+		Atom:    atomNamespace,
+		DC:      dcNamespace,
+		Channel: rssChannel{
+			Title:       channel.Title,
+			Link:        channel.Link,
+			Description: channel.Description,
+			Language:    channel.Language,
+			Items:       make([]rssItem, 0, len(channel.Items)),
+		},
+	}
+	if channel.SelfURL != "" {
+		doc.Channel.AtomLink = &atomLink{Href: channel.SelfURL, Rel: "self", Type: "application/rss+xml"}
+	}
+
+	var latest time.Time
+	for _, item := range channel.Items {
+		guid := rssGUID{Value: item.GUID}
+		if guid.Value == "" {
+			guid = rssGUID{Value: item.Link, IsPermaLink: true}
+		}
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        guid,
+			Author:      item.Author,
+			Description: item.Description,
+			PubDate:     formatTime(item.PubDate),
+		})
+		if item.PubDate.After(latest) {
+			latest = item.PubDate
+		}
+	}
+	doc.Channel.LastBuildDate = formatTime(latest)
+
+	body, err := xml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// formatTime 按 RFC 1123 格式输出，零值不输出
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC1123Z)
+}
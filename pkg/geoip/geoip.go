@@ -0,0 +1,104 @@
+package geoip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+const (
+	// Private 内网地址的所在地
+	Private = "内网"
+
+	defaultTimeout = 3 * time.Second
+	cacheKey       = "geoip:%s"
+	cacheExpire    = 7 * 24 * time.Hour
+)
+
+// result ip-api.com 格式的查询结果
+type result struct {
+	Status     string `json:"status"` // success 或 fail，未返回时视为成功
+	Country    string `json:"country"`
+	RegionName string `json:"regionName"`
+	City       string `json:"city"`
+}
+
+// Lookup 查询 IP 所在地，如 "中国 广东 深圳"；内网地址返回 Private，未配置查询接口或查询失败时返回空
+// 启用 Redis 时缓存查询结果，同一 IP 7 天内不重复查询
+func Lookup(ip string) string {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return ""
+	}
+	if addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() || addr.IsUnspecified() {
+		return Private
+	}
+	pattern := config.AppConfig.LoginLog.GeoURL
+	if pattern == "" {
+		return ""
+	}
+
+	key := fmt.Sprintf(cacheKey, addr.String())
+	if redis.Client != nil {
+		if cached, err := redis.Get(key); err == nil {
+			return cached
+		}
+	}
+
+	location, err := query(strings.ReplaceAll(pattern, "{ip}", url.PathEscape(addr.String())))
+	if err != nil {
+		return ""
+	}
+	if redis.Client != nil {
+		_ = redis.SetWithExpire(key, location, cacheExpire)
+	}
+	return location
+}
+
+// query 请求查询接口
+func query(endpoint string) (string, error) {
+	timeout := time.Duration(config.AppConfig.LoginLog.GeoTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("geoip: unexpected status %d", resp.StatusCode)
+	}
+
+	var r result
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return "", err
+	}
+	if r.Status != "" && r.Status != "success" {
+		return "", fmt.Errorf("geoip: lookup failed")
+	}
+
+	// 直辖市的省份和城市相同，只保留一个
+	parts := make([]string, 0, 3)
+	for _, part := range []string{r.Country, r.RegionName, r.City} {
+		if part != "" && (len(parts) == 0 || parts[len(parts)-1] != part) {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " "), nil
+}
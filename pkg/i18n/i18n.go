@@ -0,0 +1,150 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+// 支持的语言
+const (
+	LocaleZH = "zh-CN"
+	LocaleEN = "en"
+)
+
+// LocaleKey 上下文中保存请求语言的 key
+const LocaleKey = "locale"
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+var (
+	// catalogs 各语言的消息目录：消息码 -> 文本
+	catalogs = map[string]map[string]string{}
+	// sourceCodes 中文消息 -> 消息码，用于翻译业务代码中直接返回的中文消息
+	sourceCodes = map[string]string{}
+)
+
+func init() {
+	entries, err := localeFS.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	for _, entry := range entries {
+		raw, err := localeFS.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		catalog := map[string]string{}
+		if err := json.Unmarshal(raw, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog %s: %v", entry.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(entry.Name(), ".json")] = catalog
+	}
+
+	// 多个消息码对应同一条中文时取排序最前的，保证结果稳定
+	codes := make([]string, 0, len(catalogs[LocaleZH]))
+	for code := range catalogs[LocaleZH] {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		message := catalogs[LocaleZH][code]
+		if _, ok := sourceCodes[message]; !ok {
+			sourceCodes[message] = code
+		}
+	}
+}
+
+// DefaultLocale 请求未指定或指定了不支持的语言时使用的语言，默认中文
+func DefaultLocale() string {
+	if config.AppConfig != nil {
+		if locale := Match(config.AppConfig.I18n.DefaultLocale); locale != "" {
+			return locale
+		}
+	}
+	return LocaleZH
+}
+
+// Match 将语言标签匹配到支持的语言，如 zh、zh-TW、zh_CN 匹配 zh-CN，en-US 匹配 en，不支持时返回空字符串
+func Match(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	primary, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	switch primary {
+	case "zh":
+		return LocaleZH
+	case "en":
+		return LocaleEN
+	}
+	return ""
+}
+
+// Negotiate 根据 Accept-Language 请求头选择语言，按 q 值从高到低取第一个支持的语言
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if locale := Match(tag); locale != "" && q > 0 {
+			candidates = append(candidates, candidate{locale: locale, q: q})
+		}
+	}
+	if len(candidates) == 0 {
+		return DefaultLocale()
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].locale
+}
+
+// T 按消息码取得对应语言的消息，params 替换消息中的 {name} 占位符
+// 该语言缺少的消息使用中文，消息码不存在时返回消息码本身
+func T(locale, code string, params map[string]string) string {
+	message, ok := catalogs[locale][code]
+	if !ok {
+		if message, ok = catalogs[LocaleZH][code]; !ok {
+			return code
+		}
+	}
+	for name, value := range params {
+		message = strings.ReplaceAll(message, "{"+name+"}", value)
+	}
+	return message
+}
+
+// Code 查询中文消息的消息码，目录中没有时返回空字符串
+func Code(message string) string {
+	return sourceCodes[message]
+}
+
+// Translate 翻译业务代码返回的中文消息，返回翻译后的消息和消息码
+// 形如 "页面参数不合法: 标题不能为空" 的消息逐段翻译，消息码取第一段的消息码；目录中没有的段落保持原样
+func Translate(locale, message string) (string, string) {
+	segments := strings.Split(message, ": ")
+	code := sourceCodes[segments[0]]
+	if locale == LocaleZH {
+		return message, code
+	}
+	for i, segment := range segments {
+		if segmentCode, ok := sourceCodes[segment]; ok {
+			segments[i] = T(locale, segmentCode, nil)
+		}
+	}
+	return strings.Join(segments, ": "), code
+}
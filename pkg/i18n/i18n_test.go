@@ -0,0 +1,119 @@
+package i18n
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestCatalogsHaveSameCodes(t *testing.T) {
+	zh, en := catalogs[LocaleZH], catalogs[LocaleEN]
+	if len(zh) == 0 || len(en) == 0 {
+		t.Fatalf("catalogs not loaded: zh=%d en=%d", len(zh), len(en))
+	}
+	for code := range zh {
+		if _, ok := en[code]; !ok {
+			t.Errorf("en missing %q", code)
+		}
+	}
+	for code := range en {
+		if _, ok := zh[code]; !ok {
+			t.Errorf("zh-CN missing %q", code)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "", want: LocaleZH},
+		{header: "en-US,en;q=0.9", want: LocaleEN},
+		{header: "zh-TW", want: LocaleZH},
+		{header: "fr-FR,en;q=0.8,zh;q=0.5", want: LocaleEN},
+		{header: "zh;q=0.3,en;q=0.7", want: LocaleEN},
+		{header: "en;q=0,de", want: LocaleZH},
+		{header: "ja,ko", want: LocaleZH},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	msg, code := Translate(LocaleEN, "页面不存在")
+	if msg != "Page not found" || code != "page_not_found" {
+		t.Errorf("Translate = %q, %q", msg, code)
+	}
+
+	// 逐段翻译，目录外的段落保持原样
+	msg, code = Translate(LocaleEN, "页面参数不合法: 页面不存在: xyz")
+	if msg != "Invalid page parameters: Page not found: xyz" || code != "page_invalid" {
+		t.Errorf("Translate segments = %q, %q", msg, code)
+	}
+
+	msg, code = Translate(LocaleZH, "页面不存在")
+	if msg != "页面不存在" || code != "page_not_found" {
+		t.Errorf("Translate zh = %q, %q", msg, code)
+	}
+
+	msg, code = Translate(LocaleEN, "未知错误 abc")
+	if msg != "未知错误 abc" || code != "" {
+		t.Errorf("Translate unknown = %q, %q", msg, code)
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T(LocaleEN, "validation_required", map[string]string{"field": "title"}); got != "title is required" {
+		t.Errorf("T = %q", got)
+	}
+	if got := T(LocaleEN, "no_such_code", nil); got != "no_such_code" {
+		t.Errorf("T missing = %q", got)
+	}
+}
+
+func TestValidationMessage(t *testing.T) {
+	v := validator.New()
+	RegisterFieldNames(v)
+
+	type request struct {
+		Title  string   `json:"title" validate:"required"`
+		Slug   string   `json:"slug" validate:"omitempty,min=3"`
+		Limit  int      `form:"limit" validate:"lte=100"`
+		Tags   []string `json:"tags" validate:"max=2"`
+		Status string   `json:"status" validate:"omitempty,oneof=draft published"`
+	}
+	err := v.Struct(request{Slug: "ab", Limit: 200, Tags: []string{"a", "b", "c"}, Status: "x"})
+
+	msg, code := ValidationMessage(LocaleEN, err)
+	want := strings.Join([]string{
+		"title is required",
+		"slug must be at least 3 characters long",
+		"limit must be at most 100",
+		"tags must contain at most 2 items",
+		"status must be one of: draft, published",
+	}, "; ")
+	if msg != want || code != "validation_failed" {
+		t.Errorf("ValidationMessage = %q, %q\nwant %q", msg, code, want)
+	}
+
+	msg, _ = ValidationMessage(LocaleZH, v.Struct(request{}))
+	if msg != "title 不能为空" {
+		t.Errorf("ValidationMessage zh = %q", msg)
+	}
+
+	var body request
+	err = json.Unmarshal([]byte(`{"title": 1}`), &body)
+	if msg, code = ValidationMessage(LocaleEN, err); msg != "title has the wrong type" || code != "validation_failed" {
+		t.Errorf("type error = %q, %q", msg, code)
+	}
+	err = json.Unmarshal([]byte(`{"title"`), &body)
+	if _, code = ValidationMessage(LocaleEN, err); code != "invalid_json" {
+		t.Errorf("syntax error code = %q", code)
+	}
+}
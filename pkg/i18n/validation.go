@@ -0,0 +1,82 @@
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// RegisterFieldNames 校验错误中使用 json、form、uri 标签中的字段名，与客户端提交的参数名一致
+func RegisterFieldNames(v *validator.Validate) {
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form", "uri"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+}
+
+// ValidationMessage 翻译请求参数绑定和校验错误，返回消息和消息码
+// 多个字段校验失败时每个字段一条消息，以分号分隔
+func ValidationMessage(locale string, err error) (string, string) {
+	var validationErrors validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	var numErr *strconv.NumError
+	switch {
+	case errors.As(err, &validationErrors):
+		messages := make([]string, 0, len(validationErrors))
+		for _, fe := range validationErrors {
+			messages = append(messages, fieldMessage(locale, fe))
+		}
+		return strings.Join(messages, T(locale, "validation_separator", nil)), "validation_failed"
+	case errors.As(err, &typeErr):
+		return T(locale, "validation_type", map[string]string{"field": typeErr.Field}), "validation_failed"
+	case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return T(locale, "invalid_json", nil), "invalid_json"
+	case errors.As(err, &numErr):
+		return T(locale, "validation_number", map[string]string{"value": numErr.Num}), "validation_failed"
+	}
+	return Translate(locale, err.Error())
+}
+
+// fieldMessage 单个字段的校验失败消息，长度类规则按字段类型区分字符数、数值和项数
+func fieldMessage(locale string, fe validator.FieldError) string {
+	params := map[string]string{"field": fe.Field(), "param": fe.Param()}
+
+	tag := fe.Tag()
+	switch tag {
+	case "gte":
+		tag = "min"
+	case "lte":
+		tag = "max"
+	case "oneof":
+		params["param"] = strings.Join(strings.Fields(fe.Param()), ", ")
+	}
+
+	switch tag {
+	case "min", "max", "len":
+		switch fe.Kind() {
+		case reflect.String:
+			return T(locale, "validation_"+tag+"_string", params)
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return T(locale, "validation_"+tag+"_items", params)
+		default:
+			return T(locale, "validation_"+tag+"_number", params)
+		}
+	case "required", "email", "url", "oneof", "gt", "lt", "alphanum", "numeric":
+		return T(locale, "validation_"+tag, params)
+	}
+	return T(locale, "validation_invalid", params)
+}
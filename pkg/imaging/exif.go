@@ -0,0 +1,51 @@
+package imaging
+
+import "encoding/binary"
+
+// JPEG 标记
+const (
+	markerPrefix = 0xFF
+	markerSOI    = 0xD8
+	markerSOS    = 0xDA
+	markerAPP1   = 0xE1 // Exif / XMP
+	markerAPP13  = 0xED // Photoshop IRB / IPTC
+)
+
+// StripEXIF 去掉 JPEG 中的 Exif、XMP 和 IPTC 段（包含 GPS 位置、相机型号等），图像数据不重新编码
+// ICC 颜色配置（APP2）保留；方向信息随 Exif 一起去掉，带旋转标记的照片可能按原始方向显示
+// 非 JPEG 或结构异常时原样返回
+func StripEXIF(data []byte) []byte {
+	if len(data) < 4 || data[0] != markerPrefix || data[1] != markerSOI {
+		return data
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != markerPrefix {
+			return data
+		}
+		marker := data[pos+1]
+		if marker == markerPrefix {
+			// 填充字节
+			pos++
+			continue
+		}
+		if marker == markerSOS {
+			// 之后是压缩数据，原样保留
+			return append(out, data[pos:]...)
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return data
+		}
+		if marker != markerAPP1 && marker != markerAPP13 {
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+	return data
+}
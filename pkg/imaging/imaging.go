@@ -0,0 +1,144 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+// 默认参数
+const (
+	defaultMinSize     = 200 // KB
+	defaultJPEGQuality = 82
+	defaultWebPQuality = 80
+	defaultWebPCommand = "cwebp"
+	webpTimeout        = 30 * time.Second
+)
+
+// ErrWebPUnavailable 服务器未安装 WebP 编码工具
+var ErrWebPUnavailable = errors.New("服务器未安装 WebP 编码工具(cwebp)")
+
+// Result 优化结果
+type Result struct {
+	Data []byte // 原格式的图片，重新压缩后更小时为压缩结果，否则为原图
+	WebP []byte // WebP 版本，未启用、不支持的格式或转换后不更小时为空
+}
+
+// Optimize 按配置优化图片：去掉 JPEG 的 Exif 信息，超过 min_size 的 JPEG / PNG 重新压缩，并生成 WebP 版本
+// GIF、SVG、WebP 等其他格式原样返回；WebP 转换失败不影响原格式的结果
+func Optimize(data []byte) (*Result, error) {
+	cfg := config.AppConfig.Image
+	if cfg.StripEXIF {
+		data = StripEXIF(data)
+	}
+	result := &Result{Data: data}
+	if !cfg.Optimize {
+		return result, nil
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		// 不支持的格式
+		return result, nil
+	}
+	if format != "jpeg" && format != "png" {
+		return result, nil
+	}
+
+	minSize := cfg.MinSize
+	if minSize <= 0 {
+		minSize = defaultMinSize
+	}
+	if len(data) >= minSize*1024 {
+		if compressed, err := recompress(img, format, cfg.JPEGQuality); err == nil && len(compressed) < len(data) {
+			result.Data = compressed
+		}
+	}
+
+	if cfg.WebP {
+		webp, err := encodeWebP(data, cfg.WebPCommand, cfg.WebPQuality)
+		if err != nil {
+			return result, err
+		}
+		if len(webp) < len(result.Data) {
+			result.WebP = webp
+		}
+	}
+	return result, nil
+}
+
+// recompress 以原格式重新编码：JPEG 按配置的质量，PNG 使用最高压缩级别
+func recompress(img image.Image, format string, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		if quality <= 0 || quality > 100 {
+			quality = defaultJPEGQuality
+		}
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, err
+		}
+	case "png":
+		encoder := &png.Encoder{CompressionLevel: png.BestCompression}
+		if err := encoder.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("不支持的图片格式: %s", format)
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeWebP 通过 cwebp 将图片转换为 WebP，输入输出均走标准流
+func encodeWebP(data []byte, command string, quality int) ([]byte, error) {
+	if command == "" {
+		command = defaultWebPCommand
+	}
+	if quality <= 0 || quality > 100 {
+		quality = defaultWebPQuality
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webpTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, command, "-quiet", "-q", strconv.Itoa(quality), "-o", "-", "--", "-")
+	cmd.Stdin = bytes.NewReader(data)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, ErrWebPUnavailable
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("生成WebP超时: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("生成WebP失败: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// IsOptimizable 根据扩展名判断是否为可优化的图片（JPEG / PNG）
+func IsOptimizable(filename string) bool {
+	lower := strings.ToLower(filename)
+	return strings.HasSuffix(lower, ".jpg") || strings.HasSuffix(lower, ".jpeg") || strings.HasSuffix(lower, ".png")
+}
+
+// WebPName 图片 WebP 版本的文件名：扩展名替换为 .webp，如 a/b/c.jpg -> a/b/c.webp
+func WebPName(filename string) string {
+	if i := strings.LastIndex(filename, "."); i > strings.LastIndex(filename, "/") {
+		filename = filename[:i]
+	}
+	return filename + ".webp"
+}
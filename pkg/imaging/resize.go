@@ -0,0 +1,118 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+// 默认尺寸（宽度，像素）
+const (
+	defaultThumbnailWidth = 320
+	defaultMediumWidth    = 960
+)
+
+// Variant 图片的缩放版本
+type Variant struct {
+	Name  string // thumbnail / medium
+	Width int
+	Data  []byte
+}
+
+// Variants 按 image 配置生成缩略图和中图，原图不超过目标宽度的尺寸跳过
+// 只处理 JPEG / PNG，输出格式与原图相同；未启用或不支持的格式返回空
+func Variants(data []byte) []Variant {
+	cfg := config.AppConfig.Image
+	if !cfg.Variants {
+		return nil
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil || (format != "jpeg" && format != "png") {
+		return nil
+	}
+
+	sizes := []struct {
+		name  string
+		width int
+	}{
+		{"thumbnail", cfg.ThumbnailWidth},
+		{"medium", cfg.MediumWidth},
+	}
+	if sizes[0].width <= 0 {
+		sizes[0].width = defaultThumbnailWidth
+	}
+	if sizes[1].width <= 0 {
+		sizes[1].width = defaultMediumWidth
+	}
+
+	var variants []Variant
+	for _, size := range sizes {
+		if size.width >= img.Bounds().Dx() {
+			continue
+		}
+		encoded, err := recompress(resize(img, size.width), format, cfg.JPEGQuality)
+		if err != nil {
+			continue
+		}
+		variants = append(variants, Variant{Name: size.name, Width: size.width, Data: encoded})
+	}
+	return variants
+}
+
+// VariantName 缩放版本的文件名：扩展名前加尺寸后缀，如 a/b/c.jpg -> a/b/c_thumbnail.jpg
+func VariantName(filename, name string) string {
+	ext := ""
+	if i := strings.LastIndex(filename, "."); i > strings.LastIndex(filename, "/") {
+		filename, ext = filename[:i], filename[i:]
+	}
+	return filename + "_" + name + ext
+}
+
+// resize 按宽度等比缩小，每个目标像素取对应源区域的平均值
+func resize(src image.Image, width int) *image.RGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	height := srcH * width / srcW
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*srcH/height
+		y1 := bounds.Min.Y + (y+1)*srcH/height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*srcW/width
+			x1 := bounds.Min.X + (x+1)*srcW/width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
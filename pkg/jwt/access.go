@@ -0,0 +1,62 @@
+package jwt
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// articleAccessAudience 文章访问令牌的 aud，用于与登录令牌区分
+const articleAccessAudience = "article-access"
+
+// ArticleAccessClaims 加密文章访问令牌声明
+type ArticleAccessClaims struct {
+	ArticleID uint `json:"article_id"`
+	jwt.RegisteredClaims
+}
+
+// GenerateArticleAccessToken 生成加密文章的短期访问令牌
+func GenerateArticleAccessToken(articleID uint, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := ArticleAccessClaims{
+		ArticleID: articleID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{articleAccessAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "blog-admin-api",
+		},
+	}
+
+	kid, secret := ring.current()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// VerifyArticleAccessToken 校验访问令牌是否对指定文章有效
+func VerifyArticleAccessToken(tokenString string, articleID uint) bool {
+	if tokenString == "" {
+		return false
+	}
+
+	claims := &ArticleAccessClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		secret, ok := ring.lookup(kid)
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		return secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(articleAccessAudience))
+	if err != nil || !token.Valid {
+		return false
+	}
+	return claims.ArticleID == articleID
+}
@@ -0,0 +1,55 @@
+package jwt
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// emailVerifyAudience 邮箱验证令牌的 aud，用于与登录令牌区分
+const emailVerifyAudience = "email-verify"
+
+// EmailVerifyClaims 邮箱验证令牌声明，邮箱变更后旧令牌失效
+type EmailVerifyClaims struct {
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// GenerateEmailVerifyToken 生成邮箱验证令牌
+func GenerateEmailVerifyToken(userID uint, email string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := EmailVerifyClaims{
+		UserID: userID,
+		Email:  email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{emailVerifyAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "blog-admin-api",
+		},
+	}
+
+	kid, secret := ring.current()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = kid
+	return token.SignedString(secret)
+}
+
+// ParseEmailVerifyToken 解析邮箱验证令牌
+func ParseEmailVerifyToken(tokenString string) (*EmailVerifyClaims, error) {
+	claims := &EmailVerifyClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		secret, ok := ring.lookup(kid)
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		return secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(emailVerifyAudience))
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
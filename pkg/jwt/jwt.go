@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -13,39 +15,65 @@ type Claims struct {
 	AdminID  uint   `json:"admin_id"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	// SessionID 会话ID（刷新令牌家族），吊销会话时该会话签发的访问令牌同时失效
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken 生成JWT Token
+// GenerateToken 生成JWT Token，有效期为 jwt.expire 小时
 func GenerateToken(adminID uint, username, role string) (string, error) {
-	expireHours := config.AppConfig.JWT.Expire
+	return GenerateTokenWithTTL(adminID, username, role, time.Duration(config.AppConfig.JWT.Expire)*time.Hour)
+}
+
+// GenerateTokenWithTTL 生成指定有效期的JWT Token
+func GenerateTokenWithTTL(adminID uint, username, role string, ttl time.Duration) (string, error) {
+	return GenerateSessionToken(adminID, username, role, "", ttl)
+}
+
+// GenerateSessionToken 生成属于指定会话的JWT Token（配合刷新令牌使用的短期访问令牌）
+func GenerateSessionToken(adminID uint, username, role, sessionID string, ttl time.Duration) (string, error) {
+	// jti 用于将单个令牌加入黑名单
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
 	claims := Claims{
-		AdminID:  adminID,
-		Username: username,
-		Role:     role,
+		AdminID:   adminID,
+		Username:  username,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(expireHours) * time.Hour)),
+			ID:        hex.EncodeToString(jti),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "blog-admin-api",
 		},
 	}
 
+	kid, secret := ring.current()
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(config.AppConfig.JWT.Secret))
+	token.Header["kid"] = kid
+	return token.SignedString(secret)
 }
 
 // ParseToken 解析JWT Token
 func ParseToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(config.AppConfig.JWT.Secret), nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		secret, ok := ring.lookup(kid)
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		return secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+	// 登录令牌不带 aud，带 aud 的是其他用途的令牌（如文章访问令牌），不能用于认证
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid && len(claims.Audience) == 0 {
 		return claims, nil
 	}
 
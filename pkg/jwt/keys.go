@@ -0,0 +1,341 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/pkg/encrypt"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+const (
+	// defaultKeyID 未配置 key_id 时使用的密钥ID
+	defaultKeyID = "default"
+	// maxPreviousKeys 轮换后最多保留的旧密钥数量
+	maxPreviousKeys = 5
+	// minSecretLength 签名密钥最小长度
+	minSecretLength = 32
+	// keyRingKey 轮换后的密钥环保存在 Redis 中，多个实例共享，重启后仍然有效
+	keyRingKey = "jwt:keyring"
+	// keyRingSyncInterval 从 Redis 同步其他实例轮换结果的间隔
+	keyRingSyncInterval = 30 * time.Second
+)
+
+var (
+	// ErrSecretTooShort 签名密钥过短
+	ErrSecretTooShort = fmt.Errorf("secret must be at least %d characters", minSecretLength)
+	// ErrKeyStoreUnavailable 未启用 Redis，无法保存轮换后的密钥
+	ErrKeyStoreUnavailable = errors.New("key store unavailable, redis is required")
+	// ErrRotateTooFrequently 同一秒内重复轮换
+	ErrRotateTooFrequently = errors.New("key rotated too frequently, please retry later")
+	// ErrKeyNotFound 密钥不存在
+	ErrKeyNotFound = errors.New("signing key not found")
+	// ErrRevokeCurrentKey 当前签名密钥不能吊销
+	ErrRevokeCurrentKey = errors.New("current signing key cannot be revoked")
+)
+
+// storedRing Redis 中保存的密钥环，配置文件中的密钥不重复保存
+type storedRing struct {
+	// Current 当前签名密钥ID，为空时使用配置的 key_id
+	Current string `json:"current"`
+	// Keys 轮换生成的密钥，按轮换先后排列，启用字段加密时密钥内容加密保存
+	Keys []storedKey `json:"keys"`
+	// Revoked 已吊销的配置文件密钥ID
+	Revoked []string `json:"revoked"`
+}
+
+// storedKey 轮换生成的密钥
+type storedKey struct {
+	ID     string `json:"id"`
+	Secret string `json:"secret"`
+}
+
+// keyRing 签名密钥环：一个当前密钥用于签发，若干旧密钥仅用于校验
+type keyRing struct {
+	mu        sync.RWMutex
+	loaded    bool
+	syncedAt  time.Time
+	currentID string
+	keys      map[string][]byte
+	// previous 旧密钥ID，按轮换先后排列（最早的在前）
+	previous []string
+}
+
+var ring = &keyRing{}
+
+// load 合并配置文件和 Redis 中的密钥，之后每隔 keyRingSyncInterval 重新同步一次
+func (r *keyRing) load() {
+	r.mu.RLock()
+	fresh := r.loaded && time.Since(r.syncedAt) < keyRingSyncInterval
+	r.mu.RUnlock()
+	if fresh {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.loaded && time.Since(r.syncedAt) < keyRingSyncInterval {
+		return
+	}
+	// 读取失败时沿用已加载的密钥，首次加载失败时只使用配置文件中的密钥
+	stored, err := readStoredRing()
+	if err != nil && r.loaded {
+		r.syncedAt = time.Now()
+		return
+	}
+	r.rebuild(stored)
+}
+
+// rebuild 由配置文件和 Redis 中保存的密钥重建密钥环，调用方需持有写锁
+func (r *keyRing) rebuild(stored *storedRing) {
+	cfg := config.AppConfig.JWT
+	r.keys = make(map[string][]byte)
+	r.previous = nil
+	r.currentID = cfg.KeyID
+	if r.currentID == "" {
+		r.currentID = defaultKeyID
+	}
+	for _, k := range cfg.PreviousKeys {
+		if k.ID == "" || k.Secret == "" || k.ID == r.currentID {
+			continue
+		}
+		r.keys[k.ID] = []byte(k.Secret)
+		r.previous = append(r.previous, k.ID)
+	}
+	r.keys[r.currentID] = []byte(cfg.Secret)
+
+	if stored != nil {
+		for _, k := range stored.Keys {
+			if _, exists := r.keys[k.ID]; exists {
+				continue
+			}
+			secret, err := encrypt.Decrypt(k.Secret)
+			if err != nil || secret == "" {
+				continue
+			}
+			r.keys[k.ID] = []byte(secret)
+			if k.ID != stored.Current {
+				r.previous = append(r.previous, k.ID)
+			}
+		}
+		if _, ok := r.keys[stored.Current]; ok && stored.Current != r.currentID {
+			r.previous = append(r.previous, r.currentID)
+			r.currentID = stored.Current
+		}
+		for _, id := range stored.Revoked {
+			if id == r.currentID {
+				continue
+			}
+			delete(r.keys, id)
+		}
+	}
+
+	previous := r.previous[:0]
+	for _, id := range r.previous {
+		if _, ok := r.keys[id]; ok && id != r.currentID {
+			previous = append(previous, id)
+		}
+	}
+	r.previous = previous
+	for len(r.previous) > maxPreviousKeys {
+		delete(r.keys, r.previous[0])
+		r.previous = r.previous[1:]
+	}
+
+	r.loaded = true
+	r.syncedAt = time.Now()
+}
+
+// current 获取当前签名密钥
+func (r *keyRing) current() (string, []byte) {
+	r.load()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.currentID, r.keys[r.currentID]
+}
+
+// lookup 根据 kid 查找校验密钥，未携带 kid 的旧 token 使用当前密钥校验
+func (r *keyRing) lookup(kid string) ([]byte, bool) {
+	r.load()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if kid == "" {
+		kid = r.currentID
+	}
+	secret, ok := r.keys[kid]
+	return secret, ok
+}
+
+// readStoredRing 读取 Redis 中保存的密钥环，未启用 Redis 或尚未轮换过时返回 nil
+func readStoredRing() (*storedRing, error) {
+	if redis.Client == nil {
+		return nil, nil
+	}
+	value, err := redis.Get(keyRingKey)
+	if err != nil {
+		if redis.IsNil(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var stored storedRing
+	if err := json.Unmarshal([]byte(value), &stored); err != nil {
+		return nil, fmt.Errorf("invalid key ring: %w", err)
+	}
+	return &stored, nil
+}
+
+// writeStoredRing 保存密钥环，只保留仍在使用的密钥和仍在配置文件中的已吊销密钥ID
+func writeStoredRing(stored *storedRing) error {
+	cfg := config.AppConfig.JWT
+	current := cfg.KeyID
+	if current == "" {
+		current = defaultKeyID
+	}
+	configured := map[string]bool{current: true}
+	for _, k := range cfg.PreviousKeys {
+		configured[k.ID] = true
+	}
+	revoked := make([]string, 0, len(stored.Revoked))
+	seen := make(map[string]bool)
+	for _, id := range stored.Revoked {
+		if configured[id] && !seen[id] && id != stored.Current {
+			revoked = append(revoked, id)
+			seen[id] = true
+		}
+	}
+	stored.Revoked = revoked
+	if len(stored.Keys) > maxPreviousKeys+1 {
+		stored.Keys = stored.Keys[len(stored.Keys)-maxPreviousKeys-1:]
+	}
+
+	value, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return redis.SetWithExpire(keyRingKey, string(value), 0)
+}
+
+// KeyInfo 签名密钥信息（不包含密钥内容）
+type KeyInfo struct {
+	ID      string `json:"id"`
+	Current bool   `json:"current"`
+}
+
+// RotateKey 轮换签名密钥，返回新密钥的ID
+// 新密钥立即用于签发 token 并保存到 Redis，其他实例在 keyRingSyncInterval 内同步。
+// revokePrevious 为 false 时原密钥降级为旧密钥继续用于校验，已签发的 token 在过期前仍然有效；
+// 为 true 时吊销全部旧密钥，已签发的 token 立即失效（密钥泄露时使用）。secret 为空时自动生成随机密钥。
+func RotateKey(secret string, revokePrevious bool) (string, error) {
+	if redis.Client == nil {
+		return "", ErrKeyStoreUnavailable
+	}
+	if secret == "" {
+		buf := make([]byte, 48)
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("failed to generate secret: %w", err)
+		}
+		secret = base64.StdEncoding.EncodeToString(buf)
+	}
+	if len(secret) < minSecretLength {
+		return "", ErrSecretTooShort
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	stored, err := readStoredRing()
+	if err != nil {
+		return "", err
+	}
+	if stored == nil {
+		stored = &storedRing{}
+	}
+	ring.rebuild(stored)
+
+	kid := time.Now().Format("20060102150405")
+	if _, exists := ring.keys[kid]; exists {
+		return "", ErrRotateTooFrequently
+	}
+	sealed, err := encrypt.Encrypt(secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+
+	if revokePrevious {
+		for id := range ring.keys {
+			stored.Revoked = append(stored.Revoked, id)
+		}
+		stored.Keys = nil
+	}
+	stored.Keys = append(stored.Keys, storedKey{ID: kid, Secret: sealed})
+	stored.Current = kid
+	if err := writeStoredRing(stored); err != nil {
+		return "", err
+	}
+	ring.rebuild(stored)
+	return kid, nil
+}
+
+// RevokeKey 吊销旧密钥，使用该密钥签发的 token 立即失效，当前签名密钥不能吊销
+func RevokeKey(kid string) error {
+	if redis.Client == nil {
+		return ErrKeyStoreUnavailable
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	stored, err := readStoredRing()
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		stored = &storedRing{}
+	}
+	ring.rebuild(stored)
+
+	if kid == ring.currentID {
+		return ErrRevokeCurrentKey
+	}
+	if _, ok := ring.keys[kid]; !ok {
+		return ErrKeyNotFound
+	}
+
+	keys := stored.Keys[:0]
+	for _, k := range stored.Keys {
+		if k.ID != kid {
+			keys = append(keys, k)
+		}
+	}
+	stored.Keys = keys
+	stored.Revoked = append(stored.Revoked, kid)
+	if err := writeStoredRing(stored); err != nil {
+		return err
+	}
+	ring.rebuild(stored)
+	return nil
+}
+
+// ListKeys 列出当前可用的签名密钥
+func ListKeys() []KeyInfo {
+	ring.load()
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	keys := make([]KeyInfo, 0, len(ring.keys))
+	for id := range ring.keys {
+		keys = append(keys, KeyInfo{ID: id, Current: id == ring.currentID})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ID < keys[j].ID
+	})
+	return keys
+}
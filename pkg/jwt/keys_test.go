@@ -0,0 +1,137 @@
+package jwt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+// setupKeyRing 使用内存 Redis 和测试配置重置密钥环
+func setupKeyRing(t *testing.T) {
+	t.Helper()
+	config.AppConfig = &config.Config{JWT: config.JWTConfig{
+		Secret:       strings.Repeat("c", minSecretLength),
+		Expire:       1,
+		KeyID:        "k1",
+		PreviousKeys: []config.JWTKey{{ID: "k0", Secret: strings.Repeat("p", minSecretLength)}},
+	}}
+	mr := miniredis.RunT(t)
+	redis.Client = goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	ring = &keyRing{}
+	t.Cleanup(func() {
+		redis.Client.Close()
+		redis.Client = nil
+		ring = &keyRing{}
+	})
+}
+
+func TestRotateKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		secret         string
+		revokePrevious bool
+		wantErr        error
+		wantOldValid   bool
+	}{
+		{name: "generated secret keeps old keys", wantOldValid: true},
+		{name: "custom secret", secret: strings.Repeat("n", minSecretLength), wantOldValid: true},
+		{name: "revoke previous keys", revokePrevious: true},
+		{name: "secret too short", secret: "short", wantErr: ErrSecretTooShort, wantOldValid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupKeyRing(t)
+			old, err := GenerateToken(1, "alice", "admin")
+			if err != nil {
+				t.Fatalf("GenerateToken: %v", err)
+			}
+
+			kid, err := RotateKey(tt.secret, tt.revokePrevious)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RotateKey err = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := ParseToken(old); (err == nil) != tt.wantOldValid {
+				t.Errorf("old token valid = %v, want %v", err == nil, tt.wantOldValid)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			// 其他实例（或重启后）从 Redis 加载到同一个密钥环
+			ring = &keyRing{}
+			if current, _ := ring.current(); current != kid {
+				t.Errorf("current key after reload = %q, want %q", current, kid)
+			}
+			fresh, err := GenerateToken(1, "alice", "admin")
+			if err != nil {
+				t.Fatalf("GenerateToken: %v", err)
+			}
+			if _, err := ParseToken(fresh); err != nil {
+				t.Errorf("token signed with rotated key is invalid: %v", err)
+			}
+		})
+	}
+}
+
+func TestRotateKeyWithoutRedis(t *testing.T) {
+	config.AppConfig = &config.Config{JWT: config.JWTConfig{Secret: strings.Repeat("c", minSecretLength)}}
+	ring = &keyRing{}
+	t.Cleanup(func() { ring = &keyRing{} })
+
+	if _, err := RotateKey("", false); !errors.Is(err, ErrKeyStoreUnavailable) {
+		t.Fatalf("RotateKey err = %v, want ErrKeyStoreUnavailable", err)
+	}
+	if err := RevokeKey("default"); !errors.Is(err, ErrKeyStoreUnavailable) {
+		t.Fatalf("RevokeKey err = %v, want ErrKeyStoreUnavailable", err)
+	}
+}
+
+func TestRevokeKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		kid     string
+		wantErr error
+	}{
+		{name: "configured previous key", kid: "k0"},
+		{name: "configured key replaced by rotation", kid: "k1"},
+		{name: "current key", kid: "current", wantErr: ErrRevokeCurrentKey},
+		{name: "unknown key", kid: "missing", wantErr: ErrKeyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupKeyRing(t)
+			kid, err := RotateKey("", false)
+			if err != nil {
+				t.Fatalf("RotateKey: %v", err)
+			}
+			target := tt.kid
+			if target == "current" {
+				target = kid
+			}
+
+			err = RevokeKey(target)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RevokeKey err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			// 吊销记录保存在 Redis 中，重新加载后仍然生效
+			ring = &keyRing{}
+			if _, ok := ring.lookup(target); ok {
+				t.Errorf("key %q still valid after revoke", target)
+			}
+			if _, ok := ring.lookup(kid); !ok {
+				t.Errorf("current key %q missing after revoke", kid)
+			}
+		})
+	}
+}
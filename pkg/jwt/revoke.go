@@ -0,0 +1,92 @@
+package jwt
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+// auth:revoked:{用户ID} -> 吊销时间（Unix 秒），此前签发的登录令牌全部失效
+// auth:denied:{jti} -> 被加入黑名单的单个令牌，保留到令牌过期
+// auth:session:{会话ID} -> 被吊销的会话，该会话签发的登录令牌全部失效
+const (
+	revokedKey        = "auth:revoked:%d"
+	deniedKey         = "auth:denied:%s"
+	sessionRevokedKey = "auth:session:%s"
+)
+
+// RevokeUserTokens 吊销用户已签发的全部登录令牌（重置密码、禁用账号等场景），未启用 Redis 时不生效
+// 记录保留到登录令牌的最长有效期之后，届时被吊销的令牌已自然过期
+func RevokeUserTokens(userID uint) error {
+	if redis.Client == nil {
+		return nil
+	}
+	return redis.SetWithExpire(fmt.Sprintf(revokedKey, userID), time.Now().Unix(), revokeTTL())
+}
+
+// DenyToken 将单个登录令牌加入黑名单（如退出登录时的当前令牌），记录保留到令牌过期
+func DenyToken(claims *Claims) error {
+	if redis.Client == nil || claims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
+	}
+	return redis.SetWithExpire(fmt.Sprintf(deniedKey, claims.ID), 1, ttl)
+}
+
+// RevokeSession 吊销会话已签发的全部登录令牌
+func RevokeSession(sessionID string) error {
+	if redis.Client == nil || sessionID == "" {
+		return nil
+	}
+	return redis.SetWithExpire(fmt.Sprintf(sessionRevokedKey, sessionID), time.Now().Unix(), revokeTTL())
+}
+
+// Revoked 登录令牌是否已被吊销：令牌在黑名单中、所属会话已吊销，或签发时间不晚于用户的吊销时间
+// 未启用 Redis 或读取失败时视为未吊销
+func Revoked(claims *Claims) bool {
+	if redis.Client == nil || claims.IssuedAt == nil {
+		return false
+	}
+
+	var keys []string
+	if claims.ID != "" {
+		keys = append(keys, fmt.Sprintf(deniedKey, claims.ID))
+	}
+	if claims.SessionID != "" {
+		keys = append(keys, fmt.Sprintf(sessionRevokedKey, claims.SessionID))
+	}
+	if len(keys) > 0 {
+		if denied, err := redis.ExistsAny(keys...); err == nil && denied {
+			return true
+		}
+	}
+
+	value, err := redis.Get(fmt.Sprintf(revokedKey, claims.AdminID))
+	if err != nil {
+		return false
+	}
+	revokedAt, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false
+	}
+	return claims.IssuedAt.Unix() <= revokedAt
+}
+
+// revokeTTL 登录令牌的最长有效期
+func revokeTTL() time.Duration {
+	cfg := config.AppConfig.JWT
+	ttl := time.Duration(cfg.Expire) * time.Hour
+	if access := time.Duration(cfg.AccessExpire) * time.Minute; access > ttl {
+		ttl = access
+	}
+	if ttl < time.Hour {
+		ttl = time.Hour
+	}
+	return ttl
+}
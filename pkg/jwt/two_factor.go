@@ -0,0 +1,54 @@
+package jwt
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// twoFactorAudience 两步验证令牌的 aud，用于与登录令牌区分
+const twoFactorAudience = "two-factor"
+
+// TwoFactorClaims 两步验证令牌声明，密码校验通过后签发，提交验证码时换取登录令牌
+type TwoFactorClaims struct {
+	UserID uint `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+// GenerateTwoFactorToken 生成两步验证令牌，nonce 用于限制同一令牌的尝试次数
+func GenerateTwoFactorToken(userID uint, nonce string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := TwoFactorClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        nonce,
+			Audience:  jwt.ClaimStrings{twoFactorAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "blog-admin-api",
+		},
+	}
+
+	kid, secret := ring.current()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = kid
+	return token.SignedString(secret)
+}
+
+// ParseTwoFactorToken 解析两步验证令牌
+func ParseTwoFactorToken(tokenString string) (*TwoFactorClaims, error) {
+	claims := &TwoFactorClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		secret, ok := ring.lookup(kid)
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		return secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(twoFactorAudience))
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
@@ -1,7 +1,9 @@
 package logger
 
 import (
+	"context"
 	"io"
+	"net/http"
 	"os"
 	"time"
 
@@ -13,6 +15,14 @@ import (
 
 var Log *logrus.Logger
 
+// RequestIDKey is the gin context key holding the request ID
+const RequestIDKey = "request_id"
+
+// HeaderRequestID is the HTTP header carrying the request ID between services
+const HeaderRequestID = "X-Request-ID"
+
+type requestIDContextKey struct{}
+
 func Init() {
 	Log = logrus.New()
 
@@ -42,6 +52,9 @@ func Init() {
 		})
 	}
 
+	// Redact tokens, passwords, emails and IPs
+	Log.AddHook(&MaskHook{})
+
 	// Set output
 	if config.AppConfig.Log.Output == "file" && config.AppConfig.Log.FilePath != "" {
 		writer := &lumberjack.Logger{
@@ -57,7 +70,8 @@ func Init() {
 	}
 }
 
-// GinLogger returns a gin middleware for logging requests
+// GinLogger returns a gin middleware for structured access logs
+// (request ID, route, status, latency, response size and the signed-in user)
 func GinLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
@@ -68,18 +82,23 @@ func GinLogger() gin.HandlerFunc {
 
 		latency := time.Since(start)
 		statusCode := c.Writer.Status()
-		clientIP := c.ClientIP()
-		method := c.Request.Method
-
-		entry := Log.WithFields(logrus.Fields{
+		fields := logrus.Fields{
+			"request_id": c.GetString(RequestIDKey),
 			"status":     statusCode,
 			"latency":    latency.String(),
-			"client_ip":  clientIP,
-			"method":     method,
+			"latency_ms": float64(latency.Microseconds()) / 1000,
+			"client_ip":  c.ClientIP(),
+			"method":     c.Request.Method,
+			"route":      c.FullPath(),
 			"path":       path,
 			"query":      query,
+			"bytes":      c.Writer.Size(),
 			"user_agent": c.Request.UserAgent(),
-		})
+		}
+		if userID := c.GetUint("user_id"); userID > 0 {
+			fields["user_id"] = userID
+		}
+		entry := Log.WithFields(fields)
 
 		if len(c.Errors) > 0 {
 			entry.Error(c.Errors.String())
@@ -98,15 +117,18 @@ func GinRecovery() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
+				requestID := c.GetString(RequestIDKey)
 				Log.WithFields(logrus.Fields{
-					"error":  err,
-					"path":   c.Request.URL.Path,
-					"method": c.Request.Method,
+					"request_id": requestID,
+					"error":      err,
+					"path":       c.Request.URL.Path,
+					"method":     c.Request.Method,
 				}).Error("Panic recovered")
 
 				c.AbortWithStatusJSON(500, gin.H{
-					"code":    500,
-					"message": "Internal server error",
+					"code":       500,
+					"message":    "Internal server error",
+					"request_id": requestID,
 				})
 			}
 		}()
@@ -137,3 +159,32 @@ func Fatal(args ...interface{}) {
 func WithFields(fields logrus.Fields) *logrus.Entry {
 	return Log.WithFields(fields)
 }
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or "" if none
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// FromContext returns a log entry tagged with the request ID carried by ctx
+func FromContext(ctx context.Context) *logrus.Entry {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		return Log.WithField("request_id", requestID)
+	}
+	return logrus.NewEntry(Log)
+}
+
+// PropagateRequestID sets X-Request-ID on an outbound request from the request ID carried by its context
+func PropagateRequestID(req *http.Request) {
+	if requestID := RequestIDFromContext(req.Context()); requestID != "" {
+		req.Header.Set(HeaderRequestID, requestID)
+	}
+}
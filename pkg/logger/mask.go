@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sensitiveKeys field names whose values are always fully redacted
+var sensitiveKeys = []string{"password", "passwd", "token", "secret", "authorization", "cookie", "api_key", "apikey"}
+
+const redacted = "******"
+
+var (
+	// Bearer tokens and bare JWTs
+	bearerPattern = regexp.MustCompile(`(?i)bearer\s+[a-z0-9\-_.=]+`)
+	jwtPattern    = regexp.MustCompile(`eyJ[a-zA-Z0-9\-_]+\.[a-zA-Z0-9\-_]+\.[a-zA-Z0-9\-_]+`)
+	// key=value / "key":"value" pairs of sensitive keys inside free text or query strings
+	kvPattern    = regexp.MustCompile(`(?i)((?:password|passwd|token|secret|api_key|apikey)["']?\s*[:=]\s*["']?)[^&\s"',}]+`)
+	emailPattern = regexp.MustCompile(`([a-zA-Z0-9._%+\-])[a-zA-Z0-9._%+\-]*@([a-zA-Z0-9.\-]+\.[a-zA-Z]{2,})`)
+	ipv4Pattern  = regexp.MustCompile(`\b(\d{1,3}\.\d{1,3}\.\d{1,3})\.\d{1,3}\b`)
+	ipv6Pattern  = regexp.MustCompile(`\b([0-9a-fA-F]{1,4}:[0-9a-fA-F]{1,4}):(?:[0-9a-fA-F]{0,4}:){1,5}[0-9a-fA-F]{1,4}\b`)
+)
+
+// MaskHook redacts tokens, passwords, emails and full IPs before an entry is written
+type MaskHook struct{}
+
+// Levels applies the hook to every level
+func (h *MaskHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire masks the message and every field of the entry
+func (h *MaskHook) Fire(entry *logrus.Entry) error {
+	entry.Message = Mask(entry.Message)
+	for k, v := range entry.Data {
+		if isSensitiveKey(k) {
+			entry.Data[k] = redacted
+			continue
+		}
+		switch val := v.(type) {
+		case string:
+			entry.Data[k] = Mask(val)
+		case error:
+			entry.Data[k] = Mask(val.Error())
+		case fmt.Stringer:
+			entry.Data[k] = Mask(val.String())
+		}
+	}
+	return nil
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Mask redacts sensitive data inside free text
+//
+//	Bearer eyJhbGciOi...  -> Bearer ******
+//	password=123456       -> password=******
+//	alice@example.com     -> a***@example.com
+//	192.168.1.23          -> 192.168.1.*
+func Mask(s string) string {
+	if s == "" {
+		return s
+	}
+	s = bearerPattern.ReplaceAllString(s, "Bearer "+redacted)
+	s = jwtPattern.ReplaceAllString(s, redacted)
+	s = kvPattern.ReplaceAllString(s, "${1}"+redacted)
+	s = emailPattern.ReplaceAllString(s, "${1}***@${2}")
+	s = ipv4Pattern.ReplaceAllString(s, "${1}.*")
+	s = ipv6Pattern.ReplaceAllString(s, "${1}:*")
+	return s
+}
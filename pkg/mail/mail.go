@@ -0,0 +1,126 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+)
+
+const (
+	defaultPort = 587
+	dialTimeout = 10 * time.Second
+)
+
+// ErrDisabled 未配置 SMTP 服务器
+var ErrDisabled = errors.New("mail is not configured")
+
+// Enabled 是否已配置 SMTP 服务器
+func Enabled() bool {
+	return config.AppConfig.Mail.Host != ""
+}
+
+// Send 发送 HTML 邮件，端口为 465 时使用 SSL，否则服务器支持时使用 STARTTLS
+func Send(to, subject, html string) error {
+	return send(&Message{To: to, Subject: subject, HTML: html})
+}
+
+// send 发送一封邮件，带有请求ID时写入 X-Request-ID 邮件头
+func send(m *Message) error {
+	cfg := config.AppConfig.Mail
+	if cfg.Host == "" {
+		return ErrDisabled
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = defaultPort
+	}
+	from := cfg.From
+	if from == "" {
+		from = cfg.Username
+	}
+
+	msg := buildMessage(mail.Address{Name: cfg.FromName, Address: from}, m)
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	if port == 465 {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: cfg.Host})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+
+	client, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && port != 465 {
+		if err := client.StartTLS(&tls.Config{ServerName: cfg.Host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	if err := client.Rcpt(m.To); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	return client.Quit()
+}
+
+// buildMessage 生成 MIME 邮件，标题按 RFC 2047 编码，正文使用 base64
+func buildMessage(from mail.Address, m *Message) []byte {
+	var b bytes.Buffer
+	b.WriteString("From: " + from.String() + "\r\n")
+	b.WriteString("To: " + m.To + "\r\n")
+	b.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", m.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	if m.RequestID != "" {
+		b.WriteString(logger.HeaderRequestID + ": " + m.RequestID + "\r\n")
+	}
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(m.HTML))
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded + "\r\n")
+	return b.Bytes()
+}
@@ -0,0 +1,126 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = logrus.New()
+	logger.Log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func TestRenderBuiltinTemplates(t *testing.T) {
+	config.AppConfig = &config.Config{Mail: config.MailConfig{FromName: "My Blog"}}
+
+	for _, name := range Templates {
+		subject, body, err := Render(name, Data{"Title": "Weekly"})
+		if err != nil {
+			t.Fatalf("Render(%s): %v", name, err)
+		}
+		if subject == "" || strings.Contains(subject, "\n") {
+			t.Errorf("Render(%s) subject = %q", name, subject)
+		}
+		if !strings.Contains(body, "My Blog") || !strings.HasPrefix(body, "<!DOCTYPE html>") {
+			t.Errorf("Render(%s) body is missing the layout", name)
+		}
+	}
+}
+
+func TestRenderEscapesData(t *testing.T) {
+	config.AppConfig = &config.Config{}
+
+	_, body, err := Render(TemplateCommentReply, Data{
+		"Name":  "<b>alice</b>",
+		"Reply": `<script>alert(1)</script>`,
+		"Link":  "javascript:alert(1)",
+	})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	for _, raw := range []string{"<b>alice</b>", "<script>", `href="javascript:`} {
+		if strings.Contains(body, raw) {
+			t.Errorf("body contains unescaped %q", raw)
+		}
+	}
+}
+
+func TestRenderTemplateDirOverride(t *testing.T) {
+	dir := t.TempDir()
+	custom := `{{define "subject"}}Hi {{.Name}}{{end}}{{define "content"}}custom{{end}}`
+	if err := os.WriteFile(filepath.Join(dir, TemplateTest+".html"), []byte(custom), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config.AppConfig = &config.Config{Mail: config.MailConfig{TemplateDir: dir}}
+
+	subject, body, err := Render(TemplateTest, Data{"Name": "bob"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if subject != "Hi bob" || !strings.Contains(body, "custom") {
+		t.Fatalf("Render = %q, %q, want the custom template inside the built-in layout", subject, body)
+	}
+
+	if _, _, err := Render("../layout", nil); err == nil {
+		t.Fatal("Render accepted a template name with a path")
+	}
+}
+
+func TestQueueRetriesFailedSends(t *testing.T) {
+	config.AppConfig = &config.Config{Mail: config.MailConfig{Host: "smtp.example.com", MaxRetries: 2, RetryDelay: 1}}
+	retryUnit = time.Millisecond
+	defer func() { retryUnit = time.Second }()
+
+	original := sender
+	defer func() { sender = original }()
+	var mu sync.Mutex
+	attempts := 0
+	done := make(chan struct{})
+	sender = func(msg *Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts < 2 {
+			return errors.New("temporary failure")
+		}
+		close(done)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go RunQueue(ctx)
+
+	if err := Enqueue(&Message{To: "a@example.com", Subject: "s", HTML: "h"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not retried")
+	}
+}
+
+func TestBuildMessageRequestID(t *testing.T) {
+	from := mail.Address{Name: "Leaf", Address: "noreply@example.com"}
+	msg := string(buildMessage(from, &Message{To: "a@example.com", Subject: "s", HTML: "h", RequestID: "req-1"}))
+	if !strings.Contains(msg, "\r\nX-Request-ID: req-1\r\n") {
+		t.Fatalf("message = %q, want X-Request-ID header", msg)
+	}
+	if msg := string(buildMessage(from, &Message{To: "a@example.com", Subject: "s", HTML: "h"})); strings.Contains(msg, "X-Request-ID") {
+		t.Fatalf("message = %q, want no X-Request-ID header", msg)
+	}
+}
@@ -0,0 +1,146 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+)
+
+// 发送队列默认值
+const (
+	defaultQueueSize  = 200
+	defaultWorkers    = 2
+	defaultMaxRetries = 3
+	defaultRetryDelay = 30
+)
+
+// ErrQueueFull 发送队列已满
+var ErrQueueFull = errors.New("mail queue is full")
+
+// Message 待发送的邮件
+type Message struct {
+	To        string
+	Subject   string
+	HTML      string
+	RequestID string // 触发发送的请求ID，写入邮件头并附加到发送日志
+
+	attempts int
+}
+
+// log 带请求ID的日志条目，便于与触发发送的请求关联
+func (m *Message) log() *logrus.Entry {
+	return logger.FromContext(logger.WithRequestID(context.Background(), m.RequestID))
+}
+
+// sender 实际发送邮件的函数，retryUnit 为 retry_delay 的单位，测试时替换
+var (
+	sender = func(msg *Message) error {
+		return send(msg)
+	}
+	retryUnit = time.Second
+)
+
+var (
+	queueOnce sync.Once
+	queue     chan *Message
+)
+
+// pending 发送队列，首次使用时按配置创建
+func pending() chan *Message {
+	queueOnce.Do(func() {
+		size := config.AppConfig.Mail.QueueSize
+		if size <= 0 {
+			size = defaultQueueSize
+		}
+		queue = make(chan *Message, size)
+	})
+	return queue
+}
+
+// Enqueue 将邮件放入发送队列，由 RunQueue 启动的发送协程异步发送，失败后按间隔重试
+func Enqueue(msg *Message) error {
+	if !Enabled() {
+		return ErrDisabled
+	}
+	select {
+	case pending() <- msg:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// RunQueue 启动发送协程，ctx 取消后退出，队列中未发送的邮件会丢失
+func RunQueue(ctx context.Context) {
+	workers := config.AppConfig.Mail.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case msg := <-pending():
+					deliver(ctx, msg)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := len(pending()); n > 0 {
+		logger.Warn("Mail queue stopped with ", n, " unsent messages")
+	}
+}
+
+// deliver 发送一封邮件，失败时等待后重新入队，间隔随重试次数翻倍
+func deliver(ctx context.Context, msg *Message) {
+	err := sender(msg)
+	if err == nil {
+		return
+	}
+
+	cfg := config.AppConfig.Mail
+	maxRetries := cfg.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultMaxRetries
+	}
+	msg.attempts++
+	if msg.attempts > maxRetries {
+		msg.log().Error("Failed to send mail to ", msg.To, " after ", msg.attempts, " attempts: ", err)
+		return
+	}
+
+	retryDelay := cfg.RetryDelay
+	if retryDelay <= 0 {
+		retryDelay = defaultRetryDelay
+	}
+	delay := time.Duration(retryDelay) * retryUnit << (msg.attempts - 1)
+	msg.log().Warn("Failed to send mail to ", msg.To, ", retrying in ", delay, ": ", err)
+
+	go func() {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		select {
+		case pending() <- msg:
+		default:
+			msg.log().Error("Mail queue is full, dropping retry to ", msg.To)
+		}
+	}()
+}
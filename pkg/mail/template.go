@@ -0,0 +1,121 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+)
+
+// 邮件模板
+const (
+	TemplateVerifyEmail   = "verify_email"
+	TemplatePasswordReset = "password_reset"
+	TemplateCommentReply  = "comment_reply"
+	TemplateNewsletter    = "newsletter"
+	TemplateLoginAlert    = "login_alert"
+	TemplateTest          = "test"
+)
+
+// Templates 全部内置模板
+var Templates = []string{
+	TemplateVerifyEmail, TemplatePasswordReset, TemplateCommentReply,
+	TemplateNewsletter, TemplateLoginAlert, TemplateTest,
+}
+
+const defaultSiteName = "Leaf Blog"
+
+//go:embed templates/*.html
+var builtin embed.FS
+
+// Data 模板数据，渲染时自动补充 SiteName、SiteURL 和 Year
+type Data map[string]any
+
+// Render 渲染模板，返回标题和 HTML 正文
+// 每个模板定义 subject 和 content 两部分，正文套用 layout.html；配置了 template_dir 时同名文件优先于内置模板
+func Render(name string, data Data) (string, string, error) {
+	tmpl, err := parse(name)
+	if err != nil {
+		return "", "", err
+	}
+
+	values := Data{
+		"SiteName": siteName(),
+		"SiteURL":  strings.TrimRight(config.AppConfig.Sitemap.SiteURL, "/"),
+		"Year":     time.Now().Year(),
+	}
+	for k, v := range data {
+		values[k] = v
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", values); err != nil {
+		return "", "", fmt.Errorf("render %s subject: %w", name, err)
+	}
+	if err := tmpl.ExecuteTemplate(&body, "layout", values); err != nil {
+		return "", "", fmt.Errorf("render %s: %w", name, err)
+	}
+	return strings.TrimSpace(subject.String()), body.String(), nil
+}
+
+// SendTemplate 渲染模板后放入发送队列，邮件携带 ctx 中的请求ID
+func SendTemplate(ctx context.Context, to, name string, data Data) error {
+	subject, html, err := Render(name, data)
+	if err != nil {
+		return err
+	}
+	return Enqueue(&Message{To: to, Subject: subject, HTML: html, RequestID: logger.RequestIDFromContext(ctx)})
+}
+
+// parse 解析布局和模板，自定义模板每次渲染时重新读取，修改后无需重启
+func parse(name string) (*template.Template, error) {
+	if strings.ContainsAny(name, `/\.`) {
+		return nil, fmt.Errorf("invalid mail template %q", name)
+	}
+
+	tmpl := template.New(name)
+	for _, file := range []string{"layout", name} {
+		content, err := readTemplate(file)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tmpl.Parse(string(content)); err != nil {
+			return nil, fmt.Errorf("parse mail template %s: %w", file, err)
+		}
+	}
+	return tmpl, nil
+}
+
+// readTemplate 读取模板文件，自定义目录中没有时使用内置模板
+func readTemplate(file string) ([]byte, error) {
+	if dir := config.AppConfig.Mail.TemplateDir; dir != "" {
+		content, err := os.ReadFile(filepath.Join(dir, file+".html"))
+		if err == nil {
+			return content, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	content, err := builtin.ReadFile("templates/" + file + ".html")
+	if err != nil {
+		return nil, fmt.Errorf("mail template %q not found", file)
+	}
+	return content, nil
+}
+
+// siteName 邮件中显示的站点名称，使用发件人名称
+func siteName() string {
+	if name := config.AppConfig.Mail.FromName; name != "" {
+		return name
+	}
+	return defaultSiteName
+}
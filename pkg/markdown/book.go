@@ -0,0 +1,17 @@
+package markdown
+
+// BookEntry 电子书 / PDF 中的一节，Level 从 1 开始；章节只有标题，文章带正文 HTML
+type BookEntry struct {
+	Title string
+	Level int
+	HTML  string
+}
+
+// Book 按目录组织的文章合集，用于导出 PDF 和 EPUB
+type Book struct {
+	Title    string
+	Subtitle string // 封面副标题，如作者、导出时间
+	Author   string
+	Cover    string // 封面图片地址，可为空
+	Entries  []BookEntry
+}
@@ -0,0 +1,25 @@
+package markdown
+
+import (
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/html"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+// CommentExtensions 评论 Markdown 解析扩展：自动链接、围栏代码块和删除线，单个换行保留为换行
+const CommentExtensions = parser.Autolink | parser.FencedCode | parser.Strikethrough |
+	parser.NoIntraEmphasis | parser.HardLineBreak
+
+// commentFlags 评论渲染选项：跳过原始 HTML 和图片，只输出安全协议的链接
+const commentFlags = html.SkipHTML | html.SkipImages | html.Safelink |
+	html.NofollowLinks | html.NoreferrerLinks | html.HrefTargetBlank
+
+// RenderComment 将评论 Markdown 渲染为 HTML，结果仍需按评论策略清理
+func RenderComment(content string) string {
+	if content == "" {
+		return ""
+	}
+	doc := parser.NewWithExtensions(CommentExtensions).Parse([]byte(content))
+	renderer := html.NewRenderer(html.RendererOptions{Flags: commentFlags})
+	return string(markdown.Render(doc, renderer))
+}
@@ -0,0 +1,81 @@
+package markdown
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+	mdhtml "github.com/gomarkdown/markdown/html"
+)
+
+// diagramLanguages 识别为图表的代码块语言，值为渲染服务中的图表类型
+var diagramLanguages = map[string]string{
+	"mermaid":  "mermaid",
+	"plantuml": "plantuml",
+	"puml":     "plantuml",
+}
+
+// DiagramHook 渲染 mermaid / plantuml 代码块的钩子
+//
+// rendererURL 为空时原样输出源码：<pre class="mermaid">...</pre>，由前端 mermaid.js 等在浏览器中渲染；
+// 配置了 Kroki 兼容的渲染服务（如 https://kroki.io）时输出指向 {rendererURL}/{type}/svg/{编码后的源码} 的图片，
+// 由渲染服务在服务端生成 SVG。以图片引用而不是内联 SVG，避免 SVG 中的脚本绕过 HTML 清理。
+func DiagramHook(rendererURL string) mdhtml.RenderNodeFunc {
+	rendererURL = strings.TrimRight(rendererURL, "/")
+
+	return func(w io.Writer, node ast.Node, entering bool) (ast.WalkStatus, bool) {
+		block, ok := node.(*ast.CodeBlock)
+		if !ok {
+			return ast.GoToNext, false
+		}
+		diagramType, ok := diagramLanguages[codeLanguage(block.Info)]
+		if !ok {
+			return ast.GoToNext, false
+		}
+		if !entering {
+			return ast.GoToNext, true
+		}
+
+		source := string(block.Literal)
+		if rendererURL != "" {
+			if encoded, err := encodeDiagram(source); err == nil {
+				io.WriteString(w, `<div class="diagram diagram-`+diagramType+`"><img src="`+
+					html.EscapeString(rendererURL+"/"+diagramType+"/svg/"+encoded)+
+					`" alt="`+diagramType+` diagram" loading="lazy"></div>`+"\n")
+				return ast.GoToNext, true
+			}
+		}
+
+		io.WriteString(w, `<pre class="`+diagramType+`">`+html.EscapeString(source)+"</pre>\n")
+		return ast.GoToNext, true
+	}
+}
+
+// codeLanguage 取代码块信息串中的语言，如 "mermaid {theme: dark}" -> "mermaid"
+func codeLanguage(info []byte) string {
+	fields := strings.Fields(string(info))
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(fields[0])
+}
+
+// encodeDiagram 按 Kroki 规范编码图表源码：zlib 压缩后 base64url
+func encodeDiagram(source string) (string, error) {
+	var buf bytes.Buffer
+	zw, err := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := zw.Write([]byte(source)); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(buf.Bytes()), nil
+}
@@ -0,0 +1,360 @@
+package markdown
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// epubLanguage 电子书语言
+const epubLanguage = "zh-CN"
+
+// epubItem content.opf 中的清单条目
+type epubItem struct {
+	ID         string
+	Href       string // 相对 OEBPS/ 的路径
+	MediaType  string
+	Properties string
+}
+
+// EPUBExporter 将文章合集导出为 EPUB 3 电子书，图片打包进电子书
+type EPUBExporter struct {
+	images   *ArticleExporter  // 复用 ZIP 导出的图片读取与下载
+	hrefs    map[string]string // 图片地址 -> 电子书内路径，获取失败时为空
+	assets   map[string][]byte // 电子书内路径 -> 图片数据
+	manifest []epubItem
+}
+
+// NewEPUBExporter 创建 EPUB 导出器
+func NewEPUBExporter() *EPUBExporter {
+	return &EPUBExporter{
+		images: NewArticleExporter(),
+		hrefs:  make(map[string]string),
+		assets: make(map[string][]byte),
+	}
+}
+
+// Export 生成 EPUB：封面页、导航文档（按层级嵌套的目录）、每个条目一个 XHTML 文件
+func (e *EPUBExporter) Export(book *Book) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(buf)
+
+	// mimetype 必须是第一个文件且不压缩
+	w, err := zipWriter.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write([]byte("application/epub+zip")); err != nil {
+		return nil, err
+	}
+
+	files := map[string]string{
+		"META-INF/container.xml": epubContainer,
+		"OEBPS/style.css":        epubStyle,
+	}
+	order := []string{"META-INF/container.xml", "OEBPS/style.css"}
+	addFile := func(item epubItem, content string) {
+		e.manifest = append(e.manifest, item)
+		files["OEBPS/"+item.Href] = content
+		order = append(order, "OEBPS/"+item.Href)
+	}
+
+	e.manifest = append(e.manifest, epubItem{ID: "style", Href: "style.css", MediaType: "text/css"})
+	addFile(epubItem{ID: "cover", Href: "cover.xhtml", MediaType: "application/xhtml+xml"}, e.coverPage(book))
+	addFile(epubItem{ID: "nav", Href: "nav.xhtml", MediaType: "application/xhtml+xml", Properties: "nav"}, e.navDocument(book))
+
+	spine := []string{"cover", "nav"}
+	for i, entry := range book.Entries {
+		content, err := e.entryPage(entry)
+		if err != nil {
+			return nil, fmt.Errorf("转换 %s 失败: %w", entry.Title, err)
+		}
+		id := fmt.Sprintf("entry-%d", i+1)
+		addFile(epubItem{ID: id, Href: "text/" + id + ".xhtml", MediaType: "application/xhtml+xml"}, content)
+		spine = append(spine, id)
+	}
+
+	for _, name := range order {
+		if err := e.images.addFileToZip(zipWriter, name, []byte(files[name])); err != nil {
+			return nil, err
+		}
+	}
+	for _, item := range e.manifest {
+		data, ok := e.assets[item.Href]
+		if !ok {
+			continue
+		}
+		if err := e.images.addFileToZip(zipWriter, "OEBPS/"+item.Href, data); err != nil {
+			return nil, err
+		}
+	}
+	if err := e.images.addFileToZip(zipWriter, "OEBPS/content.opf", []byte(e.packageDocument(book, spine))); err != nil {
+		return nil, err
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return nil, fmt.Errorf("关闭ZIP文件失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// coverPage 封面页：封面图片、标题、副标题
+func (e *EPUBExporter) coverPage(book *Book) string {
+	var b strings.Builder
+	b.WriteString("<section epub:type=\"cover\" class=\"cover\">\n")
+	if book.Cover != "" {
+		if href := e.addImage(book.Cover, "cover-image"); href != "" {
+			fmt.Fprintf(&b, "<img class=\"cover-image\" src=\"%s\" alt=\"%s\"/>\n", html.EscapeString(href), html.EscapeString(book.Title))
+		}
+	}
+	fmt.Fprintf(&b, "<h1 class=\"cover-title\">%s</h1>\n", html.EscapeString(book.Title))
+	if book.Subtitle != "" {
+		fmt.Fprintf(&b, "<p class=\"cover-subtitle\">%s</p>\n", html.EscapeString(book.Subtitle))
+	}
+	b.WriteString("</section>\n")
+	return xhtmlPage(book.Title, "style.css", b.String())
+}
+
+// navDocument 导航文档，目录按条目层级嵌套；层级跳跃（如 1 直接到 3）时按上一级加 1 处理
+func (e *EPUBExporter) navDocument(book *Book) string {
+	var b strings.Builder
+	b.WriteString("<nav epub:type=\"toc\" id=\"toc\">\n<h1>目录</h1>\n")
+	depth := 0
+	for i, entry := range book.Entries {
+		level := entry.Level
+		if level < 1 {
+			level = 1
+		}
+		if level > depth+1 {
+			level = depth + 1
+		}
+		if level > depth {
+			b.WriteString("<ol>\n")
+			depth = level
+		} else {
+			b.WriteString("</li>\n")
+			for ; depth > level; depth-- {
+				b.WriteString("</ol>\n</li>\n")
+			}
+		}
+		fmt.Fprintf(&b, "<li><a href=\"text/entry-%d.xhtml\">%s</a>\n", i+1, html.EscapeString(entry.Title))
+	}
+	if depth > 0 {
+		b.WriteString("</li>\n")
+		for ; depth > 1; depth-- {
+			b.WriteString("</ol>\n</li>\n")
+		}
+		b.WriteString("</ol>\n")
+	}
+	b.WriteString("</nav>\n")
+	return xhtmlPage("目录", "style.css", b.String())
+}
+
+// entryPage 条目页面，正文 HTML 转换为 XHTML 并打包其中的图片
+func (e *EPUBExporter) entryPage(entry BookEntry) (string, error) {
+	level := pdfLevel(entry.Level)
+	var b strings.Builder
+	b.WriteString("<section epub:type=\"chapter\">\n")
+	fmt.Fprintf(&b, "<h%d class=\"entry-title\">%s</h%d>\n", level, html.EscapeString(entry.Title), level)
+	if entry.HTML != "" {
+		body, err := e.toXHTML(entry.HTML)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(body)
+		b.WriteString("\n")
+	}
+	b.WriteString("</section>\n")
+	return xhtmlPage(entry.Title, "../style.css", b.String()), nil
+}
+
+// toXHTML 解析 HTML 片段并按 XHTML 输出（空元素自闭合、属性加引号），图片改为电子书内路径
+func (e *EPUBExporter) toXHTML(content string) (string, error) {
+	context := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(content), context)
+	if err != nil {
+		return "", err
+	}
+
+	container := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, node := range nodes {
+		container.AppendChild(node)
+	}
+	e.rewriteImages(container)
+
+	var b strings.Builder
+	for node := container.FirstChild; node != nil; node = node.NextSibling {
+		if err := html.Render(&b, node); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
+
+// rewriteImages 将图片地址改为电子书内路径，无法获取的图片替换为替代文字
+func (e *EPUBExporter) rewriteImages(node *html.Node) {
+	for child := node.FirstChild; child != nil; {
+		next := child.NextSibling
+		if child.Type == html.ElementNode && child.DataAtom == atom.Img {
+			src, alt := htmlAttr(child, "src"), htmlAttr(child, "alt")
+			if href := e.addImage(src, ""); href != "" {
+				setHTMLAttr(child, "src", "../"+href)
+				setHTMLAttr(child, "alt", alt) // EPUB 要求 img 带 alt
+			} else {
+				node.InsertBefore(&html.Node{Type: html.TextNode, Data: alt}, child)
+				node.RemoveChild(child)
+			}
+		} else {
+			e.rewriteImages(child)
+		}
+		child = next
+	}
+}
+
+// addImage 获取图片并加入清单，返回相对 OEBPS/ 的路径，获取失败返回空
+func (e *EPUBExporter) addImage(src, properties string) string {
+	if href, ok := e.hrefs[src]; ok {
+		return href
+	}
+	e.hrefs[src] = ""
+
+	var data []byte
+	var filename string
+	var err error
+	switch {
+	case strings.HasPrefix(src, "/uploads/"):
+		data, filename, err = e.images.readLocalImage(src)
+	case strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://"):
+		data, filename, err = e.images.downloadImage(src)
+	default:
+		return ""
+	}
+	if err != nil {
+		fmt.Printf("[导出] 获取图片失败: %s - %v\n", src, err)
+		return ""
+	}
+
+	ext := strings.ToLower(path.Ext(filename))
+	mediaType := mime.TypeByExtension(ext)
+	if !strings.HasPrefix(mediaType, "image/") {
+		mediaType = http.DetectContentType(data)
+	}
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	if !strings.HasPrefix(mediaType, "image/") {
+		return ""
+	}
+
+	id := fmt.Sprintf("img-%d", len(e.hrefs))
+	href := "images/" + id + ext
+	e.manifest = append(e.manifest, epubItem{ID: id, Href: href, MediaType: mediaType, Properties: properties})
+	e.hrefs[src] = href
+	e.assets[href] = data
+	return href
+}
+
+// packageDocument content.opf：元数据、清单和阅读顺序
+func (e *EPUBExporter) packageDocument(book *Book, spine []string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	fmt.Fprintf(&b, "<package xmlns=\"http://www.idpf.org/2007/opf\" version=\"3.0\" unique-identifier=\"book-id\" xml:lang=\"%s\">\n", epubLanguage)
+	b.WriteString("<metadata xmlns:dc=\"http://purl.org/dc/elements/1.1/\">\n")
+	fmt.Fprintf(&b, "<dc:identifier id=\"book-id\">urn:uuid:%s</dc:identifier>\n", uuid.NewString())
+	fmt.Fprintf(&b, "<dc:title>%s</dc:title>\n", html.EscapeString(book.Title))
+	fmt.Fprintf(&b, "<dc:language>%s</dc:language>\n", epubLanguage)
+	if book.Author != "" {
+		fmt.Fprintf(&b, "<dc:creator>%s</dc:creator>\n", html.EscapeString(book.Author))
+	}
+	if book.Subtitle != "" {
+		fmt.Fprintf(&b, "<dc:description>%s</dc:description>\n", html.EscapeString(book.Subtitle))
+	}
+	fmt.Fprintf(&b, "<meta property=\"dcterms:modified\">%s</meta>\n", time.Now().UTC().Format("2006-01-02T15:04:05Z"))
+	for _, item := range e.manifest {
+		if item.Properties == "cover-image" {
+			// 兼容只识别 EPUB 2 封面声明的阅读器
+			fmt.Fprintf(&b, "<meta name=\"cover\" content=\"%s\"/>\n", item.ID)
+		}
+	}
+	b.WriteString("</metadata>\n<manifest>\n")
+	for _, item := range e.manifest {
+		fmt.Fprintf(&b, "<item id=\"%s\" href=\"%s\" media-type=\"%s\"", item.ID, html.EscapeString(item.Href), item.MediaType)
+		if item.Properties != "" {
+			fmt.Fprintf(&b, " properties=\"%s\"", item.Properties)
+		}
+		b.WriteString("/>\n")
+	}
+	b.WriteString("</manifest>\n<spine>\n")
+	for _, id := range spine {
+		fmt.Fprintf(&b, "<itemref idref=\"%s\"/>\n", id)
+	}
+	b.WriteString("</spine>\n</package>\n")
+	return b.String()
+}
+
+// xhtmlPage 包装为完整的 XHTML 文档
+func xhtmlPage(title, stylesheet, body string) string {
+	return `<?xml version="1.0" encoding="utf-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="` + epubLanguage + `" lang="` + epubLanguage + `">
+<head>
+<meta charset="utf-8"/>
+<title>` + html.EscapeString(title) + `</title>
+<link rel="stylesheet" type="text/css" href="` + stylesheet + `"/>
+</head>
+<body>
+` + body + `</body>
+</html>
+`
+}
+
+// htmlAttr 读取节点属性
+func htmlAttr(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// setHTMLAttr 设置节点属性
+func setHTMLAttr(node *html.Node, key, val string) {
+	for i, attr := range node.Attr {
+		if attr.Key == key {
+			node.Attr[i].Val = val
+			return
+		}
+	}
+	node.Attr = append(node.Attr, html.Attribute{Key: key, Val: val})
+}
+
+// epubContainer META-INF/container.xml，指向 content.opf
+const epubContainer = `<?xml version="1.0" encoding="utf-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+<rootfiles>
+<rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+</rootfiles>
+</container>
+`
+
+// epubStyle 电子书样式
+const epubStyle = `body { font-family: serif; line-height: 1.7; }
+.cover { text-align: center; margin-top: 20%; }
+.cover-image { max-width: 100%; max-height: 60%; }
+.cover-subtitle { color: #666; }
+nav ol { list-style: none; padding-left: 1.5em; }
+img { max-width: 100%; }
+pre { white-space: pre-wrap; word-wrap: break-word; font-size: 0.85em; background: #f6f8fa; padding: 0.8em; }
+code { font-family: monospace; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ddd; padding: 0.2em 0.5em; }
+blockquote { border-left: 4px solid #ddd; margin: 0; padding-left: 1em; color: #555; }
+`
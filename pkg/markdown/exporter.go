@@ -2,104 +2,199 @@ package markdown
 
 import (
 	"archive/zip"
-	"bytes"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ydcloud-dy/leaf-api/internal/model/po"
+	"github.com/ydcloud-dy/leaf-api/pkg/oss"
 )
 
+// ExportProfile 导出格式
+type ExportProfile string
+
+const (
+	ExportProfileDefault ExportProfile = ""     // 本项目格式：根目录 Markdown 加 images/，可重新导入
+	ExportProfileHugo    ExportProfile = "hugo" // Hugo：content/posts/ 加 static/images/
+	ExportProfileHexo    ExportProfile = "hexo" // Hexo：source/_posts/（草稿在 source/_drafts/）加 source/images/
+)
+
+// defaultPermalink 默认的文章固定链接格式，与前台地址一致
+const defaultPermalink = "/article/{slug}"
+
 // ArticleExporter 文章导出器
-type ArticleExporter struct{}
+type ArticleExporter struct {
+	profile   ExportProfile
+	permalink string // 固定链接格式，占位符 {slug} {id}
+}
 
 // NewArticleExporter 创建文章导出器
 func NewArticleExporter() *ArticleExporter {
-	return &ArticleExporter{}
+	return &ArticleExporter{permalink: defaultPermalink}
 }
 
-// ExportToZip 导出文章为 ZIP 文件
-func (e *ArticleExporter) ExportToZip(articles []*po.Article) ([]byte, error) {
-	buf := new(bytes.Buffer)
-	zipWriter := zip.NewWriter(buf)
-
-	// 记录已下载的图片，避免重复下载
-	downloadedImages := make(map[string]string) // 原始URL -> 文件名
+// WithProfile 按 Hugo / Hexo 的 Front Matter 约定和目录结构导出
+// permalink 为文章固定链接格式（占位符 {slug} {id}），为空时使用 /article/{slug}，迁移后保持原有地址不变
+func (e *ArticleExporter) WithProfile(profile ExportProfile, permalink string) *ArticleExporter {
+	e.profile = profile
+	if permalink != "" {
+		e.permalink = permalink
+	}
+	return e
+}
 
+// ExportToZip 导出文章为 ZIP 文件，直接写入 w（如 HTTP 响应），不在内存中缓存整个压缩包
+func (e *ArticleExporter) ExportToZip(w io.Writer, articles []*po.Article) error {
+	stream := e.NewZipStream(w)
 	for _, article := range articles {
-		// 生成 markdown 内容（包含 Front Matter）
-		markdownContent := e.generateMarkdownWithFrontMatter(article)
-
-		// 提取并处理图片
-		processedMarkdown, imageInfos := e.extractImages(markdownContent)
-
-		// 下载图片并替换链接
-		for _, imgInfo := range imageInfos {
-			// 检查是否已经下载过
-			if filename, exists := downloadedImages[imgInfo.OriginalURL]; exists {
-				// 替换占位符为已下载的文件名
-				newPattern := fmt.Sprintf("![%s](./images/%s)", imgInfo.Alt, filename)
-				processedMarkdown = strings.ReplaceAll(processedMarkdown, imgInfo.Placeholder, newPattern)
-				continue
-			}
+		if err := stream.Add(article); err != nil {
+			return err
+		}
+	}
+	return stream.Close()
+}
 
-			// 根据类型获取图片
-			var imageData []byte
-			var filename string
-			var err error
+// ZipStream 逐篇写入的 ZIP 导出，每篇文章写完后刷新到底层 writer
+type ZipStream struct {
+	exporter  *ArticleExporter
+	zipWriter *zip.Writer
+	w         io.Writer
+	// 记录已写入的图片，避免重复下载
+	downloadedImages map[string]string // 原始URL -> 文件名
+	imageErrors      ImageErrors       // 获取失败的图片
+}
 
-			if imgInfo.Type == "local" {
-				imageData, filename, err = e.readLocalImage(imgInfo.OriginalURL)
-			} else {
-				imageData, filename, err = e.downloadImage(imgInfo.OriginalURL)
-			}
+// exportImage 获取到的图片
+type exportImage struct {
+	data     []byte
+	filename string
+	ok       bool
+}
 
-			if err != nil {
-				fmt.Printf("[导出] 获取图片失败: %s - %v\n", imgInfo.OriginalURL, err)
-				// 替换为原始链接
-				newPattern := fmt.Sprintf("![%s](%s)", imgInfo.Alt, imgInfo.OriginalURL)
-				processedMarkdown = strings.ReplaceAll(processedMarkdown, imgInfo.Placeholder, newPattern)
-				continue
-			}
+// NewZipStream 创建流式 ZIP 导出，调用方负责在写完后调用 Close
+func (e *ArticleExporter) NewZipStream(w io.Writer) *ZipStream {
+	return &ZipStream{
+		exporter:         e,
+		zipWriter:        zip.NewWriter(w),
+		w:                w,
+		downloadedImages: make(map[string]string),
+	}
+}
 
-			// 保存图片到 ZIP
-			if err := e.addFileToZip(zipWriter, "images/"+filename, imageData); err != nil {
-				fmt.Printf("[导出] 添加图片到 ZIP 失败: %s - %v\n", filename, err)
-				continue
-			}
+// Add 写入一篇文章及其图片，写入失败（如客户端断开）时返回错误，调用方应停止导出
+func (s *ZipStream) Add(article *po.Article) error {
+	e := s.exporter
 
-			downloadedImages[imgInfo.OriginalURL] = filename
+	// 生成 markdown 内容（包含 Front Matter）
+	markdownContent := e.generateMarkdownWithFrontMatter(article)
 
-			// 替换占位符为实际文件名
-			newPattern := fmt.Sprintf("![%s](./images/%s)", imgInfo.Alt, filename)
-			processedMarkdown = strings.ReplaceAll(processedMarkdown, imgInfo.Placeholder, newPattern)
-		}
+	// 提取并处理图片
+	processedMarkdown, imageInfos := e.extractImages(markdownContent)
 
-		// 生成文件名：article-{id}-{title}.md
-		filename := e.generateFilename(article)
+	// 并发获取本篇新出现的图片，已写入压缩包的图片直接引用
+	var pending []ImageInfo
+	for _, imgInfo := range imageInfos {
+		if _, exists := s.downloadedImages[imgInfo.OriginalURL]; !exists {
+			pending = append(pending, imgInfo)
+		}
+	}
+	urls := make([]string, len(pending))
+	for i, imgInfo := range pending {
+		urls[i] = imgInfo.OriginalURL
+	}
+	images := make([]exportImage, len(pending))
+	failed := fetchImages(urls, func(i int, url string) error {
+		var data []byte
+		var filename string
+		var err error
+		if pending[i].Type == "local" {
+			data, filename, err = e.readLocalImage(url)
+		} else {
+			data, filename, err = e.downloadImage(url)
+		}
+		if err != nil {
+			return err
+		}
+		images[i] = exportImage{data: data, filename: filename, ok: true}
+		return nil
+	})
+	if len(failed) > 0 {
+		// 获取失败的图片保留原始链接
+		fmt.Printf("[导出] %v\n", failed)
+		s.imageErrors = append(s.imageErrors, failed...)
+	}
 
-		// 添加 markdown 文件到 ZIP
-		if err := e.addFileToZip(zipWriter, filename, []byte(processedMarkdown)); err != nil {
-			fmt.Printf("[导出] 添加文章文件到 ZIP 失败: %s - %v\n", filename, err)
+	// 按文章中的顺序写入 ZIP，保证文件名冲突时的结果稳定
+	for i, imgInfo := range pending {
+		if !images[i].ok {
 			continue
 		}
+		if err := e.addFileToZip(s.zipWriter, e.imageDir()+images[i].filename, images[i].data); err != nil {
+			return fmt.Errorf("写入图片 %s 失败: %w", images[i].filename, err)
+		}
+		s.downloadedImages[imgInfo.OriginalURL] = images[i].filename
 	}
 
-	// 必须在返回之前关闭 zipWriter，否则 ZIP 文件不完整
-	if err := zipWriter.Close(); err != nil {
-		return nil, fmt.Errorf("关闭ZIP文件失败: %w", err)
+	// 替换占位符为图片文件名
+	for _, imgInfo := range imageInfos {
+		newPattern := fmt.Sprintf("![%s](%s)", imgInfo.Alt, imgInfo.OriginalURL)
+		if filename, exists := s.downloadedImages[imgInfo.OriginalURL]; exists {
+			newPattern = fmt.Sprintf("![%s](%s%s)", imgInfo.Alt, e.imageLinkPrefix(), filename)
+		}
+		processedMarkdown = strings.ReplaceAll(processedMarkdown, imgInfo.Placeholder, newPattern)
 	}
 
-	return buf.Bytes(), nil
+	// 生成文件名：默认为 {id}-{title}.md，Hugo / Hexo 按各自目录结构以 slug 命名
+	filename := e.documentPath(article)
+
+	// 添加 markdown 文件到 ZIP
+	if err := e.addFileToZip(s.zipWriter, filename, []byte(processedMarkdown)); err != nil {
+		return fmt.Errorf("写入文章 %s 失败: %w", filename, err)
+	}
+
+	return s.flush()
+}
+
+// Close 写入 ZIP 目录并刷新，不关闭底层 writer
+func (s *ZipStream) Close() error {
+	if err := s.zipWriter.Close(); err != nil {
+		return fmt.Errorf("关闭ZIP文件失败: %w", err)
+	}
+	return s.flush()
+}
+
+// ImageErrors 返回已写入的文章中获取失败的图片，这些图片在导出的 Markdown 中保留原始链接
+func (s *ZipStream) ImageErrors() ImageErrors {
+	return s.imageErrors
+}
+
+// flush 将已压缩的数据推送到底层 writer，HTTP 响应同时推送给客户端
+func (s *ZipStream) flush() error {
+	if err := s.zipWriter.Flush(); err != nil {
+		return err
+	}
+	if flusher, ok := s.w.(interface{ Flush() }); ok {
+		flusher.Flush()
+	}
+	return nil
 }
 
 // generateMarkdownWithFrontMatter 生成带 Front Matter 的 Markdown
 func (e *ArticleExporter) generateMarkdownWithFrontMatter(article *po.Article) string {
+	switch e.profile {
+	case ExportProfileHugo:
+		return e.hugoFrontMatter(article) + article.ContentMarkdown
+	case ExportProfileHexo:
+		return e.hexoFrontMatter(article) + article.ContentMarkdown
+	}
+
 	// 生成 YAML Front Matter
 	frontMatter := fmt.Sprintf(`---
 title: %s
@@ -204,11 +299,8 @@ func (e *ArticleExporter) extractImages(markdown string) (string, []ImageInfo) {
 
 // downloadImage 下载图片
 func (e *ArticleExporter) downloadImage(url string) ([]byte, string, error) {
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
-	req, err := http.NewRequest("GET", url, nil)
+	// 私有 bucket 中的图片需要签名才能下载
+	req, err := http.NewRequest("GET", oss.SignURL(url), nil)
 	if err != nil {
 		return nil, "", err
 	}
@@ -217,7 +309,7 @@ func (e *ArticleExporter) downloadImage(url string) ([]byte, string, error) {
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
 	req.Header.Set("Referer", "https://www.google.com/")
 
-	resp, err := client.Do(req)
+	resp, err := imageHTTPClient.Do(req)
 	if err != nil {
 		// 尝试使用图片代理（对于语雀等防盗链的图片）
 		if strings.Contains(url, "cdn.nlark.com") || strings.Contains(url, "yuque.com") {
@@ -228,8 +320,8 @@ func (e *ArticleExporter) downloadImage(url string) ([]byte, string, error) {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return nil, "", fmt.Errorf("下载失败: HTTP %d", resp.StatusCode)
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", &httpStatusError{Code: resp.StatusCode}
 	}
 
 	// 读取图片数据
@@ -331,3 +423,102 @@ func (e *ArticleExporter) escapeYAMLValue(value string) string {
 	}
 	return value
 }
+
+// hugoFrontMatter 生成 Hugo 约定的 Front Matter
+func (e *ArticleExporter) hugoFrontMatter(article *po.Article) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "title: %s\n", e.escapeYAMLValue(article.Title))
+	fmt.Fprintf(&b, "slug: %s\n", e.escapeYAMLValue(e.articleSlug(article)))
+	fmt.Fprintf(&b, "url: %s\n", e.escapeYAMLValue(e.articlePermalink(article)))
+	fmt.Fprintf(&b, "date: %s\n", article.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "lastmod: %s\n", article.UpdatedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "draft: %t\n", article.Status != 1)
+	e.writeCommonFrontMatter(&b, article, "summary")
+	b.WriteString("---\n\n")
+	return b.String()
+}
+
+// hexoFrontMatter 生成 Hexo 约定的 Front Matter
+func (e *ArticleExporter) hexoFrontMatter(article *po.Article) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "title: %s\n", e.escapeYAMLValue(article.Title))
+	fmt.Fprintf(&b, "permalink: %s\n", e.escapeYAMLValue(strings.TrimSuffix(e.articlePermalink(article), "/")+"/"))
+	fmt.Fprintf(&b, "date: %s\n", article.CreatedAt.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&b, "updated: %s\n", article.UpdatedAt.Format("2006-01-02 15:04:05"))
+	if article.Status != 1 {
+		b.WriteString("published: false\n")
+	}
+	e.writeCommonFrontMatter(&b, article, "description")
+	b.WriteString("---\n\n")
+	return b.String()
+}
+
+// writeCommonFrontMatter 写入作者、摘要、封面、分类数组和标签数组
+func (e *ArticleExporter) writeCommonFrontMatter(b *strings.Builder, article *po.Article, summaryKey string) {
+	if article.Author.Nickname != "" {
+		fmt.Fprintf(b, "author: %s\n", e.escapeYAMLValue(article.Author.Nickname))
+	}
+	if summary := strings.Join(strings.Fields(article.Summary), " "); summary != "" {
+		fmt.Fprintf(b, "%s: %s\n", summaryKey, e.escapeYAMLValue(summary))
+	}
+	if article.Cover != "" {
+		fmt.Fprintf(b, "cover: %s\n", e.escapeYAMLValue(article.Cover))
+	}
+	if article.Category.Name != "" {
+		fmt.Fprintf(b, "categories: [%s]\n", e.escapeYAMLValue(article.Category.Name))
+	}
+	tags := make([]string, 0, len(article.Tags))
+	for _, tag := range article.Tags {
+		tags = append(tags, e.escapeYAMLValue(tag.Name))
+	}
+	fmt.Fprintf(b, "tags: [%s]\n", strings.Join(tags, ", "))
+}
+
+// documentPath 文章在 ZIP 中的路径
+func (e *ArticleExporter) documentPath(article *po.Article) string {
+	switch e.profile {
+	case ExportProfileHugo:
+		return "content/posts/" + e.articleSlug(article) + ".md"
+	case ExportProfileHexo:
+		if article.Status != 1 {
+			return "source/_drafts/" + e.articleSlug(article) + ".md"
+		}
+		return "source/_posts/" + e.articleSlug(article) + ".md"
+	}
+	return e.generateFilename(article)
+}
+
+// imageDir 图片在 ZIP 中的目录
+func (e *ArticleExporter) imageDir() string {
+	switch e.profile {
+	case ExportProfileHugo:
+		return "static/images/"
+	case ExportProfileHexo:
+		return "source/images/"
+	}
+	return "images/"
+}
+
+// imageLinkPrefix 正文中图片链接的前缀，Hugo / Hexo 的静态目录发布在站点根路径下
+func (e *ArticleExporter) imageLinkPrefix() string {
+	if e.profile == ExportProfileHugo || e.profile == ExportProfileHexo {
+		return "/images/"
+	}
+	return "./images/"
+}
+
+// articleSlug 文章 slug，历史文章没有 slug 时使用 ID
+func (e *ArticleExporter) articleSlug(article *po.Article) string {
+	if article.Slug != nil && *article.Slug != "" {
+		return *article.Slug
+	}
+	return strconv.FormatUint(uint64(article.ID), 10)
+}
+
+// articlePermalink 按固定链接格式生成文章地址
+func (e *ArticleExporter) articlePermalink(article *po.Article) string {
+	link := strings.ReplaceAll(e.permalink, "{slug}", url.PathEscape(e.articleSlug(article)))
+	return strings.ReplaceAll(link, "{id}", strconv.FormatUint(uint64(article.ID), 10))
+}
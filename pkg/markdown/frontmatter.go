@@ -0,0 +1,182 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// frontMatterTimeLayouts Front Matter 中支持的时间格式，不带时区的按本地时间解析（与导出一致）
+var frontMatterTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// FrontMatter 导入时识别的 Front Matter 字段
+// 兼容本项目导出的格式，以及 Hugo / Hexo 常用的 date、lastmod、updated、categories、draft 等字段
+type FrontMatter struct {
+	Title     string
+	Slug      string
+	Summary   string
+	Cover     string
+	Category  string
+	Tags      []string
+	CreatedAt *time.Time
+	UpdatedAt *time.Time
+	Status    *int // 0: draft, 1: published, 2: offline
+}
+
+// rawFrontMatter YAML 原始字段
+type rawFrontMatter struct {
+	Title       string     `yaml:"title"`
+	Slug        string     `yaml:"slug"`
+	Summary     string     `yaml:"summary"`
+	Description string     `yaml:"description"`
+	Cover       string     `yaml:"cover"`
+	Category    stringList `yaml:"category"`
+	Categories  stringList `yaml:"categories"`
+	Tags        stringList `yaml:"tags"`
+	CreatedAt   string     `yaml:"created_at"`
+	Date        string     `yaml:"date"`
+	UpdatedAt   string     `yaml:"updated_at"`
+	Updated     string     `yaml:"updated"`
+	LastMod     string     `yaml:"lastmod"`
+	Status      *int       `yaml:"status"`
+	Draft       *bool      `yaml:"draft"`
+	Published   *bool      `yaml:"published"`
+}
+
+// stringList 兼容单个字符串和（嵌套的）字符串列表，如 tags: go 与 tags: [go, web]
+type stringList []string
+
+// UnmarshalYAML 展开标量和序列
+func (l *stringList) UnmarshalYAML(node *yaml.Node) error {
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		switch n.Kind {
+		case yaml.ScalarNode:
+			if v := strings.TrimSpace(n.Value); v != "" {
+				*l = append(*l, v)
+			}
+		case yaml.SequenceNode:
+			for _, child := range n.Content {
+				walk(child)
+			}
+		}
+	}
+	walk(node)
+	return nil
+}
+
+// ParseFrontMatter 拆分 YAML Front Matter 与正文
+// 内容不以 --- 开头时返回 nil 和原内容；Front Matter 格式错误时返回错误
+func ParseFrontMatter(content string) (*FrontMatter, string, error) {
+	content = strings.TrimPrefix(content, "\ufeff")
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return nil, content, nil
+	}
+
+	// 查找结束分隔符（--- 或 ...）
+	rest := normalized[len("---\n"):]
+	var header, body string
+	found := false
+	for offset := 0; offset <= len(rest); {
+		end := strings.IndexByte(rest[offset:], '\n')
+		line := rest[offset:]
+		next := len(rest)
+		if end >= 0 {
+			line = rest[offset : offset+end]
+			next = offset + end + 1
+		}
+		if trimmed := strings.TrimRight(line, " \t"); trimmed == "---" || trimmed == "..." {
+			header = rest[:offset]
+			body = rest[next:]
+			found = true
+			break
+		}
+		if end < 0 {
+			break
+		}
+		offset = next
+	}
+	if !found {
+		return nil, content, nil
+	}
+
+	var raw rawFrontMatter
+	if err := yaml.Unmarshal([]byte(header), &raw); err != nil {
+		return nil, content, fmt.Errorf("front matter 格式错误: %w", err)
+	}
+
+	fm := &FrontMatter{
+		Title:   strings.TrimSpace(raw.Title),
+		Slug:    strings.TrimSpace(raw.Slug),
+		Summary: strings.TrimSpace(firstNonEmpty(raw.Summary, raw.Description)),
+		Cover:   strings.TrimSpace(raw.Cover),
+		Tags:    raw.Tags,
+	}
+	if categories := append(raw.Category, raw.Categories...); len(categories) > 0 {
+		fm.Category = categories[0]
+	}
+
+	var err error
+	if fm.CreatedAt, err = parseFrontMatterTime(firstNonEmpty(raw.CreatedAt, raw.Date)); err != nil {
+		return nil, content, err
+	}
+	if fm.UpdatedAt, err = parseFrontMatterTime(firstNonEmpty(raw.UpdatedAt, raw.Updated, raw.LastMod)); err != nil {
+		return nil, content, err
+	}
+
+	switch {
+	case raw.Status != nil:
+		if *raw.Status < 0 || *raw.Status > 2 {
+			return nil, content, fmt.Errorf("front matter status 无效: %d", *raw.Status)
+		}
+		fm.Status = raw.Status
+	case raw.Draft != nil:
+		fm.Status = publishStatus(!*raw.Draft)
+	case raw.Published != nil:
+		fm.Status = publishStatus(*raw.Published)
+	}
+
+	return fm, strings.TrimLeft(body, "\n"), nil
+}
+
+// parseFrontMatterTime 解析时间，为空时返回 nil
+func parseFrontMatterTime(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range frontMatterTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("front matter 时间格式无效: %s", value)
+}
+
+// publishStatus 发布为 1，否则为草稿 0
+func publishStatus(published bool) *int {
+	status := 0
+	if published {
+		status = 1
+	}
+	return &status
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
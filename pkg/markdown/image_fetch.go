@@ -0,0 +1,128 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 图片下载默认参数
+const (
+	defaultImageConcurrency = 4
+	defaultImageRetries     = 2
+	imageFetchTimeout       = 30 * time.Second
+	imageRetryDelay         = 500 * time.Millisecond
+)
+
+var (
+	imageConcurrency = defaultImageConcurrency
+	imageRetries     = defaultImageRetries
+
+	// imageHTTPClient 下载图片共用的客户端，复用连接
+	imageHTTPClient = &http.Client{Timeout: imageFetchTimeout}
+)
+
+// SetImageFetchOptions 设置同时下载的图片数和每张图片失败后的重试次数，启动时根据配置调用
+// concurrency 小于 1 时使用默认值 4，retries 小于 0 时使用默认值 2
+func SetImageFetchOptions(concurrency, retries int) {
+	if concurrency < 1 {
+		concurrency = defaultImageConcurrency
+	}
+	if retries < 0 {
+		retries = defaultImageRetries
+	}
+	imageConcurrency = concurrency
+	imageRetries = retries
+}
+
+// ImageError 单张图片处理失败
+type ImageError struct {
+	URL string
+	Err error
+}
+
+func (e *ImageError) Error() string {
+	return fmt.Sprintf("%s: %v", e.URL, e.Err)
+}
+
+func (e *ImageError) Unwrap() error {
+	return e.Err
+}
+
+// ImageErrors 多张图片处理失败的汇总，失败的图片在结果中保留原地址
+type ImageErrors []*ImageError
+
+func (e ImageErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d 张图片处理失败: %s", len(e), strings.Join(messages, "; "))
+}
+
+// httpStatusError 下载图片时服务器返回非 200 状态码
+type httpStatusError struct {
+	Code int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("HTTP 状态码错误: %d", e.Code)
+}
+
+// fetchImages 以有界并发对每个地址调用 fetch，失败时重试
+// fetch 可能被并发调用，结果需写入按下标区分的位置；返回失败的图片，顺序与 urls 一致
+func fetchImages(urls []string, fetch func(i int, url string) error) ImageErrors {
+	errs := make([]error, len(urls))
+	sem := make(chan struct{}, imageConcurrency)
+	var wg sync.WaitGroup
+	for i, url := range urls {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, url string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = retryImage(func() error {
+				return fetch(i, url)
+			})
+		}(i, url)
+	}
+	wg.Wait()
+
+	var failed ImageErrors
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, &ImageError{URL: urls[i], Err: err})
+		}
+	}
+	return failed
+}
+
+// retryImage 执行 fn，可重试的错误按递增间隔重试
+func retryImage(fn func() error) error {
+	var err error
+	for attempt := 0; attempt <= imageRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * imageRetryDelay)
+		}
+		if err = fn(); err == nil || !retryableImageError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// retryableImageError 文件不存在和 4xx（429 除外）重试也不会成功
+func retryableImageError(err error) bool {
+	if errors.Is(err, os.ErrNotExist) {
+		return false
+	}
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code >= 500 || statusErr.Code == http.StatusTooManyRequests
+	}
+	return true
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
+	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -15,7 +18,10 @@ import (
 
 // ImageProcessor Markdown 图片处理器
 type ImageProcessor struct {
-	folder string // OSS 文件夹名称
+	folder     string            // OSS 文件夹名称
+	copyHosted bool              // 是否复制已托管在 OSS/本地的图片
+	assets     map[string][]byte // 导入包中的图片，ZIP 内路径 -> 数据
+	assetDir   string            // 当前 Markdown 文件在导入包中的目录
 }
 
 // NewImageProcessor 创建图片处理器
@@ -26,8 +32,29 @@ func NewImageProcessor(uploadDir, baseURL string) *ImageProcessor {
 	}
 }
 
+// CopyHosted 已托管在 OSS/本地的图片也重新上传一份
+// 用于克隆文章，避免两篇文章引用同一图片地址，删除或替换其中一篇的图片影响另一篇
+func (p *ImageProcessor) CopyHosted() *ImageProcessor {
+	p.copyHosted = true
+	return p
+}
+
+// WithAssets 使用导入包中的图片解析相对路径（如 ./images/a.png）
+// baseDir 为 Markdown 文件在导入包中的目录，相对路径相对于该目录解析
+func (p *ImageProcessor) WithAssets(assets map[string][]byte, baseDir string) *ImageProcessor {
+	p.assets = assets
+	p.assetDir = baseDir
+	return p
+}
+
+// RehostImage 下载单张图片并重新上传，返回新地址
+func (p *ImageProcessor) RehostImage(url string) (string, error) {
+	return p.downloadAndUploadImage(url)
+}
+
 // ProcessMarkdownImages 处理 Markdown 中的图片
-// 下载所有外部图片并上传到OSS,替换为OSS/本地链接
+// 并发下载所有外部图片并上传到OSS,替换为OSS/本地链接
+// 部分图片失败时返回 ImageErrors，content 中成功的图片已替换，失败的图片保留原地址
 func (p *ImageProcessor) ProcessMarkdownImages(content string) (string, error) {
 	// 匹配 Markdown 图片语法: ![alt](url)
 	imgRegex := regexp.MustCompile(`!\[([^\]]*)\]\(([^)]+)\)`)
@@ -41,58 +68,85 @@ func (p *ImageProcessor) ProcessMarkdownImages(content string) (string, error) {
 
 	fmt.Printf("[图片处理] 找到 %d 个图片链接\n", len(matches))
 
-	// 处理每个图片
+	// 收集需要处理的图片，相同地址只处理一次
+	var urls []string
+	seen := make(map[string]bool)
 	for _, match := range matches {
 		if len(match) < 3 {
 			continue
 		}
-
 		originalURL := match[2]
-		alt := match[1]
 
 		// 跳过已经是OSS或本地图片的情况
-		if strings.HasPrefix(originalURL, "/uploads/") ||
+		if !p.copyHosted && (strings.HasPrefix(originalURL, "/uploads/") ||
 			strings.Contains(originalURL, "oss-cn-") ||
-			strings.Contains(originalURL, "aliyuncs.com") {
+			strings.Contains(originalURL, "aliyuncs.com")) {
 			fmt.Printf("[图片处理] 跳过已处理的图片: %s\n", originalURL)
 			continue
 		}
+		if !seen[originalURL] {
+			seen[originalURL] = true
+			urls = append(urls, originalURL)
+		}
+	}
 
-		fmt.Printf("[图片处理] 开始下载图片: %s\n", originalURL)
-
-		// 下载图片并上传到OSS
-		uploadedURL, err := p.downloadAndUploadImage(originalURL)
+	// 并发下载图片并上传到OSS
+	uploaded := make([]string, len(urls))
+	failed := fetchImages(urls, func(i int, url string) error {
+		uploadedURL, err := p.downloadAndUploadImage(url)
 		if err != nil {
-			fmt.Printf("[图片处理] 处理图片失败 %s: %v\n", originalURL, err)
-			continue
+			return err
 		}
+		uploaded[i] = uploadedURL
+		return nil
+	})
+	replacements := make(map[string]string, len(urls))
+	for i, url := range urls {
+		if uploaded[i] != "" {
+			replacements[url] = uploaded[i]
+		}
+	}
+	fmt.Printf("[图片处理] 成功 %d 张，失败 %d 张\n", len(replacements), len(failed))
 
-		fmt.Printf("[图片处理] 图片上传成功,URL: %s\n", uploadedURL)
-
-		// 替换图片链接
-		oldPattern := fmt.Sprintf("![%s](%s)", alt, originalURL)
-		newPattern := fmt.Sprintf("![%s](%s)", alt, uploadedURL)
+	// 替换图片链接
+	for _, match := range matches {
+		if len(match) < 3 {
+			continue
+		}
+		uploadedURL, ok := replacements[match[2]]
+		if !ok {
+			continue
+		}
+		oldPattern := fmt.Sprintf("![%s](%s)", match[1], match[2])
+		newPattern := fmt.Sprintf("![%s](%s)", match[1], uploadedURL)
 		content = strings.ReplaceAll(content, oldPattern, newPattern)
 	}
 
+	if len(failed) > 0 {
+		return content, failed
+	}
 	return content, nil
 }
 
 // downloadAndUploadImage 下载图片并上传到OSS
 func (p *ImageProcessor) downloadAndUploadImage(url string) (string, error) {
-	// 创建 HTTP 客户端
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+	// 本地存储的图片直接读取文件，其他图片尝试直接下载
+	var imgData []byte
+	var contentType string
+	var err error
+	if data, ok := p.bundledAsset(url); ok {
+		imgData = data
+	} else if strings.HasPrefix(url, "/uploads/") {
+		imgData, err = os.ReadFile("." + url)
+	} else {
+		imgData, contentType, err = p.tryDownload(url)
 	}
-
-	// 尝试直接下载
-	imgData, contentType, err := p.tryDownload(client, url)
 	if err != nil {
 		// 如果是语雀图片且下载失败,尝试使用图片代理
 		if strings.Contains(url, "cdn.nlark.com") || strings.Contains(url, "yuque.com") {
 			fmt.Printf("[图片处理] 直接下载失败,尝试使用图片代理\n")
 			proxyURL := "https://images.weserv.nl/?url=" + url
-			imgData, contentType, err = p.tryDownload(client, proxyURL)
+			imgData, contentType, err = p.tryDownload(proxyURL)
 			if err != nil {
 				return "", fmt.Errorf("代理下载也失败: %w", err)
 			}
@@ -116,17 +170,36 @@ func (p *ImageProcessor) downloadAndUploadImage(url string) (string, error) {
 	)
 
 	// 上传到 OSS (如果 OSS 不可用会自动fallback到本地存储)
-	uploadedURL, err := oss.UploadBytes(imgData, filename)
+	// JPEG/PNG 按 image 配置压缩，并生成 WebP 版本
+	uploaded, err := oss.UploadImage(imgData, filename)
 	if err != nil {
 		return "", fmt.Errorf("上传失败: %w", err)
 	}
 
-	return uploadedURL, nil
+	return uploaded.URL, nil
+}
+
+// bundledAsset 在导入包中查找相对路径对应的图片
+func (p *ImageProcessor) bundledAsset(rawURL string) ([]byte, bool) {
+	if p.assets == nil || strings.Contains(rawURL, "://") || strings.HasPrefix(rawURL, "/") {
+		return nil, false
+	}
+	candidates := []string{rawURL}
+	if unescaped, err := neturl.PathUnescape(rawURL); err == nil && unescaped != rawURL {
+		candidates = append(candidates, unescaped)
+	}
+	for _, candidate := range candidates {
+		if data, ok := p.assets[path.Join(p.assetDir, candidate)]; ok {
+			return data, true
+		}
+	}
+	return nil, false
 }
 
 // tryDownload 尝试下载图片,返回图片数据和 Content-Type
-func (p *ImageProcessor) tryDownload(client *http.Client, url string) ([]byte, string, error) {
-	req, err := http.NewRequest("GET", url, nil)
+func (p *ImageProcessor) tryDownload(url string) ([]byte, string, error) {
+	// 私有 bucket 中的图片需要签名才能下载
+	req, err := http.NewRequest("GET", oss.SignURL(url), nil)
 	if err != nil {
 		return nil, "", fmt.Errorf("创建请求失败: %w", err)
 	}
@@ -139,14 +212,14 @@ func (p *ImageProcessor) tryDownload(client *http.Client, url string) ([]byte, s
 	req.Header.Set("Cache-Control", "no-cache")
 	req.Header.Set("Pragma", "no-cache")
 
-	resp, err := client.Do(req)
+	resp, err := imageHTTPClient.Do(req)
 	if err != nil {
 		return nil, "", fmt.Errorf("下载图片失败: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, "", fmt.Errorf("HTTP 状态码错误: %d", resp.StatusCode)
+		return nil, "", &httpStatusError{Code: resp.StatusCode}
 	}
 
 	imgData, err := io.ReadAll(resp.Body)
@@ -0,0 +1,141 @@
+package markdown
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	// maxArchiveEntries ZIP 中允许的最大文件数
+	maxArchiveEntries = 2000
+	// maxArchiveSize ZIP 解压后允许的最大总大小
+	maxArchiveSize = 512 << 20
+)
+
+// archiveImageExts 作为图片资源读取的扩展名
+var archiveImageExts = map[string]bool{
+	".png":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".gif":  true,
+	".webp": true,
+	".svg":  true,
+	".bmp":  true,
+}
+
+// ArchiveDocument ZIP 中的 Markdown 文件
+type ArchiveDocument struct {
+	Name    string // ZIP 内路径，如 12-hello.md
+	Content string
+}
+
+// Archive 解析后的导入包
+// 与 ArticleExporter 导出的格式一致：根目录下的 Markdown 文件加 images/ 目录
+type Archive struct {
+	Documents []ArchiveDocument
+	Assets    map[string][]byte // ZIP 内路径 -> 图片数据
+	Tables    map[string][]byte // ZIP 内路径 -> CSV 数据（Notion 数据库）
+}
+
+// ReadArchive 读取 ZIP 导入包，返回其中的 Markdown 文件、图片和 CSV
+// 包内的 ZIP（如 Notion 大批量导出的分卷）会展开一层
+func ReadArchive(data []byte) (*Archive, error) {
+	archive := &Archive{
+		Assets: make(map[string][]byte),
+		Tables: make(map[string][]byte),
+	}
+	var total int64
+	if err := archive.read(data, &total, true); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(archive.Documents, func(i, j int) bool {
+		return archive.Documents[i].Name < archive.Documents[j].Name
+	})
+	return archive, nil
+}
+
+// read 读取一个 ZIP 的内容，total 为已解压的总大小
+func (a *Archive) read(data []byte, total *int64, expandNested bool) error {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("解析ZIP文件失败: %w", err)
+	}
+	if len(reader.File) > maxArchiveEntries {
+		return fmt.Errorf("ZIP文件数量超过限制(%d)", maxArchiveEntries)
+	}
+
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		name := path.Clean(strings.ReplaceAll(file.Name, "\\", "/"))
+		if skipArchiveEntry(name) {
+			continue
+		}
+
+		ext := strings.ToLower(path.Ext(name))
+		isDocument := ext == ".md" || ext == ".markdown"
+		isNested := ext == ".zip" && expandNested
+		if !isDocument && !isNested && ext != ".csv" && !archiveImageExts[ext] {
+			continue
+		}
+
+		content, err := readArchiveFile(file, maxArchiveSize-*total)
+		if err != nil {
+			return fmt.Errorf("读取 %s 失败: %w", name, err)
+		}
+		*total += int64(len(content))
+
+		switch {
+		case isDocument:
+			a.Documents = append(a.Documents, ArchiveDocument{Name: name, Content: string(content)})
+		case isNested:
+			if err := a.read(content, total, false); err != nil {
+				return fmt.Errorf("读取 %s 失败: %w", name, err)
+			}
+		case ext == ".csv":
+			a.Tables[name] = content
+		default:
+			a.Assets[name] = content
+		}
+	}
+	return nil
+}
+
+// skipArchiveEntry 跳过越界路径、隐藏文件和 macOS 压缩产生的元数据
+func skipArchiveEntry(name string) bool {
+	if strings.HasPrefix(name, "../") || strings.HasPrefix(name, "/") {
+		return true
+	}
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") || part == "__MACOSX" {
+			return true
+		}
+	}
+	return false
+}
+
+// readArchiveFile 读取 ZIP 中的单个文件，超过剩余额度时返回错误
+func readArchiveFile(file *zip.File, remaining int64) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, remaining+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > remaining {
+		return nil, errors.New("ZIP解压后大小超过限制")
+	}
+	return content, nil
+}
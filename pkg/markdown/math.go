@@ -0,0 +1,98 @@
+package markdown
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/gomarkdown/markdown/ast"
+	mdhtml "github.com/gomarkdown/markdown/html"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+// displayMathClass 段落内 $$...$$ 公式的标记，渲染为块级公式
+var displayMathClass = []byte("display")
+
+// parseMath 解析行内公式，替换解析器默认的 $ 处理
+//
+// $...$ 为行内公式，规则与 KaTeX auto-render / Pandoc 一致：开头的 $ 后不能是空白，
+// 结尾的 $ 前不能是空白且后面不能紧跟数字，避免 "价格 $5 到 $10" 被识别为公式；
+// 段落中的 $$...$$ 为块级公式（单独成段的 $$ 由解析器的块级规则处理）。
+// 公式内的 \$ 不作为结束符。
+func parseMath(p *parser.Parser, data []byte, offset int) (int, ast.Node) {
+	data = data[offset:]
+	if len(data) > 1 && data[1] == '$' {
+		return parseDisplayMath(data)
+	}
+	if len(data) < 3 || isSpace(data[1]) {
+		return 0, nil
+	}
+
+	for end := 1; end < len(data); end++ {
+		switch {
+		case data[end] == '\\':
+			end++
+		case data[end] == '$':
+			if isSpace(data[end-1]) || (end+1 < len(data) && isDigit(data[end+1])) {
+				continue
+			}
+			math := &ast.Math{}
+			math.Literal = data[1:end]
+			return end + 1, math
+		}
+	}
+	return 0, nil
+}
+
+// parseDisplayMath 解析段落中的 $$...$$
+func parseDisplayMath(data []byte) (int, ast.Node) {
+	for end := 2; end+1 < len(data); end++ {
+		switch {
+		case data[end] == '\\':
+			end++
+		case data[end] == '$' && data[end+1] == '$':
+			literal := data[2:end]
+			if len(bytes.TrimSpace(literal)) == 0 {
+				return 0, nil
+			}
+			math := &ast.Math{}
+			math.Literal = literal
+			math.Attribute = &ast.Attribute{Classes: [][]byte{displayMathClass}}
+			return end + 2, math
+		}
+	}
+	return 0, nil
+}
+
+// MathHook 将段落中的 $$...$$ 渲染为块级公式，其余公式使用默认输出：
+// 行内 <span class="math inline">\(...\)</span>，块级 <span class="math display">\[...\]</span>，
+// 前端 KaTeX auto-render 按 \( \) 和 \[ \] 分隔符渲染
+func MathHook(w io.Writer, node ast.Node, entering bool) (ast.WalkStatus, bool) {
+	math, ok := node.(*ast.Math)
+	if !ok || math.Attribute == nil || len(math.Classes) == 0 || !bytes.Equal(math.Classes[0], displayMathClass) {
+		return ast.GoToNext, false
+	}
+	io.WriteString(w, `<span class="math display">\[`)
+	mdhtml.EscapeHTML(w, math.Literal)
+	io.WriteString(w, `\]</span>`)
+	return ast.GoToNext, true
+}
+
+// ChainHooks 依次尝试多个渲染钩子，第一个处理了节点的钩子生效
+func ChainHooks(hooks ...mdhtml.RenderNodeFunc) mdhtml.RenderNodeFunc {
+	return func(w io.Writer, node ast.Node, entering bool) (ast.WalkStatus, bool) {
+		for _, hook := range hooks {
+			if status, handled := hook(w, node, entering); handled {
+				return status, true
+			}
+		}
+		return ast.GoToNext, false
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
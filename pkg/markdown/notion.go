@@ -0,0 +1,293 @@
+package markdown
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// notionIDPattern Notion 导出文件名末尾的页面 ID，如 "Getting Started 1a2b3c...（32 位）"
+	notionIDPattern = regexp.MustCompile(`\s+[0-9a-f]{32}$`)
+	// notionPageLinkPattern 指向其他页面或数据库的相对链接
+	notionPageLinkPattern = regexp.MustCompile(`\[([^\]]*)\]\(([^)]+\.(?:md|csv))\)`)
+)
+
+// notionTimeLayouts Notion 数据库日期属性的格式
+var notionTimeLayouts = []string{
+	"January 2, 2006 3:04 PM",
+	"January 2, 2006",
+	"2006/01/02 15:04",
+	"2006/01/02",
+}
+
+// NotionPage Notion 导出中的页面或数据库
+type NotionPage struct {
+	Path       string            // ZIP 内路径（不含扩展名），子页面位于同名目录下
+	Title      string            // 去掉页面 ID 后的标题
+	Content    string            // 转换后的 Markdown，不含标题和属性
+	Database   bool              // 是否为数据库（CSV），数据库只有子页面，没有正文
+	Properties map[string]string // 数据库行的属性（来自 CSV）
+	Children   []*NotionPage
+	order      int // 在父页面正文中出现的位置，用于保持子页面顺序
+}
+
+// Dir 页面所在目录，用于解析正文中的相对图片路径
+func (p *NotionPage) Dir() string {
+	return path.Dir(p.Path)
+}
+
+// HasChildren 是否包含子页面
+func (p *NotionPage) HasChildren() bool {
+	return len(p.Children) > 0
+}
+
+// FrontMatter 将数据库属性映射为 Front Matter（标签、分类、创建时间）
+func (p *NotionPage) FrontMatter() *FrontMatter {
+	fm := &FrontMatter{Title: p.Title}
+	for key, value := range p.Properties {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "tags", "tag", "标签":
+			for _, tag := range strings.Split(value, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					fm.Tags = append(fm.Tags, tag)
+				}
+			}
+		case "category", "categories", "分类":
+			fm.Category = strings.TrimSpace(strings.Split(value, ",")[0])
+		case "created", "created time", "date", "创建时间", "日期":
+			if t := parseNotionTime(value); t != nil {
+				fm.CreatedAt = t
+			}
+		}
+	}
+	return fm
+}
+
+// NotionPages 将 Notion 导出（Markdown & CSV 格式）整理为页面树
+// 页面 "A id.md" 的子页面位于目录 "A id/" 下；数据库 "D id.csv" 的行页面位于目录 "D id/" 下
+func NotionPages(archive *Archive) []*NotionPage {
+	nodes := make(map[string]*NotionPage)
+	raw := make(map[string]string)
+
+	// 数据库：同时存在 "D id.csv" 与 "D id_all.csv" 时只取前者
+	for name, data := range archive.Tables {
+		key := strings.TrimSuffix(name, path.Ext(name))
+		if base := strings.TrimSuffix(key, "_all"); base != key {
+			if _, ok := archive.Tables[base+".csv"]; ok {
+				continue
+			}
+			key = base
+		}
+		nodes[key] = &NotionPage{
+			Path:     key,
+			Title:    notionTitle(key),
+			Database: true,
+		}
+		raw[key] = string(data)
+	}
+
+	for _, doc := range archive.Documents {
+		key := strings.TrimSuffix(doc.Name, path.Ext(doc.Name))
+		title, content := splitNotionTitle(doc.Content)
+		if title == "" {
+			title = notionTitle(key)
+		}
+		nodes[key] = &NotionPage{Path: key, Title: title, Content: content}
+		raw[key] = doc.Content
+	}
+
+	var roots []*NotionPage
+	for key, node := range nodes {
+		parent, ok := nodes[path.Dir(key)]
+		if !ok {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+		node.order = notionLinkIndex(raw[parent.Path], node.Path)
+	}
+
+	for _, node := range nodes {
+		if node.Database {
+			applyNotionProperties(node, raw[node.Path])
+		} else {
+			node.Content = ConvertNotionMarkdown(node.Content)
+		}
+		sortNotionPages(node.Children)
+	}
+	sortNotionPages(roots)
+	return roots
+}
+
+// ConvertNotionMarkdown 将 Notion 特有的语法转换为站点支持的 Markdown
+// 标注（<aside>）转为引用块，折叠块（<details>）转为加粗标题加正文，页面间链接只保留文字
+func ConvertNotionMarkdown(content string) string {
+	content = replaceNotionBlocks(content, "<details>", "</details>", func(inner string) string {
+		summary := ""
+		if start := strings.Index(inner, "<summary>"); start >= 0 {
+			if end := strings.Index(inner[start:], "</summary>"); end >= 0 {
+				summary = strings.TrimSpace(inner[start+len("<summary>") : start+end])
+				inner = inner[start+end+len("</summary>"):]
+			}
+		}
+		body := strings.TrimSpace(inner)
+		if summary == "" {
+			return body
+		}
+		return "**" + summary + "**\n\n" + body
+	})
+
+	content = replaceNotionBlocks(content, "<aside>", "</aside>", func(inner string) string {
+		lines := strings.Split(strings.TrimSpace(inner), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return strings.Join(lines, "\n")
+	})
+
+	return notionPageLinkPattern.ReplaceAllStringFunc(content, func(link string) string {
+		match := notionPageLinkPattern.FindStringSubmatch(link)
+		if strings.Contains(match[2], "://") {
+			return link
+		}
+		return match[1]
+	})
+}
+
+// replaceNotionBlocks 替换成对的 HTML 块，从最后一个开始标签处理以支持嵌套
+func replaceNotionBlocks(content, open, close string, convert func(inner string) string) string {
+	for {
+		start := strings.LastIndex(content, open)
+		if start < 0 {
+			return content
+		}
+		end := strings.Index(content[start:], close)
+		if end < 0 {
+			return content
+		}
+		inner := content[start+len(open) : start+end]
+		content = content[:start] + "\n" + convert(inner) + "\n" + content[start+end+len(close):]
+	}
+}
+
+// splitNotionTitle 拆出正文第一行的一级标题
+func splitNotionTitle(content string) (string, string) {
+	content = strings.TrimPrefix(strings.ReplaceAll(content, "\r\n", "\n"), "\ufeff")
+	trimmed := strings.TrimLeft(content, "\n")
+	if !strings.HasPrefix(trimmed, "# ") {
+		return "", content
+	}
+	line, rest, _ := strings.Cut(trimmed, "\n")
+	return strings.TrimSpace(strings.TrimPrefix(line, "# ")), strings.TrimLeft(rest, "\n")
+}
+
+// applyNotionProperties 解析数据库 CSV，把属性写入对应的行页面，并去掉行页面正文开头的属性行
+// CSV 第一列为标题，按标题匹配行页面
+func applyNotionProperties(db *NotionPage, data string) {
+	records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix([]byte(data), []byte("\ufeff")))).ReadAll()
+	if err != nil || len(records) < 2 {
+		return
+	}
+	header := records[0]
+	rows := make(map[string]map[string]string, len(records)-1)
+	for _, record := range records[1:] {
+		if len(record) == 0 {
+			continue
+		}
+		props := make(map[string]string, len(header))
+		for i, key := range header {
+			if i > 0 && i < len(record) {
+				props[key] = record[i]
+			}
+		}
+		rows[strings.TrimSpace(record[0])] = props
+	}
+
+	for _, child := range db.Children {
+		props, ok := rows[child.Title]
+		if !ok {
+			continue
+		}
+		child.Properties = props
+		child.Content = stripNotionProperties(child.Content, header)
+	}
+}
+
+// stripNotionProperties 去掉正文开头 "属性名: 值" 形式的属性行
+func stripNotionProperties(content string, header []string) string {
+	lines := strings.Split(content, "\n")
+	i := 0
+	for ; i < len(lines); i++ {
+		key, _, ok := strings.Cut(lines[i], ":")
+		if !ok || !containsString(header, strings.TrimSpace(key)) {
+			break
+		}
+	}
+	if i == 0 {
+		return content
+	}
+	return strings.TrimLeft(strings.Join(lines[i:], "\n"), "\n")
+}
+
+// notionTitle 从路径中取出去掉页面 ID 的名称
+func notionTitle(key string) string {
+	return strings.TrimSpace(notionIDPattern.ReplaceAllString(path.Base(key), ""))
+}
+
+// notionLinkIndex 子页面链接在父页面正文中的位置，找不到时排在最后
+func notionLinkIndex(parent, child string) int {
+	name := path.Base(child)
+	for _, candidate := range []string{url.PathEscape(name), name} {
+		if idx := strings.Index(parent, candidate); idx >= 0 {
+			return idx
+		}
+	}
+	return len(parent) + 1
+}
+
+// sortNotionPages 按在父页面中出现的顺序排列，其次按标题
+func sortNotionPages(pages []*NotionPage) {
+	sort.SliceStable(pages, func(i, j int) bool {
+		if pages[i].order != pages[j].order {
+			return pages[i].order < pages[j].order
+		}
+		return pages[i].Title < pages[j].Title
+	})
+}
+
+// parseNotionTime 解析 Notion 日期属性，日期范围取开始时间
+func parseNotionTime(value string) *time.Time {
+	if start, _, ok := strings.Cut(value, " → "); ok {
+		value = start
+	}
+	for _, layout := range notionTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return &t
+		}
+	}
+	t, err := parseFrontMatterTime(value)
+	if err != nil {
+		return nil
+	}
+	return t
+}
+
+// containsString 切片中是否包含字符串
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
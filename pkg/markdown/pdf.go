@@ -0,0 +1,195 @@
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// defaultPDFCommand 默认的 HTML 转 PDF 工具
+const defaultPDFCommand = "wkhtmltopdf"
+
+// ErrPDFUnavailable 服务器未安装 HTML 转 PDF 工具
+var ErrPDFUnavailable = errors.New("服务器未安装 PDF 渲染工具(wkhtmltopdf)")
+
+// imgSrcPattern HTML 中的图片地址
+var imgSrcPattern = regexp.MustCompile(`(<img[^>]*?\ssrc=")([^"]+)(")`)
+
+// PDFExporter 通过 wkhtmltopdf 将文章导出为 PDF，图片以 data URI 内嵌
+type PDFExporter struct {
+	command string
+	timeout time.Duration
+	images  *ArticleExporter  // 复用 ZIP 导出的图片读取与下载
+	cache   map[string]string // 图片地址 -> data URI
+}
+
+// NewPDFExporter 创建 PDF 导出器，command 为空时使用 PATH 中的 wkhtmltopdf
+func NewPDFExporter(command string, timeout time.Duration) *PDFExporter {
+	if command == "" {
+		command = defaultPDFCommand
+	}
+	return &PDFExporter{
+		command: command,
+		timeout: timeout,
+		images:  NewArticleExporter(),
+		cache:   make(map[string]string),
+	}
+}
+
+// Export 生成 PDF：封面、目录、正文，一级条目从新页开始
+func (e *PDFExporter) Export(book *Book) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, e.command,
+		"--quiet",
+		"--encoding", "utf-8",
+		"--page-size", "A4",
+		"--footer-center", "[page]",
+		"--footer-font-size", "9",
+		// 图片已内嵌为 data URI，渲染时不需要读取本地文件或执行脚本
+		"--disable-local-file-access",
+		"--disable-javascript",
+		"-", "-",
+	)
+	cmd.Stdin = strings.NewReader(e.BuildHTML(book))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, ErrPDFUnavailable
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("生成PDF超时: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("生成PDF失败: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// BuildHTML 生成待转换的完整 HTML
+func (e *PDFExporter) BuildHTML(book *Book) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\">\n<title>")
+	b.WriteString(html.EscapeString(book.Title))
+	b.WriteString("</title>\n<style>")
+	b.WriteString(pdfStyle)
+	b.WriteString("</style>\n</head><body>\n")
+
+	// 封面
+	b.WriteString("<div class=\"cover\">\n")
+	if book.Cover != "" {
+		fmt.Fprintf(&b, "<img class=\"cover-image\" src=\"%s\">\n", html.EscapeString(e.embedImage(book.Cover)))
+	}
+	fmt.Fprintf(&b, "<h1 class=\"cover-title\">%s</h1>\n", html.EscapeString(book.Title))
+	if book.Subtitle != "" {
+		fmt.Fprintf(&b, "<p class=\"cover-subtitle\">%s</p>\n", html.EscapeString(book.Subtitle))
+	}
+	b.WriteString("</div>\n")
+
+	// 目录
+	b.WriteString("<div class=\"toc\">\n<h1>目录</h1>\n<ul>\n")
+	for i, entry := range book.Entries {
+		fmt.Fprintf(&b, "<li class=\"toc-level-%d\"><a href=\"#entry-%d\">%s</a></li>\n",
+			pdfLevel(entry.Level), i+1, html.EscapeString(entry.Title))
+	}
+	b.WriteString("</ul>\n</div>\n")
+
+	// 正文
+	for i, entry := range book.Entries {
+		level := pdfLevel(entry.Level)
+		fmt.Fprintf(&b, "<section class=\"entry level-%d\" id=\"entry-%d\">\n", level, i+1)
+		fmt.Fprintf(&b, "<h%d class=\"entry-title\">%s</h%d>\n", level, html.EscapeString(entry.Title), level)
+		if entry.HTML != "" {
+			b.WriteString("<div class=\"content\">\n")
+			b.WriteString(e.embedImages(entry.HTML))
+			b.WriteString("\n</div>\n")
+		}
+		b.WriteString("</section>\n")
+	}
+
+	b.WriteString("</body></html>\n")
+	return b.String()
+}
+
+// embedImages 将正文中的图片替换为 data URI，PDF 生成时不再访问网络
+func (e *PDFExporter) embedImages(content string) string {
+	return imgSrcPattern.ReplaceAllStringFunc(content, func(tag string) string {
+		match := imgSrcPattern.FindStringSubmatch(tag)
+		src := html.UnescapeString(match[2])
+		return match[1] + html.EscapeString(e.embedImage(src)) + match[3]
+	})
+}
+
+// embedImage 读取本地或远程图片并转为 data URI，失败时保留原地址
+func (e *PDFExporter) embedImage(src string) string {
+	if uri, ok := e.cache[src]; ok {
+		return uri
+	}
+
+	var data []byte
+	var err error
+	switch {
+	case strings.HasPrefix(src, "/uploads/"):
+		data, _, err = e.images.readLocalImage(src)
+	case strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://"):
+		data, _, err = e.images.downloadImage(src)
+	default:
+		return src
+	}
+	if err != nil {
+		fmt.Printf("[导出] 获取图片失败: %s - %v\n", src, err)
+		e.cache[src] = src
+		return src
+	}
+
+	uri := "data:" + http.DetectContentType(data) + ";base64," + base64.StdEncoding.EncodeToString(data)
+	e.cache[src] = uri
+	return uri
+}
+
+// pdfLevel 将层级限制在 h1-h4
+func pdfLevel(level int) int {
+	if level < 1 {
+		return 1
+	}
+	if level > 4 {
+		return 4
+	}
+	return level
+}
+
+// pdfStyle 打印样式
+const pdfStyle = `
+body { font-family: "Noto Sans CJK SC", "Source Han Sans SC", "Microsoft YaHei", "PingFang SC", sans-serif; font-size: 14px; line-height: 1.7; color: #222; }
+.cover { text-align: center; padding-top: 200px; page-break-after: always; }
+.cover-image { max-width: 80%; max-height: 400px; margin-bottom: 40px; }
+.cover-title { font-size: 36px; margin: 0 0 20px; }
+.cover-subtitle { font-size: 16px; color: #666; }
+.toc { page-break-after: always; }
+.toc ul { list-style: none; padding: 0; }
+.toc li { margin: 6px 0; }
+.toc a { color: #222; text-decoration: none; }
+.toc-level-2 { padding-left: 2em; }
+.toc-level-3 { padding-left: 4em; }
+.toc-level-4 { padding-left: 6em; }
+.entry.level-1 { page-break-before: always; }
+.toc + .entry.level-1 { page-break-before: auto; }
+img { max-width: 100%; }
+pre { background: #f6f8fa; padding: 12px; white-space: pre-wrap; word-wrap: break-word; font-size: 12px; }
+code { font-family: Menlo, Consolas, monospace; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ddd; padding: 4px 8px; }
+blockquote { border-left: 4px solid #ddd; margin: 0; padding-left: 12px; color: #555; }
+pre, blockquote, table, img { page-break-inside: avoid; }
+`
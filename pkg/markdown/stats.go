@@ -0,0 +1,67 @@
+package markdown
+
+import (
+	"math"
+	"regexp"
+	"unicode"
+)
+
+// 阅读速度：中文按字计，英文按单词计
+const (
+	cjkCharsPerMinute = 300
+	wordsPerMinute    = 200
+)
+
+var (
+	// 图片只统计 alt 文本，链接只统计链接文本
+	statsImageRegex = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	statsLinkRegex  = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	statsHTMLRegex  = regexp.MustCompile(`<[^>]+>`)
+)
+
+// ReadingStats 统计 Markdown 正文字数并估算阅读分钟数
+// 中日韩文字每个字计 1，其他语言按连续的字母数字计为 1 个单词；有内容时阅读时间至少 1 分钟
+func ReadingStats(content string) (words int, minutes int) {
+	content = statsImageRegex.ReplaceAllString(content, "$1")
+	content = statsLinkRegex.ReplaceAllString(content, "$1")
+	content = statsHTMLRegex.ReplaceAllString(content, " ")
+
+	var cjk, latin int
+	inWord := false
+	for _, r := range content {
+		switch {
+		case isCJK(r):
+			cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				latin++
+				inWord = true
+			}
+		case r == '\'' || r == '’':
+			// 英文缩写（don't）不拆分单词
+		default:
+			inWord = false
+		}
+	}
+
+	words = cjk + latin
+	if words == 0 {
+		return 0, 0
+	}
+
+	m := float64(cjk)/cjkCharsPerMinute + float64(latin)/wordsPerMinute
+	minutes = int(math.Ceil(m))
+	if minutes < 1 {
+		minutes = 1
+	}
+	return words, minutes
+}
+
+// isCJK 是否为中日韩文字（不含标点）
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
@@ -0,0 +1,68 @@
+package markdown
+
+import (
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+// Extensions 文章 Markdown 解析扩展，渲染 HTML 与提取目录必须使用同一组扩展，保证标题锚点一致
+// CommonExtensions 已包含 MathJax（$...$ 与 $$...$$ 公式）
+const Extensions = parser.CommonExtensions | parser.AutoHeadingIDs | parser.NoEmptyLineBeforeBlock
+
+// NewParser 创建文章 Markdown 解析器
+func NewParser() *parser.Parser {
+	p := parser.NewWithExtensions(Extensions)
+	p.RegisterInline('$', parseMath)
+	return p
+}
+
+// Heading 文章标题
+type Heading struct {
+	Level int
+	Text  string
+	ID    string // 与渲染后 HTML 中标题的 id 属性一致
+}
+
+// ExtractHeadings 按出现顺序提取 Markdown 中的标题
+func ExtractHeadings(content string) []Heading {
+	doc := NewParser().Parse([]byte(content))
+
+	var headings []Heading
+	ast.WalkFunc(doc, func(node ast.Node, entering bool) ast.WalkStatus {
+		heading, ok := node.(*ast.Heading)
+		if !ok || !entering {
+			return ast.GoToNext
+		}
+		if !heading.IsTitleblock && heading.HeadingID != "" {
+			headings = append(headings, Heading{
+				Level: heading.Level,
+				Text:  strings.TrimSpace(headingText(heading)),
+				ID:    heading.HeadingID,
+			})
+		}
+		return ast.SkipChildren
+	})
+	return headings
+}
+
+// headingText 拼接标题中的纯文本（去掉强调、链接等标记）
+func headingText(node ast.Node) string {
+	var sb strings.Builder
+	ast.WalkFunc(node, func(n ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.GoToNext
+		}
+		switch v := n.(type) {
+		case *ast.Text:
+			sb.Write(v.Literal)
+		case *ast.Code:
+			sb.Write(v.Literal)
+		case *ast.Math:
+			sb.Write(v.Literal)
+		}
+		return ast.GoToNext
+	})
+	return sb.String()
+}
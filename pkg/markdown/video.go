@@ -0,0 +1,172 @@
+package markdown
+
+import (
+	"html"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+var (
+	youtubeID  = regexp.MustCompile(`^[A-Za-z0-9_\-]{11}$`)
+	bilibiliBV = regexp.MustCompile(`^BV[0-9A-Za-z]{10}$`)
+	bilibiliAV = regexp.MustCompile(`^av([0-9]+)$`)
+	// youtubeStart YouTube 的 t 参数，如 90、90s、1m30s
+	youtubeStart = regexp.MustCompile(`^(?:([0-9]+)h)?(?:([0-9]+)m)?(?:([0-9]+)s?)?$`)
+)
+
+// videoExtensions 图片语法引用时按视频播放的文件类型
+var videoExtensions = map[string]bool{
+	".mp4":  true,
+	".webm": true,
+	".mov":  true,
+	".m4v":  true,
+	".ogv":  true,
+}
+
+// VideoHook 渲染视频的钩子
+//
+// 单独成段的 Bilibili / YouTube 视频链接（自动链接或 [标题](地址)）输出为播放器 iframe：
+// <div class="video-embed video-bilibili"><iframe src="https://player.bilibili.com/player.html?..."></iframe></div>，
+// 只使用 VideoEmbedURL 生成的地址，文章清理策略也只允许这些播放器地址的 iframe；
+// 图片语法引用的视频文件（如 ![演示](/uploads/videos/a.mp4)）输出为 <video controls>。
+func VideoHook(w io.Writer, node ast.Node, entering bool) (ast.WalkStatus, bool) {
+	switch node := node.(type) {
+	case *ast.Paragraph:
+		link := soleLink(node)
+		if link == nil {
+			return ast.GoToNext, false
+		}
+		provider, embedURL, ok := VideoEmbedURL(string(link.Destination))
+		if !ok {
+			return ast.GoToNext, false
+		}
+		if entering {
+			io.WriteString(w, `<div class="video-embed video-`+provider+`"><iframe src="`+html.EscapeString(embedURL)+
+				`" title="`+html.EscapeString(linkTitle(link))+`" loading="lazy" frameborder="0" allowfullscreen></iframe></div>`+"\n")
+		}
+		return ast.SkipChildren, true
+	case *ast.Image:
+		if !isVideoFile(string(node.Destination)) {
+			return ast.GoToNext, false
+		}
+		if entering {
+			io.WriteString(w, `<video src="`+html.EscapeString(string(node.Destination))+`" controls preload="metadata"></video>`)
+		}
+		return ast.SkipChildren, true
+	}
+	return ast.GoToNext, false
+}
+
+// VideoEmbedURL 识别 Bilibili / YouTube 视频页地址，返回平台名称（bilibili、youtube）和播放器地址
+func VideoEmbedURL(rawURL string) (string, string, bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", "", false
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	switch host {
+	case "youtube.com", "youtube-nocookie.com", "youtu.be":
+		var id string
+		switch {
+		case host == "youtu.be" && len(segments) == 1:
+			id = segments[0]
+		case len(segments) == 1 && segments[0] == "watch":
+			id = u.Query().Get("v")
+		case len(segments) == 2 && (segments[0] == "embed" || segments[0] == "shorts" || segments[0] == "live"):
+			id = segments[1]
+		}
+		if !youtubeID.MatchString(id) {
+			return "", "", false
+		}
+		embed := "https://www.youtube-nocookie.com/embed/" + id
+		if start := youtubeStartSeconds(u.Query().Get("t")); start > 0 {
+			embed += "?start=" + strconv.Itoa(start)
+		}
+		return "youtube", embed, true
+
+	case "bilibili.com":
+		if len(segments) != 2 || segments[0] != "video" {
+			return "", "", false
+		}
+		query := url.Values{}
+		if bilibiliBV.MatchString(segments[1]) {
+			query.Set("bvid", segments[1])
+		} else if m := bilibiliAV.FindStringSubmatch(segments[1]); m != nil {
+			query.Set("aid", m[1])
+		} else {
+			return "", "", false
+		}
+		if page, err := strconv.Atoi(u.Query().Get("p")); err == nil && page > 1 {
+			query.Set("page", strconv.Itoa(page))
+		}
+		query.Set("autoplay", "0")
+		return "bilibili", "https://player.bilibili.com/player.html?" + query.Encode(), true
+	}
+	return "", "", false
+}
+
+// soleLink 段落中只有一个链接（忽略前后空白）时返回该链接
+func soleLink(paragraph *ast.Paragraph) *ast.Link {
+	var link *ast.Link
+	for _, child := range paragraph.Children {
+		switch child := child.(type) {
+		case *ast.Link:
+			if link != nil {
+				return nil
+			}
+			link = child
+		case *ast.Text:
+			if len(strings.TrimSpace(string(child.Literal))) > 0 {
+				return nil
+			}
+		default:
+			return nil
+		}
+	}
+	return link
+}
+
+// linkTitle 链接文字，自动链接的文字即地址本身
+func linkTitle(link *ast.Link) string {
+	var b strings.Builder
+	ast.WalkFunc(link, func(node ast.Node, entering bool) ast.WalkStatus {
+		if text, ok := node.(*ast.Text); ok && entering {
+			b.Write(text.Literal)
+		}
+		return ast.GoToNext
+	})
+	return b.String()
+}
+
+// isVideoFile 地址是否指向视频文件
+func isVideoFile(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return videoExtensions[strings.ToLower(path.Ext(u.Path))]
+}
+
+// youtubeStartSeconds 解析 t 参数为秒数，无法解析时返回 0
+func youtubeStartSeconds(t string) int {
+	m := youtubeStart.FindStringSubmatch(t)
+	if m == nil {
+		return 0
+	}
+	seconds := 0
+	for i, unit := range []int{3600, 60, 1} {
+		if n, err := strconv.Atoi(m[i+1]); err == nil {
+			seconds += n * unit
+		}
+	}
+	return seconds
+}
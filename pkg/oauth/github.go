@@ -0,0 +1,88 @@
+package oauth
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+const (
+	githubAuthURL   = "https://github.com/login/oauth/authorize"
+	githubTokenURL  = "https://github.com/login/oauth/access_token"
+	githubUserURL   = "https://api.github.com/user"
+	githubEmailsURL = "https://api.github.com/user/emails"
+)
+
+// github GitHub 登录
+type github struct {
+	cfg config.OAuthProviderConfig
+}
+
+func newGitHub(cfg config.OAuthProviderConfig) Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"read:user", "user:email"}
+	}
+	return &github{cfg: cfg}
+}
+
+func (p *github) Name() string {
+	return "github"
+}
+
+func (p *github) AuthURL(state, redirectURI string) string {
+	return authCodeURL(githubAuthURL, p.cfg.ClientID, redirectURI, state, p.cfg.Scopes, nil)
+}
+
+// Exchange 公开资料中的邮箱可能为空或未验证，另外读取邮箱列表取已验证的主邮箱
+func (p *github) Exchange(ctx context.Context, code, redirectURI string) (*Identity, error) {
+	var token tokenResponse
+	err := postForm(ctx, githubTokenURL, url.Values{
+		"client_id":     {p.cfg.ClientID},
+		"client_secret": {p.cfg.ClientSecret},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+	}, &token)
+	if err != nil {
+		return nil, err
+	}
+	if err := token.err(); err != nil {
+		return nil, err
+	}
+
+	var user struct {
+		ID        int64  `json:"id"`
+		Login     string `json:"login"`
+		Name      string `json:"name"`
+		AvatarURL string `json:"avatar_url"`
+		Bio       string `json:"bio"`
+	}
+	if err := getJSON(ctx, githubUserURL, token.AccessToken, &user); err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{
+		Provider: p.Name(),
+		Subject:  strconv.FormatInt(user.ID, 10),
+		Login:    user.Login,
+		Nickname: user.Name,
+		Avatar:   user.AvatarURL,
+		Bio:      user.Bio,
+	}
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := getJSON(ctx, githubEmailsURL, token.AccessToken, &emails); err == nil {
+		for _, email := range emails {
+			if email.Primary {
+				identity.Email = email.Email
+				identity.EmailVerified = email.Verified
+				break
+			}
+		}
+	}
+	return identity, nil
+}
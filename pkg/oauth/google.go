@@ -0,0 +1,74 @@
+package oauth
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+const (
+	googleAuthURL     = "https://accounts.google.com/o/oauth2/v2/auth"
+	googleTokenURL    = "https://oauth2.googleapis.com/token"
+	googleUserInfoURL = "https://openidconnect.googleapis.com/v1/userinfo"
+)
+
+// google Google 登录（OpenID Connect）
+type google struct {
+	cfg config.OAuthProviderConfig
+}
+
+func newGoogle(cfg config.OAuthProviderConfig) Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	return &google{cfg: cfg}
+}
+
+func (p *google) Name() string {
+	return "google"
+}
+
+func (p *google) AuthURL(state, redirectURI string) string {
+	return authCodeURL(googleAuthURL, p.cfg.ClientID, redirectURI, state, p.cfg.Scopes, url.Values{"prompt": {"select_account"}})
+}
+
+func (p *google) Exchange(ctx context.Context, code, redirectURI string) (*Identity, error) {
+	var token tokenResponse
+	err := postForm(ctx, googleTokenURL, url.Values{
+		"grant_type":    {"authorization_code"},
+		"client_id":     {p.cfg.ClientID},
+		"client_secret": {p.cfg.ClientSecret},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+	}, &token)
+	if err != nil {
+		return nil, err
+	}
+	if err := token.err(); err != nil {
+		return nil, err
+	}
+
+	var user struct {
+		Sub           string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+		Picture       string `json:"picture"`
+	}
+	if err := getJSON(ctx, googleUserInfoURL, token.AccessToken, &user); err != nil {
+		return nil, err
+	}
+
+	login, _, _ := strings.Cut(user.Email, "@")
+	return &Identity{
+		Provider:      p.Name(),
+		Subject:       user.Sub,
+		Login:         login,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		Nickname:      user.Name,
+		Avatar:        user.Picture,
+	}, nil
+}
@@ -0,0 +1,178 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+)
+
+const requestTimeout = 10 * time.Second
+
+// ErrUnknownProvider 未知或未配置的登录方式
+var ErrUnknownProvider = errors.New("unknown oauth provider")
+
+// Identity 第三方账号信息
+type Identity struct {
+	Provider      string
+	Subject       string // 第三方平台的用户ID
+	UnionID       string // 同一开放平台下多个应用共享的用户ID，没有时为空
+	Login         string // 第三方平台的用户名，用于生成本站用户名
+	Email         string
+	EmailVerified bool // 第三方平台是否已验证该邮箱，只有已验证的邮箱才用于关联已有账号
+	Nickname      string
+	Avatar        string
+	Bio           string
+}
+
+// Provider 第三方登录平台
+type Provider interface {
+	// Name 平台名称，与配置中的 key 相同
+	Name() string
+	// AuthURL 授权页面地址
+	AuthURL(state, redirectURI string) string
+	// Exchange 使用授权码换取访问令牌并读取账号信息
+	Exchange(ctx context.Context, code, redirectURI string) (*Identity, error)
+}
+
+// factories 已支持的平台
+var factories = map[string]func(cfg config.OAuthProviderConfig) Provider{
+	"github":    newGitHub,
+	"google":    newGoogle,
+	"wechat":    newWeChat,
+	"wechat_mp": newWeChatMP,
+	"qq":        newQQ,
+}
+
+// platforms 同一开放平台下的登录方式共享 unionid：同一用户在不同应用中的 openid 不同、unionid 相同
+var platforms = map[string]string{
+	"wechat":    "wechat",
+	"wechat_mp": "wechat",
+	"qq":        "qq",
+}
+
+// Get 返回已配置 client_id 的平台
+func Get(name string) (Provider, error) {
+	factory, ok := factories[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	cfg, ok := config.AppConfig.OAuth.Providers[name]
+	if !ok || cfg.ClientID == "" {
+		return nil, ErrUnknownProvider
+	}
+	return factory(cfg), nil
+}
+
+// UnionProviders 与 provider 共享 unionid 的登录方式（包含自身），平台不支持 unionid 时返回 nil
+func UnionProviders(provider string) []string {
+	platform, ok := platforms[provider]
+	if !ok {
+		return nil
+	}
+	var names []string
+	for name, p := range platforms {
+		if p == platform {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Enabled 已配置的平台名称，按名称排序
+func Enabled() []string {
+	var names []string
+	for name := range factories {
+		if cfg, ok := config.AppConfig.OAuth.Providers[name]; ok && cfg.ClientID != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// authCodeURL 拼接标准 OAuth2 授权地址
+func authCodeURL(endpoint, clientID, redirectURI, state string, scopes []string, extra url.Values) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", clientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("state", state)
+	if len(scopes) > 0 {
+		query.Set("scope", strings.Join(scopes, " "))
+	}
+	for key, values := range extra {
+		query[key] = values
+	}
+	return endpoint + "?" + query.Encode()
+}
+
+// postForm 提交表单并解析 JSON 响应
+func postForm(ctx context.Context, endpoint string, form url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return doJSON(req, out)
+}
+
+// getJSON 发送 GET 请求并解析 JSON 响应，accessToken 不为空时作为 Bearer 令牌
+func getJSON(ctx context.Context, endpoint, accessToken string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	return doJSON(req, out)
+}
+
+// doJSON 发送请求并解析 JSON 响应，请求 context 中带有请求ID时通过 X-Request-ID 传递给提供方
+func doJSON(req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	logger.PropagateRequestID(req)
+	client := &http.Client{Timeout: requestTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: status %d: %s", req.Method, req.URL.Host+req.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, out)
+}
+
+// tokenResponse 标准 OAuth2 令牌响应
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (t *tokenResponse) err() error {
+	if t.Error != "" {
+		return fmt.Errorf("oauth error: %s %s", t.Error, t.ErrorDescription)
+	}
+	if t.AccessToken == "" {
+		return errors.New("oauth error: empty access token")
+	}
+	return nil
+}
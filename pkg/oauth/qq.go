@@ -0,0 +1,133 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+const (
+	qqAuthURL     = "https://graph.qq.com/oauth2.0/authorize"
+	qqTokenURL    = "https://graph.qq.com/oauth2.0/token"
+	qqOpenIDURL   = "https://graph.qq.com/oauth2.0/me"
+	qqUserInfoURL = "https://graph.qq.com/user/get_user_info"
+)
+
+// qq QQ 互联登录，需在 QQ 互联申请 unionid 权限后才会返回 unionid
+type qq struct {
+	cfg config.OAuthProviderConfig
+}
+
+func newQQ(cfg config.OAuthProviderConfig) Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"get_user_info"}
+	}
+	return &qq{cfg: cfg}
+}
+
+func (p *qq) Name() string {
+	return "qq"
+}
+
+func (p *qq) AuthURL(state, redirectURI string) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("state", state)
+	query.Set("scope", strings.Join(p.cfg.Scopes, ","))
+	return qqAuthURL + "?" + query.Encode()
+}
+
+// Exchange 换取令牌后还需单独查询 openid，QQ 不提供邮箱
+func (p *qq) Exchange(ctx context.Context, code, redirectURI string) (*Identity, error) {
+	var token struct {
+		qqError
+		AccessToken string `json:"access_token"`
+	}
+	query := url.Values{
+		"grant_type":    {"authorization_code"},
+		"client_id":     {p.cfg.ClientID},
+		"client_secret": {p.cfg.ClientSecret},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"fmt":           {"json"},
+	}
+	if err := getJSON(ctx, qqTokenURL+"?"+query.Encode(), "", &token); err != nil {
+		return nil, err
+	}
+	if err := token.err(); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("qq error: empty access token")
+	}
+
+	var me struct {
+		qqError
+		OpenID  string `json:"openid"`
+		UnionID string `json:"unionid"`
+	}
+	query = url.Values{
+		"access_token": {token.AccessToken},
+		"unionid":      {"1"},
+		"fmt":          {"json"},
+	}
+	if err := getJSON(ctx, qqOpenIDURL+"?"+query.Encode(), "", &me); err != nil {
+		return nil, err
+	}
+	if err := me.err(); err != nil {
+		return nil, err
+	}
+	if me.OpenID == "" {
+		return nil, errors.New("qq error: empty openid")
+	}
+
+	var user struct {
+		Ret         int    `json:"ret"`
+		Msg         string `json:"msg"`
+		Nickname    string `json:"nickname"`
+		FigureURLQQ string `json:"figureurl_qq_2"` // 100x100 头像，部分账号没有
+		FigureURL   string `json:"figureurl_qq_1"` // 40x40 头像
+	}
+	query = url.Values{
+		"access_token":       {token.AccessToken},
+		"oauth_consumer_key": {p.cfg.ClientID},
+		"openid":             {me.OpenID},
+	}
+	if err := getJSON(ctx, qqUserInfoURL+"?"+query.Encode(), "", &user); err != nil {
+		return nil, err
+	}
+	if user.Ret != 0 {
+		return nil, fmt.Errorf("qq error: %d %s", user.Ret, user.Msg)
+	}
+
+	avatar := user.FigureURLQQ
+	if avatar == "" {
+		avatar = user.FigureURL
+	}
+	return &Identity{
+		Provider: p.Name(),
+		Subject:  me.OpenID,
+		UnionID:  me.UnionID,
+		Nickname: user.Nickname,
+		Avatar:   avatar,
+	}, nil
+}
+
+// qqError QQ 互联 OAuth 接口错误，error 为数字错误码
+type qqError struct {
+	Error            int    `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (e *qqError) err() error {
+	if e.Error != 0 {
+		return fmt.Errorf("qq error: %d %s", e.Error, e.ErrorDescription)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+const (
+	wechatQRConnectURL = "https://open.weixin.qq.com/connect/qrconnect"
+	wechatAuthorizeURL = "https://open.weixin.qq.com/connect/oauth2/authorize"
+	wechatTokenURL     = "https://api.weixin.qq.com/sns/oauth2/access_token"
+	wechatUserInfoURL  = "https://api.weixin.qq.com/sns/userinfo"
+)
+
+// wechat 微信登录
+// wechat 为开放平台网站应用的扫码登录，wechat_mp 为公众号网页授权（在微信内打开页面时使用）；
+// 两者绑定到同一开放平台账号后 unionid 相同，据此关联到同一个用户
+type wechat struct {
+	name     string
+	endpoint string
+	cfg      config.OAuthProviderConfig
+}
+
+func newWeChat(cfg config.OAuthProviderConfig) Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"snsapi_login"}
+	}
+	return &wechat{name: "wechat", endpoint: wechatQRConnectURL, cfg: cfg}
+}
+
+func newWeChatMP(cfg config.OAuthProviderConfig) Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"snsapi_userinfo"}
+	}
+	return &wechat{name: "wechat_mp", endpoint: wechatAuthorizeURL, cfg: cfg}
+}
+
+func (p *wechat) Name() string {
+	return p.name
+}
+
+// AuthURL 微信校验参数顺序，且地址必须以 #wechat_redirect 结尾，不能使用 url.Values 拼接
+func (p *wechat) AuthURL(state, redirectURI string) string {
+	return fmt.Sprintf("%s?appid=%s&redirect_uri=%s&response_type=code&scope=%s&state=%s#wechat_redirect",
+		p.endpoint,
+		url.QueryEscape(p.cfg.ClientID),
+		url.QueryEscape(redirectURI),
+		url.QueryEscape(strings.Join(p.cfg.Scopes, ",")),
+		url.QueryEscape(state))
+}
+
+// Exchange 微信接口出错时同样返回 200，错误信息在 errcode/errmsg 中；微信不提供邮箱
+func (p *wechat) Exchange(ctx context.Context, code, redirectURI string) (*Identity, error) {
+	var token struct {
+		wechatError
+		AccessToken string `json:"access_token"`
+		OpenID      string `json:"openid"`
+		UnionID     string `json:"unionid"`
+	}
+	query := url.Values{
+		"appid":      {p.cfg.ClientID},
+		"secret":     {p.cfg.ClientSecret},
+		"code":       {code},
+		"grant_type": {"authorization_code"},
+	}
+	if err := getJSON(ctx, wechatTokenURL+"?"+query.Encode(), "", &token); err != nil {
+		return nil, err
+	}
+	if err := token.err(); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" || token.OpenID == "" {
+		return nil, errors.New("wechat error: empty access token")
+	}
+
+	var user struct {
+		wechatError
+		OpenID     string `json:"openid"`
+		UnionID    string `json:"unionid"`
+		Nickname   string `json:"nickname"`
+		HeadImgURL string `json:"headimgurl"`
+	}
+	query = url.Values{
+		"access_token": {token.AccessToken},
+		"openid":       {token.OpenID},
+		"lang":         {"zh_CN"},
+	}
+	if err := getJSON(ctx, wechatUserInfoURL+"?"+query.Encode(), "", &user); err != nil {
+		return nil, err
+	}
+	if err := user.err(); err != nil {
+		return nil, err
+	}
+
+	unionID := user.UnionID
+	if unionID == "" {
+		unionID = token.UnionID
+	}
+	return &Identity{
+		Provider: p.Name(),
+		Subject:  token.OpenID,
+		UnionID:  unionID,
+		Nickname: user.Nickname,
+		Avatar:   user.HeadImgURL,
+	}, nil
+}
+
+// wechatError 微信接口错误
+type wechatError struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+func (e *wechatError) err() error {
+	if e.ErrCode != 0 {
+		return fmt.Errorf("wechat error: %d %s", e.ErrCode, e.ErrMsg)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package oss
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+// aliyunStorage 阿里云 OSS
+type aliyunStorage struct {
+	bucket  *oss.Bucket
+	baseURL string
+}
+
+func newAliyunStorage(cfg config.OSSConfig) (*aliyunStorage, error) {
+	if cfg.Endpoint == "" {
+		return nil, errIncompleteConfig
+	}
+	client, err := oss.New(cfg.Endpoint, cfg.AccessKeyID, cfg.AccessKeySecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OSS client: %w", err)
+	}
+	bucket, err := client.Bucket(cfg.BucketName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bucket: %w", err)
+	}
+
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = "https://" + cfg.BucketName + "." + trimScheme(cfg.Endpoint)
+	}
+	return &aliyunStorage{bucket: bucket, baseURL: baseURL}, nil
+}
+
+func (s *aliyunStorage) Name() string {
+	return DriverAliyun
+}
+
+func (s *aliyunStorage) Put(key string, r io.Reader, size int64) error {
+	if err := s.bucket.PutObject(key, r); err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	return nil
+}
+
+// PutPrivate 上传私有对象，不继承 bucket 的公共读权限
+func (s *aliyunStorage) PutPrivate(key string, r io.Reader, size int64) error {
+	if err := s.bucket.PutObject(key, r, oss.ObjectACL(oss.ACLPrivate)); err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	return nil
+}
+
+func (s *aliyunStorage) Get(key string) (io.ReadCloser, error) {
+	body, err := s.bucket.GetObject(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+	return body, nil
+}
+
+func (s *aliyunStorage) Delete(key string) error {
+	if err := s.bucket.DeleteObject(key); err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+func (s *aliyunStorage) SignURL(key string, expires time.Duration) (string, error) {
+	return s.signURL(key, expires, "")
+}
+
+// signURL 生成签名地址，process 为 x-oss-process 图片处理参数，需要参与签名
+func (s *aliyunStorage) signURL(key string, expires time.Duration, process string) (string, error) {
+	var options []oss.Option
+	if process != "" {
+		options = append(options, oss.Process(process))
+	}
+	return s.bucket.SignURL(key, oss.HTTPGet, int64(expires/time.Second), options...)
+}
+
+func (s *aliyunStorage) List(prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	marker := ""
+	for {
+		result, err := s.bucket.ListObjects(oss.Prefix(prefix), oss.Marker(marker), oss.MaxKeys(1000))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+		for _, object := range result.Objects {
+			objects = append(objects, ObjectInfo{
+				Key:          object.Key,
+				Size:         object.Size,
+				LastModified: object.LastModified,
+			})
+		}
+		if !result.IsTruncated {
+			return objects, nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+func (s *aliyunStorage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// trimScheme 去掉地址中的协议
+func trimScheme(endpoint string) string {
+	if i := strings.Index(endpoint, "://"); i >= 0 {
+		return endpoint[i+3:]
+	}
+	return endpoint
+}
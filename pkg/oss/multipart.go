@@ -0,0 +1,257 @@
+package oss
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/google/uuid"
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+// 分片上传的存储位置
+const (
+	StorageOSS   = "oss"
+	StorageLocal = "local"
+)
+
+// defaultPartDir 本地分片的临时目录
+const defaultPartDir = "./tmp/uploads"
+
+// MultipartUpload 分片上传：使用阿里云 OSS 时使用 OSS 分片上传，否则分片先保存在本地临时目录，完成时合并后写入当前存储
+type MultipartUpload struct {
+	Storage  string // oss 或 local，创建后不随存储状态变化
+	Key      string // 对象键
+	UploadID string // OSS 的 UploadId，本地存储时为临时目录名
+}
+
+// PartInfo 已上传的分片
+type PartInfo struct {
+	Number int
+	Size   int64
+}
+
+// InitMultipart 创建分片上传
+func InitMultipart(objectKey string) (*MultipartUpload, error) {
+	if bucket := aliyunBucket(); bucket != nil {
+		imur, err := bucket.InitiateMultipartUpload(objectKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initiate multipart upload: %w", err)
+		}
+		return &MultipartUpload{Storage: StorageOSS, Key: objectKey, UploadID: imur.UploadID}, nil
+	}
+
+	upload := &MultipartUpload{Storage: StorageLocal, Key: objectKey, UploadID: uuid.New().String()}
+	if err := os.MkdirAll(upload.partDir(), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create part directory: %w", err)
+	}
+	return upload, nil
+}
+
+// UploadPart 上传一个分片，partNumber 从 1 开始，重复上传同一分片会覆盖
+func (m *MultipartUpload) UploadPart(partNumber int, r io.Reader, size int64) error {
+	if m.Storage == StorageOSS {
+		bucket := aliyunBucket()
+		if bucket == nil {
+			return fmt.Errorf("OSS is not configured")
+		}
+		if _, err := bucket.UploadPart(m.imur(), r, size, partNumber); err != nil {
+			return fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+		}
+		return nil
+	}
+
+	// 先写临时文件再重命名，中断的请求不会留下不完整的分片
+	tmp, err := os.CreateTemp(m.partDir(), "part-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create part file: %w", err)
+	}
+	written, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && written != size {
+		err = fmt.Errorf("part size mismatch: got %d bytes, expected %d", written, size)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save part %d: %w", partNumber, err)
+	}
+	if err := os.Rename(tmp.Name(), m.partPath(partNumber)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save part %d: %w", partNumber, err)
+	}
+	return nil
+}
+
+// ListParts 列出已上传的分片，按分片号排序
+func (m *MultipartUpload) ListParts() ([]PartInfo, error) {
+	if m.Storage == StorageOSS {
+		uploaded, err := m.listOSSParts()
+		if err != nil {
+			return nil, err
+		}
+		parts := make([]PartInfo, len(uploaded))
+		for i, part := range uploaded {
+			parts[i] = PartInfo{Number: part.PartNumber, Size: int64(part.Size)}
+		}
+		return parts, nil
+	}
+
+	entries, err := os.ReadDir(m.partDir())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list parts: %w", err)
+	}
+	var parts []PartInfo
+	for _, entry := range entries {
+		number, err := strconv.Atoi(strings.TrimSuffix(entry.Name(), ".part"))
+		if err != nil || !strings.HasSuffix(entry.Name(), ".part") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list parts: %w", err)
+		}
+		parts = append(parts, PartInfo{Number: number, Size: info.Size()})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].Number < parts[j].Number })
+	return parts, nil
+}
+
+// Complete 按分片号 1..totalParts 合并分片，返回文件地址；调用方需先确认分片齐全
+func (m *MultipartUpload) Complete(totalParts int) (string, error) {
+	if m.Storage == StorageOSS {
+		uploaded, err := m.listOSSParts()
+		if err != nil {
+			return "", err
+		}
+		if len(uploaded) != totalParts {
+			return "", fmt.Errorf("expected %d parts, found %d", totalParts, len(uploaded))
+		}
+		parts := make([]oss.UploadPart, len(uploaded))
+		for i, part := range uploaded {
+			parts[i] = oss.UploadPart{PartNumber: part.PartNumber, ETag: part.ETag}
+		}
+		bucket := aliyunBucket()
+		if bucket == nil {
+			return "", fmt.Errorf("OSS is not configured")
+		}
+		if _, err := bucket.CompleteMultipartUpload(m.imur(), parts); err != nil {
+			return "", fmt.Errorf("failed to complete multipart upload: %w", err)
+		}
+		return current().URL(m.Key), nil
+	}
+
+	// 先在分片目录中合并，再写入当前存储
+	dst, err := os.CreateTemp(m.partDir(), "complete-*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(dst.Name())
+	defer dst.Close()
+	for number := 1; number <= totalParts; number++ {
+		if err := appendPart(dst, m.partPath(number)); err != nil {
+			return "", fmt.Errorf("failed to assemble parts: %w", err)
+		}
+	}
+	size, err := dst.Seek(0, io.SeekCurrent)
+	if err == nil {
+		_, err = dst.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to assemble parts: %w", err)
+	}
+
+	s := current()
+	if err := s.Put(m.Key, dst, size); err != nil {
+		return "", err
+	}
+	dst.Close()
+	os.RemoveAll(m.partDir())
+	return s.URL(m.Key), nil
+}
+
+// Abort 取消分片上传并删除已上传的分片
+func (m *MultipartUpload) Abort() error {
+	if m.Storage == StorageOSS {
+		bucket := aliyunBucket()
+		if bucket == nil {
+			return fmt.Errorf("OSS is not configured")
+		}
+		if err := bucket.AbortMultipartUpload(m.imur()); err != nil {
+			return fmt.Errorf("failed to abort multipart upload: %w", err)
+		}
+		return nil
+	}
+	return os.RemoveAll(m.partDir())
+}
+
+func (m *MultipartUpload) imur() oss.InitiateMultipartUploadResult {
+	return oss.InitiateMultipartUploadResult{
+		Bucket:   config.AppConfig.OSS.BucketName,
+		Key:      m.Key,
+		UploadID: m.UploadID,
+	}
+}
+
+// listOSSParts 分页列出 OSS 中已上传的分片
+func (m *MultipartUpload) listOSSParts() ([]oss.UploadedPart, error) {
+	bucket := aliyunBucket()
+	if bucket == nil {
+		return nil, fmt.Errorf("OSS is not configured")
+	}
+	var parts []oss.UploadedPart
+	marker := 0
+	for {
+		result, err := bucket.ListUploadedParts(m.imur(), oss.MaxParts(1000), oss.PartNumberMarker(marker))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list parts: %w", err)
+		}
+		parts = append(parts, result.UploadedParts...)
+		if !result.IsTruncated {
+			break
+		}
+		if marker, err = strconv.Atoi(result.NextPartNumberMarker); err != nil {
+			return nil, fmt.Errorf("failed to list parts: %w", err)
+		}
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	return parts, nil
+}
+
+// aliyunBucket 当前存储为阿里云 OSS 时返回其 bucket，否则返回 nil
+func aliyunBucket() *oss.Bucket {
+	if s, ok := current().(*aliyunStorage); ok {
+		return s.bucket
+	}
+	return nil
+}
+
+// partDir 本地分片目录
+func (m *MultipartUpload) partDir() string {
+	dir := config.AppConfig.Upload.TempDir
+	if dir == "" {
+		dir = defaultPartDir
+	}
+	return filepath.Join(dir, m.UploadID)
+}
+
+func (m *MultipartUpload) partPath(partNumber int) string {
+	return filepath.Join(m.partDir(), fmt.Sprintf("%d.part", partNumber))
+}
+
+// appendPart 将分片内容追加到目标文件
+func appendPart(dst io.Writer, path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	_, err = io.Copy(dst, src)
+	return err
+}
@@ -2,55 +2,73 @@ package oss
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
-	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 	"github.com/google/uuid"
 	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/pkg/imaging"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
 )
 
-var client *oss.Client
-var bucket *oss.Bucket
-var useLocalStorage = false
+// uploadTimeout 上传到远程存储的超时时间，超时后改用本地存储
+const uploadTimeout = 5 * time.Second
 
-// Init 初始化OSS客户端
+// errIncompleteConfig 远程存储配置不完整
+var errIncompleteConfig = errors.New("storage configuration incomplete")
+
+var (
+	// store 配置的存储驱动
+	store Storage
+	// localStore 本地存储，远程存储未配置或上传失败时使用
+	localStore      = &localStorage{dir: "uploads"}
+	useLocalStorage = false
+)
+
+// Init 按 oss.driver 初始化存储驱动：aliyun（默认）、s3、minio、cos、qiniu 或 local
+// 远程存储配置不完整或初始化失败时使用本地存储，并返回原因
 func Init() error {
-	// 检查 OSS 配置是否完整
-	if config.AppConfig.OSS.Endpoint == "" ||
-		config.AppConfig.OSS.AccessKeyID == "" ||
-		config.AppConfig.OSS.AccessKeySecret == "" ||
-		config.AppConfig.OSS.BucketName == "" {
-		useLocalStorage = true
-		// 创建本地上传目录
-		os.MkdirAll("uploads", 0755)
-		return fmt.Errorf("OSS configuration incomplete, using local storage")
+	var err error
+	store, err = newStorage(config.AppConfig.OSS)
+	if err == nil && store != Storage(localStore) {
+		return nil
 	}
 
-	var err error
-	client, err = oss.New(
-		config.AppConfig.OSS.Endpoint,
-		config.AppConfig.OSS.AccessKeyID,
-		config.AppConfig.OSS.AccessKeySecret,
-	)
-	if err != nil {
-		useLocalStorage = true
-		os.MkdirAll("uploads", 0755)
-		return fmt.Errorf("failed to create OSS client: %w, using local storage", err)
+	useLocalStorage = true
+	// 创建本地上传目录
+	os.MkdirAll(localStore.dir, 0755)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, errIncompleteConfig):
+		return fmt.Errorf("OSS configuration incomplete, using local storage")
+	default:
+		return fmt.Errorf("%w, using local storage", err)
 	}
+}
 
-	bucket, err = client.Bucket(config.AppConfig.OSS.BucketName)
-	if err != nil {
-		useLocalStorage = true
-		os.MkdirAll("uploads", 0755)
-		return fmt.Errorf("failed to get bucket: %w, using local storage", err)
+// current 当前使用的存储
+func current() Storage {
+	if useLocalStorage || store == nil {
+		return localStore
 	}
+	return store
+}
 
-	return nil
+// ObjectKey 生成上传对象键: folder/2006/01/02/uuid.ext
+func ObjectKey(folder, filename string) string {
+	return fmt.Sprintf("%s/%s/%s%s",
+		folder,
+		time.Now().Format("2006/01/02"),
+		uuid.New().String(),
+		filepath.Ext(filename),
+	)
 }
 
 // UploadFile 上传文件到OSS或本地存储
@@ -61,158 +79,212 @@ func UploadFile(file *multipart.FileHeader, folder string) (string, error) {
 	}
 	defer src.Close()
 
-	ext := filepath.Ext(file.Filename)
-	filename := fmt.Sprintf("%s/%s/%s%s",
-		folder,
-		time.Now().Format("2006/01/02"),
-		uuid.New().String(),
-		ext,
-	)
+	return upload(ObjectKey(folder, file.Filename), src, file.Size, func() (io.Reader, error) {
+		// 重新打开文件
+		return file.Open()
+	})
+}
 
-	// 如果使用本地存储或 bucket 未初始化
-	if useLocalStorage || bucket == nil {
-		return uploadToLocal(src, filename)
+// UploadBytes 上传字节数据到OSS或本地存储
+func UploadBytes(data []byte, filename string) (string, error) {
+	return upload(filename, bytes.NewReader(data), int64(len(data)), func() (io.Reader, error) {
+		return bytes.NewReader(data), nil
+	})
+}
+
+// upload 上传到当前存储，远程存储失败或超时后切换到本地存储，reopen 用于重新读取内容
+func upload(key string, r io.Reader, size int64, reopen func() (io.Reader, error)) (string, error) {
+	s := current()
+	if s == Storage(localStore) {
+		if err := localStore.Put(key, r, size); err != nil {
+			return "", err
+		}
+		return localStore.URL(key), nil
 	}
 
-	// 尝试使用 OSS 上传，如果失败则使用本地存储
-	url, err := uploadToOSS(src, filename)
-	if err != nil {
-		// OSS 上传失败，切换到本地存储
+	if err := putWithTimeout(s, key, r, size); err != nil {
+		logger.Warn("Failed to upload to "+s.Name()+" storage, switching to local storage: ", err)
 		useLocalStorage = true
-		// 重新打开文件
-		src, _ = file.Open()
-		return uploadToLocal(src, filename)
+		src, err := reopen()
+		if err != nil {
+			return "", fmt.Errorf("failed to open file: %w", err)
+		}
+		if closer, ok := src.(io.Closer); ok {
+			defer closer.Close()
+		}
+		if err := localStore.Put(key, src, size); err != nil {
+			return "", err
+		}
+		return localStore.URL(key), nil
 	}
-
-	return url, nil
+	return s.URL(key), nil
 }
 
-// uploadToOSS 上传到 OSS（带超时）
-func uploadToOSS(src multipart.File, filename string) (string, error) {
+// putWithTimeout 上传到远程存储（带超时）
+func putWithTimeout(s Storage, key string, r io.Reader, size int64) error {
 	// 创建一个带超时的通道
 	done := make(chan error, 1)
-	var uploadErr error
-
 	go func() {
-		uploadErr = bucket.PutObject(filename, src)
-		done <- uploadErr
+		done <- s.Put(key, r, size)
 	}()
 
 	// 等待上传完成或超时
 	select {
 	case err := <-done:
 		if err != nil {
-			return "", fmt.Errorf("failed to upload file: %w", err)
+			return fmt.Errorf("failed to upload file: %w", err)
 		}
-		url := fmt.Sprintf("%s/%s", config.AppConfig.OSS.BaseURL, filename)
-		return url, nil
-	case <-time.After(5 * time.Second):
-		return "", fmt.Errorf("upload timeout after 5 seconds")
+		return nil
+	case <-time.After(uploadTimeout):
+		return fmt.Errorf("upload timeout after 5 seconds")
 	}
 }
 
-// uploadToLocal 上传文件到本地存储
-func uploadToLocal(src multipart.File, filename string) (string, error) {
-	// 创建目标目录
-	destPath := filepath.Join("uploads", filename)
-	destDir := filepath.Dir(destPath)
-	if err := os.MkdirAll(destDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create directory: %w", err)
+// DeleteFile 从当前存储删除文件，objectKey 为空（地址不属于当前存储）时忽略
+func DeleteFile(objectKey string) error {
+	if objectKey == "" {
+		return nil
 	}
+	return current().Delete(objectKey)
+}
 
-	// 创建目标文件
-	dst, err := os.Create(destPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to create file: %w", err)
+// GetObjectKeyFromURL 从URL中提取对象键，不是当前存储的地址时返回空
+func GetObjectKeyFromURL(url string) string {
+	base := baseURL()
+	if strings.HasPrefix(url, base+"/") {
+		return url[len(base)+1:]
 	}
-	defer dst.Close()
+	return ""
+}
 
-	// 复制文件内容
-	if _, err := io.Copy(dst, src); err != nil {
-		return "", fmt.Errorf("failed to save file: %w", err)
-	}
+// baseURL 当前存储的地址前缀
+func baseURL() string {
+	return strings.TrimSuffix(current().URL(""), "/")
+}
 
-	// 返回相对路径URL，让前端根据当前域名访问
-	// 这样无论是localhost还是IP或其他域名都能正常访问
-	url := fmt.Sprintf("/uploads/%s", filename)
-	return url, nil
+// ObjectInfo 对象信息
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
 }
 
-// DeleteFile 从OSS删除文件
-func DeleteFile(objectKey string) error {
-	err := bucket.DeleteObject(objectKey)
-	if err != nil {
-		return fmt.Errorf("failed to delete file: %w", err)
-	}
-	return nil
+// Enabled 是否已配置并连接远程存储（否则文件保存在本地）
+func Enabled() bool {
+	return current() != Storage(localStore)
 }
 
-// GetObjectKeyFromURL 从URL中提取对象键
-func GetObjectKeyFromURL(url string) string {
-	baseURL := config.AppConfig.OSS.BaseURL
-	if len(url) > len(baseURL) {
-		return url[len(baseURL)+1:]
+// Driver 当前使用的存储驱动名称，远程存储不可用时为 local
+func Driver() string {
+	return current().Name()
+}
+
+// PutObject 上传对象到远程存储，不回退到本地存储；用于备份等不对外公开的文件
+// 驱动支持对象 ACL 时以私有权限上传，即使 bucket 开启了公共读也无法直接访问
+func PutObject(objectKey string, r io.Reader) error {
+	if !Enabled() {
+		return fmt.Errorf("OSS is not configured")
 	}
-	return ""
+	if p, ok := store.(privateUploader); ok {
+		return p.PutPrivate(objectKey, r, -1)
+	}
+	return store.Put(objectKey, r, -1)
 }
 
-// UploadBytes 上传字节数据到OSS或本地存储
-func UploadBytes(data []byte, filename string) (string, error) {
-	// 如果使用本地存储或 bucket 未初始化
-	if useLocalStorage || bucket == nil {
-		return uploadBytesToLocal(data, filename)
+// GetObject 读取远程存储中的对象，调用方负责关闭
+func GetObject(objectKey string) (io.ReadCloser, error) {
+	if !Enabled() {
+		return nil, fmt.Errorf("OSS is not configured")
 	}
+	return store.Get(objectKey)
+}
 
-	// 尝试使用 OSS 上传,如果失败则使用本地存储
-	url, err := uploadBytesToOSS(data, filename)
-	if err != nil {
-		// OSS 上传失败,切换到本地存储
-		useLocalStorage = true
-		return uploadBytesToLocal(data, filename)
+// ListObjects 列出远程存储中指定前缀下的所有对象
+func ListObjects(prefix string) ([]ObjectInfo, error) {
+	if !Enabled() {
+		return nil, fmt.Errorf("OSS is not configured")
 	}
+	return store.List(prefix)
+}
 
-	return url, nil
+// ImageVariant 图片缩放版本
+type ImageVariant struct {
+	Name  string `json:"name"` // thumbnail / medium
+	Width int    `json:"width"`
+	URL   string `json:"url"`
 }
 
-// uploadBytesToOSS 上传字节数据到 OSS
-func uploadBytesToOSS(data []byte, filename string) (string, error) {
-	// 创建一个带超时的通道
-	done := make(chan error, 1)
-	var uploadErr error
+// UploadedImage 图片上传结果
+type UploadedImage struct {
+	URL      string         // 原格式图片地址
+	WebPURL  string         // WebP 版本地址，未生成时为空
+	Size     int64          // 原格式图片优化后的大小
+	Variants []ImageVariant // 缩放版本，从小到大
+}
 
-	go func() {
-		uploadErr = bucket.PutObject(filename, bytes.NewReader(data))
-		done <- uploadErr
-	}()
+// UploadImage 按 image 配置压缩图片后上传，并在同目录上传同名的 .webp 版本和缩放版本
+// 缩放版本的路径为原路径加尺寸后缀，如 a.jpg -> a_thumbnail.jpg、a_medium.jpg
+// WebP 和缩放版本生成或上传失败只记录日志，不影响原图上传
+func UploadImage(data []byte, filename string) (*UploadedImage, error) {
+	result, err := imaging.Optimize(data)
+	if err != nil {
+		logger.Warn("Failed to convert image to WebP: ", err)
+	}
 
-	// 等待上传完成或超时
-	select {
-	case err := <-done:
+	url, err := UploadBytes(result.Data, filename)
+	if err != nil {
+		return nil, err
+	}
+	uploaded := &UploadedImage{URL: url, Size: int64(len(result.Data))}
+
+	if len(result.WebP) > 0 {
+		webpURL, err := UploadBytes(result.WebP, imaging.WebPName(filename))
+		if err != nil {
+			logger.Warn("Failed to upload WebP image: ", err)
+		} else {
+			uploaded.WebPURL = webpURL
+		}
+	}
+
+	for _, variant := range imaging.Variants(data) {
+		variantURL, err := UploadBytes(variant.Data, imaging.VariantName(filename, variant.Name))
 		if err != nil {
-			return "", fmt.Errorf("failed to upload file: %w", err)
+			logger.Warn("Failed to upload image variant: ", err)
+			continue
 		}
-		url := fmt.Sprintf("%s/%s", config.AppConfig.OSS.BaseURL, filename)
-		return url, nil
-	case <-time.After(5 * time.Second):
-		return "", fmt.Errorf("upload timeout after 5 seconds")
+		uploaded.Variants = append(uploaded.Variants, ImageVariant{Name: variant.Name, Width: variant.Width, URL: variantURL})
 	}
+	return uploaded, nil
 }
 
-// uploadBytesToLocal 上传字节数据到本地存储
-func uploadBytesToLocal(data []byte, filename string) (string, error) {
-	// 创建目标目录
-	destPath := filepath.Join("uploads", filename)
-	destDir := filepath.Dir(destPath)
-	if err := os.MkdirAll(destDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create directory: %w", err)
+// UploadImageFile 上传表单中的图片文件，路径规则与 UploadFile 相同，JPEG/PNG 按 image 配置优化
+func UploadImageFile(file *multipart.FileHeader, folder string) (*UploadedImage, error) {
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
+	defer src.Close()
 
-	// 写入文件
-	if err := os.WriteFile(destPath, data, 0644); err != nil {
-		return "", fmt.Errorf("failed to save file: %w", err)
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// 返回相对路径URL
-	url := fmt.Sprintf("/uploads/%s", filename)
-	return url, nil
+	return UploadImage(data, ObjectKey(folder, file.Filename))
+}
+
+// ListStoredObjects 列出当前存储中的所有对象：已配置远程存储时为整个 bucket，否则为本地 uploads 目录
+// 本地对象的 Key 为相对 uploads 目录的路径
+func ListStoredObjects() ([]ObjectInfo, error) {
+	return current().List("")
+}
+
+// ObjectURL 对象的访问地址，规则与上传时返回的地址相同
+func ObjectURL(objectKey string) string {
+	return current().URL(objectKey)
+}
+
+// RemoveObject 从当前存储（远程存储或本地 uploads 目录）删除对象
+func RemoveObject(objectKey string) error {
+	return DeleteFile(objectKey)
 }
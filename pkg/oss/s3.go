@@ -0,0 +1,393 @@
+package oss
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+const (
+	s3Algorithm      = "AWS4-HMAC-SHA256"
+	s3UnsignedBody   = "UNSIGNED-PAYLOAD"
+	s3DefaultRegion  = "us-east-1"
+	s3RequestTimeout = 60 * time.Second
+)
+
+// s3Storage 兼容 S3 协议的对象存储，包括 AWS S3、MinIO、腾讯云 COS 和七牛云 Kodo（S3 兼容接口）
+// 请求使用 AWS Signature Version 4 签名
+type s3Storage struct {
+	name      string
+	scheme    string
+	host      string // 服务地址，不含 bucket
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	pathStyle bool // true 时地址为 host/bucket/key，否则为 bucket.host/key
+	baseURL   string
+	client    *http.Client
+}
+
+// newS3Storage 创建 S3 兼容存储，各服务商未配置 endpoint 时按 region 使用默认地址
+func newS3Storage(driver string, cfg config.OSSConfig) (*s3Storage, error) {
+	region := cfg.Region
+	endpoint := cfg.Endpoint
+	pathStyle := cfg.PathStyle
+
+	switch driver {
+	case DriverMinIO:
+		// MinIO 默认不支持虚拟主机方式
+		pathStyle = true
+	case DriverCOS:
+		if endpoint == "" && region != "" {
+			endpoint = "cos." + region + ".myqcloud.com"
+		}
+	case DriverQiniu:
+		if endpoint == "" && region != "" {
+			endpoint = "s3." + region + ".qiniucs.com"
+		}
+	case DriverS3:
+		if endpoint == "" {
+			if region == "" {
+				region = s3DefaultRegion
+			}
+			endpoint = "s3." + region + ".amazonaws.com"
+		}
+	}
+	if endpoint == "" {
+		return nil, errIncompleteConfig
+	}
+	if region == "" {
+		region = s3DefaultRegion
+	}
+
+	scheme := "https"
+	if i := strings.Index(endpoint, "://"); i >= 0 {
+		scheme = endpoint[:i]
+	}
+
+	s := &s3Storage{
+		name:      driver,
+		scheme:    scheme,
+		host:      strings.TrimRight(trimScheme(endpoint), "/"),
+		region:    region,
+		bucket:    cfg.BucketName,
+		accessKey: cfg.AccessKeyID,
+		secretKey: cfg.AccessKeySecret,
+		pathStyle: pathStyle,
+		baseURL:   strings.TrimRight(cfg.BaseURL, "/"),
+		client:    &http.Client{Timeout: s3RequestTimeout},
+	}
+	if s.baseURL == "" {
+		s.baseURL = strings.TrimRight(s.objectURL(""), "/")
+	}
+	return s, nil
+}
+
+func (s *s3Storage) Name() string {
+	return s.name
+}
+
+// Put 请求体需要计算 SHA256，无法 Seek 的数据先读入内存
+func (s *s3Storage) Put(key string, r io.Reader, size int64) error {
+	return s.put(key, r, nil)
+}
+
+// PutPrivate 上传私有对象；MinIO 和七牛云不支持对象 ACL，由 bucket 的访问策略控制
+func (s *s3Storage) PutPrivate(key string, r io.Reader, size int64) error {
+	if s.name == DriverMinIO || s.name == DriverQiniu {
+		return s.put(key, r, nil)
+	}
+	return s.put(key, r, map[string]string{"x-amz-acl": "private"})
+}
+
+func (s *s3Storage) put(key string, r io.Reader, header map[string]string) error {
+	body, ok := r.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("failed to read object: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	hash := sha256.New()
+	n, err := io.Copy(hash, body)
+	if err != nil {
+		return fmt.Errorf("failed to read object: %w", err)
+	}
+	if _, err := body.Seek(-n, io.SeekCurrent); err != nil {
+		return fmt.Errorf("failed to read object: %w", err)
+	}
+
+	resp, err := s.do(http.MethodPut, key, nil, header, io.NopCloser(body), n, hex.EncodeToString(hash.Sum(nil)))
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *s3Storage) Get(key string) (io.ReadCloser, error) {
+	resp, err := s.do(http.MethodGet, key, nil, nil, nil, 0, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+	return resp.Body, nil
+}
+
+func (s *s3Storage) Delete(key string) error {
+	resp, err := s.do(http.MethodDelete, key, nil, nil, nil, 0, "")
+	if err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// SignURL 生成预签名地址
+func (s *s3Storage) SignURL(key string, expires time.Duration) (string, error) {
+	return s.presign(key, expires, time.Now().UTC()), nil
+}
+
+// presign 以 now 为签名时间生成预签名地址
+func (s *s3Storage) presign(key string, expires time.Duration, now time.Time) string {
+	host, path := s.location(key)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", s.accessKey+"/"+s.scope(now))
+	query.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expires/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalQuery := s3CanonicalQuery(query)
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		path,
+		canonicalQuery,
+		"host:" + host + "\n",
+		"host",
+		s3UnsignedBody,
+	}, "\n")
+	signature := s.signature(now, canonicalRequest)
+
+	return s.scheme + "://" + host + path + "?" + canonicalQuery + "&X-Amz-Signature=" + signature
+}
+
+// s3ListResult ListObjectsV2 响应
+type s3ListResult struct {
+	IsTruncated           bool
+	NextContinuationToken string
+	Contents              []struct {
+		Key          string
+		Size         int64
+		LastModified time.Time
+	}
+}
+
+func (s *s3Storage) List(prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	token := ""
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("max-keys", "1000")
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := s.do(http.MethodGet, "", query, nil, nil, 0, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+		var result s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+
+		for _, object := range result.Contents {
+			objects = append(objects, ObjectInfo{
+				Key:          object.Key,
+				Size:         object.Size,
+				LastModified: object.LastModified,
+			})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+func (s *s3Storage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// objectURL 服务商提供的对象地址
+func (s *s3Storage) objectURL(key string) string {
+	host, path := s.location(key)
+	return s.scheme + "://" + host + path
+}
+
+// location 对象所在的主机和编码后的路径
+func (s *s3Storage) location(key string) (string, string) {
+	path := "/" + s3Escape(key, false)
+	if s.pathStyle {
+		return s.host, "/" + s.bucket + path
+	}
+	return s.bucket + "." + s.host, path
+}
+
+// do 发送签名请求，非 2xx 响应返回错误
+// header 为额外参与签名的 x-amz-* 请求头，键为小写；payloadHash 为空时不校验请求体（UNSIGNED-PAYLOAD）
+func (s *s3Storage) do(method, key string, query url.Values, header map[string]string, body io.ReadCloser, size int64, payloadHash string) (*http.Response, error) {
+	now := time.Now().UTC()
+	host, path := s.location(key)
+	if payloadHash == "" {
+		payloadHash = s3UnsignedBody
+	}
+	amzDate := now.Format("20060102T150405Z")
+
+	canonicalQuery := s3CanonicalQuery(query)
+	headers := map[string]string{
+		"host":                 host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	for k, v := range header {
+		headers[k] = v
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+	canonicalRequest := strings.Join([]string{
+		method,
+		path,
+		canonicalQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	signature := s.signature(now, canonicalRequest)
+
+	rawURL := s.scheme + "://" + host + path
+	if canonicalQuery != "" {
+		rawURL += "?" + canonicalQuery
+	}
+	req, err := http.NewRequest(method, rawURL, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.accessKey, s.scope(now), signedHeaders, signature))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		if method == http.MethodDelete && resp.StatusCode == http.StatusNotFound {
+			return resp, nil
+		}
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s %s: status %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return resp, nil
+}
+
+// scope 签名范围：日期/区域/s3/aws4_request
+func (s *s3Storage) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.region + "/s3/aws4_request"
+}
+
+// signature 计算 Signature Version 4 签名
+func (s *s3Storage) signature(now time.Time, canonicalRequest string) string {
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		now.Format("20060102T150405Z"),
+		s.scope(now),
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3CanonicalQuery 按参数名排序并编码查询参数
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parts = append(parts, s3Escape(key, true)+"="+s3Escape(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3Escape 按 SigV4 规则编码：只保留字母、数字和 -_.~，encodeSlash 为 false 时保留 /
+func s3Escape(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
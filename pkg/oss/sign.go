@@ -0,0 +1,157 @@
+package oss
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+)
+
+// defaultSignExpires 签名地址默认有效期（秒）
+const defaultSignExpires = 3600
+
+// signatureParams 签名地址中的参数（阿里云 OSS 和 S3 预签名），去签名时删除
+var signatureParams = []string{
+	"Expires", "OSSAccessKeyId", "Signature", "security-token",
+	"X-Amz-Algorithm", "X-Amz-Credential", "X-Amz-Date", "X-Amz-Expires",
+	"X-Amz-SignedHeaders", "X-Amz-Signature", "X-Amz-Security-Token",
+}
+
+var (
+	objectURLPattern     *regexp.Regexp
+	objectURLPatternBase string
+	objectURLPatternMu   sync.Mutex
+)
+
+// Private 是否为私有 bucket 模式：数据库中保存 base_url/对象键 形式的地址，返回给客户端时替换为临时签名地址
+func Private() bool {
+	return config.AppConfig.OSS.Private && Enabled()
+}
+
+// SignURL 为 bucket 中的对象地址生成临时签名地址，非私有模式或不是 bucket 中的地址时原样返回
+// 已签名的地址会重新签名，阿里云 OSS 地址中的 x-oss-process 图片处理参数保留并参与签名
+func SignURL(rawURL string) string {
+	if !Private() {
+		return rawURL
+	}
+	key, query, ok := splitObjectURL(rawURL)
+	if !ok || key == "" {
+		return rawURL
+	}
+
+	expires := time.Duration(config.AppConfig.OSS.SignExpires) * time.Second
+	if expires <= 0 {
+		expires = defaultSignExpires * time.Second
+	}
+	var signed string
+	var err error
+	if aliyun, ok := current().(*aliyunStorage); ok {
+		signed, err = aliyun.signURL(key, expires, query.Get("x-oss-process"))
+	} else {
+		signed, err = current().SignURL(key, expires)
+	}
+	if err != nil {
+		logger.Warn("Failed to sign object url: ", err)
+		return rawURL
+	}
+	return signed
+}
+
+// UnsignURL 去掉地址中的签名参数，还原为保存到数据库的形式
+func UnsignURL(rawURL string) string {
+	if !Private() {
+		return rawURL
+	}
+	key, query, ok := splitObjectURL(rawURL)
+	if !ok {
+		return rawURL
+	}
+	signed := false
+	for _, param := range signatureParams {
+		if query.Has(param) {
+			query.Del(param)
+			signed = true
+		}
+	}
+	if !signed {
+		return rawURL
+	}
+	unsigned := baseURL() + "/" + key
+	if encoded := query.Encode(); encoded != "" {
+		unsigned += "?" + encoded
+	}
+	return unsigned
+}
+
+// SignContent 替换文本（如 JSON 响应、Markdown、HTML）中所有 bucket 地址为签名地址
+func SignContent(text string) string {
+	return replaceObjectURLs(text, SignURL)
+}
+
+// UnsignContent 去掉文本中 bucket 地址的签名参数
+func UnsignContent(text string) string {
+	return replaceObjectURLs(text, UnsignURL)
+}
+
+// replaceObjectURLs 对文本中以 base_url 开头的地址逐个替换
+// 查询参数中的 & 在 JSON 中可能被转义为 \u0026，在 HTML 属性中可能为 &amp;，替换前还原，替换后使用原来的写法
+func replaceObjectURLs(text string, replace func(string) string) string {
+	if !Private() {
+		return text
+	}
+	pattern := objectURLRegexp()
+	if pattern == nil {
+		return text
+	}
+	return pattern.ReplaceAllStringFunc(text, func(match string) string {
+		separator := "&"
+		switch {
+		case strings.Contains(match, `\u0026`):
+			separator = `\u0026`
+		case strings.Contains(match, "&amp;"):
+			separator = "&amp;"
+		}
+		plain := strings.ReplaceAll(strings.ReplaceAll(match, `\u0026`, "&"), "&amp;", "&")
+		return strings.ReplaceAll(replace(plain), "&", separator)
+	})
+}
+
+// objectURLRegexp 匹配 base_url 开头地址的正则，base_url 变化时重新生成
+func objectURLRegexp() *regexp.Regexp {
+	base := baseURL()
+	if base == "" {
+		return nil
+	}
+
+	objectURLPatternMu.Lock()
+	defer objectURLPatternMu.Unlock()
+	if objectURLPattern == nil || objectURLPatternBase != base {
+		objectURLPattern = regexp.MustCompile(regexp.QuoteMeta(base) +
+			`/[^\s"'<>()\\?#]+(?:\?(?:[^\s"'<>()\\#&]|&amp;|&|\\u0026)*)?`)
+		objectURLPatternBase = base
+	}
+	return objectURLPattern
+}
+
+// splitObjectURL 解析 bucket 地址，返回对象键和查询参数
+func splitObjectURL(rawURL string) (string, url.Values, bool) {
+	base := baseURL()
+	if base == "" || !strings.HasPrefix(rawURL, base+"/") {
+		return "", nil, false
+	}
+	rest := rawURL[len(base)+1:]
+	path, rawQuery, _ := strings.Cut(rest, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", nil, false
+	}
+	key, err := url.PathUnescape(path)
+	if err != nil {
+		return "", nil, false
+	}
+	return key, query, true
+}
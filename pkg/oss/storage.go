@@ -0,0 +1,161 @@
+package oss
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+// 存储驱动名称，对应配置 oss.driver
+const (
+	DriverAliyun = "aliyun"
+	DriverS3     = "s3"
+	DriverMinIO  = "minio"
+	DriverCOS    = "cos"
+	DriverQiniu  = "qiniu"
+	DriverLocal  = "local"
+)
+
+// Storage 对象存储驱动
+type Storage interface {
+	// Name 驱动名称
+	Name() string
+	// Put 上传对象，size 未知时传 -1
+	Put(key string, r io.Reader, size int64) error
+	// Get 读取对象，调用方负责关闭
+	Get(key string) (io.ReadCloser, error)
+	// Delete 删除对象，对象不存在时不返回错误
+	Delete(key string) error
+	// SignURL 生成有效期为 expires 的临时访问地址
+	SignURL(key string, expires time.Duration) (string, error)
+	// List 列出前缀下的全部对象
+	List(prefix string) ([]ObjectInfo, error)
+	// URL 对象的访问地址，key 为空时返回地址前缀
+	URL(key string) string
+}
+
+// privateUploader 支持上传私有对象（对象 ACL 为 private）的驱动
+type privateUploader interface {
+	PutPrivate(key string, r io.Reader, size int64) error
+}
+
+// newStorage 根据配置创建存储驱动，driver 为空时使用阿里云 OSS（兼容旧配置）
+func newStorage(cfg config.OSSConfig) (Storage, error) {
+	driver := strings.ToLower(cfg.Driver)
+	if driver == "" {
+		driver = DriverAliyun
+	}
+
+	switch driver {
+	case DriverLocal:
+		return localStore, nil
+	case DriverAliyun, DriverS3, DriverMinIO, DriverCOS, DriverQiniu:
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+
+	if cfg.AccessKeyID == "" || cfg.AccessKeySecret == "" || cfg.BucketName == "" {
+		return nil, errIncompleteConfig
+	}
+	if driver == DriverAliyun {
+		return newAliyunStorage(cfg)
+	}
+	return newS3Storage(driver, cfg)
+}
+
+// localStorage 本地磁盘存储，文件通过 /uploads 静态路由访问
+type localStorage struct {
+	dir string
+}
+
+func (s *localStorage) Name() string {
+	return DriverLocal
+}
+
+func (s *localStorage) Put(key string, r io.Reader, size int64) error {
+	destPath := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	dst, err := os.Create(destPath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if _, err := io.Copy(dst, r); err != nil {
+		dst.Close()
+		return fmt.Errorf("failed to save file: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("failed to save file: %w", err)
+	}
+	return nil
+}
+
+func (s *localStorage) Get(key string) (io.ReadCloser, error) {
+	return os.Open(s.path(key))
+}
+
+func (s *localStorage) Delete(key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// SignURL 本地文件没有访问控制，返回普通地址
+func (s *localStorage) SignURL(key string, expires time.Duration) (string, error) {
+	return s.URL(key), nil
+}
+
+// List 对象键为相对存储目录的路径
+func (s *localStorage) List(prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	err := filepath.WalkDir(s.dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == s.dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local files: %w", err)
+	}
+	return objects, nil
+}
+
+// URL 返回相对路径，让前端根据当前域名访问
+func (s *localStorage) URL(key string) string {
+	return "/" + s.dir + "/" + key
+}
+
+func (s *localStorage) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key))
+}
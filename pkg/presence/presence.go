@@ -0,0 +1,228 @@
+package presence
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+const (
+	// keyConns 全部连接，score 为过期时间（Unix 秒）
+	keyConns = "presence:conns"
+	// keyConn 连接详情
+	keyConn = "presence:conn:%s"
+	// keyArticle 正在阅读文章的连接，score 为过期时间
+	keyArticle = "presence:article:%d"
+)
+
+// 连接方式
+const (
+	TransportWebSocket = "websocket" // WebSocket 长连接，断开时立即离开
+	TransportHeartbeat = "heartbeat" // HTTP 心跳，停止心跳后过期离开
+)
+
+// ErrUnavailable 未配置 Redis
+var ErrUnavailable = errors.New("presence requires redis")
+
+// Conn 一个在线连接
+type Conn struct {
+	ID           string
+	UserID       uint // 登录用户，游客为 0
+	IP           string
+	Path         string
+	ArticleID    uint // 正在阅读的文章，其他页面为 0
+	UserAgent    string
+	Transport    string
+	ConnectedAt  time.Time
+	LastActiveAt time.Time
+}
+
+// Visitor 访客标识，登录用户按用户 ID，游客按 IP，同一访客的多个连接只计一次
+func (c *Conn) Visitor() string {
+	if c.UserID != 0 {
+		return fmt.Sprintf("user:%d", c.UserID)
+	}
+	return "guest:" + c.IP
+}
+
+// Touch 记录连接在线，ttl 内未再次调用视为离开；切换到其他页面时离开原来阅读的文章
+func Touch(conn *Conn, ttl time.Duration) error {
+	if redis.Client == nil {
+		return ErrUnavailable
+	}
+	ctx := redis.GetContext()
+	key := fmt.Sprintf(keyConn, conn.ID)
+	previous, err := redis.Client.HGet(ctx, key, "article_id").Uint64()
+	if err != nil && !redis.IsNil(err) {
+		return err
+	}
+	previousArticleID := uint(previous)
+
+	now := time.Now()
+	if conn.ConnectedAt.IsZero() {
+		conn.ConnectedAt = now
+	}
+	conn.LastActiveAt = now
+	expireAt := float64(now.Add(ttl).Unix())
+
+	pipe := redis.Client.TxPipeline()
+	pipe.HSet(ctx, key, map[string]interface{}{
+		"user_id":        conn.UserID,
+		"ip":             conn.IP,
+		"path":           conn.Path,
+		"article_id":     conn.ArticleID,
+		"user_agent":     conn.UserAgent,
+		"transport":      conn.Transport,
+		"connected_at":   conn.ConnectedAt.Unix(),
+		"last_active_at": now.Unix(),
+	})
+	pipe.Expire(ctx, key, ttl)
+	pipe.ZAdd(ctx, keyConns, goredis.Z{Score: expireAt, Member: conn.ID})
+	if previousArticleID != 0 && previousArticleID != conn.ArticleID {
+		pipe.ZRem(ctx, fmt.Sprintf(keyArticle, previousArticleID), conn.ID)
+	}
+	if conn.ArticleID != 0 {
+		articleKey := fmt.Sprintf(keyArticle, conn.ArticleID)
+		pipe.ZAdd(ctx, articleKey, goredis.Z{Score: expireAt, Member: conn.ID})
+		pipe.Expire(ctx, articleKey, ttl)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Leave 连接断开，立即从在线列表中移除
+func Leave(conn *Conn) error {
+	if redis.Client == nil {
+		return ErrUnavailable
+	}
+	ctx := redis.GetContext()
+	pipe := redis.Client.TxPipeline()
+	pipe.ZRem(ctx, keyConns, conn.ID)
+	if conn.ArticleID != 0 {
+		pipe.ZRem(ctx, fmt.Sprintf(keyArticle, conn.ArticleID), conn.ID)
+	}
+	pipe.Del(ctx, fmt.Sprintf(keyConn, conn.ID))
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// List 查询全部在线连接，同时清理已过期的连接
+func List() ([]*Conn, error) {
+	if redis.Client == nil {
+		return nil, ErrUnavailable
+	}
+	ids, err := live(keyConns)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := redis.GetContext()
+	pipe := redis.Client.Pipeline()
+	cmds := make([]*goredis.MapStringStringCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.HGetAll(ctx, fmt.Sprintf(keyConn, id))
+	}
+	if len(ids) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil && !redis.IsNil(err) {
+			return nil, err
+		}
+	}
+
+	conns := make([]*Conn, 0, len(ids))
+	for i, cmd := range cmds {
+		fields := cmd.Val()
+		if len(fields) == 0 {
+			continue
+		}
+		conns = append(conns, parseConn(ids[i], fields))
+	}
+	return conns, nil
+}
+
+// Stats 统计在线访客数，按访客去重
+func Stats() (users, guests int, err error) {
+	conns, err := List()
+	if err != nil {
+		return 0, 0, err
+	}
+	seen := make(map[string]bool, len(conns))
+	for _, conn := range conns {
+		visitor := conn.Visitor()
+		if seen[visitor] {
+			continue
+		}
+		seen[visitor] = true
+		if conn.UserID != 0 {
+			users++
+		} else {
+			guests++
+		}
+	}
+	return users, guests, nil
+}
+
+// ArticleReaders 正在阅读文章的访客数，按访客去重
+func ArticleReaders(articleID uint) (int, error) {
+	if redis.Client == nil {
+		return 0, ErrUnavailable
+	}
+	ids, err := live(fmt.Sprintf(keyArticle, articleID))
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	ctx := redis.GetContext()
+	pipe := redis.Client.Pipeline()
+	cmds := make([]*goredis.SliceCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.HMGet(ctx, fmt.Sprintf(keyConn, id), "user_id", "ip")
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !redis.IsNil(err) {
+		return 0, err
+	}
+
+	seen := make(map[string]bool, len(ids))
+	for _, cmd := range cmds {
+		values := cmd.Val()
+		if len(values) != 2 || values[0] == nil {
+			continue
+		}
+		userID, _ := strconv.ParseUint(fmt.Sprint(values[0]), 10, 64)
+		conn := &Conn{UserID: uint(userID), IP: fmt.Sprint(values[1])}
+		seen[conn.Visitor()] = true
+	}
+	return len(seen), nil
+}
+
+// live 清理有序集合中已过期的连接，返回未过期的连接 ID
+func live(key string) ([]string, error) {
+	ctx := redis.GetContext()
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	if err := redis.Client.ZRemRangeByScore(ctx, key, "-inf", now).Err(); err != nil {
+		return nil, err
+	}
+	return redis.Client.ZRangeByScore(ctx, key, &goredis.ZRangeBy{Min: "(" + now, Max: "+inf"}).Result()
+}
+
+// parseConn 解析连接详情
+func parseConn(id string, fields map[string]string) *Conn {
+	userID, _ := strconv.ParseUint(fields["user_id"], 10, 64)
+	articleID, _ := strconv.ParseUint(fields["article_id"], 10, 64)
+	connectedAt, _ := strconv.ParseInt(fields["connected_at"], 10, 64)
+	lastActiveAt, _ := strconv.ParseInt(fields["last_active_at"], 10, 64)
+	return &Conn{
+		ID:           id,
+		UserID:       uint(userID),
+		IP:           fields["ip"],
+		Path:         fields["path"],
+		ArticleID:    uint(articleID),
+		UserAgent:    fields["user_agent"],
+		Transport:    fields["transport"],
+		ConnectedAt:  time.Unix(connectedAt, 0),
+		LastActiveAt: time.Unix(lastActiveAt, 0),
+	}
+}
@@ -0,0 +1,81 @@
+package presence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+func setupRedis(t *testing.T) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	redis.Client = goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		redis.Client.Close()
+		redis.Client = nil
+	})
+}
+
+func TestStatsCountDistinctVisitors(t *testing.T) {
+	setupRedis(t)
+
+	conns := []*Conn{
+		{ID: "a", UserID: 1, IP: "10.0.0.1", ArticleID: 5},
+		{ID: "b", UserID: 1, IP: "10.0.0.1", ArticleID: 5}, // 同一用户的第二个标签页
+		{ID: "c", IP: "10.0.0.2", ArticleID: 5},
+		{ID: "d", IP: "10.0.0.3"},
+	}
+	for _, conn := range conns {
+		if err := Touch(conn, time.Minute); err != nil {
+			t.Fatalf("Touch: %v", err)
+		}
+	}
+
+	users, guests, err := Stats()
+	if err != nil || users != 1 || guests != 2 {
+		t.Fatalf("Stats = %d users, %d guests, %v; want 1, 2", users, guests, err)
+	}
+	if readers, err := ArticleReaders(5); err != nil || readers != 2 {
+		t.Fatalf("ArticleReaders = %d, %v; want 2", readers, err)
+	}
+
+	// 断开立即离开，切换页面时离开原文章
+	if err := Leave(conns[2]); err != nil {
+		t.Fatalf("Leave: %v", err)
+	}
+	conns[0].ArticleID = 6
+	if err := Touch(conns[0], time.Minute); err != nil {
+		t.Fatalf("Touch: %v", err)
+	}
+	if readers, _ := ArticleReaders(5); readers != 1 {
+		t.Fatalf("ArticleReaders(5) = %d, want 1", readers)
+	}
+	if readers, _ := ArticleReaders(6); readers != 1 {
+		t.Fatalf("ArticleReaders(6) = %d, want 1", readers)
+	}
+}
+
+func TestListDropsExpiredConnections(t *testing.T) {
+	setupRedis(t)
+
+	if err := Touch(&Conn{ID: "live", IP: "10.0.0.1"}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := Touch(&Conn{ID: "stale", IP: "10.0.0.2", ArticleID: 3}, -time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	conns, err := List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(conns) != 1 || conns[0].ID != "live" {
+		t.Fatalf("List = %v, want only the live connection", conns)
+	}
+	if readers, _ := ArticleReaders(3); readers != 0 {
+		t.Fatalf("ArticleReaders = %d, want 0", readers)
+	}
+}
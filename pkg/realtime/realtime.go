@@ -0,0 +1,187 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+const (
+	// redisChannel 在多个实例间转发事件的 Redis 频道
+	redisChannel = "realtime:events"
+	// subscriberBuffer 每个订阅者缓冲的事件数，客户端处理不过来时丢弃新事件
+	subscriberBuffer = 32
+)
+
+// 固定频道
+const (
+	ChannelSite      = "site"      // 全站事件，所有连接都会订阅
+	ChannelGuestbook = "guestbook" // 留言板，正在查看留言板的访客订阅
+)
+
+// 事件类型
+const (
+	EventComment      = "comment"      // 文章有新评论
+	EventNotification = "notification" // 用户收到新通知
+	EventOnline       = "online"       // 在线人数变化
+)
+
+// ErrTooManySubscribers 当前实例的连接数已达上限
+var ErrTooManySubscribers = errors.New("too many realtime subscribers")
+
+// Event 推送给客户端的事件
+type Event struct {
+	Type string
+	Data json.RawMessage
+}
+
+// envelope 经 Redis 转发的事件
+type envelope struct {
+	Channel string          `json:"channel"`
+	Type    string          `json:"type"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// ArticleChannel 文章频道，正在查看文章的读者订阅
+func ArticleChannel(id uint) string {
+	return fmt.Sprintf("article:%d", id)
+}
+
+// UserChannel 用户频道，登录用户订阅自己的频道
+func UserChannel(id uint) string {
+	return fmt.Sprintf("user:%d", id)
+}
+
+// Subscriber 一个客户端连接的订阅
+type Subscriber struct {
+	channels  []string
+	events    chan Event
+	closeOnce sync.Once
+}
+
+// Events 订阅到的事件，Close 后关闭
+func (s *Subscriber) Events() <-chan Event {
+	return s.events
+}
+
+// Close 取消订阅
+func (s *Subscriber) Close() {
+	s.closeOnce.Do(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, channel := range s.channels {
+			delete(subscribers[channel], s)
+			if len(subscribers[channel]) == 0 {
+				delete(subscribers, channel)
+			}
+		}
+		count--
+		close(s.events)
+	})
+}
+
+var (
+	mu          sync.RWMutex
+	subscribers = map[string]map[*Subscriber]struct{}{}
+	count       int
+)
+
+// Subscribe 订阅频道，limit 为当前实例允许的最大连接数，0 表示不限制
+func Subscribe(limit int, channels ...string) (*Subscriber, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if limit > 0 && count >= limit {
+		return nil, ErrTooManySubscribers
+	}
+
+	sub := &Subscriber{channels: channels, events: make(chan Event, subscriberBuffer)}
+	for _, channel := range channels {
+		if subscribers[channel] == nil {
+			subscribers[channel] = map[*Subscriber]struct{}{}
+		}
+		subscribers[channel][sub] = struct{}{}
+	}
+	count++
+	return sub, nil
+}
+
+// Count 当前实例的连接数
+func Count() int {
+	mu.RLock()
+	defer mu.RUnlock()
+	return count
+}
+
+// Publish 发布事件，配置了 Redis 时经 Redis 转发给所有实例，否则只发给当前实例的订阅者
+func Publish(channel, eventType string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if redis.Client == nil {
+		deliver(channel, Event{Type: eventType, Data: payload})
+		return nil
+	}
+
+	message, err := json.Marshal(envelope{Channel: channel, Type: eventType, Data: payload})
+	if err != nil {
+		return err
+	}
+	return redis.Client.Publish(redis.GetContext(), redisChannel, message).Err()
+}
+
+// Deliver 只发给当前实例的订阅者，用于各实例自行计算的事件（如在线人数）
+func Deliver(channel, eventType string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	deliver(channel, Event{Type: eventType, Data: payload})
+	return nil
+}
+
+// Run 接收其他实例经 Redis 发布的事件并转发给本实例的订阅者，ctx 取消后退出
+func Run(ctx context.Context) {
+	if redis.Client == nil {
+		logger.Info("Realtime events are limited to this instance (redis not configured)")
+		return
+	}
+
+	pubsub := redis.Client.Subscribe(ctx, redisChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var env envelope
+			if err := json.Unmarshal([]byte(msg.Payload), &env); err != nil {
+				logger.Warn("Invalid realtime event: ", err)
+				continue
+			}
+			deliver(env.Channel, Event{Type: env.Type, Data: env.Data})
+		}
+	}
+}
+
+// deliver 发给频道的订阅者，订阅者缓冲已满时丢弃该事件，不阻塞发布方
+func deliver(channel string, event Event) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for sub := range subscribers[channel] {
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
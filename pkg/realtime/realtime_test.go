@@ -0,0 +1,118 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+	"github.com/ydcloud-dy/leaf-api/pkg/redis"
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = logrus.New()
+	logger.Log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func receive(t *testing.T, sub *Subscriber) Event {
+	t.Helper()
+	select {
+	case event := <-sub.Events():
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event received")
+		return Event{}
+	}
+}
+
+func TestPublishDeliversToChannelSubscribers(t *testing.T) {
+	article, err := Subscribe(0, ChannelSite, ArticleChannel(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer article.Close()
+	other, err := Subscribe(0, ChannelSite, ArticleChannel(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+
+	if err := Publish(ArticleChannel(1), EventComment, map[string]int{"id": 7}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if event := receive(t, article); event.Type != EventComment || string(event.Data) != `{"id":7}` {
+		t.Fatalf("event = %s %s", event.Type, event.Data)
+	}
+	select {
+	case event := <-other.Events():
+		t.Fatalf("subscriber of another article received %s", event.Type)
+	default:
+	}
+
+	if err := Deliver(ChannelSite, EventOnline, map[string]int{"count": 3}); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	receive(t, article)
+	receive(t, other)
+}
+
+func TestSubscribeLimitAndClose(t *testing.T) {
+	first, err := Subscribe(1, UserChannel(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Subscribe(1, UserChannel(2)); !errors.Is(err, ErrTooManySubscribers) {
+		t.Fatalf("Subscribe = %v, want ErrTooManySubscribers", err)
+	}
+
+	first.Close()
+	first.Close()
+	if _, ok := <-first.Events(); ok {
+		t.Fatal("events channel is still open after Close")
+	}
+	if Count() != 0 {
+		t.Fatalf("Count = %d, want 0", Count())
+	}
+	// 关闭后的订阅者不再接收事件，发布不会阻塞或 panic
+	if err := Publish(UserChannel(1), EventNotification, nil); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+}
+
+func TestRunForwardsRedisEvents(t *testing.T) {
+	mr := miniredis.RunT(t)
+	redis.Client = goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	defer func() {
+		redis.Client.Close()
+		redis.Client = nil
+	}()
+
+	sub, err := Subscribe(0, UserChannel(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go Run(ctx)
+
+	// 等待 Run 订阅 Redis 频道后再发布
+	deadline := time.Now().Add(2 * time.Second)
+	for len(mr.PubSubChannels("")) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := Publish(UserChannel(5), EventNotification, map[string]string{"type": "follow"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if event := receive(t, sub); event.Type != EventNotification {
+		t.Fatalf("event = %s", event.Type)
+	}
+}
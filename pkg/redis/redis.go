@@ -56,6 +56,11 @@ func SetWithExpire(key string, value interface{}, expiration time.Duration) erro
 	return Client.Set(ctx, key, value, expiration).Err()
 }
 
+// SetXX 仅在 key 已存在时设置，返回是否设置成功
+func SetXX(key string, value interface{}, expiration time.Duration) (bool, error) {
+	return Client.SetXX(ctx, key, value, expiration).Result()
+}
+
 // Get 获取 key 的值
 func Get(key string) (string, error) {
 	return Client.Get(ctx, key).Result()
@@ -72,6 +77,12 @@ func Exists(key string) (bool, error) {
 	return result > 0, err
 }
 
+// ExistsAny 检查多个 key 中是否有任意一个存在
+func ExistsAny(keys ...string) (bool, error) {
+	result, err := Client.Exists(ctx, keys...).Result()
+	return result > 0, err
+}
+
 // Keys 根据模式获取所有匹配的 key
 func Keys(pattern string) ([]string, error) {
 	return Client.Keys(ctx, pattern).Result()
@@ -105,3 +116,152 @@ func GetInt(key string) (int64, error) {
 func SetInt(key string, value int64, expiration time.Duration) error {
 	return Client.Set(ctx, key, value, expiration).Err()
 }
+
+// SetNX 仅在 key 不存在时设置，返回是否设置成功
+func SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
+	return Client.SetNX(ctx, key, value, expiration).Result()
+}
+
+// TTL 获取 key 的剩余过期时间
+func TTL(key string) (time.Duration, error) {
+	return Client.TTL(ctx, key).Result()
+}
+
+// SAdd 向集合添加成员
+func SAdd(key string, members ...interface{}) error {
+	return Client.SAdd(ctx, key, members...).Err()
+}
+
+// SRem 从集合移除成员
+func SRem(key string, members ...interface{}) error {
+	return Client.SRem(ctx, key, members...).Err()
+}
+
+// SMembers 获取集合的全部成员
+func SMembers(key string) ([]string, error) {
+	return Client.SMembers(ctx, key).Result()
+}
+
+// LPush 从列表左侧推入元素（用作队列）
+func LPush(key string, values ...interface{}) error {
+	return Client.LPush(ctx, key, values...).Err()
+}
+
+// BRPop 阻塞地从列表右侧弹出元素，超时未取到时返回 redis.Nil（用 IsNil 判断），c 取消时立即返回
+func BRPop(c context.Context, timeout time.Duration, key string) (string, error) {
+	result, err := Client.BRPop(c, timeout, key).Result()
+	if err != nil {
+		return "", err
+	}
+	return result[1], nil
+}
+
+// IsNil 判断错误是否为 key 不存在
+func IsNil(err error) bool {
+	return err == redis.Nil
+}
+
+var (
+	expireIfEqualScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+	delIfEqualScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+	getDelScript = redis.NewScript(`
+local value = redis.call("GET", KEYS[1])
+if value then
+	redis.call("DEL", KEYS[1])
+end
+return value`)
+
+	incrWithExpireScript = redis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+if n == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return n`)
+
+	// takeTokenScript 令牌桶：ARGV 为每毫秒补充的令牌数、桶容量、当前毫秒时间戳
+	// 返回 {是否允许, 剩余令牌数, 下一个令牌可用前的毫秒数, 桶装满前的毫秒数}
+	takeTokenScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+if now > ts then
+	tokens = math.min(burst, tokens + (now - ts) * rate)
+	ts = now
+end
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rate)
+end
+local full = math.ceil((burst - tokens) / rate)
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", tostring(ts))
+redis.call("PEXPIRE", KEYS[1], full + 1000)
+return {allowed, math.floor(tokens), wait, full}`)
+)
+
+// ExpireIfEqual 仅在 key 的值等于 value 时重设过期时间，返回是否续期成功（用于锁续期）
+func ExpireIfEqual(key, value string, expiration time.Duration) (bool, error) {
+	n, err := expireIfEqualScript.Run(ctx, Client, []string{key}, value, expiration.Milliseconds()).Int64()
+	return n == 1, err
+}
+
+// DelIfEqual 仅在 key 的值等于 value 时删除，返回是否删除成功（用于释放锁）
+func DelIfEqual(key, value string) (bool, error) {
+	n, err := delIfEqualScript.Run(ctx, Client, []string{key}, value).Int64()
+	return n == 1, err
+}
+
+// GetDel 读取并删除 key，保证只有一个调用方取到值（用于一次性令牌），key 不存在时返回 redis.Nil
+func GetDel(key string) (string, error) {
+	return getDelScript.Run(ctx, Client, []string{key}).Text()
+}
+
+// IncrWithExpire 计数加一，首次计数时设置过期时间，返回计数（用于固定窗口限流）
+func IncrWithExpire(key string, expiration time.Duration) (int64, error) {
+	return incrWithExpireScript.Run(ctx, Client, []string{key}, expiration.Milliseconds()).Int64()
+}
+
+// TokenBucket 令牌桶限流结果
+type TokenBucket struct {
+	Allowed    bool          // 是否取到令牌
+	Remaining  int64         // 剩余令牌数
+	RetryAfter time.Duration // 被拒绝时下一个令牌可用前的等待时间
+	ResetAfter time.Duration // 令牌桶重新装满前的时间
+}
+
+// TakeToken 令牌桶限流：按每秒 rate 个的速度补充令牌（最多 burst 个）后尝试取出一个，key 在桶装满后自动过期
+func TakeToken(key string, rate float64, burst int, now time.Time) (*TokenBucket, error) {
+	values, err := takeTokenScript.Run(ctx, Client, []string{key}, rate/1000, burst, now.UnixMilli()).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 4 {
+		return nil, fmt.Errorf("unexpected token bucket result: %v", values)
+	}
+	return &TokenBucket{
+		Allowed:    values[0] == 1,
+		Remaining:  values[1],
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		ResetAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
@@ -2,17 +2,32 @@ package response
 
 import (
 	"net/http"
+	"regexp"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/ydcloud-dy/leaf-api/pkg/i18n"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
 )
 
 // Response 统一响应结构
+// 错误响应的 message 按请求语言翻译，error_code 为与语言无关的消息码，消息目录中没有的消息不返回消息码
 type Response struct {
-	Code    int         `json:"code"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+	Code      int         `json:"code"`
+	Message   string      `json:"message"`
+	ErrorCode string      `json:"error_code,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
 }
 
+// internalErrorPattern 匹配数据库、驱动、网络等内部错误信息，这类信息不能返回给客户端
+var internalErrorPattern = regexp.MustCompile(`(?i)(error \d{4}|sql|gorm|mysql|redis|record not found|duplicate entry|foreign key|constraint|dial tcp|connection refused|i/o timeout|no such file|invalid memory|nil pointer|runtime error|select |insert |update |delete from|\.go:\d+)`)
+
+// requestIDPattern 上游传入的请求ID格式，不符合时重新生成，避免日志和响应头被注入任意内容
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
 // PageData 分页数据结构
 type PageData struct {
 	List     interface{} `json:"list"`
@@ -21,6 +36,13 @@ type PageData struct {
 	PageSize int         `json:"page_size"`
 }
 
+// CursorData 游标分页数据结构
+type CursorData struct {
+	List       interface{} `json:"list"`
+	NextCursor string      `json:"next_cursor"`
+	PageSize   int         `json:"page_size"`
+}
+
 // Success 成功响应
 func Success(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusOK, Response{
@@ -41,6 +63,14 @@ func SuccessWithMessage(c *gin.Context, message string, data interface{}) {
 
 // SuccessWithPage 分页成功响应
 func SuccessWithPage(c *gin.Context, list interface{}, total int64, page, pageSize int) {
+	if Version(c) == VersionV2 {
+		Success(c, PageDataV2{
+			Items:      list,
+			Pagination: newPagination(total, page, pageSize),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, Response{
 		Code:    0,
 		Message: "success",
@@ -53,50 +83,146 @@ func SuccessWithPage(c *gin.Context, list interface{}, total int64, page, pageSi
 	})
 }
 
-// Error 错误响应
-func Error(c *gin.Context, code int, message string) {
+// SuccessWithCursor 游标分页成功响应
+func SuccessWithCursor(c *gin.Context, list interface{}, nextCursor string, pageSize int) {
+	if Version(c) == VersionV2 {
+		Success(c, PageDataV2{
+			Items:      list,
+			Pagination: Pagination{NextCursor: nextCursor, PageSize: pageSize},
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, Response{
-		Code:    code,
-		Message: message,
+		Code:    0,
+		Message: "success",
+		Data: CursorData{
+			List:       list,
+			NextCursor: nextCursor,
+			PageSize:   pageSize,
+		},
 	})
 }
 
+// Error 错误响应
+func Error(c *gin.Context, code int, message string) {
+	errorJSON(c, code, message)
+}
+
 // BadRequest 请求参数错误 (code: 400)
 func BadRequest(c *gin.Context, message string) {
-	c.JSON(http.StatusOK, Response{
-		Code:    400,
-		Message: message,
-	})
+	errorJSON(c, 400, message)
 }
 
 // Unauthorized 未授权 (code: 401)
 func Unauthorized(c *gin.Context, message string) {
-	c.JSON(http.StatusOK, Response{
-		Code:    401,
-		Message: message,
-	})
+	errorJSON(c, 401, message)
 }
 
 // Forbidden 禁止访问 (code: 403)
 func Forbidden(c *gin.Context, message string) {
-	c.JSON(http.StatusOK, Response{
-		Code:    403,
-		Message: message,
-	})
+	errorJSON(c, 403, message)
 }
 
 // NotFound 资源不存在 (code: 404)
 func NotFound(c *gin.Context, message string) {
-	c.JSON(http.StatusOK, Response{
-		Code:    404,
-		Message: message,
+	errorJSON(c, 404, message)
+}
+
+// Conflict 资源冲突 (code: 409)
+func Conflict(c *gin.Context, message string) {
+	errorJSON(c, 409, message)
+}
+
+// TooManyRequests 请求过于频繁 (code: 429)
+// 与其他错误响应不同，所有版本的接口都返回 HTTP 429，便于客户端和代理按 Retry-After 重试
+func TooManyRequests(c *gin.Context, message string) {
+	message, errorCode := i18n.Translate(Locale(c), message)
+	c.JSON(http.StatusTooManyRequests, Response{
+		Code:      http.StatusTooManyRequests,
+		Message:   message,
+		ErrorCode: errorCode,
+		RequestID: c.GetString(logger.RequestIDKey),
 	})
 }
 
 // ServerError 服务器内部错误 (code: 500)
+// 原始错误只记录到日志，返回给客户端的消息会去掉数据库/SQL 等内部细节，并附带请求ID便于排查
 func ServerError(c *gin.Context, message string) {
-	c.JSON(http.StatusOK, Response{
-		Code:    500,
-		Message: message,
+	requestID := RequestID(c)
+
+	if logger.Log != nil {
+		logger.WithFields(logrus.Fields{
+			"request_id": requestID,
+			"method":     c.Request.Method,
+			"path":       c.Request.URL.Path,
+		}).Error(message)
+	}
+
+	message, errorCode := i18n.Translate(Locale(c), sanitize(message))
+	c.JSON(httpStatus(c, 500), Response{
+		Code:      500,
+		Message:   message,
+		ErrorCode: errorCode,
+		RequestID: requestID,
 	})
 }
+
+// BindError 请求参数绑定或校验失败 (code: 400)，校验错误按请求语言逐个字段翻译
+func BindError(c *gin.Context, err error) {
+	message, errorCode := i18n.ValidationMessage(Locale(c), err)
+	c.JSON(httpStatus(c, 400), Response{
+		Code:      400,
+		Message:   message,
+		ErrorCode: errorCode,
+		RequestID: c.GetString(logger.RequestIDKey),
+	})
+}
+
+// errorJSON 错误响应，消息按请求语言翻译，经过请求ID中间件时附带请求ID
+func errorJSON(c *gin.Context, code int, message string) {
+	message, errorCode := i18n.Translate(Locale(c), message)
+	c.JSON(httpStatus(c, code), Response{
+		Code:      code,
+		Message:   message,
+		ErrorCode: errorCode,
+		RequestID: c.GetString(logger.RequestIDKey),
+	})
+}
+
+// Locale 获取当前请求的语言，未经过语言协商中间件时使用默认语言
+func Locale(c *gin.Context) string {
+	if locale := c.GetString(i18n.LocaleKey); locale != "" {
+		return locale
+	}
+	return i18n.DefaultLocale()
+}
+
+// RequestID 获取当前请求ID，不存在时沿用合法的 X-Request-ID 请求头或生成新的 ID，并写入响应头
+func RequestID(c *gin.Context) string {
+	if id := c.GetString(logger.RequestIDKey); id != "" {
+		return id
+	}
+	id := c.GetHeader(logger.HeaderRequestID)
+	if !requestIDPattern.MatchString(id) {
+		id = uuid.NewString()
+	}
+	c.Set(logger.RequestIDKey, id)
+	c.Header(logger.HeaderRequestID, id)
+	return id
+}
+
+// sanitize 去掉错误消息中的内部细节
+// 形如 "批量删除失败: Error 1062 ..." 的消息保留业务前缀，纯内部错误替换为通用提示
+func sanitize(message string) string {
+	if !internalErrorPattern.MatchString(message) {
+		return message
+	}
+	if idx := strings.Index(message, ": "); idx > 0 {
+		prefix := message[:idx]
+		if !internalErrorPattern.MatchString(prefix) {
+			return prefix
+		}
+	}
+	return "服务器内部错误"
+}
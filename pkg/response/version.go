@@ -0,0 +1,59 @@
+package response
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API 版本。各版本共享同一套服务和业务逻辑，只在响应层映射为不同的数据结构
+//
+// v1（默认，根路径和 /api/v1）：HTTP 状态码始终为 200，错误通过 code 字段表示；分页数据为 list/total/page/page_size
+// v2（/api/v2）：HTTP 状态码与 code 一致；分页数据为 items + pagination
+const (
+	VersionV1 = "v1"
+	VersionV2 = "v2"
+)
+
+// VersionKey 上下文中保存 API 版本的 key
+const VersionKey = "api_version"
+
+// PageDataV2 v2 分页数据结构
+type PageDataV2 struct {
+	Items      interface{} `json:"items"`
+	Pagination Pagination  `json:"pagination"`
+}
+
+// Pagination v2 分页信息，游标分页时只返回 next_cursor 和 page_size
+type Pagination struct {
+	Total      int64  `json:"total,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"page_size"`
+	TotalPages int    `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Version 获取当前请求的 API 版本，未指定时为 v1
+func Version(c *gin.Context) string {
+	if version := c.GetString(VersionKey); version != "" {
+		return version
+	}
+	return VersionV1
+}
+
+// newPagination 构造 v2 分页信息
+func newPagination(total int64, page, pageSize int) Pagination {
+	pagination := Pagination{Total: total, Page: page, PageSize: pageSize}
+	if pageSize > 0 {
+		pagination.TotalPages = int((total + int64(pageSize) - 1) / int64(pageSize))
+	}
+	return pagination
+}
+
+// httpStatus 错误响应的 HTTP 状态码：v1 始终为 200，v2 与业务码一致（非 HTTP 状态码的业务码仍为 200）
+func httpStatus(c *gin.Context, code int) int {
+	if Version(c) == VersionV2 && code >= 400 && code < 600 {
+		return code
+	}
+	return http.StatusOK
+}
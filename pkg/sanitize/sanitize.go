@@ -0,0 +1,69 @@
+package sanitize
+
+import (
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// 策略构建后可并发使用
+var (
+	articlePolicy     = newArticlePolicy()
+	commentHTMLPolicy = newCommentHTMLPolicy()
+)
+
+var (
+	// 标题锚点由 Markdown 解析器生成，可能包含中文
+	headingID = regexp.MustCompile(`^[\p{L}\p{N}_\-.:]+$`)
+	// 代码高亮、图表和公式使用的 class，如 language-go、hljs、mermaid、math inline
+	codeClass = regexp.MustCompile(`^[\w\- ]+$`)
+	// 只允许 Bilibili / YouTube 播放器地址的 iframe
+	videoEmbedSrc = regexp.MustCompile(`^https://(?:player\.bilibili\.com/player\.html\?|www\.youtube-nocookie\.com/embed/)[\w\-=&?%]+$`)
+)
+
+// newArticlePolicy 文章正文策略：在 UGC 策略基础上保留标题锚点、代码高亮和公式的 class、新窗口打开的链接以及视频
+// 脚本、样式、表单、其他来源的 iframe 以及 on* 事件属性、javascript: 链接都会被移除
+func newArticlePolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("id").Matching(headingID).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("class").Matching(codeClass).OnElements("pre", "code", "span", "div")
+	p.AllowAttrs("target").Matching(regexp.MustCompile(`^_blank$`)).OnElements("a")
+	p.AllowAttrs("loading").Matching(regexp.MustCompile(`^(lazy|eager)$`)).OnElements("img", "iframe")
+	p.AllowAttrs("src").Matching(videoEmbedSrc).OnElements("iframe")
+	p.AllowAttrs("title").OnElements("iframe")
+	p.AllowAttrs("frameborder").Matching(regexp.MustCompile(`^0$`)).OnElements("iframe")
+	p.AllowAttrs("allowfullscreen").Matching(regexp.MustCompile(`^(allowfullscreen)?$`)).OnElements("iframe")
+	p.AllowAttrs("src", "poster").OnElements("video")
+	p.AllowAttrs("controls").Matching(regexp.MustCompile(`^(controls)?$`)).OnElements("video")
+	p.AllowAttrs("preload").Matching(regexp.MustCompile(`^(none|metadata|auto)$`)).OnElements("video")
+	return p
+}
+
+// newCommentHTMLPolicy 评论 Markdown 渲染结果策略：只保留段落、换行、粗体、斜体、删除线、代码、链接和引用
+// 标题、列表、图片、表格等标签被移除，只保留其中的文本；链接只允许 http/https/mailto，添加 nofollow 并在新窗口打开
+func newCommentHTMLPolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowElements("p", "br", "strong", "b", "em", "i", "del", "s", "code", "pre", "blockquote", "a")
+	p.AllowAttrs("href").OnElements("a")
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.RequireParseableURLs(true)
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
+
+// Article 清理文章 HTML
+func Article(html string) string {
+	if html == "" {
+		return ""
+	}
+	return articlePolicy.Sanitize(html)
+}
+
+// CommentHTML 清理评论 Markdown 渲染后的 HTML
+func CommentHTML(html string) string {
+	if html == "" {
+		return ""
+	}
+	return commentHTMLPolicy.Sanitize(html)
+}
@@ -0,0 +1,125 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArticle(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []string
+		notWant []string
+	}{
+		{name: "empty", in: ""},
+		{
+			name:    "script removed",
+			in:      `<p>hi</p><script>alert(1)</script>`,
+			want:    []string{"<p>hi</p>"},
+			notWant: []string{"<script", "alert(1)"},
+		},
+		{
+			name:    "event handler removed",
+			in:      `<img src="/a.png" onerror="alert(1)">`,
+			want:    []string{`src="/a.png"`},
+			notWant: []string{"onerror"},
+		},
+		{
+			name:    "javascript link removed",
+			in:      `<a href="javascript:alert(1)">x</a>`,
+			notWant: []string{"javascript:"},
+		},
+		{
+			name: "heading anchor and code class kept",
+			in:   `<h2 id="安装-go">安装</h2><pre><code class="language-go">fmt.Println()</code></pre>`,
+			want: []string{`id="安装-go"`, `class="language-go"`},
+		},
+		{
+			name: "video embed kept",
+			in:   `<iframe src="https://player.bilibili.com/player.html?bvid=BV1xx" frameborder="0" allowfullscreen></iframe>`,
+			want: []string{`src="https://player.bilibili.com/player.html?bvid=BV1xx"`},
+		},
+		{
+			name:    "other iframe removed",
+			in:      `<iframe src="https://evil.example.com/"></iframe>`,
+			notWant: []string{"evil.example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Article(tt.in)
+			if tt.in == "" && got != "" {
+				t.Fatalf("Article(%q) = %q, want empty", tt.in, got)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("Article(%q) = %q, want it to contain %q", tt.in, got, s)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(got, s) {
+					t.Errorf("Article(%q) = %q, must not contain %q", tt.in, got, s)
+				}
+			}
+		})
+	}
+}
+
+func TestCommentHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []string
+		notWant []string
+	}{
+		{name: "empty", in: ""},
+		{
+			name: "formatting kept",
+			in:   `<p><strong>b</strong> <em>i</em> <code>c</code></p><blockquote><p>q</p></blockquote>`,
+			want: []string{"<strong>b</strong>", "<em>i</em>", "<code>c</code>", "<blockquote>"},
+		},
+		{
+			name: "external link gets nofollow and new window",
+			in:   `<a href="https://example.com">x</a>`,
+			want: []string{`rel="nofollow noopener"`, `target="_blank"`},
+		},
+		{
+			name:    "javascript link removed",
+			in:      `<a href="javascript:alert(1)">x</a>`,
+			notWant: []string{"javascript:", "href"},
+		},
+		{
+			name:    "images and headings reduced to text",
+			in:      `<h1>title</h1><img src="https://example.com/a.png">`,
+			want:    []string{"title"},
+			notWant: []string{"<h1", "<img"},
+		},
+		{
+			name:    "script and styles removed",
+			in:      `<p style="color:red" onclick="x()">a</p><script>alert(1)</script>`,
+			want:    []string{"<p>a</p>"},
+			notWant: []string{"style", "onclick", "<script", "alert(1)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CommentHTML(tt.in)
+			if tt.in == "" && got != "" {
+				t.Fatalf("CommentHTML(%q) = %q, want empty", tt.in, got)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("CommentHTML(%q) = %q, want it to contain %q", tt.in, got, s)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(got, s) {
+					t.Errorf("CommentHTML(%q) = %q, must not contain %q", tt.in, got, s)
+				}
+			}
+		})
+	}
+}
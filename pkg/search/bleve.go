@@ -0,0 +1,166 @@
+package search
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/lang/cjk"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+func init() {
+	Register("bleve", newBleve)
+}
+
+// bleve 高亮默认使用 <mark> 标签，返回前替换为统一的高亮标签
+var bleveHighlightReplacer = strings.NewReplacer("<mark>", HighlightPreTag, "</mark>", HighlightPostTag)
+
+// bleveEngine 基于 Bleve 的内嵌索引，适合无外部搜索服务的单机部署
+// 中文使用 CJK 二元分词，英文按单词分词
+type bleveEngine struct {
+	index bleve.Index
+}
+
+func newBleve(cfg config.SearchConfig) (Engine, error) {
+	path := cfg.Path
+	if path == "" {
+		path = filepath.Join("data", "search.bleve")
+	}
+
+	index, err := bleve.Open(path)
+	if errors.Is(err, bleve.ErrorIndexPathDoesNotExist) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+		index, err = bleve.New(path, newBleveMapping())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &bleveEngine{index: index}, nil
+}
+
+// newBleveMapping 文章索引映射
+func newBleveMapping() mapping.IndexMapping {
+	text := bleve.NewTextFieldMapping()
+	text.Analyzer = cjk.AnalyzerName
+
+	keyword := bleve.NewKeywordFieldMapping()
+	keyword.Store = false
+
+	numeric := bleve.NewNumericFieldMapping()
+	numeric.Store = false
+
+	doc := bleve.NewDocumentMapping()
+	doc.AddFieldMappingsAt("title", text)
+	doc.AddFieldMappingsAt("summary", text)
+	doc.AddFieldMappingsAt("content", text)
+	doc.AddFieldMappingsAt("tags", text)
+	doc.AddFieldMappingsAt("category", keyword)
+	doc.AddFieldMappingsAt("author", keyword)
+	doc.AddFieldMappingsAt("slug", keyword)
+	doc.AddFieldMappingsAt("created_at", numeric)
+
+	m := bleve.NewIndexMapping()
+	m.DefaultMapping = doc
+	m.DefaultAnalyzer = cjk.AnalyzerName
+	return m
+}
+
+func (b *bleveEngine) Name() string {
+	return "bleve"
+}
+
+// EnsureIndex 索引在创建驱动时已打开或创建
+func (b *bleveEngine) EnsureIndex() error {
+	return nil
+}
+
+// Index 批量写入文档
+func (b *bleveEngine) Index(docs ...*Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	batch := b.index.NewBatch()
+	for _, doc := range docs {
+		if err := batch.Index(bleveID(doc.ID), map[string]interface{}{
+			"title":      doc.Title,
+			"slug":       doc.Slug,
+			"summary":    doc.Summary,
+			"content":    doc.Content,
+			"category":   doc.Category,
+			"tags":       doc.Tags,
+			"author":     doc.Author,
+			"created_at": float64(doc.CreatedAt),
+		}); err != nil {
+			return err
+		}
+	}
+	return b.index.Batch(batch)
+}
+
+// Delete 批量删除文档
+func (b *bleveEngine) Delete(ids ...uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	batch := b.index.NewBatch()
+	for _, id := range ids {
+		batch.Delete(bleveID(id))
+	}
+	return b.index.Batch(batch)
+}
+
+// Search 按标题、标签、摘要、正文加权匹配并返回高亮片段
+func (b *bleveEngine) Search(q *Query) (*Result, error) {
+	fields := map[string]float64{"title": 3, "tags": 2, "summary": 2, "content": 1}
+	disjunction := bleve.NewDisjunctionQuery()
+	for field, boost := range fields {
+		match := bleve.NewMatchQuery(q.Keyword)
+		match.SetField(field)
+		match.SetBoost(boost)
+		disjunction.AddQuery(match)
+	}
+
+	req := bleve.NewSearchRequestOptions(disjunction, q.Limit, (q.Page-1)*q.Limit, false)
+	req.Highlight = bleve.NewHighlightWithStyle("html")
+	req.Highlight.Fields = []string{"title", "summary", "content"}
+
+	res, err := b.index.Search(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Total: int64(res.Total), Hits: make([]Hit, 0, len(res.Hits))}
+	for _, h := range res.Hits {
+		id, err := strconv.ParseUint(h.ID, 10, 64)
+		if err != nil {
+			continue
+		}
+		result.Hits = append(result.Hits, Hit{
+			ID:      uint(id),
+			Score:   h.Score,
+			Title:   bleveHighlightReplacer.Replace(strings.Join(h.Fragments["title"], "")),
+			Summary: bleveHighlightReplacer.Replace(strings.Join(h.Fragments["summary"], "")),
+			Content: bleveHighlightReplacer.Replace(strings.Join(h.Fragments["content"], " ... ")),
+		})
+	}
+	return result, nil
+}
+
+// Close 关闭索引
+func (b *bleveEngine) Close() error {
+	return b.index.Close()
+}
+
+func bleveID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
@@ -0,0 +1,213 @@
+package search
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+func init() {
+	Register("elasticsearch", newElasticsearch)
+}
+
+// elasticsearch Elasticsearch 驱动（REST API，兼容 7.x / 8.x）
+type elasticsearch struct {
+	http     *httpClient
+	index    string
+	analyzer string
+}
+
+func newElasticsearch(cfg config.SearchConfig) (Engine, error) {
+	if cfg.Address == "" {
+		return nil, errors.New("search.address is required")
+	}
+
+	headers := make(map[string]string)
+	if cfg.APIKey != "" {
+		headers["Authorization"] = "ApiKey " + cfg.APIKey
+	}
+
+	analyzer := cfg.Analyzer
+	if analyzer == "" {
+		analyzer = "standard"
+	}
+
+	return &elasticsearch{
+		http: &httpClient{
+			client:  &http.Client{Timeout: timeout(cfg)},
+			address: cfg.Address,
+			headers: headers,
+			user:    cfg.Username,
+			pass:    cfg.Password,
+		},
+		index:    cfg.Index,
+		analyzer: analyzer,
+	}, nil
+}
+
+func (e *elasticsearch) Name() string {
+	return "elasticsearch"
+}
+
+// EnsureIndex 创建索引及映射
+func (e *elasticsearch) EnsureIndex() error {
+	status, err := e.http.do(http.MethodHead, "/"+e.index, "", nil, nil)
+	if err == nil && status == http.StatusOK {
+		return nil
+	}
+	if status != http.StatusNotFound {
+		return err
+	}
+
+	text := map[string]interface{}{"type": "text", "analyzer": e.analyzer}
+	mapping := map[string]interface{}{
+		"mappings": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"id":         map[string]interface{}{"type": "long"},
+				"title":      text,
+				"slug":       map[string]interface{}{"type": "keyword"},
+				"summary":    text,
+				"content":    text,
+				"category":   map[string]interface{}{"type": "keyword"},
+				"tags":       map[string]interface{}{"type": "keyword"},
+				"author":     map[string]interface{}{"type": "keyword"},
+				"created_at": map[string]interface{}{"type": "date", "format": "epoch_second"},
+			},
+		},
+	}
+	_, err = e.http.do(http.MethodPut, "/"+e.index, "", mapping, nil)
+	return err
+}
+
+// Index 批量写入文档
+func (e *elasticsearch) Index(docs ...*Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, doc := range docs {
+		action := map[string]interface{}{
+			"index": map[string]interface{}{"_index": e.index, "_id": strconv.FormatUint(uint64(doc.ID), 10)},
+		}
+		if err := enc.Encode(action); err != nil {
+			return err
+		}
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+	}
+	return e.bulk(buf.Bytes())
+}
+
+// Delete 批量删除文档
+func (e *elasticsearch) Delete(ids ...uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, id := range ids {
+		action := map[string]interface{}{
+			"delete": map[string]interface{}{"_index": e.index, "_id": strconv.FormatUint(uint64(id), 10)},
+		}
+		if err := enc.Encode(action); err != nil {
+			return err
+		}
+	}
+	return e.bulk(buf.Bytes())
+}
+
+// bulk 执行 _bulk 请求，任一条目失败时返回错误
+func (e *elasticsearch) bulk(body []byte) error {
+	var resp struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+			Error  *struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+	if _, err := e.http.do(http.MethodPost, "/_bulk?refresh=wait_for", "application/x-ndjson", body, &resp); err != nil {
+		return err
+	}
+	if !resp.Errors {
+		return nil
+	}
+
+	for _, item := range resp.Items {
+		for _, result := range item {
+			// 删除不存在的文档不视为错误
+			if result.Error != nil && result.Status != http.StatusNotFound {
+				return fmt.Errorf("bulk %s: %s", result.Error.Type, result.Error.Reason)
+			}
+		}
+	}
+	return nil
+}
+
+// Search 多字段匹配搜索并返回高亮片段
+func (e *elasticsearch) Search(q *Query) (*Result, error) {
+	body := map[string]interface{}{
+		"from": (q.Page - 1) * q.Limit,
+		"size": q.Limit,
+		"query": map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":  q.Keyword,
+				"fields": []string{"title^3", "summary^2", "content", "tags^2", "category"},
+			},
+		},
+		"_source": []string{"id"},
+		"highlight": map[string]interface{}{
+			"pre_tags":  []string{HighlightPreTag},
+			"post_tags": []string{HighlightPostTag},
+			"fields": map[string]interface{}{
+				"title":   map[string]interface{}{"number_of_fragments": 0},
+				"summary": map[string]interface{}{"number_of_fragments": 0},
+				"content": map[string]interface{}{"fragment_size": 120, "number_of_fragments": 2},
+			},
+		},
+	}
+
+	var resp struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				ID        string              `json:"_id"`
+				Score     float64             `json:"_score"`
+				Highlight map[string][]string `json:"highlight"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if _, err := e.http.do(http.MethodPost, "/"+e.index+"/_search", "", body, &resp); err != nil {
+		return nil, err
+	}
+
+	result := &Result{Total: resp.Hits.Total.Value, Hits: make([]Hit, 0, len(resp.Hits.Hits))}
+	for _, h := range resp.Hits.Hits {
+		id, err := strconv.ParseUint(h.ID, 10, 64)
+		if err != nil {
+			continue
+		}
+		result.Hits = append(result.Hits, Hit{
+			ID:      uint(id),
+			Score:   h.Score,
+			Title:   strings.Join(h.Highlight["title"], ""),
+			Summary: strings.Join(h.Highlight["summary"], ""),
+			Content: strings.Join(h.Highlight["content"], " ... "),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,125 @@
+package search
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+func init() {
+	Register("meilisearch", newMeilisearch)
+}
+
+// meilisearch Meilisearch 驱动（REST API，v1.x）
+// Meilisearch 的写操作是异步任务，Index/Delete 返回时文档可能尚未可搜索
+type meilisearch struct {
+	http  *httpClient
+	index string
+}
+
+func newMeilisearch(cfg config.SearchConfig) (Engine, error) {
+	if cfg.Address == "" {
+		return nil, errors.New("search.address is required")
+	}
+
+	headers := make(map[string]string)
+	if cfg.APIKey != "" {
+		headers["Authorization"] = "Bearer " + cfg.APIKey
+	}
+
+	return &meilisearch{
+		http: &httpClient{
+			client:  &http.Client{Timeout: timeout(cfg)},
+			address: cfg.Address,
+			headers: headers,
+		},
+		index: cfg.Index,
+	}, nil
+}
+
+func (m *meilisearch) Name() string {
+	return "meilisearch"
+}
+
+// EnsureIndex 创建索引并设置可搜索字段
+func (m *meilisearch) EnsureIndex() error {
+	status, err := m.http.do(http.MethodGet, "/indexes/"+m.index, "", nil, nil)
+	if status == http.StatusNotFound {
+		_, err = m.http.do(http.MethodPost, "/indexes", "", map[string]interface{}{
+			"uid":        m.index,
+			"primaryKey": "id",
+		}, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = m.http.do(http.MethodPatch, "/indexes/"+m.index+"/settings", "", map[string]interface{}{
+		"searchableAttributes": []string{"title", "tags", "summary", "category", "content"},
+		"sortableAttributes":   []string{"created_at"},
+	}, nil)
+	return err
+}
+
+// Index 写入或覆盖文档
+func (m *meilisearch) Index(docs ...*Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	_, err := m.http.do(http.MethodPost, "/indexes/"+m.index+"/documents", "", docs, nil)
+	return err
+}
+
+// Delete 批量删除文档
+func (m *meilisearch) Delete(ids ...uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := m.http.do(http.MethodPost, "/indexes/"+m.index+"/documents/delete-batch", "", ids, nil)
+	return err
+}
+
+// Search 搜索并返回高亮、裁剪后的片段
+func (m *meilisearch) Search(q *Query) (*Result, error) {
+	body := map[string]interface{}{
+		"q":                     q.Keyword,
+		"offset":                (q.Page - 1) * q.Limit,
+		"limit":                 q.Limit,
+		"attributesToRetrieve":  []string{"id", "title", "summary", "content"},
+		"attributesToHighlight": []string{"title", "summary", "content"},
+		"attributesToCrop":      []string{"content"},
+		"cropLength":            60,
+		"highlightPreTag":       HighlightPreTag,
+		"highlightPostTag":      HighlightPostTag,
+		"showRankingScore":      true,
+	}
+
+	var resp struct {
+		EstimatedTotalHits int64 `json:"estimatedTotalHits"`
+		Hits               []struct {
+			ID           uint    `json:"id"`
+			RankingScore float64 `json:"_rankingScore"`
+			Formatted    struct {
+				Title   string `json:"title"`
+				Summary string `json:"summary"`
+				Content string `json:"content"`
+			} `json:"_formatted"`
+		} `json:"hits"`
+	}
+	if _, err := m.http.do(http.MethodPost, "/indexes/"+m.index+"/search", "", body, &resp); err != nil {
+		return nil, err
+	}
+
+	result := &Result{Total: resp.EstimatedTotalHits, Hits: make([]Hit, 0, len(resp.Hits))}
+	for _, h := range resp.Hits {
+		result.Hits = append(result.Hits, Hit{
+			ID:      h.ID,
+			Score:   h.RankingScore,
+			Title:   h.Formatted.Title,
+			Summary: h.Formatted.Summary,
+			Content: h.Formatted.Content,
+		})
+	}
+	return result, nil
+}
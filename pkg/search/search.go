@@ -0,0 +1,156 @@
+package search
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/config"
+)
+
+// 高亮标签
+const (
+	HighlightPreTag  = "<em>"
+	HighlightPostTag = "</em>"
+)
+
+// Document 索引中的文章文档
+type Document struct {
+	ID        uint     `json:"id"`
+	Title     string   `json:"title"`
+	Slug      string   `json:"slug"`
+	Summary   string   `json:"summary"`
+	Content   string   `json:"content"`
+	Category  string   `json:"category"`
+	Tags      []string `json:"tags"`
+	Author    string   `json:"author"`
+	CreatedAt int64    `json:"created_at"`
+}
+
+// Query 搜索条件
+type Query struct {
+	Keyword string
+	Page    int
+	Limit   int
+}
+
+// Hit 搜索命中的文章及高亮片段
+type Hit struct {
+	ID      uint
+	Score   float64
+	Title   string
+	Summary string
+	Content string
+}
+
+// Result 搜索结果
+type Result struct {
+	Total int64
+	Hits  []Hit
+}
+
+// Engine 搜索引擎驱动
+type Engine interface {
+	// Name 驱动名称
+	Name() string
+	// EnsureIndex 创建索引（已存在时忽略）
+	EnsureIndex() error
+	// Index 写入或覆盖文档
+	Index(docs ...*Document) error
+	// Delete 删除文档
+	Delete(ids ...uint) error
+	// Search 全文搜索
+	Search(q *Query) (*Result, error)
+}
+
+// Factory 根据配置创建驱动
+type Factory func(cfg config.SearchConfig) (Engine, error)
+
+var (
+	mu      sync.RWMutex
+	drivers = make(map[string]Factory)
+	engine  Engine
+)
+
+// Register 注册搜索驱动
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	drivers[name] = factory
+}
+
+// Drivers 已注册的驱动名称
+func Drivers() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Init 根据配置初始化搜索引擎
+// 未配置 search.driver 时不启用全文搜索，Enabled 返回 false
+func Init() error {
+	cfg := config.AppConfig.Search
+	if cfg.Index == "" {
+		cfg.Index = "articles"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	engine = nil
+
+	if cfg.Driver == "" {
+		return nil
+	}
+
+	factory, ok := drivers[cfg.Driver]
+	if !ok {
+		return fmt.Errorf("unknown search driver %q", cfg.Driver)
+	}
+	e, err := factory(cfg)
+	if err != nil {
+		return err
+	}
+	if err := e.EnsureIndex(); err != nil {
+		return fmt.Errorf("%s: %w", cfg.Driver, err)
+	}
+	engine = e
+	return nil
+}
+
+// Enabled 是否启用全文搜索
+func Enabled() bool {
+	return Default() != nil
+}
+
+// Default 当前使用的搜索引擎，未启用时返回 nil
+func Default() Engine {
+	mu.RLock()
+	defer mu.RUnlock()
+	return engine
+}
+
+// Close 释放搜索引擎资源（内嵌索引需要在退出前关闭）
+func Close() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if closer, ok := engine.(io.Closer); ok {
+		engine = nil
+		return closer.Close()
+	}
+	return nil
+}
+
+// timeout 请求超时时间
+func timeout(cfg config.SearchConfig) time.Duration {
+	return time.Duration(cfg.Timeout) * time.Second
+}
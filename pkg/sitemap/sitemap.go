@@ -0,0 +1,130 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// MaxURLs 单个站点地图文件允许的最大 URL 数（sitemaps.org 协议限制）
+const MaxURLs = 50000
+
+// IndexFile 站点地图入口文件名，URL 超过 MaxURLs 时为索引文件
+const IndexFile = "sitemap.xml"
+
+const xmlns = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// URL 站点地图条目
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+// urlEntry urlset 中的 url 节点
+type urlEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// urlSet 站点地图文件
+type urlSet struct {
+	XMLName xml.Name   `xml:"urlset"`
+	Xmlns   string     `xml:"xmlns,attr"`
+	URLs    []urlEntry `xml:"url"`
+}
+
+// indexEntry 索引文件中的 sitemap 节点
+type indexEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// sitemapIndex 站点地图索引文件
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	Sitemaps []indexEntry `xml:"sitemap"`
+}
+
+// ShardFile 分片文件名，从 1 开始
+func ShardFile(n int) string {
+	return fmt.Sprintf("sitemap-%d.xml", n)
+}
+
+// Build 生成站点地图文件，返回文件名到 XML 内容的映射
+// URL 不超过 MaxURLs 时只生成 sitemap.xml；超过时按 MaxURLs 分片为 sitemap-N.xml，
+// sitemap.xml 为索引文件，分片地址为 shardBaseURL + "/" + 文件名
+func Build(urls []URL, shardBaseURL string) (map[string][]byte, error) {
+	if len(urls) <= MaxURLs {
+		content, err := marshalURLSet(urls)
+		if err != nil {
+			return nil, err
+		}
+		return map[string][]byte{IndexFile: content}, nil
+	}
+
+	files := make(map[string][]byte)
+	index := sitemapIndex{Xmlns: xmlns}
+	for start, n := 0, 1; start < len(urls); start, n = start+MaxURLs, n+1 {
+		end := start + MaxURLs
+		if end > len(urls) {
+			end = len(urls)
+		}
+		shard := urls[start:end]
+
+		content, err := marshalURLSet(shard)
+		if err != nil {
+			return nil, err
+		}
+		name := ShardFile(n)
+		files[name] = content
+		index.Sitemaps = append(index.Sitemaps, indexEntry{
+			Loc:     shardBaseURL + "/" + name,
+			LastMod: formatTime(latest(shard)),
+		})
+	}
+
+	content, err := marshal(index)
+	if err != nil {
+		return nil, err
+	}
+	files[IndexFile] = content
+	return files, nil
+}
+
+// marshalURLSet 生成单个站点地图文件
+func marshalURLSet(urls []URL) ([]byte, error) {
+	set := urlSet{Xmlns: xmlns, URLs: make([]urlEntry, 0, len(urls))}
+	for _, u := range urls {
+		set.URLs = append(set.URLs, urlEntry{Loc: u.Loc, LastMod: formatTime(u.LastMod)})
+	}
+	return marshal(set)
+}
+
+// marshal 序列化为带 XML 声明的文档
+func marshal(v interface{}) ([]byte, error) {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// latest 返回分片中最新的修改时间
+func latest(urls []URL) time.Time {
+	var t time.Time
+	for _, u := range urls {
+		if u.LastMod.After(t) {
+			t = u.LastMod
+		}
+	}
+	return t
+}
+
+// formatTime 按 W3C Datetime 格式输出，零值不输出 lastmod
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,57 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+)
+
+// MaxLength slug 最大长度
+const MaxLength = 100
+
+var pinyinArgs = pinyin.NewArgs()
+
+// Make 根据标题生成 URL 友好的 slug（也用于规范化用户输入的 slug）
+// 英文和数字转为小写保留，中文转换为不带声调的拼音，其余字符作为分隔符
+//
+//	"Go 语言入门"   -> "go-yu-yan-ru-men"
+//	"Hello, World!" -> "hello-world"
+func Make(title string) string {
+	words := make([]string, 0, len(title))
+	var word strings.Builder
+
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range title {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word.WriteRune(unicode.ToLower(r))
+		case unicode.Is(unicode.Han, r):
+			flush()
+			words = append(words, pinyin.LazyPinyin(string(r), pinyinArgs)...)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return truncate(strings.Join(words, "-"))
+}
+
+// truncate 按单词边界截断到最大长度
+func truncate(s string) string {
+	if len(s) <= MaxLength {
+		return s
+	}
+	s = s[:MaxLength]
+	if i := strings.LastIndex(s, "-"); i > 0 {
+		s = s[:i]
+	}
+	return strings.Trim(s, "-")
+}
@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+)
+
+// 投递请求头
+const (
+	HeaderEvent     = "X-Leaf-Event"
+	HeaderDelivery  = "X-Leaf-Delivery"
+	HeaderTimestamp = "X-Leaf-Timestamp"
+	HeaderSignature = "X-Leaf-Signature"
+)
+
+// 响应体最多保留的字节数
+const maxResponseBody = 1000
+
+// Request 一次投递请求
+type Request struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID uint
+	Body       []byte
+	RequestID  string // 触发投递的请求ID，通过 X-Request-ID 传递给接收方
+}
+
+// Result 投递结果
+type Result struct {
+	StatusCode int
+	Body       string // 截断后的响应体
+	Duration   time.Duration
+}
+
+// Sign 计算签名：hex(hmac_sha256(secret, TIMESTAMP + "." + body))
+// 接收方使用 X-Leaf-Timestamp 和原始请求体重新计算，与 X-Leaf-Signature 中 sha256= 之后的部分比较
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send 发送一次投递，返回 2xx 以外的状态码视为失败
+func Send(client *http.Client, req *Request) (*Result, error) {
+	httpReq, err := http.NewRequest(http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := time.Now().Unix()
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "Leaf-Webhook/1.0")
+	httpReq.Header.Set(HeaderEvent, req.Event)
+	httpReq.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(req.DeliveryID), 10))
+	httpReq.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(HeaderSignature, "sha256="+Sign(req.Secret, timestamp, req.Body))
+	if req.RequestID != "" {
+		httpReq.Header.Set(logger.HeaderRequestID, req.RequestID)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(httpReq)
+	result := &Result{Duration: time.Since(start)}
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	result.StatusCode = resp.StatusCode
+	result.Body = strings.ToValidUTF8(string(body), "")
+	result.Duration = time.Since(start)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return result, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return result, nil
+}
@@ -0,0 +1,251 @@
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+)
+
+const (
+	// recordSize 加密记录大小，整条消息只有一条记录
+	recordSize = 4096
+	// MaxPayload 明文负载上限：记录大小减去 AES-GCM 标签和分隔符
+	MaxPayload = recordSize - 16 - 1
+	// vapidExpire VAPID 令牌有效期，规范要求不超过 24 小时
+	vapidExpire = 12 * time.Hour
+	sendTimeout = 15 * time.Second
+)
+
+var (
+	// ErrGone 订阅已失效（推送服务返回 404 或 410），应删除订阅
+	ErrGone = errors.New("push subscription has expired or been unsubscribed")
+	// ErrPayloadTooLarge 负载超过单条记录的大小
+	ErrPayloadTooLarge = errors.New("push payload is too large")
+	// ErrInvalidSubscription 订阅的公钥或认证密钥格式错误
+	ErrInvalidSubscription = errors.New("invalid push subscription keys")
+)
+
+// Subscription 浏览器 PushSubscription 中的推送地址和密钥（base64url 编码）
+type Subscription struct {
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
+
+// Validate 检查推送地址为 https 地址且密钥格式正确
+func (s *Subscription) Validate() error {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return ErrInvalidSubscription
+	}
+	_, _, _, err = s.keys()
+	return err
+}
+
+// keys 解析浏览器公钥（未压缩格式）和 16 字节认证密钥
+func (s *Subscription) keys() ([]byte, *ecdh.PublicKey, []byte, error) {
+	uaRaw, err := decodeBase64(s.P256dh)
+	if err != nil {
+		return nil, nil, nil, ErrInvalidSubscription
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(uaRaw)
+	if err != nil {
+		return nil, nil, nil, ErrInvalidSubscription
+	}
+	authSecret, err := decodeBase64(s.Auth)
+	if err != nil || len(authSecret) != 16 {
+		return nil, nil, nil, ErrInvalidSubscription
+	}
+	return uaRaw, uaPublic, authSecret, nil
+}
+
+// Client 使用 VAPID 身份向浏览器推送服务发送加密消息（RFC 8030、RFC 8291、RFC 8292）
+type Client struct {
+	privateKey *ecdsa.PrivateKey
+	publicKey  string
+	subject    string
+	httpClient *http.Client
+}
+
+// New 创建推送客户端，privateKey 为 base64url 编码的 P-256 私钥，subject 为 mailto: 或 https: 联系地址
+func New(privateKey, subject string) (*Client, error) {
+	raw, err := decodeBase64(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	key, err := ecdh.P256().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+
+	// 公钥为未压缩格式 0x04 || X || Y
+	public := key.PublicKey().Bytes()
+	signer := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(public[1:33]),
+			Y:     new(big.Int).SetBytes(public[33:]),
+		},
+		D: new(big.Int).SetBytes(raw),
+	}
+	return &Client{
+		privateKey: signer,
+		publicKey:  base64.RawURLEncoding.EncodeToString(public),
+		subject:    subject,
+		httpClient: &http.Client{Timeout: sendTimeout},
+	}, nil
+}
+
+// GenerateKeys 生成一对 VAPID 密钥，返回 base64url 编码的公钥和私钥
+func GenerateKeys() (string, string, error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		base64.RawURLEncoding.EncodeToString(key.Bytes()), nil
+}
+
+// PublicKey 浏览器订阅时使用的 applicationServerKey
+func (c *Client) PublicKey() string {
+	return c.publicKey
+}
+
+// Send 加密并发送一条消息，ttl 为推送服务保留未送达消息的秒数
+// 推送服务返回 404/410 时返回 ErrGone，其他非 2xx 状态返回普通错误；ctx 中的请求ID通过 X-Request-ID 传递
+func (c *Client) Send(ctx context.Context, sub *Subscription, payload []byte, ttl int) error {
+	if len(payload) > MaxPayload {
+		return ErrPayloadTooLarge
+	}
+	body, err := encrypt(sub, payload)
+	if err != nil {
+		return err
+	}
+	token, err := c.vapidToken(sub.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(ttl))
+	req.Header.Set("Authorization", "vapid t="+token+", k="+c.publicKey)
+	logger.PropagateRequestID(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrGone
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return fmt.Errorf("push service returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// vapidToken 为推送服务签发 ES256 令牌，aud 为推送地址的源
+func (c *Client) vapidToken(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("invalid push endpoint %q", endpoint)
+	}
+	claims := jwt.MapClaims{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(vapidExpire).Unix(),
+		"sub": c.subject,
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(c.privateKey)
+}
+
+// encrypt 按 RFC 8291 使用 aes128gcm 加密负载
+// 消息头为 salt(16) || 记录大小(4) || 公钥长度(1) || 临时公钥(65)，之后是唯一一条加密记录
+func encrypt(sub *Subscription, payload []byte) ([]byte, error) {
+	uaRaw, uaPublic, authSecret, err := sub.keys()
+	if err != nil {
+		return nil, err
+	}
+
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := asPrivate.PublicKey().Bytes()
+	shared, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	keyInfo := "WebPush: info\x00" + string(uaRaw) + string(asPublic)
+	ikm, err := hkdf.Key(sha256.New, shared, authSecret, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, 16+4+1+len(asPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+
+	// 0x02 表示最后一条记录，不填充
+	plaintext := append(append([]byte{}, payload...), 0x02)
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// decodeBase64 解码浏览器和密钥工具使用的 base64url，兼容带填充和标准 base64
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(strings.TrimSpace(s), "=")
+	s = strings.NewReplacer("+", "-", "/", "_").Replace(s)
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -0,0 +1,155 @@
+package webpush
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/ydcloud-dy/leaf-api/pkg/logger"
+)
+
+// newSubscriber 模拟浏览器生成订阅密钥
+func newSubscriber(t *testing.T, endpoint string) (*Subscription, *ecdh.PrivateKey, []byte) {
+	t.Helper()
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := make([]byte, 16)
+	if _, err := rand.Read(auth); err != nil {
+		t.Fatal(err)
+	}
+	return &Subscription{
+		Endpoint: endpoint,
+		P256dh:   base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		Auth:     base64.RawURLEncoding.EncodeToString(auth),
+	}, key, auth
+}
+
+// decrypt 按浏览器端流程解密 aes128gcm 消息
+func decrypt(t *testing.T, body []byte, key *ecdh.PrivateKey, auth []byte) []byte {
+	t.Helper()
+	salt := body[:16]
+	if rs := binary.BigEndian.Uint32(body[16:20]); rs != recordSize {
+		t.Fatalf("record size = %d", rs)
+	}
+	idLen := int(body[20])
+	asRaw := body[21 : 21+idLen]
+	asPublic, err := ecdh.P256().NewPublicKey(asRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shared, err := key.ECDH(asPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := "WebPush: info\x00" + string(key.PublicKey().Bytes()) + string(asRaw)
+	ikm, _ := hkdf.Key(sha256.New, shared, auth, info, 32)
+	cek, _ := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	nonce, _ := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	plain, err := gcm.Open(nil, nonce, body[21+idLen:], nil)
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if plain[len(plain)-1] != 0x02 {
+		t.Fatalf("missing last record delimiter")
+	}
+	return plain[:len(plain)-1]
+}
+
+func TestSendEncryptsPayloadAndSignsVAPID(t *testing.T) {
+	publicKey, privateKey, err := GenerateKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := New(privateKey, "mailto:admin@example.com")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if client.PublicKey() != publicKey {
+		t.Fatalf("PublicKey = %s, want %s", client.PublicKey(), publicKey)
+	}
+
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header.Clone()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	sub, key, auth := newSubscriber(t, server.URL+"/push/abc")
+	ctx := logger.WithRequestID(context.Background(), "req-1")
+	if err := client.Send(ctx, sub, []byte(`{"title":"hello"}`), 60); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if got := decrypt(t, body, key, auth); string(got) != `{"title":"hello"}` {
+		t.Fatalf("payload = %s", got)
+	}
+	if header.Get("Content-Encoding") != "aes128gcm" || header.Get("TTL") != "60" || header.Get("X-Request-ID") != "req-1" {
+		t.Fatalf("headers = %v", header)
+	}
+
+	authz := header.Get("Authorization")
+	if !strings.HasPrefix(authz, "vapid t=") || !strings.HasSuffix(authz, ", k="+publicKey) {
+		t.Fatalf("Authorization = %q", authz)
+	}
+	token := strings.TrimSuffix(strings.TrimPrefix(authz, "vapid t="), ", k="+publicKey)
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return &client.privateKey.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"ES256"}))
+	if err != nil {
+		t.Fatalf("verify VAPID token: %v", err)
+	}
+	if claims["aud"] != server.URL || claims["sub"] != "mailto:admin@example.com" {
+		t.Fatalf("claims = %v", claims)
+	}
+}
+
+func TestSendReportsGoneSubscription(t *testing.T) {
+	_, privateKey, _ := GenerateKeys()
+	client, _ := New(privateKey, "mailto:admin@example.com")
+
+	status := http.StatusGone
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	sub, _, _ := newSubscriber(t, server.URL)
+
+	if err := client.Send(context.Background(), sub, []byte("x"), 60); !errors.Is(err, ErrGone) {
+		t.Fatalf("Send = %v, want ErrGone", err)
+	}
+	status = http.StatusTooManyRequests
+	if err := client.Send(context.Background(), sub, []byte("x"), 60); err == nil || errors.Is(err, ErrGone) {
+		t.Fatalf("Send = %v, want a temporary error", err)
+	}
+	if err := client.Send(context.Background(), sub, make([]byte, MaxPayload+1), 60); !errors.Is(err, ErrPayloadTooLarge) {
+		t.Fatalf("Send = %v, want ErrPayloadTooLarge", err)
+	}
+	if err := sub.Validate(); !errors.Is(err, ErrInvalidSubscription) {
+		t.Fatalf("Validate(%s) = %v, want ErrInvalidSubscription for a non-https endpoint", sub.Endpoint, err)
+	}
+	sub.Auth = "short"
+	if err := client.Send(context.Background(), sub, []byte("x"), 60); !errors.Is(err, ErrInvalidSubscription) {
+		t.Fatalf("Send = %v, want ErrInvalidSubscription", err)
+	}
+}
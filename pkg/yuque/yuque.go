@@ -0,0 +1,182 @@
+package yuque
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL 语雀开放 API 地址
+const DefaultBaseURL = "https://www.yuque.com/api/v2"
+
+// 目录节点类型
+const (
+	NodeDoc   = "DOC"   // 文档
+	NodeTitle = "TITLE" // 分组标题
+	NodeLink  = "LINK"  // 外链
+)
+
+// docsPageSize 文档列表每页数量（接口上限）
+const docsPageSize = 100
+
+// ErrUnauthorized Token 无效或没有知识库权限
+var ErrUnauthorized = errors.New("语雀 Token 无效或没有权限")
+
+// ErrNotFound 知识库或文档不存在
+var ErrNotFound = errors.New("语雀知识库或文档不存在")
+
+// Repo 知识库
+type Repo struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Namespace   string `json:"namespace"`
+	Description string `json:"description"`
+}
+
+// TOCNode 知识库目录节点，按目录顺序（先序）返回
+type TOCNode struct {
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	UUID       string `json:"uuid"`
+	URL        string `json:"url"` // 文档 slug
+	DocID      uint   `json:"doc_id"`
+	Level      int    `json:"level"`
+	ParentUUID string `json:"parent_uuid"`
+	ChildUUID  string `json:"child_uuid"` // 第一个子节点，为空表示没有子节点
+}
+
+// Doc 文档，列表接口不返回 Body
+type Doc struct {
+	ID               uint      `json:"id"`
+	Slug             string    `json:"slug"`
+	Title            string    `json:"title"`
+	Body             string    `json:"body"` // Markdown 正文
+	Status           int       `json:"status"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	ContentUpdatedAt time.Time `json:"content_updated_at"` // 正文最后修改时间，用于增量同步
+}
+
+// Client 语雀开放 API 客户端
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewClient 创建客户端，baseURL 为空时使用语雀公有云地址
+func NewClient(baseURL, token string, timeout time.Duration) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: timeout},
+	}
+}
+
+// Repo 查询知识库，namespace 形如 group/book
+func (c *Client) Repo(namespace string) (*Repo, error) {
+	var repo Repo
+	if err := c.get(repoPath(namespace), nil, &repo); err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
+// TOC 查询知识库目录
+func (c *Client) TOC(namespace string) ([]TOCNode, error) {
+	var nodes []TOCNode
+	if err := c.get(repoPath(namespace)+"/toc", nil, &nodes); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// Docs 查询知识库的全部文档（不含正文）
+func (c *Client) Docs(namespace string) ([]Doc, error) {
+	var all []Doc
+	for offset := 0; ; offset += docsPageSize {
+		var page []Doc
+		query := url.Values{
+			"offset": {strconv.Itoa(offset)},
+			"limit":  {strconv.Itoa(docsPageSize)},
+		}
+		if err := c.get(repoPath(namespace)+"/docs", query, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < docsPageSize {
+			return all, nil
+		}
+	}
+}
+
+// Doc 查询文档详情（含 Markdown 正文）
+func (c *Client) Doc(namespace, slug string) (*Doc, error) {
+	var doc Doc
+	if err := c.get(repoPath(namespace)+"/docs/"+url.PathEscape(slug), nil, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// get 发送 GET 请求并解析响应中的 data 字段
+func (c *Client) get(path string, query url.Values, out interface{}) error {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Auth-Token", c.token)
+	req.Header.Set("User-Agent", "Leaf-Yuque-Sync/1.0")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求语雀失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusNotFound:
+		return ErrNotFound
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 500))
+		return fmt.Errorf("语雀接口返回 %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("解析语雀响应失败: %w", err)
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("解析语雀响应失败: %w", err)
+	}
+	return nil
+}
+
+// repoPath 知识库接口路径，namespace 中的 / 保留，其余部分转义
+func repoPath(namespace string) string {
+	parts := strings.Split(strings.Trim(namespace, "/"), "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return "/repos/" + strings.Join(parts, "/")
+}